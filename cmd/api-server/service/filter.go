@@ -23,12 +23,17 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"regexp"
+	"strconv"
+	"strings"
 
+	"hcm/cmd/api-server/service/ratelimit"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/logs"
 	"hcm/pkg/runtime/gwparser"
+	"hcm/pkg/tools/redact"
 
 	"github.com/emicklei/go-restful/v3"
 )
@@ -47,6 +52,24 @@ func (p *proxy) restFilter() restful.FilterFunction {
 		}
 		req.Request.Header = kt.Header()
 
+		if p.limiter != nil {
+			limitReq := &ratelimit.Request{
+				AppCode: kt.AppCode,
+				User:    kt.User,
+				Path:    strings.TrimPrefix(r.URL.Path, "/api/v1"),
+				Rid:     kt.Rid,
+			}
+			allowed, rule, wait := p.limiter.Allow(r.Context(), limitReq)
+			if !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				w.WriteHeader(http.StatusTooManyRequests)
+				fmt.Fprintf(w, errf.New(errf.TooManyRequest, "too many requests, please retry later").Error())
+				logs.Warnf("request is rate limited, rule: %s, uri: %s, appcode: %s, user: %s, rid: %s", rule,
+					r.RequestURI, kt.AppCode, kt.User, kt.Rid)
+				return
+			}
+		}
+
 		body, err := peekRequest(r)
 		if err != nil {
 			w.WriteHeader(http.StatusForbidden)
@@ -71,8 +94,9 @@ func peekRequest(req *http.Request) (string, error) {
 
 		req.Body = ioutil.NopCloser(bytes.NewBuffer(byt))
 
+		// sensitive fields like cloud secret keys must not be written to logs in cleartext.
 		reg := regexp.MustCompile("\\s+")
-		str := reg.ReplaceAllString(string(byt), "")
+		str := reg.ReplaceAllString(string(redact.JSON(byt)), "")
 		return str, nil
 	}
 
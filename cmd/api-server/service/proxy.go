@@ -26,6 +26,7 @@ import (
 	"strings"
 	"time"
 
+	"hcm/cmd/api-server/service/ratelimit"
 	"hcm/pkg/cc"
 	"hcm/pkg/client/discovery"
 	"hcm/pkg/criteria/constant"
@@ -40,10 +41,12 @@ import (
 type proxy struct {
 	discovery map[cc.Name]*discovery.APIDiscovery
 	cli       *http.Client
+	// limiter is the request rate limiter, nil means rate limit is disabled.
+	limiter *ratelimit.Limiter
 }
 
 // newProxy create new rest proxy.
-func newProxy(dis serviced.Discover, cli *http.Client) (*proxy, error) {
+func newProxy(dis serviced.Discover, cli *http.Client, limiter *ratelimit.Limiter) (*proxy, error) {
 	apiDiscovery := make(map[cc.Name]*discovery.APIDiscovery)
 
 	discoverServices := []cc.Name{cc.CloudServerName, cc.AccountServerName}
@@ -54,6 +57,7 @@ func newProxy(dis serviced.Discover, cli *http.Client) (*proxy, error) {
 	p := &proxy{
 		discovery: apiDiscovery,
 		cli:       cli,
+		limiter:   limiter,
 	}
 
 	return p, nil
@@ -0,0 +1,58 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package ratelimit implements the token bucket rate limit of api server.
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// bucket is the state of a token bucket.
+type bucket struct {
+	// Tokens is the count of tokens left in the bucket at Last.
+	Tokens float64 `json:"tokens"`
+	// Last is the unix nano time that the bucket is updated.
+	Last int64 `json:"last"`
+}
+
+// take a token from the bucket at now, returns the updated bucket and the duration to wait before a token is
+// available, zero wait means the token is taken successfully. nil bucket means a new full bucket.
+func take(b *bucket, rate float64, burst uint, now time.Time) (*bucket, time.Duration) {
+	tokens := float64(burst)
+	if b != nil {
+		elapsed := now.UnixNano() - b.Last
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		tokens = math.Min(float64(burst), b.Tokens+float64(elapsed)/float64(time.Second)*rate)
+	}
+
+	if tokens >= 1 {
+		return &bucket{Tokens: tokens - 1, Last: now.UnixNano()}, 0
+	}
+
+	wait := time.Duration((1 - tokens) / rate * float64(time.Second))
+	if wait <= 0 {
+		wait = time.Nanosecond
+	}
+
+	return &bucket{Tokens: tokens, Last: now.UnixNano()}, wait
+}
@@ -0,0 +1,251 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/logs"
+	"hcm/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	etcd3 "go.etcd.io/etcd/client/v3"
+)
+
+// Limiter limits the requests of api server by token bucket rules.
+type Limiter struct {
+	rules  []rule
+	local  store
+	shared *etcdStore
+	metric *limitMetric
+}
+
+// rule is the parsed rate limit rule.
+type rule struct {
+	cc.RateLimitRule
+	values map[string]struct{}
+}
+
+// NewLimiter new rate limiter, etcdCli is only used when the buckets are shared by all the instances.
+func NewLimiter(opt cc.RateLimit, etcdCli *etcd3.Client) *Limiter {
+	ttl := time.Duration(opt.BucketTTLSec) * time.Second
+
+	l := &Limiter{
+		rules:  parseRules(opt.Rules),
+		local:  newLocalStore(ttl),
+		metric: initLimitMetric(metrics.Register()),
+	}
+
+	if opt.Shared && etcdCli != nil {
+		l.shared = newEtcdStore(etcdCli, ttl)
+	}
+
+	return l
+}
+
+func parseRules(rules []cc.RateLimitRule) []rule {
+	parsed := make([]rule, 0, len(rules))
+	for _, one := range rules {
+		r := rule{RateLimitRule: one, values: make(map[string]struct{}, len(one.Values))}
+		for _, value := range one.Values {
+			r.values[value] = struct{}{}
+		}
+		parsed = append(parsed, r)
+	}
+
+	return parsed
+}
+
+// Request is the request info used to match the rate limit rules.
+type Request struct {
+	AppCode string
+	User    string
+	// Path is the request path without the /api/v1 prefix.
+	Path string
+	Rid  string
+}
+
+// Allow checks whether the request is allowed by all the matched rules, if not, returns the rejecting rule name and
+// the duration the client should wait before retry. tokens are only consumed when all the matched rules allow.
+func (l *Limiter) Allow(ctx context.Context, req *Request) (bool, string, time.Duration) {
+	matched := make([]matchedRule, 0)
+	for _, r := range l.rules {
+		if key, ok := r.match(req); ok {
+			matched = append(matched, matchedRule{rule: r, key: key})
+		}
+	}
+
+	// check all the matched buckets first, so that the tokens of the other rules are not consumed if rejected.
+	for _, m := range matched {
+		wait, _, _ := l.apply(ctx, m.key, m.rule, req.Rid, peekOp)
+		if wait > 0 {
+			l.metric.requestCounter.WithLabelValues(m.rule.Name, "reject").Inc()
+			return false, m.rule.Name, wait
+		}
+	}
+
+	// the tokens may be taken by concurrent requests after checked, refund the taken tokens if rejected.
+	for idx := range matched {
+		wait, s, burst := l.apply(ctx, matched[idx].key, matched[idx].rule, req.Rid, takeOp)
+		if wait > 0 {
+			for _, taken := range matched[:idx] {
+				taken.store.refund(taken.key, taken.burst)
+			}
+			l.metric.requestCounter.WithLabelValues(matched[idx].rule.Name, "reject").Inc()
+			return false, matched[idx].rule.Name, wait
+		}
+		matched[idx].store, matched[idx].burst = s, burst
+	}
+
+	for _, m := range matched {
+		l.metric.requestCounter.WithLabelValues(m.rule.Name, "allow").Inc()
+	}
+
+	return true, "", 0
+}
+
+// matchedRule is the rule matched by the request, store and burst are the bucket that the token is taken from.
+type matchedRule struct {
+	rule  rule
+	key   string
+	store store
+	burst uint
+}
+
+// bucketOp is the operation on a token bucket of the store.
+type bucketOp func(ctx context.Context, s store, key string, rate float64, burst uint) (time.Duration, error)
+
+func peekOp(ctx context.Context, s store, key string, rate float64, burst uint) (time.Duration, error) {
+	return s.peek(ctx, key, rate, burst)
+}
+
+func takeOp(ctx context.Context, s store, key string, rate float64, burst uint) (time.Duration, error) {
+	return s.take(ctx, key, rate, burst)
+}
+
+// apply the operation on the rule's bucket, returns the wait duration, the store and burst of the bucket. if the
+// shared bucket is not available, the local bucket is used with the per-replica share of the rule's rate and burst,
+// so that all the instances together still limit the requests to the rule's rate approximately.
+func (l *Limiter) apply(ctx context.Context, key string, r rule, rid string, op bucketOp) (time.Duration, store,
+	uint) {
+
+	if l.shared == nil {
+		wait, _ := op(ctx, l.local, key, r.Rate, r.Burst)
+		return wait, l.local, r.Burst
+	}
+
+	wait, err := op(ctx, l.shared, key, r.Rate, r.Burst)
+	if err == nil {
+		return wait, l.shared, r.Burst
+	}
+
+	replicas := l.shared.getReplicas()
+	l.metric.sharedErrCounter.WithLabelValues(r.Name).Inc()
+	logs.Errorf("use shared rate limit bucket failed, use local bucket with 1/%d rate instead, err: %v, rule: %s, "+
+		"rid: %s", replicas, err, r.Name, rid)
+
+	rate, burst := replicaShare(r.Rate, r.Burst, replicas)
+	wait, _ = op(ctx, l.local, key, rate, burst)
+	return wait, l.local, burst
+}
+
+// replicaShare returns the rate and burst of one replica when the rule's rate and burst are divided by replicas.
+func replicaShare(rate float64, burst uint, replicas int64) (float64, uint) {
+	if replicas <= 1 {
+		return rate, burst
+	}
+
+	share := uint(math.Ceil(float64(burst) / float64(replicas)))
+	if share < 1 {
+		share = 1
+	}
+
+	return rate / float64(replicas), share
+}
+
+// match returns the bucket key of the request if the request matches the rule.
+func (r rule) match(req *Request) (string, bool) {
+	if len(r.Routes) != 0 {
+		matched := false
+		for _, route := range r.Routes {
+			if strings.HasPrefix(req.Path, route) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return "", false
+		}
+	}
+
+	var value string
+	switch r.Dimension {
+	case cc.AppCodeRateLimit:
+		value = req.AppCode
+	case cc.UserRateLimit:
+		value = req.User
+	case cc.RouteRateLimit:
+		return r.Name, true
+	default:
+		return "", false
+	}
+
+	if len(r.values) != 0 {
+		if _, exists := r.values[value]; !exists {
+			return "", false
+		}
+	}
+
+	return r.Name + "/" + value, true
+}
+
+type limitMetric struct {
+	// requestCounter record the rate limited request count by rule name and allow or reject result.
+	requestCounter *prometheus.CounterVec
+	// sharedErrCounter record the count that the shared bucket is not available by rule name.
+	sharedErrCounter *prometheus.CounterVec
+}
+
+func initLimitMetric(register prometheus.Registerer) *limitMetric {
+	m := new(limitMetric)
+
+	m.requestCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.RateLimitSubSys,
+		Name:      "request_total",
+		Help:      "the total count of rate limited requests, labeled by rule name and allow or reject result",
+	}, []string{"rule", "result"})
+	register.MustRegister(m.requestCounter)
+
+	m.sharedErrCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.RateLimitSubSys,
+		Name:      "shared_bucket_error_total",
+		Help:      "the total count that the shared token bucket is not available, labeled by rule name",
+	}, []string{"rule"})
+	register.MustRegister(m.sharedErrCounter)
+
+	return m
+}
@@ -0,0 +1,169 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hcm/pkg/cc"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestTake(t *testing.T) {
+	now := time.Now()
+
+	b, wait := take(nil, 1, 2, now)
+	if wait != 0 || b.Tokens != 1 {
+		t.Fatalf("take from new bucket failed, wait: %v, tokens: %v", wait, b.Tokens)
+	}
+
+	b, wait = take(b, 1, 2, now)
+	if wait != 0 || b.Tokens != 0 {
+		t.Fatalf("take the last token failed, wait: %v, tokens: %v", wait, b.Tokens)
+	}
+
+	b, wait = take(b, 1, 2, now.Add(500*time.Millisecond))
+	if wait != 500*time.Millisecond {
+		t.Fatalf("take from empty bucket should wait 500ms, but got %v", wait)
+	}
+
+	// tokens never exceed burst.
+	b, wait = take(b, 1, 2, now.Add(time.Hour))
+	if wait != 0 || b.Tokens != 1 {
+		t.Fatalf("take from refilled bucket failed, wait: %v, tokens: %v", wait, b.Tokens)
+	}
+}
+
+func TestLimiterAllow(t *testing.T) {
+	opt := cc.RateLimit{
+		Enable: true,
+		Rules: []cc.RateLimitRule{
+			{Name: "cvm-list", Dimension: cc.RouteRateLimit, Routes: []string{"/cloud/cvms/list"}, Rate: 0.001,
+				Burst: 3},
+			{Name: "app", Dimension: cc.AppCodeRateLimit, Values: []string{"bad-app"}, Rate: 0.001, Burst: 1},
+		},
+	}
+
+	l := &Limiter{
+		rules:  parseRules(opt.Rules),
+		local:  newLocalStore(time.Minute),
+		metric: initLimitMetric(prometheus.NewRegistry()),
+	}
+
+	ctx := context.Background()
+	if ok, _, _ := l.Allow(ctx, &Request{AppCode: "bad-app", Path: "/cloud/vpcs/list"}); !ok {
+		t.Fatal("first request of bad-app should be allowed")
+	}
+
+	ok, name, wait := l.Allow(ctx, &Request{AppCode: "bad-app", Path: "/cloud/vpcs/list"})
+	if ok || name != "app" || wait <= 0 {
+		t.Fatalf("second request of bad-app should be rejected by app rule, name: %s, wait: %v", name, wait)
+	}
+
+	for i := 0; i < 3; i++ {
+		if ok, _, _ = l.Allow(ctx, &Request{AppCode: "good-app", Path: "/cloud/cvms/list"}); !ok {
+			t.Fatalf("request %d of cvm list should be allowed", i)
+		}
+	}
+
+	ok, name, _ = l.Allow(ctx, &Request{AppCode: "other-app", Path: "/cloud/cvms/list"})
+	if ok || name != "cvm-list" {
+		t.Fatalf("cvm list request should be rejected by route rule, name: %s", name)
+	}
+
+	if ok, _, _ = l.Allow(ctx, &Request{AppCode: "good-app", Path: "/cloud/vpcs/list"}); !ok {
+		t.Fatal("request not matching any rule should be allowed")
+	}
+}
+
+func TestLimiterNotConsumeOnReject(t *testing.T) {
+	opt := cc.RateLimit{
+		Enable: true,
+		Rules: []cc.RateLimitRule{
+			{Name: "cvm-list", Dimension: cc.RouteRateLimit, Routes: []string{"/cloud/cvms/list"}, Rate: 0.001,
+				Burst: 2},
+			{Name: "app", Dimension: cc.AppCodeRateLimit, Values: []string{"bad-app"}, Rate: 0.001, Burst: 1},
+		},
+	}
+
+	local := newLocalStore(time.Minute)
+	l := &Limiter{
+		rules:  parseRules(opt.Rules),
+		local:  local,
+		metric: initLimitMetric(prometheus.NewRegistry()),
+	}
+
+	ctx := context.Background()
+	if ok, _, _ := l.Allow(ctx, &Request{AppCode: "bad-app", Path: "/cloud/vpcs/list"}); !ok {
+		t.Fatal("first request of bad-app should be allowed")
+	}
+
+	// rejected by app rule, the token of cvm-list rule should not be consumed.
+	for i := 0; i < 3; i++ {
+		ok, name, _ := l.Allow(ctx, &Request{AppCode: "bad-app", Path: "/cloud/cvms/list"})
+		if ok || name != "app" {
+			t.Fatalf("request %d of bad-app should be rejected by app rule, name: %s", i, name)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if ok, _, _ := l.Allow(ctx, &Request{AppCode: "good-app", Path: "/cloud/cvms/list"}); !ok {
+			t.Fatalf("request %d of cvm list should be allowed", i)
+		}
+	}
+
+	local.refund("cvm-list", 2)
+	if b := local.buckets["cvm-list"]; b.Tokens < 1 {
+		t.Fatalf("refunded token should be given back to bucket, tokens: %v", b.Tokens)
+	}
+}
+
+func TestReserveSize(t *testing.T) {
+	if size := reserveSize(1000, 50); size != 50 {
+		t.Fatalf("reserve size should not exceed burst, got: %v", size)
+	}
+
+	if size := reserveSize(100, 50); size != 10 {
+		t.Fatalf("reserve size should be the tokens generated in reserve window, got: %v", size)
+	}
+
+	if size := reserveSize(0.5, 1); size != 1 {
+		t.Fatalf("reserve size should be at least 1, got: %v", size)
+	}
+}
+
+func TestReplicaShare(t *testing.T) {
+	rate, burst := replicaShare(100, 10, 4)
+	if rate != 25 || burst != 3 {
+		t.Fatalf("replica share of 4 replicas is wrong, rate: %v, burst: %d", rate, burst)
+	}
+
+	rate, burst = replicaShare(1, 1, 3)
+	if burst != 1 || rate != 1.0/3 {
+		t.Fatalf("replica share should keep at least 1 burst, rate: %v, burst: %d", rate, burst)
+	}
+
+	if rate, burst = replicaShare(100, 10, 0); rate != 100 || burst != 10 {
+		t.Fatalf("unknown replicas should not divide the rate, rate: %v, burst: %d", rate, burst)
+	}
+}
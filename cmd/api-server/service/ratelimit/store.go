@@ -0,0 +1,414 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/uuid"
+
+	etcd3 "go.etcd.io/etcd/client/v3"
+)
+
+// store stores the token buckets.
+type store interface {
+	// peek returns the duration to wait before a token of the bucket of the key is available without taking it,
+	// zero wait means a token is available.
+	peek(ctx context.Context, key string, rate float64, burst uint) (time.Duration, error)
+	// take a token from the bucket of the key, returns the duration to wait before a token is available,
+	// zero wait means the token is taken successfully.
+	take(ctx context.Context, key string, rate float64, burst uint) (time.Duration, error)
+	// refund gives back the token taken from the bucket of the key, used when the request is rejected by other rules.
+	refund(key string, burst uint)
+}
+
+// localStore stores the token buckets in memory of current instance.
+type localStore struct {
+	lock    sync.Mutex
+	buckets map[string]*bucket
+	ttl     time.Duration
+}
+
+func newLocalStore(ttl time.Duration) *localStore {
+	s := &localStore{
+		buckets: make(map[string]*bucket),
+		ttl:     ttl,
+	}
+
+	go s.cleanIdle()
+
+	return s
+}
+
+// peek the local bucket of the key.
+func (s *localStore) peek(_ context.Context, key string, rate float64, burst uint) (time.Duration, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, wait := take(s.buckets[key], rate, burst, time.Now())
+	return wait, nil
+}
+
+// take a token from the local bucket of the key.
+func (s *localStore) take(_ context.Context, key string, rate float64, burst uint) (time.Duration, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	b, wait := take(s.buckets[key], rate, burst, time.Now())
+	s.buckets[key] = b
+	return wait, nil
+}
+
+// refund a token to the local bucket of the key.
+func (s *localStore) refund(key string, burst uint) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if b, exists := s.buckets[key]; exists {
+		b.Tokens = math.Min(float64(burst), b.Tokens+1)
+	}
+}
+
+// cleanIdle clean the buckets that are not used for ttl periodically.
+func (s *localStore) cleanIdle() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		expireAt := time.Now().Add(-s.ttl).UnixNano()
+
+		s.lock.Lock()
+		for key, b := range s.buckets {
+			if b.Last < expireAt {
+				delete(s.buckets, key)
+			}
+		}
+		s.lock.Unlock()
+	}
+}
+
+const (
+	// bucketKeyPrefix is the etcd key prefix of the shared token buckets.
+	bucketKeyPrefix = "/hcm/api-server/ratelimit/buckets/"
+	// memberKeyPrefix is the etcd key prefix of the api server instances which share the token buckets.
+	memberKeyPrefix = "/hcm/api-server/ratelimit/members/"
+	// maxCasRetry is the max retry times when the shared bucket is updated by other instances concurrently.
+	maxCasRetry = 5
+	// reserveWindow is the duration of tokens that an instance reserves from the shared bucket at a time, the
+	// reserved tokens which are not used in the window are dropped, so that one instance can not hoard tokens.
+	reserveWindow = 100 * time.Millisecond
+	// memberSyncInterval is the interval to keep alive current instance and count the instances.
+	memberSyncInterval = 5 * time.Second
+)
+
+// etcdStore stores the token buckets in etcd, so that the buckets are shared by all the api server instances.
+// to avoid accessing etcd for every request, an instance reserves a batch of tokens from the shared bucket by
+// compare-and-swap on the mod revision of the key, and takes tokens from the local reservation first.
+type etcdStore struct {
+	cli *etcd3.Client
+	ttl time.Duration
+
+	// the shared buckets are put with a lease which is granted every half ttl, so that an idle bucket is
+	// deleted by etcd after ttl at most, and a lease is not granted for every request.
+	leaseLock    sync.Mutex
+	leaseID      etcd3.LeaseID
+	leaseGrantAt time.Time
+
+	reserveLock sync.Mutex
+	reserved    map[string]*reservation
+
+	// memberKey is the key that current instance registered to count the instances, replicas is the count.
+	memberKey   string
+	memberLease etcd3.LeaseID
+	replicas    atomic.Int64
+}
+
+// reservation is the tokens reserved from a shared bucket by current instance.
+type reservation struct {
+	lock     sync.Mutex
+	tokens   float64
+	expireAt time.Time
+}
+
+func newEtcdStore(cli *etcd3.Client, ttl time.Duration) *etcdStore {
+	s := &etcdStore{
+		cli:       cli,
+		ttl:       ttl,
+		reserved:  make(map[string]*reservation),
+		memberKey: memberKeyPrefix + uuid.UUID(),
+	}
+	s.replicas.Store(1)
+
+	go s.syncMembers()
+	go s.cleanReserved()
+
+	return s
+}
+
+// peek the reservation of the key, and the shared bucket if the reservation is used up.
+func (s *etcdStore) peek(ctx context.Context, key string, rate float64, burst uint) (time.Duration, error) {
+	s.reserveLock.Lock()
+	r, exists := s.reserved[key]
+	s.reserveLock.Unlock()
+
+	if exists {
+		r.lock.Lock()
+		available := r.tokens >= 1 && time.Now().Before(r.expireAt)
+		r.lock.Unlock()
+
+		if available {
+			return 0, nil
+		}
+	}
+
+	prev, _, err := s.getBucket(ctx, bucketKeyPrefix+key)
+	if err != nil {
+		return 0, err
+	}
+
+	_, wait := take(prev, rate, burst, time.Now())
+	return wait, nil
+}
+
+// refund a token to the reservation of the key, the token is dropped if the reservation is expired.
+func (s *etcdStore) refund(key string, _ uint) {
+	s.reserveLock.Lock()
+	r, exists := s.reserved[key]
+	s.reserveLock.Unlock()
+
+	if !exists {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if time.Now().Before(r.expireAt) {
+		r.tokens++
+	}
+}
+
+// take a token from the reservation of the key, reserve a batch of tokens from the shared bucket if the
+// reservation is used up.
+func (s *etcdStore) take(ctx context.Context, key string, rate float64, burst uint) (time.Duration, error) {
+	s.reserveLock.Lock()
+	r, exists := s.reserved[key]
+	if !exists {
+		r = new(reservation)
+		s.reserved[key] = r
+	}
+	s.reserveLock.Unlock()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+	if r.tokens >= 1 && now.Before(r.expireAt) {
+		r.tokens--
+		return 0, nil
+	}
+
+	reserved, wait, err := s.reserve(ctx, key, rate, burst, reserveSize(rate, burst))
+	if err != nil || wait > 0 {
+		return wait, err
+	}
+
+	// one of the reserved tokens is taken by current request.
+	r.tokens = reserved - 1
+	r.expireAt = now.Add(reserveWindow)
+	return 0, nil
+}
+
+// reserveSize returns the count of tokens to reserve at a time, which is the tokens generated in reserve window,
+// at least 1 and at most burst.
+func reserveSize(rate float64, burst uint) float64 {
+	size := math.Ceil(rate * reserveWindow.Seconds())
+	return math.Max(1, math.Min(size, float64(burst)))
+}
+
+// reserve at most size tokens from the shared bucket of the key, returns the count of reserved tokens, or the
+// duration to wait before a token is available.
+func (s *etcdStore) reserve(ctx context.Context, key string, rate float64, burst uint, size float64) (float64,
+	time.Duration, error) {
+
+	key = bucketKeyPrefix + key
+
+	for retry := 0; retry < maxCasRetry; retry++ {
+		prev, modRevision, err := s.getBucket(ctx, key)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		next, wait := take(prev, rate, burst, time.Now())
+		if wait > 0 {
+			// no token is taken, the bucket need not to be updated.
+			return 0, wait, nil
+		}
+
+		// take the rest tokens of the batch, the token taken above is included.
+		reserved := math.Min(size, math.Floor(next.Tokens)+1)
+		next.Tokens -= reserved - 1
+
+		value, err := json.Marshal(next)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		leaseID, err := s.lease(ctx)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		txnResp, err := s.cli.Txn(ctx).
+			If(etcd3.Compare(etcd3.ModRevision(key), "=", modRevision)).
+			Then(etcd3.OpPut(key, string(value), etcd3.WithLease(leaseID))).
+			Commit()
+		if err != nil {
+			return 0, 0, fmt.Errorf("update bucket %s failed, err: %v", key, err)
+		}
+
+		if txnResp.Succeeded {
+			return reserved, 0, nil
+		}
+	}
+
+	return 0, 0, fmt.Errorf("update bucket %s conflicted after %d retries", key, maxCasRetry)
+}
+
+// getBucket get the shared bucket of the etcd key and its mod revision, nil bucket means a new full bucket.
+func (s *etcdStore) getBucket(ctx context.Context, key string) (*bucket, int64, error) {
+	resp, err := s.cli.Get(ctx, key)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get bucket %s failed, err: %v", key, err)
+	}
+
+	if len(resp.Kvs) == 0 {
+		return nil, 0, nil
+	}
+
+	prev := new(bucket)
+	if err = json.Unmarshal(resp.Kvs[0].Value, prev); err != nil {
+		// the bucket is broken, overwrite it with a new full bucket.
+		prev = nil
+	}
+
+	return prev, resp.Kvs[0].ModRevision, nil
+}
+
+// lease returns the lease that the shared buckets are put with.
+func (s *etcdStore) lease(ctx context.Context) (etcd3.LeaseID, error) {
+	s.leaseLock.Lock()
+	defer s.leaseLock.Unlock()
+
+	if s.leaseID != etcd3.NoLease && time.Since(s.leaseGrantAt) < s.ttl/2 {
+		return s.leaseID, nil
+	}
+
+	resp, err := s.cli.Grant(ctx, int64(s.ttl/time.Second))
+	if err != nil {
+		return etcd3.NoLease, fmt.Errorf("grant bucket lease failed, err: %v", err)
+	}
+
+	s.leaseID = resp.ID
+	s.leaseGrantAt = time.Now()
+	return s.leaseID, nil
+}
+
+// cleanReserved clean the expired reservations periodically.
+func (s *etcdStore) cleanReserved() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		now := time.Now()
+
+		s.reserveLock.Lock()
+		for key, r := range s.reserved {
+			if r.lock.TryLock() {
+				if now.After(r.expireAt) {
+					delete(s.reserved, key)
+				}
+				r.lock.Unlock()
+			}
+		}
+		s.reserveLock.Unlock()
+	}
+}
+
+// getReplicas returns the count of api server instances which share the token buckets, it is used to divide the
+// rate when the shared buckets are not available.
+func (s *etcdStore) getReplicas() int64 {
+	return s.replicas.Load()
+}
+
+// syncMembers keep alive current instance and count the instances periodically.
+func (s *etcdStore) syncMembers() {
+	ticker := time.NewTicker(memberSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.syncMember(); err != nil {
+			logs.Errorf("sync rate limit members failed, err: %v, replicas: %d", err, s.getReplicas())
+		}
+
+		<-ticker.C
+	}
+}
+
+func (s *etcdStore) syncMember() error {
+	ctx, cancel := context.WithTimeout(context.Background(), memberSyncInterval)
+	defer cancel()
+
+	if s.memberLease != etcd3.NoLease {
+		if _, err := s.cli.KeepAliveOnce(ctx, s.memberLease); err != nil {
+			// the lease may be expired, register current instance again.
+			s.memberLease = etcd3.NoLease
+			return fmt.Errorf("keep alive member lease failed, err: %v", err)
+		}
+	} else {
+		resp, err := s.cli.Grant(ctx, int64(3*memberSyncInterval/time.Second))
+		if err != nil {
+			return fmt.Errorf("grant member lease failed, err: %v", err)
+		}
+
+		if _, err = s.cli.Put(ctx, s.memberKey, "", etcd3.WithLease(resp.ID)); err != nil {
+			return fmt.Errorf("put member %s failed, err: %v", s.memberKey, err)
+		}
+		s.memberLease = resp.ID
+	}
+
+	resp, err := s.cli.Get(ctx, memberKeyPrefix, etcd3.WithPrefix(), etcd3.WithCountOnly())
+	if err != nil {
+		return fmt.Errorf("count members failed, err: %v", err)
+	}
+
+	if resp.Count > 0 {
+		s.replicas.Store(resp.Count)
+	}
+
+	return nil
+}
@@ -28,6 +28,7 @@ import (
 	"strconv"
 	"time"
 
+	"hcm/cmd/api-server/service/ratelimit"
 	"hcm/pkg/cc"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/handler"
@@ -37,6 +38,8 @@ import (
 	"hcm/pkg/runtime/shutdown"
 	"hcm/pkg/serviced"
 	"hcm/pkg/tools/ssl"
+
+	etcd3 "go.etcd.io/etcd/client/v3"
 )
 
 // Service do all the api server's work
@@ -64,7 +67,12 @@ func NewService(dis serviced.Discover) (*Service, error) {
 		return nil, err
 	}
 
-	p, err := newProxy(dis, cli)
+	limiter, err := newLimiter()
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := newProxy(dis, cli, limiter)
 	if err != nil {
 		return nil, err
 	}
@@ -74,6 +82,30 @@ func NewService(dis serviced.Discover) (*Service, error) {
 	}, nil
 }
 
+// newLimiter new request rate limiter, returns nil if rate limit is disabled.
+func newLimiter() (*ratelimit.Limiter, error) {
+	opt := cc.ApiServer().RateLimit
+	if !opt.Enable {
+		return nil, nil
+	}
+
+	var etcdCli *etcd3.Client
+	if opt.Shared {
+		etcdOpt, err := cc.ApiServer().Service.Etcd.ToConfig()
+		if err != nil {
+			return nil, fmt.Errorf("get etcd config failed, err: %v", err)
+		}
+
+		etcdCli, err = etcd3.New(etcdOpt)
+		if err != nil {
+			return nil, fmt.Errorf("new etcd client failed, err: %v", err)
+		}
+	}
+
+	logs.Infof("rate limit is enabled, shared: %v, rules: %d.", opt.Shared, len(opt.Rules))
+	return ratelimit.NewLimiter(opt, etcdCli), nil
+}
+
 // ListenAndServeRest listen and serve the restful server
 func (s *Service) ListenAndServeRest() error {
 
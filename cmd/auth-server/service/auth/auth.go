@@ -21,7 +21,6 @@
 package auth
 
 import (
-	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -32,11 +31,11 @@ import (
 	"hcm/pkg/api/core"
 	dsproto "hcm/pkg/api/data-service"
 	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/iam/client"
 	"hcm/pkg/iam/meta"
-	"hcm/pkg/iam/sdk/auth"
 	"hcm/pkg/iam/sys"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
@@ -47,8 +46,8 @@ import (
 
 // Auth related operate.
 type Auth struct {
-	// auth related operate.
-	auth auth.Authorizer
+	// backend is the authorize backend which makes authorize decisions.
+	backend Backend
 	// ds data service's auth related api.
 	ds *dataservice.Client
 	// disableAuth defines whether iam authorization is disabled
@@ -57,14 +56,16 @@ type Auth struct {
 	disableWriteOpt *options.DisableWriteOption
 	// esb client.
 	esbCli esb.Client
+	// cache is the authorize decision cache, nil means cache is disabled.
+	cache *DecisionCache
 }
 
 // NewAuth new auth.
-func NewAuth(auth auth.Authorizer, ds *dataservice.Client, disableAuth bool, esbCli esb.Client,
-	disableWriteOpt *options.DisableWriteOption) (*Auth, error) {
+func NewAuth(backend Backend, ds *dataservice.Client, disableAuth bool, esbCli esb.Client,
+	disableWriteOpt *options.DisableWriteOption, cache *DecisionCache) (*Auth, error) {
 
-	if auth == nil {
-		return nil, errf.New(errf.InvalidParameter, "auth is nil")
+	if backend == nil {
+		return nil, errf.New(errf.InvalidParameter, "authorize backend is nil")
 	}
 
 	if ds == nil {
@@ -76,11 +77,12 @@ func NewAuth(auth auth.Authorizer, ds *dataservice.Client, disableAuth bool, esb
 	}
 
 	i := &Auth{
-		auth:            auth,
+		backend:         backend,
 		ds:              ds,
 		disableAuth:     disableAuth,
 		disableWriteOpt: disableWriteOpt,
 		esbCli:          esbCli,
+		cache:           cache,
 	}
 
 	return i, nil
@@ -96,6 +98,7 @@ func (a *Auth) InitAuthService(c *capability.Capability) {
 	h.Add("GetApplyPermUrl", "POST", "/auth/find/apply_perm_url", a.GetApplyPermUrl)
 	h.Add("ListAuthorizedInstances", "POST", "/auth/list/authorized_resource", a.ListAuthorizedInstances)
 	h.Add("RegisterResCreatorAction", "POST", "/auth/register/resource_create_action", a.RegisterResourceCreatorAction)
+	h.Add("InvalidateCache", "POST", "/auth/cache/invalidate", a.InvalidateCache)
 
 	h.Load(c.WebService)
 }
@@ -107,7 +110,7 @@ func (a *Auth) AuthorizeBatch(cts *rest.Contexts) (interface{}, error) {
 		return nil, err
 	}
 
-	return a.authorizeBatch(cts.Kit, req, true)
+	return a.authorizeBatch(cts.Kit, req, true, isCacheBypassed(cts))
 }
 
 // AuthorizeAnyBatch batch authorize if resource has any permission.
@@ -117,11 +120,13 @@ func (a *Auth) AuthorizeAnyBatch(cts *rest.Contexts) (interface{}, error) {
 		return nil, err
 	}
 
-	return a.authorizeBatch(cts.Kit, req, false)
+	return a.authorizeBatch(cts.Kit, req, false, isCacheBypassed(cts))
 }
 
 // AuthorizeBatch authorize resource batch.
-func (a *Auth) authorizeBatch(kt *kit.Kit, req *authserver.AuthorizeBatchReq, exact bool) ([]meta.Decision, error) {
+func (a *Auth) authorizeBatch(kt *kit.Kit, req *authserver.AuthorizeBatchReq, exact, bypassCache bool) (
+	[]meta.Decision, error) {
+
 	if len(req.Resources) == 0 {
 		return make([]meta.Decision, 0), nil
 	}
@@ -140,52 +145,64 @@ func (a *Auth) authorizeBatch(kt *kit.Kit, req *authserver.AuthorizeBatchReq, ex
 		return decisions, nil
 	}
 
-	// parse hcm resource to iam resource
-	opts, decisions, err := parseAttributesToBatchOptions(kt, req.User, req.Resources...)
-	if err != nil {
-		return nil, err
+	if a.cache == nil || bypassCache || req.User == nil {
+		return a.backend.AuthorizeBatch(kt, req.User, req.Resources, exact)
 	}
 
-	// all resources are skipped
-	if opts == nil {
-		return decisions, nil
-	}
+	return a.authorizeBatchWithCache(kt, req, exact)
+}
 
-	// do authentication
-	var authDecisions []*client.Decision
-	if exact {
-		authDecisions, err = a.auth.AuthorizeBatch(kt.Ctx, opts)
-		if err != nil {
-			logs.Errorf("authorize batch failed, err: %v ,ops: %#v, req: %#v, rid: %s", err, opts, req, kt.Rid)
-			return nil, err
-		}
-	} else {
-		authDecisions, err = a.auth.AuthorizeAnyBatch(kt.Ctx, opts)
-		if err != nil {
-			logs.Errorf("authorize any batch failed, err: %v, ops: %#v, req: %#v, rid: %s", err, opts, req, kt.Rid)
-			return nil, err
+// authorizeBatchWithCache authorize resource batch, use the cached decisions first, and only authorize the missed
+// resources by authorize backend.
+func (a *Auth) authorizeBatchWithCache(kt *kit.Kit, req *authserver.AuthorizeBatchReq, exact bool) (
+	[]meta.Decision, error) {
+
+	user := req.User.UserName
+	decisions := make([]meta.Decision, len(req.Resources))
+	missIndexes := make([]int, 0)
+	missResources := make([]meta.ResourceAttribute, 0)
+	for index, resource := range req.Resources {
+		if resource.Basic != nil {
+			if value, hit := a.cache.get(decisionCacheKind, user, decisionCacheKey(&resource, exact)); hit {
+				decisions[index].Authorized = value.(bool)
+				continue
+			}
 		}
+
+		missIndexes = append(missIndexes, index)
+		missResources = append(missResources, resource)
 	}
 
-	index := 0
-	decisionLen := len(decisions)
-	for _, decision := range authDecisions {
-		// skip resources' decisions are already set as authorized
-		for index < decisionLen && decisions[index].Authorized {
-			index++
-		}
+	if len(missResources) == 0 {
+		return decisions, nil
+	}
 
-		if index >= decisionLen {
-			break
-		}
+	missDecisions, err := a.backend.AuthorizeBatch(kt, req.User, missResources, exact)
+	if err != nil {
+		return nil, err
+	}
 
-		decisions[index].Authorized = decision.Authorized
-		index++
+	for i, index := range missIndexes {
+		decisions[index] = missDecisions[i]
+		if missResources[i].Basic != nil {
+			a.cache.set(user, decisionCacheKey(&missResources[i], exact), missDecisions[i].Authorized,
+				!missDecisions[i].Authorized)
+		}
 	}
 
 	return decisions, nil
 }
 
+func decisionCacheKey(res *meta.ResourceAttribute, exact bool) string {
+	return fmt.Sprintf("%s/%t/%s/%s/%s/%d", decisionCacheKind, exact, res.Type, res.Action, res.ResourceID,
+		res.BizID)
+}
+
+// isCacheBypassed check if the request need to bypass the authorize cache, which is used for debugging.
+func isCacheBypassed(cts *rest.Contexts) bool {
+	return len(cts.Request.Request.Header.Get(constant.AuthCacheBypassKey)) != 0
+}
+
 func (a *Auth) isWriteOperationDisabled(kt *kit.Kit, resources []meta.ResourceAttribute) error {
 	if !a.disableWriteOpt.IsDisabled {
 		return nil
@@ -516,41 +533,23 @@ func (a *Auth) ListAuthorizedInstances(cts *rest.Contexts) (interface{}, error)
 		return nil, err
 	}
 
-	res := &meta.ResourceAttribute{
-		Basic: &meta.Basic{
-			Type:   req.Type,
-			Action: req.Action,
-		},
-	}
-	actionID, resources, err := AdaptAuthOptions(res)
-	if err != nil {
-		return nil, err
+	if a.cache == nil || isCacheBypassed(cts) || req.User == nil {
+		return a.backend.ListAuthorizedInstances(cts.Kit, req)
 	}
 
-	if len(resources) != 1 {
-		logs.Errorf("auth resources(%+v) length is invalid, req: %+v, rid: %s", resources, req, cts.Kit.Rid)
-		return nil, errors.New("auth resources length is not 1, cannot list authorized instances")
+	key := fmt.Sprintf("%s/%s/%s", instanceCacheKind, req.Type, req.Action)
+	if value, hit := a.cache.get(instanceCacheKind, req.User.UserName, key); hit {
+		return value, nil
 	}
 
-	ops := &client.AuthOptions{
-		System: sys.SystemIDHCM,
-		Subject: client.Subject{
-			Type: sys.UserSubjectType,
-			ID:   req.User.UserName,
-		},
-		Action: client.Action{
-			ID: string(actionID),
-		},
-		Resources: resources,
-	}
-	authorizeList, err := a.auth.ListAuthorizedInstances(cts.Kit.Ctx, ops, resources[0].Type)
+	list, err := a.backend.ListAuthorizedInstances(cts.Kit, req)
 	if err != nil {
-		logs.Errorf("list authorized instances failed, err: %v,  ops: %+v, req: %+v, rid: %s", err, ops, req,
-			cts.Kit.Rid)
 		return nil, err
 	}
+	// instances that are not all authorized may be granted in iam later, so they are cached as denied value.
+	a.cache.set(req.User.UserName, key, list, !list.IsAny)
 
-	return authorizeList, nil
+	return list, nil
 }
 
 // RegisterResourceCreatorAction registers iam resource instance so that creator will be authorized on related actions
@@ -561,29 +560,45 @@ func (a *Auth) RegisterResourceCreatorAction(cts *rest.Contexts) (interface{}, e
 		return nil, err
 	}
 
-	opts := &client.InstanceWithCreator{
-		System:  sys.SystemIDHCM,
-		Type:    req.Instance.Type,
-		ID:      req.Instance.ID,
-		Name:    req.Instance.Name,
-		Creator: req.Creator,
+	policies, err := a.backend.RegisterResourceCreatorAction(cts.Kit, req)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, ancestor := range req.Instance.Ancestors {
-		opts.Ancestors = append(opts.Ancestors, client.InstanceAncestor{
-			System: sys.SystemIDHCM,
-			Type:   ancestor.Type,
-			ID:     ancestor.ID,
-		})
+	// creator is granted new permissions, his cached decisions are outdated.
+	if a.cache != nil {
+		if err = a.cache.Invalidate(cts.Kit, req.Creator); err != nil {
+			logs.Errorf("invalidate creator auth cache failed, err: %v, creator: %s, rid: %s", err, req.Creator,
+				cts.Kit.Rid)
+		}
 	}
 
-	policies, err := a.auth.RegisterResourceCreatorAction(cts.Kit.Ctx, opts)
-	if err != nil {
-		logs.Errorf("list authorized instances failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+	return policies, nil
+}
+
+// InvalidateCache invalidate the authorize cache of all auth server instances, it should be called when the
+// permissions of users are changed, such as account biz relations are changed. only hcm internal services are
+// allowed to call it, so that the cache can not be flushed by users.
+func (a *Auth) InvalidateCache(cts *rest.Contexts) (interface{}, error) {
+	if cts.Kit.AppCode != constant.BackendOperationAppCodeKey {
+		return nil, errf.Newf(errf.PermissionDenied, "app %s is not allowed to invalidate auth cache",
+			cts.Kit.AppCode)
+	}
+
+	req := new(authserver.InvalidateCacheReq)
+	if err := cts.DecodeInto(req); err != nil {
 		return nil, err
 	}
 
-	return policies, nil
+	if a.cache == nil {
+		return nil, nil
+	}
+
+	if err := a.cache.Invalidate(cts.Kit, req.Users...); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
 }
 
 // GetApplyPermUrl get iam apply permission url.
@@ -593,11 +608,5 @@ func (a *Auth) GetApplyPermUrl(cts *rest.Contexts) (interface{}, error) {
 		return nil, err
 	}
 
-	url, err := a.auth.GetApplyPermUrl(cts.Kit.Ctx, req)
-	if err != nil {
-		logs.Errorf("get iam apply permission url failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
-		return nil, err
-	}
-
-	return url, nil
+	return a.backend.GetApplyPermUrl(cts.Kit, req)
 }
@@ -0,0 +1,190 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package auth
+
+import (
+	"errors"
+
+	authserver "hcm/pkg/api/auth-server"
+	"hcm/pkg/iam/client"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/iam/sdk/auth"
+	"hcm/pkg/iam/sys"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// Backend is the authorize backend which makes authorize decisions for hcm resources.
+type Backend interface {
+	// AuthorizeBatch authorize resources, if exact is false, user is authorized if he has any permission of the
+	// resource's action, regardless of the resource instance.
+	AuthorizeBatch(kt *kit.Kit, user *meta.UserInfo, resources []meta.ResourceAttribute, exact bool) (
+		[]meta.Decision, error)
+	// ListAuthorizedInstances list the authorized instances of the resource type and action.
+	ListAuthorizedInstances(kt *kit.Kit, req *authserver.ListAuthorizedInstancesReq) (*client.AuthorizeList, error)
+	// RegisterResourceCreatorAction grant the creator the related permissions of the created resource.
+	RegisterResourceCreatorAction(kt *kit.Kit, req *authserver.RegisterResourceCreatorActionReq) (
+		[]client.CreatorActionPolicy, error)
+	// GetApplyPermUrl get the url for user to apply permissions.
+	GetApplyPermUrl(kt *kit.Kit, req *meta.IamPermission) (string, error)
+}
+
+// NewIAMBackend new authorize backend which uses BlueKing IAM to authorize.
+func NewIAMBackend(authorizer auth.Authorizer) Backend {
+	return &iamBackend{auth: authorizer}
+}
+
+// iamBackend authorize with BlueKing IAM, hcm resources are converted to iam resources by AdaptAuthOptions.
+type iamBackend struct {
+	auth auth.Authorizer
+}
+
+// AuthorizeBatch authorize resource batch.
+func (b *iamBackend) AuthorizeBatch(kt *kit.Kit, user *meta.UserInfo, resources []meta.ResourceAttribute,
+	exact bool) ([]meta.Decision, error) {
+
+	// parse hcm resource to iam resource
+	opts, decisions, err := parseAttributesToBatchOptions(kt, user, resources...)
+	if err != nil {
+		return nil, err
+	}
+
+	// all resources are skipped
+	if opts == nil {
+		return decisions, nil
+	}
+
+	// do authentication
+	var authDecisions []*client.Decision
+	if exact {
+		authDecisions, err = b.auth.AuthorizeBatch(kt.Ctx, opts)
+		if err != nil {
+			logs.Errorf("authorize batch failed, err: %v ,ops: %#v, resources: %#v, rid: %s", err, opts, resources,
+				kt.Rid)
+			return nil, err
+		}
+	} else {
+		authDecisions, err = b.auth.AuthorizeAnyBatch(kt.Ctx, opts)
+		if err != nil {
+			logs.Errorf("authorize any batch failed, err: %v, ops: %#v, resources: %#v, rid: %s", err, opts,
+				resources, kt.Rid)
+			return nil, err
+		}
+	}
+
+	index := 0
+	decisionLen := len(decisions)
+	for _, decision := range authDecisions {
+		// skip resources' decisions are already set as authorized
+		for index < decisionLen && decisions[index].Authorized {
+			index++
+		}
+
+		if index >= decisionLen {
+			break
+		}
+
+		decisions[index].Authorized = decision.Authorized
+		index++
+	}
+
+	return decisions, nil
+}
+
+// ListAuthorizedInstances list authorized instances info.
+func (b *iamBackend) ListAuthorizedInstances(kt *kit.Kit, req *authserver.ListAuthorizedInstancesReq) (
+	*client.AuthorizeList, error) {
+
+	res := &meta.ResourceAttribute{
+		Basic: &meta.Basic{
+			Type:   req.Type,
+			Action: req.Action,
+		},
+	}
+	actionID, resources, err := AdaptAuthOptions(res)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resources) != 1 {
+		logs.Errorf("auth resources(%+v) length is invalid, req: %+v, rid: %s", resources, req, kt.Rid)
+		return nil, errors.New("auth resources length is not 1, cannot list authorized instances")
+	}
+
+	ops := &client.AuthOptions{
+		System: sys.SystemIDHCM,
+		Subject: client.Subject{
+			Type: sys.UserSubjectType,
+			ID:   req.User.UserName,
+		},
+		Action: client.Action{
+			ID: string(actionID),
+		},
+		Resources: resources,
+	}
+	authorizeList, err := b.auth.ListAuthorizedInstances(kt.Ctx, ops, resources[0].Type)
+	if err != nil {
+		logs.Errorf("list authorized instances failed, err: %v,  ops: %+v, req: %+v, rid: %s", err, ops, req,
+			kt.Rid)
+		return nil, err
+	}
+
+	return authorizeList, nil
+}
+
+// RegisterResourceCreatorAction registers iam resource instance so that creator will be authorized on related actions
+func (b *iamBackend) RegisterResourceCreatorAction(kt *kit.Kit, req *authserver.RegisterResourceCreatorActionReq) (
+	[]client.CreatorActionPolicy, error) {
+
+	opts := &client.InstanceWithCreator{
+		System:  sys.SystemIDHCM,
+		Type:    req.Instance.Type,
+		ID:      req.Instance.ID,
+		Name:    req.Instance.Name,
+		Creator: req.Creator,
+	}
+
+	for _, ancestor := range req.Instance.Ancestors {
+		opts.Ancestors = append(opts.Ancestors, client.InstanceAncestor{
+			System: sys.SystemIDHCM,
+			Type:   ancestor.Type,
+			ID:     ancestor.ID,
+		})
+	}
+
+	policies, err := b.auth.RegisterResourceCreatorAction(kt.Ctx, opts)
+	if err != nil {
+		logs.Errorf("register resource creator action failed, err: %v, req: %+v, rid: %s", err, req, kt.Rid)
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// GetApplyPermUrl get iam apply permission url.
+func (b *iamBackend) GetApplyPermUrl(kt *kit.Kit, req *meta.IamPermission) (string, error) {
+	url, err := b.auth.GetApplyPermUrl(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("get iam apply permission url failed, err: %v, req: %+v, rid: %s", err, req, kt.Rid)
+		return "", err
+	}
+
+	return url, nil
+}
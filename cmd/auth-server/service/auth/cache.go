@@ -0,0 +1,298 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package auth
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	etcd3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	// cacheInvalidationKey is the etcd key used to broadcast cache invalidation to all auth server instances.
+	cacheInvalidationKey = "/hcm/auth-server/cache/invalidation"
+
+	decisionCacheKind = "decision"
+	instanceCacheKind = "instance"
+)
+
+// DecisionCache caches the authorize decisions and authorized instances of users, the cache of all the auth server
+// instances are invalidated together by etcd watch. when the cache is full, the least recently used entry is evicted.
+type DecisionCache struct {
+	ttl        time.Duration
+	denyTTL    time.Duration
+	maxEntries int
+	etcdCli    *etcd3.Client
+	metric     *cacheMetric
+
+	lock sync.Mutex
+	// users maps user name to the cached entries of the user, so that the cache can be invalidated by user.
+	users map[string]map[string]*list.Element
+	// lru is the cached entries ordered by recent usage, the front is the most recently used one.
+	lru *list.List
+}
+
+type cacheEntry struct {
+	user     string
+	key      string
+	value    interface{}
+	expireAt time.Time
+}
+
+// cacheInvalidation is the cache invalidation message broadcast by etcd.
+type cacheInvalidation struct {
+	// Users whose cache need to be invalidated, empty means all users.
+	Users []string `json:"users"`
+	Rid   string   `json:"rid"`
+}
+
+// NewDecisionCache new authorize decision cache, and start watching cache invalidation.
+func NewDecisionCache(opt cc.AuthCache, etcdCli *etcd3.Client) *DecisionCache {
+	c := &DecisionCache{
+		ttl:        time.Duration(opt.TTLSec) * time.Second,
+		denyTTL:    time.Duration(opt.DenyTTLSec) * time.Second,
+		maxEntries: int(opt.MaxEntries),
+		etcdCli:    etcdCli,
+		metric:     initCacheMetric(metrics.Register()),
+		users:      make(map[string]map[string]*list.Element),
+		lru:        list.New(),
+	}
+
+	go c.watchInvalidation()
+	go c.cleanExpired()
+
+	return c
+}
+
+// get the cached value of the user by key.
+func (c *DecisionCache) get(kind, user, key string) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, exists := c.users[user][key]
+	if !exists {
+		c.metric.lookupCounter.WithLabelValues(kind, "miss").Inc()
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expireAt) {
+		c.remove(elem)
+		c.metric.lookupCounter.WithLabelValues(kind, "miss").Inc()
+		return nil, false
+	}
+
+	c.lru.MoveToFront(elem)
+	c.metric.lookupCounter.WithLabelValues(kind, "hit").Inc()
+	return entry.value, true
+}
+
+// set the cached value of the user by key, denied value is cached with the shorter deny ttl, so that permissions
+// granted in iam take effect soon even if the policy change is not noticed yet. if the cache is full, the least
+// recently used entry is evicted.
+func (c *DecisionCache) set(user, key string, value interface{}, denied bool) {
+	ttl := c.ttl
+	if denied {
+		ttl = c.denyTTL
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entries, exists := c.users[user]
+	if !exists {
+		entries = make(map[string]*list.Element)
+		c.users[user] = entries
+	}
+
+	if elem, exists := entries[key]; exists {
+		entry := elem.Value.(*cacheEntry)
+		entry.value = value
+		entry.expireAt = time.Now().Add(ttl)
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	entries[key] = c.lru.PushFront(&cacheEntry{user: user, key: key, value: value, expireAt: time.Now().Add(ttl)})
+
+	for c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+		c.metric.evictCounter.Inc()
+	}
+}
+
+// remove the cached entry, the lock must be held by the caller.
+func (c *DecisionCache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*cacheEntry)
+
+	entries := c.users[entry.user]
+	delete(entries, entry.key)
+	if len(entries) == 0 {
+		delete(c.users, entry.user)
+	}
+}
+
+// size returns the count of the cached entries.
+func (c *DecisionCache) size() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.lru.Len()
+}
+
+// invalidateLocal invalidate the cache of current instance, empty users means all users.
+func (c *DecisionCache) invalidateLocal(users ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(users) == 0 {
+		c.users = make(map[string]map[string]*list.Element)
+		c.lru.Init()
+		c.metric.invalidateCounter.WithLabelValues("all").Inc()
+		return
+	}
+
+	for _, user := range users {
+		for _, elem := range c.users[user] {
+			c.remove(elem)
+		}
+	}
+	c.metric.invalidateCounter.WithLabelValues("user").Inc()
+}
+
+// Invalidate the cache of all auth server instances, empty users means all users.
+func (c *DecisionCache) Invalidate(kt *kit.Kit, users ...string) error {
+	c.invalidateLocal(users...)
+
+	msg, err := json.Marshal(cacheInvalidation{Users: users, Rid: kt.Rid})
+	if err != nil {
+		return err
+	}
+
+	if _, err = c.etcdCli.Put(kt.Ctx, cacheInvalidationKey, string(msg)); err != nil {
+		logs.Errorf("broadcast auth cache invalidation failed, err: %v, users: %v, rid: %s", err, users, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// watchInvalidation watch the cache invalidation broadcast by other auth server instances.
+func (c *DecisionCache) watchInvalidation() {
+	for {
+		watchCh := c.etcdCli.Watch(context.Background(), cacheInvalidationKey)
+		for resp := range watchCh {
+			if err := resp.Err(); err != nil {
+				logs.Errorf("watch auth cache invalidation failed, err: %v", err)
+				break
+			}
+
+			for _, event := range resp.Events {
+				if event.Type != etcd3.EventTypePut {
+					continue
+				}
+
+				msg := new(cacheInvalidation)
+				if err := json.Unmarshal(event.Kv.Value, msg); err != nil {
+					logs.Errorf("unmarshal auth cache invalidation failed, err: %v, value: %s", err, event.Kv.Value)
+					// invalidate all cache to avoid using outdated decisions
+					c.invalidateLocal()
+					continue
+				}
+
+				logs.V(3).Infof("received auth cache invalidation, users: %v, rid: %s", msg.Users, msg.Rid)
+				c.invalidateLocal(msg.Users...)
+			}
+		}
+
+		// the watch channel is closed, the invalidation broadcast may be lost, so invalidate all and re-watch.
+		c.invalidateLocal()
+		time.Sleep(time.Second)
+	}
+}
+
+// cleanExpired clean the expired entries periodically.
+func (c *DecisionCache) cleanExpired() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		now := time.Now()
+
+		c.lock.Lock()
+		for elem := c.lru.Back(); elem != nil; {
+			prev := elem.Prev()
+			if now.After(elem.Value.(*cacheEntry).expireAt) {
+				c.remove(elem)
+			}
+			elem = prev
+		}
+		c.lock.Unlock()
+	}
+}
+
+type cacheMetric struct {
+	// lookupCounter record the cache lookup count by cache kind and hit or miss result.
+	lookupCounter *prometheus.CounterVec
+	// invalidateCounter record the cache invalidation count by invalidation scope.
+	invalidateCounter *prometheus.CounterVec
+	// evictCounter record the count of entries evicted because the cache is full.
+	evictCounter prometheus.Counter
+}
+
+func initCacheMetric(register prometheus.Registerer) *cacheMetric {
+	m := new(cacheMetric)
+
+	m.lookupCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.AuthCacheSubSys,
+		Name:      "lookup_total",
+		Help:      "the total count of authorize cache lookup, labeled by cache kind and hit or miss result",
+	}, []string{"kind", "result"})
+	register.MustRegister(m.lookupCounter)
+
+	m.invalidateCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.AuthCacheSubSys,
+		Name:      "invalidate_total",
+		Help:      "the total count of authorize cache invalidation, labeled by invalidation scope",
+	}, []string{"scope"})
+	register.MustRegister(m.invalidateCounter)
+
+	m.evictCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.AuthCacheSubSys,
+		Name:      "evict_total",
+		Help:      "the total count of authorize cache entries evicted because the cache is full",
+	})
+	register.MustRegister(m.evictCounter)
+
+	return m
+}
@@ -0,0 +1,116 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package auth
+
+import (
+	"container/list"
+	"testing"
+	"time"
+
+	"hcm/pkg/iam/client"
+	"hcm/pkg/iam/meta"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestDecisionCache(t *testing.T) {
+	c := &DecisionCache{
+		ttl:        time.Minute,
+		denyTTL:    time.Second,
+		maxEntries: 2,
+		metric:     initCacheMetric(prometheus.NewRegistry()),
+		users:      make(map[string]map[string]*list.Element),
+		lru:        list.New(),
+	}
+
+	res := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Cvm, Action: meta.Find}, BizID: 100}
+	key := decisionCacheKey(&res, true)
+	if key == decisionCacheKey(&res, false) {
+		t.Fatalf("exact and any decisions should use different cache keys")
+	}
+
+	c.set("alice", key, true, false)
+	c.set("bob", key, false, true)
+	if _, hit := c.get(decisionCacheKind, "alice", key); !hit {
+		t.Fatalf("alice's decision should be cached")
+	}
+
+	// bob is the least recently used one, it should be evicted when cache is full.
+	c.set("carol", key, true, false)
+	if c.size() != 2 {
+		t.Fatalf("cache size should not exceed max entries, got: %d", c.size())
+	}
+
+	if _, hit := c.get(decisionCacheKind, "bob", key); hit {
+		t.Fatalf("bob's decision should be evicted")
+	}
+
+	if value, hit := c.get(decisionCacheKind, "carol", key); !hit || !value.(bool) {
+		t.Fatalf("carol's decision should be cached")
+	}
+
+	c.set("dave", key, false, true)
+	if ttl := time.Until(c.users["dave"][key].Value.(*cacheEntry).expireAt); ttl > time.Second {
+		t.Fatalf("deny decision should be cached with deny ttl, got: %s", ttl)
+	}
+
+	c.invalidateLocal("carol")
+	if _, hit := c.get(decisionCacheKind, "carol", key); hit || c.size() != 1 {
+		t.Fatalf("carol's decision should be invalidated, size: %d", c.size())
+	}
+
+	c.users["dave"][key].Value.(*cacheEntry).expireAt = time.Now().Add(-time.Second)
+	if _, hit := c.get(decisionCacheKind, "dave", key); hit || c.size() != 0 {
+		t.Fatalf("expired decision should not be hit and should be removed")
+	}
+
+	c.set("alice", key, true, false)
+	c.invalidateLocal()
+	if len(c.users) != 0 || c.size() != 0 {
+		t.Fatalf("all decisions should be invalidated")
+	}
+}
+
+func TestDiffPolicySubjects(t *testing.T) {
+	alice := client.PolicySubject{Type: policySubjectUser, ID: "alice"}
+	bob := client.PolicySubject{Type: policySubjectUser, ID: "bob"}
+	group := client.PolicySubject{Type: "group", ID: "1"}
+
+	previous := map[int64]client.PolicyResult{
+		1: {ID: 1, Version: "1", Subject: alice},
+		2: {ID: 2, Version: "1", Subject: bob},
+		3: {ID: 3, Version: "1", Subject: group},
+	}
+	current := map[int64]client.PolicyResult{
+		1: {ID: 1, Version: "1", Subject: alice},
+		2: {ID: 2, Version: "2", Subject: bob},
+	}
+
+	subjects := diffPolicySubjects(previous, current)
+	if len(subjects) != 2 {
+		t.Fatalf("updated and deleted policy subjects should be returned, got: %v", subjects)
+	}
+
+	for _, subject := range subjects {
+		if subject == alice {
+			t.Fatalf("unchanged policy subject should not be returned")
+		}
+	}
+}
@@ -283,6 +283,12 @@ func genRecycleBinResource(a *meta.ResourceAttribute) (client.ActionID, []client
 			return sys.BizRecycleBinOperate, []client.Resource{bizRes}, nil
 		}
 		return sys.RecycleBinOperate, []client.Resource{res}, nil
+	case meta.Update:
+		// update recycle bin config, like recycle policy
+		if a.BizID > 0 {
+			return sys.BizRecycleBinConfig, []client.Resource{bizRes}, nil
+		}
+		return sys.RecycleBinConfig, []client.Resource{res}, nil
 	default:
 		return "", nil, errf.Newf(errf.InvalidParameter, "unsupported hcm action: %s", a.Basic.Action)
 	}
@@ -606,9 +612,11 @@ func genLoadBalancerResource(a *meta.ResourceAttribute) (client.ActionID, []clie
 	case meta.Update:
 		// update resource is related to hcm account resource
 		return sys.CLBResOperate, []client.Resource{res}, nil
-	case meta.Delete:
+	case meta.Delete, meta.Recycle:
 		// delete resource is related to hcm account resource
 		return sys.CLBResDelete, []client.Resource{res}, nil
+	case meta.Destroy, meta.Recover:
+		return sys.RecycleBinOperate, []client.Resource{res}, nil
 	default:
 		return "", nil, errf.Newf(errf.InvalidParameter, "unsupported hcm action: %s", a.Basic.Action)
 	}
@@ -632,8 +640,10 @@ func genBizLoadBalancerResource(a *meta.ResourceAttribute) (client.ActionID, []c
 		return sys.BizCLBResCreate, []client.Resource{res}, nil
 	case meta.Update:
 		return sys.BizCLBResOperate, []client.Resource{res}, nil
-	case meta.Delete:
+	case meta.Delete, meta.Recycle:
 		return sys.BizCLBResDelete, []client.Resource{res}, nil
+	case meta.Destroy, meta.Recover:
+		return sys.BizRecycleBinOperate, []client.Resource{res}, nil
 	default:
 		return "", nil, errf.Newf(errf.InvalidParameter, "unsupported hcm action: %s", a.Basic.Action)
 	}
@@ -0,0 +1,158 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package auth
+
+import (
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/iam/client"
+	"hcm/pkg/iam/sys"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/serviced"
+	"hcm/pkg/tools/maps"
+)
+
+const (
+	policyListPageSize = 500
+	policySubjectUser  = "user"
+)
+
+// PolicyWatcher watch the iam policy changes by comparing the policy versions periodically, and invalidate the
+// authorize cache of the users whose policies changed. policies granted to user groups or departments can not be
+// resolved to users, so all the cache are invalidated when they changed.
+type PolicyWatcher struct {
+	sys      *sys.Sys
+	cache    *DecisionCache
+	interval time.Duration
+	// policies maps action id to the policies of the action, key is policy id.
+	policies map[client.ActionID]map[int64]client.PolicyResult
+}
+
+// NewPolicyWatcher new iam policy watcher.
+func NewPolicyWatcher(iamSys *sys.Sys, cache *DecisionCache, intervalSec uint) *PolicyWatcher {
+	return &PolicyWatcher{
+		sys:      iamSys,
+		cache:    cache,
+		interval: time.Duration(intervalSec) * time.Second,
+		policies: make(map[client.ActionID]map[int64]client.PolicyResult),
+	}
+}
+
+// Run check the iam policy changes periodically on master auth server, the invalidation is broadcast to all the
+// auth server instances by the cache.
+func (w *PolicyWatcher) Run(state serviced.State) {
+	for {
+		time.Sleep(w.interval)
+
+		if !state.IsMaster() {
+			// the policies may change when not master, they are loaded as baseline again after becoming master.
+			w.policies = make(map[client.ActionID]map[int64]client.PolicyResult)
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		users, all := w.check(kt)
+		if !all && len(users) == 0 {
+			continue
+		}
+
+		logs.Infof("iam policies changed, invalidate auth cache, all: %v, users: %v, rid: %s", all, users, kt.Rid)
+		if all {
+			users = nil
+		}
+		if err := w.cache.Invalidate(kt, users...); err != nil {
+			logs.Errorf("invalidate auth cache after iam policies changed failed, err: %v, rid: %s", err, kt.Rid)
+		}
+	}
+}
+
+// check the policy changes of all the hcm actions, returns the users whose policies changed, or all is true if
+// policies of user groups or departments changed.
+func (w *PolicyWatcher) check(kt *kit.Kit) (users []string, all bool) {
+	changed := make(map[string]struct{})
+	for _, action := range sys.GenerateStaticActions() {
+		current, err := w.listPolicies(kt, action.ID)
+		if err != nil {
+			logs.Errorf("list iam policies failed, err: %v, action: %s, rid: %s", err, action.ID, kt.Rid)
+			continue
+		}
+
+		previous, exists := w.policies[action.ID]
+		w.policies[action.ID] = current
+		if !exists {
+			continue
+		}
+
+		for _, subject := range diffPolicySubjects(previous, current) {
+			if subject.Type != policySubjectUser {
+				all = true
+				continue
+			}
+			changed[subject.ID] = struct{}{}
+		}
+	}
+
+	return maps.Keys(changed), all
+}
+
+func (w *PolicyWatcher) listPolicies(kt *kit.Kit, actionID client.ActionID) (map[int64]client.PolicyResult,
+	error) {
+
+	policies := make(map[int64]client.PolicyResult)
+	params := &client.ListPoliciesParams{ActionID: actionID, PageSize: policyListPageSize,
+		Timestamp: time.Now().Unix()}
+	for params.Page = 1; ; params.Page++ {
+		result, err := w.sys.ListPolicies(kt.Ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, one := range result.Results {
+			if one != nil {
+				policies[one.ID] = *one
+			}
+		}
+
+		if len(result.Results) < policyListPageSize || int64(len(policies)) >= result.Count {
+			return policies, nil
+		}
+	}
+}
+
+// diffPolicySubjects returns the subjects of the policies that are added, deleted or updated.
+func diffPolicySubjects(previous, current map[int64]client.PolicyResult) []client.PolicySubject {
+	subjects := make([]client.PolicySubject, 0)
+	for id, policy := range current {
+		old, exists := previous[id]
+		if !exists || old.Version != policy.Version || old.ExpiredAt != policy.ExpiredAt {
+			subjects = append(subjects, policy.Subject)
+		}
+	}
+
+	for id, policy := range previous {
+		if _, exists := current[id]; !exists {
+			subjects = append(subjects, policy.Subject)
+		}
+	}
+
+	return subjects
+}
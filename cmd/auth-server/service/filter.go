@@ -45,6 +45,7 @@ const (
 	authModule    moduleType = "auth" // auth module.
 	initialModule moduleType = "init" // initial hcm auth model in iam module.
 	iamModule     moduleType = "iam"  // iam callback module.
+	rbacModule    moduleType = "rbac" // local rbac management module.
 )
 
 // restFilter returns auth server restful request filter.
@@ -70,7 +71,7 @@ func (s *Service) restFilter() restful.FilterFunction {
 				return
 			}
 
-		case authModule:
+		case authModule, rbacModule:
 			if err := authRequestFilter(w, r); err != nil {
 				fmt.Fprintf(w, errf.Error(err).Error())
 				return
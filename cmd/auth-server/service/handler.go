@@ -108,8 +108,12 @@ func (s *Service) apiSet() *restful.Container {
 		WebService: ws,
 	}
 
-	s.initial.InitInitialService(c)
-	s.iam.InitIAMService(c)
+	if s.rbac != nil {
+		s.rbac.InitRbacService(c)
+	} else {
+		s.initial.InitInitialService(c)
+		s.iam.InitIAMService(c)
+	}
 	s.auth.InitAuthService(c)
 
 	return restful.NewContainer().Add(c.WebService)
@@ -0,0 +1,100 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package rbac
+
+import (
+	"strconv"
+
+	coreauth "hcm/pkg/api/core/auth"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/iam/client"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/tools/slice"
+)
+
+// wildcard matches all resource types or actions.
+const wildcard = "*"
+
+// matchTypeAction check if the policy is related to the resource type and action.
+func matchTypeAction(policy *coreauth.RolePolicy, resType meta.ResourceType, action meta.Action) bool {
+	if policy.ResType != wildcard && policy.ResType != resType {
+		return false
+	}
+
+	return policy.Action == wildcard || policy.Action == action
+}
+
+// matchScope check if the resource is in the scope of the policy.
+func matchScope(policy *coreauth.RolePolicy, res *meta.ResourceAttribute) bool {
+	switch policy.Scope {
+	case enumor.AuthScopeAny:
+		return true
+	case enumor.AuthScopeBiz:
+		return res.BizID > 0 && policy.ScopeID == strconv.FormatInt(res.BizID, 10)
+	case enumor.AuthScopeResource:
+		return len(res.ResourceID) != 0 && policy.ScopeID == res.ResourceID
+	default:
+		return false
+	}
+}
+
+// authorize check if the policies grant the resource. if exact is false, the same as iam authorize any, user is
+// authorized when he has any policy of the resource type and action, regardless of the scope.
+func authorize(policies []coreauth.RolePolicy, res *meta.ResourceAttribute, exact bool) bool {
+	for index := range policies {
+		policy := &policies[index]
+		if !matchTypeAction(policy, res.Type, res.Action) {
+			continue
+		}
+
+		if !exact || matchScope(policy, res) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// authorizedInstances get authorized instance ids of the resource type and action from policies.
+func authorizedInstances(policies []coreauth.RolePolicy, resType meta.ResourceType,
+	action meta.Action) *client.AuthorizeList {
+
+	ids := make([]string, 0)
+	for index := range policies {
+		policy := &policies[index]
+		if !matchTypeAction(policy, resType, action) {
+			continue
+		}
+
+		switch policy.Scope {
+		case enumor.AuthScopeAny:
+			return &client.AuthorizeList{IsAny: true}
+		case enumor.AuthScopeBiz:
+			// biz instances are authorized by biz id
+			if resType == meta.Biz {
+				ids = append(ids, policy.ScopeID)
+			}
+		case enumor.AuthScopeResource:
+			ids = append(ids, policy.ScopeID)
+		}
+	}
+
+	return &client.AuthorizeList{Ids: slice.Unique(ids)}
+}
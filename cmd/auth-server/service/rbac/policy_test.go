@@ -0,0 +1,69 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package rbac
+
+import (
+	"testing"
+
+	coreauth "hcm/pkg/api/core/auth"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/iam/meta"
+)
+
+func TestAuthorize(t *testing.T) {
+	policies := []coreauth.RolePolicy{
+		{ResType: meta.Cvm, Action: meta.Find, Scope: enumor.AuthScopeBiz, ScopeID: "100"},
+		{ResType: meta.Account, Action: meta.Find, Scope: enumor.AuthScopeResource, ScopeID: "00000001"},
+		{ResType: meta.Biz, Action: wildcard, Scope: enumor.AuthScopeBiz, ScopeID: "200"},
+	}
+
+	cases := []struct {
+		res    meta.ResourceAttribute
+		exact  bool
+		expect bool
+	}{
+		{res: newRes(meta.Cvm, meta.Find, "", 100), exact: true, expect: true},
+		{res: newRes(meta.Cvm, meta.Find, "", 101), exact: true, expect: false},
+		{res: newRes(meta.Cvm, meta.Delete, "", 100), exact: true, expect: false},
+		{res: newRes(meta.Cvm, meta.Find, "", 0), exact: false, expect: true},
+		{res: newRes(meta.Account, meta.Find, "00000001", 0), exact: true, expect: true},
+		{res: newRes(meta.Account, meta.Find, "", 0), exact: true, expect: false},
+		{res: newRes(meta.Biz, meta.Access, "", 200), exact: true, expect: true},
+	}
+	for idx, c := range cases {
+		if got := authorize(policies, &c.res, c.exact); got != c.expect {
+			t.Errorf("case %d: authorize %+v got %v, expect: %v", idx, c.res.Basic, got, c.expect)
+		}
+	}
+
+	list := authorizedInstances(policies, meta.Biz, meta.Access)
+	if list.IsAny || len(list.Ids) != 1 || list.Ids[0] != "200" {
+		t.Errorf("got unexpected authorized biz list: %+v", list)
+	}
+
+	policies = append(policies, coreauth.RolePolicy{ResType: wildcard, Action: meta.Find, Scope: enumor.AuthScopeAny})
+	if list = authorizedInstances(policies, meta.Account, meta.Find); !list.IsAny {
+		t.Errorf("account find should be authorized any, got: %+v", list)
+	}
+}
+
+func newRes(resType meta.ResourceType, action meta.Action, id string, bizID int64) meta.ResourceAttribute {
+	return meta.ResourceAttribute{Basic: &meta.Basic{Type: resType, Action: action, ResourceID: id}, BizID: bizID}
+}
@@ -0,0 +1,236 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package rbac 本地rbac鉴权后端，使用存储在hcm数据库中的角色、权限策略、角色绑定进行鉴权，不依赖蓝鲸权限中心
+package rbac
+
+import (
+	"hcm/cmd/auth-server/service/auth"
+	authserver "hcm/pkg/api/auth-server"
+	"hcm/pkg/api/core"
+	coreauth "hcm/pkg/api/core/auth"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/client"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/iam/sys"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+// RBAC is the local rbac authorize backend.
+type RBAC struct {
+	ds *dataservice.Client
+	// admins are the super administrators who are authorized to do all operations.
+	admins map[string]struct{}
+	// cache is the authorize decision cache which need to be invalidated when rbac data changes, nil if disabled.
+	cache *auth.DecisionCache
+}
+
+var _ auth.Backend = new(RBAC)
+
+// NewRBAC new local rbac authorize backend.
+func NewRBAC(ds *dataservice.Client, admins []string, cache *auth.DecisionCache) (*RBAC, error) {
+	if ds == nil {
+		return nil, errf.New(errf.InvalidParameter, "data client is nil")
+	}
+
+	r := &RBAC{
+		ds:     ds,
+		admins: make(map[string]struct{}, len(admins)),
+		cache:  cache,
+	}
+	for _, admin := range admins {
+		r.admins[admin] = struct{}{}
+	}
+
+	return r, nil
+}
+
+// IsAdmin check if the user is the super administrator of local rbac.
+func (r *RBAC) IsAdmin(user string) bool {
+	_, exists := r.admins[user]
+	return exists
+}
+
+// AuthorizeBatch authorize resource batch with the policies of the roles bound to the user.
+func (r *RBAC) AuthorizeBatch(kt *kit.Kit, user *meta.UserInfo, resources []meta.ResourceAttribute, exact bool) (
+	[]meta.Decision, error) {
+
+	if user == nil || len(user.UserName) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "user is required")
+	}
+
+	decisions := make([]meta.Decision, len(resources))
+	if r.IsAdmin(user.UserName) {
+		for index := range decisions {
+			decisions[index].Authorized = true
+		}
+		return decisions, nil
+	}
+
+	var policies []coreauth.RolePolicy
+	for index := range resources {
+		skip, err := isSkipped(&resources[index])
+		if err != nil {
+			logs.Errorf("check if resource is skipped failed, err: %v, resource: %+v, rid: %s", err,
+				resources[index], kt.Rid)
+			return nil, err
+		}
+
+		if skip {
+			decisions[index].Authorized = true
+			continue
+		}
+
+		// load user's policies lazily, only once
+		if policies == nil {
+			policies, err = r.listUserPolicies(kt, user.UserName)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		decisions[index].Authorized = authorize(policies, &resources[index], exact)
+	}
+
+	return decisions, nil
+}
+
+// isSkipped check if the resource do not need to be authorized, keep the same as iam backend.
+func isSkipped(res *meta.ResourceAttribute) (bool, error) {
+	if res.Basic == nil {
+		return false, errf.New(errf.InvalidParameter, "resource basic is not set")
+	}
+
+	if res.Basic.Action == meta.SkipAction {
+		return true, nil
+	}
+
+	// unsupported resources are rejected by adaptor, which is the same as iam backend
+	action, _, err := auth.AdaptAuthOptions(res)
+	if err != nil {
+		return false, err
+	}
+
+	return action == sys.Skip, nil
+}
+
+// ListAuthorizedInstances list authorized instance ids of the resource type and action.
+func (r *RBAC) ListAuthorizedInstances(kt *kit.Kit, req *authserver.ListAuthorizedInstancesReq) (
+	*client.AuthorizeList, error) {
+
+	if req.User == nil || len(req.User.UserName) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "user is required")
+	}
+
+	if r.IsAdmin(req.User.UserName) {
+		return &client.AuthorizeList{IsAny: true}, nil
+	}
+
+	policies, err := r.listUserPolicies(kt, req.User.UserName)
+	if err != nil {
+		return nil, err
+	}
+
+	return authorizedInstances(policies, req.Type, req.Action), nil
+}
+
+// RegisterResourceCreatorAction local rbac do not grant creator permissions automatically, the permissions are
+// granted by roles explicitly.
+func (r *RBAC) RegisterResourceCreatorAction(kt *kit.Kit, req *authserver.RegisterResourceCreatorActionReq) (
+	[]client.CreatorActionPolicy, error) {
+
+	logs.V(3).Infof("local rbac skip register resource creator action, req: %+v, rid: %s", req, kt.Rid)
+	return make([]client.CreatorActionPolicy, 0), nil
+}
+
+// GetApplyPermUrl local rbac has no permission apply page, permissions are granted by administrators.
+func (r *RBAC) GetApplyPermUrl(_ *kit.Kit, _ *meta.IamPermission) (string, error) {
+	return "", nil
+}
+
+// invalidateCache invalidate the authorize cache after rbac data changed, empty users means all users.
+func (r *RBAC) invalidateCache(kt *kit.Kit, users ...string) {
+	if r.cache == nil {
+		return
+	}
+
+	if err := r.cache.Invalidate(kt, users...); err != nil {
+		logs.Errorf("invalidate auth cache failed, err: %v, users: %v, rid: %s", err, users, kt.Rid)
+	}
+}
+
+// listUserPolicies list all policies of the roles bound to the user.
+func (r *RBAC) listUserPolicies(kt *kit.Kit, user string) ([]coreauth.RolePolicy, error) {
+	bindings, err := listAll(func(page *core.BasePage) ([]coreauth.RoleBinding, error) {
+		req := &core.ListReq{Filter: tools.EqualExpression("user", user), Page: page}
+		result, err := r.ds.Global.AuthRbac.ListBinding(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		return result.Details, nil
+	})
+	if err != nil {
+		logs.Errorf("list auth role binding failed, err: %v, user: %s, rid: %s", err, user, kt.Rid)
+		return nil, err
+	}
+
+	policies := make([]coreauth.RolePolicy, 0)
+	roleIDs := slice.Unique(slice.Map(bindings, func(one coreauth.RoleBinding) string { return one.RoleID }))
+	for _, ids := range slice.Split(roleIDs, int(core.DefaultMaxPageLimit)) {
+		details, err := listAll(func(page *core.BasePage) ([]coreauth.RolePolicy, error) {
+			req := &core.ListReq{Filter: tools.ContainersExpression("role_id", ids), Page: page}
+			result, err := r.ds.Global.AuthRbac.ListPolicy(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		})
+		if err != nil {
+			logs.Errorf("list auth role policy failed, err: %v, role ids: %v, rid: %s", err, ids, kt.Rid)
+			return nil, err
+		}
+		policies = append(policies, details...)
+	}
+
+	return policies, nil
+}
+
+func listAll[T any](list func(page *core.BasePage) ([]T, error)) ([]T, error) {
+	page := core.NewDefaultBasePage()
+	result := make([]T, 0)
+	for {
+		details, err := list(page)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, details...)
+		if uint(len(details)) < page.Limit {
+			break
+		}
+
+		page.Start += uint32(page.Limit)
+	}
+
+	return result, nil
+}
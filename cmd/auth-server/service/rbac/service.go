@@ -0,0 +1,279 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package rbac
+
+import (
+	"net/http"
+
+	"hcm/cmd/auth-server/service/capability"
+	authserver "hcm/pkg/api/auth-server"
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	dsauth "hcm/pkg/api/data-service/auth"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// InitRbacService initialize the local rbac roles, policies and bindings management service, only the super
+// administrators are allowed to manage them.
+func (r *RBAC) InitRbacService(c *capability.Capability) {
+	h := rest.NewHandler()
+
+	h.Add("CreateRbacRole", http.MethodPost, "/rbac/roles/create", r.CreateRole)
+	h.Add("UpdateRbacRole", http.MethodPatch, "/rbac/roles/{id}", r.UpdateRole)
+	h.Add("ListRbacRole", http.MethodPost, "/rbac/roles/list", r.ListRole)
+	h.Add("BatchDeleteRbacRole", http.MethodDelete, "/rbac/roles/batch", r.BatchDeleteRole)
+
+	h.Add("CreateRbacPolicy", http.MethodPost, "/rbac/policies/create", r.CreatePolicy)
+	h.Add("ListRbacPolicy", http.MethodPost, "/rbac/policies/list", r.ListPolicy)
+	h.Add("BatchDeleteRbacPolicy", http.MethodDelete, "/rbac/policies/batch", r.BatchDeletePolicy)
+
+	h.Add("CreateRbacBinding", http.MethodPost, "/rbac/bindings/create", r.CreateBinding)
+	h.Add("ListRbacBinding", http.MethodPost, "/rbac/bindings/list", r.ListBinding)
+	h.Add("BatchDeleteRbacBinding", http.MethodDelete, "/rbac/bindings/batch", r.BatchDeleteBinding)
+
+	h.Load(c.WebService)
+}
+
+func (r *RBAC) checkAdmin(cts *rest.Contexts) error {
+	if !r.IsAdmin(cts.Kit.User) {
+		logs.Errorf("user %s is not local rbac administrator, rid: %s", cts.Kit.User, cts.Kit.Rid)
+		return errf.New(errf.PermissionDenied, "only administrators can manage local rbac")
+	}
+
+	return nil
+}
+
+// CreateRole create local rbac role.
+func (r *RBAC) CreateRole(cts *rest.Contexts) (interface{}, error) {
+	if err := r.checkAdmin(cts); err != nil {
+		return nil, err
+	}
+
+	req := new(dsauth.RoleCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return r.ds.Global.AuthRbac.CreateRole(cts.Kit, req)
+}
+
+// UpdateRole update local rbac role.
+func (r *RBAC) UpdateRole(cts *rest.Contexts) (interface{}, error) {
+	if err := r.checkAdmin(cts); err != nil {
+		return nil, err
+	}
+
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(dsauth.RoleUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return nil, r.ds.Global.AuthRbac.UpdateRole(cts.Kit, id, req)
+}
+
+// ListRole list local rbac role.
+func (r *RBAC) ListRole(cts *rest.Contexts) (interface{}, error) {
+	if err := r.checkAdmin(cts); err != nil {
+		return nil, err
+	}
+
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return r.ds.Global.AuthRbac.ListRole(cts.Kit, req)
+}
+
+// BatchDeleteRole batch delete local rbac role, the policies and bindings of the roles are deleted together.
+func (r *RBAC) BatchDeleteRole(cts *rest.Contexts) (interface{}, error) {
+	if err := r.checkAdmin(cts); err != nil {
+		return nil, err
+	}
+
+	req := new(authserver.RbacBatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	delReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", req.IDs)}
+	if err := r.ds.Global.AuthRbac.BatchDeleteRole(cts.Kit, delReq); err != nil {
+		return nil, err
+	}
+
+	r.invalidateCache(cts.Kit)
+	return nil, nil
+}
+
+// CreatePolicy create local rbac role policies.
+func (r *RBAC) CreatePolicy(cts *rest.Contexts) (interface{}, error) {
+	if err := r.checkAdmin(cts); err != nil {
+		return nil, err
+	}
+
+	req := new(dsauth.RolePolicyBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	result, err := r.ds.Global.AuthRbac.BatchCreatePolicy(cts.Kit, req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.invalidateCache(cts.Kit)
+	return result, nil
+}
+
+// ListPolicy list local rbac role policies.
+func (r *RBAC) ListPolicy(cts *rest.Contexts) (interface{}, error) {
+	if err := r.checkAdmin(cts); err != nil {
+		return nil, err
+	}
+
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return r.ds.Global.AuthRbac.ListPolicy(cts.Kit, req)
+}
+
+// BatchDeletePolicy batch delete local rbac role policies.
+func (r *RBAC) BatchDeletePolicy(cts *rest.Contexts) (interface{}, error) {
+	if err := r.checkAdmin(cts); err != nil {
+		return nil, err
+	}
+
+	req := new(authserver.RbacBatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	delReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", req.IDs)}
+	if err := r.ds.Global.AuthRbac.BatchDeletePolicy(cts.Kit, delReq); err != nil {
+		return nil, err
+	}
+
+	r.invalidateCache(cts.Kit)
+	return nil, nil
+}
+
+// CreateBinding bind local rbac role to users.
+func (r *RBAC) CreateBinding(cts *rest.Contexts) (interface{}, error) {
+	if err := r.checkAdmin(cts); err != nil {
+		return nil, err
+	}
+
+	req := new(dsauth.RoleBindingBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	result, err := r.ds.Global.AuthRbac.BatchCreateBinding(cts.Kit, req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.invalidateCache(cts.Kit, req.Users...)
+	return result, nil
+}
+
+// ListBinding list local rbac role bindings.
+func (r *RBAC) ListBinding(cts *rest.Contexts) (interface{}, error) {
+	if err := r.checkAdmin(cts); err != nil {
+		return nil, err
+	}
+
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return r.ds.Global.AuthRbac.ListBinding(cts.Kit, req)
+}
+
+// BatchDeleteBinding batch delete local rbac role bindings.
+func (r *RBAC) BatchDeleteBinding(cts *rest.Contexts) (interface{}, error) {
+	if err := r.checkAdmin(cts); err != nil {
+		return nil, err
+	}
+
+	req := new(authserver.RbacBatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	delReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", req.IDs)}
+	if err := r.ds.Global.AuthRbac.BatchDeleteBinding(cts.Kit, delReq); err != nil {
+		return nil, err
+	}
+
+	r.invalidateCache(cts.Kit)
+	return nil, nil
+}
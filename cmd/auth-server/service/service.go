@@ -29,6 +29,7 @@ import (
 	"hcm/cmd/auth-server/service/auth"
 	"hcm/cmd/auth-server/service/iam"
 	"hcm/cmd/auth-server/service/initial"
+	"hcm/cmd/auth-server/service/rbac"
 	"hcm/pkg/cc"
 	apicli "hcm/pkg/client"
 	dataservice "hcm/pkg/client/data-service"
@@ -41,6 +42,8 @@ import (
 	"hcm/pkg/serviced"
 	"hcm/pkg/thirdparty/esb"
 	"hcm/pkg/tools/ssl"
+
+	etcd3 "go.etcd.io/etcd/client/v3"
 )
 
 // Service do all the data service's work
@@ -53,20 +56,24 @@ type Service struct {
 	disableAuth bool
 	// disableWriteOpt defines which biz's write operation needs to be disabled
 	disableWriteOpt *options.DisableWriteOption
+	// authorize defines the authorize backend settings.
+	authorize cc.Authorize
 
-	// iam logic module.
+	// iam logic module, only used by iam authorize backend.
 	iam *iam.IAM
-	// initial logic module.
+	// initial logic module, only used by iam authorize backend.
 	initial *initial.Initial
+	// rbac logic module, only used by local authorize backend.
+	rbac *rbac.RBAC
 	// auth logic module.
 	auth *auth.Auth
 }
 
 // NewService create a service instance.
-func NewService(sd serviced.Discover, iamSettings cc.IAM, esbSettings cc.Esb, disableAuth bool,
-	disableWriteOpt *options.DisableWriteOption) (*Service, error) {
+func NewService(sd serviced.Discover, iamSettings cc.IAM, esbSettings cc.Esb, authorize cc.Authorize,
+	disableAuth bool, disableWriteOpt *options.DisableWriteOption) (*Service, error) {
 
-	cli, err := newClientSet(sd, iamSettings, esbSettings, disableAuth)
+	cli, err := newClientSet(sd, iamSettings, esbSettings, authorize, disableAuth)
 	if err != nil {
 		return nil, fmt.Errorf("new client set failed, err: %v", err)
 	}
@@ -81,6 +88,7 @@ func NewService(sd serviced.Discover, iamSettings cc.IAM, esbSettings cc.Esb, di
 		state:           state,
 		disableAuth:     disableAuth,
 		disableWriteOpt: disableWriteOpt,
+		authorize:       authorize,
 	}
 
 	if err = s.initLogicModule(); err != nil {
@@ -90,7 +98,8 @@ func NewService(sd serviced.Discover, iamSettings cc.IAM, esbSettings cc.Esb, di
 	return s, nil
 }
 
-func newClientSet(sd serviced.Discover, iamSettings cc.IAM, esbSettings cc.Esb, disableAuth bool) (*ClientSet, error) {
+func newClientSet(sd serviced.Discover, iamSettings cc.IAM, esbSettings cc.Esb, authorize cc.Authorize,
+	disableAuth bool) (*ClientSet, error) {
 
 	logs.Infof("start initialize the client set.")
 
@@ -114,6 +123,22 @@ func newClientSet(sd serviced.Discover, iamSettings cc.IAM, esbSettings cc.Esb,
 
 	logs.Infof("initialize system api client set success.")
 
+	esbClient, err := esb.NewClient(&esbSettings, metrics.Register())
+	if err != nil {
+		return nil, err
+	}
+
+	cs := &ClientSet{
+		ds:     apiClientSet.DataService(),
+		esbCli: esbClient,
+	}
+
+	// local authorize backend do not depend on iam.
+	if authorize.Backend == cc.LocalAuthBackend {
+		logs.Infof("initialize the client set success, using local authorize backend.")
+		return cs, nil
+	}
+
 	cfg := &client.Config{
 		Address:   iamSettings.Endpoints,
 		AppCode:   iamSettings.AppCode,
@@ -138,23 +163,14 @@ func newClientSet(sd serviced.Discover, iamSettings cc.IAM, esbSettings cc.Esb,
 		return nil, fmt.Errorf("new iam logics failed, err: %v", err)
 	}
 
-	esbClient, err := esb.NewClient(&esbSettings, metrics.Register())
-	if err != nil {
-		return nil, err
-	}
-
 	authSdk, err := pkgauth.NewAuth(iamCli, iamLgc, esbClient)
 	if err != nil {
 		return nil, fmt.Errorf("new iam auth sdk failed, err: %v", err)
 	}
 	logs.Infof("initialize iam auth sdk success.")
 
-	cs := &ClientSet{
-		ds:     apiClientSet.DataService(),
-		sys:    iamSys,
-		auth:   authSdk,
-		esbCli: esbClient,
-	}
+	cs.sys = iamSys
+	cs.auth = authSdk
 	logs.Infof("initialize the client set success.")
 	return cs, nil
 }
@@ -163,9 +179,9 @@ func newClientSet(sd serviced.Discover, iamSettings cc.IAM, esbSettings cc.Esb,
 type ClientSet struct {
 	// data service's sys api
 	ds *dataservice.Client
-	// iam sys related operate.
+	// iam sys related operate, only used by iam authorize backend.
 	sys *sys.Sys
-	// auth related operate.
+	// iam auth related operate, only used by iam authorize backend.
 	auth pkgauth.Authorizer
 	// esb client.
 	esbCli esb.Client
@@ -174,18 +190,53 @@ type ClientSet struct {
 // initLogicModule init logic module.
 func (s *Service) initLogicModule() error {
 	var err error
+	var backend auth.Backend
 
-	s.initial, err = initial.NewInitial(s.client.sys, s.disableAuth)
-	if err != nil {
-		return err
+	var cache *auth.DecisionCache
+	if !s.authorize.Cache.Disabled {
+		etcdOpt, err := cc.AuthServer().Service.Etcd.ToConfig()
+		if err != nil {
+			return fmt.Errorf("get etcd config failed, err: %v", err)
+		}
+
+		etcdCli, err := etcd3.New(etcdOpt)
+		if err != nil {
+			return fmt.Errorf("new etcd client failed, err: %v", err)
+		}
+
+		cache = auth.NewDecisionCache(s.authorize.Cache, etcdCli)
+		logs.Infof("authorize cache is enabled, ttl: %ds, deny ttl: %ds.", s.authorize.Cache.TTLSec,
+			s.authorize.Cache.DenyTTLSec)
 	}
 
-	s.iam, err = iam.NewIAM(s.client.ds, s.client.sys, s.disableAuth)
-	if err != nil {
-		return err
+	switch s.authorize.Backend {
+	case cc.LocalAuthBackend:
+		s.rbac, err = rbac.NewRBAC(s.client.ds, s.authorize.Admins, cache)
+		if err != nil {
+			return err
+		}
+		backend = s.rbac
+	default:
+		s.initial, err = initial.NewInitial(s.client.sys, s.disableAuth)
+		if err != nil {
+			return err
+		}
+
+		s.iam, err = iam.NewIAM(s.client.ds, s.client.sys, s.disableAuth)
+		if err != nil {
+			return err
+		}
+		backend = auth.NewIAMBackend(s.client.auth)
+
+		// permissions granted or revoked in iam are not noticed by auth server, watch the policy changes.
+		if cache != nil {
+			watcher := auth.NewPolicyWatcher(s.client.sys, cache, s.authorize.Cache.PolicyCheckIntervalSec)
+			go watcher.Run(s.state)
+		}
 	}
 
-	s.auth, err = auth.NewAuth(s.client.auth, s.client.ds, s.disableAuth, s.client.esbCli, s.disableWriteOpt)
+	s.auth, err = auth.NewAuth(backend, s.client.ds, s.disableAuth, s.client.esbCli, s.disableWriteOpt,
+		cache)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,260 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package applicationpolicy 申请单策略评估
+package applicationpolicy
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	coreapplication "hcm/pkg/api/core/application"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/times"
+)
+
+// Evaluate 使用生效的策略评估申请单的事实数据。
+// require策略要求申请单满足全部条件，事实数据中不存在的字段：策略限定了申请类型时视为违反，否则视为不适用；
+// auto_approve策略在申请单满足全部条件时命中，事实数据中不存在的字段视为不满足。
+// 存在违反的require策略时驳回申请单，否则命中任一auto_approve策略时免审批，其余情况按审批流程审批。
+func Evaluate(policies []*coreapplication.ApplicationPolicy,
+	facts coreapplication.PolicyFacts) *coreapplication.PolicyEvaluation {
+
+	evaluation := &coreapplication.PolicyEvaluation{
+		Result:      enumor.PolicyEvaluationPass,
+		Facts:       facts,
+		Policies:    make([]coreapplication.PolicyEvaluationItem, 0, len(policies)),
+		EvaluatedAt: times.ConvStdTimeFormat(times.ConvStdTimeNow()),
+	}
+
+	denied, autoApproved := false, false
+	for _, policy := range policies {
+		item := evaluatePolicy(policy, facts)
+		evaluation.Policies = append(evaluation.Policies, item)
+
+		switch policy.Effect {
+		case enumor.RequirePolicyEffect:
+			if !item.Matched {
+				denied = true
+			}
+		case enumor.AutoApprovePolicyEffect:
+			if item.Matched {
+				autoApproved = true
+			}
+		}
+	}
+
+	switch {
+	case denied:
+		evaluation.Result = enumor.PolicyEvaluationDeny
+	case autoApproved:
+		evaluation.Result = enumor.PolicyEvaluationAutoApprove
+	}
+
+	return evaluation
+}
+
+func evaluatePolicy(policy *coreapplication.ApplicationPolicy,
+	facts coreapplication.PolicyFacts) coreapplication.PolicyEvaluationItem {
+
+	item := coreapplication.PolicyEvaluationItem{
+		PolicyID: policy.ID,
+		Name:     policy.Name,
+		Effect:   policy.Effect,
+		Matched:  true,
+	}
+
+	reasons := make([]string, 0)
+	for _, condition := range policy.Conditions {
+		actual, exists := facts.Get(condition.Field)
+		if !exists && condition.Op != enumor.PolicyOpExists {
+			switch {
+			case policy.Effect == enumor.AutoApprovePolicyEffect:
+				item.Matched = false
+			case len(policy.ApplicationType) != 0:
+				// 限定了申请类型的require策略，申请单缺少条件字段时无法证明满足策略，视为违反
+				item.Matched = false
+				reasons = append(reasons, fmt.Sprintf("policy %s requires %s %s %v, but %s is missing",
+					policy.Name, condition.Field, condition.Op, condition.Value, condition.Field))
+			default:
+				// 对所有申请类型生效的require策略只约束申请单中存在的字段，例如cpu限制不作用于硬盘申请
+			}
+			continue
+		}
+
+		if matchCondition(condition, actual, exists) {
+			continue
+		}
+
+		item.Matched = false
+		reasons = append(reasons, fmt.Sprintf("policy %s requires %s %s %v, actual: %v", policy.Name,
+			condition.Field, condition.Op, condition.Value, actual))
+	}
+
+	// 只有违反require策略时才需要记录原因
+	if policy.Effect == enumor.RequirePolicyEffect && !item.Matched {
+		if len(policy.Message) != 0 {
+			reasons = append([]string{policy.Message}, reasons...)
+		}
+		item.Reasons = reasons
+	}
+
+	return item
+}
+
+// matchCondition 判断事实数据是否满足条件，事实数据为列表时（如业务ID列表）需要每个元素都满足条件，
+// 事实数据不存在、为空值或空列表时无法证明满足条件，视为不满足
+func matchCondition(condition coreapplication.PolicyCondition, actual interface{}, exists bool) bool {
+	if condition.Op == enumor.PolicyOpExists {
+		return exists && !isEmptyValue(actual)
+	}
+
+	list := toList(actual)
+	if !exists || len(list) == 0 {
+		return false
+	}
+
+	for _, one := range list {
+		if !matchValue(condition.Op, one, condition.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchValue(op enumor.PolicyConditionOp, actual, expect interface{}) bool {
+	switch op {
+	case enumor.PolicyOpEqual:
+		return equal(actual, expect)
+	case enumor.PolicyOpNotEqual:
+		return !equal(actual, expect)
+	case enumor.PolicyOpIn:
+		return contains(toList(expect), actual)
+	case enumor.PolicyOpNotIn:
+		return !contains(toList(expect), actual)
+	case enumor.PolicyOpGreaterThan, enumor.PolicyOpGreaterThanEqual, enumor.PolicyOpLessThan,
+		enumor.PolicyOpLessThanEqual:
+		return compare(op, actual, expect)
+	default:
+		return false
+	}
+}
+
+func compare(op enumor.PolicyConditionOp, actual, expect interface{}) bool {
+	a, ok := toFloat(actual)
+	if !ok {
+		return false
+	}
+
+	e, ok := toFloat(expect)
+	if !ok {
+		return false
+	}
+
+	switch op {
+	case enumor.PolicyOpGreaterThan:
+		return a > e
+	case enumor.PolicyOpGreaterThanEqual:
+		return a >= e
+	case enumor.PolicyOpLessThan:
+		return a < e
+	case enumor.PolicyOpLessThanEqual:
+		return a <= e
+	default:
+		return false
+	}
+}
+
+func contains(list []interface{}, actual interface{}) bool {
+	for _, one := range list {
+		if equal(actual, one) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// equal 数值按浮点数比较，其余类型按字符串比较，兼容策略条件值经过json反序列化后的类型
+func equal(actual, expect interface{}) bool {
+	a, aOk := toFloat(actual)
+	e, eOk := toFloat(expect)
+	if aOk && eOk {
+		return a == e
+	}
+
+	return fmt.Sprint(actual) == fmt.Sprint(expect)
+}
+
+func toFloat(val interface{}) (float64, bool) {
+	if val == nil {
+		return 0, false
+	}
+
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		f, err := strconv.ParseFloat(v.String(), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func toList(val interface{}) []interface{} {
+	if val == nil {
+		return make([]interface{}, 0)
+	}
+
+	v := reflect.ValueOf(val)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return []interface{}{val}
+	}
+
+	list := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		list = append(list, v.Index(i).Interface())
+	}
+
+	return list
+}
+
+func isEmptyValue(val interface{}) bool {
+	if val == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
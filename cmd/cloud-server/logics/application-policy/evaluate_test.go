@@ -0,0 +1,160 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package applicationpolicy
+
+import (
+	"testing"
+
+	coreapplication "hcm/pkg/api/core/application"
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestEvaluate(t *testing.T) {
+	policies := []*coreapplication.ApplicationPolicy{
+		{ID: "1", Name: "max_cpu", Effect: enumor.RequirePolicyEffect, Message: "max 32 vCPU per cvm",
+			Conditions: coreapplication.PolicyConditions{{Field: "cpu", Op: enumor.PolicyOpLessThanEqual,
+				Value: 32.0}}},
+		{ID: "2", Name: "families", Effect: enumor.RequirePolicyEffect,
+			Conditions: coreapplication.PolicyConditions{{Field: "instance_family", Op: enumor.PolicyOpIn,
+				Value: []interface{}{"S5", "SA2"}}}},
+		{ID: "3", Name: "owner_tag", Effect: enumor.RequirePolicyEffect,
+			Conditions: coreapplication.PolicyConditions{{Field: "tags.owner", Op: enumor.PolicyOpExists}}},
+		{ID: "4", Name: "small", Effect: enumor.AutoApprovePolicyEffect,
+			Conditions: coreapplication.PolicyConditions{{Field: "cpu", Op: enumor.PolicyOpLessThanEqual, Value: 4.0},
+				{Field: "count", Op: enumor.PolicyOpLessThanEqual, Value: 2.0}}},
+	}
+
+	cases := []struct {
+		name    string
+		facts   coreapplication.PolicyFacts
+		result  enumor.PolicyEvaluationResult
+		reasons int
+	}{
+		{
+			name: "auto approve small cvm",
+			facts: coreapplication.PolicyFacts{"cpu": int64(2), "count": int64(1), "instance_family": "S5",
+				"tags": map[string]string{"owner": "admin"}},
+			result: enumor.PolicyEvaluationAutoApprove,
+		},
+		{
+			name: "pass large cvm",
+			facts: coreapplication.PolicyFacts{"cpu": int64(16), "count": int64(1), "instance_family": "SA2",
+				"tags": map[string]string{"owner": "admin"}},
+			result: enumor.PolicyEvaluationPass,
+		},
+		{
+			name:    "deny violating cvm",
+			facts:   coreapplication.PolicyFacts{"cpu": int64(64), "count": int64(1), "instance_family": "M5"},
+			result:  enumor.PolicyEvaluationDeny,
+			reasons: 4,
+		},
+		{
+			name:   "disk without cpu fact",
+			facts:  coreapplication.PolicyFacts{"count": int64(1), "tags": map[string]string{"owner": "admin"}},
+			result: enumor.PolicyEvaluationPass,
+		},
+	}
+
+	for _, c := range cases {
+		evaluation := Evaluate(policies, c.facts)
+		if evaluation.Result != c.result {
+			t.Errorf("%s got result: %s, expect: %s, evaluation: %+v", c.name, evaluation.Result, c.result,
+				evaluation.Policies)
+		}
+		if len(evaluation.Reasons()) != c.reasons {
+			t.Errorf("%s got reasons: %v, expect count: %d", c.name, evaluation.Reasons(), c.reasons)
+		}
+	}
+}
+
+func TestEvaluateScopedRequireMissingFact(t *testing.T) {
+	policies := []*coreapplication.ApplicationPolicy{
+		{ID: "1", Name: "lb_region", ApplicationType: enumor.CreateLoadBalancer, Effect: enumor.RequirePolicyEffect,
+			Conditions: coreapplication.PolicyConditions{{Field: "region", Op: enumor.PolicyOpIn,
+				Value: []interface{}{"ap-guangzhou"}}}},
+	}
+
+	cases := []struct {
+		name    string
+		facts   coreapplication.PolicyFacts
+		result  enumor.PolicyEvaluationResult
+		reasons int
+	}{
+		{
+			name:   "scoped require with fact",
+			facts:  coreapplication.PolicyFacts{"region": "ap-guangzhou"},
+			result: enumor.PolicyEvaluationPass,
+		},
+		{
+			name:    "scoped require without fact",
+			facts:   coreapplication.PolicyFacts{"count": int64(1)},
+			result:  enumor.PolicyEvaluationDeny,
+			reasons: 1,
+		},
+	}
+
+	for _, c := range cases {
+		evaluation := Evaluate(policies, c.facts)
+		if evaluation.Result != c.result {
+			t.Errorf("%s got result: %s, expect: %s, evaluation: %+v", c.name, evaluation.Result, c.result,
+				evaluation.Policies)
+		}
+		if len(evaluation.Reasons()) != c.reasons {
+			t.Errorf("%s got reasons: %v, expect count: %d", c.name, evaluation.Reasons(), c.reasons)
+		}
+	}
+}
+
+func TestMatchConditionWithList(t *testing.T) {
+	condition := coreapplication.PolicyCondition{Field: "bk_biz_ids", Op: enumor.PolicyOpNotIn,
+		Value: []interface{}{100.0}}
+	if !matchCondition(condition, []int64{1, 2}, true) {
+		t.Errorf("biz ids [1 2] should not in [100]")
+	}
+	if matchCondition(condition, []int64{1, 100}, true) {
+		t.Errorf("biz ids [1 100] should match 100")
+	}
+}
+
+func TestMatchConditionWithEmptyFact(t *testing.T) {
+	conditions := []coreapplication.PolicyCondition{
+		{Field: "bk_biz_ids", Op: enumor.PolicyOpNotIn, Value: []interface{}{100.0}},
+		{Field: "bk_biz_ids", Op: enumor.PolicyOpNotEqual, Value: 100.0},
+		{Field: "bk_biz_ids", Op: enumor.PolicyOpLessThan, Value: 100.0},
+	}
+	facts := []struct {
+		name   string
+		actual interface{}
+		exists bool
+	}{
+		{name: "empty list", actual: []int64{}, exists: true},
+		{name: "nil list", actual: []int64(nil), exists: true},
+		{name: "nil value", actual: nil, exists: true},
+		{name: "missing", actual: nil, exists: false},
+	}
+
+	for _, condition := range conditions {
+		for _, fact := range facts {
+			if matchCondition(condition, fact.actual, fact.exists) {
+				t.Errorf("%s fact should not match condition %s %v", fact.name, condition.Op, condition.Value)
+			}
+		}
+	}
+}
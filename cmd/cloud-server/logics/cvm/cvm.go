@@ -24,7 +24,9 @@ import (
 	"hcm/cmd/cloud-server/logics/audit"
 	"hcm/cmd/cloud-server/logics/disk"
 	"hcm/cmd/cloud-server/logics/eip"
+	proto "hcm/pkg/api/cloud-server/cvm"
 	"hcm/pkg/api/core"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
 	rr "hcm/pkg/api/core/recycle-record"
 	"hcm/pkg/client"
 	"hcm/pkg/criteria/enumor"
@@ -41,8 +43,15 @@ type Interface interface {
 		records []rr.CvmRecycleRecord) (*core.BatchOperateResult, error)
 	GetNotCmdbRecyclableHosts(kt *kit.Kit, bizHostsIds map[int64][]string) ([]string, error)
 	RecyclePreCheck(kt *kit.Kit, infoMap map[string]types.CloudResourceBasicInfo) error
+	RecycleCvm(kt *kit.Kit, infos []proto.CvmRecycleInfo, basicInfoMap map[string]types.CloudResourceBasicInfo) (string,
+		error)
 	BatchFinalizeRelRecord(kt *kit.Kit, resType enumor.CloudResourceType,
 		status enumor.RecycleRecordStatus, resIds []string) error
+	CheckLifecycleStatus(kt *kit.Kit, action enumor.ActionName, ids []string) error
+	ResizePreCheck(kt *kit.Kit, id, instanceType string) (*corecvm.BaseCvm, error)
+	ListAvailableInstanceType(kt *kit.Kit, baseCvm *corecvm.BaseCvm) ([]InstanceTypeSpec, error)
+	ListAvailableInstanceTypeByCharge(kt *kit.Kit, baseCvm *corecvm.BaseCvm, chargeType string) ([]InstanceTypeSpec,
+		error)
 }
 
 type cvm struct {
@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 
+	logicsrecycle "hcm/cmd/cloud-server/logics/recycle"
 	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/pkg/api/cloud-server/recycle"
 	"hcm/pkg/api/core"
@@ -545,9 +546,14 @@ func (c *cvm) getCmdbHostId(kt *kit.Kit, bizID int64,
 	return hostIDs, nil
 }
 
-// RecyclePreCheck  回收预校验，包含主机状态和CC待回收模块检查
+// RecyclePreCheck  回收预校验，包含回收策略销毁前动作、主机状态和CC待回收模块检查
 func (c *cvm) RecyclePreCheck(kt *kit.Kit, basicInfoMap map[string]types.CloudResourceBasicInfo) error {
 
+	// 0. 回收策略的销毁前动作需要支持主机的云厂商，避免主机在没有备份的情况下被销毁
+	if err := c.checkPreDestroyVendor(kt, basicInfoMap); err != nil {
+		return err
+	}
+
 	leftInfo := maps.Clone(basicInfoMap)
 	bizHostsMap := make(map[int64][]string)
 	for id, hostInfo := range leftInfo {
@@ -579,6 +585,31 @@ func (c *cvm) RecyclePreCheck(kt *kit.Kit, basicInfoMap map[string]types.CloudRe
 	return nil
 }
 
+// checkPreDestroyVendor 按业务和云厂商检查主机生效的回收策略的销毁前动作是否支持该云厂商
+func (c *cvm) checkPreDestroyVendor(kt *kit.Kit, basicInfoMap map[string]types.CloudResourceBasicInfo) error {
+	type bizVendor struct {
+		bizID  int64
+		vendor enumor.Vendor
+	}
+
+	checked := make(map[bizVendor]struct{})
+	for id, info := range basicInfoMap {
+		key := bizVendor{bizID: info.BkBizID, vendor: info.Vendor}
+		if _, exists := checked[key]; exists {
+			continue
+		}
+		checked[key] = struct{}{}
+
+		err := logicsrecycle.ValidatePreDestroyVendor(kt, c.client, enumor.CvmCloudResType, info.BkBizID, info.Vendor)
+		if err != nil {
+			logs.Errorf("check recycle pre-destroy vendor failed, err: %v, cvm: %s, rid: %s", err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
 // BatchFinalizeRelRecord 批量更改关联资源的状态
 func (c *cvm) BatchFinalizeRelRecord(kt *kit.Kit, resType enumor.CloudResourceType,
 	status enumor.RecycleRecordStatus, resIds []string) error {
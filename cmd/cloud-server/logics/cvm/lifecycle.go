@@ -0,0 +1,89 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cvm
+
+import (
+	"fmt"
+	"strings"
+
+	"hcm/pkg/api/core"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+// OperationAllowedLifecycleStatus 主机操作允许的生命周期状态
+var OperationAllowedLifecycleStatus = map[enumor.ActionName][]enumor.CvmLifecycleStatus{
+	enumor.ActionStartCvm:  {enumor.CvmLifecycleStopped},
+	enumor.ActionStopCvm:   {enumor.CvmLifecycleRunning},
+	enumor.ActionRebootCvm: {enumor.CvmLifecycleRunning},
+	enumor.ActionResizeCvm: {enumor.CvmLifecycleRunning, enumor.CvmLifecycleStopped},
+}
+
+// CheckLifecycleStatus 检查主机的生命周期状态是否允许执行该操作，状态未知的主机交由云上校验
+func (c *cvm) CheckLifecycleStatus(kt *kit.Kit, action enumor.ActionName, ids []string) error {
+	allowed, exists := OperationAllowedLifecycleStatus[action]
+	if !exists {
+		return fmt.Errorf("cvm operation %s has no lifecycle status check", action)
+	}
+
+	invalid := make([]string, 0)
+	for _, batch := range slice.Split(slice.Unique(ids), int(core.DefaultMaxPageLimit)) {
+		listReq := &core.ListReq{
+			Fields: []string{"id", "lifecycle_status"},
+			Filter: tools.ContainersExpression("id", batch),
+			Page:   core.NewDefaultBasePage(),
+		}
+		result, err := c.client.DataService().Global.Cvm.ListCvm(kt, listReq)
+		if err != nil {
+			logs.Errorf("list cvm lifecycle status failed, err: %v, ids: %v, rid: %s", err, batch, kt.Rid)
+			return err
+		}
+
+		invalid = append(invalid, disallowedLifecycleCvms(allowed, result.Details)...)
+	}
+
+	if len(invalid) != 0 {
+		return errf.Newf(errf.InvalidParameter, "cvm %s can not %s, allowed lifecycle status: %v",
+			strings.Join(invalid, ", "), action, allowed)
+	}
+
+	return nil
+}
+
+// disallowedLifecycleCvms 返回生命周期状态不在允许列表中的主机，格式为 id(lifecycle_status)，状态未知的主机不做拦截
+func disallowedLifecycleCvms(allowed []enumor.CvmLifecycleStatus, cvms []corecvm.BaseCvm) []string {
+	invalid := make([]string, 0)
+	for _, one := range cvms {
+		if one.LifecycleStatus == enumor.CvmLifecycleUnknown || len(one.LifecycleStatus) == 0 {
+			continue
+		}
+
+		if !slice.IsItemInSlice(allowed, one.LifecycleStatus) {
+			invalid = append(invalid, fmt.Sprintf("%s(%s)", one.ID, one.LifecycleStatus))
+		}
+	}
+
+	return invalid
+}
@@ -0,0 +1,113 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cvm
+
+import (
+	"reflect"
+	"testing"
+
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestOperationAllowedLifecycleStatus(t *testing.T) {
+	statuses := []enumor.CvmLifecycleStatus{enumor.CvmLifecyclePending, enumor.CvmLifecycleRunning,
+		enumor.CvmLifecycleStopping, enumor.CvmLifecycleStopped, enumor.CvmLifecycleRebooting,
+		enumor.CvmLifecycleTerminating, enumor.CvmLifecycleTerminated, enumor.CvmLifecycleError}
+
+	expect := map[enumor.ActionName][]enumor.CvmLifecycleStatus{
+		enumor.ActionStartCvm:  {enumor.CvmLifecycleStopped},
+		enumor.ActionStopCvm:   {enumor.CvmLifecycleRunning},
+		enumor.ActionRebootCvm: {enumor.CvmLifecycleRunning},
+		enumor.ActionResizeCvm: {enumor.CvmLifecycleRunning, enumor.CvmLifecycleStopped},
+	}
+
+	for action, allowedStatus := range expect {
+		allowed, exists := OperationAllowedLifecycleStatus[action]
+		if !exists {
+			t.Errorf("action %s has no lifecycle status check", action)
+			continue
+		}
+
+		for _, status := range statuses {
+			cvms := []corecvm.BaseCvm{{ID: "cvm-1", LifecycleStatus: status}}
+			invalid := disallowedLifecycleCvms(allowed, cvms)
+
+			shouldAllow := false
+			for _, one := range allowedStatus {
+				if one == status {
+					shouldAllow = true
+				}
+			}
+			if shouldAllow && len(invalid) != 0 {
+				t.Errorf("action %s should allow lifecycle status %s, got invalid: %v", action, status, invalid)
+			}
+			if !shouldAllow && len(invalid) != 1 {
+				t.Errorf("action %s should reject lifecycle status %s", action, status)
+			}
+		}
+	}
+}
+
+func TestDisallowedLifecycleCvms(t *testing.T) {
+	cvms := []corecvm.BaseCvm{
+		{ID: "running", LifecycleStatus: enumor.CvmLifecycleRunning},
+		{ID: "stopped", LifecycleStatus: enumor.CvmLifecycleStopped},
+		{ID: "pending", LifecycleStatus: enumor.CvmLifecyclePending},
+		{ID: "unknown", LifecycleStatus: enumor.CvmLifecycleUnknown},
+		{ID: "empty"},
+	}
+
+	got := disallowedLifecycleCvms(OperationAllowedLifecycleStatus[enumor.ActionStartCvm], cvms)
+	expect := []string{"running(running)", "pending(pending)"}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("start cvm expect invalid %v, got: %v", expect, got)
+	}
+}
+
+// TestVendorStatusOperation 校验云上主机状态经过归一化后能否执行对应操作
+func TestVendorStatusOperation(t *testing.T) {
+	cases := []struct {
+		vendor enumor.Vendor
+		status string
+		action enumor.ActionName
+		allow  bool
+	}{
+		{vendor: enumor.TCloud, status: "STOPPED", action: enumor.ActionStartCvm, allow: true},
+		{vendor: enumor.TCloud, status: "RUNNING", action: enumor.ActionStartCvm, allow: false},
+		{vendor: enumor.Aws, status: "stopping", action: enumor.ActionStartCvm, allow: false},
+		{vendor: enumor.Aws, status: "running", action: enumor.ActionRebootCvm, allow: true},
+		{vendor: enumor.Gcp, status: "TERMINATED", action: enumor.ActionStartCvm, allow: true},
+		{vendor: enumor.Azure, status: "PowerState/deallocated", action: enumor.ActionStopCvm, allow: false},
+		{vendor: enumor.HuaWei, status: "SHUTOFF", action: enumor.ActionResizeCvm, allow: true},
+		{vendor: enumor.HuaWei, status: "RESIZE", action: enumor.ActionResizeCvm, allow: false},
+		// 无法识别的云上状态交由云上校验，不做拦截
+		{vendor: enumor.TCloud, status: "NEW_STATUS", action: enumor.ActionStopCvm, allow: true},
+	}
+
+	for _, c := range cases {
+		cvms := []corecvm.BaseCvm{{ID: "cvm-1", LifecycleStatus: corecvm.NormalizeLifecycleStatus(c.vendor, c.status)}}
+		invalid := disallowedLifecycleCvms(OperationAllowedLifecycleStatus[c.action], cvms)
+		if c.allow != (len(invalid) == 0) {
+			t.Errorf("%s status %s %s expect allow: %v, got invalid: %v", c.vendor, c.status, c.action, c.allow,
+				invalid)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cvm
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	hcprotoinstancetype "hcm/pkg/api/hc-service/instance-type"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/shopspring/decimal"
+	tcvm "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cvm/v20170312"
+)
+
+const (
+	// tcloudPrepaid 腾讯云包年包月计费模式
+	tcloudPrepaid = "PREPAID"
+	// hoursPerMonth 按小时计费的价格折算为月价格时使用的每月小时数
+	hoursPerMonth = 730
+)
+
+// ResizePreCheck 调整主机规格前的校验，包括主机的生命周期状态、目标机型是否与当前机型相同、目标机型在主机所在地域/可用区
+// 是否可用，校验通过返回主机基础信息
+func (c *cvm) ResizePreCheck(kt *kit.Kit, id, instanceType string) (*corecvm.BaseCvm, error) {
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := c.client.DataService().Global.Cvm.ListCvm(kt, listReq)
+	if err != nil {
+		logs.Errorf("list cvm failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "cvm %s not found", id)
+	}
+	baseCvm := &result.Details[0]
+
+	if baseCvm.MachineType == instanceType {
+		return nil, errf.Newf(errf.InvalidParameter, "cvm %s instance type is already %s", id, instanceType)
+	}
+
+	if err = c.CheckLifecycleStatus(kt, enumor.ActionResizeCvm, []string{id}); err != nil {
+		return nil, err
+	}
+
+	instanceTypes, err := c.ListAvailableInstanceType(kt, baseCvm)
+	if err != nil {
+		logs.Errorf("list available instance type failed, err: %v, cvm: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	for _, one := range instanceTypes {
+		if one.InstanceType == instanceType {
+			return baseCvm, nil
+		}
+	}
+
+	return nil, errf.Newf(errf.InvalidParameter, "instance type %s is not available in region: %s, zone: %s",
+		instanceType, baseCvm.Region, baseCvm.Zone)
+}
+
+// InstanceTypeSpec 机型规格，内存单位为MB
+type InstanceTypeSpec struct {
+	InstanceType   string `json:"instance_type"`
+	InstanceFamily string `json:"instance_family"`
+	CPU            int64  `json:"cpu"`
+	Memory         int64  `json:"memory"`
+	// MonthlyPrice 机型按主机计费模式折算的每月目录价（折扣价），币种为账号的结算币种，仅腾讯云返回
+	MonthlyPrice *decimal.Decimal `json:"monthly_price,omitempty"`
+}
+
+// ListAvailableInstanceType 查询主机所在地域/可用区可用的机型
+func (c *cvm) ListAvailableInstanceType(kt *kit.Kit, baseCvm *corecvm.BaseCvm) ([]InstanceTypeSpec, error) {
+	chargeType := ""
+	if baseCvm.Vendor == enumor.TCloud {
+		// 腾讯云机型列表需按主机的计费模式查询
+		detail, err := c.client.DataService().TCloud.Cvm.GetCvm(kt.Ctx, kt.Header(), baseCvm.ID)
+		if err != nil {
+			return nil, err
+		}
+		if detail.Extension != nil {
+			chargeType = converter.PtrToVal(detail.Extension.InstanceChargeType)
+		}
+	}
+
+	return c.ListAvailableInstanceTypeByCharge(kt, baseCvm, chargeType)
+}
+
+// ListAvailableInstanceTypeByCharge 按指定的计费模式查询主机所在地域/可用区可用的机型，计费模式仅腾讯云使用，
+// 用于调用方已批量查询主机计费模式的场景
+func (c *cvm) ListAvailableInstanceTypeByCharge(kt *kit.Kit, baseCvm *corecvm.BaseCvm, chargeType string) (
+	[]InstanceTypeSpec, error) {
+
+	hcCli := c.client.HCService()
+	instanceTypes := make([]InstanceTypeSpec, 0)
+	switch baseCvm.Vendor {
+	case enumor.TCloud:
+		req := &hcprotoinstancetype.TCloudInstanceTypeListReq{AccountID: baseCvm.AccountID, Region: baseCvm.Region,
+			Zone: baseCvm.Zone, InstanceChargeType: chargeType}
+		list, err := hcCli.TCloud.InstanceType.List(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, one := range list {
+			instanceTypes = append(instanceTypes, InstanceTypeSpec{InstanceType: one.InstanceType,
+				InstanceFamily: one.InstanceFamily, CPU: one.CPU, Memory: one.Memory,
+				MonthlyPrice: tcloudMonthlyPrice(chargeType, one.Price)})
+		}
+
+	case enumor.Aws:
+		req := &hcprotoinstancetype.AwsInstanceTypeListReq{AccountID: baseCvm.AccountID, Region: baseCvm.Region}
+		list, err := hcCli.Aws.InstanceType.List(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, one := range list {
+			instanceTypes = append(instanceTypes, InstanceTypeSpec{InstanceType: one.InstanceType,
+				InstanceFamily: one.InstanceFamily, CPU: one.CPU, Memory: one.Memory})
+		}
+
+	case enumor.HuaWei:
+		req := &hcprotoinstancetype.HuaWeiInstanceTypeListReq{AccountID: baseCvm.AccountID, Region: baseCvm.Region,
+			Zone: baseCvm.Zone}
+		list, err := hcCli.HuaWei.InstanceType.List(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, one := range list {
+			instanceTypes = append(instanceTypes, InstanceTypeSpec{InstanceType: one.InstanceType,
+				InstanceFamily: one.InstanceFamily, CPU: one.CPU, Memory: one.Memory})
+		}
+
+	case enumor.Gcp:
+		req := &hcprotoinstancetype.GcpInstanceTypeListReq{AccountID: baseCvm.AccountID, Zone: baseCvm.Zone}
+		list, err := hcCli.Gcp.InstanceType.List(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, one := range list {
+			instanceTypes = append(instanceTypes, InstanceTypeSpec{InstanceType: one.InstanceType,
+				InstanceFamily: one.InstanceFamily, CPU: one.CPU, Memory: one.Memory})
+		}
+
+	case enumor.Azure:
+		req := &hcprotoinstancetype.AzureInstanceTypeListReq{AccountID: baseCvm.AccountID, Region: baseCvm.Region}
+		list, err := hcCli.Azure.InstanceType.List(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, one := range list {
+			instanceTypes = append(instanceTypes, InstanceTypeSpec{InstanceType: one.InstanceType,
+				InstanceFamily: one.InstanceFamily, CPU: one.CPU, Memory: one.Memory})
+		}
+
+	default:
+		return nil, fmt.Errorf("vendor: %s not support", baseCvm.Vendor)
+	}
+
+	return instanceTypes, nil
+}
+
+// tcloudMonthlyPrice 将腾讯云机型价格折算为每月价格，包年包月取一个月的价格，按量计费按小时单价折算，价格缺失时返回nil
+func tcloudMonthlyPrice(chargeType string, price tcvm.ItemPrice) *decimal.Decimal {
+	if chargeType == tcloudPrepaid {
+		monthly := price.DiscountPrice
+		if monthly == nil {
+			monthly = price.OriginalPrice
+		}
+		if monthly == nil {
+			return nil
+		}
+		return converter.ValToPtr(decimal.NewFromFloat(*monthly))
+	}
+
+	if converter.PtrToVal(price.ChargeUnit) != "HOUR" {
+		return nil
+	}
+	hourly := price.UnitPriceDiscount
+	if hourly == nil {
+		hourly = price.UnitPrice
+	}
+	if hourly == nil {
+		return nil
+	}
+	return converter.ValToPtr(decimal.NewFromFloat(*hourly).Mul(decimal.NewFromInt(hoursPerMonth)).Round(2))
+}
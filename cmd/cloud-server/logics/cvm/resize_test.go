@@ -0,0 +1,45 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cvm
+
+import (
+	"testing"
+
+	"hcm/pkg/tools/converter"
+
+	tcvm "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cvm/v20170312"
+)
+
+func TestTCloudMonthlyPrice(t *testing.T) {
+	prepaid := tcvm.ItemPrice{OriginalPrice: converter.ValToPtr(300.0), DiscountPrice: converter.ValToPtr(240.0)}
+	if price := tcloudMonthlyPrice(tcloudPrepaid, prepaid); price == nil || price.String() != "240" {
+		t.Errorf("expect prepaid monthly price 240, got: %v", price)
+	}
+
+	postpaid := tcvm.ItemPrice{UnitPrice: converter.ValToPtr(0.5), UnitPriceDiscount: converter.ValToPtr(0.2),
+		ChargeUnit: converter.ValToPtr("HOUR")}
+	if price := tcloudMonthlyPrice("POSTPAID_BY_HOUR", postpaid); price == nil || price.String() != "146" {
+		t.Errorf("expect postpaid monthly price 146, got: %v", price)
+	}
+
+	if price := tcloudMonthlyPrice(tcloudPrepaid, tcvm.ItemPrice{}); price != nil {
+		t.Errorf("expect nil price when price is missing, got: %v", price)
+	}
+}
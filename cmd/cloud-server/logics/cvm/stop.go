@@ -194,8 +194,8 @@ func (c *cvm) checkAndStopCvm(kt *kit.Kit, infoMap map[string]types.CloudResourc
 	}
 	cvmIds := converter.MapKeyToSlice(infoMap)
 	// filter out not stopped cvm
-	notStoppedRule := filter.AtomRule{Field: "status", Op: filter.NotIn.Factory(), Value: []string{"STOPPING",
-		"STOPPED", "stopping", "stopped", "SUSPENDING", "SUSPENDED", "PowerState/stopped", "SHUTOFF"}}
+	notStoppedRule := filter.AtomRule{Field: "lifecycle_status", Op: filter.NotIn.Factory(),
+		Value: []enumor.CvmLifecycleStatus{enumor.CvmLifecycleStopping, enumor.CvmLifecycleStopped}}
 	notStoppedFilter, err := tools.And(tools.ContainersExpression("id", cvmIds), notStoppedRule)
 	if err != nil {
 		return err
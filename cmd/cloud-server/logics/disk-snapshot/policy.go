@@ -0,0 +1,185 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot ...
+package disksnapshot
+
+import (
+	"fmt"
+	"time"
+
+	"hcm/cmd/cloud-server/logics/audit"
+	actiondisksnapshot "hcm/cmd/task-server/logics/action/disk-snapshot"
+	"hcm/pkg/api/core"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	datadisk "hcm/pkg/api/data-service/cloud/disk"
+	hcdisk "hcm/pkg/api/hc-service/disk"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/counter"
+	"hcm/pkg/tools/cron"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+)
+
+// Interface define disk snapshot logics interface.
+type Interface interface {
+	// ExecPolicy 执行定期快照策略，为策略下的云硬盘创建快照，返回创建的任务流ID
+	ExecPolicy(kt *kit.Kit, policy *coredisk.SnapshotPolicy, now time.Time) (*core.CreateResult, error)
+}
+
+type diskSnapshot struct {
+	client *client.ClientSet
+	audit  audit.Interface
+}
+
+// NewDiskSnapshot new disk snapshot logics.
+func NewDiskSnapshot(client *client.ClientSet, audit audit.Interface) Interface {
+	return &diskSnapshot{
+		client: client,
+		audit:  audit,
+	}
+}
+
+// ExecPolicy 执行定期快照策略，已不属于策略业务、已回收或云厂商不支持快照的云硬盘会被跳过
+func (d *diskSnapshot) ExecPolicy(kt *kit.Kit, policy *coredisk.SnapshotPolicy, now time.Time) (
+	*core.CreateResult, error) {
+
+	disks, err := d.listPolicyDisks(kt, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(disks) == 0 {
+		return nil, errf.Newf(errf.InvalidParameter, "snapshot policy %s has no disk to create snapshot", policy.ID)
+	}
+
+	diskIDs := slice.Map(disks, func(one *coredisk.BaseDisk) string { return one.ID })
+	if err = d.audit.ResBaseOperationAudit(kt, enumor.DiskAuditResType, protoaudit.CreateSnapshot,
+		diskIDs); err != nil {
+		logs.Errorf("create disk create snapshot audit failed, err: %v, policy: %s, rid: %s", err, policy.ID, kt.Rid)
+		return nil, err
+	}
+
+	name := PolicySnapshotName(policy.ID, now)
+	tasks := make([]ts.CustomFlowTask, 0, len(disks))
+	nextID := counter.NewNumStringCounter(1, 10)
+	for _, one := range disks {
+		tasks = append(tasks, ts.CustomFlowTask{
+			ActionID:   action.ActIDType(nextID()),
+			ActionName: enumor.ActionCreateDiskSnapshot,
+			Params: &actiondisksnapshot.CreateDiskSnapshotOption{
+				Vendor: enumor.Vendor(one.Vendor),
+				SnapshotCreateReq: hcdisk.SnapshotCreateReq{
+					AccountID:      one.AccountID,
+					DiskID:         one.ID,
+					SnapshotName:   name,
+					PolicyID:       policy.ID,
+					RetentionCount: policy.RetentionCount,
+				},
+			},
+		})
+	}
+
+	flowReq := &ts.AddCustomFlowReq{
+		Name:  enumor.FlowExecDiskSnapshotPolicy,
+		Tasks: tasks,
+	}
+	result, err := d.client.TaskServer().CreateCustomFlow(kt, flowReq)
+	if err != nil {
+		logs.Errorf("call taskserver to create exec snapshot policy flow failed, err: %v, policy: %s, rid: %s",
+			err, policy.ID, kt.Rid)
+		return nil, err
+	}
+
+	updateReq := &datadisk.SnapshotPolicyUpdateReq{LastExecutedAt: times.ConvStdTimeFormat(now)}
+	if err = d.client.DataService().Global.DiskSnapshot.UpdatePolicy(kt, policy.ID, updateReq); err != nil {
+		logs.Errorf("update snapshot policy last executed time failed, err: %v, policy: %s, rid: %s", err,
+			policy.ID, kt.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// listPolicyDisks 查询策略下可以创建快照的云硬盘
+func (d *diskSnapshot) listPolicyDisks(kt *kit.Kit, policy *coredisk.SnapshotPolicy) ([]*coredisk.BaseDisk, error) {
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", policy.DiskIDs),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := d.client.DataService().Global.ListDisk(kt, listReq)
+	if err != nil {
+		logs.Errorf("list snapshot policy disks failed, err: %v, policy: %s, rid: %s", err, policy.ID, kt.Rid)
+		return nil, err
+	}
+
+	disks := make([]*coredisk.BaseDisk, 0, len(result.Details))
+	for _, one := range result.Details {
+		if one.BkBizID != policy.BkBizID || one.RecycleStatus == string(enumor.RecycleStatus) ||
+			!slice.IsItemInSlice(coredisk.SnapshotVendors, enumor.Vendor(one.Vendor)) {
+
+			logs.Warnf("disk %s can not create snapshot by policy %s, skip, rid: %s", one.ID, policy.ID, kt.Rid)
+			continue
+		}
+		disks = append(disks, one)
+	}
+
+	return disks, nil
+}
+
+// PolicySnapshotName 生成定期快照策略创建的快照名称，由小写字母、数字和连字符组成，满足各云厂商的命名规则
+func PolicySnapshotName(policyID string, now time.Time) string {
+	return fmt.Sprintf("hcm-snap-%s-%s", policyID, now.Format("200601021504"))
+}
+
+// isPolicyDue 判断策略在当前分钟是否需要执行，同一分钟内已执行过的策略不再重复执行
+func isPolicyDue(policy *coredisk.SnapshotPolicy, now time.Time) (bool, error) {
+	if !policy.Enabled {
+		return false, nil
+	}
+
+	schedule, err := cron.Parse(policy.Cron)
+	if err != nil {
+		return false, err
+	}
+
+	if !schedule.Match(now) {
+		return false, nil
+	}
+
+	if len(policy.LastExecutedAt) == 0 {
+		return true, nil
+	}
+
+	last, err := time.Parse(constant.TimeStdFormat, policy.LastExecutedAt)
+	if err != nil {
+		return true, nil
+	}
+
+	return !last.Truncate(time.Minute).Equal(now.Truncate(time.Minute)), nil
+}
@@ -0,0 +1,86 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"testing"
+	"time"
+
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	"hcm/pkg/tools/times"
+)
+
+func TestIsPolicyDue(t *testing.T) {
+	now := time.Date(2024, 11, 18, 2, 0, 0, 0, time.Local)
+	cases := []struct {
+		name   string
+		policy *coredisk.SnapshotPolicy
+		expect bool
+	}{
+		{
+			name:   "match",
+			policy: &coredisk.SnapshotPolicy{Cron: "0 2 * * *", Enabled: true},
+			expect: true,
+		},
+		{
+			name:   "disabled",
+			policy: &coredisk.SnapshotPolicy{Cron: "0 2 * * *", Enabled: false},
+			expect: false,
+		},
+		{
+			name:   "not match",
+			policy: &coredisk.SnapshotPolicy{Cron: "0 3 * * *", Enabled: true},
+			expect: false,
+		},
+		{
+			name: "executed in the same minute",
+			policy: &coredisk.SnapshotPolicy{Cron: "0 2 * * *", Enabled: true,
+				LastExecutedAt: times.ConvStdTimeFormat(now.Add(10 * time.Second))},
+			expect: false,
+		},
+		{
+			name: "executed yesterday",
+			policy: &coredisk.SnapshotPolicy{Cron: "0 2 * * *", Enabled: true,
+				LastExecutedAt: times.ConvStdTimeFormat(now.Add(-24 * time.Hour))},
+			expect: true,
+		},
+	}
+
+	for _, c := range cases {
+		got, err := isPolicyDue(c.policy, now)
+		if err != nil {
+			t.Fatalf("%s: check policy due failed, err: %v", c.name, err)
+		}
+		if got != c.expect {
+			t.Errorf("%s: expect %v, got: %v", c.name, c.expect, got)
+		}
+	}
+
+	if _, err := isPolicyDue(&coredisk.SnapshotPolicy{Cron: "invalid", Enabled: true}, now); err == nil {
+		t.Errorf("check policy with invalid cron should be failed")
+	}
+}
+
+func TestPolicySnapshotName(t *testing.T) {
+	name := PolicySnapshotName("00000001", time.Date(2024, 11, 18, 2, 5, 0, 0, time.Local))
+	if name != "hcm-snap-00000001-202411180205" {
+		t.Errorf("unexpected policy snapshot name: %s", name)
+	}
+}
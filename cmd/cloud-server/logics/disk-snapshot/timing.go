@@ -0,0 +1,96 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"time"
+
+	"hcm/cmd/cloud-server/logics/audit"
+	"hcm/pkg/api/core"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	"hcm/pkg/client"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/serviced"
+)
+
+// TimingExecPolicy 每分钟检查一次已启用的定期快照策略，执行到达执行时间的策略
+func TimingExecPolicy(cliSet *client.ClientSet, state serviced.State) {
+	snapshot := NewDiskSnapshot(cliSet, audit.NewAudit(cliSet.DataService()))
+
+	for {
+		// 对齐到下一分钟开始时执行，避免错过cron表达式匹配的分钟
+		now := time.Now()
+		time.Sleep(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+
+		if !state.IsMaster() {
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		if err := execDuePolicies(kt, cliSet, snapshot, time.Now().Truncate(time.Minute)); err != nil {
+			logs.Errorf("exec disk snapshot policies failed, err: %v, rid: %s", err, kt.Rid)
+		}
+	}
+}
+
+func execDuePolicies(kt *kit.Kit, cliSet *client.ClientSet, snapshot Interface, now time.Time) error {
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("enabled", true),
+		Page:   core.NewDefaultBasePage(),
+	}
+
+	policies := make([]*coredisk.SnapshotPolicy, 0)
+	for {
+		result, err := cliSet.DataService().Global.DiskSnapshot.ListPolicy(kt, listReq)
+		if err != nil {
+			logs.Errorf("list enabled snapshot policy failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+		policies = append(policies, result.Details...)
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	for _, policy := range policies {
+		due, err := isPolicyDue(policy, now)
+		if err != nil {
+			logs.Errorf("check snapshot policy %s failed, err: %v, rid: %s", policy.ID, err, kt.Rid)
+			continue
+		}
+
+		if !due {
+			continue
+		}
+
+		result, err := snapshot.ExecPolicy(kt, policy, now)
+		if err != nil {
+			logs.Errorf("exec snapshot policy %s failed, err: %v, rid: %s", policy.ID, err, kt.Rid)
+			continue
+		}
+		logs.Infof("exec snapshot policy %s success, flow: %s, rid: %s", policy.ID, result.ID, kt.Rid)
+	}
+
+	return nil
+}
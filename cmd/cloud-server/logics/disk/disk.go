@@ -24,8 +24,11 @@ import (
 	"fmt"
 
 	"hcm/cmd/cloud-server/logics/audit"
+	csdisk "hcm/pkg/api/cloud-server/disk"
 	"hcm/pkg/api/cloud-server/recycle"
 	"hcm/pkg/api/core"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	coredisk "hcm/pkg/api/core/cloud/disk"
 	recyclerecord "hcm/pkg/api/core/recycle-record"
 	protoaudit "hcm/pkg/api/data-service/audit"
 	"hcm/pkg/api/data-service/cloud"
@@ -52,6 +55,11 @@ type Interface interface {
 	BatchGetDiskInfo(kt *kit.Kit, cvmDetail map[string]*recycle.CvmDetail) (err error)
 	BatchDetach(kt *kit.Kit, cvmRecycleMap map[string]*recycle.CvmDetail) (failed []string, err error)
 	BatchReattachDisk(kt *kit.Kit, cvmRecycleMap map[string]*recycle.CvmDetail) (err error)
+
+	ResizePreCheck(kt *kit.Kit, id string, diskSize uint64) (*coredisk.BaseDisk, error)
+	GetAttachedCvm(kt *kit.Kit, diskID string) (*corecvm.BaseCvm, error)
+	RecycleDisk(kt *kit.Kit, infos []csdisk.DiskRecycleInfo, basicInfoMap map[string]types.CloudResourceBasicInfo) (
+		interface{}, error)
 }
 type disk struct {
 	client *client.ClientSet
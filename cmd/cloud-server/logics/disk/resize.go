@@ -0,0 +1,86 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disk
+
+import (
+	"hcm/pkg/api/core"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// ResizePreCheck 扩容云盘前的校验，扩容后的大小需大于当前大小，校验通过返回云盘基础信息
+func (d *disk) ResizePreCheck(kt *kit.Kit, id string, diskSize uint64) (*coredisk.BaseDisk, error) {
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := d.client.DataService().Global.ListDisk(kt, listReq)
+	if err != nil {
+		logs.Errorf("list disk failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "disk %s not found", id)
+	}
+
+	baseDisk := result.Details[0]
+	if diskSize <= baseDisk.DiskSize {
+		return nil, errf.Newf(errf.InvalidParameter, "disk_size should be greater than current size %d",
+			baseDisk.DiskSize)
+	}
+
+	return baseDisk, nil
+}
+
+// GetAttachedCvm 查询云盘挂载的主机，云盘未挂载时返回nil
+func (d *disk) GetAttachedCvm(kt *kit.Kit, diskID string) (*corecvm.BaseCvm, error) {
+	relReq := &core.ListReq{
+		Filter: tools.EqualExpression("disk_id", diskID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	relRes, err := d.client.DataService().Global.ListDiskCvmRel(kt, relReq)
+	if err != nil {
+		logs.Errorf("list disk cvm rel failed, err: %v, disk: %s, rid: %s", err, diskID, kt.Rid)
+		return nil, err
+	}
+	if len(relRes.Details) == 0 {
+		return nil, nil
+	}
+
+	cvmReq := &core.ListReq{
+		Filter: tools.EqualExpression("id", relRes.Details[0].CvmID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	cvmRes, err := d.client.DataService().Global.Cvm.ListCvm(kt, cvmReq)
+	if err != nil {
+		logs.Errorf("list cvm failed, err: %v, id: %s, rid: %s", err, relRes.Details[0].CvmID, kt.Rid)
+		return nil, err
+	}
+	if len(cvmRes.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "cvm %s attached by disk %s not found", relRes.Details[0].CvmID,
+			diskID)
+	}
+
+	return &cvmRes.Details[0], nil
+}
@@ -0,0 +1,103 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package image ...
+package image
+
+import (
+	"hcm/pkg/api/core"
+	coreimage "hcm/pkg/api/core/cloud/image"
+	hcimage "hcm/pkg/api/hc-service/image"
+	"hcm/pkg/client"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+// ValidateImageUsable 校验创建主机使用的镜像未被弃用且状态正常，非hcm管理的私有镜像不做校验。
+// 共享给其他账号的镜像与源镜像的云上ID一致，因此不区分账号，共享的镜像随源镜像一起弃用。
+func ValidateImageUsable(kt *kit.Kit, cli *dataservice.Client, vendor enumor.Vendor, region,
+	cloudImageID string) error {
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", vendor),
+			tools.RuleEqual("region", region),
+			tools.RuleEqual("cloud_id", cloudImageID),
+		),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id", "deprecated", "status"},
+	}
+	result, err := cli.Global.PrivateImage.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list private image failed, err: %v, cloud id: %s, rid: %s", err, cloudImageID, kt.Rid)
+		return err
+	}
+
+	for _, one := range result.Details {
+		if one.Deprecated {
+			return errf.Newf(errf.InvalidParameter, "image %s is deprecated, can not be used to create cvm",
+				cloudImageID)
+		}
+
+		if one.Status != enumor.PrivateImageNormal {
+			return errf.Newf(errf.InvalidParameter, "image %s status is %s, can not be used to create cvm",
+				cloudImageID, one.Status)
+		}
+	}
+
+	return nil
+}
+
+// NewPrivateImageSyncer 返回账号资源同步完成后同步私有镜像云上状态的回调，不支持私有镜像的云厂商直接跳过
+func NewPrivateImageSyncer(cli *client.ClientSet) func(kt *kit.Kit, vendor enumor.Vendor, accountID string) error {
+	return func(kt *kit.Kit, vendor enumor.Vendor, accountID string) error {
+		if !slice.IsItemInSlice(coreimage.PrivateImageVendors, vendor) {
+			return nil
+		}
+
+		req := &hcimage.PrivateImageSyncReq{AccountID: accountID}
+		var err error
+		switch vendor {
+		case enumor.TCloud:
+			err = cli.HCService().TCloud.PrivateImage.Sync(kt, req)
+		case enumor.HuaWei:
+			err = cli.HCService().HuaWei.PrivateImage.Sync(kt, req)
+		case enumor.Aws:
+			err = cli.HCService().Aws.PrivateImage.Sync(kt, req)
+		case enumor.Gcp:
+			err = cli.HCService().Gcp.PrivateImage.Sync(kt, req)
+		case enumor.Azure:
+			err = cli.HCService().Azure.PrivateImage.Sync(kt, req)
+		default:
+			return nil
+		}
+		if err != nil {
+			logs.Errorf("sync %s private image failed, err: %v, account: %s, rid: %s", vendor, err, accountID,
+				kt.Rid)
+			return err
+		}
+
+		return nil
+	}
+}
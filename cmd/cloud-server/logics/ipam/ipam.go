@@ -0,0 +1,528 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package ipam ...
+package ipam
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/cidr"
+)
+
+// Interface define ipam interface.
+type Interface interface {
+	CheckCidr(kt *kit.Kit, opt *CheckCidrOption) ([]corecloud.IpamCidrConflict, error)
+	ProposeCidr(kt *kit.Kit, opt *ProposeCidrOption) (string, error)
+	ValidateVpcCidr(kt *kit.Kit, opt *VpcCidrOption) error
+	ValidateSubnetCidr(kt *kit.Kit, vpcID string, subnetCidr string) error
+}
+
+// NewIpam new ipam.
+func NewIpam(client *client.ClientSet) Interface {
+	return &ipam{
+		client: client,
+	}
+}
+
+type ipam struct {
+	client *client.ClientSet
+}
+
+// CheckCidrOption define check cidr option.
+type CheckCidrOption struct {
+	Cidr string
+	// VpcID 检查子网网段时指定，只检查与该vpc下子网的冲突，为空时检查所有vpc、子网以及ipam分配记录
+	VpcID string
+}
+
+// ProposeCidrOption define propose cidr option, one of pool id and vpc id should be set.
+type ProposeCidrOption struct {
+	// PoolID 从地址池中为新vpc分配网段
+	PoolID string
+	// VpcID 从vpc网段中为新子网分配网段
+	VpcID   string
+	Masklen int
+}
+
+// VpcCidrOption define the vpc cidr to be validated.
+type VpcCidrOption struct {
+	BkBizID   int64
+	Vendor    enumor.Vendor
+	AccountID string
+	Region    string
+	Cidr      string
+}
+
+// usedCidr is the cidr occupied by vpc, subnet or ipam allocation.
+type usedCidr struct {
+	corecloud.IpamCidrConflict
+	ipNet net.IPNet
+	// unbound 是否是未绑定资源的ipam分配记录
+	unbound bool
+}
+
+// CheckCidr return the occupied cidrs which overlap with the cidr.
+func (i *ipam) CheckCidr(kt *kit.Kit, opt *CheckCidrOption) ([]corecloud.IpamCidrConflict, error) {
+	overlapped, err := i.listOverlapped(kt, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := make([]corecloud.IpamCidrConflict, 0, len(overlapped))
+	for _, one := range overlapped {
+		conflicts = append(conflicts, one.IpamCidrConflict)
+	}
+
+	return conflicts, nil
+}
+
+func (i *ipam) listOverlapped(kt *kit.Kit, opt *CheckCidrOption) ([]usedCidr, error) {
+	_, target, err := net.ParseCIDR(opt.Cidr)
+	if err != nil || target.IP.To4() == nil {
+		return nil, errf.Newf(errf.InvalidParameter, "cidr: %s is not a valid ipv4 cidr", opt.Cidr)
+	}
+
+	var used []usedCidr
+	if len(opt.VpcID) != 0 {
+		used, err = i.listSubnetCidrs(kt, tools.EqualExpression("vpc_id", opt.VpcID))
+	} else {
+		used, err = i.listAllUsedCidrs(kt)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	overlapped := make([]usedCidr, 0)
+	for _, one := range used {
+		if cidr.IsOverlapped(one.ipNet, *target) {
+			overlapped = append(overlapped, one)
+		}
+	}
+
+	return overlapped, nil
+}
+
+// ProposeCidr propose the first cidr which does not overlap with any occupied cidr.
+func (i *ipam) ProposeCidr(kt *kit.Kit, opt *ProposeCidrOption) (string, error) {
+	var outers []string
+	var used []usedCidr
+	switch {
+	case len(opt.PoolID) != 0:
+		pool, err := i.getPool(kt, opt.PoolID)
+		if err != nil {
+			return "", err
+		}
+
+		outers = []string{pool.Cidr}
+		if used, err = i.listPoolUsedCidrs(kt, pool, ""); err != nil {
+			return "", err
+		}
+
+	case len(opt.VpcID) != 0:
+		vpcCidrs, err := i.listVpcCidrs(kt, "", tools.EqualExpression("id", opt.VpcID))
+		if err != nil {
+			return "", err
+		}
+
+		if len(vpcCidrs) == 0 {
+			return "", errf.Newf(errf.InvalidParameter, "vpc: %s has no ipv4 cidr", opt.VpcID)
+		}
+
+		for _, one := range vpcCidrs {
+			outers = append(outers, one.Cidr)
+		}
+		if used, err = i.listSubnetCidrs(kt, tools.EqualExpression("vpc_id", opt.VpcID)); err != nil {
+			return "", err
+		}
+
+	default:
+		return "", errf.New(errf.InvalidParameter, "pool id or vpc id is required")
+	}
+
+	usedNets := make([]net.IPNet, 0, len(used))
+	for _, one := range used {
+		usedNets = append(usedNets, one.ipNet)
+	}
+
+	var lastErr error
+	for _, outer := range outers {
+		_, outerNet, err := net.ParseCIDR(outer)
+		if err != nil {
+			return "", err
+		}
+
+		proposed, err := cidr.FirstAvailableNet(*outerNet, usedNets, opt.Masklen)
+		if err == nil {
+			return proposed.String(), nil
+		}
+		lastErr = err
+	}
+
+	return "", errf.Newf(errf.InvalidParameter, "no available /%d cidr, err: %v", opt.Masklen, lastErr)
+}
+
+// ValidateVpcCidr validate the cidr of the vpc to be created. the check is only enforced when the biz has ipam pools
+// in the vendor region, the cidr must be contained by one of them, and can not overlap with the vpcs and subnets of
+// the same vendor, account and biz, or other allocations of the pool, except the unbound allocation of the biz which
+// contains the cidr, it is allocated for the vpc in advance. overlapping cidrs out of ipam pools are allowed.
+func (i *ipam) ValidateVpcCidr(kt *kit.Kit, opt *VpcCidrOption) error {
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("bk_biz_id", opt.BkBizID), tools.RuleEqual("vendor", opt.Vendor),
+			tools.RuleEqual("region", opt.Region)),
+		Page: core.NewDefaultBasePage(),
+	}
+	pools, err := i.client.DataService().Global.Ipam.ListPool(kt, listReq)
+	if err != nil {
+		logs.Errorf("list ipam cidr pool failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	if len(pools.Details) == 0 {
+		return nil
+	}
+
+	var pool *corecloud.IpamPool
+	for idx := range pools.Details {
+		if cidr.IsSubnetContained(pools.Details[idx].Cidr, opt.Cidr) == nil {
+			pool = &pools.Details[idx]
+			break
+		}
+	}
+
+	if pool == nil {
+		return fmt.Errorf("cidr %s is not contained by any ipam pool of biz %d in %s %s", opt.Cidr,
+			opt.BkBizID, opt.Vendor, opt.Region)
+	}
+
+	_, target, err := net.ParseCIDR(opt.Cidr)
+	if err != nil || target.IP.To4() == nil {
+		return errf.Newf(errf.InvalidParameter, "cidr: %s is not a valid ipv4 cidr", opt.Cidr)
+	}
+
+	used, err := i.listPoolUsedCidrs(kt, pool, opt.AccountID)
+	if err != nil {
+		return err
+	}
+
+	msgs := make([]string, 0)
+	for _, one := range used {
+		if !cidr.IsOverlapped(one.ipNet, *target) {
+			continue
+		}
+
+		if one.unbound && one.Usage == enumor.IpamUsageAllocation && one.BkBizID == opt.BkBizID &&
+			cidr.IsSubnetContained(one.Cidr, opt.Cidr) == nil {
+			continue
+		}
+		msgs = append(msgs, fmt.Sprintf("%s %s(%s)", one.Usage, one.ResID, one.Cidr))
+	}
+
+	if len(msgs) != 0 {
+		return fmt.Errorf("cidr %s overlaps with %s", opt.Cidr, strings.Join(msgs, ", "))
+	}
+
+	return nil
+}
+
+// ValidateSubnetCidr validate the cidr of the subnet to be created can not overlap with other subnets in the vpc.
+func (i *ipam) ValidateSubnetCidr(kt *kit.Kit, vpcID string, subnetCidr string) error {
+	if len(vpcID) == 0 {
+		return errors.New("vpc id is required")
+	}
+
+	conflicts, err := i.CheckCidr(kt, &CheckCidrOption{Cidr: subnetCidr, VpcID: vpcID})
+	if err != nil {
+		return err
+	}
+
+	if len(conflicts) != 0 {
+		msgs := make([]string, 0, len(conflicts))
+		for _, one := range conflicts {
+			msgs = append(msgs, fmt.Sprintf("subnet %s(%s)", one.ResID, one.Cidr))
+		}
+		return fmt.Errorf("cidr %s overlaps with %s", subnetCidr, strings.Join(msgs, ", "))
+	}
+
+	return nil
+}
+
+func (i *ipam) getPool(kt *kit.Kit, poolID string) (*corecloud.IpamPool, error) {
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("id", poolID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := i.client.DataService().Global.Ipam.ListPool(kt, listReq)
+	if err != nil {
+		logs.Errorf("get ipam cidr pool failed, err: %v, id: %s, rid: %s", err, poolID, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "ipam cidr pool: %s not found", poolID)
+	}
+
+	return &result.Details[0], nil
+}
+
+// listAllUsedCidrs list the cidrs of all synced vpcs, subnets on every vendor and all ipam allocations.
+func (i *ipam) listAllUsedCidrs(kt *kit.Kit) ([]usedCidr, error) {
+	vpcCidrs, err := i.listVpcCidrs(kt, "", tools.AllExpression())
+	if err != nil {
+		return nil, err
+	}
+
+	subnetCidrs, err := i.listSubnetCidrs(kt, tools.AllExpression())
+	if err != nil {
+		return nil, err
+	}
+
+	allocCidrs, err := i.listAllocationCidrs(kt, tools.AllExpression())
+	if err != nil {
+		return nil, err
+	}
+
+	result := append(vpcCidrs, subnetCidrs...)
+	return append(result, allocCidrs...), nil
+}
+
+// listPoolUsedCidrs list the cidrs used in the scope of the pool, which are the vpcs and subnets of the pool's vendor
+// and biz (and account if set), and the allocations of the pool.
+func (i *ipam) listPoolUsedCidrs(kt *kit.Kit, pool *corecloud.IpamPool, accountID string) ([]usedCidr, error) {
+	rules := []*filter.AtomRule{tools.RuleEqual("vendor", pool.Vendor), tools.RuleEqual("bk_biz_id", pool.BkBizID)}
+	if len(accountID) != 0 {
+		rules = append(rules, tools.RuleEqual("account_id", accountID))
+	}
+	expr := tools.ExpressionAnd(rules...)
+
+	vpcCidrs, err := i.listVpcCidrs(kt, pool.Vendor, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	subnetCidrs, err := i.listSubnetCidrs(kt, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	allocCidrs, err := i.listAllocationCidrs(kt, tools.EqualExpression("pool_id", pool.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := append(vpcCidrs, subnetCidrs...)
+	return append(result, allocCidrs...), nil
+}
+
+func (i *ipam) listAllocationCidrs(kt *kit.Kit, expr *filter.Expression) ([]usedCidr, error) {
+	allocations, err := listAll(func(page *core.BasePage) ([]corecloud.IpamAllocation, error) {
+		result, err := i.client.DataService().Global.Ipam.ListAllocation(kt, &core.ListReq{Filter: expr, Page: page})
+		if err != nil {
+			return nil, err
+		}
+		return result.Details, nil
+	})
+	if err != nil {
+		logs.Errorf("list ipam cidr allocation failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	result := make([]usedCidr, 0, len(allocations))
+	for _, one := range allocations {
+		usage := enumor.IpamUsageAllocation
+		if one.Type == enumor.IpamReservation {
+			usage = enumor.IpamUsageReservation
+		}
+
+		before := len(result)
+		result = appendUsed(result, corecloud.IpamCidrConflict{Usage: usage, BkBizID: one.BkBizID, ResID: one.ID},
+			one.Cidr)
+		if len(result) > before {
+			result[before].unbound = len(one.ResID) == 0
+		}
+	}
+
+	return result, nil
+}
+
+// listVpcCidrs list the cidrs of the vpcs matched by the expression, only the vpcs of the vendor are listed if set.
+func (i *ipam) listVpcCidrs(kt *kit.Kit, vendor enumor.Vendor, expr *filter.Expression) ([]usedCidr, error) {
+	result := make([]usedCidr, 0)
+	add := func(vpc corecloud.BaseVpc, cidrs ...string) {
+		for _, one := range cidrs {
+			result = appendUsed(result, corecloud.IpamCidrConflict{Usage: enumor.IpamUsageVpc, Vendor: vpc.Vendor,
+				BkBizID: vpc.BkBizID, ResID: vpc.ID, CloudID: vpc.CloudID, Name: vpc.Name}, one)
+		}
+	}
+
+	ds := i.client.DataService()
+	// gcp vpc没有网段，网段定义在子网上
+	listers := map[enumor.Vendor]func() error{
+		enumor.TCloud: func() error {
+			vpcs, err := listAll(func(page *core.BasePage) ([]corecloud.Vpc[corecloud.TCloudVpcExtension], error) {
+				res, err := ds.TCloud.Vpc.ListVpcExt(kt.Ctx, kt.Header(), &core.ListReq{Filter: expr, Page: page})
+				if err != nil {
+					return nil, err
+				}
+				return res.Details, nil
+			})
+			for _, vpc := range vpcs {
+				if vpc.Extension != nil {
+					for _, one := range vpc.Extension.Cidr {
+						add(vpc.BaseVpc, one.Cidr)
+					}
+				}
+			}
+			return err
+		},
+		enumor.Aws: func() error {
+			vpcs, err := listAll(func(page *core.BasePage) ([]corecloud.Vpc[corecloud.AwsVpcExtension], error) {
+				res, err := ds.Aws.Vpc.ListVpcExt(kt.Ctx, kt.Header(), &core.ListReq{Filter: expr, Page: page})
+				if err != nil {
+					return nil, err
+				}
+				return res.Details, nil
+			})
+			for _, vpc := range vpcs {
+				if vpc.Extension != nil {
+					for _, one := range vpc.Extension.Cidr {
+						add(vpc.BaseVpc, one.Cidr)
+					}
+				}
+			}
+			return err
+		},
+		enumor.HuaWei: func() error {
+			vpcs, err := listAll(func(page *core.BasePage) ([]corecloud.Vpc[corecloud.HuaWeiVpcExtension], error) {
+				res, err := ds.HuaWei.Vpc.ListVpcExt(kt.Ctx, kt.Header(), &core.ListReq{Filter: expr, Page: page})
+				if err != nil {
+					return nil, err
+				}
+				return res.Details, nil
+			})
+			for _, vpc := range vpcs {
+				if vpc.Extension != nil {
+					for _, one := range vpc.Extension.Cidr {
+						add(vpc.BaseVpc, one.Cidr)
+					}
+				}
+			}
+			return err
+		},
+		enumor.Azure: func() error {
+			vpcs, err := listAll(func(page *core.BasePage) ([]corecloud.Vpc[corecloud.AzureVpcExtension], error) {
+				res, err := ds.Azure.Vpc.ListVpcExt(kt.Ctx, kt.Header(), &core.ListReq{Filter: expr, Page: page})
+				if err != nil {
+					return nil, err
+				}
+				return res.Details, nil
+			})
+			for _, vpc := range vpcs {
+				if vpc.Extension != nil {
+					for _, one := range vpc.Extension.Cidr {
+						add(vpc.BaseVpc, one.Cidr)
+					}
+				}
+			}
+			return err
+		},
+	}
+
+	for _, one := range []enumor.Vendor{enumor.TCloud, enumor.Aws, enumor.HuaWei, enumor.Azure} {
+		if len(vendor) != 0 && vendor != one {
+			continue
+		}
+
+		if err := listers[one](); err != nil {
+			logs.Errorf("list %s vpc failed, err: %v, rid: %s", one, err, kt.Rid)
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (i *ipam) listSubnetCidrs(kt *kit.Kit, expr *filter.Expression) ([]usedCidr, error) {
+	subnets, err := listAll(func(page *core.BasePage) ([]corecloud.BaseSubnet, error) {
+		res, err := i.client.DataService().Global.Subnet.List(kt.Ctx, kt.Header(),
+			&core.ListReq{Filter: expr, Page: page})
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	})
+	if err != nil {
+		logs.Errorf("list subnet failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	result := make([]usedCidr, 0, len(subnets))
+	for _, subnet := range subnets {
+		for _, one := range subnet.Ipv4Cidr {
+			result = appendUsed(result, corecloud.IpamCidrConflict{Usage: enumor.IpamUsageSubnet,
+				Vendor: subnet.Vendor, BkBizID: subnet.BkBizID, ResID: subnet.ID, CloudID: subnet.CloudID,
+				Name: subnet.Name}, one)
+		}
+	}
+
+	return result, nil
+}
+
+// appendUsed append the ipv4 cidr to used cidrs, the invalid and ipv6 cidrs are ignored.
+func appendUsed(used []usedCidr, conflict corecloud.IpamCidrConflict, cidrStr string) []usedCidr {
+	_, ipNet, err := net.ParseCIDR(cidrStr)
+	if err != nil || ipNet.IP.To4() == nil {
+		return used
+	}
+
+	conflict.Cidr = ipNet.String()
+	return append(used, usedCidr{IpamCidrConflict: conflict, ipNet: *ipNet})
+}
+
+func listAll[T any](list func(page *core.BasePage) ([]T, error)) ([]T, error) {
+	page := core.NewDefaultBasePage()
+	result := make([]T, 0)
+	for {
+		details, err := list(page)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, details...)
+		if uint(len(details)) < page.Limit {
+			break
+		}
+
+		page.Start += uint32(page.Limit)
+	}
+
+	return result, nil
+}
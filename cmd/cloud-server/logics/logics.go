@@ -24,7 +24,11 @@ import (
 	"hcm/cmd/cloud-server/logics/audit"
 	"hcm/cmd/cloud-server/logics/cvm"
 	"hcm/cmd/cloud-server/logics/disk"
+	disksnapshot "hcm/cmd/cloud-server/logics/disk-snapshot"
 	"hcm/cmd/cloud-server/logics/eip"
+	"hcm/cmd/cloud-server/logics/ipam"
+	securitygroup "hcm/cmd/cloud-server/logics/security-group"
+	"hcm/cmd/cloud-server/logics/topology"
 	"hcm/pkg/client"
 	"hcm/pkg/thirdparty/esb"
 )
@@ -35,6 +39,11 @@ type Logics struct {
 	Disk  disk.Interface
 	Cvm   cvm.Interface
 	Eip   eip.Interface
+
+	DiskSnapshot  disksnapshot.Interface
+	SecurityGroup securitygroup.Interface
+	Ipam          ipam.Interface
+	Topology      topology.Interface
 }
 
 // NewLogics create a new cloud server logics.
@@ -47,5 +56,10 @@ func NewLogics(c *client.ClientSet, esbClient esb.Client) *Logics {
 		Disk:  disk.NewDisk(c, auditLogics),
 		Cvm:   cvm.NewCvm(c, auditLogics, eipLogics, diskLogics, esbClient),
 		Eip:   eip.NewEip(c, auditLogics),
+
+		DiskSnapshot:  disksnapshot.NewDiskSnapshot(c, auditLogics),
+		SecurityGroup: securitygroup.NewSecurityGroup(c),
+		Ipam:          ipam.NewIpam(c),
+		Topology:      topology.NewTopology(c),
 	}
 }
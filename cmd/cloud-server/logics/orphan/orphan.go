@@ -0,0 +1,180 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package orphan 闲置、孤立资源检测，找出未挂载的磁盘、未绑定的弹性公网IP、未使用的安全组、没有监听器或后端的负载均衡
+// 以及长时间关机的主机，并结合账单估算其月成本
+package orphan
+
+import (
+	"sort"
+
+	rescost "hcm/cmd/cloud-server/logics/res-cost"
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+
+	"github.com/shopspring/decimal"
+)
+
+// Interface define orphan resource logics.
+type Interface interface {
+	ListOrphanRes(kt *kit.Kit, opt *ListOption) ([]cloudserver.OrphanRes, error)
+}
+
+// ListOption list orphan resources option.
+type ListOption struct {
+	BkBizID int64
+	// ResTypes 检测的资源类型，为空时检测全部支持的类型
+	ResTypes   []enumor.CloudResourceType
+	AccountIDs []string
+	// IDs 仅检测指定ID的资源，为空时检测业务下全部资源
+	IDs         []string
+	StoppedDays int
+	// WithCost 是否根据账单月份估算资源的月成本
+	WithCost  bool
+	BillYear  int
+	BillMonth int
+}
+
+// NewOrphan new orphan resource logics.
+func NewOrphan(client *client.ClientSet) Interface {
+	o := &orphan{client: client}
+	o.scanners = map[enumor.CloudResourceType]scanner{
+		enumor.DiskCloudResType:          o.scanDisk,
+		enumor.EipCloudResType:           o.scanEip,
+		enumor.SecurityGroupCloudResType: o.scanSecurityGroup,
+		enumor.LoadBalancerCloudResType:  o.scanLoadBalancer,
+		enumor.CvmCloudResType:           o.scanCvm,
+	}
+	return o
+}
+
+// scanner 检测某一资源类型的闲置资源
+type scanner func(kt *kit.Kit, opt *ListOption) ([]cloudserver.OrphanRes, error)
+
+type orphan struct {
+	client   *client.ClientSet
+	scanners map[enumor.CloudResourceType]scanner
+}
+
+// ListOrphanRes 检测业务下的闲置、孤立资源，回收中的资源不会被检测
+func (o *orphan) ListOrphanRes(kt *kit.Kit, opt *ListOption) ([]cloudserver.OrphanRes, error) {
+	resTypes := opt.ResTypes
+	if len(resTypes) == 0 {
+		resTypes = cloudserver.OrphanResTypes
+	}
+
+	details := make([]cloudserver.OrphanRes, 0)
+	for _, resType := range resTypes {
+		scan, exists := o.scanners[resType]
+		if !exists {
+			continue
+		}
+
+		result, err := scan(kt, opt)
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, result...)
+	}
+
+	if !opt.WithCost || len(details) == 0 {
+		return details, nil
+	}
+
+	vendors := slice.Unique(slice.Map(details, func(one cloudserver.OrphanRes) enumor.Vendor { return one.Vendor }))
+	costMap, err := rescost.ListMonthlyResCost(kt, o.client, opt.BkBizID, vendors, opt.BillYear, opt.BillMonth)
+	if err != nil {
+		return nil, err
+	}
+	fillCost(details, costMap)
+
+	return details, nil
+}
+
+// baseRules 各资源类型通用的过滤条件
+func baseRules(opt *ListOption) []*filter.AtomRule {
+	rules := []*filter.AtomRule{
+		tools.RuleEqual("bk_biz_id", opt.BkBizID),
+		tools.RuleNotEqual("recycle_status", enumor.RecycleStatus),
+	}
+	if len(opt.AccountIDs) > 0 {
+		rules = append(rules, tools.RuleIn("account_id", opt.AccountIDs))
+	}
+	if len(opt.IDs) > 0 {
+		rules = append(rules, tools.RuleIn("id", opt.IDs))
+	}
+	return rules
+}
+
+// fillCost 根据资源云上ID关联账单成本
+func fillCost(details []cloudserver.OrphanRes, costMap map[enumor.Vendor]map[string]rescost.ResCost) {
+	for i := range details {
+		resCost, exists := costMap[details[i].Vendor][details[i].CloudID]
+		if !exists {
+			continue
+		}
+		cost := resCost.Cost
+		details[i].MonthlyCost = &cost
+		details[i].Currency = resCost.Currency
+	}
+}
+
+// Summarize 按账号汇总闲置资源的数量和估算月成本，结果按账号ID排序
+func Summarize(details []cloudserver.OrphanRes) []cloudserver.OrphanAccountSummary {
+	summaryMap := make(map[string]*cloudserver.OrphanAccountSummary)
+	costMap := make(map[string]map[enumor.CurrencyCode]decimal.Decimal)
+	for _, one := range details {
+		summary, exists := summaryMap[one.AccountID]
+		if !exists {
+			summary = &cloudserver.OrphanAccountSummary{
+				AccountID: one.AccountID,
+				Vendor:    one.Vendor,
+				Counts:    make(map[enumor.CloudResourceType]int),
+			}
+			summaryMap[one.AccountID] = summary
+			costMap[one.AccountID] = make(map[enumor.CurrencyCode]decimal.Decimal)
+		}
+		summary.Counts[one.ResType]++
+
+		if one.MonthlyCost != nil {
+			costMap[one.AccountID][one.Currency] = costMap[one.AccountID][one.Currency].Add(*one.MonthlyCost)
+		}
+	}
+
+	result := make([]cloudserver.OrphanAccountSummary, 0, len(summaryMap))
+	for accountID, summary := range summaryMap {
+		summary.EstimatedCosts = make([]cloudserver.OrphanCost, 0, len(costMap[accountID]))
+		for currency, cost := range costMap[accountID] {
+			summary.EstimatedCosts = append(summary.EstimatedCosts,
+				cloudserver.OrphanCost{Currency: currency, Cost: cost})
+		}
+		sort.Slice(summary.EstimatedCosts, func(i, j int) bool {
+			return summary.EstimatedCosts[i].Currency < summary.EstimatedCosts[j].Currency
+		})
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].AccountID < result[j].AccountID })
+
+	return result
+}
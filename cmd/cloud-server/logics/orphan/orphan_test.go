@@ -0,0 +1,126 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package orphan
+
+import (
+	"testing"
+
+	rescost "hcm/cmd/cloud-server/logics/res-cost"
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestIsEipUnbound(t *testing.T) {
+	cases := []struct {
+		name   string
+		vendor enumor.Vendor
+		status string
+		hasRel bool
+		expect bool
+	}{
+		{name: "tcloud unbind", vendor: enumor.TCloud, status: "UNBIND", expect: true},
+		{name: "tcloud bind", vendor: enumor.TCloud, status: "BIND"},
+		{name: "tcloud unbind with rel", vendor: enumor.TCloud, status: "UNBIND", hasRel: true},
+		{name: "huawei down", vendor: enumor.HuaWei, status: "DOWN", expect: true},
+		{name: "huawei active", vendor: enumor.HuaWei, status: "ACTIVE"},
+		{name: "gcp reserved", vendor: enumor.Gcp, status: "RESERVED", expect: true},
+		{name: "gcp in use", vendor: enumor.Gcp, status: "IN_USE"},
+		{name: "unknown vendor", vendor: enumor.Vendor("other"), status: "UNBIND"},
+	}
+
+	for _, c := range cases {
+		if got := isEipUnbound(c.vendor, c.status, c.hasRel); got != c.expect {
+			t.Errorf("%s: expect %v, got %v", c.name, c.expect, got)
+		}
+	}
+}
+
+func TestLbOrphanReason(t *testing.T) {
+	tgWithTarget := map[string]struct{}{"tg-1": {}}
+
+	cases := []struct {
+		name        string
+		hasListener bool
+		tgIDs       []string
+		reason      cloudserver.OrphanReason
+		isOrphan    bool
+	}{
+		{name: "no listener", reason: cloudserver.OrphanLbNoListener, isOrphan: true},
+		{name: "listener without target group", hasListener: true, reason: cloudserver.OrphanLbNoTarget,
+			isOrphan: true},
+		{name: "target group without target", hasListener: true, tgIDs: []string{"tg-2"},
+			reason: cloudserver.OrphanLbNoTarget, isOrphan: true},
+		{name: "in use", hasListener: true, tgIDs: []string{"tg-2", "tg-1"}},
+	}
+
+	for _, c := range cases {
+		reason, isOrphan := lbOrphanReason(c.hasListener, c.tgIDs, tgWithTarget)
+		if reason != c.reason || isOrphan != c.isOrphan {
+			t.Errorf("%s: expect (%s, %v), got (%s, %v)", c.name, c.reason, c.isOrphan, reason, isOrphan)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	details := []cloudserver.OrphanRes{
+		{ResType: enumor.DiskCloudResType, ID: "1", CloudID: "vol-1", AccountID: "b", Vendor: enumor.Aws},
+		{ResType: enumor.DiskCloudResType, ID: "2", CloudID: "vol-2", AccountID: "b", Vendor: enumor.Aws},
+		{ResType: enumor.EipCloudResType, ID: "3", CloudID: "eipalloc-3", AccountID: "b", Vendor: enumor.Aws},
+		{ResType: enumor.CvmCloudResType, ID: "4", CloudID: "ins-4", AccountID: "a", Vendor: enumor.TCloud},
+	}
+	fillCost(details, map[enumor.Vendor]map[string]rescost.ResCost{
+		enumor.Aws: {
+			"vol-1":      {Cost: decimal.NewFromFloat(1.5), Currency: enumor.CurrencyUSD},
+			"vol-2":      {Cost: decimal.NewFromFloat(2.25), Currency: enumor.CurrencyUSD},
+			"eipalloc-3": {Cost: decimal.NewFromInt(3), Currency: enumor.CurrencyCNY},
+		},
+	})
+	if details[3].MonthlyCost != nil {
+		t.Fatalf("expect no cost of cvm without bill items, got: %v", details[3].MonthlyCost)
+	}
+
+	summary := Summarize(details)
+	if len(summary) != 2 || summary[0].AccountID != "a" || summary[1].AccountID != "b" {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	if summary[0].Counts[enumor.CvmCloudResType] != 1 || len(summary[0].EstimatedCosts) != 0 {
+		t.Errorf("unexpected summary of account a: %+v", summary[0])
+	}
+
+	b := summary[1]
+	if b.Counts[enumor.DiskCloudResType] != 2 || b.Counts[enumor.EipCloudResType] != 1 {
+		t.Errorf("unexpected counts of account b: %+v", b.Counts)
+	}
+	if len(b.EstimatedCosts) != 2 {
+		t.Fatalf("expect 2 currencies of account b, got: %+v", b.EstimatedCosts)
+	}
+	for _, cost := range b.EstimatedCosts {
+		expect := decimal.NewFromFloat(3.75)
+		if cost.Currency == enumor.CurrencyCNY {
+			expect = decimal.NewFromInt(3)
+		}
+		if !cost.Cost.Equal(expect) {
+			t.Errorf("expect %s cost %s, got %s", cost.Currency, expect, cost.Cost)
+		}
+	}
+}
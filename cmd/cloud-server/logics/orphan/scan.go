@@ -0,0 +1,400 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package orphan
+
+import (
+	"time"
+
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	dataeip "hcm/pkg/api/data-service/cloud/eip"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+// eipUnboundStatus 各云厂商弹性公网IP未绑定时的状态
+var eipUnboundStatus = map[enumor.Vendor]string{
+	enumor.TCloud: string(enumor.EipUnBind),
+	enumor.Aws:    string(enumor.EipUnBind),
+	enumor.Azure:  string(enumor.EipUnBind),
+	enumor.HuaWei: "DOWN",
+	enumor.Gcp:    "RESERVED",
+}
+
+// scanDisk 未挂载到任何主机的磁盘
+func (o *orphan) scanDisk(kt *kit.Kit, opt *ListOption) ([]cloudserver.OrphanRes, error) {
+	result := make([]cloudserver.OrphanRes, 0)
+	listDisk := func(req *core.ListReq) ([]*coredisk.BaseDisk, error) {
+		res, err := o.client.DataService().Global.ListDisk(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	}
+
+	err := listByPage(tools.ExpressionAnd(baseRules(opt)...), listDisk, func(disks []*coredisk.BaseDisk) error {
+		ids := slice.Map(disks, func(one *coredisk.BaseDisk) string { return one.ID })
+		attached, err := relatedIDs(ids, "disk_id", func(req *core.ListReq) ([]string, error) {
+			res, err := o.client.DataService().Global.ListDiskCvmRel(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(res.Details, func(one *protocloud.DiskCvmRelResult) string { return one.DiskID }), nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, one := range disks {
+			if _, exists := attached[one.ID]; exists {
+				continue
+			}
+			result = append(result, cloudserver.OrphanRes{ResType: enumor.DiskCloudResType, ID: one.ID,
+				CloudID: one.CloudID, Name: one.Name, Vendor: enumor.Vendor(one.Vendor), AccountID: one.AccountID,
+				Region: one.Region, BkBizID: one.BkBizID, Reason: cloudserver.OrphanDiskUnattached})
+		}
+		return nil
+	})
+	if err != nil {
+		logs.Errorf("scan orphan disk failed, err: %v, biz: %d, rid: %s", err, opt.BkBizID, kt.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// scanEip 未绑定任何实例的弹性公网IP，需同时满足没有与主机的关联关系且云上状态为未绑定
+func (o *orphan) scanEip(kt *kit.Kit, opt *ListOption) ([]cloudserver.OrphanRes, error) {
+	result := make([]cloudserver.OrphanRes, 0)
+	listEip := func(req *core.ListReq) ([]*dataeip.EipResult, error) {
+		res, err := o.client.DataService().Global.ListEip(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	}
+
+	err := listByPage(tools.ExpressionAnd(baseRules(opt)...), listEip, func(eips []*dataeip.EipResult) error {
+		ids := slice.Map(eips, func(one *dataeip.EipResult) string { return one.ID })
+		bound, err := relatedIDs(ids, "eip_id", func(req *core.ListReq) ([]string, error) {
+			res, err := o.client.DataService().Global.ListEipCvmRel(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(res.Details, func(one *protocloud.EipCvmRelResult) string { return one.EipID }), nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, one := range eips {
+			_, hasRel := bound[one.ID]
+			if !isEipUnbound(enumor.Vendor(one.Vendor), one.Status, hasRel) {
+				continue
+			}
+			result = append(result, cloudserver.OrphanRes{ResType: enumor.EipCloudResType, ID: one.ID,
+				CloudID: one.CloudID, Name: converter.PtrToVal(one.Name), Vendor: enumor.Vendor(one.Vendor),
+				AccountID: one.AccountID, Region: one.Region, BkBizID: one.BkBizID,
+				Reason: cloudserver.OrphanEipUnbound})
+		}
+		return nil
+	})
+	if err != nil {
+		logs.Errorf("scan orphan eip failed, err: %v, biz: %d, rid: %s", err, opt.BkBizID, kt.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// isEipUnbound 弹性公网IP是否未绑定，状态未知的云厂商不视为未绑定
+func isEipUnbound(vendor enumor.Vendor, status string, hasRel bool) bool {
+	if hasRel {
+		return false
+	}
+	unbound, exists := eipUnboundStatus[vendor]
+	return exists && status == unbound
+}
+
+// scanSecurityGroup 未关联主机，也未关联负载均衡等其他资源的安全组
+func (o *orphan) scanSecurityGroup(kt *kit.Kit, opt *ListOption) ([]cloudserver.OrphanRes, error) {
+	result := make([]cloudserver.OrphanRes, 0)
+	listSG := func(req *core.ListReq) ([]corecloud.BaseSecurityGroup, error) {
+		res, err := o.client.DataService().Global.SecurityGroup.ListSecurityGroup(kt.Ctx, kt.Header(),
+			&protocloud.SecurityGroupListReq{Filter: req.Filter, Page: req.Page})
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	}
+
+	err := listByPage(tools.ExpressionAnd(baseRules(opt)...), listSG, func(sgs []corecloud.BaseSecurityGroup) error {
+		ids := slice.Map(sgs, func(one corecloud.BaseSecurityGroup) string { return one.ID })
+		cvmUsed, err := relatedIDs(ids, "security_group_id", func(req *core.ListReq) ([]string, error) {
+			res, err := o.client.DataService().Global.SGCvmRel.List(kt.Ctx, kt.Header(), req)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(res.Details, func(one corecloud.SecurityGroupCvmRel) string {
+				return one.SecurityGroupID
+			}), nil
+		})
+		if err != nil {
+			return err
+		}
+
+		resUsed, err := relatedIDs(ids, "security_group_id", func(req *core.ListReq) ([]string, error) {
+			res, err := o.client.DataService().Global.SGCommonRel.List(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(res.Details, func(one corecloud.SecurityGroupCommonRel) string {
+				return one.SecurityGroupID
+			}), nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, one := range sgs {
+			if _, exists := cvmUsed[one.ID]; exists {
+				continue
+			}
+			if _, exists := resUsed[one.ID]; exists {
+				continue
+			}
+			result = append(result, cloudserver.OrphanRes{ResType: enumor.SecurityGroupCloudResType, ID: one.ID,
+				CloudID: one.CloudID, Name: one.Name, Vendor: one.Vendor, AccountID: one.AccountID,
+				Region: one.Region, BkBizID: one.BkBizID, Reason: cloudserver.OrphanSGUnused})
+		}
+		return nil
+	})
+	if err != nil {
+		logs.Errorf("scan orphan security group failed, err: %v, biz: %d, rid: %s", err, opt.BkBizID, kt.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// scanLoadBalancer 没有监听器，或监听器关联的目标组中没有任何后端的负载均衡
+func (o *orphan) scanLoadBalancer(kt *kit.Kit, opt *ListOption) ([]cloudserver.OrphanRes, error) {
+	result := make([]cloudserver.OrphanRes, 0)
+	listLb := func(req *core.ListReq) ([]corelb.BaseLoadBalancer, error) {
+		res, err := o.client.DataService().Global.LoadBalancer.ListLoadBalancer(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	}
+
+	err := listByPage(tools.ExpressionAnd(baseRules(opt)...), listLb, func(lbs []corelb.BaseLoadBalancer) error {
+		ids := slice.Map(lbs, func(one corelb.BaseLoadBalancer) string { return one.ID })
+		withListener, lbTgMap, tgWithTarget, err := o.getLbUsage(kt, ids)
+		if err != nil {
+			return err
+		}
+
+		for _, one := range lbs {
+			_, hasListener := withListener[one.ID]
+			reason, isOrphan := lbOrphanReason(hasListener, lbTgMap[one.ID], tgWithTarget)
+			if !isOrphan {
+				continue
+			}
+			result = append(result, cloudserver.OrphanRes{ResType: enumor.LoadBalancerCloudResType, ID: one.ID,
+				CloudID: one.CloudID, Name: one.Name, Vendor: one.Vendor, AccountID: one.AccountID,
+				Region: one.Region, BkBizID: one.BkBizID, Reason: reason})
+		}
+		return nil
+	})
+	if err != nil {
+		logs.Errorf("scan orphan load balancer failed, err: %v, biz: %d, rid: %s", err, opt.BkBizID, kt.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// getLbUsage 查询负载均衡的使用情况，返回有监听器的负载均衡、负载均衡关联的目标组以及有后端的目标组
+func (o *orphan) getLbUsage(kt *kit.Kit, lbIDs []string) (map[string]struct{}, map[string][]string,
+	map[string]struct{}, error) {
+
+	lbCli := o.client.DataService().Global.LoadBalancer
+	withListener, err := relatedIDs(lbIDs, "lb_id", func(req *core.ListReq) ([]string, error) {
+		res, err := lbCli.ListListener(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		return slice.Map(res.Details, func(one corelb.BaseListener) string { return one.LbID }), nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	lbTgMap := make(map[string][]string)
+	listRel := func(req *core.ListReq) ([]corelb.BaseTargetListenerRuleRel, error) {
+		res, err := lbCli.ListTargetGroupListenerRel(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	}
+	err = listByPage(tools.ContainersExpression("lb_id", lbIDs), listRel,
+		func(rels []corelb.BaseTargetListenerRuleRel) error {
+			for _, rel := range rels {
+				lbTgMap[rel.LbID] = append(lbTgMap[rel.LbID], rel.TargetGroupID)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	tgIDs := make([]string, 0)
+	for _, ids := range lbTgMap {
+		tgIDs = append(tgIDs, ids...)
+	}
+	tgWithTarget := make(map[string]struct{})
+	for _, batch := range slice.Split(slice.Unique(tgIDs), int(filter.DefaultMaxInLimit)) {
+		related, err := relatedIDs(batch, "target_group_id", func(req *core.ListReq) ([]string, error) {
+			res, err := lbCli.ListTarget(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(res.Details, func(one corelb.BaseTarget) string { return one.TargetGroupID }), nil
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for id := range related {
+			tgWithTarget[id] = struct{}{}
+		}
+	}
+
+	return withListener, lbTgMap, tgWithTarget, nil
+}
+
+// lbOrphanReason 根据负载均衡的监听器和后端情况判断是否闲置
+func lbOrphanReason(hasListener bool, tgIDs []string, tgWithTarget map[string]struct{}) (cloudserver.OrphanReason,
+	bool) {
+
+	if !hasListener {
+		return cloudserver.OrphanLbNoListener, true
+	}
+
+	for _, id := range tgIDs {
+		if _, exists := tgWithTarget[id]; exists {
+			return "", false
+		}
+	}
+	return cloudserver.OrphanLbNoTarget, true
+}
+
+// scanCvm 关机超过指定天数的主机，主机没有记录关机时间，以主机最后一次更新的时间作为关机时间的下限，
+// 主机在关机后有其他字段变更时会推迟被判定为闲置的时间
+func (o *orphan) scanCvm(kt *kit.Kit, opt *ListOption) ([]cloudserver.OrphanRes, error) {
+	stoppedDays := opt.StoppedDays
+	if stoppedDays <= 0 {
+		stoppedDays = cloudserver.DefaultOrphanStoppedDays
+	}
+	before := time.Now().AddDate(0, 0, -stoppedDays).Format(constant.TimeStdFormat)
+
+	rules := append(baseRules(opt), tools.RuleEqual("lifecycle_status", enumor.CvmLifecycleStopped),
+		&filter.AtomRule{Field: "updated_at", Op: filter.LessThan.Factory(), Value: before})
+
+	result := make([]cloudserver.OrphanRes, 0)
+	listCvm := func(req *core.ListReq) ([]corecvm.BaseCvm, error) {
+		res, err := o.client.DataService().Global.Cvm.ListCvm(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	}
+	err := listByPage(tools.ExpressionAnd(rules...), listCvm, func(cvms []corecvm.BaseCvm) error {
+		for _, one := range cvms {
+			result = append(result, cloudserver.OrphanRes{ResType: enumor.CvmCloudResType, ID: one.ID,
+				CloudID: one.CloudID, Name: one.Name, Vendor: one.Vendor, AccountID: one.AccountID,
+				Region: one.Region, BkBizID: one.BkBizID, Reason: cloudserver.OrphanCvmLongStopped,
+				IdleSince: one.UpdatedAt})
+		}
+		return nil
+	})
+	if err != nil {
+		logs.Errorf("scan long stopped cvm failed, err: %v, biz: %d, rid: %s", err, opt.BkBizID, kt.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// listByPage 分页查询全部记录，每页记录交给 handle 处理
+func listByPage[T any](expr *filter.Expression, list func(req *core.ListReq) ([]T, error),
+	handle func(details []T) error) error {
+
+	listReq := &core.ListReq{Filter: expr, Page: core.NewDefaultBasePage()}
+	for {
+		details, err := list(listReq)
+		if err != nil {
+			return err
+		}
+
+		if err = handle(details); err != nil {
+			return err
+		}
+
+		if len(details) < int(listReq.Page.Limit) {
+			return nil
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+}
+
+// relatedIDs 查询关联表中 field 字段在 ids 中的记录，返回存在关联记录的ID，ids 数量不能超过in操作的上限
+func relatedIDs(ids []string, field string, list func(req *core.ListReq) ([]string, error)) (
+	map[string]struct{}, error) {
+
+	related := make(map[string]struct{})
+	if len(ids) == 0 {
+		return related, nil
+	}
+
+	err := listByPage(tools.ContainersExpression(field, ids), list, func(details []string) error {
+		for _, id := range details {
+			related[id] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return related, nil
+}
@@ -0,0 +1,118 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package logicsrecycle
+
+import (
+	"hcm/cmd/cloud-server/logics/eip"
+	"hcm/pkg/api/cloud-server/recycle"
+	"hcm/pkg/api/core"
+	rr "hcm/pkg/api/core/recycle-record"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// eipRecycler 回收eip时解绑主机但保留eip的分配，恢复时重新绑定到原主机
+type eipRecycler struct {
+	client *client.ClientSet
+	eip    eip.Interface
+}
+
+// ResType ...
+func (r *eipRecycler) ResType() enumor.CloudResourceType {
+	return enumor.EipCloudResType
+}
+
+// Prepare disassociate eip from the bound cvm and record the binding info.
+func (r *eipRecycler) Prepare(kt *kit.Kit, info types.CloudResourceBasicInfo) (interface{}, error) {
+	relReq := &core.ListReq{
+		Filter: tools.EqualExpression("eip_id", info.ID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	rels, err := r.client.DataService().Global.ListEipCvmRel(kt, relReq)
+	if err != nil {
+		logs.Errorf("list eip cvm rel failed, err: %v, eip: %s, rid: %s", err, info.ID, kt.Rid)
+		return nil, err
+	}
+
+	detail := &rr.EipRecycleDetail{}
+	if len(rels.Details) == 0 {
+		return detail, nil
+	}
+
+	cvmID := rels.Details[0].CvmID
+	cvmDetail := map[string]*recycle.CvmDetail{
+		cvmID: {Vendor: info.Vendor, CvmID: cvmID, AccountID: info.AccountID},
+	}
+	if err = r.eip.BatchGetEipInfo(kt, cvmDetail); err != nil {
+		logs.Errorf("get eip bind info failed, err: %v, eip: %s, cvm: %s, rid: %s", err, info.ID, cvmID, kt.Rid)
+		return nil, err
+	}
+
+	detail.CvmID = cvmID
+	for _, one := range cvmDetail[cvmID].EipList {
+		if one.EipID == info.ID {
+			detail.NicID = one.NicID
+			break
+		}
+	}
+
+	err = r.eip.DisassociateEip(kt, info.Vendor, info.ID, detail.CvmID, detail.NicID, info.AccountID)
+	if err != nil {
+		logs.Errorf("disassociate eip failed, err: %v, eip: %s, cvm: %s, rid: %s", err, info.ID, cvmID, kt.Rid)
+		return nil, err
+	}
+
+	return detail, nil
+}
+
+// Restore re-associate eip to the cvm which it was bound to before recycle.
+func (r *eipRecycler) Restore(kt *kit.Kit, info types.CloudResourceBasicInfo, detail interface{}) error {
+	eipDetail := new(rr.EipRecycleDetail)
+	if err := decodeDetail(detail, eipDetail); err != nil {
+		return err
+	}
+
+	if len(eipDetail.CvmID) == 0 {
+		return nil
+	}
+
+	err := r.eip.AssociateEip(kt, info.Vendor, info.ID, eipDetail.CvmID, eipDetail.NicID, info.AccountID)
+	if err != nil {
+		logs.Errorf("associate eip failed, err: %v, eip: %s, cvm: %s, rid: %s", err, info.ID, eipDetail.CvmID,
+			kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// DestroyReady ...
+func (r *eipRecycler) DestroyReady(_ *kit.Kit, _ types.CloudResourceBasicInfo) (bool, error) {
+	return true, nil
+}
+
+// Destroy release the eip.
+func (r *eipRecycler) Destroy(kt *kit.Kit, info types.CloudResourceBasicInfo) error {
+	return r.eip.DeleteEip(kt, info.Vendor, info.ID)
+}
@@ -0,0 +1,116 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package logicsrecycle
+
+import (
+	"fmt"
+
+	actionlb "hcm/cmd/task-server/logics/action/load-balancer"
+	"hcm/pkg/api/core"
+	rr "hcm/pkg/api/core/recycle-record"
+	hclb "hcm/pkg/api/hc-service/load-balancer"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	cvt "hcm/pkg/tools/converter"
+)
+
+// lbRecycler 负载均衡回收，回收前需要满足删除条件，回收期间保持负载均衡不变
+type lbRecycler struct {
+	client *client.ClientSet
+}
+
+// ResType ...
+func (r *lbRecycler) ResType() enumor.CloudResourceType {
+	return enumor.LoadBalancerCloudResType
+}
+
+// Prepare check the load balancer can be deleted, the load balancer with delete protection or listeners can not be
+// recycled.
+func (r *lbRecycler) Prepare(kt *kit.Kit, info types.CloudResourceBasicInfo) (interface{}, error) {
+	if info.Vendor != enumor.TCloud {
+		return nil, errf.Newf(errf.InvalidParameter, "recycle load balancer not support vendor: %s", info.Vendor)
+	}
+
+	lbReq := &core.ListReq{
+		Filter: tools.EqualExpression("id", info.ID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	lbResp, err := r.client.DataService().TCloud.LoadBalancer.ListLoadBalancer(kt, lbReq)
+	if err != nil {
+		logs.Errorf("list load balancer failed, err: %v, id: %s, rid: %s", err, info.ID, kt.Rid)
+		return nil, err
+	}
+	for _, lb := range lbResp.Details {
+		if cvt.PtrToVal(lb.Extension.DeleteProtect) {
+			return nil, fmt.Errorf("%s(%s) is protected for delection", lb.Name, lb.CloudID)
+		}
+	}
+
+	lblReq := &core.ListReq{
+		Filter: tools.EqualExpression("lb_id", info.ID),
+		Page:   &core.BasePage{Count: false, Start: 0, Limit: 1},
+	}
+	listenerResp, err := r.client.DataService().Global.LoadBalancer.ListListener(kt, lblReq)
+	if err != nil {
+		logs.Errorf("list listener failed, err: %v, lb id: %s, rid: %s", err, info.ID, kt.Rid)
+		return nil, err
+	}
+	if len(listenerResp.Details) != 0 {
+		lbl := listenerResp.Details[0]
+		return nil, fmt.Errorf("load balancer(%s) with listener(%s:%s) can not be recycled",
+			lbl.CloudLbID, lbl.CloudID, lbl.Name)
+	}
+
+	return &rr.LoadBalancerRecycleDetail{}, nil
+}
+
+// Restore ...
+func (r *lbRecycler) Restore(_ *kit.Kit, _ types.CloudResourceBasicInfo, _ interface{}) error {
+	return nil
+}
+
+// DestroyReady ...
+func (r *lbRecycler) DestroyReady(_ *kit.Kit, _ types.CloudResourceBasicInfo) (bool, error) {
+	return true, nil
+}
+
+// Destroy delete the load balancer by task flow.
+func (r *lbRecycler) Destroy(kt *kit.Kit, info types.CloudResourceBasicInfo) error {
+	task := ts.CustomFlowTask{
+		ActionName: enumor.ActionDeleteLoadBalancer,
+		Params: actionlb.DeleteLoadBalancerOption{
+			Vendor: info.Vendor,
+			TCloudBatchDeleteLoadbalancerReq: hclb.TCloudBatchDeleteLoadbalancerReq{
+				AccountID: info.AccountID,
+				Region:    info.Region,
+				IDs:       []string{info.ID},
+			},
+		},
+		Retry: tableasync.NewRetryWithPolicy(3, 1000, 5000),
+	}
+	return runSingleTaskFlow(kt, r.client, enumor.FlowDeleteLoadBalancer, task)
+}
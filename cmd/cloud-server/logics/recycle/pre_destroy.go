@@ -0,0 +1,388 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package logicsrecycle
+
+import (
+	"fmt"
+	"strings"
+
+	"hcm/pkg/api/core"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	coreimage "hcm/pkg/api/core/cloud/image"
+	corerr "hcm/pkg/api/core/recycle-record"
+	"hcm/pkg/api/data-service/recycle-record"
+	hcdisk "hcm/pkg/api/hc-service/disk"
+	hcimage "hcm/pkg/api/hc-service/image"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	rr "hcm/pkg/dal/table/recycle-record"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+// GetRecyclePolicy 获取资源在业务下生效的回收策略，业务策略优先于全局策略，没有策略时返回nil
+func GetRecyclePolicy(kt *kit.Kit, c *client.ClientSet, resType enumor.CloudResourceType, bizID int64) (
+	*corerr.RecyclePolicy, error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("res_type", resType),
+			tools.RuleIn("bk_biz_id", []int64{bizID, rr.GlobalRecyclePolicyBizID}),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := c.DataService().Global.RecycleRecord.ListRecyclePolicy(kt, listReq)
+	if err != nil {
+		logs.Errorf("list recycle policy failed, err: %v, res type: %s, biz: %d, rid: %s", err, resType, bizID,
+			kt.Rid)
+		return nil, err
+	}
+
+	return pickRecyclePolicy(result.Details, bizID), nil
+}
+
+// pickRecyclePolicy 从业务策略和全局策略中选出生效的策略，业务策略优先于全局策略
+func pickRecyclePolicy(policies []corerr.RecyclePolicy, bizID int64) *corerr.RecyclePolicy {
+	var policy *corerr.RecyclePolicy
+	for i := range policies {
+		if policies[i].BkBizID == bizID {
+			return &policies[i]
+		}
+		if policies[i].BkBizID == rr.GlobalRecyclePolicyBizID {
+			policy = &policies[i]
+		}
+	}
+
+	return policy
+}
+
+// PreDestroy 按回收策略在资源销毁前为硬盘创建快照或为主机创建私有镜像，返回资源是否可以销毁。快照或镜像创建后记录在
+// 回收记录中，已经创建过的记录不会重复创建，其云上状态可用前资源保持待回收，创建失败时返回错误
+func PreDestroy(kt *kit.Kit, c *client.ClientSet, record corerr.RecycleRecord,
+	info types.CloudResourceBasicInfo) (bool, error) {
+
+	if preDestroyDone(record) {
+		return true, nil
+	}
+
+	if len(record.SnapshotIDs) == 0 {
+		policy, err := GetRecyclePolicy(kt, c, record.ResType, record.BkBizID)
+		if err != nil {
+			return false, err
+		}
+
+		preAction, err := decidePreDestroyAction(policy, record.ResType, info.Vendor)
+		if err != nil {
+			return false, err
+		}
+
+		if preAction == enumor.NoneRecyclePreDestroyAction {
+			return true, nil
+		}
+
+		event := &corerr.TimelineEvent{Stage: enumor.SnapshottingRecycleTimelineStage, Message: string(preAction)}
+		if err = updateRecord(kt, c, recyclerecord.UpdateReq{ID: record.ID, Timeline: event}); err != nil {
+			return false, err
+		}
+
+		id, err := createPreDestroyBackup(kt, c, preAction, record, info)
+		if err != nil {
+			return false, err
+		}
+
+		if err = updateRecord(kt, c, recyclerecord.UpdateReq{ID: record.ID, SnapshotIDs: []string{id}}); err != nil {
+			return false, err
+		}
+		record.SnapshotIDs = []string{id}
+	}
+
+	ready, err := preDestroyBackupReady(kt, c, record, info)
+	if err != nil || !ready {
+		return false, err
+	}
+
+	event := &corerr.TimelineEvent{Stage: enumor.SnapshottedRecycleTimelineStage,
+		Message: strings.Join(record.SnapshotIDs, ",")}
+	if err = updateRecord(kt, c, recyclerecord.UpdateReq{ID: record.ID, Timeline: event}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// preDestroyDone 资源类型不支持销毁前动作，或销毁前创建的快照或镜像已可用时，不需要再执行销毁前动作
+func preDestroyDone(record corerr.RecycleRecord) bool {
+	if !enumor.IsRecyclePreDestroySupported(record.ResType) {
+		return true
+	}
+
+	for _, event := range record.Timeline {
+		if event.Stage == enumor.SnapshottedRecycleTimelineStage {
+			return true
+		}
+	}
+
+	return false
+}
+
+// preDestroyActionVendors 各销毁前动作支持的云厂商
+var preDestroyActionVendors = map[enumor.RecyclePreDestroyAction][]enumor.Vendor{
+	enumor.SnapshotRecyclePreDestroyAction: coredisk.SnapshotVendors,
+	enumor.ImageRecyclePreDestroyAction:    coreimage.PrivateImageVendors,
+}
+
+// decidePreDestroyAction 根据回收策略决定资源销毁前需要执行的动作，云厂商不支持策略的销毁前动作时返回错误，
+// 避免资源在没有备份的情况下被销毁
+func decidePreDestroyAction(policy *corerr.RecyclePolicy, resType enumor.CloudResourceType,
+	vendor enumor.Vendor) (enumor.RecyclePreDestroyAction, error) {
+
+	if policy == nil || policy.PreDestroyAction == enumor.NoneRecyclePreDestroyAction {
+		return enumor.NoneRecyclePreDestroyAction, nil
+	}
+
+	if err := policy.PreDestroyAction.Validate(resType); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if !slice.IsItemInSlice(preDestroyActionVendors[policy.PreDestroyAction], vendor) {
+		return "", errf.Newf(errf.InvalidParameter, "recycle pre-destroy action %s does not support vendor: %s",
+			policy.PreDestroyAction, vendor)
+	}
+
+	return policy.PreDestroyAction, nil
+}
+
+// ValidatePreDestroyVendor 校验资源在业务下生效的回收策略的销毁前动作是否支持该云厂商，用于资源放入回收站前拒绝
+// 销毁时无法执行销毁前动作的资源
+func ValidatePreDestroyVendor(kt *kit.Kit, c *client.ClientSet, resType enumor.CloudResourceType, bizID int64,
+	vendor enumor.Vendor) error {
+
+	policy, err := GetRecyclePolicy(kt, c, resType, bizID)
+	if err != nil {
+		return err
+	}
+
+	_, err = decidePreDestroyAction(policy, resType, vendor)
+	return err
+}
+
+// createPreDestroyBackup 执行销毁前动作，返回创建的快照或私有镜像的ID，快照和镜像名称使用回收记录ID，便于追溯
+func createPreDestroyBackup(kt *kit.Kit, c *client.ClientSet, preAction enumor.RecyclePreDestroyAction,
+	record corerr.RecycleRecord, info types.CloudResourceBasicInfo) (string, error) {
+
+	name := fmt.Sprintf("recycle-%s", record.ID)
+	memo := fmt.Sprintf("created before recycle record %s is destroyed", record.ID)
+	var result *core.CreateResult
+	var err error
+	switch preAction {
+	case enumor.SnapshotRecyclePreDestroyAction:
+		req := &hcdisk.SnapshotCreateReq{AccountID: info.AccountID, DiskID: record.ResID, SnapshotName: name,
+			Memo: memo}
+		result, err = createDiskSnapshot(kt, c, info.Vendor, req)
+
+	case enumor.ImageRecyclePreDestroyAction:
+		// 主机即将被销毁，创建镜像时允许强制关机
+		req := &hcimage.PrivateImageCreateReq{AccountID: info.AccountID, CvmID: record.ResID, ImageName: name,
+			ForcePoweroff: info.Vendor == enumor.TCloud, Memo: memo}
+		result, err = createPrivateImage(kt, c, info.Vendor, req)
+
+	default:
+		return "", errf.Newf(errf.InvalidParameter, "unsupported recycle pre-destroy action: %s", preAction)
+	}
+	if err != nil {
+		logs.Errorf("recycle pre-destroy action %s failed, err: %v, record: %s, rid: %s", preAction, err, record.ID,
+			kt.Rid)
+		return "", err
+	}
+
+	if result == nil || len(result.ID) == 0 {
+		return "", fmt.Errorf("recycle pre-destroy action %s returns empty id", preAction)
+	}
+
+	return result.ID, nil
+}
+
+// preDestroyBackupReady 检查销毁前创建的快照或私有镜像的云上状态是否可用，创建失败或已被删除时返回错误
+func preDestroyBackupReady(kt *kit.Kit, c *client.ClientSet, record corerr.RecycleRecord,
+	info types.CloudResourceBasicInfo) (bool, error) {
+
+	switch record.ResType {
+	case enumor.DiskCloudResType:
+		return diskSnapshotReady(kt, c, info, record.SnapshotIDs)
+	case enumor.CvmCloudResType:
+		return privateImageReady(kt, c, info, record.SnapshotIDs)
+	default:
+		return false, errf.Newf(errf.InvalidParameter, "%s does not support recycle pre-destroy action",
+			record.ResType)
+	}
+}
+
+func diskSnapshotReady(kt *kit.Kit, c *client.ClientSet, info types.CloudResourceBasicInfo, ids []string) (bool,
+	error) {
+
+	req := &hcdisk.SnapshotStatusRefreshReq{AccountID: info.AccountID, IDs: ids}
+	result, err := refreshDiskSnapshotStatus(kt, c, info.Vendor, req)
+	if err != nil {
+		logs.Errorf("refresh disk snapshot status failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return false, err
+	}
+
+	for _, one := range result.Details {
+		switch coredisk.NormalizeSnapshotStatus(info.Vendor, one.Status) {
+		case enumor.DiskSnapshotNormal:
+		case enumor.DiskSnapshotFailed:
+			return false, fmt.Errorf("disk snapshot %s is failed to create, status: %s", one.CloudID, one.Status)
+		default:
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func privateImageReady(kt *kit.Kit, c *client.ClientSet, info types.CloudResourceBasicInfo, ids []string) (bool,
+	error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", ids),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := c.DataService().Global.PrivateImage.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list private image failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return false, err
+	}
+
+	// 同步时会删除云上已不存在的镜像记录
+	if len(result.Details) != len(ids) {
+		return false, errf.Newf(errf.RecordNotFound, "some private images of %v are not found", ids)
+	}
+
+	ready := true
+	for _, one := range result.Details {
+		switch one.Status {
+		case enumor.PrivateImageNormal:
+		case enumor.PrivateImageFailed:
+			return false, fmt.Errorf("private image %s is failed to create", one.CloudID)
+		default:
+			ready = false
+		}
+	}
+
+	if ready {
+		return true, nil
+	}
+
+	// 镜像状态由同步更新，未就绪时主动同步一次，下一轮回收时再检查
+	if err = syncPrivateImage(kt, c, info.Vendor, &hcimage.PrivateImageSyncReq{AccountID: info.AccountID}); err != nil {
+		logs.Errorf("sync private image failed, err: %v, account: %s, rid: %s", err, info.AccountID, kt.Rid)
+		return false, err
+	}
+
+	return false, nil
+}
+
+func createDiskSnapshot(kt *kit.Kit, c *client.ClientSet, vendor enumor.Vendor, req *hcdisk.SnapshotCreateReq) (
+	*core.CreateResult, error) {
+
+	switch vendor {
+	case enumor.TCloud:
+		return c.HCService().TCloud.DiskSnapshot.Create(kt, req)
+	case enumor.Aws:
+		return c.HCService().Aws.DiskSnapshot.Create(kt, req)
+	case enumor.HuaWei:
+		return c.HCService().HuaWei.DiskSnapshot.Create(kt, req)
+	case enumor.Azure:
+		return c.HCService().Azure.DiskSnapshot.Create(kt, req)
+	case enumor.Gcp:
+		return c.HCService().Gcp.DiskSnapshot.Create(kt, req)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "%s does not support disk snapshot", vendor)
+	}
+}
+
+func refreshDiskSnapshotStatus(kt *kit.Kit, c *client.ClientSet, vendor enumor.Vendor,
+	req *hcdisk.SnapshotStatusRefreshReq) (*hcdisk.SnapshotStatusRefreshResult, error) {
+
+	switch vendor {
+	case enumor.TCloud:
+		return c.HCService().TCloud.DiskSnapshot.RefreshStatus(kt, req)
+	case enumor.Aws:
+		return c.HCService().Aws.DiskSnapshot.RefreshStatus(kt, req)
+	case enumor.HuaWei:
+		return c.HCService().HuaWei.DiskSnapshot.RefreshStatus(kt, req)
+	case enumor.Azure:
+		return c.HCService().Azure.DiskSnapshot.RefreshStatus(kt, req)
+	case enumor.Gcp:
+		return c.HCService().Gcp.DiskSnapshot.RefreshStatus(kt, req)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "%s does not support disk snapshot", vendor)
+	}
+}
+
+func createPrivateImage(kt *kit.Kit, c *client.ClientSet, vendor enumor.Vendor, req *hcimage.PrivateImageCreateReq) (
+	*core.CreateResult, error) {
+
+	switch vendor {
+	case enumor.TCloud:
+		return c.HCService().TCloud.PrivateImage.Create(kt, req)
+	case enumor.HuaWei:
+		return c.HCService().HuaWei.PrivateImage.Create(kt, req)
+	case enumor.Aws:
+		return c.HCService().Aws.PrivateImage.Create(kt, req)
+	case enumor.Gcp:
+		return c.HCService().Gcp.PrivateImage.Create(kt, req)
+	case enumor.Azure:
+		return c.HCService().Azure.PrivateImage.Create(kt, req)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "%s does not support private image", vendor)
+	}
+}
+
+func syncPrivateImage(kt *kit.Kit, c *client.ClientSet, vendor enumor.Vendor, req *hcimage.PrivateImageSyncReq) error {
+	switch vendor {
+	case enumor.TCloud:
+		return c.HCService().TCloud.PrivateImage.Sync(kt, req)
+	case enumor.HuaWei:
+		return c.HCService().HuaWei.PrivateImage.Sync(kt, req)
+	case enumor.Aws:
+		return c.HCService().Aws.PrivateImage.Sync(kt, req)
+	case enumor.Gcp:
+		return c.HCService().Gcp.PrivateImage.Sync(kt, req)
+	case enumor.Azure:
+		return c.HCService().Azure.PrivateImage.Sync(kt, req)
+	default:
+		return errf.Newf(errf.InvalidParameter, "%s does not support private image", vendor)
+	}
+}
+
+func updateRecord(kt *kit.Kit, c *client.ClientSet, req recyclerecord.UpdateReq) error {
+	updateReq := &recyclerecord.BatchUpdateReq{Data: []recyclerecord.UpdateReq{req}}
+	if err := c.DataService().Global.RecycleRecord.BatchUpdateRecycleRecord(kt, updateReq); err != nil {
+		logs.Errorf("update recycle record failed, err: %v, id: %s, rid: %s", err, req.ID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package logicsrecycle
+
+import (
+	"testing"
+
+	corerr "hcm/pkg/api/core/recycle-record"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/types"
+	rr "hcm/pkg/dal/table/recycle-record"
+	"hcm/pkg/kit"
+)
+
+func TestPickRecyclePolicy(t *testing.T) {
+	global := corerr.RecyclePolicy{ID: "global", BkBizID: rr.GlobalRecyclePolicyBizID}
+	biz := corerr.RecyclePolicy{ID: "biz", BkBizID: 100}
+
+	cases := []struct {
+		name     string
+		policies []corerr.RecyclePolicy
+		bizID    int64
+		expect   string
+	}{
+		{name: "no policy", policies: nil, bizID: 100, expect: ""},
+		{name: "only global", policies: []corerr.RecyclePolicy{global}, bizID: 100, expect: "global"},
+		{name: "biz before global", policies: []corerr.RecyclePolicy{biz, global}, bizID: 100, expect: "biz"},
+		{name: "biz after global", policies: []corerr.RecyclePolicy{global, biz}, bizID: 100, expect: "biz"},
+		{name: "other biz", policies: []corerr.RecyclePolicy{biz}, bizID: 200, expect: ""},
+		{name: "other biz with global", policies: []corerr.RecyclePolicy{biz, global}, bizID: 200,
+			expect: "global"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			policy := pickRecyclePolicy(c.policies, c.bizID)
+			if c.expect == "" {
+				if policy != nil {
+					t.Fatalf("expect no policy, got %s", policy.ID)
+				}
+				return
+			}
+			if policy == nil || policy.ID != c.expect {
+				t.Fatalf("expect policy %s, got %+v", c.expect, policy)
+			}
+		})
+	}
+}
+
+func TestDecidePreDestroyAction(t *testing.T) {
+	snapshot := &corerr.RecyclePolicy{PreDestroyAction: enumor.SnapshotRecyclePreDestroyAction}
+	image := &corerr.RecyclePolicy{PreDestroyAction: enumor.ImageRecyclePreDestroyAction}
+
+	cases := []struct {
+		name    string
+		policy  *corerr.RecyclePolicy
+		resType enumor.CloudResourceType
+		vendor  enumor.Vendor
+		expect  enumor.RecyclePreDestroyAction
+		wantErr bool
+	}{
+		{name: "no policy", resType: enumor.DiskCloudResType, vendor: enumor.TCloud,
+			expect: enumor.NoneRecyclePreDestroyAction},
+		{name: "none action", policy: &corerr.RecyclePolicy{}, resType: enumor.DiskCloudResType,
+			vendor: enumor.Azure, expect: enumor.NoneRecyclePreDestroyAction},
+		{name: "tcloud snapshot", policy: snapshot, resType: enumor.DiskCloudResType, vendor: enumor.TCloud,
+			expect: enumor.SnapshotRecyclePreDestroyAction},
+		{name: "gcp snapshot", policy: snapshot, resType: enumor.DiskCloudResType, vendor: enumor.Gcp,
+			expect: enumor.SnapshotRecyclePreDestroyAction},
+		{name: "huawei image", policy: image, resType: enumor.CvmCloudResType, vendor: enumor.HuaWei,
+			expect: enumor.ImageRecyclePreDestroyAction},
+		{name: "aws image", policy: image, resType: enumor.CvmCloudResType, vendor: enumor.Aws,
+			expect: enumor.ImageRecyclePreDestroyAction},
+		{name: "unsupported image vendor", policy: image, resType: enumor.CvmCloudResType, vendor: enumor.Kaopu,
+			wantErr: true},
+		{name: "action mismatches res type", policy: image, resType: enumor.DiskCloudResType,
+			vendor: enumor.TCloud, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			action, err := decidePreDestroyAction(c.policy, c.resType, c.vendor)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("expect err: %v, got: %v", c.wantErr, err)
+			}
+			if action != c.expect {
+				t.Fatalf("expect action %q, got %q", c.expect, action)
+			}
+		})
+	}
+}
+
+func TestPreDestroyDone(t *testing.T) {
+	// 已完成销毁前动作或不支持销毁前动作的记录直接返回，不会再查询策略或调用云上接口
+	cases := []corerr.RecycleRecord{
+		{BaseRecycleRecord: corerr.BaseRecycleRecord{ID: "r-1", ResType: enumor.EipCloudResType}},
+		{BaseRecycleRecord: corerr.BaseRecycleRecord{ID: "r-2", ResType: enumor.DiskCloudResType,
+			SnapshotIDs: []string{"snap-1"}, Timeline: []corerr.TimelineEvent{
+				{Stage: enumor.ScheduledRecycleTimelineStage}, {Stage: enumor.SnapshottedRecycleTimelineStage}}}},
+	}
+
+	for _, record := range cases {
+		ready, err := PreDestroy(kit.New(), nil, record, types.CloudResourceBasicInfo{Vendor: enumor.TCloud})
+		if err != nil || !ready {
+			t.Fatalf("pre-destroy of record %s should be skipped, got ready: %v, err: %v", record.ID, ready, err)
+		}
+	}
+
+	snapshotting := corerr.RecycleRecord{BaseRecycleRecord: corerr.BaseRecycleRecord{ID: "r-3",
+		ResType: enumor.CvmCloudResType, SnapshotIDs: []string{"img-1"},
+		Timeline: []corerr.TimelineEvent{{Stage: enumor.SnapshottingRecycleTimelineStage}}}}
+	if preDestroyDone(snapshotting) {
+		t.Fatalf("pre-destroy of record with snapshotting backup should not be done")
+	}
+}
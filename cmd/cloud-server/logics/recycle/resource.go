@@ -0,0 +1,137 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package logicsrecycle
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"hcm/cmd/cloud-server/logics/async"
+	"hcm/cmd/cloud-server/logics/audit"
+	"hcm/cmd/cloud-server/logics/eip"
+	corerr "hcm/pkg/api/core/recycle-record"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// ResRecycler defines the resource specific operations of the recycle bin, the resource is soft deleted when it is
+// put into recycle bin, and is restored when it is recovered, or destroyed after the recycle wait period.
+type ResRecycler interface {
+	// ResType returns the cloud resource type of the recycler.
+	ResType() enumor.CloudResourceType
+	// Prepare soft deletes the resource before it is put into recycle bin, such as releasing the bindings of the
+	// resource, and returns the recycle detail which records how to restore the resource.
+	Prepare(kt *kit.Kit, info types.CloudResourceBasicInfo) (interface{}, error)
+	// Restore the resource by the recycle detail returned by Prepare when the resource is recovered.
+	Restore(kt *kit.Kit, info types.CloudResourceBasicInfo, detail interface{}) error
+	// DestroyReady returns whether the resource can be destroyed now, the resource which is not ready is kept in
+	// recycle bin until it is ready, such as the vpc whose subnets are not destroyed yet.
+	DestroyReady(kt *kit.Kit, info types.CloudResourceBasicInfo) (bool, error)
+	// Destroy the resource actually.
+	Destroy(kt *kit.Kit, info types.CloudResourceBasicInfo) error
+}
+
+// NewResRecyclers new all the resource recyclers of the resources which are recycled in the generic way.
+func NewResRecyclers(c *client.ClientSet, audit audit.Interface,
+	eipLgc eip.Interface) map[enumor.CloudResourceType]ResRecycler {
+
+	recyclers := []ResRecycler{
+		&eipRecycler{client: c, eip: eipLgc},
+		&sgRecycler{client: c, audit: audit},
+		&lbRecycler{client: c},
+		&vpcRecycler{client: c},
+		&subnetRecycler{client: c},
+	}
+
+	recyclerMap := make(map[enumor.CloudResourceType]ResRecycler, len(recyclers))
+	for _, one := range recyclers {
+		recyclerMap[one.ResType()] = one
+	}
+	return recyclerMap
+}
+
+// RecoverRes recover the recycled resources. the recycle records are recovered by recoverRecords first, and the
+// relations released when recycling are restored only after that succeeds, so that a failed recovery never leaves a
+// resource in recycle bin with its relations already restored. the resources whose relations failed to be restored
+// are still recovered, they are returned as partial failure.
+func RecoverRes(kt *kit.Kit, recycler ResRecycler, infos map[string]types.CloudResourceBasicInfo,
+	records []corerr.RecycleRecord, recoverRecords func() error) error {
+
+	if err := recoverRecords(); err != nil {
+		logs.Errorf("recover %s recycle records failed, err: %v, rid: %s", recycler.ResType(), err, kt.Rid)
+		return err
+	}
+
+	failedIDs := make([]string, 0)
+	for _, record := range records {
+		if err := recycler.Restore(kt, infos[record.ResID], record.Detail); err != nil {
+			logs.Errorf("restore %s(%s) failed, err: %v, detail: %+v, rid: %s", recycler.ResType(), record.ResID, err,
+				record.Detail, kt.Rid)
+			failedIDs = append(failedIDs, record.ResID)
+		}
+	}
+
+	if len(failedIDs) > 0 {
+		return errf.Newf(errf.PartialFailed, "%s(ids: %v) are recovered, but failed to restore their relations",
+			recycler.ResType(), failedIDs)
+	}
+	return nil
+}
+
+// decodeDetail decode the recycle detail of recycle record into the detail of specific resource type.
+func decodeDetail(detail interface{}, result interface{}) error {
+	if detail == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(detail)
+	if err != nil {
+		return fmt.Errorf("marshal recycle detail failed, err: %v", err)
+	}
+
+	if err = json.Unmarshal(raw, result); err != nil {
+		return fmt.Errorf("unmarshal recycle detail failed, err: %v", err)
+	}
+
+	return nil
+}
+
+// runSingleTaskFlow create a custom flow with single task and wait for the task to end.
+func runSingleTaskFlow(kt *kit.Kit, c *client.ClientSet, flowName enumor.FlowName, task ts.CustomFlowTask) error {
+	task.ActionID = action.ActIDType("1")
+	flowReq := &ts.AddCustomFlowReq{
+		Name:  flowName,
+		Tasks: []ts.CustomFlowTask{task},
+	}
+
+	result, err := c.TaskServer().CreateCustomFlow(kt, flowReq)
+	if err != nil {
+		logs.Errorf("call taskserver to create custom flow failed, err: %v, flow: %s, rid: %s", err, flowName, kt.Rid)
+		return err
+	}
+
+	return async.WaitTaskToEnd(kt, c.TaskServer(), result.ID)
+}
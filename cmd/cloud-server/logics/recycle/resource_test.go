@@ -0,0 +1,111 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package logicsrecycle
+
+import (
+	"errors"
+	"testing"
+
+	corerr "hcm/pkg/api/core/recycle-record"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+)
+
+type fakeRecycler struct {
+	failIDs  map[string]bool
+	restored []string
+}
+
+func (f *fakeRecycler) ResType() enumor.CloudResourceType {
+	return enumor.SecurityGroupCloudResType
+}
+
+func (f *fakeRecycler) Prepare(_ *kit.Kit, _ types.CloudResourceBasicInfo) (interface{}, error) {
+	return nil, nil
+}
+
+func (f *fakeRecycler) Restore(_ *kit.Kit, info types.CloudResourceBasicInfo, _ interface{}) error {
+	if f.failIDs[info.ID] {
+		return errors.New("restore failed")
+	}
+	f.restored = append(f.restored, info.ID)
+	return nil
+}
+
+func (f *fakeRecycler) DestroyReady(_ *kit.Kit, _ types.CloudResourceBasicInfo) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeRecycler) Destroy(_ *kit.Kit, _ types.CloudResourceBasicInfo) error {
+	return nil
+}
+
+func TestRecoverRes(t *testing.T) {
+	infos := map[string]types.CloudResourceBasicInfo{"sg-1": {ID: "sg-1"}, "sg-2": {ID: "sg-2"}}
+	records := []corerr.RecycleRecord{
+		{BaseRecycleRecord: corerr.BaseRecycleRecord{ID: "r-1", ResID: "sg-1"}},
+		{BaseRecycleRecord: corerr.BaseRecycleRecord{ID: "r-2", ResID: "sg-2"}},
+	}
+
+	cases := []struct {
+		name       string
+		recoverErr error
+		failIDs    map[string]bool
+		restored   []string
+		wantErr    bool
+		partial    bool
+	}{
+		{name: "recover succeeded", restored: []string{"sg-1", "sg-2"}},
+		{name: "recover failed", recoverErr: errors.New("db error"), wantErr: true},
+		{name: "restore partially failed", failIDs: map[string]bool{"sg-1": true}, restored: []string{"sg-2"},
+			wantErr: true, partial: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			recycler := &fakeRecycler{failIDs: c.failIDs}
+			recovered := false
+			err := RecoverRes(kit.New(), recycler, infos, records, func() error {
+				recovered = true
+				return c.recoverErr
+			})
+
+			if !recovered {
+				t.Fatalf("recycle records are not recovered")
+			}
+			if (err != nil) != c.wantErr {
+				t.Fatalf("expect err: %v, got: %v", c.wantErr, err)
+			}
+			if c.partial && errf.Error(err).Code != errf.PartialFailed {
+				t.Fatalf("expect partial failed, got: %v", err)
+			}
+			if len(recycler.restored) != len(c.restored) {
+				t.Fatalf("expect restored %v, got %v", c.restored, recycler.restored)
+			}
+			for i := range c.restored {
+				if recycler.restored[i] != c.restored[i] {
+					t.Fatalf("expect restored %v, got %v", c.restored, recycler.restored)
+				}
+			}
+		})
+	}
+}
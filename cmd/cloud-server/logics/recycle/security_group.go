@@ -0,0 +1,292 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package logicsrecycle
+
+import (
+	"fmt"
+	"sort"
+
+	"hcm/cmd/cloud-server/logics/audit"
+	actionsg "hcm/cmd/task-server/logics/action/security-group"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	rr "hcm/pkg/api/core/recycle-record"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	hcproto "hcm/pkg/api/hc-service"
+	hclb "hcm/pkg/api/hc-service/load-balancer"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+)
+
+// sgRecycler 回收安全组时解除与主机、负载均衡的关联，恢复时按原有优先级重新关联
+type sgRecycler struct {
+	client *client.ClientSet
+	audit  audit.Interface
+}
+
+// ResType ...
+func (r *sgRecycler) ResType() enumor.CloudResourceType {
+	return enumor.SecurityGroupCloudResType
+}
+
+// Prepare detach security group from the cvms and load balancers, and record the relations.
+func (r *sgRecycler) Prepare(kt *kit.Kit, info types.CloudResourceBasicInfo) (interface{}, error) {
+	switch info.Vendor {
+	case enumor.TCloud, enumor.Aws, enumor.HuaWei:
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "recycle security group not support vendor: %s", info.Vendor)
+	}
+
+	detail := &rr.SecurityGroupRecycleDetail{}
+
+	cvmIDs, err := r.listRelCvmIDs(kt, info.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, cvmID := range cvmIDs {
+		if err = r.disassociateCvm(kt, info, cvmID); err != nil {
+			return detail, err
+		}
+		detail.CvmIDs = append(detail.CvmIDs, cvmID)
+	}
+
+	if info.Vendor != enumor.TCloud {
+		return detail, nil
+	}
+
+	lbRels, err := r.listLbRels(kt, tools.ExpressionAnd(tools.RuleEqual("security_group_id", info.ID),
+		tools.RuleEqual("res_type", enumor.LoadBalancerCloudResType)))
+	if err != nil {
+		return detail, err
+	}
+	for _, rel := range lbRels {
+		req := &hclb.TCloudDisAssociateLbSecurityGroupReq{LbID: rel.ResID, SecurityGroupID: info.ID}
+		if err = r.client.HCService().TCloud.SecurityGroup.DisassociateLb(kt.Ctx, kt.Header(), req); err != nil {
+			logs.Errorf("disassociate security group from lb failed, err: %v, sg: %s, lb: %s, rid: %s", err,
+				info.ID, rel.ResID, kt.Rid)
+			return detail, err
+		}
+		r.operationAudit(kt, info.ID, protoaudit.Disassociate, enumor.LoadBalancerAuditResType, rel.ResID)
+		detail.LoadBalancers = append(detail.LoadBalancers, rr.SGLbBindInfo{LbID: rel.ResID, Priority: rel.Priority})
+	}
+
+	return detail, nil
+}
+
+func (r *sgRecycler) listRelCvmIDs(kt *kit.Kit, sgID string) ([]string, error) {
+	cvmIDs := make([]string, 0)
+	req := &core.ListReq{
+		Filter: tools.EqualExpression("security_group_id", sgID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	for {
+		result, err := r.client.DataService().Global.SGCvmRel.List(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			logs.Errorf("list security group cvm rel failed, err: %v, sg: %s, rid: %s", err, sgID, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range result.Details {
+			cvmIDs = append(cvmIDs, one.CvmID)
+		}
+
+		if len(result.Details) < int(core.DefaultMaxPageLimit) {
+			break
+		}
+		req.Page.Start += uint32(core.DefaultMaxPageLimit)
+	}
+
+	return cvmIDs, nil
+}
+
+// listLbRels list the security group load balancer relations by filter, the relations are sorted by priority.
+func (r *sgRecycler) listLbRels(kt *kit.Kit, expr *filter.Expression) ([]corecloud.SecurityGroupCommonRel, error) {
+	rels := make([]corecloud.SecurityGroupCommonRel, 0)
+	req := &core.ListReq{
+		Filter: expr,
+		Page:   core.NewDefaultBasePage(),
+	}
+	for {
+		result, err := r.client.DataService().Global.SGCommonRel.List(kt, req)
+		if err != nil {
+			logs.Errorf("list security group common rel failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		rels = append(rels, result.Details...)
+
+		if len(result.Details) < int(core.DefaultMaxPageLimit) {
+			break
+		}
+		req.Page.Start += uint32(core.DefaultMaxPageLimit)
+	}
+
+	sort.SliceStable(rels, func(i, j int) bool { return rels[i].Priority < rels[j].Priority })
+	return rels, nil
+}
+
+func (r *sgRecycler) disassociateCvm(kt *kit.Kit, info types.CloudResourceBasicInfo, cvmID string) error {
+	req := &hcproto.SecurityGroupAssociateCvmReq{SecurityGroupID: info.ID, CvmID: cvmID}
+
+	var err error
+	switch info.Vendor {
+	case enumor.TCloud:
+		err = r.client.HCService().TCloud.SecurityGroup.DisassociateCvm(kt.Ctx, kt.Header(), req)
+	case enumor.Aws:
+		err = r.client.HCService().Aws.SecurityGroup.DisassociateCvm(kt.Ctx, kt.Header(), req)
+	case enumor.HuaWei:
+		err = r.client.HCService().HuaWei.SecurityGroup.DisassociateCvm(kt.Ctx, kt.Header(), req)
+	default:
+		err = errf.Newf(errf.InvalidParameter, "disassociate cvm not support vendor: %s", info.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("disassociate security group from cvm failed, err: %v, sg: %s, cvm: %s, rid: %s", err, info.ID,
+			cvmID, kt.Rid)
+		return err
+	}
+
+	r.operationAudit(kt, info.ID, protoaudit.Disassociate, enumor.CvmAuditResType, cvmID)
+	return nil
+}
+
+func (r *sgRecycler) associateCvm(kt *kit.Kit, info types.CloudResourceBasicInfo, cvmID string) error {
+	req := &hcproto.SecurityGroupAssociateCvmReq{SecurityGroupID: info.ID, CvmID: cvmID}
+
+	var err error
+	switch info.Vendor {
+	case enumor.TCloud:
+		err = r.client.HCService().TCloud.SecurityGroup.AssociateCvm(kt.Ctx, kt.Header(), req)
+	case enumor.Aws:
+		err = r.client.HCService().Aws.SecurityGroup.AssociateCvm(kt.Ctx, kt.Header(), req)
+	case enumor.HuaWei:
+		err = r.client.HCService().HuaWei.SecurityGroup.AssociateCvm(kt.Ctx, kt.Header(), req)
+	default:
+		err = errf.Newf(errf.InvalidParameter, "associate cvm not support vendor: %s", info.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("associate security group to cvm failed, err: %v, sg: %s, cvm: %s, rid: %s", err, info.ID,
+			cvmID, kt.Rid)
+		return err
+	}
+
+	r.operationAudit(kt, info.ID, protoaudit.Associate, enumor.CvmAuditResType, cvmID)
+	return nil
+}
+
+// Restore re-associate security group to the cvms and load balancers which it was bound to before recycle.
+func (r *sgRecycler) Restore(kt *kit.Kit, info types.CloudResourceBasicInfo, detail interface{}) error {
+	sgDetail := new(rr.SecurityGroupRecycleDetail)
+	if err := decodeDetail(detail, sgDetail); err != nil {
+		return err
+	}
+
+	for _, cvmID := range sgDetail.CvmIDs {
+		if err := r.associateCvm(kt, info, cvmID); err != nil {
+			return err
+		}
+	}
+
+	for _, lb := range sgDetail.LoadBalancers {
+		if err := r.associateLb(kt, info.ID, lb); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// associateLb insert security group back to the load balancer at the original priority, the security groups of load
+// balancer are set by replacing, so the current bound security groups need to be kept.
+func (r *sgRecycler) associateLb(kt *kit.Kit, sgID string, bind rr.SGLbBindInfo) error {
+	current, err := r.listLbRels(kt, tools.ExpressionAnd(tools.RuleEqual("res_id", bind.LbID),
+		tools.RuleEqual("res_type", enumor.LoadBalancerCloudResType)))
+	if err != nil {
+		return err
+	}
+
+	sgIDs := make([]string, 0, len(current)+1)
+	inserted := false
+	for _, one := range current {
+		if one.SecurityGroupID == sgID {
+			return nil
+		}
+		if !inserted && one.Priority > bind.Priority {
+			sgIDs = append(sgIDs, sgID)
+			inserted = true
+		}
+		sgIDs = append(sgIDs, one.SecurityGroupID)
+	}
+	if !inserted {
+		sgIDs = append(sgIDs, sgID)
+	}
+
+	if len(sgIDs) > constant.LoadBalancerBindSecurityGroupMaxLimit {
+		return fmt.Errorf("load balancer(%s) bound security groups exceed limit %d, can not restore security group",
+			bind.LbID, constant.LoadBalancerBindSecurityGroupMaxLimit)
+	}
+
+	req := &hclb.TCloudSetLbSecurityGroupReq{LbID: bind.LbID, SecurityGroupIDs: sgIDs}
+	if err = r.client.HCService().TCloud.SecurityGroup.AssociateLb(kt.Ctx, kt.Header(), req); err != nil {
+		logs.Errorf("associate security group to lb failed, err: %v, sg: %s, lb: %s, rid: %s", err, sgID,
+			bind.LbID, kt.Rid)
+		return err
+	}
+
+	r.operationAudit(kt, sgID, protoaudit.Associate, enumor.LoadBalancerAuditResType, bind.LbID)
+	return nil
+}
+
+// operationAudit 关联关系变更审计，审计失败不影响回收流程
+func (r *sgRecycler) operationAudit(kt *kit.Kit, sgID string, action protoaudit.OperationAction,
+	assResType enumor.AuditResourceType, assResID string) {
+
+	info := protoaudit.CloudResourceOperationInfo{
+		ResType:           enumor.SecurityGroupAuditResType,
+		ResID:             sgID,
+		Action:            action,
+		AssociatedResType: assResType,
+		AssociatedResID:   assResID,
+	}
+	if err := r.audit.ResOperationAudit(kt, info); err != nil {
+		logs.Errorf("create security group operation audit failed, err: %v, sg: %s, rid: %s", err, sgID, kt.Rid)
+	}
+}
+
+// DestroyReady ...
+func (r *sgRecycler) DestroyReady(_ *kit.Kit, _ types.CloudResourceBasicInfo) (bool, error) {
+	return true, nil
+}
+
+// Destroy delete the security group by task flow.
+func (r *sgRecycler) Destroy(kt *kit.Kit, info types.CloudResourceBasicInfo) error {
+	task := ts.CustomFlowTask{
+		ActionName: enumor.ActionDeleteSecurityGroup,
+		Params:     actionsg.DeleteSGOption{Vendor: info.Vendor, ID: info.ID},
+	}
+	return runSingleTaskFlow(kt, r.client, enumor.FlowDeleteSecurityGroup, task)
+}
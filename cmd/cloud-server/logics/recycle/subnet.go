@@ -0,0 +1,64 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package logicsrecycle
+
+import (
+	actionsubnet "hcm/cmd/task-server/logics/action/subnet"
+	rr "hcm/pkg/api/core/recycle-record"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+)
+
+// subnetRecycler 子网回收，回收期间子网保持不变，到期后删除
+type subnetRecycler struct {
+	client *client.ClientSet
+}
+
+// ResType ...
+func (r *subnetRecycler) ResType() enumor.CloudResourceType {
+	return enumor.SubnetCloudResType
+}
+
+// Prepare ...
+func (r *subnetRecycler) Prepare(_ *kit.Kit, _ types.CloudResourceBasicInfo) (interface{}, error) {
+	return &rr.SubnetRecycleDetail{}, nil
+}
+
+// Restore ...
+func (r *subnetRecycler) Restore(_ *kit.Kit, _ types.CloudResourceBasicInfo, _ interface{}) error {
+	return nil
+}
+
+// DestroyReady ...
+func (r *subnetRecycler) DestroyReady(_ *kit.Kit, _ types.CloudResourceBasicInfo) (bool, error) {
+	return true, nil
+}
+
+// Destroy delete the subnet by task flow.
+func (r *subnetRecycler) Destroy(kt *kit.Kit, info types.CloudResourceBasicInfo) error {
+	task := ts.CustomFlowTask{
+		ActionName: enumor.ActionDeleteSubnet,
+		Params:     actionsubnet.DeleteSubnetOption{Vendor: info.Vendor, ID: info.ID},
+	}
+	return runSingleTaskFlow(kt, r.client, enumor.FlowDeleteSubnet, task)
+}
@@ -0,0 +1,110 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package logicsrecycle
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	rr "hcm/pkg/api/core/recycle-record"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// vpcRecycler vpc回收，vpc下的子网需要一同回收，子网全部销毁后才会销毁vpc
+type vpcRecycler struct {
+	client *client.ClientSet
+}
+
+// ResType ...
+func (r *vpcRecycler) ResType() enumor.CloudResourceType {
+	return enumor.VpcCloudResType
+}
+
+// Prepare check all the subnets of vpc are recycled.
+func (r *vpcRecycler) Prepare(kt *kit.Kit, info types.CloudResourceBasicInfo) (interface{}, error) {
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("vpc_id", info.ID),
+			tools.RuleNotEqual("recycle_status", enumor.RecycleStatus)),
+		Page: core.NewCountPage(),
+	}
+	result, err := r.client.DataService().Global.Subnet.List(kt.Ctx, kt.Header(), req)
+	if err != nil {
+		logs.Errorf("count not recycled subnet of vpc failed, err: %v, vpc: %s, rid: %s", err, info.ID, kt.Rid)
+		return nil, err
+	}
+
+	if result.Count > 0 {
+		return nil, fmt.Errorf("vpc(%s) has %d subnets not recycled, please recycle them first", info.ID,
+			result.Count)
+	}
+
+	return &rr.VpcRecycleDetail{}, nil
+}
+
+// Restore ...
+func (r *vpcRecycler) Restore(_ *kit.Kit, _ types.CloudResourceBasicInfo, _ interface{}) error {
+	return nil
+}
+
+// DestroyReady vpc can be destroyed only when all the subnets of vpc are destroyed.
+func (r *vpcRecycler) DestroyReady(kt *kit.Kit, info types.CloudResourceBasicInfo) (bool, error) {
+	req := &core.ListReq{
+		Filter: tools.EqualExpression("vpc_id", info.ID),
+		Page:   core.NewCountPage(),
+	}
+	result, err := r.client.DataService().Global.Subnet.List(kt.Ctx, kt.Header(), req)
+	if err != nil {
+		logs.Errorf("count subnet of vpc failed, err: %v, vpc: %s, rid: %s", err, info.ID, kt.Rid)
+		return false, err
+	}
+
+	return result.Count == 0, nil
+}
+
+// Destroy delete the vpc.
+func (r *vpcRecycler) Destroy(kt *kit.Kit, info types.CloudResourceBasicInfo) error {
+	var err error
+	switch info.Vendor {
+	case enumor.TCloud:
+		err = r.client.HCService().TCloud.Vpc.Delete(kt.Ctx, kt.Header(), info.ID)
+	case enumor.Aws:
+		err = r.client.HCService().Aws.Vpc.Delete(kt.Ctx, kt.Header(), info.ID)
+	case enumor.Gcp:
+		err = r.client.HCService().Gcp.Vpc.Delete(kt.Ctx, kt.Header(), info.ID)
+	case enumor.Azure:
+		err = r.client.HCService().Azure.Vpc.Delete(kt.Ctx, kt.Header(), info.ID)
+	case enumor.HuaWei:
+		err = r.client.HCService().HuaWei.Vpc.Delete(kt.Ctx, kt.Header(), info.ID)
+	default:
+		err = errf.Newf(errf.InvalidParameter, "delete vpc not support vendor: %s", info.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("delete vpc failed, err: %v, vpc: %s, rid: %s", err, info.ID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
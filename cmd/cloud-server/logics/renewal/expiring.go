@@ -0,0 +1,406 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package renewal 包年包月资源到期查询、到期通知
+package renewal
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	hcrenewal "hcm/pkg/api/hc-service/renewal"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+)
+
+const (
+	// tcloudPrepaid 腾讯云包年包月计费模式
+	tcloudPrepaid = "PREPAID"
+	// tcloudAutoRenew 腾讯云自动续费标识
+	tcloudAutoRenew = "NOTIFY_AND_AUTO_RENEW"
+	// huaweiCvmPrepaid 华为云云服务器包年包月计费模式
+	huaweiCvmPrepaid = "1"
+	// huaweiDiskPrepaid 华为云云硬盘包年包月计费模式
+	huaweiDiskPrepaid = "prePaid"
+	// huaweiExpireTimeBatch 华为云费用中心单次查询的资源数上限
+	huaweiExpireTimeBatch = 50
+)
+
+// Interface define prepaid resource renewal logics.
+type Interface interface {
+	ListExpiringRes(kt *kit.Kit, opt *ListExpiringOption) ([]cloudserver.ExpiringRes, error)
+}
+
+// ListExpiringOption list expiring prepaid resources option.
+type ListExpiringOption struct {
+	// ResType 为空时查询所有支持续费的资源类型
+	ResType enumor.CloudResourceType
+	// Filter 资源的附加过滤条件，如业务、账号、鉴权条件，仅支持主机和云硬盘的公共字段
+	Filter *filter.Expression
+	// BwpAccountIDs 查询这些华为云账号下的包年包月共享带宽，带宽包未纳入hcm资源管理，不属于任何业务，为空时不查询带宽包
+	BwpAccountIDs []string
+	AdvanceDays   uint
+	Now           time.Time
+}
+
+// NewRenewal new prepaid resource renewal logics.
+func NewRenewal(client *client.ClientSet) Interface {
+	return &renewal{client: client}
+}
+
+type renewal struct {
+	client *client.ClientSet
+}
+
+// ListExpiringRes 查询指定天数内到期的包年包月资源，已过期但尚未释放的资源也会返回，按剩余天数升序排列
+func (r *renewal) ListExpiringRes(kt *kit.Kit, opt *ListExpiringOption) ([]cloudserver.ExpiringRes, error) {
+	listFuncs := make([]func(kt *kit.Kit, expr *filter.Expression) ([]cloudserver.ExpiringRes, error), 0)
+	if len(opt.ResType) == 0 || opt.ResType == enumor.CvmCloudResType {
+		listFuncs = append(listFuncs, r.listTCloudPrepaidCvm, r.listHuaWeiPrepaidCvm)
+	}
+	if len(opt.ResType) == 0 || opt.ResType == enumor.DiskCloudResType {
+		listFuncs = append(listFuncs, r.listTCloudPrepaidDisk, r.listHuaWeiPrepaidDisk)
+	}
+
+	all := make([]cloudserver.ExpiringRes, 0)
+	for _, list := range listFuncs {
+		resList, err := list(kt, opt.Filter)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, resList...)
+	}
+
+	if len(opt.ResType) == 0 || opt.ResType == enumor.BandwidthPackageCloudResType {
+		deadline := opt.Now.Add(time.Duration(opt.AdvanceDays) * times.Day)
+		bwpList, err := r.listHuaWeiPrepaidBwp(kt, opt.BwpAccountIDs, deadline)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, bwpList...)
+	}
+
+	return filterExpiring(kt, all, opt.Now, opt.AdvanceDays), nil
+}
+
+// filterExpiring 计算资源距离到期的剩余天数，并过滤出指定天数内到期的资源
+func filterExpiring(kt *kit.Kit, all []cloudserver.ExpiringRes, now time.Time,
+	advanceDays uint) []cloudserver.ExpiringRes {
+
+	deadline := now.Add(time.Duration(advanceDays) * times.Day)
+	result := make([]cloudserver.ExpiringRes, 0)
+	for _, one := range all {
+		if len(one.ExpireTime) == 0 {
+			continue
+		}
+
+		expireTime, err := parseExpireTime(one.ExpireTime)
+		if err != nil {
+			logs.Errorf("parse %s %s expire time %s failed, err: %v, rid: %s", one.ResType, one.ID, one.ExpireTime,
+				err, kt.Rid)
+			continue
+		}
+
+		if expireTime.After(deadline) {
+			continue
+		}
+
+		one.RemainDays = int(math.Floor(expireTime.Sub(now).Hours() / 24))
+		result = append(result, one)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].RemainDays < result[j].RemainDays
+	})
+
+	return result
+}
+
+// expireTimeLayouts 各云厂商返回的到期时间格式，不带时区的时间按本地时区解析
+var expireTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05Z0700", "2006-01-02 15:04:05"}
+
+func parseExpireTime(value string) (time.Time, error) {
+	for _, layout := range expireTimeLayouts {
+		t, err := time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, errors.New("unsupported time format")
+}
+
+// withFilter 合并资源的查询条件和附加过滤条件
+func withFilter(expr *filter.Expression, rules ...filter.RuleFactory) (*filter.Expression, error) {
+	if expr != nil && !expr.IsEmpty() {
+		rules = append(rules, expr)
+	}
+	return tools.And(rules...)
+}
+
+func (r *renewal) listTCloudPrepaidCvm(kt *kit.Kit, expr *filter.Expression) ([]cloudserver.ExpiringRes, error) {
+	cvms, err := listAll(func(page *core.BasePage) ([]corecvm.Cvm[corecvm.TCloudCvmExtension], error) {
+		listFilter, err := withFilter(expr, tools.RuleEqual("vendor", enumor.TCloud),
+			tools.RuleJSONEqual("extension.instance_charge_type", tcloudPrepaid))
+		if err != nil {
+			return nil, err
+		}
+		req := &protocloud.CvmListReq{Filter: listFilter, Page: page}
+		result, err := r.client.DataService().TCloud.Cvm.ListCvmExt(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return nil, err
+		}
+		return result.Details, nil
+	})
+	if err != nil {
+		logs.Errorf("list tcloud prepaid cvm failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	result := make([]cloudserver.ExpiringRes, 0, len(cvms))
+	for _, one := range cvms {
+		res := cvmExpiringRes(one.BaseCvm)
+		res.ExpireTime = one.CloudExpiredTime
+		if one.Extension != nil {
+			res.AutoRenew = converter.PtrToVal(one.Extension.RenewFlag) == tcloudAutoRenew
+		}
+		result = append(result, res)
+	}
+
+	return result, nil
+}
+
+// listHuaWeiPrepaidCvm 华为云云服务器未同步到期时间，需要通过费用中心查询
+func (r *renewal) listHuaWeiPrepaidCvm(kt *kit.Kit, expr *filter.Expression) ([]cloudserver.ExpiringRes, error) {
+	cvms, err := listAll(func(page *core.BasePage) ([]corecvm.Cvm[corecvm.HuaWeiCvmExtension], error) {
+		listFilter, err := withFilter(expr, tools.RuleEqual("vendor", enumor.HuaWei),
+			tools.RuleJSONEqual("extension.metadata.charging_mode", huaweiCvmPrepaid))
+		if err != nil {
+			return nil, err
+		}
+		req := &protocloud.CvmListReq{Filter: listFilter, Page: page}
+		result, err := r.client.DataService().HuaWei.Cvm.ListCvmExt(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return nil, err
+		}
+		return result.Details, nil
+	})
+	if err != nil {
+		logs.Errorf("list huawei prepaid cvm failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	accountCvms := make(map[string][]cloudserver.ExpiringRes)
+	for _, one := range cvms {
+		accountCvms[one.AccountID] = append(accountCvms[one.AccountID], cvmExpiringRes(one.BaseCvm))
+	}
+
+	result := make([]cloudserver.ExpiringRes, 0, len(cvms))
+	for accountID, resList := range accountCvms {
+		for _, batch := range slice.Split(resList, huaweiExpireTimeBatch) {
+			cloudIDs := slice.Map(batch, func(one cloudserver.ExpiringRes) string { return one.CloudID })
+			req := &hcrenewal.ExpireTimeListReq{AccountID: accountID, CloudIDs: cloudIDs}
+			expireTimes, err := r.client.HCService().HuaWei.Renewal.ListExpireTime(kt, req)
+			if err != nil {
+				logs.Errorf("list huawei cvm expire time failed, err: %v, account: %s, rid: %s", err, accountID,
+					kt.Rid)
+				return nil, err
+			}
+
+			expireMap := make(map[string]int, len(expireTimes.Details))
+			for idx, one := range expireTimes.Details {
+				expireMap[one.CloudID] = idx
+			}
+
+			for _, res := range batch {
+				idx, exists := expireMap[res.CloudID]
+				if !exists {
+					continue
+				}
+				res.ExpireTime = expireTimes.Details[idx].ExpireTime
+				res.AutoRenew = expireTimes.Details[idx].AutoRenew
+				result = append(result, res)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (r *renewal) listTCloudPrepaidDisk(kt *kit.Kit, expr *filter.Expression) ([]cloudserver.ExpiringRes, error) {
+	disks, err := listAll(func(page *core.BasePage) ([]*coredisk.Disk[coredisk.TCloudExtension], error) {
+		listFilter, err := withFilter(expr, tools.RuleEqual("vendor", enumor.TCloud),
+			tools.RuleJSONEqual("extension.disk_charge_type", tcloudPrepaid))
+		if err != nil {
+			return nil, err
+		}
+		req := &core.ListReq{Filter: listFilter, Page: page}
+		result, err := r.client.DataService().TCloud.ListDisk(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return nil, err
+		}
+		return result.Details, nil
+	})
+	if err != nil {
+		logs.Errorf("list tcloud prepaid disk failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	result := make([]cloudserver.ExpiringRes, 0, len(disks))
+	for _, one := range disks {
+		res := diskExpiringRes(one.BaseDisk)
+		if one.Extension != nil {
+			res.ExpireTime = converter.PtrToVal(one.Extension.DeadlineTime)
+			if one.Extension.DiskChargePrepaid != nil {
+				res.AutoRenew = converter.PtrToVal(one.Extension.DiskChargePrepaid.RenewFlag) == tcloudAutoRenew
+			}
+		}
+		result = append(result, res)
+	}
+
+	return result, nil
+}
+
+func (r *renewal) listHuaWeiPrepaidDisk(kt *kit.Kit, expr *filter.Expression) ([]cloudserver.ExpiringRes, error) {
+	disks, err := listAll(func(page *core.BasePage) ([]*coredisk.Disk[coredisk.HuaWeiExtension], error) {
+		listFilter, err := withFilter(expr, tools.RuleEqual("vendor", enumor.HuaWei),
+			tools.RuleJSONEqual("extension.charge_type", huaweiDiskPrepaid))
+		if err != nil {
+			return nil, err
+		}
+		req := &core.ListReq{Filter: listFilter, Page: page}
+		result, err := r.client.DataService().HuaWei.ListDisk(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return nil, err
+		}
+		return result.Details, nil
+	})
+	if err != nil {
+		logs.Errorf("list huawei prepaid disk failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	result := make([]cloudserver.ExpiringRes, 0, len(disks))
+	for _, one := range disks {
+		res := diskExpiringRes(one.BaseDisk)
+		if one.Extension != nil {
+			res.ExpireTime = one.Extension.ExpireTime
+			if one.Extension.ChargePrepaid != nil {
+				res.AutoRenew = converter.PtrToVal(one.Extension.ChargePrepaid.IsAutoRenew) == "true"
+			}
+		}
+		result = append(result, res)
+	}
+
+	return result, nil
+}
+
+// listHuaWeiPrepaidBwp 共享带宽未纳入hcm资源管理，通过费用中心查询到期时间在deadline之前的共享带宽，资源ID为云上ID
+func (r *renewal) listHuaWeiPrepaidBwp(kt *kit.Kit, accountIDs []string, deadline time.Time) (
+	[]cloudserver.ExpiringRes, error) {
+
+	result := make([]cloudserver.ExpiringRes, 0)
+	for _, accountID := range accountIDs {
+		req := &hcrenewal.BandwidthPackageListReq{AccountID: accountID, ExpireTimeEnd: &deadline}
+		bwpList, err := r.client.HCService().HuaWei.Renewal.ListBandwidthPackage(kt, req)
+		if err != nil {
+			logs.Errorf("list huawei prepaid bandwidth package failed, err: %v, account: %s, rid: %s", err,
+				accountID, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range bwpList.Details {
+			result = append(result, cloudserver.ExpiringRes{
+				ResType:    enumor.BandwidthPackageCloudResType,
+				ID:         one.CloudID,
+				CloudID:    one.CloudID,
+				Name:       one.Name,
+				Vendor:     enumor.HuaWei,
+				AccountID:  accountID,
+				Region:     one.Region,
+				BkBizID:    constant.UnassignedBiz,
+				ExpireTime: one.ExpireTime,
+				AutoRenew:  one.AutoRenew,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func cvmExpiringRes(one corecvm.BaseCvm) cloudserver.ExpiringRes {
+	return cloudserver.ExpiringRes{
+		ResType:   enumor.CvmCloudResType,
+		ID:        one.ID,
+		CloudID:   one.CloudID,
+		Name:      one.Name,
+		Vendor:    one.Vendor,
+		AccountID: one.AccountID,
+		Region:    one.Region,
+		BkBizID:   one.BkBizID,
+	}
+}
+
+func diskExpiringRes(one coredisk.BaseDisk) cloudserver.ExpiringRes {
+	return cloudserver.ExpiringRes{
+		ResType:   enumor.DiskCloudResType,
+		ID:        one.ID,
+		CloudID:   one.CloudID,
+		Name:      one.Name,
+		Vendor:    enumor.Vendor(one.Vendor),
+		AccountID: one.AccountID,
+		Region:    one.Region,
+		BkBizID:   one.BkBizID,
+	}
+}
+
+func listAll[T any](list func(page *core.BasePage) ([]T, error)) ([]T, error) {
+	page := core.NewDefaultBasePage()
+	result := make([]T, 0)
+	for {
+		details, err := list(page)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, details...)
+		if uint(len(details)) < page.Limit {
+			break
+		}
+
+		page.Start += uint32(page.Limit)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,95 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package renewal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+func TestParseExpireTime(t *testing.T) {
+	cases := map[string]time.Time{
+		"2024-11-20T08:00:00Z":      time.Date(2024, 11, 20, 8, 0, 0, 0, time.UTC),
+		"2024-11-20T16:00:00+08:00": time.Date(2024, 11, 20, 8, 0, 0, 0, time.UTC),
+		"2024-11-20 16:00:00":       time.Date(2024, 11, 20, 16, 0, 0, 0, time.Local),
+	}
+	for value, expect := range cases {
+		got, err := parseExpireTime(value)
+		if err != nil {
+			t.Fatalf("parse %s failed, err: %v", value, err)
+		}
+		if !got.Equal(expect) {
+			t.Errorf("parse %s expect %v, got: %v", value, expect, got)
+		}
+	}
+
+	if _, err := parseExpireTime("20241120"); err == nil {
+		t.Errorf("parse invalid expire time should be failed")
+	}
+}
+
+func TestFilterExpiring(t *testing.T) {
+	now := time.Date(2024, 11, 20, 0, 0, 0, 0, time.UTC)
+	all := []cloudserver.ExpiringRes{
+		{ID: "far", ExpireTime: "2024-12-20T00:00:00Z"},
+		{ID: "soon", ExpireTime: "2024-11-23T12:00:00Z"},
+		{ID: "expired", ExpireTime: "2024-11-19T12:00:00Z"},
+		{ID: "empty"},
+		{ID: "invalid", ExpireTime: "invalid"},
+	}
+
+	result := filterExpiring(kit.New(), all, now, 7)
+	if len(result) != 2 {
+		t.Fatalf("expect 2 expiring resources, got: %+v", result)
+	}
+	if result[0].ID != "expired" || result[0].RemainDays != -1 {
+		t.Errorf("expect expired resource first with remain days -1, got: %+v", result[0])
+	}
+	if result[1].ID != "soon" || result[1].RemainDays != 3 {
+		t.Errorf("expect soon resource with remain days 3, got: %+v", result[1])
+	}
+}
+
+func TestGroupByBizAndRenderMail(t *testing.T) {
+	resList := []cloudserver.ExpiringRes{
+		{ID: "1", BkBizID: 100, ResType: enumor.CvmCloudResType, Name: "<cvm>", RemainDays: 5},
+		{ID: "2", BkBizID: 100, ResType: enumor.DiskCloudResType, RemainDays: 1, AutoRenew: true},
+		{ID: "3", BkBizID: -1, ResType: enumor.CvmCloudResType},
+		{ID: "4", BkBizID: 0, ResType: enumor.CvmCloudResType},
+	}
+
+	bizRes := groupByBiz(resList)
+	if len(bizRes[100]) != 2 || len(bizRes[unassignedBizID]) != 2 {
+		t.Fatalf("group by biz mismatch, got: %+v", bizRes)
+	}
+
+	content := renderExpiryMail("biz", 7, bizRes[100])
+	if strings.Contains(content, "<cvm>") || !strings.Contains(content, "&lt;cvm&gt;") {
+		t.Errorf("resource name should be escaped, got: %s", content)
+	}
+	if strings.Index(content, "<td>disk</td>") > strings.Index(content, "<td>cvm</td>") {
+		t.Errorf("resources should be sorted by remain days, got: %s", content)
+	}
+}
@@ -0,0 +1,206 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package renewal
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/serviced"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+	"hcm/pkg/thirdparty/esb"
+	"hcm/pkg/thirdparty/esb/cmdb"
+	"hcm/pkg/tools/maps"
+	"hcm/pkg/tools/slice"
+)
+
+// TimingNoticeExpiringRes 定期查询即将到期的包年包月资源，按业务通过邮件通知业务运维人员及配置的额外接收人
+func TimingNoticeExpiringRes(cliSet *client.ClientSet, state serviced.State, esbClient esb.Client,
+	cmsiCli cmsi.Client, conf cc.ExpiryWatch) {
+
+	notifier := &expiryNotifier{
+		renewal:   NewRenewal(cliSet),
+		esbClient: esbClient,
+		cmsiCli:   cmsiCli,
+		conf:      conf,
+	}
+
+	for {
+		time.Sleep(time.Duration(conf.IntervalHour) * time.Hour)
+
+		if !state.IsMaster() {
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		if err := notifier.notice(kt); err != nil {
+			logs.Errorf("notice expiring prepaid resources failed, err: %v, rid: %s", err, kt.Rid)
+		}
+	}
+}
+
+type expiryNotifier struct {
+	renewal   Interface
+	esbClient esb.Client
+	cmsiCli   cmsi.Client
+	conf      cc.ExpiryWatch
+}
+
+func (n *expiryNotifier) notice(kt *kit.Kit) error {
+	opt := &ListExpiringOption{AdvanceDays: n.conf.AdvanceDays, Now: time.Now()}
+	expiring, err := n.renewal.ListExpiringRes(kt, opt)
+	if err != nil {
+		return err
+	}
+
+	bizRes := groupByBiz(expiring)
+	if len(bizRes) == 0 {
+		return nil
+	}
+
+	bizIDs := slice.Filter(maps.Keys(bizRes), func(bizID int64) bool { return bizID > 0 })
+	bizs, err := n.getBizs(kt, bizIDs)
+	if err != nil {
+		return err
+	}
+
+	for bizID, resList := range bizRes {
+		biz, exists := bizs[bizID]
+		if !exists {
+			biz = cmdb.Biz{BizID: bizID, BizName: "未分配"}
+		}
+
+		receivers := make([]string, 0)
+		if len(biz.BizMaintainer) != 0 {
+			receivers = append(receivers, strings.Split(biz.BizMaintainer, ",")...)
+		}
+		receivers = slice.Unique(append(receivers, n.conf.Receivers...))
+		if len(receivers) == 0 {
+			logs.Infof("biz %d has no receivers of expiring resources notice, skip, rid: %s", bizID, kt.Rid)
+			continue
+		}
+
+		mail := &cmsi.CmsiMail{
+			Receiver: strings.Join(receivers, ","),
+			Title:    fmt.Sprintf(expiryMailTitle, biz.BizName, len(resList), n.conf.AdvanceDays),
+			Content:  renderExpiryMail(biz.BizName, n.conf.AdvanceDays, resList),
+		}
+		if err = n.cmsiCli.SendMail(kt, mail); err != nil {
+			logs.Errorf("send biz %d expiring resources notice mail failed, err: %v, rid: %s", bizID, err, kt.Rid)
+		}
+	}
+
+	return nil
+}
+
+// unassignedBizID 未分配业务的资源的业务ID
+const unassignedBizID int64 = -1
+
+// groupByBiz 按业务聚合资源，未分配业务的资源聚合在一起
+func groupByBiz(resList []cloudserver.ExpiringRes) map[int64][]cloudserver.ExpiringRes {
+	result := make(map[int64][]cloudserver.ExpiringRes)
+	for _, one := range resList {
+		bizID := one.BkBizID
+		if bizID <= 0 {
+			bizID = unassignedBizID
+		}
+		result[bizID] = append(result[bizID], one)
+	}
+	return result
+}
+
+// getBizs get biz info from cmdb, returns map[bizID]biz.
+func (n *expiryNotifier) getBizs(kt *kit.Kit, bizIDs []int64) (map[int64]cmdb.Biz, error) {
+	bizs := make(map[int64]cmdb.Biz, len(bizIDs))
+	if len(bizIDs) == 0 {
+		return bizs, nil
+	}
+
+	params := &cmdb.SearchBizParams{
+		Fields: []string{"bk_biz_id", "bk_biz_name", "bk_biz_maintainer"},
+		Page:   cmdb.BasePage{Limit: int64(len(bizIDs))},
+		BizPropertyFilter: &cmdb.QueryFilter{
+			Rule: &cmdb.CombinedRule{
+				Condition: "AND",
+				Rules: []cmdb.Rule{
+					&cmdb.AtomRule{Field: cmdb.BizIDField, Operator: cmdb.OperatorIn, Value: bizIDs},
+				},
+			},
+		},
+	}
+	result, err := n.esbClient.Cmdb().SearchBusiness(kt, params)
+	if err != nil {
+		logs.Errorf("search cmdb business failed, err: %v, biz ids: %v, rid: %s", err, bizIDs, kt.Rid)
+		return nil, err
+	}
+
+	for _, biz := range result.Info {
+		bizs[biz.BizID] = biz
+	}
+
+	return bizs, nil
+}
+
+const (
+	expiryMailTitle    = "HCM业务[%s]有%d个包年包月资源将在%d天内到期"
+	expiryMailTemplate = `<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8" />
+    <title>HCM包年包月资源到期提醒</title>
+  </head>
+  <body>
+    <p>尊敬的用户您好！业务[%s]以下包年包月资源将在%d天内到期，请及时续费或开启自动续费，避免资源到期后被停服或释放：</p>
+    <table border="1" cellspacing="0" cellpadding="4">
+      <tr><th>资源类型</th><th>云厂商</th><th>账号ID</th><th>地域</th><th>资源</th><th>到期时间</th><th>剩余天数</th><th>自动续费</th></tr>
+%s
+    </table>
+  </body>
+</html>`
+)
+
+func renderExpiryMail(bizName string, advanceDays uint, resList []cloudserver.ExpiringRes) string {
+	sort.SliceStable(resList, func(i, j int) bool {
+		return resList[i].RemainDays < resList[j].RemainDays
+	})
+
+	rows := make([]string, 0, len(resList))
+	for _, one := range resList {
+		autoRenew := "否"
+		if one.AutoRenew {
+			autoRenew = "是"
+		}
+		rows = append(rows, fmt.Sprintf("      <tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s(%s)</td>"+
+			"<td>%s</td><td>%d</td><td>%s</td></tr>", one.ResType, one.Vendor, one.AccountID,
+			html.EscapeString(one.Region), html.EscapeString(one.Name), html.EscapeString(one.CloudID),
+			html.EscapeString(one.ExpireTime), one.RemainDays, autoRenew))
+	}
+
+	return fmt.Sprintf(expiryMailTemplate, html.EscapeString(bizName), advanceDays, strings.Join(rows, "\n"))
+}
@@ -0,0 +1,159 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package rescost 从账单明细中汇总资源成本
+package rescost
+
+import (
+	"encoding/json"
+	"strings"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/bill"
+	databill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"github.com/shopspring/decimal"
+)
+
+// ResCost 资源在账单月份内的成本
+type ResCost struct {
+	Cost     decimal.Decimal     `json:"cost"`
+	Currency enumor.CurrencyCode `json:"currency"`
+}
+
+// resIDParsers 各云厂商从账单明细扩展信息中解析资源云上ID的方法，腾讯云、微软云的账单明细未保存扩展信息，无法关联到资源
+var resIDParsers = map[enumor.Vendor]func(extension []byte) (string, error){
+	enumor.Aws:    parseAwsResID,
+	enumor.HuaWei: parseHuaWeiResID,
+	enumor.Gcp:    parseGcpResID,
+}
+
+// SupportVendor 是否支持通过账单明细汇总该云厂商资源的成本
+func SupportVendor(vendor enumor.Vendor) bool {
+	_, exists := resIDParsers[vendor]
+	return exists
+}
+
+// ListMonthlyResCost 汇总业务在账单月份内各资源的成本，返回云厂商到资源云上ID及其成本的映射，不支持的云厂商会被忽略
+func ListMonthlyResCost(kt *kit.Kit, cli *client.ClientSet, bizID int64, vendors []enumor.Vendor, year,
+	month int) (map[enumor.Vendor]map[string]ResCost, error) {
+
+	result := make(map[enumor.Vendor]map[string]ResCost, len(vendors))
+	for _, vendor := range vendors {
+		if !SupportVendor(vendor) {
+			continue
+		}
+
+		costMap := make(map[string]ResCost)
+		listReq := &databill.BillItemListReq{
+			ItemCommonOpt: &databill.ItemCommonOpt{Vendor: vendor, Year: year, Month: month},
+			ListReq: &core.ListReq{
+				Filter: tools.EqualExpression("bk_biz_id", bizID),
+				Page:   core.NewDefaultBasePage(),
+			},
+		}
+		for {
+			items, err := cli.DataService().Global.Bill.ListBillItemRaw(kt, listReq)
+			if err != nil {
+				logs.Errorf("list %s bill item raw failed, err: %v, biz: %d, month: %d-%d, rid: %s", vendor, err,
+					bizID, year, month, kt.Rid)
+				return nil, err
+			}
+
+			sumResCost(kt, vendor, items.Details, costMap)
+
+			if len(items.Details) < int(listReq.Page.Limit) {
+				break
+			}
+			listReq.Page.Start += uint32(listReq.Page.Limit)
+		}
+		result[vendor] = costMap
+	}
+
+	return result, nil
+}
+
+// sumResCost 按资源云上ID累加账单明细的成本，无法解析出资源ID的明细会被忽略
+func sumResCost(kt *kit.Kit, vendor enumor.Vendor, items []*bill.BillItemRaw, costMap map[string]ResCost) {
+	parser := resIDParsers[vendor]
+	for _, item := range items {
+		if item == nil || item.BaseBillItem == nil || len(item.Extension) == 0 {
+			continue
+		}
+
+		resID, err := parser([]byte(item.Extension))
+		if err != nil {
+			logs.Warnf("parse %s bill item %s resource id failed, err: %v, rid: %s", vendor, item.ID, err, kt.Rid)
+			continue
+		}
+		if len(resID) == 0 {
+			continue
+		}
+
+		resCost, exists := costMap[resID]
+		if !exists {
+			resCost = ResCost{Cost: decimal.Zero, Currency: item.Currency}
+		}
+		resCost.Cost = resCost.Cost.Add(item.Cost)
+		costMap[resID] = resCost
+	}
+}
+
+func parseAwsResID(extension []byte) (string, error) {
+	ext := struct {
+		ResourceID string `json:"line_item_resource_id"`
+	}{}
+	if err := json.Unmarshal(extension, &ext); err != nil {
+		return "", err
+	}
+
+	return ext.ResourceID, nil
+}
+
+func parseHuaWeiResID(extension []byte) (string, error) {
+	ext := struct {
+		ResourceID string `json:"resource_id"`
+	}{}
+	if err := json.Unmarshal(extension, &ext); err != nil {
+		return "", err
+	}
+
+	return ext.ResourceID, nil
+}
+
+// parseGcpResID 谷歌云资源全局名称形如 //compute.googleapis.com/projects/{project}/zones/{zone}/instances/{id}，取最后一段
+func parseGcpResID(extension []byte) (string, error) {
+	ext := struct {
+		ResourceGlobalName string `json:"resource_global_name"`
+	}{}
+	if err := json.Unmarshal(extension, &ext); err != nil {
+		return "", err
+	}
+
+	if len(ext.ResourceGlobalName) == 0 {
+		return "", nil
+	}
+
+	return ext.ResourceGlobalName[strings.LastIndex(ext.ResourceGlobalName, "/")+1:], nil
+}
@@ -0,0 +1,90 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package rescost
+
+import (
+	"encoding/json"
+	"testing"
+
+	"hcm/pkg/api/core/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestParseResID(t *testing.T) {
+	cases := []struct {
+		vendor    enumor.Vendor
+		extension string
+		expect    string
+	}{
+		{vendor: enumor.Aws, extension: `{"line_item_resource_id": "i-0abc"}`, expect: "i-0abc"},
+		{vendor: enumor.HuaWei, extension: `{"resource_id": "8f1c-22d0"}`, expect: "8f1c-22d0"},
+		{vendor: enumor.Gcp, extension: `{"resource_global_name": "//compute.googleapis.com/projects/p/zones/z/` +
+			`instances/123456"}`, expect: "123456"},
+		{vendor: enumor.Gcp, extension: `{}`, expect: ""},
+	}
+
+	for _, c := range cases {
+		got, err := resIDParsers[c.vendor]([]byte(c.extension))
+		if err != nil {
+			t.Fatalf("parse %s resource id failed, err: %v", c.vendor, err)
+		}
+		if got != c.expect {
+			t.Errorf("parse %s resource id expect %s, got: %s", c.vendor, c.expect, got)
+		}
+	}
+
+	if SupportVendor(enumor.TCloud) {
+		t.Errorf("tcloud bill item has no extension, should not be supported")
+	}
+}
+
+func TestSumResCost(t *testing.T) {
+	newItem := func(cost string, extension string) *bill.BillItemRaw {
+		return &bill.BillItemRaw{
+			BaseBillItem: &bill.BaseBillItem{Currency: enumor.CurrencyUSD, Cost: decimal.RequireFromString(cost)},
+			Extension:    json.RawMessage(extension),
+		}
+	}
+
+	items := []*bill.BillItemRaw{
+		newItem("1.25", `{"line_item_resource_id": "i-1"}`),
+		newItem("2.5", `{"line_item_resource_id": "i-1"}`),
+		newItem("3", `{"line_item_resource_id": "i-2"}`),
+		newItem("4", `{"line_item_resource_id": ""}`),
+		newItem("5", `invalid`),
+		newItem("6", ``),
+	}
+
+	costMap := make(map[string]ResCost)
+	sumResCost(kit.New(), enumor.Aws, items, costMap)
+
+	if len(costMap) != 2 {
+		t.Fatalf("expect 2 resources, got: %+v", costMap)
+	}
+	if !costMap["i-1"].Cost.Equal(decimal.RequireFromString("3.75")) || costMap["i-1"].Currency != enumor.CurrencyUSD {
+		t.Errorf("unexpected i-1 cost: %+v", costMap["i-1"])
+	}
+	if !costMap["i-2"].Cost.Equal(decimal.NewFromInt(3)) {
+		t.Errorf("unexpected i-2 cost: %+v", costMap["i-2"])
+	}
+}
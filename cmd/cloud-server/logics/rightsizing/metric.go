@@ -0,0 +1,80 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package rightsizing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// MetricSource 主机利用率指标源，按主机云上ID返回利用率，指标源中没有的主机不返回
+type MetricSource interface {
+	ListCvmUtilization(kt *kit.Kit, cloudIDs []string) (map[string]cloudserver.CvmUtilization, error)
+}
+
+// NewMetricSource new metric source by config.
+func NewMetricSource(opt cc.RightsizingMetricSource) (MetricSource, error) {
+	switch opt.Type {
+	case cc.StaticFileMetricSource:
+		return &staticFileSource{path: opt.Path}, nil
+	case "":
+		return nil, errf.New(errf.Aborted, "rightsizing metric source is not configured")
+	default:
+		return nil, fmt.Errorf("rightsizing metric source type %s is not supported", opt.Type)
+	}
+}
+
+// staticFileSource 从JSON文件读取主机利用率，文件内容为主机云上ID到利用率的映射，由外部监控系统定期导出
+type staticFileSource struct {
+	path string
+}
+
+// ListCvmUtilization 每次调用时重新读取文件，以便外部更新文件后无需重启服务
+func (s *staticFileSource) ListCvmUtilization(kt *kit.Kit, cloudIDs []string) (
+	map[string]cloudserver.CvmUtilization, error) {
+
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		logs.Errorf("read rightsizing metric file %s failed, err: %v, rid: %s", s.path, err, kt.Rid)
+		return nil, err
+	}
+
+	all := make(map[string]cloudserver.CvmUtilization)
+	if err = json.Unmarshal(content, &all); err != nil {
+		logs.Errorf("unmarshal rightsizing metric file %s failed, err: %v, rid: %s", s.path, err, kt.Rid)
+		return nil, err
+	}
+
+	result := make(map[string]cloudserver.CvmUtilization, len(cloudIDs))
+	for _, cloudID := range cloudIDs {
+		if util, exists := all[cloudID]; exists {
+			result[cloudID] = util
+		}
+	}
+
+	return result, nil
+}
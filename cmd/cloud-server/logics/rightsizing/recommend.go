@@ -0,0 +1,116 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package rightsizing
+
+import (
+	"fmt"
+	"math"
+
+	cvmlgc "hcm/cmd/cloud-server/logics/cvm"
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/cc"
+)
+
+// memPerCPUUnit 估算机型成本时，按每1核CPU与4GB内存价格相当将规格折算为成本单位
+const memPerCPUUnit = 4 * 1024
+
+// advice 规格优化建议，savingRatio 为预计可节省的成本比例
+type advice struct {
+	action      cloudserver.RightsizingAction
+	reason      string
+	current     *cvmlgc.InstanceTypeSpec
+	target      *cvmlgc.InstanceTypeSpec
+	savingRatio float64
+}
+
+// costUnits 将机型规格折算为成本单位，用于比较不同机型的价格高低和估算节省比例
+func costUnits(spec *cvmlgc.InstanceTypeSpec) float64 {
+	return float64(spec.CPU) + float64(spec.Memory)/memPerCPUUnit
+}
+
+// recommend 根据利用率给出规格优化建议，无需调整或无法评估时返回nil。
+// 1. CPU、内存使用率P95均低于空闲阈值时建议关机，节省全部成本；
+// 2. 按目标使用率计算所需的CPU、内存，在满足需求且成本单位更低的机型中选择成本最低的，同机型族优先，
+// 其他机型族的机型成本更低时（如CPU空闲而内存紧张）建议更换机型族。
+func recommend(machineType string, specs []cvmlgc.InstanceTypeSpec, util cloudserver.CvmUtilization,
+	conf cc.Rightsizing) *advice {
+
+	var current *cvmlgc.InstanceTypeSpec
+	for i := range specs {
+		if specs[i].InstanceType == machineType {
+			current = &specs[i]
+			break
+		}
+	}
+
+	if util.CpuP95 < conf.IdleCpuPercent && util.MemP95 < conf.IdleMemPercent {
+		return &advice{
+			action:      cloudserver.RightsizingShutdown,
+			reason:      fmt.Sprintf("CPU使用率P95为%.1f%%，内存使用率P95为%.1f%%，主机处于空闲状态", util.CpuP95, util.MemP95),
+			current:     current,
+			savingRatio: 1,
+		}
+	}
+
+	if current == nil || current.CPU <= 0 {
+		return nil
+	}
+
+	needCPU := math.Ceil(float64(current.CPU) * util.CpuP95 / conf.TargetPercent)
+	needMem := float64(current.Memory) * util.MemP95 / conf.TargetPercent
+	currentUnits := costUnits(current)
+
+	var sameFamily, otherFamily *cvmlgc.InstanceTypeSpec
+	for i := range specs {
+		one := &specs[i]
+		if one.InstanceType == current.InstanceType || float64(one.CPU) < needCPU || float64(one.Memory) < needMem ||
+			costUnits(one) >= currentUnits {
+			continue
+		}
+
+		if one.InstanceFamily == current.InstanceFamily {
+			if sameFamily == nil || costUnits(one) < costUnits(sameFamily) {
+				sameFamily = one
+			}
+			continue
+		}
+
+		if otherFamily == nil || costUnits(one) < costUnits(otherFamily) {
+			otherFamily = one
+		}
+	}
+
+	adv := &advice{current: current}
+	switch {
+	case sameFamily != nil && (otherFamily == nil || costUnits(sameFamily) <= costUnits(otherFamily)):
+		adv.action = cloudserver.RightsizingDownsize
+		adv.target = sameFamily
+	case otherFamily != nil:
+		adv.action = cloudserver.RightsizingChangeFamily
+		adv.target = otherFamily
+	default:
+		return nil
+	}
+
+	adv.reason = fmt.Sprintf("CPU使用率P95为%.1f%%，内存使用率P95为%.1f%%，调整为%s（%d核%dMB）后预计使用率不超过%.0f%%",
+		util.CpuP95, util.MemP95, adv.target.InstanceType, adv.target.CPU, adv.target.Memory, conf.TargetPercent)
+	adv.savingRatio = 1 - costUnits(adv.target)/currentUnits
+	return adv
+}
@@ -0,0 +1,158 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package rightsizing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cvmlgc "hcm/cmd/cloud-server/logics/cvm"
+	rescost "hcm/cmd/cloud-server/logics/res-cost"
+	cloudserver "hcm/pkg/api/cloud-server"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+
+	"github.com/shopspring/decimal"
+)
+
+var testConf = cc.Rightsizing{IdleCpuPercent: 5, IdleMemPercent: 10, TargetPercent: 70}
+
+var testSpecs = []cvmlgc.InstanceTypeSpec{
+	{InstanceType: "S5.LARGE8", InstanceFamily: "S5", CPU: 4, Memory: 8192},
+	{InstanceType: "S5.MEDIUM4", InstanceFamily: "S5", CPU: 2, Memory: 4096},
+	{InstanceType: "S5.SMALL2", InstanceFamily: "S5", CPU: 1, Memory: 2048},
+	{InstanceType: "M5.MEDIUM16", InstanceFamily: "M5", CPU: 2, Memory: 16384},
+	{InstanceType: "M5.LARGE32", InstanceFamily: "M5", CPU: 4, Memory: 32768},
+	{InstanceType: "S5.2XLARGE32", InstanceFamily: "S5", CPU: 8, Memory: 32768},
+}
+
+func TestRecommend(t *testing.T) {
+	cases := []struct {
+		name        string
+		machineType string
+		util        cloudserver.CvmUtilization
+		action      cloudserver.RightsizingAction
+		target      string
+		savingRatio float64
+	}{
+		{name: "idle", machineType: "S5.LARGE8", util: cloudserver.CvmUtilization{CpuP95: 2, MemP95: 8},
+			action: cloudserver.RightsizingShutdown, savingRatio: 1},
+		{name: "downsize", machineType: "S5.LARGE8", util: cloudserver.CvmUtilization{CpuP95: 30, MemP95: 30},
+			action: cloudserver.RightsizingDownsize, target: "S5.MEDIUM4", savingRatio: 0.5},
+		{name: "cpu low memory high", machineType: "S5.2XLARGE32",
+			util:   cloudserver.CvmUtilization{CpuP95: 10, MemP95: 30},
+			action: cloudserver.RightsizingChangeFamily, target: "M5.MEDIUM16", savingRatio: 0.625},
+		{name: "busy", machineType: "S5.LARGE8", util: cloudserver.CvmUtilization{CpuP95: 80, MemP95: 60}},
+		{name: "unknown instance type", machineType: "SA2.LARGE8",
+			util: cloudserver.CvmUtilization{CpuP95: 30, MemP95: 30}},
+	}
+
+	for _, c := range cases {
+		adv := recommend(c.machineType, testSpecs, c.util, testConf)
+		if len(c.action) == 0 {
+			if adv != nil {
+				t.Errorf("%s: expect no advice, got: %+v", c.name, adv)
+			}
+			continue
+		}
+
+		if adv == nil {
+			t.Errorf("%s: expect advice %s, got nil", c.name, c.action)
+			continue
+		}
+		if adv.action != c.action {
+			t.Errorf("%s: expect action %s, got: %s", c.name, c.action, adv.action)
+		}
+		if len(c.target) != 0 && (adv.target == nil || adv.target.InstanceType != c.target) {
+			t.Errorf("%s: expect target %s, got: %+v", c.name, c.target, adv.target)
+		}
+		if adv.savingRatio != c.savingRatio {
+			t.Errorf("%s: expect saving ratio %v, got: %v", c.name, c.savingRatio, adv.savingRatio)
+		}
+	}
+}
+
+func TestStaticFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	content := `{"ins-1": {"cpu_avg": 1.5, "cpu_p95": 3, "mem_avg": 20, "mem_p95": 25}, "ins-2": {"cpu_p95": 50}}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("write metric file failed, err: %v", err)
+	}
+
+	source, err := NewMetricSource(cc.RightsizingMetricSource{Type: cc.StaticFileMetricSource, Path: path})
+	if err != nil {
+		t.Fatalf("new metric source failed, err: %v", err)
+	}
+
+	utilMap, err := source.ListCvmUtilization(kit.New(), []string{"ins-1", "ins-3"})
+	if err != nil {
+		t.Fatalf("list cvm utilization failed, err: %v", err)
+	}
+	if len(utilMap) != 1 || utilMap["ins-1"].CpuP95 != 3 || utilMap["ins-1"].MemP95 != 25 {
+		t.Errorf("unexpected utilization: %+v", utilMap)
+	}
+
+	if _, err = NewMetricSource(cc.RightsizingMetricSource{}); err == nil {
+		t.Errorf("new metric source without type should be failed")
+	}
+}
+
+func TestBuildRecommendationCostSource(t *testing.T) {
+	price := func(v string) *decimal.Decimal {
+		d := decimal.RequireFromString(v)
+		return &d
+	}
+	current := &cvmlgc.InstanceTypeSpec{InstanceType: "S5.LARGE8", CPU: 4, Memory: 8192, MonthlyPrice: price("200")}
+	target := &cvmlgc.InstanceTypeSpec{InstanceType: "S5.MEDIUM4", CPU: 2, Memory: 4096, MonthlyPrice: price("120")}
+	one := &corecvm.BaseCvm{ID: "00000001", CloudID: "ins-1", Vendor: enumor.TCloud, MachineType: "S5.LARGE8"}
+	downsize := &advice{action: cloudserver.RightsizingDownsize, current: current, target: target, savingRatio: 0.5}
+
+	costMap := map[string]rescost.ResCost{"ins-1": {Cost: decimal.RequireFromString("100"),
+		Currency: enumor.CurrencyUSD}}
+	rec := buildRecommendation(one, cloudserver.CvmUtilization{}, downsize, costMap, enumor.CurrencyCNY)
+	if rec.CostSource != cloudserver.RightsizingCostBill || rec.Currency != enumor.CurrencyUSD ||
+		!rec.EstimatedMonthlySavings.Equal(decimal.NewFromInt(50)) {
+		t.Errorf("expect savings from bill, got: %+v", rec)
+	}
+
+	rec = buildRecommendation(one, cloudserver.CvmUtilization{}, downsize, nil, enumor.CurrencyCNY)
+	if rec.CostSource != cloudserver.RightsizingCostListPrice || !rec.MonthlyCost.Equal(*current.MonthlyPrice) ||
+		!rec.EstimatedMonthlySavings.Equal(decimal.NewFromInt(80)) || rec.Currency != enumor.CurrencyCNY {
+		t.Errorf("expect savings from list price, got: %+v", rec)
+	}
+
+	shutdown := &advice{action: cloudserver.RightsizingShutdown, current: current, savingRatio: 1}
+	rec = buildRecommendation(one, cloudserver.CvmUtilization{}, shutdown, nil, enumor.CurrencyCNY)
+	if rec.CostSource != cloudserver.RightsizingCostListPrice ||
+		!rec.EstimatedMonthlySavings.Equal(decimal.NewFromInt(200)) {
+		t.Errorf("expect shutdown saves whole list price, got: %+v", rec)
+	}
+
+	noPrice := &advice{action: cloudserver.RightsizingDownsize, current: &cvmlgc.InstanceTypeSpec{CPU: 4},
+		target: target, savingRatio: 0.5}
+	rec = buildRecommendation(one, cloudserver.CvmUtilization{}, noPrice, nil, "")
+	if rec.CostSource != cloudserver.RightsizingCostUnknown || rec.MonthlyCost != nil ||
+		rec.EstimatedMonthlySavings != nil {
+		t.Errorf("expect unknown cost, got: %+v", rec)
+	}
+}
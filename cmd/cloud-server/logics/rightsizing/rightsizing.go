@@ -0,0 +1,323 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package rightsizing 主机规格优化建议，结合主机机型规格、账单成本和利用率指标给出降配、更换机型族或关机建议
+package rightsizing
+
+import (
+	"fmt"
+	"sort"
+
+	cvmlgc "hcm/cmd/cloud-server/logics/cvm"
+	rescost "hcm/cmd/cloud-server/logics/res-cost"
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+
+	"github.com/shopspring/decimal"
+)
+
+// Interface define cvm rightsizing logics.
+type Interface interface {
+	ListRecommendation(kt *kit.Kit, opt *ListRecommendationOption) (*cloudserver.RightsizingRecommendListResult,
+		error)
+}
+
+// ListRecommendationOption list rightsizing recommendations option.
+type ListRecommendationOption struct {
+	BkBizID int64
+	// Filter 主机的附加过滤条件，如账号、主机ID
+	Filter    *filter.Expression
+	BillYear  int
+	BillMonth int
+	Page      *core.BasePage
+}
+
+// NewRightsizing new cvm rightsizing logics.
+func NewRightsizing(client *client.ClientSet, cvmLgc cvmlgc.Interface, conf cc.Rightsizing) Interface {
+	return &rightsizing{client: client, cvmLgc: cvmLgc, conf: conf}
+}
+
+type rightsizing struct {
+	client *client.ClientSet
+	cvmLgc cvmlgc.Interface
+	conf   cc.Rightsizing
+}
+
+// ListRecommendation 为业务下一页运行中的主机生成规格优化建议，页内按预计节省费用降序排列，成本未知的建议排在最后
+func (r *rightsizing) ListRecommendation(kt *kit.Kit, opt *ListRecommendationOption) (
+	*cloudserver.RightsizingRecommendListResult, error) {
+
+	source, err := NewMetricSource(r.conf.MetricSource)
+	if err != nil {
+		return nil, err
+	}
+
+	cvmResult, err := r.listRunningCvm(kt, opt)
+	if err != nil {
+		return nil, err
+	}
+	if opt.Page.Count {
+		return &cloudserver.RightsizingRecommendListResult{Count: cvmResult.Count}, nil
+	}
+	cvms := cvmResult.Details
+
+	result := &cloudserver.RightsizingRecommendListResult{
+		Details:        make([]cloudserver.RightsizingRecommendation, 0),
+		NoMetricCvmIDs: make([]string, 0),
+	}
+	if len(cvms) == 0 {
+		return result, nil
+	}
+
+	cloudIDs := slice.Map(cvms, func(one corecvm.BaseCvm) string { return one.CloudID })
+	utilMap, err := source.ListCvmUtilization(kt, cloudIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	vendors := slice.Unique(slice.Map(cvms, func(one corecvm.BaseCvm) enumor.Vendor { return one.Vendor }))
+	costMap, err := rescost.ListMonthlyResCost(kt, r.client, opt.BkBizID, vendors, opt.BillYear, opt.BillMonth)
+	if err != nil {
+		return nil, err
+	}
+
+	chargeTypeMap, err := r.listTCloudChargeType(kt, cvms)
+	if err != nil {
+		return nil, err
+	}
+
+	currencyMap, err := r.listTCloudCurrency(kt, cvms)
+	if err != nil {
+		return nil, err
+	}
+
+	specCache := make(map[string][]cvmlgc.InstanceTypeSpec)
+	for i := range cvms {
+		one := &cvms[i]
+		util, exists := utilMap[one.CloudID]
+		if !exists {
+			result.NoMetricCvmIDs = append(result.NoMetricCvmIDs, one.ID)
+			continue
+		}
+
+		specs, err := r.listInstanceTypeSpec(kt, one, chargeTypeMap[one.ID], specCache)
+		if err != nil {
+			// 单个主机机型查询失败不影响其他主机的建议
+			logs.Errorf("list cvm %s available instance type failed, err: %v, rid: %s", one.ID, err, kt.Rid)
+			continue
+		}
+
+		adv := recommend(one.MachineType, specs, util, r.conf)
+		if adv == nil {
+			continue
+		}
+
+		rec := buildRecommendation(one, util, adv, costMap[one.Vendor], currencyMap[one.AccountID])
+		result.Details = append(result.Details, rec)
+	}
+
+	sortRecommendation(result.Details)
+	return result, nil
+}
+
+// listRunningCvm 分页查询业务下运行中的主机，仅运行中的主机有可参考的利用率
+func (r *rightsizing) listRunningCvm(kt *kit.Kit, opt *ListRecommendationOption) (*protocloud.CvmListResult,
+	error) {
+
+	cvmExpr := opt.Filter
+	if cvmExpr == nil {
+		cvmExpr = tools.AllExpression()
+	}
+
+	expr, err := tools.And(cvmExpr, tools.EqualExpression("bk_biz_id", opt.BkBizID),
+		tools.EqualExpression("lifecycle_status", enumor.CvmLifecycleRunning))
+	if err != nil {
+		return nil, err
+	}
+
+	page := *opt.Page
+	if !page.Count {
+		page.Sort = "id"
+		page.Order = core.Ascending
+	}
+	result, err := r.client.DataService().Global.Cvm.ListCvm(kt, &core.ListReq{Filter: expr, Page: &page})
+	if err != nil {
+		logs.Errorf("list running cvm failed, err: %v, biz: %d, rid: %s", err, opt.BkBizID, kt.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// listTCloudChargeType 批量查询腾讯云主机的计费模式，返回主机ID到计费模式的映射，腾讯云可用机型及价格与计费模式相关
+func (r *rightsizing) listTCloudChargeType(kt *kit.Kit, cvms []corecvm.BaseCvm) (map[string]string, error) {
+	ids := make([]string, 0)
+	for _, one := range cvms {
+		if one.Vendor == enumor.TCloud {
+			ids = append(ids, one.ID)
+		}
+	}
+
+	result := make(map[string]string, len(ids))
+	for _, batch := range slice.Split(ids, int(core.DefaultMaxPageLimit)) {
+		req := &protocloud.CvmListReq{Filter: tools.ContainersExpression("id", batch),
+			Page: core.NewDefaultBasePage()}
+		list, err := r.client.DataService().TCloud.Cvm.ListCvmExt(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			logs.Errorf("list tcloud cvm extension failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+		for _, one := range list.Details {
+			if one.Extension != nil {
+				result[one.ID] = converter.PtrToVal(one.Extension.InstanceChargeType)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// listTCloudCurrency 查询腾讯云主机所属账号的结算币种，返回账号ID到币种的映射，用于标识机型目录价的币种，
+// 中国站账号为人民币，国际站账号为美元
+func (r *rightsizing) listTCloudCurrency(kt *kit.Kit, cvms []corecvm.BaseCvm) (map[string]enumor.CurrencyCode,
+	error) {
+
+	accountIDs := make([]string, 0)
+	for _, one := range cvms {
+		if one.Vendor == enumor.TCloud {
+			accountIDs = append(accountIDs, one.AccountID)
+		}
+	}
+	accountIDs = slice.Unique(accountIDs)
+
+	result := make(map[string]enumor.CurrencyCode, len(accountIDs))
+	for _, batch := range slice.Split(accountIDs, int(core.DefaultMaxPageLimit)) {
+		req := &protocloud.AccountListReq{Filter: tools.ContainersExpression("id", batch),
+			Page: core.NewDefaultBasePage()}
+		list, err := r.client.DataService().Global.Account.List(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			logs.Errorf("list tcloud account failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+		for _, one := range list.Details {
+			if one.Site == enumor.InternationalSite {
+				result[one.ID] = enumor.CurrencyUSD
+				continue
+			}
+			result[one.ID] = enumor.CurrencyCNY
+		}
+	}
+
+	return result, nil
+}
+
+// listInstanceTypeSpec 查询主机可用的机型规格，同一账号、地域、可用区、计费模式下的主机共用查询结果
+func (r *rightsizing) listInstanceTypeSpec(kt *kit.Kit, one *corecvm.BaseCvm, chargeType string,
+	cache map[string][]cvmlgc.InstanceTypeSpec) ([]cvmlgc.InstanceTypeSpec, error) {
+
+	key := fmt.Sprintf("%s/%s/%s/%s/%s", one.Vendor, one.AccountID, one.Region, one.Zone, chargeType)
+	if specs, exists := cache[key]; exists {
+		return specs, nil
+	}
+
+	specs, err := r.cvmLgc.ListAvailableInstanceTypeByCharge(kt, one, chargeType)
+	if err != nil {
+		return nil, err
+	}
+	cache[key] = specs
+
+	return specs, nil
+}
+
+// buildRecommendation 构造规格优化建议，优先按账单成本估算节省费用，账单无法关联到主机时按机型目录价估算
+func buildRecommendation(one *corecvm.BaseCvm, util cloudserver.CvmUtilization, adv *advice,
+	costMap map[string]rescost.ResCost, listPriceCurrency enumor.CurrencyCode) cloudserver.RightsizingRecommendation {
+
+	rec := cloudserver.RightsizingRecommendation{
+		CvmID:               one.ID,
+		CloudID:             one.CloudID,
+		Name:                one.Name,
+		Vendor:              one.Vendor,
+		AccountID:           one.AccountID,
+		Region:              one.Region,
+		Zone:                one.Zone,
+		BkBizID:             one.BkBizID,
+		Action:              adv.action,
+		Reason:              adv.reason,
+		Utilization:         util,
+		CurrentInstanceType: one.MachineType,
+		CostSource:          cloudserver.RightsizingCostUnknown,
+	}
+	if adv.current != nil {
+		rec.CurrentCPU = adv.current.CPU
+		rec.CurrentMemory = adv.current.Memory
+	}
+	if adv.target != nil {
+		rec.TargetInstanceType = adv.target.InstanceType
+		rec.TargetCPU = adv.target.CPU
+		rec.TargetMemory = adv.target.Memory
+	}
+
+	if resCost, exists := costMap[one.CloudID]; exists {
+		savings := resCost.Cost.Mul(decimal.NewFromFloat(adv.savingRatio)).Round(2)
+		rec.CostSource = cloudserver.RightsizingCostBill
+		rec.MonthlyCost = &resCost.Cost
+		rec.Currency = resCost.Currency
+		rec.EstimatedMonthlySavings = &savings
+		return rec
+	}
+
+	// 关机节省当前机型的全部费用，调整规格节省当前机型与目标机型的差价
+	if adv.current == nil || adv.current.MonthlyPrice == nil {
+		return rec
+	}
+	savings := *adv.current.MonthlyPrice
+	if adv.target != nil {
+		if adv.target.MonthlyPrice == nil {
+			return rec
+		}
+		savings = savings.Sub(*adv.target.MonthlyPrice)
+	}
+	rec.CostSource = cloudserver.RightsizingCostListPrice
+	rec.MonthlyCost = adv.current.MonthlyPrice
+	rec.Currency = listPriceCurrency
+	rec.EstimatedMonthlySavings = &savings
+	return rec
+}
+
+func sortRecommendation(details []cloudserver.RightsizingRecommendation) {
+	sort.SliceStable(details, func(i, j int) bool {
+		a, b := details[i].EstimatedMonthlySavings, details[j].EstimatedMonthlySavings
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.GreaterThan(*b)
+	})
+}
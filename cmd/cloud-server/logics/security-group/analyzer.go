@@ -0,0 +1,371 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"net"
+	"sort"
+	"strings"
+
+	cloudserver "hcm/pkg/api/cloud-server"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/slice"
+)
+
+// evalMode 检查点内规则的评估方式
+type evalMode int
+
+const (
+	// firstMatch 按照规则顺序匹配，首条命中的规则决定动作
+	firstMatch evalMode = iota
+	// anyAllow 规则全部为允许规则，任一规则命中即放通，没有顺序（aws）
+	anyAllow
+)
+
+// policy 流量路径上的一个检查点，比如 azure 子网和网络接口上的安全组分别为一个检查点
+type policy struct {
+	scope            enumor.CloudResourceType
+	scopeID          string
+	securityGroupIDs []string
+	mode             evalMode
+	defaultIngress   enumor.SecurityGroupRuleAction
+	defaultEgress    enumor.SecurityGroupRuleAction
+	// ingress, egress 为按实际匹配顺序排列的规则
+	ingress []corecloud.NormalizedSGRule
+	egress  []corecloud.NormalizedSGRule
+}
+
+func newPolicy(scope enumor.CloudResourceType, scopeID string, mode evalMode) *policy {
+	return &policy{
+		scope:            scope,
+		scopeID:          scopeID,
+		securityGroupIDs: make([]string, 0),
+		mode:             mode,
+		defaultIngress:   enumor.SGRuleDeny,
+		defaultEgress:    enumor.SGRuleDeny,
+		ingress:          make([]corecloud.NormalizedSGRule, 0),
+		egress:           make([]corecloud.NormalizedSGRule, 0),
+	}
+}
+
+// addRules add rules to policy by rule type, the rules order is kept.
+func (p *policy) addRules(rules []corecloud.NormalizedSGRule) {
+	for _, one := range rules {
+		if one.Type == enumor.Egress {
+			p.egress = append(p.egress, one)
+			continue
+		}
+		p.ingress = append(p.ingress, one)
+	}
+}
+
+// sortByPriority sort rules by priority, deny rule takes precedence over allow rule with same priority.
+func (p *policy) sortByPriority() {
+	for _, rules := range [][]corecloud.NormalizedSGRule{p.ingress, p.egress} {
+		sort.SliceStable(rules, func(i, j int) bool {
+			if rules[i].Priority != rules[j].Priority {
+				return rules[i].Priority < rules[j].Priority
+			}
+			return rules[i].Action == enumor.SGRuleDeny && rules[j].Action != enumor.SGRuleDeny
+		})
+	}
+}
+
+func (p *policy) rules(ruleType enumor.SecurityGroupRuleType) ([]corecloud.NormalizedSGRule,
+	enumor.SecurityGroupRuleAction) {
+
+	if ruleType == enumor.Egress {
+		return p.egress, p.defaultEgress
+	}
+	return p.ingress, p.defaultIngress
+}
+
+// analyze mark every rule of the policy as effective, shadowed or redundant.
+func (p *policy) analyze() cloudserver.SGPolicyAnalysis {
+	return cloudserver.SGPolicyAnalysis{
+		Scope:            p.scope,
+		ScopeID:          p.scopeID,
+		SecurityGroupIDs: p.securityGroupIDs,
+		DefaultIngress:   p.defaultIngress,
+		DefaultEgress:    p.defaultEgress,
+		Ingress:          analyzeRules(p.ingress, p.mode),
+		Egress:           analyzeRules(p.egress, p.mode),
+	}
+}
+
+func analyzeRules(rules []corecloud.NormalizedSGRule, mode evalMode) []corecloud.AnalyzedSGRule {
+	result := make([]corecloud.AnalyzedSGRule, len(rules))
+	for i, rule := range rules {
+		result[i] = corecloud.AnalyzedSGRule{
+			NormalizedSGRule: rule,
+			Order:            i,
+			Status:           enumor.SGRuleEffective,
+		}
+
+		for j, other := range rules {
+			if i == j || !covers(other, rule) {
+				continue
+			}
+
+			if mode == firstMatch {
+				// 只有排在前面的规则才会使当前规则无法命中
+				if j > i {
+					continue
+				}
+
+				result[i].Status = enumor.SGRuleRedundant
+				if other.Action != rule.Action {
+					result[i].Status = enumor.SGRuleShadowed
+				}
+				result[i].CoveredBy = other.RuleID
+				break
+			}
+
+			// 两条规则互相覆盖时，只将后面的规则标记为冗余
+			if j > i && covers(rule, other) {
+				continue
+			}
+			result[i].Status = enumor.SGRuleRedundant
+			result[i].CoveredBy = other.RuleID
+			break
+		}
+	}
+
+	return result
+}
+
+// decide check whether the traffic is allowed by the policy.
+func (p *policy) decide(ruleType enumor.SecurityGroupRuleType, ip net.IP, protocol string,
+	port int64) cloudserver.SGPolicyDecision {
+
+	rules, defaultAction := p.rules(ruleType)
+	decision := cloudserver.SGPolicyDecision{
+		Scope:             p.scope,
+		ScopeID:           p.scopeID,
+		Allowed:           defaultAction == enumor.SGRuleAllow,
+		UnresolvedRuleIDs: make([]string, 0),
+	}
+
+	// unresolvedActions 无法判定是否命中的规则的动作，与最终动作不同时结果无法确定
+	unresolvedActions := make(map[enumor.SecurityGroupRuleAction]struct{})
+	for idx, rule := range rules {
+		if !matchProtocolPort(rule, protocol, port) {
+			continue
+		}
+
+		addrMatched := matchAddress(rule, ip)
+		if !addrMatched && len(rule.References) == 0 {
+			continue
+		}
+
+		if !addrMatched || len(rule.Targets) != 0 || strings.HasPrefix(rule.Protocol, templateProtocolPrefix) {
+			decision.UnresolvedRuleIDs = append(decision.UnresolvedRuleIDs, rule.RuleID)
+			unresolvedActions[rule.Action] = struct{}{}
+			continue
+		}
+
+		decision.Allowed = rule.Action == enumor.SGRuleAllow
+		decision.MatchedRule = &corecloud.AnalyzedSGRule{
+			NormalizedSGRule: rule,
+			Order:            idx,
+			Status:           enumor.SGRuleEffective,
+		}
+		break
+	}
+
+	finalAction := sgRuleAction(decision.Allowed)
+	for action := range unresolvedActions {
+		if action != finalAction {
+			decision.Indeterminate = true
+			decision.Allowed = false
+			break
+		}
+	}
+
+	return decision
+}
+
+func matchProtocolPort(rule corecloud.NormalizedSGRule, protocol string, port int64) bool {
+	if strings.HasPrefix(rule.Protocol, templateProtocolPrefix) {
+		return true
+	}
+
+	if rule.Protocol != protocolAll && rule.Protocol != protocol {
+		return false
+	}
+
+	if !hasPort(protocol) || len(rule.Ports) == 0 {
+		return true
+	}
+
+	for _, one := range rule.Ports {
+		if port >= one.From && port <= one.To {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchAddress(rule corecloud.NormalizedSGRule, ip net.IP) bool {
+	for _, addr := range rule.Addresses {
+		switch addr {
+		case addressAny:
+			return true
+		case azureTagInternet:
+			if !isInternalIP(ip) {
+				return true
+			}
+		case azureTagVirtualNetwork:
+			// 虚拟网络地址空间没有同步，按照私有地址近似判断
+			if isInternalIP(ip) {
+				return true
+			}
+		default:
+			_, ipNet, err := net.ParseCIDR(addr)
+			if err == nil && ipNet.Contains(ip) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isInternalIP(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast()
+}
+
+// covers check whether all traffic matched by rule b can be matched by rule a.
+func covers(a, b corecloud.NormalizedSGRule) bool {
+	return coversTargets(a, b) && coversProtocol(a, b) && coversAddress(a, b)
+}
+
+// coversTargets 指定了目标的规则只作用于部分实例，只能覆盖目标相同的规则
+func coversTargets(a, b corecloud.NormalizedSGRule) bool {
+	if len(a.Targets) == 0 {
+		return true
+	}
+
+	if len(a.Targets) != len(b.Targets) {
+		return false
+	}
+
+	for _, target := range b.Targets {
+		if !slice.IsItemInSlice(a.Targets, target) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func coversProtocol(a, b corecloud.NormalizedSGRule) bool {
+	if a.Protocol != protocolAll && a.Protocol != b.Protocol {
+		return false
+	}
+
+	if len(a.Ports) == 0 || !hasPort(b.Protocol) {
+		return true
+	}
+
+	if len(b.Ports) == 0 {
+		return false
+	}
+
+	for _, bp := range b.Ports {
+		if !portRangeCovered(a.Ports, bp) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// portRangeCovered check whether target port range is covered by the union of port ranges.
+func portRangeCovered(ranges []corecloud.PortRange, target corecloud.PortRange) bool {
+	sorted := make([]corecloud.PortRange, len(ranges))
+	copy(sorted, ranges)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].From < sorted[j].From })
+
+	next := target.From
+	for _, one := range sorted {
+		if one.From > next {
+			break
+		}
+		if one.To >= next {
+			next = one.To + 1
+		}
+		if next > target.To {
+			return true
+		}
+	}
+
+	return false
+}
+
+func coversAddress(a, b corecloud.NormalizedSGRule) bool {
+	for _, addr := range a.Addresses {
+		if addr == addressAny {
+			return true
+		}
+	}
+
+	for _, ref := range b.References {
+		if !slice.IsItemInSlice(a.References, ref) {
+			return false
+		}
+	}
+
+	for _, addr := range b.Addresses {
+		if !addressCovered(a.Addresses, addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func addressCovered(addresses []string, target string) bool {
+	_, targetNet, targetErr := net.ParseCIDR(target)
+	for _, addr := range addresses {
+		if addr == target {
+			return true
+		}
+
+		if targetErr != nil {
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(addr)
+		if err != nil {
+			continue
+		}
+
+		aOnes, aBits := ipNet.Mask.Size()
+		tOnes, tBits := targetNet.Mask.Size()
+		if aBits == tBits && aOnes <= tOnes && ipNet.Contains(targetNet.IP) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,218 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"net"
+	"testing"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/converter"
+)
+
+func TestTCloudFirstMatch(t *testing.T) {
+	rules, err := NormalizeTCloudRules([]corecloud.TCloudSecurityGroupRule{
+		{ID: "1", CloudPolicyIndex: 0, Protocol: converter.ValToPtr("TCP"), Port: converter.ValToPtr("22"),
+			IPv4Cidr: converter.ValToPtr("10.0.0.0/8"), Action: "ACCEPT", Type: enumor.Ingress},
+		{ID: "2", CloudPolicyIndex: 1, Protocol: converter.ValToPtr("TCP"), Port: converter.ValToPtr("22"),
+			IPv4Cidr: converter.ValToPtr("0.0.0.0/0"), Action: "DROP", Type: enumor.Ingress},
+		{ID: "3", CloudPolicyIndex: 2, Protocol: converter.ValToPtr("TCP"), Port: converter.ValToPtr("22"),
+			IPv4Cidr: converter.ValToPtr("192.168.1.0/24"), Action: "ACCEPT", Type: enumor.Ingress},
+		{ID: "4", CloudPolicyIndex: 3, Protocol: converter.ValToPtr("TCP"), Port: converter.ValToPtr("10,22"),
+			IPv4Cidr: converter.ValToPtr("10.1.0.0/16"), Action: "ACCEPT", Type: enumor.Ingress},
+	})
+	if err != nil {
+		t.Fatalf("normalize tcloud rules failed, err: %v", err)
+	}
+
+	p := newPolicy(enumor.CvmCloudResType, "cvm", firstMatch)
+	p.addRules(rules)
+	analysis := p.analyze()
+
+	expects := []enumor.SGRuleAnalysisStatus{enumor.SGRuleEffective, enumor.SGRuleEffective, enumor.SGRuleShadowed,
+		enumor.SGRuleEffective}
+	for idx, one := range analysis.Ingress {
+		if one.Status != expects[idx] {
+			t.Errorf("rule %s got status: %s, expect: %s", one.RuleID, one.Status, expects[idx])
+		}
+	}
+
+	cases := []struct {
+		ip      string
+		port    int64
+		allowed bool
+	}{
+		{ip: "10.1.1.1", port: 22, allowed: true},
+		{ip: "192.168.1.1", port: 22, allowed: false},
+		{ip: "10.1.1.1", port: 10, allowed: true},
+		{ip: "10.2.1.1", port: 10, allowed: false},
+	}
+	for _, c := range cases {
+		decision := p.decide(enumor.Ingress, net.ParseIP(c.ip), "tcp", c.port)
+		if decision.Allowed != c.allowed {
+			t.Errorf("%s:%d got allowed: %v, expect: %v", c.ip, c.port, decision.Allowed, c.allowed)
+		}
+	}
+}
+
+func TestAwsRedundant(t *testing.T) {
+	rules, err := NormalizeAwsRules([]corecloud.AwsSecurityGroupRule{
+		{ID: "1", Protocol: converter.ValToPtr("tcp"), FromPort: converter.ValToPtr(int64(1000)),
+			ToPort: converter.ValToPtr(int64(2000)), IPv4Cidr: converter.ValToPtr("10.0.0.0/16"), Type: enumor.Ingress},
+		{ID: "2", Protocol: converter.ValToPtr("tcp"), FromPort: converter.ValToPtr(int64(1500)),
+			ToPort: converter.ValToPtr(int64(1600)), IPv4Cidr: converter.ValToPtr("10.0.1.0/24"), Type: enumor.Ingress},
+		{ID: "3", Protocol: converter.ValToPtr("-1"), IPv4Cidr: converter.ValToPtr("10.0.0.0/16"),
+			Type: enumor.Ingress},
+		{ID: "4", Protocol: converter.ValToPtr("tcp"), FromPort: converter.ValToPtr(int64(22)),
+			ToPort: converter.ValToPtr(int64(22)), CloudTargetSecurityGroupID: converter.ValToPtr("sg-1"),
+			Type: enumor.Ingress},
+	})
+	if err != nil {
+		t.Fatalf("normalize aws rules failed, err: %v", err)
+	}
+
+	p := newPolicy(enumor.CvmCloudResType, "cvm", anyAllow)
+	p.addRules(rules)
+	analysis := p.analyze()
+
+	expects := []enumor.SGRuleAnalysisStatus{enumor.SGRuleRedundant, enumor.SGRuleRedundant, enumor.SGRuleEffective,
+		enumor.SGRuleEffective}
+	for idx, one := range analysis.Ingress {
+		if one.Status != expects[idx] {
+			t.Errorf("rule %s got status: %s, expect: %s", one.RuleID, one.Status, expects[idx])
+		}
+	}
+
+	decision := p.decide(enumor.Ingress, net.ParseIP("172.16.0.1"), "tcp", 22)
+	if decision.Allowed || len(decision.UnresolvedRuleIDs) != 1 {
+		t.Errorf("got allowed: %v, unresolved: %v, expect denied with one unresolved rule", decision.Allowed,
+			decision.UnresolvedRuleIDs)
+	}
+}
+
+func TestHuaWeiDenyPrecedence(t *testing.T) {
+	rules, err := NormalizeHuaWeiRules([]corecloud.HuaWeiSecurityGroupRule{
+		{ID: "1", Protocol: "tcp", Port: "3389", Priority: 1, Action: "allow", Ethertype: "IPv4",
+			Type: enumor.Ingress},
+		{ID: "2", Protocol: "", Priority: 1, Action: "deny", Ethertype: "IPv4", RemoteIPPrefix: "0.0.0.0/0",
+			Type: enumor.Ingress},
+	})
+	if err != nil {
+		t.Fatalf("normalize huawei rules failed, err: %v", err)
+	}
+
+	p := newPolicy(enumor.CvmCloudResType, "cvm", firstMatch)
+	p.addRules(rules)
+	p.sortByPriority()
+
+	decision := p.decide(enumor.Ingress, net.ParseIP("1.1.1.1"), "tcp", 3389)
+	if decision.Allowed || decision.MatchedRule == nil || decision.MatchedRule.RuleID != "2" {
+		t.Errorf("deny rule with same priority should take precedence, got: %+v", decision)
+	}
+
+	analysis := p.analyze()
+	if analysis.Ingress[1].RuleID != "1" || analysis.Ingress[1].Status != enumor.SGRuleShadowed {
+		t.Errorf("allow rule should be shadowed by deny rule, got: %+v", analysis.Ingress[1])
+	}
+}
+
+func TestGcpTargetRuleIndeterminate(t *testing.T) {
+	rules, err := NormalizeGcpFirewallRules([]corecloud.GcpFirewallRule{
+		{ID: "1", Priority: 100, Type: "INGRESS", SourceRanges: []string{"0.0.0.0/0"}, TargetTags: []string{"web"},
+			Allowed: []corecloud.GcpProtocolSet{{Protocol: "tcp", Port: []string{"80"}}}},
+		{ID: "2", Priority: 200, Type: "INGRESS", SourceRanges: []string{"10.0.0.0/8"},
+			TargetServiceAccounts: []string{"sa@project.iam.gserviceaccount.com"},
+			Denied:                []corecloud.GcpProtocolSet{{Protocol: "tcp", Port: []string{"22"}}}},
+		{ID: "3", Priority: 300, Type: "INGRESS", SourceRanges: []string{"10.0.0.0/8"},
+			Allowed: []corecloud.GcpProtocolSet{{Protocol: "tcp"}}},
+	})
+	if err != nil {
+		t.Fatalf("normalize gcp firewall rules failed, err: %v", err)
+	}
+
+	p := newPolicy(enumor.VpcCloudResType, "vpc", firstMatch)
+	p.defaultEgress = enumor.SGRuleAllow
+	p.addRules(rules)
+	p.sortByPriority()
+
+	cases := []struct {
+		name          string
+		ip            string
+		port          int64
+		allowed       bool
+		indeterminate bool
+	}{
+		// 目标规则放通，但不确定是否作用于实例，默认拒绝
+		{name: "target allow before default deny", ip: "1.1.1.1", port: 80, allowed: false, indeterminate: true},
+		// 目标规则拒绝，但不确定是否作用于实例，后续规则放通
+		{name: "target deny before allow", ip: "10.0.0.1", port: 22, allowed: false, indeterminate: true},
+		// 目标规则与最终动作相同，不影响结果
+		{name: "target allow before allow", ip: "10.0.0.1", port: 80, allowed: true, indeterminate: false},
+	}
+	for _, c := range cases {
+		decision := p.decide(enumor.Ingress, net.ParseIP(c.ip), "tcp", c.port)
+		if decision.Allowed != c.allowed || decision.Indeterminate != c.indeterminate {
+			t.Errorf("%s: got allowed: %v, indeterminate: %v, expect: %v, %v", c.name, decision.Allowed,
+				decision.Indeterminate, c.allowed, c.indeterminate)
+		}
+	}
+
+	analysis := p.analyze()
+	for _, one := range analysis.Ingress {
+		if one.Status != enumor.SGRuleEffective {
+			t.Errorf("rule %s should be effective, got: %s, covered by: %s", one.RuleID, one.Status, one.CoveredBy)
+		}
+	}
+}
+
+func TestParsePorts(t *testing.T) {
+	cases := []struct {
+		ports  string
+		expect []corecloud.PortRange
+		hasErr bool
+	}{
+		{ports: "ALL", expect: nil},
+		{ports: "1-65535", expect: nil},
+		{ports: "80", expect: []corecloud.PortRange{{From: 80, To: 80}}},
+		{ports: "80,8000-8080", expect: []corecloud.PortRange{{From: 80, To: 80}, {From: 8000, To: 8080}}},
+		{ports: "90-80", hasErr: true},
+		{ports: "http", hasErr: true},
+	}
+
+	for _, c := range cases {
+		got, err := parsePorts(c.ports)
+		if (err != nil) != c.hasErr {
+			t.Errorf("parse %s got err: %v, expect err: %v", c.ports, err, c.hasErr)
+			continue
+		}
+
+		if len(got) != len(c.expect) {
+			t.Errorf("parse %s got: %v, expect: %v", c.ports, got, c.expect)
+			continue
+		}
+
+		for idx := range got {
+			if got[idx] != c.expect[idx] {
+				t.Errorf("parse %s got: %v, expect: %v", c.ports, got, c.expect)
+			}
+		}
+	}
+}
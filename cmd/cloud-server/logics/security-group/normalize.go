@@ -0,0 +1,436 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/converter"
+)
+
+const (
+	// protocolAll 所有协议
+	protocolAll = "all"
+	// addressAny 任意地址
+	addressAny = "*"
+	// templateProtocolPrefix 协议端口模版，无法解析成具体的协议端口
+	templateProtocolPrefix = "template:"
+
+	minPort = 0
+	maxPort = 65535
+)
+
+// normalizeProtocol 将各厂商的协议统一为小写协议名，all 表示所有协议
+func normalizeProtocol(protocol string) string {
+	protocol = strings.ToLower(strings.TrimSpace(protocol))
+	switch protocol {
+	case "", "all", "any", "*", "-1":
+		return protocolAll
+	case "6":
+		return "tcp"
+	case "17":
+		return "udp"
+	case "1":
+		return "icmp"
+	case "58":
+		return "icmpv6"
+	default:
+		return protocol
+	}
+}
+
+// hasPort 只有 tcp、udp 协议区分端口，其余协议按照所有端口处理
+func hasPort(protocol string) bool {
+	return protocol == "tcp" || protocol == "udp"
+}
+
+// parsePorts parse port expression like "80", "80-90", "80,443", empty result means all ports.
+func parsePorts(ports ...string) ([]corecloud.PortRange, error) {
+	result := make([]corecloud.PortRange, 0)
+	for _, port := range ports {
+		for _, one := range strings.Split(port, ",") {
+			one = strings.ToLower(strings.TrimSpace(one))
+			switch one {
+			case "":
+				continue
+			case "all", "*", "-1":
+				return nil, nil
+			}
+
+			from, to, found := strings.Cut(one, "-")
+			if !found {
+				to = from
+			}
+
+			fromPort, err := strconv.ParseInt(strings.TrimSpace(from), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid port: %s", one)
+			}
+
+			toPort, err := strconv.ParseInt(strings.TrimSpace(to), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid port: %s", one)
+			}
+
+			if fromPort > toPort || fromPort < minPort || toPort > maxPort {
+				return nil, fmt.Errorf("invalid port range: %s", one)
+			}
+
+			// 1-65535 与 0-65535 都表示所有端口
+			if fromPort <= 1 && toPort == maxPort {
+				return nil, nil
+			}
+
+			result = append(result, corecloud.PortRange{From: fromPort, To: toPort})
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+
+	return result, nil
+}
+
+// normalizeAddress 统一地址格式，单个IP转换为CIDR
+func normalizeAddress(addr string) string {
+	addr = strings.TrimSpace(addr)
+	if addr == addressAny {
+		return addressAny
+	}
+
+	if ip := net.ParseIP(addr); ip != nil {
+		if ip.To4() != nil {
+			return addr + "/32"
+		}
+		return addr + "/128"
+	}
+
+	return addr
+}
+
+func isCidr(addr string) bool {
+	_, _, err := net.ParseCIDR(addr)
+	return err == nil
+}
+
+func sgRuleAction(allow bool) enumor.SecurityGroupRuleAction {
+	if allow {
+		return enumor.SGRuleAllow
+	}
+	return enumor.SGRuleDeny
+}
+
+// NormalizeTCloudRules normalize tcloud security group rules.
+func NormalizeTCloudRules(rules []corecloud.TCloudSecurityGroupRule) ([]corecloud.NormalizedSGRule, error) {
+	result := make([]corecloud.NormalizedSGRule, 0, len(rules))
+	for _, one := range rules {
+		rule := corecloud.NormalizedSGRule{
+			RuleID:          one.ID,
+			SecurityGroupID: one.SecurityGroupID,
+			Vendor:          enumor.TCloud,
+			Type:            one.Type,
+			Action:          sgRuleAction(strings.ToUpper(one.Action) == "ACCEPT"),
+			Protocol:        normalizeProtocol(converter.PtrToVal(one.Protocol)),
+			Priority:        one.CloudPolicyIndex,
+			Memo:            one.Memo,
+		}
+
+		switch {
+		case len(converter.PtrToVal(one.CloudServiceID)) != 0:
+			rule.Protocol = templateProtocolPrefix + *one.CloudServiceID
+		case len(converter.PtrToVal(one.CloudServiceGroupID)) != 0:
+			rule.Protocol = templateProtocolPrefix + *one.CloudServiceGroupID
+		default:
+			if hasPort(rule.Protocol) {
+				ports, err := parsePorts(converter.PtrToVal(one.Port))
+				if err != nil {
+					return nil, fmt.Errorf("tcloud rule(%s) %v", one.ID, err)
+				}
+				rule.Ports = ports
+			}
+		}
+
+		for _, cidr := range []*string{one.IPv4Cidr, one.IPv6Cidr} {
+			if len(converter.PtrToVal(cidr)) != 0 {
+				rule.Addresses = append(rule.Addresses, normalizeAddress(*cidr))
+			}
+		}
+		if len(converter.PtrToVal(one.CloudTargetSecurityGroupID)) != 0 {
+			rule.References = append(rule.References, "security_group:"+*one.CloudTargetSecurityGroupID)
+		}
+		if len(converter.PtrToVal(one.CloudAddressID)) != 0 {
+			rule.References = append(rule.References, "address:"+*one.CloudAddressID)
+		}
+		if len(converter.PtrToVal(one.CloudAddressGroupID)) != 0 {
+			rule.References = append(rule.References, "address_group:"+*one.CloudAddressGroupID)
+		}
+		fillAnyAddress(&rule)
+
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+// NormalizeAwsRules normalize aws security group rules, aws security group only has allow rules.
+func NormalizeAwsRules(rules []corecloud.AwsSecurityGroupRule) ([]corecloud.NormalizedSGRule, error) {
+	result := make([]corecloud.NormalizedSGRule, 0, len(rules))
+	for _, one := range rules {
+		rule := corecloud.NormalizedSGRule{
+			RuleID:          one.ID,
+			CloudRuleID:     one.CloudID,
+			SecurityGroupID: one.SecurityGroupID,
+			Vendor:          enumor.Aws,
+			Type:            one.Type,
+			Action:          enumor.SGRuleAllow,
+			Protocol:        normalizeProtocol(converter.PtrToVal(one.Protocol)),
+			Memo:            one.Memo,
+		}
+
+		// icmp 协议的 from_port、to_port 为 type 和 code，不作为端口处理
+		if hasPort(rule.Protocol) && one.FromPort != nil && one.ToPort != nil && *one.FromPort != -1 {
+			ports, err := parsePorts(fmt.Sprintf("%d-%d", *one.FromPort, *one.ToPort))
+			if err != nil {
+				return nil, fmt.Errorf("aws rule(%s) %v", one.ID, err)
+			}
+			rule.Ports = ports
+		}
+
+		for _, cidr := range []*string{one.IPv4Cidr, one.IPv6Cidr} {
+			if len(converter.PtrToVal(cidr)) != 0 {
+				rule.Addresses = append(rule.Addresses, normalizeAddress(*cidr))
+			}
+		}
+		if len(converter.PtrToVal(one.CloudTargetSecurityGroupID)) != 0 {
+			rule.References = append(rule.References, "security_group:"+*one.CloudTargetSecurityGroupID)
+		}
+		if len(converter.PtrToVal(one.CloudPrefixListID)) != 0 {
+			rule.References = append(rule.References, "prefix_list:"+*one.CloudPrefixListID)
+		}
+		fillAnyAddress(&rule)
+
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+// NormalizeHuaWeiRules normalize huawei security group rules.
+func NormalizeHuaWeiRules(rules []corecloud.HuaWeiSecurityGroupRule) ([]corecloud.NormalizedSGRule, error) {
+	result := make([]corecloud.NormalizedSGRule, 0, len(rules))
+	for _, one := range rules {
+		rule := corecloud.NormalizedSGRule{
+			RuleID:          one.ID,
+			CloudRuleID:     one.CloudID,
+			SecurityGroupID: one.SecurityGroupID,
+			Vendor:          enumor.HuaWei,
+			Type:            one.Type,
+			Action:          sgRuleAction(strings.ToLower(one.Action) != "deny"),
+			Protocol:        normalizeProtocol(one.Protocol),
+			Priority:        one.Priority,
+			Memo:            one.Memo,
+		}
+
+		if hasPort(rule.Protocol) {
+			ports, err := parsePorts(one.Port)
+			if err != nil {
+				return nil, fmt.Errorf("huawei rule(%s) %v", one.ID, err)
+			}
+			rule.Ports = ports
+		}
+
+		if len(one.RemoteIPPrefix) != 0 {
+			rule.Addresses = append(rule.Addresses, normalizeAddress(one.RemoteIPPrefix))
+		}
+		if len(one.CloudRemoteGroupID) != 0 {
+			rule.References = append(rule.References, "security_group:"+one.CloudRemoteGroupID)
+		}
+		if len(one.CloudRemoteAddressGroupID) != 0 {
+			rule.References = append(rule.References, "address_group:"+one.CloudRemoteAddressGroupID)
+		}
+
+		// 华为云未指定远端时表示对应IP版本的所有地址
+		if len(rule.Addresses) == 0 && len(rule.References) == 0 {
+			if strings.EqualFold(one.Ethertype, "IPv6") {
+				rule.Addresses = []string{"::/0"}
+			} else {
+				rule.Addresses = []string{"0.0.0.0/0"}
+			}
+		}
+
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+// azureServiceTags azure 服务标签中可以近似按地址判定的标签
+var azureServiceTags = map[string]struct{}{
+	azureTagInternet:       {},
+	azureTagVirtualNetwork: {},
+}
+
+const (
+	azureTagInternet       = "Internet"
+	azureTagVirtualNetwork = "VirtualNetwork"
+)
+
+// NormalizeAzureRules normalize azure security group rules.
+func NormalizeAzureRules(rules []corecloud.AzureSecurityGroupRule) ([]corecloud.NormalizedSGRule, error) {
+	result := make([]corecloud.NormalizedSGRule, 0, len(rules))
+	for _, one := range rules {
+		rule := corecloud.NormalizedSGRule{
+			RuleID:          one.ID,
+			CloudRuleID:     one.CloudID,
+			SecurityGroupID: one.SecurityGroupID,
+			Vendor:          enumor.Azure,
+			Type:            one.Type,
+			Action:          sgRuleAction(strings.EqualFold(one.Access, "Allow")),
+			Protocol:        normalizeProtocol(one.Protocol),
+			Priority:        int64(one.Priority),
+			Memo:            one.Memo,
+		}
+
+		if hasPort(rule.Protocol) {
+			ports := append([]string{converter.PtrToVal(one.DestinationPortRange)},
+				converter.PtrToSlice(one.DestinationPortRanges)...)
+			portRanges, err := parsePorts(ports...)
+			if err != nil {
+				return nil, fmt.Errorf("azure rule(%s) %v", one.ID, err)
+			}
+			rule.Ports = portRanges
+		}
+
+		// 入方向对端为源地址，出方向对端为目的地址
+		prefix, prefixes, asgIDs := one.SourceAddressPrefix, one.SourceAddressPrefixes,
+			one.CloudSourceAppSecurityGroupIDs
+		if one.Type == enumor.Egress {
+			prefix, prefixes, asgIDs = one.DestinationAddressPrefix, one.DestinationAddressPrefixes,
+				one.CloudDestinationAppSecurityGroupIDs
+		}
+
+		for _, addr := range append([]string{converter.PtrToVal(prefix)}, converter.PtrToSlice(prefixes)...) {
+			addr = normalizeAddress(addr)
+			if len(addr) == 0 {
+				continue
+			}
+
+			if _, exists := azureServiceTags[addr]; exists || addr == addressAny || isCidr(addr) {
+				rule.Addresses = append(rule.Addresses, addr)
+				continue
+			}
+			rule.References = append(rule.References, "service_tag:"+addr)
+		}
+		for _, id := range converter.PtrToSlice(asgIDs) {
+			rule.References = append(rule.References, "application_security_group:"+id)
+		}
+		fillAnyAddress(&rule)
+
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+// NormalizeGcpFirewallRules normalize gcp firewall rules, one firewall rule may be split into several rules by
+// protocol, disabled rules are ignored.
+func NormalizeGcpFirewallRules(rules []corecloud.GcpFirewallRule) ([]corecloud.NormalizedSGRule, error) {
+	result := make([]corecloud.NormalizedSGRule, 0, len(rules))
+	for _, one := range rules {
+		if one.Disabled {
+			continue
+		}
+
+		ruleType := enumor.Ingress
+		addresses := one.SourceRanges
+		references := make([]string, 0)
+		if strings.EqualFold(one.Type, "EGRESS") {
+			ruleType = enumor.Egress
+			addresses = one.DestinationRanges
+		} else {
+			for _, tag := range one.SourceTags {
+				references = append(references, "tag:"+tag)
+			}
+			for _, account := range one.SourceServiceAccounts {
+				references = append(references, "service_account:"+account)
+			}
+		}
+
+		targets := make([]string, 0, len(one.TargetTags)+len(one.TargetServiceAccounts))
+		for _, tag := range one.TargetTags {
+			targets = append(targets, "tag:"+tag)
+		}
+		for _, account := range one.TargetServiceAccounts {
+			targets = append(targets, "service_account:"+account)
+		}
+
+		sets := []struct {
+			allow bool
+			set   []corecloud.GcpProtocolSet
+		}{{allow: true, set: one.Allowed}, {allow: false, set: one.Denied}}
+
+		for _, actionSet := range sets {
+			for _, protocolSet := range actionSet.set {
+				rule := corecloud.NormalizedSGRule{
+					RuleID:      one.ID,
+					CloudRuleID: one.CloudID,
+					Vendor:      enumor.Gcp,
+					Type:        ruleType,
+					Action:      sgRuleAction(actionSet.allow),
+					Protocol:    normalizeProtocol(protocolSet.Protocol),
+					Priority:    one.Priority,
+					References:  references,
+					Targets:     targets,
+					Memo:        converter.ValToPtr(one.Memo),
+				}
+
+				if hasPort(rule.Protocol) {
+					ports, err := parsePorts(protocolSet.Port...)
+					if err != nil {
+						return nil, fmt.Errorf("gcp firewall rule(%s) %v", one.ID, err)
+					}
+					rule.Ports = ports
+				}
+
+				for _, addr := range addresses {
+					rule.Addresses = append(rule.Addresses, normalizeAddress(addr))
+				}
+				fillAnyAddress(&rule)
+
+				result = append(result, rule)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// fillAnyAddress 未指定对端的规则表示任意地址
+func fillAnyAddress(rule *corecloud.NormalizedSGRule) {
+	if len(rule.Addresses) == 0 && len(rule.References) == 0 {
+		rule.Addresses = []string{addressAny}
+	}
+}
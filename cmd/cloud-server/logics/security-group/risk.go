@@ -0,0 +1,647 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"sync"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	coreni "hcm/pkg/api/core/cloud/network-interface"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/serviced"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+	"hcm/pkg/thirdparty/esb"
+	"hcm/pkg/thirdparty/esb/cmdb"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/maps"
+	"hcm/pkg/tools/slice"
+)
+
+// RiskScanner scan the security groups (firewall rules for gcp) of the account by the configured risk rules.
+type RiskScanner interface {
+	Scan(kt *kit.Kit, vendor enumor.Vendor, accountID string) error
+	// Submit 提交账号的扫描任务后立即返回，扫描由 Run 在后台异步执行，不阻塞资源同步流程
+	Submit(kt *kit.Kit, vendor enumor.Vendor, accountID string) error
+	// Run 在后台依次执行提交的扫描任务，只有主节点执行
+	Run(state serviced.State)
+}
+
+type riskScanner struct {
+	sg           *securityGroup
+	esbClient    esb.Client
+	cmsiCli      cmsi.Client
+	ruleChecks   []cc.SGRiskRule
+	groupChecks  []cc.SGRiskRule
+	notice       cc.SGRiskNotice
+	minNoticeLvl int
+	queue        *riskScanQueue
+}
+
+// NewRiskScanner new security group risk scanner.
+func NewRiskScanner(client *client.ClientSet, esbClient esb.Client, cmsiCli cmsi.Client,
+	conf cc.SGRiskScan) RiskScanner {
+	rules := conf.Rules
+	if len(rules) == 0 {
+		rules = defaultRiskRules()
+	}
+
+	scanner := &riskScanner{
+		sg:           &securityGroup{client: client},
+		esbClient:    esbClient,
+		cmsiCli:      cmsiCli,
+		ruleChecks:   make([]cc.SGRiskRule, 0),
+		groupChecks:  make([]cc.SGRiskRule, 0),
+		notice:       conf.Notice,
+		minNoticeLvl: enumor.SGRiskHigh.Level(),
+		queue:        newRiskScanQueue(),
+	}
+	if len(conf.Notice.MinSeverity) != 0 {
+		scanner.minNoticeLvl = conf.Notice.MinSeverity.Level()
+	}
+
+	for _, rule := range rules {
+		switch rule.Type {
+		case enumor.SGRiskUnattached, enumor.SGRiskEmpty:
+			scanner.groupChecks = append(scanner.groupChecks, rule)
+		default:
+			scanner.ruleChecks = append(scanner.ruleChecks, rule)
+		}
+	}
+
+	return scanner
+}
+
+// riskScanTask 账号扫描任务
+type riskScanTask struct {
+	vendor    enumor.Vendor
+	accountID string
+}
+
+// riskScanQueue 待扫描账号队列，同一账号在扫描前多次提交只会扫描一次
+type riskScanQueue struct {
+	lock    sync.Mutex
+	tasks   []riskScanTask
+	pending map[riskScanTask]struct{}
+	// signal 有新任务提交时通知后台执行
+	signal chan struct{}
+}
+
+func newRiskScanQueue() *riskScanQueue {
+	return &riskScanQueue{
+		tasks:   make([]riskScanTask, 0),
+		pending: make(map[riskScanTask]struct{}),
+		signal:  make(chan struct{}, 1),
+	}
+}
+
+// push add the task to the queue, returns false if the task is already waiting to be scanned.
+func (q *riskScanQueue) push(task riskScanTask) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if _, exists := q.pending[task]; exists {
+		return false
+	}
+	q.pending[task] = struct{}{}
+	q.tasks = append(q.tasks, task)
+
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+
+	return true
+}
+
+// pop the first task of the queue, returns false if the queue is empty.
+func (q *riskScanQueue) pop() (riskScanTask, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if len(q.tasks) == 0 {
+		return riskScanTask{}, false
+	}
+
+	task := q.tasks[0]
+	q.tasks = q.tasks[1:]
+	delete(q.pending, task)
+	return task, true
+}
+
+// Submit the account scan task, the scan is executed asynchronously by Run.
+func (r *riskScanner) Submit(kt *kit.Kit, vendor enumor.Vendor, accountID string) error {
+	if !r.queue.push(riskScanTask{vendor: vendor, accountID: accountID}) {
+		logs.Infof("%s account[%s] security group risk scan is already pending, rid: %s", vendor, accountID, kt.Rid)
+	}
+
+	return nil
+}
+
+// Run the submitted scan tasks one by one, the tasks are dropped if current instance is not master.
+func (r *riskScanner) Run(state serviced.State) {
+	for range r.queue.signal {
+		for {
+			task, exists := r.queue.pop()
+			if !exists {
+				break
+			}
+
+			if !state.IsMaster() {
+				continue
+			}
+
+			kt := core.NewBackendKit()
+			if err := r.Scan(kt, task.vendor, task.accountID); err != nil {
+				logs.Errorf("%s account[%s] security group risk scan failed, err: %v, rid: %s", task.vendor,
+					task.accountID, err, kt.Rid)
+			}
+		}
+	}
+}
+
+// Scan the account and replace its findings with the latest result, new findings will be noticed if enabled.
+func (r *riskScanner) Scan(kt *kit.Kit, vendor enumor.Vendor, accountID string) error {
+	var findings []dataproto.SGRiskFindingCreate
+	var err error
+	switch vendor {
+	case enumor.TCloud, enumor.Aws, enumor.HuaWei, enumor.Azure:
+		findings, err = r.scanSecurityGroups(kt, vendor, accountID)
+	case enumor.Gcp:
+		findings, err = r.scanGcpFirewallRules(kt, accountID)
+	default:
+		return errf.Newf(errf.InvalidParameter, "vendor: %s not support security group risk scan", vendor)
+	}
+	if err != nil {
+		return err
+	}
+
+	previous, err := listAll(func(page *core.BasePage) ([]corecloud.SGRiskFinding, error) {
+		req := &core.ListReq{
+			Filter: tools.ExpressionAnd(tools.RuleEqual("vendor", vendor), tools.RuleEqual("account_id", accountID)),
+			Page:   page,
+			Fields: []string{"res_id", "rule_id", "check_name"},
+		}
+		result, err := r.sg.client.DataService().Global.SGRiskFinding.List(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		return result.Details, nil
+	})
+	if err != nil {
+		logs.Errorf("list security group risk findings failed, err: %v, account: %s, rid: %s", err, accountID, kt.Rid)
+		return err
+	}
+
+	replaceReq := &dataproto.SGRiskFindingReplaceReq{Vendor: vendor, AccountID: accountID, Findings: findings}
+	if err = r.sg.client.DataService().Global.SGRiskFinding.Replace(kt, replaceReq); err != nil {
+		logs.Errorf("replace security group risk findings failed, err: %v, account: %s, rid: %s", err, accountID,
+			kt.Rid)
+		return err
+	}
+
+	logs.Infof("%s account[%s] security group risk scan finished, finding count: %d, rid: %s", vendor, accountID,
+		len(findings), kt.Rid)
+
+	if r.notice.Enable {
+		r.noticeNewFindings(kt, previous, findings)
+	}
+
+	return nil
+}
+
+// scanSecurityGroups scan all security groups of the account.
+func (r *riskScanner) scanSecurityGroups(kt *kit.Kit, vendor enumor.Vendor, accountID string) (
+	[]dataproto.SGRiskFindingCreate, error) {
+
+	sgs, err := listAll(func(page *core.BasePage) ([]corecloud.BaseSecurityGroup, error) {
+		req := &dataproto.SecurityGroupListReq{
+			Filter: tools.ExpressionAnd(tools.RuleEqual("vendor", vendor), tools.RuleEqual("account_id", accountID)),
+			Page:   page,
+		}
+		result, err := r.sg.client.DataService().Global.SecurityGroup.ListSecurityGroup(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return nil, err
+		}
+		return result.Details, nil
+	})
+	if err != nil {
+		logs.Errorf("list security group failed, err: %v, account: %s, rid: %s", err, accountID, kt.Rid)
+		return nil, err
+	}
+
+	if len(sgs) == 0 {
+		return make([]dataproto.SGRiskFindingCreate, 0), nil
+	}
+
+	var attached map[string]struct{}
+	if len(r.groupChecks) != 0 {
+		sgIDs := slice.Map(sgs, func(sg corecloud.BaseSecurityGroup) string { return sg.ID })
+		if attached, err = r.listAttachedSGIDs(kt, vendor, accountID, sgIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	findings := make([]dataproto.SGRiskFindingCreate, 0)
+	for _, sg := range sgs {
+		normalized, err := r.sg.listNormalizedRules(kt, vendor, sg.ID)
+		if err != nil {
+			// 单个安全组规则解析失败不影响其他安全组的扫描
+			logs.Errorf("normalize security group rules failed, err: %v, sg: %s, rid: %s", err, sg.ID, kt.Rid)
+			continue
+		}
+
+		newFinding := func(check cc.SGRiskRule, rule *corecloud.NormalizedSGRule,
+			description string) dataproto.SGRiskFindingCreate {
+
+			finding := dataproto.SGRiskFindingCreate{
+				Vendor:      vendor,
+				AccountID:   accountID,
+				BkBizID:     sg.BkBizID,
+				Region:      sg.Region,
+				ResType:     enumor.SecurityGroupCloudResType,
+				ResID:       sg.ID,
+				CloudResID:  sg.CloudID,
+				ResName:     sg.Name,
+				CheckName:   check.Name,
+				CheckType:   check.Type,
+				Severity:    check.Severity,
+				Description: description,
+			}
+			if rule != nil {
+				finding.RuleID = rule.RuleID
+				finding.CloudRuleID = rule.CloudRuleID
+			}
+			return finding
+		}
+
+		for _, check := range r.groupChecks {
+			switch check.Type {
+			case enumor.SGRiskUnattached:
+				if _, exists := attached[sg.ID]; !exists {
+					findings = append(findings, newFinding(check, nil,
+						"security group is not attached to any synced resource"))
+				}
+			case enumor.SGRiskEmpty:
+				if len(normalized) == 0 {
+					findings = append(findings, newFinding(check, nil, "security group has no rules"))
+				}
+			}
+		}
+
+		p := sgRiskPolicy(vendor, sg.ID, normalized)
+		for _, risk := range checkRuleRisks(r.ruleChecks, p.analyze().Ingress) {
+			findings = append(findings, newFinding(risk.check, &risk.rule, risk.description))
+		}
+	}
+
+	return findings, nil
+}
+
+// sgRiskPolicy build the policy of single security group to find out the shadowed rules, the rules are ordered by
+// the vendor's matching semantics.
+func sgRiskPolicy(vendor enumor.Vendor, sgID string, rules []corecloud.NormalizedSGRule) *policy {
+	mode := firstMatch
+	if vendor == enumor.Aws {
+		mode = anyAllow
+	}
+
+	p := newPolicy(enumor.SecurityGroupCloudResType, sgID, mode)
+	p.securityGroupIDs = append(p.securityGroupIDs, sgID)
+
+	switch vendor {
+	case enumor.TCloud:
+		sorted := append([]corecloud.NormalizedSGRule(nil), rules...)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Priority < sorted[j].Priority })
+		p.addRules(sorted)
+	case enumor.HuaWei, enumor.Azure:
+		p.addRules(rules)
+		p.sortByPriority()
+	default:
+		p.addRules(rules)
+	}
+
+	return p
+}
+
+// listAttachedSGIDs list the security groups which are attached to cvm, load balancer, or azure network interface
+// and subnet.
+func (r *riskScanner) listAttachedSGIDs(kt *kit.Kit, vendor enumor.Vendor, accountID string, sgIDs []string) (
+	map[string]struct{}, error) {
+
+	attached := make(map[string]struct{})
+	for _, batch := range slice.Split(sgIDs, int(core.DefaultMaxPageLimit)) {
+		cvmRels, err := listAll(func(page *core.BasePage) ([]corecloud.SecurityGroupCvmRel, error) {
+			req := &core.ListReq{Filter: tools.ContainersExpression("security_group_id", batch), Page: page,
+				Fields: []string{"security_group_id"}}
+			result, err := r.sg.client.DataService().Global.SGCvmRel.List(kt.Ctx, kt.Header(), req)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		})
+		if err != nil {
+			logs.Errorf("list security group cvm rels failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+		for _, rel := range cvmRels {
+			attached[rel.SecurityGroupID] = struct{}{}
+		}
+
+		commonRels, err := listAll(func(page *core.BasePage) ([]corecloud.SecurityGroupCommonRel, error) {
+			req := &core.ListReq{Filter: tools.ContainersExpression("security_group_id", batch), Page: page,
+				Fields: []string{"security_group_id"}}
+			result, err := r.sg.client.DataService().Global.SGCommonRel.List(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		})
+		if err != nil {
+			logs.Errorf("list security group common rels failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+		for _, rel := range commonRels {
+			attached[rel.SecurityGroupID] = struct{}{}
+		}
+	}
+
+	if vendor != enumor.Azure {
+		return attached, nil
+	}
+
+	// azure网络安全组关联在网络接口和子网上，关联关系记录在其扩展字段中
+	nis, err := listAll(func(page *core.BasePage) ([]coreni.NetworkInterface[coreni.AzureNIExtension], error) {
+		req := &core.ListReq{Filter: tools.EqualExpression("account_id", accountID), Page: page}
+		result, err := r.sg.client.DataService().Azure.NetworkInterface.ListNetworkInterfaceExt(kt.Ctx, kt.Header(),
+			req)
+		if err != nil {
+			return nil, err
+		}
+		return result.Details, nil
+	})
+	if err != nil {
+		logs.Errorf("list azure network interface failed, err: %v, account: %s, rid: %s", err, accountID, kt.Rid)
+		return nil, err
+	}
+	for _, ni := range nis {
+		if ni.Extension != nil && len(converter.PtrToVal(ni.Extension.SecurityGroupID)) != 0 {
+			attached[*ni.Extension.SecurityGroupID] = struct{}{}
+		}
+	}
+
+	subnets, err := listAll(func(page *core.BasePage) ([]corecloud.Subnet[corecloud.AzureSubnetExtension], error) {
+		req := &core.ListReq{Filter: tools.EqualExpression("account_id", accountID), Page: page}
+		result, err := r.sg.client.DataService().Azure.Subnet.ListSubnetExt(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return nil, err
+		}
+		return result.Details, nil
+	})
+	if err != nil {
+		logs.Errorf("list azure subnet failed, err: %v, account: %s, rid: %s", err, accountID, kt.Rid)
+		return nil, err
+	}
+	for _, subnet := range subnets {
+		if subnet.Extension != nil && len(subnet.Extension.SecurityGroupID) != 0 {
+			attached[subnet.Extension.SecurityGroupID] = struct{}{}
+		}
+	}
+
+	return attached, nil
+}
+
+// scanGcpFirewallRules gcp没有安全组，防火墙规则作用于整个vpc，只做规则级别的检查。
+// 规则可能通过目标标记作用于不同的实例，因此每条规则单独检查，不分析规则之间的覆盖关系。
+func (r *riskScanner) scanGcpFirewallRules(kt *kit.Kit, accountID string) ([]dataproto.SGRiskFindingCreate, error) {
+	rules, err := listAll(func(page *core.BasePage) ([]corecloud.GcpFirewallRule, error) {
+		req := &dataproto.GcpFirewallRuleListReq{Filter: tools.EqualExpression("account_id", accountID), Page: page}
+		result, err := r.sg.client.DataService().Gcp.Firewall.ListFirewallRule(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return nil, err
+		}
+		return result.Details, nil
+	})
+	if err != nil {
+		logs.Errorf("list gcp firewall rule failed, err: %v, account: %s, rid: %s", err, accountID, kt.Rid)
+		return nil, err
+	}
+
+	findings := make([]dataproto.SGRiskFindingCreate, 0)
+	for _, one := range rules {
+		normalized, err := NormalizeGcpFirewallRules([]corecloud.GcpFirewallRule{one})
+		if err != nil {
+			logs.Errorf("normalize gcp firewall rule failed, err: %v, rule: %s, rid: %s", err, one.ID, kt.Rid)
+			continue
+		}
+
+		analyzed := make([]corecloud.AnalyzedSGRule, 0, len(normalized))
+		for _, rule := range normalized {
+			analyzed = append(analyzed, corecloud.AnalyzedSGRule{NormalizedSGRule: rule, Status: enumor.SGRuleEffective})
+		}
+
+		// 一条防火墙规则可能包含多个协议，同一个检查项只记录一次
+		hitChecks := make(map[string]struct{})
+		for _, risk := range checkRuleRisks(r.ruleChecks, analyzed) {
+			if _, exists := hitChecks[risk.check.Name]; exists {
+				continue
+			}
+			hitChecks[risk.check.Name] = struct{}{}
+
+			findings = append(findings, dataproto.SGRiskFindingCreate{
+				Vendor:      enumor.Gcp,
+				AccountID:   accountID,
+				BkBizID:     one.BkBizID,
+				ResType:     enumor.GcpFirewallRuleCloudResType,
+				ResID:       one.ID,
+				CloudResID:  one.CloudID,
+				ResName:     one.Name,
+				RuleID:      one.ID,
+				CloudRuleID: one.CloudID,
+				CheckName:   risk.check.Name,
+				CheckType:   risk.check.Type,
+				Severity:    risk.check.Severity,
+				Description: risk.description,
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+func riskFindingKey(resID, ruleID, checkName string) string {
+	return resID + "/" + ruleID + "/" + checkName
+}
+
+// noticeNewFindings send the findings not found in the previous scan to the biz maintainers and the configured
+// receivers by mail, one mail per biz, notice failure does not affect scan.
+func (r *riskScanner) noticeNewFindings(kt *kit.Kit, previous []corecloud.SGRiskFinding,
+	findings []dataproto.SGRiskFindingCreate) {
+
+	existing := make(map[string]struct{}, len(previous))
+	for _, one := range previous {
+		existing[riskFindingKey(one.ResID, one.RuleID, one.CheckName)] = struct{}{}
+	}
+
+	newFindings := make([]dataproto.SGRiskFindingCreate, 0)
+	for _, one := range findings {
+		if one.Severity.Level() < r.minNoticeLvl {
+			continue
+		}
+
+		if _, exists := existing[riskFindingKey(one.ResID, one.RuleID, one.CheckName)]; exists {
+			continue
+		}
+		newFindings = append(newFindings, one)
+	}
+
+	bizFindings := groupRiskFindingsByBiz(newFindings)
+	if len(bizFindings) == 0 {
+		return
+	}
+
+	bizIDs := slice.Filter(maps.Keys(bizFindings), func(bizID int64) bool { return bizID > 0 })
+	bizs, err := r.getBizs(kt, bizIDs)
+	if err != nil {
+		// 业务信息查询失败时仍然通知配置的接收人
+		logs.Errorf("get bizs of security group risk findings failed, err: %v, rid: %s", err, kt.Rid)
+		bizs = make(map[int64]cmdb.Biz)
+	}
+
+	for bizID, one := range bizFindings {
+		biz, exists := bizs[bizID]
+		if !exists {
+			biz = cmdb.Biz{BizID: bizID, BizName: "未分配"}
+		}
+
+		receivers := make([]string, 0)
+		if len(biz.BizMaintainer) != 0 {
+			receivers = append(receivers, strings.Split(biz.BizMaintainer, ",")...)
+		}
+		receivers = slice.Unique(append(receivers, r.notice.Receivers...))
+		if len(receivers) == 0 {
+			logs.Infof("biz %d has no receivers of security group risk notice, skip, rid: %s", bizID, kt.Rid)
+			continue
+		}
+
+		mail := &cmsi.CmsiMail{
+			Receiver: strings.Join(receivers, ","),
+			Title:    fmt.Sprintf(sgRiskMailTitle, biz.BizName, len(one)),
+			Content:  renderSGRiskMail(biz.BizName, one),
+		}
+		if err = r.cmsiCli.SendMail(kt, mail); err != nil {
+			logs.Errorf("send biz %d security group risk notice mail failed, err: %v, rid: %s", bizID, err, kt.Rid)
+		}
+	}
+}
+
+// unassignedBizID 未分配业务的风险的业务ID
+const unassignedBizID int64 = -1
+
+// groupRiskFindingsByBiz 按业务聚合风险，未分配业务的风险聚合在一起，业务内按风险等级从高到低排序
+func groupRiskFindingsByBiz(findings []dataproto.SGRiskFindingCreate) map[int64][]dataproto.SGRiskFindingCreate {
+	result := make(map[int64][]dataproto.SGRiskFindingCreate)
+	for _, one := range findings {
+		bizID := one.BkBizID
+		if bizID <= 0 {
+			bizID = unassignedBizID
+		}
+		result[bizID] = append(result[bizID], one)
+	}
+
+	for _, one := range result {
+		sort.SliceStable(one, func(i, j int) bool { return one[i].Severity.Level() > one[j].Severity.Level() })
+	}
+
+	return result
+}
+
+// getBizs get biz info from cmdb, returns map[bizID]biz.
+func (r *riskScanner) getBizs(kt *kit.Kit, bizIDs []int64) (map[int64]cmdb.Biz, error) {
+	bizs := make(map[int64]cmdb.Biz, len(bizIDs))
+	if len(bizIDs) == 0 {
+		return bizs, nil
+	}
+
+	params := &cmdb.SearchBizParams{
+		Fields: []string{"bk_biz_id", "bk_biz_name", "bk_biz_maintainer"},
+		Page:   cmdb.BasePage{Limit: int64(len(bizIDs))},
+		BizPropertyFilter: &cmdb.QueryFilter{
+			Rule: &cmdb.CombinedRule{
+				Condition: "AND",
+				Rules: []cmdb.Rule{
+					&cmdb.AtomRule{Field: cmdb.BizIDField, Operator: cmdb.OperatorIn, Value: bizIDs},
+				},
+			},
+		},
+	}
+	result, err := r.esbClient.Cmdb().SearchBusiness(kt, params)
+	if err != nil {
+		logs.Errorf("search cmdb business failed, err: %v, biz ids: %v, rid: %s", err, bizIDs, kt.Rid)
+		return nil, err
+	}
+
+	for _, biz := range result.Info {
+		bizs[biz.BizID] = biz
+	}
+
+	return bizs, nil
+}
+
+const (
+	sgRiskMailTitle    = "HCM业务[%s]安全组风险扫描发现%d个新风险"
+	sgRiskMailTemplate = `<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8" />
+    <title>HCM安全组风险扫描</title>
+  </head>
+  <body>
+    <p>尊敬的用户您好！业务[%s]安全组风险扫描发现以下新风险，请及时处理：</p>
+    <table border="1" cellspacing="0" cellpadding="4">
+      <tr><th>云厂商</th><th>地域</th><th>资源</th><th>规则ID</th><th>检查项</th><th>风险等级</th><th>描述</th></tr>
+%s
+    </table>
+  </body>
+</html>`
+)
+
+func renderSGRiskMail(bizName string, findings []dataproto.SGRiskFindingCreate) string {
+	rows := make([]string, 0, len(findings))
+	for _, one := range findings {
+		rows = append(rows, fmt.Sprintf("      <tr><td>%s</td><td>%s</td><td>%s(%s)</td><td>%s</td>"+
+			"<td>%s</td><td>%s</td><td>%s</td></tr>", one.Vendor, html.EscapeString(one.Region),
+			html.EscapeString(one.ResName), html.EscapeString(one.CloudResID), html.EscapeString(one.CloudRuleID),
+			html.EscapeString(one.CheckName), one.Severity, html.EscapeString(one.Description)))
+	}
+
+	return fmt.Sprintf(sgRiskMailTemplate, html.EscapeString(bizName), strings.Join(rows, "\n"))
+}
@@ -0,0 +1,159 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+)
+
+// defaultRiskRules 未配置扫描规则时使用的内置规则
+func defaultRiskRules() []cc.SGRiskRule {
+	return []cc.SGRiskRule{
+		{Name: "public_ssh", Type: enumor.SGRiskPublicPort, Severity: enumor.SGRiskHigh, Protocol: "tcp",
+			Ports: []int64{22}},
+		{Name: "public_rdp", Type: enumor.SGRiskPublicPort, Severity: enumor.SGRiskHigh, Protocol: "tcp",
+			Ports: []int64{3389}},
+		{Name: "public_database", Type: enumor.SGRiskPublicPort, Severity: enumor.SGRiskHigh, Protocol: "tcp",
+			Ports: []int64{1433, 1521, 3306, 5432, 6379, 9200, 11211, 27017}},
+		{Name: "wide_port_range", Type: enumor.SGRiskWidePortRange, Severity: enumor.SGRiskMedium,
+			MaxPortCount: 1000},
+		{Name: "unattached", Type: enumor.SGRiskUnattached, Severity: enumor.SGRiskLow},
+		{Name: "empty", Type: enumor.SGRiskEmpty, Severity: enumor.SGRiskLow},
+	}
+}
+
+// ruleRisk is the risk found on one security group rule.
+type ruleRisk struct {
+	check       cc.SGRiskRule
+	rule        corecloud.NormalizedSGRule
+	description string
+}
+
+// checkRuleRisks check the ingress rules by the rule level checks, shadowed rules will never be hit, so they are
+// skipped.
+func checkRuleRisks(checks []cc.SGRiskRule, rules []corecloud.AnalyzedSGRule) []ruleRisk {
+	risks := make([]ruleRisk, 0)
+	for _, rule := range rules {
+		if rule.Type != enumor.Ingress || rule.Action != enumor.SGRuleAllow || rule.Status == enumor.SGRuleShadowed {
+			continue
+		}
+
+		// 无法解析的协议端口模版不做检查
+		if strings.HasPrefix(rule.Protocol, templateProtocolPrefix) {
+			continue
+		}
+
+		for _, check := range checks {
+			var description string
+			var hit bool
+			switch check.Type {
+			case enumor.SGRiskPublicPort:
+				description, hit = checkPublicPort(check, rule.NormalizedSGRule)
+			case enumor.SGRiskWidePortRange:
+				description, hit = checkWidePortRange(check, rule.NormalizedSGRule)
+			}
+
+			if hit {
+				risks = append(risks, ruleRisk{check: check, rule: rule.NormalizedSGRule, description: description})
+			}
+		}
+	}
+
+	return risks
+}
+
+// checkPublicPort check whether the rule opens the sensitive ports to the internet.
+func checkPublicPort(check cc.SGRiskRule, rule corecloud.NormalizedSGRule) (string, bool) {
+	publicAddrs := publicAddresses(rule)
+	if len(publicAddrs) == 0 {
+		return "", false
+	}
+
+	protocol := "tcp"
+	if len(check.Protocol) != 0 {
+		protocol = normalizeProtocol(check.Protocol)
+	}
+
+	opened := make([]string, 0)
+	for _, port := range check.Ports {
+		if matchProtocolPort(rule, protocol, port) {
+			opened = append(opened, strconv.FormatInt(port, 10))
+		}
+	}
+
+	if len(opened) == 0 {
+		return "", false
+	}
+
+	return fmt.Sprintf("allow %s to access %s port %s", strings.Join(publicAddrs, ","), protocol,
+		strings.Join(opened, ",")), true
+}
+
+// checkWidePortRange check whether the count of ports opened by the rule exceeds the limit, rules only reference
+// other security groups are not checked.
+func checkWidePortRange(check cc.SGRiskRule, rule corecloud.NormalizedSGRule) (string, bool) {
+	if len(rule.Addresses) == 0 {
+		return "", false
+	}
+
+	if rule.Protocol != protocolAll && !hasPort(rule.Protocol) {
+		return "", false
+	}
+
+	count := portCount(rule.Ports)
+	if count <= check.MaxPortCount {
+		return "", false
+	}
+
+	return fmt.Sprintf("%d ports are opened by the rule, exceeds the limit %d", count, check.MaxPortCount), true
+}
+
+// publicAddresses return the internet addresses of the rule.
+func publicAddresses(rule corecloud.NormalizedSGRule) []string {
+	addrs := make([]string, 0)
+	for _, addr := range rule.Addresses {
+		switch addr {
+		case addressAny, "0.0.0.0/0", "::/0", azureTagInternet:
+			addrs = append(addrs, addr)
+		}
+	}
+
+	return addrs
+}
+
+// portCount count the ports, empty ranges means all ports.
+func portCount(ranges []corecloud.PortRange) int64 {
+	if len(ranges) == 0 {
+		return maxPort
+	}
+
+	var count int64
+	for _, one := range ranges {
+		count += one.To - one.From + 1
+	}
+
+	return count
+}
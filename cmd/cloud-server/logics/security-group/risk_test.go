@@ -0,0 +1,123 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"testing"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/converter"
+)
+
+func TestCheckRuleRisks(t *testing.T) {
+	rules, err := NormalizeTCloudRules([]corecloud.TCloudSecurityGroupRule{
+		{ID: "1", CloudPolicyIndex: 0, Protocol: converter.ValToPtr("TCP"), Port: converter.ValToPtr("22"),
+			IPv4Cidr: converter.ValToPtr("0.0.0.0/0"), Action: "ACCEPT", Type: enumor.Ingress},
+		{ID: "2", CloudPolicyIndex: 1, Protocol: converter.ValToPtr("TCP"), Port: converter.ValToPtr("3389"),
+			IPv4Cidr: converter.ValToPtr("0.0.0.0/0"), Action: "DROP", Type: enumor.Ingress},
+		{ID: "3", CloudPolicyIndex: 2, Protocol: converter.ValToPtr("TCP"), Port: converter.ValToPtr("3389"),
+			IPv4Cidr: converter.ValToPtr("0.0.0.0/0"), Action: "ACCEPT", Type: enumor.Ingress},
+		{ID: "4", CloudPolicyIndex: 3, Protocol: converter.ValToPtr("UDP"), Port: converter.ValToPtr("1-5000"),
+			IPv4Cidr: converter.ValToPtr("10.0.0.0/8"), Action: "ACCEPT", Type: enumor.Ingress},
+		{ID: "5", CloudPolicyIndex: 4, Protocol: converter.ValToPtr("TCP"), Port: converter.ValToPtr("3306"),
+			IPv4Cidr: converter.ValToPtr("10.0.0.0/8"), Action: "ACCEPT", Type: enumor.Ingress},
+	})
+	if err != nil {
+		t.Fatalf("normalize tcloud rules failed, err: %v", err)
+	}
+
+	p := newPolicy(enumor.SecurityGroupCloudResType, "sg", firstMatch)
+	p.addRules(rules)
+	risks := checkRuleRisks(defaultRiskRules(), p.analyze().Ingress)
+
+	expects := map[string]string{"1": "public_ssh", "4": "wide_port_range"}
+	if len(risks) != len(expects) {
+		t.Fatalf("got %d risks, expect: %d, risks: %+v", len(risks), len(expects), risks)
+	}
+	for _, one := range risks {
+		if expects[one.rule.RuleID] != one.check.Name {
+			t.Errorf("rule %s got risk: %s, expect: %s", one.rule.RuleID, one.check.Name, expects[one.rule.RuleID])
+		}
+	}
+}
+
+func TestRiskScanQueue(t *testing.T) {
+	queue := newRiskScanQueue()
+
+	first := riskScanTask{vendor: enumor.TCloud, accountID: "a1"}
+	second := riskScanTask{vendor: enumor.Aws, accountID: "a2"}
+	if !queue.push(first) || !queue.push(second) {
+		t.Fatalf("push new task should succeed")
+	}
+
+	// 等待扫描的账号再次提交时不重复扫描
+	if queue.push(first) {
+		t.Errorf("push pending task should be skipped")
+	}
+
+	for _, expect := range []riskScanTask{first, second} {
+		task, exists := queue.pop()
+		if !exists || task != expect {
+			t.Errorf("expect pop task: %+v, got: %+v, exists: %v", expect, task, exists)
+		}
+	}
+	if _, exists := queue.pop(); exists {
+		t.Errorf("queue should be empty")
+	}
+
+	// 已经开始扫描的账号可以再次提交
+	if !queue.push(first) {
+		t.Errorf("push task after popped should succeed")
+	}
+}
+
+func TestGroupRiskFindingsByBiz(t *testing.T) {
+	findings := []dataproto.SGRiskFindingCreate{
+		{BkBizID: 1, ResID: "sg-1", Severity: enumor.SGRiskMedium},
+		{BkBizID: 2, ResID: "sg-2", Severity: enumor.SGRiskHigh},
+		{BkBizID: 1, ResID: "sg-3", Severity: enumor.SGRiskHigh},
+		{BkBizID: -1, ResID: "sg-4", Severity: enumor.SGRiskHigh},
+		{BkBizID: 0, ResID: "sg-5", Severity: enumor.SGRiskHigh},
+	}
+
+	result := groupRiskFindingsByBiz(findings)
+	expects := map[int64][]string{1: {"sg-3", "sg-1"}, 2: {"sg-2"}, unassignedBizID: {"sg-4", "sg-5"}}
+	if len(result) != len(expects) {
+		t.Fatalf("expect %d bizs, got: %d", len(expects), len(result))
+	}
+	for bizID, resIDs := range expects {
+		got := make([]string, 0, len(result[bizID]))
+		for _, one := range result[bizID] {
+			got = append(got, one.ResID)
+		}
+		if len(got) != len(resIDs) {
+			t.Errorf("biz %d expect findings: %v, got: %v", bizID, resIDs, got)
+			continue
+		}
+		for i := range resIDs {
+			if got[i] != resIDs[i] {
+				t.Errorf("biz %d expect findings: %v, got: %v", bizID, resIDs, got)
+				break
+			}
+		}
+	}
+}
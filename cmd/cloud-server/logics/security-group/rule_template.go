@@ -0,0 +1,515 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	hcproto "hcm/pkg/api/hc-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+)
+
+const (
+	// huaweiMaxPriority 华为云安全组规则优先级取值范围为1-100，值越小优先级越高
+	huaweiMaxPriority = 100
+	// gcpTplBasePriority gcp防火墙规则默认优先级为1000
+	gcpTplBasePriority = 1000
+)
+
+// SGRuleTplTarget define the target which the security group rule template is compiled for.
+type SGRuleTplTarget struct {
+	TemplateID      string
+	TemplateVersion uint64
+	Vendor          enumor.Vendor
+	AccountID       string
+	BkBizID         int64
+	// ResID 安全组ID，gcp为vpc ID
+	ResID string
+	// CloudResID 安全组云上ID，gcp为vpc云上ID
+	CloudResID    string
+	GcpTargetTags []string
+}
+
+// CompiledSGRules define the vendor rule create requests compiled from security group rule template.
+type CompiledSGRules struct {
+	TCloud []*hcproto.TCloudSGRuleCreateReq
+	Aws    []*hcproto.AwsSGRuleCreateReq
+	HuaWei []*hcproto.HuaWeiSGRuleCreateReq
+	Azure  []*hcproto.AzureSGRuleCreateReq
+	Gcp    []*hcproto.GcpFirewallRuleCreateReq
+}
+
+// CompileRuleTemplate compile vendor neutral template rules into the vendor rule create requests of the target.
+func (s *securityGroup) CompileRuleTemplate(kt *kit.Kit, target *SGRuleTplTarget,
+	rules []corecloud.SGTemplateRule) (*CompiledSGRules, error) {
+
+	switch target.Vendor {
+	case enumor.TCloud:
+		return &CompiledSGRules{TCloud: compileTCloudRules(target, rules)}, nil
+
+	case enumor.Aws:
+		reqs, err := compileAwsRules(target, rules)
+		if err != nil {
+			return nil, err
+		}
+		return &CompiledSGRules{Aws: reqs}, nil
+
+	case enumor.HuaWei:
+		return &CompiledSGRules{HuaWei: compileHuaWeiRules(target, rules)}, nil
+
+	case enumor.Azure:
+		// azure安全组规则的优先级不能重复，由下发任务在执行时按已有规则分配，使模版规则排在已有规则之后
+		return &CompiledSGRules{Azure: compileAzureRules(target, rules)}, nil
+
+	case enumor.Gcp:
+		return &CompiledSGRules{Gcp: compileGcpRules(target, rules)}, nil
+
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support security group rule template",
+			target.Vendor)
+	}
+}
+
+func compileTCloudRules(target *SGRuleTplTarget, rules []corecloud.SGTemplateRule) []*hcproto.TCloudSGRuleCreateReq {
+	ingress := &hcproto.TCloudSGRuleCreateReq{AccountID: target.AccountID}
+	egress := &hcproto.TCloudSGRuleCreateReq{AccountID: target.AccountID}
+	for _, rule := range rules {
+		action := "ACCEPT"
+		if rule.Action == enumor.SGRuleDeny {
+			action = "DROP"
+		}
+
+		for _, addr := range rule.Addresses {
+			for _, port := range templatePorts(rule, "ALL") {
+				one := hcproto.TCloudSGRuleCreate{
+					Protocol: converter.ValToPtr(strings.ToUpper(rule.Protocol)),
+					Port:     converter.ValToPtr(port),
+					Action:   action,
+					Memo:     rule.Memo,
+				}
+				if isIPv6(addr) {
+					one.IPv6Cidr = converter.ValToPtr(addr)
+				} else {
+					one.IPv4Cidr = converter.ValToPtr(addr)
+				}
+
+				if rule.Type == enumor.Egress {
+					egress.EgressRuleSet = append(egress.EgressRuleSet, one)
+				} else {
+					ingress.IngressRuleSet = append(ingress.IngressRuleSet, one)
+				}
+			}
+		}
+	}
+
+	return nonEmptyReqs(ingress, len(ingress.IngressRuleSet), egress, len(egress.EgressRuleSet))
+}
+
+func compileAwsRules(target *SGRuleTplTarget, rules []corecloud.SGTemplateRule) ([]*hcproto.AwsSGRuleCreateReq,
+	error) {
+
+	ingress := &hcproto.AwsSGRuleCreateReq{AccountID: target.AccountID}
+	egress := &hcproto.AwsSGRuleCreateReq{AccountID: target.AccountID}
+	for idx, rule := range rules {
+		if rule.Action == enumor.SGRuleDeny {
+			return nil, errf.Newf(errf.InvalidParameter, "rules[%d] is deny rule, aws security group only supports "+
+				"allow rule", idx)
+		}
+
+		protocol := rule.Protocol
+		if protocol == protocolAll {
+			protocol = "-1"
+		}
+
+		for _, addr := range rule.Addresses {
+			for _, port := range templatePorts(rule, "") {
+				one := hcproto.AwsSGRuleCreate{
+					Protocol: converter.ValToPtr(protocol),
+					Memo:     rule.Memo,
+				}
+
+				switch {
+				case rule.Protocol == "icmp":
+					one.FromPort, one.ToPort = converter.ValToPtr(int64(-1)), converter.ValToPtr(int64(-1))
+				case hasPort(rule.Protocol) && len(port) == 0:
+					one.FromPort, one.ToPort = converter.ValToPtr(int64(minPort)), converter.ValToPtr(int64(maxPort))
+				case hasPort(rule.Protocol):
+					from, to, _ := corecloud.ParseSGTemplatePort(port)
+					one.FromPort, one.ToPort = converter.ValToPtr(from), converter.ValToPtr(to)
+				}
+
+				if isIPv6(addr) {
+					one.IPv6Cidr = converter.ValToPtr(toCidr(addr))
+				} else {
+					one.IPv4Cidr = converter.ValToPtr(toCidr(addr))
+				}
+
+				if rule.Type == enumor.Egress {
+					egress.EgressRuleSet = append(egress.EgressRuleSet, one)
+				} else {
+					ingress.IngressRuleSet = append(ingress.IngressRuleSet, one)
+				}
+			}
+		}
+	}
+
+	return nonEmptyReqs(ingress, len(ingress.IngressRuleSet), egress, len(egress.EgressRuleSet)), nil
+}
+
+func compileHuaWeiRules(target *SGRuleTplTarget, rules []corecloud.SGTemplateRule) []*hcproto.HuaWeiSGRuleCreateReq {
+	reqs := make([]*hcproto.HuaWeiSGRuleCreateReq, 0)
+	for idx, rule := range rules {
+		// 模版规则的顺序即为匹配顺序，超出华为云优先级范围的规则使用最低优先级
+		priority := int64(idx + 1)
+		if priority > huaweiMaxPriority {
+			priority = huaweiMaxPriority
+		}
+
+		var protocol *string
+		if rule.Protocol != protocolAll {
+			protocol = converter.ValToPtr(rule.Protocol)
+		}
+
+		for _, addr := range rule.Addresses {
+			for _, port := range templatePorts(rule, "") {
+				ethertype := "IPv4"
+				if isIPv6(addr) {
+					ethertype = "IPv6"
+				}
+
+				one := &hcproto.HuaWeiSGRuleCreate{
+					Memo:           rule.Memo,
+					Ethertype:      converter.ValToPtr(ethertype),
+					Protocol:       protocol,
+					RemoteIPPrefix: converter.ValToPtr(toCidr(addr)),
+					Action:         converter.ValToPtr(string(rule.Action)),
+					Priority:       priority,
+				}
+				if len(port) != 0 {
+					one.Port = converter.ValToPtr(port)
+				}
+
+				req := &hcproto.HuaWeiSGRuleCreateReq{AccountID: target.AccountID}
+				if rule.Type == enumor.Egress {
+					req.EgressRule = one
+				} else {
+					req.IngressRule = one
+				}
+				reqs = append(reqs, req)
+			}
+		}
+	}
+
+	return reqs
+}
+
+func compileAzureRules(target *SGRuleTplTarget, rules []corecloud.SGTemplateRule) []*hcproto.AzureSGRuleCreateReq {
+	ingress := &hcproto.AzureSGRuleCreateReq{AccountID: target.AccountID}
+	egress := &hcproto.AzureSGRuleCreateReq{AccountID: target.AccountID}
+
+	for idx, rule := range rules {
+		protocol := "*"
+		if rule.Protocol != protocolAll {
+			protocol = strings.ToUpper(rule.Protocol[:1]) + rule.Protocol[1:]
+		}
+
+		access := "Allow"
+		if rule.Action == enumor.SGRuleDeny {
+			access = "Deny"
+		}
+
+		// azure同一条规则中的地址必须属于同一地址族
+		for familyIdx, addrs := range splitAddressFamily(rule.Addresses) {
+			one := hcproto.AzureSGRuleCreate{
+				Name: fmt.Sprintf("hcm-tpl-%s-v%d-%d-%d", target.TemplateID, target.TemplateVersion, idx,
+					familyIdx),
+				Memo:            rule.Memo,
+				Protocol:        protocol,
+				SourcePortRange: converter.ValToPtr("*"),
+				Type:            rule.Type,
+				Access:          access,
+			}
+
+			ports := templatePorts(rule, "*")
+			if len(ports) == 1 {
+				one.DestinationPortRange = converter.ValToPtr(ports[0])
+			} else {
+				one.DestinationPortRanges = converter.SliceToPtr(ports)
+			}
+
+			peer, peers := converter.ValToPtr(addrs[0]), []*string(nil)
+			if len(addrs) > 1 {
+				peer, peers = nil, converter.SliceToPtr(addrs)
+			}
+
+			if rule.Type == enumor.Egress {
+				one.SourceAddressPrefix = converter.ValToPtr("*")
+				one.DestinationAddressPrefix, one.DestinationAddressPrefixes = peer, peers
+				egress.EgressRuleSet = append(egress.EgressRuleSet, one)
+			} else {
+				one.DestinationAddressPrefix = converter.ValToPtr("*")
+				one.SourceAddressPrefix, one.SourceAddressPrefixes = peer, peers
+				ingress.IngressRuleSet = append(ingress.IngressRuleSet, one)
+			}
+		}
+	}
+
+	return nonEmptyReqs(ingress, len(ingress.IngressRuleSet), egress, len(egress.EgressRuleSet))
+}
+
+func compileGcpRules(target *SGRuleTplTarget, rules []corecloud.SGTemplateRule) []*hcproto.GcpFirewallRuleCreateReq {
+	reqs := make([]*hcproto.GcpFirewallRuleCreateReq, 0, len(rules))
+	for idx, rule := range rules {
+		req := &hcproto.GcpFirewallRuleCreateReq{
+			BkBizID:    target.BkBizID,
+			AccountID:  target.AccountID,
+			CloudVpcID: target.CloudResID,
+			Type:       strings.ToUpper(string(rule.Type)),
+			// gcp防火墙规则名称在项目内唯一，带上vpc和模版版本避免重复下发时冲突
+			Name: fmt.Sprintf("hcm-tpl-%s-v%d-%s-%d", target.TemplateID, target.TemplateVersion, target.ResID,
+				idx),
+			Priority:   int64(gcpTplBasePriority + idx),
+			Memo:       converter.PtrToVal(rule.Memo),
+			TargetTags: target.GcpTargetTags,
+		}
+
+		protocolSet := []corecloud.GcpProtocolSet{{Protocol: rule.Protocol, Port: rule.Ports}}
+		if rule.Action == enumor.SGRuleDeny {
+			req.Denied = protocolSet
+		} else {
+			req.Allowed = protocolSet
+		}
+
+		addrs := make([]string, 0, len(rule.Addresses))
+		for _, addr := range rule.Addresses {
+			addrs = append(addrs, toCidr(addr))
+		}
+		if rule.Type == enumor.Egress {
+			req.DestinationRanges = addrs
+		} else {
+			req.SourceRanges = addrs
+		}
+
+		reqs = append(reqs, req)
+	}
+
+	return reqs
+}
+
+// templatePorts return ports of the template rule, one port expression for each vendor rule, allPorts is used when
+// the rule matches all ports.
+func templatePorts(rule corecloud.SGTemplateRule, allPorts string) []string {
+	if len(rule.Ports) == 0 {
+		return []string{allPorts}
+	}
+
+	return rule.Ports
+}
+
+// nonEmptyReqs 厂商接口一次只能创建一个方向的规则，返回有规则的请求
+func nonEmptyReqs[T any](ingress *T, ingressCnt int, egress *T, egressCnt int) []*T {
+	reqs := make([]*T, 0, 2)
+	if ingressCnt != 0 {
+		reqs = append(reqs, ingress)
+	}
+	if egressCnt != 0 {
+		reqs = append(reqs, egress)
+	}
+
+	return reqs
+}
+
+func isIPv6(addr string) bool {
+	ip, _, err := net.ParseCIDR(addr)
+	if err != nil {
+		ip = net.ParseIP(addr)
+	}
+
+	return ip != nil && ip.To4() == nil
+}
+
+func toCidr(addr string) string {
+	return normalizeAddress(addr)
+}
+
+func splitAddressFamily(addrs []string) [][]string {
+	var ipv4, ipv6 []string
+	for _, addr := range addrs {
+		if isIPv6(addr) {
+			ipv6 = append(ipv6, addr)
+		} else {
+			ipv4 = append(ipv4, addr)
+		}
+	}
+
+	result := make([][]string, 0, 2)
+	if len(ipv4) != 0 {
+		result = append(result, ipv4)
+	}
+	if len(ipv6) != 0 {
+		result = append(result, ipv6)
+	}
+
+	return result
+}
+
+// CheckRuleTemplateDrift check whether the targets which the template applied to still satisfy the template.
+func (s *securityGroup) CheckRuleTemplateDrift(kt *kit.Kit, tpl *corecloud.SGRuleTemplate,
+	applies []corecloud.SGRuleTplApply) ([]cloudserver.SGRuleTemplateDrift, error) {
+
+	expected := normalizeTemplateRules(tpl.Rules)
+	result := make([]cloudserver.SGRuleTemplateDrift, 0, len(applies))
+	for _, apply := range applies {
+		drift := cloudserver.SGRuleTemplateDrift{
+			ApplyID:        apply.ID,
+			Vendor:         apply.Vendor,
+			ResType:        apply.ResType,
+			ResID:          apply.ResID,
+			CloudResID:     apply.CloudResID,
+			AppliedVersion: apply.TemplateVersion,
+			MissingRules:   make([]int, 0),
+		}
+
+		if apply.State != enumor.SGRuleTplApplySuccess {
+			drift.Status = enumor.SGRuleTplNotApplied
+			drift.Reason = apply.Reason
+			result = append(result, drift)
+			continue
+		}
+
+		current, err := s.listTargetRules(kt, apply.Vendor, apply.ResID)
+		if err != nil {
+			return nil, err
+		}
+
+		drift.MissingRules = missingTemplateRules(expected, current)
+		switch {
+		case apply.TemplateVersion < tpl.Version:
+			drift.Status = enumor.SGRuleTplOutdated
+		case len(drift.MissingRules) != 0:
+			drift.Status = enumor.SGRuleTplDrifted
+		default:
+			drift.Status = enumor.SGRuleTplInSync
+		}
+		result = append(result, drift)
+	}
+
+	return result, nil
+}
+
+// listTargetRules list the normalized rules of the target, target is vpc for gcp and security group for others.
+func (s *securityGroup) listTargetRules(kt *kit.Kit, vendor enumor.Vendor, resID string) (
+	[]corecloud.NormalizedSGRule, error) {
+
+	if vendor != enumor.Gcp {
+		return s.listNormalizedRules(kt, vendor, resID)
+	}
+
+	rules, err := listAll(func(page *core.BasePage) ([]corecloud.GcpFirewallRule, error) {
+		req := &dataproto.GcpFirewallRuleListReq{Filter: tools.EqualExpression("vpc_id", resID), Page: page}
+		result, err := s.client.DataService().Gcp.Firewall.ListFirewallRule(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return nil, err
+		}
+		return result.Details, nil
+	})
+	if err != nil {
+		logs.Errorf("list gcp firewall rule failed, err: %v, vpc: %s, rid: %s", err, resID, kt.Rid)
+		return nil, err
+	}
+
+	return NormalizeGcpFirewallRules(rules)
+}
+
+// normalizeTemplateRules convert each template rule to normalized rules, vendor rules usually contain only one
+// address or port range, so the template rule is split by address and port range.
+func normalizeTemplateRules(rules []corecloud.SGTemplateRule) [][]corecloud.NormalizedSGRule {
+	result := make([][]corecloud.NormalizedSGRule, 0, len(rules))
+	for _, rule := range rules {
+		protocol := normalizeProtocol(rule.Protocol)
+
+		var portRanges []corecloud.PortRange
+		if hasPort(protocol) {
+			// 模版规则在创建时已经校验过端口格式
+			portRanges, _ = parsePorts(rule.Ports...)
+		}
+
+		pieces := make([]corecloud.NormalizedSGRule, 0)
+		for _, addr := range rule.Addresses {
+			piece := corecloud.NormalizedSGRule{
+				Type:      rule.Type,
+				Action:    rule.Action,
+				Protocol:  protocol,
+				Addresses: []string{toCidr(addr)},
+			}
+
+			if len(portRanges) == 0 {
+				pieces = append(pieces, piece)
+				continue
+			}
+
+			for _, portRange := range portRanges {
+				piece.Ports = []corecloud.PortRange{portRange}
+				pieces = append(pieces, piece)
+			}
+		}
+
+		result = append(result, pieces)
+	}
+
+	return result
+}
+
+// missingTemplateRules return the index of expected rules which are not covered by current rules with the same
+// direction and action.
+func missingTemplateRules(expected [][]corecloud.NormalizedSGRule, current []corecloud.NormalizedSGRule) []int {
+	missing := make([]int, 0)
+	for idx, pieces := range expected {
+		for _, piece := range pieces {
+			if !isRuleCovered(piece, current) {
+				missing = append(missing, idx)
+				break
+			}
+		}
+	}
+
+	return missing
+}
+
+func isRuleCovered(rule corecloud.NormalizedSGRule, current []corecloud.NormalizedSGRule) bool {
+	for _, one := range current {
+		if one.Type == rule.Type && one.Action == rule.Action && covers(one, rule) {
+			return true
+		}
+	}
+
+	return false
+}
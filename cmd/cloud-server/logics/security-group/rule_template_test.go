@@ -0,0 +1,89 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"reflect"
+	"testing"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/converter"
+)
+
+func TestCompileAndDriftRuleTemplate(t *testing.T) {
+	tplRules := []corecloud.SGTemplateRule{
+		{Type: enumor.Ingress, Action: enumor.SGRuleAllow, Protocol: "tcp", Ports: []string{"80", "443"},
+			Addresses: []string{"10.0.0.0/8", "192.168.1.1"}},
+		{Type: enumor.Egress, Action: enumor.SGRuleDeny, Protocol: "all", Addresses: []string{"0.0.0.0/0"}},
+	}
+	target := &SGRuleTplTarget{TemplateID: "tpl", TemplateVersion: 1, Vendor: enumor.TCloud, AccountID: "acc"}
+
+	reqs := compileTCloudRules(target, tplRules)
+	if len(reqs) != 2 {
+		t.Fatalf("got %d tcloud requests, expect: 2", len(reqs))
+	}
+	if len(reqs[0].IngressRuleSet) != 4 || len(reqs[1].EgressRuleSet) != 1 {
+		t.Fatalf("got %d ingress and %d egress rules, expect: 4 and 1", len(reqs[0].IngressRuleSet),
+			len(reqs[1].EgressRuleSet))
+	}
+
+	if _, err := compileAwsRules(target, tplRules); err == nil {
+		t.Errorf("compile deny rule for aws should be failed")
+	}
+
+	// 模拟已下发的规则中443端口的一条规则被删除
+	current := make([]corecloud.TCloudSecurityGroupRule, 0)
+	for _, req := range reqs {
+		for _, one := range append(req.IngressRuleSet, req.EgressRuleSet...) {
+			if *one.Port == "443" && *one.IPv4Cidr == "10.0.0.0/8" {
+				continue
+			}
+			rule := corecloud.TCloudSecurityGroupRule{ID: *one.Port, CloudPolicyIndex: int64(len(current)),
+				Protocol: one.Protocol, Port: one.Port, IPv4Cidr: one.IPv4Cidr, Action: one.Action,
+				Type: enumor.Ingress}
+			if len(req.EgressRuleSet) != 0 {
+				rule.Type = enumor.Egress
+			}
+			current = append(current, rule)
+		}
+	}
+	normalized, err := NormalizeTCloudRules(current)
+	if err != nil {
+		t.Fatalf("normalize tcloud rules failed, err: %v", err)
+	}
+
+	missing := missingTemplateRules(normalizeTemplateRules(tplRules), normalized)
+	if !reflect.DeepEqual(missing, []int{0}) {
+		t.Errorf("got missing rules: %v, expect: [0]", missing)
+	}
+
+	current = append(current, corecloud.TCloudSecurityGroupRule{ID: "x", CloudPolicyIndex: int64(len(current)),
+		Protocol: converter.ValToPtr("TCP"), Port: converter.ValToPtr("1-1000"),
+		IPv4Cidr: converter.ValToPtr("10.0.0.0/8"), Action: "ACCEPT", Type: enumor.Ingress})
+	normalized, err = NormalizeTCloudRules(current)
+	if err != nil {
+		t.Fatalf("normalize tcloud rules failed, err: %v", err)
+	}
+
+	if missing = missingTemplateRules(normalizeTemplateRules(tplRules), normalized); len(missing) != 0 {
+		t.Errorf("got missing rules: %v, expect none", missing)
+	}
+}
@@ -0,0 +1,573 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package securitygroup ...
+package securitygroup
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	coreni "hcm/pkg/api/core/cloud/network-interface"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+)
+
+// Interface define security group logics interface.
+type Interface interface {
+	AnalyzeEffectiveRules(kt *kit.Kit, vendor enumor.Vendor, resType enumor.CloudResourceType, resID string) (
+		*cloudserver.SGEffectiveRuleResult, error)
+	CheckReachability(kt *kit.Kit, vendor enumor.Vendor, req *cloudserver.SGReachabilityCheckReq) (
+		*cloudserver.SGReachabilityResult, error)
+	CompileRuleTemplate(kt *kit.Kit, target *SGRuleTplTarget, rules []corecloud.SGTemplateRule) (*CompiledSGRules,
+		error)
+	CheckRuleTemplateDrift(kt *kit.Kit, tpl *corecloud.SGRuleTemplate, applies []corecloud.SGRuleTplApply) (
+		[]cloudserver.SGRuleTemplateDrift, error)
+}
+
+type securityGroup struct {
+	client *client.ClientSet
+}
+
+// NewSecurityGroup new security group logics.
+func NewSecurityGroup(client *client.ClientSet) Interface {
+	return &securityGroup{
+		client: client,
+	}
+}
+
+// AnalyzeEffectiveRules analyze the effective rules of all security groups attached to the cvm or network interface.
+func (s *securityGroup) AnalyzeEffectiveRules(kt *kit.Kit, vendor enumor.Vendor, resType enumor.CloudResourceType,
+	resID string) (*cloudserver.SGEffectiveRuleResult, error) {
+
+	policies, err := s.loadPolicies(kt, vendor, resType, resID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &cloudserver.SGEffectiveRuleResult{
+		Vendor:   vendor,
+		ResType:  resType,
+		ResID:    resID,
+		Policies: make([]cloudserver.SGPolicyAnalysis, 0, len(policies)),
+	}
+	for _, one := range policies {
+		result.Policies = append(result.Policies, one.analyze())
+	}
+
+	return result, nil
+}
+
+// CheckReachability check whether the traffic can reach the cvm or network interface (ingress), or be sent out from
+// it (egress).
+func (s *securityGroup) CheckReachability(kt *kit.Kit, vendor enumor.Vendor, req *cloudserver.SGReachabilityCheckReq) (
+	*cloudserver.SGReachabilityResult, error) {
+
+	policies, err := s.loadPolicies(kt, vendor, req.ResType, req.ResID)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := net.ParseIP(req.Address)
+	if ip == nil {
+		return nil, errf.Newf(errf.InvalidParameter, "address: %s is not a valid ip", req.Address)
+	}
+
+	result := &cloudserver.SGReachabilityResult{
+		Allowed:   true,
+		Decisions: make([]cloudserver.SGPolicyDecision, 0, len(policies)),
+	}
+	for _, one := range policies {
+		decision := one.decide(req.Type, ip, normalizeProtocol(req.Protocol), req.Port)
+		result.Allowed = result.Allowed && decision.Allowed
+		result.Indeterminate = result.Indeterminate || decision.Indeterminate
+		result.Decisions = append(result.Decisions, decision)
+	}
+
+	return result, nil
+}
+
+// loadPolicies load the checkpoints on the traffic path of the resource, checkpoints are built by the vendor's
+// security group semantics.
+func (s *securityGroup) loadPolicies(kt *kit.Kit, vendor enumor.Vendor, resType enumor.CloudResourceType,
+	resID string) ([]*policy, error) {
+
+	switch vendor {
+	case enumor.TCloud:
+		return s.loadTCloudPolicies(kt, resType, resID)
+	case enumor.Aws:
+		return s.loadAwsPolicies(kt, resType, resID)
+	case enumor.HuaWei:
+		return s.loadHuaWeiPolicies(kt, resType, resID)
+	case enumor.Azure:
+		return s.loadAzurePolicies(kt, resType, resID)
+	case enumor.Gcp:
+		return s.loadGcpPolicies(kt, resType, resID)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support", vendor)
+	}
+}
+
+// loadTCloudPolicies 腾讯云实例绑定多个安全组时，按照绑定顺序依次匹配各安全组中的规则，首条命中的规则生效
+func (s *securityGroup) loadTCloudPolicies(kt *kit.Kit, resType enumor.CloudResourceType, resID string) (
+	[]*policy, error) {
+
+	if resType != enumor.CvmCloudResType {
+		return nil, errf.Newf(errf.InvalidParameter, "tcloud not support res_type: %s", resType)
+	}
+
+	cvm, err := s.client.DataService().TCloud.Cvm.GetCvm(kt.Ctx, kt.Header(), resID)
+	if err != nil {
+		logs.Errorf("get tcloud cvm failed, err: %v, id: %s, rid: %s", err, resID, kt.Rid)
+		return nil, err
+	}
+
+	sgs, err := s.listSGByCloudIDs(kt, enumor.TCloud, cvm.AccountID, cvm.Extension.CloudSecurityGroupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newPolicy(enumor.CvmCloudResType, resID, firstMatch)
+	for _, sg := range sgs {
+		normalized, err := s.listNormalizedRules(kt, enumor.TCloud, sg.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		// 安全组内部按照策略索引排序，安全组之间保持绑定顺序
+		sort.SliceStable(normalized, func(i, j int) bool { return normalized[i].Priority < normalized[j].Priority })
+		p.securityGroupIDs = append(p.securityGroupIDs, sg.ID)
+		p.addRules(normalized)
+	}
+
+	return []*policy{p}, nil
+}
+
+// loadAwsPolicies aws安全组只有允许规则，实例绑定的所有安全组规则取并集
+func (s *securityGroup) loadAwsPolicies(kt *kit.Kit, resType enumor.CloudResourceType, resID string) (
+	[]*policy, error) {
+
+	if resType != enumor.CvmCloudResType {
+		return nil, errf.Newf(errf.InvalidParameter, "aws not support res_type: %s", resType)
+	}
+
+	cvm, err := s.client.DataService().Aws.Cvm.GetCvm(kt.Ctx, kt.Header(), resID)
+	if err != nil {
+		logs.Errorf("get aws cvm failed, err: %v, id: %s, rid: %s", err, resID, kt.Rid)
+		return nil, err
+	}
+
+	sgs, err := s.listSGByCloudIDs(kt, enumor.Aws, cvm.AccountID, cvm.Extension.CloudSecurityGroupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newPolicy(enumor.CvmCloudResType, resID, anyAllow)
+	for _, sg := range sgs {
+		normalized, err := s.listNormalizedRules(kt, enumor.Aws, sg.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		p.securityGroupIDs = append(p.securityGroupIDs, sg.ID)
+		p.addRules(normalized)
+	}
+
+	return []*policy{p}, nil
+}
+
+// loadHuaWeiPolicies 华为云实例绑定的所有安全组规则合并后按照优先级匹配，相同优先级拒绝规则优先
+func (s *securityGroup) loadHuaWeiPolicies(kt *kit.Kit, resType enumor.CloudResourceType, resID string) (
+	[]*policy, error) {
+
+	var accountID string
+	var cloudSGIDs []string
+	switch resType {
+	case enumor.CvmCloudResType:
+		cvm, err := s.client.DataService().HuaWei.Cvm.GetCvm(kt.Ctx, kt.Header(), resID)
+		if err != nil {
+			logs.Errorf("get huawei cvm failed, err: %v, id: %s, rid: %s", err, resID, kt.Rid)
+			return nil, err
+		}
+		accountID, cloudSGIDs = cvm.AccountID, cvm.Extension.CloudSecurityGroupIDs
+
+	case enumor.NetworkInterfaceCloudResType:
+		ni, err := s.client.DataService().HuaWei.NetworkInterface.Get(kt.Ctx, kt.Header(), resID)
+		if err != nil {
+			logs.Errorf("get huawei network interface failed, err: %v, id: %s, rid: %s", err, resID, kt.Rid)
+			return nil, err
+		}
+		accountID = ni.AccountID
+		if ni.Extension != nil {
+			cloudSGIDs = ni.Extension.CloudSecurityGroupIDs
+		}
+
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "huawei not support res_type: %s", resType)
+	}
+
+	sgs, err := s.listSGByCloudIDs(kt, enumor.HuaWei, accountID, cloudSGIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newPolicy(resType, resID, firstMatch)
+	for _, sg := range sgs {
+		normalized, err := s.listNormalizedRules(kt, enumor.HuaWei, sg.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		p.securityGroupIDs = append(p.securityGroupIDs, sg.ID)
+		p.addRules(normalized)
+	}
+	p.sortByPriority()
+
+	return []*policy{p}, nil
+}
+
+// loadAzurePolicies azure网络安全组可以同时关联子网和网络接口，流量需要同时被两者放通，云主机按照主网卡进行分析
+func (s *securityGroup) loadAzurePolicies(kt *kit.Kit, resType enumor.CloudResourceType, resID string) (
+	[]*policy, error) {
+
+	var ni *coreni.NetworkInterface[coreni.AzureNIExtension]
+	switch resType {
+	case enumor.CvmCloudResType:
+		cvm, err := s.client.DataService().Azure.Cvm.GetCvm(kt.Ctx, kt.Header(), resID)
+		if err != nil {
+			logs.Errorf("get azure cvm failed, err: %v, id: %s, rid: %s", err, resID, kt.Rid)
+			return nil, err
+		}
+
+		if cvm.Extension == nil || len(cvm.Extension.CloudNetworkInterfaceIDs) == 0 {
+			return make([]*policy, 0), nil
+		}
+
+		primaryID := cvm.Extension.CloudNetworkInterfaceIDs[0]
+		listReq := &core.ListReq{
+			Filter: tools.EqualExpression("cloud_id", primaryID),
+			Page:   core.NewDefaultBasePage(),
+		}
+		niResult, err := s.client.DataService().Azure.NetworkInterface.ListNetworkInterfaceExt(kt.Ctx, kt.Header(),
+			listReq)
+		if err != nil {
+			logs.Errorf("list azure network interface failed, err: %v, cloud_id: %s, rid: %s", err, primaryID, kt.Rid)
+			return nil, err
+		}
+
+		if len(niResult.Details) == 0 {
+			return nil, errf.Newf(errf.RecordNotFound, "network interface: %s not found", primaryID)
+		}
+		ni = &niResult.Details[0]
+
+	case enumor.NetworkInterfaceCloudResType:
+		var err error
+		ni, err = s.client.DataService().Azure.NetworkInterface.Get(kt.Ctx, kt.Header(), resID)
+		if err != nil {
+			logs.Errorf("get azure network interface failed, err: %v, id: %s, rid: %s", err, resID, kt.Rid)
+			return nil, err
+		}
+
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "azure not support res_type: %s", resType)
+	}
+
+	policies := make([]*policy, 0, 2)
+	if len(ni.SubnetID) != 0 {
+		subnet, err := s.client.DataService().Azure.Subnet.Get(kt.Ctx, kt.Header(), ni.SubnetID)
+		if err != nil {
+			logs.Errorf("get azure subnet failed, err: %v, id: %s, rid: %s", err, ni.SubnetID, kt.Rid)
+			return nil, err
+		}
+
+		if subnet.Extension != nil && len(subnet.Extension.SecurityGroupID) != 0 {
+			p, err := s.azurePolicy(kt, enumor.SubnetCloudResType, subnet.ID, subnet.Extension.SecurityGroupID)
+			if err != nil {
+				return nil, err
+			}
+			policies = append(policies, p)
+		}
+	}
+
+	if ni.Extension != nil && len(converter.PtrToVal(ni.Extension.SecurityGroupID)) != 0 {
+		p, err := s.azurePolicy(kt, enumor.NetworkInterfaceCloudResType, ni.ID, *ni.Extension.SecurityGroupID)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+
+	return policies, nil
+}
+
+func (s *securityGroup) azurePolicy(kt *kit.Kit, scope enumor.CloudResourceType, scopeID, sgID string) (
+	*policy, error) {
+
+	normalized, err := s.listNormalizedRules(kt, enumor.Azure, sgID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newPolicy(scope, scopeID, firstMatch)
+	p.securityGroupIDs = append(p.securityGroupIDs, sgID)
+	p.addRules(normalized)
+	p.addRules(azureDefaultRules(sgID))
+	p.sortByPriority()
+
+	return p, nil
+}
+
+// azureDefaultRules azure 网络安全组内置的默认规则，不会同步到 hcm，优先级最低
+// reference:
+// https://learn.microsoft.com/zh-cn/azure/virtual-network/network-security-groups-overview#default-security-rules
+func azureDefaultRules(sgID string) []corecloud.NormalizedSGRule {
+	build := func(name string, ruleType enumor.SecurityGroupRuleType, allow bool, priority int64,
+		addr string) corecloud.NormalizedSGRule {
+
+		rule := corecloud.NormalizedSGRule{
+			RuleID:          name,
+			SecurityGroupID: sgID,
+			Vendor:          enumor.Azure,
+			Type:            ruleType,
+			Action:          sgRuleAction(allow),
+			Protocol:        protocolAll,
+			Priority:        priority,
+		}
+		if _, exists := azureServiceTags[addr]; exists || addr == addressAny {
+			rule.Addresses = []string{addr}
+		} else {
+			rule.References = []string{"service_tag:" + addr}
+		}
+		return rule
+	}
+
+	return []corecloud.NormalizedSGRule{
+		build("AllowVnetInBound", enumor.Ingress, true, 65000, azureTagVirtualNetwork),
+		build("AllowAzureLoadBalancerInBound", enumor.Ingress, true, 65001, "AzureLoadBalancer"),
+		build("DenyAllInBound", enumor.Ingress, false, 65500, addressAny),
+		build("AllowVnetOutBound", enumor.Egress, true, 65000, azureTagVirtualNetwork),
+		build("AllowInternetOutBound", enumor.Egress, true, 65001, azureTagInternet),
+		build("DenyAllOutBound", enumor.Egress, false, 65500, addressAny),
+	}
+}
+
+// loadGcpPolicies gcp防火墙规则作用于整个vpc，按照优先级匹配，相同优先级拒绝规则优先，隐含拒绝所有入流量、允许所有出流量。
+// 实例的网络标记和服务账号没有同步，指定了目标标记或目标服务账号的规则无法判定是否作用于实例，连通性检查结果为无法确定。
+func (s *securityGroup) loadGcpPolicies(kt *kit.Kit, resType enumor.CloudResourceType, resID string) (
+	[]*policy, error) {
+
+	var vpcIDs []string
+	switch resType {
+	case enumor.CvmCloudResType:
+		cvm, err := s.client.DataService().Gcp.Cvm.GetCvm(kt.Ctx, kt.Header(), resID)
+		if err != nil {
+			logs.Errorf("get gcp cvm failed, err: %v, id: %s, rid: %s", err, resID, kt.Rid)
+			return nil, err
+		}
+		vpcIDs = cvm.VpcIDs
+
+	case enumor.NetworkInterfaceCloudResType:
+		ni, err := s.client.DataService().Gcp.NetworkInterface.Get(kt.Ctx, kt.Header(), resID)
+		if err != nil {
+			logs.Errorf("get gcp network interface failed, err: %v, id: %s, rid: %s", err, resID, kt.Rid)
+			return nil, err
+		}
+		vpcIDs = []string{ni.VpcID}
+
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "gcp not support res_type: %s", resType)
+	}
+
+	policies := make([]*policy, 0, len(vpcIDs))
+	for _, vpcID := range vpcIDs {
+		rules, err := listAll(func(page *core.BasePage) ([]corecloud.GcpFirewallRule, error) {
+			req := &dataproto.GcpFirewallRuleListReq{Filter: tools.EqualExpression("vpc_id", vpcID), Page: page}
+			result, err := s.client.DataService().Gcp.Firewall.ListFirewallRule(kt.Ctx, kt.Header(), req)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		})
+		if err != nil {
+			logs.Errorf("list gcp firewall rule failed, err: %v, vpc: %s, rid: %s", err, vpcID, kt.Rid)
+			return nil, err
+		}
+
+		normalized, err := NormalizeGcpFirewallRules(rules)
+		if err != nil {
+			return nil, err
+		}
+
+		p := newPolicy(enumor.VpcCloudResType, vpcID, firstMatch)
+		p.defaultEgress = enumor.SGRuleAllow
+		p.addRules(normalized)
+		p.sortByPriority()
+		policies = append(policies, p)
+	}
+
+	return policies, nil
+}
+
+// listNormalizedRules list all rules of the security group and convert them to vendor neutral rules.
+func (s *securityGroup) listNormalizedRules(kt *kit.Kit, vendor enumor.Vendor, sgID string) (
+	[]corecloud.NormalizedSGRule, error) {
+
+	var normalized []corecloud.NormalizedSGRule
+	var err error
+	switch vendor {
+	case enumor.TCloud:
+		var rules []corecloud.TCloudSecurityGroupRule
+		rules, err = listAll(func(page *core.BasePage) ([]corecloud.TCloudSecurityGroupRule, error) {
+			req := &dataproto.TCloudSGRuleListReq{Filter: tools.AllExpression(), Page: page}
+			result, err := s.client.DataService().TCloud.SecurityGroup.ListSecurityGroupRule(kt.Ctx, kt.Header(),
+				req, sgID)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		})
+		if err == nil {
+			normalized, err = NormalizeTCloudRules(rules)
+		}
+
+	case enumor.Aws:
+		var rules []corecloud.AwsSecurityGroupRule
+		rules, err = listAll(func(page *core.BasePage) ([]corecloud.AwsSecurityGroupRule, error) {
+			req := &dataproto.AwsSGRuleListReq{Filter: tools.AllExpression(), Page: page}
+			result, err := s.client.DataService().Aws.SecurityGroup.ListSecurityGroupRule(kt.Ctx, kt.Header(),
+				req, sgID)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		})
+		if err == nil {
+			normalized, err = NormalizeAwsRules(rules)
+		}
+
+	case enumor.HuaWei:
+		var rules []corecloud.HuaWeiSecurityGroupRule
+		rules, err = listAll(func(page *core.BasePage) ([]corecloud.HuaWeiSecurityGroupRule, error) {
+			req := &dataproto.HuaWeiSGRuleListReq{Filter: tools.AllExpression(), Page: page}
+			result, err := s.client.DataService().HuaWei.SecurityGroup.ListSecurityGroupRule(kt.Ctx, kt.Header(),
+				req, sgID)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		})
+		if err == nil {
+			normalized, err = NormalizeHuaWeiRules(rules)
+		}
+
+	case enumor.Azure:
+		var rules []corecloud.AzureSecurityGroupRule
+		rules, err = listAll(func(page *core.BasePage) ([]corecloud.AzureSecurityGroupRule, error) {
+			req := &dataproto.AzureSGRuleListReq{Filter: tools.AllExpression(), Page: page}
+			result, err := s.client.DataService().Azure.SecurityGroup.ListSecurityGroupRule(kt.Ctx, kt.Header(),
+				req, sgID)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		})
+		if err == nil {
+			normalized, err = NormalizeAzureRules(rules)
+		}
+
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support security group", vendor)
+	}
+	if err != nil {
+		logs.Errorf("list %s security group rule failed, err: %v, sg: %s, rid: %s", vendor, err, sgID, kt.Rid)
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+// listSGByCloudIDs list security groups by cloud ids, the result keeps the order of cloud ids.
+func (s *securityGroup) listSGByCloudIDs(kt *kit.Kit, vendor enumor.Vendor, accountID string, cloudIDs []string) (
+	[]corecloud.BaseSecurityGroup, error) {
+
+	if len(cloudIDs) == 0 {
+		return make([]corecloud.BaseSecurityGroup, 0), nil
+	}
+
+	req := &dataproto.SecurityGroupListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", vendor),
+			tools.RuleEqual("account_id", accountID),
+			tools.RuleIn("cloud_id", cloudIDs),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := s.client.DataService().Global.SecurityGroup.ListSecurityGroup(kt.Ctx, kt.Header(), req)
+	if err != nil {
+		logs.Errorf("list security group failed, err: %v, cloud_ids: %v, rid: %s", err, cloudIDs, kt.Rid)
+		return nil, err
+	}
+
+	sgMap := make(map[string]corecloud.BaseSecurityGroup, len(result.Details))
+	for _, one := range result.Details {
+		sgMap[one.CloudID] = one
+	}
+
+	sgs := make([]corecloud.BaseSecurityGroup, 0, len(cloudIDs))
+	for _, cloudID := range cloudIDs {
+		sg, exists := sgMap[cloudID]
+		if !exists {
+			return nil, fmt.Errorf("security group: %s not found, please sync it first", cloudID)
+		}
+		sgs = append(sgs, sg)
+	}
+
+	return sgs, nil
+}
+
+// listAll list all resources page by page.
+func listAll[T any](list func(page *core.BasePage) ([]T, error)) ([]T, error) {
+	page := core.NewDefaultBasePage()
+	result := make([]T, 0)
+	for {
+		details, err := list(page)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, details...)
+		if uint(len(details)) < page.Limit {
+			break
+		}
+
+		page.Start += uint32(page.Limit)
+	}
+
+	return result, nil
+}
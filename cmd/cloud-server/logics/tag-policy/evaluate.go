@@ -0,0 +1,245 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tagpolicy 标签合规策略评估及自动修复
+package tagpolicy
+
+import (
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	tabletagpolicy "hcm/pkg/dal/table/cloud/tag-policy"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/thirdparty/esb"
+	"hcm/pkg/tools/slice"
+)
+
+// Evaluator evaluate the tags of the account's resources by the enabled tag policies.
+type Evaluator interface {
+	Evaluate(kt *kit.Kit, vendor enumor.Vendor, accountID string) error
+}
+
+type evaluator struct {
+	client    *client.ClientSet
+	esbClient esb.Client
+	remediate bool
+}
+
+// NewEvaluator new tag compliance evaluator.
+func NewEvaluator(client *client.ClientSet, esbClient esb.Client, conf cc.TagCompliance) Evaluator {
+	return &evaluator{
+		client:    client,
+		esbClient: esbClient,
+		remediate: conf.Remediate,
+	}
+}
+
+// tagResource 参与标签合规评估的资源
+type tagResource struct {
+	ID      string
+	CloudID string
+	Name    string
+	BkBizID int64
+	Region  string
+	Tags    map[string]string
+}
+
+// Evaluate the account's resources and replace its compliance result with the latest, the default tags are added to
+// the non-compliant resources by task-server if the policy enabled auto remediate.
+func (e *evaluator) Evaluate(kt *kit.Kit, vendor enumor.Vendor, accountID string) error {
+	policies, err := e.listEnabledPolicies(kt)
+	if err != nil {
+		return err
+	}
+
+	findings := make([]dataproto.TagComplianceFindingCreate, 0)
+	summaries := make([]dataproto.TagComplianceSummaryCreate, 0)
+	// 只有同步了标签的资源才能进行标签合规评估
+	for _, resType := range dataproto.TagSyncResTypes[vendor] {
+		if !matchAnyPolicy(policies, resType) {
+			continue
+		}
+
+		resources, err := e.listResources(kt, vendor, accountID, resType)
+		if err != nil {
+			logs.Errorf("list %s resources failed, err: %v, account: %s, rid: %s", resType, err, accountID, kt.Rid)
+			return err
+		}
+
+		resFindings, resSummaries := evaluateResources(policies, vendor, accountID, resType, resources)
+		findings = append(findings, resFindings...)
+		summaries = append(summaries, resSummaries...)
+	}
+
+	replaceReq := &dataproto.TagComplianceReplaceReq{
+		Vendor:    vendor,
+		AccountID: accountID,
+		Findings:  findings,
+		Summaries: summaries,
+	}
+	if err = e.client.DataService().Global.TagPolicy.ReplaceCompliance(kt, replaceReq); err != nil {
+		logs.Errorf("replace tag compliance failed, err: %v, account: %s, rid: %s", err, accountID, kt.Rid)
+		return err
+	}
+
+	logs.Infof("%s account[%s] tag compliance evaluate finished, finding count: %d, rid: %s", vendor, accountID,
+		len(findings), kt.Rid)
+
+	if e.remediate {
+		if err = e.remediateFindings(kt, vendor, accountID, policies, findings); err != nil {
+			logs.Errorf("remediate tag compliance findings failed, err: %v, account: %s, rid: %s", err, accountID,
+				kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchAnyPolicy 判断资源类型是否有生效的策略
+func matchAnyPolicy(policies []*corecloud.TagPolicy, resType enumor.CloudResourceType) bool {
+	for _, policy := range policies {
+		if policy.MatchResType(resType) {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateResources 使用对资源所属业务生效的策略评估资源，返回不合规资源以及按业务统计的合规情况，
+// 没有生效策略的资源不参与统计
+func evaluateResources(policies []*corecloud.TagPolicy, vendor enumor.Vendor, accountID string,
+	resType enumor.CloudResourceType, resources []tagResource) ([]dataproto.TagComplianceFindingCreate,
+	[]dataproto.TagComplianceSummaryCreate) {
+
+	findings := make([]dataproto.TagComplianceFindingCreate, 0)
+	summaryMap := make(map[int64]*dataproto.TagComplianceSummaryCreate)
+	bizIDs := make([]int64, 0)
+	for _, res := range resources {
+		compliant := true
+		evaluated := false
+		for _, policy := range policies {
+			if !policy.MatchResType(resType) ||
+				(policy.BkBizID != tabletagpolicy.GlobalTagPolicyBizID && policy.BkBizID != res.BkBizID) {
+				continue
+			}
+			evaluated = true
+
+			violations := checkTags(policy.Rules, res.Tags)
+			if len(violations) == 0 {
+				continue
+			}
+			compliant = false
+
+			findings = append(findings, dataproto.TagComplianceFindingCreate{
+				PolicyID:   policy.ID,
+				Vendor:     vendor,
+				AccountID:  accountID,
+				BkBizID:    res.BkBizID,
+				Region:     res.Region,
+				ResType:    resType,
+				ResID:      res.ID,
+				CloudResID: res.CloudID,
+				ResName:    res.Name,
+				Violations: violations,
+			})
+		}
+
+		if !evaluated {
+			continue
+		}
+
+		summary, exists := summaryMap[res.BkBizID]
+		if !exists {
+			summary = &dataproto.TagComplianceSummaryCreate{Vendor: vendor, AccountID: accountID,
+				BkBizID: res.BkBizID, ResType: resType}
+			summaryMap[res.BkBizID] = summary
+			bizIDs = append(bizIDs, res.BkBizID)
+		}
+		summary.TotalCount++
+		if compliant {
+			summary.CompliantCount++
+		}
+	}
+
+	summaries := make([]dataproto.TagComplianceSummaryCreate, 0, len(bizIDs))
+	for _, bizID := range bizIDs {
+		summaries = append(summaries, *summaryMap[bizID])
+	}
+
+	return findings, summaries
+}
+
+// checkTags 检查资源标签是否满足策略规则，返回违反的规则
+func checkTags(rules corecloud.TagPolicyRules, tags map[string]string) []corecloud.TagViolation {
+	violations := make([]corecloud.TagViolation, 0)
+	for _, rule := range rules {
+		value, exists := tags[rule.Key]
+		if !exists {
+			violations = append(violations, corecloud.TagViolation{Key: rule.Key, Type: enumor.TagViolationMissing})
+			continue
+		}
+
+		if len(rule.AllowedValues) != 0 && !slice.IsItemInSlice(rule.AllowedValues, value) {
+			violations = append(violations, corecloud.TagViolation{Key: rule.Key,
+				Type: enumor.TagViolationInvalidValue, Value: value})
+		}
+	}
+
+	return violations
+}
+
+// listEnabledPolicies list all the enabled tag policies, the resources of an account may belong to any biz.
+func (e *evaluator) listEnabledPolicies(kt *kit.Kit) ([]*corecloud.TagPolicy, error) {
+	return listAll(func(page *core.BasePage) ([]*corecloud.TagPolicy, error) {
+		req := &core.ListReq{Filter: tools.EqualExpression("enabled", true), Page: page}
+		result, err := e.client.DataService().Global.TagPolicy.List(kt, req)
+		if err != nil {
+			logs.Errorf("list enabled tag policy failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+		return result.Details, nil
+	})
+}
+
+// listAll list all the resources page by page.
+func listAll[T any](list func(page *core.BasePage) ([]T, error)) ([]T, error) {
+	page := core.NewDefaultBasePage()
+	result := make([]T, 0)
+	for {
+		details, err := list(page)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, details...)
+		if uint(len(details)) < page.Limit {
+			break
+		}
+
+		page.Start += uint32(page.Limit)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,183 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tagpolicy
+
+import (
+	"testing"
+
+	actionrestag "hcm/cmd/task-server/logics/action/resource-tag"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestEvaluateResources(t *testing.T) {
+	policies := []*corecloud.TagPolicy{
+		{ID: "global", BkBizID: 0, AutoRemediate: true, Rules: corecloud.TagPolicyRules{
+			{Key: "owner", DefaultValue: "admin"},
+			{Key: "env", AllowedValues: []string{"prod", "test"}, DefaultValue: "test"},
+		}},
+		{ID: "biz", BkBizID: 100, AutoRemediate: true, ResTypes: []enumor.CloudResourceType{enumor.CvmCloudResType},
+			Rules: corecloud.TagPolicyRules{
+				{Key: "env", DefaultValue: "prod"},
+				{Key: "module", DefaultValue: corecloud.TagValueVarModuleName},
+			}},
+		{ID: "disk", BkBizID: 100, ResTypes: []enumor.CloudResourceType{enumor.DiskCloudResType},
+			Rules: corecloud.TagPolicyRules{{Key: "disk"}}},
+	}
+
+	resources := []tagResource{
+		{ID: "1", CloudID: "ins-1", BkBizID: 100, Tags: map[string]string{"owner": "a", "env": "prod",
+			"module": "m"}},
+		{ID: "2", CloudID: "ins-2", BkBizID: 100, Tags: map[string]string{"env": "dev"}},
+		{ID: "3", CloudID: "ins-3", BkBizID: -1},
+	}
+
+	findings, summaries := evaluateResources(policies, enumor.TCloud, "account", enumor.CvmCloudResType, resources)
+	if len(findings) != 3 {
+		t.Fatalf("expect 3 findings, got: %d", len(findings))
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("expect 2 summaries, got: %d", len(summaries))
+	}
+	if summaries[0].BkBizID != 100 || summaries[0].TotalCount != 2 || summaries[0].CompliantCount != 1 {
+		t.Errorf("biz summary mismatch, got: %+v", summaries[0])
+	}
+	if summaries[1].BkBizID != -1 || summaries[1].TotalCount != 1 || summaries[1].CompliantCount != 0 {
+		t.Errorf("unassigned summary mismatch, got: %+v", summaries[1])
+	}
+
+	resRemediate := make(map[string]*remediateRes)
+	for _, res := range collectRemediateRes(policies, findings) {
+		resRemediate[res.resID] = res
+	}
+
+	// 业务策略与全局策略的默认值冲突时以业务策略为准，取值不合规的标签不自动修复
+	if res := resRemediate["2"]; res == nil || len(res.tags) != 2 || res.tags["owner"].value != "admin" ||
+		res.tags["module"].value != corecloud.TagValueVarModuleName {
+		t.Errorf("res 2 remediate tags mismatch, got: %+v", res)
+	}
+	if res := resRemediate["3"]; res == nil || len(res.tags) != 2 || res.tags["env"].value != "test" {
+		t.Errorf("res 3 remediate tags mismatch, got: %+v", res)
+	}
+}
+
+func TestCheckTags(t *testing.T) {
+	rules := corecloud.TagPolicyRules{
+		{Key: "owner"},
+		{Key: "env", AllowedValues: []string{"prod", "test"}},
+	}
+
+	cases := []struct {
+		name       string
+		tags       map[string]string
+		violations []corecloud.TagViolation
+	}{
+		{
+			name: "compliant",
+			tags: map[string]string{"owner": "admin", "env": "prod"},
+		},
+		{
+			name: "missing",
+			tags: nil,
+			violations: []corecloud.TagViolation{{Key: "owner", Type: enumor.TagViolationMissing},
+				{Key: "env", Type: enumor.TagViolationMissing}},
+		},
+		{
+			name:       "invalid value",
+			tags:       map[string]string{"owner": "", "env": "dev"},
+			violations: []corecloud.TagViolation{{Key: "env", Type: enumor.TagViolationInvalidValue, Value: "dev"}},
+		},
+	}
+
+	for _, c := range cases {
+		violations := checkTags(rules, c.tags)
+		if len(violations) != len(c.violations) {
+			t.Errorf("%s: expect %d violations, got: %+v", c.name, len(c.violations), violations)
+			continue
+		}
+		for i := range violations {
+			if violations[i] != c.violations[i] {
+				t.Errorf("%s: expect violation %+v, got: %+v", c.name, c.violations[i], violations[i])
+			}
+		}
+	}
+}
+
+func TestRenderTagValue(t *testing.T) {
+	vars := map[string]string{corecloud.TagValueVarBizID: "100", corecloud.TagValueVarBizName: "game"}
+
+	cases := []struct {
+		value  string
+		expect string
+		ok     bool
+	}{
+		{value: "static", expect: "static", ok: true},
+		{value: "{bk_biz_name}-{bk_biz_id}", expect: "game-100", ok: true},
+		{value: "{bk_module_name}", ok: false},
+	}
+
+	for _, c := range cases {
+		value, ok := renderTagValue(c.value, vars)
+		if value != c.expect || ok != c.ok {
+			t.Errorf("render %s expect: %s/%v, got: %s/%v", c.value, c.expect, c.ok, value, ok)
+		}
+	}
+}
+
+func TestBuildRemediateTasks(t *testing.T) {
+	resources := make([]*remediateRes, 0)
+	for i := 0; i < 150; i++ {
+		resources = append(resources, &remediateRes{resType: enumor.CvmCloudResType, resID: string(rune('a' + i%26)),
+			tags: map[string]remediateTag{"owner": {value: "admin"}}})
+	}
+	resources = append(resources, &remediateRes{resType: enumor.DiskCloudResType, resID: "disk",
+		tags: map[string]remediateTag{"owner": {value: "admin"}}})
+	resources = append(resources, &remediateRes{resType: enumor.CvmCloudResType, resID: "empty",
+		tags: map[string]remediateTag{}})
+
+	tasks := buildRemediateTasks(enumor.TCloud, "account", resources)
+	if len(tasks) != 3 {
+		t.Fatalf("expect 3 tasks, got: %d", len(tasks))
+	}
+
+	counts := []int{100, 50, 1}
+	for i, task := range tasks {
+		opt := task.Params.(*actionrestag.AddResTagsOption)
+		if len(opt.ResIDs) != counts[i] {
+			t.Errorf("task %d expect %d res, got: %d", i, counts[i], len(opt.ResIDs))
+		}
+		if err := opt.Validate(); err != nil {
+			t.Errorf("task %d option invalid, err: %v", i, err)
+		}
+	}
+}
+
+func TestSkipRemediatingRes(t *testing.T) {
+	resources := []*remediateRes{{resID: "a"}, {resID: "b"}, {resID: "c"}}
+
+	result := skipRemediatingRes(resources, map[string]struct{}{"b": {}})
+	if len(result) != 2 || result[0].resID != "a" || result[1].resID != "c" {
+		t.Fatalf("expect remediating res b skipped, got: %+v", result)
+	}
+
+	if result = skipRemediatingRes(resources, nil); len(result) != 3 {
+		t.Fatalf("expect all res kept, got: %d", len(result))
+	}
+}
@@ -0,0 +1,473 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tagpolicy
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	actionrestag "hcm/cmd/task-server/logics/action/resource-tag"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	tabletagpolicy "hcm/pkg/dal/table/cloud/tag-policy"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/thirdparty/esb/cmdb"
+	"hcm/pkg/tools/counter"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/maps"
+	"hcm/pkg/tools/slice"
+)
+
+// remediateTag 待补齐的标签
+type remediateTag struct {
+	value string
+	// fromBiz 是否来自业务策略，业务策略与全局策略设置了相同标签键时以业务策略为准
+	fromBiz bool
+}
+
+// remediateRes 待补齐标签的资源
+type remediateRes struct {
+	resType enumor.CloudResourceType
+	resID   string
+	cloudID string
+	bizID   int64
+	tags    map[string]remediateTag
+}
+
+// remediateFindings add the default tags to the resources missing tags, only the findings of policies that enabled
+// auto remediate are remediated, and the tags with unresolvable default value are skipped.
+func (e *evaluator) remediateFindings(kt *kit.Kit, vendor enumor.Vendor, accountID string,
+	policies []*corecloud.TagPolicy, findings []dataproto.TagComplianceFindingCreate) error {
+
+	resources := collectRemediateRes(policies, findings)
+	if len(resources) == 0 {
+		return nil
+	}
+
+	// 上一轮评估创建的补齐流程还未执行完成时，跳过这些资源，避免重复创建补齐流程
+	remediatingIDs, err := e.listRemediatingResIDs(kt, vendor, accountID)
+	if err != nil {
+		return err
+	}
+	resources = skipRemediatingRes(resources, remediatingIDs)
+	if len(resources) == 0 {
+		logs.Infof("%s account[%s] tag compliance findings are all remediating, skip, rid: %s", vendor, accountID,
+			kt.Rid)
+		return nil
+	}
+
+	if err = e.resolveTagValues(kt, vendor, resources); err != nil {
+		return err
+	}
+
+	tasks := buildRemediateTasks(vendor, accountID, resources)
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	flowReq := &ts.AddCustomFlowReq{
+		Name:  enumor.FlowAddResourceTags,
+		Tasks: tasks,
+	}
+	result, err := e.client.TaskServer().CreateCustomFlow(kt, flowReq)
+	if err != nil {
+		logs.Errorf("call taskserver to create custom flow failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	logs.Infof("%s account[%s] tag compliance remediate flow created, flow: %s, task count: %d, rid: %s", vendor,
+		accountID, result.ID, len(tasks), kt.Rid)
+
+	return nil
+}
+
+// listRemediatingResIDs 查询账号下补齐标签任务还未结束的资源ID
+func (e *evaluator) listRemediatingResIDs(kt *kit.Kit, vendor enumor.Vendor, accountID string) (
+	map[string]struct{}, error) {
+
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("flow_name", enumor.FlowAddResourceTags),
+			tools.RuleEqual("action_name", enumor.ActionAddResourceTags),
+			tools.RuleIn("state", []enumor.TaskState{enumor.TaskInit, enumor.TaskPending, enumor.TaskRunning}),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+
+	resIDs := make(map[string]struct{})
+	for {
+		result, err := e.client.TaskServer().ListTask(kt, req)
+		if err != nil {
+			logs.Errorf("list remediating add resource tags tasks failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, task := range result.Details {
+			opt := new(actionrestag.AddResTagsOption)
+			if err = json.UnmarshalFromString(string(task.Params), opt); err != nil {
+				logs.Errorf("unmarshal add resource tags task params failed, err: %v, task: %s, rid: %s", err,
+					task.ID, kt.Rid)
+				return nil, err
+			}
+
+			if opt.Vendor != vendor || opt.AccountID != accountID {
+				continue
+			}
+			for _, id := range opt.ResIDs {
+				resIDs[id] = struct{}{}
+			}
+		}
+
+		if len(result.Details) < int(req.Page.Limit) {
+			break
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+
+	return resIDs, nil
+}
+
+// skipRemediatingRes 过滤掉补齐标签任务还未结束的资源
+func skipRemediatingRes(resources []*remediateRes, remediatingIDs map[string]struct{}) []*remediateRes {
+	if len(remediatingIDs) == 0 {
+		return resources
+	}
+
+	result := make([]*remediateRes, 0, len(resources))
+	for _, res := range resources {
+		if _, exists := remediatingIDs[res.resID]; exists {
+			continue
+		}
+		result = append(result, res)
+	}
+
+	return result
+}
+
+// collectRemediateRes 收集缺失标签且标签规则设置了默认值的资源
+func collectRemediateRes(policies []*corecloud.TagPolicy,
+	findings []dataproto.TagComplianceFindingCreate) []*remediateRes {
+
+	policyMap := make(map[string]*corecloud.TagPolicy, len(policies))
+	for _, policy := range policies {
+		policyMap[policy.ID] = policy
+	}
+
+	resMap := make(map[string]*remediateRes)
+	resources := make([]*remediateRes, 0)
+	for _, finding := range findings {
+		policy, exists := policyMap[finding.PolicyID]
+		if !exists || !policy.AutoRemediate {
+			continue
+		}
+
+		ruleMap := make(map[string]corecloud.TagPolicyRule, len(policy.Rules))
+		for _, rule := range policy.Rules {
+			ruleMap[rule.Key] = rule
+		}
+		fromBiz := policy.BkBizID != tabletagpolicy.GlobalTagPolicyBizID
+
+		for _, violation := range finding.Violations {
+			if violation.Type != enumor.TagViolationMissing || len(ruleMap[violation.Key].DefaultValue) == 0 {
+				continue
+			}
+
+			res, exists := resMap[finding.ResID]
+			if !exists {
+				res = &remediateRes{resType: finding.ResType, resID: finding.ResID, cloudID: finding.CloudResID,
+					bizID: finding.BkBizID, tags: make(map[string]remediateTag)}
+				resMap[finding.ResID] = res
+				resources = append(resources, res)
+			}
+
+			if tag, exists := res.tags[violation.Key]; exists && (tag.fromBiz || !fromBiz) {
+				continue
+			}
+			res.tags[violation.Key] = remediateTag{value: ruleMap[violation.Key].DefaultValue, fromBiz: fromBiz}
+		}
+	}
+
+	return resources
+}
+
+// resolveTagValues 替换默认值中的变量，无法解析的标签将被忽略
+func (e *evaluator) resolveTagValues(kt *kit.Kit, vendor enumor.Vendor, resources []*remediateRes) error {
+	bizIDs := make([]int64, 0)
+	bizCvmCloudIDs := make(map[int64][]string)
+	for _, res := range resources {
+		for _, tag := range res.tags {
+			if res.bizID > 0 && strings.Contains(tag.value, corecloud.TagValueVarBizName) {
+				bizIDs = append(bizIDs, res.bizID)
+			}
+			if res.bizID > 0 && res.resType == enumor.CvmCloudResType &&
+				strings.Contains(tag.value, corecloud.TagValueVarModuleName) {
+				bizCvmCloudIDs[res.bizID] = append(bizCvmCloudIDs[res.bizID], res.cloudID)
+			}
+		}
+	}
+
+	bizNames, err := e.getBizNames(kt, slice.Unique(bizIDs))
+	if err != nil {
+		return err
+	}
+
+	moduleNames := make(map[string]string)
+	for bizID, cloudIDs := range bizCvmCloudIDs {
+		names, err := e.getHostModuleNames(kt, vendor, bizID, slice.Unique(cloudIDs))
+		if err != nil {
+			return err
+		}
+		for cloudID, name := range names {
+			moduleNames[cloudID] = name
+		}
+	}
+
+	for _, res := range resources {
+		vars := make(map[string]string)
+		if res.bizID > 0 {
+			vars[corecloud.TagValueVarBizID] = strconv.FormatInt(res.bizID, 10)
+		}
+		if name, exists := bizNames[res.bizID]; exists {
+			vars[corecloud.TagValueVarBizName] = name
+		}
+		if name, exists := moduleNames[res.cloudID]; exists && res.resType == enumor.CvmCloudResType {
+			vars[corecloud.TagValueVarModuleName] = name
+		}
+
+		for key, tag := range res.tags {
+			value, ok := renderTagValue(tag.value, vars)
+			if !ok {
+				logs.Warnf("tag value of res: %s key: %s can not be resolved, skip it, rid: %s", res.resID, key,
+					kt.Rid)
+				delete(res.tags, key)
+				continue
+			}
+			tag.value = value
+			res.tags[key] = tag
+		}
+	}
+
+	return nil
+}
+
+// renderTagValue 使用变量值替换标签值模板，存在未解析的变量或结果为空时返回false
+func renderTagValue(value string, vars map[string]string) (string, bool) {
+	if !corecloud.IsTagValueTemplate(value) {
+		return value, true
+	}
+
+	for _, name := range []string{corecloud.TagValueVarBizID, corecloud.TagValueVarBizName,
+		corecloud.TagValueVarModuleName} {
+
+		if !strings.Contains(value, name) {
+			continue
+		}
+
+		varValue, exists := vars[name]
+		if !exists || len(varValue) == 0 {
+			return "", false
+		}
+		value = strings.ReplaceAll(value, name, varValue)
+	}
+
+	return value, len(value) != 0
+}
+
+// getBizNames get biz names from cmdb, returns map[bizID]bizName.
+func (e *evaluator) getBizNames(kt *kit.Kit, bizIDs []int64) (map[int64]string, error) {
+	bizNames := make(map[int64]string, len(bizIDs))
+	if len(bizIDs) == 0 {
+		return bizNames, nil
+	}
+
+	params := &cmdb.SearchBizParams{
+		Fields: []string{"bk_biz_id", "bk_biz_name"},
+		Page:   cmdb.BasePage{Limit: int64(len(bizIDs))},
+		BizPropertyFilter: &cmdb.QueryFilter{
+			Rule: &cmdb.CombinedRule{
+				Condition: "AND",
+				Rules: []cmdb.Rule{
+					&cmdb.AtomRule{Field: cmdb.BizIDField, Operator: cmdb.OperatorIn, Value: bizIDs},
+				},
+			},
+		},
+	}
+	result, err := e.esbClient.Cmdb().SearchBusiness(kt, params)
+	if err != nil {
+		logs.Errorf("search cmdb business failed, err: %v, biz ids: %v, rid: %s", err, bizIDs, kt.Rid)
+		return nil, err
+	}
+
+	for _, biz := range result.Info {
+		bizNames[biz.BizID] = biz.BizName
+	}
+
+	return bizNames, nil
+}
+
+// getHostModuleNames get the cmdb module names of the hosts, returns map[cloudID]moduleName, the hosts belong to
+// multiple modules are ignored.
+func (e *evaluator) getHostModuleNames(kt *kit.Kit, vendor enumor.Vendor, bizID int64, cloudIDs []string) (
+	map[string]string, error) {
+
+	moduleNames := make(map[string]string)
+	for _, batch := range slice.Split(cloudIDs, 200) {
+		listParams := &cmdb.ListBizHostParams{
+			BizID:  bizID,
+			Fields: []string{"bk_host_id", "bk_cloud_inst_id"},
+			Page:   cmdb.BasePage{Limit: 200},
+			HostPropertyFilter: &cmdb.QueryFilter{
+				Rule: &cmdb.CombinedRule{
+					Condition: "AND",
+					Rules: []cmdb.Rule{
+						&cmdb.AtomRule{Field: "bk_cloud_vendor", Operator: cmdb.OperatorEqual,
+							Value: cmdb.HcmCmdbVendorMap[vendor]},
+						&cmdb.AtomRule{Field: "bk_cloud_inst_id", Operator: cmdb.OperatorIn, Value: batch},
+					},
+				},
+			},
+		}
+		hosts, err := e.esbClient.Cmdb().ListBizHost(kt, listParams)
+		if err != nil {
+			logs.Errorf("list cmdb biz host failed, err: %v, biz: %d, rid: %s", err, bizID, kt.Rid)
+			return nil, err
+		}
+		if len(hosts.Info) == 0 {
+			continue
+		}
+
+		hostToCloud := make(map[int64]string, len(hosts.Info))
+		for _, host := range hosts.Info {
+			hostToCloud[host.BkHostID] = host.BkCloudInstID
+		}
+
+		relation, err := e.esbClient.Cmdb().FindHostTopoRelation(kt, &cmdb.FindHostTopoRelationParams{
+			BizID:   bizID,
+			HostIDs: maps.Keys(hostToCloud),
+			Page:    cmdb.BasePage{Limit: 500},
+		})
+		if err != nil {
+			logs.Errorf("find cmdb host topo relation failed, err: %v, biz: %d, rid: %s", err, bizID, kt.Rid)
+			return nil, err
+		}
+
+		hostModules := make(map[int64][]int64)
+		for _, rel := range relation.Data {
+			hostModules[rel.HostID] = append(hostModules[rel.HostID], rel.BkModuleID)
+		}
+
+		moduleIDs := make([]int64, 0)
+		for _, ids := range hostModules {
+			if len(ids) == 1 {
+				moduleIDs = append(moduleIDs, ids[0])
+			}
+		}
+		if len(moduleIDs) == 0 {
+			continue
+		}
+
+		modules, err := e.esbClient.Cmdb().SearchModule(kt, &cmdb.SearchModuleParams{
+			BizID:     bizID,
+			Fields:    []string{"bk_module_id", "bk_module_name"},
+			Page:      cmdb.BasePage{Limit: 200},
+			Condition: map[string]interface{}{"bk_module_id": map[string]interface{}{"$in": slice.Unique(moduleIDs)}},
+		})
+		if err != nil {
+			logs.Errorf("search cmdb module failed, err: %v, biz: %d, rid: %s", err, bizID, kt.Rid)
+			return nil, err
+		}
+
+		modNames := make(map[int64]string, len(modules.Info))
+		for _, module := range modules.Info {
+			modNames[module.BkModuleID] = module.BkModuleName
+		}
+
+		for hostID, ids := range hostModules {
+			if name, exists := modNames[ids[0]]; exists && len(ids) == 1 {
+				moduleNames[hostToCloud[hostID]] = name
+			}
+		}
+	}
+
+	return moduleNames, nil
+}
+
+// buildRemediateTasks 将需要补齐相同标签的同类资源合并到同一个任务中
+func buildRemediateTasks(vendor enumor.Vendor, accountID string, resources []*remediateRes) []ts.CustomFlowTask {
+	type group struct {
+		resType enumor.CloudResourceType
+		tags    map[string]string
+		resIDs  []string
+	}
+
+	groupMap := make(map[string]*group)
+	groupKeys := make([]string, 0)
+	for _, res := range resources {
+		if len(res.tags) == 0 {
+			continue
+		}
+
+		tags := make(map[string]string, len(res.tags))
+		pairs := make([]string, 0, len(res.tags))
+		for key, tag := range res.tags {
+			tags[key] = tag.value
+			pairs = append(pairs, key+"="+tag.value)
+		}
+		sort.Strings(pairs)
+
+		groupKey := string(res.resType) + "/" + strings.Join(pairs, ",")
+		g, exists := groupMap[groupKey]
+		if !exists {
+			g = &group{resType: res.resType, tags: tags}
+			groupMap[groupKey] = g
+			groupKeys = append(groupKeys, groupKey)
+		}
+		g.resIDs = append(g.resIDs, res.resID)
+	}
+
+	nextID := counter.NewNumStringCounter(1, 10)
+	tasks := make([]ts.CustomFlowTask, 0)
+	for _, groupKey := range groupKeys {
+		g := groupMap[groupKey]
+		for _, resIDs := range slice.Split(g.resIDs, constant.BatchOperationMaxLimit) {
+			tasks = append(tasks, ts.CustomFlowTask{
+				ActionID:   action.ActIDType(nextID()),
+				ActionName: enumor.ActionAddResourceTags,
+				Params: &actionrestag.AddResTagsOption{
+					Vendor:    vendor,
+					AccountID: accountID,
+					ResType:   g.resType,
+					ResIDs:    resIDs,
+					Tags:      g.tags,
+				},
+			})
+		}
+	}
+
+	return tasks
+}
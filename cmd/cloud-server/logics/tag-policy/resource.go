@@ -0,0 +1,203 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tagpolicy
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+)
+
+// listResources list the account's resources of the res type with their tags.
+func (e *evaluator) listResources(kt *kit.Kit, vendor enumor.Vendor, accountID string,
+	resType enumor.CloudResourceType) ([]tagResource, error) {
+
+	accountFilter := tools.ExpressionAnd(tools.RuleEqual("vendor", vendor), tools.RuleEqual("account_id", accountID))
+
+	var resources []tagResource
+	var err error
+	switch resType {
+	case enumor.CvmCloudResType:
+		resources, err = e.listCvm(kt, accountFilter)
+	case enumor.DiskCloudResType:
+		resources, err = e.listDisk(kt, accountFilter)
+	case enumor.SecurityGroupCloudResType:
+		resources, err = e.listSecurityGroup(kt, accountFilter)
+	case enumor.EipCloudResType:
+		resources, err = e.listEip(kt, accountFilter)
+	case enumor.VpcCloudResType:
+		resources, err = e.listVpc(kt, accountFilter)
+	case enumor.LoadBalancerCloudResType:
+		resources, err = e.listLoadBalancer(kt, accountFilter)
+	default:
+		return nil, fmt.Errorf("tag compliance not support res type: %s", resType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tagMap, err := e.listResourceTags(kt, accountID, resType)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range resources {
+		resources[i].Tags = tagMap[resources[i].ID]
+	}
+
+	return resources, nil
+}
+
+// listResourceTags list the account's resource tags of the res type, returns map[resID]map[tagKey]tagValue.
+func (e *evaluator) listResourceTags(kt *kit.Kit, accountID string, resType enumor.CloudResourceType) (
+	map[string]map[string]string, error) {
+
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("account_id", accountID), tools.RuleEqual("res_type", resType)),
+		Page:   &core.BasePage{Limit: constant.BatchOperationMaxLimit},
+	}
+	tagMap := make(map[string]map[string]string)
+	for {
+		result, err := e.client.DataService().Global.ResourceTag.List(kt, req)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tag := range result.Details {
+			if _, exists := tagMap[tag.ResID]; !exists {
+				tagMap[tag.ResID] = make(map[string]string)
+			}
+			tagMap[tag.ResID][tag.TagKey] = tag.TagValue
+		}
+
+		if uint(len(result.Details)) < req.Page.Limit {
+			break
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+
+	return tagMap, nil
+}
+
+func (e *evaluator) listCvm(kt *kit.Kit, expr *filter.Expression) ([]tagResource, error) {
+	return listAll(func(page *core.BasePage) ([]tagResource, error) {
+		result, err := e.client.DataService().Global.Cvm.ListCvm(kt, &core.ListReq{Filter: expr, Page: page})
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]tagResource, 0, len(result.Details))
+		for _, one := range result.Details {
+			resources = append(resources, tagResource{ID: one.ID, CloudID: one.CloudID, Name: one.Name,
+				BkBizID: one.BkBizID, Region: one.Region})
+		}
+		return resources, nil
+	})
+}
+
+func (e *evaluator) listDisk(kt *kit.Kit, expr *filter.Expression) ([]tagResource, error) {
+	return listAll(func(page *core.BasePage) ([]tagResource, error) {
+		result, err := e.client.DataService().Global.ListDisk(kt, &core.ListReq{Filter: expr, Page: page})
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]tagResource, 0, len(result.Details))
+		for _, one := range result.Details {
+			resources = append(resources, tagResource{ID: one.ID, CloudID: one.CloudID, Name: one.Name,
+				BkBizID: one.BkBizID, Region: one.Region})
+		}
+		return resources, nil
+	})
+}
+
+func (e *evaluator) listSecurityGroup(kt *kit.Kit, expr *filter.Expression) ([]tagResource, error) {
+	return listAll(func(page *core.BasePage) ([]tagResource, error) {
+		req := &protocloud.SecurityGroupListReq{Filter: expr, Page: page}
+		result, err := e.client.DataService().Global.SecurityGroup.ListSecurityGroup(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]tagResource, 0, len(result.Details))
+		for _, one := range result.Details {
+			resources = append(resources, tagResource{ID: one.ID, CloudID: one.CloudID, Name: one.Name,
+				BkBizID: one.BkBizID, Region: one.Region})
+		}
+		return resources, nil
+	})
+}
+
+func (e *evaluator) listEip(kt *kit.Kit, expr *filter.Expression) ([]tagResource, error) {
+	return listAll(func(page *core.BasePage) ([]tagResource, error) {
+		result, err := e.client.DataService().Global.ListEip(kt, &core.ListReq{Filter: expr, Page: page})
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]tagResource, 0, len(result.Details))
+		for _, one := range result.Details {
+			resources = append(resources, tagResource{ID: one.ID, CloudID: one.CloudID,
+				Name: converter.PtrToVal(one.Name), BkBizID: one.BkBizID, Region: one.Region})
+		}
+		return resources, nil
+	})
+}
+
+func (e *evaluator) listVpc(kt *kit.Kit, expr *filter.Expression) ([]tagResource, error) {
+	return listAll(func(page *core.BasePage) ([]tagResource, error) {
+		result, err := e.client.DataService().Global.Vpc.List(kt.Ctx, kt.Header(),
+			&core.ListReq{Filter: expr, Page: page})
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]tagResource, 0, len(result.Details))
+		for _, one := range result.Details {
+			resources = append(resources, tagResource{ID: one.ID, CloudID: one.CloudID, Name: one.Name,
+				BkBizID: one.BkBizID, Region: one.Region})
+		}
+		return resources, nil
+	})
+}
+
+func (e *evaluator) listLoadBalancer(kt *kit.Kit, expr *filter.Expression) ([]tagResource, error) {
+	return listAll(func(page *core.BasePage) ([]tagResource, error) {
+		result, err := e.client.DataService().Global.LoadBalancer.ListLoadBalancer(kt,
+			&core.ListReq{Filter: expr, Page: page})
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]tagResource, 0, len(result.Details))
+		for _, one := range result.Details {
+			resources = append(resources, tagResource{ID: one.ID, CloudID: one.CloudID, Name: one.Name,
+				BkBizID: one.BkBizID, Region: one.Region})
+		}
+		return resources, nil
+	})
+}
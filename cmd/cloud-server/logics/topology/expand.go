@@ -0,0 +1,420 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package topology
+
+import (
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	corert "hcm/pkg/api/core/cloud/route-table"
+	"hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/slice"
+)
+
+type expander func(t *topology, kt *kit.Kit, nodes []*node) ([]relation, error)
+
+// expanders define how to list the relations of each type of node.
+var expanders = map[enumor.CloudResourceType]expander{
+	enumor.VpcCloudResType:              (*topology).expandVpc,
+	enumor.SubnetCloudResType:           (*topology).expandSubnet,
+	enumor.CvmCloudResType:              (*topology).expandCvm,
+	enumor.NetworkInterfaceCloudResType: (*topology).expandNetworkInterface,
+	enumor.SecurityGroupCloudResType:    (*topology).expandSecurityGroup,
+	enumor.EipCloudResType:              (*topology).expandEip,
+	enumor.LoadBalancerCloudResType:     (*topology).expandLoadBalancer,
+	enumor.RouteTableCloudResType:       (*topology).expandRouteTable,
+}
+
+func nodeIDs(nodes []*node) []string {
+	return slice.Map(nodes, func(one *node) string { return one.ResID })
+}
+
+// attrRelations build the relations by the vpc and route table attributes of the nodes.
+func attrRelations(nodes []*node, vpcEdge enumor.TopologyEdgeType) []relation {
+	result := make([]relation, 0)
+	for _, one := range nodes {
+		if len(one.vpcID) != 0 && len(vpcEdge) != 0 {
+			result = append(result, newRelation(vpcEdge, enumor.VpcCloudResType, one.vpcID, one.ResType, one.ResID))
+		}
+
+		if len(one.routeTableID) != 0 {
+			result = append(result, newRelation(enumor.TopoRouteTableSubnet, enumor.RouteTableCloudResType,
+				one.routeTableID, one.ResType, one.ResID))
+		}
+	}
+
+	return result
+}
+
+func (t *topology) expandVpc(kt *kit.Kit, nodes []*node) ([]relation, error) {
+	ids := nodeIDs(nodes)
+	return t.collect(
+		func() ([]relation, error) { return t.subnetRelations(kt, "vpc_id", ids) },
+		func() ([]relation, error) { return t.vpcCvmRelations(kt, "vpc_id", ids) },
+		func() ([]relation, error) { return t.routeTableRelations(kt, ids) },
+		func() ([]relation, error) { return t.vpcLbRelations(kt, ids) },
+	)
+}
+
+func (t *topology) expandSubnet(kt *kit.Kit, nodes []*node) ([]relation, error) {
+	ids := nodeIDs(nodes)
+	return t.collect(
+		func() ([]relation, error) { return attrRelations(nodes, enumor.TopoVpcSubnet), nil },
+		func() ([]relation, error) { return t.subnetCvmRelations(kt, "subnet_id", ids) },
+		func() ([]relation, error) { return t.sgCommonRelations(kt, enumor.SubnetCloudResType, ids) },
+	)
+}
+
+func (t *topology) expandCvm(kt *kit.Kit, nodes []*node) ([]relation, error) {
+	ids := nodeIDs(nodes)
+	return t.collect(
+		func() ([]relation, error) { return t.vpcCvmRelations(kt, "cvm_id", ids) },
+		func() ([]relation, error) { return t.subnetCvmRelations(kt, "cvm_id", ids) },
+		func() ([]relation, error) { return t.niCvmRelations(kt, "cvm_id", ids) },
+		func() ([]relation, error) { return t.sgCvmRelations(kt, "cvm_id", ids) },
+		func() ([]relation, error) { return t.sgCommonRelations(kt, enumor.CvmCloudResType, ids) },
+		func() ([]relation, error) { return t.eipCvmRelations(kt, "cvm_id", ids) },
+		func() ([]relation, error) { return t.cvmTargetRelations(kt, ids) },
+	)
+}
+
+func (t *topology) expandNetworkInterface(kt *kit.Kit, nodes []*node) ([]relation, error) {
+	return t.niCvmRelations(kt, "network_interface_id", nodeIDs(nodes))
+}
+
+func (t *topology) expandSecurityGroup(kt *kit.Kit, nodes []*node) ([]relation, error) {
+	ids := nodeIDs(nodes)
+	return t.collect(
+		func() ([]relation, error) { return t.sgCvmRelations(kt, "security_group_id", ids) },
+		func() ([]relation, error) { return t.sgCommonRelations(kt, "", ids) },
+	)
+}
+
+func (t *topology) expandEip(kt *kit.Kit, nodes []*node) ([]relation, error) {
+	return t.eipCvmRelations(kt, "eip_id", nodeIDs(nodes))
+}
+
+func (t *topology) expandLoadBalancer(kt *kit.Kit, nodes []*node) ([]relation, error) {
+	ids := nodeIDs(nodes)
+	return t.collect(
+		func() ([]relation, error) { return attrRelations(nodes, enumor.TopoVpcLoadBalancer), nil },
+		func() ([]relation, error) { return t.sgCommonRelations(kt, enumor.LoadBalancerCloudResType, ids) },
+		func() ([]relation, error) { return t.lbTargetRelations(kt, ids) },
+	)
+}
+
+func (t *topology) expandRouteTable(kt *kit.Kit, nodes []*node) ([]relation, error) {
+	ids := nodeIDs(nodes)
+	return t.collect(
+		func() ([]relation, error) { return attrRelations(nodes, enumor.TopoVpcRouteTable), nil },
+		func() ([]relation, error) { return t.subnetRelations(kt, "route_table_id", ids) },
+	)
+}
+
+func (t *topology) collect(listFuncs ...func() ([]relation, error)) ([]relation, error) {
+	result := make([]relation, 0)
+	for _, list := range listFuncs {
+		relations, err := list()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, relations...)
+	}
+
+	return result, nil
+}
+
+// subnetRelations list the vpc or route table relations of subnets, field is vpc_id or route_table_id.
+func (t *topology) subnetRelations(kt *kit.Kit, field string, ids []string) ([]relation, error) {
+	subnets, err := listByIDs(ids, func(ids []string, page *core.BasePage) ([]corecloud.BaseSubnet, error) {
+		req := &core.ListReq{Filter: tools.ContainersExpression(field, ids), Page: page}
+		res, err := t.client.DataService().Global.Subnet.List(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]relation, 0, len(subnets))
+	for _, one := range subnets {
+		result = append(result, attrRelations([]*node{{
+			TopologyNode: proto.TopologyNode{ResType: enumor.SubnetCloudResType, ResID: one.ID},
+			vpcID:        one.VpcID,
+			routeTableID: one.RouteTableID,
+		}}, enumor.TopoVpcSubnet)...)
+	}
+
+	return result, nil
+}
+
+func (t *topology) routeTableRelations(kt *kit.Kit, vpcIDs []string) ([]relation, error) {
+	routeTables, err := listByIDs(vpcIDs, func(ids []string, page *core.BasePage) ([]corert.BaseRouteTable, error) {
+		req := &core.ListReq{Filter: tools.ContainersExpression("vpc_id", ids), Page: page}
+		res, err := t.client.DataService().Global.RouteTable.List(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slice.Map(routeTables, func(one corert.BaseRouteTable) relation {
+		return newRelation(enumor.TopoVpcRouteTable, enumor.VpcCloudResType, one.VpcID,
+			enumor.RouteTableCloudResType, one.ID)
+	}), nil
+}
+
+func (t *topology) vpcLbRelations(kt *kit.Kit, vpcIDs []string) ([]relation, error) {
+	lbs, err := listByIDs(vpcIDs, func(ids []string, page *core.BasePage) ([]corelb.BaseLoadBalancer, error) {
+		req := &core.ListReq{Filter: tools.ContainersExpression("vpc_id", ids), Page: page}
+		res, err := t.client.DataService().Global.LoadBalancer.ListLoadBalancer(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slice.Map(lbs, func(one corelb.BaseLoadBalancer) relation {
+		return newRelation(enumor.TopoVpcLoadBalancer, enumor.VpcCloudResType, one.VpcID,
+			enumor.LoadBalancerCloudResType, one.ID)
+	}), nil
+}
+
+// vpcCvmRelations list vpc cvm relations, field is vpc_id or cvm_id.
+func (t *topology) vpcCvmRelations(kt *kit.Kit, field string, ids []string) ([]relation, error) {
+	rels, err := listByIDs(ids, func(ids []string, page *core.BasePage) ([]corecloud.VpcCvmRel, error) {
+		req := &core.ListReq{Filter: tools.ContainersExpression(field, ids), Page: page}
+		res, err := t.client.DataService().Global.VpcCvmRel.List(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slice.Map(rels, func(one corecloud.VpcCvmRel) relation {
+		return newRelation(enumor.TopoVpcCvm, enumor.VpcCloudResType, one.VpcID, enumor.CvmCloudResType, one.CvmID)
+	}), nil
+}
+
+// subnetCvmRelations list subnet cvm relations, field is subnet_id or cvm_id.
+func (t *topology) subnetCvmRelations(kt *kit.Kit, field string, ids []string) ([]relation, error) {
+	rels, err := listByIDs(ids, func(ids []string, page *core.BasePage) ([]corecloud.SubnetCvmRel, error) {
+		req := &core.ListReq{Filter: tools.ContainersExpression(field, ids), Page: page}
+		res, err := t.client.DataService().Global.SubnetCvmRel.List(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slice.Map(rels, func(one corecloud.SubnetCvmRel) relation {
+		return newRelation(enumor.TopoSubnetCvm, enumor.SubnetCloudResType, one.SubnetID, enumor.CvmCloudResType,
+			one.CvmID)
+	}), nil
+}
+
+// niCvmRelations list network interface cvm relations, field is network_interface_id or cvm_id.
+func (t *topology) niCvmRelations(kt *kit.Kit, field string, ids []string) ([]relation, error) {
+	rels, err := listByIDs(ids, func(ids []string, page *core.BasePage) ([]*cloud.NetworkInterfaceCvmRelResult,
+		error) {
+
+		req := &core.ListReq{Filter: tools.ContainersExpression(field, ids), Page: page}
+		res, err := t.client.DataService().Global.NetworkInterfaceCvmRel.List(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slice.Map(rels, func(one *cloud.NetworkInterfaceCvmRelResult) relation {
+		return newRelation(enumor.TopoCvmNetworkInterface, enumor.CvmCloudResType, one.CvmID,
+			enumor.NetworkInterfaceCloudResType, one.NetworkInterfaceID)
+	}), nil
+}
+
+// sgCvmRelations list security group cvm relations, field is security_group_id or cvm_id.
+func (t *topology) sgCvmRelations(kt *kit.Kit, field string, ids []string) ([]relation, error) {
+	rels, err := listByIDs(ids, func(ids []string, page *core.BasePage) ([]corecloud.SecurityGroupCvmRel, error) {
+		req := &core.ListReq{Filter: tools.ContainersExpression(field, ids), Page: page}
+		res, err := t.client.DataService().Global.SGCvmRel.List(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slice.Map(rels, func(one corecloud.SecurityGroupCvmRel) relation {
+		return newRelation(enumor.TopoSecurityGroupBinding, enumor.SecurityGroupCloudResType, one.SecurityGroupID,
+			enumor.CvmCloudResType, one.CvmID)
+	}), nil
+}
+
+// sgCommonRelations list security group common relations. if resType is empty, ids are security group ids,
+// otherwise ids are the ids of the bound resources.
+func (t *topology) sgCommonRelations(kt *kit.Kit, resType enumor.CloudResourceType, ids []string) ([]relation,
+	error) {
+
+	rels, err := listByIDs(ids, func(ids []string, page *core.BasePage) ([]corecloud.SecurityGroupCommonRel,
+		error) {
+
+		req := &core.ListReq{Filter: tools.ContainersExpression("security_group_id", ids), Page: page}
+		if len(resType) != 0 {
+			req.Filter = tools.ExpressionAnd(tools.RuleEqual("res_type", resType), tools.RuleIn("res_id", ids))
+		}
+		res, err := t.client.DataService().Global.SGCommonRel.List(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]relation, 0, len(rels))
+	for _, one := range rels {
+		if !proto.IsTopologyResType(one.ResType) {
+			continue
+		}
+		result = append(result, newRelation(enumor.TopoSecurityGroupBinding, enumor.SecurityGroupCloudResType,
+			one.SecurityGroupID, one.ResType, one.ResID))
+	}
+
+	return result, nil
+}
+
+// eipCvmRelations list eip cvm relations, field is eip_id or cvm_id.
+func (t *topology) eipCvmRelations(kt *kit.Kit, field string, ids []string) ([]relation, error) {
+	rels, err := listByIDs(ids, func(ids []string, page *core.BasePage) ([]*cloud.EipCvmRelResult, error) {
+		req := &core.ListReq{Filter: tools.ContainersExpression(field, ids), Page: page}
+		res, err := t.client.DataService().Global.ListEipCvmRel(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slice.Map(rels, func(one *cloud.EipCvmRelResult) relation {
+		return newRelation(enumor.TopoEipCvm, enumor.EipCloudResType, one.EipID, enumor.CvmCloudResType, one.CvmID)
+	}), nil
+}
+
+// lbTargetRelations list the backend cvms of load balancers through target groups.
+func (t *topology) lbTargetRelations(kt *kit.Kit, lbIDs []string) ([]relation, error) {
+	tgRels, err := t.listTgLbRels(kt, "lb_id", lbIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tgIDs := slice.Map(tgRels, func(one corelb.BaseTargetListenerRuleRel) string { return one.TargetGroupID })
+	targets, err := t.listCvmTargets(kt, "target_group_id", tgIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return targetRelations(tgRels, targets), nil
+}
+
+// cvmTargetRelations list the load balancers which use the cvms as backend through target groups.
+func (t *topology) cvmTargetRelations(kt *kit.Kit, cvmIDs []string) ([]relation, error) {
+	targets, err := t.listCvmTargets(kt, "inst_id", cvmIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tgIDs := slice.Map(targets, func(one corelb.BaseTarget) string { return one.TargetGroupID })
+	tgRels, err := t.listTgLbRels(kt, "target_group_id", tgIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return targetRelations(tgRels, targets), nil
+}
+
+func targetRelations(tgRels []corelb.BaseTargetListenerRuleRel, targets []corelb.BaseTarget) []relation {
+	tgLbIDs := make(map[string][]string)
+	for _, one := range tgRels {
+		tgLbIDs[one.TargetGroupID] = append(tgLbIDs[one.TargetGroupID], one.LbID)
+	}
+
+	result := make([]relation, 0)
+	for _, target := range targets {
+		for _, lbID := range tgLbIDs[target.TargetGroupID] {
+			result = append(result, newRelation(enumor.TopoLoadBalancerTarget, enumor.LoadBalancerCloudResType, lbID,
+				enumor.CvmCloudResType, target.InstID))
+		}
+	}
+
+	return result
+}
+
+func (t *topology) listTgLbRels(kt *kit.Kit, field string, ids []string) ([]corelb.BaseTargetListenerRuleRel,
+	error) {
+
+	return listByIDs(ids, func(ids []string, page *core.BasePage) ([]corelb.BaseTargetListenerRuleRel, error) {
+		req := &core.ListReq{Filter: tools.ContainersExpression(field, ids), Page: page}
+		res, err := t.client.DataService().Global.LoadBalancer.ListTargetGroupListenerRel(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	})
+}
+
+func (t *topology) listCvmTargets(kt *kit.Kit, field string, ids []string) ([]corelb.BaseTarget, error) {
+	return listByIDs(ids, func(ids []string, page *core.BasePage) ([]corelb.BaseTarget, error) {
+		req := &core.ListReq{
+			Filter: tools.ExpressionAnd(tools.RuleEqual("inst_type", enumor.CvmInstType), tools.RuleIn(field, ids)),
+			Page:   page,
+		}
+		res, err := t.client.DataService().Global.LoadBalancer.ListTarget(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		return res.Details, nil
+	})
+}
@@ -0,0 +1,156 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package topology
+
+import (
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/criteria/enumor"
+)
+
+// node is the topology node with the attributes used to expand relations.
+type node struct {
+	proto.TopologyNode
+	vpcID        string
+	routeTableID string
+}
+
+type nodeRef struct {
+	resType enumor.CloudResourceType
+	id      string
+}
+
+func (r nodeRef) key() string {
+	return proto.TopologyNodeKey(r.resType, r.id)
+}
+
+// relation is the directed relation between two resources.
+type relation struct {
+	edgeType enumor.TopologyEdgeType
+	source   nodeRef
+	target   nodeRef
+}
+
+func newRelation(edgeType enumor.TopologyEdgeType, sourceType enumor.CloudResourceType, sourceID string,
+	targetType enumor.CloudResourceType, targetID string) relation {
+
+	return relation{
+		edgeType: edgeType,
+		source:   nodeRef{resType: sourceType, id: sourceID},
+		target:   nodeRef{resType: targetType, id: targetID},
+	}
+}
+
+type graph struct {
+	maxNodes  int
+	edgeTypes map[enumor.TopologyEdgeType]struct{}
+
+	nodes     map[string]*node
+	nodeOrder []string
+	edges     map[proto.TopologyEdge]struct{}
+	edgeOrder []proto.TopologyEdge
+	truncated bool
+}
+
+func newGraph(maxNodes int, edgeTypes []enumor.TopologyEdgeType) *graph {
+	g := &graph{
+		maxNodes:  maxNodes,
+		nodes:     make(map[string]*node),
+		nodeOrder: make([]string, 0),
+		edges:     make(map[proto.TopologyEdge]struct{}),
+		edgeOrder: make([]proto.TopologyEdge, 0),
+	}
+
+	if len(edgeTypes) != 0 {
+		g.edgeTypes = make(map[enumor.TopologyEdgeType]struct{}, len(edgeTypes))
+		for _, one := range edgeTypes {
+			g.edgeTypes[one] = struct{}{}
+		}
+	}
+
+	return g
+}
+
+// filterRelations filter the relations whose type is not required, and the relations whose endpoint is invalid.
+func (g *graph) filterRelations(relations []relation) []relation {
+	result := make([]relation, 0, len(relations))
+	for _, one := range relations {
+		if len(one.source.id) == 0 || len(one.target.id) == 0 {
+			continue
+		}
+
+		if g.edgeTypes != nil {
+			if _, exists := g.edgeTypes[one.edgeType]; !exists {
+				continue
+			}
+		}
+		result = append(result, one)
+	}
+
+	return result
+}
+
+// addNode add node to graph, returns false if the node already exists or the graph is full.
+func (g *graph) addNode(n *node) bool {
+	if _, exists := g.nodes[n.Key]; exists {
+		return false
+	}
+
+	if len(g.nodes) >= g.maxNodes {
+		g.truncated = true
+		return false
+	}
+
+	g.nodes[n.Key] = n
+	g.nodeOrder = append(g.nodeOrder, n.Key)
+	return true
+}
+
+// addEdge add edge to graph, the edge whose endpoint is not in graph is ignored.
+func (g *graph) addEdge(rel relation) {
+	edge := proto.TopologyEdge{Type: rel.edgeType, Source: rel.source.key(), Target: rel.target.key()}
+	if _, exists := g.nodes[edge.Source]; !exists {
+		return
+	}
+
+	if _, exists := g.nodes[edge.Target]; !exists {
+		return
+	}
+
+	if _, exists := g.edges[edge]; exists {
+		return
+	}
+
+	g.edges[edge] = struct{}{}
+	g.edgeOrder = append(g.edgeOrder, edge)
+}
+
+func (g *graph) result(root string) *proto.TopologyGraph {
+	nodes := make([]proto.TopologyNode, 0, len(g.nodeOrder))
+	for _, key := range g.nodeOrder {
+		nodes = append(nodes, g.nodes[key].TopologyNode)
+	}
+
+	return &proto.TopologyGraph{
+		Root:      root,
+		Nodes:     nodes,
+		Edges:     g.edgeOrder,
+		Truncated: g.truncated,
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package topology
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestGraph(t *testing.T) {
+	g := newGraph(3, []enumor.TopologyEdgeType{enumor.TopoVpcSubnet, enumor.TopoSubnetCvm})
+
+	vpc := newNode(enumor.VpcCloudResType, "vpc1", "vpc-1", "vpc", enumor.TCloud, 1)
+	if !g.addNode(vpc) || g.addNode(vpc) {
+		t.Fatalf("add node should succeed only once")
+	}
+
+	relations := g.filterRelations([]relation{
+		newRelation(enumor.TopoVpcSubnet, enumor.VpcCloudResType, "vpc1", enumor.SubnetCloudResType, "subnet1"),
+		newRelation(enumor.TopoVpcCvm, enumor.VpcCloudResType, "vpc1", enumor.CvmCloudResType, "cvm1"),
+		newRelation(enumor.TopoSubnetCvm, enumor.SubnetCloudResType, "subnet1", enumor.CvmCloudResType, "cvm1"),
+		newRelation(enumor.TopoSubnetCvm, enumor.SubnetCloudResType, "subnet1", enumor.CvmCloudResType, "cvm2"),
+		newRelation(enumor.TopoSubnetCvm, enumor.SubnetCloudResType, "subnet1", enumor.CvmCloudResType, ""),
+	})
+	if len(relations) != 3 {
+		t.Fatalf("got %d relations after filter, expect: 3", len(relations))
+	}
+
+	g.addNode(newNode(enumor.SubnetCloudResType, "subnet1", "subnet-1", "subnet", enumor.TCloud, 1))
+	g.addNode(newNode(enumor.CvmCloudResType, "cvm1", "ins-1", "cvm1", enumor.TCloud, 1))
+	if g.addNode(newNode(enumor.CvmCloudResType, "cvm2", "ins-2", "cvm2", enumor.TCloud, 1)) || !g.truncated {
+		t.Fatalf("graph should be truncated when node count exceeds max nodes")
+	}
+
+	for _, one := range append(relations, relations...) {
+		g.addEdge(one)
+	}
+
+	result := g.result(vpc.Key)
+	if result.Root != "vpc:vpc1" || len(result.Nodes) != 3 || !result.Truncated {
+		t.Fatalf("got unexpected graph: %+v", result)
+	}
+
+	// cvm2不在图中，对应的边被忽略，重复的边只保留一条
+	if len(result.Edges) != 2 || result.Edges[1].Source != "subnet:subnet1" || result.Edges[1].Target != "cvm:cvm1" {
+		t.Errorf("got unexpected edges: %+v", result.Edges)
+	}
+}
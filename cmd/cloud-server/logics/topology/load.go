@@ -0,0 +1,215 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package topology
+
+import (
+	"fmt"
+
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	coreni "hcm/pkg/api/core/cloud/network-interface"
+	corert "hcm/pkg/api/core/cloud/route-table"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/api/data-service/cloud/eip"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+func newNode(resType enumor.CloudResourceType, id, cloudID, name string, vendor enumor.Vendor,
+	bizID int64) *node {
+
+	return &node{
+		TopologyNode: proto.TopologyNode{
+			Key:     proto.TopologyNodeKey(resType, id),
+			ResType: resType,
+			ResID:   id,
+			CloudID: cloudID,
+			Name:    name,
+			Vendor:  vendor,
+			BkBizID: bizID,
+		},
+	}
+}
+
+// loadNodes load the topology nodes of the resources.
+func (t *topology) loadNodes(kt *kit.Kit, resType enumor.CloudResourceType, ids []string) ([]*node, error) {
+	ds := t.client.DataService().Global
+	var nodes []*node
+	var err error
+	switch resType {
+	case enumor.VpcCloudResType:
+		nodes, err = listNodes(ids, func(req *core.ListReq) ([]*node, error) {
+			res, err := ds.Vpc.List(kt.Ctx, kt.Header(), req)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(res.Details, func(one corecloud.BaseVpc) *node {
+				return newNode(resType, one.ID, one.CloudID, one.Name, one.Vendor, one.BkBizID)
+			}), nil
+		})
+
+	case enumor.SubnetCloudResType:
+		nodes, err = listNodes(ids, func(req *core.ListReq) ([]*node, error) {
+			res, err := ds.Subnet.List(kt.Ctx, kt.Header(), req)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(res.Details, func(one corecloud.BaseSubnet) *node {
+				n := newNode(resType, one.ID, one.CloudID, one.Name, one.Vendor, one.BkBizID)
+				n.vpcID = one.VpcID
+				n.routeTableID = one.RouteTableID
+				return n
+			}), nil
+		})
+
+	case enumor.CvmCloudResType:
+		nodes, err = listNodes(ids, func(req *core.ListReq) ([]*node, error) {
+			res, err := ds.Cvm.ListCvm(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(res.Details, func(one corecvm.BaseCvm) *node {
+				return newNode(resType, one.ID, one.CloudID, one.Name, one.Vendor, one.BkBizID)
+			}), nil
+		})
+
+	case enumor.NetworkInterfaceCloudResType:
+		nodes, err = listNodes(ids, func(req *core.ListReq) ([]*node, error) {
+			res, err := ds.NetworkInterface.List(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(res.Details, func(one coreni.BaseNetworkInterface) *node {
+				n := newNode(resType, one.ID, one.CloudID, one.Name, one.Vendor, one.BkBizID)
+				n.vpcID = one.VpcID
+				return n
+			}), nil
+		})
+
+	case enumor.SecurityGroupCloudResType:
+		nodes, err = listNodes(ids, func(req *core.ListReq) ([]*node, error) {
+			sgReq := &protocloud.SecurityGroupListReq{Filter: req.Filter, Page: req.Page}
+			res, err := ds.SecurityGroup.ListSecurityGroup(kt.Ctx, kt.Header(), sgReq)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(res.Details, func(one corecloud.BaseSecurityGroup) *node {
+				return newNode(resType, one.ID, one.CloudID, one.Name, one.Vendor, one.BkBizID)
+			}), nil
+		})
+
+	case enumor.EipCloudResType:
+		nodes, err = listNodes(ids, func(req *core.ListReq) ([]*node, error) {
+			res, err := ds.ListEip(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(res.Details, func(one *eip.EipResult) *node {
+				return newNode(resType, one.ID, one.CloudID, converter.PtrToVal(one.Name), enumor.Vendor(one.Vendor),
+					one.BkBizID)
+			}), nil
+		})
+
+	case enumor.LoadBalancerCloudResType:
+		nodes, err = listNodes(ids, func(req *core.ListReq) ([]*node, error) {
+			res, err := ds.LoadBalancer.ListLoadBalancer(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(res.Details, func(one corelb.BaseLoadBalancer) *node {
+				n := newNode(resType, one.ID, one.CloudID, one.Name, one.Vendor, one.BkBizID)
+				n.vpcID = one.VpcID
+				return n
+			}), nil
+		})
+
+	case enumor.RouteTableCloudResType:
+		nodes, err = listNodes(ids, func(req *core.ListReq) ([]*node, error) {
+			res, err := ds.RouteTable.List(kt.Ctx, kt.Header(), req)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(res.Details, func(one corert.BaseRouteTable) *node {
+				n := newNode(resType, one.ID, one.CloudID, one.Name, one.Vendor, one.BkBizID)
+				n.vpcID = one.VpcID
+				return n
+			}), nil
+		})
+
+	default:
+		return nil, fmt.Errorf("topology node type: %s not support", resType)
+	}
+	if err != nil {
+		logs.Errorf("load %s topology nodes failed, err: %v, ids: %v, rid: %s", resType, err, ids, kt.Rid)
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// listNodes list nodes by ids in batches.
+func listNodes(ids []string, list func(req *core.ListReq) ([]*node, error)) ([]*node, error) {
+	return listByIDs(ids, func(ids []string, page *core.BasePage) ([]*node, error) {
+		return list(&core.ListReq{Filter: tools.ContainersExpression("id", ids), Page: page})
+	})
+}
+
+// listByIDs list all resources by ids in batches, the ids of each batch are used as an "in" filter value.
+func listByIDs[T any](ids []string, list func(ids []string, page *core.BasePage) ([]T, error)) ([]T, error) {
+	result := make([]T, 0)
+	for _, batch := range slice.Split(slice.Unique(ids), int(core.DefaultMaxPageLimit)) {
+		details, err := listAll(func(page *core.BasePage) ([]T, error) {
+			return list(batch, page)
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, details...)
+	}
+
+	return result, nil
+}
+
+func listAll[T any](list func(page *core.BasePage) ([]T, error)) ([]T, error) {
+	page := core.NewDefaultBasePage()
+	result := make([]T, 0)
+	for {
+		details, err := list(page)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, details...)
+		if uint(len(details)) < page.Limit {
+			break
+		}
+
+		page.Start += uint32(page.Limit)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,167 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package topology ...
+package topology
+
+import (
+	"sort"
+
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// Interface define network topology interface.
+type Interface interface {
+	Graph(kt *kit.Kit, bizID int64, req *proto.TopologyGraphReq) (*proto.TopologyGraph, error)
+}
+
+// NewTopology new network topology.
+func NewTopology(client *client.ClientSet) Interface {
+	return &topology{
+		client: client,
+	}
+}
+
+type topology struct {
+	client *client.ClientSet
+}
+
+// Graph build the network topology graph rooted at the resource, the relations are expanded breadth first from the
+// root until the depth or max nodes limit is reached. only the resources of the biz are included in the graph.
+func (t *topology) Graph(kt *kit.Kit, bizID int64, req *proto.TopologyGraphReq) (*proto.TopologyGraph, error) {
+	roots, err := t.loadNodes(kt, req.ResType, []string{req.ResID})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(roots) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "%s: %s not found", req.ResType, req.ResID)
+	}
+	root := roots[0]
+
+	if root.BkBizID != bizID {
+		return nil, errf.Newf(errf.InvalidParameter, "%s: %s not belongs to biz: %d", req.ResType, req.ResID, bizID)
+	}
+
+	g := newGraph(req.MaxNodes, req.EdgeTypes)
+	g.addNode(root)
+
+	frontier := []*node{root}
+	for depth := 1; depth <= req.Depth && len(frontier) != 0 && !g.truncated; depth++ {
+		relations, err := t.expand(kt, frontier)
+		if err != nil {
+			return nil, err
+		}
+
+		relations = g.filterRelations(relations)
+		newNodes, err := t.loadNewNodes(kt, g, relations)
+		if err != nil {
+			return nil, err
+		}
+
+		frontier = make([]*node, 0, len(newNodes))
+		for _, one := range newNodes {
+			if one.BkBizID != bizID {
+				continue
+			}
+
+			one.Depth = depth
+			if g.addNode(one) {
+				frontier = append(frontier, one)
+			}
+		}
+
+		for _, one := range relations {
+			g.addEdge(one)
+		}
+	}
+
+	return g.result(root.Key), nil
+}
+
+// expand list the relations of the nodes.
+func (t *topology) expand(kt *kit.Kit, nodes []*node) ([]relation, error) {
+	typeNodes := make(map[enumor.CloudResourceType][]*node)
+	for _, one := range nodes {
+		typeNodes[one.ResType] = append(typeNodes[one.ResType], one)
+	}
+
+	relations := make([]relation, 0)
+	for _, resType := range sortedTypes(typeNodes) {
+		expander, exists := expanders[resType]
+		if !exists {
+			continue
+		}
+
+		rels, err := expander(t, kt, typeNodes[resType])
+		if err != nil {
+			logs.Errorf("expand %s topology relations failed, err: %v, rid: %s", resType, err, kt.Rid)
+			return nil, err
+		}
+		relations = append(relations, rels...)
+	}
+
+	return relations, nil
+}
+
+// loadNewNodes load the relation endpoints which are not in graph.
+func (t *topology) loadNewNodes(kt *kit.Kit, g *graph, relations []relation) ([]*node, error) {
+	typeIDs := make(map[enumor.CloudResourceType][]string)
+	added := make(map[string]struct{})
+	for _, rel := range relations {
+		for _, ref := range []nodeRef{rel.source, rel.target} {
+			key := ref.key()
+			if _, exists := g.nodes[key]; exists {
+				continue
+			}
+
+			if _, exists := added[key]; exists {
+				continue
+			}
+			added[key] = struct{}{}
+			typeIDs[ref.resType] = append(typeIDs[ref.resType], ref.id)
+		}
+	}
+
+	result := make([]*node, 0)
+	for _, resType := range sortedTypes(typeIDs) {
+		nodes, err := t.loadNodes(kt, resType, typeIDs[resType])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, nodes...)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
+
+func sortedTypes[T any](m map[enumor.CloudResourceType]T) []enumor.CloudResourceType {
+	types := make([]enumor.CloudResourceType, 0, len(m))
+	for resType := range m {
+		types = append(types, resType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
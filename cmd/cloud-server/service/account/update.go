@@ -83,6 +83,12 @@ func (a *accountSvc) Update(cts *rest.Contexts) (interface{}, error) {
 		if err != nil {
 			return nil, err
 		}
+
+		// 账号所属业务变更会影响用户对该账号下资源的权限，需要清理鉴权缓存，清理失败时缓存会在过期后自动失效
+		if err = a.authorizer.InvalidateCache(cts.Kit); err != nil {
+			logs.Errorf("invalidate auth cache after account biz rel changed failed, err: %v, rid: %s", err,
+				cts.Kit.Rid)
+		}
 	}
 
 	switch baseInfo.Vendor {
@@ -29,11 +29,13 @@ import (
 	azurecvmhandler "hcm/cmd/cloud-server/service/application/handlers/cvm/azure"
 	gcpcvmhandler "hcm/cmd/cloud-server/service/application/handlers/cvm/gcp"
 	huaweicvmhandler "hcm/cmd/cloud-server/service/application/handlers/cvm/huawei"
+	resizecvmhandler "hcm/cmd/cloud-server/service/application/handlers/cvm/resize"
 	tcloudcvmhandler "hcm/cmd/cloud-server/service/application/handlers/cvm/tcloud"
 	awsdiskhandler "hcm/cmd/cloud-server/service/application/handlers/disk/aws"
 	azurediskhandler "hcm/cmd/cloud-server/service/application/handlers/disk/azure"
 	gcpdiskhandler "hcm/cmd/cloud-server/service/application/handlers/disk/gcp"
 	huaweidiskhandler "hcm/cmd/cloud-server/service/application/handlers/disk/huawei"
+	resizediskhandler "hcm/cmd/cloud-server/service/application/handlers/disk/resize"
 	tclouddiskhandler "hcm/cmd/cloud-server/service/application/handlers/disk/tcloud"
 	"hcm/cmd/cloud-server/service/application/handlers/load_balancer/tcloud"
 	createmainaccount "hcm/cmd/cloud-server/service/application/handlers/main-account/create-main-account"
@@ -52,6 +54,7 @@ import (
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 	"hcm/pkg/tools/json"
@@ -307,6 +310,18 @@ func (a *applicationSvc) getHandlerByApplication(cts *rest.Contexts, application
 		return a.getHandlerOfCreateDisk(opt, vendor, application)
 	case enumor.CreateLoadBalancer:
 		return a.getHandlerOfCreateLoadBalancer(opt, vendor, application)
+	case enumor.ResizeCvm:
+		req, err := parseReqFromApplicationContent[cscvm.ResizeCvmReq](application.Content)
+		if err != nil {
+			return nil, err
+		}
+		return resizecvmhandler.NewApplicationOfResizeCvm(opt, vendor, req), nil
+	case enumor.ResizeDisk:
+		req, err := parseReqFromApplicationContent[csdisk.ResizeDiskReq](application.Content)
+		if err != nil {
+			return nil, err
+		}
+		return resizediskhandler.NewApplicationOfResizeDisk(opt, vendor, req), nil
 	case enumor.CreateMainAccount:
 		req, err := parseReqFromApplicationContent[proto.MainAccountCreateReq](application.Content)
 		if err != nil {
@@ -379,6 +394,15 @@ func (a *applicationSvc) deliver(cts *rest.Contexts, application *dataproto.Appl
 		deliverStatus = enumor.DeliverError
 	}
 
+	// 同步交付的资源在交付后写入申请单标签，标签写入失败不影响已交付的资源，失败原因记录到交付详情中
+	if err == nil {
+		if err = a.tagDeliveredResources(cts.Kit, application, deliveryDetail); err != nil {
+			logs.Errorf("tag delivered resources of application[id=%s] failed, err: %v, rid: %s", application.ID,
+				err, cts.Kit.Rid)
+			deliveryDetail["tag_error"] = err.Error()
+		}
+	}
+
 	// 更新DB里单据的交付状态和详情
 	deliveryDetailStr, err = json.MarshalToString(deliveryDetail)
 	if err != nil {
@@ -388,3 +412,40 @@ func (a *applicationSvc) deliver(cts *rest.Contexts, application *dataproto.Appl
 		deliveryDetailStr = `{"error": "marshal deliver detail failed"}`
 	}
 }
+
+// tagDeliveredResources 将申请单标签写入硬盘、VPC、负载均衡申请单交付的资源，主机申请单为异步交付，在交付完成后写入
+func (a *applicationSvc) tagDeliveredResources(kt *kit.Kit, application *dataproto.ApplicationResp,
+	deliveryDetail map[string]interface{}) error {
+
+	tags, err := handlers.ParseApplicationTags(application.Content)
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	switch application.Type {
+	case enumor.CreateDisk:
+		ids, _ := deliveryDetail["disk_ids"].([]string)
+		return handlers.TagDeliveredResources(kt, a.client, tags, enumor.DiskCloudResType, ids)
+	case enumor.CreateVpc:
+		id, _ := deliveryDetail["vpc_id"].(string)
+		if len(id) == 0 {
+			return nil
+		}
+		return handlers.TagDeliveredResources(kt, a.client, tags, enumor.VpcCloudResType, []string{id})
+	case enumor.CreateLoadBalancer:
+		cloudIDs, _ := deliveryDetail["load_balancer_id"].([]string)
+		if len(cloudIDs) == 0 {
+			return nil
+		}
+		ids, err := handlers.ListLoadBalancerIDsByCloudIDs(kt, a.client, cloudIDs)
+		if err != nil {
+			return err
+		}
+		return handlers.TagDeliveredResources(kt, a.client, tags, enumor.LoadBalancerCloudResType, ids)
+	}
+
+	return nil
+}
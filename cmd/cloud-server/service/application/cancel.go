@@ -42,10 +42,18 @@ func (a *applicationSvc) Cancel(cts *rest.Contexts) (interface{}, error) {
 		)
 	}
 
-	// 根据SN调用ITSM接口撤销单据
-	err = a.itsmCli.WithdrawTicket(cts.Kit, application.SN, cts.Kit.User)
-	if err != nil {
-		return nil, fmt.Errorf("call itsm cancel ticket api failed, err: %v", err)
+	// 由申请策略决定的单据创建时已驳回或进入交付，不能撤销
+	if application.Source == enumor.ApplicationSourcePolicy {
+		return nil, errf.Newf(errf.InvalidParameter, "application %s is decided by policy, can not be cancelled",
+			applicationID)
+	}
+
+	// 根据SN调用ITSM接口撤销单据，内置审批引擎的单据直接更新状态即可
+	if application.Source != enumor.ApplicationSourceNative {
+		err = a.itsmCli.WithdrawTicket(cts.Kit, application.SN, cts.Kit.User)
+		if err != nil {
+			return nil, fmt.Errorf("call itsm cancel ticket api failed, err: %v", err)
+		}
 	}
 
 	// 更新状态
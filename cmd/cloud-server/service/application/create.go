@@ -21,6 +21,7 @@ package application
 
 import (
 	"fmt"
+	"strings"
 
 	"hcm/cmd/cloud-server/service/application/handlers"
 	accounthandler "hcm/cmd/cloud-server/service/application/handlers/account"
@@ -28,11 +29,13 @@ import (
 	azurecvmhandler "hcm/cmd/cloud-server/service/application/handlers/cvm/azure"
 	gcpcvmhandler "hcm/cmd/cloud-server/service/application/handlers/cvm/gcp"
 	huaweicvmhandler "hcm/cmd/cloud-server/service/application/handlers/cvm/huawei"
+	resizecvmhandler "hcm/cmd/cloud-server/service/application/handlers/cvm/resize"
 	tcloudcvmhandler "hcm/cmd/cloud-server/service/application/handlers/cvm/tcloud"
 	awsdiskhandler "hcm/cmd/cloud-server/service/application/handlers/disk/aws"
 	azurediskhandler "hcm/cmd/cloud-server/service/application/handlers/disk/azure"
 	gcpdiskhandler "hcm/cmd/cloud-server/service/application/handlers/disk/gcp"
 	huaweidiskhandler "hcm/cmd/cloud-server/service/application/handlers/disk/huawei"
+	resizediskhandler "hcm/cmd/cloud-server/service/application/handlers/disk/resize"
 	tclouddiskhandler "hcm/cmd/cloud-server/service/application/handlers/disk/tcloud"
 	lbtcloud "hcm/cmd/cloud-server/service/application/handlers/load_balancer/tcloud"
 	createmainaccount "hcm/cmd/cloud-server/service/application/handlers/main-account/create-main-account"
@@ -93,26 +96,107 @@ func (a *applicationSvc) create(cts *rest.Contexts, req *proto.CreateCommonReq,
 		return nil, err
 	}
 
-	// 查询审批流程服务ID
 	applicationType := handler.GetType()
-	serviceID, managers, err := a.getApprovalProcessInfo(cts, applicationType)
+
+	// 生成存储到DB的申请单内容
+	content, err := json.MarshalToString(handler.GenerateApplicationContent())
 	if err != nil {
-		return nil, fmt.Errorf("get approval process service id and managers failed, err: %v", err)
+		return nil, errf.NewFromErr(
+			errf.InvalidParameter,
+			fmt.Errorf("json marshal request data failed, err: %w", err),
+		)
+	}
+
+	// 申请单标签随申请内容保存，交付时写入创建的资源
+	if len(req.Tags) != 0 {
+		if content, err = appendContentField(content, handlers.ApplicationTagsContentKey, req.Tags); err != nil {
+			return nil, err
+		}
 	}
 
+	// 主机、硬盘、VPC、负载均衡及主机、硬盘变配需要记录业务ID
+	var bkBizIDs = make([]int64, 0)
+	if applicationType == enumor.CreateCvm || applicationType == enumor.CreateDisk ||
+		applicationType == enumor.CreateVpc || applicationType == enumor.CreateLoadBalancer ||
+		applicationType == enumor.ResizeCvm || applicationType == enumor.ResizeDisk {
+		bkBizIDs = handler.GetBkBizIDs()
+	}
+
+	createReq := &dataproto.ApplicationCreateReq{
+		Type:           applicationType,
+		Status:         enumor.Pending,
+		BkBizIDs:       bkBizIDs,
+		Applicant:      cts.Kit.User,
+		Content:        content,
+		DeliveryDetail: "{}",
+		Memo:           req.Remark,
+	}
+
+	// 创建审批单据前进行申请策略评估，评估结果记录到申请单内容中
+	evaluation, err := a.evaluateApplicationPolicies(cts.Kit, handler, bkBizIDs, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if evaluation != nil {
+		if createReq.Content, err = appendPolicyEvaluation(content, evaluation); err != nil {
+			return nil, err
+		}
+
+		if evaluation.Result != enumor.PolicyEvaluationPass {
+			return a.createAndDecideByPolicy(cts, createReq, evaluation)
+		}
+	}
+
+	// 查询审批流程
+	process, err := a.getApprovalProcess(cts.Kit, applicationType)
+	if err != nil {
+		return nil, fmt.Errorf("get approval process failed, err: %v", err)
+	}
+	managers := strings.Split(process.Managers, ",")
+
+	// 根据申请类型配置的审批引擎创建审批单据
+	createReq.Source = enumor.ApplicationSourceITSM
+	switch process.Engine {
+	case enumor.NativeApprovalEngine:
+		createReq.Source = enumor.ApplicationSourceNative
+		createReq.SN, createReq.ApprovalDetail, err = a.createNativeApproval(cts.Kit, handler, process, managers)
+	default:
+		createReq.SN, err = a.createItsmTicket(cts, handler, process.ServiceID, managers)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 调用DB创建单据
+	result, err := a.client.DataService().Global.Application.Create(cts.Kit.Ctx, cts.Kit.Header(), createReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if createReq.Source == enumor.ApplicationSourceNative {
+		a.notifyNativeApprovers(cts.Kit, result.ID, applicationType, createReq.ApprovalDetail)
+	}
+
+	return result, nil
+}
+
+// createItsmTicket 调用ITSM创建审批单据，返回单据号
+func (a *applicationSvc) createItsmTicket(cts *rest.Contexts, handler handlers.ApplicationHandler, serviceID int64,
+	managers []string) (string, error) {
+
 	// 生成ITSM的回调地址
 	callbackUrl := a.getCallbackUrl()
 
 	// 渲染ITSM单据标题
 	itsmTitle, err := handler.RenderItsmTitle()
 	if err != nil {
-		return nil, fmt.Errorf("render itsm ticket title error: %w", err)
+		return "", fmt.Errorf("render itsm ticket title error: %w", err)
 	}
 
 	// 渲染ITSM单据申请内容
 	itsmForm, err := handler.RenderItsmForm()
 	if err != nil {
-		return nil, fmt.Errorf("render itsm ticket form error: %w", err)
+		return "", fmt.Errorf("render itsm ticket form error: %w", err)
 	}
 
 	// 获取ITSM单据涉及到的各个节点审批人
@@ -132,45 +216,10 @@ func (a *applicationSvc) create(cts *rest.Contexts, req *proto.CreateCommonReq,
 		},
 	)
 	if err != nil {
-		return nil, fmt.Errorf("call itsm create ticket api failed, err: %w", err)
-	}
-
-	// 调用DB创建单据
-	content, err := json.MarshalToString(handler.GenerateApplicationContent())
-	if err != nil {
-		return nil, errf.NewFromErr(
-			errf.InvalidParameter,
-			fmt.Errorf("json marshal request data failed, err: %w", err),
-		)
-	}
-
-	// 主机、硬盘、VPC、负载均衡需要记录业务ID
-	var bkBizIDs = make([]int64, 0)
-	if applicationType == enumor.CreateCvm || applicationType == enumor.CreateDisk ||
-		applicationType == enumor.CreateVpc || applicationType == enumor.CreateLoadBalancer {
-		bkBizIDs = handler.GetBkBizIDs()
-	}
-
-	result, err := a.client.DataService().Global.Application.Create(
-		cts.Kit.Ctx,
-		cts.Kit.Header(),
-		&dataproto.ApplicationCreateReq{
-			SN:             sn,
-			Source:         enumor.ApplicationSourceITSM,
-			Type:           applicationType,
-			Status:         enumor.Pending,
-			BkBizIDs:       bkBizIDs,
-			Applicant:      cts.Kit.User,
-			Content:        content,
-			DeliveryDetail: "{}",
-			Memo:           req.Remark,
-		},
-	)
-	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("call itsm create ticket api failed, err: %w", err)
 	}
 
-	return result, nil
+	return sn, nil
 }
 
 func parseReqFromRequestBody[T any](cts *rest.Contexts) (*T, error) {
@@ -413,6 +462,56 @@ func (a *applicationSvc) CreateForCreateLB(cts *rest.Contexts) (interface{}, err
 	return nil, nil
 }
 
+// CreateForResizeCvm 创建调整主机规格申请单
+func (a *applicationSvc) CreateForResizeCvm(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	commReq, err := decodeCommonReqAndValidate(cts)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req, err := parseReqFromRequestBody[cscvm.ResizeCvmReq](cts)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err = a.checkApplyResOperatePermission(cts, meta.Cvm, enumor.CvmCloudResType, req.ID); err != nil {
+		return nil, err
+	}
+
+	handler := resizecvmhandler.NewApplicationOfResizeCvm(a.getHandlerOption(cts), vendor, req)
+	return a.create(cts, commReq, handler)
+}
+
+// CreateForResizeDisk 创建扩容云盘申请单
+func (a *applicationSvc) CreateForResizeDisk(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	commReq, err := decodeCommonReqAndValidate(cts)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req, err := parseReqFromRequestBody[csdisk.ResizeDiskReq](cts)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err = a.checkApplyResOperatePermission(cts, meta.Disk, enumor.DiskCloudResType, req.ID); err != nil {
+		return nil, err
+	}
+
+	handler := resizediskhandler.NewApplicationOfResizeDisk(a.getHandlerOption(cts), vendor, req)
+	return a.create(cts, commReq, handler)
+}
+
 // CreateForCreateMainAccount ...
 func (a *applicationSvc) CreateForCreateMainAccount(cts *rest.Contexts) (interface{}, error) {
 	req, err := parseReqFromRequestBody[proto.MainAccountCreateReq](cts)
@@ -23,9 +23,12 @@ import (
 	"fmt"
 
 	proto "hcm/pkg/api/cloud-server/application"
+	coreapplication "hcm/pkg/api/core/application"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/rest"
+	"hcm/pkg/tools/json"
 )
 
 // Get ...
@@ -52,14 +55,9 @@ func (a *applicationSvc) Get(cts *rest.Contexts) (interface{}, error) {
 		}
 	}
 
-	// 查询审批链接
-	ticket, err := a.itsmCli.GetTicketResult(cts.Kit, application.SN)
-	if err != nil {
-		return nil, fmt.Errorf("call itsm get ticket url failed, err: %v", err)
-	}
-
-	return &proto.ApplicationGetResp{
+	resp := &proto.ApplicationGetResp{
 		ID:             application.ID,
+		Source:         application.Source,
 		SN:             application.SN,
 		Type:           application.Type,
 		Status:         application.Status,
@@ -68,6 +66,28 @@ func (a *applicationSvc) Get(cts *rest.Contexts) (interface{}, error) {
 		DeliveryDetail: application.DeliveryDetail,
 		Memo:           application.Memo,
 		Revision:       application.Revision,
-		TicketUrl:      ticket.TicketURL,
-	}, nil
+	}
+
+	switch application.Source {
+	case enumor.ApplicationSourceNative:
+		// 内置审批引擎的单据直接返回审批详情
+		approval := new(coreapplication.NativeApproval)
+		if err = json.UnmarshalFromString(application.ApprovalDetail, approval); err != nil {
+			return nil, fmt.Errorf("unmarshal application approval detail failed, err: %v", err)
+		}
+		resp.ApprovalDetail = approval
+		return resp, nil
+	case enumor.ApplicationSourcePolicy:
+		// 由申请策略决定的单据没有审批单据，策略评估结果记录在申请单内容中
+		return resp, nil
+	}
+
+	// 查询审批链接
+	ticket, err := a.itsmCli.GetTicketResult(cts.Kit, application.SN)
+	if err != nil {
+		return nil, fmt.Errorf("call itsm get ticket url failed, err: %v", err)
+	}
+	resp.TicketUrl = ticket.TicketURL
+
+	return resp, nil
 }
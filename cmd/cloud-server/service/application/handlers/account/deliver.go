@@ -65,6 +65,12 @@ func (a *ApplicationOfAddAccount) Deliver() (enumor.ApplicationStatus, map[strin
 			"but add create action associate permissions failed, err: %v", err)}, err
 	}
 
+	// 新账号关联了业务，用户对业务下资源的权限随之变化，需要清理鉴权缓存，清理失败时缓存会在过期后自动失效
+	if err = a.authorizer.InvalidateCache(a.Cts.Kit); err != nil {
+		logs.Errorf("invalidate auth cache after account %s delivered failed, err: %v, rid: %s", accountID, err,
+			a.Cts.Kit.Rid)
+	}
+
 	// 不同步登记账号
 	if a.req.Type != enumor.RegistrationAccount {
 		go func() {
@@ -23,6 +23,9 @@ import (
 	"fmt"
 
 	"hcm/cmd/cloud-server/logics/audit"
+	cvmlgc "hcm/cmd/cloud-server/logics/cvm"
+	disklgc "hcm/cmd/cloud-server/logics/disk"
+	"hcm/cmd/cloud-server/logics/ipam"
 	"hcm/pkg/api/core"
 	"hcm/pkg/client"
 	"hcm/pkg/criteria/enumor"
@@ -42,6 +45,9 @@ type HandlerOption struct {
 	Audit     audit.Interface
 	ItsmCli   itsm2.Client
 	CmsiCli   cmsi.Client
+	Ipam      ipam.Interface
+	CvmLgc    cvmlgc.Interface
+	DiskLgc   disklgc.Interface
 }
 
 // BaseApplicationHandler 基础的Handler 一些公共函数和属性处理，可以给到其他具体Handler组合
@@ -55,6 +61,9 @@ type BaseApplicationHandler struct {
 	Cipher     cryptography.Crypto
 	Audit      audit.Interface
 	CmsiClient cmsi.Client
+	Ipam       ipam.Interface
+	CvmLgc     cvmlgc.Interface
+	DiskLgc    disklgc.Interface
 }
 
 // NewBaseApplicationHandler ...
@@ -70,6 +79,9 @@ func NewBaseApplicationHandler(
 		Cipher:          opt.Cipher,
 		Audit:           opt.Audit,
 		CmsiClient:      opt.CmsiCli,
+		Ipam:            opt.Ipam,
+		CvmLgc:          opt.CvmLgc,
+		DiskLgc:         opt.DiskLgc,
 	}
 }
 
@@ -47,9 +47,26 @@ func (a *BaseApplicationHandler) GetImage(vendor enumor.Vendor, cloudImageID str
 	if err != nil {
 		return nil, err
 	}
-	if resp == nil || len(resp.Details) == 0 {
+	if resp != nil && len(resp.Details) != 0 {
+		return resp.Details[0], nil
+	}
+
+	// 公共镜像中不存在时，查询hcm管理的私有镜像
+	privateResp, err := a.Client.DataService().Global.PrivateImage.List(a.Cts.Kit, listReq)
+	if err != nil {
+		return nil, err
+	}
+	if privateResp == nil || len(privateResp.Details) == 0 {
 		return nil, fmt.Errorf("not found %s image by cloud_id(%s)", vendor, cloudImageID)
 	}
 
-	return resp.Details[0], nil
+	privateImage := privateResp.Details[0]
+	return &coreimage.BaseImage{
+		ID:       privateImage.ID,
+		Vendor:   string(privateImage.Vendor),
+		CloudID:  privateImage.CloudID,
+		Name:     privateImage.Name,
+		Type:     coreimage.PrivateImageType,
+		Revision: privateImage.Revision,
+	}, nil
 }
@@ -0,0 +1,127 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package handlers
+
+import (
+	coreapplication "hcm/pkg/api/core/application"
+)
+
+// GetPolicyFacts 申请单用于策略评估的事实数据，默认只包含云厂商和申请类型，具体申请单可以覆盖补充资源规格
+func (a *BaseApplicationHandler) GetPolicyFacts() (coreapplication.PolicyFacts, error) {
+	facts := coreapplication.PolicyFacts{
+		coreapplication.FactApplicationType: a.applicationType,
+	}
+	if len(a.vendor) != 0 {
+		facts[coreapplication.FactVendor] = a.vendor
+	}
+
+	return facts, nil
+}
+
+// CvmPolicyFactsOption 主机申请单用于策略评估的规格数据
+type CvmPolicyFactsOption struct {
+	Region         string
+	Zone           string
+	InstanceType   string
+	InstanceFamily string
+	Cpu            int64
+	// Memory 内存，单位MB
+	Memory        int64
+	RequiredCount int64
+	PublicIP      bool
+	// DiskSizeGB 单台主机系统盘和数据盘的总大小
+	DiskSizeGB int64
+}
+
+// GetCvmPolicyFacts 生成主机申请单用于策略评估的事实数据
+func (a *BaseApplicationHandler) GetCvmPolicyFacts(opt *CvmPolicyFactsOption) (coreapplication.PolicyFacts, error) {
+	facts, err := a.GetPolicyFacts()
+	if err != nil {
+		return nil, err
+	}
+
+	facts[coreapplication.FactRegion] = opt.Region
+	if len(opt.Zone) != 0 {
+		facts[coreapplication.FactZone] = opt.Zone
+	}
+	facts[coreapplication.FactInstanceType] = opt.InstanceType
+	facts[coreapplication.FactInstanceFamily] = opt.InstanceFamily
+	facts[coreapplication.FactCpu] = opt.Cpu
+	facts[coreapplication.FactMemory] = opt.Memory
+	facts[coreapplication.FactCount] = opt.RequiredCount
+	facts[coreapplication.FactPublicIP] = opt.PublicIP
+	facts[coreapplication.FactDiskSizeGB] = opt.DiskSizeGB
+
+	return facts, nil
+}
+
+// GetDiskPolicyFacts 生成硬盘申请单用于策略评估的事实数据
+func (a *BaseApplicationHandler) GetDiskPolicyFacts(region, zone string, diskSizeGB, diskCount int64) (
+	coreapplication.PolicyFacts, error) {
+
+	facts, err := a.GetPolicyFacts()
+	if err != nil {
+		return nil, err
+	}
+
+	facts[coreapplication.FactRegion] = region
+	if len(zone) != 0 {
+		facts[coreapplication.FactZone] = zone
+	}
+	facts[coreapplication.FactDiskSizeGB] = diskSizeGB
+	facts[coreapplication.FactCount] = diskCount
+
+	return facts, nil
+}
+
+// GetVpcPolicyFacts 生成VPC申请单用于策略评估的事实数据，每个申请单创建一个VPC
+func (a *BaseApplicationHandler) GetVpcPolicyFacts(region, zone string) (coreapplication.PolicyFacts, error) {
+	facts, err := a.GetPolicyFacts()
+	if err != nil {
+		return nil, err
+	}
+
+	facts[coreapplication.FactRegion] = region
+	if len(zone) != 0 {
+		facts[coreapplication.FactZone] = zone
+	}
+	facts[coreapplication.FactCount] = int64(1)
+
+	return facts, nil
+}
+
+// GetLoadBalancerPolicyFacts 生成负载均衡申请单用于策略评估的事实数据
+func (a *BaseApplicationHandler) GetLoadBalancerPolicyFacts(region, zone string, count int64, publicIP bool) (
+	coreapplication.PolicyFacts, error) {
+
+	facts, err := a.GetPolicyFacts()
+	if err != nil {
+		return nil, err
+	}
+
+	facts[coreapplication.FactRegion] = region
+	if len(zone) != 0 {
+		facts[coreapplication.FactZone] = zone
+	}
+	facts[coreapplication.FactCount] = count
+	facts[coreapplication.FactPublicIP] = publicIP
+
+	return facts, nil
+}
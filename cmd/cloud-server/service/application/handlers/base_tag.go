@@ -0,0 +1,136 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/tidwall/gjson"
+
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	hcrestag "hcm/pkg/api/hc-service/resource-tag"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
+)
+
+// ApplicationTagsContentKey 申请单内容中记录申请单标签的字段，交付时将这些标签写入创建的资源
+const ApplicationTagsContentKey = "application_tags"
+
+// ParseApplicationTags 从申请单内容中解析申请单标签，没有标签时返回空
+func ParseApplicationTags(content string) (map[string]string, error) {
+	raw := gjson.Get(content, ApplicationTagsContentKey)
+	if !raw.Exists() {
+		return nil, nil
+	}
+
+	tags := make(map[string]string)
+	if err := json.UnmarshalFromString(raw.Raw, &tags); err != nil {
+		return nil, fmt.Errorf("unmarshal application tags failed, err: %v", err)
+	}
+
+	return tags, nil
+}
+
+// TagDeliveredResources 将申请单标签写入交付的资源，按资源所属账号分批调用云上打标签接口
+func TagDeliveredResources(kt *kit.Kit, cli *client.ClientSet, tags map[string]string,
+	resType enumor.CloudResourceType, ids []string) error {
+
+	if len(tags) == 0 || len(ids) == 0 {
+		return nil
+	}
+
+	basicInfos, err := cli.DataService().Global.Cloud.ListResBasicInfo(kt, protocloud.ListResourceBasicInfoReq{
+		ResourceType: resType,
+		IDs:          ids,
+		Fields:       types.CommonBasicInfoFields,
+	})
+	if err != nil {
+		logs.Errorf("list delivered resource basic info failed, err: %v, type: %s, ids: %v, rid: %s", err, resType,
+			ids, kt.Rid)
+		return err
+	}
+
+	accountResIDs := make(map[string][]string)
+	accountVendor := make(map[string]enumor.Vendor)
+	for _, info := range basicInfos {
+		accountResIDs[info.AccountID] = append(accountResIDs[info.AccountID], info.ID)
+		accountVendor[info.AccountID] = info.Vendor
+	}
+
+	for accountID, resIDs := range accountResIDs {
+		for _, batch := range slice.Split(resIDs, constant.BatchOperationMaxLimit) {
+			req := &hcrestag.ResTagAddReq{AccountID: accountID, ResType: resType, ResIDs: batch, Tags: tags}
+
+			switch vendor := accountVendor[accountID]; vendor {
+			case enumor.TCloud:
+				err = cli.HCService().TCloud.ResourceTag.Add(kt, req)
+			case enumor.Aws:
+				err = cli.HCService().Aws.ResourceTag.Add(kt, req)
+			case enumor.HuaWei:
+				err = cli.HCService().HuaWei.ResourceTag.Add(kt, req)
+			case enumor.Gcp:
+				err = cli.HCService().Gcp.ResourceTag.Add(kt, req)
+			case enumor.Azure:
+				err = cli.HCService().Azure.ResourceTag.Add(kt, req)
+			default:
+				return errf.Newf(errf.Unknown, "vendor: %s not support", vendor)
+			}
+			if err != nil {
+				logs.Errorf("add application tags to delivered resources failed, err: %v, req: %+v, rid: %s", err,
+					req, kt.Rid)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ListLoadBalancerIDsByCloudIDs 查询负载均衡云ID对应的ID，用于给交付的负载均衡写入申请单标签
+func ListLoadBalancerIDsByCloudIDs(kt *kit.Kit, cli *client.ClientSet, cloudIDs []string) ([]string, error) {
+	ids := make([]string, 0, len(cloudIDs))
+	for _, batch := range slice.Split(cloudIDs, int(core.DefaultMaxPageLimit)) {
+		req := &core.ListReq{
+			Filter: tools.ContainersExpression("cloud_id", batch),
+			Page:   core.NewDefaultBasePage(),
+			Fields: []string{"id"},
+		}
+		result, err := cli.DataService().Global.LoadBalancer.ListLoadBalancer(kt, req)
+		if err != nil {
+			logs.Errorf("list load balancer by cloud ids failed, err: %v, cloud ids: %v, rid: %s", err, batch, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range result.Details {
+			ids = append(ids, one.ID)
+		}
+	}
+
+	return ids, nil
+}
@@ -23,6 +23,8 @@ import (
 	"errors"
 
 	logicsaccount "hcm/cmd/cloud-server/logics/account"
+	logicsimage "hcm/cmd/cloud-server/logics/image"
+	"hcm/pkg/criteria/enumor"
 )
 
 // CheckReq 检查申请单的数据是否正确
@@ -35,6 +37,12 @@ func (a *ApplicationOfCreateAwsCvm) CheckReq() error {
 		return err
 	}
 
+	err := logicsimage.ValidateImageUsable(a.Cts.Kit, a.Client.DataService(), enumor.Aws, a.req.Region,
+		a.req.CloudImageID)
+	if err != nil {
+		return err
+	}
+
 	// TCloud 支持 DryRun，可预校验
 	result, err := a.Client.HCService().Aws.Cvm.BatchCreateCvm(a.Cts.Kit, a.toHcProtoAwsBatchCreateReq(true))
 	if err != nil {
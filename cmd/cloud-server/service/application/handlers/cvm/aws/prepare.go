@@ -22,7 +22,9 @@ package aws
 import (
 	"fmt"
 
+	"hcm/cmd/cloud-server/service/application/handlers"
 	proto "hcm/pkg/api/cloud-server/cvm"
+	coreapplication "hcm/pkg/api/core/application"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/thirdparty/api-gateway/itsm"
 )
@@ -71,3 +73,28 @@ func (a *ApplicationOfCreateAwsCvm) GetItsmApprover(managers []string) []itsm.Va
 func (a *ApplicationOfCreateAwsCvm) GetBkBizIDs() []int64 {
 	return []int64{a.req.BkBizID}
 }
+
+// GetPolicyFacts 获取申请单用于策略评估的事实数据
+func (a *ApplicationOfCreateAwsCvm) GetPolicyFacts() (coreapplication.PolicyFacts, error) {
+	instanceTypeInfo, err := a.GetAwsInstanceType(a.req.AccountID, a.req.Region, a.req.InstanceType)
+	if err != nil {
+		return nil, err
+	}
+
+	diskSizeGB := a.req.SystemDisk.DiskSizeGB
+	for _, one := range a.req.DataDisk {
+		diskSizeGB += one.DiskSizeGB * one.DiskCount
+	}
+
+	return a.GetCvmPolicyFacts(&handlers.CvmPolicyFactsOption{
+		Region:         a.req.Region,
+		Zone:           a.req.Zone,
+		InstanceType:   a.req.InstanceType,
+		InstanceFamily: instanceTypeInfo.InstanceFamily,
+		Cpu:            instanceTypeInfo.CPU,
+		Memory:         instanceTypeInfo.Memory,
+		RequiredCount:  a.req.RequiredCount,
+		PublicIP:       a.req.PublicIPAssigned,
+		DiskSizeGB:     diskSizeGB,
+	})
+}
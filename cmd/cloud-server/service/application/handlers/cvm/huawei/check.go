@@ -23,6 +23,8 @@ import (
 	"errors"
 
 	logicsaccount "hcm/cmd/cloud-server/logics/account"
+	logicsimage "hcm/cmd/cloud-server/logics/image"
+	"hcm/pkg/criteria/enumor"
 )
 
 // CheckReq 检查申请单的数据是否正确
@@ -35,6 +37,12 @@ func (a *ApplicationOfCreateHuaWeiCvm) CheckReq() error {
 		return err
 	}
 
+	err := logicsimage.ValidateImageUsable(a.Cts.Kit, a.Client.DataService(), enumor.HuaWei, a.req.Region,
+		a.req.CloudImageID)
+	if err != nil {
+		return err
+	}
+
 	// TCloud 支持 DryRun，可预校验
 	result, err := a.Client.HCService().HuaWei.Cvm.BatchCreateCvm(a.Cts.Kit, a.toHcProtoHuaWeiBatchCreateReq(true))
 	if err != nil {
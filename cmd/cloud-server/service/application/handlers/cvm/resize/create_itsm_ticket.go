@@ -0,0 +1,79 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package resize
+
+import (
+	"fmt"
+	"strings"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+type formItem struct {
+	Label string
+	Value string
+}
+
+// RenderItsmTitle 渲染ITSM单据标题
+func (a *ApplicationOfResizeCvm) RenderItsmTitle() (string, error) {
+	return fmt.Sprintf("申请调整[%s]主机规格(%s)", a.Vendor().GetNameZh(), a.cvm.Name), nil
+}
+
+// RenderItsmForm 渲染ITSM表单
+func (a *ApplicationOfResizeCvm) RenderItsmForm() (string, error) {
+	formItems := make([]formItem, 0)
+
+	// 业务
+	bizName, err := a.GetBizName(a.cvm.BkBizID)
+	if err != nil {
+		return "", err
+	}
+	formItems = append(formItems, formItem{Label: "业务", Value: bizName})
+
+	// 云账号
+	accountInfo, err := a.GetAccount(a.cvm.AccountID)
+	if err != nil {
+		return "", err
+	}
+	formItems = append(formItems, formItem{Label: "云账号", Value: accountInfo.Name})
+
+	// 运行中的主机需要先关机，调整规格后再开机
+	restart := "否"
+	if a.cvm.LifecycleStatus == enumor.CvmLifecycleRunning {
+		restart = "是"
+	}
+
+	formItems = append(formItems, []formItem{
+		{Label: "云厂商", Value: a.Vendor().GetNameZh()},
+		{Label: "云地域", Value: a.cvm.Region},
+		{Label: "可用区", Value: a.cvm.Zone},
+		{Label: "主机", Value: fmt.Sprintf("%s(%s)", a.cvm.Name, a.cvm.CloudID)},
+		{Label: "当前机型", Value: a.cvm.MachineType},
+		{Label: "目标机型", Value: a.req.InstanceType},
+		{Label: "需要关机", Value: restart},
+	}...)
+
+	// 转换为ITSM表单内容数据
+	content := make([]string, 0, len(formItems))
+	for _, i := range formItems {
+		content = append(content, fmt.Sprintf("%s: %s", i.Label, i.Value))
+	}
+	return strings.Join(content, "\n"), nil
+}
@@ -0,0 +1,55 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package resize
+
+import (
+	actioncvm "hcm/cmd/task-server/logics/action/cvm"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/logs"
+)
+
+// Deliver 执行资源交付，创建调整主机规格的Flow，Flow结束后由定时任务更新单据状态
+func (a *ApplicationOfResizeCvm) Deliver() (enumor.ApplicationStatus, map[string]interface{}, error) {
+	kt := a.Cts.Kit
+	tasks, err := actioncvm.BuildResizeCvmTasks(a.cvm, a.req.InstanceType)
+	if err != nil {
+		return enumor.DeliverError, map[string]interface{}{"error": err.Error()}, err
+	}
+
+	err = a.Audit.ResBaseOperationAudit(kt, enumor.CvmAuditResType, protoaudit.Resize, []string{a.cvm.ID})
+	if err != nil {
+		logs.Errorf("create operation audit failed, err: %v, rid: %s", err, kt.Rid)
+		return enumor.DeliverError, map[string]interface{}{"error": err.Error()}, err
+	}
+
+	addReq := &ts.AddCustomFlowReq{
+		Name:  enumor.FlowResizeCvm,
+		Tasks: tasks,
+	}
+	result, err := a.Client.TaskServer().CreateCustomFlow(kt, addReq)
+	if err != nil {
+		logs.Errorf("call taskserver to create custom flow failed, err: %v, rid: %s", err, kt.Rid)
+		return enumor.DeliverError, map[string]interface{}{"error": err.Error()}, err
+	}
+
+	return enumor.Delivering, map[string]interface{}{"flow_id": result.ID}, nil
+}
@@ -0,0 +1,79 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package resize
+
+import (
+	proto "hcm/pkg/api/cloud-server/cvm"
+	coreapplication "hcm/pkg/api/core/application"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/thirdparty/api-gateway/itsm"
+)
+
+// PrepareReq ...
+func (a *ApplicationOfResizeCvm) PrepareReq() error {
+	return nil
+}
+
+// GenerateApplicationContent 获取预处理过的数据，以interface格式
+func (a *ApplicationOfResizeCvm) GenerateApplicationContent() interface{} {
+	// 需要将Vendor也存储进去，当前机型便于单据展示
+	return &struct {
+		*proto.ResizeCvmReq `json:",inline"`
+		Vendor              enumor.Vendor `json:"vendor"`
+		BkBizID             int64         `json:"bk_biz_id"`
+		CurrentInstanceType string        `json:"current_instance_type"`
+	}{
+		ResizeCvmReq:        a.req,
+		Vendor:              a.Vendor(),
+		BkBizID:             a.cvm.BkBizID,
+		CurrentInstanceType: a.cvm.MachineType,
+	}
+}
+
+// PrepareReqFromContent ...
+func (a *ApplicationOfResizeCvm) PrepareReqFromContent() error {
+	return nil
+}
+
+// GetItsmApprover 获取itsm审批人
+func (a *ApplicationOfResizeCvm) GetItsmApprover(managers []string) []itsm.VariableApprover {
+	return a.GetItsmPlatformAndAccountApprover(managers, a.cvm.AccountID)
+}
+
+// GetBkBizIDs 获取当前的业务IDs
+func (a *ApplicationOfResizeCvm) GetBkBizIDs() []int64 {
+	return []int64{a.cvm.BkBizID}
+}
+
+// GetPolicyFacts 获取申请单用于策略评估的事实数据
+func (a *ApplicationOfResizeCvm) GetPolicyFacts() (coreapplication.PolicyFacts, error) {
+	facts, err := a.BaseApplicationHandler.GetPolicyFacts()
+	if err != nil {
+		return nil, err
+	}
+
+	facts[coreapplication.FactRegion] = a.cvm.Region
+	if len(a.cvm.Zone) != 0 {
+		facts[coreapplication.FactZone] = a.cvm.Zone
+	}
+	facts[coreapplication.FactInstanceType] = a.req.InstanceType
+
+	return facts, nil
+}
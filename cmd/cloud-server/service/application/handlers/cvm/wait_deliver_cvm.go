@@ -25,6 +25,7 @@ import (
 
 	"github.com/tidwall/gjson"
 
+	"hcm/cmd/cloud-server/service/application/handlers"
 	actioncvm "hcm/cmd/task-server/logics/action/cvm"
 	"hcm/pkg/api/core"
 	coreasync "hcm/pkg/api/core/async"
@@ -48,14 +49,19 @@ func TimingHandleDeliverApplication(cliSet *client.ClientSet, interval time.Dura
 		time.Sleep(2 * time.Second)
 
 		kt := core.NewBackendKit()
-		if err := WaitAndHandleDeliverCvm(kt, cliSet.DataService(), cliSet.TaskServer()); err != nil {
+		if err := WaitAndHandleDeliverCvm(kt, cliSet); err != nil {
 			logs.Errorf("WaitAndHandleDeliverCvm err: %v, rid: %s", err, kt.Rid)
 		}
+
+		if err := WaitAndHandleDeliverResize(kt, cliSet.DataService(), cliSet.TaskServer()); err != nil {
+			logs.Errorf("WaitAndHandleDeliverResize err: %v, rid: %s", err, kt.Rid)
+		}
 	}
 }
 
 // WaitAndHandleDeliverCvm wait deliver cvm.
-func WaitAndHandleDeliverCvm(kt *kit.Kit, dsCli *dataservice.Client, tsCli *taskserver.Client) error {
+func WaitAndHandleDeliverCvm(kt *kit.Kit, cliSet *client.ClientSet) error {
+	dsCli, tsCli := cliSet.DataService(), cliSet.TaskServer()
 
 	// 查询交付状态中的单据
 	apps, err := queryDeliveringApplication(kt, dsCli)
@@ -90,7 +96,7 @@ func WaitAndHandleDeliverCvm(kt *kit.Kit, dsCli *dataservice.Client, tsCli *task
 
 	// 将生产出来的机器及其关联资源分配到业务下，并将结果保存到单据中
 	for flowID, result := range flowResultMap {
-		if err = handleDeliverCvm(kt, dsCli, tsCli, flowAppMap, flowID, result); err != nil {
+		if err = handleDeliverCvm(kt, cliSet, flowAppMap, flowID, result); err != nil {
 			return err
 		}
 	}
@@ -98,8 +104,10 @@ func WaitAndHandleDeliverCvm(kt *kit.Kit, dsCli *dataservice.Client, tsCli *task
 	return nil
 }
 
-func handleDeliverCvm(kt *kit.Kit, dsCli *dataservice.Client, tsCli *taskserver.Client,
-	flowAppMap map[string]*ds.ApplicationResp, flowID string, result *hccvm.BatchCreateResult) error {
+func handleDeliverCvm(kt *kit.Kit, cliSet *client.ClientSet, flowAppMap map[string]*ds.ApplicationResp,
+	flowID string, result *hccvm.BatchCreateResult) error {
+
+	dsCli, tsCli := cliSet.DataService(), cliSet.TaskServer()
 
 	app := flowAppMap[flowID]
 
@@ -133,6 +141,11 @@ func handleDeliverCvm(kt *kit.Kit, dsCli *dataservice.Client, tsCli *taskserver.
 		}
 
 		detail["cvm_ids"] = assignResult.IDs
+
+		// 将申请单标签写入交付的主机，标签写入失败不影响已交付的主机，失败原因记录到交付详情中
+		if err = tagDeliveredCvm(kt, cliSet, app, assignResult.IDs); err != nil {
+			detail["tag_error"] = err.Error()
+		}
 		requiredCount := gjson.Get(app.Content, "required_count").Int()
 		if len(result.SuccessCloudIDs) != int(requiredCount) {
 			state = enumor.DeliverPartial
@@ -159,6 +172,22 @@ func handleDeliverCvm(kt *kit.Kit, dsCli *dataservice.Client, tsCli *taskserver.
 	return nil
 }
 
+// tagDeliveredCvm 将申请单标签写入交付的主机
+func tagDeliveredCvm(kt *kit.Kit, cliSet *client.ClientSet, app *ds.ApplicationResp, ids []string) error {
+	tags, err := handlers.ParseApplicationTags(app.Content)
+	if err != nil {
+		logs.Errorf("parse application tags failed, err: %v, id: %s, rid: %s", err, app.ID, kt.Rid)
+		return err
+	}
+
+	if err = handlers.TagDeliveredResources(kt, cliSet, tags, enumor.CvmCloudResType, ids); err != nil {
+		logs.Errorf("tag delivered cvm failed, err: %v, application: %s, rid: %s", err, app.ID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
 // queryAndParseEndStateFlowByFlowID 查询并解析结束状态Flow
 func queryAndParseEndStateFlowByFlowID(kt *kit.Kit, cli *taskserver.Client, flowIDs []string) (
 	map[string]*hccvm.BatchCreateResult, error) {
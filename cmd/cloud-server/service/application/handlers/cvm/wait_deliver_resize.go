@@ -0,0 +1,129 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package appcvm
+
+import (
+	"github.com/tidwall/gjson"
+
+	"hcm/pkg/api/core"
+	ds "hcm/pkg/api/data-service"
+	dataservice "hcm/pkg/client/data-service"
+	taskserver "hcm/pkg/client/task-server"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+)
+
+// resizeApplicationTypes 通过异步Flow交付的变配类单据
+var resizeApplicationTypes = []enumor.ApplicationType{enumor.ResizeCvm, enumor.ResizeDisk}
+
+// WaitAndHandleDeliverResize 处理交付中的调整主机规格、扩容云盘单据，Flow结束后更新单据的交付状态
+func WaitAndHandleDeliverResize(kt *kit.Kit, dsCli *dataservice.Client, tsCli *taskserver.Client) error {
+	listReq := &ds.ApplicationListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("status", enumor.Delivering),
+			tools.RuleIn("type", resizeApplicationTypes),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	apps, err := dsCli.Global.Application.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list delivering resize application failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	if len(apps.Details) == 0 {
+		return nil
+	}
+
+	flowIDs := make([]string, 0, len(apps.Details))
+	flowAppMap := make(map[string]*ds.ApplicationResp, len(apps.Details))
+	for _, app := range apps.Details {
+		flowID := gjson.Get(app.DeliveryDetail, "flow_id").String()
+		flowIDs = append(flowIDs, flowID)
+		flowAppMap[flowID] = app
+	}
+
+	flowReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleIn("id", flowIDs),
+			tools.RuleIn("state", []enumor.FlowState{enumor.FlowSuccess, enumor.FlowFailed}),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	flows, err := tsCli.ListFlow(kt, flowReq)
+	if err != nil {
+		logs.Errorf("list flow failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	for _, flow := range flows.Details {
+		state := enumor.Completed
+		detail := map[string]interface{}{"flow_id": flow.ID}
+		if flow.State == enumor.FlowFailed {
+			state = enumor.DeliverError
+			detail["error"] = getFlowFailedReason(kt, tsCli, flow.ID)
+		}
+
+		marshal, err := json.MarshalToString(detail)
+		if err != nil {
+			logs.Errorf("marshal deliver detail failed, err: %v, detail: %+v, rid: %s", err, detail, kt.Rid)
+			return err
+		}
+
+		app := flowAppMap[flow.ID]
+		req := &ds.ApplicationUpdateReq{
+			Status:         state,
+			DeliveryDetail: converter.ValToPtr(marshal),
+		}
+		if _, err = dsCli.Global.Application.Update(kt, app.ID, req); err != nil {
+			logs.Errorf("update application failed, err: %v, id: %s, rid: %s", err, app.ID, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getFlowFailedReason 选取一个失败任务的错误信息作为Flow的失败原因
+func getFlowFailedReason(kt *kit.Kit, tsCli *taskserver.Client, flowID string) string {
+	req := &core.ListReq{
+		Filter: tools.EqualWithOpExpression(filter.And, map[string]interface{}{
+			"flow_id": flowID,
+			"state":   enumor.TaskFailed,
+		}),
+		Page: &core.BasePage{Start: 0, Limit: 1},
+	}
+	result, err := tsCli.ListTask(kt, req)
+	if err != nil {
+		logs.Errorf("list failed task failed, err: %v, flow: %s, rid: %s", err, flowID, kt.Rid)
+		return "flow failed"
+	}
+
+	if len(result.Details) == 0 || result.Details[0].Reason == nil {
+		return "flow failed"
+	}
+
+	return result.Details[0].Reason.Message
+}
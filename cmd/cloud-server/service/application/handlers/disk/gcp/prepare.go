@@ -21,6 +21,7 @@ package gcp
 
 import (
 	csdisk "hcm/pkg/api/cloud-server/disk"
+	coreapplication "hcm/pkg/api/core/application"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/thirdparty/api-gateway/itsm"
 )
@@ -56,3 +57,8 @@ func (a *ApplicationOfCreateGcpDisk) GetItsmApprover(managers []string) []itsm.V
 func (a *ApplicationOfCreateGcpDisk) GetBkBizIDs() []int64 {
 	return []int64{a.req.BkBizID}
 }
+
+// GetPolicyFacts 获取申请单用于策略评估的事实数据
+func (a *ApplicationOfCreateGcpDisk) GetPolicyFacts() (coreapplication.PolicyFacts, error) {
+	return a.GetDiskPolicyFacts(a.req.Region, a.req.Zone, int64(a.req.DiskSize), int64(a.req.DiskCount))
+}
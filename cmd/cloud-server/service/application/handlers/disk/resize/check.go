@@ -0,0 +1,43 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package resize
+
+import (
+	"fmt"
+)
+
+// CheckReq 校验扩容后的大小是否大于当前大小，审批通过交付前会再次校验
+func (a *ApplicationOfResizeDisk) CheckReq() error {
+	if err := a.req.Validate(); err != nil {
+		return err
+	}
+
+	disk, err := a.DiskLgc.ResizePreCheck(a.Cts.Kit, a.req.ID, a.req.DiskSize)
+	if err != nil {
+		return err
+	}
+
+	if disk.Vendor != string(a.Vendor()) {
+		return fmt.Errorf("disk %s vendor is %s, not %s", disk.ID, disk.Vendor, a.Vendor())
+	}
+	a.disk = disk
+
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package resize
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type formItem struct {
+	Label string
+	Value string
+}
+
+// RenderItsmTitle 渲染ITSM单据标题
+func (a *ApplicationOfResizeDisk) RenderItsmTitle() (string, error) {
+	return fmt.Sprintf("申请扩容[%s]云盘(%s)", a.Vendor().GetNameZh(), a.disk.Name), nil
+}
+
+// RenderItsmForm 渲染ITSM表单
+func (a *ApplicationOfResizeDisk) RenderItsmForm() (string, error) {
+	formItems := make([]formItem, 0)
+
+	// 业务
+	bizName, err := a.GetBizName(a.disk.BkBizID)
+	if err != nil {
+		return "", err
+	}
+	formItems = append(formItems, formItem{Label: "业务", Value: bizName})
+
+	// 云账号
+	accountInfo, err := a.GetAccount(a.disk.AccountID)
+	if err != nil {
+		return "", err
+	}
+	formItems = append(formItems, formItem{Label: "云账号", Value: accountInfo.Name})
+
+	formItems = append(formItems, []formItem{
+		{Label: "云厂商", Value: a.Vendor().GetNameZh()},
+		{Label: "云地域", Value: a.disk.Region},
+		{Label: "可用区", Value: a.disk.Zone},
+		{Label: "云硬盘", Value: fmt.Sprintf("%s(%s)", a.disk.Name, a.disk.CloudID)},
+		{Label: "当前大小", Value: strconv.FormatUint(a.disk.DiskSize, 10)},
+		{Label: "扩容后大小", Value: strconv.FormatUint(a.req.DiskSize, 10)},
+	}...)
+
+	// 转换为ITSM表单内容数据
+	content := make([]string, 0, len(formItems))
+	for _, i := range formItems {
+		content = append(content, fmt.Sprintf("%s: %s", i.Label, i.Value))
+	}
+	return strings.Join(content, "\n"), nil
+}
@@ -0,0 +1,47 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package resize 扩容云盘申请单
+package resize
+
+import (
+	"hcm/cmd/cloud-server/service/application/handlers"
+	csdisk "hcm/pkg/api/cloud-server/disk"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	"hcm/pkg/criteria/enumor"
+)
+
+// ApplicationOfResizeDisk ...
+type ApplicationOfResizeDisk struct {
+	handlers.BaseApplicationHandler
+
+	req *csdisk.ResizeDiskReq
+	// disk 待扩容的云盘，在CheckReq中查询
+	disk *coredisk.BaseDisk
+}
+
+// NewApplicationOfResizeDisk ...
+func NewApplicationOfResizeDisk(opt *handlers.HandlerOption, vendor enumor.Vendor,
+	req *csdisk.ResizeDiskReq) *ApplicationOfResizeDisk {
+
+	return &ApplicationOfResizeDisk{
+		BaseApplicationHandler: handlers.NewBaseApplicationHandler(opt, enumor.ResizeDisk, vendor),
+		req:                    req,
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package resize
+
+import (
+	csdisk "hcm/pkg/api/cloud-server/disk"
+	coreapplication "hcm/pkg/api/core/application"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/thirdparty/api-gateway/itsm"
+)
+
+// PrepareReq ...
+func (a *ApplicationOfResizeDisk) PrepareReq() error {
+	return nil
+}
+
+// GenerateApplicationContent 获取预处理过的数据，以interface格式
+func (a *ApplicationOfResizeDisk) GenerateApplicationContent() interface{} {
+	// 需要将Vendor也存储进去，当前大小便于单据展示
+	return &struct {
+		*csdisk.ResizeDiskReq `json:",inline"`
+		Vendor                enumor.Vendor `json:"vendor"`
+		BkBizID               int64         `json:"bk_biz_id"`
+		CurrentDiskSize       uint64        `json:"current_disk_size"`
+	}{
+		ResizeDiskReq:   a.req,
+		Vendor:          a.Vendor(),
+		BkBizID:         a.disk.BkBizID,
+		CurrentDiskSize: a.disk.DiskSize,
+	}
+}
+
+// PrepareReqFromContent ...
+func (a *ApplicationOfResizeDisk) PrepareReqFromContent() error {
+	return nil
+}
+
+// GetItsmApprover 获取itsm审批人
+func (a *ApplicationOfResizeDisk) GetItsmApprover(managers []string) []itsm.VariableApprover {
+	return a.GetItsmPlatformAndAccountApprover(managers, a.disk.AccountID)
+}
+
+// GetBkBizIDs 获取当前的业务IDs
+func (a *ApplicationOfResizeDisk) GetBkBizIDs() []int64 {
+	return []int64{a.disk.BkBizID}
+}
+
+// GetPolicyFacts 获取申请单用于策略评估的事实数据，硬盘大小为扩容后的大小
+func (a *ApplicationOfResizeDisk) GetPolicyFacts() (coreapplication.PolicyFacts, error) {
+	return a.GetDiskPolicyFacts(a.disk.Region, a.disk.Zone, int64(a.req.DiskSize), 1)
+}
@@ -20,12 +20,13 @@
 package handlers
 
 import (
+	coreapplication "hcm/pkg/api/core/application"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/thirdparty/api-gateway/itsm"
 )
 
 // ApplicationHandler 定义了申请单的表单校验，与itsm对接、审批通过后的资源交付函数
-// 创建申请单：CheckReq -> PrepareReq -> CreateITSMTicket -> GenerateApplicationContent -> "SaveToDB"
+// 创建申请单：CheckReq -> PrepareReq -> GetPolicyFacts -> CreateITSMTicket -> GenerateApplicationContent -> "SaveToDB"
 // 审批通过交付："LoadApplicationFromDB" -> PrepareReqForContent-> CheckReq -> Deliver -> "UpdateStatusToDB"
 // Note: 这里创建申请单的请求数据和交付资源的请求数据结构是一样的，这是一种"偷懒"行为，
 // 更好的方式是Handler拆分成两种抽象：申请单创建者Creator、申请单交付者Deliverer，然后定义各自的数据结构
@@ -58,4 +59,7 @@ type ApplicationHandler interface {
 
 	// GetBkBizIDs 获取当前的业务IDs
 	GetBkBizIDs() []int64
+
+	// GetPolicyFacts 获取申请单用于策略评估的事实数据
+	GetPolicyFacts() (coreapplication.PolicyFacts, error)
 }
@@ -20,9 +20,12 @@
 package tcloud
 
 import (
+	typeslb "hcm/pkg/adaptor/types/load-balancer"
+	coreapplication "hcm/pkg/api/core/application"
 	hclb "hcm/pkg/api/hc-service/load-balancer"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/thirdparty/api-gateway/itsm"
+	"hcm/pkg/tools/converter"
 )
 
 // PrepareReq 预处理请求参数，比如敏感数据加密
@@ -57,3 +60,19 @@ func (a *ApplicationOfCreateTCloudLB) GetItsmApprover(managers []string) []itsm.
 func (a *ApplicationOfCreateTCloudLB) GetBkBizIDs() []int64 {
 	return []int64{a.req.BkBizID}
 }
+
+// GetPolicyFacts 获取申请单用于策略评估的事实数据，未指定可用区时不提供zone
+func (a *ApplicationOfCreateTCloudLB) GetPolicyFacts() (coreapplication.PolicyFacts, error) {
+	zone := ""
+	if len(a.req.Zones) != 0 {
+		zone = a.req.Zones[0]
+	}
+
+	count := int64(1)
+	if a.req.RequireCount != nil {
+		count = int64(converter.PtrToVal(a.req.RequireCount))
+	}
+
+	return a.GetLoadBalancerPolicyFacts(a.req.Region, zone, count,
+		a.req.LoadBalancerType == typeslb.OpenLoadBalancerType)
+}
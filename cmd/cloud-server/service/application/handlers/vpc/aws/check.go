@@ -21,6 +21,8 @@ package aws
 
 import (
 	logicsaccount "hcm/cmd/cloud-server/logics/account"
+	"hcm/cmd/cloud-server/logics/ipam"
+	"hcm/pkg/criteria/enumor"
 )
 
 // CheckReq 检查申请单的数据是否正确
@@ -33,5 +35,16 @@ func (a *ApplicationOfCreateAwsVpc) CheckReq() error {
 		return err
 	}
 
+	opt := &ipam.VpcCidrOption{
+		BkBizID:   a.req.BkBizID,
+		Vendor:    enumor.Aws,
+		AccountID: a.req.AccountID,
+		Region:    a.req.Region,
+		Cidr:      a.req.IPv4Cidr,
+	}
+	if err := a.Ipam.ValidateVpcCidr(a.Cts.Kit, opt); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -21,6 +21,7 @@ package aws
 
 import (
 	csvpc "hcm/pkg/api/cloud-server/vpc"
+	coreapplication "hcm/pkg/api/core/application"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/thirdparty/api-gateway/itsm"
 )
@@ -58,3 +59,8 @@ func (a *ApplicationOfCreateAwsVpc) GetItsmApprover(managers []string) []itsm.Va
 func (a *ApplicationOfCreateAwsVpc) GetBkBizIDs() []int64 {
 	return []int64{a.req.BkBizID}
 }
+
+// GetPolicyFacts 获取申请单用于策略评估的事实数据
+func (a *ApplicationOfCreateAwsVpc) GetPolicyFacts() (coreapplication.PolicyFacts, error) {
+	return a.GetVpcPolicyFacts(a.req.Region, "")
+}
@@ -19,7 +19,11 @@
 
 package azure
 
-import logicsaccount "hcm/cmd/cloud-server/logics/account"
+import (
+	logicsaccount "hcm/cmd/cloud-server/logics/account"
+	"hcm/cmd/cloud-server/logics/ipam"
+	"hcm/pkg/criteria/enumor"
+)
 
 // CheckReq 检查申请单的数据是否正确
 func (a *ApplicationOfCreateAzureVpc) CheckReq() error {
@@ -31,5 +35,16 @@ func (a *ApplicationOfCreateAzureVpc) CheckReq() error {
 		return err
 	}
 
+	opt := &ipam.VpcCidrOption{
+		BkBizID:   a.req.BkBizID,
+		Vendor:    enumor.Azure,
+		AccountID: a.req.AccountID,
+		Region:    a.req.Region,
+		Cidr:      a.req.IPv4Cidr,
+	}
+	if err := a.Ipam.ValidateVpcCidr(a.Cts.Kit, opt); err != nil {
+		return err
+	}
+
 	return nil
 }
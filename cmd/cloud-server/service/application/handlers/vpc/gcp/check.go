@@ -19,7 +19,11 @@
 
 package gcp
 
-import logicsaccount "hcm/cmd/cloud-server/logics/account"
+import (
+	logicsaccount "hcm/cmd/cloud-server/logics/account"
+	"hcm/cmd/cloud-server/logics/ipam"
+	"hcm/pkg/criteria/enumor"
+)
 
 // CheckReq 检查申请单的数据是否正确
 func (a *ApplicationOfCreateGcpVpc) CheckReq() error {
@@ -31,5 +35,17 @@ func (a *ApplicationOfCreateGcpVpc) CheckReq() error {
 		return err
 	}
 
+	// gcp vpc没有网段，使用子网网段进行ipam校验
+	opt := &ipam.VpcCidrOption{
+		BkBizID:   a.req.BkBizID,
+		Vendor:    enumor.Gcp,
+		AccountID: a.req.AccountID,
+		Region:    a.req.Region,
+		Cidr:      a.req.Subnet.IPv4Cidr,
+	}
+	if err := a.Ipam.ValidateVpcCidr(a.Cts.Kit, opt); err != nil {
+		return err
+	}
+
 	return nil
 }
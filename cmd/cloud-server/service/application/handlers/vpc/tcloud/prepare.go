@@ -21,6 +21,7 @@ package tcloud
 
 import (
 	csvpc "hcm/pkg/api/cloud-server/vpc"
+	coreapplication "hcm/pkg/api/core/application"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/thirdparty/api-gateway/itsm"
 )
@@ -58,3 +59,8 @@ func (a *ApplicationOfCreateTCloudVpc) GetItsmApprover(managers []string) []itsm
 func (a *ApplicationOfCreateTCloudVpc) GetBkBizIDs() []int64 {
 	return []int64{a.req.BkBizID}
 }
+
+// GetPolicyFacts 获取申请单用于策略评估的事实数据
+func (a *ApplicationOfCreateTCloudVpc) GetPolicyFacts() (coreapplication.PolicyFacts, error) {
+	return a.GetVpcPolicyFacts(a.req.Region, a.req.Subnet.Zone)
+}
@@ -27,6 +27,9 @@ import (
 	"github.com/tidwall/gjson"
 
 	"hcm/cmd/cloud-server/logics/audit"
+	"hcm/cmd/cloud-server/logics/cvm"
+	"hcm/cmd/cloud-server/logics/disk"
+	"hcm/cmd/cloud-server/logics/ipam"
 	"hcm/cmd/cloud-server/service/application/handlers"
 	"hcm/cmd/cloud-server/service/capability"
 	"hcm/pkg/api/core"
@@ -37,6 +40,7 @@ import (
 	"hcm/pkg/cryptography"
 	"hcm/pkg/iam/auth"
 	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 	"hcm/pkg/runtime/filter"
@@ -56,12 +60,18 @@ func InitApplicationService(c *capability.Capability, bkHcmUrl string) {
 		esbCli:     c.EsbClient,
 		bkHcmUrl:   bkHcmUrl,
 		cmsiCli:    c.CmsiCli,
+		ipam:       c.Logics.Ipam,
+		cvmLgc:     c.Logics.Cvm,
+		diskLgc:    c.Logics.Disk,
 	}
 	h := rest.NewHandler()
 	h.Add("ListApplications", "POST", "/applications/list", svc.ListApplications)
 	h.Add("Get", "GET", "/applications/{application_id}", svc.Get)
 	h.Add("Cancel", "PATCH", "/applications/{application_id}/cancel", svc.Cancel)
 	h.Add("Approve", "POST", "/applications/approve", svc.Approve)
+	h.Add("NativeApprove", "POST", "/applications/{application_id}/approve", svc.NativeApprove)
+	h.Add("NativeReject", "POST", "/applications/{application_id}/reject", svc.NativeReject)
+	h.Add("NativeComment", "POST", "/applications/{application_id}/comment", svc.NativeComment)
 
 	h.Add("CreateForAddAccount", "POST", "/applications/types/add_account", svc.CreateForAddAccount)
 	h.Add("CreateForCreateCvm", "POST", "/vendors/{vendor}/applications/types/create_cvm", svc.CreateForCreateCvm)
@@ -69,6 +79,8 @@ func InitApplicationService(c *capability.Capability, bkHcmUrl string) {
 	h.Add("CreateForCreateDisk", "POST", "/vendors/{vendor}/applications/types/create_disk", svc.CreateForCreateDisk)
 	h.Add("CreateForCreateLB", "POST",
 		"/vendors/{vendor}/applications/types/create_load_balancer", svc.CreateForCreateLB)
+	h.Add("CreateForResizeCvm", "POST", "/vendors/{vendor}/applications/types/resize_cvm", svc.CreateForResizeCvm)
+	h.Add("CreateForResizeDisk", "POST", "/vendors/{vendor}/applications/types/resize_disk", svc.CreateForResizeDisk)
 
 	h.Add("CreateForCreateMainAccount", "POST",
 		"/applications/types/create_main_account", svc.CreateForCreateMainAccount)
@@ -99,6 +111,9 @@ type applicationSvc struct {
 	esbCli     esb.Client
 	bkHcmUrl   string
 	cmsiCli    cmsi.Client
+	ipam       ipam.Interface
+	cvmLgc     cvm.Interface
+	diskLgc    disk.Interface
 }
 
 func (a *applicationSvc) getCallbackUrl() string {
@@ -114,18 +129,21 @@ func (a *applicationSvc) getHandlerOption(cts *rest.Contexts) *handlers.HandlerO
 		Cipher:    a.cipher,
 		Audit:     a.audit,
 		CmsiCli:   a.cmsiCli,
+		Ipam:      a.ipam,
+		CvmLgc:    a.cvmLgc,
+		DiskLgc:   a.diskLgc,
 	}
 }
 
-func (a *applicationSvc) getApprovalProcessInfo(
-	cts *rest.Contexts, applicationType enumor.ApplicationType,
-) (int64, []string, error) {
+func (a *applicationSvc) getApprovalProcess(
+	kt *kit.Kit, applicationType enumor.ApplicationType,
+) (*dataproto.ApprovalProcessResp, error) {
 	// DB中添加4条记录，分别对应add_account、create_cvm、create_vpc、create_disk
 	// Note：目前4条记录对应一个itsm流程id，后续如果要使用其它流程可直接修改数据库适配
-	// 新增类型只需要增加对应的tye和DB记录
+	// 新增类型只需要增加对应的tye和DB记录，审批引擎可通过engine字段按申请类型切换为内置审批引擎
 	result, err := a.client.DataService().Global.ApprovalProcess.List(
-		cts.Kit.Ctx,
-		cts.Kit.Header(),
+		kt.Ctx,
+		kt.Header(),
 		&dataproto.ApprovalProcessListReq{
 			Filter: &filter.Expression{
 				Op: filter.And,
@@ -145,13 +163,13 @@ func (a *applicationSvc) getApprovalProcessInfo(
 		},
 	)
 	if err != nil {
-		return 0, nil, err
+		return nil, err
 	}
 	if result.Details == nil || len(result.Details) != 1 {
-		return 0, nil, fmt.Errorf("approval process of [%s] not init", applicationType)
+		return nil, fmt.Errorf("approval process of [%s] not init", applicationType)
 	}
 
-	return result.Details[0].ServiceID, strings.Split(result.Details[0].Managers, ","), nil
+	return result.Details[0], nil
 }
 
 func (a *applicationSvc) updateStatusWithDetail(
@@ -212,6 +230,25 @@ func (a *applicationSvc) checkApplyResPermission(cts *rest.Contexts, resType met
 	return nil
 }
 
+// checkApplyResOperatePermission 校验对已有资源发起变更类申请的权限，资源需已分配到业务，按资源所属业务鉴权
+func (a *applicationSvc) checkApplyResOperatePermission(cts *rest.Contexts, resType meta.ResourceType,
+	cloudResType enumor.CloudResourceType, id string) error {
+
+	info, err := a.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit, cloudResType, id)
+	if err != nil {
+		logs.Errorf("get %s basic info failed, err: %v, id: %s, rid: %s", cloudResType, err, id, cts.Kit.Rid)
+		return err
+	}
+
+	if info.BkBizID <= 0 {
+		return errf.Newf(errf.InvalidParameter, "%s %s is not assigned to biz", cloudResType, id)
+	}
+
+	// authorize
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: resType, Action: meta.Apply}, BizID: info.BkBizID}
+	return a.authorizer.AuthorizeWithPerm(cts.Kit, authRes)
+}
+
 func (a *applicationSvc) checkActionPermission(cts *rest.Contexts, resType meta.ResourceType,
 	action meta.Action) error {
 
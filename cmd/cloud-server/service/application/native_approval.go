@@ -0,0 +1,357 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hcm/cmd/cloud-server/service/application/handlers"
+	proto "hcm/pkg/api/cloud-server/application"
+	coreapplication "hcm/pkg/api/core/application"
+	dataproto "hcm/pkg/api/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+	"hcm/pkg/thirdparty/esb/cmdb"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+)
+
+const nativeApprovalMailTitle = "HCM申请单[%s]待您审批"
+
+// createNativeApproval 根据审批流程配置的审批阶段生成内置审批引擎的审批详情，返回单据号和审批详情
+func (a *applicationSvc) createNativeApproval(kt *kit.Kit, handler handlers.ApplicationHandler,
+	process *dataproto.ApprovalProcessResp, managers []string) (string, string, error) {
+
+	approval := &coreapplication.NativeApproval{
+		Revision:     1,
+		CurrentStage: 0,
+		TimeoutHours: process.TimeoutHours,
+		Stages:       make([]coreapplication.NativeApprovalStage, 0, len(process.Stages)),
+		Records:      make([]coreapplication.ApprovalRecord, 0),
+	}
+	for _, stage := range process.Stages {
+		approvers, err := a.resolveStageApprovers(kt, handler, stage, managers)
+		if err != nil {
+			return "", "", err
+		}
+
+		approval.Stages = append(approval.Stages, coreapplication.NativeApprovalStage{
+			Name:         stage.Name,
+			ApproverType: stage.ApproverType,
+			Approvers:    approvers,
+			Status:       enumor.WaitingApprovalStage,
+		})
+	}
+	if len(approval.Stages) == 0 {
+		return "", "", fmt.Errorf("approval process of [%s] has no stages", process.ApplicationType)
+	}
+	startNativeApprovalStage(approval, 0)
+
+	detail, err := json.MarshalToString(approval)
+	if err != nil {
+		return "", "", fmt.Errorf("marshal native approval detail failed, err: %v", err)
+	}
+
+	return genApplicationSN(), detail, nil
+}
+
+// resolveStageApprovers 获取审批阶段的审批人，未获取到审批人时由平台管理员审批，避免单据无人审批
+func (a *applicationSvc) resolveStageApprovers(kt *kit.Kit, handler handlers.ApplicationHandler,
+	stage coreapplication.ApprovalStage, managers []string) ([]string, error) {
+
+	approvers := make([]string, 0)
+	switch stage.ApproverType {
+	case enumor.PlatformManagerApprover:
+		approvers = append(approvers, managers...)
+	case enumor.StaticUserApprover:
+		approvers = append(approvers, stage.Users...)
+	case enumor.AccountManagerApprover:
+		// 账号负责人复用ITSM审批人变量的解析逻辑
+		for _, one := range handler.GetItsmApprover(managers) {
+			if one.Variable == "account_manager" {
+				approvers = append(approvers, one.Approvers...)
+			}
+		}
+	case enumor.BizManagerApprover:
+		maintainers, err := a.listBizMaintainers(kt, handler.GetBkBizIDs())
+		if err != nil {
+			return nil, err
+		}
+		approvers = append(approvers, maintainers...)
+	default:
+		return nil, fmt.Errorf("unsupported approver type: %s", stage.ApproverType)
+	}
+
+	approvers = slice.Unique(slice.Filter(approvers, func(user string) bool { return len(user) != 0 }))
+	if len(approvers) == 0 {
+		logs.Warnf("approval stage %s has no %s approver, use platform managers instead, rid: %s", stage.Name,
+			stage.ApproverType, kt.Rid)
+		return managers, nil
+	}
+
+	return approvers, nil
+}
+
+// listBizMaintainers 查询CMDB业务的运维人员
+func (a *applicationSvc) listBizMaintainers(kt *kit.Kit, bizIDs []int64) ([]string, error) {
+	if len(bizIDs) == 0 {
+		return make([]string, 0), nil
+	}
+
+	params := &cmdb.SearchBizParams{
+		Fields: []string{"bk_biz_id", "bk_biz_maintainer"},
+		BizPropertyFilter: &cmdb.QueryFilter{
+			Rule: &cmdb.CombinedRule{
+				Condition: cmdb.ConditionAnd,
+				Rules: []cmdb.Rule{
+					&cmdb.AtomRule{Field: "bk_biz_id", Operator: cmdb.OperatorIn, Value: bizIDs},
+				},
+			},
+		},
+	}
+	result, err := a.esbCli.Cmdb().SearchBusiness(kt, params)
+	if err != nil {
+		logs.Errorf("call cmdb search business failed, err: %v, biz ids: %v, rid: %s", err, bizIDs, kt.Rid)
+		return nil, fmt.Errorf("call cmdb search business api failed, err: %v", err)
+	}
+
+	maintainers := make([]string, 0)
+	for _, biz := range result.Info {
+		maintainers = append(maintainers, strings.Split(biz.BizMaintainer, ",")...)
+	}
+
+	return maintainers, nil
+}
+
+// startNativeApprovalStage 开始指定的审批阶段，并计算审批超时时间
+func startNativeApprovalStage(approval *coreapplication.NativeApproval, stageIdx int) {
+	approval.CurrentStage = stageIdx
+	approval.Stages[stageIdx].Status = enumor.PendingApprovalStage
+	if approval.TimeoutHours > 0 {
+		deadline := time.Now().Add(time.Duration(approval.TimeoutHours) * time.Hour)
+		approval.Stages[stageIdx].Deadline = times.ConvStdTimeFormat(deadline)
+	}
+}
+
+// NativeApprove 内置审批引擎审批通过
+func (a *applicationSvc) NativeApprove(cts *rest.Contexts) (interface{}, error) {
+	return a.operateNativeApproval(cts, enumor.ApproveAction)
+}
+
+// NativeReject 内置审批引擎审批驳回
+func (a *applicationSvc) NativeReject(cts *rest.Contexts) (interface{}, error) {
+	return a.operateNativeApproval(cts, enumor.RejectAction)
+}
+
+// NativeComment 内置审批引擎审批评论
+func (a *applicationSvc) NativeComment(cts *rest.Contexts) (interface{}, error) {
+	return a.operateNativeApproval(cts, enumor.CommentAction)
+}
+
+func (a *applicationSvc) operateNativeApproval(cts *rest.Contexts, action enumor.ApprovalAction) (
+	interface{}, error) {
+
+	req := new(proto.NativeApprovalOperateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	applicationID := cts.PathParameter("application_id").String()
+	application, err := a.client.DataService().Global.Application.Get(cts.Kit.Ctx, cts.Kit.Header(), applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if application.Source != enumor.ApplicationSourceNative {
+		return nil, errf.Newf(errf.InvalidParameter, "application %s is not approved by native approval engine",
+			applicationID)
+	}
+	if application.Status != enumor.Pending {
+		return nil, errf.Newf(errf.InvalidParameter, "application %s is %s, can not be operated", applicationID,
+			application.Status)
+	}
+
+	approval := new(coreapplication.NativeApproval)
+	if err = json.UnmarshalFromString(application.ApprovalDetail, approval); err != nil {
+		logs.Errorf("unmarshal application approval detail failed, err: %v, id: %s, rid: %s", err, applicationID,
+			cts.Kit.Rid)
+		return nil, err
+	}
+	if approval.CurrentStage < 0 || approval.CurrentStage >= len(approval.Stages) {
+		return nil, fmt.Errorf("application %s current stage %d is invalid", applicationID, approval.CurrentStage)
+	}
+
+	if err = checkNativeApprovalOperator(cts.Kit.User, application, approval, action); err != nil {
+		return nil, errf.NewFromErr(errf.PermissionDenied, err)
+	}
+
+	revision := approval.Revision
+	status := applyNativeApprovalAction(approval, cts.Kit.User, action, req.Comment, time.Now())
+
+	detail, err := json.MarshalToString(approval)
+	if err != nil {
+		return nil, fmt.Errorf("marshal native approval detail failed, err: %v", err)
+	}
+
+	// 按审批前的状态和审批详情版本进行CAS更新，并发审批或超时升级时只有一个操作能够成功，避免重复交付
+	updateReq := &dataproto.ApplicationUpdateReq{Status: status, ApprovalDetail: &detail,
+		ExpectedStatus: enumor.Pending, ExpectedApprovalRevision: nativeApprovalRevisionCond(revision)}
+	if _, err = a.client.DataService().Global.Application.Update(cts.Kit, applicationID, updateReq); err != nil {
+		logs.Errorf("update application approval detail failed, err: %v, id: %s, rid: %s", err, applicationID,
+			cts.Kit.Rid)
+		if ef := errf.Error(err); ef != nil && ef.Code == errf.RecordNotUpdate {
+			return nil, errf.Newf(errf.RecordNotUpdate, "application %s has been changed by others, please "+
+				"refresh and retry", applicationID)
+		}
+		return nil, err
+	}
+
+	switch {
+	case status == enumor.Delivering:
+		// 审批通过后需要进行资源交付，与ITSM审批回调使用相同的交付流程，只有CAS更新成功的审批操作才会交付
+		go a.deliver(cts, application)
+	case action == enumor.ApproveAction:
+		a.notifyNativeApprovers(cts.Kit, applicationID, application.Type, detail)
+	}
+
+	return nil, nil
+}
+
+// applyNativeApprovalAction 将审批操作应用到审批详情上，返回操作后的申请单状态
+func applyNativeApprovalAction(approval *coreapplication.NativeApproval, operator string,
+	action enumor.ApprovalAction, comment string, now time.Time) enumor.ApplicationStatus {
+
+	approval.Revision++
+	approval.Records = append(approval.Records, coreapplication.ApprovalRecord{
+		Stage:     approval.CurrentStage,
+		Operator:  operator,
+		Action:    action,
+		Comment:   comment,
+		CreatedAt: times.ConvStdTimeFormat(now),
+	})
+
+	status := enumor.Pending
+	switch action {
+	case enumor.ApproveAction:
+		approval.Stages[approval.CurrentStage].Status = enumor.PassApprovalStage
+		if approval.CurrentStage == len(approval.Stages)-1 {
+			// 最后一个审批阶段通过后，单据进入交付中
+			status = enumor.Delivering
+		} else {
+			startNativeApprovalStage(approval, approval.CurrentStage+1)
+		}
+	case enumor.RejectAction:
+		approval.Stages[approval.CurrentStage].Status = enumor.RejectedApprovalStage
+		status = enumor.Rejected
+	}
+
+	return status
+}
+
+// nativeApprovalRevisionCond 返回CAS更新审批详情时期望的版本，历史单据的审批详情没有版本，只能按申请单状态进行CAS
+func nativeApprovalRevisionCond(revision int) *int {
+	if revision == 0 {
+		return nil
+	}
+	return &revision
+}
+
+// checkNativeApprovalOperator 审批通过和驳回只能由当前阶段审批人操作，评论可以由申请人和任一阶段审批人操作
+func checkNativeApprovalOperator(user string, application *dataproto.ApplicationResp,
+	approval *coreapplication.NativeApproval, action enumor.ApprovalAction) error {
+
+	switch action {
+	case enumor.ApproveAction, enumor.RejectAction:
+		if slice.IsItemInSlice(approval.Stages[approval.CurrentStage].Approvers, user) {
+			return nil
+		}
+		return fmt.Errorf("%s is not the approver of current stage", user)
+	case enumor.CommentAction:
+		if application.Applicant == user {
+			return nil
+		}
+		for _, stage := range approval.Stages {
+			if slice.IsItemInSlice(stage.Approvers, user) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s is neither the applicant nor the approver", user)
+	default:
+		return fmt.Errorf("unsupported approval action: %s", action)
+	}
+}
+
+// notifyNativeApprovers 邮件通知当前阶段的审批人，通知失败不影响审批流程
+func (a *applicationSvc) notifyNativeApprovers(kt *kit.Kit, applicationID string, appType enumor.ApplicationType,
+	approvalDetail string) {
+
+	approval := new(coreapplication.NativeApproval)
+	if err := json.UnmarshalFromString(approvalDetail, approval); err != nil {
+		logs.Errorf("unmarshal application approval detail failed, err: %v, id: %s, rid: %s", err, applicationID,
+			kt.Rid)
+		return
+	}
+	if approval.CurrentStage < 0 || approval.CurrentStage >= len(approval.Stages) {
+		return
+	}
+
+	if err := sendNativeApprovalMail(kt, a.cmsiCli, a.bkHcmUrl, applicationID, appType,
+		approval.Stages[approval.CurrentStage]); err != nil {
+		logs.Errorf("send native approval mail failed, err: %v, id: %s, rid: %s", err, applicationID, kt.Rid)
+	}
+}
+
+func sendNativeApprovalMail(kt *kit.Kit, cmsiCli cmsi.Client, bkHcmUrl string, applicationID string,
+	appType enumor.ApplicationType, stage coreapplication.NativeApprovalStage) error {
+
+	if cmsiCli == nil {
+		return errors.New("cmsi client is not initialized")
+	}
+
+	content := fmt.Sprintf("申请单[%s]（%s）进入审批阶段[%s]，请前往 %s 处理。", applicationID, appType, stage.Name,
+		strings.TrimRight(bkHcmUrl, "/"))
+	if len(stage.Deadline) != 0 {
+		content += fmt.Sprintf("超过 %s 未审批将升级给平台管理员审批。", stage.Deadline)
+	}
+
+	mail := &cmsi.CmsiMail{
+		ReceiverUserName: strings.Join(stage.Approvers, ","),
+		Title:            fmt.Sprintf(nativeApprovalMailTitle, applicationID),
+		Content:          content,
+	}
+	return cmsiCli.SendMail(kt, mail)
+}
+
+// parseNativeApprovalDeadline 解析审批阶段的超时时间
+func parseNativeApprovalDeadline(deadline string) (time.Time, error) {
+	return time.Parse(constant.TimeStdFormat, deadline)
+}
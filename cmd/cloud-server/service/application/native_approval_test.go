@@ -0,0 +1,135 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"testing"
+	"time"
+
+	coreapplication "hcm/pkg/api/core/application"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+)
+
+func newTestNativeApproval(stageCnt int) *coreapplication.NativeApproval {
+	approval := &coreapplication.NativeApproval{Revision: 1, TimeoutHours: 1}
+	for i := 0; i < stageCnt; i++ {
+		approval.Stages = append(approval.Stages, coreapplication.NativeApprovalStage{
+			Name:      "stage",
+			Approvers: []string{"approver"},
+			Status:    enumor.WaitingApprovalStage,
+		})
+	}
+	startNativeApprovalStage(approval, 0)
+	return approval
+}
+
+func TestApplyNativeApprovalAction(t *testing.T) {
+	approval := newTestNativeApproval(2)
+
+	// 第一个阶段通过后进入下一个阶段，单据仍然是待审批
+	status := applyNativeApprovalAction(approval, "approver", enumor.ApproveAction, "", time.Now())
+	if status != enumor.Pending {
+		t.Fatalf("expect status pending after first stage, got %s", status)
+	}
+	if approval.CurrentStage != 1 || approval.Stages[0].Status != enumor.PassApprovalStage ||
+		approval.Stages[1].Status != enumor.PendingApprovalStage || len(approval.Stages[1].Deadline) == 0 {
+		t.Fatalf("stage is not progressed, approval: %+v", approval)
+	}
+
+	// 评论不改变审批阶段
+	status = applyNativeApprovalAction(approval, "applicant", enumor.CommentAction, "comment", time.Now())
+	if status != enumor.Pending || approval.CurrentStage != 1 {
+		t.Fatalf("comment should not change approval, status: %s, approval: %+v", status, approval)
+	}
+
+	// 最后一个阶段通过后进入交付中
+	status = applyNativeApprovalAction(approval, "approver", enumor.ApproveAction, "", time.Now())
+	if status != enumor.Delivering || approval.Stages[1].Status != enumor.PassApprovalStage {
+		t.Fatalf("expect delivering after last stage, status: %s, approval: %+v", status, approval)
+	}
+
+	if approval.Revision != 4 || len(approval.Records) != 3 {
+		t.Fatalf("expect revision 4 with 3 records, got revision %d with %d records", approval.Revision,
+			len(approval.Records))
+	}
+}
+
+func TestApplyNativeApprovalReject(t *testing.T) {
+	approval := newTestNativeApproval(2)
+
+	status := applyNativeApprovalAction(approval, "approver", enumor.RejectAction, "no", time.Now())
+	if status != enumor.Rejected {
+		t.Fatalf("expect rejected, got %s", status)
+	}
+	if approval.CurrentStage != 0 || approval.Stages[0].Status != enumor.RejectedApprovalStage ||
+		approval.Stages[1].Status != enumor.WaitingApprovalStage {
+		t.Fatalf("unexpected stages after reject, approval: %+v", approval)
+	}
+}
+
+func TestEscalateNativeApprovalStage(t *testing.T) {
+	now := time.Now()
+	managers := []string{"manager", "approver"}
+
+	notExpired := newTestNativeApproval(1)
+	escalated, err := escalateNativeApprovalStage(notExpired, managers, "system", now)
+	if err != nil || escalated {
+		t.Fatalf("stage before deadline should not be escalated, escalated: %v, err: %v", escalated, err)
+	}
+
+	expired := newTestNativeApproval(1)
+	expired.Stages[0].Deadline = times.ConvStdTimeFormat(now.Add(-time.Minute))
+	escalated, err = escalateNativeApprovalStage(expired, managers, "system", now)
+	if err != nil || !escalated {
+		t.Fatalf("expired stage should be escalated, escalated: %v, err: %v", escalated, err)
+	}
+	stage := expired.Stages[0]
+	if !stage.Escalated || len(stage.Approvers) != 2 || !slice.IsItemInSlice(stage.Approvers, "manager") {
+		t.Fatalf("unexpected escalated stage: %+v", stage)
+	}
+	if expired.Revision != 2 || expired.Records[0].Action != enumor.EscalateAction {
+		t.Fatalf("escalation should bump revision and add record, approval: %+v", expired)
+	}
+
+	// 已经升级过或已经审批结束的阶段不会重复升级
+	escalated, err = escalateNativeApprovalStage(expired, managers, "system", now)
+	if err != nil || escalated {
+		t.Fatalf("escalated stage should not be escalated again, escalated: %v, err: %v", escalated, err)
+	}
+
+	approved := newTestNativeApproval(1)
+	approved.Stages[0].Deadline = times.ConvStdTimeFormat(now.Add(-time.Minute))
+	applyNativeApprovalAction(approved, "approver", enumor.ApproveAction, "", now)
+	escalated, err = escalateNativeApprovalStage(approved, managers, "system", now)
+	if err != nil || escalated {
+		t.Fatalf("approved stage should not be escalated, escalated: %v, err: %v", escalated, err)
+	}
+}
+
+func TestNativeApprovalRevisionCond(t *testing.T) {
+	if nativeApprovalRevisionCond(0) != nil {
+		t.Fatalf("legacy approval without revision should not use revision condition")
+	}
+	if cond := nativeApprovalRevisionCond(3); cond == nil || *cond != 3 {
+		t.Fatalf("expect revision condition 3, got %v", cond)
+	}
+}
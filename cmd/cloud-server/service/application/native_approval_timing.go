@@ -0,0 +1,186 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"hcm/pkg/api/core"
+	coreapplication "hcm/pkg/api/core/application"
+	dataproto "hcm/pkg/api/data-service"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/serviced"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+)
+
+// TimingEscalateNativeApproval 定时检查内置审批引擎单据的审批超时情况，超时的审批阶段升级给平台管理员审批
+func TimingEscalateNativeApproval(cliSet *client.ClientSet, state serviced.State, cmsiCli cmsi.Client,
+	bkHcmUrl string, interval time.Duration) {
+
+	svc := &applicationSvc{
+		client:   cliSet,
+		cmsiCli:  cmsiCli,
+		bkHcmUrl: bkHcmUrl,
+	}
+
+	for {
+		time.Sleep(interval)
+
+		if !state.IsMaster() {
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		if err := svc.escalateNativeApproval(kt); err != nil {
+			logs.Errorf("escalate native approval failed, err: %v, rid: %s", err, kt.Rid)
+		}
+	}
+}
+
+func (a *applicationSvc) escalateNativeApproval(kt *kit.Kit) error {
+	listReq := &dataproto.ApplicationListReq{
+		Filter: tools.EqualWithOpExpression(filter.And, map[string]interface{}{
+			"source": enumor.ApplicationSourceNative,
+			"status": enumor.Pending,
+		}),
+		Page: core.NewDefaultBasePage(),
+	}
+
+	// 同一申请类型的平台管理员只查询一次
+	managersMap := make(map[enumor.ApplicationType][]string)
+	now := time.Now()
+	for {
+		result, err := a.client.DataService().Global.Application.List(kt, listReq)
+		if err != nil {
+			return fmt.Errorf("list pending native application failed, err: %v", err)
+		}
+
+		for _, application := range result.Details {
+			managers, exists := managersMap[application.Type]
+			if !exists {
+				process, err := a.getApprovalProcess(kt, application.Type)
+				if err != nil {
+					logs.Errorf("get approval process failed, err: %v, type: %s, rid: %s", err, application.Type,
+						kt.Rid)
+					continue
+				}
+				managers = strings.Split(process.Managers, ",")
+				managersMap[application.Type] = managers
+			}
+
+			if err = a.escalateOneNativeApproval(kt, application, managers, now); err != nil {
+				logs.Errorf("escalate application %s approval failed, err: %v, rid: %s", application.ID, err,
+					kt.Rid)
+			}
+		}
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return nil
+}
+
+func (a *applicationSvc) escalateOneNativeApproval(kt *kit.Kit, application *dataproto.ApplicationResp,
+	managers []string, now time.Time) error {
+
+	approval := new(coreapplication.NativeApproval)
+	if err := json.UnmarshalFromString(application.ApprovalDetail, approval); err != nil {
+		return fmt.Errorf("unmarshal approval detail failed, err: %v", err)
+	}
+
+	revision := approval.Revision
+	escalated, err := escalateNativeApprovalStage(approval, managers, kt.User, now)
+	if err != nil || !escalated {
+		return err
+	}
+
+	detail, err := json.MarshalToString(approval)
+	if err != nil {
+		return fmt.Errorf("marshal approval detail failed, err: %v", err)
+	}
+
+	// 按查询时的状态和审批详情版本进行CAS更新，避免覆盖并发的审批操作，例如把已经进入交付中的单据改回待审批
+	updateReq := &dataproto.ApplicationUpdateReq{Status: enumor.Pending, ApprovalDetail: &detail,
+		ExpectedStatus: enumor.Pending, ExpectedApprovalRevision: nativeApprovalRevisionCond(revision)}
+	if _, err = a.client.DataService().Global.Application.Update(kt, application.ID, updateReq); err != nil {
+		if ef := errf.Error(err); ef != nil && ef.Code == errf.RecordNotUpdate {
+			logs.Infof("application %s has been changed during escalation, skip it, rid: %s", application.ID,
+				kt.Rid)
+			return nil
+		}
+		return err
+	}
+
+	stage := approval.Stages[approval.CurrentStage]
+
+	logs.Infof("application %s stage %s approval timeout, escalate to %v, rid: %s", application.ID, stage.Name,
+		managers, kt.Rid)
+
+	a.notifyNativeApprovers(kt, application.ID, application.Type, detail)
+	return nil
+}
+
+// escalateNativeApprovalStage 当前审批阶段超时未审批时，将平台管理员加入审批人，返回是否进行了升级
+func escalateNativeApprovalStage(approval *coreapplication.NativeApproval, managers []string, operator string,
+	now time.Time) (bool, error) {
+
+	if approval.CurrentStage < 0 || approval.CurrentStage >= len(approval.Stages) {
+		return false, fmt.Errorf("current stage %d is invalid", approval.CurrentStage)
+	}
+
+	stage := &approval.Stages[approval.CurrentStage]
+	if stage.Status != enumor.PendingApprovalStage || stage.Escalated || len(stage.Deadline) == 0 {
+		return false, nil
+	}
+	deadline, err := parseNativeApprovalDeadline(stage.Deadline)
+	if err != nil {
+		return false, fmt.Errorf("parse stage deadline %s failed, err: %v", stage.Deadline, err)
+	}
+	if now.Before(deadline) {
+		return false, nil
+	}
+
+	approval.Revision++
+	stage.Approvers = slice.Unique(append(stage.Approvers, managers...))
+	stage.Escalated = true
+	approval.Records = append(approval.Records, coreapplication.ApprovalRecord{
+		Stage:     approval.CurrentStage,
+		Operator:  operator,
+		Action:    enumor.EscalateAction,
+		Comment:   "审批超时，升级给平台管理员审批",
+		CreatedAt: times.ConvStdTimeFormat(now),
+	})
+
+	return true, nil
+}
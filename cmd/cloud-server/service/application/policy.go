@@ -0,0 +1,154 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	applicationpolicy "hcm/cmd/cloud-server/logics/application-policy"
+	"hcm/cmd/cloud-server/service/application/handlers"
+	"hcm/pkg/api/core"
+	coreapplication "hcm/pkg/api/core/application"
+	dataproto "hcm/pkg/api/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/rand"
+)
+
+const policyEvaluationContentKey = "policy_evaluation"
+
+// evaluateApplicationPolicies 使用申请业务和申请类型生效的申请策略评估申请单，没有生效的策略时返回nil
+func (a *applicationSvc) evaluateApplicationPolicies(kt *kit.Kit, handler handlers.ApplicationHandler,
+	bkBizIDs []int64, tags map[string]string) (*coreapplication.PolicyEvaluation, error) {
+
+	policies, err := a.listEffectiveApplicationPolicies(kt, handler.GetType(), bkBizIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	facts, err := handler.GetPolicyFacts()
+	if err != nil {
+		logs.Errorf("get application policy facts failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, fmt.Errorf("get application policy facts failed, err: %v", err)
+	}
+	facts[coreapplication.FactBkBizIDs] = bkBizIDs
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	facts[coreapplication.FactTags] = tags
+
+	return applicationpolicy.Evaluate(policies, facts), nil
+}
+
+// listEffectiveApplicationPolicies 查询对申请业务（包括全部业务）和申请类型（包括全部类型）生效的已启用策略
+func (a *applicationSvc) listEffectiveApplicationPolicies(kt *kit.Kit, applicationType enumor.ApplicationType,
+	bkBizIDs []int64) ([]*coreapplication.ApplicationPolicy, error) {
+
+	req := &core.ListReq{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				filter.AtomRule{Field: "enabled", Op: filter.Equal.Factory(), Value: true},
+				filter.AtomRule{Field: "bk_biz_id", Op: filter.In.Factory(), Value: append([]int64{0}, bkBizIDs...)},
+				filter.AtomRule{Field: "application_type", Op: filter.In.Factory(),
+					Value: []string{"", string(applicationType)}},
+			},
+		},
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := a.client.DataService().Global.ApplicationPolicy.List(kt, req)
+	if err != nil {
+		logs.Errorf("list application policy failed, err: %v, type: %s, biz: %v, rid: %s", err, applicationType,
+			bkBizIDs, kt.Rid)
+		return nil, err
+	}
+
+	return result.Details, nil
+}
+
+// appendPolicyEvaluation 将策略评估结果记录到申请单内容中
+func appendPolicyEvaluation(content string, evaluation *coreapplication.PolicyEvaluation) (string, error) {
+	return appendContentField(content, policyEvaluationContentKey, evaluation)
+}
+
+// appendContentField 在申请单内容中追加字段
+func appendContentField(content string, key string, value interface{}) (string, error) {
+	fields := make(map[string]interface{})
+	if err := json.UnmarshalFromString(content, &fields); err != nil {
+		return "", fmt.Errorf("unmarshal application content failed, err: %v", err)
+	}
+	fields[key] = value
+
+	return json.MarshalToString(fields)
+}
+
+// createAndDecideByPolicy 按申请策略的评估结果创建申请单，不创建审批单据。
+// 违反策略时驳回并返回原因，满足自动审批策略时免审批直接交付
+func (a *applicationSvc) createAndDecideByPolicy(cts *rest.Contexts, req *dataproto.ApplicationCreateReq,
+	evaluation *coreapplication.PolicyEvaluation) (interface{}, error) {
+
+	req.SN = genApplicationSN()
+	req.Source = enumor.ApplicationSourcePolicy
+	switch evaluation.Result {
+	case enumor.PolicyEvaluationDeny:
+		req.Status = enumor.Rejected
+	case enumor.PolicyEvaluationAutoApprove:
+		req.Status = enumor.Delivering
+	default:
+		return nil, fmt.Errorf("policy evaluation result %s can not decide application", evaluation.Result)
+	}
+
+	result, err := a.client.DataService().Global.Application.Create(cts.Kit.Ctx, cts.Kit.Header(), req)
+	if err != nil {
+		logs.Errorf("create application by policy evaluation failed, err: %v, result: %s, rid: %s", err,
+			evaluation.Result, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if evaluation.Result == enumor.PolicyEvaluationDeny {
+		return nil, errf.Newf(errf.ApplicationPolicyDenied, "application %s is rejected by policy: %s", result.ID,
+			strings.Join(evaluation.Reasons(), "; "))
+	}
+
+	// 自动审批的单据与审批通过的单据使用相同的交付流程
+	application, err := a.client.DataService().Global.Application.Get(cts.Kit.Ctx, cts.Kit.Header(), result.ID)
+	if err != nil {
+		logs.Errorf("get auto approved application failed, err: %v, id: %s, rid: %s", err, result.ID, cts.Kit.Rid)
+		return nil, err
+	}
+	go a.deliver(cts, application)
+
+	return result, nil
+}
+
+// genApplicationSN 生成不经过ITSM的申请单的单据号
+func genApplicationSN() string {
+	return fmt.Sprintf("HCM%s%s", time.Now().Format("20060102150405"), strings.ToUpper(rand.String(6)))
+}
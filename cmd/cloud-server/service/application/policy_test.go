@@ -0,0 +1,45 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"testing"
+
+	"hcm/cmd/cloud-server/service/application/handlers"
+)
+
+func TestApplicationTagsContent(t *testing.T) {
+	// 没有记录申请单标签的内容解析为空
+	tags, err := handlers.ParseApplicationTags(`{"account_id":"00000001"}`)
+	if err != nil || len(tags) != 0 {
+		t.Fatalf("expect no tags, got %v, err: %v", tags, err)
+	}
+
+	content, err := appendContentField(`{"account_id":"00000001"}`, handlers.ApplicationTagsContentKey,
+		map[string]string{"owner": "tom"})
+	if err != nil {
+		t.Fatalf("append application tags failed, err: %v", err)
+	}
+
+	tags, err = handlers.ParseApplicationTags(content)
+	if err != nil || len(tags) != 1 || tags["owner"] != "tom" {
+		t.Fatalf("expect tags owner=tom, got %v, err: %v", tags, err)
+	}
+}
@@ -26,7 +26,10 @@ import (
 	"hcm/pkg/api/core"
 	dataproto "hcm/pkg/api/data-service"
 	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
 	"hcm/pkg/iam/auth"
+	"hcm/pkg/iam/meta"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 	"hcm/pkg/runtime/filter"
@@ -43,6 +46,14 @@ func InitService(c *capability.Capability) {
 
 	h.Add("GetApprovalProcessServiceID", http.MethodGet, "/approval_processes/service_id",
 		svc.GetApprovalProcessServiceID)
+	h.Add("ListApprovalProcess", http.MethodPost, "/approval_processes/list", svc.ListApprovalProcess)
+	h.Add("UpdateApprovalProcess", http.MethodPatch, "/approval_processes/{id}", svc.UpdateApprovalProcess)
+
+	h.Add("CreateApplicationPolicy", http.MethodPost, "/application_policies/create", svc.CreateApplicationPolicy)
+	h.Add("UpdateApplicationPolicy", http.MethodPatch, "/application_policies/{id}", svc.UpdateApplicationPolicy)
+	h.Add("ListApplicationPolicy", http.MethodPost, "/application_policies/list", svc.ListApplicationPolicy)
+	h.Add("BatchDeleteApplicationPolicy", http.MethodDelete, "/application_policies/batch",
+		svc.BatchDeleteApplicationPolicy)
 
 	h.Load(c.WebService)
 }
@@ -72,6 +83,10 @@ func (svc *service) GetApprovalProcessServiceID(cts *rest.Contexts) (interface{}
 	serviceIdMap := make(map[int64]struct{})
 	serviceIds := make([]int64, 0)
 	for _, one := range result.Details {
+		// 内置审批引擎不依赖ITSM服务
+		if one.Engine == enumor.NativeApprovalEngine {
+			continue
+		}
 		if _, exists := serviceIdMap[one.ServiceID]; !exists {
 			serviceIdMap[one.ServiceID] = struct{}{}
 			serviceIds = append(serviceIds, one.ServiceID)
@@ -80,3 +95,51 @@ func (svc *service) GetApprovalProcessServiceID(cts *rest.Contexts) (interface{}
 
 	return serviceIds, nil
 }
+
+// ListApprovalProcess 查询各申请类型的审批流程配置
+func (svc *service) ListApprovalProcess(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Application, Action: meta.Find}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	listReq := &dataproto.ApprovalProcessListReq{Filter: req.Filter, Page: req.Page}
+	return svc.client.DataService().Global.ApprovalProcess.List(cts.Kit.Ctx, cts.Kit.Header(), listReq)
+}
+
+// UpdateApprovalProcess 更新申请类型的审批流程配置，可切换ITSM和内置审批引擎
+func (svc *service) UpdateApprovalProcess(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(dataproto.ApprovalProcessUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Application, Action: meta.Update}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	_, err := svc.client.DataService().Global.ApprovalProcess.Update(cts.Kit.Ctx, cts.Kit.Header(), id, req)
+	if err != nil {
+		logs.Errorf("update approval process failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
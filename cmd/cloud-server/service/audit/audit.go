@@ -53,6 +53,7 @@ func InitService(c *capability.Capability) {
 	h.Add("ListAudit", http.MethodPost, "/audits/list", svc.ListAudit)
 	h.Add("ListAuditAsyncFlow", http.MethodPost, "/audits/async_flow/list", svc.ListAuditAsyncFlow)
 	h.Add("ListAuditAsyncTask", http.MethodPost, "/audits/async_task/list", svc.ListAuditAsyncTask)
+	h.Add("VerifyAuditChain", http.MethodPost, "/audits/chains/verify", svc.VerifyAuditChain)
 
 	// biz audit apis
 	h.Add("GetBizAudit", http.MethodGet, "/bizs/{bk_biz_id}/audits/{id}", svc.GetBizAudit)
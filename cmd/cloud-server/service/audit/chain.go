@@ -0,0 +1,154 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"time"
+
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	coreaudit "hcm/pkg/api/core/audit"
+	"hcm/pkg/api/data-service/audit"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/serviced"
+)
+
+// VerifyAuditChain verify all the audit hash chains of a day, to find out whether the audits are deleted or modified.
+func (svc svc) VerifyAuditChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(cloudserver.AuditChainVerifyReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// audit chain contains audits of all the businesses, so the global audit permission is required.
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Audit, Action: meta.Find}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	// the chains of a day are named by the date with shard suffix, and the chain created before sharding is
+	// named by the date only.
+	names := []string{req.Day}
+	for shard := uint(0); shard < tableaudit.MaxChainShards; shard++ {
+		names = append(names, tableaudit.ChainName(req.Day, shard))
+	}
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("chain", names),
+		Page:   core.NewDefaultBasePage(),
+	}
+	chains, err := svc.client.DataService().Global.Audit.ListAuditChain(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list audit chains of day %s failed, err: %v, rid: %s", req.Day, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(chains.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "audit chain of %s not found", req.Day)
+	}
+
+	result := &coreaudit.ChainDayVerifyResult{Day: req.Day, Verified: true,
+		Chains: make([]coreaudit.ChainVerifyResult, 0, len(chains.Details))}
+	for _, one := range chains.Details {
+		verifyResult, err := svc.client.DataService().Global.Audit.VerifyAuditChain(cts.Kit,
+			&audit.ChainVerifyReq{Chain: one.Chain})
+		if err != nil {
+			logs.Errorf("verify audit chain %s failed, err: %v, rid: %s", one.Chain, err, cts.Kit.Rid)
+			return nil, err
+		}
+
+		result.Verified = result.Verified && verifyResult.Verified
+		result.Count += verifyResult.Count
+		result.Chains = append(result.Chains, *verifyResult)
+	}
+
+	return result, nil
+}
+
+// ChainExportTiming export the audit chain segments of the days before today which are not exported periodically.
+func ChainExportTiming(cliSet *client.ClientSet, sd serviced.State, conf cc.AuditExport) {
+	interval := time.Duration(conf.IntervalMin) * time.Minute
+	logs.Infof("audit chain export is enabled, interval: %v", interval)
+
+	for {
+		time.Sleep(interval)
+
+		if !sd.IsMaster() {
+			continue
+		}
+
+		exportChains(core.NewBackendKit(), cliSet)
+	}
+}
+
+// exportChains export all the chains which are not exported page by page, the exported chains are excluded from the
+// following pages, so the page start only skips the chains that are still not exported in previous pages.
+func exportChains(kt *kit.Kit, cliSet *client.ClientSet) {
+	today := time.Now().Format(constant.DateLayout)
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("exported_at", ""),
+		Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "chain", Order: core.Ascending},
+	}
+
+	for {
+		result, err := cliSet.DataService().Global.Audit.ListAuditChain(kt, listReq)
+		if err != nil {
+			logs.Errorf("list audit chains to be exported failed, err: %v, rid: %s", err, kt.Rid)
+			return
+		}
+
+		remained := 0
+		for _, one := range result.Details {
+			// the chain of today is still growing, it is exported after today.
+			if one.Chain >= today {
+				remained++
+				continue
+			}
+
+			exportResult, err := cliSet.DataService().Global.Audit.ExportAuditChain(kt,
+				&audit.ChainExportReq{Chain: one.Chain})
+			if err != nil {
+				logs.Errorf("export audit chain %s failed, err: %v, rid: %s", one.Chain, err, kt.Rid)
+				remained++
+				continue
+			}
+
+			logs.Infof("export audit chain %s success, count: %d, verified: %v, manifest: %s, rid: %s", one.Chain,
+				exportResult.Count, exportResult.Verified, exportResult.ManifestPath, kt.Rid)
+		}
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			return
+		}
+		listReq.Page.Start += uint32(remained)
+	}
+}
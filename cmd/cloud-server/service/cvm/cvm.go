@@ -55,6 +55,9 @@ func InitCvmService(c *capability.Capability) {
 	h.Add("BatchStartCvm", http.MethodPost, "/cvms/batch/start", svc.BatchStartCvm)
 	h.Add("BatchStopCvm", http.MethodPost, "/cvms/batch/stop", svc.BatchStopCvm)
 	h.Add("BatchRebootCvm", http.MethodPost, "/cvms/batch/reboot", svc.BatchRebootCvm)
+	h.Add("RollingOperateCvm", http.MethodPost, "/cvms/rolling/{operation}", svc.RollingOperateCvm)
+	h.Add("ResizeCvm", http.MethodPost, "/cvms/resize", svc.ResizeCvm)
+	h.Add("InquiryPriceResizeCvm", http.MethodPost, "/cvms/resize/prices/inquiry", svc.InquiryPriceResizeCvm)
 	h.Add("QueryCvmRelatedRes", http.MethodPost, "/cvms/rel_res/batch", svc.QueryCvmRelatedRes)
 
 	// 资源下回收相关接口
@@ -70,6 +73,11 @@ func InitCvmService(c *capability.Capability) {
 	h.Add("BatchStartBizCvm", http.MethodPost, "/bizs/{bk_biz_id}/cvms/batch/start", svc.BatchStartBizCvm)
 	h.Add("BatchStopBizCvm", http.MethodPost, "/bizs/{bk_biz_id}/cvms/batch/stop", svc.BatchStopBizCvm)
 	h.Add("BatchRebootBizCvm", http.MethodPost, "/bizs/{bk_biz_id}/cvms/batch/reboot", svc.BatchRebootBizCvm)
+	h.Add("RollingOperateBizCvm", http.MethodPost, "/bizs/{bk_biz_id}/cvms/rolling/{operation}",
+		svc.RollingOperateBizCvm)
+	h.Add("ResizeBizCvm", http.MethodPost, "/bizs/{bk_biz_id}/cvms/resize", svc.ResizeBizCvm)
+	h.Add("InquiryPriceResizeBizCvm", http.MethodPost, "/bizs/{bk_biz_id}/cvms/resize/prices/inquiry",
+		svc.InquiryPriceResizeBizCvm)
 	h.Add("QueryBizCvmRelatedRes", http.MethodPost, "/bizs/{bk_biz_id}/cvms/rel_res/batch", svc.QueryBizCvmRelatedRes)
 
 	// 业务下回收接口
@@ -18,7 +18,6 @@
 package cvm
 
 import (
-	"errors"
 	"fmt"
 
 	"hcm/cmd/cloud-server/logics/recycle"
@@ -29,7 +28,6 @@ import (
 	protoaudit "hcm/pkg/api/data-service/audit"
 	"hcm/pkg/api/data-service/cloud"
 	dsrecord "hcm/pkg/api/data-service/recycle-record"
-	"hcm/pkg/cc"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
@@ -40,7 +38,6 @@ import (
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 	"hcm/pkg/tools/hooks/handler"
-	"hcm/pkg/tools/maps"
 	"hcm/pkg/tools/slice"
 )
 
@@ -81,201 +78,13 @@ func (svc *cvmSvc) recycleCvmSvc(cts *rest.Contexts, validHandler handler.ValidW
 		return nil, err
 	}
 
-	// 1. 预检，有一个失败则全部失败，且不进审计
-	if err := svc.cvmLgc.RecyclePreCheck(cts.Kit, basicInfoMap); err != nil {
-		logs.Errorf("recycle precheck fail, err: %v, rid: %s", err, cts.Kit.Rid)
-		return nil, err
-	}
-
-	cvmStatus := make(map[string]*recycle.CvmDetail, len(req.Infos))
-	for _, cvmRecycleReq := range req.Infos {
-		cvmStatus[cvmRecycleReq.ID] = &recycle.CvmDetail{
-			Vendor:           basicInfoMap[cvmRecycleReq.ID].Vendor,
-			AccountID:        basicInfoMap[cvmRecycleReq.ID].AccountID,
-			CvmID:            cvmRecycleReq.ID,
-			CvmRecycleDetail: corerecord.CvmRecycleDetail{CvmRecycleOptions: cvmRecycleReq.CvmRecycleOptions},
-		}
-	}
-
-	auditInfos := slice.Map(req.Infos, func(info proto.CvmRecycleInfo) protoaudit.CloudResRecycleAuditInfo {
-		return protoaudit.CloudResRecycleAuditInfo{ResID: info.ID, Data: info.CvmRecycleOptions}
-	})
-	// create recycle audit
-	auditReq := &protoaudit.CloudResourceRecycleAuditReq{ResType: enumor.CvmAuditResType, Action: protoaudit.Recycle,
-		Infos: auditInfos,
-	}
-	if err = svc.audit.ResRecycleAudit(cts.Kit, auditReq); err != nil {
-		logs.Errorf("create recycle audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
-		return nil, err
-	}
-
-	defer func(svc *cvmSvc, kt *kit.Kit, cvmStatus map[string]*recycle.CvmDetail) {
-		err := svc.recycleCleanUp(kt, cvmStatus)
-		if err != nil {
-			logs.Errorf("failed to cleanup recycle, err: %v, rid: %s", err, kt.Rid)
-		}
-	}(svc, cts.Kit, cvmStatus)
-
-	taskID, err := svc.recycleCvm(cts.Kit, req, cvmStatus)
+	taskID, err := svc.cvmLgc.RecycleCvm(cts.Kit, req.Infos, basicInfoMap)
 	if err != nil {
 		return nil, err
 	}
 	return recycle.RecycleResult{TaskID: taskID}, nil
 }
 
-// recycleCvm  回收核心逻辑（创建recycle record）
-// 1. 获取磁盘信息
-// 2. 解绑不随主机回收磁盘
-// 3. 获取eip信息
-// 4. 解绑不随主机回收eip
-// 5. 标记磁盘和eip为被动回收
-// 6. 回收主机 (仅回收前置步骤成功的）
-func (svc *cvmSvc) recycleCvm(kt *kit.Kit, req *proto.CvmRecycleReq,
-	cvmStatus map[string]*recycle.CvmDetail) (taskID string, err error) {
-	// 获取磁盘信息
-	if err := svc.diskLgc.BatchGetDiskInfo(kt, cvmStatus); err != nil {
-		logs.Errorf("failed to get disk info of cvm, err: %v, rid: %s", err, kt.Rid)
-		return "", err
-	}
-	// 过滤出不随主机回收的磁盘，并解绑
-	failed, err := svc.diskLgc.BatchDetach(kt,
-		maps.FilterByValue(cvmStatus, func(c *recycle.CvmDetail) bool { return !c.WithDisk }))
-	if err != nil {
-		logs.Errorf("failed to detach some disks of cvm(%v), err: %v, rid: %s", failed, err, kt.Rid)
-	}
-
-	// 获取eip信息
-	if err := svc.eipLgc.BatchGetEipInfo(kt, cvmStatus); err != nil {
-		logs.Errorf("failed to get eip info of cvm, err: %v, rid: %s", err, kt.Rid)
-	}
-
-	// 过滤出不随主机回收的Eip，并解绑
-	failed, err = svc.eipLgc.BatchUnbind(kt,
-		maps.FilterByValue(cvmStatus, func(c *recycle.CvmDetail) bool { return !c.WithEip }))
-	if err != nil {
-		logs.Errorf("failed to unbind eip of cvm(%v), err: %v, rid: %s", failed, err, kt.Rid)
-	}
-
-	// 标记磁盘和eip为回收(修改disk表和eip表中的recycle_status字段为recycling)
-	err = svc.markRelatedRecycleStatus(kt, cvmStatus)
-	if err != nil {
-		return "", err
-	}
-
-	// 创建回收任务
-	opt := &dsrecord.BatchRecycleReq{
-		ResType:            enumor.CvmCloudResType,
-		DefaultRecycleTime: cc.CloudServer().Recycle.AutoDeleteTime,
-	}
-	for _, info := range req.Infos {
-		// 过滤掉已经失败的id
-		if recCvm := cvmStatus[info.ID]; recCvm != nil && recCvm.FailedAt == "" {
-			opt.Infos = append(opt.Infos,
-				dsrecord.RecycleReq{ID: info.ID, Detail: cvmStatus[info.ID].CvmRecycleDetail})
-		}
-	}
-	if len(opt.Infos) == 0 {
-		return "", errors.New("all cvm recycle failed")
-	}
-
-	// 创建回收记录
-	taskID, err = svc.client.DataService().Global.RecycleRecord.BatchRecycleCloudRes(kt, opt)
-	if err != nil {
-		logs.Errorf("fail to recycle cvm, err: %v, rid: %s", err, kt.Rid)
-		for _, info := range opt.Infos {
-			cvmStatus[info.ID].FailedAt = enumor.CvmCloudResType
-		}
-		return "", err
-	}
-
-	return taskID, nil
-}
-
-// recycleCleanUp 处理回收失败需要尝试重新绑定的eip、disk
-func (svc *cvmSvc) recycleCleanUp(kt *kit.Kit, cvmStatus map[string]*recycle.CvmDetail) error {
-
-	eipRebind := make(map[string]*recycle.CvmDetail, len(cvmStatus))
-	diskRebind := make(map[string]*recycle.CvmDetail, len(cvmStatus))
-
-	for cvmId, detail := range cvmStatus {
-		switch detail.FailedAt {
-		case "":
-			continue
-		case enumor.DiskCloudResType:
-			continue
-		case enumor.EipCloudResType:
-			diskRebind[cvmId] = detail
-		case enumor.CvmCloudResType:
-			// 	重新挂载磁盘和绑定eip
-			eipRebind[cvmId] = detail
-			diskRebind[cvmId] = detail
-		default:
-			return fmt.Errorf("unknown failed type: %v", detail.FailedAt)
-		}
-	}
-	// 	尝试重新挂载磁盘
-	err := svc.eipLgc.BatchRebind(kt, eipRebind)
-	if err != nil {
-		return err
-	}
-	err = svc.diskLgc.BatchReattachDisk(kt, diskRebind)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-// markRelatedRecycleStatus 将关联资源标记为回收状态, 创建关联回收任务
-func (svc *cvmSvc) markRelatedRecycleStatus(kt *kit.Kit, cvmStatus map[string]*recycle.CvmDetail) error {
-	var diskReqs []dsrecord.RecycleReq
-	var eipIds []string
-	for _, recCvm := range cvmStatus {
-		// 过滤掉已经失败的id
-		if recCvm.FailedAt != "" {
-			continue
-		}
-		if recCvm.WithDisk {
-			diskReqs = slice.Map(recCvm.DiskList, func(d corerecord.DiskAttachInfo) dsrecord.RecycleReq {
-				return dsrecord.RecycleReq{ID: d.DiskID, Detail: corerecord.DiskRelatedRecycleOpt{CvmID: recCvm.CvmID}}
-			})
-		}
-		if recCvm.WithEip {
-			eipIds = slice.Map(recCvm.EipList, func(e corerecord.EipBindInfo) string { return e.EipID })
-		}
-	}
-
-	if len(diskReqs) > 0 {
-		// 创建disk回收任务 RecycleTypeRelated
-		opt := &dsrecord.BatchRecycleReq{
-			ResType:            enumor.DiskCloudResType,
-			RecycleType:        enumor.RecycleTypeRelated,
-			DefaultRecycleTime: cc.CloudServer().Recycle.AutoDeleteTime,
-			Infos:              diskReqs,
-		}
-		_, err := svc.client.DataService().Global.RecycleRecord.BatchRecycleCloudRes(kt, opt)
-		if err != nil {
-			logs.Errorf("fail to create related disk recycle record, err: %v, disk infos: %v, rid: %s",
-				err, diskReqs, kt.Rid)
-			return err
-		}
-
-	}
-	if len(eipIds) > 0 {
-		// 标记eip为回收状态
-		err := svc.client.DataService().Global.RecycleRecord.BatchUpdateRecycleStatus(kt,
-			&dsrecord.BatchUpdateRecycleStatusReq{
-				ResType:       enumor.EipCloudResType,
-				IDs:           eipIds,
-				RecycleStatus: enumor.RecycleStatus,
-			})
-		if err != nil {
-			logs.Errorf("fail to mark eip recycling status, err: %v, eip ids: %v, rid: %s", err, eipIds, kt.Rid)
-			return err
-		}
-	}
-	return nil
-}
-
 func (svc *cvmSvc) detachDiskByCvmIDs(kt *kit.Kit, ids []string, basicInfoMap map[string]types.CloudResourceBasicInfo) (
 	*core.BatchOperateAllResult, error) {
 
@@ -0,0 +1,159 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cvm
+
+import (
+	actioncvm "hcm/cmd/task-server/logics/action/cvm"
+	proto "hcm/pkg/api/cloud-server/cvm"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	hcprotocvm "hcm/pkg/api/hc-service/cvm"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// ResizeCvm resize cvm.
+func (svc *cvmSvc) ResizeCvm(cts *rest.Contexts) (interface{}, error) {
+	return svc.resizeCvmSvc(cts, handler.ResOperateAuth)
+}
+
+// ResizeBizCvm resize biz cvm.
+func (svc *cvmSvc) ResizeBizCvm(cts *rest.Contexts) (interface{}, error) {
+	return svc.resizeCvmSvc(cts, handler.BizOperateAuth)
+}
+
+func (svc *cvmSvc) resizeCvmSvc(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (interface{}, error) {
+	req, _, err := svc.decodeAndAuthResizeCvmReq(cts, validHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	baseCvm, err := svc.cvmLgc.ResizePreCheck(cts.Kit, req.ID, req.InstanceType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.audit.ResBaseOperationAudit(cts.Kit, enumor.CvmAuditResType, protoaudit.Resize,
+		[]string{req.ID}); err != nil {
+		logs.Errorf("create operation audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	tasks, err := actioncvm.BuildResizeCvmTasks(baseCvm, req.InstanceType)
+	if err != nil {
+		return nil, err
+	}
+	addReq := &ts.AddCustomFlowReq{
+		Name:  enumor.FlowResizeCvm,
+		Tasks: tasks,
+	}
+	result, err := svc.client.TaskServer().CreateCustomFlow(cts.Kit, addReq)
+	if err != nil {
+		logs.Errorf("call taskserver to create custom flow failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	// 调整规格需要关机、变配、开机，耗时较长，不等待Flow结束，由调用方通过Flow查询执行进度
+	return result, nil
+}
+
+// InquiryPriceResizeCvm inquiry price of resize cvm.
+func (svc *cvmSvc) InquiryPriceResizeCvm(cts *rest.Contexts) (interface{}, error) {
+	return svc.inquiryPriceResizeCvmSvc(cts, handler.ResOperateAuth)
+}
+
+// InquiryPriceResizeBizCvm inquiry price of resize biz cvm.
+func (svc *cvmSvc) InquiryPriceResizeBizCvm(cts *rest.Contexts) (interface{}, error) {
+	return svc.inquiryPriceResizeCvmSvc(cts, handler.BizOperateAuth)
+}
+
+func (svc *cvmSvc) inquiryPriceResizeCvmSvc(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req, info, err := svc.decodeAndAuthResizeCvmReq(cts, validHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	hcReq := &hcprotocvm.ResizeCvmReq{ID: req.ID, InstanceType: req.InstanceType}
+	switch info.Vendor {
+	case enumor.TCloud:
+		result, err := svc.client.HCService().TCloud.Cvm.InquiryResizePrice(cts.Kit, hcReq)
+		if err != nil {
+			logs.Errorf("inquiry price of resize tcloud cvm failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+		return result, nil
+	case enumor.HuaWei:
+		result, err := svc.client.HCService().HuaWei.Cvm.InquiryResizePrice(cts.Kit, hcReq)
+		if err != nil {
+			logs.Errorf("inquiry price of resize huawei cvm failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+		return result, nil
+	default:
+		// aws、azure、gcp 云上没有调整规格的询价接口
+		return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support inquiry price of resize cvm, "+
+			"only tcloud and huawei are supported", info.Vendor)
+	}
+}
+
+func (svc *cvmSvc) decodeAndAuthResizeCvmReq(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	*proto.ResizeCvmReq, *types.CloudResourceBasicInfo, error) {
+
+	req := new(proto.ResizeCvmReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	basicInfoReq := dataproto.ListResourceBasicInfoReq{
+		ResourceType: enumor.CvmCloudResType,
+		IDs:          []string{req.ID},
+		Fields:       append(types.CommonBasicInfoFields, "region", "recycle_status"),
+	}
+	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, exist := basicInfoMap[req.ID]
+	if !exist {
+		return nil, nil, errf.Newf(errf.RecordNotFound, "cvm %s not found", req.ID)
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Cvm,
+		Action: meta.Update, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return req, &info, nil
+}
@@ -0,0 +1,223 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cvm
+
+import (
+	"hcm/cmd/cloud-server/logics/cvm"
+	actioncvm "hcm/cmd/task-server/logics/action/cvm"
+	proto "hcm/pkg/api/cloud-server/cvm"
+	"hcm/pkg/api/core"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// rollingOperation 滚动操作对应的主机操作
+type rollingOperation struct {
+	actionName enumor.ActionName
+	flowName   enumor.FlowName
+	authAction meta.Action
+	// auditAction 删除操作使用删除审计，无需指定
+	auditAction protoaudit.OperationAction
+	// expectStatus 操作完成后主机期望的生命周期状态
+	expectStatus enumor.CvmLifecycleStatus
+	// supportLbGate 关机、删除后的主机不再提供服务，不支持负载均衡后端健康检查
+	supportLbGate bool
+}
+
+var rollingOperations = map[string]rollingOperation{
+	"start": {
+		actionName:    enumor.ActionStartCvm,
+		flowName:      enumor.FlowRollingStartCvm,
+		authAction:    meta.Start,
+		auditAction:   protoaudit.Start,
+		expectStatus:  enumor.CvmLifecycleRunning,
+		supportLbGate: true,
+	},
+	"stop": {
+		actionName:   enumor.ActionStopCvm,
+		flowName:     enumor.FlowRollingStopCvm,
+		authAction:   meta.Stop,
+		auditAction:  protoaudit.Stop,
+		expectStatus: enumor.CvmLifecycleStopped,
+	},
+	"reboot": {
+		actionName:    enumor.ActionRebootCvm,
+		flowName:      enumor.FlowRollingRebootCvm,
+		authAction:    meta.Reboot,
+		auditAction:   protoaudit.Reboot,
+		expectStatus:  enumor.CvmLifecycleRunning,
+		supportLbGate: true,
+	},
+	"delete": {
+		actionName:   enumor.ActionDeleteCvm,
+		flowName:     enumor.FlowRollingDeleteCvm,
+		authAction:   meta.Delete,
+		expectStatus: enumor.CvmLifecycleTerminated,
+	},
+}
+
+// RollingOperateCvm rolling operate cvm.
+func (svc *cvmSvc) RollingOperateCvm(cts *rest.Contexts) (interface{}, error) {
+	return svc.rollingOperateCvmSvc(cts, handler.ResOperateAuth)
+}
+
+// RollingOperateBizCvm rolling operate biz cvm.
+func (svc *cvmSvc) RollingOperateBizCvm(cts *rest.Contexts) (interface{}, error) {
+	return svc.rollingOperateCvmSvc(cts, handler.BizOperateAuth)
+}
+
+func (svc *cvmSvc) rollingOperateCvmSvc(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	operation := cts.PathParameter("operation").String()
+	op, exist := rollingOperations[operation]
+	if !exist {
+		return nil, errf.Newf(errf.InvalidParameter, "rolling operation: %s not support", operation)
+	}
+
+	req := new(proto.RollingOperateCvmReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if req.Gate.Type == enumor.RollingGateLbTargetHealth && !op.supportLbGate {
+		return nil, errf.Newf(errf.InvalidParameter, "rolling %s not support %s gate", operation, req.Gate.Type)
+	}
+
+	basicInfoReq := dataproto.ListResourceBasicInfoReq{
+		ResourceType: enumor.CvmCloudResType,
+		IDs:          req.IDs,
+		Fields:       append(types.CommonBasicInfoFields, "region", "recycle_status"),
+	}
+	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Cvm,
+		Action: op.authAction, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, exist = cvm.OperationAllowedLifecycleStatus[op.actionName]; exist {
+		if err = svc.cvmLgc.CheckLifecycleStatus(cts.Kit, op.actionName, req.IDs); err != nil {
+			return nil, err
+		}
+	}
+
+	gate := actioncvm.RollingGate{Type: req.Gate.Type, ExpectStatus: op.expectStatus}
+	if req.Gate.Type == enumor.RollingGateLbTargetHealth {
+		if err = svc.validateRollingLbGate(cts, validHandler, req.Gate.TargetGroupID); err != nil {
+			return nil, err
+		}
+		gate.TargetGroupID = req.Gate.TargetGroupID
+	}
+
+	if op.actionName == enumor.ActionDeleteCvm {
+		err = svc.audit.ResDeleteAudit(cts.Kit, enumor.CvmAuditResType, req.IDs)
+	} else {
+		err = svc.audit.ResBaseOperationAudit(cts.Kit, enumor.CvmAuditResType, op.auditAction, req.IDs)
+	}
+	if err != nil {
+		logs.Errorf("create operation audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	tasks, err := actioncvm.BuildRollingTasks(&actioncvm.RollingTaskOption{
+		ActionName:   op.actionName,
+		IDs:          req.IDs,
+		BasicInfoMap: basicInfoMap,
+		BatchSize:    req.BatchSize,
+		Gate: actioncvm.RollingCvmGateOption{
+			PauseSec:         req.PauseSec,
+			CheckTimeoutSec:  req.Gate.CheckTimeoutSec,
+			FailureThreshold: req.FailureThreshold,
+			Gate:             gate,
+		},
+	})
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	addReq := &ts.AddCustomFlowReq{
+		Name:  op.flowName,
+		Tasks: tasks,
+	}
+	result, err := svc.client.TaskServer().CreateCustomFlow(cts.Kit, addReq)
+	if err != nil {
+		logs.Errorf("call taskserver to create custom flow failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	// 滚动操作按批次暂停、检查，耗时较长，不等待Flow结束，由调用方通过Flow查询执行进度
+	return result, nil
+}
+
+// validateRollingLbGate 校验目标组的权限、云厂商及绑定关系，目标组绑定的监听器、规则在每次健康检查时由任务查询
+func (svc *cvmSvc) validateRollingLbGate(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler,
+	tgID string) error {
+
+	basicInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit,
+		enumor.TargetGroupCloudResType, tgID)
+	if err != nil {
+		return err
+	}
+
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.TargetGroup,
+		Action: meta.Find, BasicInfo: basicInfo})
+	if err != nil {
+		return err
+	}
+
+	if basicInfo.Vendor != enumor.TCloud {
+		return errf.Newf(errf.InvalidParameter, "target group: %s vendor: %s not support lb_target_health gate",
+			tgID, basicInfo.Vendor)
+	}
+
+	relReq := &core.ListReq{
+		Filter: tools.EqualExpression("target_group_id", tgID),
+		Page:   core.NewCountPage(),
+	}
+	relResp, err := svc.client.DataService().Global.LoadBalancer.ListTargetGroupListenerRel(cts.Kit, relReq)
+	if err != nil {
+		logs.Errorf("list target group listener rel failed, err: %v, tgID: %s, rid: %s", err, tgID, cts.Kit.Rid)
+		return err
+	}
+	if relResp.Count == 0 {
+		return errf.Newf(errf.InvalidParameter, "target group: %s has not bound listener", tgID)
+	}
+
+	return nil
+}
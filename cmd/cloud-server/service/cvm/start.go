@@ -78,6 +78,10 @@ func (svc *cvmSvc) batchStartCvmSvc(cts *rest.Contexts, validHandler handler.Val
 		return nil, err
 	}
 
+	if err = svc.cvmLgc.CheckLifecycleStatus(cts.Kit, enumor.ActionStartCvm, req.IDs); err != nil {
+		return nil, err
+	}
+
 	if err = svc.audit.ResBaseOperationAudit(cts.Kit, enumor.CvmAuditResType, protoaudit.Start, req.IDs); err != nil {
 		logs.Errorf("create operation audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
 		return nil, err
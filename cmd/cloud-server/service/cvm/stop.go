@@ -73,6 +73,10 @@ func (svc *cvmSvc) batchStopCvmSvc(cts *rest.Contexts, validHandler handler.Vali
 		return nil, err
 	}
 
+	if err = svc.cvmLgc.CheckLifecycleStatus(cts.Kit, enumor.ActionStopCvm, req.IDs); err != nil {
+		return nil, err
+	}
+
 	if err = svc.audit.ResBaseOperationAudit(cts.Kit, enumor.CvmAuditResType, protoaudit.Stop, req.IDs); err != nil {
 		logs.Errorf("create operation audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
 		return nil, err
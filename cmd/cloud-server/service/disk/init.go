@@ -27,10 +27,11 @@ import (
 // InitDiskService initialize the disk service.
 func InitDiskService(c *capability.Capability) {
 	svc := &diskSvc{
-		client:     c.ApiClient,
-		authorizer: c.Authorizer,
-		audit:      c.Audit,
-		diskLgc:    c.Logics.Disk,
+		client:      c.ApiClient,
+		authorizer:  c.Authorizer,
+		audit:       c.Audit,
+		diskLgc:     c.Logics.Disk,
+		snapshotLgc: c.Logics.DiskSnapshot,
 	}
 
 	h := rest.NewHandler()
@@ -45,6 +46,7 @@ func InitDiskService(c *capability.Capability) {
 	h.Add("DeleteDisk", http.MethodDelete, "/disks/{id}", svc.DeleteDisk)
 	h.Add("CreateDisk", http.MethodPost, "/disks/create", svc.CreateDisk)
 	h.Add("InquiryPriceDisk", http.MethodPost, "/disks/prices/inquiry", svc.InquiryPriceDisk)
+	h.Add("ResizeDisk", http.MethodPost, "/disks/resize", svc.ResizeDisk)
 
 	h.Add("ListDiskExtByCvmID", http.MethodGet, "/vendors/{vendor}/disks/cvms/{cvm_id}", svc.ListDiskExtByCvmID)
 	h.Add("ListRelWithCvm", http.MethodPost, "/disk_cvm_rels/with/cvms/list", svc.ListRelWithCvm)
@@ -63,6 +65,7 @@ func InitDiskService(c *capability.Capability) {
 	h.Add("DeleteBizDisk", http.MethodDelete, "/bizs/{bk_biz_id}/disks/{id}", svc.DeleteBizDisk)
 	h.Add("AttachBizDisk", http.MethodPost, "/bizs/{bk_biz_id}/disks/attach", svc.AttachBizDisk)
 	h.Add("DetachBizDisk", http.MethodPost, "/bizs/{bk_biz_id}/disks/detach", svc.DetachBizDisk)
+	h.Add("ResizeBizDisk", http.MethodPost, "/bizs/{bk_biz_id}/disks/resize", svc.ResizeBizDisk)
 
 	// recycle operation in res
 	h.Add("RecycleDisk", http.MethodPost, "/disks/recycle", svc.RecycleDisk)
@@ -77,5 +80,33 @@ func InitDiskService(c *capability.Capability) {
 	h.Add("BatchDeleteBizRecycledDisk", http.MethodDelete, "/bizs/{bk_biz_id}/recycled/disks/batch",
 		svc.BatchDeleteBizRecycledDisk)
 
+	// disk snapshot apis in res
+	h.Add("ListDiskSnapshot", http.MethodPost, "/disk_snapshots/list", svc.ListDiskSnapshot)
+	h.Add("CreateDiskSnapshot", http.MethodPost, "/disk_snapshots/create", svc.CreateDiskSnapshot)
+	h.Add("DeleteDiskSnapshot", http.MethodDelete, "/disk_snapshots/batch", svc.DeleteDiskSnapshot)
+	h.Add("RollbackDiskSnapshot", http.MethodPost, "/disk_snapshots/rollback", svc.RollbackDiskSnapshot)
+	h.Add("SyncDiskSnapshot", http.MethodPost, "/disk_snapshots/sync", svc.SyncDiskSnapshot)
+
+	// disk snapshot apis in biz
+	h.Add("ListBizDiskSnapshot", http.MethodPost, "/bizs/{bk_biz_id}/disk_snapshots/list", svc.ListBizDiskSnapshot)
+	h.Add("CreateBizDiskSnapshot", http.MethodPost, "/bizs/{bk_biz_id}/disk_snapshots/create",
+		svc.CreateBizDiskSnapshot)
+	h.Add("DeleteBizDiskSnapshot", http.MethodDelete, "/bizs/{bk_biz_id}/disk_snapshots/batch",
+		svc.DeleteBizDiskSnapshot)
+	h.Add("RollbackBizDiskSnapshot", http.MethodPost, "/bizs/{bk_biz_id}/disk_snapshots/rollback",
+		svc.RollbackBizDiskSnapshot)
+
+	// disk snapshot policy apis in biz
+	h.Add("ListBizDiskSnapshotPolicy", http.MethodPost, "/bizs/{bk_biz_id}/disk_snapshot_policies/list",
+		svc.ListBizDiskSnapshotPolicy)
+	h.Add("CreateBizDiskSnapshotPolicy", http.MethodPost, "/bizs/{bk_biz_id}/disk_snapshot_policies/create",
+		svc.CreateBizDiskSnapshotPolicy)
+	h.Add("UpdateBizDiskSnapshotPolicy", http.MethodPatch, "/bizs/{bk_biz_id}/disk_snapshot_policies/{id}",
+		svc.UpdateBizDiskSnapshotPolicy)
+	h.Add("DeleteBizDiskSnapshotPolicy", http.MethodDelete, "/bizs/{bk_biz_id}/disk_snapshot_policies/batch",
+		svc.DeleteBizDiskSnapshotPolicy)
+	h.Add("ExecBizDiskSnapshotPolicy", http.MethodPost, "/bizs/{bk_biz_id}/disk_snapshot_policies/{id}/execute",
+		svc.ExecBizDiskSnapshotPolicy)
+
 	h.Load(c.WebService)
 }
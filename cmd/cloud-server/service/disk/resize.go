@@ -0,0 +1,108 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disk
+
+import (
+	"hcm/cmd/cloud-server/logics/async"
+	actiondisk "hcm/cmd/task-server/logics/action/disk"
+	csdisk "hcm/pkg/api/cloud-server/disk"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// ResizeDisk resize disk.
+func (svc *diskSvc) ResizeDisk(cts *rest.Contexts) (interface{}, error) {
+	return svc.resizeDisk(cts, handler.ResOperateAuth)
+}
+
+// ResizeBizDisk resize biz disk.
+func (svc *diskSvc) ResizeBizDisk(cts *rest.Contexts) (interface{}, error) {
+	return svc.resizeDisk(cts, handler.BizOperateAuth)
+}
+
+func (svc *diskSvc) resizeDisk(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (interface{}, error) {
+	req := new(csdisk.ResizeDiskReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	basicInfoReq := dataproto.ListResourceBasicInfoReq{
+		ResourceType: enumor.DiskCloudResType,
+		IDs:          []string{req.ID},
+		Fields:       append(types.CommonBasicInfoFields, "region", "recycle_status"),
+	}
+	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Disk,
+		Action: meta.Update, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, err
+	}
+
+	disk, err := svc.diskLgc.ResizePreCheck(cts.Kit, req.ID, req.DiskSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.audit.ResBaseOperationAudit(cts.Kit, enumor.DiskAuditResType, protoaudit.Resize,
+		[]string{req.ID}); err != nil {
+		logs.Errorf("create operation audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	cvm, err := svc.diskLgc.GetAttachedCvm(cts.Kit, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := actiondisk.BuildResizeDiskTasks(disk, req.DiskSize, cvm)
+	if err != nil {
+		logs.Errorf("build resize disk tasks failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	addReq := &ts.AddCustomFlowReq{
+		Name:  enumor.FlowResizeDisk,
+		Tasks: tasks,
+	}
+	result, err := svc.client.TaskServer().CreateCustomFlow(cts.Kit, addReq)
+	if err != nil {
+		logs.Errorf("call taskserver to create custom flow failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, async.WaitTaskToEnd(cts.Kit, svc.client.TaskServer(), result.ID)
+}
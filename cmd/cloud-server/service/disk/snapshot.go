@@ -0,0 +1,421 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disk
+
+import (
+	actiondisksnapshot "hcm/cmd/task-server/logics/action/disk-snapshot"
+	csdisk "hcm/pkg/api/cloud-server/disk"
+	"hcm/pkg/api/core"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	datadisk "hcm/pkg/api/data-service/cloud/disk"
+	hcdisk "hcm/pkg/api/hc-service/disk"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/counter"
+	"hcm/pkg/tools/hooks/handler"
+	"hcm/pkg/tools/slice"
+)
+
+// ListDiskSnapshot list disk snapshot.
+func (svc *diskSvc) ListDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.listDiskSnapshot(cts, handler.ListResourceAuthRes)
+}
+
+// ListBizDiskSnapshot list biz disk snapshot.
+func (svc *diskSvc) ListBizDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.listDiskSnapshot(cts, handler.ListBizAuthRes)
+}
+
+func (svc *diskSvc) listDiskSnapshot(cts *rest.Contexts, authHandler handler.ListAuthResHandler) (
+	interface{}, error) {
+
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 快照复用云硬盘的查看权限
+	expr, noPermFlag, err := authHandler(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.Disk, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &datadisk.SnapshotListResult{Count: 0, Details: make([]*coredisk.Snapshot, 0)}, nil
+	}
+
+	listReq := &core.ListReq{
+		Filter: expr,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	return svc.client.DataService().Global.DiskSnapshot.List(cts.Kit, listReq)
+}
+
+// CreateDiskSnapshot create disk snapshot.
+func (svc *diskSvc) CreateDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.createDiskSnapshot(cts, handler.ResOperateAuth)
+}
+
+// CreateBizDiskSnapshot create biz disk snapshot.
+func (svc *diskSvc) CreateBizDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.createDiskSnapshot(cts, handler.BizOperateAuth)
+}
+
+func (svc *diskSvc) createDiskSnapshot(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(csdisk.SnapshotCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	diskIDs := slice.Unique(slice.Map(req.Snapshots, func(one csdisk.SnapshotCreateInfo) string {
+		return one.DiskID
+	}))
+	diskInfos, err := svc.listSnapshotBasicInfo(cts.Kit, enumor.DiskCloudResType, diskIDs, "recycle_status")
+	if err != nil {
+		return nil, err
+	}
+
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Disk,
+		Action: meta.Update, BasicInfos: diskInfos})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validateSnapshotVendor(diskInfos, coredisk.SnapshotVendors); err != nil {
+		return nil, err
+	}
+
+	if err = svc.audit.ResBaseOperationAudit(cts.Kit, enumor.DiskAuditResType, protoaudit.CreateSnapshot,
+		diskIDs); err != nil {
+		logs.Errorf("create disk create snapshot audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	tasks := make([]ts.CustomFlowTask, 0, len(req.Snapshots))
+	nextID := counter.NewNumStringCounter(1, 10)
+	for _, one := range req.Snapshots {
+		info := diskInfos[one.DiskID]
+		tasks = append(tasks, ts.CustomFlowTask{
+			ActionID:   action.ActIDType(nextID()),
+			ActionName: enumor.ActionCreateDiskSnapshot,
+			Params: &actiondisksnapshot.CreateDiskSnapshotOption{
+				Vendor: info.Vendor,
+				SnapshotCreateReq: hcdisk.SnapshotCreateReq{
+					AccountID:    info.AccountID,
+					DiskID:       one.DiskID,
+					SnapshotName: one.SnapshotName,
+					Memo:         one.Memo,
+				},
+			},
+		})
+	}
+
+	return svc.createSnapshotFlow(cts.Kit, enumor.FlowCreateDiskSnapshot, tasks)
+}
+
+// DeleteDiskSnapshot delete disk snapshot.
+func (svc *diskSvc) DeleteDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.deleteDiskSnapshot(cts, handler.ResOperateAuth)
+}
+
+// DeleteBizDiskSnapshot delete biz disk snapshot.
+func (svc *diskSvc) DeleteBizDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.deleteDiskSnapshot(cts, handler.BizOperateAuth)
+}
+
+func (svc *diskSvc) deleteDiskSnapshot(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(csdisk.SnapshotDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	ids := slice.Unique(req.IDs)
+	infos, err := svc.listSnapshotBasicInfo(cts.Kit, enumor.DiskSnapshotCloudResType, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Disk,
+		Action: meta.Update, BasicInfos: infos})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := svc.listSnapshot(cts.Kit, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// 快照的操作记录在源云硬盘的审计中，源云硬盘未同步到hcm的快照不记录审计
+	diskIDs := make([]string, 0, len(snapshots))
+	for _, one := range snapshots {
+		if len(one.DiskID) != 0 {
+			diskIDs = append(diskIDs, one.DiskID)
+		}
+	}
+	if len(diskIDs) != 0 {
+		if err = svc.audit.ResBaseOperationAudit(cts.Kit, enumor.DiskAuditResType, protoaudit.DeleteSnapshot,
+			slice.Unique(diskIDs)); err != nil {
+			logs.Errorf("create disk delete snapshot audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	accountIDs := make(map[string][]string)
+	vendors := make(map[string]enumor.Vendor)
+	for _, id := range ids {
+		info := infos[id]
+		accountIDs[info.AccountID] = append(accountIDs[info.AccountID], id)
+		vendors[info.AccountID] = info.Vendor
+	}
+
+	tasks := make([]ts.CustomFlowTask, 0, len(accountIDs))
+	nextID := counter.NewNumStringCounter(1, 10)
+	for accountID, snapshotIDs := range accountIDs {
+		tasks = append(tasks, ts.CustomFlowTask{
+			ActionID:   action.ActIDType(nextID()),
+			ActionName: enumor.ActionDeleteDiskSnapshot,
+			Params: &actiondisksnapshot.DeleteDiskSnapshotOption{
+				Vendor: vendors[accountID],
+				SnapshotDeleteReq: hcdisk.SnapshotDeleteReq{
+					AccountID: accountID,
+					IDs:       snapshotIDs,
+				},
+			},
+		})
+	}
+
+	return svc.createSnapshotFlow(cts.Kit, enumor.FlowDeleteDiskSnapshot, tasks)
+}
+
+// RollbackDiskSnapshot rollback disk snapshot to its source disk.
+func (svc *diskSvc) RollbackDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.rollbackDiskSnapshot(cts, handler.ResOperateAuth)
+}
+
+// RollbackBizDiskSnapshot rollback biz disk snapshot to its source disk.
+func (svc *diskSvc) RollbackBizDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.rollbackDiskSnapshot(cts, handler.BizOperateAuth)
+}
+
+func (svc *diskSvc) rollbackDiskSnapshot(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(csdisk.SnapshotRollbackReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	infos, err := svc.listSnapshotBasicInfo(cts.Kit, enumor.DiskSnapshotCloudResType, []string{req.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Disk,
+		Action: meta.Update, BasicInfos: infos})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validateSnapshotVendor(infos, coredisk.SnapshotRollbackVendors); err != nil {
+		return nil, err
+	}
+
+	snapshots, err := svc.listSnapshot(cts.Kit, []string{req.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := snapshots[0]
+	if len(snapshot.DiskID) == 0 {
+		return nil, errf.Newf(errf.InvalidParameter, "source disk of snapshot %s is not managed by hcm", req.ID)
+	}
+
+	if err = svc.audit.ResBaseOperationAudit(cts.Kit, enumor.DiskAuditResType, protoaudit.RollbackSnapshot,
+		[]string{snapshot.DiskID}); err != nil {
+		logs.Errorf("create disk rollback snapshot audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	rollbackReq := &hcdisk.SnapshotRollbackReq{AccountID: snapshot.AccountID, ID: snapshot.ID}
+	switch snapshot.Vendor {
+	case enumor.TCloud:
+		err = svc.client.HCService().TCloud.DiskSnapshot.Rollback(cts.Kit, rollbackReq)
+	case enumor.Aws:
+		err = svc.client.HCService().Aws.DiskSnapshot.Rollback(cts.Kit, rollbackReq)
+	case enumor.HuaWei:
+		err = svc.client.HCService().HuaWei.DiskSnapshot.Rollback(cts.Kit, rollbackReq)
+	}
+	if err != nil {
+		logs.Errorf("rollback %s disk snapshot failed, err: %v, id: %s, rid: %s", snapshot.Vendor, err, req.ID,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// SyncDiskSnapshot sync disk snapshots of account region.
+func (svc *diskSvc) SyncDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(csdisk.SnapshotSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Account, Action: meta.Find,
+		ResourceID: req.AccountID}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	info, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit, enumor.AccountCloudResType,
+		req.AccountID)
+	if err != nil {
+		logs.Errorf("get account basic info failed, err: %v, id: %s, rid: %s", err, req.AccountID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	syncReq := &hcdisk.SnapshotSyncReq{AccountID: req.AccountID, Region: req.Region}
+	switch info.Vendor {
+	case enumor.TCloud:
+		err = svc.client.HCService().TCloud.DiskSnapshot.Sync(cts.Kit, syncReq)
+	case enumor.Aws:
+		err = svc.client.HCService().Aws.DiskSnapshot.Sync(cts.Kit, syncReq)
+	case enumor.HuaWei:
+		err = svc.client.HCService().HuaWei.DiskSnapshot.Sync(cts.Kit, syncReq)
+	case enumor.Azure:
+		err = svc.client.HCService().Azure.DiskSnapshot.Sync(cts.Kit, syncReq)
+	case enumor.Gcp:
+		err = svc.client.HCService().Gcp.DiskSnapshot.Sync(cts.Kit, syncReq)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "%s does not support disk snapshot", info.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("sync %s disk snapshot failed, err: %v, req: %+v, rid: %s", info.Vendor, err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// listSnapshotBasicInfo 查询资源基础信息，并校验资源均存在
+func (svc *diskSvc) listSnapshotBasicInfo(kt *kit.Kit, resType enumor.CloudResourceType, ids []string,
+	fields ...string) (map[string]types.CloudResourceBasicInfo, error) {
+
+	basicInfoReq := dataproto.ListResourceBasicInfoReq{
+		ResourceType: resType,
+		IDs:          ids,
+		Fields:       append(fields, types.CommonBasicInfoFields...),
+	}
+	infos, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(kt, basicInfoReq)
+	if err != nil {
+		logs.Errorf("list %s basic info failed, err: %v, ids: %v, rid: %s", resType, err, ids, kt.Rid)
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if _, exists := infos[id]; !exists {
+			return nil, errf.Newf(errf.RecordNotFound, "%s %s not found", resType, id)
+		}
+	}
+
+	return infos, nil
+}
+
+func (svc *diskSvc) listSnapshot(kt *kit.Kit, ids []string) ([]*coredisk.Snapshot, error) {
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", ids),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.client.DataService().Global.DiskSnapshot.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list disk snapshot failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) != len(ids) {
+		return nil, errf.Newf(errf.RecordNotFound, "some disk snapshots of %v are not found", ids)
+	}
+
+	return result.Details, nil
+}
+
+// createSnapshotFlow 快照的创建、删除耗时较长，异步执行，直接返回任务流ID
+func (svc *diskSvc) createSnapshotFlow(kt *kit.Kit, flowName enumor.FlowName, tasks []ts.CustomFlowTask) (
+	interface{}, error) {
+
+	flowReq := &ts.AddCustomFlowReq{
+		Name:  flowName,
+		Tasks: tasks,
+	}
+	result, err := svc.client.TaskServer().CreateCustomFlow(kt, flowReq)
+	if err != nil {
+		logs.Errorf("call taskserver to create %s flow failed, err: %v, rid: %s", flowName, err, kt.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func validateSnapshotVendor(infos map[string]types.CloudResourceBasicInfo, vendors []enumor.Vendor) error {
+	for _, info := range infos {
+		if !slice.IsItemInSlice(vendors, info.Vendor) {
+			return errf.Newf(errf.InvalidParameter, "%s(id=%s) of vendor %s does not support the snapshot operation",
+				info.ResType, info.ID, info.Vendor)
+		}
+	}
+	return nil
+}
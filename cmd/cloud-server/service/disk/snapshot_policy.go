@@ -0,0 +1,236 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disk
+
+import (
+	csdisk "hcm/pkg/api/cloud-server/disk"
+	"hcm/pkg/api/core"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	dataservice "hcm/pkg/api/data-service"
+	datadisk "hcm/pkg/api/data-service/cloud/disk"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+)
+
+// ListBizDiskSnapshotPolicy list biz disk snapshot policy.
+func (svc *diskSvc) ListBizDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	expr, noPermFlag, err := handler.ListBizAuthRes(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.Disk, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &datadisk.SnapshotPolicyListResult{Count: 0, Details: make([]*coredisk.SnapshotPolicy, 0)}, nil
+	}
+
+	listReq := &core.ListReq{
+		Filter: expr,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	return svc.client.DataService().Global.DiskSnapshot.ListPolicy(cts.Kit, listReq)
+}
+
+// CreateBizDiskSnapshotPolicy create biz disk snapshot policy.
+func (svc *diskSvc) CreateBizDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(csdisk.SnapshotPolicyCreateReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	diskIDs := slice.Unique(req.DiskIDs)
+	if err = svc.validatePolicyDisks(cts, diskIDs); err != nil {
+		return nil, err
+	}
+
+	createReq := &datadisk.SnapshotPolicyCreateReq{
+		Name:           req.Name,
+		BkBizID:        bizID,
+		Cron:           req.Cron,
+		RetentionCount: req.RetentionCount,
+		DiskIDs:        diskIDs,
+		Enabled:        req.Enabled,
+		Memo:           req.Memo,
+	}
+	return svc.client.DataService().Global.DiskSnapshot.CreatePolicy(cts.Kit, createReq)
+}
+
+// UpdateBizDiskSnapshotPolicy update biz disk snapshot policy.
+func (svc *diskSvc) UpdateBizDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(csdisk.SnapshotPolicyUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if _, err := svc.getBizSnapshotPolicy(cts, id, meta.Update); err != nil {
+		return nil, err
+	}
+
+	updateReq := &datadisk.SnapshotPolicyUpdateReq{
+		Name:           req.Name,
+		Cron:           req.Cron,
+		RetentionCount: req.RetentionCount,
+		Enabled:        req.Enabled,
+		Memo:           req.Memo,
+	}
+	if req.DiskIDs != nil {
+		diskIDs := slice.Unique(*req.DiskIDs)
+		if err := svc.validatePolicyDisks(cts, diskIDs); err != nil {
+			return nil, err
+		}
+		updateReq.DiskIDs = &diskIDs
+	}
+
+	if err := svc.client.DataService().Global.DiskSnapshot.UpdatePolicy(cts.Kit, id, updateReq); err != nil {
+		logs.Errorf("update disk snapshot policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// DeleteBizDiskSnapshotPolicy delete biz disk snapshot policies, the snapshots created by them are retained.
+func (svc *diskSvc) DeleteBizDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(csdisk.SnapshotPolicyDeleteReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Disk, Action: meta.Update}, BizID: bizID}
+	if err = svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	delReq := &dataservice.BatchDeleteReq{
+		Filter: tools.ExpressionAnd(tools.RuleIn("id", req.IDs), tools.RuleEqual("bk_biz_id", bizID)),
+	}
+	if err = svc.client.DataService().Global.DiskSnapshot.BatchDeletePolicy(cts.Kit, delReq); err != nil {
+		logs.Errorf("delete disk snapshot policy failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ExecBizDiskSnapshotPolicy execute biz disk snapshot policy immediately.
+func (svc *diskSvc) ExecBizDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	policy, err := svc.getBizSnapshotPolicy(cts, id, meta.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.snapshotLgc.ExecPolicy(cts.Kit, policy, times.ConvStdTimeNow())
+}
+
+// getBizSnapshotPolicy 查询业务下的定期快照策略并鉴权
+func (svc *diskSvc) getBizSnapshotPolicy(cts *rest.Contexts, id string, action meta.Action) (
+	*coredisk.SnapshotPolicy, error) {
+
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Disk, Action: action}, BizID: bizID}
+	if err = svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("id", id), tools.RuleEqual("bk_biz_id", bizID)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.client.DataService().Global.DiskSnapshot.ListPolicy(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list disk snapshot policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "disk snapshot policy %s not found in biz %d", id, bizID)
+	}
+
+	return result.Details[0], nil
+}
+
+// validatePolicyDisks 校验策略的云硬盘均属于当前业务，且云厂商支持快照
+func (svc *diskSvc) validatePolicyDisks(cts *rest.Contexts, diskIDs []string) error {
+	infos, err := svc.listSnapshotBasicInfo(cts.Kit, enumor.DiskCloudResType, diskIDs, "recycle_status")
+	if err != nil {
+		return err
+	}
+
+	err = handler.BizOperateAuth(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Disk,
+		Action: meta.Update, BasicInfos: infos})
+	if err != nil {
+		return err
+	}
+
+	return validateSnapshotVendor(infos, coredisk.SnapshotVendors)
+}
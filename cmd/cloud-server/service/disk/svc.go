@@ -27,6 +27,7 @@ import (
 
 	"hcm/cmd/cloud-server/logics/audit"
 	disklgc "hcm/cmd/cloud-server/logics/disk"
+	disksnapshot "hcm/cmd/cloud-server/logics/disk-snapshot"
 	cloudproto "hcm/pkg/api/cloud-server/disk"
 	"hcm/pkg/api/core"
 	"hcm/pkg/api/data-service/cloud"
@@ -46,10 +47,11 @@ import (
 )
 
 type diskSvc struct {
-	client     *client.ClientSet
-	authorizer auth.Authorizer
-	audit      audit.Interface
-	diskLgc    disklgc.Interface
+	client      *client.ClientSet
+	authorizer  auth.Authorizer
+	audit       audit.Interface
+	diskLgc     disklgc.Interface
+	snapshotLgc disksnapshot.Interface
 }
 
 // ListDisk list disk.
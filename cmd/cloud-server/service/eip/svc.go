@@ -43,6 +43,7 @@ import (
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/iam/auth"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/kit"
@@ -255,6 +256,7 @@ func (svc *eipSvc) batchDeleteEip(cts *rest.Contexts, validHandler handler.Valid
 	basicInfoReq := cloud.ListResourceBasicInfoReq{
 		ResourceType: enumor.EipCloudResType,
 		IDs:          req.IDs,
+		Fields:       types.ResWithRecycleBasicFields,
 	}
 	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
 	if err != nil {
@@ -369,12 +371,17 @@ func (svc *eipSvc) authorizeEipAssignOp(kt *kit.Kit, ids []string) error {
 	basicInfoReq := cloud.ListResourceBasicInfoReq{
 		ResourceType: enumor.EipCloudResType,
 		IDs:          ids,
+		Fields:       types.ResWithRecycleBasicFields,
 	}
 	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(kt, basicInfoReq)
 	if err != nil {
 		return err
 	}
 
+	if err = handler.ValidateNotRecycled(basicInfoMap); err != nil {
+		return err
+	}
+
 	authRes := make([]meta.ResourceAttribute, 0, len(basicInfoMap))
 	for _, info := range basicInfoMap {
 		authRes = append(authRes, meta.ResourceAttribute{Basic: &meta.Basic{
@@ -22,6 +22,7 @@ package image
 import (
 	"net/http"
 
+	"hcm/cmd/cloud-server/logics/audit"
 	"hcm/cmd/cloud-server/service/capability"
 	"hcm/pkg/client"
 	"hcm/pkg/iam/auth"
@@ -33,6 +34,7 @@ func InitImageService(c *capability.Capability) {
 	svc := &imageSvc{
 		client:     c.ApiClient,
 		authorizer: c.Authorizer,
+		audit:      c.Audit,
 	}
 
 	h := rest.NewHandler()
@@ -40,10 +42,28 @@ func InitImageService(c *capability.Capability) {
 	h.Add("GetImage", http.MethodGet, "/vendors/{vendor}/images/{id}", svc.RetrieveImage)
 	h.Add("ListImage", http.MethodPost, "/images/list", svc.ListImage)
 
+	// 资源下私有镜像相关接口
+	h.Add("ListPrivateImage", http.MethodPost, "/private_images/list", svc.ListPrivateImage)
+	h.Add("CreatePrivateImage", http.MethodPost, "/private_images/create", svc.CreatePrivateImage)
+	h.Add("CopyPrivateImage", http.MethodPost, "/private_images/copy", svc.CopyPrivateImage)
+	h.Add("SharePrivateImage", http.MethodPost, "/private_images/share", svc.SharePrivateImage)
+	h.Add("DeprecatePrivateImage", http.MethodPatch, "/private_images/deprecate", svc.DeprecatePrivateImage)
+
+	// 业务下私有镜像相关接口
+	h.Add("ListBizPrivateImage", http.MethodPost, "/bizs/{bk_biz_id}/private_images/list", svc.ListBizPrivateImage)
+	h.Add("CreateBizPrivateImage", http.MethodPost, "/bizs/{bk_biz_id}/private_images/create",
+		svc.CreateBizPrivateImage)
+	h.Add("CopyBizPrivateImage", http.MethodPost, "/bizs/{bk_biz_id}/private_images/copy", svc.CopyBizPrivateImage)
+	h.Add("ShareBizPrivateImage", http.MethodPost, "/bizs/{bk_biz_id}/private_images/share",
+		svc.ShareBizPrivateImage)
+	h.Add("DeprecateBizPrivateImage", http.MethodPatch, "/bizs/{bk_biz_id}/private_images/deprecate",
+		svc.DeprecateBizPrivateImage)
+
 	h.Load(c.WebService)
 }
 
 type imageSvc struct {
 	client     *client.ClientSet
 	authorizer auth.Authorizer
+	audit      audit.Interface
 }
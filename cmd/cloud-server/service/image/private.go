@@ -0,0 +1,496 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package image
+
+import (
+	"fmt"
+
+	actionimage "hcm/cmd/task-server/logics/action/image"
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	coreimage "hcm/pkg/api/core/cloud/image"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	dataimage "hcm/pkg/api/data-service/cloud/image"
+	hcimage "hcm/pkg/api/hc-service/image"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/counter"
+	"hcm/pkg/tools/hooks/handler"
+	"hcm/pkg/tools/maps"
+	"hcm/pkg/tools/slice"
+)
+
+// ListPrivateImage list private image.
+func (svc *imageSvc) ListPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	return svc.listPrivateImage(cts, handler.ListResourceAuthRes)
+}
+
+// ListBizPrivateImage list biz private image.
+func (svc *imageSvc) ListBizPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	return svc.listPrivateImage(cts, handler.ListBizAuthRes)
+}
+
+func (svc *imageSvc) listPrivateImage(cts *rest.Contexts, authHandler handler.ListAuthResHandler) (
+	interface{}, error) {
+
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 私有镜像基于主机创建，复用主机的查看权限
+	expr, noPermFlag, err := authHandler(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.Cvm, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &dataimage.PrivateImageListResult{Count: 0, Details: make([]*coreimage.PrivateImage, 0)}, nil
+	}
+
+	listReq := &core.ListReq{
+		Filter: expr,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	return svc.client.DataService().Global.PrivateImage.List(cts.Kit, listReq)
+}
+
+// CreatePrivateImage create private image from cvm.
+func (svc *imageSvc) CreatePrivateImage(cts *rest.Contexts) (interface{}, error) {
+	return svc.createPrivateImage(cts, handler.ResOperateAuth)
+}
+
+// CreateBizPrivateImage create biz private image from cvm.
+func (svc *imageSvc) CreateBizPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	return svc.createPrivateImage(cts, handler.BizOperateAuth)
+}
+
+func (svc *imageSvc) createPrivateImage(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(cloudserver.PrivateImageCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	cvmIDs := slice.Unique(slice.Map(req.Images, func(one cloudserver.PrivateImageCreateInfo) string {
+		return one.CvmID
+	}))
+	cvmInfos, err := svc.listBasicInfo(cts.Kit, enumor.CvmCloudResType, cvmIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 创建镜像可能导致主机关机，需要主机的编辑权限
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Cvm,
+		Action: meta.Update, BasicInfos: cvmInfos})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validatePrivateImageVendor(cvmInfos); err != nil {
+		return nil, err
+	}
+
+	if err = svc.audit.ResBaseOperationAudit(cts.Kit, enumor.CvmAuditResType, protoaudit.CreateImage,
+		cvmIDs); err != nil {
+		logs.Errorf("create cvm create image audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	tasks := make([]ts.CustomFlowTask, 0, len(req.Images))
+	nextID := counter.NewNumStringCounter(1, 10)
+	for _, one := range req.Images {
+		info := cvmInfos[one.CvmID]
+		tasks = append(tasks, ts.CustomFlowTask{
+			ActionID:   action.ActIDType(nextID()),
+			ActionName: enumor.ActionCreatePrivateImage,
+			Params: &actionimage.CreatePrivateImageOption{
+				Vendor: info.Vendor,
+				PrivateImageCreateReq: hcimage.PrivateImageCreateReq{
+					AccountID:     info.AccountID,
+					CvmID:         one.CvmID,
+					ImageName:     one.ImageName,
+					ForcePoweroff: req.ForcePoweroff,
+					Memo:          one.Memo,
+				},
+			},
+		})
+	}
+
+	return svc.createFlow(cts.Kit, enumor.FlowCreatePrivateImage, tasks)
+}
+
+// CopyPrivateImage copy private image to other regions.
+func (svc *imageSvc) CopyPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	return svc.copyPrivateImage(cts, handler.ResOperateAuth)
+}
+
+// CopyBizPrivateImage copy biz private image to other regions.
+func (svc *imageSvc) CopyBizPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	return svc.copyPrivateImage(cts, handler.BizOperateAuth)
+}
+
+func (svc *imageSvc) copyPrivateImage(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(cloudserver.PrivateImageCopyReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	info, err := svc.validatePrivateImage(cts, validHandler, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slice.IsItemInSlice(coreimage.PrivateImageCopyVendors, info.Vendor) {
+		return nil, errf.Newf(errf.InvalidParameter, "%s private image does not support copy to other regions",
+			info.Vendor)
+	}
+
+	if slice.IsItemInSlice(req.DstRegions, info.Region) {
+		return nil, errf.Newf(errf.InvalidParameter, "dst regions can not contain image region %s", info.Region)
+	}
+
+	tasks := []ts.CustomFlowTask{{
+		ActionID:   "1",
+		ActionName: enumor.ActionCopyPrivateImage,
+		Params: &actionimage.CopyPrivateImageOption{
+			Vendor: info.Vendor,
+			PrivateImageCopyReq: hcimage.PrivateImageCopyReq{
+				AccountID:  info.AccountID,
+				ID:         req.ID,
+				DstRegions: slice.Unique(req.DstRegions),
+				ImageName:  req.ImageName,
+			},
+		},
+	}}
+
+	return svc.createFlow(cts.Kit, enumor.FlowCopyPrivateImage, tasks)
+}
+
+// SharePrivateImage share private image to other accounts.
+func (svc *imageSvc) SharePrivateImage(cts *rest.Contexts) (interface{}, error) {
+	return svc.sharePrivateImage(cts, handler.ResOperateAuth)
+}
+
+// ShareBizPrivateImage share biz private image to other accounts.
+func (svc *imageSvc) ShareBizPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	return svc.sharePrivateImage(cts, handler.BizOperateAuth)
+}
+
+func (svc *imageSvc) sharePrivateImage(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(cloudserver.PrivateImageShareReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	info, err := svc.validatePrivateImage(cts, validHandler, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slice.IsItemInSlice(coreimage.PrivateImageShareVendors, info.Vendor) {
+		return nil, errf.Newf(errf.InvalidParameter, "%s private image does not support share", info.Vendor)
+	}
+
+	shareAccountIDs := slice.Unique(req.ShareAccountIDs)
+	if err = svc.validateSameRoot(cts.Kit, info.Vendor, info.AccountID, shareAccountIDs); err != nil {
+		return nil, err
+	}
+
+	shareReq := &hcimage.PrivateImageShareReq{
+		AccountID:       info.AccountID,
+		ID:              req.ID,
+		ShareAccountIDs: shareAccountIDs,
+	}
+	switch info.Vendor {
+	case enumor.TCloud:
+		err = svc.client.HCService().TCloud.PrivateImage.Share(cts.Kit, shareReq)
+	case enumor.HuaWei:
+		err = svc.client.HCService().HuaWei.PrivateImage.Share(cts.Kit, shareReq)
+	case enumor.Aws:
+		err = svc.client.HCService().Aws.PrivateImage.Share(cts.Kit, shareReq)
+	case enumor.Gcp:
+		err = svc.client.HCService().Gcp.PrivateImage.Share(cts.Kit, shareReq)
+	}
+	if err != nil {
+		logs.Errorf("share %s private image failed, err: %v, req: %+v, rid: %s", info.Vendor, err, shareReq,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// DeprecatePrivateImage deprecate or restore private image.
+func (svc *imageSvc) DeprecatePrivateImage(cts *rest.Contexts) (interface{}, error) {
+	return svc.deprecatePrivateImage(cts, handler.ResOperateAuth)
+}
+
+// DeprecateBizPrivateImage deprecate or restore biz private image.
+func (svc *imageSvc) DeprecateBizPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	return svc.deprecatePrivateImage(cts, handler.BizOperateAuth)
+}
+
+func (svc *imageSvc) deprecatePrivateImage(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(cloudserver.PrivateImageDeprecateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	ids := slice.Unique(req.IDs)
+	infos, err := svc.listBasicInfo(cts.Kit, enumor.PrivateImageCloudResType, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Cvm,
+		Action: meta.Update, BasicInfos: infos})
+	if err != nil {
+		return nil, err
+	}
+
+	updateReq := &dataimage.PrivateImageBatchUpdateReq{
+		Images: make([]dataimage.PrivateImageUpdate, 0, len(ids)),
+	}
+	for _, id := range ids {
+		updateReq.Images = append(updateReq.Images, dataimage.PrivateImageUpdate{
+			ID:         id,
+			Deprecated: req.Deprecated,
+		})
+	}
+	if err = svc.client.DataService().Global.PrivateImage.BatchUpdate(cts.Kit, updateReq); err != nil {
+		logs.Errorf("update private image deprecated failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// validatePrivateImage 查询私有镜像基础信息并鉴权，私有镜像的操作复用主机的编辑权限
+func (svc *imageSvc) validatePrivateImage(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler,
+	id string) (*types.CloudResourceBasicInfo, error) {
+
+	infos, err := svc.listBasicInfo(cts.Kit, enumor.PrivateImageCloudResType, []string{id})
+	if err != nil {
+		return nil, err
+	}
+
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Cvm,
+		Action: meta.Update, BasicInfos: infos})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validatePrivateImageVendor(infos); err != nil {
+		return nil, err
+	}
+
+	info := infos[id]
+	return &info, nil
+}
+
+// listBasicInfo 查询资源基础信息，并校验资源均存在
+func (svc *imageSvc) listBasicInfo(kt *kit.Kit, resType enumor.CloudResourceType, ids []string) (
+	map[string]types.CloudResourceBasicInfo, error) {
+
+	basicInfoReq := dataproto.ListResourceBasicInfoReq{
+		ResourceType: resType,
+		IDs:          ids,
+		Fields:       append([]string{"region"}, types.CommonBasicInfoFields...),
+	}
+	infos, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(kt, basicInfoReq)
+	if err != nil {
+		logs.Errorf("list %s basic info failed, err: %v, ids: %v, rid: %s", resType, err, ids, kt.Rid)
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if _, exists := infos[id]; !exists {
+			return nil, errf.Newf(errf.RecordNotFound, "%s %s not found", resType, id)
+		}
+	}
+
+	return infos, nil
+}
+
+// validateSameRoot 校验接收共享的账号与镜像所属账号属于同一个根账号
+func (svc *imageSvc) validateSameRoot(kt *kit.Kit, vendor enumor.Vendor, accountID string,
+	shareAccountIDs []string) error {
+
+	cloudAccountIDs := make(map[string]string, len(shareAccountIDs)+1)
+	for _, id := range append([]string{accountID}, shareAccountIDs...) {
+		cloudAccountID, err := svc.getCloudAccountID(kt, vendor, id)
+		if err != nil {
+			return err
+		}
+		cloudAccountIDs[id] = cloudAccountID
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", vendor),
+			tools.RuleIn("cloud_id", slice.Unique(maps.Values(cloudAccountIDs))),
+		),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"cloud_id", "parent_account_id"},
+	}
+	mainAccounts, err := svc.client.DataService().Global.MainAccount.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list main account failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	rootIDs := make(map[string]string, len(mainAccounts.Details))
+	for _, one := range mainAccounts.Details {
+		rootIDs[one.CloudID] = one.ParentAccountID
+	}
+
+	rootID := rootIDs[cloudAccountIDs[accountID]]
+	if len(rootID) == 0 {
+		return errf.Newf(errf.InvalidParameter, "account %s does not belong to any root account", accountID)
+	}
+
+	for _, id := range shareAccountIDs {
+		if rootIDs[cloudAccountIDs[id]] != rootID {
+			return errf.Newf(errf.InvalidParameter, "account %s does not belong to the same root account as %s",
+				id, accountID)
+		}
+	}
+
+	return nil
+}
+
+// getCloudAccountID 获取账号对应的云上账号ID，与二级账号的云ID一致，gcp为账号所属的项目ID
+func (svc *imageSvc) getCloudAccountID(kt *kit.Kit, vendor enumor.Vendor, accountID string) (string, error) {
+	switch vendor {
+	case enumor.TCloud:
+		account, err := svc.client.DataService().TCloud.Account.Get(kt.Ctx, kt.Header(), accountID)
+		if err != nil {
+			logs.Errorf("get tcloud account failed, err: %v, id: %s, rid: %s", err, accountID, kt.Rid)
+			return "", err
+		}
+		if account.Vendor != vendor || account.Extension == nil {
+			return "", errf.Newf(errf.InvalidParameter, "account %s is not %s account", accountID, vendor)
+		}
+		return account.Extension.CloudMainAccountID, nil
+
+	case enumor.HuaWei:
+		account, err := svc.client.DataService().HuaWei.Account.Get(kt.Ctx, kt.Header(), accountID)
+		if err != nil {
+			logs.Errorf("get huawei account failed, err: %v, id: %s, rid: %s", err, accountID, kt.Rid)
+			return "", err
+		}
+		if account.Vendor != vendor || account.Extension == nil {
+			return "", errf.Newf(errf.InvalidParameter, "account %s is not %s account", accountID, vendor)
+		}
+		return account.Extension.CloudSubAccountID, nil
+
+	case enumor.Aws:
+		account, err := svc.client.DataService().Aws.Account.Get(kt.Ctx, kt.Header(), accountID)
+		if err != nil {
+			logs.Errorf("get aws account failed, err: %v, id: %s, rid: %s", err, accountID, kt.Rid)
+			return "", err
+		}
+		if account.Vendor != vendor || account.Extension == nil {
+			return "", errf.Newf(errf.InvalidParameter, "account %s is not %s account", accountID, vendor)
+		}
+		return account.Extension.CloudAccountID, nil
+
+	case enumor.Gcp:
+		account, err := svc.client.DataService().Gcp.Account.Get(kt.Ctx, kt.Header(), accountID)
+		if err != nil {
+			logs.Errorf("get gcp account failed, err: %v, id: %s, rid: %s", err, accountID, kt.Rid)
+			return "", err
+		}
+		if account.Vendor != vendor || account.Extension == nil {
+			return "", errf.Newf(errf.InvalidParameter, "account %s is not %s account", accountID, vendor)
+		}
+		return account.Extension.CloudProjectID, nil
+
+	default:
+		return "", errf.Newf(errf.InvalidParameter, "%s does not support private image", vendor)
+	}
+}
+
+// createFlow 创建、复制镜像耗时较长，异步执行，直接返回任务流ID
+func (svc *imageSvc) createFlow(kt *kit.Kit, flowName enumor.FlowName, tasks []ts.CustomFlowTask) (
+	interface{}, error) {
+
+	flowReq := &ts.AddCustomFlowReq{
+		Name:  flowName,
+		Tasks: tasks,
+	}
+	result, err := svc.client.TaskServer().CreateCustomFlow(kt, flowReq)
+	if err != nil {
+		logs.Errorf("call taskserver to create %s flow failed, err: %v, rid: %s", flowName, err, kt.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func validatePrivateImageVendor(infos map[string]types.CloudResourceBasicInfo) error {
+	for _, info := range infos {
+		if !slice.IsItemInSlice(coreimage.PrivateImageVendors, info.Vendor) {
+			return errf.NewFromErr(errf.InvalidParameter,
+				fmt.Errorf("%s(id=%s) of vendor %s does not support private image", info.ResType, info.ID,
+					info.Vendor))
+		}
+	}
+	return nil
+}
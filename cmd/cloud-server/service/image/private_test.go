@@ -0,0 +1,51 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package image
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/types"
+)
+
+func TestValidatePrivateImageVendor(t *testing.T) {
+	cases := []struct {
+		vendor    enumor.Vendor
+		expectErr bool
+	}{
+		{vendor: enumor.TCloud, expectErr: false},
+		{vendor: enumor.HuaWei, expectErr: false},
+		{vendor: enumor.Aws, expectErr: false},
+		{vendor: enumor.Azure, expectErr: false},
+		{vendor: enumor.Gcp, expectErr: false},
+		{vendor: enumor.Zenlayer, expectErr: true},
+	}
+
+	for _, c := range cases {
+		infos := map[string]types.CloudResourceBasicInfo{
+			"1": {ResType: enumor.CvmCloudResType, ID: "1", Vendor: c.vendor},
+		}
+		err := validatePrivateImageVendor(infos)
+		if (err != nil) != c.expectErr {
+			t.Errorf("validate vendor %s expect error: %v, got: %v", c.vendor, c.expectErr, err)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ipam
+
+import (
+	ipamlogic "hcm/cmd/cloud-server/logics/ipam"
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+	"hcm/pkg/tools/slice"
+)
+
+// ReserveBizIpamCidr reserve cidr in biz ipam cidr pool, the reserved cidr will not be proposed.
+func (svc *ipamSvc) ReserveBizIpamCidr(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.IpamReserveReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	pool, err := svc.getBizIpamPool(cts, req.PoolID, meta.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	createReq := &dataproto.IpamAllocationBatchCreateReq{
+		Allocations: []dataproto.IpamAllocationCreate{{
+			PoolID:  pool.ID,
+			BkBizID: pool.BkBizID,
+			Cidr:    req.Cidr,
+			Type:    enumor.IpamReservation,
+			Memo:    req.Memo,
+		}},
+	}
+	result, err := svc.client.DataService().Global.Ipam.BatchCreateAllocation(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("reserve ipam cidr failed, err: %v, pool: %s, cidr: %s, rid: %s", err, pool.ID, req.Cidr,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return core.CreateResult{ID: result.IDs[0]}, nil
+}
+
+// ListBizIpamAllocation list biz ipam cidr allocation.
+func (svc *ipamSvc) ListBizIpamAllocation(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	expr, noPermFlag, err := handler.ListBizAuthRes(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.Vpc, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &core.ListResult{Count: 0, Details: make([]interface{}, 0)}, nil
+	}
+	req.Filter = expr
+
+	return svc.client.DataService().Global.Ipam.ListAllocation(cts.Kit, req)
+}
+
+// BatchDeleteBizIpamAllocation batch delete biz ipam cidr allocation, the cidr will be released to the pool.
+func (svc *ipamSvc) BatchDeleteBizIpamAllocation(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(proto.IpamBatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleIn("id", req.IDs), tools.RuleEqual("bk_biz_id", bizID)),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id", "bk_biz_id"},
+	}
+	result, err := svc.client.DataService().Global.Ipam.ListAllocation(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list ipam cidr allocation failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, nil
+	}
+
+	basicInfos := make(map[string]types.CloudResourceBasicInfo, len(result.Details))
+	for _, one := range result.Details {
+		basicInfos[one.ID] = types.CloudResourceBasicInfo{ID: one.ID, BkBizID: one.BkBizID}
+	}
+	err = handler.BizOperateAuth(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer,
+		ResType: meta.Vpc, Action: meta.Update, BasicInfos: basicInfos})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := slice.Map(result.Details, func(one corecloud.IpamAllocation) string { return one.ID })
+	delReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", ids)}
+	if err = svc.client.DataService().Global.Ipam.BatchDeleteAllocation(cts.Kit, delReq); err != nil {
+		logs.Errorf("delete ipam cidr allocation failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ProposeBizIpamCidr propose an available cidr for new vpc from the ipam pool, or for new subnet from the vpc.
+func (svc *ipamSvc) ProposeBizIpamCidr(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.IpamProposeReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	act := meta.Find
+	if req.Allocate {
+		act = meta.Update
+	}
+
+	var pool *corecloud.IpamPool
+	var err error
+	if len(req.PoolID) != 0 {
+		if pool, err = svc.getBizIpamPool(cts, req.PoolID, act); err != nil {
+			return nil, err
+		}
+	} else {
+		if err = svc.authBizVpc(cts, req.VpcID); err != nil {
+			return nil, err
+		}
+	}
+
+	opt := &ipamlogic.ProposeCidrOption{PoolID: req.PoolID, VpcID: req.VpcID, Masklen: req.GetMaskLen()}
+	proposed, err := svc.ipam.ProposeCidr(cts.Kit, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &proto.IpamProposeResult{Cidr: proposed}
+	if !req.Allocate || pool == nil {
+		return result, nil
+	}
+
+	createReq := &dataproto.IpamAllocationBatchCreateReq{
+		Allocations: []dataproto.IpamAllocationCreate{{
+			PoolID:  pool.ID,
+			BkBizID: pool.BkBizID,
+			Cidr:    proposed,
+			Type:    enumor.IpamAllocation,
+			Memo:    req.Memo,
+		}},
+	}
+	created, err := svc.client.DataService().Global.Ipam.BatchCreateAllocation(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("allocate ipam cidr failed, err: %v, pool: %s, cidr: %s, rid: %s", err, pool.ID, proposed,
+			cts.Kit.Rid)
+		return nil, err
+	}
+	result.AllocationID = created.IDs[0]
+
+	return result, nil
+}
+
+// CheckBizIpamCidr check whether the cidr overlaps with the synced vpcs, subnets and ipam allocations.
+func (svc *ipamSvc) CheckBizIpamCidr(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(proto.IpamCheckReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(req.VpcID) != 0 {
+		if err = svc.authBizVpc(cts, req.VpcID); err != nil {
+			return nil, err
+		}
+	} else {
+		err = handler.BizOperateAuth(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer,
+			ResType: meta.Vpc, Action: meta.Find, BasicInfo: &types.CloudResourceBasicInfo{BkBizID: bizID}})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	conflicts, err := svc.ipam.CheckCidr(cts.Kit, &ipamlogic.CheckCidrOption{Cidr: req.Cidr, VpcID: req.VpcID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.IpamCheckResult{Overlapped: len(conflicts) != 0, Conflicts: conflicts}, nil
+}
+
+// authBizVpc check the vpc belongs to the biz in path, and authorize the find action.
+func (svc *ipamSvc) authBizVpc(cts *rest.Contexts, vpcID string) error {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return err
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("id", vpcID),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id", "bk_biz_id"},
+	}
+	result, err := svc.client.DataService().Global.Vpc.List(cts.Kit.Ctx, cts.Kit.Header(), listReq)
+	if err != nil {
+		logs.Errorf("get vpc failed, err: %v, id: %s, rid: %s", err, vpcID, cts.Kit.Rid)
+		return err
+	}
+
+	if len(result.Details) == 0 {
+		return errf.Newf(errf.RecordNotFound, "vpc: %s not found", vpcID)
+	}
+
+	if result.Details[0].BkBizID != bizID {
+		return errf.Newf(errf.InvalidParameter, "vpc: %s not matches url biz", vpcID)
+	}
+
+	return handler.BizOperateAuth(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer,
+		ResType: meta.Vpc, Action: meta.Find,
+		BasicInfo: &types.CloudResourceBasicInfo{ID: vpcID, BkBizID: bizID}})
+}
@@ -0,0 +1,66 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package ipam ...
+package ipam
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/logics/ipam"
+	"hcm/cmd/cloud-server/service/capability"
+	"hcm/pkg/client"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/rest"
+)
+
+// InitService initialize the ipam service.
+func InitService(c *capability.Capability) {
+	svc := &ipamSvc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+		ipam:       c.Logics.Ipam,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CreateBizIpamPool", http.MethodPost, "/bizs/{bk_biz_id}/ipam/pools/create", svc.CreateBizIpamPool)
+	h.Add("UpdateBizIpamPool", http.MethodPatch, "/bizs/{bk_biz_id}/ipam/pools/{id}", svc.UpdateBizIpamPool)
+	h.Add("ListBizIpamPool", http.MethodPost, "/bizs/{bk_biz_id}/ipam/pools/list", svc.ListBizIpamPool)
+	h.Add("BatchDeleteBizIpamPool", http.MethodDelete, "/bizs/{bk_biz_id}/ipam/pools/batch",
+		svc.BatchDeleteBizIpamPool)
+
+	h.Add("ReserveBizIpamCidr", http.MethodPost, "/bizs/{bk_biz_id}/ipam/allocations/reserve",
+		svc.ReserveBizIpamCidr)
+	h.Add("ListBizIpamAllocation", http.MethodPost, "/bizs/{bk_biz_id}/ipam/allocations/list",
+		svc.ListBizIpamAllocation)
+	h.Add("BatchDeleteBizIpamAllocation", http.MethodDelete, "/bizs/{bk_biz_id}/ipam/allocations/batch",
+		svc.BatchDeleteBizIpamAllocation)
+
+	h.Add("ProposeBizIpamCidr", http.MethodPost, "/bizs/{bk_biz_id}/ipam/cidrs/propose", svc.ProposeBizIpamCidr)
+	h.Add("CheckBizIpamCidr", http.MethodPost, "/bizs/{bk_biz_id}/ipam/cidrs/check", svc.CheckBizIpamCidr)
+
+	h.Load(c.WebService)
+}
+
+type ipamSvc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+	ipam       ipam.Interface
+}
@@ -0,0 +1,250 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ipam
+
+import (
+	"net"
+
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/cidr"
+	"hcm/pkg/tools/hooks/handler"
+	"hcm/pkg/tools/slice"
+)
+
+// CreateBizIpamPool create biz ipam cidr pool, the pools of the same biz, vendor and region can not overlap.
+func (svc *ipamSvc) CreateBizIpamPool(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(proto.IpamPoolCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err = handler.BizOperateAuth(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer,
+		ResType: meta.Vpc, Action: meta.Create, BasicInfo: &types.CloudResourceBasicInfo{BkBizID: bizID}})
+	if err != nil {
+		return nil, err
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("bk_biz_id", bizID), tools.RuleEqual("vendor", req.Vendor),
+			tools.RuleEqual("region", req.Region)),
+		Page: core.NewDefaultBasePage(),
+	}
+	pools, err := svc.client.DataService().Global.Ipam.ListPool(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list ipam cidr pool failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	_, reqNet, err := net.ParseCIDR(req.Cidr)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	for _, pool := range pools.Details {
+		_, poolNet, err := net.ParseCIDR(pool.Cidr)
+		if err != nil {
+			return nil, err
+		}
+
+		if cidr.IsOverlapped(*poolNet, *reqNet) {
+			return nil, errf.Newf(errf.InvalidParameter, "cidr %s overlaps with ipam pool %s(%s)", req.Cidr,
+				pool.Name, pool.Cidr)
+		}
+	}
+
+	createReq := &dataproto.IpamPoolBatchCreateReq{
+		Pools: []dataproto.IpamPoolCreate{{
+			Name:    req.Name,
+			Vendor:  req.Vendor,
+			Region:  req.Region,
+			BkBizID: bizID,
+			Cidr:    req.Cidr,
+			Memo:    req.Memo,
+		}},
+	}
+	result, err := svc.client.DataService().Global.Ipam.BatchCreatePool(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create ipam cidr pool failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return core.CreateResult{ID: result.IDs[0]}, nil
+}
+
+// UpdateBizIpamPool update biz ipam cidr pool.
+func (svc *ipamSvc) UpdateBizIpamPool(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.IpamPoolUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	pool, err := svc.getBizIpamPool(cts, cts.PathParameter("id").String(), meta.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	updateReq := &dataproto.IpamPoolUpdateReq{
+		Name: req.Name,
+		Memo: req.Memo,
+	}
+	if err = svc.client.DataService().Global.Ipam.UpdatePool(cts.Kit, pool.ID, updateReq); err != nil {
+		logs.Errorf("update ipam cidr pool failed, err: %v, id: %s, rid: %s", err, pool.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBizIpamPool list biz ipam cidr pool.
+func (svc *ipamSvc) ListBizIpamPool(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	expr, noPermFlag, err := handler.ListBizAuthRes(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.Vpc, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &core.ListResult{Count: 0, Details: make([]interface{}, 0)}, nil
+	}
+	req.Filter = expr
+
+	return svc.client.DataService().Global.Ipam.ListPool(cts.Kit, req)
+}
+
+// BatchDeleteBizIpamPool batch delete biz ipam cidr pool, the pool which has allocations can not be deleted.
+func (svc *ipamSvc) BatchDeleteBizIpamPool(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(proto.IpamBatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleIn("id", req.IDs), tools.RuleEqual("bk_biz_id", bizID)),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id", "bk_biz_id"},
+	}
+	result, err := svc.client.DataService().Global.Ipam.ListPool(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list ipam cidr pool failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, nil
+	}
+
+	basicInfos := make(map[string]types.CloudResourceBasicInfo, len(result.Details))
+	for _, one := range result.Details {
+		basicInfos[one.ID] = types.CloudResourceBasicInfo{ID: one.ID, BkBizID: one.BkBizID}
+	}
+	err = handler.BizOperateAuth(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer,
+		ResType: meta.Vpc, Action: meta.Delete, BasicInfos: basicInfos})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := slice.Map(result.Details, func(one corecloud.IpamPool) string { return one.ID })
+	delReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", ids)}
+	if err = svc.client.DataService().Global.Ipam.BatchDeletePool(cts.Kit, delReq); err != nil {
+		logs.Errorf("delete ipam cidr pool failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// getBizIpamPool get the ipam cidr pool of the biz in path, and authorize the action.
+func (svc *ipamSvc) getBizIpamPool(cts *rest.Contexts, id string, act meta.Action) (*corecloud.IpamPool, error) {
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.client.DataService().Global.Ipam.ListPool(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("get ipam cidr pool failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "ipam cidr pool: %s not found", id)
+	}
+	pool := result.Details[0]
+
+	if pool.BkBizID != bizID {
+		return nil, errf.Newf(errf.InvalidParameter, "ipam cidr pool: %s not matches url biz", id)
+	}
+
+	err = handler.BizOperateAuth(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer,
+		ResType: meta.Vpc, Action: act, BasicInfo: &types.CloudResourceBasicInfo{ID: pool.ID, BkBizID: pool.BkBizID}})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pool, nil
+}
@@ -26,9 +26,11 @@ import (
 	dataproto "hcm/pkg/api/data-service/cloud"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
 )
 
 // AssignLbToBiz 分配到业务下
@@ -46,6 +48,7 @@ func (svc *lbSvc) AssignLbToBiz(cts *rest.Contexts) (any, error) {
 	clbInfoReq := dataproto.ListResourceBasicInfoReq{
 		ResourceType: enumor.LoadBalancerCloudResType,
 		IDs:          req.LbIDs,
+		Fields:       types.ResWithRecycleBasicFields,
 	}
 	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, clbInfoReq)
 	if err != nil {
@@ -53,6 +56,10 @@ func (svc *lbSvc) AssignLbToBiz(cts *rest.Contexts) (any, error) {
 		return nil, err
 	}
 
+	if err = handler.ValidateNotRecycled(basicInfoMap); err != nil {
+		return nil, err
+	}
+
 	authRes := make([]meta.ResourceAttribute, 0, len(basicInfoMap))
 	for _, info := range basicInfoMap {
 		authRes = append(authRes, meta.ResourceAttribute{
@@ -377,7 +377,7 @@ func (svc *lbSvc) getAndCheckLBPerm(cts *rest.Contexts,
 
 	// 获取操作记录详情
 	lbInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit,
-		enumor.LoadBalancerCloudResType, lbID)
+		enumor.LoadBalancerCloudResType, lbID, types.ResWithRecycleBasicFields...)
 	if err != nil {
 		logs.Errorf("get load balancer basic info failed, id: %d, err: %v, rid: %s", lbID, err, cts.Kit.Rid)
 		return nil, err
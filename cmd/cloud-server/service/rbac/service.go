@@ -0,0 +1,246 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package rbac 本地rbac角色、权限策略、角色绑定管理接口，请求经网关认证后转发到auth-server，由其校验管理员身份
+package rbac
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/service/capability"
+	authserver "hcm/pkg/api/auth-server"
+	"hcm/pkg/api/core"
+	dsauth "hcm/pkg/api/data-service/auth"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// InitService initial the local rbac management service.
+func InitService(c *capability.Capability) {
+	svc := &rbacSvc{
+		client: c.ApiClient,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CreateRbacRole", http.MethodPost, "/rbac/roles/create", svc.CreateRole)
+	h.Add("UpdateRbacRole", http.MethodPatch, "/rbac/roles/{id}", svc.UpdateRole)
+	h.Add("ListRbacRole", http.MethodPost, "/rbac/roles/list", svc.ListRole)
+	h.Add("BatchDeleteRbacRole", http.MethodDelete, "/rbac/roles/batch", svc.BatchDeleteRole)
+
+	h.Add("CreateRbacPolicy", http.MethodPost, "/rbac/policies/create", svc.CreatePolicy)
+	h.Add("ListRbacPolicy", http.MethodPost, "/rbac/policies/list", svc.ListPolicy)
+	h.Add("BatchDeleteRbacPolicy", http.MethodDelete, "/rbac/policies/batch", svc.BatchDeletePolicy)
+
+	h.Add("CreateRbacBinding", http.MethodPost, "/rbac/bindings/create", svc.CreateBinding)
+	h.Add("ListRbacBinding", http.MethodPost, "/rbac/bindings/list", svc.ListBinding)
+	h.Add("BatchDeleteRbacBinding", http.MethodDelete, "/rbac/bindings/batch", svc.BatchDeleteBinding)
+
+	h.Load(c.WebService)
+}
+
+type rbacSvc struct {
+	client *client.ClientSet
+}
+
+// CreateRole create local rbac role.
+func (svc *rbacSvc) CreateRole(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsauth.RoleCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	result, err := svc.client.AuthServer().CreateRbacRole(cts.Kit, req)
+	if err != nil {
+		logs.Errorf("create rbac role failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateRole update local rbac role.
+func (svc *rbacSvc) UpdateRole(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(dsauth.RoleUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.client.AuthServer().UpdateRbacRole(cts.Kit, id, req); err != nil {
+		logs.Errorf("update rbac role %s failed, err: %v, req: %+v, rid: %s", id, err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListRole list local rbac role.
+func (svc *rbacSvc) ListRole(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return svc.client.AuthServer().ListRbacRole(cts.Kit, req)
+}
+
+// BatchDeleteRole batch delete local rbac role.
+func (svc *rbacSvc) BatchDeleteRole(cts *rest.Contexts) (interface{}, error) {
+	req, err := decodeBatchDeleteReq(cts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.client.AuthServer().BatchDeleteRbacRole(cts.Kit, req); err != nil {
+		logs.Errorf("batch delete rbac role failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// CreatePolicy create local rbac role policies.
+func (svc *rbacSvc) CreatePolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsauth.RolePolicyBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	result, err := svc.client.AuthServer().CreateRbacPolicy(cts.Kit, req)
+	if err != nil {
+		logs.Errorf("create rbac policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListPolicy list local rbac role policies.
+func (svc *rbacSvc) ListPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return svc.client.AuthServer().ListRbacPolicy(cts.Kit, req)
+}
+
+// BatchDeletePolicy batch delete local rbac role policies.
+func (svc *rbacSvc) BatchDeletePolicy(cts *rest.Contexts) (interface{}, error) {
+	req, err := decodeBatchDeleteReq(cts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.client.AuthServer().BatchDeleteRbacPolicy(cts.Kit, req); err != nil {
+		logs.Errorf("batch delete rbac policy failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// CreateBinding create local rbac role bindings.
+func (svc *rbacSvc) CreateBinding(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsauth.RoleBindingBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	result, err := svc.client.AuthServer().CreateRbacBinding(cts.Kit, req)
+	if err != nil {
+		logs.Errorf("create rbac binding failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListBinding list local rbac role bindings.
+func (svc *rbacSvc) ListBinding(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return svc.client.AuthServer().ListRbacBinding(cts.Kit, req)
+}
+
+// BatchDeleteBinding batch delete local rbac role bindings.
+func (svc *rbacSvc) BatchDeleteBinding(cts *rest.Contexts) (interface{}, error) {
+	req, err := decodeBatchDeleteReq(cts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.client.AuthServer().BatchDeleteRbacBinding(cts.Kit, req); err != nil {
+		logs.Errorf("batch delete rbac binding failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func decodeBatchDeleteReq(cts *rest.Contexts) (*authserver.RbacBatchDeleteReq, error) {
+	req := new(authserver.RbacBatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return req, nil
+}
@@ -0,0 +1,235 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recycle
+
+import (
+	"net/http"
+	"time"
+
+	logicsorphan "hcm/cmd/cloud-server/logics/orphan"
+	cloudserver "hcm/pkg/api/cloud-server"
+	proto "hcm/pkg/api/cloud-server/cvm"
+	csdisk "hcm/pkg/api/cloud-server/disk"
+	csrecycle "hcm/pkg/api/cloud-server/recycle"
+	"hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+	"hcm/pkg/tools/slice"
+)
+
+// initOrphanService register the orphan resource report and one-click recycle apis.
+func (svc *svc) initOrphanService(h *rest.Handler) {
+	h.Add("ListBizOrphanRes", http.MethodPost, "/bizs/{bk_biz_id}/orphan_resources/list", svc.ListBizOrphanRes)
+	h.Add("RecycleBizOrphanRes", http.MethodPost, "/bizs/{bk_biz_id}/orphan_resources/recycle",
+		svc.RecycleBizOrphanRes)
+}
+
+// ListBizOrphanRes list orphan resources of the biz with estimated monthly cost.
+func (svc *svc) ListBizOrphanRes(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	if bizID <= 0 {
+		return nil, errf.New(errf.InvalidParameter, "bk_biz_id is invalid")
+	}
+
+	req := new(cloudserver.OrphanResListReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Biz, Action: meta.Access}, BizID: bizID}
+	if err = svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	// 未指定账单月份时使用上个月的账单估算成本
+	billYear, billMonth := req.BillYear, req.BillMonth
+	if billYear == 0 {
+		now := time.Now()
+		lastMonth := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, now.Location())
+		billYear, billMonth = lastMonth.Year(), int(lastMonth.Month())
+	}
+
+	opt := &logicsorphan.ListOption{
+		BkBizID:     bizID,
+		ResTypes:    req.ResTypes,
+		AccountIDs:  req.AccountIDs,
+		StoppedDays: req.StoppedDays,
+		WithCost:    true,
+		BillYear:    billYear,
+		BillMonth:   billMonth,
+	}
+	details, err := svc.orphanLgc.ListOrphanRes(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list biz orphan resource failed, err: %v, biz: %d, rid: %s", err, bizID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &cloudserver.OrphanResListResult{Details: details, Summary: logicsorphan.Summarize(details)}, nil
+}
+
+// orphanAuthType 闲置资源回收时各资源类型的鉴权资源类型
+func orphanAuthType(resType enumor.CloudResourceType) meta.ResourceType {
+	switch resType {
+	case enumor.CvmCloudResType:
+		return meta.Cvm
+	case enumor.DiskCloudResType:
+		return meta.Disk
+	}
+
+	if m, exists := findResRecycleMeta(resType); exists {
+		return m.authType
+	}
+	return ""
+}
+
+// findResRecycleMeta 查找通用方式回收的资源的元信息
+func findResRecycleMeta(resType enumor.CloudResourceType) (resRecycleMeta, bool) {
+	for _, m := range resRecycleMetas {
+		if m.resType == resType {
+			return m, true
+		}
+	}
+	return resRecycleMeta{}, false
+}
+
+// RecycleBizOrphanRes put the selected orphan resources of the biz into recycle bin, the resources are checked again
+// to make sure that they are still orphaned, and are recycled in the same way as the recycle api of each type.
+func (svc *svc) RecycleBizOrphanRes(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(cloudserver.OrphanResRecycleReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 全部资源鉴权、校验通过后才开始回收
+	basicInfoMaps := make(map[enumor.CloudResourceType]map[string]types.CloudResourceBasicInfo, len(req.Items))
+	for _, item := range req.Items {
+		basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit,
+			cloud.ListResourceBasicInfoReq{ResourceType: item.ResType, IDs: item.IDs,
+				Fields: append(types.ResWithRecycleBasicFields, "region")})
+		if err != nil {
+			return nil, err
+		}
+
+		err = handler.BizOperateAuth(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer,
+			ResType: orphanAuthType(item.ResType), Action: meta.Recycle, BasicInfos: basicInfoMap})
+		if err != nil {
+			return nil, err
+		}
+
+		if err = svc.checkStillOrphan(cts.Kit, bizID, item, req.StoppedDays); err != nil {
+			return nil, err
+		}
+		basicInfoMaps[item.ResType] = basicInfoMap
+	}
+
+	result := &cloudserver.OrphanResRecycleResult{Details: make([]cloudserver.OrphanResRecycleDetail, 0)}
+	for _, item := range req.Items {
+		detail := cloudserver.OrphanResRecycleDetail{ResType: item.ResType}
+		res, err := svc.recycleOrphanRes(cts.Kit, item, basicInfoMaps[item.ResType], req)
+		if err != nil {
+			logs.Errorf("recycle orphan %s failed, err: %v, ids: %v, rid: %s", item.ResType, err, item.IDs,
+				cts.Kit.Rid)
+			detail.Error = err.Error()
+		}
+		detail.Result = res
+		result.Details = append(result.Details, detail)
+	}
+
+	return result, nil
+}
+
+// checkStillOrphan 校验选中的资源当前仍为闲置资源，避免回收报告生成后又被使用的资源
+func (svc *svc) checkStillOrphan(kt *kit.Kit, bizID int64, item cloudserver.OrphanResRecycleItem,
+	stoppedDays int) error {
+
+	opt := &logicsorphan.ListOption{
+		BkBizID:     bizID,
+		ResTypes:    []enumor.CloudResourceType{item.ResType},
+		IDs:         item.IDs,
+		StoppedDays: stoppedDays,
+	}
+	orphans, err := svc.orphanLgc.ListOrphanRes(kt, opt)
+	if err != nil {
+		return err
+	}
+
+	orphanIDs := make(map[string]struct{}, len(orphans))
+	for _, one := range orphans {
+		orphanIDs[one.ID] = struct{}{}
+	}
+	notOrphanIDs := slice.Filter(item.IDs, func(id string) bool {
+		_, exists := orphanIDs[id]
+		return !exists
+	})
+	if len(notOrphanIDs) > 0 {
+		return errf.Newf(errf.InvalidParameter, "%s %v are not orphaned any more", item.ResType, notOrphanIDs)
+	}
+
+	return nil
+}
+
+// recycleOrphanRes 按资源类型复用对应的回收逻辑
+func (svc *svc) recycleOrphanRes(kt *kit.Kit, item cloudserver.OrphanResRecycleItem,
+	basicInfoMap map[string]types.CloudResourceBasicInfo, req *cloudserver.OrphanResRecycleReq) (interface{}, error) {
+
+	switch item.ResType {
+	case enumor.CvmCloudResType:
+		infos := slice.Map(item.IDs, func(id string) proto.CvmRecycleInfo {
+			return proto.CvmRecycleInfo{ID: id, CvmRecycleOptions: req.CvmRecycleOptions}
+		})
+		taskID, err := svc.cvmLgc.RecycleCvm(kt, infos, basicInfoMap)
+		if err != nil {
+			return nil, err
+		}
+		return &csrecycle.RecycleResult{TaskID: taskID}, nil
+
+	case enumor.DiskCloudResType:
+		infos := slice.Map(item.IDs, func(id string) csdisk.DiskRecycleInfo { return csdisk.DiskRecycleInfo{ID: id} })
+		return svc.diskLgc.RecycleDisk(kt, infos, basicInfoMap)
+
+	default:
+		if m, exists := findResRecycleMeta(item.ResType); exists {
+			return svc.recycleResByIDs(kt, m, item.IDs, basicInfoMap)
+		}
+		return nil, errf.Newf(errf.InvalidParameter, "res_type %s does not support recycle", item.ResType)
+	}
+}
@@ -0,0 +1,235 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recycle
+
+import (
+	"fmt"
+	"net/http"
+
+	proto "hcm/pkg/api/cloud-server/recycle"
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	protods "hcm/pkg/api/data-service/recycle-record"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	rr "hcm/pkg/dal/table/recycle-record"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+)
+
+func (svc *svc) initRecyclePolicyService(h *rest.Handler) {
+	h.Add("CreateRecyclePolicy", http.MethodPost, "/recycle_policies/create", svc.CreateRecyclePolicy)
+	h.Add("UpdateRecyclePolicy", http.MethodPatch, "/recycle_policies/{id}", svc.UpdateRecyclePolicy)
+	h.Add("ListRecyclePolicy", http.MethodPost, "/recycle_policies/list", svc.ListRecyclePolicy)
+	h.Add("BatchDeleteRecyclePolicy", http.MethodDelete, "/recycle_policies/batch", svc.BatchDeleteRecyclePolicy)
+
+	h.Add("CreateBizRecyclePolicy", http.MethodPost, "/bizs/{bk_biz_id}/recycle_policies/create",
+		svc.CreateBizRecyclePolicy)
+	h.Add("UpdateBizRecyclePolicy", http.MethodPatch, "/bizs/{bk_biz_id}/recycle_policies/{id}",
+		svc.UpdateBizRecyclePolicy)
+	h.Add("ListBizRecyclePolicy", http.MethodPost, "/bizs/{bk_biz_id}/recycle_policies/list",
+		svc.ListBizRecyclePolicy)
+	h.Add("BatchDeleteBizRecyclePolicy", http.MethodDelete, "/bizs/{bk_biz_id}/recycle_policies/batch",
+		svc.BatchDeleteBizRecyclePolicy)
+}
+
+// CreateRecyclePolicy create recycle policy, bk_biz_id 0 means the global policy.
+func (svc *svc) CreateRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.createRecyclePolicy(cts, false)
+}
+
+// CreateBizRecyclePolicy create biz recycle policy.
+func (svc *svc) CreateBizRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.createRecyclePolicy(cts, true)
+}
+
+func (svc *svc) createRecyclePolicy(cts *rest.Contexts, isBiz bool) (interface{}, error) {
+	req := new(protods.RecyclePolicyCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	bizID, err := svc.authorizeRecyclePolicy(cts, isBiz, meta.Update)
+	if err != nil {
+		return nil, err
+	}
+	if isBiz {
+		req.BkBizID = bizID
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return svc.client.DataService().Global.RecycleRecord.CreateRecyclePolicy(cts.Kit, req)
+}
+
+// UpdateRecyclePolicy update recycle policy.
+func (svc *svc) UpdateRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.updateRecyclePolicy(cts, false)
+}
+
+// UpdateBizRecyclePolicy update biz recycle policy.
+func (svc *svc) UpdateBizRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.updateRecyclePolicy(cts, true)
+}
+
+func (svc *svc) updateRecyclePolicy(cts *rest.Contexts, isBiz bool) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protods.RecyclePolicyUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	bizID, err := svc.authorizeRecyclePolicy(cts, isBiz, meta.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	if isBiz {
+		if err = svc.checkBizRecyclePolicy(cts.Kit, bizID, []string{id}); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, svc.client.DataService().Global.RecycleRecord.UpdateRecyclePolicy(cts.Kit, id, req)
+}
+
+// ListRecyclePolicy list recycle policy.
+func (svc *svc) ListRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.listRecyclePolicy(cts, false)
+}
+
+// ListBizRecyclePolicy list biz recycle policy, the global policy is returned together.
+func (svc *svc) ListBizRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.listRecyclePolicy(cts, true)
+}
+
+func (svc *svc) listRecyclePolicy(cts *rest.Contexts, isBiz bool) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	bizID, err := svc.authorizeRecyclePolicy(cts, isBiz, meta.Find)
+	if err != nil {
+		return nil, err
+	}
+
+	if isBiz {
+		bizFilter := tools.ContainersExpression("bk_biz_id", []int64{bizID, rr.GlobalRecyclePolicyBizID})
+		req.Filter, err = tools.And(req.Filter, bizFilter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return svc.client.DataService().Global.RecycleRecord.ListRecyclePolicy(cts.Kit, req)
+}
+
+// BatchDeleteRecyclePolicy batch delete recycle policy.
+func (svc *svc) BatchDeleteRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchDeleteRecyclePolicy(cts, false)
+}
+
+// BatchDeleteBizRecyclePolicy batch delete biz recycle policy.
+func (svc *svc) BatchDeleteBizRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchDeleteRecyclePolicy(cts, true)
+}
+
+func (svc *svc) batchDeleteRecyclePolicy(cts *rest.Contexts, isBiz bool) (interface{}, error) {
+	req := new(proto.RecyclePolicyDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	bizID, err := svc.authorizeRecyclePolicy(cts, isBiz, meta.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	if isBiz {
+		if err = svc.checkBizRecyclePolicy(cts.Kit, bizID, req.IDs); err != nil {
+			return nil, err
+		}
+	}
+
+	deleteReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", req.IDs)}
+	return nil, svc.client.DataService().Global.RecycleRecord.BatchDeleteRecyclePolicy(cts.Kit, deleteReq)
+}
+
+// authorizeRecyclePolicy authorize recycle policy operation, returns the biz id of the biz api.
+func (svc *svc) authorizeRecyclePolicy(cts *rest.Contexts, isBiz bool, action meta.Action) (int64, error) {
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.RecycleBin, Action: action}}
+	if !isBiz {
+		return 0, svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes)
+	}
+
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return 0, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	if bizID <= 0 {
+		return 0, errf.New(errf.InvalidParameter, "bk_biz_id is invalid")
+	}
+
+	authRes.BizID = bizID
+	return bizID, svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes)
+}
+
+// checkBizRecyclePolicy check if the recycle policies all belong to the biz.
+func (svc *svc) checkBizRecyclePolicy(kt *kit.Kit, bizID int64, ids []string) error {
+	ids = slice.Unique(ids)
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleIn("id", ids), tools.RuleEqual("bk_biz_id", bizID)),
+		Page:   core.NewCountPage(),
+	}
+	result, err := svc.client.DataService().Global.RecycleRecord.ListRecyclePolicy(kt, listReq)
+	if err != nil {
+		logs.Errorf("count biz recycle policy failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return err
+	}
+
+	if result.Count != uint64(len(ids)) {
+		return errf.NewFromErr(errf.InvalidParameter,
+			fmt.Errorf("some recycle policies(ids=%v) do not belong to biz %d", ids, bizID))
+	}
+
+	return nil
+}
@@ -20,6 +20,7 @@
 package recycle
 
 import (
+	"errors"
 	"time"
 
 	"hcm/cmd/cloud-server/logics"
@@ -44,9 +45,10 @@ import (
 )
 
 type recycle struct {
-	client *client.ClientSet
-	logics *logics.Logics
-	state  serviced.State
+	client    *client.ClientSet
+	logics    *logics.Logics
+	state     serviced.State
+	recyclers map[enumor.CloudResourceType]logicsrecycle.ResRecycler
 }
 
 // RecycleTiming timing recycle all resource.
@@ -59,16 +61,29 @@ func RecycleTiming(c *client.ClientSet, state serviced.State, conf cc.Recycle, e
 
 	go r.recycleTiming(enumor.DiskCloudResType, r.recycleDiskWorker, conf)
 	go r.recycleTiming(enumor.CvmCloudResType, r.recycleCvmWorker, conf)
+
+	r.recyclers = logicsrecycle.NewResRecyclers(c, r.logics.Audit, r.logics.Eip)
+	for resType, recycler := range r.recyclers {
+		go r.recycleTiming(resType, r.genResRecycleWorker(recycler), conf)
+	}
 }
 
 type recycleWorker func(kt *kit.Kit, info *types.CloudResourceBasicInfo) error
 
+// errRecycleNotReady is returned by recycle worker when the resource is not ready to be destroyed, the record is kept
+// waiting and will be recycled in the next round.
+var errRecycleNotReady = errors.New("resource is not ready to be destroyed")
+
 func (r *recycle) recycleTiming(resType enumor.CloudResourceType, worker recycleWorker, conf cc.Recycle) {
+	// 未就绪的记录仍处于待回收状态，翻页跳过这些记录以处理后面的记录，所有记录都处理一轮后再从头开始
+	var start uint32
+	passHandled := 0
 	for {
 		kt := core.NewBackendKit()
 
 		if !r.state.IsMaster() {
 			logs.Infof("recycle %s, but is not master, skip", resType)
+			start, passHandled = 0, 0
 			time.Sleep(time.Minute)
 			continue
 		}
@@ -86,10 +101,13 @@ func (r *recycle) recycleTiming(resType enumor.CloudResourceType, worker recycle
 			time.Sleep(time.Minute)
 			continue
 		}
+		page := core.NewDefaultBasePage()
+		page.Start = start
+		page.Sort = "id"
 		listReq := &core.ListReq{
 			Filter: expr,
-			Page:   core.NewDefaultBasePage(),
-			Fields: []string{"id", "res_id", "bk_biz_id"},
+			Page:   page,
+			Fields: []string{"id", "res_type", "res_id", "cloud_res_id", "bk_biz_id", "snapshot_ids", "timeline"},
 		}
 		recordRes, err := r.client.DataService().Global.RecycleRecord.ListRecycleRecord(kt, listReq)
 		if err != nil {
@@ -100,6 +118,7 @@ func (r *recycle) recycleTiming(resType enumor.CloudResourceType, worker recycle
 
 		// sleep for a while if no resource needs recycling
 		if len(recordRes.Details) == 0 {
+			start, passHandled = 0, 0
 			time.Sleep(time.Minute * 10)
 			continue
 		}
@@ -130,23 +149,41 @@ func (r *recycle) recycleTiming(resType enumor.CloudResourceType, worker recycle
 		}
 
 		// recycle resources one by one
+		handled := 0
 		for _, record := range recordRes.Details {
 			if !r.state.IsMaster() {
 				logs.Infof("recycle %s res(id: %s), but is not master, skip, rid: %s", resType, record.ResID, kt.Rid)
 				time.Sleep(time.Minute)
 				break
 			}
-			r.execWorker(kt, worker, record, basicInfoMap)
+			if r.execWorker(kt, worker, record, basicInfoMap) {
+				handled++
+			}
+		}
+
+		logs.Infof("finished recycle %s, start: %d, count: %d, handled: %d, rid: %s", resType, start,
+			len(recordRes.Details), handled, kt.Rid)
+
+		passHandled += handled
+		if len(recordRes.Details) == int(page.Limit) {
+			// handled records are no longer waiting, so the next page starts after the records still waiting
+			start += uint32(len(recordRes.Details) - handled)
+			continue
 		}
 
-		logs.Infof("finished recycle %s, count: %d, rid: %s", resType, len(recordRes.Details), kt.Rid)
+		// sleep for a while if all the resources are not ready to be destroyed, they are kept waiting for next round
+		if passHandled == 0 {
+			time.Sleep(time.Minute * 10)
+		}
+		start, passHandled = 0, 0
 	}
 }
 
 const maxRetryCount = 3
 
+// execWorker execute recycle worker for the record, returns whether the record is handled.
 func (r *recycle) execWorker(kt *kit.Kit, worker recycleWorker, record recyclerecord.RecycleRecord,
-	basicInfoMap map[string]types.CloudResourceBasicInfo) {
+	basicInfoMap map[string]types.CloudResourceBasicInfo) bool {
 
 	basicInfo, exists := basicInfoMap[record.ResID]
 	if !exists {
@@ -154,27 +191,52 @@ func (r *recycle) execWorker(kt *kit.Kit, worker recycleWorker, record recyclere
 			kt.Rid)
 		logicsrecycle.MarkRecordFailed(kt, r.client.DataService(),
 			errf.New(errf.RecordNotFound, "Recourse Not Found"), []string{record.ID})
-		return
+		return true
 	}
 
 	rty := retry.NewRetryPolicy(maxRetryCount, [2]uint{500, 15000})
-	var err error
 
 	// 类型为cvm且在业务下回收的，需要检查是否在cmdb 待回收模块中
 	// 因为cvm记录中的BkBizID已经在加入业务的时候被清掉了，所以要以recycle_record中的为准
 	basicInfo.BkBizID = record.BkBizID
+
+	// 按回收策略在销毁前创建快照或镜像，快照或镜像可用前资源保持待回收
+	ready, err := logicsrecycle.PreDestroy(kt, r.client, record, basicInfo)
+	if err != nil {
+		logs.Errorf("[%s]recycle res(id: %s) pre-destroy failed, err: %v, rid: %s", record.ResType, record.ResID,
+			err, kt.Rid)
+		logicsrecycle.MarkRecordFailed(kt, r.client.DataService(), err, []string{record.ID})
+		return true
+	}
+	if !ready {
+		logs.V(3).Infof("[%s]recycle res(id: %s) pre-destroy backup is not ready, skip, rid: %s", record.ResType,
+			record.ResID, kt.Rid)
+		return false
+	}
+
+	notReady := false
 	err = rty.BaseExec(kt, func() error {
-		return worker(kt, &basicInfo)
+		err := worker(kt, &basicInfo)
+		if errors.Is(err, errRecycleNotReady) {
+			notReady = true
+			return nil
+		}
+		return err
 	})
+	if notReady {
+		logs.V(3).Infof("[%s]recycle res(id: %s) is not ready, skip, rid: %s", record.ResType, record.ResID, kt.Rid)
+		return false
+	}
 	if err != nil {
 		// Failed after retry
 		logicsrecycle.MarkRecordFailed(kt, r.client.DataService(), err, []string{record.ID})
-		return
+		return true
 	}
 	// Success
 	logs.V(3).Infof("[%s]recycle res(id: %s) success,  rid: %s", record.ResType, record.ID, kt.Rid)
 
 	logicsrecycle.MarkRecordSuccess(kt, r.client.DataService(), []string{record.ID})
+	return true
 }
 
 func (r *recycle) recycleDiskWorker(kt *kit.Kit, info *types.CloudResourceBasicInfo) error {
@@ -195,3 +257,24 @@ func (r *recycle) recycleCvmWorker(kt *kit.Kit, info *types.CloudResourceBasicIn
 	}
 	return nil
 }
+
+// genResRecycleWorker generate recycle worker of the resources recycled in the generic way.
+func (r *recycle) genResRecycleWorker(recycler logicsrecycle.ResRecycler) recycleWorker {
+	return func(kt *kit.Kit, info *types.CloudResourceBasicInfo) error {
+		ready, err := recycler.DestroyReady(kt, *info)
+		if err != nil {
+			logs.Errorf("check %s destroy ready failed, err: %v, id: %s, rid: %s", recycler.ResType(), err, info.ID,
+				kt.Rid)
+			return err
+		}
+		if !ready {
+			return errRecycleNotReady
+		}
+
+		if err = recycler.Destroy(kt, *info); err != nil {
+			logs.Errorf("destroy %s failed, err: %v, id: %s, rid: %s", recycler.ResType(), err, info.ID, kt.Rid)
+			return err
+		}
+		return nil
+	}
+}
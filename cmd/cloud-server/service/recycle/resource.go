@@ -0,0 +1,385 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recycle
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/logics/recycle"
+	csrecycle "hcm/pkg/api/cloud-server/recycle"
+	"hcm/pkg/api/core"
+	corerr "hcm/pkg/api/core/recycle-record"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	"hcm/pkg/api/data-service/cloud"
+	dsrr "hcm/pkg/api/data-service/recycle-record"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// resRecycleMeta 通用回收资源的元信息
+type resRecycleMeta struct {
+	resType   enumor.CloudResourceType
+	auditType enumor.AuditResourceType
+	authType  meta.ResourceType
+	// path 资源在路由中的名称
+	path string
+}
+
+var resRecycleMetas = []resRecycleMeta{
+	{resType: enumor.EipCloudResType, auditType: enumor.EipAuditResType, authType: meta.Eip, path: "eips"},
+	{resType: enumor.SecurityGroupCloudResType, auditType: enumor.SecurityGroupAuditResType,
+		authType: meta.SecurityGroup, path: "security_groups"},
+	{resType: enumor.LoadBalancerCloudResType, auditType: enumor.LoadBalancerAuditResType,
+		authType: meta.LoadBalancer, path: "load_balancers"},
+	{resType: enumor.VpcCloudResType, auditType: enumor.VpcCloudAuditResType, authType: meta.Vpc, path: "vpcs"},
+	{resType: enumor.SubnetCloudResType, auditType: enumor.SubnetAuditResType, authType: meta.Subnet,
+		path: "subnets"},
+}
+
+// initResRecycleService register the recycle, recover and destroy apis of the resources recycled in the generic way.
+func (svc *svc) initResRecycleService(h *rest.Handler) {
+	for _, m := range resRecycleMetas {
+		m := m
+		name := string(m.resType)
+
+		h.Add("Recycle_"+name, http.MethodPost, "/"+m.path+"/recycle",
+			func(cts *rest.Contexts) (interface{}, error) { return svc.recycleRes(cts, m, handler.ResOperateAuth) })
+		h.Add("RecycleBiz_"+name, http.MethodPost, "/bizs/{bk_biz_id}/"+m.path+"/recycle",
+			func(cts *rest.Contexts) (interface{}, error) { return svc.recycleRes(cts, m, handler.BizOperateAuth) })
+
+		h.Add("Recover_"+name, http.MethodPost, "/"+m.path+"/recover",
+			func(cts *rest.Contexts) (interface{}, error) { return svc.recoverRes(cts, m, handler.ResOperateAuth) })
+		h.Add("RecoverBiz_"+name, http.MethodPost, "/bizs/{bk_biz_id}/"+m.path+"/recover",
+			func(cts *rest.Contexts) (interface{}, error) { return svc.recoverRes(cts, m, handler.BizOperateAuth) })
+
+		h.Add("BatchDeleteRecycled_"+name, http.MethodDelete, "/recycled/"+m.path+"/batch",
+			func(cts *rest.Contexts) (interface{}, error) {
+				return svc.batchDeleteRecycledRes(cts, m, handler.ResOperateAuth)
+			})
+		h.Add("BatchDeleteBizRecycled_"+name, http.MethodDelete, "/bizs/{bk_biz_id}/recycled/"+m.path+"/batch",
+			func(cts *rest.Contexts) (interface{}, error) {
+				return svc.batchDeleteRecycledRes(cts, m, handler.BizOperateAuth)
+			})
+	}
+}
+
+func (svc *svc) listResBasicInfo(cts *rest.Contexts, resType enumor.CloudResourceType, ids []string) (
+	map[string]types.CloudResourceBasicInfo, error) {
+
+	basicInfoReq := cloud.ListResourceBasicInfoReq{
+		ResourceType: resType,
+		IDs:          ids,
+		Fields:       append(types.ResWithRecycleBasicFields, "region"),
+	}
+	return svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+}
+
+func (svc *svc) recycleRes(cts *rest.Contexts, m resRecycleMeta, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(csrecycle.ResRecycleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	basicInfoMap, err := svc.listResBasicInfo(cts, m.resType, req.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: m.authType,
+		Action: meta.Recycle, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.recycleResByIDs(cts.Kit, m, req.IDs, basicInfoMap)
+}
+
+// recycleResByIDs 记录回收审计，预处理资源后创建回收任务，调用方需自行完成鉴权
+func (svc *svc) recycleResByIDs(kt *kit.Kit, m resRecycleMeta, ids []string,
+	basicInfoMap map[string]types.CloudResourceBasicInfo) (interface{}, error) {
+
+	// create recycle audit
+	auditInfos := make([]protoaudit.CloudResRecycleAuditInfo, 0, len(ids))
+	for _, id := range ids {
+		info := basicInfoMap[id]
+		auditInfos = append(auditInfos, protoaudit.CloudResRecycleAuditInfo{ResID: id, Data: &info})
+	}
+	auditReq := &protoaudit.CloudResourceRecycleAuditReq{
+		ResType: m.auditType,
+		Action:  protoaudit.Recycle,
+		Infos:   auditInfos,
+	}
+	if err := svc.audit.ResRecycleAudit(kt, auditReq); err != nil {
+		logs.Errorf("create recycle audit failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	recycler := svc.recyclers[m.resType]
+	res := new(csrecycle.ResRecycleResult)
+	opt := &dsrr.BatchRecycleReq{
+		ResType:            m.resType,
+		DefaultRecycleTime: cc.CloudServer().Recycle.AutoDeleteTime,
+		Infos:              make([]dsrr.RecycleReq, 0, len(ids)),
+	}
+	details := make(map[string]interface{}, len(ids))
+	// soft delete resources before putting them into recycle bin
+	for _, id := range ids {
+		info := basicInfoMap[id]
+		detail, err := recycler.Prepare(kt, info)
+		if err != nil {
+			logs.Errorf("prepare recycle %s(%s) failed, err: %v, rid: %s", m.resType, id, err, kt.Rid)
+			svc.rollbackPrepare(kt, recycler, info, detail)
+			res.Failed = append(res.Failed, core.FailedInfo{ID: id, Error: err})
+			continue
+		}
+		details[id] = detail
+		opt.Infos = append(opt.Infos, dsrr.RecycleReq{ID: id, Detail: detail})
+	}
+
+	if len(opt.Infos) == 0 {
+		return res, res.Failed[0].Error
+	}
+
+	taskID, err := svc.client.DataService().Global.RecycleRecord.BatchRecycleCloudRes(kt, opt)
+	if err != nil {
+		for _, one := range opt.Infos {
+			svc.rollbackPrepare(kt, recycler, basicInfoMap[one.ID], details[one.ID])
+			res.Failed = append(res.Failed, core.FailedInfo{ID: one.ID, Error: err})
+		}
+		return res, err
+	}
+
+	// the succeeded resources are already in recycle bin, return the task id with the failed ones.
+	res.TaskID = taskID
+	for _, one := range opt.Infos {
+		res.Succeeded = append(res.Succeeded, one.ID)
+	}
+
+	if len(res.Failed) > 0 {
+		return res, res.Failed[0].Error
+	}
+	return res, nil
+}
+
+// rollbackPrepare restore the resource which is failed to be put into recycle bin.
+func (svc *svc) rollbackPrepare(kt *kit.Kit, recycler logicsrecycle.ResRecycler,
+	info types.CloudResourceBasicInfo, detail interface{}) {
+
+	if detail == nil {
+		return
+	}
+
+	if err := recycler.Restore(kt, info, detail); err != nil {
+		logs.Errorf("rollback recycle prepare of %s(%s) failed, err: %v, detail: %+v, rid: %s",
+			recycler.ResType(), info.ID, err, detail, kt.Rid)
+	}
+}
+
+// listRecycleRecords list and validate the recycle records, only the waiting records in the same task can be
+// operated at the same time.
+func (svc *svc) listRecycleRecords(cts *rest.Contexts, resType enumor.CloudResourceType, recordIDs []string) (
+	[]corerr.RecycleRecord, error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", recordIDs),
+		Page:   &core.BasePage{Limit: constant.BatchOperationMaxLimit},
+	}
+	records, err := svc.client.DataService().Global.RecycleRecord.ListRecycleRecord(cts.Kit, listReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records.Details) != len(recordIDs) {
+		return nil, errf.New(errf.InvalidParameter, "some record_ids are not in recycle bin")
+	}
+
+	taskID := ""
+	for _, one := range records.Details {
+		if len(taskID) == 0 {
+			taskID = one.TaskID
+		} else if taskID != one.TaskID {
+			return nil, errf.Newf(errf.InvalidParameter, "only %s in one task can be reclaimed at the same time",
+				resType)
+		}
+
+		if one.Status != enumor.WaitingRecycleRecordStatus {
+			return nil, errf.Newf(errf.InvalidParameter, "record: %s not is wait_recycle status", one.ID)
+		}
+
+		if one.ResType != resType {
+			return nil, errf.Newf(errf.InvalidParameter, "record: %s not is %s recycle record", one.ID, resType)
+		}
+
+		if one.RecycleType == enumor.RecycleTypeRelated {
+			return nil, errf.Newf(errf.InvalidParameter, "related recycled %s(%s) can not be operated", resType,
+				one.ResID)
+		}
+	}
+
+	return records.Details, nil
+}
+
+func (svc *svc) recoverRes(cts *rest.Contexts, m resRecycleMeta, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(csrecycle.ResRecoverReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	records, err := svc.listRecycleRecords(cts, m.resType, req.RecordIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resIDs := make([]string, 0, len(records))
+	auditInfos := make([]protoaudit.CloudResRecycleAuditInfo, 0, len(records))
+	for _, record := range records {
+		resIDs = append(resIDs, record.ResID)
+		auditInfos = append(auditInfos, protoaudit.CloudResRecycleAuditInfo{ResID: record.ResID, Data: record.Detail})
+	}
+
+	basicInfoMap, err := svc.listResBasicInfo(cts, m.resType, resIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: m.authType,
+		Action: meta.Recover, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, err
+	}
+
+	// create recover audit
+	auditReq := &protoaudit.CloudResourceRecycleAuditReq{
+		ResType: m.auditType,
+		Action:  protoaudit.Recover,
+		Infos:   auditInfos,
+	}
+	if err = svc.audit.ResRecycleAudit(cts.Kit, auditReq); err != nil {
+		logs.Errorf("create recover audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	// take resources out of recycle bin, then restore the relations of resources released when recycling
+	opt := &dsrr.BatchRecoverReq{
+		ResType:   m.resType,
+		RecordIDs: req.RecordIDs,
+	}
+	recoverRecords := func() error {
+		return svc.client.DataService().Global.RecycleRecord.BatchRecoverCloudResource(cts.Kit, opt)
+	}
+	if err = logicsrecycle.RecoverRes(cts.Kit, svc.recyclers[m.resType], basicInfoMap, records,
+		recoverRecords); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (svc *svc) batchDeleteRecycledRes(cts *rest.Contexts, m resRecycleMeta,
+	validHandler handler.ValidWithAuthHandler) (interface{}, error) {
+
+	req := new(csrecycle.ResDeleteRecycleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	records, err := svc.listRecycleRecords(cts, m.resType, req.RecordIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	opRet := new(core.BatchOperateResult)
+	var recycleErr error
+	for _, record := range records {
+		recycleErr = svc.destroyOneRecord(cts, m, validHandler, record)
+		if recycleErr != nil {
+			logs.Errorf("fail to destroy %s recycle record(%s), err: %v, rid:%s", m.resType, record.ID, recycleErr,
+				cts.Kit.Rid)
+
+			opRet.Failed = &core.FailedInfo{ID: record.ID, Error: recycleErr}
+			if ef := errf.Error(recycleErr); ef != nil && ef.Code == errf.RecordNotFound {
+				logicsrecycle.MarkRecordFailed(cts.Kit, svc.client.DataService(), recycleErr, []string{record.ID})
+			}
+			break
+		}
+		opRet.Succeeded = append(opRet.Succeeded, record.ID)
+	}
+
+	if len(opRet.Succeeded) > 0 {
+		logicsrecycle.MarkRecordSuccess(cts.Kit, svc.client.DataService(), opRet.Succeeded)
+	}
+	return opRet, recycleErr
+}
+
+func (svc *svc) destroyOneRecord(cts *rest.Contexts, m resRecycleMeta, validHandler handler.ValidWithAuthHandler,
+	record corerr.RecycleRecord) error {
+
+	basicInfoMap, err := svc.listResBasicInfo(cts, m.resType, []string{record.ResID})
+	if err != nil {
+		return err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: m.authType,
+		Action: meta.Destroy, BasicInfos: basicInfoMap})
+	if err != nil {
+		return err
+	}
+
+	recycler := svc.recyclers[m.resType]
+	info := basicInfoMap[record.ResID]
+	ready, err := recycler.DestroyReady(cts.Kit, info)
+	if err != nil {
+		return err
+	}
+	if !ready {
+		return errf.Newf(errf.InvalidParameter, "%s(%s) can not be destroyed now, the dependent resources are not "+
+			"destroyed yet", m.resType, record.ResID)
+	}
+
+	return recycler.Destroy(cts.Kit, info)
+}
@@ -22,8 +22,14 @@ package recycle
 import (
 	"net/http"
 
+	"hcm/cmd/cloud-server/logics/audit"
+	"hcm/cmd/cloud-server/logics/cvm"
+	"hcm/cmd/cloud-server/logics/disk"
+	logicsorphan "hcm/cmd/cloud-server/logics/orphan"
+	"hcm/cmd/cloud-server/logics/recycle"
 	"hcm/cmd/cloud-server/service/capability"
 	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/iam/auth"
 	"hcm/pkg/rest"
 )
@@ -33,6 +39,11 @@ func InitService(c *capability.Capability) {
 	svc := &svc{
 		client:     c.ApiClient,
 		authorizer: c.Authorizer,
+		audit:      c.Audit,
+		recyclers:  logicsrecycle.NewResRecyclers(c.ApiClient, c.Audit, c.Logics.Eip),
+		cvmLgc:     c.Logics.Cvm,
+		diskLgc:    c.Logics.Disk,
+		orphanLgc:  logicsorphan.NewOrphan(c.ApiClient),
 	}
 
 	h := rest.NewHandler()
@@ -40,10 +51,19 @@ func InitService(c *capability.Capability) {
 	h.Add("ListRecycleRecord", http.MethodPost, "/recycle_records/list", svc.ListRecycleRecord)
 	h.Add("ListBizRecycleRecord", http.MethodPost, "/bizs/{bk_biz_id}/recycle_records/list", svc.ListBizRecycleRecord)
 
+	svc.initResRecycleService(h)
+	svc.initRecyclePolicyService(h)
+	svc.initOrphanService(h)
+
 	h.Load(c.WebService)
 }
 
 type svc struct {
 	client     *client.ClientSet
 	authorizer auth.Authorizer
+	audit      audit.Interface
+	recyclers  map[enumor.CloudResourceType]logicsrecycle.ResRecycler
+	cvmLgc     cvm.Interface
+	diskLgc    disk.Interface
+	orphanLgc  logicsorphan.Interface
 }
@@ -0,0 +1,164 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package renewal
+
+import (
+	"time"
+
+	logicsrenewal "hcm/cmd/cloud-server/logics/renewal"
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// ListExpiringPrepaidRes list expiring prepaid resources.
+func (svc *renewalSvc) ListExpiringPrepaidRes(cts *rest.Contexts) (interface{}, error) {
+	return svc.listExpiringPrepaidRes(cts, false)
+}
+
+// ListBizExpiringPrepaidRes list expiring prepaid resources of the biz.
+func (svc *renewalSvc) ListBizExpiringPrepaidRes(cts *rest.Contexts) (interface{}, error) {
+	return svc.listExpiringPrepaidRes(cts, true)
+}
+
+func (svc *renewalSvc) listExpiringPrepaidRes(cts *rest.Contexts, isBiz bool) (interface{}, error) {
+	req := new(cloudserver.ExpiringResListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	expr := tools.AllExpression()
+	if len(req.AccountIDs) != 0 {
+		expr = tools.ContainersExpression("account_id", req.AccountIDs)
+	}
+
+	expr, noPermFlag, err := svc.authExpiringFilter(cts, isBiz, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &cloudserver.ExpiringResListResult{Details: make([]cloudserver.ExpiringRes, 0)}, nil
+	}
+
+	opt := &logicsrenewal.ListExpiringOption{
+		ResType:     req.ResType,
+		Filter:      expr,
+		AdvanceDays: req.AdvanceDays,
+		Now:         time.Now(),
+	}
+	if !isBiz && (len(req.ResType) == 0 || req.ResType == enumor.BandwidthPackageCloudResType) {
+		if opt.BwpAccountIDs, err = svc.listBwpAccountIDs(cts, req.AccountIDs); err != nil {
+			return nil, err
+		}
+	}
+	details, err := logicsrenewal.NewRenewal(svc.client).ListExpiringRes(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list expiring prepaid resource failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &cloudserver.ExpiringResListResult{Details: details}, nil
+}
+
+// authExpiringFilter 资源下接口按资源所属账号鉴权，业务下接口仅返回业务下的资源
+func (svc *renewalSvc) authExpiringFilter(cts *rest.Contexts, isBiz bool, expr *filter.Expression) (
+	*filter.Expression, bool, error) {
+
+	if !isBiz {
+		return handler.ListResourceAuthRes(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+			ResType: meta.Account, Action: meta.Find, Filter: expr})
+	}
+
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, false, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	if bizID <= 0 {
+		return nil, false, errf.New(errf.InvalidParameter, "bk_biz_id is invalid")
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Biz, Action: meta.Access}, BizID: bizID}
+	if err = svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, false, err
+	}
+
+	bizExpr, err := tools.And(expr, tools.EqualExpression("bk_biz_id", bizID))
+	if err != nil {
+		return nil, false, err
+	}
+
+	return bizExpr, false, nil
+}
+
+// listBwpAccountIDs 带宽包不属于任何业务，仅资源下接口返回，查询有权限的华为云账号，目前仅华为云支持查询带宽包到期时间
+func (svc *renewalSvc) listBwpAccountIDs(cts *rest.Contexts, accountIDs []string) ([]string, error) {
+	expr := tools.EqualExpression("vendor", enumor.HuaWei)
+	if len(accountIDs) != 0 {
+		var err error
+		expr, err = tools.And(expr, tools.ContainersExpression("id", accountIDs))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	authOpt := &meta.ListAuthResInput{Type: meta.Account, Action: meta.Find}
+	expr, noPermFlag, err := svc.authorizer.ListAuthInstWithFilter(cts.Kit, authOpt, expr, "id")
+	if err != nil {
+		return nil, err
+	}
+	if noPermFlag {
+		return make([]string, 0), nil
+	}
+
+	result := make([]string, 0)
+	page := core.NewDefaultBasePage()
+	for {
+		listReq := &dataproto.AccountListReq{Filter: expr, Page: page}
+		accounts, err := svc.client.DataService().Global.Account.List(cts.Kit.Ctx, cts.Kit.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list huawei account failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+
+		for _, one := range accounts.Details {
+			result = append(result, one.ID)
+		}
+
+		if uint(len(accounts.Details)) < page.Limit {
+			break
+		}
+		page.Start += uint32(page.Limit)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,62 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package renewal ...
+package renewal
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/logics/audit"
+	"hcm/cmd/cloud-server/service/capability"
+	"hcm/pkg/client"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/rest"
+)
+
+// InitService initial the prepaid resource renewal service
+func InitService(c *capability.Capability) {
+	svc := &renewalSvc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+		audit:      c.Audit,
+	}
+
+	h := rest.NewHandler()
+
+	// 资源下包年包月资源到期及续费相关接口
+	h.Add("ListExpiringPrepaidRes", http.MethodPost, "/prepaid_resources/expiring/list", svc.ListExpiringPrepaidRes)
+	h.Add("RenewPrepaidRes", http.MethodPost, "/prepaid_resources/renew", svc.RenewPrepaidRes)
+	h.Add("SetPrepaidResAutoRenew", http.MethodPost, "/prepaid_resources/auto_renew/set", svc.SetPrepaidResAutoRenew)
+
+	// 业务下包年包月资源到期及续费相关接口
+	h.Add("ListBizExpiringPrepaidRes", http.MethodPost, "/bizs/{bk_biz_id}/prepaid_resources/expiring/list",
+		svc.ListBizExpiringPrepaidRes)
+	h.Add("RenewBizPrepaidRes", http.MethodPost, "/bizs/{bk_biz_id}/prepaid_resources/renew", svc.RenewBizPrepaidRes)
+	h.Add("SetBizPrepaidResAutoRenew", http.MethodPost, "/bizs/{bk_biz_id}/prepaid_resources/auto_renew/set",
+		svc.SetBizPrepaidResAutoRenew)
+
+	h.Load(c.WebService)
+}
+
+type renewalSvc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+	audit      audit.Interface
+}
@@ -0,0 +1,295 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package renewal
+
+import (
+	"fmt"
+	"sort"
+
+	actionrenewal "hcm/cmd/task-server/logics/action/renewal"
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/counter"
+	"hcm/pkg/tools/hooks/handler"
+	"hcm/pkg/tools/slice"
+)
+
+// RenewPrepaidRes renew prepaid resources.
+func (svc *renewalSvc) RenewPrepaidRes(cts *rest.Contexts) (interface{}, error) {
+	return svc.renewPrepaidRes(cts, handler.ResOperateAuth, false)
+}
+
+// RenewBizPrepaidRes renew biz prepaid resources.
+func (svc *renewalSvc) RenewBizPrepaidRes(cts *rest.Contexts) (interface{}, error) {
+	return svc.renewPrepaidRes(cts, handler.BizOperateAuth, true)
+}
+
+func (svc *renewalSvc) renewPrepaidRes(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler,
+	isBiz bool) (interface{}, error) {
+
+	req := new(cloudserver.PrepaidResRenewReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	var accountInfos [][]types.CloudResourceBasicInfo
+	var err error
+	if req.ResType == enumor.BandwidthPackageCloudResType {
+		accountInfos, err = svc.validateBwpAndAudit(cts, isBiz, req.AccountID, req.IDs, protoaudit.Renew)
+	} else {
+		accountInfos, err = svc.validateAndAudit(cts, validHandler, req.ResType, req.IDs, protoaudit.Renew)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]ts.CustomFlowTask, 0, len(accountInfos))
+	nextID := counter.NewNumStringCounter(1, 10)
+	for _, infos := range accountInfos {
+		tasks = append(tasks, ts.CustomFlowTask{
+			ActionID:   action.ActIDType(nextID()),
+			ActionName: enumor.ActionRenewPrepaidRes,
+			Params: &actionrenewal.RenewPrepaidResOption{
+				Vendor:    infos[0].Vendor,
+				AccountID: infos[0].AccountID,
+				ResType:   req.ResType,
+				ResIDs:    basicInfoIDs(infos),
+				Period:    req.Period,
+			},
+		})
+	}
+
+	return svc.createFlow(cts, enumor.FlowRenewPrepaidRes, tasks)
+}
+
+// SetPrepaidResAutoRenew set prepaid resources auto renew.
+func (svc *renewalSvc) SetPrepaidResAutoRenew(cts *rest.Contexts) (interface{}, error) {
+	return svc.setPrepaidResAutoRenew(cts, handler.ResOperateAuth, false)
+}
+
+// SetBizPrepaidResAutoRenew set biz prepaid resources auto renew.
+func (svc *renewalSvc) SetBizPrepaidResAutoRenew(cts *rest.Contexts) (interface{}, error) {
+	return svc.setPrepaidResAutoRenew(cts, handler.BizOperateAuth, true)
+}
+
+func (svc *renewalSvc) setPrepaidResAutoRenew(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler,
+	isBiz bool) (interface{}, error) {
+
+	req := new(cloudserver.PrepaidResAutoRenewReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	var accountInfos [][]types.CloudResourceBasicInfo
+	var err error
+	if req.ResType == enumor.BandwidthPackageCloudResType {
+		accountInfos, err = svc.validateBwpAndAudit(cts, isBiz, req.AccountID, req.IDs, protoaudit.SetAutoRenew)
+	} else {
+		accountInfos, err = svc.validateAndAudit(cts, validHandler, req.ResType, req.IDs, protoaudit.SetAutoRenew)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]ts.CustomFlowTask, 0, len(accountInfos))
+	nextID := counter.NewNumStringCounter(1, 10)
+	for _, infos := range accountInfos {
+		tasks = append(tasks, ts.CustomFlowTask{
+			ActionID:   action.ActIDType(nextID()),
+			ActionName: enumor.ActionSetPrepaidResAutoRenew,
+			Params: &actionrenewal.SetPrepaidResAutoRenewOption{
+				Vendor:    infos[0].Vendor,
+				AccountID: infos[0].AccountID,
+				ResType:   req.ResType,
+				ResIDs:    basicInfoIDs(infos),
+				AutoRenew: *req.AutoRenew,
+			},
+		})
+	}
+
+	return svc.createFlow(cts, enumor.FlowSetPrepaidResAutoRenew, tasks)
+}
+
+// validateAndAudit 校验资源所属业务并鉴权、记录操作审计，返回按账号分组的资源基础信息
+func (svc *renewalSvc) validateAndAudit(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler,
+	resType enumor.CloudResourceType, ids []string, auditAction protoaudit.OperationAction) (
+	[][]types.CloudResourceBasicInfo, error) {
+
+	basicInfoReq := dataproto.ListResourceBasicInfoReq{
+		ResourceType: resType,
+		IDs:          ids,
+	}
+	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		logs.Errorf("list %s basic info failed, err: %v, ids: %v, rid: %s", resType, err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	authResType, auditResType := meta.Cvm, enumor.CvmAuditResType
+	if resType == enumor.DiskCloudResType {
+		authResType, auditResType = meta.Disk, enumor.DiskAuditResType
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: authResType,
+		Action: meta.Update, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, err
+	}
+
+	accountMap := make(map[string][]types.CloudResourceBasicInfo)
+	for _, info := range basicInfoMap {
+		switch info.Vendor {
+		case enumor.TCloud, enumor.HuaWei:
+		default:
+			return nil, errf.NewFromErr(errf.InvalidParameter,
+				fmt.Errorf("%s(id=%s) of vendor %s does not support renewal", resType, info.ID, info.Vendor))
+		}
+		accountMap[info.AccountID] = append(accountMap[info.AccountID], info)
+	}
+
+	if err = svc.audit.ResBaseOperationAudit(cts.Kit, auditResType, auditAction, ids); err != nil {
+		logs.Errorf("create %s operation audit failed, err: %v, rid: %s", auditAction, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	accountIDs := make([]string, 0, len(accountMap))
+	for accountID := range accountMap {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Strings(accountIDs)
+
+	accountInfos := make([][]types.CloudResourceBasicInfo, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		accountInfos = append(accountInfos, accountMap[accountID])
+	}
+
+	return accountInfos, nil
+}
+
+// validateBwpAndAudit 带宽包未纳入hcm资源管理，不属于任何业务，需要带宽包所属账号的编辑权限，资源ID为带宽包的云上ID。
+// 目前仅华为云支持，腾讯云未提供带宽包续费接口
+func (svc *renewalSvc) validateBwpAndAudit(cts *rest.Contexts, isBiz bool, accountID string, cloudIDs []string,
+	auditAction protoaudit.OperationAction) ([][]types.CloudResourceBasicInfo, error) {
+
+	if isBiz {
+		return nil, errf.New(errf.InvalidParameter, "bandwidth package does not belong to biz, please operate it "+
+			"in resource management")
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Account, Action: meta.Update,
+		ResourceID: accountID}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	listReq := &dataproto.AccountListReq{
+		Filter: tools.EqualExpression("id", accountID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	accounts, err := svc.client.DataService().Global.Account.List(cts.Kit.Ctx, cts.Kit.Header(), listReq)
+	if err != nil {
+		logs.Errorf("list account failed, err: %v, id: %s, rid: %s", err, accountID, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(accounts.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "account %s not found", accountID)
+	}
+
+	vendor := accounts.Details[0].Vendor
+	switch vendor {
+	case enumor.HuaWei:
+	case enumor.TCloud:
+		return nil, errf.New(errf.InvalidParameter, "tcloud does not provide api to renew bandwidth package, "+
+			"please renew it in the tcloud console")
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "bandwidth package of vendor %s does not support renewal",
+			vendor)
+	}
+
+	auditReq := &protoaudit.CloudResourceOperationAuditReq{
+		Operations: make([]protoaudit.CloudResourceOperationInfo, 0, len(cloudIDs)),
+	}
+	infos := make([]types.CloudResourceBasicInfo, 0, len(cloudIDs))
+	for _, cloudID := range slice.Unique(cloudIDs) {
+		auditReq.Operations = append(auditReq.Operations, protoaudit.CloudResourceOperationInfo{
+			ResType:           enumor.BandwidthPackageAuditResType,
+			ResID:             cloudID,
+			Action:            auditAction,
+			AssociatedResType: enumor.AccountAuditResType,
+			AssociatedResID:   accountID,
+		})
+		infos = append(infos, types.CloudResourceBasicInfo{ID: cloudID, CloudID: cloudID, Vendor: vendor,
+			AccountID: accountID})
+	}
+
+	err = svc.client.DataService().Global.Audit.CloudResourceOperationAudit(cts.Kit.Ctx, cts.Kit.Header(), auditReq)
+	if err != nil {
+		logs.Errorf("create bandwidth package %s audit failed, err: %v, rid: %s", auditAction, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return [][]types.CloudResourceBasicInfo{infos}, nil
+}
+
+// createFlow 续费类操作耗时较长，异步执行，直接返回任务流ID
+func (svc *renewalSvc) createFlow(cts *rest.Contexts, flowName enumor.FlowName, tasks []ts.CustomFlowTask) (
+	interface{}, error) {
+
+	flowReq := &ts.AddCustomFlowReq{
+		Name:  flowName,
+		Tasks: tasks,
+	}
+	result, err := svc.client.TaskServer().CreateCustomFlow(cts.Kit, flowReq)
+	if err != nil {
+		logs.Errorf("call taskserver to create %s flow failed, err: %v, rid: %s", flowName, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func basicInfoIDs(infos []types.CloudResourceBasicInfo) []string {
+	ids := make([]string, 0, len(infos))
+	for _, info := range infos {
+		ids = append(ids, info.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
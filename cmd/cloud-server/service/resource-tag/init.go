@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package restag ...
+package restag
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/service/capability"
+	"hcm/pkg/client"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/rest"
+)
+
+// InitService initial the resource tag service
+func InitService(c *capability.Capability) {
+	svc := &resTagSvc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+	}
+
+	h := rest.NewHandler()
+
+	// 资源下资源标签相关接口
+	h.Add("AddResourceTags", http.MethodPost, "/resource_tags/add", svc.AddResourceTags)
+	h.Add("RemoveResourceTags", http.MethodPost, "/resource_tags/remove", svc.RemoveResourceTags)
+	h.Add("ListResourceTags", http.MethodPost, "/resource_tags/list", svc.ListResourceTags)
+
+	// 业务下资源标签相关接口
+	h.Add("AddBizResourceTags", http.MethodPost, "/bizs/{bk_biz_id}/resource_tags/add", svc.AddBizResourceTags)
+	h.Add("RemoveBizResourceTags", http.MethodPost, "/bizs/{bk_biz_id}/resource_tags/remove",
+		svc.RemoveBizResourceTags)
+
+	h.Load(c.WebService)
+}
+
+type resTagSvc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+}
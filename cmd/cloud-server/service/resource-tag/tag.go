@@ -0,0 +1,252 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package restag
+
+import (
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	hcrestag "hcm/pkg/api/hc-service/resource-tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// tagAuthResTypes 支持标签写回的资源类型及其对应的鉴权资源类型
+var tagAuthResTypes = map[enumor.CloudResourceType]meta.ResourceType{
+	enumor.CvmCloudResType:           meta.Cvm,
+	enumor.DiskCloudResType:          meta.Disk,
+	enumor.SecurityGroupCloudResType: meta.SecurityGroup,
+	enumor.EipCloudResType:           meta.Eip,
+	enumor.VpcCloudResType:           meta.Vpc,
+	enumor.LoadBalancerCloudResType:  meta.LoadBalancer,
+}
+
+// AddResourceTags add resource tags.
+func (svc *resTagSvc) AddResourceTags(cts *rest.Contexts) (interface{}, error) {
+	return svc.addResourceTags(cts, handler.ResOperateAuth)
+}
+
+// AddBizResourceTags add biz resource tags.
+func (svc *resTagSvc) AddBizResourceTags(cts *rest.Contexts) (interface{}, error) {
+	return svc.addResourceTags(cts, handler.BizOperateAuth)
+}
+
+func (svc *resTagSvc) addResourceTags(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(cloudserver.ResourceTagAddReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	accountInfos, err := svc.authAndGroupByAccount(cts, validHandler, req.ResType, req.ResIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for accountID, infos := range accountInfos {
+		hcReq := &hcrestag.ResTagAddReq{
+			AccountID: accountID,
+			ResType:   req.ResType,
+			ResIDs:    resIDs(infos),
+			Tags:      req.Tags,
+		}
+
+		switch vendor := infos[0].Vendor; vendor {
+		case enumor.TCloud:
+			err = svc.client.HCService().TCloud.ResourceTag.Add(cts.Kit, hcReq)
+		case enumor.Aws:
+			err = svc.client.HCService().Aws.ResourceTag.Add(cts.Kit, hcReq)
+		case enumor.HuaWei:
+			err = svc.client.HCService().HuaWei.ResourceTag.Add(cts.Kit, hcReq)
+		case enumor.Gcp:
+			err = svc.client.HCService().Gcp.ResourceTag.Add(cts.Kit, hcReq)
+		case enumor.Azure:
+			err = svc.client.HCService().Azure.ResourceTag.Add(cts.Kit, hcReq)
+		default:
+			return nil, errf.Newf(errf.Unknown, "vendor: %s not support", vendor)
+		}
+		if err != nil {
+			logs.Errorf("add resource tags failed, err: %v, req: %+v, rid: %s", err, hcReq, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// RemoveResourceTags remove resource tags.
+func (svc *resTagSvc) RemoveResourceTags(cts *rest.Contexts) (interface{}, error) {
+	return svc.removeResourceTags(cts, handler.ResOperateAuth)
+}
+
+// RemoveBizResourceTags remove biz resource tags.
+func (svc *resTagSvc) RemoveBizResourceTags(cts *rest.Contexts) (interface{}, error) {
+	return svc.removeResourceTags(cts, handler.BizOperateAuth)
+}
+
+func (svc *resTagSvc) removeResourceTags(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(cloudserver.ResourceTagRemoveReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	accountInfos, err := svc.authAndGroupByAccount(cts, validHandler, req.ResType, req.ResIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for accountID, infos := range accountInfos {
+		hcReq := &hcrestag.ResTagRemoveReq{
+			AccountID: accountID,
+			ResType:   req.ResType,
+			ResIDs:    resIDs(infos),
+			Keys:      req.Keys,
+		}
+
+		switch vendor := infos[0].Vendor; vendor {
+		case enumor.TCloud:
+			err = svc.client.HCService().TCloud.ResourceTag.Remove(cts.Kit, hcReq)
+		case enumor.Aws:
+			err = svc.client.HCService().Aws.ResourceTag.Remove(cts.Kit, hcReq)
+		case enumor.HuaWei:
+			err = svc.client.HCService().HuaWei.ResourceTag.Remove(cts.Kit, hcReq)
+		case enumor.Gcp:
+			err = svc.client.HCService().Gcp.ResourceTag.Remove(cts.Kit, hcReq)
+		case enumor.Azure:
+			err = svc.client.HCService().Azure.ResourceTag.Remove(cts.Kit, hcReq)
+		default:
+			return nil, errf.Newf(errf.Unknown, "vendor: %s not support", vendor)
+		}
+		if err != nil {
+			logs.Errorf("remove resource tags failed, err: %v, req: %+v, rid: %s", err, hcReq, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// authAndGroupByAccount 鉴权并校验资源分配状态和回收状态，然后将资源按账号分组
+func (svc *resTagSvc) authAndGroupByAccount(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler,
+	resType enumor.CloudResourceType, ids []string) (map[string][]types.CloudResourceBasicInfo, error) {
+
+	authResType, exists := tagAuthResTypes[resType]
+	if !exists {
+		return nil, errf.Newf(errf.InvalidParameter, "resource type %s does not support tag", resType)
+	}
+
+	basicInfos, err := svc.listResBasicInfo(cts.Kit, resType, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: authResType,
+		Action: meta.Update, BasicInfos: basicInfos})
+	if err != nil {
+		return nil, err
+	}
+
+	accountInfos := make(map[string][]types.CloudResourceBasicInfo)
+	for _, info := range basicInfos {
+		accountInfos[info.AccountID] = append(accountInfos[info.AccountID], info)
+	}
+
+	return accountInfos, nil
+}
+
+func (svc *resTagSvc) listResBasicInfo(kt *kit.Kit, resType enumor.CloudResourceType, ids []string) (
+	map[string]types.CloudResourceBasicInfo, error) {
+
+	basicReq := protocloud.ListResourceBasicInfoReq{
+		ResourceType: resType,
+		IDs:          ids,
+		Fields:       types.ResWithRecycleBasicFields,
+	}
+	basicInfos, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(kt, basicReq)
+	if err != nil {
+		logs.Errorf("list resource basic info failed, err: %v, req: %+v, rid: %s", err, basicReq, kt.Rid)
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if _, exists := basicInfos[id]; !exists {
+			return nil, errf.Newf(errf.RecordNotFound, "%s %s not found", resType, id)
+		}
+	}
+
+	return basicInfos, nil
+}
+
+func resIDs(infos []types.CloudResourceBasicInfo) []string {
+	ids := make([]string, 0, len(infos))
+	for _, info := range infos {
+		ids = append(ids, info.ID)
+	}
+	return ids
+}
+
+// ListResourceTags list resource tags.
+func (svc *resTagSvc) ListResourceTags(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 标签随资源所属账号鉴权，有账号的资源查看权限即可查看该账号下资源的标签
+	expr, noPermFlag, err := handler.ListResourceAuthRes(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.Account, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &core.ListResult{Count: 0, Details: make([]interface{}, 0)}, nil
+	}
+	req.Filter = expr
+
+	result, err := svc.client.DataService().Global.ResourceTag.List(cts.Kit, req)
+	if err != nil {
+		logs.Errorf("list resource tags failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,54 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package rightsizing ...
+package rightsizing
+
+import (
+	"net/http"
+
+	cvmlgc "hcm/cmd/cloud-server/logics/cvm"
+	"hcm/cmd/cloud-server/service/capability"
+	"hcm/pkg/client"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/rest"
+)
+
+// InitService initial the cvm rightsizing service
+func InitService(c *capability.Capability) {
+	svc := &rightsizingSvc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+		cvmLgc:     c.Logics.Cvm,
+	}
+
+	h := rest.NewHandler()
+
+	// 业务下主机规格优化建议相关接口
+	h.Add("ListBizRightsizingRecommendation", http.MethodPost,
+		"/bizs/{bk_biz_id}/cvms/rightsizing/recommendations/list", svc.ListBizRightsizingRecommendation)
+
+	h.Load(c.WebService)
+}
+
+type rightsizingSvc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+	cvmLgc     cvmlgc.Interface
+}
@@ -0,0 +1,91 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package rightsizing
+
+import (
+	"time"
+
+	logicsrightsizing "hcm/cmd/cloud-server/logics/rightsizing"
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+)
+
+// ListBizRightsizingRecommendation list cvm rightsizing recommendations of the biz.
+func (svc *rightsizingSvc) ListBizRightsizingRecommendation(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	if bizID <= 0 {
+		return nil, errf.New(errf.InvalidParameter, "bk_biz_id is invalid")
+	}
+
+	req := new(cloudserver.RightsizingRecommendListReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Biz, Action: meta.Access}, BizID: bizID}
+	if err = svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	rules := make([]*filter.AtomRule, 0)
+	if len(req.AccountIDs) != 0 {
+		rules = append(rules, tools.RuleIn("account_id", req.AccountIDs))
+	}
+	if len(req.CvmIDs) != 0 {
+		rules = append(rules, tools.RuleIn("id", req.CvmIDs))
+	}
+
+	// 未指定账单月份时使用上个月的账单估算成本
+	billYear, billMonth := req.BillYear, req.BillMonth
+	if billYear == 0 {
+		now := time.Now()
+		lastMonth := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, now.Location())
+		billYear, billMonth = lastMonth.Year(), int(lastMonth.Month())
+	}
+
+	opt := &logicsrightsizing.ListRecommendationOption{
+		BkBizID:   bizID,
+		Filter:    tools.ExpressionAnd(rules...),
+		BillYear:  billYear,
+		BillMonth: billMonth,
+		Page:      req.Page,
+	}
+	result, err := logicsrightsizing.NewRightsizing(svc.client, svc.cvmLgc, cc.CloudServer().Rightsizing).
+		ListRecommendation(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list biz rightsizing recommendation failed, err: %v, biz: %d, rid: %s", err, bizID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,118 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// AnalyzeSGEffectiveRule analyze effective security group rules of cvm or network interface.
+func (svc *securityGroupSvc) AnalyzeSGEffectiveRule(cts *rest.Contexts) (interface{}, error) {
+	return svc.analyzeSGEffectiveRule(cts, handler.ResOperateAuth)
+}
+
+// AnalyzeBizSGEffectiveRule analyze effective security group rules of biz cvm or network interface.
+func (svc *securityGroupSvc) AnalyzeBizSGEffectiveRule(cts *rest.Contexts) (interface{}, error) {
+	return svc.analyzeSGEffectiveRule(cts, handler.BizOperateAuth)
+}
+
+func (svc *securityGroupSvc) analyzeSGEffectiveRule(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(proto.SGEffectiveRuleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	baseInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit, req.ResType, req.ResID)
+	if err != nil {
+		logs.Errorf("get resource basic info failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.SecurityGroupRule,
+		Action: meta.Find, BasicInfo: baseInfo})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := svc.sgLogic.AnalyzeEffectiveRules(cts.Kit, baseInfo.Vendor, req.ResType, req.ResID)
+	if err != nil {
+		logs.Errorf("analyze security group effective rules failed, err: %v, req: %+v, rid: %s", err, req,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CheckSGReachability check whether the traffic is allowed by security groups of cvm or network interface.
+func (svc *securityGroupSvc) CheckSGReachability(cts *rest.Contexts) (interface{}, error) {
+	return svc.checkSGReachability(cts, handler.ResOperateAuth)
+}
+
+// CheckBizSGReachability check whether the traffic is allowed by security groups of biz cvm or network interface.
+func (svc *securityGroupSvc) CheckBizSGReachability(cts *rest.Contexts) (interface{}, error) {
+	return svc.checkSGReachability(cts, handler.BizOperateAuth)
+}
+
+func (svc *securityGroupSvc) checkSGReachability(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(proto.SGReachabilityCheckReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	baseInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit, req.ResType, req.ResID)
+	if err != nil {
+		logs.Errorf("get resource basic info failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.SecurityGroupRule,
+		Action: meta.Find, BasicInfo: baseInfo})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := svc.sgLogic.CheckReachability(cts.Kit, baseInfo.Vendor, req)
+	if err != nil {
+		logs.Errorf("check security group reachability failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
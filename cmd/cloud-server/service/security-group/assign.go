@@ -28,11 +28,13 @@ import (
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/hooks/handler"
 )
 
 // AssignSecurityGroupToBiz assign security group to biz.
@@ -50,12 +52,17 @@ func (svc *securityGroupSvc) AssignSecurityGroupToBiz(cts *rest.Contexts) (inter
 	basicInfoReq := dataproto.ListResourceBasicInfoReq{
 		ResourceType: enumor.SecurityGroupCloudResType,
 		IDs:          req.SecurityGroupIDs,
+		Fields:       types.ResWithRecycleBasicFields,
 	}
 	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = handler.ValidateNotRecycled(basicInfoMap); err != nil {
+		return nil, err
+	}
+
 	authRes := make([]meta.ResourceAttribute, 0, len(basicInfoMap))
 	for _, info := range basicInfoMap {
 		authRes = append(authRes, meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.SecurityGroup,
@@ -185,7 +185,7 @@ func (svc *securityGroupSvc) decodeAndValidateAssocCvmReq(cts *rest.Contexts, ac
 	basicReq := &cloud.BatchListResourceBasicInfoReq{
 		Items: []cloud.ListResourceBasicInfoReq{
 			{ResourceType: enumor.SecurityGroupCloudResType, IDs: []string{req.SecurityGroupID},
-				Fields: types.CommonBasicInfoFields},
+				Fields: types.ResWithRecycleBasicFields},
 			{ResourceType: enumor.CvmCloudResType, IDs: []string{req.CvmID}, Fields: types.ResWithRecycleBasicFields},
 		},
 	}
@@ -58,9 +58,9 @@ func (svc *securityGroupSvc) associateLb(cts *rest.Contexts, validHandler handle
 	basicReq := &cloud.BatchListResourceBasicInfoReq{
 		Items: []cloud.ListResourceBasicInfoReq{
 			{ResourceType: enumor.SecurityGroupCloudResType, IDs: req.SecurityGroupIDs,
-				Fields: types.CommonBasicInfoFields},
+				Fields: types.ResWithRecycleBasicFields},
 			{ResourceType: enumor.LoadBalancerCloudResType, IDs: []string{req.LbID},
-				Fields: types.CommonBasicInfoFields},
+				Fields: types.ResWithRecycleBasicFields},
 		},
 	}
 
@@ -140,9 +140,9 @@ func (svc *securityGroupSvc) disassociateLb(cts *rest.Contexts, validHandler han
 	basicReq := &cloud.BatchListResourceBasicInfoReq{
 		Items: []cloud.ListResourceBasicInfoReq{
 			{ResourceType: enumor.SecurityGroupCloudResType, IDs: []string{req.SecurityGroupID},
-				Fields: types.CommonBasicInfoFields},
+				Fields: types.ResWithRecycleBasicFields},
 			{ResourceType: enumor.LoadBalancerCloudResType,
-				IDs: []string{req.LbID}, Fields: types.CommonBasicInfoFields},
+				IDs: []string{req.LbID}, Fields: types.ResWithRecycleBasicFields},
 		},
 	}
 
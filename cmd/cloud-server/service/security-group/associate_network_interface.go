@@ -25,6 +25,7 @@ import (
 	hcproto "hcm/pkg/api/hc-service"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
@@ -136,7 +137,7 @@ func (svc *securityGroupSvc) decodeAndValidateAssocNIReq(cts *rest.Contexts, act
 	}
 
 	basicInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit,
-		enumor.SecurityGroupCloudResType, req.SecurityGroupID)
+		enumor.SecurityGroupCloudResType, req.SecurityGroupID, types.ResWithRecycleBasicFields...)
 	if err != nil {
 		logs.Errorf("get resource vendor failed, id: %s, err: %s, rid: %s", basicInfo, err, cts.Kit.Rid)
 		return nil, err
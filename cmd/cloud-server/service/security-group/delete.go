@@ -28,6 +28,7 @@ import (
 	"hcm/pkg/async/action"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
@@ -60,6 +61,7 @@ func (svc *securityGroupSvc) batchDeleteSecurityGroup(cts *rest.Contexts, validH
 	basicInfoReq := dataproto.ListResourceBasicInfoReq{
 		ResourceType: enumor.SecurityGroupCloudResType,
 		IDs:          req.IDs,
+		Fields:       types.ResWithRecycleBasicFields,
 	}
 	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
 	if err != nil {
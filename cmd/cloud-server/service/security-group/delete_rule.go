@@ -22,6 +22,7 @@ package securitygroup
 import (
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
@@ -57,7 +58,7 @@ func (svc *securityGroupSvc) deleteSGRule(cts *rest.Contexts, validHandler handl
 	}
 
 	basicInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit,
-		enumor.SecurityGroupCloudResType, sgID)
+		enumor.SecurityGroupCloudResType, sgID, types.ResWithRecycleBasicFields...)
 	if err != nil {
 		return nil, err
 	}
@@ -23,6 +23,7 @@ import (
 	"net/http"
 
 	"hcm/cmd/cloud-server/logics/audit"
+	securitygroup "hcm/cmd/cloud-server/logics/security-group"
 	"hcm/cmd/cloud-server/service/capability"
 	"hcm/pkg/client"
 	"hcm/pkg/iam/auth"
@@ -35,6 +36,7 @@ func InitSecurityGroupService(c *capability.Capability) {
 		client:     c.ApiClient,
 		authorizer: c.Authorizer,
 		audit:      c.Audit,
+		sgLogic:    c.Logics.SecurityGroup,
 	}
 
 	h := rest.NewHandler()
@@ -70,6 +72,10 @@ func InitSecurityGroupService(c *capability.Capability) {
 		"/security_group/{id}/common/list", svc.ListResourceIdBySecurityGroup)
 	h.Add("ListCvmIdBySecurityGroup", http.MethodPost,
 		"/security_group/{id}/cvm/list", svc.ListCvmIdBySecurityGroup)
+	h.Add("AnalyzeSGEffectiveRule", http.MethodPost, "/security_groups/analyze/effective_rules",
+		svc.AnalyzeSGEffectiveRule)
+	h.Add("CheckSGReachability", http.MethodPost, "/security_groups/analyze/reachability", svc.CheckSGReachability)
+	h.Add("ListSGRiskFinding", http.MethodPost, "/security_groups/risks/list", svc.ListSGRiskFinding)
 
 	bizService(h, svc)
 	initSecurityGroupServiceHooks(svc, h)
@@ -120,10 +126,32 @@ func bizService(h *rest.Handler, svc *securityGroupSvc) {
 		"/bizs/{bk_biz_id}/security_group/{id}/common/list", svc.ListBizResourceIDBySecurityGroup)
 	h.Add("ListBizCvmIdBySecurityGroup", http.MethodPost,
 		"/bizs/{bk_biz_id}/security_group/{id}/cvm/list", svc.ListBizCvmIdBySecurityGroup)
+	h.Add("AnalyzeBizSGEffectiveRule", http.MethodPost, "/bizs/{bk_biz_id}/security_groups/analyze/effective_rules",
+		svc.AnalyzeBizSGEffectiveRule)
+	h.Add("CheckBizSGReachability", http.MethodPost, "/bizs/{bk_biz_id}/security_groups/analyze/reachability",
+		svc.CheckBizSGReachability)
+	h.Add("ListBizSGRiskFinding", http.MethodPost, "/bizs/{bk_biz_id}/security_groups/risks/list",
+		svc.ListBizSGRiskFinding)
+
+	h.Add("CreateBizSGRuleTemplate", http.MethodPost, "/bizs/{bk_biz_id}/security_groups/rule_templates/create",
+		svc.CreateBizSGRuleTemplate)
+	h.Add("UpdateBizSGRuleTemplate", http.MethodPatch, "/bizs/{bk_biz_id}/security_groups/rule_templates/{id}",
+		svc.UpdateBizSGRuleTemplate)
+	h.Add("ListBizSGRuleTemplate", http.MethodPost, "/bizs/{bk_biz_id}/security_groups/rule_templates/list",
+		svc.ListBizSGRuleTemplate)
+	h.Add("BatchDeleteBizSGRuleTemplate", http.MethodDelete, "/bizs/{bk_biz_id}/security_groups/rule_templates/batch",
+		svc.BatchDeleteBizSGRuleTemplate)
+	h.Add("ApplyBizSGRuleTemplate", http.MethodPost, "/bizs/{bk_biz_id}/security_groups/rule_templates/{id}/apply",
+		svc.ApplyBizSGRuleTemplate)
+	h.Add("ListBizSGRuleTemplateApply", http.MethodPost,
+		"/bizs/{bk_biz_id}/security_groups/rule_templates/{id}/applies/list", svc.ListBizSGRuleTemplateApply)
+	h.Add("CheckBizSGRuleTemplateDrift", http.MethodPost,
+		"/bizs/{bk_biz_id}/security_groups/rule_templates/{id}/drift/check", svc.CheckBizSGRuleTemplateDrift)
 }
 
 type securityGroupSvc struct {
 	client     *client.ClientSet
 	authorizer auth.Authorizer
 	audit      audit.Interface
+	sgLogic    securitygroup.Interface
 }
@@ -0,0 +1,534 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"fmt"
+
+	sglogic "hcm/cmd/cloud-server/logics/security-group"
+	actionsg "hcm/cmd/task-server/logics/action/security-group"
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/counter"
+	"hcm/pkg/tools/hooks/handler"
+	"hcm/pkg/tools/slice"
+)
+
+// CreateBizSGRuleTemplate create biz security group rule template.
+func (svc *securityGroupSvc) CreateBizSGRuleTemplate(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(proto.SGRuleTemplateCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err = handler.BizOperateAuth(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer,
+		ResType: meta.SecurityGroup, Action: meta.Create, BasicInfo: &types.CloudResourceBasicInfo{BkBizID: bizID}})
+	if err != nil {
+		return nil, err
+	}
+
+	createReq := &dataproto.SGRuleTemplateBatchCreateReq{
+		Templates: []dataproto.SGRuleTemplateCreate{{
+			Name:    req.Name,
+			BkBizID: bizID,
+			Rules:   req.Rules,
+			Memo:    req.Memo,
+		}},
+	}
+	result, err := svc.client.DataService().Global.SGRuleTemplate.BatchCreate(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create security group rule template failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return core.CreateResult{ID: result.IDs[0]}, nil
+}
+
+// UpdateBizSGRuleTemplate update biz security group rule template.
+func (svc *securityGroupSvc) UpdateBizSGRuleTemplate(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(proto.SGRuleTemplateUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	tpl, err := svc.getBizSGRuleTemplate(cts, id, meta.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	updateReq := &dataproto.SGRuleTemplateUpdateReq{
+		Name:  req.Name,
+		Rules: req.Rules,
+		Memo:  req.Memo,
+	}
+	if err = svc.client.DataService().Global.SGRuleTemplate.Update(cts.Kit, tpl.ID, updateReq); err != nil {
+		logs.Errorf("update security group rule template failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBizSGRuleTemplate list biz security group rule template.
+func (svc *securityGroupSvc) ListBizSGRuleTemplate(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	expr, noPermFlag, err := handler.ListBizAuthRes(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.SecurityGroup, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &core.ListResult{Count: 0, Details: make([]interface{}, 0)}, nil
+	}
+	req.Filter = expr
+
+	return svc.client.DataService().Global.SGRuleTemplate.List(cts.Kit, req)
+}
+
+// BatchDeleteBizSGRuleTemplate batch delete biz security group rule template, the rules which have been applied
+// to the security groups will not be deleted.
+func (svc *securityGroupSvc) BatchDeleteBizSGRuleTemplate(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(proto.SGRuleTemplateDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", req.IDs),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id", "bk_biz_id"},
+	}
+	result, err := svc.client.DataService().Global.SGRuleTemplate.List(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list security group rule template failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, nil
+	}
+
+	basicInfos := make(map[string]types.CloudResourceBasicInfo, len(result.Details))
+	for _, one := range result.Details {
+		basicInfos[one.ID] = types.CloudResourceBasicInfo{ID: one.ID, BkBizID: one.BkBizID}
+	}
+	err = handler.BizOperateAuth(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer,
+		ResType: meta.SecurityGroup, Action: meta.Delete, BasicInfos: basicInfos})
+	if err != nil {
+		return nil, err
+	}
+
+	delReq := &dataproto.SGRuleTplDeleteReq{IDs: slice.Map(result.Details,
+		func(one corecloud.SGRuleTemplate) string { return one.ID })}
+	if err = svc.client.DataService().Global.SGRuleTemplate.BatchDelete(cts.Kit, delReq); err != nil {
+		logs.Errorf("delete security group rule template failed, err: %v, biz: %d, ids: %v, rid: %s", err, bizID,
+			delReq.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBizSGRuleTemplateApply list biz security group rule template apply records.
+func (svc *securityGroupSvc) ListBizSGRuleTemplateApply(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	tpl, err := svc.getBizSGRuleTemplate(cts, cts.PathParameter("id").String(), meta.Find)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Filter, err = tools.And(tools.EqualExpression("template_id", tpl.ID), req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.client.DataService().Global.SGRuleTemplate.ListApply(cts.Kit, req)
+}
+
+// getBizSGRuleTemplate get the security group rule template of the biz in path, and authorize the action.
+func (svc *securityGroupSvc) getBizSGRuleTemplate(cts *rest.Contexts, id string, act meta.Action) (
+	*corecloud.SGRuleTemplate, error) {
+
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.client.DataService().Global.SGRuleTemplate.List(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("get security group rule template failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "security group rule template: %s not found", id)
+	}
+	tpl := result.Details[0]
+
+	if act == meta.Find {
+		_, noPermFlag, err := handler.ListBizAuthRes(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+			ResType: meta.SecurityGroup, Action: meta.Find})
+		if err != nil {
+			return nil, err
+		}
+
+		if noPermFlag {
+			return nil, errf.New(errf.PermissionDenied, "no permission")
+		}
+
+		if tpl.BkBizID != bizID {
+			return nil, errf.Newf(errf.InvalidParameter, "security group rule template: %s not matches url biz", id)
+		}
+		return &tpl, nil
+	}
+
+	err = handler.BizOperateAuth(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer,
+		ResType: meta.SecurityGroup, Action: act,
+		BasicInfo: &types.CloudResourceBasicInfo{ID: tpl.ID, BkBizID: tpl.BkBizID}})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tpl, nil
+}
+
+// ApplyBizSGRuleTemplate apply biz security group rule template to security groups and gcp vpcs. the template rules
+// are compiled into the vendor rules of each target, and created by an async flow, one task for each target.
+func (svc *securityGroupSvc) ApplyBizSGRuleTemplate(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.SGRuleTemplateApplyReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	tpl, err := svc.getBizSGRuleTemplate(cts, cts.PathParameter("id").String(), meta.Find)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := svc.getSGRuleTplTargets(cts, tpl, req)
+	if err != nil {
+		return nil, err
+	}
+
+	createReq := &dataproto.SGRuleTplApplyBatchCreateReq{
+		Applies: make([]dataproto.SGRuleTplApplyCreate, 0, len(targets)),
+	}
+	for _, target := range targets {
+		resType := enumor.SecurityGroupCloudResType
+		if target.Vendor == enumor.Gcp {
+			resType = enumor.VpcCloudResType
+		}
+		createReq.Applies = append(createReq.Applies, dataproto.SGRuleTplApplyCreate{
+			TemplateID:      tpl.ID,
+			TemplateVersion: tpl.Version,
+			Vendor:          target.Vendor,
+			AccountID:       target.AccountID,
+			BkBizID:         target.BkBizID,
+			ResType:         resType,
+			ResID:           target.ResID,
+			CloudResID:      target.CloudResID,
+		})
+	}
+	created, err := svc.client.DataService().Global.SGRuleTemplate.BatchCreateApply(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create security group rule template apply failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	flowID, err := svc.createApplySGRuleTplFlow(cts.Kit, tpl, targets, created.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.SGRuleTemplateApplyResult{FlowID: flowID, ApplyIDs: created.IDs}, nil
+}
+
+// getSGRuleTplTargets get the apply targets and authorize the rule create permission of them.
+func (svc *securityGroupSvc) getSGRuleTplTargets(cts *rest.Contexts, tpl *corecloud.SGRuleTemplate,
+	req *proto.SGRuleTemplateApplyReq) ([]*sglogic.SGRuleTplTarget, error) {
+
+	targets := make([]*sglogic.SGRuleTplTarget, 0, len(req.SecurityGroupIDs)+len(req.GcpVpcIDs))
+	if len(req.SecurityGroupIDs) != 0 {
+		sgIDs := slice.Unique(req.SecurityGroupIDs)
+		listReq := &dataproto.SecurityGroupListReq{
+			Filter: tools.ContainersExpression("id", sgIDs),
+			Page:   core.NewDefaultBasePage(),
+		}
+		result, err := svc.client.DataService().Global.SecurityGroup.ListSecurityGroup(cts.Kit.Ctx,
+			cts.Kit.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list security group failed, err: %v, ids: %v, rid: %s", err, sgIDs, cts.Kit.Rid)
+			return nil, err
+		}
+
+		if len(result.Details) != len(sgIDs) {
+			return nil, errf.Newf(errf.InvalidParameter, "some security groups of %v not found", sgIDs)
+		}
+
+		basicInfos := make(map[string]types.CloudResourceBasicInfo, len(result.Details))
+		for _, sg := range result.Details {
+			basicInfos[sg.ID] = types.CloudResourceBasicInfo{ResType: enumor.SecurityGroupCloudResType, ID: sg.ID,
+				Vendor: sg.Vendor, AccountID: sg.AccountID, BkBizID: sg.BkBizID}
+			targets = append(targets, &sglogic.SGRuleTplTarget{
+				TemplateID:      tpl.ID,
+				TemplateVersion: tpl.Version,
+				Vendor:          sg.Vendor,
+				AccountID:       sg.AccountID,
+				BkBizID:         sg.BkBizID,
+				ResID:           sg.ID,
+				CloudResID:      sg.CloudID,
+			})
+		}
+
+		err = handler.BizOperateAuth(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer,
+			ResType: meta.SecurityGroupRule, Action: meta.Create, BasicInfos: basicInfos})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(req.GcpVpcIDs) != 0 {
+		vpcIDs := slice.Unique(req.GcpVpcIDs)
+		listReq := &core.ListReq{
+			Filter: tools.ExpressionAnd(tools.RuleIn("id", vpcIDs), tools.RuleEqual("vendor", enumor.Gcp)),
+			Page:   core.NewDefaultBasePage(),
+		}
+		result, err := svc.client.DataService().Global.Vpc.List(cts.Kit.Ctx, cts.Kit.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list gcp vpc failed, err: %v, ids: %v, rid: %s", err, vpcIDs, cts.Kit.Rid)
+			return nil, err
+		}
+
+		if len(result.Details) != len(vpcIDs) {
+			return nil, errf.Newf(errf.InvalidParameter, "some gcp vpcs of %v not found", vpcIDs)
+		}
+
+		basicInfos := make(map[string]types.CloudResourceBasicInfo, len(result.Details))
+		for _, vpc := range result.Details {
+			basicInfos[vpc.ID] = types.CloudResourceBasicInfo{ResType: enumor.VpcCloudResType, ID: vpc.ID,
+				Vendor: vpc.Vendor, AccountID: vpc.AccountID, BkBizID: vpc.BkBizID}
+			targets = append(targets, &sglogic.SGRuleTplTarget{
+				TemplateID:      tpl.ID,
+				TemplateVersion: tpl.Version,
+				Vendor:          vpc.Vendor,
+				AccountID:       vpc.AccountID,
+				BkBizID:         vpc.BkBizID,
+				ResID:           vpc.ID,
+				CloudResID:      vpc.CloudID,
+				GcpTargetTags:   req.GcpTargetTags,
+			})
+		}
+
+		err = handler.BizOperateAuth(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer,
+			ResType: meta.GcpFirewallRule, Action: meta.Create, BasicInfos: basicInfos})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return targets, nil
+}
+
+// createApplySGRuleTplFlow compile the template for each target and create the apply flow, the targets which are
+// failed to compile are marked as failed directly.
+func (svc *securityGroupSvc) createApplySGRuleTplFlow(kt *kit.Kit, tpl *corecloud.SGRuleTemplate,
+	targets []*sglogic.SGRuleTplTarget, applyIDs []string) (string, error) {
+
+	if len(targets) != len(applyIDs) {
+		return "", fmt.Errorf("apply ids count %d not matches targets count %d", len(applyIDs), len(targets))
+	}
+
+	getTaskID := counter.NewNumStringCounter(1, 10)
+	tasks := make([]ts.CustomFlowTask, 0, len(targets))
+	updates := make([]dataproto.SGRuleTplApplyUpdate, 0, len(targets))
+	taskApplyIDs := make([]string, 0, len(targets))
+	for idx, target := range targets {
+		compiled, err := svc.sgLogic.CompileRuleTemplate(kt, target, tpl.Rules)
+		if err != nil {
+			logs.Errorf("compile security group rule template failed, err: %v, tpl: %s, res: %s, rid: %s", err,
+				tpl.ID, target.ResID, kt.Rid)
+			updates = append(updates, dataproto.SGRuleTplApplyUpdate{ID: applyIDs[idx],
+				State: enumor.SGRuleTplApplyFailed, Reason: err.Error()})
+			continue
+		}
+
+		tasks = append(tasks, ts.CustomFlowTask{
+			ActionID:   action.ActIDType(getTaskID()),
+			ActionName: enumor.ActionApplySGRuleTemplate,
+			Params: &actionsg.ApplySGRuleTplOption{
+				ApplyID: applyIDs[idx],
+				Vendor:  target.Vendor,
+				ResID:   target.ResID,
+				TCloud:  compiled.TCloud,
+				Aws:     compiled.Aws,
+				HuaWei:  compiled.HuaWei,
+				Azure:   compiled.Azure,
+				Gcp:     compiled.Gcp,
+			},
+		})
+		taskApplyIDs = append(taskApplyIDs, applyIDs[idx])
+	}
+
+	var flowID string
+	if len(tasks) != 0 {
+		flowReq := &ts.AddCustomFlowReq{
+			Name:  enumor.FlowApplySGRuleTemplate,
+			Tasks: tasks,
+		}
+		result, err := svc.client.TaskServer().CreateCustomFlow(kt, flowReq)
+		if err != nil {
+			logs.Errorf("call taskserver to create custom flow failed, err: %v, rid: %s", err, kt.Rid)
+			return "", err
+		}
+		flowID = result.ID
+
+		for _, id := range taskApplyIDs {
+			updates = append(updates, dataproto.SGRuleTplApplyUpdate{ID: id, FlowID: flowID})
+		}
+	}
+
+	if len(updates) != 0 {
+		updateReq := &dataproto.SGRuleTplApplyBatchUpdateReq{Applies: updates}
+		if err := svc.client.DataService().Global.SGRuleTemplate.BatchUpdateApply(kt, updateReq); err != nil {
+			logs.Errorf("update security group rule template apply failed, err: %v, rid: %s", err, kt.Rid)
+			return "", err
+		}
+	}
+
+	return flowID, nil
+}
+
+// CheckBizSGRuleTemplateDrift check whether the targets which the template applied to have drifted from it.
+func (svc *securityGroupSvc) CheckBizSGRuleTemplateDrift(cts *rest.Contexts) (interface{}, error) {
+	tpl, err := svc.getBizSGRuleTemplate(cts, cts.PathParameter("id").String(), meta.Find)
+	if err != nil {
+		return nil, err
+	}
+
+	// 同一个目标可能多次下发，只取最新的一次下发记录进行检查
+	latest := make(map[string]corecloud.SGRuleTplApply)
+	order := make([]string, 0)
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("template_id", tpl.ID),
+		Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "id", Order: core.Ascending},
+	}
+	for {
+		result, err := svc.client.DataService().Global.SGRuleTemplate.ListApply(cts.Kit, listReq)
+		if err != nil {
+			logs.Errorf("list security group rule template apply failed, err: %v, tpl: %s, rid: %s", err, tpl.ID,
+				cts.Kit.Rid)
+			return nil, err
+		}
+
+		for _, one := range result.Details {
+			if _, exists := latest[one.ResID]; !exists {
+				order = append(order, one.ResID)
+			}
+			latest[one.ResID] = one
+		}
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	applies := make([]corecloud.SGRuleTplApply, 0, len(order))
+	for _, resID := range order {
+		applies = append(applies, latest[resID])
+	}
+
+	details, err := svc.sgLogic.CheckRuleTemplateDrift(cts.Kit, tpl, applies)
+	if err != nil {
+		logs.Errorf("check security group rule template drift failed, err: %v, tpl: %s, rid: %s", err, tpl.ID,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &proto.SGRuleTemplateDriftResult{TemplateID: tpl.ID, TemplateVersion: tpl.Version, Details: details}, nil
+}
@@ -24,6 +24,7 @@ import (
 	hcproto "hcm/pkg/api/hc-service"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
@@ -59,7 +60,7 @@ func (svc *securityGroupSvc) updateSecurityGroup(cts *rest.Contexts, validHandle
 	}
 
 	baseInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit,
-		enumor.SecurityGroupCloudResType, id)
+		enumor.SecurityGroupCloudResType, id, types.ResWithRecycleBasicFields...)
 	if err != nil {
 		logs.Errorf("get resource vendor failed, id: %s, err: %s, rid: %s", id, err, cts.Kit.Rid)
 		return nil, err
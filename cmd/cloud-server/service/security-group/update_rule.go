@@ -62,7 +62,7 @@ func (svc *securityGroupSvc) updateSGRule(cts *rest.Contexts, validHandler handl
 	}
 
 	sgBaseInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit,
-		enumor.SecurityGroupCloudResType, sgID)
+		enumor.SecurityGroupCloudResType, sgID, types.ResWithRecycleBasicFields...)
 	if err != nil {
 		return nil, err
 	}
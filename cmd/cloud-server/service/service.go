@@ -30,6 +30,11 @@ import (
 
 	"hcm/cmd/cloud-server/logics"
 	logicaudit "hcm/cmd/cloud-server/logics/audit"
+	logicdisksnapshot "hcm/cmd/cloud-server/logics/disk-snapshot"
+	logicimage "hcm/cmd/cloud-server/logics/image"
+	logicrenewal "hcm/cmd/cloud-server/logics/renewal"
+	logicsg "hcm/cmd/cloud-server/logics/security-group"
+	logictagpolicy "hcm/cmd/cloud-server/logics/tag-policy"
 	"hcm/cmd/cloud-server/service/account"
 	"hcm/cmd/cloud-server/service/application"
 	appcvm "hcm/cmd/cloud-server/service/application/handlers/cvm"
@@ -49,18 +54,25 @@ import (
 	"hcm/cmd/cloud-server/service/firewall"
 	"hcm/cmd/cloud-server/service/image"
 	instancetype "hcm/cmd/cloud-server/service/instance-type"
+	"hcm/cmd/cloud-server/service/ipam"
 	loadbalancer "hcm/cmd/cloud-server/service/load-balancer"
 	mailverify "hcm/cmd/cloud-server/service/mail-verify"
 	networkinterface "hcm/cmd/cloud-server/service/network-interface"
+	"hcm/cmd/cloud-server/service/rbac"
 	"hcm/cmd/cloud-server/service/recycle"
 	"hcm/cmd/cloud-server/service/region"
+	"hcm/cmd/cloud-server/service/renewal"
 	resourcegroup "hcm/cmd/cloud-server/service/resource-group"
+	restag "hcm/cmd/cloud-server/service/resource-tag"
+	"hcm/cmd/cloud-server/service/rightsizing"
 	routetable "hcm/cmd/cloud-server/service/route-table"
 	securitygroup "hcm/cmd/cloud-server/service/security-group"
 	subaccount "hcm/cmd/cloud-server/service/sub-account"
 	"hcm/cmd/cloud-server/service/subnet"
 	"hcm/cmd/cloud-server/service/sync"
 	"hcm/cmd/cloud-server/service/sync/lock"
+	tagpolicy "hcm/cmd/cloud-server/service/tag-policy"
+	"hcm/cmd/cloud-server/service/topology"
 	"hcm/cmd/cloud-server/service/user"
 	"hcm/cmd/cloud-server/service/vpc"
 	"hcm/cmd/cloud-server/service/zone"
@@ -118,7 +130,18 @@ func NewService(sd serviced.ServiceDiscover) (*Service, error) {
 
 	if cc.CloudServer().CloudResource.Sync.Enable {
 		interval := time.Duration(cc.CloudServer().CloudResource.Sync.SyncIntervalMin) * time.Minute
-		go sync.CloudResourceSync(interval, sd, apiClientSet)
+		hooks := make([]sync.AccountSyncedHook, 0)
+		if cc.CloudServer().SGRiskScan.Enable {
+			scanner := logicsg.NewRiskScanner(apiClientSet, esbClient, svr.cmsiCli, cc.CloudServer().SGRiskScan)
+			go scanner.Run(sd)
+			hooks = append(hooks, scanner.Submit)
+		}
+		if cc.CloudServer().TagCompliance.Enable {
+			evaluator := logictagpolicy.NewEvaluator(apiClientSet, svr.esbClient, cc.CloudServer().TagCompliance)
+			hooks = append(hooks, evaluator.Evaluate)
+		}
+		hooks = append(hooks, logicimage.NewPrivateImageSyncer(apiClientSet))
+		go sync.CloudResourceSync(interval, sd, apiClientSet, hooks...)
 	}
 
 	if cc.CloudServer().BillConfig.Enable {
@@ -128,7 +151,21 @@ func NewService(sd serviced.ServiceDiscover) (*Service, error) {
 
 	recycle.RecycleTiming(apiClientSet, sd, cc.CloudServer().Recycle, esbClient)
 
+	if cc.CloudServer().AuditExport.Enable {
+		go audit.ChainExportTiming(apiClientSet, sd, cc.CloudServer().AuditExport)
+	}
+
+	if cc.CloudServer().ExpiryWatch.Enable {
+		go logicrenewal.TimingNoticeExpiringRes(apiClientSet, sd, esbClient, svr.cmsiCli,
+			cc.CloudServer().ExpiryWatch)
+	}
+
+	if cc.CloudServer().SnapshotPolicy.Enable {
+		go logicdisksnapshot.TimingExecPolicy(apiClientSet, sd)
+	}
+
 	go appcvm.TimingHandleDeliverApplication(svr.client, 2*time.Second)
+	go application.TimingEscalateNativeApproval(svr.client, sd, svr.cmsiCli, cc.CloudServer().BkHcmUrl, time.Minute)
 
 	return svr, nil
 }
@@ -320,6 +357,13 @@ func (s *Service) apiSet(bkHcmUrl string) *restful.Container {
 	asynctask.InitService(c)
 
 	bandwidthpackage.InitService(c)
+	ipam.InitService(c)
+	topology.InitService(c)
+	restag.InitService(c)
+	tagpolicy.InitService(c)
+	renewal.InitService(c)
+	rightsizing.InitService(c)
+	rbac.InitService(c)
 
 	mailverify.InitEmailService(c)
 
@@ -73,5 +73,13 @@ func (svc *service) UpdateSubAccount(cts *rest.Contexts) (interface{}, error) {
 		return nil, err
 	}
 
+	// 子账号所属业务变更会影响用户对该子账号的权限，需要清理鉴权缓存，清理失败时缓存会在过期后自动失效
+	if req.BkBizIDs != nil {
+		if err = svc.authorizer.InvalidateCache(cts.Kit); err != nil {
+			logs.Errorf("invalidate auth cache after sub account biz changed failed, err: %v, rid: %s", err,
+				cts.Kit.Rid)
+		}
+	}
+
 	return nil, nil
 }
@@ -25,6 +25,7 @@ import (
 
 	"hcm/cmd/cloud-server/logics/async"
 	"hcm/cmd/cloud-server/logics/audit"
+	"hcm/cmd/cloud-server/logics/ipam"
 	"hcm/cmd/cloud-server/service/capability"
 	"hcm/cmd/cloud-server/service/common"
 	actionsubnet "hcm/cmd/task-server/logics/action/subnet"
@@ -39,6 +40,8 @@ import (
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/iam/auth"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/kit"
@@ -57,6 +60,7 @@ func InitSubnetService(c *capability.Capability) {
 		client:     c.ApiClient,
 		authorizer: c.Authorizer,
 		audit:      c.Audit,
+		ipam:       c.Logics.Ipam,
 	}
 
 	h := rest.NewHandler()
@@ -88,6 +92,7 @@ type subnetSvc struct {
 	client     *client.ClientSet
 	authorizer auth.Authorizer
 	audit      audit.Interface
+	ipam       ipam.Interface
 }
 
 // CreateSubnet create subnet.
@@ -152,6 +157,10 @@ func (svc *subnetSvc) createTCloudSubnet(kt *kit.Kit, bizID int64, data json.Raw
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.validateSubnetCidr(kt, enumor.TCloud, req.AccountID, req.CloudVpcID, req.IPv4Cidr); err != nil {
+		return nil, err
+	}
+
 	opt := &hcservice.TCloudSubnetBatchCreateReq{
 		BkBizID:    bizID,
 		AccountID:  req.AccountID,
@@ -186,6 +195,11 @@ func (svc *subnetSvc) createAwsSubnet(kt *kit.Kit, bizID int64, data json.RawMes
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.validateSubnetCidr(kt, enumor.Aws, req.AccountID, req.CloudVpcID,
+		converter.PtrToVal(req.IPv4Cidr)); err != nil {
+		return nil, err
+	}
+
 	opt := &hcservice.SubnetCreateReq[hcservice.AwsSubnetCreateExt]{
 		BaseSubnetCreateReq: convertBaseSubnetCreateReq(bizID, req.BaseSubnetCreateReq),
 		Extension: &hcservice.AwsSubnetCreateExt{
@@ -215,6 +229,10 @@ func (svc *subnetSvc) createGcpSubnet(kt *kit.Kit, bizID int64, data json.RawMes
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.validateSubnetCidr(kt, enumor.Gcp, req.AccountID, req.CloudVpcID, req.IPv4Cidr); err != nil {
+		return nil, err
+	}
+
 	opt := &hcservice.SubnetCreateReq[hcservice.GcpSubnetCreateExt]{
 		BaseSubnetCreateReq: convertBaseSubnetCreateReq(bizID, req.BaseSubnetCreateReq),
 		Extension: &hcservice.GcpSubnetCreateExt{
@@ -244,6 +262,10 @@ func (svc *subnetSvc) createAzureSubnet(kt *kit.Kit, bizID int64, data json.RawM
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.validateSubnetCidr(kt, enumor.Azure, req.AccountID, req.CloudVpcID, req.IPv4Cidr...); err != nil {
+		return nil, err
+	}
+
 	// check azure subnet params
 	if err := svc.checkAzureSubnetParams(req); err != nil {
 		return nil, err
@@ -280,6 +302,10 @@ func (svc *subnetSvc) createHuaWeiSubnet(kt *kit.Kit, bizID int64, data json.Raw
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.validateSubnetCidr(kt, enumor.HuaWei, req.AccountID, req.CloudVpcID, req.IPv4Cidr); err != nil {
+		return nil, err
+	}
+
 	opt := &hcservice.SubnetCreateReq[hcservice.HuaWeiSubnetCreateExt]{
 		BaseSubnetCreateReq: convertBaseSubnetCreateReq(bizID, req.BaseSubnetCreateReq),
 		Extension: &hcservice.HuaWeiSubnetCreateExt{
@@ -298,6 +324,40 @@ func (svc *subnetSvc) createHuaWeiSubnet(kt *kit.Kit, bizID int64, data json.Raw
 	return createRes, nil
 }
 
+// validateSubnetCidr validate the subnet cidr does not overlap with other subnets in the vpc.
+func (svc *subnetSvc) validateSubnetCidr(kt *kit.Kit, vendor enumor.Vendor, accountID, cloudVpcID string,
+	subnetCidrs ...string) error {
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("vendor", vendor), tools.RuleEqual("account_id", accountID),
+			tools.RuleEqual("cloud_id", cloudVpcID)),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id"},
+	}
+	vpcRes, err := svc.client.DataService().Global.Vpc.List(kt.Ctx, kt.Header(), listReq)
+	if err != nil {
+		logs.Errorf("list vpc failed, err: %v, cloud id: %s, rid: %s", err, cloudVpcID, kt.Rid)
+		return err
+	}
+
+	// vpc未同步时由云上校验网段
+	if len(vpcRes.Details) == 0 {
+		return nil
+	}
+
+	for _, subnetCidr := range subnetCidrs {
+		if len(subnetCidr) == 0 {
+			continue
+		}
+
+		if err = svc.ipam.ValidateSubnetCidr(kt, vpcRes.Details[0].ID, subnetCidr); err != nil {
+			return errf.NewFromErr(errf.InvalidParameter, err)
+		}
+	}
+
+	return nil
+}
+
 func convertBaseSubnetCreateReq(bizID int64, req *cloudserver.BaseSubnetCreateReq) *hcservice.BaseSubnetCreateReq {
 	return &hcservice.BaseSubnetCreateReq{
 		AccountID:  req.AccountID,
@@ -329,7 +389,8 @@ func (svc *subnetSvc) updateSubnet(cts *rest.Contexts, validHandler handler.Vali
 	}
 
 	id := cts.PathParameter("id").String()
-	basicInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit, enumor.SubnetCloudResType, id)
+	basicInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit, enumor.SubnetCloudResType, id,
+		types.ResWithRecycleBasicFields...)
 	if err != nil {
 		return nil, err
 	}
@@ -507,6 +568,7 @@ func (svc *subnetSvc) batchDeleteSubnet(cts *rest.Contexts, validHandler handler
 	basicInfoReq := cloud.ListResourceBasicInfoReq{
 		ResourceType: enumor.SubnetCloudResType,
 		IDs:          req.IDs,
+		Fields:       types.ResWithRecycleBasicFields,
 	}
 	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
 	if err != nil {
@@ -572,12 +634,17 @@ func (svc *subnetSvc) AssignSubnetToBiz(cts *rest.Contexts) (interface{}, error)
 	basicInfoReq := cloud.ListResourceBasicInfoReq{
 		ResourceType: enumor.SubnetCloudResType,
 		IDs:          req.SubnetIDs,
+		Fields:       types.ResWithRecycleBasicFields,
 	}
 	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = handler.ValidateNotRecycled(basicInfoMap); err != nil {
+		return nil, err
+	}
+
 	authRes := make([]meta.ResourceAttribute, 0, len(basicInfoMap))
 	for _, info := range basicInfoMap {
 		authRes = append(authRes, meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Subnet, Action: meta.Assign,
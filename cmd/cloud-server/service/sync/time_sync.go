@@ -39,8 +39,13 @@ import (
 	"hcm/pkg/tools/retry"
 )
 
+// AccountSyncedHook 账号下的资源全部同步成功后执行的回调，回调失败不影响同步流程
+type AccountSyncedHook func(kt *kit.Kit, vendor enumor.Vendor, accountID string) error
+
 // CloudResourceSync 定时同步云资源
-func CloudResourceSync(intervalMin time.Duration, sd serviced.ServiceDiscover, cliSet *client.ClientSet) {
+func CloudResourceSync(intervalMin time.Duration, sd serviced.ServiceDiscover, cliSet *client.ClientSet,
+	hooks ...AccountSyncedHook) {
+
 	logs.Infof("cloud resource sync enable, syncIntervalMin: %v", intervalMin)
 
 	for {
@@ -59,7 +64,7 @@ func CloudResourceSync(intervalMin time.Duration, sd serviced.ServiceDiscover, c
 		waitGroup.Add(len(syncers))
 		for _, vendorSyncer := range syncers {
 			go func(vendor account.VendorSyncer) {
-				allAccountSync(core.NewBackendKit(), cliSet, vendor)
+				allAccountSync(core.NewBackendKit(), cliSet, vendor, hooks)
 				waitGroup.Done()
 			}(vendorSyncer)
 		}
@@ -71,7 +76,8 @@ func CloudResourceSync(intervalMin time.Duration, sd serviced.ServiceDiscover, c
 }
 
 // allAccountSync all account sync.
-func allAccountSync(kt *kit.Kit, cliSet *client.ClientSet, syncer account.VendorSyncer) {
+func allAccountSync(kt *kit.Kit, cliSet *client.ClientSet, syncer account.VendorSyncer,
+	hooks []AccountSyncedHook) {
 
 	startTime := time.Now()
 	logs.Infof("%s start sync all cloud resource, time: %v, rid: %s", syncer.Vendor(), startTime, kt.Rid)
@@ -120,6 +126,13 @@ func allAccountSync(kt *kit.Kit, cliSet *client.ClientSet, syncer account.Vendor
 
 			// 公共资源仅需要同步一次即可
 			syncPublicResource = false
+
+			for _, hook := range hooks {
+				if err := hook(kt, syncer.Vendor(), acc.ID); err != nil {
+					logs.Errorf("%s account synced hook failed, err: %v, accountID: %s, rid: %s", syncer.Vendor(),
+						err, acc.ID, kt.Rid)
+				}
+			}
 		}
 		if len(accounts) < int(core.DefaultMaxPageLimit) {
 			break
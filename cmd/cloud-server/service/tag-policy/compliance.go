@@ -0,0 +1,205 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tagpolicy
+
+import (
+	"math"
+	"strconv"
+
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// ListTagComplianceFinding list tag compliance finding.
+func (svc *tagPolicySvc) ListTagComplianceFinding(cts *rest.Contexts) (interface{}, error) {
+	return svc.listTagComplianceFinding(cts, false)
+}
+
+// ListBizTagComplianceFinding list tag compliance finding of the biz's resources.
+func (svc *tagPolicySvc) ListBizTagComplianceFinding(cts *rest.Contexts) (interface{}, error) {
+	return svc.listTagComplianceFinding(cts, true)
+}
+
+func (svc *tagPolicySvc) listTagComplianceFinding(cts *rest.Contexts, isBiz bool) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	expr, noPermFlag, err := svc.authComplianceFilter(cts, isBiz, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &protocloud.TagComplianceFindingListResult{Count: 0, Details: make([]corecloud.TagComplianceFinding,
+			0)}, nil
+	}
+	req.Filter = expr
+
+	result, err := svc.client.DataService().Global.TagPolicy.ListComplianceFinding(cts.Kit, req)
+	if err != nil {
+		logs.Errorf("list tag compliance finding failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// StatTagCompliance stat tag compliance rate group by biz or account.
+func (svc *tagPolicySvc) StatTagCompliance(cts *rest.Contexts) (interface{}, error) {
+	return svc.statTagCompliance(cts, false)
+}
+
+// StatBizTagCompliance stat tag compliance rate of the biz's resources group by biz or account.
+func (svc *tagPolicySvc) StatBizTagCompliance(cts *rest.Contexts) (interface{}, error) {
+	return svc.statTagCompliance(cts, true)
+}
+
+func (svc *tagPolicySvc) statTagCompliance(cts *rest.Contexts, isBiz bool) (interface{}, error) {
+	req := new(cloudserver.TagComplianceStatsReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if req.Filter == nil {
+		req.Filter = tools.AllExpression()
+	}
+
+	expr, noPermFlag, err := svc.authComplianceFilter(cts, isBiz, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &cloudserver.TagComplianceStatsResult{Details: make([]cloudserver.TagComplianceStatsItem, 0)}, nil
+	}
+
+	summaries, err := svc.listAllComplianceSummary(cts.Kit, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cloudserver.TagComplianceStatsResult{Details: statComplianceSummaries(req.GroupBy, summaries)}, nil
+}
+
+// authComplianceFilter 合规结果随资源所属账号鉴权，业务下接口仅返回业务下资源的合规结果
+func (svc *tagPolicySvc) authComplianceFilter(cts *rest.Contexts, isBiz bool, expr *filter.Expression) (
+	*filter.Expression, bool, error) {
+
+	if !isBiz {
+		return handler.ListResourceAuthRes(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+			ResType: meta.Account, Action: meta.Find, Filter: expr})
+	}
+
+	bizID, err := svc.authorizeTagPolicy(cts, true, meta.Find)
+	if err != nil {
+		return nil, false, err
+	}
+
+	bizExpr, err := tools.And(expr, tools.EqualExpression("bk_biz_id", bizID))
+	if err != nil {
+		return nil, false, err
+	}
+
+	return bizExpr, false, nil
+}
+
+func (svc *tagPolicySvc) listAllComplianceSummary(kt *kit.Kit, expr *filter.Expression) (
+	[]corecloud.TagComplianceSummary, error) {
+
+	req := &core.ListReq{Filter: expr, Page: core.NewDefaultBasePage()}
+	summaries := make([]corecloud.TagComplianceSummary, 0)
+	for {
+		result, err := svc.client.DataService().Global.TagPolicy.ListComplianceSummary(kt, req)
+		if err != nil {
+			logs.Errorf("list tag compliance summary failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		summaries = append(summaries, result.Details...)
+		if uint(len(result.Details)) < req.Page.Limit {
+			break
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+
+	return summaries, nil
+}
+
+// statComplianceSummaries 按业务或账号汇总合规统计并计算合规率
+func statComplianceSummaries(groupBy enumor.TagComplianceGroupBy,
+	summaries []corecloud.TagComplianceSummary) []cloudserver.TagComplianceStatsItem {
+
+	itemMap := make(map[string]*cloudserver.TagComplianceStatsItem)
+	keys := make([]string, 0)
+	for _, summary := range summaries {
+		key := summary.AccountID
+		if groupBy == enumor.TagComplianceGroupByBiz {
+			key = strconv.FormatInt(summary.BkBizID, 10)
+		}
+
+		item, exists := itemMap[key]
+		if !exists {
+			item = new(cloudserver.TagComplianceStatsItem)
+			if groupBy == enumor.TagComplianceGroupByBiz {
+				item.BkBizID = converter.ValToPtr(summary.BkBizID)
+			} else {
+				item.AccountID = summary.AccountID
+			}
+			itemMap[key] = item
+			keys = append(keys, key)
+		}
+
+		item.TotalCount += summary.TotalCount
+		item.CompliantCount += summary.CompliantCount
+	}
+
+	items := make([]cloudserver.TagComplianceStatsItem, 0, len(keys))
+	for _, key := range keys {
+		item := itemMap[key]
+		if item.TotalCount != 0 {
+			item.ComplianceRate = math.Round(float64(item.CompliantCount)*10000/float64(item.TotalCount)) / 100
+		}
+		items = append(items, *item)
+	}
+
+	return items
+}
@@ -0,0 +1,67 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tagpolicy ...
+package tagpolicy
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/service/capability"
+	"hcm/pkg/client"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/rest"
+)
+
+// InitService initial the tag policy service
+func InitService(c *capability.Capability) {
+	svc := &tagPolicySvc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+	}
+
+	h := rest.NewHandler()
+
+	// 资源下标签策略及合规相关接口
+	h.Add("CreateTagPolicy", http.MethodPost, "/tag_policies/create", svc.CreateTagPolicy)
+	h.Add("UpdateTagPolicy", http.MethodPatch, "/tag_policies/{id}", svc.UpdateTagPolicy)
+	h.Add("ListTagPolicy", http.MethodPost, "/tag_policies/list", svc.ListTagPolicy)
+	h.Add("BatchDeleteTagPolicy", http.MethodDelete, "/tag_policies/batch", svc.BatchDeleteTagPolicy)
+	h.Add("ListTagComplianceFinding", http.MethodPost, "/tag_compliance/findings/list",
+		svc.ListTagComplianceFinding)
+	h.Add("StatTagCompliance", http.MethodPost, "/tag_compliance/stats", svc.StatTagCompliance)
+
+	// 业务下标签策略及合规相关接口
+	h.Add("CreateBizTagPolicy", http.MethodPost, "/bizs/{bk_biz_id}/tag_policies/create", svc.CreateBizTagPolicy)
+	h.Add("UpdateBizTagPolicy", http.MethodPatch, "/bizs/{bk_biz_id}/tag_policies/{id}", svc.UpdateBizTagPolicy)
+	h.Add("ListBizTagPolicy", http.MethodPost, "/bizs/{bk_biz_id}/tag_policies/list", svc.ListBizTagPolicy)
+	h.Add("BatchDeleteBizTagPolicy", http.MethodDelete, "/bizs/{bk_biz_id}/tag_policies/batch",
+		svc.BatchDeleteBizTagPolicy)
+	h.Add("ListBizTagComplianceFinding", http.MethodPost, "/bizs/{bk_biz_id}/tag_compliance/findings/list",
+		svc.ListBizTagComplianceFinding)
+	h.Add("StatBizTagCompliance", http.MethodPost, "/bizs/{bk_biz_id}/tag_compliance/stats",
+		svc.StatBizTagCompliance)
+
+	h.Load(c.WebService)
+}
+
+type tagPolicySvc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+}
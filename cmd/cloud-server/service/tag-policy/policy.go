@@ -0,0 +1,218 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tagpolicy
+
+import (
+	"fmt"
+
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	tabletagpolicy "hcm/pkg/dal/table/cloud/tag-policy"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+)
+
+// CreateTagPolicy create tag policy, bk_biz_id 0 means the global policy.
+func (svc *tagPolicySvc) CreateTagPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.createTagPolicy(cts, false)
+}
+
+// CreateBizTagPolicy create biz tag policy.
+func (svc *tagPolicySvc) CreateBizTagPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.createTagPolicy(cts, true)
+}
+
+func (svc *tagPolicySvc) createTagPolicy(cts *rest.Contexts, isBiz bool) (interface{}, error) {
+	req := new(protocloud.TagPolicyCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	bizID, err := svc.authorizeTagPolicy(cts, isBiz, meta.Update)
+	if err != nil {
+		return nil, err
+	}
+	if isBiz {
+		req.BkBizID = bizID
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return svc.client.DataService().Global.TagPolicy.Create(cts.Kit, req)
+}
+
+// UpdateTagPolicy update tag policy.
+func (svc *tagPolicySvc) UpdateTagPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.updateTagPolicy(cts, false)
+}
+
+// UpdateBizTagPolicy update biz tag policy.
+func (svc *tagPolicySvc) UpdateBizTagPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.updateTagPolicy(cts, true)
+}
+
+func (svc *tagPolicySvc) updateTagPolicy(cts *rest.Contexts, isBiz bool) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protocloud.TagPolicyUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	bizID, err := svc.authorizeTagPolicy(cts, isBiz, meta.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	if isBiz {
+		if err = svc.checkBizTagPolicy(cts.Kit, bizID, []string{id}); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, svc.client.DataService().Global.TagPolicy.Update(cts.Kit, id, req)
+}
+
+// ListTagPolicy list tag policy.
+func (svc *tagPolicySvc) ListTagPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.listTagPolicy(cts, false)
+}
+
+// ListBizTagPolicy list biz tag policy, the global policy is returned together.
+func (svc *tagPolicySvc) ListBizTagPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.listTagPolicy(cts, true)
+}
+
+func (svc *tagPolicySvc) listTagPolicy(cts *rest.Contexts, isBiz bool) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	bizID, err := svc.authorizeTagPolicy(cts, isBiz, meta.Find)
+	if err != nil {
+		return nil, err
+	}
+
+	if isBiz {
+		bizFilter := tools.ContainersExpression("bk_biz_id", []int64{bizID, tabletagpolicy.GlobalTagPolicyBizID})
+		req.Filter, err = tools.And(req.Filter, bizFilter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return svc.client.DataService().Global.TagPolicy.List(cts.Kit, req)
+}
+
+// BatchDeleteTagPolicy batch delete tag policy.
+func (svc *tagPolicySvc) BatchDeleteTagPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchDeleteTagPolicy(cts, false)
+}
+
+// BatchDeleteBizTagPolicy batch delete biz tag policy.
+func (svc *tagPolicySvc) BatchDeleteBizTagPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchDeleteTagPolicy(cts, true)
+}
+
+func (svc *tagPolicySvc) batchDeleteTagPolicy(cts *rest.Contexts, isBiz bool) (interface{}, error) {
+	req := new(cloudserver.TagPolicyDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	bizID, err := svc.authorizeTagPolicy(cts, isBiz, meta.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	if isBiz {
+		if err = svc.checkBizTagPolicy(cts.Kit, bizID, req.IDs); err != nil {
+			return nil, err
+		}
+	}
+
+	deleteReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", req.IDs)}
+	return nil, svc.client.DataService().Global.TagPolicy.BatchDelete(cts.Kit, deleteReq)
+}
+
+// authorizeTagPolicy authorize tag policy operation, returns the biz id of the biz api.
+func (svc *tagPolicySvc) authorizeTagPolicy(cts *rest.Contexts, isBiz bool, action meta.Action) (int64, error) {
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Biz, Action: action}}
+	if !isBiz {
+		return 0, svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes)
+	}
+
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return 0, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	if bizID <= 0 {
+		return 0, errf.New(errf.InvalidParameter, "bk_biz_id is invalid")
+	}
+
+	authRes.BizID = bizID
+	return bizID, svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes)
+}
+
+// checkBizTagPolicy check if the tag policies all belong to the biz, the global policy can not be changed in biz.
+func (svc *tagPolicySvc) checkBizTagPolicy(kt *kit.Kit, bizID int64, ids []string) error {
+	ids = slice.Unique(ids)
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleIn("id", ids), tools.RuleEqual("bk_biz_id", bizID)),
+		Page:   core.NewCountPage(),
+	}
+	result, err := svc.client.DataService().Global.TagPolicy.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("count biz tag policy failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return err
+	}
+
+	if result.Count != uint64(len(ids)) {
+		return errf.NewFromErr(errf.InvalidParameter,
+			fmt.Errorf("some tag policies(ids=%v) do not belong to biz %d", ids, bizID))
+	}
+
+	return nil
+}
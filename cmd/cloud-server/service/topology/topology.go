@@ -0,0 +1,88 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package topology ...
+package topology
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/logics/topology"
+	"hcm/cmd/cloud-server/service/capability"
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// InitService initialize the network topology service.
+func InitService(c *capability.Capability) {
+	svc := &topologySvc{
+		authorizer: c.Authorizer,
+		topology:   c.Logics.Topology,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("GetBizTopologyGraph", http.MethodPost, "/bizs/{bk_biz_id}/topology/graph", svc.GetBizTopologyGraph)
+
+	h.Load(c.WebService)
+}
+
+type topologySvc struct {
+	authorizer auth.Authorizer
+	topology   topology.Interface
+}
+
+// GetBizTopologyGraph get the network topology graph rooted at the biz resource.
+func (svc *topologySvc) GetBizTopologyGraph(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	if bizID <= 0 {
+		return nil, errf.New(errf.InvalidParameter, "biz id is invalid")
+	}
+
+	req := new(proto.TopologyGraphReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Biz, Action: meta.Access}, BizID: bizID}
+	if err = svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	graph, err := svc.topology.Graph(cts.Kit, bizID, req)
+	if err != nil {
+		logs.Errorf("get topology graph failed, err: %v, res: %s/%s, rid: %s", err, req.ResType, req.ResID,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return graph, nil
+}
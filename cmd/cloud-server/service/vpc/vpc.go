@@ -37,6 +37,7 @@ import (
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/iam/auth"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/kit"
@@ -247,7 +248,7 @@ func (svc *vpcSvc) updateVpc(cts *rest.Contexts, validHandler handler.ValidWithA
 
 	id := cts.PathParameter("id").String()
 	basicInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit,
-		enumor.VpcCloudResType, id)
+		enumor.VpcCloudResType, id, types.ResWithRecycleBasicFields...)
 	if err != nil {
 		return nil, err
 	}
@@ -468,7 +469,7 @@ func (svc *vpcSvc) deleteVpc(cts *rest.Contexts, validHandler handler.ValidWithA
 	}
 
 	basicInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit,
-		enumor.VpcCloudResType, id)
+		enumor.VpcCloudResType, id, types.ResWithRecycleBasicFields...)
 	if err != nil {
 		return nil, err
 	}
@@ -657,12 +658,17 @@ func (svc *vpcSvc) authorizeVpcBatchOp(kt *kit.Kit, action meta.Action, ids []st
 	basicInfoReq := cloud.ListResourceBasicInfoReq{
 		ResourceType: enumor.VpcCloudResType,
 		IDs:          ids,
+		Fields:       types.ResWithRecycleBasicFields,
 	}
 	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(kt, basicInfoReq)
 	if err != nil {
 		return err
 	}
 
+	if err = handler.ValidateNotRecycled(basicInfoMap); err != nil {
+		return err
+	}
+
 	authRes := make([]meta.ResourceAttribute, 0, len(basicInfoMap))
 	for _, info := range basicInfoMap {
 		authRes = append(authRes, meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Vpc, Action: action,
@@ -75,12 +75,6 @@ func (ds *dataService) prepare(opt *options.Option) error {
 	network := cc.DataService().Network
 	metrics.InitMetrics(net.JoinHostPort(network.BindIP, strconv.Itoa(int(network.Port))))
 
-	svc, err := service.NewService()
-	if err != nil {
-		return fmt.Errorf("initialize service failed, err: %v", err)
-	}
-	ds.svc = svc
-
 	// register data service.
 	svcOpt := serviced.NewServiceOption(cc.DataServiceName, cc.DataService().Network)
 	sd, err := serviced.NewService(cc.DataService().Service, svcOpt)
@@ -90,6 +84,12 @@ func (ds *dataService) prepare(opt *options.Option) error {
 
 	ds.sd = sd
 
+	svc, err := service.NewService(sd)
+	if err != nil {
+		return fmt.Errorf("initialize service failed, err: %v", err)
+	}
+	ds.svc = svc
+
 	// init hcm control tool
 	if err := ctl.LoadCtl(ctl.WithBasics(sd)...); err != nil {
 		return fmt.Errorf("load control tool failed, err: %v", err)
@@ -37,6 +37,7 @@ import (
 	tabletype "hcm/pkg/dal/table/types"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -80,6 +81,7 @@ func (svc *applicationSvc) Create(cts *rest.Contexts) (interface{}, error) {
 		Content:        tabletype.JsonField(req.Content),
 		DeliveryDetail: tabletype.JsonField(req.DeliveryDetail),
 		Memo:           req.Memo,
+		ApprovalDetail: tabletype.JsonField(req.ApprovalDetail),
 		Creator:        cts.Kit.User,
 		Reviser:        cts.Kit.User,
 	}
@@ -122,11 +124,30 @@ func (svc *applicationSvc) Update(cts *rest.Contexts) (interface{}, error) {
 	if req.DeliveryDetail != nil {
 		application.DeliveryDetail = tabletype.JsonField(*req.DeliveryDetail)
 	}
+	if req.ApprovalDetail != nil {
+		application.ApprovalDetail = tabletype.JsonField(*req.ApprovalDetail)
+	}
+
+	if len(req.ExpectedStatus) == 0 && req.ExpectedApprovalRevision == nil {
+		err := svc.dao.Application().Update(cts.Kit, tools.EqualExpression("id", applicationID), application)
+		if err != nil {
+			logs.Errorf("update application failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, fmt.Errorf("update application failed, err: %v", err)
+		}
+		return nil, nil
+	}
 
-	err := svc.dao.Application().Update(cts.Kit, tools.EqualExpression("id", applicationID), application)
+	rules := []*filter.AtomRule{tools.RuleEqual("id", applicationID)}
+	if len(req.ExpectedStatus) != 0 {
+		rules = append(rules, tools.RuleEqual("status", req.ExpectedStatus))
+	}
+	if req.ExpectedApprovalRevision != nil {
+		rules = append(rules, tools.RuleJSONEqual("approval_detail.revision", *req.ExpectedApprovalRevision))
+	}
+	err := svc.dao.Application().UpdateByCAS(cts.Kit, tools.ExpressionAnd(rules...), application)
 	if err != nil {
-		logs.Errorf("update application failed, err: %v, rid: %s", err, cts.Kit.Rid)
-		return nil, fmt.Errorf("update application failed, err: %v", err)
+		logs.Errorf("update application by cas failed, err: %v, id: %s, rid: %s", err, applicationID, cts.Kit.Rid)
+		return nil, err
 	}
 
 	return nil, nil
@@ -146,6 +167,7 @@ func (svc *applicationSvc) convertToApplicationResp(
 		Content:        string(application.Content),
 		DeliveryDetail: string(application.DeliveryDetail),
 		Memo:           application.Memo,
+		ApprovalDetail: string(application.ApprovalDetail),
 		Revision: core.Revision{
 			Creator:   application.Creator,
 			Reviser:   application.Reviser,
@@ -25,6 +25,7 @@ import (
 
 	"hcm/cmd/data-service/service/capability"
 	"hcm/pkg/api/core"
+	coreapplication "hcm/pkg/api/core/application"
 	proto "hcm/pkg/api/data-service"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
@@ -33,8 +34,11 @@ import (
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/dal/dao/types"
 	tableapplication "hcm/pkg/dal/table/application"
+	tabletype "hcm/pkg/dal/table/types"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -70,9 +74,19 @@ func (svc *approvalProcessSvc) Create(cts *rest.Contexts) (interface{}, error) {
 	process := &tableapplication.ApprovalProcessTable{
 		ApplicationType: string(req.ApplicationType),
 		ServiceID:       req.ServiceID,
+		Managers:        req.Managers,
+		Engine:          string(req.Engine),
+		TimeoutHours:    converter.ValToPtr(req.TimeoutHours),
 		Creator:         cts.Kit.User,
 		Reviser:         cts.Kit.User,
 	}
+	if len(req.Stages) != 0 {
+		stages, err := json.MarshalToString(req.Stages)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		process.Stages = tabletype.JsonField(stages)
+	}
 
 	approvalProcessID, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
 		approvalProcessID, err := svc.dao.ApprovalProcess().CreateWithTx(cts.Kit, txn, process)
@@ -107,7 +121,17 @@ func (svc *approvalProcessSvc) Update(cts *rest.Contexts) (interface{}, error) {
 	}
 
 	approvalProcess := &tableapplication.ApprovalProcessTable{
-		ServiceID: req.ServiceID,
+		ServiceID:    req.ServiceID,
+		Managers:     req.Managers,
+		Engine:       string(req.Engine),
+		TimeoutHours: req.TimeoutHours,
+	}
+	if len(req.Stages) != 0 {
+		stages, err := json.MarshalToString(req.Stages)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		approvalProcess.Stages = tabletype.JsonField(stages)
 	}
 
 	err := svc.dao.ApprovalProcess().Update(cts.Kit, tools.EqualExpression("id", approvalProcessID), approvalProcess)
@@ -121,19 +145,35 @@ func (svc *approvalProcessSvc) Update(cts *rest.Contexts) (interface{}, error) {
 
 func (svc *approvalProcessSvc) convertToApprovalProcessResp(
 	approvalProcess *tableapplication.ApprovalProcessTable,
-) *proto.ApprovalProcessResp {
+) (*proto.ApprovalProcessResp, error) {
+
+	stages := make(coreapplication.ApprovalStages, 0)
+	if !approvalProcess.Stages.IsEmpty() {
+		if err := json.UnmarshalFromString(string(approvalProcess.Stages), &stages); err != nil {
+			return nil, fmt.Errorf("unmarshal approval process(%s) stages failed, err: %v", approvalProcess.ID, err)
+		}
+	}
+
+	engine := enumor.ApprovalEngine(approvalProcess.Engine)
+	if len(engine) == 0 {
+		engine = enumor.ItsmApprovalEngine
+	}
+
 	return &proto.ApprovalProcessResp{
 		ID:              approvalProcess.ID,
 		ApplicationType: enumor.ApplicationType(approvalProcess.ApplicationType),
 		ServiceID:       approvalProcess.ServiceID,
 		Managers:        approvalProcess.Managers,
+		Engine:          engine,
+		Stages:          stages,
+		TimeoutHours:    converter.PtrToVal(approvalProcess.TimeoutHours),
 		Revision: core.Revision{
 			Creator:   approvalProcess.Creator,
 			Reviser:   approvalProcess.Reviser,
 			CreatedAt: approvalProcess.CreatedAt.String(),
 			UpdatedAt: approvalProcess.UpdatedAt.String(),
 		},
-	}
+	}, nil
 }
 
 func (svc *approvalProcessSvc) List(cts *rest.Contexts) (interface{}, error) {
@@ -161,7 +201,12 @@ func (svc *approvalProcessSvc) List(cts *rest.Contexts) (interface{}, error) {
 
 	details := make([]*proto.ApprovalProcessResp, 0, len(daoApprovalProcessResp.Details))
 	for _, approvalProcess := range daoApprovalProcessResp.Details {
-		details = append(details, svc.convertToApprovalProcessResp(approvalProcess))
+		detail, err := svc.convertToApprovalProcessResp(approvalProcess)
+		if err != nil {
+			logs.Errorf("convert approval process failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+		details = append(details, detail)
 	}
 
 	return &proto.ApprovalProcessListResult{Details: details}, nil
@@ -0,0 +1,259 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"fmt"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/api/core"
+	coreapplication "hcm/pkg/api/core/application"
+	proto "hcm/pkg/api/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableapplication "hcm/pkg/dal/table/application"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// InitApplicationPolicyService ...
+func InitApplicationPolicyService(cap *capability.Capability) {
+	svc := &applicationPolicySvc{
+		dao: cap.Dao,
+	}
+	h := rest.NewHandler()
+
+	h.Add("CreateApplicationPolicy", "POST", "/application_policies/create", svc.Create)
+	h.Add("UpdateApplicationPolicy", "PATCH", "/application_policies/{id}", svc.Update)
+	h.Add("ListApplicationPolicy", "POST", "/application_policies/list", svc.List)
+	h.Add("BatchDeleteApplicationPolicy", "DELETE", "/application_policies/batch", svc.BatchDelete)
+
+	h.Load(cap.WebService)
+}
+
+type applicationPolicySvc struct {
+	dao dao.Set
+}
+
+// Create application policy.
+func (svc *applicationPolicySvc) Create(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApplicationPolicyCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	conditions, err := json.MarshalToString(req.Conditions)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tableapplication.ApplicationPolicyTable{
+		Name:            req.Name,
+		BkBizID:         req.BkBizID,
+		ApplicationType: converter.ValToPtr(string(req.ApplicationType)),
+		Effect:          string(req.Effect),
+		Conditions:      tabletype.JsonField(conditions),
+		Message:         converter.ValToPtr(req.Message),
+		Enabled:         req.Enabled,
+		Memo:            converter.ValToPtr(req.Memo),
+		Creator:         cts.Kit.User,
+		Reviser:         cts.Kit.User,
+	}
+	result, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.ApplicationPolicy().CreateWithTx(cts.Kit, txn, model)
+	})
+	if err != nil {
+		logs.Errorf("create application policy failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	id, ok := result.(string)
+	if !ok {
+		return nil, fmt.Errorf("create application policy but return id is invalid, result: %v", result)
+	}
+
+	return &core.CreateResult{ID: id}, nil
+}
+
+// Update application policy.
+func (svc *applicationPolicySvc) Update(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(proto.ApplicationPolicyUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tableapplication.ApplicationPolicyTable{
+		Name:    req.Name,
+		Effect:  string(req.Effect),
+		Message: req.Message,
+		Enabled: req.Enabled,
+		Memo:    req.Memo,
+		Reviser: cts.Kit.User,
+	}
+	if req.ApplicationType != nil {
+		model.ApplicationType = converter.ValToPtr(string(*req.ApplicationType))
+	}
+	if len(req.Conditions) != 0 {
+		conditions, err := json.MarshalToString(req.Conditions)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		model.Conditions = tabletype.JsonField(conditions)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.ApplicationPolicy().UpdateByIDWithTx(cts.Kit, txn, id, model)
+	})
+	if err != nil {
+		logs.Errorf("update application policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// List application policy.
+func (svc *applicationPolicySvc) List(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.ApplicationPolicy().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list application policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list application policy failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &proto.ApplicationPolicyListResult{Count: result.Count}, nil
+	}
+
+	details := make([]*coreapplication.ApplicationPolicy, 0, len(result.Details))
+	for _, one := range result.Details {
+		detail, err := convertToApplicationPolicy(one)
+		if err != nil {
+			logs.Errorf("convert application policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+		details = append(details, detail)
+	}
+
+	return &proto.ApplicationPolicyListResult{Details: details}, nil
+}
+
+func convertToApplicationPolicy(one *tableapplication.ApplicationPolicyTable) (
+	*coreapplication.ApplicationPolicy, error) {
+
+	conditions := make(coreapplication.PolicyConditions, 0)
+	if !one.Conditions.IsEmpty() {
+		if err := json.UnmarshalFromString(string(one.Conditions), &conditions); err != nil {
+			return nil, fmt.Errorf("unmarshal application policy(%s) conditions failed, err: %v", one.ID, err)
+		}
+	}
+
+	return &coreapplication.ApplicationPolicy{
+		ID:              one.ID,
+		Name:            one.Name,
+		BkBizID:         one.BkBizID,
+		ApplicationType: enumor.ApplicationType(converter.PtrToVal(one.ApplicationType)),
+		Effect:          enumor.ApplicationPolicyEffect(one.Effect),
+		Conditions:      conditions,
+		Message:         converter.PtrToVal(one.Message),
+		Enabled:         converter.PtrToVal(one.Enabled),
+		Memo:            converter.PtrToVal(one.Memo),
+		Revision: core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}, nil
+}
+
+// BatchDelete application policy.
+func (svc *applicationPolicySvc) BatchDelete(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listOpt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id"},
+	}
+	policies, err := svc.dao.ApplicationPolicy().List(cts.Kit, listOpt)
+	if err != nil {
+		logs.Errorf("list application policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(policies.Details) == 0 {
+		return nil, nil
+	}
+
+	ids := slice.Map(policies.Details, func(one *tableapplication.ApplicationPolicyTable) string { return one.ID })
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.ApplicationPolicy().DeleteWithTx(cts.Kit, txn, tools.ContainersExpression("id", ids))
+	})
+	if err != nil {
+		logs.Errorf("delete application policy failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
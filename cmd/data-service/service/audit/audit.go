@@ -33,6 +33,7 @@ import (
 	"hcm/pkg/dal/dao"
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/objectstore"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 )
@@ -40,8 +41,9 @@ import (
 // InitAuditService initial the Audit service
 func InitAuditService(cap *capability.Capability) {
 	svc := &svc{
-		cloudAudit: cloud.NewCloudAudit(cap.Dao),
-		dao:        cap.Dao,
+		cloudAudit:  cloud.NewCloudAudit(cap.Dao),
+		dao:         cap.Dao,
+		objectStore: cap.ObjectStore,
 	}
 
 	h := rest.NewHandler()
@@ -58,14 +60,18 @@ func InitAuditService(cap *capability.Capability) {
 		svc.cloudAudit.CloudResourceRecycleAudit)
 	h.Add("ListAudit", http.MethodPost, "/audits/list", svc.ListAudit)
 	h.Add("GetAudit", http.MethodGet, "/audits/{id}", svc.GetAudit)
+	h.Add("ListAuditChain", http.MethodPost, "/audits/chains/list", svc.ListAuditChain)
+	h.Add("VerifyAuditChain", http.MethodPost, "/audits/chains/verify", svc.VerifyAuditChain)
+	h.Add("ExportAuditChain", http.MethodPost, "/audits/chains/export", svc.ExportAuditChain)
 
 	h.Load(cap.WebService)
 }
 
 // Audit define audit service.
 type svc struct {
-	cloudAudit *cloud.Audit
-	dao        dao.Set
+	cloudAudit  *cloud.Audit
+	dao         dao.Set
+	objectStore objectstore.Storage
 }
 
 // ListAudit list audits.
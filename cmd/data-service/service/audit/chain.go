@@ -0,0 +1,312 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"hcm/pkg/api/core"
+	coreaudit "hcm/pkg/api/core/audit"
+	proto "hcm/pkg/api/data-service/audit"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// ListAuditChain list audit hash chains.
+func (svc *svc) ListAuditChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.Audit().ListChain(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list audit chain failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list audit chain failed, err: %v", err)
+	}
+	if req.Page.Count {
+		return &proto.ChainListResult{Count: result.Count}, nil
+	}
+
+	details := make([]coreaudit.Chain, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, coreaudit.Chain{
+			Chain:      one.Chain,
+			LastSeq:    one.LastSeq,
+			LastHash:   one.LastHash,
+			ExportPath: one.ExportPath,
+			ExportedAt: one.ExportedAt,
+			CreatedAt:  one.CreatedAt.String(),
+			UpdatedAt:  one.UpdatedAt.String(),
+		})
+	}
+
+	return &proto.ChainListResult{Details: details}, nil
+}
+
+// VerifyAuditChain verify the audit hash chain, to find out whether the audits are deleted or modified.
+func (svc *svc) VerifyAuditChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ChainVerifyReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	head, err := svc.getChain(cts.Kit, req.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.verifyChain(cts.Kit, head, nil)
+}
+
+func (svc *svc) getChain(kt *kit.Kit, chain string) (*tableaudit.AuditChainTable, error) {
+	opt := &types.ListOption{
+		Filter: tools.EqualExpression("chain", chain),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.Audit().ListChain(kt, opt)
+	if err != nil {
+		logs.Errorf("list audit chain failed, err: %v, chain: %s, rid: %s", err, chain, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "audit chain %s not found", chain)
+	}
+
+	return &result.Details[0], nil
+}
+
+// verifyChain walks through the audits of the chain in seq order, and checks the seq continuity, the hash of each
+// audit and the link to the previous audit. visit is called with every audit if it is not nil.
+func (svc *svc) verifyChain(kt *kit.Kit, head *tableaudit.AuditChainTable,
+	visit func(one *tableaudit.AuditTable) error) (*coreaudit.ChainVerifyResult, error) {
+
+	result := &coreaudit.ChainVerifyResult{Chain: head.Chain, Issues: make([]coreaudit.ChainIssue, 0)}
+	addIssue := func(issueType enumor.AuditChainIssueType, seq, auditID uint64, format string, args ...interface{}) {
+		if len(result.Issues) < coreaudit.ChainVerifyMaxIssues {
+			result.Issues = append(result.Issues, coreaudit.ChainIssue{Type: issueType, Seq: seq, AuditID: auditID,
+				Message: fmt.Sprintf(format, args...)})
+		}
+	}
+
+	var lastSeq uint64
+	var lastHash string
+	for {
+		audits, err := svc.dao.Audit().ListChainAudits(kt, head.Chain, lastSeq, core.DefaultMaxPageLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		for idx := range audits {
+			one := &audits[idx]
+			if one.Seq != lastSeq+1 {
+				addIssue(enumor.AuditChainGap, lastSeq+1, 0, "audits of seq %d to %d are missing", lastSeq+1,
+					one.Seq-1)
+			}
+
+			if one.PrevHash != lastHash {
+				addIssue(enumor.AuditChainBrokenLink, one.Seq, one.ID, "prev hash %s is not the hash %s of seq %d",
+					one.PrevHash, lastHash, lastSeq)
+			}
+
+			hash, err := one.ComputeHash()
+			if err != nil {
+				logs.Errorf("compute audit hash failed, err: %v, id: %d, rid: %s", err, one.ID, kt.Rid)
+				return nil, err
+			}
+
+			if hash != one.Hash {
+				addIssue(enumor.AuditChainModified, one.Seq, one.ID, "audit content does not match its hash")
+			}
+
+			if visit != nil {
+				if err = visit(one); err != nil {
+					return nil, err
+				}
+			}
+
+			result.Count++
+			lastSeq, lastHash = one.Seq, one.Hash
+		}
+
+		if uint(len(audits)) < core.DefaultMaxPageLimit {
+			break
+		}
+	}
+
+	if lastSeq != head.LastSeq || lastHash != head.LastHash {
+		addIssue(enumor.AuditChainTailMissing, lastSeq+1, 0, "chain head is seq %d with hash %s, but the last audit"+
+			" is seq %d with hash %s", head.LastSeq, head.LastHash, lastSeq, lastHash)
+	}
+
+	result.LastSeq, result.LastHash = lastSeq, lastHash
+	result.Verified = len(result.Issues) == 0
+	return result, nil
+}
+
+// ExportAuditChain export the audits of the chain as a segment to the object store, with a signed manifest.
+func (svc *svc) ExportAuditChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ChainExportReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if svc.objectStore == nil {
+		return nil, errf.New(errf.Aborted, "object store is not configured, can not export audit chain")
+	}
+
+	conf := cc.DataService().AuditChain
+	if len(conf.SignKey) == 0 {
+		return nil, errf.New(errf.Aborted, "audit chain sign key is not configured, can not export audit chain")
+	}
+
+	head, err := svc.getChain(cts.Kit, req.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	segmentPath := path.Join(conf.ExportPrefix, head.Chain, "audit.jsonl")
+	manifestPath := path.Join(conf.ExportPrefix, head.Chain, "manifest.json")
+
+	// the segment is a json line file of the audits in seq order, it is streamed to the object store while the
+	// chain is verified, so that the audits of the chain are not buffered in memory.
+	reader, writer := io.Pipe()
+	uploadErr := make(chan error, 1)
+	go func() {
+		err := svc.objectStore.Upload(cts.Kit, segmentPath, reader)
+		// stop the writer if the upload is aborted, otherwise it will be blocked.
+		reader.CloseWithError(err)
+		uploadErr <- err
+	}()
+
+	segmentHash := sha256.New()
+	encoder := json.NewEncoder(io.MultiWriter(writer, segmentHash))
+	var firstSeq uint64
+	verifyResult, err := svc.verifyChain(cts.Kit, head, func(one *tableaudit.AuditTable) error {
+		if firstSeq == 0 {
+			firstSeq = one.Seq
+		}
+		return encoder.Encode(one)
+	})
+	writer.CloseWithError(err)
+
+	if upErr := <-uploadErr; upErr != nil {
+		logs.Errorf("upload audit chain segment failed, err: %v, path: %s, rid: %s", upErr, segmentPath,
+			cts.Kit.Rid)
+		if err == nil {
+			err = upErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &coreaudit.ChainManifest{
+		Chain:         head.Chain,
+		Count:         verifyResult.Count,
+		FirstSeq:      firstSeq,
+		LastSeq:       verifyResult.LastSeq,
+		LastHash:      verifyResult.LastHash,
+		Verified:      verifyResult.Verified,
+		SegmentPath:   segmentPath,
+		SegmentSha256: hex.EncodeToString(segmentHash.Sum(nil)),
+		ExportedAt:    time.Now().Format(constant.TimeStdFormat),
+	}
+	if manifest.Signature, err = signManifest(manifest, conf.SignKey); err != nil {
+		return nil, err
+	}
+
+	manifestRaw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.objectStore.Upload(cts.Kit, manifestPath, bytes.NewReader(manifestRaw)); err != nil {
+		logs.Errorf("upload audit chain manifest failed, err: %v, path: %s, rid: %s", err, manifestPath,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	if err = svc.dao.Audit().UpdateChainExport(cts.Kit, head.Chain, manifestPath, manifest.ExportedAt); err != nil {
+		return nil, err
+	}
+
+	if !verifyResult.Verified {
+		logs.Warnf("audit chain %s is exported with %d issues, rid: %s", head.Chain, len(verifyResult.Issues),
+			cts.Kit.Rid)
+	}
+
+	return &coreaudit.ChainExportResult{
+		SegmentPath:  segmentPath,
+		ManifestPath: manifestPath,
+		Count:        manifest.Count,
+		Verified:     manifest.Verified,
+		Signature:    manifest.Signature,
+	}, nil
+}
+
+// signManifest returns the hex encoded HMAC-SHA256 of the manifest json without signature.
+func signManifest(manifest *coreaudit.ChainManifest, key string) (string, error) {
+	unsigned := *manifest
+	unsigned.Signature = ""
+
+	raw, err := json.Marshal(unsigned)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(raw)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,76 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	protoaudit "hcm/pkg/api/data-service/audit"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+)
+
+// bandwidthPackageOperationAuditBuild 带宽包未纳入hcm资源管理，资源ID为云上ID，关联资源为带宽包所属账号
+func (ad Audit) bandwidthPackageOperationAuditBuild(kt *kit.Kit, ops []protoaudit.CloudResourceOperationInfo) (
+	[]*tableaudit.AuditTable, error) {
+
+	accountIDs := make([]string, 0, len(ops))
+	for _, one := range ops {
+		if one.AssociatedResType != enumor.AccountAuditResType || len(one.AssociatedResID) == 0 {
+			return nil, errf.Newf(errf.InvalidParameter, "bandwidth package %s associated account is required",
+				one.ResID)
+		}
+		accountIDs = append(accountIDs, one.AssociatedResID)
+	}
+
+	idAccountMap, err := ad.listAccount(kt, accountIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	audits := make([]*tableaudit.AuditTable, 0, len(ops))
+	for _, one := range ops {
+		account, exist := idAccountMap[one.AssociatedResID]
+		if !exist {
+			return nil, errf.Newf(errf.RecordNotFound, "account: %s not found", one.AssociatedResID)
+		}
+
+		action, err := one.Action.ConvAuditAction()
+		if err != nil {
+			return nil, err
+		}
+
+		audits = append(audits, &tableaudit.AuditTable{
+			ResID:      one.ResID,
+			CloudResID: one.ResID,
+			ResType:    enumor.BandwidthPackageAuditResType,
+			Action:     action,
+			Vendor:     enumor.Vendor(account.Vendor),
+			AccountID:  account.ID,
+			Operator:   kt.User,
+			Source:     kt.GetRequestSource(),
+			Rid:        kt.Rid,
+			AppCode:    kt.AppCode,
+			Detail:     &tableaudit.BasicDetail{},
+		})
+	}
+
+	return audits, nil
+}
@@ -89,6 +89,8 @@ func (ad Audit) buildOperationAuditInfo(kt *kit.Kit, resType enumor.AuditResourc
 		audits, err = ad.diskOperationAuditBuild(kt, operations)
 	case enumor.TargetGroupAuditResType:
 		audits, err = ad.loadBalancer.TargetGroupOperationAuditBuild(kt, operations)
+	case enumor.BandwidthPackageAuditResType:
+		audits, err = ad.bandwidthPackageOperationAuditBuild(kt, operations)
 	default:
 		return nil, fmt.Errorf("cloud resource type: %s not support", resType)
 	}
@@ -50,8 +50,10 @@ func (ad Audit) CloudResourceRecycleAudit(cts *rest.Contexts) (interface{}, erro
 	}
 
 	resIDs := make([]string, 0, len(req.Infos))
+	dataMap := make(map[string]interface{}, len(req.Infos))
 	for _, info := range req.Infos {
 		resIDs = append(resIDs, info.ResID)
+		dataMap[info.ResID] = info.Data
 	}
 	infos, err := ad.dao.RecycleRecord().ListResourceInfo(cts.Kit, resType, resIDs)
 	if err != nil {
@@ -63,7 +65,7 @@ func (ad Audit) CloudResourceRecycleAudit(cts *rest.Contexts) (interface{}, erro
 	}
 
 	audits := make([]*tableaudit.AuditTable, 0, len(infos))
-	for idx, info := range infos {
+	for _, info := range infos {
 		audits = append(audits, &tableaudit.AuditTable{
 			ResID:      info.ID,
 			CloudResID: info.CloudID,
@@ -78,7 +80,7 @@ func (ad Audit) CloudResourceRecycleAudit(cts *rest.Contexts) (interface{}, erro
 			Rid:        cts.Kit.Rid,
 			AppCode:    cts.Kit.AppCode,
 			Detail: &tableaudit.BasicDetail{
-				Data: req.Infos[idx].Data,
+				Data: dataMap[info.ID],
 			},
 		})
 	}
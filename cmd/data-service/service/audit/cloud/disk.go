@@ -92,10 +92,14 @@ func (ad Audit) diskAssignAuditBuild(kt *kit.Kit, assigns []protoaudit.CloudReso
 func (ad Audit) diskOperationAuditBuild(kt *kit.Kit, ops []protoaudit.CloudResourceOperationInfo) (
 	[]*tableaudit.AuditTable, error,
 ) {
+	baseOps := make([]protoaudit.CloudResourceOperationInfo, 0)
 	assCvmOps := make([]protoaudit.CloudResourceOperationInfo, 0)
 
 	for _, op := range ops {
 		switch op.Action {
+		case protoaudit.Renew, protoaudit.SetAutoRenew, protoaudit.CreateSnapshot, protoaudit.DeleteSnapshot,
+			protoaudit.RollbackSnapshot, protoaudit.Resize:
+			baseOps = append(baseOps, op)
 		case protoaudit.Associate, protoaudit.Disassociate:
 			switch op.AssociatedResType {
 			case enumor.CvmAuditResType:
@@ -109,7 +113,15 @@ func (ad Audit) diskOperationAuditBuild(kt *kit.Kit, ops []protoaudit.CloudResou
 	}
 
 	audits := make([]*tableaudit.AuditTable, 0, len(ops))
-	if len(ops) != 0 {
+	if len(baseOps) != 0 {
+		audit, err := ad.diskBaseOperationAuditBuild(kt, baseOps)
+		if err != nil {
+			return nil, err
+		}
+		audits = append(audits, audit...)
+	}
+
+	if len(assCvmOps) != 0 {
 		audit, err := ad.diskAssCvmOperationAuditBuild(kt, assCvmOps)
 		if err != nil {
 			return nil, err
@@ -119,6 +131,50 @@ func (ad Audit) diskOperationAuditBuild(kt *kit.Kit, ops []protoaudit.CloudResou
 	return audits, nil
 }
 
+func (ad Audit) diskBaseOperationAuditBuild(kt *kit.Kit, ops []protoaudit.CloudResourceOperationInfo) (
+	[]*tableaudit.AuditTable, error,
+) {
+	ids := make([]string, 0, len(ops))
+	for _, one := range ops {
+		ids = append(ids, one.ResID)
+	}
+
+	diskIDMap, err := ad.listDisk(kt, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	audits := make([]*tableaudit.AuditTable, 0, len(ops))
+	for _, one := range ops {
+		diskData, exist := diskIDMap[one.ResID]
+		if !exist {
+			continue
+		}
+
+		action, err := one.Action.ConvAuditAction()
+		if err != nil {
+			return nil, err
+		}
+
+		audits = append(audits, &tableaudit.AuditTable{
+			ResID:      diskData.ID,
+			CloudResID: diskData.CloudID,
+			ResName:    diskData.Name,
+			ResType:    enumor.DiskAuditResType,
+			Action:     action,
+			BkBizID:    diskData.BkBizID,
+			Vendor:     enumor.Vendor(diskData.Vendor),
+			AccountID:  diskData.AccountID,
+			Operator:   kt.User,
+			Source:     kt.GetRequestSource(),
+			Rid:        kt.Rid,
+			AppCode:    kt.AppCode,
+			Detail:     &tableaudit.BasicDetail{},
+		})
+	}
+	return audits, nil
+}
+
 func (ad Audit) diskAssCvmOperationAuditBuild(
 	kt *kit.Kit,
 	ops []protoaudit.CloudResourceOperationInfo,
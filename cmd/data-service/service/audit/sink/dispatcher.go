@@ -0,0 +1,334 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sink
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao"
+	daoaudit "hcm/pkg/dal/dao/audit"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/metrics"
+	"hcm/pkg/serviced"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Dispatcher delivers the audits in outbox to all the sinks periodically on master node, the outbox events
+// are deleted after they are delivered to all the sinks, otherwise they are retried with exponential backoff
+// only to the sinks that they are not delivered to, and kept as dead letters after max attempts.
+type Dispatcher struct {
+	dao    dao.Set
+	state  serviced.State
+	conf   cc.AuditSink
+	sinks  []Sink
+	metric *dispatchMetric
+}
+
+// NewDispatcher new audit sink dispatcher.
+func NewDispatcher(daoSet dao.Set, state serviced.State, conf cc.AuditSink) (*Dispatcher, error) {
+	sinks := make([]Sink, 0, len(conf.Sinks))
+	for _, opt := range conf.Sinks {
+		sink, err := newSink(opt)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	return &Dispatcher{
+		dao:    daoSet,
+		state:  state,
+		conf:   conf,
+		sinks:  sinks,
+		metric: initDispatchMetric(metrics.Register()),
+	}, nil
+}
+
+// Run the dispatcher loop.
+func (d *Dispatcher) Run() {
+	interval := time.Duration(d.conf.IntervalMS) * time.Millisecond
+	logs.Infof("audit sink dispatcher is enabled, interval: %v, sinks: %d", interval, len(d.sinks))
+
+	for {
+		time.Sleep(interval)
+
+		if !d.state.IsMaster() {
+			continue
+		}
+
+		// keep dispatching until the due outbox events are drained.
+		for {
+			count, err := d.dispatch(core.NewBackendKit())
+			if err != nil || count < d.conf.BatchSize {
+				break
+			}
+		}
+	}
+}
+
+// dispatch one batch of the due outbox events, returns the count of outbox events handled.
+func (d *Dispatcher) dispatch(kt *kit.Kit) (uint, error) {
+	now := time.Now().Unix()
+	events, err := d.dao.Audit().ListDueOutbox(kt, now, d.conf.BatchSize)
+	if err != nil {
+		logs.Errorf("list due audit outbox failed, err: %v, rid: %s", err, kt.Rid)
+		return 0, err
+	}
+
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	audits, missingIDs, err := d.listAudits(kt, events)
+	if err != nil {
+		return 0, err
+	}
+
+	// the audit of the outbox event is not found, which can not be delivered any more.
+	if len(missingIDs) != 0 {
+		logs.Warnf("audits of outbox %v are not found, skip them, rid: %s", missingIDs, kt.Rid)
+		if err = d.dao.Audit().DeleteOutbox(kt, missingIDs); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(audits) == 0 {
+		return uint(len(events)), nil
+	}
+
+	doneIDs, delays := d.deliver(kt, now, events, audits)
+	for _, delay := range delays {
+		if err = d.dao.Audit().DelayOutbox(kt, delay); err != nil {
+			return 0, err
+		}
+
+		if delay.Status == enumor.AuditOutboxDeadLetter {
+			logs.Errorf("audit outbox %v reach max attempts %d, move to dead letter, err: %s, rid: %s", delay.IDs,
+				d.conf.MaxAttempts, delay.LastError, kt.Rid)
+			d.metric.deadLetterCounter.Add(float64(len(delay.IDs)))
+		}
+	}
+
+	if len(doneIDs) != 0 {
+		if err = d.dao.Audit().DeleteOutbox(kt, doneIDs); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(delays) != 0 {
+		return 0, fmt.Errorf("deliver audits of %d outbox events failed", len(events)-len(doneIDs))
+	}
+
+	return uint(len(events)), nil
+}
+
+// deliver the audits of the outbox events to the sinks that they are not delivered to yet. returns the ids of the
+// events delivered to all the sinks, and the delays of the events that failed to be delivered to some sinks, the
+// events that fail after max attempts are delayed as dead letters.
+func (d *Dispatcher) deliver(kt *kit.Kit, now int64, events []tableaudit.AuditOutboxTable,
+	audits map[uint64]tableaudit.AuditTable) ([]uint64, []*daoaudit.OutboxDelay) {
+
+	delivered := make(map[uint64]map[string]struct{}, len(audits))
+	for _, one := range events {
+		if _, exists := audits[one.ID]; !exists {
+			continue
+		}
+
+		delivered[one.ID] = make(map[string]struct{}, len(one.DeliveredSinks))
+		for _, name := range one.DeliveredSinks {
+			delivered[one.ID][name] = struct{}{}
+		}
+	}
+
+	errs := make([]string, 0)
+	for _, sink := range d.sinks {
+		ids := make([]uint64, 0)
+		pending := make([]tableaudit.AuditTable, 0)
+		for _, one := range events {
+			sinks, exists := delivered[one.ID]
+			if !exists {
+				continue
+			}
+			if _, done := sinks[sink.Name()]; !done {
+				ids = append(ids, one.ID)
+				pending = append(pending, audits[one.ID])
+			}
+		}
+
+		if len(pending) == 0 {
+			continue
+		}
+
+		if err := sink.Send(kt, pending); err != nil {
+			logs.Errorf("send %d audits to sink %s failed, err: %v, rid: %s", len(pending), sink.Name(), err, kt.Rid)
+			d.metric.deliverCounter.WithLabelValues(sink.Name(), "failed").Add(float64(len(pending)))
+			errs = append(errs, fmt.Sprintf("%s: %v", sink.Name(), err))
+			continue
+		}
+
+		d.metric.deliverCounter.WithLabelValues(sink.Name(), "success").Add(float64(len(pending)))
+		for _, id := range ids {
+			delivered[id][sink.Name()] = struct{}{}
+		}
+	}
+
+	// the events with the same delivered sinks and attempts are delayed together.
+	doneIDs := make([]uint64, 0)
+	delayMap := make(map[string]*daoaudit.OutboxDelay)
+	delays := make([]*daoaudit.OutboxDelay, 0)
+	for _, one := range events {
+		sinks, exists := delivered[one.ID]
+		if !exists {
+			continue
+		}
+
+		if d.deliveredAll(sinks) {
+			doneIDs = append(doneIDs, one.ID)
+			continue
+		}
+
+		names := make([]string, 0, len(sinks))
+		for name := range sinks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		key := fmt.Sprintf("%d/%s", one.Attempts, strings.Join(names, ","))
+		delay, exists := delayMap[key]
+		if !exists {
+			delay = &daoaudit.OutboxDelay{
+				DeliveredSinks: names,
+				NextRetryAt:    now + d.backoff(one.Attempts),
+				LastError:      strings.Join(errs, "; "),
+				Status:         enumor.AuditOutboxPending,
+			}
+			if d.conf.MaxAttempts > 0 && one.Attempts+1 >= d.conf.MaxAttempts {
+				delay.Status = enumor.AuditOutboxDeadLetter
+			}
+			delayMap[key] = delay
+			delays = append(delays, delay)
+		}
+		delay.IDs = append(delay.IDs, one.ID)
+	}
+
+	return doneIDs, delays
+}
+
+// deliveredAll returns whether the audit is delivered to all the sinks.
+func (d *Dispatcher) deliveredAll(delivered map[string]struct{}) bool {
+	for _, sink := range d.sinks {
+		if _, exists := delivered[sink.Name()]; !exists {
+			return false
+		}
+	}
+
+	return true
+}
+
+// listAudits list the audits of the outbox events mapped by outbox event id, and returns the ids of the outbox
+// events whose audit is not found.
+func (d *Dispatcher) listAudits(kt *kit.Kit, events []tableaudit.AuditOutboxTable) (
+	map[uint64]tableaudit.AuditTable, []uint64, error) {
+
+	chainSeqs := make(map[string][]uint64)
+	for _, one := range events {
+		chainSeqs[one.AuditChain] = append(chainSeqs[one.AuditChain], one.AuditSeq)
+	}
+
+	auditMap := make(map[string]tableaudit.AuditTable, len(events))
+	for chain, seqs := range chainSeqs {
+		audits, err := d.dao.Audit().ListChainAuditsBySeq(kt, chain, seqs)
+		if err != nil {
+			logs.Errorf("list audit chain %s audits by seq failed, err: %v, rid: %s", chain, err, kt.Rid)
+			return nil, nil, err
+		}
+
+		for _, one := range audits {
+			auditMap[chainSeqKey(one.Chain, one.Seq)] = one
+		}
+	}
+
+	audits := make(map[uint64]tableaudit.AuditTable, len(events))
+	missingIDs := make([]uint64, 0)
+	for _, one := range events {
+		audit, exists := auditMap[chainSeqKey(one.AuditChain, one.AuditSeq)]
+		if !exists {
+			missingIDs = append(missingIDs, one.ID)
+			continue
+		}
+		audits[one.ID] = audit
+	}
+
+	return audits, missingIDs, nil
+}
+
+// backoff returns the seconds to wait before the next retry, which is doubled with every attempt and capped
+// by the max retry backoff.
+func (d *Dispatcher) backoff(attempts uint) int64 {
+	backoff := float64(d.conf.RetryBackoffSec) * math.Pow(2, float64(attempts))
+	if backoff > float64(d.conf.MaxRetryBackoffSec) {
+		return int64(d.conf.MaxRetryBackoffSec)
+	}
+
+	return int64(backoff)
+}
+
+func chainSeqKey(chain string, seq uint64) string {
+	return fmt.Sprintf("%s/%d", chain, seq)
+}
+
+type dispatchMetric struct {
+	// deliverCounter record the delivered audit count by sink name and success or failed result.
+	deliverCounter *prometheus.CounterVec
+	// deadLetterCounter record the count of outbox events moved to dead letter after max attempts.
+	deadLetterCounter prometheus.Counter
+}
+
+func initDispatchMetric(register prometheus.Registerer) *dispatchMetric {
+	m := new(dispatchMetric)
+
+	m.deliverCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.AuditSinkSubSys,
+		Name:      "deliver_total",
+		Help:      "the total count of audits delivered to the sinks, labeled by sink name and result",
+	}, []string{"sink", "result"})
+	register.MustRegister(m.deliverCounter)
+
+	m.deadLetterCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.AuditSinkSubSys,
+		Name:      "dead_letter_total",
+		Help:      "the total count of audit outbox events moved to dead letter after max delivery attempts",
+	})
+	register.MustRegister(m.deadLetterCounter)
+
+	return m
+}
@@ -0,0 +1,76 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"hcm/pkg/cc"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+)
+
+// fileSink appends the audits to a local file as json lines, it is used for local testing.
+type fileSink struct {
+	name string
+
+	lock sync.Mutex
+	file *os.File
+}
+
+func newFileSink(name string, opt cc.AuditFileSinkOption) (*fileSink, error) {
+	file, err := os.OpenFile(opt.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit sink file %s failed, err: %v", opt.Path, err)
+	}
+
+	return &fileSink{name: name, file: file}, nil
+}
+
+// Name returns the sink name.
+func (s *fileSink) Name() string {
+	return s.name
+}
+
+// Send audits to file.
+func (s *fileSink) Send(_ *kit.Kit, audits []tableaudit.AuditTable) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	encoder := json.NewEncoder(s.file)
+	for idx := range audits {
+		if err := encoder.Encode(audits[idx]); err != nil {
+			return fmt.Errorf("write audit to file failed, err: %v", err)
+		}
+	}
+
+	return s.file.Sync()
+}
+
+// Close the file.
+func (s *fileSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.file.Close()
+}
@@ -0,0 +1,54 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package sink delivers the audits to the external sinks, such as SIEM, through the audit outbox.
+package sink
+
+import (
+	"fmt"
+
+	"hcm/pkg/cc"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+)
+
+// Sink is the external system that the audits are delivered to.
+type Sink interface {
+	// Name returns the unique name of the sink.
+	Name() string
+	// Send the audits to the sink, the audits may be sent more than once if the delivery is retried,
+	// the receiver can use the chain and seq of the audit to de-duplicate.
+	Send(kt *kit.Kit, audits []tableaudit.AuditTable) error
+	// Close release the resources of the sink.
+	Close() error
+}
+
+// newSink create sink by option.
+func newSink(opt cc.AuditSinkOption) (Sink, error) {
+	switch opt.Type {
+	case cc.SyslogAuditSink:
+		return newSyslogSink(opt.Name, opt.Syslog), nil
+	case cc.WebhookAuditSink:
+		return newWebhookSink(opt.Name, opt.Webhook), nil
+	case cc.FileAuditSink:
+		return newFileSink(opt.Name, opt.File)
+	default:
+		return nil, fmt.Errorf("unsupported audit sink type: %s", opt.Type)
+	}
+}
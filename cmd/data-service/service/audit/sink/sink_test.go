@@ -0,0 +1,143 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sink
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestSyslogFormat(t *testing.T) {
+	s := newSyslogSink("siem", cc.AuditSyslogSink{Network: "udp", AppName: "bk-hcm", Facility: 13})
+	msg, err := s.format(&tableaudit.AuditTable{ID: 1, Chain: "2024-10-29", Seq: 1})
+	if err != nil {
+		t.Fatalf("format syslog message failed, err: %v", err)
+	}
+
+	// facility 13 * 8 + severity 5
+	if !strings.HasPrefix(string(msg), "<109>1 ") {
+		t.Errorf("unexpected syslog header: %s", msg)
+	}
+
+	if !strings.Contains(string(msg), " bk-hcm ") || !strings.Contains(string(msg), " audit - {") {
+		t.Errorf("unexpected syslog message: %s", msg)
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`[{"id":1}]`)
+	sign := Sign("secret", "1730160000", body)
+	if len(sign) != 64 {
+		t.Errorf("unexpected signature length: %d", len(sign))
+	}
+
+	if sign != Sign("secret", "1730160000", body) {
+		t.Errorf("signature is not stable")
+	}
+
+	if sign == Sign("secret", "1730160001", body) || sign == Sign("other", "1730160000", body) {
+		t.Errorf("signature should change with timestamp and secret")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := &Dispatcher{conf: cc.AuditSink{RetryBackoffSec: 5, MaxRetryBackoffSec: 600}}
+	expects := map[uint]int64{0: 5, 1: 10, 3: 40, 7: 600, 30: 600}
+	for attempts, expect := range expects {
+		if got := d.backoff(attempts); got != expect {
+			t.Errorf("backoff of %d attempts expect %d, but got %d", attempts, expect, got)
+		}
+	}
+}
+
+type fakeSink struct {
+	name string
+	fail bool
+	sent []uint64
+}
+
+func (f *fakeSink) Name() string { return f.name }
+
+func (f *fakeSink) Send(_ *kit.Kit, audits []tableaudit.AuditTable) error {
+	if f.fail {
+		return errors.New("unavailable")
+	}
+	for _, one := range audits {
+		f.sent = append(f.sent, one.ID)
+	}
+	return nil
+}
+
+func (f *fakeSink) Close() error { return nil }
+
+func TestDeliver(t *testing.T) {
+	good, bad := &fakeSink{name: "good"}, &fakeSink{name: "bad", fail: true}
+	d := &Dispatcher{
+		conf:   cc.AuditSink{RetryBackoffSec: 5, MaxRetryBackoffSec: 600, MaxAttempts: 3},
+		sinks:  []Sink{good, bad},
+		metric: initDispatchMetric(prometheus.NewRegistry()),
+	}
+
+	events := []tableaudit.AuditOutboxTable{
+		{ID: 1, Attempts: 0},
+		{ID: 2, Attempts: 1, DeliveredSinks: types.StringArray{"good"}},
+		{ID: 3, Attempts: 2, DeliveredSinks: types.StringArray{"good"}},
+		{ID: 4, Attempts: 1, DeliveredSinks: types.StringArray{"bad"}},
+	}
+	audits := map[uint64]tableaudit.AuditTable{1: {ID: 11}, 2: {ID: 12}, 3: {ID: 13}, 4: {ID: 14}}
+
+	doneIDs, delays := d.deliver(kit.New(), 100, events, audits)
+
+	// the audits already delivered to good sink are not sent again.
+	if len(good.sent) != 2 || good.sent[0] != 11 || good.sent[1] != 14 {
+		t.Fatalf("good sink should only receive the undelivered audits, got: %v", good.sent)
+	}
+
+	if len(doneIDs) != 1 || doneIDs[0] != 4 {
+		t.Fatalf("only event 4 is delivered to all the sinks, got: %v", doneIDs)
+	}
+
+	if len(delays) != 3 {
+		t.Fatalf("events with different attempts should be delayed separately, got: %d", len(delays))
+	}
+
+	for _, delay := range delays {
+		if len(delay.DeliveredSinks) != 1 || delay.DeliveredSinks[0] != "good" {
+			t.Errorf("delivered sinks of %v should be recorded, got: %v", delay.IDs, delay.DeliveredSinks)
+		}
+
+		expect := enumor.AuditOutboxPending
+		if delay.IDs[0] == 3 {
+			expect = enumor.AuditOutboxDeadLetter
+		}
+		if delay.Status != expect {
+			t.Errorf("status of %v expect %s, but got %s", delay.IDs, expect, delay.Status)
+		}
+	}
+}
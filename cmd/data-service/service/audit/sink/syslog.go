@@ -0,0 +1,139 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"hcm/pkg/cc"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+)
+
+const (
+	// syslogVersion is the version of RFC5424 syslog protocol.
+	syslogVersion = 1
+	// syslogSeverity is the severity of the audit syslog messages, which is notice.
+	syslogSeverity = 5
+	// syslogMsgID is the MSGID of the audit syslog messages.
+	syslogMsgID = "audit"
+)
+
+// syslogSink sends every audit as a RFC5424 syslog message with the audit json as MSG.
+// the messages are sent with octet counting framing (RFC6587) on tcp.
+type syslogSink struct {
+	name     string
+	opt      cc.AuditSyslogSink
+	hostname string
+
+	lock sync.Mutex
+	conn net.Conn
+}
+
+func newSyslogSink(name string, opt cc.AuditSyslogSink) *syslogSink {
+	hostname, err := os.Hostname()
+	if err != nil || len(hostname) == 0 {
+		hostname = "-"
+	}
+
+	return &syslogSink{
+		name:     name,
+		opt:      opt,
+		hostname: hostname,
+	}
+}
+
+// Name returns the sink name.
+func (s *syslogSink) Name() string {
+	return s.name
+}
+
+// Send audits as syslog messages.
+func (s *syslogSink) Send(_ *kit.Kit, audits []tableaudit.AuditTable) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.opt.Network, s.opt.Address, 10*time.Second)
+		if err != nil {
+			return fmt.Errorf("dial syslog server %s failed, err: %v", s.opt.Address, err)
+		}
+		s.conn = conn
+	}
+
+	for idx := range audits {
+		msg, err := s.format(&audits[idx])
+		if err != nil {
+			return err
+		}
+
+		if s.opt.Network == "tcp" {
+			msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+		}
+
+		if err = s.conn.SetWriteDeadline(time.Now().Add(10 * time.Second)); err != nil {
+			return err
+		}
+
+		if _, err = s.conn.Write(msg); err != nil {
+			// reconnect on next send.
+			_ = s.conn.Close()
+			s.conn = nil
+			return fmt.Errorf("write syslog message failed, err: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// format the audit as RFC5424 syslog message:
+// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (s *syslogSink) format(audit *tableaudit.AuditTable) ([]byte, error) {
+	body, err := json.Marshal(audit)
+	if err != nil {
+		return nil, fmt.Errorf("marshal audit failed, err: %v", err)
+	}
+
+	pri := s.opt.Facility*8 + syslogSeverity
+	header := fmt.Sprintf("<%d>%d %s %s %s %d %s - ", pri, syslogVersion, time.Now().Format(time.RFC3339Nano),
+		s.hostname, s.opt.AppName, os.Getpid(), syslogMsgID)
+
+	return append([]byte(header), body...), nil
+}
+
+// Close the connection to syslog server.
+func (s *syslogSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
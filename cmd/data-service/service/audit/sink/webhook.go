@@ -0,0 +1,114 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sink
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/constant"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+)
+
+const (
+	// WebhookTimestampHeader is the header of the unix seconds that the webhook request is sent.
+	WebhookTimestampHeader = "X-Bkhcm-Timestamp"
+	// WebhookSignatureHeader is the header of the webhook request signature, which is the hex encoded
+	// HMAC-SHA256 of "{timestamp}.{body}" with the webhook secret.
+	WebhookSignatureHeader = "X-Bkhcm-Signature"
+)
+
+// webhookSink posts the audits as a json array to the webhook url with HMAC signature.
+type webhookSink struct {
+	name   string
+	opt    cc.AuditWebhookSink
+	client *http.Client
+}
+
+func newWebhookSink(name string, opt cc.AuditWebhookSink) *webhookSink {
+	return &webhookSink{
+		name:   name,
+		opt:    opt,
+		client: &http.Client{Timeout: time.Duration(opt.TimeoutSec) * time.Second},
+	}
+}
+
+// Name returns the sink name.
+func (s *webhookSink) Name() string {
+	return s.name
+}
+
+// Send audits to webhook, any non 2xx response is treated as failure.
+func (s *webhookSink) Send(kt *kit.Kit, audits []tableaudit.AuditTable) error {
+	body, err := json.Marshal(audits)
+	if err != nil {
+		return fmt.Errorf("marshal audits failed, err: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(kt.Ctx, http.MethodPost, s.opt.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(constant.RidKey, kt.Rid)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, Sign(s.opt.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post audits to webhook failed, err: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook responded with status %d, body: %s", resp.StatusCode, msg)
+	}
+
+	return nil
+}
+
+// Close the webhook sink.
+func (s *webhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of "{timestamp}.{body}", the webhook receiver can use it to verify
+// the request.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,141 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package rbac
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	coreauth "hcm/pkg/api/core/auth"
+	dataservice "hcm/pkg/api/data-service"
+	dsauth "hcm/pkg/api/data-service/auth"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/types"
+	tableauth "hcm/pkg/dal/table/auth"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BatchCreateAuthRoleBinding batch create auth role binding.
+func (svc *rbacSvc) BatchCreateAuthRoleBinding(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsauth.RoleBindingBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.checkRoleExists(cts, req.RoleID); err != nil {
+		return nil, err
+	}
+
+	models := make([]*tableauth.RoleBindingTable, 0, len(req.Users))
+	for _, user := range slice.Unique(req.Users) {
+		models = append(models, &tableauth.RoleBindingTable{
+			RoleID:  req.RoleID,
+			User:    user,
+			Creator: cts.Kit.User,
+		})
+	}
+
+	result, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.AuthRoleBinding().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("batch create auth role binding failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	ids, ok := result.([]string)
+	if !ok {
+		return nil, fmt.Errorf("batch create auth role binding but return id type is not []string, id type: %T",
+			result)
+	}
+
+	return &core.BatchCreateResult{IDs: ids}, nil
+}
+
+// ListAuthRoleBinding list auth role binding.
+func (svc *rbacSvc) ListAuthRoleBinding(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.AuthRoleBinding().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list auth role binding failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list auth role binding failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &dsauth.RoleBindingListResult{Count: result.Count}, nil
+	}
+
+	details := make([]coreauth.RoleBinding, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, coreauth.RoleBinding{
+			ID:        one.ID,
+			RoleID:    one.RoleID,
+			User:      one.User,
+			Creator:   one.Creator,
+			CreatedAt: one.CreatedAt.String(),
+		})
+	}
+
+	return &dsauth.RoleBindingListResult{Details: details}, nil
+}
+
+// BatchDeleteAuthRoleBinding batch delete auth role binding.
+func (svc *rbacSvc) BatchDeleteAuthRoleBinding(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.AuthRoleBinding().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("delete auth role binding failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,61 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package rbac 本地rbac鉴权的角色、权限策略、角色绑定数据管理
+package rbac
+
+import (
+	"net/http"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/rest"
+)
+
+// InitService initial the local rbac auth service
+func InitService(cap *capability.Capability) {
+	svc := &rbacSvc{
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CreateAuthRole", http.MethodPost, "/auth/roles/create", svc.CreateAuthRole)
+	h.Add("UpdateAuthRole", http.MethodPatch, "/auth/roles/{id}", svc.UpdateAuthRole)
+	h.Add("ListAuthRole", http.MethodPost, "/auth/roles/list", svc.ListAuthRole)
+	h.Add("BatchDeleteAuthRole", http.MethodDelete, "/auth/roles/batch", svc.BatchDeleteAuthRole)
+
+	h.Add("BatchCreateAuthRolePolicy", http.MethodPost, "/auth/role_policies/batch/create",
+		svc.BatchCreateAuthRolePolicy)
+	h.Add("ListAuthRolePolicy", http.MethodPost, "/auth/role_policies/list", svc.ListAuthRolePolicy)
+	h.Add("BatchDeleteAuthRolePolicy", http.MethodDelete, "/auth/role_policies/batch",
+		svc.BatchDeleteAuthRolePolicy)
+
+	h.Add("BatchCreateAuthRoleBinding", http.MethodPost, "/auth/role_bindings/batch/create",
+		svc.BatchCreateAuthRoleBinding)
+	h.Add("ListAuthRoleBinding", http.MethodPost, "/auth/role_bindings/list", svc.ListAuthRoleBinding)
+	h.Add("BatchDeleteAuthRoleBinding", http.MethodDelete, "/auth/role_bindings/batch",
+		svc.BatchDeleteAuthRoleBinding)
+
+	h.Load(cap.WebService)
+}
+
+type rbacSvc struct {
+	dao dao.Set
+}
@@ -0,0 +1,214 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package rbac
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	coreauth "hcm/pkg/api/core/auth"
+	dataservice "hcm/pkg/api/data-service"
+	dsauth "hcm/pkg/api/data-service/auth"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableauth "hcm/pkg/dal/table/auth"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateAuthRole create auth role.
+func (svc *rbacSvc) CreateAuthRole(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsauth.RoleCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tableauth.RoleTable{
+		Name:    req.Name,
+		Memo:    req.Memo,
+		Creator: cts.Kit.User,
+		Reviser: cts.Kit.User,
+	}
+	result, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.AuthRole().BatchCreateWithTx(cts.Kit, txn, []*tableauth.RoleTable{model})
+	})
+	if err != nil {
+		logs.Errorf("create auth role failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	ids, ok := result.([]string)
+	if !ok || len(ids) != 1 {
+		return nil, fmt.Errorf("create auth role but return ids is invalid, result: %v", result)
+	}
+
+	return &core.CreateResult{ID: ids[0]}, nil
+}
+
+// UpdateAuthRole update auth role.
+func (svc *rbacSvc) UpdateAuthRole(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(dsauth.RoleUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tableauth.RoleTable{
+		Name:    req.Name,
+		Memo:    req.Memo,
+		Reviser: cts.Kit.User,
+	}
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.AuthRole().UpdateByIDWithTx(cts.Kit, txn, id, model)
+	})
+	if err != nil {
+		logs.Errorf("update auth role failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListAuthRole list auth role.
+func (svc *rbacSvc) ListAuthRole(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.AuthRole().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list auth role failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list auth role failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &dsauth.RoleListResult{Count: result.Count}, nil
+	}
+
+	details := make([]coreauth.Role, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, coreauth.Role{
+			ID:   one.ID,
+			Name: one.Name,
+			Memo: one.Memo,
+			Revision: &core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &dsauth.RoleListResult{Details: details}, nil
+}
+
+// BatchDeleteAuthRole batch delete auth role, the policies and bindings of the role are deleted together.
+func (svc *rbacSvc) BatchDeleteAuthRole(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listOpt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id"},
+	}
+	roles, err := svc.dao.AuthRole().List(cts.Kit, listOpt)
+	if err != nil {
+		logs.Errorf("list auth role failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(roles.Details) == 0 {
+		return nil, nil
+	}
+
+	ids := slice.Map(roles.Details, func(one tableauth.RoleTable) string { return one.ID })
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		roleExpr := tools.ContainersExpression("role_id", ids)
+		if err := svc.dao.AuthRolePolicy().DeleteWithTx(cts.Kit, txn, roleExpr); err != nil {
+			return nil, err
+		}
+
+		if err := svc.dao.AuthRoleBinding().DeleteWithTx(cts.Kit, txn, roleExpr); err != nil {
+			return nil, err
+		}
+
+		return nil, svc.dao.AuthRole().DeleteWithTx(cts.Kit, txn, tools.ContainersExpression("id", ids))
+	})
+	if err != nil {
+		logs.Errorf("delete auth role failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// checkRoleExists check if the auth role exists.
+func (svc *rbacSvc) checkRoleExists(cts *rest.Contexts, roleID string) error {
+	opt := &types.ListOption{
+		Filter: tools.EqualExpression("id", roleID),
+		Page:   core.NewCountPage(),
+	}
+	result, err := svc.dao.AuthRole().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("count auth role failed, err: %v, id: %s, rid: %s", err, roleID, cts.Kit.Rid)
+		return err
+	}
+
+	if result.Count == 0 {
+		return errf.Newf(errf.RecordNotFound, "auth role %s not found", roleID)
+	}
+
+	return nil
+}
@@ -98,6 +98,7 @@ func batchCreateCvm[T corecvm.Extension](cts *rest.Contexts, svc *cvmSvc, vendor
 				OsName:               one.OsName,
 				Memo:                 one.Memo,
 				Status:               one.Status,
+				LifecycleStatus:      string(one.LifecycleStatus),
 				PrivateIPv4Addresses: one.PrivateIPv4Addresses,
 				PrivateIPv6Addresses: one.PrivateIPv6Addresses,
 				PublicIPv4Addresses:  one.PublicIPv4Addresses,
@@ -119,6 +119,7 @@ func (svc *relSvc) ListWithCvm(cts *rest.Contexts) (interface{}, error) {
 			OsName:               one.OsName,
 			Memo:                 one.Memo,
 			Status:               one.Status,
+			LifecycleStatus:      enumor.CvmLifecycleStatus(one.LifecycleStatus),
 			PrivateIPv4Addresses: one.PrivateIPv4Addresses,
 			PrivateIPv6Addresses: one.PrivateIPv6Addresses,
 			PublicIPv4Addresses:  one.PublicIPv4Addresses,
@@ -50,6 +50,20 @@ func InitService(cap *capability.Capability) {
 	h.Add("BatchDeleteDisk", http.MethodDelete, "/disks/batch", svc.BatchDeleteDisk)
 	h.Add("CountDisk", http.MethodPost, "/disks/count", svc.CountDisk)
 
+	// 云硬盘快照
+	h.Add("BatchCreateDiskSnapshot", http.MethodPost, "/disk_snapshots/batch/create", svc.BatchCreateDiskSnapshot)
+	h.Add("BatchUpdateDiskSnapshot", http.MethodPatch, "/disk_snapshots/batch/update", svc.BatchUpdateDiskSnapshot)
+	h.Add("ListDiskSnapshot", http.MethodPost, "/disk_snapshots/list", svc.ListDiskSnapshot)
+	h.Add("BatchDeleteDiskSnapshot", http.MethodDelete, "/disk_snapshots/batch", svc.BatchDeleteDiskSnapshot)
+
+	// 定期快照策略
+	h.Add("CreateDiskSnapshotPolicy", http.MethodPost, "/disk_snapshot_policies/create",
+		svc.CreateDiskSnapshotPolicy)
+	h.Add("UpdateDiskSnapshotPolicy", http.MethodPatch, "/disk_snapshot_policies/{id}", svc.UpdateDiskSnapshotPolicy)
+	h.Add("ListDiskSnapshotPolicy", http.MethodPost, "/disk_snapshot_policies/list", svc.ListDiskSnapshotPolicy)
+	h.Add("BatchDeleteDiskSnapshotPolicy", http.MethodDelete, "/disk_snapshot_policies/batch",
+		svc.BatchDeleteDiskSnapshotPolicy)
+
 	h.Load(cap.WebService)
 }
 
@@ -0,0 +1,204 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disk
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	dataservice "hcm/pkg/api/data-service"
+	dataproto "hcm/pkg/api/data-service/cloud/disk"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/types"
+	tabledisk "hcm/pkg/dal/table/cloud/disk"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BatchCreateDiskSnapshot batch create disk snapshot.
+func (dSvc *diskSvc) BatchCreateDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataproto.SnapshotBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make([]tabledisk.SnapshotTable, 0, len(req.Snapshots))
+	for _, one := range req.Snapshots {
+		models = append(models, tabledisk.SnapshotTable{
+			Vendor:           one.Vendor,
+			AccountID:        one.AccountID,
+			BkBizID:          one.BkBizID,
+			Region:           one.Region,
+			CloudID:          one.CloudID,
+			Name:             one.Name,
+			DiskID:           one.DiskID,
+			CloudDiskID:      one.CloudDiskID,
+			DiskSize:         one.DiskSize,
+			Status:           one.Status,
+			PolicyID:         one.PolicyID,
+			CloudCreatedTime: one.CloudCreatedTime,
+			Memo:             converter.ValToPtr(one.Memo),
+			Creator:          cts.Kit.User,
+			Reviser:          cts.Kit.User,
+		})
+	}
+
+	result, err := dSvc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return dSvc.dao.DiskSnapshot().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("batch create disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	ids, ok := result.([]string)
+	if !ok {
+		return nil, fmt.Errorf("batch create disk snapshot but return ids type %T is not []string", result)
+	}
+
+	return &core.BatchCreateResult{IDs: ids}, nil
+}
+
+// BatchUpdateDiskSnapshot batch update disk snapshot.
+func (dSvc *diskSvc) BatchUpdateDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataproto.SnapshotBatchUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := dSvc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		for _, one := range req.Snapshots {
+			model := &tabledisk.SnapshotTable{
+				BkBizID:  one.BkBizID,
+				Name:     one.Name,
+				DiskID:   one.DiskID,
+				DiskSize: one.DiskSize,
+				Status:   one.Status,
+				Memo:     one.Memo,
+				Reviser:  cts.Kit.User,
+			}
+
+			if err := dSvc.dao.DiskSnapshot().UpdateByIDWithTx(cts.Kit, txn, one.ID, model); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("batch update disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListDiskSnapshot list disk snapshot.
+func (dSvc *diskSvc) ListDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := dSvc.dao.DiskSnapshot().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list disk snapshot failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &dataproto.SnapshotListResult{Count: result.Count}, nil
+	}
+
+	details := make([]*coredisk.Snapshot, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, convertToSnapshot(one))
+	}
+
+	return &dataproto.SnapshotListResult{Details: details}, nil
+}
+
+// BatchDeleteDiskSnapshot batch delete disk snapshot.
+func (dSvc *diskSvc) BatchDeleteDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := dSvc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, dSvc.dao.DiskSnapshot().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("batch delete disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func convertToSnapshot(one tabledisk.SnapshotTable) *coredisk.Snapshot {
+	return &coredisk.Snapshot{
+		ID:               one.ID,
+		Vendor:           one.Vendor,
+		AccountID:        one.AccountID,
+		BkBizID:          one.BkBizID,
+		Region:           one.Region,
+		CloudID:          one.CloudID,
+		Name:             one.Name,
+		DiskID:           one.DiskID,
+		CloudDiskID:      one.CloudDiskID,
+		DiskSize:         one.DiskSize,
+		Status:           one.Status,
+		PolicyID:         one.PolicyID,
+		CloudCreatedTime: one.CloudCreatedTime,
+		Memo:             converter.PtrToVal(one.Memo),
+		Revision: core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}
+}
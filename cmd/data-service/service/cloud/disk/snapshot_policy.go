@@ -0,0 +1,190 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disk
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	dataservice "hcm/pkg/api/data-service"
+	dataproto "hcm/pkg/api/data-service/cloud/disk"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/types"
+	tabledisk "hcm/pkg/dal/table/cloud/disk"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateDiskSnapshotPolicy create disk snapshot policy.
+func (dSvc *diskSvc) CreateDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataproto.SnapshotPolicyCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := tabledisk.SnapshotPolicyTable{
+		Name:           req.Name,
+		BkBizID:        req.BkBizID,
+		Cron:           req.Cron,
+		RetentionCount: req.RetentionCount,
+		DiskIDs:        req.DiskIDs,
+		Enabled:        req.Enabled,
+		Memo:           req.Memo,
+		Creator:        cts.Kit.User,
+		Reviser:        cts.Kit.User,
+	}
+	result, err := dSvc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return dSvc.dao.DiskSnapshotPolicy().BatchCreateWithTx(cts.Kit, txn,
+			[]tabledisk.SnapshotPolicyTable{model})
+	})
+	if err != nil {
+		logs.Errorf("create disk snapshot policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	ids, ok := result.([]string)
+	if !ok || len(ids) != 1 {
+		return nil, fmt.Errorf("create disk snapshot policy but return ids %v is invalid", result)
+	}
+
+	return &core.CreateResult{ID: ids[0]}, nil
+}
+
+// UpdateDiskSnapshotPolicy update disk snapshot policy.
+func (dSvc *diskSvc) UpdateDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(dataproto.SnapshotPolicyUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tabledisk.SnapshotPolicyTable{
+		Name:           req.Name,
+		Cron:           req.Cron,
+		RetentionCount: req.RetentionCount,
+		Enabled:        req.Enabled,
+		LastExecutedAt: req.LastExecutedAt,
+		Memo:           req.Memo,
+		Reviser:        cts.Kit.User,
+	}
+	if req.DiskIDs != nil {
+		model.DiskIDs = tabletype.StringArray(*req.DiskIDs)
+	}
+
+	_, err := dSvc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, dSvc.dao.DiskSnapshotPolicy().UpdateByIDWithTx(cts.Kit, txn, id, model)
+	})
+	if err != nil {
+		logs.Errorf("update disk snapshot policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListDiskSnapshotPolicy list disk snapshot policy.
+func (dSvc *diskSvc) ListDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := dSvc.dao.DiskSnapshotPolicy().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list disk snapshot policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list disk snapshot policy failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &dataproto.SnapshotPolicyListResult{Count: result.Count}, nil
+	}
+
+	details := make([]*coredisk.SnapshotPolicy, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, &coredisk.SnapshotPolicy{
+			ID:             one.ID,
+			Name:           one.Name,
+			BkBizID:        one.BkBizID,
+			Cron:           one.Cron,
+			RetentionCount: one.RetentionCount,
+			DiskIDs:        one.DiskIDs,
+			Enabled:        converter.PtrToVal(one.Enabled),
+			LastExecutedAt: one.LastExecutedAt,
+			Memo:           converter.PtrToVal(one.Memo),
+			Revision: core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &dataproto.SnapshotPolicyListResult{Details: details}, nil
+}
+
+// BatchDeleteDiskSnapshotPolicy batch delete disk snapshot policy.
+func (dSvc *diskSvc) BatchDeleteDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := dSvc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, dSvc.dao.DiskSnapshotPolicy().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("batch delete disk snapshot policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -20,9 +20,16 @@
 package eip
 
 import (
+	"fmt"
+
+	"hcm/pkg/api/core"
 	dataproto "hcm/pkg/api/data-service/cloud/eip"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 
 	"github.com/jmoiron/sqlx"
@@ -39,8 +46,32 @@ func (svc *eipSvc) BatchDeleteEip(cts *rest.Contexts) (interface{}, error) {
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
-		return nil, svc.dao.Eip().DeleteWithTx(cts.Kit, txn, req.Filter)
+	opt := &types.ListOption{
+		Fields: []string{"id"},
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+	}
+	listResp, err := svc.dao.Eip().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list eip failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list eip failed, err: %v", err)
+	}
+
+	if len(listResp.Details) == 0 {
+		return nil, nil
+	}
+
+	delIDs := make([]string, len(listResp.Details))
+	for index, one := range listResp.Details {
+		delIDs[index] = one.ID
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := svc.dao.Eip().DeleteWithTx(cts.Kit, txn, tools.ContainersExpression("id", delIDs)); err != nil {
+			return nil, err
+		}
+
+		return nil, svc.dao.ResourceTag().DeleteByResWithTx(cts.Kit, txn, enumor.EipCloudResType, delIDs)
 	})
 	if err != nil {
 		return nil, err
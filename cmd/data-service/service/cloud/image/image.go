@@ -43,6 +43,11 @@ func InitService(cap *capability.Capability) {
 	h.Add("BatchUpdateImageExt", http.MethodPatch, "/vendors/{vendor}/images", pSvc.BatchUpdateImageExt)
 	h.Add("BatchDeleteImage", http.MethodDelete, "/images/batch", pSvc.BatchDeleteImage)
 
+	h.Add("BatchCreatePrivateImage", http.MethodPost, "/private_images/batch/create", pSvc.BatchCreatePrivateImage)
+	h.Add("BatchUpdatePrivateImage", http.MethodPatch, "/private_images/batch/update", pSvc.BatchUpdatePrivateImage)
+	h.Add("ListPrivateImage", http.MethodPost, "/private_images/list", pSvc.ListPrivateImage)
+	h.Add("BatchDeletePrivateImage", http.MethodDelete, "/private_images/batch", pSvc.BatchDeletePrivateImage)
+
 	h.Load(cap.WebService)
 }
 
@@ -0,0 +1,228 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package image
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	coreimage "hcm/pkg/api/core/cloud/image"
+	dataservice "hcm/pkg/api/data-service"
+	dataproto "hcm/pkg/api/data-service/cloud/image"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableimage "hcm/pkg/dal/table/cloud/image"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BatchCreatePrivateImage batch create private image.
+func (pSvc *imageSvc) BatchCreatePrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataproto.PrivateImageBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make([]tableimage.PrivateImageTable, 0, len(req.Images))
+	for _, one := range req.Images {
+		models = append(models, tableimage.PrivateImageTable{
+			Vendor:           one.Vendor,
+			AccountID:        one.AccountID,
+			BkBizID:          one.BkBizID,
+			Region:           one.Region,
+			CloudID:          one.CloudID,
+			Name:             one.Name,
+			SourceCvmID:      one.SourceCvmID,
+			SourceCloudCvmID: one.SourceCloudCvmID,
+			SourceImageID:    one.SourceImageID,
+			SharedAccountIDs: make(tabletype.StringArray, 0),
+			Status:           one.Status,
+			Deprecated:       converter.ValToPtr(false),
+			Memo:             converter.ValToPtr(one.Memo),
+			Creator:          cts.Kit.User,
+			Reviser:          cts.Kit.User,
+		})
+	}
+
+	result, err := pSvc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return pSvc.dao.PrivateImage().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("batch create private image failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	ids, ok := result.([]string)
+	if !ok {
+		return nil, fmt.Errorf("batch create private image but return ids type %T is not []string", result)
+	}
+
+	return &core.BatchCreateResult{IDs: ids}, nil
+}
+
+// BatchUpdatePrivateImage batch update private image.
+func (pSvc *imageSvc) BatchUpdatePrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataproto.PrivateImageBatchUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := pSvc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		for _, one := range req.Images {
+			model := &tableimage.PrivateImageTable{
+				Deprecated: one.Deprecated,
+				Memo:       one.Memo,
+				Reviser:    cts.Kit.User,
+			}
+			if one.SharedAccountIDs != nil {
+				model.SharedAccountIDs = *one.SharedAccountIDs
+			}
+			if one.Status != nil {
+				model.Status = *one.Status
+			}
+
+			if err := pSvc.dao.PrivateImage().UpdateByIDWithTx(cts.Kit, txn, one.ID, model); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("batch update private image failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BatchDeletePrivateImage batch delete private image.
+func (pSvc *imageSvc) BatchDeletePrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: []string{"id"},
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+	}
+	listResp, err := pSvc.dao.PrivateImage().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list private image failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list private image failed, err: %v", err)
+	}
+
+	if len(listResp.Details) == 0 {
+		return nil, nil
+	}
+
+	delIDs := make([]string, len(listResp.Details))
+	for index, one := range listResp.Details {
+		delIDs[index] = one.ID
+	}
+
+	_, err = pSvc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, pSvc.dao.PrivateImage().DeleteWithTx(cts.Kit, txn, tools.ContainersExpression("id", delIDs))
+	})
+	if err != nil {
+		logs.Errorf("delete private image failed, err: %v, ids: %v, rid: %s", err, delIDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListPrivateImage list private image.
+func (pSvc *imageSvc) ListPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := pSvc.dao.PrivateImage().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list private image failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list private image failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &dataproto.PrivateImageListResult{Count: result.Count}, nil
+	}
+
+	details := make([]*coreimage.PrivateImage, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, convertToPrivateImage(one))
+	}
+
+	return &dataproto.PrivateImageListResult{Details: details}, nil
+}
+
+func convertToPrivateImage(one tableimage.PrivateImageTable) *coreimage.PrivateImage {
+	return &coreimage.PrivateImage{
+		ID:               one.ID,
+		Vendor:           one.Vendor,
+		AccountID:        one.AccountID,
+		BkBizID:          one.BkBizID,
+		Region:           one.Region,
+		CloudID:          one.CloudID,
+		Name:             one.Name,
+		SourceCvmID:      one.SourceCvmID,
+		SourceCloudCvmID: one.SourceCloudCvmID,
+		SourceImageID:    one.SourceImageID,
+		SharedAccountIDs: one.SharedAccountIDs,
+		Status:           one.Status,
+		Deprecated:       converter.PtrToVal(one.Deprecated),
+		Memo:             converter.PtrToVal(one.Memo),
+		Revision: core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}
+}
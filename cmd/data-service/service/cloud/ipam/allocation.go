@@ -0,0 +1,251 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ipam
+
+import (
+	"fmt"
+	"net"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/types"
+	tableipam "hcm/pkg/dal/table/cloud/ipam"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/cidr"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BatchCreateIpamAllocation batch create ipam cidr allocation, the cidr must be contained by the pool and can not
+// overlap with the other allocations of the pool.
+func (svc *ipamSvc) BatchCreateIpamAllocation(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.IpamAllocationBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make([]*tableipam.IpamAllocationTable, 0, len(req.Allocations))
+	for _, one := range req.Allocations {
+		models = append(models, &tableipam.IpamAllocationTable{
+			PoolID:  one.PoolID,
+			BkBizID: one.BkBizID,
+			Cidr:    one.Cidr,
+			Type:    one.Type,
+			ResType: one.ResType,
+			ResID:   one.ResID,
+			Memo:    one.Memo,
+			Creator: cts.Kit.User,
+			Reviser: cts.Kit.User,
+		})
+	}
+
+	result, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := svc.validateAllocations(cts.Kit, txn, models); err != nil {
+			return nil, err
+		}
+
+		return svc.dao.IpamAllocation().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("batch create ipam cidr allocation failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	ids, ok := result.([]string)
+	if !ok {
+		return nil, fmt.Errorf("batch create ipam cidr allocation but return id type is not []string, id type: %T",
+			result)
+	}
+
+	return &core.BatchCreateResult{IDs: ids}, nil
+}
+
+// validateAllocations lock the pools of the allocations and check the allocations are contained by the pool and
+// not overlapped with the other allocations of the pool, the pools are locked until the transaction ends, so that
+// the concurrent allocations of the same pool are validated serially.
+func (svc *ipamSvc) validateAllocations(kt *kit.Kit, txn *sqlx.Tx, models []*tableipam.IpamAllocationTable) error {
+	poolIDs := slice.Unique(slice.Map(models, func(one *tableipam.IpamAllocationTable) string { return one.PoolID }))
+	pools, err := svc.dao.IpamPool().LockByIDsWithTx(kt, txn, poolIDs)
+	if err != nil {
+		return err
+	}
+
+	poolMap := make(map[string]tableipam.IpamPoolTable, len(pools))
+	for _, pool := range pools {
+		poolMap[pool.ID] = pool
+	}
+
+	allocations, err := svc.dao.IpamAllocation().ListByPoolIDsWithTx(kt, txn, poolIDs)
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string][]net.IPNet, len(poolIDs))
+	for _, one := range allocations {
+		_, n, err := net.ParseCIDR(one.Cidr)
+		if err != nil {
+			logs.Errorf("ipam cidr allocation %s has invalid cidr: %s, rid: %s", one.ID, one.Cidr, kt.Rid)
+			continue
+		}
+		used[one.PoolID] = append(used[one.PoolID], *n)
+	}
+
+	for _, model := range models {
+		pool, exists := poolMap[model.PoolID]
+		if !exists {
+			return errf.Newf(errf.RecordNotFound, "ipam cidr pool: %s not found", model.PoolID)
+		}
+
+		if pool.BkBizID != model.BkBizID {
+			return errf.Newf(errf.InvalidParameter, "ipam cidr pool: %s is not belong to biz: %d", pool.ID,
+				model.BkBizID)
+		}
+
+		if err = cidr.IsSubnetContained(pool.Cidr, model.Cidr); err != nil {
+			return errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		_, n, _ := net.ParseCIDR(model.Cidr)
+		for _, one := range used[model.PoolID] {
+			if cidr.IsOverlapped(one, *n) {
+				return errf.Newf(errf.InvalidParameter, "cidr %s overlaps with allocated cidr %s", model.Cidr,
+					one.String())
+			}
+		}
+		used[model.PoolID] = append(used[model.PoolID], *n)
+	}
+
+	return nil
+}
+
+// UpdateIpamAllocation update ipam cidr allocation.
+func (svc *ipamSvc) UpdateIpamAllocation(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protocloud.IpamAllocationUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tableipam.IpamAllocationTable{
+		ResType: req.ResType,
+		ResID:   req.ResID,
+		Memo:    req.Memo,
+		Reviser: cts.Kit.User,
+	}
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.IpamAllocation().UpdateByIDWithTx(cts.Kit, txn, id, model)
+	})
+	if err != nil {
+		logs.Errorf("update ipam cidr allocation failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListIpamAllocation list ipam cidr allocation.
+func (svc *ipamSvc) ListIpamAllocation(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.IpamAllocation().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list ipam cidr allocation failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list ipam cidr allocation failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.IpamAllocationListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.IpamAllocation, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, corecloud.IpamAllocation{
+			ID:      one.ID,
+			PoolID:  one.PoolID,
+			BkBizID: one.BkBizID,
+			Cidr:    one.Cidr,
+			Type:    one.Type,
+			ResType: one.ResType,
+			ResID:   one.ResID,
+			Memo:    one.Memo,
+			Revision: &core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &protocloud.IpamAllocationListResult{Details: details}, nil
+}
+
+// BatchDeleteIpamAllocation batch delete ipam cidr allocation, the cidr will be released to the pool.
+func (svc *ipamSvc) BatchDeleteIpamAllocation(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.IpamAllocation().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("delete ipam cidr allocation failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
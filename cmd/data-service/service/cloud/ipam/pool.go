@@ -0,0 +1,214 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ipam
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableipam "hcm/pkg/dal/table/cloud/ipam"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BatchCreateIpamPool batch create ipam cidr pool.
+func (svc *ipamSvc) BatchCreateIpamPool(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.IpamPoolBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make([]*tableipam.IpamPoolTable, 0, len(req.Pools))
+	for _, one := range req.Pools {
+		models = append(models, &tableipam.IpamPoolTable{
+			Name:    one.Name,
+			Vendor:  one.Vendor,
+			Region:  one.Region,
+			BkBizID: one.BkBizID,
+			Cidr:    one.Cidr,
+			Memo:    one.Memo,
+			Creator: cts.Kit.User,
+			Reviser: cts.Kit.User,
+		})
+	}
+
+	result, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.IpamPool().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("batch create ipam cidr pool failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	ids, ok := result.([]string)
+	if !ok {
+		return nil, fmt.Errorf("batch create ipam cidr pool but return id type is not []string, id type: %T",
+			result)
+	}
+
+	return &core.BatchCreateResult{IDs: ids}, nil
+}
+
+// UpdateIpamPool update ipam cidr pool.
+func (svc *ipamSvc) UpdateIpamPool(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protocloud.IpamPoolUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tableipam.IpamPoolTable{
+		Name:    req.Name,
+		Memo:    req.Memo,
+		Reviser: cts.Kit.User,
+	}
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.IpamPool().UpdateByIDWithTx(cts.Kit, txn, id, model)
+	})
+	if err != nil {
+		logs.Errorf("update ipam cidr pool failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListIpamPool list ipam cidr pool.
+func (svc *ipamSvc) ListIpamPool(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.IpamPool().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list ipam cidr pool failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list ipam cidr pool failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.IpamPoolListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.IpamPool, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, corecloud.IpamPool{
+			ID:      one.ID,
+			Name:    one.Name,
+			Vendor:  one.Vendor,
+			Region:  one.Region,
+			BkBizID: one.BkBizID,
+			Cidr:    one.Cidr,
+			Memo:    one.Memo,
+			Revision: &core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &protocloud.IpamPoolListResult{Details: details}, nil
+}
+
+// BatchDeleteIpamPool batch delete ipam cidr pool, the pool which still has allocations can not be deleted.
+func (svc *ipamSvc) BatchDeleteIpamPool(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listOpt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id"},
+	}
+	pools, err := svc.dao.IpamPool().List(cts.Kit, listOpt)
+	if err != nil {
+		logs.Errorf("list ipam cidr pool failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(pools.Details) == 0 {
+		return nil, nil
+	}
+
+	ids := slice.Map(pools.Details, func(one tableipam.IpamPoolTable) string { return one.ID })
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		// lock the pools so that no allocation can be created in the pools before they are deleted
+		if _, err := svc.dao.IpamPool().LockByIDsWithTx(cts.Kit, txn, ids); err != nil {
+			return nil, err
+		}
+
+		allocations, err := svc.dao.IpamAllocation().ListByPoolIDsWithTx(cts.Kit, txn, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(allocations) != 0 {
+			return nil, errf.Newf(errf.InvalidParameter,
+				"pools(ids: %v) still have %d allocations, release them first", ids, len(allocations))
+		}
+
+		return nil, svc.dao.IpamPool().DeleteWithTx(cts.Kit, txn, tools.ContainersExpression("id", ids))
+	})
+	if err != nil {
+		logs.Errorf("delete ipam cidr pool failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,220 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package restag
+
+import (
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tablerestag "hcm/pkg/dal/table/cloud/resource-tag"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/maps"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Sync the tags of the account's cloud resources, only the tags of the resources which are changed will be
+// rewritten, and the tags of the deleted resources will be removed.
+func (svc *resTagSvc) Sync(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.ResourceTagSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	cloudTagMap := make(map[string]map[string]string, len(req.Items))
+	for _, one := range req.Items {
+		cloudTagMap[one.CloudResID] = one.Tags
+	}
+
+	idMap := make(map[string]string)
+	if len(cloudTagMap) != 0 {
+		var err error
+		idMap, err = svc.dao.Cloud().ListResIDByCloudID(cts.Kit, req.ResType, req.AccountID, maps.Keys(cloudTagMap))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dbTagMap, err := svc.listResTags(cts.Kit, req.ResType, maps.Values(idMap))
+	if err != nil {
+		return nil, err
+	}
+
+	changedResIDs := make([]string, 0)
+	models := make([]*tablerestag.ResourceTagTable, 0)
+	for cloudID, resID := range idMap {
+		tags := cloudTagMap[cloudID]
+		if maps.Equal(tags, dbTagMap[resID]) {
+			continue
+		}
+
+		changedResIDs = append(changedResIDs, resID)
+		for key, value := range tags {
+			models = append(models, &tablerestag.ResourceTagTable{
+				Vendor:     req.Vendor,
+				AccountID:  req.AccountID,
+				ResType:    req.ResType,
+				ResID:      resID,
+				CloudResID: cloudID,
+				TagKey:     key,
+				TagValue:   value,
+				Creator:    cts.Kit.User,
+				Reviser:    cts.Kit.User,
+			})
+		}
+	}
+
+	if len(changedResIDs) == 0 && len(req.DeletedCloudResIDs) == 0 {
+		return nil, nil
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if len(changedResIDs) != 0 {
+			delFilter := tools.ExpressionAnd(tools.RuleEqual("res_type", req.ResType),
+				tools.RuleIn("res_id", changedResIDs))
+			if err := svc.dao.ResourceTag().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(req.DeletedCloudResIDs) != 0 {
+			delFilter := tools.ExpressionAnd(tools.RuleEqual("account_id", req.AccountID),
+				tools.RuleEqual("res_type", req.ResType), tools.RuleIn("cloud_res_id", req.DeletedCloudResIDs))
+			if err := svc.dao.ResourceTag().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, batch := range slice.Split(models, constant.BatchOperationMaxLimit) {
+			if _, err := svc.dao.ResourceTag().BatchCreateWithTx(cts.Kit, txn, batch); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("sync resource tag failed, err: %v, account: %s, res type: %s, rid: %s", err, req.AccountID,
+			req.ResType, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// listResTags list the tags of the resources, returns resource id to tags map.
+func (svc *resTagSvc) listResTags(kt *kit.Kit, resType enumor.CloudResourceType, resIDs []string) (
+	map[string]map[string]string, error) {
+
+	tagMap := make(map[string]map[string]string, len(resIDs))
+	if len(resIDs) == 0 {
+		return tagMap, nil
+	}
+
+	listOpt := &types.ListOption{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("res_type", resType), tools.RuleIn("res_id", resIDs)),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"res_id", "tag_key", "tag_value"},
+	}
+	for {
+		result, err := svc.dao.ResourceTag().List(kt, listOpt)
+		if err != nil {
+			logs.Errorf("list resource tag failed, err: %v, res type: %s, rid: %s", err, resType, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range result.Details {
+			if _, exists := tagMap[one.ResID]; !exists {
+				tagMap[one.ResID] = make(map[string]string)
+			}
+			tagMap[one.ResID][one.TagKey] = one.TagValue
+		}
+
+		if uint(len(result.Details)) < listOpt.Page.Limit {
+			break
+		}
+		listOpt.Page.Start += uint32(listOpt.Page.Limit)
+	}
+
+	return tagMap, nil
+}
+
+// BatchUpsert add tags to resources, the value of the tag will be updated if the key already exists.
+func (svc *resTagSvc) BatchUpsert(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.ResourceTagBatchUpsertReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		models := make([]*tablerestag.ResourceTagTable, 0)
+		for _, one := range req.Items {
+			delFilter := tools.ExpressionAnd(tools.RuleEqual("res_type", one.ResType),
+				tools.RuleEqual("res_id", one.ResID), tools.RuleIn("tag_key", maps.Keys(one.Tags)))
+			if err := svc.dao.ResourceTag().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+				return nil, err
+			}
+
+			for key, value := range one.Tags {
+				models = append(models, &tablerestag.ResourceTagTable{
+					Vendor:     one.Vendor,
+					AccountID:  one.AccountID,
+					ResType:    one.ResType,
+					ResID:      one.ResID,
+					CloudResID: one.CloudResID,
+					TagKey:     key,
+					TagValue:   value,
+					Creator:    cts.Kit.User,
+					Reviser:    cts.Kit.User,
+				})
+			}
+		}
+
+		for _, batch := range slice.Split(models, constant.BatchOperationMaxLimit) {
+			if _, err := svc.dao.ResourceTag().BatchCreateWithTx(cts.Kit, txn, batch); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("batch upsert resource tag failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
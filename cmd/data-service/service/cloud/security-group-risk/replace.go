@@ -0,0 +1,91 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sgrisk
+
+import (
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	tablesgrisk "hcm/pkg/dal/table/cloud/security-group-risk"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Replace delete all the risk findings of the account, then create the latest findings.
+func (svc *sgRiskSvc) Replace(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.SGRiskFindingReplaceReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		delFilter := tools.ExpressionAnd(tools.RuleEqual("vendor", req.Vendor),
+			tools.RuleEqual("account_id", req.AccountID))
+		if err := svc.dao.SGRiskFinding().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+			return nil, err
+		}
+
+		for _, batch := range slice.Split(req.Findings, constant.BatchOperationMaxLimit) {
+			models := make([]*tablesgrisk.SGRiskFindingTable, 0, len(batch))
+			for _, one := range batch {
+				models = append(models, &tablesgrisk.SGRiskFindingTable{
+					Vendor:      one.Vendor,
+					AccountID:   one.AccountID,
+					BkBizID:     one.BkBizID,
+					Region:      one.Region,
+					ResType:     one.ResType,
+					ResID:       one.ResID,
+					CloudResID:  one.CloudResID,
+					ResName:     one.ResName,
+					RuleID:      one.RuleID,
+					CloudRuleID: one.CloudRuleID,
+					CheckName:   one.CheckName,
+					CheckType:   one.CheckType,
+					Severity:    one.Severity,
+					Description: one.Description,
+					Creator:     cts.Kit.User,
+					Reviser:     cts.Kit.User,
+				})
+			}
+
+			if _, err := svc.dao.SGRiskFinding().BatchCreateWithTx(cts.Kit, txn, models); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("replace security group risk finding failed, err: %v, vendor: %s, account: %s, rid: %s", err,
+			req.Vendor, req.AccountID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,169 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sgruletpl
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/types"
+	tablesgruletpl "hcm/pkg/dal/table/cloud/security-group-rule-template"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BatchCreateSGRuleTplApply batch create security group rule template apply records in pending state.
+func (svc *sgRuleTplSvc) BatchCreateSGRuleTplApply(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.SGRuleTplApplyBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make([]*tablesgruletpl.SGRuleTplApplyTable, 0, len(req.Applies))
+	for _, one := range req.Applies {
+		models = append(models, &tablesgruletpl.SGRuleTplApplyTable{
+			TemplateID:      one.TemplateID,
+			TemplateVersion: one.TemplateVersion,
+			Vendor:          one.Vendor,
+			AccountID:       one.AccountID,
+			BkBizID:         one.BkBizID,
+			ResType:         one.ResType,
+			ResID:           one.ResID,
+			CloudResID:      one.CloudResID,
+			State:           enumor.SGRuleTplApplyPending,
+			Creator:         cts.Kit.User,
+			Reviser:         cts.Kit.User,
+		})
+	}
+
+	result, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.SGRuleTplApply().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("batch create security group rule template apply failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	ids, ok := result.([]string)
+	if !ok {
+		return nil, fmt.Errorf("batch create security group rule template apply but return id type is not "+
+			"[]string, id type: %T", result)
+	}
+
+	return &core.BatchCreateResult{IDs: ids}, nil
+}
+
+// BatchUpdateSGRuleTplApply batch update security group rule template apply records.
+func (svc *sgRuleTplSvc) BatchUpdateSGRuleTplApply(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.SGRuleTplApplyBatchUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		for _, one := range req.Applies {
+			model := &tablesgruletpl.SGRuleTplApplyTable{
+				State:   one.State,
+				Reason:  one.Reason,
+				FlowID:  one.FlowID,
+				Reviser: cts.Kit.User,
+			}
+			if err := svc.dao.SGRuleTplApply().UpdateByIDWithTx(cts.Kit, txn, one.ID, model); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("batch update security group rule template apply failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListSGRuleTplApply list security group rule template apply records.
+func (svc *sgRuleTplSvc) ListSGRuleTplApply(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.SGRuleTplApply().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list security group rule template apply failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list security group rule template apply failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.SGRuleTplApplyListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.SGRuleTplApply, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, corecloud.SGRuleTplApply{
+			ID:              one.ID,
+			TemplateID:      one.TemplateID,
+			TemplateVersion: one.TemplateVersion,
+			Vendor:          one.Vendor,
+			AccountID:       one.AccountID,
+			BkBizID:         one.BkBizID,
+			ResType:         one.ResType,
+			ResID:           one.ResID,
+			CloudResID:      one.CloudResID,
+			State:           one.State,
+			Reason:          one.Reason,
+			FlowID:          one.FlowID,
+			Revision: &core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &protocloud.SGRuleTplApplyListResult{Details: details}, nil
+}
@@ -0,0 +1,58 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package sgruletpl ...
+package sgruletpl
+
+import (
+	"net/http"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/rest"
+)
+
+// InitService initial the security group rule template service
+func InitService(cap *capability.Capability) {
+	svc := &sgRuleTplSvc{
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("BatchCreateSGRuleTpl", http.MethodPost, "/security_group_rule_templates/batch/create",
+		svc.BatchCreateSGRuleTpl)
+	h.Add("UpdateSGRuleTpl", http.MethodPatch, "/security_group_rule_templates/{id}", svc.UpdateSGRuleTpl)
+	h.Add("ListSGRuleTpl", http.MethodPost, "/security_group_rule_templates/list", svc.ListSGRuleTpl)
+	h.Add("BatchDeleteSGRuleTpl", http.MethodDelete, "/security_group_rule_templates/batch",
+		svc.BatchDeleteSGRuleTpl)
+
+	h.Add("BatchCreateSGRuleTplApply", http.MethodPost, "/security_group_rule_template_applies/batch/create",
+		svc.BatchCreateSGRuleTplApply)
+	h.Add("BatchUpdateSGRuleTplApply", http.MethodPatch, "/security_group_rule_template_applies/batch",
+		svc.BatchUpdateSGRuleTplApply)
+	h.Add("ListSGRuleTplApply", http.MethodPost, "/security_group_rule_template_applies/list",
+		svc.ListSGRuleTplApply)
+
+	h.Load(cap.WebService)
+}
+
+type sgRuleTplSvc struct {
+	dao dao.Set
+}
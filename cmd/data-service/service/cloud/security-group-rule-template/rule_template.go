@@ -0,0 +1,223 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sgruletpl
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tablesgruletpl "hcm/pkg/dal/table/cloud/security-group-rule-template"
+	tabletypes "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BatchCreateSGRuleTpl batch create security group rule template.
+func (svc *sgRuleTplSvc) BatchCreateSGRuleTpl(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.SGRuleTemplateBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make([]*tablesgruletpl.SGRuleTemplateTable, 0, len(req.Templates))
+	for _, one := range req.Templates {
+		rules, err := tabletypes.NewJsonField(one.Rules)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		models = append(models, &tablesgruletpl.SGRuleTemplateTable{
+			Name:    one.Name,
+			BkBizID: one.BkBizID,
+			Rules:   rules,
+			Version: 1,
+			Memo:    one.Memo,
+			Creator: cts.Kit.User,
+			Reviser: cts.Kit.User,
+		})
+	}
+
+	result, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.SGRuleTemplate().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("batch create security group rule template failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	ids, ok := result.([]string)
+	if !ok {
+		return nil, fmt.Errorf("batch create security group rule template but return id type is not []string, "+
+			"id type: %T", result)
+	}
+
+	return &core.BatchCreateResult{IDs: ids}, nil
+}
+
+// UpdateSGRuleTpl update security group rule template, version is increased when the rules are updated.
+func (svc *sgRuleTplSvc) UpdateSGRuleTpl(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protocloud.SGRuleTemplateUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		listOpt := &types.ListOption{
+			Filter: tools.EqualExpression("id", id),
+			Page:   core.NewDefaultBasePage(),
+			Fields: []string{"id", "version"},
+		}
+		result, err := svc.dao.SGRuleTemplate().List(cts.Kit, listOpt)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(result.Details) == 0 {
+			return nil, errf.Newf(errf.RecordNotFound, "security group rule template: %s not found", id)
+		}
+
+		model := &tablesgruletpl.SGRuleTemplateTable{
+			Name:    req.Name,
+			Memo:    req.Memo,
+			Reviser: cts.Kit.User,
+		}
+		if len(req.Rules) != 0 {
+			if model.Rules, err = tabletypes.NewJsonField(req.Rules); err != nil {
+				return nil, errf.NewFromErr(errf.InvalidParameter, err)
+			}
+			model.Version = result.Details[0].Version + 1
+		}
+
+		return nil, svc.dao.SGRuleTemplate().UpdateByIDWithTx(cts.Kit, txn, id, model)
+	})
+	if err != nil {
+		logs.Errorf("update security group rule template failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListSGRuleTpl list security group rule template.
+func (svc *sgRuleTplSvc) ListSGRuleTpl(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.SGRuleTemplate().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list security group rule template failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list security group rule template failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.SGRuleTemplateListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.SGRuleTemplate, 0, len(result.Details))
+	for _, one := range result.Details {
+		rules := make([]corecloud.SGTemplateRule, 0)
+		if len(one.Rules) != 0 {
+			if err = json.Unmarshal([]byte(one.Rules), &rules); err != nil {
+				logs.Errorf("unmarshal security group rule template rules failed, err: %v, id: %s, rid: %s", err,
+					one.ID, cts.Kit.Rid)
+				return nil, err
+			}
+		}
+
+		details = append(details, corecloud.SGRuleTemplate{
+			ID:      one.ID,
+			Name:    one.Name,
+			BkBizID: one.BkBizID,
+			Rules:   rules,
+			Version: one.Version,
+			Memo:    one.Memo,
+			Revision: &core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &protocloud.SGRuleTemplateListResult{Details: details}, nil
+}
+
+// BatchDeleteSGRuleTpl batch delete security group rule template and the apply records of them.
+func (svc *sgRuleTplSvc) BatchDeleteSGRuleTpl(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.SGRuleTplDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		err := svc.dao.SGRuleTplApply().DeleteWithTx(cts.Kit, txn, tools.ContainersExpression("template_id",
+			req.IDs))
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, svc.dao.SGRuleTemplate().DeleteWithTx(cts.Kit, txn, tools.ContainersExpression("id", req.IDs))
+	})
+	if err != nil {
+		logs.Errorf("delete security group rule template failed, err: %v, ids: %v, rid: %s", err, req.IDs,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -212,6 +212,11 @@ func (svc *securityGroupSvc) BatchDeleteSecurityGroup(cts *rest.Contexts) (inter
 			return nil, err
 		}
 
+		if err := svc.dao.ResourceTag().DeleteByResWithTx(cts.Kit, txn, enumor.SecurityGroupCloudResType,
+			delIDs); err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	})
 	if err != nil {
@@ -0,0 +1,232 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tagpolicy
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tabletagpolicy "hcm/pkg/dal/table/cloud/tag-policy"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ReplaceCompliance delete all the tag compliance findings and summaries of the account, then create the latest.
+func (svc *tagPolicySvc) ReplaceCompliance(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.TagComplianceReplaceReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	findings := make([]*tabletagpolicy.TagComplianceFindingTable, 0, len(req.Findings))
+	for _, one := range req.Findings {
+		violations, err := json.MarshalToString(one.Violations)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		findings = append(findings, &tabletagpolicy.TagComplianceFindingTable{
+			PolicyID:   one.PolicyID,
+			Vendor:     one.Vendor,
+			AccountID:  one.AccountID,
+			BkBizID:    one.BkBizID,
+			Region:     one.Region,
+			ResType:    one.ResType,
+			ResID:      one.ResID,
+			CloudResID: one.CloudResID,
+			ResName:    one.ResName,
+			Violations: tabletype.JsonField(violations),
+			Creator:    cts.Kit.User,
+			Reviser:    cts.Kit.User,
+		})
+	}
+
+	summaries := make([]*tabletagpolicy.TagComplianceSummaryTable, 0, len(req.Summaries))
+	for _, one := range req.Summaries {
+		summaries = append(summaries, &tabletagpolicy.TagComplianceSummaryTable{
+			Vendor:         one.Vendor,
+			AccountID:      one.AccountID,
+			BkBizID:        one.BkBizID,
+			ResType:        one.ResType,
+			TotalCount:     one.TotalCount,
+			CompliantCount: one.CompliantCount,
+			Creator:        cts.Kit.User,
+			Reviser:        cts.Kit.User,
+		})
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		delFilter := tools.ExpressionAnd(tools.RuleEqual("vendor", req.Vendor),
+			tools.RuleEqual("account_id", req.AccountID))
+		if err := svc.dao.TagComplianceFinding().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+			return nil, err
+		}
+
+		if err := svc.dao.TagComplianceSummary().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+			return nil, err
+		}
+
+		for _, batch := range slice.Split(findings, constant.BatchOperationMaxLimit) {
+			if _, err := svc.dao.TagComplianceFinding().BatchCreateWithTx(cts.Kit, txn, batch); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, batch := range slice.Split(summaries, constant.BatchOperationMaxLimit) {
+			if _, err := svc.dao.TagComplianceSummary().BatchCreateWithTx(cts.Kit, txn, batch); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("replace tag compliance failed, err: %v, vendor: %s, account: %s, rid: %s", err, req.Vendor,
+			req.AccountID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListFinding list tag compliance findings.
+func (svc *tagPolicySvc) ListFinding(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.TagComplianceFinding().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list tag compliance findings failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list tag compliance findings failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.TagComplianceFindingListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.TagComplianceFinding, 0, len(result.Details))
+	for _, one := range result.Details {
+		violations := make([]corecloud.TagViolation, 0)
+		if !one.Violations.IsEmpty() {
+			if err = json.UnmarshalFromString(string(one.Violations), &violations); err != nil {
+				logs.Errorf("unmarshal tag compliance finding(%s) violations failed, err: %v, rid: %s", one.ID, err,
+					cts.Kit.Rid)
+				return nil, err
+			}
+		}
+
+		details = append(details, corecloud.TagComplianceFinding{
+			ID:         one.ID,
+			PolicyID:   one.PolicyID,
+			Vendor:     one.Vendor,
+			AccountID:  one.AccountID,
+			BkBizID:    one.BkBizID,
+			Region:     one.Region,
+			ResType:    one.ResType,
+			ResID:      one.ResID,
+			CloudResID: one.CloudResID,
+			ResName:    one.ResName,
+			Violations: violations,
+			Revision: &core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &protocloud.TagComplianceFindingListResult{Details: details}, nil
+}
+
+// ListSummary list tag compliance summaries.
+func (svc *tagPolicySvc) ListSummary(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.TagComplianceSummary().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list tag compliance summaries failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list tag compliance summaries failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.TagComplianceSummaryListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.TagComplianceSummary, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, corecloud.TagComplianceSummary{
+			ID:             one.ID,
+			Vendor:         one.Vendor,
+			AccountID:      one.AccountID,
+			BkBizID:        one.BkBizID,
+			ResType:        one.ResType,
+			TotalCount:     one.TotalCount,
+			CompliantCount: one.CompliantCount,
+			Revision: &core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &protocloud.TagComplianceSummaryListResult{Details: details}, nil
+}
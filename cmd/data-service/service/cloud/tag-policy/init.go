@@ -0,0 +1,53 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tagpolicy ...
+package tagpolicy
+
+import (
+	"net/http"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/rest"
+)
+
+// InitService initial the tag policy and tag compliance service
+func InitService(cap *capability.Capability) {
+	svc := &tagPolicySvc{
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CreateTagPolicy", http.MethodPost, "/tag_policies/create", svc.CreatePolicy)
+	h.Add("UpdateTagPolicy", http.MethodPatch, "/tag_policies/{id}", svc.UpdatePolicy)
+	h.Add("ListTagPolicy", http.MethodPost, "/tag_policies/list", svc.ListPolicy)
+	h.Add("BatchDeleteTagPolicy", http.MethodDelete, "/tag_policies/batch", svc.BatchDeletePolicy)
+
+	h.Add("ReplaceTagCompliance", http.MethodPost, "/tag_compliance/replace", svc.ReplaceCompliance)
+	h.Add("ListTagComplianceFinding", http.MethodPost, "/tag_compliance/findings/list", svc.ListFinding)
+	h.Add("ListTagComplianceSummary", http.MethodPost, "/tag_compliance/summaries/list", svc.ListSummary)
+
+	h.Load(cap.WebService)
+}
+
+type tagPolicySvc struct {
+	dao dao.Set
+}
@@ -0,0 +1,253 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tagpolicy
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tabletagpolicy "hcm/pkg/dal/table/cloud/tag-policy"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreatePolicy create tag policy.
+func (svc *tagPolicySvc) CreatePolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.TagPolicyCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rules, err := json.MarshalToString(req.Rules)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tabletagpolicy.TagPolicyTable{
+		Name:          req.Name,
+		BkBizID:       req.BkBizID,
+		ResTypes:      convResTypes(req.ResTypes),
+		Rules:         tabletype.JsonField(rules),
+		AutoRemediate: req.AutoRemediate,
+		Enabled:       req.Enabled,
+		Memo:          converter.ValToPtr(req.Memo),
+		Creator:       cts.Kit.User,
+		Reviser:       cts.Kit.User,
+	}
+	result, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.TagPolicy().CreateWithTx(cts.Kit, txn, model)
+	})
+	if err != nil {
+		logs.Errorf("create tag policy failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	id, ok := result.(string)
+	if !ok {
+		return nil, fmt.Errorf("create tag policy but return id is invalid, result: %v", result)
+	}
+
+	return &core.CreateResult{ID: id}, nil
+}
+
+// UpdatePolicy update tag policy.
+func (svc *tagPolicySvc) UpdatePolicy(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protocloud.TagPolicyUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tabletagpolicy.TagPolicyTable{
+		Name:          req.Name,
+		AutoRemediate: req.AutoRemediate,
+		Enabled:       req.Enabled,
+		Memo:          req.Memo,
+		Reviser:       cts.Kit.User,
+	}
+	if req.ResTypes != nil {
+		model.ResTypes = convResTypes(*req.ResTypes)
+	}
+	if len(req.Rules) != 0 {
+		rules, err := json.MarshalToString(req.Rules)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		model.Rules = tabletype.JsonField(rules)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.TagPolicy().UpdateByIDWithTx(cts.Kit, txn, id, model)
+	})
+	if err != nil {
+		logs.Errorf("update tag policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// convResTypes convert resource types to string array, empty array means all resource types.
+func convResTypes(resTypes []enumor.CloudResourceType) tabletype.StringArray {
+	result := make(tabletype.StringArray, 0, len(resTypes))
+	for _, resType := range slice.Unique(resTypes) {
+		result = append(result, string(resType))
+	}
+	return result
+}
+
+// ListPolicy list tag policy.
+func (svc *tagPolicySvc) ListPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.TagPolicy().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list tag policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list tag policy failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.TagPolicyListResult{Count: result.Count}, nil
+	}
+
+	details := make([]*corecloud.TagPolicy, 0, len(result.Details))
+	for _, one := range result.Details {
+		detail, err := convertToTagPolicy(one)
+		if err != nil {
+			logs.Errorf("convert tag policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+		details = append(details, detail)
+	}
+
+	return &protocloud.TagPolicyListResult{Details: details}, nil
+}
+
+func convertToTagPolicy(one tabletagpolicy.TagPolicyTable) (*corecloud.TagPolicy, error) {
+	rules := make(corecloud.TagPolicyRules, 0)
+	if !one.Rules.IsEmpty() {
+		if err := json.UnmarshalFromString(string(one.Rules), &rules); err != nil {
+			return nil, fmt.Errorf("unmarshal tag policy(%s) rules failed, err: %v", one.ID, err)
+		}
+	}
+
+	resTypes := make([]enumor.CloudResourceType, 0, len(one.ResTypes))
+	for _, resType := range one.ResTypes {
+		resTypes = append(resTypes, enumor.CloudResourceType(resType))
+	}
+
+	return &corecloud.TagPolicy{
+		ID:            one.ID,
+		Name:          one.Name,
+		BkBizID:       one.BkBizID,
+		ResTypes:      resTypes,
+		Rules:         rules,
+		AutoRemediate: converter.PtrToVal(one.AutoRemediate),
+		Enabled:       converter.PtrToVal(one.Enabled),
+		Memo:          converter.PtrToVal(one.Memo),
+		Revision: core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}, nil
+}
+
+// BatchDeletePolicy batch delete tag policy, the compliance findings of the policy are deleted together.
+func (svc *tagPolicySvc) BatchDeletePolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listOpt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id"},
+	}
+	policies, err := svc.dao.TagPolicy().List(cts.Kit, listOpt)
+	if err != nil {
+		logs.Errorf("list tag policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(policies.Details) == 0 {
+		return nil, nil
+	}
+
+	ids := slice.Map(policies.Details, func(one tabletagpolicy.TagPolicyTable) string { return one.ID })
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		err := svc.dao.TagComplianceFinding().DeleteWithTx(cts.Kit, txn, tools.ContainersExpression("policy_id", ids))
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, svc.dao.TagPolicy().DeleteWithTx(cts.Kit, txn, tools.ContainersExpression("id", ids))
+	})
+	if err != nil {
+		logs.Errorf("delete tag policy failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,252 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recyclerecord
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	protocore "hcm/pkg/api/core/recycle-record"
+	dataservice "hcm/pkg/api/data-service"
+	protodata "hcm/pkg/api/data-service/recycle-record"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	prototable "hcm/pkg/dal/table/recycle-record"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateRecyclePolicy create recycle policy.
+func (svc *recycleRecordSvc) CreateRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(protodata.RecyclePolicyCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &prototable.RecyclePolicyTable{
+		BkBizID:          req.BkBizID,
+		ResType:          req.ResType,
+		RetentionHours:   req.RetentionHours,
+		PreDestroyAction: converter.ValToPtr(req.PreDestroyAction),
+		Memo:             req.Memo,
+		Creator:          cts.Kit.User,
+		Reviser:          cts.Kit.User,
+	}
+	result, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.RecyclePolicy().CreateWithTx(cts.Kit, txn, model)
+	})
+	if err != nil {
+		logs.Errorf("create recycle policy failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	id, ok := result.(string)
+	if !ok {
+		return nil, fmt.Errorf("create recycle policy but return id is invalid, result: %v", result)
+	}
+
+	return &core.CreateResult{ID: id}, nil
+}
+
+// UpdateRecyclePolicy update recycle policy.
+func (svc *recycleRecordSvc) UpdateRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protodata.RecyclePolicyUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if req.PreDestroyAction != nil {
+		// 销毁前动作与资源类型相关，需要按已有策略的资源类型校验
+		policy, err := svc.getRecyclePolicy(cts.Kit, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = req.PreDestroyAction.Validate(policy.ResType); err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+	}
+
+	model := &prototable.RecyclePolicyTable{
+		RetentionHours:   req.RetentionHours,
+		PreDestroyAction: req.PreDestroyAction,
+		Memo:             req.Memo,
+		Reviser:          cts.Kit.User,
+	}
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.RecyclePolicy().UpdateByIDWithTx(cts.Kit, txn, id, model)
+	})
+	if err != nil {
+		logs.Errorf("update recycle policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListRecyclePolicy list recycle policy.
+func (svc *recycleRecordSvc) ListRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.RecyclePolicy().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list recycle policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list recycle policy failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protodata.RecyclePolicyListResult{Count: result.Count}, nil
+	}
+
+	details := make([]protocore.RecyclePolicy, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, protocore.RecyclePolicy{
+			ID:               one.ID,
+			BkBizID:          one.BkBizID,
+			ResType:          one.ResType,
+			RetentionHours:   converter.PtrToVal(one.RetentionHours),
+			PreDestroyAction: converter.PtrToVal(one.PreDestroyAction),
+			Memo:             converter.PtrToVal(one.Memo),
+			Revision: core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &protodata.RecyclePolicyListResult{Details: details}, nil
+}
+
+// BatchDeleteRecyclePolicy batch delete recycle policy.
+func (svc *recycleRecordSvc) BatchDeleteRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listOpt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id"},
+	}
+	policies, err := svc.dao.RecyclePolicy().List(cts.Kit, listOpt)
+	if err != nil {
+		logs.Errorf("list recycle policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(policies.Details) == 0 {
+		return nil, nil
+	}
+
+	ids := slice.Map(policies.Details, func(one prototable.RecyclePolicyTable) string { return one.ID })
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.RecyclePolicy().DeleteWithTx(cts.Kit, txn, tools.ContainersExpression("id", ids))
+	})
+	if err != nil {
+		logs.Errorf("delete recycle policy failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (svc *recycleRecordSvc) getRecyclePolicy(kt *kit.Kit, id string) (*prototable.RecyclePolicyTable, error) {
+	opt := &types.ListOption{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.RecyclePolicy().List(kt, opt)
+	if err != nil {
+		logs.Errorf("list recycle policy failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "recycle policy %s not found", id)
+	}
+
+	return &result.Details[0], nil
+}
+
+// listRecyclePolicyMap 查询资源类型在指定业务和全局生效的回收策略，key为业务ID
+func (svc *recycleRecordSvc) listRecyclePolicyMap(kt *kit.Kit, resType enumor.CloudResourceType, bizIDs []int64) (
+	map[int64]prototable.RecyclePolicyTable, error) {
+
+	bizIDs = append(bizIDs, prototable.GlobalRecyclePolicyBizID)
+	opt := &types.ListOption{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("res_type", resType),
+			tools.RuleIn("bk_biz_id", slice.Unique(bizIDs)),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.RecyclePolicy().List(kt, opt)
+	if err != nil {
+		logs.Errorf("list recycle policy failed, err: %v, res type: %s, rid: %s", err, resType, kt.Rid)
+		return nil, err
+	}
+
+	policyMap := make(map[int64]prototable.RecyclePolicyTable, len(result.Details))
+	for _, one := range result.Details {
+		policyMap[one.BkBizID] = one
+	}
+
+	return policyMap, nil
+}
@@ -39,7 +39,9 @@ import (
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
 	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
 	"hcm/pkg/tools/times"
 
 	"github.com/jmoiron/sqlx"
@@ -60,6 +62,11 @@ func InitRecycleRecordService(cap *capability.Capability) {
 	h.Add("BatchUpdateRecycleStatus", "PATCH", "/recycle_records/recycle_status/batch",
 		svc.BatchUpdateRecycleStatus)
 
+	h.Add("CreateRecyclePolicy", "POST", "/recycle_policies/create", svc.CreateRecyclePolicy)
+	h.Add("UpdateRecyclePolicy", "PATCH", "/recycle_policies/{id}", svc.UpdateRecyclePolicy)
+	h.Add("ListRecyclePolicy", "POST", "/recycle_policies/list", svc.ListRecyclePolicy)
+	h.Add("BatchDeleteRecyclePolicy", "DELETE", "/recycle_policies/batch", svc.BatchDeleteRecyclePolicy)
+
 	h.Load(cap.WebService)
 }
 
@@ -78,8 +85,10 @@ func (svc *recycleRecordSvc) BatchRecycleCloudResource(cts *rest.Contexts) (inte
 	}
 
 	resIDs := make([]string, 0, len(req.Infos))
+	detailMap := make(map[string]interface{}, len(req.Infos))
 	for _, info := range req.Infos {
 		resIDs = append(resIDs, info.ID)
+		detailMap[info.ID] = info.Detail
 	}
 
 	resourceInfo, err := svc.dao.RecycleRecord().ListResourceInfo(cts.Kit, req.ResType, resIDs)
@@ -91,24 +100,35 @@ func (svc *recycleRecordSvc) BatchRecycleCloudResource(cts *rest.Contexts) (inte
 		return nil, errf.Newf(errf.InvalidParameter, "recycle resource count is invalid")
 	}
 
+	bizIDs := slice.Map(resourceInfo, func(one protodao.RecycleResourceInfo) int64 { return one.BkBizID })
+	policyMap, err := svc.listRecyclePolicyMap(cts.Kit, req.ResType, bizIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := times.ConvStdTimeNow()
+	timeline, err := appendTimeline("", protocore.TimelineEvent{Stage: enumor.ScheduledRecycleTimelineStage,
+		Time: times.ConvStdTimeFormat(now)})
+	if err != nil {
+		return nil, err
+	}
+
 	taskID, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
 		recycleRecords := make([]prototable.RecycleRecordTable, 0, len(resourceInfo))
-		for idx, info := range resourceInfo {
+		for _, info := range resourceInfo {
 			accountInfo, err := svc.checkAndGetAccount(cts.Kit, info)
 			if err != nil {
 				return nil, err
 			}
 
-			recycleDetail, err := tabletype.NewJsonField(req.Infos[idx].Detail)
+			// 资源信息不保证与请求顺序一致，需要按资源ID取对应的回收详情
+			recycleDetail, err := tabletype.NewJsonField(detailMap[info.ID])
 			if err != nil {
 				return nil, errf.NewFromErr(errf.InvalidParameter, err)
 			}
 
-			recycleReserveTime := req.DefaultRecycleTime
-			// TODO: 将默认时间修改放到cloud-server中去做
-			if accountInfo.Details[0].RecycleReserveTime > -1 {
-				recycleReserveTime = uint(accountInfo.Details[0].RecycleReserveTime)
-			}
+			recycleReserveTime := getRecycleReserveTime(req.DefaultRecycleTime, info.BkBizID,
+				accountInfo.Details[0].RecycleReserveTime, policyMap)
 			recycleRecords = append(recycleRecords, prototable.RecycleRecordTable{
 				RecycleType: req.RecycleType,
 				Vendor:      info.Vendor,
@@ -121,9 +141,11 @@ func (svc *recycleRecordSvc) BatchRecycleCloudResource(cts *rest.Contexts) (inte
 				Region:      info.Region,
 				Detail:      recycleDetail,
 				Status:      enumor.WaitingRecycleRecordStatus,
+				SnapshotIDs: "[]",
+				Timeline:    timeline,
 				Creator:     cts.Kit.User,
 				Reviser:     cts.Kit.User,
-				RecycledAt:  times.ConvStdTimeNow().Add(time.Hour * time.Duration(recycleReserveTime)),
+				RecycledAt:  now.Add(time.Hour * time.Duration(recycleReserveTime)),
 			})
 		}
 		// 标记资源回收状态
@@ -145,6 +167,39 @@ func (svc *recycleRecordSvc) BatchRecycleCloudResource(cts *rest.Contexts) (inte
 	return taskID, nil
 }
 
+// getRecycleReserveTime 获取资源在回收站中的保留时间，优先级：业务策略 > 账号配置 > 全局策略 > 默认值
+func getRecycleReserveTime(defaultTime uint, bizID int64, accountReserveTime int,
+	policyMap map[int64]prototable.RecyclePolicyTable) uint {
+
+	if policy, exists := policyMap[bizID]; exists && bizID != prototable.GlobalRecyclePolicyBizID {
+		return uint(converter.PtrToVal(policy.RetentionHours))
+	}
+
+	// TODO: 将默认时间修改放到cloud-server中去做
+	if accountReserveTime > -1 {
+		return uint(accountReserveTime)
+	}
+
+	if policy, exists := policyMap[prototable.GlobalRecyclePolicyBizID]; exists {
+		return uint(converter.PtrToVal(policy.RetentionHours))
+	}
+
+	return defaultTime
+}
+
+// appendTimeline 向回收记录的时间线中追加事件
+func appendTimeline(timeline tabletype.JsonField, events ...protocore.TimelineEvent) (tabletype.JsonField, error) {
+	all := make([]protocore.TimelineEvent, 0)
+	if len(timeline) != 0 && timeline != "null" {
+		if err := json.UnmarshalFromString(string(timeline), &all); err != nil {
+			return "", fmt.Errorf("unmarshal recycle record timeline failed, err: %v", err)
+		}
+	}
+	all = append(all, events...)
+
+	return tabletype.NewJsonField(all)
+}
+
 func (svc *recycleRecordSvc) checkAndGetAccount(kt *kit.Kit, info protodao.RecycleResourceInfo) (
 	*types.ListAccountDetails, error) {
 
@@ -173,7 +228,7 @@ func (svc *recycleRecordSvc) BatchRecoverCloudResource(cts *rest.Contexts) (inte
 		Page: &core.BasePage{
 			Limit: core.DefaultMaxPageLimit,
 		},
-		Fields: []string{"id", "bk_biz_id", "res_id"},
+		Fields: []string{"id", "bk_biz_id", "res_id", "timeline"},
 	}
 	listResp, err := svc.dao.RecycleRecord().List(cts.Kit, opt)
 	if err != nil {
@@ -185,11 +240,17 @@ func (svc *recycleRecordSvc) BatchRecoverCloudResource(cts *rest.Contexts) (inte
 		return nil, nil
 	}
 
-	recordIDs := make([]string, len(listResp.Details))
 	bizCvmIDMap := make(map[int64][]string)
-	for index, one := range listResp.Details {
-		recordIDs[index] = one.ID
+	timelineMap := make(map[string]tabletype.JsonField, len(listResp.Details))
+	recoveredEvent := protocore.TimelineEvent{Stage: enumor.RecoveredRecycleTimelineStage,
+		Time: times.ConvStdTimeFormat(times.ConvStdTimeNow())}
+	for _, one := range listResp.Details {
 		bizCvmIDMap[one.BkBizID] = append(bizCvmIDMap[one.BkBizID], one.ResID)
+		timeline, err := appendTimeline(one.Timeline, recoveredEvent)
+		if err != nil {
+			return nil, err
+		}
+		timelineMap[one.ID] = timeline
 	}
 
 	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
@@ -203,14 +264,17 @@ func (svc *recycleRecordSvc) BatchRecoverCloudResource(cts *rest.Contexts) (inte
 			}
 		}
 
-		// delete recycle records
-		updateFilter := tools.ContainersExpression("id", recordIDs)
-		updateData := &prototable.RecycleRecordTable{
-			Status:  enumor.RecoverRecycleRecordStatus,
-			Reviser: cts.Kit.User,
-		}
-		if err := svc.dao.RecycleRecord().Update(cts.Kit, txn, updateFilter, updateData); err != nil {
-			return nil, err
+		// mark recycle records as recovered, every record has its own timeline
+		for id, timeline := range timelineMap {
+			updateData := &prototable.RecycleRecordTable{
+				Status:   enumor.RecoverRecycleRecordStatus,
+				Timeline: timeline,
+				Reviser:  cts.Kit.User,
+			}
+			err := svc.dao.RecycleRecord().Update(cts.Kit, txn, tools.EqualExpression("id", id), updateData)
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		return nil, nil
@@ -250,6 +314,12 @@ func (svc *recycleRecordSvc) ListRecycleRecord(cts *rest.Contexts) (interface{},
 
 	records := make([]protocore.RecycleRecord, 0, len(res.Details))
 	for _, recycleRecord := range res.Details {
+		snapshotIDs, timeline, err := decodeSnapshotAndTimeline(recycleRecord)
+		if err != nil {
+			logs.Errorf("decode recycle record failed, err: %v, id: %s, rid: %s", err, recycleRecord.ID, cts.Kit.Rid)
+			return nil, err
+		}
+
 		records = append(records, protocore.RecycleRecord{
 			BaseRecycleRecord: protocore.BaseRecycleRecord{
 				ID:          recycleRecord.ID,
@@ -265,6 +335,8 @@ func (svc *recycleRecordSvc) ListRecycleRecord(cts *rest.Contexts) (interface{},
 				Region:      recycleRecord.Region,
 				Status:      enumor.RecycleRecordStatus(recycleRecord.Status),
 				RecycledAt:  times.ConvStdTimeFormat(recycleRecord.RecycledAt),
+				SnapshotIDs: snapshotIDs,
+				Timeline:    timeline,
 				Revision: core.Revision{
 					Creator:   recycleRecord.Creator,
 					Reviser:   recycleRecord.Reviser,
@@ -279,6 +351,24 @@ func (svc *recycleRecordSvc) ListRecycleRecord(cts *rest.Contexts) (interface{},
 	return &protodata.ListResult{Details: records}, nil
 }
 
+func decodeSnapshotAndTimeline(record prototable.RecycleRecordTable) ([]string, []protocore.TimelineEvent, error) {
+	var snapshotIDs []string
+	if len(record.SnapshotIDs) != 0 && record.SnapshotIDs != "null" {
+		if err := json.UnmarshalFromString(string(record.SnapshotIDs), &snapshotIDs); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal snapshot ids failed, err: %v", err)
+		}
+	}
+
+	var timeline []protocore.TimelineEvent
+	if len(record.Timeline) != 0 && record.Timeline != "null" {
+		if err := json.UnmarshalFromString(string(record.Timeline), &timeline); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal timeline failed, err: %v", err)
+		}
+	}
+
+	return snapshotIDs, timeline, nil
+}
+
 // BatchUpdateRecycleRecord batch update recycle records.
 func (svc *recycleRecordSvc) BatchUpdateRecycleRecord(cts *rest.Contexts) (interface{}, error) {
 	req := new(protodata.BatchUpdateReq)
@@ -298,7 +388,7 @@ func (svc *recycleRecordSvc) BatchUpdateRecycleRecord(cts *rest.Contexts) (inter
 	opt := &types.ListOption{
 		Filter: tools.ContainersExpression("id", ids),
 		Page:   core.NewDefaultBasePage(),
-		Fields: []string{"id", "detail"},
+		Fields: []string{"id", "detail", "timeline"},
 	}
 	res, err := svc.dao.RecycleRecord().List(cts.Kit, opt)
 	if err != nil {
@@ -310,20 +400,37 @@ func (svc *recycleRecordSvc) BatchUpdateRecycleRecord(cts *rest.Contexts) (inter
 		return nil, fmt.Errorf("list recycle record failed, some recycle record(ids=%+v) doesn't exist", ids)
 	}
 
-	detailMap := make(map[string]tabletype.JsonField)
+	recordMap := make(map[string]prototable.RecycleRecordTable)
 	for _, recycleRecord := range res.Details {
-		detailMap[recycleRecord.ID] = recycleRecord.Detail
+		recordMap[recycleRecord.ID] = recycleRecord
 	}
 
-	record := &prototable.RecycleRecordTable{
-		Reviser: cts.Kit.User,
-	}
 	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
 		for _, updateReq := range req.Data {
-			record.Status = string(updateReq.Status)
+			record := &prototable.RecycleRecordTable{
+				Status:  string(updateReq.Status),
+				Reviser: cts.Kit.User,
+			}
+
+			if len(updateReq.SnapshotIDs) != 0 {
+				snapshotIDs, err := tabletype.NewJsonField(updateReq.SnapshotIDs)
+				if err != nil {
+					return nil, errf.NewFromErr(errf.InvalidParameter, err)
+				}
+				record.SnapshotIDs = snapshotIDs
+			}
+
+			events := buildTimelineEvents(updateReq)
+			if len(events) != 0 {
+				timeline, err := appendTimeline(recordMap[updateReq.ID].Timeline, events...)
+				if err != nil {
+					return nil, err
+				}
+				record.Timeline = timeline
+			}
 
 			if updateReq.Detail != nil {
-				updatedDetail, err := json.UpdateMerge(updateReq.Detail, string(detailMap[updateReq.ID]))
+				updatedDetail, err := json.UpdateMerge(updateReq.Detail, string(recordMap[updateReq.ID].Detail))
 				if err != nil {
 					return nil, fmt.Errorf("extension update merge failed, err: %v", err)
 				}
@@ -340,10 +447,43 @@ func (svc *recycleRecordSvc) BatchUpdateRecycleRecord(cts *rest.Contexts) (inter
 
 		return nil, nil
 	})
+	if err != nil {
+		logs.Errorf("batch update recycle record failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
 
 	return nil, nil
 }
 
+// buildTimelineEvents 根据更新请求生成需要追加的时间线事件，回收成功或失败时自动追加对应事件
+func buildTimelineEvents(req protodata.UpdateReq) []protocore.TimelineEvent {
+	events := make([]protocore.TimelineEvent, 0)
+	now := times.ConvStdTimeFormat(times.ConvStdTimeNow())
+	if req.Timeline != nil {
+		event := *req.Timeline
+		if len(event.Time) == 0 {
+			event.Time = now
+		}
+		events = append(events, event)
+	}
+
+	var stage enumor.RecycleTimelineStage
+	switch req.Status {
+	case enumor.RecycledRecycleRecordStatus:
+		stage = enumor.DestroyedRecycleTimelineStage
+	case enumor.FailedRecycleRecordStatus:
+		stage = enumor.FailedRecycleTimelineStage
+	default:
+		return events
+	}
+
+	if req.Timeline != nil && req.Timeline.Stage == stage {
+		return events
+	}
+
+	return append(events, protocore.TimelineEvent{Stage: stage, Time: now})
+}
+
 // BatchUpdateRecycleStatus 批量更新资源的回收状态字段
 func (svc *recycleRecordSvc) BatchUpdateRecycleStatus(cts *rest.Contexts) (reply interface{}, err error) {
 	req := new(protodata.BatchUpdateRecycleStatusReq)
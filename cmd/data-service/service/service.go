@@ -32,7 +32,9 @@ import (
 	rootaccount "hcm/cmd/data-service/service/account-set/root-account"
 	"hcm/cmd/data-service/service/application"
 	"hcm/cmd/data-service/service/audit"
+	"hcm/cmd/data-service/service/audit/sink"
 	"hcm/cmd/data-service/service/auth"
+	"hcm/cmd/data-service/service/auth/rbac"
 	"hcm/cmd/data-service/service/bill/billadjustmentitem"
 	"hcm/cmd/data-service/service/bill/billdailytask"
 	"hcm/cmd/data-service/service/bill/billexchangerate"
@@ -59,17 +61,22 @@ import (
 	"hcm/cmd/data-service/service/cloud/eip"
 	eipcvmrel "hcm/cmd/data-service/service/cloud/eip-cvm-rel"
 	"hcm/cmd/data-service/service/cloud/image"
+	"hcm/cmd/data-service/service/cloud/ipam"
 	loadbalancer "hcm/cmd/data-service/service/cloud/load-balancer"
 	networkinterface "hcm/cmd/data-service/service/cloud/network-interface"
 	networkcvmrel "hcm/cmd/data-service/service/cloud/network-interface-cvm-rel"
 	"hcm/cmd/data-service/service/cloud/region"
 	resourcegroup "hcm/cmd/data-service/service/cloud/resource-group"
+	restag "hcm/cmd/data-service/service/cloud/resource-tag"
 	routetable "hcm/cmd/data-service/service/cloud/route-table"
 	securitygroup "hcm/cmd/data-service/service/cloud/security-group"
 	sgcomrel "hcm/cmd/data-service/service/cloud/security-group-common-rel"
 	sgcvmrel "hcm/cmd/data-service/service/cloud/security-group-cvm-rel"
+	sgrisk "hcm/cmd/data-service/service/cloud/security-group-risk"
+	sgruletpl "hcm/cmd/data-service/service/cloud/security-group-rule-template"
 	subaccount "hcm/cmd/data-service/service/cloud/sub-account"
 	sync "hcm/cmd/data-service/service/cloud/sync"
+	tagpolicy "hcm/cmd/data-service/service/cloud/tag-policy"
 	"hcm/cmd/data-service/service/cloud/zone"
 	"hcm/cmd/data-service/service/cos"
 	recyclerecord "hcm/cmd/data-service/service/recycle-record"
@@ -101,8 +108,10 @@ type Service struct {
 }
 
 // NewService create a service instance.
-func NewService() (*Service, error) {
-	dao, err := dao.NewDaoSet(cc.DataService().Database)
+func NewService(sd serviced.State) (*Service, error) {
+	sinkConf := cc.DataService().AuditSink
+	dao, err := dao.NewDaoSet(cc.DataService().Database, dao.WithAuditOutbox(sinkConf.Enable),
+		dao.WithAuditChainShards(cc.DataService().AuditChain.Shards))
 	if err != nil {
 		return nil, err
 	}
@@ -133,6 +142,15 @@ func NewService() (*Service, error) {
 		objectStore: oStore,
 	}
 
+	// 审计事件投递到外部审计系统
+	if sinkConf.Enable {
+		dispatcher, err := sink.NewDispatcher(dao, sd, sinkConf)
+		if err != nil {
+			return nil, err
+		}
+		go dispatcher.Run()
+	}
+
 	return svr, nil
 }
 
@@ -221,6 +239,7 @@ func (s *Service) apiSet() *restful.Container {
 	cloud.InitSubnetService(capability)
 	cloud.InitCloudService(capability)
 	auth.InitAuthService(capability)
+	rbac.InitService(capability)
 	disk.InitService(capability)
 	region.InitRegionService(capability)
 	resourcegroup.InitAzureResourceGroupService(capability)
@@ -233,6 +252,7 @@ func (s *Service) apiSet() *restful.Container {
 	routetable.InitRouteTableService(capability)
 	application.InitApplicationService(capability)
 	application.InitApprovalProcessService(capability)
+	application.InitApplicationPolicyService(capability)
 	diskcvmrel.InitService(capability)
 	eipcvmrel.InitService(capability)
 	networkinterface.InitNetInterfaceService(capability)
@@ -247,6 +267,11 @@ func (s *Service) apiSet() *restful.Container {
 	cert.InitService(capability)
 	loadbalancer.InitService(capability)
 	sgcomrel.InitService(capability)
+	sgrisk.InitService(capability)
+	restag.InitService(capability)
+	tagpolicy.InitService(capability)
+	sgruletpl.InitService(capability)
+	ipam.InitService(capability)
 	mainaccount.InitService(capability)
 	rootaccount.InitService(capability)
 
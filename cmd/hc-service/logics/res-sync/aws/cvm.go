@@ -96,6 +96,12 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		}
 	}
 
+	err = common.SyncResourceTags(kt, cli.dbCli, enumor.Aws, params.AccountID, enumor.CvmCloudResType, cvmFromCloud,
+		delCloudIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
@@ -180,6 +186,7 @@ func (cli *client) createCvm(kt *kit.Kit, accountID string, region string,
 			// 云上不支持该字段
 			Memo:                 nil,
 			Status:               converter.PtrToVal(one.State.Name),
+			LifecycleStatus:      corecvm.NormalizeLifecycleStatus(enumor.Aws, converter.PtrToVal(one.State.Name)),
 			PrivateIPv4Addresses: privateIPv4Addresses,
 			// 云上不支持该字段
 			PrivateIPv6Addresses: nil,
@@ -320,6 +327,7 @@ func (cli *client) updateCvm(kt *kit.Kit, accountID string, region string,
 			// 云上不支持该字段
 			Memo:                 nil,
 			Status:               converter.PtrToVal(one.State.Name),
+			LifecycleStatus:      corecvm.NormalizeLifecycleStatus(enumor.Aws, converter.PtrToVal(one.State.Name)),
 			PrivateIPv4Addresses: privateIPv4Addresses,
 			// 云上不支持该字段
 			PrivateIPv6Addresses: nil,
@@ -93,6 +93,12 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		}
 	}
 
+	err = common.SyncResourceTags(kt, cli.dbCli, enumor.Aws, params.AccountID, enumor.VpcCloudResType, vpcFromCloud,
+		delCloudIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -93,6 +93,12 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		}
 	}
 
+	err = common.SyncResourceTags(kt, cli.dbCli, enumor.Azure, params.AccountID, enumor.CvmCloudResType, cvmFromCloud,
+		delCloudIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
@@ -233,6 +239,7 @@ func (cli *client) createCvm(kt *kit.Kit, accountID string, resGroupName string,
 			// 云上不支持该字段
 			Memo:                 nil,
 			Status:               converter.PtrToVal(one.Status),
+			LifecycleStatus:      corecvm.NormalizeLifecycleStatus(enumor.Azure, converter.PtrToVal(one.Status)),
 			PrivateIPv4Addresses: cloudMap[converter.PtrToVal(one.ID)].PrivateIPv4Addresses,
 			PrivateIPv6Addresses: cloudMap[converter.PtrToVal(one.ID)].PrivateIPv6Addresses,
 			PublicIPv4Addresses:  cloudMap[converter.PtrToVal(one.ID)].PublicIPv4Addresses,
@@ -357,6 +364,7 @@ func (cli *client) updateCvm(kt *kit.Kit, accountID string, resGroupName string,
 			// 云上不支持该字段
 			Memo:                 nil,
 			Status:               converter.PtrToVal(one.Status),
+			LifecycleStatus:      corecvm.NormalizeLifecycleStatus(enumor.Azure, converter.PtrToVal(one.Status)),
 			PrivateIPv4Addresses: cloudMap[converter.PtrToVal(one.ID)].PrivateIPv4Addresses,
 			PrivateIPv6Addresses: cloudMap[converter.PtrToVal(one.ID)].PrivateIPv6Addresses,
 			PublicIPv4Addresses:  cloudMap[converter.PtrToVal(one.ID)].PublicIPv4Addresses,
@@ -91,6 +91,12 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 		}
 	}
 
+	err = common.SyncResourceTags(kt, cli.dbCli, enumor.Azure, params.AccountID, enumor.EipCloudResType, eipFromCloud,
+		delCloudIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
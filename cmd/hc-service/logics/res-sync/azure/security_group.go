@@ -113,6 +113,12 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		return nil, err
 	}
 
+	err = common.SyncResourceTags(kt, cli.dbCli, enumor.Azure, params.AccountID, enumor.SecurityGroupCloudResType,
+		sgFromCloud, delCloudIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
@@ -0,0 +1,79 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package common
+
+import (
+	protocloud "hcm/pkg/api/data-service/cloud"
+	dataclient "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+// TaggedCloudRes 携带标签的云上资源
+type TaggedCloudRes interface {
+	GetCloudID() string
+	GetCloudTags() map[string]string
+}
+
+// SyncResourceTags 同步云上资源的标签到资源标签表，删除的资源的标签会一并清理
+func SyncResourceTags[T TaggedCloudRes](kt *kit.Kit, dataCli *dataclient.Client, vendor enumor.Vendor,
+	accountID string, resType enumor.CloudResourceType, resFromCloud []T, delCloudIDs []string) error {
+
+	items := make([]protocloud.ResourceTagSyncItem, 0, len(resFromCloud))
+	for _, one := range resFromCloud {
+		items = append(items, protocloud.ResourceTagSyncItem{
+			CloudResID: one.GetCloudID(),
+			Tags:       one.GetCloudTags(),
+		})
+	}
+
+	for _, batch := range slice.Split(items, constant.BatchOperationMaxLimit) {
+		req := &protocloud.ResourceTagSyncReq{
+			Vendor:    vendor,
+			AccountID: accountID,
+			ResType:   resType,
+			Items:     batch,
+		}
+		if err := dataCli.Global.ResourceTag.Sync(kt, req); err != nil {
+			logs.Errorf("[%s] sync %s resource tags failed, err: %v, account: %s, rid: %s", vendor, resType, err,
+				accountID, kt.Rid)
+			return err
+		}
+	}
+
+	for _, batch := range slice.Split(delCloudIDs, constant.BatchOperationMaxLimit) {
+		req := &protocloud.ResourceTagSyncReq{
+			Vendor:             vendor,
+			AccountID:          accountID,
+			ResType:            resType,
+			DeletedCloudResIDs: batch,
+		}
+		if err := dataCli.Global.ResourceTag.Sync(kt, req); err != nil {
+			logs.Errorf("[%s] clean deleted %s resource tags failed, err: %v, account: %s, rid: %s", vendor, resType,
+				err, accountID, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
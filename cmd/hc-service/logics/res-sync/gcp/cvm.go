@@ -98,6 +98,12 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		}
 	}
 
+	err = common.SyncResourceTags(kt, cli.dbCli, enumor.Gcp, params.AccountID, enumor.CvmCloudResType, cvmFromCloud,
+		delCloudIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
@@ -195,6 +201,7 @@ func (cli *client) createCvm(kt *kit.Kit, accountID string, region string, zone
 			OsName:               "",
 			Memo:                 converter.ValToPtr(one.Description),
 			Status:               one.Status,
+			LifecycleStatus:      corecvm.NormalizeLifecycleStatus(enumor.Gcp, one.Status),
 			PrivateIPv4Addresses: priIPv4,
 			PrivateIPv6Addresses: priIPv6,
 			PublicIPv4Addresses:  pubIPv4,
@@ -345,6 +352,7 @@ func (cli *client) updateCvm(kt *kit.Kit, accountID string, region string, zone
 			SubnetIDs:            subnetIDs,
 			Memo:                 converter.ValToPtr(one.Description),
 			Status:               one.Status,
+			LifecycleStatus:      corecvm.NormalizeLifecycleStatus(enumor.Gcp, one.Status),
 			PrivateIPv4Addresses: priIPv4,
 			PrivateIPv6Addresses: priIPv6,
 			PublicIPv4Addresses:  pubIPv4,
@@ -100,6 +100,12 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		}
 	}
 
+	err = common.SyncResourceTags(kt, cli.dbCli, enumor.HuaWei, params.AccountID, enumor.CvmCloudResType, cvmFromCloud,
+		delCloudIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
@@ -179,6 +185,7 @@ func (cli *client) updateCvm(kt *kit.Kit, accountID string, region string,
 			ImageID:              imageID,
 			Memo:                 one.Description,
 			Status:               one.Status,
+			LifecycleStatus:      corecvm.NormalizeLifecycleStatus(enumor.HuaWei, one.Status),
 			PrivateIPv4Addresses: privateIPv4Addresses,
 			PrivateIPv6Addresses: privateIPv6Addresses,
 			PublicIPv4Addresses:  publicIPv4Addresses,
@@ -340,6 +347,7 @@ func (cli *client) createCvm(kt *kit.Kit, accountID string, region string,
 			OsName:               one.Metadata["os_type"],
 			Memo:                 one.Description,
 			Status:               one.Status,
+			LifecycleStatus:      corecvm.NormalizeLifecycleStatus(enumor.HuaWei, one.Status),
 			PrivateIPv4Addresses: privateIPv4Addresses,
 			PrivateIPv6Addresses: privateIPv6Addresses,
 			PublicIPv4Addresses:  publicIPv4Addresses,
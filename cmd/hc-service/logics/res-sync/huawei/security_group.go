@@ -90,6 +90,8 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		}
 	}
 
+	// 华为云安全组（vpc v3）接口未返回标签，且 SDK 未提供安全组标签查询接口，所以不同步安全组标签
+
 	// 同步安全组规则
 	sgFromDB, err = cli.listSGFromDB(kt, params)
 	if err != nil {
@@ -92,6 +92,12 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		}
 	}
 
+	err = common.SyncResourceTags(kt, cli.dbCli, enumor.TCloud, params.AccountID, enumor.CvmCloudResType, cvmFromCloud,
+		delCloudIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
@@ -147,6 +153,7 @@ func (cli *client) updateCvm(kt *kit.Kit, accountID string, region string,
 			dataDiskIDs = append(dataDiskIDs, *disk.DiskId)
 		}
 
+		status := converter.PtrToVal(one.InstanceState)
 		updateOne := dataproto.CvmBatchUpdate[corecvm.TCloudCvmExtension]{
 			ID:             id,
 			Name:           converter.PtrToVal(one.InstanceName),
@@ -159,7 +166,8 @@ func (cli *client) updateCvm(kt *kit.Kit, accountID string, region string,
 			ImageID:        imageID,
 			// 备注字段云上没有，仅限hcm内部使用
 			Memo:                 nil,
-			Status:               converter.PtrToVal(one.InstanceState),
+			Status:               status,
+			LifecycleStatus:      corecvm.NormalizeLifecycleStatus(enumor.TCloud, status),
 			PrivateIPv4Addresses: converter.PtrToSlice(one.PrivateIpAddresses),
 			PublicIPv4Addresses:  converter.PtrToSlice(one.PublicIpAddresses),
 			// 云上该字段没有
@@ -270,6 +278,7 @@ func (cli *client) createCvm(kt *kit.Kit, accountID string, region string,
 			dataDiskIDs = append(dataDiskIDs, *disk.DiskId)
 		}
 
+		status := converter.PtrToVal(one.InstanceState)
 		addOne := dataproto.CvmBatchCreate[corecvm.TCloudCvmExtension]{
 			CloudID:        converter.PtrToVal(one.InstanceId),
 			Name:           converter.PtrToVal(one.InstanceName),
@@ -287,7 +296,8 @@ func (cli *client) createCvm(kt *kit.Kit, accountID string, region string,
 			OsName:         converter.PtrToVal(one.OsName),
 			// 备注字段云上没有，仅限hcm内部使用
 			Memo:                 nil,
-			Status:               converter.PtrToVal(one.InstanceState),
+			Status:               status,
+			LifecycleStatus:      corecvm.NormalizeLifecycleStatus(enumor.TCloud, status),
 			PrivateIPv4Addresses: converter.PtrToSlice(one.PrivateIpAddresses),
 			PublicIPv4Addresses:  converter.PtrToSlice(one.PublicIpAddresses),
 			MachineType:          *one.InstanceType,
@@ -90,6 +90,12 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		}
 	}
 
+	err = common.SyncResourceTags(kt, cli.dbCli, enumor.TCloud, params.AccountID, enumor.DiskCloudResType,
+		diskFromCloud, delCloudIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
@@ -126,6 +126,13 @@ func (cli *client) LoadBalancer(kt *kit.Kit, params *SyncBaseParams, opt *SyncLB
 	if err = cli.updateLoadBalancer(kt, params.AccountID, params.Region, updateMap); err != nil {
 		return nil, err
 	}
+
+	err = common.SyncResourceTags(kt, cli.dbCli, enumor.TCloud, params.AccountID, enumor.LoadBalancerCloudResType,
+		lbFromCloud, delCloudIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
@@ -115,6 +115,12 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		return nil, err
 	}
 
+	err = common.SyncResourceTags(kt, cli.dbCli, enumor.TCloud, params.AccountID, enumor.SecurityGroupCloudResType,
+		sgFromCloud, delCloudIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	return new(SyncResult), nil
 }
 
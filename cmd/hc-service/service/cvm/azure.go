@@ -236,6 +236,7 @@ func (svc *cvmSvc) StopAzureCvm(cts *rest.Contexts) (interface{}, error) {
 		ResourceGroupName: cvmFromDB.Extension.ResourceGroupName,
 		Name:              cvmFromDB.Name,
 		SkipShutdown:      req.SkipShutdown,
+		Deallocate:        req.Deallocate,
 	}
 	if err = client.StopCvm(cts.Kit, opt); err != nil {
 		logs.Errorf("request adaptor to stop azure cvm failed, err: %v, opt: %v, rid: %s", err, opt, cts.Kit.Rid)
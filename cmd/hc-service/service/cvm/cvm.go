@@ -39,6 +39,8 @@ func InitCvmService(cap *capability.Capability) {
 	svc.initAzureCvmService(cap)
 	svc.initGcpCvmService(cap)
 	svc.initHuaWeiCvmService(cap)
+	svc.initCvmStatusService(cap)
+	svc.initCvmResizeService(cap)
 }
 
 type cvmSvc struct {
@@ -0,0 +1,241 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cvm
+
+import (
+	"net/http"
+
+	"hcm/cmd/hc-service/service/capability"
+	typecvm "hcm/pkg/adaptor/types/cvm"
+	"hcm/pkg/api/core"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	protocvm "hcm/pkg/api/hc-service/cvm"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+)
+
+func (svc *cvmSvc) initCvmResizeService(cap *capability.Capability) {
+	h := rest.NewHandler()
+
+	h.Add("ResizeCvm", http.MethodPost, "/vendors/{vendor}/cvms/resize", svc.ResizeCvm)
+	h.Add("InquiryPriceResizeTCloudCvm", http.MethodPost, "/vendors/tcloud/cvms/resize/prices/inquiry",
+		svc.InquiryPriceResizeTCloudCvm)
+	h.Add("InquiryPriceResizeHuaWeiCvm", http.MethodPost, "/vendors/huawei/cvms/resize/prices/inquiry",
+		svc.InquiryPriceResizeHuaWeiCvm)
+
+	h.Load(cap.WebService)
+}
+
+// ResizeCvm 调整主机规格，调整完成后从云上同步主机信息。aws、gcp 要求主机处于关机状态，由调用方负责关机和开机
+func (svc *cvmSvc) ResizeCvm(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.PathParameter("vendor").String())
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(protocvm.ResizeCvmReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	cvm, err := svc.getBaseCvm(cts.Kit, vendor, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.resizeCvm(cts.Kit, vendor, cvm, req.InstanceType); err != nil {
+		logs.Errorf("resize %s cvm failed, err: %v, id: %s, instance type: %s, rid: %s", vendor, err, req.ID,
+			req.InstanceType, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if vendor == enumor.Azure {
+		return nil, svc.syncAzureCvmStatus(cts.Kit, cvm.ID)
+	}
+
+	if err = svc.syncCvmStatusByGroup(cts.Kit, vendor, []corecvm.BaseCvm{*cvm}); err != nil {
+		logs.Errorf("sync %s cvm after resize failed, err: %v, id: %s, rid: %s", vendor, err, req.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (svc *cvmSvc) resizeCvm(kt *kit.Kit, vendor enumor.Vendor, cvm *corecvm.BaseCvm, instanceType string) error {
+	switch vendor {
+	case enumor.TCloud:
+		cli, err := svc.ad.TCloud(kt, cvm.AccountID)
+		if err != nil {
+			return err
+		}
+		opt := &typecvm.TCloudResizeOption{Region: cvm.Region, CloudIDs: []string{cvm.CloudID},
+			InstanceType: instanceType}
+		return cli.ResizeCvm(kt, opt)
+
+	case enumor.Aws:
+		cli, err := svc.ad.Aws(kt, cvm.AccountID)
+		if err != nil {
+			return err
+		}
+		opt := &typecvm.AwsResizeOption{Region: cvm.Region, CloudID: cvm.CloudID, InstanceType: instanceType}
+		return cli.ResizeCvm(kt, opt)
+
+	case enumor.HuaWei:
+		cli, err := svc.ad.HuaWei(kt, cvm.AccountID)
+		if err != nil {
+			return err
+		}
+		opt := &typecvm.HuaWeiResizeOption{Region: cvm.Region, CloudID: cvm.CloudID, FlavorRef: instanceType}
+		return cli.ResizeCvm(kt, opt)
+
+	case enumor.Azure:
+		azureCvm, err := svc.dataCli.Azure.Cvm.GetCvm(kt.Ctx, kt.Header(), cvm.ID)
+		if err != nil {
+			return err
+		}
+		cli, err := svc.ad.Azure(kt, cvm.AccountID)
+		if err != nil {
+			return err
+		}
+		opt := &typecvm.AzureResizeOption{ResourceGroupName: azureCvm.Extension.ResourceGroupName,
+			Name: cvm.Name, VMSize: instanceType}
+		return cli.ResizeCvm(kt, opt)
+
+	case enumor.Gcp:
+		cli, err := svc.ad.Gcp(kt, cvm.AccountID)
+		if err != nil {
+			return err
+		}
+		opt := &typecvm.GcpResizeOption{Zone: cvm.Zone, Name: cvm.Name, MachineType: instanceType}
+		return cli.ResizeCvm(kt, opt)
+
+	default:
+		return errf.Newf(errf.InvalidParameter, "%s does not support resize cvm", vendor)
+	}
+}
+
+// InquiryPriceResizeTCloudCvm 腾讯云主机调整规格询价
+func (svc *cvmSvc) InquiryPriceResizeTCloudCvm(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocvm.ResizeCvmReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	cvm, err := svc.getBaseCvm(cts.Kit, enumor.TCloud, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := svc.ad.TCloud(cts.Kit, cvm.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typecvm.TCloudResizeOption{Region: cvm.Region, CloudIDs: []string{cvm.CloudID},
+		InstanceType: req.InstanceType}
+	result, err := cli.InquiryPriceResizeCvm(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("inquiry cvm resize price failed, err: %v, id: %s, rid: %s", err, req.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// InquiryPriceResizeHuaWeiCvm 华为云主机调整规格询价，按主机当前的计费模式查询目标规格的价格，包年包月返回一个月的价格，
+// 按需计费返回一小时的价格，仅包含云主机规格本身的价格，不包含云硬盘
+func (svc *cvmSvc) InquiryPriceResizeHuaWeiCvm(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocvm.ResizeCvmReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	cvm, err := svc.dataCli.HuaWei.Cvm.GetCvm(cts.Kit.Ctx, cts.Kit.Header(), req.ID)
+	if err != nil {
+		logs.Errorf("get huawei cvm failed, err: %v, id: %s, rid: %s", err, req.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	charge := new(typecvm.HuaWeiInstanceCharge)
+	chargingMode := ""
+	if cvm.Extension != nil && cvm.Extension.Metadata != nil {
+		chargingMode = cvm.Extension.Metadata.ChargingMode
+	}
+	switch chargingMode {
+	case "0":
+		charge.ChargingMode = typecvm.PostPaid
+	case "1":
+		charge.ChargingMode = typecvm.PrePaid
+		charge.PeriodType = converter.ValToPtr(typecvm.Month)
+		charge.PeriodNum = converter.ValToPtr(int32(1))
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "huawei cvm %s charging mode %s not support inquiry price",
+			req.ID, chargingMode)
+	}
+
+	cli, err := svc.ad.HuaWei(cts.Kit, cvm.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typecvm.HuaWeiCreateOption{Region: cvm.Region, Zone: cvm.Zone, InstanceType: req.InstanceType,
+		InstanceCharge: charge}
+	result, err := cli.InquiryPriceCvm(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("inquiry huawei cvm resize price failed, err: %v, id: %s, rid: %s", err, req.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// getBaseCvm 查询指定云厂商的主机基础信息
+func (svc *cvmSvc) getBaseCvm(kt *kit.Kit, vendor enumor.Vendor, id string) (*corecvm.BaseCvm, error) {
+	listReq := &core.ListReq{
+		Fields: []string{"id", "cloud_id", "name", "vendor", "account_id", "region", "zone"},
+		Filter: tools.ExpressionAnd(tools.RuleEqual("id", id), tools.RuleEqual("vendor", vendor)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	listResp, err := svc.dataCli.Global.Cvm.ListCvm(kt, listReq)
+	if err != nil {
+		logs.Errorf("list cvm failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(listResp.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "%s cvm %s not found", vendor, id)
+	}
+
+	return &listResp.Details[0], nil
+}
@@ -0,0 +1,182 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cvm
+
+import (
+	"fmt"
+	"net/http"
+
+	syncaws "hcm/cmd/hc-service/logics/res-sync/aws"
+	syncazure "hcm/cmd/hc-service/logics/res-sync/azure"
+	syncgcp "hcm/cmd/hc-service/logics/res-sync/gcp"
+	synchuawei "hcm/cmd/hc-service/logics/res-sync/huawei"
+	synctcloud "hcm/cmd/hc-service/logics/res-sync/tcloud"
+	"hcm/cmd/hc-service/service/capability"
+	"hcm/pkg/api/core"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	protocvm "hcm/pkg/api/hc-service/cvm"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+func (svc *cvmSvc) initCvmStatusService(cap *capability.Capability) {
+	h := rest.NewHandler()
+
+	h.Add("SyncCvmStatus", http.MethodPost, "/vendors/{vendor}/cvms/status/sync", svc.SyncCvmStatus)
+
+	h.Load(cap.WebService)
+}
+
+// SyncCvmStatus 按主机ID从云上同步主机，用于刷新操作后仍处于过渡状态的主机状态，已删除的主机会被跳过
+func (svc *cvmSvc) SyncCvmStatus(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.PathParameter("vendor").String())
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(protocvm.SyncCvmStatusReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Fields: []string{"id", "cloud_id", "vendor", "account_id", "region", "zone"},
+		Filter: tools.ExpressionAnd(tools.RuleIn("id", req.IDs), tools.RuleEqual("vendor", vendor)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	listResp, err := svc.dataCli.Global.Cvm.ListCvm(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list cvm failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if vendor == enumor.Azure {
+		// azure 同步需要主机所在资源组，需逐台查询主机扩展信息
+		for _, one := range listResp.Details {
+			if err = svc.syncAzureCvmStatus(cts.Kit, one.ID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+
+	// 按账号、地域（gcp还需可用区）分组后同步
+	groups := make(map[string][]corecvm.BaseCvm)
+	for _, one := range listResp.Details {
+		key := one.AccountID + "_" + one.Region
+		if vendor == enumor.Gcp {
+			key += "_" + one.Zone
+		}
+		groups[key] = append(groups[key], one)
+	}
+
+	for _, cvms := range groups {
+		if err = svc.syncCvmStatusByGroup(cts.Kit, vendor, cvms); err != nil {
+			logs.Errorf("sync %s cvm status failed, err: %v, account: %s, region: %s, rid: %s", vendor, err,
+				cvms[0].AccountID, cvms[0].Region, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// syncCvmStatusByGroup 同步同一账号、地域下的主机，cvms 不能为空
+func (svc *cvmSvc) syncCvmStatusByGroup(kt *kit.Kit, vendor enumor.Vendor, cvms []corecvm.BaseCvm) error {
+	accountID, region, zone := cvms[0].AccountID, cvms[0].Region, cvms[0].Zone
+	cloudIDs := make([]string, 0, len(cvms))
+	for _, one := range cvms {
+		cloudIDs = append(cloudIDs, one.CloudID)
+	}
+
+	switch vendor {
+	case enumor.TCloud:
+		cli, err := svc.ad.TCloud(kt, accountID)
+		if err != nil {
+			return err
+		}
+		params := &synctcloud.SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+		_, err = synctcloud.NewClient(svc.dataCli, cli).Cvm(kt, params, &synctcloud.SyncCvmOption{})
+		return err
+
+	case enumor.Aws:
+		cli, err := svc.ad.Aws(kt, accountID)
+		if err != nil {
+			return err
+		}
+		params := &syncaws.SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+		_, err = syncaws.NewClient(svc.dataCli, cli).Cvm(kt, params, &syncaws.SyncCvmOption{})
+		return err
+
+	case enumor.HuaWei:
+		cli, err := svc.ad.HuaWei(kt, accountID)
+		if err != nil {
+			return err
+		}
+		params := &synchuawei.SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+		_, err = synchuawei.NewClient(svc.dataCli, cli).Cvm(kt, params, &synchuawei.SyncCvmOption{})
+		return err
+
+	case enumor.Gcp:
+		cli, err := svc.ad.Gcp(kt, accountID)
+		if err != nil {
+			return err
+		}
+		params := &syncgcp.SyncBaseParams{AccountID: accountID, CloudIDs: cloudIDs}
+		_, err = syncgcp.NewClient(svc.dataCli, cli).Cvm(kt, params,
+			&syncgcp.SyncCvmOption{Region: region, Zone: zone})
+		return err
+
+	default:
+		return fmt.Errorf("vendor: %s not support", vendor)
+	}
+}
+
+func (svc *cvmSvc) syncAzureCvmStatus(kt *kit.Kit, id string) error {
+	cvmFromDB, err := svc.dataCli.Azure.Cvm.GetCvm(kt.Ctx, kt.Header(), id)
+	if err != nil {
+		logs.Errorf("request dataservice get azure cvm failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+
+	cli, err := svc.ad.Azure(kt, cvmFromDB.AccountID)
+	if err != nil {
+		return err
+	}
+
+	params := &syncazure.SyncBaseParams{
+		AccountID:         cvmFromDB.AccountID,
+		ResourceGroupName: cvmFromDB.Extension.ResourceGroupName,
+		CloudIDs:          []string{cvmFromDB.CloudID},
+	}
+	if _, err = syncazure.NewClient(svc.dataCli, cli).Cvm(kt, params, &syncazure.SyncCvmOption{}); err != nil {
+		logs.Errorf("sync azure cvm failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"hcm/pkg/adaptor/aws"
+	"hcm/pkg/adaptor/azure"
+	"hcm/pkg/adaptor/gcp"
+	"hcm/pkg/adaptor/huawei"
+	"hcm/pkg/adaptor/tcloud"
+	typesdisk "hcm/pkg/adaptor/types/disk"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/converter"
+)
+
+// snapshotOperator 各云云硬盘快照操作，屏蔽各云 adaptor 参数的差异
+type snapshotOperator interface {
+	// listSnapshot 查询地域下的快照，cloudID 不为空时只查询该快照
+	listSnapshot(kt *kit.Kit, region, cloudID string) ([]typesdisk.DiskSnapshot, error)
+	createSnapshot(kt *kit.Kit, disk *coredisk.BaseDisk, name string) (string, error)
+	deleteSnapshot(kt *kit.Kit, snapshot *coredisk.Snapshot) error
+	rollbackSnapshot(kt *kit.Kit, snapshot *coredisk.Snapshot, disk *coredisk.BaseDisk) error
+}
+
+func (svc *service) snapshotOperator(kt *kit.Kit, vendor enumor.Vendor, accountID string) (snapshotOperator,
+	error) {
+
+	switch vendor {
+	case enumor.TCloud:
+		cli, err := svc.adaptor.TCloud(kt, accountID)
+		if err != nil {
+			return nil, err
+		}
+		return &tcloudOperator{cli: cli}, nil
+	case enumor.Aws:
+		cli, err := svc.adaptor.Aws(kt, accountID)
+		if err != nil {
+			return nil, err
+		}
+		return &awsOperator{cli: cli, dataCli: svc.dataCli}, nil
+	case enumor.HuaWei:
+		cli, err := svc.adaptor.HuaWei(kt, accountID)
+		if err != nil {
+			return nil, err
+		}
+		return &huaweiOperator{cli: cli}, nil
+	case enumor.Azure:
+		cli, err := svc.adaptor.Azure(kt, accountID)
+		if err != nil {
+			return nil, err
+		}
+		return &azureOperator{cli: cli}, nil
+	case enumor.Gcp:
+		cli, err := svc.adaptor.Gcp(kt, accountID)
+		if err != nil {
+			return nil, err
+		}
+		return &gcpOperator{cli: cli}, nil
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "%s does not support disk snapshot", vendor)
+	}
+}
+
+type tcloudOperator struct {
+	cli tcloud.TCloud
+}
+
+func (op *tcloudOperator) listSnapshot(kt *kit.Kit, region, cloudID string) ([]typesdisk.DiskSnapshot, error) {
+	opt := &typesdisk.TCloudDiskSnapshotListOption{Region: region}
+	if len(cloudID) != 0 {
+		opt.CloudIDs = []string{cloudID}
+	}
+	return op.cli.ListDiskSnapshot(kt, opt)
+}
+
+func (op *tcloudOperator) createSnapshot(kt *kit.Kit, disk *coredisk.BaseDisk, name string) (string, error) {
+	opt := &typesdisk.TCloudDiskSnapshotCreateOption{Region: disk.Region, CloudDiskID: disk.CloudID,
+		SnapshotName: name}
+	return op.cli.CreateDiskSnapshot(kt, opt)
+}
+
+func (op *tcloudOperator) deleteSnapshot(kt *kit.Kit, snapshot *coredisk.Snapshot) error {
+	opt := &typesdisk.TCloudDiskSnapshotDeleteOption{Region: snapshot.Region, CloudIDs: []string{snapshot.CloudID}}
+	return op.cli.DeleteDiskSnapshot(kt, opt)
+}
+
+func (op *tcloudOperator) rollbackSnapshot(kt *kit.Kit, snapshot *coredisk.Snapshot, disk *coredisk.BaseDisk) error {
+	opt := &typesdisk.TCloudDiskSnapshotRollbackOption{Region: snapshot.Region, CloudID: snapshot.CloudID,
+		CloudDiskID: disk.CloudID}
+	return op.cli.RollbackDiskSnapshot(kt, opt)
+}
+
+type awsOperator struct {
+	cli     *aws.Aws
+	dataCli *dataservice.Client
+}
+
+func (op *awsOperator) listSnapshot(kt *kit.Kit, region, cloudID string) ([]typesdisk.DiskSnapshot, error) {
+	opt := &typesdisk.AwsDiskSnapshotListOption{Region: region}
+	if len(cloudID) != 0 {
+		opt.CloudIDs = []string{cloudID}
+	}
+	return op.cli.ListDiskSnapshot(kt, opt)
+}
+
+func (op *awsOperator) createSnapshot(kt *kit.Kit, disk *coredisk.BaseDisk, name string) (string, error) {
+	opt := &typesdisk.AwsDiskSnapshotCreateOption{Region: disk.Region, CloudDiskID: disk.CloudID, SnapshotName: name}
+	return op.cli.CreateDiskSnapshot(kt, opt)
+}
+
+func (op *awsOperator) deleteSnapshot(kt *kit.Kit, snapshot *coredisk.Snapshot) error {
+	opt := &typesdisk.AwsDiskSnapshotDeleteOption{Region: snapshot.Region, CloudID: snapshot.CloudID}
+	return op.cli.DeleteDiskSnapshot(kt, opt)
+}
+
+// rollbackSnapshot aws 仅支持使用快照替换主机的根卷，因此只能回滚挂载在主机上的系统盘
+func (op *awsOperator) rollbackSnapshot(kt *kit.Kit, snapshot *coredisk.Snapshot, disk *coredisk.BaseDisk) error {
+	if !disk.IsSystemDisk {
+		return errf.Newf(errf.InvalidParameter, "aws only support rollback snapshot of system disk, disk: %s",
+			disk.ID)
+	}
+
+	detail, err := op.dataCli.Aws.RetrieveDisk(kt.Ctx, kt.Header(), disk.ID)
+	if err != nil {
+		return err
+	}
+
+	var cloudCvmID string
+	if detail.Extension != nil && len(detail.Extension.Attachment) != 0 {
+		cloudCvmID = converter.PtrToVal(detail.Extension.Attachment[0].InstanceId)
+	}
+	if len(cloudCvmID) == 0 {
+		return errf.Newf(errf.InvalidParameter, "aws disk %s is not attached to any cvm", disk.ID)
+	}
+
+	opt := &typesdisk.AwsDiskSnapshotRollbackOption{Region: snapshot.Region, CloudID: snapshot.CloudID,
+		CloudCvmID: cloudCvmID}
+	return op.cli.RollbackDiskSnapshot(kt, opt)
+}
+
+type huaweiOperator struct {
+	cli *huawei.HuaWei
+}
+
+func (op *huaweiOperator) listSnapshot(kt *kit.Kit, region, cloudID string) ([]typesdisk.DiskSnapshot, error) {
+	return op.cli.ListDiskSnapshot(kt, &typesdisk.HuaWeiDiskSnapshotListOption{Region: region, CloudID: cloudID})
+}
+
+func (op *huaweiOperator) createSnapshot(kt *kit.Kit, disk *coredisk.BaseDisk, name string) (string, error) {
+	opt := &typesdisk.HuaWeiDiskSnapshotCreateOption{Region: disk.Region, CloudDiskID: disk.CloudID,
+		SnapshotName: name}
+	return op.cli.CreateDiskSnapshot(kt, opt)
+}
+
+func (op *huaweiOperator) deleteSnapshot(kt *kit.Kit, snapshot *coredisk.Snapshot) error {
+	opt := &typesdisk.HuaWeiDiskSnapshotDeleteOption{Region: snapshot.Region, CloudID: snapshot.CloudID}
+	return op.cli.DeleteDiskSnapshot(kt, opt)
+}
+
+func (op *huaweiOperator) rollbackSnapshot(kt *kit.Kit, snapshot *coredisk.Snapshot, disk *coredisk.BaseDisk) error {
+	opt := &typesdisk.HuaWeiDiskSnapshotRollbackOption{Region: snapshot.Region, CloudID: snapshot.CloudID,
+		CloudDiskID: disk.CloudID}
+	return op.cli.RollbackDiskSnapshot(kt, opt)
+}
+
+type azureOperator struct {
+	cli *azure.Azure
+}
+
+func (op *azureOperator) listSnapshot(kt *kit.Kit, region, cloudID string) ([]typesdisk.DiskSnapshot, error) {
+	snapshots, err := op.cli.ListDiskSnapshot(kt, &typesdisk.AzureDiskSnapshotListOption{Region: region})
+	if err != nil {
+		return nil, err
+	}
+	return filterByCloudID(snapshots, cloudID), nil
+}
+
+func (op *azureOperator) createSnapshot(kt *kit.Kit, disk *coredisk.BaseDisk, name string) (string, error) {
+	opt := &typesdisk.AzureDiskSnapshotCreateOption{Region: disk.Region, CloudDiskID: disk.CloudID,
+		SnapshotName: name}
+	return op.cli.CreateDiskSnapshot(kt, opt)
+}
+
+func (op *azureOperator) deleteSnapshot(kt *kit.Kit, snapshot *coredisk.Snapshot) error {
+	return op.cli.DeleteDiskSnapshot(kt, &typesdisk.AzureDiskSnapshotDeleteOption{CloudID: snapshot.CloudID})
+}
+
+// rollbackSnapshot azure 云上不提供快照回滚能力，需基于快照创建新的云硬盘后替换
+func (op *azureOperator) rollbackSnapshot(_ *kit.Kit, _ *coredisk.Snapshot, _ *coredisk.BaseDisk) error {
+	return errf.New(errf.InvalidParameter, "azure does not support rollback disk snapshot")
+}
+
+type gcpOperator struct {
+	cli *gcp.Gcp
+}
+
+func (op *gcpOperator) listSnapshot(kt *kit.Kit, region, cloudID string) ([]typesdisk.DiskSnapshot, error) {
+	snapshots, err := op.cli.ListDiskSnapshot(kt, &typesdisk.GcpDiskSnapshotListOption{Region: region})
+	if err != nil {
+		return nil, err
+	}
+	return filterByCloudID(snapshots, cloudID), nil
+}
+
+func (op *gcpOperator) createSnapshot(kt *kit.Kit, disk *coredisk.BaseDisk, name string) (string, error) {
+	opt := &typesdisk.GcpDiskSnapshotCreateOption{Zone: disk.Zone, DiskName: disk.Name, SnapshotName: name}
+	return op.cli.CreateDiskSnapshot(kt, opt)
+}
+
+func (op *gcpOperator) deleteSnapshot(kt *kit.Kit, snapshot *coredisk.Snapshot) error {
+	return op.cli.DeleteDiskSnapshot(kt, &typesdisk.GcpDiskSnapshotDeleteOption{SnapshotName: snapshot.Name})
+}
+
+// rollbackSnapshot gcp 云上不提供快照回滚能力，需基于快照创建新的云硬盘后替换
+func (op *gcpOperator) rollbackSnapshot(_ *kit.Kit, _ *coredisk.Snapshot, _ *coredisk.BaseDisk) error {
+	return errf.New(errf.InvalidParameter, "gcp does not support rollback disk snapshot")
+}
+
+func filterByCloudID(snapshots []typesdisk.DiskSnapshot, cloudID string) []typesdisk.DiskSnapshot {
+	if len(cloudID) == 0 {
+		return snapshots
+	}
+
+	for _, one := range snapshots {
+		if one.CloudID == cloudID {
+			return []typesdisk.DiskSnapshot{one}
+		}
+	}
+
+	return make([]typesdisk.DiskSnapshot, 0)
+}
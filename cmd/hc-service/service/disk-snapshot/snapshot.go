@@ -0,0 +1,337 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"hcm/pkg/api/core"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	dataservice "hcm/pkg/api/data-service"
+	datadisk "hcm/pkg/api/data-service/cloud/disk"
+	proto "hcm/pkg/api/hc-service/disk"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// CreateDiskSnapshot 为云硬盘创建快照并记录，快照继承云硬盘的业务。由定期快照策略触发时，
+// 创建完成后删除该云硬盘超出保留个数的最早的策略快照
+func (svc *service) CreateDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(proto.SnapshotCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	disk, err := svc.getDisk(cts.Kit, vendor, req.AccountID, req.DiskID)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := svc.snapshotOperator(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	cloudID, err := op.createSnapshot(cts.Kit, disk, req.SnapshotName)
+	if err != nil {
+		logs.Errorf("create %s disk snapshot failed, err: %v, disk: %s, rid: %s", vendor, err, disk.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	snapshot := datadisk.SnapshotCreate{
+		Vendor:      vendor,
+		AccountID:   req.AccountID,
+		BkBizID:     disk.BkBizID,
+		Region:      disk.Region,
+		CloudID:     cloudID,
+		Name:        req.SnapshotName,
+		DiskID:      disk.ID,
+		CloudDiskID: disk.CloudID,
+		DiskSize:    disk.DiskSize,
+		PolicyID:    req.PolicyID,
+		Memo:        req.Memo,
+	}
+	// 查询快照的云上状态，查询失败不影响快照的记录，状态会在下次同步时更新
+	details, err := op.listSnapshot(cts.Kit, disk.Region, cloudID)
+	if err != nil {
+		logs.Warnf("get %s disk snapshot %s failed, err: %v, rid: %s", vendor, cloudID, err, cts.Kit.Rid)
+	} else if len(details) != 0 {
+		snapshot.Status = details[0].Status
+		snapshot.CloudCreatedTime = details[0].CloudCreatedTime
+	}
+
+	createReq := &datadisk.SnapshotBatchCreateReq{Snapshots: []datadisk.SnapshotCreate{snapshot}}
+	result, err := svc.dataCli.Global.DiskSnapshot.BatchCreate(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create disk snapshot record failed, err: %v, cloud id: %s, rid: %s", err, cloudID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(req.PolicyID) != 0 && req.RetentionCount > 0 {
+		if err = svc.cleanExpiredPolicySnapshots(cts.Kit, op, disk.ID, req.PolicyID, req.RetentionCount); err != nil {
+			return nil, err
+		}
+	}
+
+	return &core.CreateResult{ID: result.IDs[0]}, nil
+}
+
+// cleanExpiredPolicySnapshots 删除云硬盘超出保留个数的最早的策略快照
+func (svc *service) cleanExpiredPolicySnapshots(kt *kit.Kit, op snapshotOperator, diskID, policyID string,
+	retentionCount uint64) error {
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("disk_id", diskID), tools.RuleEqual("policy_id", policyID)),
+		Page: &core.BasePage{
+			Start: uint32(retentionCount),
+			Limit: core.DefaultMaxPageLimit,
+			Sort:  "created_at",
+			Order: core.Descending,
+		},
+	}
+	result, err := svc.dataCli.Global.DiskSnapshot.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list expired policy snapshot failed, err: %v, disk: %s, policy: %s, rid: %s", err, diskID,
+			policyID, kt.Rid)
+		return err
+	}
+
+	if len(result.Details) == 0 {
+		return nil
+	}
+
+	return svc.deleteSnapshots(kt, op, result.Details)
+}
+
+// DeleteDiskSnapshot 删除云硬盘快照
+func (svc *service) DeleteDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(proto.SnapshotDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	snapshots, err := svc.listSnapshots(cts.Kit, vendor, req.AccountID, req.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := svc.snapshotOperator(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, svc.deleteSnapshots(cts.Kit, op, snapshots)
+}
+
+// deleteSnapshots 删除云上快照及其记录，删除失败时仍删除已在云上删除成功的快照记录
+func (svc *service) deleteSnapshots(kt *kit.Kit, op snapshotOperator, snapshots []*coredisk.Snapshot) error {
+	deletedIDs := make([]string, 0, len(snapshots))
+	var deleteErr error
+	for _, one := range snapshots {
+		if deleteErr = op.deleteSnapshot(kt, one); deleteErr != nil {
+			logs.Errorf("delete %s disk snapshot failed, err: %v, id: %s, rid: %s", one.Vendor, deleteErr, one.ID,
+				kt.Rid)
+			break
+		}
+		deletedIDs = append(deletedIDs, one.ID)
+	}
+
+	if len(deletedIDs) != 0 {
+		delReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", deletedIDs)}
+		if err := svc.dataCli.Global.DiskSnapshot.BatchDelete(kt, delReq); err != nil {
+			logs.Errorf("delete disk snapshot records failed, err: %v, ids: %v, rid: %s", err, deletedIDs, kt.Rid)
+			return err
+		}
+	}
+
+	return deleteErr
+}
+
+// RollbackDiskSnapshot 将快照回滚到源云硬盘
+func (svc *service) RollbackDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(proto.SnapshotRollbackReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	snapshots, err := svc.listSnapshots(cts.Kit, vendor, req.AccountID, []string{req.ID})
+	if err != nil {
+		return nil, err
+	}
+	snapshot := snapshots[0]
+
+	if len(snapshot.DiskID) == 0 {
+		return nil, errf.Newf(errf.InvalidParameter, "source disk of snapshot %s is not managed by hcm", req.ID)
+	}
+
+	disk, err := svc.getDisk(cts.Kit, vendor, req.AccountID, snapshot.DiskID)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := svc.snapshotOperator(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = op.rollbackSnapshot(cts.Kit, snapshot, disk); err != nil {
+		logs.Errorf("rollback %s disk snapshot failed, err: %v, id: %s, rid: %s", vendor, err, req.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// RefreshDiskSnapshotStatus 查询快照的云上状态并更新快照记录，云上已不存在的快照返回错误
+func (svc *service) RefreshDiskSnapshotStatus(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(proto.SnapshotStatusRefreshReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	snapshots, err := svc.listSnapshots(cts.Kit, vendor, req.AccountID, req.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := svc.snapshotOperator(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &proto.SnapshotStatusRefreshResult{Details: make([]proto.SnapshotStatus, 0, len(snapshots))}
+	updates := make([]datadisk.SnapshotUpdate, 0)
+	for _, one := range snapshots {
+		details, err := op.listSnapshot(cts.Kit, one.Region, one.CloudID)
+		if err != nil {
+			logs.Errorf("get %s disk snapshot %s failed, err: %v, rid: %s", vendor, one.CloudID, err, cts.Kit.Rid)
+			return nil, err
+		}
+		if len(details) == 0 {
+			return nil, errf.Newf(errf.RecordNotFound, "disk snapshot %s is not found in cloud", one.CloudID)
+		}
+
+		status := details[0].Status
+		result.Details = append(result.Details, proto.SnapshotStatus{ID: one.ID, CloudID: one.CloudID,
+			Status: status})
+		if status != one.Status {
+			updates = append(updates, datadisk.SnapshotUpdate{ID: one.ID, Status: status})
+		}
+	}
+
+	if len(updates) != 0 {
+		updateReq := &datadisk.SnapshotBatchUpdateReq{Snapshots: updates}
+		if err = svc.dataCli.Global.DiskSnapshot.BatchUpdate(cts.Kit, updateReq); err != nil {
+			logs.Errorf("update disk snapshot status failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// getDisk 查询云硬盘，并校验云硬盘属于该账号
+func (svc *service) getDisk(kt *kit.Kit, vendor enumor.Vendor, accountID, diskID string) (*coredisk.BaseDisk,
+	error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("id", diskID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dataCli.Global.ListDisk(kt, listReq)
+	if err != nil {
+		logs.Errorf("list disk failed, err: %v, id: %s, rid: %s", err, diskID, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "disk %s not found", diskID)
+	}
+
+	disk := result.Details[0]
+	if enumor.Vendor(disk.Vendor) != vendor || disk.AccountID != accountID {
+		return nil, errf.Newf(errf.InvalidParameter, "disk %s does not belong to %s account %s", diskID, vendor,
+			accountID)
+	}
+
+	return disk, nil
+}
+
+// listSnapshots 查询快照，并校验快照均存在且属于该账号
+func (svc *service) listSnapshots(kt *kit.Kit, vendor enumor.Vendor, accountID string, ids []string) (
+	[]*coredisk.Snapshot, error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", ids),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dataCli.Global.DiskSnapshot.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list disk snapshot failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) != len(ids) {
+		return nil, errf.Newf(errf.RecordNotFound, "some disk snapshots of %v are not found", ids)
+	}
+
+	for _, one := range result.Details {
+		if one.Vendor != vendor || one.AccountID != accountID {
+			return nil, errf.Newf(errf.InvalidParameter, "disk snapshot %s does not belong to %s account %s",
+				one.ID, vendor, accountID)
+		}
+	}
+
+	return result.Details, nil
+}
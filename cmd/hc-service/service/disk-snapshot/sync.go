@@ -0,0 +1,224 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"hcm/pkg/api/core"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	dataservice "hcm/pkg/api/data-service"
+	datadisk "hcm/pkg/api/data-service/cloud/disk"
+	proto "hcm/pkg/api/hc-service/disk"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+)
+
+// SyncDiskSnapshot 同步账号地域下的云硬盘快照
+func (svc *service) SyncDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(proto.SnapshotSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	op, err := svc.snapshotOperator(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	cloudSnapshots, err := op.listSnapshot(cts.Kit, req.Region, "")
+	if err != nil {
+		logs.Errorf("list %s cloud disk snapshot failed, err: %v, account: %s, region: %s, rid: %s", vendor, err,
+			req.AccountID, req.Region, cts.Kit.Rid)
+		return nil, err
+	}
+
+	dbSnapshots, err := svc.listAllDBSnapshots(cts.Kit, vendor, req.AccountID, req.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	cloudMap := make(map[string]bool, len(cloudSnapshots))
+	dbMap := make(map[string]*coredisk.Snapshot, len(dbSnapshots))
+	for _, one := range dbSnapshots {
+		dbMap[one.CloudID] = one
+	}
+
+	adds := make([]datadisk.SnapshotCreate, 0)
+	updates := make([]datadisk.SnapshotUpdate, 0)
+	for _, one := range cloudSnapshots {
+		cloudMap[one.CloudID] = true
+
+		db, exists := dbMap[one.CloudID]
+		if !exists {
+			adds = append(adds, datadisk.SnapshotCreate{
+				Vendor:           vendor,
+				AccountID:        req.AccountID,
+				BkBizID:          constant.UnassignedBiz,
+				Region:           req.Region,
+				CloudID:          one.CloudID,
+				Name:             one.Name,
+				CloudDiskID:      one.CloudDiskID,
+				DiskSize:         one.DiskSize,
+				Status:           one.Status,
+				CloudCreatedTime: one.CloudCreatedTime,
+			})
+			continue
+		}
+
+		if db.Name == one.Name && db.Status == one.Status && db.DiskSize == one.DiskSize {
+			continue
+		}
+		updates = append(updates, datadisk.SnapshotUpdate{
+			ID:       db.ID,
+			Name:     one.Name,
+			DiskSize: one.DiskSize,
+			Status:   one.Status,
+		})
+	}
+
+	delIDs := make([]string, 0)
+	for _, one := range dbSnapshots {
+		if !cloudMap[one.CloudID] {
+			delIDs = append(delIDs, one.ID)
+		}
+	}
+
+	if err = svc.createSyncSnapshots(cts.Kit, vendor, req.AccountID, adds); err != nil {
+		return nil, err
+	}
+
+	for _, batch := range slice.Split(updates, constant.BatchOperationMaxLimit) {
+		updateReq := &datadisk.SnapshotBatchUpdateReq{Snapshots: batch}
+		if err = svc.dataCli.Global.DiskSnapshot.BatchUpdate(cts.Kit, updateReq); err != nil {
+			logs.Errorf("update disk snapshot records failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	for _, batch := range slice.Split(delIDs, constant.BatchOperationMaxLimit) {
+		delReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", batch)}
+		if err = svc.dataCli.Global.DiskSnapshot.BatchDelete(cts.Kit, delReq); err != nil {
+			logs.Errorf("delete disk snapshot records failed, err: %v, ids: %v, rid: %s", err, batch, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// createSyncSnapshots 记录云上新增的快照，源云硬盘已同步到hcm时，快照关联该云硬盘并继承其业务
+func (svc *service) createSyncSnapshots(kt *kit.Kit, vendor enumor.Vendor, accountID string,
+	adds []datadisk.SnapshotCreate) error {
+
+	if len(adds) == 0 {
+		return nil
+	}
+
+	cloudDiskIDs := make([]string, 0, len(adds))
+	for _, one := range adds {
+		if len(one.CloudDiskID) != 0 {
+			cloudDiskIDs = append(cloudDiskIDs, one.CloudDiskID)
+		}
+	}
+
+	diskMap := make(map[string]*coredisk.BaseDisk)
+	for _, batch := range slice.Split(slice.Unique(cloudDiskIDs), int(core.DefaultMaxPageLimit)) {
+		listReq := &core.ListReq{
+			Filter: tools.ExpressionAnd(
+				tools.RuleEqual("vendor", vendor),
+				tools.RuleEqual("account_id", accountID),
+				tools.RuleIn("cloud_id", batch),
+			),
+			Page: core.NewDefaultBasePage(),
+		}
+		result, err := svc.dataCli.Global.ListDisk(kt, listReq)
+		if err != nil {
+			logs.Errorf("list disk failed, err: %v, cloud ids: %v, rid: %s", err, batch, kt.Rid)
+			return err
+		}
+		for _, disk := range result.Details {
+			diskMap[disk.CloudID] = disk
+		}
+	}
+
+	for i := range adds {
+		disk, exists := diskMap[adds[i].CloudDiskID]
+		if !exists {
+			continue
+		}
+		adds[i].DiskID = disk.ID
+		adds[i].BkBizID = disk.BkBizID
+	}
+
+	for _, batch := range slice.Split(adds, constant.BatchOperationMaxLimit) {
+		createReq := &datadisk.SnapshotBatchCreateReq{Snapshots: batch}
+		if _, err := svc.dataCli.Global.DiskSnapshot.BatchCreate(kt, createReq); err != nil {
+			logs.Errorf("create disk snapshot records failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listAllDBSnapshots 查询账号地域下的全部快照记录
+func (svc *service) listAllDBSnapshots(kt *kit.Kit, vendor enumor.Vendor, accountID, region string) (
+	[]*coredisk.Snapshot, error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", vendor),
+			tools.RuleEqual("account_id", accountID),
+			tools.RuleEqual("region", region),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+
+	snapshots := make([]*coredisk.Snapshot, 0)
+	for {
+		result, err := svc.dataCli.Global.DiskSnapshot.List(kt, listReq)
+		if err != nil {
+			logs.Errorf("list disk snapshot failed, err: %v, account: %s, region: %s, rid: %s", err, accountID,
+				region, kt.Rid)
+			return nil, err
+		}
+		snapshots = append(snapshots, result.Details...)
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return snapshots, nil
+}
@@ -0,0 +1,186 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disk
+
+import (
+	syncaws "hcm/cmd/hc-service/logics/res-sync/aws"
+	syncazure "hcm/cmd/hc-service/logics/res-sync/azure"
+	syncgcp "hcm/cmd/hc-service/logics/res-sync/gcp"
+	synchuawei "hcm/cmd/hc-service/logics/res-sync/huawei"
+	synctcloud "hcm/cmd/hc-service/logics/res-sync/tcloud"
+	"hcm/pkg/adaptor/types/disk"
+	"hcm/pkg/api/core"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	proto "hcm/pkg/api/hc-service/disk"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// ResizeDisk 扩容云硬盘，扩容完成后同步云硬盘信息。扩容后的大小需大于当前大小
+func (svc *service) ResizeDisk(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.PathParameter("vendor").String())
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(proto.DiskResizeReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("id", req.DiskID), tools.RuleEqual("vendor", vendor)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.DataCli.Global.ListDisk(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list disk failed, err: %v, id: %s, rid: %s", err, req.DiskID, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "%s disk %s not found", vendor, req.DiskID)
+	}
+
+	diskInfo := result.Details[0]
+	if req.DiskSize <= diskInfo.DiskSize {
+		return nil, errf.Newf(errf.InvalidParameter, "disk_size should be greater than current size %d",
+			diskInfo.DiskSize)
+	}
+
+	switch vendor {
+	case enumor.TCloud:
+		err = svc.resizeTCloudDisk(cts.Kit, diskInfo, req.DiskSize)
+	case enumor.Aws:
+		err = svc.resizeAwsDisk(cts.Kit, diskInfo, req.DiskSize)
+	case enumor.HuaWei:
+		err = svc.resizeHuaWeiDisk(cts.Kit, diskInfo, req.DiskSize)
+	case enumor.Azure:
+		err = svc.resizeAzureDisk(cts.Kit, diskInfo, req.DiskSize)
+	case enumor.Gcp:
+		err = svc.resizeGcpDisk(cts.Kit, diskInfo, req.DiskSize)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "%s does not support resize disk", vendor)
+	}
+	if err != nil {
+		logs.Errorf("resize %s disk failed, err: %v, id: %s, size: %d, rid: %s", vendor, err, req.DiskID,
+			req.DiskSize, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (svc *service) resizeTCloudDisk(kt *kit.Kit, diskInfo *coredisk.BaseDisk, size uint64) error {
+	client, err := svc.Adaptor.TCloud(kt, diskInfo.AccountID)
+	if err != nil {
+		return err
+	}
+
+	opt := &disk.TCloudDiskResizeOption{Region: diskInfo.Region, CloudDiskID: diskInfo.CloudID, DiskSize: size}
+	if err = client.ResizeDisk(kt, opt); err != nil {
+		return err
+	}
+
+	params := &synctcloud.SyncBaseParams{AccountID: diskInfo.AccountID, Region: diskInfo.Region,
+		CloudIDs: []string{diskInfo.CloudID}}
+	_, err = synctcloud.NewClient(svc.DataCli, client).Disk(kt, params, &synctcloud.SyncDiskOption{})
+	return err
+}
+
+func (svc *service) resizeAwsDisk(kt *kit.Kit, diskInfo *coredisk.BaseDisk, size uint64) error {
+	client, err := svc.Adaptor.Aws(kt, diskInfo.AccountID)
+	if err != nil {
+		return err
+	}
+
+	opt := &disk.AwsDiskResizeOption{Region: diskInfo.Region, CloudDiskID: diskInfo.CloudID, DiskSize: int64(size)}
+	if err = client.ResizeDisk(kt, opt); err != nil {
+		return err
+	}
+
+	params := &syncaws.SyncBaseParams{AccountID: diskInfo.AccountID, Region: diskInfo.Region,
+		CloudIDs: []string{diskInfo.CloudID}}
+	_, err = syncaws.NewClient(svc.DataCli, client).Disk(kt, params, &syncaws.SyncDiskOption{})
+	return err
+}
+
+func (svc *service) resizeHuaWeiDisk(kt *kit.Kit, diskInfo *coredisk.BaseDisk, size uint64) error {
+	client, err := svc.Adaptor.HuaWei(kt, diskInfo.AccountID)
+	if err != nil {
+		return err
+	}
+
+	opt := &disk.HuaWeiDiskResizeOption{Region: diskInfo.Region, CloudDiskID: diskInfo.CloudID,
+		DiskSize: int32(size)}
+	if err = client.ResizeDisk(kt, opt); err != nil {
+		return err
+	}
+
+	params := &synchuawei.SyncBaseParams{AccountID: diskInfo.AccountID, Region: diskInfo.Region,
+		CloudIDs: []string{diskInfo.CloudID}}
+	_, err = synchuawei.NewClient(svc.DataCli, client).Disk(kt, params, &synchuawei.SyncDiskOption{})
+	return err
+}
+
+func (svc *service) resizeAzureDisk(kt *kit.Kit, diskInfo *coredisk.BaseDisk, size uint64) error {
+	diskData, err := svc.DataCli.Azure.RetrieveDisk(kt.Ctx, kt.Header(), diskInfo.ID)
+	if err != nil {
+		return err
+	}
+
+	client, err := svc.Adaptor.Azure(kt, diskInfo.AccountID)
+	if err != nil {
+		return err
+	}
+
+	opt := &disk.AzureDiskResizeOption{ResourceGroupName: diskData.Extension.ResourceGroupName,
+		DiskName: diskInfo.Name, DiskSize: int32(size)}
+	if err = client.ResizeDisk(kt, opt); err != nil {
+		return err
+	}
+
+	params := &syncazure.SyncBaseParams{AccountID: diskInfo.AccountID, ResourceGroupName: opt.ResourceGroupName,
+		CloudIDs: []string{diskInfo.CloudID}}
+	_, err = syncazure.NewClient(svc.DataCli, client).Disk(kt, params, &syncazure.SyncDiskOption{})
+	return err
+}
+
+func (svc *service) resizeGcpDisk(kt *kit.Kit, diskInfo *coredisk.BaseDisk, size uint64) error {
+	client, err := svc.Adaptor.Gcp(kt, diskInfo.AccountID)
+	if err != nil {
+		return err
+	}
+
+	opt := &disk.GcpDiskResizeOption{Zone: diskInfo.Zone, DiskName: diskInfo.Name, DiskSize: int64(size)}
+	if err = client.ResizeDisk(kt, opt); err != nil {
+		return err
+	}
+
+	params := &syncgcp.SyncBaseParams{AccountID: diskInfo.AccountID, CloudIDs: []string{diskInfo.CloudID}}
+	_, err = syncgcp.NewClient(svc.DataCli, client).Disk(kt, params, &syncgcp.SyncDiskOption{Zone: diskInfo.Zone})
+	return err
+}
@@ -65,6 +65,9 @@ func InitDiskService(cap *capability.Capability) {
 	h.Add("DetachHuaWeiDisk", http.MethodPost, "/vendors/huawei/disks/detach", d.DetachHuaWeiDisk)
 	h.Add("DetachAwsDisk", http.MethodPost, "/vendors/aws/disks/detach", d.DetachAwsDisk)
 
+	// 扩容云盘
+	h.Add("ResizeDisk", http.MethodPost, "/vendors/{vendor}/disks/resize", d.ResizeDisk)
+
 	// 询价
 	h.Add("InquiryPriceTCloudDisk", http.MethodPost, "/vendors/tcloud/disks/prices/inquiry", d.InquiryPriceTCloudDisk)
 	h.Add("InquiryPriceHuaWeiDisk", http.MethodPost, "/vendors/huawei/disks/prices/inquiry", d.InquiryPriceHuaWeiDisk)
@@ -0,0 +1,65 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package image
+
+import (
+	typeimage "hcm/pkg/adaptor/types/image"
+	proto "hcm/pkg/api/hc-service/image"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// CreateAzurePrivateImage 基于微软云主机创建托管镜像，镜像的业务与源主机一致。
+// 源主机需已在系统内通用化并解除分配，否则微软云会拒绝创建
+func (svc *imageSvc) CreateAzurePrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.PrivateImageCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	cvm, err := svc.getCvmBasicInfo(cts.Kit, enumor.Azure, req.AccountID, req.CvmID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := svc.ad.Azure(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typeimage.AzureImageCreateOption{
+		Region:     cvm.Region,
+		CloudCvmID: cvm.CloudID,
+		ImageName:  req.ImageName,
+	}
+	cloudImageID, err := client.CreateImage(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("request adaptor to create azure image failed, err: %v, opt: %+v, rid: %s", err, opt,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return svc.createPrivateImage(cts.Kit, enumor.Azure, req, cvm, cloudImageID)
+}
@@ -0,0 +1,141 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package image
+
+import (
+	typeimage "hcm/pkg/adaptor/types/image"
+	proto "hcm/pkg/api/hc-service/image"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+)
+
+// CreateGcpPrivateImage 基于谷歌云主机的系统盘创建私有镜像，镜像的业务与源主机一致。
+// gcp镜像为全局资源，记录的地域为源主机所在地域
+func (svc *imageSvc) CreateGcpPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.PrivateImageCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	cvm, err := svc.getCvmBasicInfo(cts.Kit, enumor.Gcp, req.AccountID, req.CvmID)
+	if err != nil {
+		return nil, err
+	}
+
+	cvmFromDB, err := svc.dataCli.Gcp.Cvm.GetCvm(cts.Kit.Ctx, cts.Kit.Header(), req.CvmID)
+	if err != nil {
+		logs.Errorf("get gcp cvm failed, err: %v, id: %s, rid: %s", err, req.CvmID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	bootDiskSelfLink := ""
+	if cvmFromDB.Extension != nil {
+		for _, one := range cvmFromDB.Extension.Disks {
+			if one.Boot {
+				bootDiskSelfLink = one.SelfLink
+				break
+			}
+		}
+	}
+	if len(bootDiskSelfLink) == 0 {
+		return nil, errf.Newf(errf.InvalidParameter, "gcp cvm %s has no boot disk", req.CvmID)
+	}
+
+	client, err := svc.ad.Gcp(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typeimage.GcpImageCreateOption{
+		CloudBootDiskSelfLink: bootDiskSelfLink,
+		ImageName:             req.ImageName,
+	}
+	cloudImageID, err := client.CreateImage(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("request adaptor to create gcp image failed, err: %v, opt: %+v, rid: %s", err, opt,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return svc.createPrivateImage(cts.Kit, enumor.Gcp, req, cvm, cloudImageID)
+}
+
+// ShareGcpPrivateImage 将谷歌云私有镜像共享给其他谷歌云账号所使用的服务账号
+func (svc *imageSvc) ShareGcpPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.PrivateImageShareReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	img, err := svc.getPrivateImage(cts.Kit, enumor.Gcp, req.AccountID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	shareAccountIDs := slice.Unique(req.ShareAccountIDs)
+	emails := make([]string, 0, len(shareAccountIDs))
+	for _, accountID := range shareAccountIDs {
+		if accountID == req.AccountID {
+			return nil, errf.Newf(errf.InvalidParameter, "can not share image to its own account %s", accountID)
+		}
+
+		account, err := svc.dataCli.Gcp.Account.Get(cts.Kit.Ctx, cts.Kit.Header(), accountID)
+		if err != nil {
+			logs.Errorf("get gcp account failed, err: %v, id: %s, rid: %s", err, accountID, cts.Kit.Rid)
+			return nil, err
+		}
+
+		if account.Vendor != enumor.Gcp || account.Extension == nil || len(account.Extension.Email) == 0 {
+			return nil, errf.Newf(errf.InvalidParameter, "account %s is not gcp account with service account",
+				accountID)
+		}
+		emails = append(emails, account.Extension.Email)
+	}
+
+	client, err := svc.ad.Gcp(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typeimage.GcpImageShareOption{
+		ImageName:            img.Name,
+		ServiceAccountEmails: slice.Unique(emails),
+	}
+	if err = client.ShareImage(cts.Kit, opt); err != nil {
+		logs.Errorf("request adaptor to share gcp image failed, err: %v, opt: %+v, rid: %s", err, opt,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	if err = svc.addSharedAccounts(cts.Kit, img, shareAccountIDs); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
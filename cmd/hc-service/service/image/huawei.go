@@ -0,0 +1,192 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package image
+
+import (
+	typeimage "hcm/pkg/adaptor/types/image"
+	proto "hcm/pkg/api/hc-service/image"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/maps"
+	"hcm/pkg/tools/slice"
+)
+
+// CreateHuaWeiPrivateImage 基于华为云主机创建私有镜像，镜像的业务与源主机一致
+func (svc *imageSvc) CreateHuaWeiPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.PrivateImageCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	cvm, err := svc.getCvmBasicInfo(cts.Kit, enumor.HuaWei, req.AccountID, req.CvmID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := svc.ad.HuaWei(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typeimage.HuaWeiImageCreateOption{
+		Region:     cvm.Region,
+		CloudCvmID: cvm.CloudID,
+		ImageName:  req.ImageName,
+	}
+	cloudImageID, err := client.CreateImage(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("request adaptor to create huawei image failed, err: %v, opt: %+v, rid: %s", err, opt,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return svc.createPrivateImage(cts.Kit, enumor.HuaWei, req, cvm, cloudImageID)
+}
+
+// CopyHuaWeiPrivateImage 将华为云私有镜像复制到其他地域
+func (svc *imageSvc) CopyHuaWeiPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.PrivateImageCopyReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	img, err := svc.getPrivateImage(cts.Kit, enumor.HuaWei, req.AccountID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := svc.ad.HuaWei(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	imageName := req.ImageName
+	if len(imageName) == 0 {
+		imageName = img.Name
+	}
+	opt := &typeimage.HuaWeiImageCopyOption{
+		Region:       img.Region,
+		CloudImageID: img.CloudID,
+		DstRegions:   req.DstRegions,
+		ImageName:    imageName,
+	}
+	copied, err := client.CopyImage(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("request adaptor to copy huawei image failed, err: %v, opt: %+v, rid: %s", err, opt,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return svc.createCopiedImages(cts.Kit, img, imageName, copied)
+}
+
+// ShareHuaWeiPrivateImage 将华为云私有镜像共享给其他华为云账号在镜像所在地域的项目，并由接收方账号接受共享
+func (svc *imageSvc) ShareHuaWeiPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.PrivateImageShareReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	img, err := svc.getPrivateImage(cts.Kit, enumor.HuaWei, req.AccountID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	shareAccountIDs := slice.Unique(req.ShareAccountIDs)
+	projectIDs := make(map[string]string, len(shareAccountIDs))
+	for _, accountID := range shareAccountIDs {
+		if accountID == req.AccountID {
+			return nil, errf.Newf(errf.InvalidParameter, "can not share image to its own account %s", accountID)
+		}
+
+		account, err := svc.dataCli.HuaWei.Account.Get(cts.Kit.Ctx, cts.Kit.Header(), accountID)
+		if err != nil {
+			logs.Errorf("get huawei account failed, err: %v, id: %s, rid: %s", err, accountID, cts.Kit.Rid)
+			return nil, err
+		}
+		if account.Vendor != enumor.HuaWei {
+			return nil, errf.Newf(errf.InvalidParameter, "account %s is not huawei account", accountID)
+		}
+
+		dstClient, err := svc.ad.HuaWei(cts.Kit, accountID)
+		if err != nil {
+			return nil, err
+		}
+
+		projectID, err := dstClient.GetProjectID(cts.Kit, img.Region)
+		if err != nil {
+			logs.Errorf("get huawei account project id failed, err: %v, account: %s, region: %s, rid: %s", err,
+				accountID, img.Region, cts.Kit.Rid)
+			return nil, err
+		}
+		projectIDs[accountID] = projectID
+	}
+
+	client, err := svc.ad.HuaWei(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typeimage.HuaWeiImageShareOption{
+		Region:          img.Region,
+		CloudImageID:    img.CloudID,
+		CloudProjectIDs: slice.Unique(maps.Values(projectIDs)),
+	}
+	if err = client.ShareImage(cts.Kit, opt); err != nil {
+		logs.Errorf("request adaptor to share huawei image failed, err: %v, opt: %+v, rid: %s", err, opt,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	for accountID, projectID := range projectIDs {
+		dstClient, err := svc.ad.HuaWei(cts.Kit, accountID)
+		if err != nil {
+			return nil, err
+		}
+
+		acceptOpt := &typeimage.HuaWeiImageAcceptOption{
+			Region:         img.Region,
+			CloudImageID:   img.CloudID,
+			CloudProjectID: projectID,
+		}
+		if err = dstClient.AcceptSharedImage(cts.Kit, acceptOpt); err != nil {
+			logs.Errorf("request adaptor to accept huawei shared image failed, err: %v, account: %s, opt: %+v, "+
+				"rid: %s", err, accountID, acceptOpt, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	if err = svc.addSharedAccounts(cts.Kit, img, shareAccountIDs); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,237 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package image ...
+package image
+
+import (
+	"net/http"
+
+	cloudadaptor "hcm/cmd/hc-service/logics/cloud-adaptor"
+	"hcm/cmd/hc-service/service/capability"
+	typeimage "hcm/pkg/adaptor/types/image"
+	"hcm/pkg/api/core"
+	coreimage "hcm/pkg/api/core/cloud/image"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	dataimage "hcm/pkg/api/data-service/cloud/image"
+	proto "hcm/pkg/api/hc-service/image"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+)
+
+// InitService initial the private image service
+func InitService(cap *capability.Capability) {
+	svc := &imageSvc{
+		ad:      cap.CloudAdaptor,
+		dataCli: cap.ClientSet.DataService(),
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CreateTCloudPrivateImage", http.MethodPost, "/vendors/tcloud/private_images/create",
+		svc.CreateTCloudPrivateImage)
+	h.Add("CopyTCloudPrivateImage", http.MethodPost, "/vendors/tcloud/private_images/copy",
+		svc.CopyTCloudPrivateImage)
+	h.Add("ShareTCloudPrivateImage", http.MethodPost, "/vendors/tcloud/private_images/share",
+		svc.ShareTCloudPrivateImage)
+	h.Add("SyncTCloudPrivateImage", http.MethodPost, "/vendors/tcloud/private_images/sync",
+		svc.SyncTCloudPrivateImage)
+
+	h.Add("CreateHuaWeiPrivateImage", http.MethodPost, "/vendors/huawei/private_images/create",
+		svc.CreateHuaWeiPrivateImage)
+	h.Add("CopyHuaWeiPrivateImage", http.MethodPost, "/vendors/huawei/private_images/copy",
+		svc.CopyHuaWeiPrivateImage)
+	h.Add("ShareHuaWeiPrivateImage", http.MethodPost, "/vendors/huawei/private_images/share",
+		svc.ShareHuaWeiPrivateImage)
+	h.Add("SyncHuaWeiPrivateImage", http.MethodPost, "/vendors/huawei/private_images/sync",
+		svc.SyncHuaWeiPrivateImage)
+
+	h.Add("CreateAwsPrivateImage", http.MethodPost, "/vendors/aws/private_images/create",
+		svc.CreateAwsPrivateImage)
+	h.Add("CopyAwsPrivateImage", http.MethodPost, "/vendors/aws/private_images/copy",
+		svc.CopyAwsPrivateImage)
+	h.Add("ShareAwsPrivateImage", http.MethodPost, "/vendors/aws/private_images/share",
+		svc.ShareAwsPrivateImage)
+	h.Add("SyncAwsPrivateImage", http.MethodPost, "/vendors/aws/private_images/sync",
+		svc.SyncAwsPrivateImage)
+
+	h.Add("CreateGcpPrivateImage", http.MethodPost, "/vendors/gcp/private_images/create",
+		svc.CreateGcpPrivateImage)
+	h.Add("ShareGcpPrivateImage", http.MethodPost, "/vendors/gcp/private_images/share",
+		svc.ShareGcpPrivateImage)
+	h.Add("SyncGcpPrivateImage", http.MethodPost, "/vendors/gcp/private_images/sync",
+		svc.SyncGcpPrivateImage)
+
+	h.Add("CreateAzurePrivateImage", http.MethodPost, "/vendors/azure/private_images/create",
+		svc.CreateAzurePrivateImage)
+	h.Add("SyncAzurePrivateImage", http.MethodPost, "/vendors/azure/private_images/sync",
+		svc.SyncAzurePrivateImage)
+
+	h.Load(cap.WebService)
+}
+
+type imageSvc struct {
+	ad      *cloudadaptor.CloudAdaptorClient
+	dataCli *dataservice.Client
+}
+
+// getCvmBasicInfo 查询创建镜像的源主机，并校验主机属于该账号
+func (svc *imageSvc) getCvmBasicInfo(kt *kit.Kit, vendor enumor.Vendor, accountID, cvmID string) (
+	*types.CloudResourceBasicInfo, error) {
+
+	basicReq := protocloud.ListResourceBasicInfoReq{
+		ResourceType: enumor.CvmCloudResType,
+		IDs:          []string{cvmID},
+		Fields:       append([]string{"cloud_id", "region"}, types.CommonBasicInfoFields...),
+	}
+	infos, err := svc.dataCli.Global.Cloud.ListResBasicInfo(kt, basicReq)
+	if err != nil {
+		logs.Errorf("list cvm basic info failed, err: %v, id: %s, rid: %s", err, cvmID, kt.Rid)
+		return nil, err
+	}
+
+	info, exists := infos[cvmID]
+	if !exists {
+		return nil, errf.Newf(errf.RecordNotFound, "cvm %s not found", cvmID)
+	}
+
+	if info.Vendor != vendor || info.AccountID != accountID {
+		return nil, errf.Newf(errf.InvalidParameter, "cvm %s does not belong to %s account %s", cvmID, vendor,
+			accountID)
+	}
+
+	return &info, nil
+}
+
+// getPrivateImage 查询私有镜像，并校验镜像属于该账号且状态正常
+func (svc *imageSvc) getPrivateImage(kt *kit.Kit, vendor enumor.Vendor, accountID, id string) (
+	*coreimage.PrivateImage, error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dataCli.Global.PrivateImage.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list private image failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "private image %s not found", id)
+	}
+
+	img := result.Details[0]
+	if img.Vendor != vendor || img.AccountID != accountID {
+		return nil, errf.Newf(errf.InvalidParameter, "private image %s does not belong to %s account %s", id,
+			vendor, accountID)
+	}
+
+	if img.Status != enumor.PrivateImageNormal {
+		return nil, errf.Newf(errf.InvalidParameter, "private image %s status is %s, not %s", id, img.Status,
+			enumor.PrivateImageNormal)
+	}
+
+	return img, nil
+}
+
+// createPrivateImage 记录基于主机创建的私有镜像，镜像的业务与源主机一致
+func (svc *imageSvc) createPrivateImage(kt *kit.Kit, vendor enumor.Vendor, req *proto.PrivateImageCreateReq,
+	cvm *types.CloudResourceBasicInfo, cloudImageID string) (*core.CreateResult, error) {
+
+	createReq := &dataimage.PrivateImageBatchCreateReq{
+		Images: []dataimage.PrivateImageCreate{{
+			Vendor:           vendor,
+			AccountID:        req.AccountID,
+			BkBizID:          cvm.BkBizID,
+			Region:           cvm.Region,
+			CloudID:          cloudImageID,
+			Name:             req.ImageName,
+			SourceCvmID:      cvm.ID,
+			SourceCloudCvmID: cvm.CloudID,
+			Status:           enumor.PrivateImageNormal,
+			Memo:             req.Memo,
+		}},
+	}
+	result, err := svc.dataCli.Global.PrivateImage.BatchCreate(kt, createReq)
+	if err != nil {
+		logs.Errorf("create %s private image failed, err: %v, cloud id: %s, rid: %s", vendor, err, cloudImageID,
+			kt.Rid)
+		return nil, err
+	}
+
+	return &core.CreateResult{ID: result.IDs[0]}, nil
+}
+
+// createCopiedImages 记录复制到其他地域的私有镜像，复制的镜像继承源镜像的业务及源主机信息
+func (svc *imageSvc) createCopiedImages(kt *kit.Kit, src *coreimage.PrivateImage, imageName string,
+	copied []typeimage.CopiedImage) (*core.BatchCreateResult, error) {
+
+	if len(imageName) == 0 {
+		imageName = src.Name
+	}
+
+	createReq := &dataimage.PrivateImageBatchCreateReq{
+		Images: make([]dataimage.PrivateImageCreate, 0, len(copied)),
+	}
+	for _, one := range copied {
+		createReq.Images = append(createReq.Images, dataimage.PrivateImageCreate{
+			Vendor:           src.Vendor,
+			AccountID:        src.AccountID,
+			BkBizID:          src.BkBizID,
+			Region:           one.Region,
+			CloudID:          one.CloudImageID,
+			Name:             imageName,
+			SourceCvmID:      src.SourceCvmID,
+			SourceCloudCvmID: src.SourceCloudCvmID,
+			SourceImageID:    src.ID,
+			Status:           enumor.PrivateImageNormal,
+			Memo:             src.Memo,
+		})
+	}
+
+	result, err := svc.dataCli.Global.PrivateImage.BatchCreate(kt, createReq)
+	if err != nil {
+		logs.Errorf("create copied private image failed, err: %v, source: %s, rid: %s", err, src.ID, kt.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// addSharedAccounts 记录私有镜像已共享的账号
+func (svc *imageSvc) addSharedAccounts(kt *kit.Kit, img *coreimage.PrivateImage, accountIDs []string) error {
+	shared := slice.Unique(append(img.SharedAccountIDs, accountIDs...))
+	updateReq := &dataimage.PrivateImageBatchUpdateReq{
+		Images: []dataimage.PrivateImageUpdate{{ID: img.ID, SharedAccountIDs: &shared}},
+	}
+	if err := svc.dataCli.Global.PrivateImage.BatchUpdate(kt, updateReq); err != nil {
+		logs.Errorf("update private image shared accounts failed, err: %v, id: %s, rid: %s", err, img.ID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
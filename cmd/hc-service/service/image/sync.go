@@ -0,0 +1,254 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package image
+
+import (
+	typeimage "hcm/pkg/adaptor/types/image"
+	"hcm/pkg/api/core"
+	coreimage "hcm/pkg/api/core/cloud/image"
+	dataservice "hcm/pkg/api/data-service"
+	dataimage "hcm/pkg/api/data-service/cloud/image"
+	proto "hcm/pkg/api/hc-service/image"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+)
+
+// listImageStateFunc 查询账号在地域下的全部自定义镜像及其云上状态
+type listImageStateFunc func(kt *kit.Kit, region string) ([]typeimage.PrivateImageState, error)
+
+// SyncTCloudPrivateImage 同步腾讯云私有镜像的云上状态
+func (svc *imageSvc) SyncTCloudPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.PrivateImageSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := svc.ad.TCloud(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	listState := func(kt *kit.Kit, region string) ([]typeimage.PrivateImageState, error) {
+		return client.ListPrivateImageState(kt, &typeimage.PrivateImageStateListOption{Region: region})
+	}
+	return nil, svc.syncPrivateImages(cts.Kit, enumor.TCloud, req.AccountID, listState)
+}
+
+// SyncHuaWeiPrivateImage 同步华为云私有镜像的云上状态
+func (svc *imageSvc) SyncHuaWeiPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.PrivateImageSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := svc.ad.HuaWei(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	listState := func(kt *kit.Kit, region string) ([]typeimage.PrivateImageState, error) {
+		return client.ListPrivateImageState(kt, &typeimage.PrivateImageStateListOption{Region: region})
+	}
+	return nil, svc.syncPrivateImages(cts.Kit, enumor.HuaWei, req.AccountID, listState)
+}
+
+// SyncAwsPrivateImage 同步亚马逊云私有镜像的云上状态
+func (svc *imageSvc) SyncAwsPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.PrivateImageSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := svc.ad.Aws(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	listState := func(kt *kit.Kit, region string) ([]typeimage.PrivateImageState, error) {
+		return client.ListPrivateImageState(kt, &typeimage.PrivateImageStateListOption{Region: region})
+	}
+	return nil, svc.syncPrivateImages(cts.Kit, enumor.Aws, req.AccountID, listState)
+}
+
+// SyncGcpPrivateImage 同步谷歌云私有镜像的云上状态，gcp镜像为全局资源，各地域均按项目下的全部镜像对比
+func (svc *imageSvc) SyncGcpPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.PrivateImageSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := svc.ad.Gcp(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	listState := func(kt *kit.Kit, region string) ([]typeimage.PrivateImageState, error) {
+		return client.ListPrivateImageState(kt, &typeimage.PrivateImageStateListOption{Region: region})
+	}
+	return nil, svc.syncPrivateImages(cts.Kit, enumor.Gcp, req.AccountID, listState)
+}
+
+// SyncAzurePrivateImage 同步微软云私有镜像的云上状态
+func (svc *imageSvc) SyncAzurePrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.PrivateImageSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := svc.ad.Azure(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	listState := func(kt *kit.Kit, region string) ([]typeimage.PrivateImageState, error) {
+		return client.ListPrivateImageState(kt, &typeimage.PrivateImageStateListOption{Region: region})
+	}
+	return nil, svc.syncPrivateImages(cts.Kit, enumor.Azure, req.AccountID, listState)
+}
+
+// syncPrivateImages 按地域对比hcm记录与云上镜像，更新状态变化的镜像，并删除云上已不存在的镜像
+func (svc *imageSvc) syncPrivateImages(kt *kit.Kit, vendor enumor.Vendor, accountID string,
+	listState listImageStateFunc) error {
+
+	images, err := svc.listAccountPrivateImages(kt, vendor, accountID)
+	if err != nil {
+		return err
+	}
+
+	regionImages := make(map[string][]coreimage.PrivateImage)
+	for _, one := range images {
+		regionImages[one.Region] = append(regionImages[one.Region], one)
+	}
+
+	updates := make([]dataimage.PrivateImageUpdate, 0)
+	delIDs := make([]string, 0)
+	for region, list := range regionImages {
+		states, err := listState(kt, region)
+		if err != nil {
+			logs.Errorf("list %s private image state failed, err: %v, account: %s, region: %s, rid: %s", vendor,
+				err, accountID, region, kt.Rid)
+			return err
+		}
+
+		regionUpdates, regionDelIDs := diffPrivateImageStatus(vendor, list, states)
+		updates = append(updates, regionUpdates...)
+		delIDs = append(delIDs, regionDelIDs...)
+	}
+
+	for _, batch := range slice.Split(updates, constant.BatchOperationMaxLimit) {
+		updateReq := &dataimage.PrivateImageBatchUpdateReq{Images: batch}
+		if err = svc.dataCli.Global.PrivateImage.BatchUpdate(kt, updateReq); err != nil {
+			logs.Errorf("update private image status failed, err: %v, account: %s, rid: %s", err, accountID,
+				kt.Rid)
+			return err
+		}
+	}
+
+	for _, batch := range slice.Split(delIDs, constant.BatchOperationMaxLimit) {
+		delReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", batch)}
+		if err = svc.dataCli.Global.PrivateImage.BatchDelete(kt, delReq); err != nil {
+			logs.Errorf("delete private image failed, err: %v, ids: %v, rid: %s", err, batch, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listAccountPrivateImages 查询账号下的全部私有镜像记录
+func (svc *imageSvc) listAccountPrivateImages(kt *kit.Kit, vendor enumor.Vendor, accountID string) (
+	[]coreimage.PrivateImage, error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", vendor),
+			tools.RuleEqual("account_id", accountID),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	images := make([]coreimage.PrivateImage, 0)
+	for {
+		result, err := svc.dataCli.Global.PrivateImage.List(kt, listReq)
+		if err != nil {
+			logs.Errorf("list private image failed, err: %v, account: %s, rid: %s", err, accountID, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range result.Details {
+			images = append(images, *one)
+		}
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return images, nil
+}
+
+// diffPrivateImageStatus 对比同一地域的镜像记录与云上镜像，返回需要更新状态的镜像及云上已不存在需要删除的镜像ID
+func diffPrivateImageStatus(vendor enumor.Vendor, images []coreimage.PrivateImage,
+	states []typeimage.PrivateImageState) ([]dataimage.PrivateImageUpdate, []string) {
+
+	cloudStatus := make(map[string]enumor.PrivateImageStatus, len(states))
+	for _, one := range states {
+		cloudStatus[one.CloudImageID] = coreimage.NormalizePrivateImageStatus(vendor, one.State)
+	}
+
+	updates := make([]dataimage.PrivateImageUpdate, 0)
+	delIDs := make([]string, 0)
+	for _, one := range images {
+		status, exists := cloudStatus[one.CloudID]
+		if !exists {
+			delIDs = append(delIDs, one.ID)
+			continue
+		}
+
+		if status == one.Status {
+			continue
+		}
+
+		updates = append(updates, dataimage.PrivateImageUpdate{ID: one.ID, Status: &status})
+	}
+
+	return updates, delIDs
+}
@@ -0,0 +1,81 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package image
+
+import (
+	"reflect"
+	"testing"
+
+	typeimage "hcm/pkg/adaptor/types/image"
+	coreimage "hcm/pkg/api/core/cloud/image"
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestDiffPrivateImageStatus(t *testing.T) {
+	images := []coreimage.PrivateImage{
+		{ID: "1", CloudID: "img-1", Status: enumor.PrivateImageNormal},
+		{ID: "2", CloudID: "img-2", Status: enumor.PrivateImageNormal},
+		{ID: "3", CloudID: "img-3", Status: enumor.PrivateImageCreating},
+		{ID: "4", CloudID: "img-4", Status: enumor.PrivateImageNormal},
+	}
+	states := []typeimage.PrivateImageState{
+		{CloudImageID: "img-1", State: "NORMAL"},
+		{CloudImageID: "img-2", State: "CREATEFAILED"},
+		{CloudImageID: "img-3", State: "USING"},
+		// 云上存在但未被hcm管理的镜像不做处理
+		{CloudImageID: "img-5", State: "NORMAL"},
+	}
+
+	updates, delIDs := diffPrivateImageStatus(enumor.TCloud, images, states)
+
+	gotStatus := make(map[string]enumor.PrivateImageStatus, len(updates))
+	for _, one := range updates {
+		if one.Status == nil {
+			t.Fatalf("update of image %s has no status", one.ID)
+		}
+		gotStatus[one.ID] = *one.Status
+	}
+	expectStatus := map[string]enumor.PrivateImageStatus{
+		"2": enumor.PrivateImageFailed,
+		"3": enumor.PrivateImageNormal,
+	}
+	if !reflect.DeepEqual(gotStatus, expectStatus) {
+		t.Errorf("expect status updates %v, got: %v", expectStatus, gotStatus)
+	}
+
+	if !reflect.DeepEqual(delIDs, []string{"4"}) {
+		t.Errorf("expect deleted ids [4], got: %v", delIDs)
+	}
+}
+
+func TestDiffPrivateImageStatusEmptyCloud(t *testing.T) {
+	images := []coreimage.PrivateImage{
+		{ID: "1", CloudID: "img-1", Status: enumor.PrivateImageNormal},
+		{ID: "2", CloudID: "img-2", Status: enumor.PrivateImageFailed},
+	}
+
+	updates, delIDs := diffPrivateImageStatus(enumor.HuaWei, images, nil)
+	if len(updates) != 0 {
+		t.Errorf("expect no status updates, got: %v", updates)
+	}
+	if !reflect.DeepEqual(delIDs, []string{"1", "2"}) {
+		t.Errorf("expect deleted ids [1 2], got: %v", delIDs)
+	}
+}
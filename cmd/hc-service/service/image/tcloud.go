@@ -0,0 +1,159 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package image
+
+import (
+	typeimage "hcm/pkg/adaptor/types/image"
+	proto "hcm/pkg/api/hc-service/image"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+)
+
+// CreateTCloudPrivateImage 基于腾讯云主机创建私有镜像，镜像的业务与源主机一致
+func (svc *imageSvc) CreateTCloudPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.PrivateImageCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	cvm, err := svc.getCvmBasicInfo(cts.Kit, enumor.TCloud, req.AccountID, req.CvmID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := svc.ad.TCloud(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typeimage.TCloudImageCreateOption{
+		Region:        cvm.Region,
+		CloudCvmID:    cvm.CloudID,
+		ImageName:     req.ImageName,
+		ForcePoweroff: req.ForcePoweroff,
+	}
+	cloudImageID, err := client.CreateImage(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("request adaptor to create tcloud image failed, err: %v, opt: %+v, rid: %s", err, opt,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return svc.createPrivateImage(cts.Kit, enumor.TCloud, req, cvm, cloudImageID)
+}
+
+// CopyTCloudPrivateImage 将腾讯云私有镜像复制到其他地域
+func (svc *imageSvc) CopyTCloudPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.PrivateImageCopyReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	img, err := svc.getPrivateImage(cts.Kit, enumor.TCloud, req.AccountID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := svc.ad.TCloud(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typeimage.TCloudImageCopyOption{
+		Region:       img.Region,
+		CloudImageID: img.CloudID,
+		DstRegions:   req.DstRegions,
+		ImageName:    req.ImageName,
+	}
+	copied, err := client.CopyImage(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("request adaptor to copy tcloud image failed, err: %v, opt: %+v, rid: %s", err, opt,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return svc.createCopiedImages(cts.Kit, img, req.ImageName, copied)
+}
+
+// ShareTCloudPrivateImage 将腾讯云私有镜像共享给其他腾讯云账号所属的主账号
+func (svc *imageSvc) ShareTCloudPrivateImage(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.PrivateImageShareReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	img, err := svc.getPrivateImage(cts.Kit, enumor.TCloud, req.AccountID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	shareAccountIDs := slice.Unique(req.ShareAccountIDs)
+	cloudMainAccountIDs := make([]string, 0, len(shareAccountIDs))
+	for _, accountID := range shareAccountIDs {
+		if accountID == req.AccountID {
+			return nil, errf.Newf(errf.InvalidParameter, "can not share image to its own account %s", accountID)
+		}
+
+		account, err := svc.dataCli.TCloud.Account.Get(cts.Kit.Ctx, cts.Kit.Header(), accountID)
+		if err != nil {
+			logs.Errorf("get tcloud account failed, err: %v, id: %s, rid: %s", err, accountID, cts.Kit.Rid)
+			return nil, err
+		}
+
+		if account.Vendor != enumor.TCloud || account.Extension == nil {
+			return nil, errf.Newf(errf.InvalidParameter, "account %s is not tcloud account", accountID)
+		}
+		cloudMainAccountIDs = append(cloudMainAccountIDs, account.Extension.CloudMainAccountID)
+	}
+
+	client, err := svc.ad.TCloud(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typeimage.TCloudImageShareOption{
+		Region:              img.Region,
+		CloudImageID:        img.CloudID,
+		CloudMainAccountIDs: slice.Unique(cloudMainAccountIDs),
+	}
+	if err = client.ShareImage(cts.Kit, opt); err != nil {
+		logs.Errorf("request adaptor to share tcloud image failed, err: %v, opt: %+v, rid: %s", err, opt,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	if err = svc.addSharedAccounts(cts.Kit, img, shareAccountIDs); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,55 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package renewal ...
+package renewal
+
+import (
+	"net/http"
+
+	cloudclient "hcm/cmd/hc-service/logics/cloud-adaptor"
+	"hcm/cmd/hc-service/service/capability"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/rest"
+)
+
+// InitService initial the prepaid resource renewal service
+func InitService(cap *capability.Capability) {
+	svc := &service{
+		adaptor: cap.CloudAdaptor,
+		dataCli: cap.ClientSet.DataService(),
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("RenewPrepaidRes", http.MethodPost, "/vendors/{vendor}/prepaid_resources/renew", svc.RenewPrepaidRes)
+	h.Add("SetPrepaidResAutoRenew", http.MethodPost, "/vendors/{vendor}/prepaid_resources/auto_renew/set",
+		svc.SetPrepaidResAutoRenew)
+	h.Add("ListHuaWeiPrepaidExpireTime", http.MethodPost, "/vendors/huawei/prepaid_resources/expire_time/list",
+		svc.ListHuaWeiPrepaidExpireTime)
+	h.Add("ListHuaWeiPrepaidBandwidthPackage", http.MethodPost,
+		"/vendors/huawei/prepaid_resources/bandwidth_packages/list", svc.ListHuaWeiPrepaidBandwidthPackage)
+
+	h.Load(cap.WebService)
+}
+
+type service struct {
+	adaptor *cloudclient.CloudAdaptorClient
+	dataCli *dataservice.Client
+}
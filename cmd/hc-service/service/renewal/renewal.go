@@ -0,0 +1,356 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package renewal
+
+import (
+	"strings"
+
+	synchuawei "hcm/cmd/hc-service/logics/res-sync/huawei"
+	synctcloud "hcm/cmd/hc-service/logics/res-sync/tcloud"
+	typesrenewal "hcm/pkg/adaptor/types/renewal"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	proto "hcm/pkg/api/hc-service/renewal"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// renewOperator 各云 adaptor 的包年包月资源续费操作
+type renewOperator interface {
+	RenewResources(kt *kit.Kit, opt *typesrenewal.RenewResOption) (*typesrenewal.BatchResult, error)
+	SetResourcesAutoRenew(kt *kit.Kit, opt *typesrenewal.SetAutoRenewOption) (*typesrenewal.BatchResult, error)
+}
+
+// RenewPrepaidRes 续费包年包月资源，续费成功后同步资源以更新到期时间
+func (svc *service) RenewPrepaidRes(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(proto.ResRenewReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	infos, err := svc.prepaidResInfos(cts.Kit, vendor, req.AccountID, req.ResType, req.ResIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := svc.renewOperator(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*typesrenewal.BatchResult)
+	for region, cloudIDs := range groupByRegion(infos) {
+		opt := &typesrenewal.RenewResOption{
+			Region:   region,
+			ResType:  req.ResType,
+			CloudIDs: cloudIDs,
+			Period:   req.Period,
+		}
+		result, err := cli.RenewResources(cts.Kit, opt)
+		if err != nil {
+			logs.Errorf("renew %s resources failed, err: %v, opt: %+v, rid: %s", vendor, err, opt, cts.Kit.Rid)
+			result = &typesrenewal.BatchResult{FailedCloudIDs: cloudIDs, FailedMessage: err.Error()}
+		}
+		results[region] = result
+	}
+
+	return svc.handleBatchResult(cts.Kit, vendor, req.AccountID, req.ResType, infos, results)
+}
+
+// SetPrepaidResAutoRenew 开启或关闭包年包月资源的自动续费，设置成功后同步资源以更新续费标识
+func (svc *service) SetPrepaidResAutoRenew(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(proto.ResAutoRenewSetReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	infos, err := svc.prepaidResInfos(cts.Kit, vendor, req.AccountID, req.ResType, req.ResIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := svc.renewOperator(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*typesrenewal.BatchResult)
+	for region, cloudIDs := range groupByRegion(infos) {
+		opt := &typesrenewal.SetAutoRenewOption{
+			Region:    region,
+			ResType:   req.ResType,
+			CloudIDs:  cloudIDs,
+			AutoRenew: req.AutoRenew,
+		}
+		result, err := cli.SetResourcesAutoRenew(cts.Kit, opt)
+		if err != nil {
+			logs.Errorf("set %s resources auto renew failed, err: %v, opt: %+v, rid: %s", vendor, err, opt,
+				cts.Kit.Rid)
+			result = &typesrenewal.BatchResult{FailedCloudIDs: cloudIDs, FailedMessage: err.Error()}
+		}
+		results[region] = result
+	}
+
+	return svc.handleBatchResult(cts.Kit, vendor, req.AccountID, req.ResType, infos, results)
+}
+
+// handleBatchResult 同步操作成功的资源，并将各地域的云上操作结果转换为按资源ID记录的结果
+func (svc *service) handleBatchResult(kt *kit.Kit, vendor enumor.Vendor, accountID string,
+	resType enumor.CloudResourceType, infos map[string]types.CloudResourceBasicInfo,
+	results map[string]*typesrenewal.BatchResult) (*proto.BatchResult, error) {
+
+	cloudToID := make(map[string]string, len(infos))
+	for _, info := range infos {
+		cloudToID[info.CloudID] = info.ID
+	}
+
+	batchResult := &proto.BatchResult{SuccessIDs: make([]string, 0), FailedIDs: make([]string, 0)}
+	failedMessages := make([]string, 0)
+	successRegionInfos := make(map[string][]string)
+	for region, result := range results {
+		for _, cloudID := range result.SuccessCloudIDs {
+			batchResult.SuccessIDs = append(batchResult.SuccessIDs, cloudToID[cloudID])
+		}
+		if len(result.SuccessCloudIDs) != 0 {
+			successRegionInfos[region] = result.SuccessCloudIDs
+		}
+
+		for _, cloudID := range result.FailedCloudIDs {
+			batchResult.FailedIDs = append(batchResult.FailedIDs, cloudToID[cloudID])
+		}
+		if len(result.FailedMessage) != 0 {
+			failedMessages = append(failedMessages, result.FailedMessage)
+		}
+	}
+	batchResult.FailedMessage = strings.Join(failedMessages, "; ")
+
+	// 云上操作已经生效，同步失败不影响操作结果，避免调用方重试导致重复续费，资源在下次定时同步时更新
+	if err := svc.syncResources(kt, vendor, accountID, resType, successRegionInfos); err != nil {
+		logs.Errorf("sync %s %s after renewal operation failed, err: %v, account: %s, rid: %s", vendor, resType,
+			err, accountID, kt.Rid)
+	}
+
+	return batchResult, nil
+}
+
+// ListHuaWeiPrepaidExpireTime 查询华为云包年包月资源的到期时间
+func (svc *service) ListHuaWeiPrepaidExpireTime(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ExpireTimeListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	cli, err := svc.adaptor.HuaWei(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typesrenewal.ListExpireTimeOption{CloudIDs: req.CloudIDs}
+	details, err := cli.ListPrepaidExpireTime(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list huawei prepaid expire time failed, err: %v, opt: %+v, rid: %s", err, opt, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &proto.ExpireTimeListResult{Details: details}, nil
+}
+
+// ListHuaWeiPrepaidBandwidthPackage 查询华为云包年包月的共享带宽
+func (svc *service) ListHuaWeiPrepaidBandwidthPackage(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.BandwidthPackageListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	cli, err := svc.adaptor.HuaWei(cts.Kit, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typesrenewal.ListBandwidthPackageOption{ExpireTimeEnd: req.ExpireTimeEnd}
+	details, err := cli.ListPrepaidBandwidthPackage(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list huawei prepaid bandwidth package failed, err: %v, account: %s, rid: %s", err,
+			req.AccountID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &proto.BandwidthPackageListResult{Details: details}, nil
+}
+
+func (svc *service) renewOperator(kt *kit.Kit, vendor enumor.Vendor, accountID string) (renewOperator, error) {
+	switch vendor {
+	case enumor.TCloud:
+		return svc.adaptor.TCloud(kt, accountID)
+	case enumor.HuaWei:
+		return svc.adaptor.HuaWei(kt, accountID)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "%s does not support prepaid resource renewal", vendor)
+	}
+}
+
+// syncResources 同步续费操作后的资源，更新本地的到期时间及续费标识
+func (svc *service) syncResources(kt *kit.Kit, vendor enumor.Vendor, accountID string,
+	resType enumor.CloudResourceType, regionInfos map[string][]string) error {
+
+	// 带宽包未纳入hcm资源管理，无需同步
+	if resType == enumor.BandwidthPackageCloudResType {
+		return nil
+	}
+
+	for region, cloudIDs := range regionInfos {
+		var err error
+		switch vendor {
+		case enumor.TCloud:
+			err = svc.syncTCloudResources(kt, accountID, region, resType, cloudIDs)
+		case enumor.HuaWei:
+			err = svc.syncHuaWeiResources(kt, accountID, region, resType, cloudIDs)
+		}
+		if err != nil {
+			logs.Errorf("sync %s %s after renewal failed, err: %v, region: %s, ids: %v, rid: %s", vendor, resType,
+				err, region, cloudIDs, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (svc *service) syncTCloudResources(kt *kit.Kit, accountID, region string, resType enumor.CloudResourceType,
+	cloudIDs []string) error {
+
+	cli, err := svc.adaptor.TCloud(kt, accountID)
+	if err != nil {
+		return err
+	}
+
+	syncClient := synctcloud.NewClient(svc.dataCli, cli)
+	params := &synctcloud.SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+	switch resType {
+	case enumor.CvmCloudResType:
+		_, err = syncClient.Cvm(kt, params, new(synctcloud.SyncCvmOption))
+	case enumor.DiskCloudResType:
+		_, err = syncClient.Disk(kt, params, new(synctcloud.SyncDiskOption))
+	}
+	return err
+}
+
+func (svc *service) syncHuaWeiResources(kt *kit.Kit, accountID, region string, resType enumor.CloudResourceType,
+	cloudIDs []string) error {
+
+	cli, err := svc.adaptor.HuaWei(kt, accountID)
+	if err != nil {
+		return err
+	}
+
+	syncClient := synchuawei.NewClient(svc.dataCli, cli)
+	params := &synchuawei.SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+	switch resType {
+	case enumor.CvmCloudResType:
+		_, err = syncClient.Cvm(kt, params, new(synchuawei.SyncCvmOption))
+	case enumor.DiskCloudResType:
+		_, err = syncClient.Disk(kt, params, new(synchuawei.SyncDiskOption))
+	}
+	return err
+}
+
+// prepaidResInfos 查询待操作资源的基础信息。带宽包未纳入hcm资源管理，资源ID即云上ID，
+// 华为云费用中心续费不区分地域，因此不记录地域，操作后也无需同步
+func (svc *service) prepaidResInfos(kt *kit.Kit, vendor enumor.Vendor, accountID string,
+	resType enumor.CloudResourceType, ids []string) (map[string]types.CloudResourceBasicInfo, error) {
+
+	if resType != enumor.BandwidthPackageCloudResType {
+		return svc.listResBasicInfo(kt, vendor, accountID, resType, ids)
+	}
+
+	if vendor != enumor.HuaWei {
+		return nil, errf.Newf(errf.InvalidParameter, "%s does not support bandwidth package renewal", vendor)
+	}
+
+	infos := make(map[string]types.CloudResourceBasicInfo, len(ids))
+	for _, id := range ids {
+		infos[id] = types.CloudResourceBasicInfo{ID: id, CloudID: id, Vendor: vendor, AccountID: accountID}
+	}
+	return infos, nil
+}
+
+// listResBasicInfo 查询资源基础信息，并校验资源均属于该账号
+func (svc *service) listResBasicInfo(kt *kit.Kit, vendor enumor.Vendor, accountID string,
+	resType enumor.CloudResourceType, ids []string) (map[string]types.CloudResourceBasicInfo, error) {
+
+	basicReq := protocloud.ListResourceBasicInfoReq{
+		ResourceType: resType,
+		IDs:          ids,
+		Fields:       append([]string{"cloud_id", "region"}, types.CommonBasicInfoFields...),
+	}
+	infos, err := svc.dataCli.Global.Cloud.ListResBasicInfo(kt, basicReq)
+	if err != nil {
+		logs.Errorf("list resource basic info failed, err: %v, type: %s, ids: %v, rid: %s", err, resType, ids, kt.Rid)
+		return nil, err
+	}
+
+	for _, id := range ids {
+		info, exists := infos[id]
+		if !exists {
+			return nil, errf.Newf(errf.RecordNotFound, "%s %s not found", resType, id)
+		}
+
+		if info.Vendor != vendor || info.AccountID != accountID {
+			return nil, errf.Newf(errf.InvalidParameter, "%s %s does not belong to %s account %s", resType, id,
+				vendor, accountID)
+		}
+	}
+
+	return infos, nil
+}
+
+// groupByRegion 按地域聚合资源的云上ID
+func groupByRegion(infos map[string]types.CloudResourceBasicInfo) map[string][]string {
+	result := make(map[string][]string)
+	for _, info := range infos {
+		result[info.Region] = append(result[info.Region], info.CloudID)
+	}
+	return result
+}
@@ -0,0 +1,228 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package restag
+
+import (
+	typestag "hcm/pkg/adaptor/types/tag"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	proto "hcm/pkg/api/hc-service/resource-tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// tagOperator 各云 adaptor 的标签操作
+type tagOperator interface {
+	TagResources(kt *kit.Kit, opt *typestag.TagResOption) error
+	UnTagResources(kt *kit.Kit, opt *typestag.UnTagResOption) error
+}
+
+// AddResourceTags 为资源添加标签，先写云上，成功后再更新本地标签
+func (svc *service) AddResourceTags(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(proto.ResTagAddReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	infos, err := svc.listResBasicInfo(cts.Kit, vendor, req.AccountID, req.ResType, req.ResIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := svc.tagOperator(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	for location, resInfos := range groupByLocation(infos) {
+		opt := &typestag.TagResOption{
+			Region:   location.region,
+			Zone:     location.zone,
+			ResType:  req.ResType,
+			CloudIDs: cloudIDs(resInfos),
+			Tags:     req.Tags,
+		}
+		if err = cli.TagResources(cts.Kit, opt); err != nil {
+			logs.Errorf("tag %s resources failed, err: %v, opt: %+v, rid: %s", vendor, err, opt, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	items := make([]protocloud.ResourceTagUpsertItem, 0, len(infos))
+	for _, info := range infos {
+		items = append(items, protocloud.ResourceTagUpsertItem{
+			Vendor:     vendor,
+			AccountID:  req.AccountID,
+			ResType:    req.ResType,
+			ResID:      info.ID,
+			CloudResID: info.CloudID,
+			Tags:       req.Tags,
+		})
+	}
+	upsertReq := &protocloud.ResourceTagBatchUpsertReq{Items: items}
+	if err = svc.dataCli.Global.ResourceTag.BatchUpsert(cts.Kit, upsertReq); err != nil {
+		logs.Errorf("upsert resource tags failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// RemoveResourceTags 删除资源的指定标签，先写云上，成功后再删除本地标签
+func (svc *service) RemoveResourceTags(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(proto.ResTagRemoveReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	infos, err := svc.listResBasicInfo(cts.Kit, vendor, req.AccountID, req.ResType, req.ResIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := svc.tagOperator(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	for location, resInfos := range groupByLocation(infos) {
+		opt := &typestag.UnTagResOption{
+			Region:   location.region,
+			Zone:     location.zone,
+			ResType:  req.ResType,
+			CloudIDs: cloudIDs(resInfos),
+			Keys:     req.Keys,
+		}
+		if err = cli.UnTagResources(cts.Kit, opt); err != nil {
+			logs.Errorf("untag %s resources failed, err: %v, opt: %+v, rid: %s", vendor, err, opt, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	delReq := &dataservice.BatchDeleteReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("res_type", req.ResType),
+			tools.RuleIn("res_id", req.ResIDs),
+			tools.RuleIn("tag_key", req.Keys),
+		),
+	}
+	if err = svc.dataCli.Global.ResourceTag.BatchDelete(cts.Kit, delReq); err != nil {
+		logs.Errorf("delete resource tags failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (svc *service) tagOperator(kt *kit.Kit, vendor enumor.Vendor, accountID string) (tagOperator, error) {
+	switch vendor {
+	case enumor.TCloud:
+		return svc.adaptor.TCloud(kt, accountID)
+	case enumor.Aws:
+		return svc.adaptor.Aws(kt, accountID)
+	case enumor.HuaWei:
+		return svc.adaptor.HuaWei(kt, accountID)
+	case enumor.Gcp:
+		return svc.adaptor.Gcp(kt, accountID)
+	case enumor.Azure:
+		return svc.adaptor.Azure(kt, accountID)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "%s does not support resource tag", vendor)
+	}
+}
+
+// listResBasicInfo 查询资源基础信息，并校验资源均属于该账号
+func (svc *service) listResBasicInfo(kt *kit.Kit, vendor enumor.Vendor, accountID string,
+	resType enumor.CloudResourceType, ids []string) (map[string]types.CloudResourceBasicInfo, error) {
+
+	fields := append([]string{"cloud_id", "region"}, types.CommonBasicInfoFields...)
+	// gcp 的主机和硬盘为可用区资源，标签操作需要可用区
+	if resType == enumor.CvmCloudResType || resType == enumor.DiskCloudResType {
+		fields = append(fields, "zone")
+	}
+
+	basicReq := protocloud.ListResourceBasicInfoReq{
+		ResourceType: resType,
+		IDs:          ids,
+		Fields:       fields,
+	}
+	infos, err := svc.dataCli.Global.Cloud.ListResBasicInfo(kt, basicReq)
+	if err != nil {
+		logs.Errorf("list resource basic info failed, err: %v, type: %s, ids: %v, rid: %s", err, resType, ids, kt.Rid)
+		return nil, err
+	}
+
+	for _, id := range ids {
+		info, exists := infos[id]
+		if !exists {
+			return nil, errf.Newf(errf.RecordNotFound, "%s %s not found", resType, id)
+		}
+
+		if info.Vendor != vendor || info.AccountID != accountID {
+			return nil, errf.Newf(errf.InvalidParameter, "%s %s does not belong to %s account %s", resType, id,
+				vendor, accountID)
+		}
+	}
+
+	return infos, nil
+}
+
+type resLocation struct {
+	region string
+	zone   string
+}
+
+func groupByLocation(infos map[string]types.CloudResourceBasicInfo) map[resLocation][]types.CloudResourceBasicInfo {
+	result := make(map[resLocation][]types.CloudResourceBasicInfo)
+	for _, info := range infos {
+		location := resLocation{region: info.Region, zone: info.Zone}
+		result[location] = append(result[location], info)
+	}
+	return result
+}
+
+func cloudIDs(infos []types.CloudResourceBasicInfo) []string {
+	ids := make([]string, 0, len(infos))
+	for _, info := range infos {
+		ids = append(ids, info.CloudID)
+	}
+	return ids
+}
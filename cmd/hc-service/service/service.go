@@ -38,11 +38,15 @@ import (
 	"hcm/cmd/hc-service/service/cert"
 	"hcm/cmd/hc-service/service/cvm"
 	"hcm/cmd/hc-service/service/disk"
+	disksnapshot "hcm/cmd/hc-service/service/disk-snapshot"
 	"hcm/cmd/hc-service/service/eip"
 	"hcm/cmd/hc-service/service/firewall"
+	"hcm/cmd/hc-service/service/image"
 	instancetype "hcm/cmd/hc-service/service/instance-type"
 	loadbalancer "hcm/cmd/hc-service/service/load-balancer"
 	mainaccount "hcm/cmd/hc-service/service/main-account"
+	"hcm/cmd/hc-service/service/renewal"
+	restag "hcm/cmd/hc-service/service/resource-tag"
 	routetable "hcm/cmd/hc-service/service/route-table"
 	securitygroup "hcm/cmd/hc-service/service/security-group"
 	"hcm/cmd/hc-service/service/subnet"
@@ -174,6 +178,10 @@ func (s *Service) apiSet() *restful.Container {
 	cert.InitCertService(c)
 	bwpkg.InitBwPkgService(c)
 	mainaccount.InitService(c)
+	restag.InitService(c)
+	renewal.InitService(c)
+	image.InitService(c)
+	disksnapshot.InitService(c)
 
 	return restful.NewContainer().Add(c.WebService)
 }
@@ -20,10 +20,16 @@
 package actioncvm
 
 import (
+	"fmt"
+
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	hcprotocvm "hcm/pkg/api/hc-service/cvm"
 	ts "hcm/pkg/api/task-server"
 	"hcm/pkg/async/action"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/tools/counter"
+	"hcm/pkg/tools/slice"
 	"hcm/pkg/tools/uuid"
 )
 
@@ -61,3 +67,135 @@ func BuildCreateCvmTasks(totalCount int64, bizID int64, limit int64,
 
 	return tasks
 }
+
+// RollingTaskOption 滚动操作任务构建参数
+type RollingTaskOption struct {
+	// ActionName 主机操作，如开机、关机、重启、删除
+	ActionName enumor.ActionName
+	// IDs 待操作主机ID，按顺序划分批次
+	IDs          []string
+	BasicInfoMap map[string]types.CloudResourceBasicInfo
+	BatchSize    int
+	// Gate 健康检查任务参数模板，Batch、CvmIDs 按批次填充，最后一个批次之后不再暂停
+	Gate RollingCvmGateOption
+}
+
+// BuildRollingTasks 构建滚动操作任务，每批次的主机按账号、地域合并为主机操作任务，之后追加一个健康检查任务，
+// 下一批次的主机操作任务依赖上一批次的健康检查任务，健康检查失败时Flow失败，后续批次不再执行。
+func BuildRollingTasks(opt *RollingTaskOption) ([]ts.CustomFlowTask, error) {
+	if opt.BatchSize <= 0 {
+		return nil, fmt.Errorf("batch size should > 0")
+	}
+
+	ctrFunc := counter.NewNumStringCounter(1, 10)
+	batches := slice.Split(slice.Unique(opt.IDs), opt.BatchSize)
+	tasks := make([]ts.CustomFlowTask, 0)
+	var prevGateIDs []action.ActIDType
+	for idx, batch := range batches {
+		options, err := BuildOperationOptions(batch, opt.BasicInfoMap)
+		if err != nil {
+			return nil, err
+		}
+
+		operationIDs := make([]action.ActIDType, 0, len(options))
+		for _, one := range options {
+			actionID := action.ActIDType(ctrFunc())
+			operationIDs = append(operationIDs, actionID)
+			tasks = append(tasks, ts.CustomFlowTask{
+				ActionID:   actionID,
+				ActionName: opt.ActionName,
+				Params:     one,
+				DependOn:   prevGateIDs,
+			})
+		}
+
+		gate := opt.Gate
+		gate.Batch = idx + 1
+		gate.CvmIDs = batch
+		if idx == len(batches)-1 {
+			gate.PauseSec = 0
+		}
+		gateID := action.ActIDType(ctrFunc())
+		tasks = append(tasks, ts.CustomFlowTask{
+			ActionID:   gateID,
+			ActionName: enumor.ActionRollingCvmGate,
+			Params:     gate,
+			DependOn:   operationIDs,
+		})
+		prevGateIDs = []action.ActIDType{gateID}
+	}
+
+	return tasks, nil
+}
+
+// BuildOperationOptions 构建主机操作参数，TCloud/Aws/HuaWei 按账号、地域合并批量操作，Azure/Gcp 仅支持单台操作，
+// 参数顺序与主机ID的顺序一致
+func BuildOperationOptions(ids []string, basicInfoMap map[string]types.CloudResourceBasicInfo) (
+	[]CvmOperationOption, error) {
+
+	options := make([]CvmOperationOption, 0)
+	indexMap := make(map[string]int)
+	for _, id := range ids {
+		info, exist := basicInfoMap[id]
+		if !exist {
+			return nil, fmt.Errorf("cvm: %s not found", id)
+		}
+
+		switch info.Vendor {
+		case enumor.TCloud, enumor.Aws, enumor.HuaWei:
+			key := info.AccountID + "_" + info.Region
+			idx, exist := indexMap[key]
+			if !exist {
+				indexMap[key] = len(options)
+				options = append(options, CvmOperationOption{
+					Vendor:    info.Vendor,
+					AccountID: info.AccountID,
+					Region:    info.Region,
+					IDs:       []string{info.ID},
+				})
+				continue
+			}
+			options[idx].IDs = append(options[idx].IDs, info.ID)
+
+		case enumor.Azure, enumor.Gcp:
+			options = append(options, CvmOperationOption{
+				Vendor:    info.Vendor,
+				AccountID: info.AccountID,
+				IDs:       []string{info.ID},
+			})
+
+		default:
+			return nil, fmt.Errorf("vendor: %s not support", info.Vendor)
+		}
+	}
+
+	return options, nil
+}
+
+// BuildResizeCvmTasks 构建调整主机规格任务，运行中的主机需要先关机，调整规格任务无论成功与否都会重新开机，
+// 其他状态的主机直接调整规格
+func BuildResizeCvmTasks(cvm *corecvm.BaseCvm, instanceType string) ([]ts.CustomFlowTask, error) {
+	resizeOpt := &ResizeCvmOption{
+		Vendor:       cvm.Vendor,
+		ResizeCvmReq: hcprotocvm.ResizeCvmReq{ID: cvm.ID, InstanceType: instanceType},
+	}
+	if cvm.LifecycleStatus != enumor.CvmLifecycleRunning {
+		return []ts.CustomFlowTask{{ActionID: "1", ActionName: enumor.ActionResizeCvm, Params: resizeOpt}}, nil
+	}
+
+	basicInfoMap := map[string]types.CloudResourceBasicInfo{
+		cvm.ID: {ID: cvm.ID, Vendor: cvm.Vendor, AccountID: cvm.AccountID, Region: cvm.Region},
+	}
+	options, err := BuildOperationOptions([]string{cvm.ID}, basicInfoMap)
+	if err != nil {
+		return nil, err
+	}
+
+	resizeOpt.StartOption = &options[0]
+	tasks := []ts.CustomFlowTask{
+		{ActionID: "1", ActionName: enumor.ActionStopCvm, Params: options[0]},
+		{ActionID: "2", ActionName: enumor.ActionResizeCvm, Params: resizeOpt, DependOn: []action.ActIDType{"1"}},
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,174 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actioncvm
+
+import (
+	"reflect"
+	"testing"
+
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	"hcm/pkg/async/action"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/types"
+)
+
+func TestBuildRollingTasks(t *testing.T) {
+	infos := map[string]types.CloudResourceBasicInfo{
+		"1": {ID: "1", Vendor: enumor.TCloud, AccountID: "a1", Region: "ap-guangzhou"},
+		"2": {ID: "2", Vendor: enumor.TCloud, AccountID: "a1", Region: "ap-guangzhou"},
+		"3": {ID: "3", Vendor: enumor.TCloud, AccountID: "a1", Region: "ap-shanghai"},
+		"4": {ID: "4", Vendor: enumor.Gcp, AccountID: "a2"},
+		"5": {ID: "5", Vendor: enumor.Gcp, AccountID: "a2"},
+	}
+	opt := &RollingTaskOption{
+		ActionName:   enumor.ActionRebootCvm,
+		IDs:          []string{"1", "2", "3", "4", "5", "1"},
+		BasicInfoMap: infos,
+		BatchSize:    3,
+		Gate: RollingCvmGateOption{
+			PauseSec:         30,
+			CheckTimeoutSec:  60,
+			FailureThreshold: 1,
+			Gate:             RollingGate{Type: enumor.RollingGateCvmStatus, ExpectStatus: enumor.CvmLifecycleRunning},
+		},
+	}
+
+	tasks, err := BuildRollingTasks(opt)
+	if err != nil {
+		t.Fatalf("build rolling tasks failed, err: %v", err)
+	}
+
+	// 批次1：[1 2 3] 按地域合并为2个操作任务 + 1个检查任务；批次2：[4 5] gcp 单台操作，2个操作任务 + 1个检查任务
+	expectActions := []enumor.ActionName{
+		enumor.ActionRebootCvm, enumor.ActionRebootCvm, enumor.ActionRollingCvmGate,
+		enumor.ActionRebootCvm, enumor.ActionRebootCvm, enumor.ActionRollingCvmGate,
+	}
+	if len(tasks) != len(expectActions) {
+		t.Fatalf("expect %d tasks, got: %d", len(expectActions), len(tasks))
+	}
+	for i, task := range tasks {
+		if task.ActionName != expectActions[i] {
+			t.Errorf("task %d expect action %s, got: %s", i, expectActions[i], task.ActionName)
+		}
+	}
+
+	expectDepends := [][]action.ActIDType{
+		nil, nil, {tasks[0].ActionID, tasks[1].ActionID},
+		{tasks[2].ActionID}, {tasks[2].ActionID}, {tasks[3].ActionID, tasks[4].ActionID},
+	}
+	for i, task := range tasks {
+		if len(task.DependOn) == 0 && len(expectDepends[i]) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(task.DependOn, expectDepends[i]) {
+			t.Errorf("task %d expect depend on %v, got: %v", i, expectDepends[i], task.DependOn)
+		}
+	}
+
+	firstGate, ok := tasks[2].Params.(RollingCvmGateOption)
+	if !ok {
+		t.Fatalf("gate task params type mismatch: %T", tasks[2].Params)
+	}
+	if firstGate.Batch != 1 || firstGate.PauseSec != 30 ||
+		!reflect.DeepEqual(firstGate.CvmIDs, []string{"1", "2", "3"}) {
+		t.Errorf("unexpected first gate option: %+v", firstGate)
+	}
+
+	lastGate := tasks[5].Params.(RollingCvmGateOption)
+	// 最后一个批次之后不再暂停
+	if lastGate.Batch != 2 || lastGate.PauseSec != 0 || !reflect.DeepEqual(lastGate.CvmIDs, []string{"4", "5"}) {
+		t.Errorf("unexpected last gate option: %+v", lastGate)
+	}
+
+	op := tasks[0].Params.(CvmOperationOption)
+	if op.Region != "ap-guangzhou" || !reflect.DeepEqual(op.IDs, []string{"1", "2"}) {
+		t.Errorf("unexpected first operation option: %+v", op)
+	}
+}
+
+func TestBuildRollingTasksInvalid(t *testing.T) {
+	infos := map[string]types.CloudResourceBasicInfo{
+		"1": {ID: "1", Vendor: enumor.TCloud, AccountID: "a1", Region: "ap-guangzhou"},
+	}
+
+	if _, err := BuildRollingTasks(&RollingTaskOption{IDs: []string{"1"}, BasicInfoMap: infos}); err == nil {
+		t.Errorf("batch size 0 should be invalid")
+	}
+
+	opt := &RollingTaskOption{IDs: []string{"1", "2"}, BasicInfoMap: infos, BatchSize: 1}
+	if _, err := BuildRollingTasks(opt); err == nil {
+		t.Errorf("cvm without basic info should be invalid")
+	}
+}
+
+func TestBuildResizeCvmTasks(t *testing.T) {
+	cvm := &corecvm.BaseCvm{
+		ID:              "1",
+		Vendor:          enumor.TCloud,
+		AccountID:       "a1",
+		Region:          "ap-guangzhou",
+		LifecycleStatus: enumor.CvmLifecycleRunning,
+	}
+
+	tasks, err := BuildResizeCvmTasks(cvm, "S5.LARGE8")
+	if err != nil {
+		t.Fatalf("build resize cvm tasks failed, err: %v", err)
+	}
+
+	// 运行中的主机：关机 -> 调整规格（无论成功与否都重新开机），不再依赖调整规格成功才开机
+	if len(tasks) != 2 {
+		t.Fatalf("expect 2 tasks, got: %d", len(tasks))
+	}
+	if tasks[0].ActionName != enumor.ActionStopCvm || tasks[1].ActionName != enumor.ActionResizeCvm {
+		t.Errorf("expect stop -> resize tasks, got: %s -> %s", tasks[0].ActionName, tasks[1].ActionName)
+	}
+	if !reflect.DeepEqual(tasks[1].DependOn, []action.ActIDType{tasks[0].ActionID}) {
+		t.Errorf("resize task should depend on stop task, got: %v", tasks[1].DependOn)
+	}
+
+	resizeOpt, ok := tasks[1].Params.(*ResizeCvmOption)
+	if !ok {
+		t.Fatalf("resize task params type mismatch: %T", tasks[1].Params)
+	}
+	if resizeOpt.ID != "1" || resizeOpt.InstanceType != "S5.LARGE8" {
+		t.Errorf("unexpected resize option: %+v", resizeOpt)
+	}
+	expectStart := &CvmOperationOption{Vendor: enumor.TCloud, AccountID: "a1", Region: "ap-guangzhou",
+		IDs: []string{"1"}}
+	if !reflect.DeepEqual(resizeOpt.StartOption, expectStart) {
+		t.Errorf("expect start option %+v, got: %+v", expectStart, resizeOpt.StartOption)
+	}
+	if err = resizeOpt.Validate(); err != nil {
+		t.Errorf("resize option should be valid, err: %v", err)
+	}
+
+	// 非运行中的主机直接调整规格，调整后不开机
+	cvm.LifecycleStatus = enumor.CvmLifecycleStopped
+	tasks, err = BuildResizeCvmTasks(cvm, "S5.LARGE8")
+	if err != nil {
+		t.Fatalf("build resize cvm tasks failed, err: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ActionName != enumor.ActionResizeCvm || len(tasks[0].DependOn) != 0 {
+		t.Fatalf("expect single resize task, got: %+v", tasks)
+	}
+	if opt := tasks[0].Params.(*ResizeCvmOption); opt.StartOption != nil {
+		t.Errorf("stopped cvm should not be started after resize, got start option: %+v", opt.StartOption)
+	}
+}
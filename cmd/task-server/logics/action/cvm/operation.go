@@ -53,6 +53,8 @@ type CvmOperationOption struct {
 	Region    string        `json:"region" validate:"omitempty"`
 	// IDs TCloud/HuaWei/Aws 支持批量操作，Azure/Gcp 仅支持单个操作
 	IDs []string `json:"ids" validate:"required,min=1,max=100"`
+	// Deallocate 仅 Azure 关机时生效，是否解除分配虚拟机
+	Deallocate bool `json:"deallocate,omitempty" validate:"omitempty"`
 }
 
 // Validate operation cvm option.
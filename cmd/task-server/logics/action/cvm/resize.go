@@ -0,0 +1,159 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actioncvm
+
+import (
+	"fmt"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	hcprotocvm "hcm/pkg/api/hc-service/cvm"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/logs"
+)
+
+var _ action.Action = new(ResizeCvmAction)
+var _ action.ParameterAction = new(ResizeCvmAction)
+
+// ResizeCvmAction resize cvm action.
+// 指定 StartOption 时，无论调整规格成功与否都会重新开机，避免调整前已关机的运行中主机在调整失败后一直处于关机状态。
+type ResizeCvmAction struct{}
+
+// ResizeCvmOption resize cvm option.
+type ResizeCvmOption struct {
+	Vendor                  enumor.Vendor `json:"vendor" validate:"required"`
+	hcprotocvm.ResizeCvmReq `json:",inline"`
+	// StartOption 调整规格后重新开机的参数，为空时不开机
+	StartOption *CvmOperationOption `json:"start_option,omitempty" validate:"omitempty"`
+}
+
+// Validate ResizeCvmOption.
+func (opt *ResizeCvmOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if opt.StartOption != nil {
+		if err := opt.StartOption.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return opt.ResizeCvmReq.Validate()
+}
+
+// ResizeCvmResult 调整规格失败或重新开机失败时的任务结果，分别记录两个步骤的错误
+type ResizeCvmResult struct {
+	ResizeError string `json:"resize_error,omitempty"`
+	StartError  string `json:"start_error,omitempty"`
+}
+
+// ParameterNew return request params.
+func (act ResizeCvmAction) ParameterNew() (params interface{}) {
+	return new(ResizeCvmOption)
+}
+
+// Name return action name.
+func (act ResizeCvmAction) Name() enumor.ActionName {
+	return enumor.ActionResizeCvm
+}
+
+// Run resize cvm by hc-service, and start cvm after resize if start option is set.
+func (act ResizeCvmAction) Run(kt run.ExecuteKit, params interface{}) (interface{}, error) {
+	opt, ok := params.(*ResizeCvmOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	resizeErr := act.resize(kt, opt)
+	if opt.StartOption == nil {
+		return nil, resizeErr
+	}
+
+	_, startErr := NewStartAction().Run(kt, opt.StartOption)
+	if startErr != nil {
+		logs.Errorf("start cvm after resize failed, err: %v, resize err: %v, id: %s, rid: %s", startErr, resizeErr,
+			opt.ID, kt.Kit().Rid)
+	}
+
+	result, err := resizeStartResult(resizeErr, startErr)
+	if err != nil {
+		return result, err
+	}
+
+	return nil, nil
+}
+
+func (act ResizeCvmAction) resize(kt run.ExecuteKit, opt *ResizeCvmOption) error {
+	cli := actcli.GetHCService()
+	var err error
+	switch opt.Vendor {
+	case enumor.TCloud:
+		err = cli.TCloud.Cvm.ResizeCvm(kt.Kit(), &opt.ResizeCvmReq)
+	case enumor.Aws:
+		err = cli.Aws.Cvm.ResizeCvm(kt.Kit(), &opt.ResizeCvmReq)
+	case enumor.HuaWei:
+		err = cli.HuaWei.Cvm.ResizeCvm(kt.Kit(), &opt.ResizeCvmReq)
+	case enumor.Azure:
+		err = cli.Azure.Cvm.ResizeCvm(kt.Kit(), &opt.ResizeCvmReq)
+	case enumor.Gcp:
+		err = cli.Gcp.Cvm.ResizeCvm(kt.Kit(), &opt.ResizeCvmReq)
+	default:
+		return errf.Newf(errf.InvalidParameter, "vendor: %s not support resize cvm", opt.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("resize cvm failed, err: %v, vendor: %s, opt: %+v, rid: %s", err, opt.Vendor, opt, kt.Kit().Rid)
+		return err
+	}
+
+	return nil
+}
+
+// resizeStartResult 合并调整规格与重新开机的结果，任一步骤失败时任务失败，并在结果中分别返回两个步骤的错误
+func resizeStartResult(resizeErr, startErr error) (*ResizeCvmResult, error) {
+	if resizeErr == nil && startErr == nil {
+		return nil, nil
+	}
+
+	result := new(ResizeCvmResult)
+	if resizeErr != nil {
+		result.ResizeError = resizeErr.Error()
+	}
+	if startErr != nil {
+		result.StartError = startErr.Error()
+	}
+
+	switch {
+	case startErr == nil:
+		return result, fmt.Errorf("resize cvm failed and cvm has been restarted, err: %v", resizeErr)
+	case resizeErr == nil:
+		return result, fmt.Errorf("cvm has been resized but start cvm failed, err: %v", startErr)
+	default:
+		return result, fmt.Errorf("resize cvm failed, err: %v, and start cvm failed, err: %v", resizeErr,
+			startErr)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actioncvm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	hcprotocvm "hcm/pkg/api/hc-service/cvm"
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestResizeStartResult(t *testing.T) {
+	resizeErr := errors.New("instance type sold out")
+	startErr := errors.New("start timeout")
+
+	cases := []struct {
+		name         string
+		resizeErr    error
+		startErr     error
+		expectErr    bool
+		expectResult *ResizeCvmResult
+		errContains  []string
+	}{
+		{name: "all success"},
+		{
+			name:         "resize failed and restarted",
+			resizeErr:    resizeErr,
+			expectErr:    true,
+			expectResult: &ResizeCvmResult{ResizeError: resizeErr.Error()},
+			errContains:  []string{"resize cvm failed", "restarted", resizeErr.Error()},
+		},
+		{
+			name:         "resized but start failed",
+			startErr:     startErr,
+			expectErr:    true,
+			expectResult: &ResizeCvmResult{StartError: startErr.Error()},
+			errContains:  []string{"start cvm failed", startErr.Error()},
+		},
+		{
+			name:         "both failed",
+			resizeErr:    resizeErr,
+			startErr:     startErr,
+			expectErr:    true,
+			expectResult: &ResizeCvmResult{ResizeError: resizeErr.Error(), StartError: startErr.Error()},
+			errContains:  []string{resizeErr.Error(), startErr.Error()},
+		},
+	}
+
+	for _, c := range cases {
+		result, err := resizeStartResult(c.resizeErr, c.startErr)
+		if (err != nil) != c.expectErr {
+			t.Errorf("%s: expect error: %v, got: %v", c.name, c.expectErr, err)
+			continue
+		}
+
+		if c.expectResult == nil {
+			if result != nil {
+				t.Errorf("%s: expect nil result, got: %+v", c.name, result)
+			}
+			continue
+		}
+		if result == nil || *result != *c.expectResult {
+			t.Errorf("%s: expect result %+v, got: %+v", c.name, c.expectResult, result)
+		}
+		for _, sub := range c.errContains {
+			if !strings.Contains(err.Error(), sub) {
+				t.Errorf("%s: expect error contains %q, got: %v", c.name, sub, err)
+			}
+		}
+	}
+}
+
+func TestResizeCvmOptionValidate(t *testing.T) {
+	opt := &ResizeCvmOption{
+		Vendor:       enumor.Gcp,
+		ResizeCvmReq: hcprotocvm.ResizeCvmReq{ID: "1", InstanceType: "e2-medium"},
+	}
+	if err := opt.Validate(); err != nil {
+		t.Errorf("resize option without start option should be valid, err: %v", err)
+	}
+
+	// gcp 仅支持单台开机
+	opt.StartOption = &CvmOperationOption{Vendor: enumor.Gcp, AccountID: "a1", IDs: []string{"1", "2"}}
+	if err := opt.Validate(); err == nil {
+		t.Errorf("invalid start option should be rejected")
+	}
+
+	opt.StartOption.IDs = []string{"1"}
+	if err := opt.Validate(); err != nil {
+		t.Errorf("resize option with valid start option should be valid, err: %v", err)
+	}
+}
@@ -0,0 +1,424 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actioncvm
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/core"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	protocvm "hcm/pkg/api/hc-service/cvm"
+	hclb "hcm/pkg/api/hc-service/load-balancer"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/dao/tools"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+const (
+	// RollingFailedCvmIDKey 共享数据中记录滚动操作累计未通过健康检查的主机ID
+	RollingFailedCvmIDKey = "rolling_failed_cvm_ids"
+	// rollingGateStartAtKeyFmt 共享数据中记录各批次健康检查开始时间（unix秒）的key
+	rollingGateStartAtKeyFmt = "rolling_gate_%d_start_at"
+
+	rollingGatePollInterval = 10 * time.Second
+)
+
+var _ action.Action = new(RollingCvmGateAction)
+var _ action.ParameterAction = new(RollingCvmGateAction)
+
+// RollingCvmGateAction 滚动操作批次间的健康检查，依赖本批次的主机操作任务，
+// 未通过检查的主机累计数超过失败阈值时返回错误，使Flow失败从而终止后续批次。
+// 暂停及检查未通过时的轮询通过 action.Reschedule 重新下发任务实现，等待期间不占用执行器，也不受任务执行超时限制。
+type RollingCvmGateAction struct{}
+
+// RollingCvmGateOption rolling cvm gate option.
+type RollingCvmGateOption struct {
+	// Batch 批次序号，从1开始
+	Batch int `json:"batch" validate:"min=1"`
+	// CvmIDs 本批次操作的主机ID
+	CvmIDs []string `json:"cvm_ids" validate:"required,min=1"`
+	// PauseSec 检查前的暂停时长，给主机及后端服务留出启动时间
+	PauseSec int `json:"pause_sec" validate:"min=0"`
+	// CheckTimeoutSec 健康检查的最长等待时长，超时仍未通过的主机计为失败
+	CheckTimeoutSec int `json:"check_timeout_sec" validate:"min=0"`
+	// FailureThreshold 允许累计未通过检查的主机数，超过则终止后续批次
+	FailureThreshold int `json:"failure_threshold" validate:"min=0"`
+	// Gate 健康检查参数
+	Gate RollingGate `json:"gate" validate:"required"`
+}
+
+// RollingGate 健康检查参数
+type RollingGate struct {
+	Type enumor.RollingGateType `json:"type" validate:"required"`
+	// ExpectStatus 检查类型为 cvm_status 时期望的主机生命周期状态，期望 terminated 时主机已不存在也视为通过
+	ExpectStatus enumor.CvmLifecycleStatus `json:"expect_status" validate:"omitempty"`
+	// TargetGroupID 检查类型为 lb_target_health 时检查的目标组，每次检查时查询目标组当前绑定的监听器、规则
+	TargetGroupID string `json:"target_group_id" validate:"omitempty"`
+}
+
+// Validate rolling gate.
+func (g RollingGate) Validate() error {
+	if err := g.Type.Validate(); err != nil {
+		return err
+	}
+
+	switch g.Type {
+	case enumor.RollingGateCvmStatus:
+		if err := g.ExpectStatus.Validate(); err != nil {
+			return err
+		}
+	case enumor.RollingGateLbTargetHealth:
+		if len(g.TargetGroupID) == 0 {
+			return fmt.Errorf("target_group_id is required for %s gate", g.Type)
+		}
+	}
+
+	return nil
+}
+
+// Validate rolling cvm gate option.
+func (opt RollingCvmGateOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if opt.PauseSec > constant.RollingCvmPauseMaxSec {
+		return fmt.Errorf("pause_sec should <= %d", constant.RollingCvmPauseMaxSec)
+	}
+
+	if opt.CheckTimeoutSec > constant.RollingCvmCheckTimeoutMaxSec {
+		return fmt.Errorf("check_timeout_sec should <= %d", constant.RollingCvmCheckTimeoutMaxSec)
+	}
+
+	return opt.Gate.Validate()
+}
+
+// ParameterNew return rolling cvm gate option.
+func (act RollingCvmGateAction) ParameterNew() interface{} {
+	return new(RollingCvmGateOption)
+}
+
+// Name return action name.
+func (act RollingCvmGateAction) Name() enumor.ActionName {
+	return enumor.ActionRollingCvmGate
+}
+
+// Run rolling cvm gate.
+func (act RollingCvmGateAction) Run(kt run.ExecuteKit, params interface{}) (interface{}, error) {
+	opt, ok := params.(*RollingCvmGateOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	startAt, err := act.startAt(kt, opt.Batch)
+	if err != nil {
+		return nil, err
+	}
+
+	elapsed := time.Since(startAt)
+	pause := time.Duration(opt.PauseSec) * time.Second
+	if elapsed < pause {
+		return nil, action.Reschedule(pause-elapsed, fmt.Sprintf("rolling batch %d is pausing", opt.Batch))
+	}
+
+	failedIDs, err := act.check(kt.Kit(), opt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(failedIDs) == 0 {
+		return nil, nil
+	}
+
+	deadline := pause + time.Duration(opt.CheckTimeoutSec)*time.Second
+	if wait := RollingCheckWait(elapsed, deadline); wait > 0 {
+		return nil, action.Reschedule(wait, fmt.Sprintf("rolling batch %d has %d cvms not passed %s gate",
+			opt.Batch, len(failedIDs), opt.Gate.Type))
+	}
+
+	if err = kt.ShareData().AppendIDs(kt.Kit(), RollingFailedCvmIDKey, failedIDs...); err != nil {
+		logs.Errorf("save rolling failed cvm ids failed, err: %v, ids: %v, rid: %s", err, failedIDs, kt.Kit().Rid)
+		return nil, err
+	}
+
+	idsStr, _ := kt.ShareData().Get(RollingFailedCvmIDKey)
+	totalFailed := len(slice.Unique(tableasync.ParseIDsStr(idsStr)))
+	logs.Warnf("rolling cvm batch %d has %d cvms not passed %s gate, total failed: %d, ids: %v, rid: %s",
+		opt.Batch, len(failedIDs), opt.Gate.Type, totalFailed, failedIDs, kt.Kit().Rid)
+
+	if totalFailed > opt.FailureThreshold {
+		return nil, fmt.Errorf("rolling aborted at batch %d, failed cvm count %d exceeds threshold %d",
+			opt.Batch, totalFailed, opt.FailureThreshold)
+	}
+
+	return nil, nil
+}
+
+// startAt 返回批次健康检查的开始时间，首次执行时记录到共享数据中，重新下发执行时沿用该时间计算暂停及检查时长
+func (act RollingCvmGateAction) startAt(kt run.ExecuteKit, batch int) (time.Time, error) {
+	key := fmt.Sprintf(rollingGateStartAtKeyFmt, batch)
+	if val, exist := kt.ShareData().Get(key); exist {
+		sec, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parse rolling gate start time %s failed, err: %v", val, err)
+		}
+		return time.Unix(sec, 0), nil
+	}
+
+	now := time.Now()
+	if err := kt.ShareData().Set(kt.Kit(), key, strconv.FormatInt(now.Unix(), 10)); err != nil {
+		logs.Errorf("save rolling gate start time failed, err: %v, batch: %d, rid: %s", err, batch, kt.Kit().Rid)
+		return time.Time{}, err
+	}
+
+	return now, nil
+}
+
+// RollingCheckWait 返回健康检查未通过时距离下一次检查的等待时长，已到达检查截止时长时返回0，不再等待
+func RollingCheckWait(elapsed, deadline time.Duration) time.Duration {
+	remain := deadline - elapsed
+	if remain <= 0 {
+		return 0
+	}
+
+	if remain < rollingGatePollInterval {
+		return remain
+	}
+
+	return rollingGatePollInterval
+}
+
+// check 返回本批次未通过检查的主机ID
+func (act RollingCvmGateAction) check(kt *kit.Kit, opt *RollingCvmGateOption) ([]string, error) {
+	switch opt.Gate.Type {
+	case enumor.RollingGateNone:
+		return nil, nil
+
+	case enumor.RollingGateCvmStatus:
+		return act.checkCvmStatus(kt, opt.CvmIDs, opt.Gate.ExpectStatus)
+
+	case enumor.RollingGateLbTargetHealth:
+		healthy, err := act.checkLbTargetHealth(kt, opt.Gate.TargetGroupID)
+		if err != nil {
+			return nil, err
+		}
+		// 健康状态按监听器/规则聚合，无法对应到具体主机，未通过时整批主机计为失败
+		if !healthy {
+			return opt.CvmIDs, nil
+		}
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("rolling gate type: %s not support", opt.Gate.Type)
+	}
+}
+
+// checkCvmStatus 先从云上刷新主机状态，再比较主机的生命周期状态
+func (act RollingCvmGateAction) checkCvmStatus(kt *kit.Kit, ids []string, expect enumor.CvmLifecycleStatus) (
+	[]string, error) {
+
+	cvms, err := act.listCvm(kt, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	vendorIDs := make(map[enumor.Vendor][]string)
+	for _, one := range cvms {
+		vendorIDs[one.Vendor] = append(vendorIDs[one.Vendor], one.ID)
+	}
+
+	cli := actcli.GetHCService()
+	for vendor, vIDs := range vendorIDs {
+		req := &protocvm.SyncCvmStatusReq{IDs: vIDs}
+		switch vendor {
+		case enumor.TCloud:
+			err = cli.TCloud.Cvm.SyncCvmStatus(kt, req)
+		case enumor.Aws:
+			err = cli.Aws.Cvm.SyncCvmStatus(kt, req)
+		case enumor.HuaWei:
+			err = cli.HuaWei.Cvm.SyncCvmStatus(kt, req)
+		case enumor.Gcp:
+			err = cli.Gcp.Cvm.SyncCvmStatus(kt, req)
+		case enumor.Azure:
+			err = cli.Azure.Cvm.SyncCvmStatus(kt, req)
+		default:
+			err = fmt.Errorf("vendor: %s not support", vendor)
+		}
+		if err != nil {
+			logs.Errorf("sync %s cvm status failed, err: %v, ids: %v, rid: %s", vendor, err, vIDs, kt.Rid)
+			return nil, err
+		}
+	}
+
+	if cvms, err = act.listCvm(kt, ids); err != nil {
+		return nil, err
+	}
+
+	return RollingStatusFailedIDs(ids, cvms, expect), nil
+}
+
+func (act RollingCvmGateAction) listCvm(kt *kit.Kit, ids []string) ([]corecvm.BaseCvm, error) {
+	listReq := &core.ListReq{
+		Fields: []string{"id", "vendor", "lifecycle_status"},
+		Filter: tools.ContainersExpression("id", ids),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := actcli.GetDataService().Global.Cvm.ListCvm(kt, listReq)
+	if err != nil {
+		logs.Errorf("list cvm failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return nil, err
+	}
+
+	return result.Details, nil
+}
+
+// RollingStatusFailedIDs 返回生命周期状态与期望不一致的主机ID，期望 terminated 时已不存在的主机视为通过
+func RollingStatusFailedIDs(ids []string, cvms []corecvm.BaseCvm, expect enumor.CvmLifecycleStatus) []string {
+	statusMap := make(map[string]enumor.CvmLifecycleStatus, len(cvms))
+	for _, one := range cvms {
+		statusMap[one.ID] = one.LifecycleStatus
+	}
+
+	failedIDs := make([]string, 0)
+	for _, id := range ids {
+		status, exist := statusMap[id]
+		if !exist {
+			if expect != enumor.CvmLifecycleTerminated {
+				failedIDs = append(failedIDs, id)
+			}
+			continue
+		}
+
+		if status != expect {
+			failedIDs = append(failedIDs, id)
+		}
+	}
+
+	return failedIDs
+}
+
+// checkLbTargetHealth 检查目标组当前所绑定的监听器、规则下是否存在不健康的后端服务
+func (act RollingCvmGateAction) checkLbTargetHealth(kt *kit.Kit, tgID string) (bool, error) {
+	tg, err := actcli.GetDataService().TCloud.LoadBalancer.GetTargetGroup(kt, tgID)
+	if err != nil {
+		logs.Errorf("get tcloud target group failed, err: %v, id: %s, rid: %s", err, tgID, kt.Rid)
+		return false, err
+	}
+
+	relReq := &core.ListReq{
+		Filter: tools.EqualExpression("target_group_id", tgID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	relResp, err := actcli.GetDataService().Global.LoadBalancer.ListTargetGroupListenerRel(kt, relReq)
+	if err != nil {
+		logs.Errorf("list target group listener rel failed, err: %v, tgID: %s, rid: %s", err, tgID, kt.Rid)
+		return false, err
+	}
+	if len(relResp.Details) == 0 {
+		return false, fmt.Errorf("target group: %s has not bound listener", tgID)
+	}
+
+	cloudLbIDs := make([]string, 0, len(relResp.Details))
+	cloudLblIDs := make([]string, 0)
+	cloudRuleIDs := make([]string, 0)
+	for _, rel := range relResp.Details {
+		cloudLbIDs = append(cloudLbIDs, rel.CloudLbID)
+		if rel.ListenerRuleType == enumor.Layer7RuleType {
+			cloudRuleIDs = append(cloudRuleIDs, rel.CloudListenerRuleID)
+			continue
+		}
+		cloudLblIDs = append(cloudLblIDs, rel.CloudLblID)
+	}
+
+	req := &hclb.TCloudTargetHealthReq{
+		AccountID:  tg.AccountID,
+		Region:     tg.Region,
+		CloudLbIDs: slice.Unique(cloudLbIDs),
+	}
+	resp, err := actcli.GetHCService().TCloud.Clb.ListTargetHealth(kt, req)
+	if err != nil {
+		logs.Errorf("list tcloud target health failed, err: %v, req: %+v, rid: %s", err, req, kt.Rid)
+		return false, err
+	}
+
+	return RollingLbTargetHealthy(resp, cloudLblIDs, cloudRuleIDs), nil
+}
+
+// RollingLbTargetHealthy 判断给定四层监听器、七层规则下的后端服务是否全部健康，未查询到任一监听器或规则时视为不健康
+func RollingLbTargetHealthy(resp *hclb.TCloudTargetHealthResp, cloudLblIDs, cloudRuleIDs []string) bool {
+	if resp == nil {
+		return false
+	}
+
+	lblMap := converter.StringSliceToMap(cloudLblIDs)
+	ruleMap := converter.StringSliceToMap(cloudRuleIDs)
+	matched := false
+	for _, lb := range resp.Details {
+		for _, lbl := range lb.Listeners {
+			if lbl == nil {
+				continue
+			}
+			if _, exist := lblMap[lbl.CloudLblID]; exist {
+				matched = true
+				if !isAllTargetHealthy(lbl.HealthCheck) {
+					return false
+				}
+			}
+			for _, rule := range lbl.Rules {
+				if rule == nil {
+					continue
+				}
+				if _, exist := ruleMap[rule.CloudRuleID]; exist {
+					matched = true
+					if !isAllTargetHealthy(rule.HealthCheck) {
+						return false
+					}
+				}
+			}
+		}
+	}
+
+	return matched
+}
+
+func isAllTargetHealthy(info *corelb.TCloudHealthCheckInfo) bool {
+	if info == nil {
+		return true
+	}
+
+	return converter.PtrToVal(info.UnHealthNum) == 0
+}
@@ -0,0 +1,157 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actioncvm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	hclb "hcm/pkg/api/hc-service/load-balancer"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/converter"
+)
+
+func TestRollingStatusFailedIDs(t *testing.T) {
+	cvms := []corecvm.BaseCvm{
+		{ID: "1", LifecycleStatus: enumor.CvmLifecycleRunning},
+		{ID: "2", LifecycleStatus: enumor.CvmLifecycleStopped},
+		{ID: "3", LifecycleStatus: enumor.CvmLifecycleTerminated},
+	}
+	ids := []string{"1", "2", "3", "4"}
+
+	cases := []struct {
+		expect enumor.CvmLifecycleStatus
+		failed []string
+	}{
+		{expect: enumor.CvmLifecycleRunning, failed: []string{"2", "3", "4"}},
+		{expect: enumor.CvmLifecycleStopped, failed: []string{"1", "3", "4"}},
+		// 期望 terminated 时已不存在的主机视为通过
+		{expect: enumor.CvmLifecycleTerminated, failed: []string{"1", "2"}},
+	}
+
+	for _, c := range cases {
+		got := RollingStatusFailedIDs(ids, cvms, c.expect)
+		if !reflect.DeepEqual(got, c.failed) {
+			t.Errorf("expect %s failed ids %v, got: %v", c.expect, c.failed, got)
+		}
+	}
+}
+
+func TestRollingLbTargetHealthy(t *testing.T) {
+	healthy := &corelb.TCloudHealthCheckInfo{UnHealthNum: converter.ValToPtr(int64(0))}
+	unhealthy := &corelb.TCloudHealthCheckInfo{UnHealthNum: converter.ValToPtr(int64(1))}
+	resp := &hclb.TCloudTargetHealthResp{
+		Details: []hclb.TCloudTargetHealthResult{{
+			CloudLbID: "lb-1",
+			Listeners: []*hclb.TCloudTargetHealthLblResult{
+				{CloudLblID: "lbl-healthy", HealthCheck: healthy},
+				{CloudLblID: "lbl-unhealthy", HealthCheck: unhealthy},
+				{CloudLblID: "lbl-no-check"},
+				{
+					CloudLblID: "lbl-7",
+					Rules: []*hclb.TCloudTargetHealthRuleResult{
+						{CloudRuleID: "rule-healthy", HealthCheck: healthy},
+						{CloudRuleID: "rule-unhealthy", HealthCheck: unhealthy},
+					},
+				},
+				nil,
+			},
+		}},
+	}
+
+	cases := []struct {
+		name    string
+		resp    *hclb.TCloudTargetHealthResp
+		lblIDs  []string
+		ruleIDs []string
+		expect  bool
+	}{
+		{name: "healthy listener", resp: resp, lblIDs: []string{"lbl-healthy"}, expect: true},
+		{name: "listener without health check", resp: resp, lblIDs: []string{"lbl-no-check"}, expect: true},
+		{name: "unhealthy listener", resp: resp, lblIDs: []string{"lbl-healthy", "lbl-unhealthy"}, expect: false},
+		{name: "healthy rule", resp: resp, ruleIDs: []string{"rule-healthy"}, expect: true},
+		{name: "unhealthy rule", resp: resp, lblIDs: []string{"lbl-healthy"}, ruleIDs: []string{"rule-unhealthy"},
+			expect: false},
+		{name: "not matched", resp: resp, lblIDs: []string{"lbl-other"}, ruleIDs: []string{"rule-other"},
+			expect: false},
+		{name: "nil response", resp: nil, lblIDs: []string{"lbl-healthy"}, expect: false},
+	}
+
+	for _, c := range cases {
+		if got := RollingLbTargetHealthy(c.resp, c.lblIDs, c.ruleIDs); got != c.expect {
+			t.Errorf("%s: expect healthy %v, got: %v", c.name, c.expect, got)
+		}
+	}
+}
+
+func TestRollingCheckWait(t *testing.T) {
+	cases := []struct {
+		elapsed  time.Duration
+		deadline time.Duration
+		expect   time.Duration
+	}{
+		{elapsed: 0, deadline: 0, expect: 0},
+		{elapsed: 30 * time.Second, deadline: 30 * time.Second, expect: 0},
+		{elapsed: 40 * time.Second, deadline: 30 * time.Second, expect: 0},
+		{elapsed: 0, deadline: time.Hour, expect: rollingGatePollInterval},
+		{elapsed: 27 * time.Second, deadline: 30 * time.Second, expect: 3 * time.Second},
+	}
+
+	for _, c := range cases {
+		if got := RollingCheckWait(c.elapsed, c.deadline); got != c.expect {
+			t.Errorf("elapsed %s deadline %s expect wait %s, got: %s", c.elapsed, c.deadline, c.expect, got)
+		}
+	}
+}
+
+func TestRollingCvmGateOptionValidate(t *testing.T) {
+	base := RollingCvmGateOption{
+		Batch:  1,
+		CvmIDs: []string{"1"},
+		Gate:   RollingGate{Type: enumor.RollingGateCvmStatus, ExpectStatus: enumor.CvmLifecycleRunning},
+	}
+
+	longPause := base
+	longPause.PauseSec = 600
+	longPause.CheckTimeoutSec = 600
+	if err := longPause.Validate(); err != nil {
+		t.Errorf("pause and check longer than task timeout should be valid, err: %v", err)
+	}
+
+	tooLong := base
+	tooLong.PauseSec = 3601
+	if err := tooLong.Validate(); err == nil {
+		t.Errorf("pause_sec over limit should be invalid")
+	}
+
+	lbGate := base
+	lbGate.Gate = RollingGate{Type: enumor.RollingGateLbTargetHealth}
+	if err := lbGate.Validate(); err == nil {
+		t.Errorf("lb_target_health gate without target_group_id should be invalid")
+	}
+
+	lbGate.Gate.TargetGroupID = "00000001"
+	if err := lbGate.Validate(); err != nil {
+		t.Errorf("lb_target_health gate with target_group_id should be valid, err: %v", err)
+	}
+}
@@ -76,6 +76,7 @@ func NewStopAction() StopAction {
 			AzureFunc: func(kt *kit.Kit, cli *hcservice.Client, opt *CvmOperationOption) error {
 				req := &hcprotocvm.AzureStopReq{
 					SkipShutdown: false,
+					Deallocate:   opt.Deallocate,
 				}
 				return cli.Azure.Cvm.StopCvm(kt, opt.IDs[0], req)
 			},
@@ -0,0 +1,161 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package actiondisksnapshot ...
+package actiondisksnapshot
+
+import (
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/core"
+	hcdisk "hcm/pkg/api/hc-service/disk"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/logs"
+)
+
+// CreateDiskSnapshotAction create disk snapshot.
+type CreateDiskSnapshotAction struct{}
+
+// CreateDiskSnapshotOption create disk snapshot option.
+type CreateDiskSnapshotOption struct {
+	Vendor                   enumor.Vendor `json:"vendor" validate:"required"`
+	hcdisk.SnapshotCreateReq `json:",inline"`
+}
+
+// Validate CreateDiskSnapshotOption.
+func (opt *CreateDiskSnapshotOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	return opt.SnapshotCreateReq.Validate()
+}
+
+// ParameterNew return request params.
+func (act CreateDiskSnapshotAction) ParameterNew() (params interface{}) {
+	return new(CreateDiskSnapshotOption)
+}
+
+// Name return action name.
+func (act CreateDiskSnapshotAction) Name() enumor.ActionName {
+	return enumor.ActionCreateDiskSnapshot
+}
+
+// Run create disk snapshot by hc-service, return the id of the created snapshot.
+func (act CreateDiskSnapshotAction) Run(kt run.ExecuteKit, params interface{}) (interface{}, error) {
+	opt, ok := params.(*CreateDiskSnapshotOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	cli := actcli.GetHCService()
+	var result *core.CreateResult
+	var err error
+	switch opt.Vendor {
+	case enumor.TCloud:
+		result, err = cli.TCloud.DiskSnapshot.Create(kt.Kit(), &opt.SnapshotCreateReq)
+	case enumor.Aws:
+		result, err = cli.Aws.DiskSnapshot.Create(kt.Kit(), &opt.SnapshotCreateReq)
+	case enumor.HuaWei:
+		result, err = cli.HuaWei.DiskSnapshot.Create(kt.Kit(), &opt.SnapshotCreateReq)
+	case enumor.Azure:
+		result, err = cli.Azure.DiskSnapshot.Create(kt.Kit(), &opt.SnapshotCreateReq)
+	case enumor.Gcp:
+		result, err = cli.Gcp.DiskSnapshot.Create(kt.Kit(), &opt.SnapshotCreateReq)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support create disk snapshot", opt.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("create disk snapshot failed, err: %v, vendor: %s, opt: %+v, rid: %s", err, opt.Vendor, opt,
+			kt.Kit().Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteDiskSnapshotAction delete disk snapshots.
+type DeleteDiskSnapshotAction struct{}
+
+// DeleteDiskSnapshotOption delete disk snapshot option.
+type DeleteDiskSnapshotOption struct {
+	Vendor                   enumor.Vendor `json:"vendor" validate:"required"`
+	hcdisk.SnapshotDeleteReq `json:",inline"`
+}
+
+// Validate DeleteDiskSnapshotOption.
+func (opt *DeleteDiskSnapshotOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	return opt.SnapshotDeleteReq.Validate()
+}
+
+// ParameterNew return request params.
+func (act DeleteDiskSnapshotAction) ParameterNew() (params interface{}) {
+	return new(DeleteDiskSnapshotOption)
+}
+
+// Name return action name.
+func (act DeleteDiskSnapshotAction) Name() enumor.ActionName {
+	return enumor.ActionDeleteDiskSnapshot
+}
+
+// Run delete disk snapshots by hc-service.
+func (act DeleteDiskSnapshotAction) Run(kt run.ExecuteKit, params interface{}) (interface{}, error) {
+	opt, ok := params.(*DeleteDiskSnapshotOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	cli := actcli.GetHCService()
+	var err error
+	switch opt.Vendor {
+	case enumor.TCloud:
+		err = cli.TCloud.DiskSnapshot.Delete(kt.Kit(), &opt.SnapshotDeleteReq)
+	case enumor.Aws:
+		err = cli.Aws.DiskSnapshot.Delete(kt.Kit(), &opt.SnapshotDeleteReq)
+	case enumor.HuaWei:
+		err = cli.HuaWei.DiskSnapshot.Delete(kt.Kit(), &opt.SnapshotDeleteReq)
+	case enumor.Azure:
+		err = cli.Azure.DiskSnapshot.Delete(kt.Kit(), &opt.SnapshotDeleteReq)
+	case enumor.Gcp:
+		err = cli.Gcp.DiskSnapshot.Delete(kt.Kit(), &opt.SnapshotDeleteReq)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support delete disk snapshot", opt.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("delete disk snapshot failed, err: %v, vendor: %s, opt: %+v, rid: %s", err, opt.Vendor, opt,
+			kt.Kit().Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,222 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package actiondisk ...
+package actiondisk
+
+import (
+	"fmt"
+	"path"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	actioncvm "hcm/cmd/task-server/logics/action/cvm"
+	typedisk "hcm/pkg/adaptor/types/disk"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	hcdisk "hcm/pkg/api/hc-service/disk"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+var _ action.Action = new(ResizeDiskAction)
+var _ action.ParameterAction = new(ResizeDiskAction)
+
+// ResizeDiskAction resize disk action.
+// 指定 StartOption 时，无论扩容成功与否都会重新开机，避免扩容前关机的主机在扩容失败后一直处于关机状态。
+type ResizeDiskAction struct{}
+
+// ResizeDiskOption resize disk option.
+type ResizeDiskOption struct {
+	Vendor               enumor.Vendor `json:"vendor" validate:"required"`
+	hcdisk.DiskResizeReq `json:",inline"`
+	// StartOption 扩容后重新开机的参数，为空时不开机
+	StartOption *actioncvm.CvmOperationOption `json:"start_option,omitempty" validate:"omitempty"`
+}
+
+// Validate ResizeDiskOption.
+func (opt *ResizeDiskOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if opt.StartOption != nil {
+		if err := opt.StartOption.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return opt.DiskResizeReq.Validate()
+}
+
+// ResizeDiskResult 扩容失败或重新开机失败时的任务结果，分别记录两个步骤的错误
+type ResizeDiskResult struct {
+	ResizeError string `json:"resize_error,omitempty"`
+	StartError  string `json:"start_error,omitempty"`
+}
+
+// ParameterNew return request params.
+func (act ResizeDiskAction) ParameterNew() (params interface{}) {
+	return new(ResizeDiskOption)
+}
+
+// Name return action name.
+func (act ResizeDiskAction) Name() enumor.ActionName {
+	return enumor.ActionResizeDisk
+}
+
+// Run resize disk by hc-service, and start cvm after resize if start option is set.
+func (act ResizeDiskAction) Run(kt run.ExecuteKit, params interface{}) (interface{}, error) {
+	opt, ok := params.(*ResizeDiskOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	resizeErr := act.resize(kt, opt)
+	if opt.StartOption == nil {
+		return nil, resizeErr
+	}
+
+	_, startErr := actioncvm.NewStartAction().Run(kt, opt.StartOption)
+	if startErr != nil {
+		logs.Errorf("start cvm after resize disk failed, err: %v, resize err: %v, disk: %s, cvm: %v, rid: %s",
+			startErr, resizeErr, opt.DiskID, opt.StartOption.IDs, kt.Kit().Rid)
+	}
+
+	result, err := resizeStartResult(resizeErr, startErr)
+	if err != nil {
+		return result, err
+	}
+
+	return nil, nil
+}
+
+func (act ResizeDiskAction) resize(kt run.ExecuteKit, opt *ResizeDiskOption) error {
+	cli := actcli.GetHCService()
+	var err error
+	switch opt.Vendor {
+	case enumor.TCloud:
+		err = cli.TCloud.Disk.ResizeDisk(kt.Kit(), &opt.DiskResizeReq)
+	case enumor.Aws:
+		err = cli.Aws.Disk.ResizeDisk(kt.Kit(), &opt.DiskResizeReq)
+	case enumor.HuaWei:
+		err = cli.HuaWei.Disk.ResizeDisk(kt.Kit(), &opt.DiskResizeReq)
+	case enumor.Azure:
+		err = cli.Azure.Disk.ResizeDisk(kt.Kit(), &opt.DiskResizeReq)
+	case enumor.Gcp:
+		err = cli.Gcp.Disk.ResizeDisk(kt.Kit(), &opt.DiskResizeReq)
+	default:
+		return errf.Newf(errf.InvalidParameter, "vendor: %s not support resize disk", opt.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("resize disk failed, err: %v, vendor: %s, opt: %+v, rid: %s", err, opt.Vendor, opt,
+			kt.Kit().Rid)
+		return err
+	}
+
+	return nil
+}
+
+// resizeStartResult 合并扩容与重新开机的结果，任一步骤失败时任务失败，并在结果中分别返回两个步骤的错误
+func resizeStartResult(resizeErr, startErr error) (*ResizeDiskResult, error) {
+	if resizeErr == nil && startErr == nil {
+		return nil, nil
+	}
+
+	result := new(ResizeDiskResult)
+	if resizeErr != nil {
+		result.ResizeError = resizeErr.Error()
+	}
+	if startErr != nil {
+		result.StartError = startErr.Error()
+	}
+
+	switch {
+	case startErr == nil:
+		return result, fmt.Errorf("resize disk failed and cvm has been restarted, err: %v", resizeErr)
+	case resizeErr == nil:
+		return result, fmt.Errorf("disk has been resized but start cvm failed, err: %v", startErr)
+	default:
+		return result, fmt.Errorf("resize disk failed, err: %v, and start cvm failed, err: %v", resizeErr,
+			startErr)
+	}
+}
+
+// offlineResizeDiskTypes 挂载在运行中的主机上时不支持在线扩容的云盘类型，Gcp 云盘类型取 disk_type 的最后一段。
+// Azure 托管磁盘需要虚拟机处于解除分配状态才能扩容，不区分云盘类型
+var offlineResizeDiskTypes = map[enumor.Vendor][]string{
+	enumor.Gcp:    {"hyperdisk-ml"},
+	enumor.HuaWei: {typedisk.HuaWeiDiskTypeEnum.SATA},
+}
+
+// NeedStopCvmBeforeResize 判断挂载在运行中的主机上的云盘扩容前是否需要关机
+func NeedStopCvmBeforeResize(disk *coredisk.BaseDisk) bool {
+	vendor := enumor.Vendor(disk.Vendor)
+	switch vendor {
+	case enumor.Azure:
+		return true
+	case enumor.Gcp, enumor.HuaWei:
+		return slice.IsItemInSlice(offlineResizeDiskTypes[vendor], path.Base(disk.DiskType))
+	default:
+		return false
+	}
+}
+
+// BuildResizeDiskTasks 构建扩容云盘任务，cvm 为云盘挂载的主机，未挂载时为空。
+// 云盘挂载在运行中的主机上且不支持在线扩容时，需要先关机，扩容任务无论成功与否都会重新开机，其他情况直接扩容
+func BuildResizeDiskTasks(disk *coredisk.BaseDisk, diskSize uint64, cvm *corecvm.BaseCvm) (
+	[]ts.CustomFlowTask, error) {
+
+	resizeOpt := &ResizeDiskOption{
+		Vendor:        enumor.Vendor(disk.Vendor),
+		DiskResizeReq: hcdisk.DiskResizeReq{DiskID: disk.ID, DiskSize: diskSize},
+	}
+	if cvm == nil || cvm.LifecycleStatus != enumor.CvmLifecycleRunning || !NeedStopCvmBeforeResize(disk) {
+		return []ts.CustomFlowTask{{ActionID: "1", ActionName: enumor.ActionResizeDisk, Params: resizeOpt}}, nil
+	}
+
+	basicInfoMap := map[string]types.CloudResourceBasicInfo{
+		cvm.ID: {ID: cvm.ID, Vendor: cvm.Vendor, AccountID: cvm.AccountID, Region: cvm.Region},
+	}
+	options, err := actioncvm.BuildOperationOptions([]string{cvm.ID}, basicInfoMap)
+	if err != nil {
+		return nil, err
+	}
+
+	resizeOpt.StartOption = &options[0]
+	stopOpt := options[0]
+	// Azure 托管磁盘需要虚拟机解除分配后才能扩容
+	stopOpt.Deallocate = stopOpt.Vendor == enumor.Azure
+	tasks := []ts.CustomFlowTask{
+		{ActionID: "1", ActionName: enumor.ActionStopCvm, Params: stopOpt},
+		{ActionID: "2", ActionName: enumor.ActionResizeDisk, Params: resizeOpt, DependOn: []action.ActIDType{"1"}},
+	}
+
+	return tasks, nil
+}
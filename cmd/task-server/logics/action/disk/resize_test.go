@@ -0,0 +1,108 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actiondisk
+
+import (
+	"errors"
+	"testing"
+
+	actioncvm "hcm/cmd/task-server/logics/action/cvm"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestBuildResizeDiskTasks(t *testing.T) {
+	runningCvm := func(vendor enumor.Vendor) *corecvm.BaseCvm {
+		return &corecvm.BaseCvm{ID: "cvm-1", Vendor: vendor, AccountID: "a1", Region: "r1",
+			LifecycleStatus: enumor.CvmLifecycleRunning}
+	}
+	stoppedCvm := runningCvm(enumor.Azure)
+	stoppedCvm.LifecycleStatus = enumor.CvmLifecycleStopped
+
+	cases := []struct {
+		name       string
+		disk       *coredisk.BaseDisk
+		cvm        *corecvm.BaseCvm
+		expectStop bool
+	}{
+		{name: "detached", disk: &coredisk.BaseDisk{ID: "d1", Vendor: "azure"}, cvm: nil},
+		{name: "azure on stopped cvm", disk: &coredisk.BaseDisk{ID: "d1", Vendor: "azure"}, cvm: stoppedCvm},
+		{name: "azure on running cvm", disk: &coredisk.BaseDisk{ID: "d1", Vendor: "azure"},
+			cvm: runningCvm(enumor.Azure), expectStop: true},
+		{name: "tcloud online resize", disk: &coredisk.BaseDisk{ID: "d1", Vendor: "tcloud", DiskType: "CLOUD_SSD"},
+			cvm: runningCvm(enumor.TCloud)},
+		{name: "gcp pd-ssd online resize", disk: &coredisk.BaseDisk{ID: "d1", Vendor: "gcp",
+			DiskType: "https://www.googleapis.com/compute/v1/projects/p/zones/z/diskTypes/pd-ssd"},
+			cvm: runningCvm(enumor.Gcp)},
+		{name: "gcp hyperdisk-ml", disk: &coredisk.BaseDisk{ID: "d1", Vendor: "gcp",
+			DiskType: "https://www.googleapis.com/compute/v1/projects/p/zones/z/diskTypes/hyperdisk-ml"},
+			cvm: runningCvm(enumor.Gcp), expectStop: true},
+		{name: "huawei sata", disk: &coredisk.BaseDisk{ID: "d1", Vendor: "huawei", DiskType: "SATA"},
+			cvm: runningCvm(enumor.HuaWei), expectStop: true},
+	}
+
+	for _, c := range cases {
+		tasks, err := BuildResizeDiskTasks(c.disk, 100, c.cvm)
+		if err != nil {
+			t.Errorf("%s: build resize disk tasks failed, err: %v", c.name, err)
+			continue
+		}
+
+		if !c.expectStop {
+			if len(tasks) != 1 || tasks[0].ActionName != enumor.ActionResizeDisk ||
+				tasks[0].Params.(*ResizeDiskOption).StartOption != nil {
+				t.Errorf("%s: expect single resize disk task without start option, got: %+v", c.name, tasks)
+			}
+			continue
+		}
+
+		if len(tasks) != 2 || tasks[0].ActionName != enumor.ActionStopCvm ||
+			tasks[1].ActionName != enumor.ActionResizeDisk {
+			t.Errorf("%s: expect stop -> resize tasks, got: %+v", c.name, tasks)
+			continue
+		}
+		stopOpt := tasks[0].Params.(actioncvm.CvmOperationOption)
+		if stopOpt.Deallocate != (c.cvm.Vendor == enumor.Azure) {
+			t.Errorf("%s: expect deallocate: %v, got: %v", c.name, c.cvm.Vendor == enumor.Azure, stopOpt.Deallocate)
+		}
+		resizeOpt := tasks[1].Params.(*ResizeDiskOption)
+		if resizeOpt.StartOption == nil || resizeOpt.StartOption.IDs[0] != c.cvm.ID ||
+			resizeOpt.StartOption.Deallocate {
+			t.Errorf("%s: expect start option of cvm %s, got: %+v", c.name, c.cvm.ID, resizeOpt.StartOption)
+		}
+	}
+}
+
+func TestResizeStartResult(t *testing.T) {
+	if result, err := resizeStartResult(nil, nil); result != nil || err != nil {
+		t.Errorf("expect no result and error when both succeed, got: %+v, %v", result, err)
+	}
+
+	result, err := resizeStartResult(errors.New("quota exceeded"), nil)
+	if err == nil || result.ResizeError != "quota exceeded" || result.StartError != "" {
+		t.Errorf("expect resize error only, got: %+v, %v", result, err)
+	}
+
+	result, err = resizeStartResult(nil, errors.New("start timeout"))
+	if err == nil || result.ResizeError != "" || result.StartError != "start timeout" {
+		t.Errorf("expect start error only, got: %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package actionimage ...
+package actionimage
+
+import (
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/core"
+	hcimage "hcm/pkg/api/hc-service/image"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/logs"
+)
+
+// CreatePrivateImageAction create private image from cvm.
+type CreatePrivateImageAction struct{}
+
+// CreatePrivateImageOption create private image option.
+type CreatePrivateImageOption struct {
+	Vendor                        enumor.Vendor `json:"vendor" validate:"required"`
+	hcimage.PrivateImageCreateReq `json:",inline"`
+}
+
+// Validate CreatePrivateImageOption.
+func (opt *CreatePrivateImageOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	return opt.PrivateImageCreateReq.Validate()
+}
+
+// ParameterNew return request params.
+func (act CreatePrivateImageAction) ParameterNew() (params interface{}) {
+	return new(CreatePrivateImageOption)
+}
+
+// Name return action name.
+func (act CreatePrivateImageAction) Name() enumor.ActionName {
+	return enumor.ActionCreatePrivateImage
+}
+
+// Run create private image by hc-service, return the id of the created private image.
+func (act CreatePrivateImageAction) Run(kt run.ExecuteKit, params interface{}) (interface{}, error) {
+	opt, ok := params.(*CreatePrivateImageOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	cli := actcli.GetHCService()
+	var result *core.CreateResult
+	var err error
+	switch opt.Vendor {
+	case enumor.TCloud:
+		result, err = cli.TCloud.PrivateImage.Create(kt.Kit(), &opt.PrivateImageCreateReq)
+	case enumor.HuaWei:
+		result, err = cli.HuaWei.PrivateImage.Create(kt.Kit(), &opt.PrivateImageCreateReq)
+	case enumor.Aws:
+		result, err = cli.Aws.PrivateImage.Create(kt.Kit(), &opt.PrivateImageCreateReq)
+	case enumor.Gcp:
+		result, err = cli.Gcp.PrivateImage.Create(kt.Kit(), &opt.PrivateImageCreateReq)
+	case enumor.Azure:
+		result, err = cli.Azure.PrivateImage.Create(kt.Kit(), &opt.PrivateImageCreateReq)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support create private image", opt.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("create private image failed, err: %v, vendor: %s, opt: %+v, rid: %s", err, opt.Vendor, opt,
+			kt.Kit().Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CopyPrivateImageAction copy private image to other regions.
+type CopyPrivateImageAction struct{}
+
+// CopyPrivateImageOption copy private image option.
+type CopyPrivateImageOption struct {
+	Vendor                      enumor.Vendor `json:"vendor" validate:"required"`
+	hcimage.PrivateImageCopyReq `json:",inline"`
+}
+
+// Validate CopyPrivateImageOption.
+func (opt *CopyPrivateImageOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	return opt.PrivateImageCopyReq.Validate()
+}
+
+// ParameterNew return request params.
+func (act CopyPrivateImageAction) ParameterNew() (params interface{}) {
+	return new(CopyPrivateImageOption)
+}
+
+// Name return action name.
+func (act CopyPrivateImageAction) Name() enumor.ActionName {
+	return enumor.ActionCopyPrivateImage
+}
+
+// Run copy private image by hc-service, return the ids of the copied private images.
+func (act CopyPrivateImageAction) Run(kt run.ExecuteKit, params interface{}) (interface{}, error) {
+	opt, ok := params.(*CopyPrivateImageOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	cli := actcli.GetHCService()
+	var result *core.BatchCreateResult
+	var err error
+	switch opt.Vendor {
+	case enumor.TCloud:
+		result, err = cli.TCloud.PrivateImage.Copy(kt.Kit(), &opt.PrivateImageCopyReq)
+	case enumor.HuaWei:
+		result, err = cli.HuaWei.PrivateImage.Copy(kt.Kit(), &opt.PrivateImageCopyReq)
+	case enumor.Aws:
+		result, err = cli.Aws.PrivateImage.Copy(kt.Kit(), &opt.PrivateImageCopyReq)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support copy private image", opt.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("copy private image failed, err: %v, vendor: %s, opt: %+v, rid: %s", err, opt.Vendor, opt,
+			kt.Kit().Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
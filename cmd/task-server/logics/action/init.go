@@ -28,9 +28,14 @@ import (
 	actionrootsummary "hcm/cmd/task-server/logics/action/bill/rootsummary"
 	actcli "hcm/cmd/task-server/logics/action/cli"
 	actioncvm "hcm/cmd/task-server/logics/action/cvm"
+	actiondisk "hcm/cmd/task-server/logics/action/disk"
+	actiondisksnapshot "hcm/cmd/task-server/logics/action/disk-snapshot"
 	actioneip "hcm/cmd/task-server/logics/action/eip"
 	actionfirewall "hcm/cmd/task-server/logics/action/firewall"
+	actionimage "hcm/cmd/task-server/logics/action/image"
 	actionlb "hcm/cmd/task-server/logics/action/load-balancer"
+	actionrenewal "hcm/cmd/task-server/logics/action/renewal"
+	actionrestag "hcm/cmd/task-server/logics/action/resource-tag"
 	actionsg "hcm/cmd/task-server/logics/action/security-group"
 	actionsubnet "hcm/cmd/task-server/logics/action/subnet"
 	actionflow "hcm/cmd/task-server/logics/flow"
@@ -54,13 +59,24 @@ func register() {
 	action.RegisterAction(actioncvm.NewDeleteAction())
 	action.RegisterAction(actioncvm.CreateCvmAction{})
 	action.RegisterAction(actioncvm.AssignCvmAction{})
+	action.RegisterAction(actioncvm.RollingCvmGateAction{})
+	action.RegisterAction(actioncvm.ResizeCvmAction{})
 
 	action.RegisterAction(actionfirewall.DeleteAction{})
 
 	action.RegisterAction(actionsubnet.DeleteAction{})
 	action.RegisterAction(actionsg.DeleteSgAction{})
 	action.RegisterAction(actionsg.CreateHuaweiSGRuleAction{})
+	action.RegisterAction(actionsg.ApplySGRuleTplAction{})
 	action.RegisterAction(actioneip.DeleteEIPAction{})
+	action.RegisterAction(actionrestag.AddResTagsAction{})
+	action.RegisterAction(actionrenewal.RenewPrepaidResAction{})
+	action.RegisterAction(actionrenewal.SetPrepaidResAutoRenewAction{})
+	action.RegisterAction(actionimage.CreatePrivateImageAction{})
+	action.RegisterAction(actionimage.CopyPrivateImageAction{})
+	action.RegisterAction(actiondisk.ResizeDiskAction{})
+	action.RegisterAction(actiondisksnapshot.CreateDiskSnapshotAction{})
+	action.RegisterAction(actiondisksnapshot.DeleteDiskSnapshotAction{})
 
 	action.RegisterAction(actionlb.AddTargetToGroupAction{})
 	action.RegisterAction(actionflow.LoadBalancerOperateWatchAction{})
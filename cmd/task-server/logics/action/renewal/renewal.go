@@ -0,0 +1,190 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package actionrenewal ...
+package actionrenewal
+
+import (
+	"fmt"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	hcrenewal "hcm/pkg/api/hc-service/renewal"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/logs"
+)
+
+// RenewPrepaidResAction renew prepaid resources.
+type RenewPrepaidResAction struct{}
+
+// RenewPrepaidResOption renew prepaid resources option.
+type RenewPrepaidResOption struct {
+	Vendor    enumor.Vendor            `json:"vendor" validate:"required"`
+	AccountID string                   `json:"account_id" validate:"required"`
+	ResType   enumor.CloudResourceType `json:"res_type" validate:"required"`
+	// ResIDs 资源ID，资源类型为带宽包时为带宽包的云上ID
+	ResIDs []string `json:"res_ids" validate:"required,min=1"`
+	// Period 续费时长，单位：月
+	Period int64 `json:"period" validate:"required,min=1,max=36"`
+}
+
+// Validate RenewPrepaidResOption.
+func (opt *RenewPrepaidResOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if len(opt.ResIDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("res_ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return nil
+}
+
+// ParameterNew return request params.
+func (act RenewPrepaidResAction) ParameterNew() (params interface{}) {
+	return new(RenewPrepaidResOption)
+}
+
+// Name return action name.
+func (act RenewPrepaidResAction) Name() enumor.ActionName {
+	return enumor.ActionRenewPrepaidRes
+}
+
+// Run renew prepaid resources by hc-service.
+func (act RenewPrepaidResAction) Run(kt run.ExecuteKit, params interface{}) (interface{}, error) {
+	opt, ok := params.(*RenewPrepaidResOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	req := &hcrenewal.ResRenewReq{
+		AccountID: opt.AccountID,
+		ResType:   opt.ResType,
+		ResIDs:    opt.ResIDs,
+		Period:    opt.Period,
+	}
+	cli := actcli.GetHCService()
+	var result *hcrenewal.BatchResult
+	var err error
+	switch opt.Vendor {
+	case enumor.TCloud:
+		result, err = cli.TCloud.Renewal.Renew(kt.Kit(), req)
+	case enumor.HuaWei:
+		result, err = cli.HuaWei.Renewal.Renew(kt.Kit(), req)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support renew prepaid resources", opt.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("renew prepaid resources failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Kit().Rid)
+		return nil, err
+	}
+
+	return result, batchResultError(result)
+}
+
+// SetPrepaidResAutoRenewAction enable or disable auto renew of prepaid resources.
+type SetPrepaidResAutoRenewAction struct{}
+
+// SetPrepaidResAutoRenewOption set prepaid resources auto renew option.
+type SetPrepaidResAutoRenewOption struct {
+	Vendor    enumor.Vendor            `json:"vendor" validate:"required"`
+	AccountID string                   `json:"account_id" validate:"required"`
+	ResType   enumor.CloudResourceType `json:"res_type" validate:"required"`
+	// ResIDs 资源ID，资源类型为带宽包时为带宽包的云上ID
+	ResIDs    []string `json:"res_ids" validate:"required,min=1"`
+	AutoRenew bool     `json:"auto_renew"`
+}
+
+// Validate SetPrepaidResAutoRenewOption.
+func (opt *SetPrepaidResAutoRenewOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if len(opt.ResIDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("res_ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return nil
+}
+
+// ParameterNew return request params.
+func (act SetPrepaidResAutoRenewAction) ParameterNew() (params interface{}) {
+	return new(SetPrepaidResAutoRenewOption)
+}
+
+// Name return action name.
+func (act SetPrepaidResAutoRenewAction) Name() enumor.ActionName {
+	return enumor.ActionSetPrepaidResAutoRenew
+}
+
+// Run set auto renew of prepaid resources by hc-service.
+func (act SetPrepaidResAutoRenewAction) Run(kt run.ExecuteKit, params interface{}) (interface{}, error) {
+	opt, ok := params.(*SetPrepaidResAutoRenewOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	req := &hcrenewal.ResAutoRenewSetReq{
+		AccountID: opt.AccountID,
+		ResType:   opt.ResType,
+		ResIDs:    opt.ResIDs,
+		AutoRenew: opt.AutoRenew,
+	}
+	cli := actcli.GetHCService()
+	var result *hcrenewal.BatchResult
+	var err error
+	switch opt.Vendor {
+	case enumor.TCloud:
+		result, err = cli.TCloud.Renewal.SetAutoRenew(kt.Kit(), req)
+	case enumor.HuaWei:
+		result, err = cli.HuaWei.Renewal.SetAutoRenew(kt.Kit(), req)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support set auto renew", opt.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("set prepaid resources auto renew failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Kit().Rid)
+		return nil, err
+	}
+
+	return result, batchResultError(result)
+}
+
+// batchResultError 存在操作失败的资源时返回错误，任务结果中记录每个资源的操作结果。
+// 成功的资源已经在云上生效，任务不应重试，避免重复续费
+func batchResultError(result *hcrenewal.BatchResult) error {
+	if result == nil || len(result.FailedIDs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%d resources failed, success ids: %v, failed ids: %v, message: %s", len(result.FailedIDs),
+		result.SuccessIDs, result.FailedIDs, result.FailedMessage)
+}
@@ -0,0 +1,111 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package actionrestag ...
+package actionrestag
+
+import (
+	"fmt"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	hcrestag "hcm/pkg/api/hc-service/resource-tag"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/logs"
+)
+
+// AddResTagsAction add tags to resources.
+type AddResTagsAction struct{}
+
+// AddResTagsOption add resource tags option.
+type AddResTagsOption struct {
+	Vendor    enumor.Vendor            `json:"vendor" validate:"required"`
+	AccountID string                   `json:"account_id" validate:"required"`
+	ResType   enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResIDs    []string                 `json:"res_ids" validate:"required,min=1"`
+	Tags      map[string]string        `json:"tags" validate:"required,min=1"`
+}
+
+// Validate AddResTagsOption.
+func (opt *AddResTagsOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if len(opt.ResIDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("res_ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return nil
+}
+
+// ParameterNew return request params.
+func (act AddResTagsAction) ParameterNew() (params interface{}) {
+	return new(AddResTagsOption)
+}
+
+// Name return action name.
+func (act AddResTagsAction) Name() enumor.ActionName {
+	return enumor.ActionAddResourceTags
+}
+
+// Run add tags to resources by hc-service, the resource tags stored in hcm are updated together.
+func (act AddResTagsAction) Run(kt run.ExecuteKit, params interface{}) (interface{}, error) {
+	opt, ok := params.(*AddResTagsOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	req := &hcrestag.ResTagAddReq{
+		AccountID: opt.AccountID,
+		ResType:   opt.ResType,
+		ResIDs:    opt.ResIDs,
+		Tags:      opt.Tags,
+	}
+	cli := actcli.GetHCService()
+	var err error
+	switch opt.Vendor {
+	case enumor.TCloud:
+		err = cli.TCloud.ResourceTag.Add(kt.Kit(), req)
+	case enumor.Aws:
+		err = cli.Aws.ResourceTag.Add(kt.Kit(), req)
+	case enumor.HuaWei:
+		err = cli.HuaWei.ResourceTag.Add(kt.Kit(), req)
+	case enumor.Gcp:
+		err = cli.Gcp.ResourceTag.Add(kt.Kit(), req)
+	case enumor.Azure:
+		err = cli.Azure.ResourceTag.Add(kt.Kit(), req)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support add resource tags", opt.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("add resource tags failed, err: %v, vendor: %s, opt: %+v, rid: %s", err, opt.Vendor, opt,
+			kt.Kit().Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
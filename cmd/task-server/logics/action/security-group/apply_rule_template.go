@@ -0,0 +1,420 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actionsg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	hcproto "hcm/pkg/api/hc-service"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+var _ action.Action = new(ApplySGRuleTplAction)
+var _ action.ParameterAction = new(ApplySGRuleTplAction)
+
+// ApplySGRuleTplAction apply security group rule template to one target.
+type ApplySGRuleTplAction struct{}
+
+// ApplySGRuleTplOption define apply security group rule template option, rules are compiled into vendor rule create
+// requests by cloud-server, only the requests of the target vendor are set. azure rule priorities are assigned when
+// the task runs.
+type ApplySGRuleTplOption struct {
+	ApplyID string        `json:"apply_id" validate:"required"`
+	Vendor  enumor.Vendor `json:"vendor" validate:"required"`
+	// ResID 安全组ID，gcp为vpc ID
+	ResID  string                              `json:"res_id" validate:"required"`
+	TCloud []*hcproto.TCloudSGRuleCreateReq    `json:"tcloud,omitempty" validate:"omitempty"`
+	Aws    []*hcproto.AwsSGRuleCreateReq       `json:"aws,omitempty" validate:"omitempty"`
+	HuaWei []*hcproto.HuaWeiSGRuleCreateReq    `json:"huawei,omitempty" validate:"omitempty"`
+	Azure  []*hcproto.AzureSGRuleCreateReq     `json:"azure,omitempty" validate:"omitempty"`
+	Gcp    []*hcproto.GcpFirewallRuleCreateReq `json:"gcp,omitempty" validate:"omitempty"`
+}
+
+// Validate ApplySGRuleTplOption.
+func (opt *ApplySGRuleTplOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// ParameterNew return apply security group rule template params.
+func (act ApplySGRuleTplAction) ParameterNew() (params any) {
+	return new(ApplySGRuleTplOption)
+}
+
+// Name return action name.
+func (act ApplySGRuleTplAction) Name() enumor.ActionName {
+	return enumor.ActionApplySGRuleTemplate
+}
+
+// Run create the compiled rules on the target, and record the result to the apply record.
+func (act ApplySGRuleTplAction) Run(kt run.ExecuteKit, params any) (any, error) {
+	opt, ok := params.(*ApplySGRuleTplOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	runErr := createTplRules(kt.Kit(), opt)
+
+	update := dataproto.SGRuleTplApplyUpdate{ID: opt.ApplyID, State: enumor.SGRuleTplApplySuccess}
+	if runErr != nil {
+		logs.Errorf("apply security group rule template failed, err: %v, apply: %s, res: %s, rid: %s", runErr,
+			opt.ApplyID, opt.ResID, kt.Kit().Rid)
+		update.State = enumor.SGRuleTplApplyFailed
+		update.Reason = runErr.Error()
+		if len(update.Reason) > 1024 {
+			update.Reason = update.Reason[:1024]
+		}
+	}
+
+	req := &dataproto.SGRuleTplApplyBatchUpdateReq{Applies: []dataproto.SGRuleTplApplyUpdate{update}}
+	if err := actcli.GetDataService().Global.SGRuleTemplate.BatchUpdateApply(kt.Kit(), req); err != nil {
+		logs.Errorf("update security group rule template apply failed, err: %v, apply: %s, rid: %s", err,
+			opt.ApplyID, kt.Kit().Rid)
+		return nil, err
+	}
+
+	return nil, runErr
+}
+
+// createTplRules create the compiled rules which do not exist on the target, so that the task can be retried or
+// re-run without creating duplicate rules.
+func createTplRules(kt *kit.Kit, opt *ApplySGRuleTplOption) error {
+	hcCli := actcli.GetHCService()
+	switch opt.Vendor {
+	case enumor.TCloud:
+		existing, err := listTplTargetRules(kt, opt.Vendor, opt.ResID, func(page *core.BasePage) (
+			[]corecloud.TCloudSecurityGroupRule, error) {
+
+			req := &dataproto.TCloudSGRuleListReq{Filter: tools.AllExpression(), Page: page}
+			result, err := actcli.GetDataService().TCloud.SecurityGroup.ListSecurityGroupRule(kt.Ctx, kt.Header(),
+				req, opt.ResID)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		keys := make(map[string]struct{}, len(existing))
+		for _, one := range existing {
+			keys[tcloudRuleKey(one.Type, one.Protocol, one.Port, one.IPv4Cidr, one.IPv6Cidr, one.Action)] = struct{}{}
+		}
+
+		for _, req := range opt.TCloud {
+			notExists := func(ruleType enumor.SecurityGroupRuleType) func(hcproto.TCloudSGRuleCreate) bool {
+				return func(one hcproto.TCloudSGRuleCreate) bool {
+					_, exists := keys[tcloudRuleKey(ruleType, one.Protocol, one.Port, one.IPv4Cidr, one.IPv6Cidr,
+						one.Action)]
+					return !exists
+				}
+			}
+			req.IngressRuleSet = slice.Filter(req.IngressRuleSet, notExists(enumor.Ingress))
+			req.EgressRuleSet = slice.Filter(req.EgressRuleSet, notExists(enumor.Egress))
+			if len(req.IngressRuleSet) == 0 && len(req.EgressRuleSet) == 0 {
+				continue
+			}
+
+			if _, err = hcCli.TCloud.SecurityGroup.BatchCreateSecurityGroupRule(kt.Ctx, kt.Header(), opt.ResID,
+				req); err != nil {
+				return err
+			}
+		}
+
+	case enumor.Aws:
+		existing, err := listTplTargetRules(kt, opt.Vendor, opt.ResID, func(page *core.BasePage) (
+			[]corecloud.AwsSecurityGroupRule, error) {
+
+			req := &dataproto.AwsSGRuleListReq{Filter: tools.AllExpression(), Page: page}
+			result, err := actcli.GetDataService().Aws.SecurityGroup.ListSecurityGroupRule(kt.Ctx, kt.Header(),
+				req, opt.ResID)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		keys := make(map[string]struct{}, len(existing))
+		for _, one := range existing {
+			keys[awsRuleKey(one.Type, one.Protocol, one.FromPort, one.ToPort, one.IPv4Cidr, one.IPv6Cidr)] = struct{}{}
+		}
+
+		for _, req := range opt.Aws {
+			notExists := func(ruleType enumor.SecurityGroupRuleType) func(hcproto.AwsSGRuleCreate) bool {
+				return func(one hcproto.AwsSGRuleCreate) bool {
+					_, exists := keys[awsRuleKey(ruleType, one.Protocol, one.FromPort, one.ToPort, one.IPv4Cidr,
+						one.IPv6Cidr)]
+					return !exists
+				}
+			}
+			req.IngressRuleSet = slice.Filter(req.IngressRuleSet, notExists(enumor.Ingress))
+			req.EgressRuleSet = slice.Filter(req.EgressRuleSet, notExists(enumor.Egress))
+			if len(req.IngressRuleSet) == 0 && len(req.EgressRuleSet) == 0 {
+				continue
+			}
+
+			if _, err = hcCli.Aws.SecurityGroup.BatchCreateSecurityGroupRule(kt.Ctx, kt.Header(), opt.ResID,
+				req); err != nil {
+				return err
+			}
+		}
+
+	case enumor.HuaWei:
+		existing, err := listTplTargetRules(kt, opt.Vendor, opt.ResID, func(page *core.BasePage) (
+			[]corecloud.HuaWeiSecurityGroupRule, error) {
+
+			req := &dataproto.HuaWeiSGRuleListReq{Filter: tools.AllExpression(), Page: page}
+			result, err := actcli.GetDataService().HuaWei.SecurityGroup.ListSecurityGroupRule(kt.Ctx, kt.Header(),
+				req, opt.ResID)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		keys := make(map[string]struct{}, len(existing))
+		for _, one := range existing {
+			keys[huaweiRuleKey(one.Type, &one.Protocol, &one.Ethertype, &one.RemoteIPPrefix, &one.Port, &one.Action,
+				one.Priority)] = struct{}{}
+		}
+
+		for _, req := range opt.HuaWei {
+			ruleType, rule := enumor.Ingress, req.IngressRule
+			if req.EgressRule != nil {
+				ruleType, rule = enumor.Egress, req.EgressRule
+			}
+			if rule == nil {
+				continue
+			}
+
+			key := huaweiRuleKey(ruleType, rule.Protocol, rule.Ethertype, rule.RemoteIPPrefix, rule.Port, rule.Action,
+				rule.Priority)
+			if _, exists := keys[key]; exists {
+				continue
+			}
+
+			if _, err = hcCli.HuaWei.SecurityGroup.CreateSecurityGroupRule(kt, opt.ResID, req); err != nil {
+				return err
+			}
+		}
+
+	case enumor.Azure:
+		if err := createAzureTplRules(kt, opt); err != nil {
+			return err
+		}
+
+	case enumor.Gcp:
+		names := slice.Map(opt.Gcp, func(req *hcproto.GcpFirewallRuleCreateReq) string { return req.Name })
+		existing, err := listTplTargetRules(kt, opt.Vendor, opt.ResID, func(page *core.BasePage) (
+			[]corecloud.GcpFirewallRule, error) {
+
+			req := &dataproto.GcpFirewallRuleListReq{
+				Filter: tools.ExpressionAnd(tools.RuleEqual("vpc_id", opt.ResID), tools.RuleIn("name", names)),
+				Page:   page,
+			}
+			result, err := actcli.GetDataService().Gcp.Firewall.ListFirewallRule(kt.Ctx, kt.Header(), req)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		existNames := make(map[string]struct{}, len(existing))
+		for _, one := range existing {
+			existNames[one.Name] = struct{}{}
+		}
+
+		for _, req := range opt.Gcp {
+			if _, exists := existNames[req.Name]; exists {
+				continue
+			}
+
+			if _, err = hcCli.Gcp.Firewall.CreateFirewallRule(kt.Ctx, kt.Header(), req); err != nil {
+				return fmt.Errorf("create firewall rule %s failed, err: %v", req.Name, err)
+			}
+		}
+
+	default:
+		return fmt.Errorf("vendor: %s not support security group rule template", opt.Vendor)
+	}
+
+	return nil
+}
+
+const (
+	// azureMinPriority、azureMaxPriority 为azure安全组规则可以使用的优先级范围
+	azureMinPriority = 100
+	azureMaxPriority = 4096
+)
+
+// createAzureTplRules azure安全组规则名称和优先级在安全组内都不能重复，已存在同名（同一模版版本）的规则不再创建，
+// 其余规则按模版顺序排在下发时已有规则之后。
+func createAzureTplRules(kt *kit.Kit, opt *ApplySGRuleTplOption) error {
+	existing, err := listTplTargetRules(kt, opt.Vendor, opt.ResID, func(page *core.BasePage) (
+		[]corecloud.AzureSecurityGroupRule, error) {
+
+		req := &dataproto.AzureSGRuleListReq{Filter: tools.AllExpression(), Page: page}
+		result, err := actcli.GetDataService().Azure.SecurityGroup.ListSecurityGroupRule(kt.Ctx, kt.Header(), req,
+			opt.ResID)
+		if err != nil {
+			return nil, err
+		}
+		return result.Details, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, req := range opt.Azure {
+		req.IngressRuleSet = slice.Filter(req.IngressRuleSet, azureRuleNotExists(existing))
+		req.EgressRuleSet = slice.Filter(req.EgressRuleSet, azureRuleNotExists(existing))
+	}
+
+	if err = assignAzureTplPriority(existing, opt.Azure); err != nil {
+		return err
+	}
+
+	for _, req := range opt.Azure {
+		if len(req.IngressRuleSet) == 0 && len(req.EgressRuleSet) == 0 {
+			continue
+		}
+
+		if _, err = actcli.GetHCService().Azure.SecurityGroup.BatchCreateSecurityGroupRule(kt.Ctx, kt.Header(),
+			opt.ResID, req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func azureRuleNotExists(existing []corecloud.AzureSecurityGroupRule) func(hcproto.AzureSGRuleCreate) bool {
+	names := make(map[string]struct{}, len(existing))
+	for _, one := range existing {
+		names[one.Name] = struct{}{}
+	}
+
+	return func(one hcproto.AzureSGRuleCreate) bool {
+		_, exists := names[one.Name]
+		return !exists
+	}
+}
+
+// assignAzureTplPriority assign the priorities of the rules to be created after the existing rules of same type.
+func assignAzureTplPriority(existing []corecloud.AzureSecurityGroupRule, reqs []*hcproto.AzureSGRuleCreateReq) error {
+	next := map[enumor.SecurityGroupRuleType]int32{enumor.Ingress: azureMinPriority, enumor.Egress: azureMinPriority}
+	for _, one := range existing {
+		if one.Priority <= azureMaxPriority && one.Priority >= next[one.Type] {
+			next[one.Type] = one.Priority + 1
+		}
+	}
+
+	for _, req := range reqs {
+		for _, rules := range [][]hcproto.AzureSGRuleCreate{req.IngressRuleSet, req.EgressRuleSet} {
+			for idx := range rules {
+				if next[rules[idx].Type] > azureMaxPriority {
+					return fmt.Errorf("no available azure rule priority for rule %s", rules[idx].Name)
+				}
+				rules[idx].Priority = next[rules[idx].Type]
+				next[rules[idx].Type]++
+			}
+		}
+	}
+
+	return nil
+}
+
+func tcloudRuleKey(ruleType enumor.SecurityGroupRuleType, protocol, port, ipv4, ipv6 *string, action string) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%s", ruleType, strings.ToUpper(converter.PtrToVal(protocol)),
+		strings.ToUpper(converter.PtrToVal(port)), converter.PtrToVal(ipv4), converter.PtrToVal(ipv6),
+		strings.ToUpper(action))
+}
+
+func awsRuleKey(ruleType enumor.SecurityGroupRuleType, protocol *string, from, to *int64, ipv4, ipv6 *string) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%s", ruleType, converter.PtrToVal(protocol), ptrIntToStr(from),
+		ptrIntToStr(to), converter.PtrToVal(ipv4), converter.PtrToVal(ipv6))
+}
+
+func huaweiRuleKey(ruleType enumor.SecurityGroupRuleType, protocol, ethertype, remote, port, action *string,
+	priority int64) string {
+
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%s/%d", ruleType, converter.PtrToVal(protocol),
+		converter.PtrToVal(ethertype), converter.PtrToVal(remote), converter.PtrToVal(port),
+		converter.PtrToVal(action), priority)
+}
+
+func ptrIntToStr(val *int64) string {
+	if val == nil {
+		return ""
+	}
+	return strconv.FormatInt(*val, 10)
+}
+
+// listTplTargetRules list all rules of the target page by page.
+func listTplTargetRules[T any](kt *kit.Kit, vendor enumor.Vendor, resID string,
+	list func(page *core.BasePage) ([]T, error)) ([]T, error) {
+
+	page := core.NewDefaultBasePage()
+	result := make([]T, 0)
+	for {
+		details, err := list(page)
+		if err != nil {
+			logs.Errorf("list %s rules of rule template target failed, err: %v, res: %s, rid: %s", vendor, err, resID,
+				kt.Rid)
+			return nil, err
+		}
+
+		result = append(result, details...)
+		if uint(len(details)) < page.Limit {
+			break
+		}
+		page.Start += uint32(page.Limit)
+	}
+
+	return result, nil
+}
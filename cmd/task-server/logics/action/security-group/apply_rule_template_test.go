@@ -0,0 +1,96 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actionsg
+
+import (
+	"testing"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	hcproto "hcm/pkg/api/hc-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/slice"
+)
+
+func TestAssignAzureTplPriority(t *testing.T) {
+	existing := []corecloud.AzureSecurityGroupRule{
+		{Name: "custom-in", Type: enumor.Ingress, Priority: 300},
+		{Name: "hcm-tpl-1-v1-0-0", Type: enumor.Ingress, Priority: 301},
+		{Name: "custom-out", Type: enumor.Egress, Priority: 50},
+		// azure 默认规则的优先级超出可用范围，不影响分配
+		{Name: "AllowVnetInBound", Type: enumor.Ingress, Priority: 65000},
+	}
+
+	reqs := []*hcproto.AzureSGRuleCreateReq{
+		{IngressRuleSet: []hcproto.AzureSGRuleCreate{
+			{Name: "hcm-tpl-1-v1-0-0", Type: enumor.Ingress},
+			{Name: "hcm-tpl-1-v1-1-0", Type: enumor.Ingress},
+			{Name: "hcm-tpl-1-v1-2-0", Type: enumor.Ingress},
+		}},
+		{EgressRuleSet: []hcproto.AzureSGRuleCreate{{Name: "hcm-tpl-1-v1-3-0", Type: enumor.Egress}}},
+	}
+
+	// 重试时已创建的规则不再创建
+	for _, req := range reqs {
+		req.IngressRuleSet = slice.Filter(req.IngressRuleSet, azureRuleNotExists(existing))
+		req.EgressRuleSet = slice.Filter(req.EgressRuleSet, azureRuleNotExists(existing))
+	}
+	if len(reqs[0].IngressRuleSet) != 2 || reqs[0].IngressRuleSet[0].Name != "hcm-tpl-1-v1-1-0" {
+		t.Fatalf("existing rule should be skipped, got: %+v", reqs[0].IngressRuleSet)
+	}
+
+	if err := assignAzureTplPriority(existing, reqs); err != nil {
+		t.Fatalf("assign azure priority failed, err: %v", err)
+	}
+
+	expects := map[string]int32{"hcm-tpl-1-v1-1-0": 302, "hcm-tpl-1-v1-2-0": 303, "hcm-tpl-1-v1-3-0": 100}
+	for _, req := range reqs {
+		for _, one := range append(req.IngressRuleSet, req.EgressRuleSet...) {
+			if one.Priority != expects[one.Name] {
+				t.Errorf("rule %s expect priority: %d, got: %d", one.Name, expects[one.Name], one.Priority)
+			}
+		}
+	}
+
+	full := []corecloud.AzureSecurityGroupRule{{Name: "last", Type: enumor.Ingress, Priority: azureMaxPriority}}
+	overflow := []*hcproto.AzureSGRuleCreateReq{{IngressRuleSet: []hcproto.AzureSGRuleCreate{
+		{Name: "hcm-tpl-1-v1-0-0", Type: enumor.Ingress}}}}
+	if err := assignAzureTplPriority(full, overflow); err == nil {
+		t.Errorf("assign priority should fail when no priority is available")
+	}
+}
+
+func TestRuleKey(t *testing.T) {
+	protocol, port, cidr := "tcp", "22", "10.0.0.0/8"
+	upperProtocol, upperPort := "TCP", "22"
+	if tcloudRuleKey(enumor.Ingress, &protocol, &port, &cidr, nil, "accept") !=
+		tcloudRuleKey(enumor.Ingress, &upperProtocol, &upperPort, &cidr, nil, "ACCEPT") {
+		t.Errorf("tcloud rule key should ignore case")
+	}
+	if tcloudRuleKey(enumor.Ingress, &protocol, &port, &cidr, nil, "ACCEPT") ==
+		tcloudRuleKey(enumor.Egress, &protocol, &port, &cidr, nil, "ACCEPT") {
+		t.Errorf("tcloud rule key should distinguish rule type")
+	}
+
+	from, to := int64(22), int64(22)
+	if awsRuleKey(enumor.Ingress, &protocol, &from, &to, &cidr, nil) ==
+		awsRuleKey(enumor.Ingress, &protocol, nil, nil, &cidr, nil) {
+		t.Errorf("aws rule key should distinguish ports")
+	}
+}
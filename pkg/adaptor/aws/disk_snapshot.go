@@ -0,0 +1,173 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"fmt"
+
+	"hcm/pkg/adaptor/types/disk"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// ListDiskSnapshot 查询账号自有的云硬盘快照，分页查询全部满足条件的快照
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_DescribeSnapshots.html
+func (a *Aws) ListDiskSnapshot(kt *kit.Kit, opt *disk.AwsDiskSnapshotListOption) ([]disk.DiskSnapshot, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "aws disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &ec2.DescribeSnapshotsInput{OwnerIds: []*string{aws.String("self")}}
+	if len(opt.CloudIDs) != 0 {
+		req.SnapshotIds = aws.StringSlice(opt.CloudIDs)
+	}
+	if len(opt.CloudDiskIDs) != 0 {
+		req.Filters = []*ec2.Filter{{Name: aws.String("volume-id"), Values: aws.StringSlice(opt.CloudDiskIDs)}}
+	}
+
+	snapshots := make([]disk.DiskSnapshot, 0)
+	err = client.DescribeSnapshotsPagesWithContext(kt.Ctx, req,
+		func(page *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
+			for _, one := range page.Snapshots {
+				name, _ := parseTags(one.Tags)
+				snapshot := disk.DiskSnapshot{
+					CloudID:     converter.PtrToVal(one.SnapshotId),
+					Name:        name,
+					Region:      opt.Region,
+					CloudDiskID: converter.PtrToVal(one.VolumeId),
+					DiskSize:    uint64(converter.PtrToVal(one.VolumeSize)),
+					Status:      converter.PtrToVal(one.State),
+				}
+				if one.StartTime != nil {
+					snapshot.CloudCreatedTime = one.StartTime.String()
+				}
+				snapshots = append(snapshots, snapshot)
+			}
+			return true
+		})
+	if err != nil {
+		logs.Errorf("list aws disk snapshot failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// CreateDiskSnapshot 为云硬盘创建快照，快照为异步创建，返回时快照可能仍处于pending状态
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_CreateSnapshot.html
+func (a *Aws) CreateDiskSnapshot(kt *kit.Kit, opt *disk.AwsDiskSnapshotCreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "aws disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return "", err
+	}
+
+	req := &ec2.CreateSnapshotInput{
+		VolumeId:          aws.String(opt.CloudDiskID),
+		TagSpecifications: genNameTags(snapshotTagResType, opt.SnapshotName),
+	}
+	resp, err := client.CreateSnapshotWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("create aws disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return "", err
+	}
+
+	snapshotID := converter.PtrToVal(resp.SnapshotId)
+	if len(snapshotID) == 0 {
+		return "", fmt.Errorf("aws create disk snapshot but return snapshot id is empty, disk: %s",
+			opt.CloudDiskID)
+	}
+
+	return snapshotID, nil
+}
+
+// DeleteDiskSnapshot 删除云硬盘快照
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_DeleteSnapshot.html
+func (a *Aws) DeleteDiskSnapshot(kt *kit.Kit, opt *disk.AwsDiskSnapshotDeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "aws disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	req := &ec2.DeleteSnapshotInput{SnapshotId: aws.String(opt.CloudID)}
+	if _, err = client.DeleteSnapshotWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("delete aws disk snapshot failed, err: %v, id: %s, rid: %s", err, opt.CloudID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// RollbackDiskSnapshot 使用快照替换主机的根卷，aws仅支持回滚主机的系统盘，替换过程中主机会重启
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_CreateReplaceRootVolumeTask.html
+func (a *Aws) RollbackDiskSnapshot(kt *kit.Kit, opt *disk.AwsDiskSnapshotRollbackOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "aws disk snapshot rollback option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	req := &ec2.CreateReplaceRootVolumeTaskInput{
+		InstanceId: aws.String(opt.CloudCvmID),
+		SnapshotId: aws.String(opt.CloudID),
+	}
+	if _, err = client.CreateReplaceRootVolumeTaskWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("rollback aws disk snapshot failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+
+	return nil
+}
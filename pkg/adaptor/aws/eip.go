@@ -91,6 +91,7 @@ func (a *Aws) ListEip(kt *kit.Kit, opt *eip.AwsEipListOption) (*eip.AwsEipListRe
 			PrivateIpAddress:   address.PrivateIpAddress,
 			NetworkBorderGroup: address.NetworkBorderGroup,
 			NetworkInterfaceId: address.NetworkInterfaceId,
+			Tags:               convTagsToMap(address.Tags),
 		}
 	}
 
@@ -0,0 +1,256 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"fmt"
+
+	"hcm/pkg/adaptor/poller"
+	"hcm/pkg/adaptor/types/image"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// CreateImage 基于主机创建自定义镜像(AMI)，并等待镜像创建完成
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_CreateImage.html
+func (a *Aws) CreateImage(kt *kit.Kit, opt *image.AwsImageCreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "aws image create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return "", err
+	}
+
+	req := &ec2.CreateImageInput{
+		InstanceId: aws.String(opt.CloudCvmID),
+		Name:       aws.String(opt.ImageName),
+		NoReboot:   aws.Bool(!opt.Reboot),
+	}
+	resp, err := client.CreateImageWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("aws create image failed, err: %v, cvm: %s, rid: %s", err, opt.CloudCvmID, kt.Rid)
+		return "", err
+	}
+
+	imageID := converter.PtrToVal(resp.ImageId)
+	if len(imageID) == 0 {
+		return "", fmt.Errorf("aws create image but return image id is empty, cvm: %s", opt.CloudCvmID)
+	}
+
+	if err = a.waitImageAvailable(kt, opt.Region, imageID); err != nil {
+		return "", err
+	}
+
+	return imageID, nil
+}
+
+// CopyImage 将自定义镜像复制到其他地域，aws需在目的地域发起复制，并等待各目的地域的镜像复制完成
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_CopyImage.html
+func (a *Aws) CopyImage(kt *kit.Kit, opt *image.AwsImageCopyOption) ([]image.CopiedImage, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "aws image copy option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	copied := make([]image.CopiedImage, 0, len(opt.DstRegions))
+	for _, region := range opt.DstRegions {
+		client, err := a.clientSet.ec2Client(region)
+		if err != nil {
+			return nil, err
+		}
+
+		req := &ec2.CopyImageInput{
+			Name:          aws.String(opt.ImageName),
+			SourceImageId: aws.String(opt.CloudImageID),
+			SourceRegion:  aws.String(opt.Region),
+		}
+		resp, err := client.CopyImageWithContext(kt.Ctx, req)
+		if err != nil {
+			logs.Errorf("aws copy image failed, err: %v, image: %s, dst region: %s, rid: %s", err,
+				opt.CloudImageID, region, kt.Rid)
+			return nil, err
+		}
+
+		imageID := converter.PtrToVal(resp.ImageId)
+		if len(imageID) == 0 {
+			return nil, fmt.Errorf("aws copy image %s to %s but return image id is empty", opt.CloudImageID,
+				region)
+		}
+		copied = append(copied, image.CopiedImage{Region: region, CloudImageID: imageID})
+	}
+
+	for _, one := range copied {
+		if err := a.waitImageAvailable(kt, one.Region, one.CloudImageID); err != nil {
+			return nil, err
+		}
+	}
+
+	return copied, nil
+}
+
+// ShareImage 授予其他亚马逊云账号使用自定义镜像的权限
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_ModifyImageAttribute.html
+func (a *Aws) ShareImage(kt *kit.Kit, opt *image.AwsImageShareOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "aws image share option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	permissions := make([]*ec2.LaunchPermission, 0, len(opt.CloudAccountIDs))
+	for _, accountID := range opt.CloudAccountIDs {
+		permissions = append(permissions, &ec2.LaunchPermission{UserId: aws.String(accountID)})
+	}
+	req := &ec2.ModifyImageAttributeInput{
+		ImageId:          aws.String(opt.CloudImageID),
+		LaunchPermission: &ec2.LaunchPermissionModifications{Add: permissions},
+	}
+	if _, err = client.ModifyImageAttributeWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("aws share image failed, err: %v, image: %s, accounts: %v, rid: %s", err, opt.CloudImageID,
+			opt.CloudAccountIDs, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// ListPrivateImageState 查询账号在地域下自有的全部镜像及其状态，已注销的镜像不会返回
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_DescribeImages.html
+func (a *Aws) ListPrivateImageState(kt *kit.Kit, opt *image.PrivateImageStateListOption) (
+	[]image.PrivateImageState, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "aws private image state list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &ec2.DescribeImagesInput{Owners: []*string{aws.String("self")}}
+	resp, err := client.DescribeImagesWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("describe aws private images failed, err: %v, region: %s, rid: %s", err, opt.Region, kt.Rid)
+		return nil, err
+	}
+
+	states := make([]image.PrivateImageState, 0, len(resp.Images))
+	for _, one := range resp.Images {
+		states = append(states, image.PrivateImageState{
+			CloudImageID: converter.PtrToVal(one.ImageId),
+			State:        converter.PtrToVal(one.State),
+		})
+	}
+
+	return states, nil
+}
+
+// waitImageAvailable 等待镜像状态变为available
+func (a *Aws) waitImageAvailable(kt *kit.Kit, region, imageID string) error {
+	respPoller := poller.Poller[*Aws, []*ec2.Image, poller.BaseDoneResult]{
+		Handler: &createImagePollingHandler{region: region},
+	}
+	result, err := respPoller.PollUntilDone(a, kt, []*string{aws.String(imageID)}, nil)
+	if err != nil {
+		return err
+	}
+
+	if len(result.SuccessCloudIDs) == 0 {
+		return fmt.Errorf("aws image %s in %s is not available, message: %s", imageID, region,
+			result.FailedMessage)
+	}
+
+	return nil
+}
+
+type createImagePollingHandler struct {
+	region string
+}
+
+// Done 镜像状态为available时创建完成，pending、transient为处理中，其余状态均视为失败
+func (h *createImagePollingHandler) Done(pollResult []*ec2.Image) (bool, *poller.BaseDoneResult) {
+	result := new(poller.BaseDoneResult)
+	for _, one := range pollResult {
+		state := converter.PtrToVal(one.State)
+		switch state {
+		case ec2.ImageStateAvailable:
+			result.SuccessCloudIDs = append(result.SuccessCloudIDs, converter.PtrToVal(one.ImageId))
+		case ec2.ImageStatePending, ec2.ImageStateTransient:
+			result.UnknownCloudIDs = append(result.UnknownCloudIDs, converter.PtrToVal(one.ImageId))
+		default:
+			result.FailedCloudIDs = append(result.FailedCloudIDs, converter.PtrToVal(one.ImageId))
+			result.FailedMessage = fmt.Sprintf("image state: %s", state)
+			if one.StateReason != nil {
+				result.FailedMessage += fmt.Sprintf(", reason: %s", converter.PtrToVal(one.StateReason.Message))
+			}
+		}
+	}
+
+	return len(result.UnknownCloudIDs) == 0, result
+}
+
+// Poll 查询镜像状态
+func (h *createImagePollingHandler) Poll(client *Aws, kt *kit.Kit, cloudIDs []*string) ([]*ec2.Image, error) {
+	ec2Client, err := client.clientSet.ec2Client(h.region)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &ec2.DescribeImagesInput{ImageIds: cloudIDs}
+	resp, err := ec2Client.DescribeImagesWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("describe aws images failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	if len(resp.Images) != len(cloudIDs) {
+		return nil, fmt.Errorf("aws image %v not found", converter.PtrToSlice(cloudIDs))
+	}
+
+	return resp.Images, nil
+}
+
+var _ poller.PollingHandler[*Aws, []*ec2.Image, poller.BaseDoneResult] = new(createImagePollingHandler)
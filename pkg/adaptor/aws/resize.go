@@ -0,0 +1,87 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"hcm/pkg/adaptor/types/cvm"
+	"hcm/pkg/adaptor/types/disk"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// ResizeCvm 修改实例类型，实例需处于关机状态
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_ModifyInstanceAttribute.html
+func (a *Aws) ResizeCvm(kt *kit.Kit, opt *cvm.AwsResizeOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "resize option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	req := &ec2.ModifyInstanceAttributeInput{
+		InstanceId:   aws.String(opt.CloudID),
+		InstanceType: &ec2.AttributeValue{Value: aws.String(opt.InstanceType)},
+	}
+	if _, err = client.ModifyInstanceAttributeWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("modify cvm instance type failed, err: %v, id: %s, rid: %s", err, opt.CloudID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// ResizeDisk 扩容云硬盘，云上扩容为异步完成，提交成功即返回
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_ModifyVolume.html
+func (a *Aws) ResizeDisk(kt *kit.Kit, opt *disk.AwsDiskResizeOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "aws disk resize option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	req := &ec2.ModifyVolumeInput{
+		VolumeId: aws.String(opt.CloudDiskID),
+		Size:     aws.Int64(opt.DiskSize),
+	}
+	if _, err = client.ModifyVolumeWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("modify aws volume size failed, err: %v, id: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"fmt"
+
+	typestag "hcm/pkg/adaptor/types/tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// tagSupportedResTypes 支持通过 ec2 标签接口打标签的资源类型
+var tagSupportedResTypes = map[enumor.CloudResourceType]struct{}{
+	enumor.CvmCloudResType:           {},
+	enumor.DiskCloudResType:          {},
+	enumor.SecurityGroupCloudResType: {},
+	enumor.EipCloudResType:           {},
+	enumor.VpcCloudResType:           {},
+	enumor.SubnetCloudResType:        {},
+}
+
+// TagResources 为资源添加标签，已存在的同名标签会被覆盖
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_CreateTags.html
+func (a *Aws) TagResources(kt *kit.Kit, opt *typestag.TagResOption) error {
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if _, exists := tagSupportedResTypes[opt.ResType]; !exists {
+		return errf.Newf(errf.InvalidParameter, "aws resource type %s does not support tag", opt.ResType)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new ec2 client failed, err: %v", err)
+	}
+
+	tags := make([]*ec2.Tag, 0, len(opt.Tags))
+	for key, value := range opt.Tags {
+		tags = append(tags, &ec2.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+
+	req := &ec2.CreateTagsInput{
+		Resources: aws.StringSlice(opt.CloudIDs),
+		Tags:      tags,
+	}
+	if _, err = client.CreateTagsWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("aws create tags failed, err: %v, ids: %v, rid: %s", err, opt.CloudIDs, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// UnTagResources 删除资源的标签
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_DeleteTags.html
+func (a *Aws) UnTagResources(kt *kit.Kit, opt *typestag.UnTagResOption) error {
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if _, exists := tagSupportedResTypes[opt.ResType]; !exists {
+		return errf.Newf(errf.InvalidParameter, "aws resource type %s does not support tag", opt.ResType)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new ec2 client failed, err: %v", err)
+	}
+
+	tags := make([]*ec2.Tag, 0, len(opt.Keys))
+	for _, key := range opt.Keys {
+		tags = append(tags, &ec2.Tag{Key: aws.String(key)})
+	}
+
+	req := &ec2.DeleteTagsInput{
+		Resources: aws.StringSlice(opt.CloudIDs),
+		Tags:      tags,
+	}
+	if _, err = client.DeleteTagsWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("aws delete tags failed, err: %v, ids: %v, rid: %s", err, opt.CloudIDs, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -34,8 +34,9 @@ const tagKeyForResourceName = "ImportMode"
 type tagResourceType string
 
 const (
-	vpcTagResType    tagResourceType = "vpc"
-	subnetTagResType tagResourceType = "subnet"
+	vpcTagResType      tagResourceType = "vpc"
+	subnetTagResType   tagResourceType = "subnet"
+	snapshotTagResType tagResourceType = "snapshot"
 )
 
 // genNameTags generate name ec2 tags.
@@ -69,3 +70,16 @@ func parseTags(tags []*ec2.Tag) (string, []*ec2.Tag) {
 
 	return "", tags
 }
+
+// convTagsToMap convert ec2 tags to map.
+func convTagsToMap(tags []*ec2.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, one := range tags {
+		if one == nil || one.Key == nil {
+			continue
+		}
+		result[*one.Key] = converter.PtrToVal(one.Value)
+	}
+
+	return result
+}
@@ -241,6 +241,8 @@ func convertVpc(data *ec2.Vpc, region string) *types.AwsVpc {
 	v := &types.AwsVpc{
 		CloudID: converter.PtrToVal(data.VpcId),
 		Region:  region,
+		// parseTags 会修改原标签切片，所以需要先转换标签
+		Tags: convTagsToMap(data.Tags),
 		Extension: &cloud.AwsVpcExtension{
 			State:           converter.PtrToVal(data.State),
 			InstanceTenancy: converter.PtrToVal(data.InstanceTenancy),
@@ -263,6 +263,7 @@ func converterToAzureCvm(vms []*armcompute.VirtualMachine) []*typecvm.AzureCvm {
 			Location: SPtrToLowerNoSpaceSPtr(v.Location),
 			Type:     v.Type,
 			Zones:    v.Zones,
+			Tags:     v.Tags,
 		}
 
 		if v.Properties == nil {
@@ -446,6 +447,10 @@ func (az *Azure) StopCvm(kt *kit.Kit, opt *typecvm.AzureStopOption) error {
 		return fmt.Errorf("new cvm client failed, err: %v", err)
 	}
 
+	if opt.Deallocate {
+		return az.deallocateCvm(kt, client, opt)
+	}
+
 	poller, err := client.BeginPowerOff(kt.Ctx, opt.ResourceGroupName, opt.Name,
 		&armcompute.VirtualMachinesClientBeginPowerOffOptions{SkipShutdown: to.Ptr(opt.SkipShutdown)})
 	if err != nil {
@@ -462,6 +467,25 @@ func (az *Azure) StopCvm(kt *kit.Kit, opt *typecvm.AzureStopOption) error {
 	return nil
 }
 
+// deallocateCvm 关机并解除分配虚拟机
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/virtual-machines/deallocate?tabs=HTTP
+func (az *Azure) deallocateCvm(kt *kit.Kit, client *armcompute.VirtualMachinesClient,
+	opt *typecvm.AzureStopOption) error {
+
+	poller, err := client.BeginDeallocate(kt.Ctx, opt.ResourceGroupName, opt.Name, nil)
+	if err != nil {
+		logs.Errorf("begin deallocate cvm failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	if _, err = poller.PollUntilDone(kt.Ctx, nil); err != nil {
+		logs.Errorf("poll until cvm deallocate failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
 // CreateCvm reference: https://learn.microsoft.com/en-us/rest/api/compute/virtual-machines/create-or-update?tabs=HTTP
 func (az *Azure) CreateCvm(kt *kit.Kit, opt *typecvm.AzureCreateOption) (string, error) {
 	if opt == nil {
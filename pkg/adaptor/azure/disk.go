@@ -119,6 +119,7 @@ func (az *Azure) GetDisk(kt *kit.Kit, opt *disk.AzureDiskGetOption) (*disk.Azure
 		Status:   (*string)(resp.Disk.Properties.DiskState),
 		DiskSize: resp.Disk.Properties.DiskSizeBytes,
 		Zones:    resp.Disk.Zones,
+		Tags:     resp.Disk.Tags,
 	}
 
 	return converterResp, nil
@@ -275,6 +276,7 @@ func converterDisk(disks []*armcompute.Disk) []*disk.AzureDisk {
 			OSType:   (*string)(v.Properties.OSType),
 			SKUName:  (*string)(v.SKU.Name),
 			SKUTier:  v.SKU.Tier,
+			Tags:     v.Tags,
 		}
 		typesDisk = append(typesDisk, tmp)
 	}
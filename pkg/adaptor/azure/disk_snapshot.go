@@ -0,0 +1,187 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package azure
+
+import (
+	"fmt"
+	"strings"
+
+	"hcm/pkg/adaptor/types/disk"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+)
+
+// ListDiskSnapshot 查询订阅下指定地域的云硬盘快照
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/snapshots/list?tabs=Go
+func (az *Azure) ListDiskSnapshot(kt *kit.Kit, opt *disk.AzureDiskSnapshotListOption) ([]disk.DiskSnapshot, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "azure disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := az.clientSet.snapshotClient()
+	if err != nil {
+		return nil, fmt.Errorf("new azure snapshot client failed, err: %v", err)
+	}
+
+	snapshots := make([]disk.DiskSnapshot, 0)
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(kt.Ctx)
+		if err != nil {
+			logs.Errorf("list azure disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, errorf(err)
+		}
+
+		for _, one := range page.Value {
+			if one == nil || SPtrToLowerNoSpaceStr(one.Location) != opt.Region {
+				continue
+			}
+			snapshots = append(snapshots, convertDiskSnapshot(one))
+		}
+	}
+
+	return snapshots, nil
+}
+
+func convertDiskSnapshot(one *armcompute.Snapshot) disk.DiskSnapshot {
+	snapshot := disk.DiskSnapshot{
+		CloudID: SPtrToLowerStr(one.ID),
+		Name:    converter.PtrToVal(one.Name),
+		Region:  SPtrToLowerNoSpaceStr(one.Location),
+	}
+
+	if one.Properties == nil {
+		return snapshot
+	}
+
+	snapshot.DiskSize = uint64(converter.PtrToVal(one.Properties.DiskSizeGB))
+	snapshot.Status = converter.PtrToVal(one.Properties.ProvisioningState)
+	if one.Properties.CreationData != nil {
+		snapshot.CloudDiskID = SPtrToLowerStr(one.Properties.CreationData.SourceResourceID)
+	}
+	if one.Properties.TimeCreated != nil {
+		snapshot.CloudCreatedTime = one.Properties.TimeCreated.String()
+	}
+
+	return snapshot
+}
+
+// CreateDiskSnapshot 为云硬盘创建增量快照，快照创建在云硬盘所在的资源组下
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/snapshots/create-or-update?tabs=Go
+func (az *Azure) CreateDiskSnapshot(kt *kit.Kit, opt *disk.AzureDiskSnapshotCreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "azure disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	resGroupName, err := parseIDToResourceGroup(opt.CloudDiskID)
+	if err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := az.clientSet.snapshotClient()
+	if err != nil {
+		return "", fmt.Errorf("new azure snapshot client failed, err: %v", err)
+	}
+
+	req := armcompute.Snapshot{
+		Location: converter.ValToPtr(opt.Region),
+		Properties: &armcompute.SnapshotProperties{
+			CreationData: &armcompute.CreationData{
+				CreateOption:     converter.ValToPtr(armcompute.DiskCreateOptionCopy),
+				SourceResourceID: converter.ValToPtr(opt.CloudDiskID),
+			},
+			Incremental: converter.ValToPtr(true),
+		},
+	}
+	poller, err := client.BeginCreateOrUpdate(kt.Ctx, resGroupName, opt.SnapshotName, req, nil)
+	if err != nil {
+		logs.Errorf("create azure disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return "", errorf(err)
+	}
+
+	resp, err := poller.PollUntilDone(kt.Ctx, nil)
+	if err != nil {
+		logs.Errorf("wait azure disk snapshot created failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID,
+			kt.Rid)
+		return "", errorf(err)
+	}
+
+	return SPtrToLowerStr(resp.ID), nil
+}
+
+// DeleteDiskSnapshot 删除云硬盘快照
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/snapshots/delete?tabs=Go
+func (az *Azure) DeleteDiskSnapshot(kt *kit.Kit, opt *disk.AzureDiskSnapshotDeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "azure disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	resGroupName, err := parseIDToResourceGroup(opt.CloudID)
+	if err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := az.clientSet.snapshotClient()
+	if err != nil {
+		return fmt.Errorf("new azure snapshot client failed, err: %v", err)
+	}
+
+	poller, err := client.BeginDelete(kt.Ctx, resGroupName, parseIDToName(opt.CloudID), nil)
+	if err != nil {
+		logs.Errorf("delete azure disk snapshot failed, err: %v, id: %s, rid: %s", err, opt.CloudID, kt.Rid)
+		return errorf(err)
+	}
+
+	if _, err = poller.PollUntilDone(kt.Ctx, nil); err != nil {
+		logs.Errorf("wait azure disk snapshot deleted failed, err: %v, id: %s, rid: %s", err, opt.CloudID, kt.Rid)
+		return errorf(err)
+	}
+
+	return nil
+}
+
+// parseIDToResourceGroup parse resource group name from resource id.
+// id format: /subscriptions/{subscription}/resourceGroups/{resourceGroup}/providers/{provider}/.../{name}.
+func parseIDToResourceGroup(id string) (string, error) {
+	parts := strings.Split(id, "/")
+	for idx := 0; idx < len(parts)-1; idx++ {
+		if strings.EqualFold(parts[idx], "resourceGroups") && len(parts[idx+1]) != 0 {
+			return parts[idx+1], nil
+		}
+	}
+
+	return "", fmt.Errorf("resource group not found in azure resource id: %s", id)
+}
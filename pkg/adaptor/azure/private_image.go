@@ -0,0 +1,123 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package azure
+
+import (
+	"fmt"
+
+	"hcm/pkg/adaptor/types/image"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+)
+
+// CreateImage 基于主机创建托管镜像，镜像创建在主机所在的资源组下，并等待镜像创建完成。
+// azure仅支持基于已通用化(generalized)的主机创建托管镜像，未通用化的主机会创建失败
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/images/create-or-update?tabs=Go
+func (az *Azure) CreateImage(kt *kit.Kit, opt *image.AzureImageCreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "azure image create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	resGroupName, err := parseIDToResourceGroup(opt.CloudCvmID)
+	if err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	factory, err := az.clientSet.clientFactory()
+	if err != nil {
+		return "", err
+	}
+
+	req := armcompute.Image{
+		Location: converter.ValToPtr(opt.Region),
+		Properties: &armcompute.ImageProperties{
+			SourceVirtualMachine: &armcompute.SubResource{ID: converter.ValToPtr(opt.CloudCvmID)},
+		},
+	}
+	poller, err := factory.NewImagesClient().BeginCreateOrUpdate(kt.Ctx, resGroupName, opt.ImageName, req, nil)
+	if err != nil {
+		logs.Errorf("create azure image failed, err: %v, cvm: %s, rid: %s", err, opt.CloudCvmID, kt.Rid)
+		return "", errorf(err)
+	}
+
+	resp, err := poller.PollUntilDone(kt.Ctx, nil)
+	if err != nil {
+		logs.Errorf("wait azure image created failed, err: %v, cvm: %s, rid: %s", err, opt.CloudCvmID, kt.Rid)
+		return "", errorf(err)
+	}
+
+	imageID := SPtrToLowerStr(resp.ID)
+	if len(imageID) == 0 {
+		return "", fmt.Errorf("azure create image but return image id is empty, cvm: %s", opt.CloudCvmID)
+	}
+
+	return imageID, nil
+}
+
+// ListPrivateImageState 查询订阅下指定地域的全部托管镜像及其预配状态
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/images/list?tabs=Go
+func (az *Azure) ListPrivateImageState(kt *kit.Kit, opt *image.PrivateImageStateListOption) (
+	[]image.PrivateImageState, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "azure private image state list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	factory, err := az.clientSet.clientFactory()
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]image.PrivateImageState, 0)
+	pager := factory.NewImagesClient().NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(kt.Ctx)
+		if err != nil {
+			logs.Errorf("list azure private images failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, errorf(err)
+		}
+
+		for _, one := range page.Value {
+			if one == nil || SPtrToLowerNoSpaceStr(one.Location) != opt.Region {
+				continue
+			}
+
+			state := image.PrivateImageState{CloudImageID: SPtrToLowerStr(one.ID)}
+			if one.Properties != nil {
+				state.State = converter.PtrToVal(one.Properties.ProvisioningState)
+			}
+			states = append(states, state)
+		}
+	}
+
+	return states, nil
+}
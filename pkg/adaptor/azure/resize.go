@@ -0,0 +1,103 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package azure
+
+import (
+	"fmt"
+
+	"hcm/pkg/adaptor/types/cvm"
+	"hcm/pkg/adaptor/types/disk"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+)
+
+// ResizeCvm 调整虚拟机大小，运行中的虚拟机由云上自动重启
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/virtual-machines/update?tabs=Go
+func (az *Azure) ResizeCvm(kt *kit.Kit, opt *cvm.AzureResizeOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "resize option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := az.clientSet.virtualMachineClient()
+	if err != nil {
+		return fmt.Errorf("new cvm client failed, err: %v", err)
+	}
+
+	update := armcompute.VirtualMachineUpdate{
+		Properties: &armcompute.VirtualMachineProperties{
+			HardwareProfile: &armcompute.HardwareProfile{
+				VMSize: to.Ptr(armcompute.VirtualMachineSizeTypes(opt.VMSize)),
+			},
+		},
+	}
+	poller, err := client.BeginUpdate(kt.Ctx, opt.ResourceGroupName, opt.Name, update, nil)
+	if err != nil {
+		logs.Errorf("begin resize cvm failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	if _, err = poller.PollUntilDone(kt.Ctx, nil); err != nil {
+		logs.Errorf("poll until cvm resize failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// ResizeDisk 扩容托管磁盘，已挂载的磁盘需要虚拟机处于解除分配状态
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/disks/update?tabs=Go
+func (az *Azure) ResizeDisk(kt *kit.Kit, opt *disk.AzureDiskResizeOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "azure disk resize option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := az.clientSet.diskClient()
+	if err != nil {
+		return fmt.Errorf("new disk client failed, err: %v", err)
+	}
+
+	update := armcompute.DiskUpdate{
+		Properties: &armcompute.DiskUpdateProperties{DiskSizeGB: to.Ptr(opt.DiskSize)},
+	}
+	poller, err := client.BeginUpdate(kt.Ctx, opt.ResourceGroupName, opt.DiskName, update, nil)
+	if err != nil {
+		logs.Errorf("begin resize azure disk failed, err: %v, rid: %s", err, kt.Rid)
+		return errorf(err)
+	}
+
+	if _, err = poller.PollUntilDone(kt.Ctx, nil); err != nil {
+		logs.Errorf("poll until azure disk resize failed, err: %v, rid: %s", err, kt.Rid)
+		return errorf(err)
+	}
+
+	return nil
+}
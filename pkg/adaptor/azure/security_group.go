@@ -262,6 +262,7 @@ func (az *Azure) converCloudToSecurityGroup(cloud *armnetwork.SecurityGroup) *se
 		Etag:            cloud.Etag,
 		FlushConnection: nil,
 		ResourceGUID:    nil,
+		Tags:            cloud.Tags,
 	}
 	if cloud.Properties != nil {
 		respSecurityGroup.FlushConnection = cloud.Properties.FlushConnection
@@ -293,6 +294,7 @@ func (az *Azure) getSecurityGroupByCloudID(kt *kit.Kit, resGroupName, cloudID st
 		Etag:            resp.SecurityGroup.Etag,
 		FlushConnection: nil,
 		ResourceGUID:    nil,
+		Tags:            resp.SecurityGroup.Tags,
 	}
 	if resp.SecurityGroup.Properties != nil {
 		sg.FlushConnection = resp.SecurityGroup.Properties.FlushConnection
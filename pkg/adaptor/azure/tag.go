@@ -0,0 +1,122 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package azure
+
+import (
+	"fmt"
+
+	typestag "hcm/pkg/adaptor/types/tag"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+// TagResources 为资源合并标签，已存在的同名标签会被覆盖，资源 id 即为标签作用域
+// reference: https://learn.microsoft.com/en-us/rest/api/resources/tags/update-at-scope
+func (az *Azure) TagResources(kt *kit.Kit, opt *typestag.TagResOption) error {
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := az.clientSet.tagsClient()
+	if err != nil {
+		return fmt.Errorf("new tags client failed, err: %v", err)
+	}
+
+	tags := make(map[string]*string, len(opt.Tags))
+	for key, value := range opt.Tags {
+		tags[key] = converter.ValToPtr(value)
+	}
+
+	for _, id := range opt.CloudIDs {
+		params := armresources.TagsPatchResource{
+			Operation:  converter.ValToPtr(armresources.TagsPatchOperationMerge),
+			Properties: &armresources.Tags{Tags: tags},
+		}
+		if _, err = client.UpdateAtScope(kt.Ctx, id, params, nil); err != nil {
+			logs.Errorf("azure merge resource tags failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnTagResources 删除资源的指定标签，先查询资源当前标签，再以剩余标签整体替换
+// reference: https://learn.microsoft.com/en-us/rest/api/resources/tags/update-at-scope
+func (az *Azure) UnTagResources(kt *kit.Kit, opt *typestag.UnTagResOption) error {
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := az.clientSet.tagsClient()
+	if err != nil {
+		return fmt.Errorf("new tags client failed, err: %v", err)
+	}
+
+	for _, id := range opt.CloudIDs {
+		resp, err := client.GetAtScope(kt.Ctx, id, nil)
+		if err != nil {
+			logs.Errorf("azure get resource tags failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return err
+		}
+
+		if resp.Properties == nil || len(resp.Properties.Tags) == 0 {
+			continue
+		}
+
+		tags := resp.Properties.Tags
+		changed := false
+		for _, key := range opt.Keys {
+			if _, exists := tags[key]; exists {
+				delete(tags, key)
+				changed = true
+			}
+		}
+
+		if !changed {
+			continue
+		}
+
+		params := armresources.TagsPatchResource{
+			Operation:  converter.ValToPtr(armresources.TagsPatchOperationReplace),
+			Properties: &armresources.Tags{Tags: tags},
+		}
+		if _, err = client.UpdateAtScope(kt.Ctx, id, params, nil); err != nil {
+			logs.Errorf("azure replace resource tags failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// convTags 将 azure 资源标签转换为 map
+func convTags(tags map[string]*string) map[string]string {
+	result := make(map[string]string, len(tags))
+	for key, value := range tags {
+		result[key] = converter.PtrToVal(value)
+	}
+
+	return result
+}
@@ -265,6 +265,7 @@ func convertVpc(data *armnetwork.VirtualNetwork, resourceGroup string) *types.Az
 		CloudID: SPtrToLowerStr(data.ID),
 		Name:    SPtrToLowerStr(data.Name),
 		Region:  SPtrToLowerNoSpaceStr(data.Location),
+		Tags:    convTags(data.Tags),
 		Extension: &types.AzureVpcExtension{
 			ResourceGroupName: strings.ToLower(resourceGroup),
 			DNSServers:        make([]string, 0),
@@ -0,0 +1,174 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package gcp
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"hcm/pkg/adaptor/types/disk"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// ListDiskSnapshot 查询源云硬盘在指定地域的快照，gcp快照为全局资源，按源云硬盘所在地域过滤
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/snapshots/list
+func (g *Gcp) ListDiskSnapshot(kt *kit.Kit, opt *disk.GcpDiskSnapshotListOption) ([]disk.DiskSnapshot, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "gcp disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]disk.DiskSnapshot, 0)
+	listCall := client.Snapshots.List(g.CloudProjectID()).Context(kt.Ctx)
+	err = listCall.Pages(kt.Ctx, func(page *compute.SnapshotList) error {
+		for _, one := range page.Items {
+			region := parseSourceDiskRegion(one.SourceDisk)
+			if region != opt.Region {
+				continue
+			}
+
+			snapshots = append(snapshots, disk.DiskSnapshot{
+				CloudID:          strconv.FormatUint(one.Id, 10),
+				Name:             one.Name,
+				Region:           region,
+				CloudDiskID:      one.SourceDiskId,
+				DiskSize:         uint64(one.DiskSizeGb),
+				Status:           one.Status,
+				CloudCreatedTime: one.CreationTimestamp,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		logs.Errorf("list gcp disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// parseSourceDiskRegion parse region from source disk url.
+// zonal disk url: https://www.googleapis.com/compute/v1/projects/{project}/zones/{zone}/disks/{disk}
+// regional disk url: https://www.googleapis.com/compute/v1/projects/{project}/regions/{region}/disks/{disk}
+func parseSourceDiskRegion(sourceDisk string) string {
+	parts := strings.Split(sourceDisk, "/")
+	for idx := 0; idx < len(parts)-1; idx++ {
+		switch parts[idx] {
+		case "zones":
+			zone := parts[idx+1]
+			if pos := strings.LastIndex(zone, "-"); pos != -1 {
+				return zone[:pos]
+			}
+			return zone
+		case "regions":
+			return parts[idx+1]
+		}
+	}
+
+	return ""
+}
+
+// CreateDiskSnapshot 为云硬盘创建快照，返回快照的云上ID
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/disks/createSnapshot
+func (g *Gcp) CreateDiskSnapshot(kt *kit.Kit, opt *disk.GcpDiskSnapshotCreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "gcp disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return "", err
+	}
+
+	snapshot := &compute.Snapshot{Name: opt.SnapshotName}
+	_, err = client.Disks.CreateSnapshot(g.CloudProjectID(), opt.Zone, opt.DiskName, snapshot).Context(kt.Ctx).Do()
+	if err != nil {
+		logs.Errorf("create gcp disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.DiskName, kt.Rid)
+		return "", err
+	}
+
+	return g.getSnapshotCloudID(kt, client, opt.SnapshotName)
+}
+
+// getSnapshotCloudID 快照创建为异步操作，等待快照资源生成后返回快照的云上ID
+func (g *Gcp) getSnapshotCloudID(kt *kit.Kit, client *compute.Service, name string) (string, error) {
+	endTime := time.Now().Add(time.Minute)
+	for {
+		if time.Now().After(endTime) {
+			return "", fmt.Errorf("gcp disk snapshot %s not found", name)
+		}
+
+		resp, err := client.Snapshots.Get(g.CloudProjectID(), name).Context(kt.Ctx).Do()
+		if err == nil && resp != nil {
+			return strconv.FormatUint(resp.Id, 10), nil
+		}
+
+		if apiErr, ok := err.(*googleapi.Error); err != nil && (!ok || apiErr.Code != http.StatusNotFound) {
+			logs.Errorf("get gcp disk snapshot failed, err: %v, name: %s, rid: %s", err, name, kt.Rid)
+			return "", err
+		}
+
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// DeleteDiskSnapshot 删除云硬盘快照
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/snapshots/delete
+func (g *Gcp) DeleteDiskSnapshot(kt *kit.Kit, opt *disk.GcpDiskSnapshotDeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "gcp disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return err
+	}
+
+	if _, err = client.Snapshots.Delete(g.CloudProjectID(), opt.SnapshotName).Context(kt.Ctx).Do(); err != nil {
+		logs.Errorf("delete gcp disk snapshot failed, err: %v, name: %s, rid: %s", err, opt.SnapshotName, kt.Rid)
+		return err
+	}
+
+	return nil
+}
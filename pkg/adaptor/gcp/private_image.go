@@ -0,0 +1,198 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package gcp
+
+import (
+	"fmt"
+	"strconv"
+
+	"hcm/pkg/adaptor/poller"
+	"hcm/pkg/adaptor/types/image"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// imageUserRole 允许使用镜像创建主机的角色
+const imageUserRole = "roles/compute.imageUser"
+
+// CreateImage 基于主机系统盘创建自定义镜像，并等待镜像创建完成，返回镜像的云上ID。
+// gcp镜像为全局资源，创建时强制使用运行中主机的系统盘，不会关停主机
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/images/insert
+func (g *Gcp) CreateImage(kt *kit.Kit, opt *image.GcpImageCreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "gcp image create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return "", err
+	}
+
+	req := &compute.Image{
+		Name:       opt.ImageName,
+		SourceDisk: opt.CloudBootDiskSelfLink,
+	}
+	_, err = client.Images.Insert(g.CloudProjectID(), req).ForceCreate(true).Context(kt.Ctx).Do()
+	if err != nil {
+		logs.Errorf("gcp create image failed, err: %v, disk: %s, rid: %s", err, opt.CloudBootDiskSelfLink, kt.Rid)
+		return "", err
+	}
+
+	respPoller := poller.Poller[*Gcp, []*compute.Image, poller.BaseDoneResult]{
+		Handler: new(createImagePollingHandler),
+	}
+	result, err := respPoller.PollUntilDone(g, kt, []*string{converter.ValToPtr(opt.ImageName)}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if len(result.SuccessCloudIDs) == 0 {
+		return "", fmt.Errorf("gcp image %s is not created successfully, message: %s", opt.ImageName,
+			result.FailedMessage)
+	}
+
+	return result.SuccessCloudIDs[0], nil
+}
+
+// ShareImage 为其他账号的服务账号授予镜像使用者角色，使其可以使用该镜像创建主机
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/images/setIamPolicy
+func (g *Gcp) ShareImage(kt *kit.Kit, opt *image.GcpImageShareOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "gcp image share option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return err
+	}
+
+	policy, err := client.Images.GetIamPolicy(g.CloudProjectID(), opt.ImageName).Context(kt.Ctx).Do()
+	if err != nil {
+		logs.Errorf("get gcp image iam policy failed, err: %v, image: %s, rid: %s", err, opt.ImageName, kt.Rid)
+		return err
+	}
+
+	members := make([]string, 0, len(opt.ServiceAccountEmails))
+	for _, email := range opt.ServiceAccountEmails {
+		members = append(members, "serviceAccount:"+email)
+	}
+	policy.Bindings = append(policy.Bindings, &compute.Binding{Role: imageUserRole, Members: members})
+
+	req := &compute.GlobalSetPolicyRequest{Policy: policy}
+	_, err = client.Images.SetIamPolicy(g.CloudProjectID(), opt.ImageName, req).Context(kt.Ctx).Do()
+	if err != nil {
+		logs.Errorf("gcp share image failed, err: %v, image: %s, members: %v, rid: %s", err, opt.ImageName,
+			members, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// ListPrivateImageState 查询项目下的全部自定义镜像及其状态，gcp镜像为全局资源，忽略地域条件
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/images/list
+func (g *Gcp) ListPrivateImageState(kt *kit.Kit, opt *image.PrivateImageStateListOption) (
+	[]image.PrivateImageState, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "gcp private image state list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]image.PrivateImageState, 0)
+	listCall := client.Images.List(g.CloudProjectID()).Context(kt.Ctx)
+	err = listCall.Pages(kt.Ctx, func(page *compute.ImageList) error {
+		for _, one := range page.Items {
+			states = append(states, image.PrivateImageState{
+				CloudImageID: strconv.FormatUint(one.Id, 10),
+				State:        one.Status,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		logs.Errorf("list gcp private images failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	return states, nil
+}
+
+type createImagePollingHandler struct{}
+
+// Done 镜像状态为READY时创建完成，FAILED为创建失败。成功结果中返回镜像的云上ID
+func (h *createImagePollingHandler) Done(pollResult []*compute.Image) (bool, *poller.BaseDoneResult) {
+	result := new(poller.BaseDoneResult)
+	for _, one := range pollResult {
+		switch one.Status {
+		case "READY":
+			result.SuccessCloudIDs = append(result.SuccessCloudIDs, strconv.FormatUint(one.Id, 10))
+		case "FAILED":
+			result.FailedCloudIDs = append(result.FailedCloudIDs, one.Name)
+			result.FailedMessage = fmt.Sprintf("image status: %s", one.Status)
+		default:
+			result.UnknownCloudIDs = append(result.UnknownCloudIDs, one.Name)
+		}
+	}
+
+	return len(result.UnknownCloudIDs) == 0, result
+}
+
+// Poll 按镜像名称查询镜像状态
+func (h *createImagePollingHandler) Poll(client *Gcp, kt *kit.Kit, names []*string) ([]*compute.Image, error) {
+	computeClient, err := client.clientSet.computeClient(kt)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]*compute.Image, 0, len(names))
+	for _, name := range names {
+		one, err := computeClient.Images.Get(client.CloudProjectID(), converter.PtrToVal(name)).Context(kt.Ctx).Do()
+		if err != nil {
+			logs.Errorf("get gcp image failed, err: %v, name: %s, rid: %s", err, converter.PtrToVal(name), kt.Rid)
+			return nil, err
+		}
+		images = append(images, one)
+	}
+
+	return images, nil
+}
+
+var _ poller.PollingHandler[*Gcp, []*compute.Image, poller.BaseDoneResult] = new(createImagePollingHandler)
@@ -0,0 +1,114 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package gcp
+
+import (
+	"fmt"
+	"time"
+
+	"hcm/pkg/adaptor/types/cvm"
+	"hcm/pkg/adaptor/types/disk"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// gcpOperationWaitTimeout 等待gcp可用区操作完成的最长时间
+const gcpOperationWaitTimeout = 5 * time.Minute
+
+// ResizeCvm 修改实例机器类型，实例需处于关机状态
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/instances/setMachineType
+func (g *Gcp) ResizeCvm(kt *kit.Kit, opt *cvm.GcpResizeOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "resize option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return err
+	}
+
+	req := &compute.InstancesSetMachineTypeRequest{
+		MachineType: fmt.Sprintf("zones/%s/machineTypes/%s", opt.Zone, opt.MachineType),
+	}
+	op, err := client.Instances.SetMachineType(g.CloudProjectID(), opt.Zone, opt.Name, req).Context(kt.Ctx).Do()
+	if err != nil {
+		logs.Errorf("set instance machine type failed, err: %v, opt: %v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+
+	return g.waitZoneOperation(kt, client, opt.Zone, op)
+}
+
+// ResizeDisk 扩容永久性磁盘
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/disks/resize
+func (g *Gcp) ResizeDisk(kt *kit.Kit, opt *disk.GcpDiskResizeOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "gcp disk resize option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return err
+	}
+
+	req := &compute.DisksResizeRequest{SizeGb: opt.DiskSize}
+	op, err := client.Disks.Resize(g.CloudProjectID(), opt.Zone, opt.DiskName, req).Context(kt.Ctx).Do()
+	if err != nil {
+		logs.Errorf("resize gcp disk failed, err: %v, opt: %v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+
+	return g.waitZoneOperation(kt, client, opt.Zone, op)
+}
+
+// waitZoneOperation 等待可用区操作结束，操作失败时返回云上错误信息
+func (g *Gcp) waitZoneOperation(kt *kit.Kit, client *compute.Service, zone string, op *compute.Operation) error {
+	endTime := time.Now().Add(gcpOperationWaitTimeout)
+	for op.Status != "DONE" {
+		if time.Now().After(endTime) {
+			return fmt.Errorf("wait gcp operation %s timeout", op.Name)
+		}
+
+		result, err := client.ZoneOperations.Wait(g.CloudProjectID(), zone, op.Name).Context(kt.Ctx).Do()
+		if err != nil {
+			logs.Errorf("wait gcp zone operation failed, err: %v, name: %s, rid: %s", err, op.Name, kt.Rid)
+			return err
+		}
+		op = result
+	}
+
+	if op.Error != nil && len(op.Error.Errors) != 0 {
+		return fmt.Errorf("gcp operation %s failed, code: %s, message: %s", op.Name, op.Error.Errors[0].Code,
+			op.Error.Errors[0].Message)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package gcp
+
+import (
+	"strings"
+	"testing"
+
+	"hcm/pkg/kit"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// TestWaitZoneOperationDone 已结束的操作不再轮询，操作失败时返回云上错误信息
+func TestWaitZoneOperationDone(t *testing.T) {
+	cli := new(Gcp)
+	kt := kit.New()
+
+	op := &compute.Operation{Name: "op-1", Status: "DONE"}
+	if err := cli.waitZoneOperation(kt, nil, "us-central1-a", op); err != nil {
+		t.Errorf("done operation without error should succeed, got: %v", err)
+	}
+
+	op.Error = &compute.OperationError{Errors: []*compute.OperationErrorErrors{
+		{Code: "ZONE_RESOURCE_POOL_EXHAUSTED", Message: "machine type not available"},
+	}}
+	err := cli.waitZoneOperation(kt, nil, "us-central1-a", op)
+	if err == nil || !strings.Contains(err.Error(), "ZONE_RESOURCE_POOL_EXHAUSTED") {
+		t.Errorf("failed operation should return cloud error, got: %v", err)
+	}
+}
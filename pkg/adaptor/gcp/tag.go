@@ -0,0 +1,137 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package gcp
+
+import (
+	"fmt"
+
+	typestag "hcm/pkg/adaptor/types/tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// TagResources 为资源添加 label，已存在的同名 label 会被覆盖
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/instances/setLabels
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/disks/setLabels
+func (g *Gcp) TagResources(kt *kit.Kit, opt *typestag.TagResOption) error {
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return g.updateLabels(kt, opt.Zone, opt.ResType, opt.CloudIDs, func(labels map[string]string) bool {
+		changed := false
+		for key, value := range opt.Tags {
+			if origin, exists := labels[key]; !exists || origin != value {
+				labels[key] = value
+				changed = true
+			}
+		}
+		return changed
+	})
+}
+
+// UnTagResources 删除资源的指定 label
+func (g *Gcp) UnTagResources(kt *kit.Kit, opt *typestag.UnTagResOption) error {
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return g.updateLabels(kt, opt.Zone, opt.ResType, opt.CloudIDs, func(labels map[string]string) bool {
+		changed := false
+		for _, key := range opt.Keys {
+			if _, exists := labels[key]; exists {
+				delete(labels, key)
+				changed = true
+			}
+		}
+		return changed
+	})
+}
+
+// updateLabels gcp 设置 label 需要携带当前的 label 指纹，所以需要逐个查询资源后再整体设置 label。
+// modify 返回 false 表示 label 无变化，跳过设置。
+func (g *Gcp) updateLabels(kt *kit.Kit, zone string, resType enumor.CloudResourceType, cloudIDs []string,
+	modify func(labels map[string]string) bool) error {
+
+	if len(zone) == 0 {
+		return errf.New(errf.InvalidParameter, "zone is required")
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return fmt.Errorf("new compute client failed, err: %v", err)
+	}
+
+	project := g.CloudProjectID()
+	for _, id := range cloudIDs {
+		switch resType {
+		case enumor.CvmCloudResType:
+			inst, err := client.Instances.Get(project, zone, id).Context(kt.Ctx).Do()
+			if err != nil {
+				logs.Errorf("get gcp instance failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+				return err
+			}
+
+			labels := make(map[string]string, len(inst.Labels))
+			for key, value := range inst.Labels {
+				labels[key] = value
+			}
+			if !modify(labels) {
+				continue
+			}
+
+			req := &compute.InstancesSetLabelsRequest{Labels: labels, LabelFingerprint: inst.LabelFingerprint}
+			if _, err = client.Instances.SetLabels(project, zone, id, req).Context(kt.Ctx).Do(); err != nil {
+				logs.Errorf("set gcp instance labels failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+				return err
+			}
+
+		case enumor.DiskCloudResType:
+			disk, err := client.Disks.Get(project, zone, id).Context(kt.Ctx).Do()
+			if err != nil {
+				logs.Errorf("get gcp disk failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+				return err
+			}
+
+			labels := make(map[string]string, len(disk.Labels))
+			for key, value := range disk.Labels {
+				labels[key] = value
+			}
+			if !modify(labels) {
+				continue
+			}
+
+			req := &compute.ZoneSetLabelsRequest{Labels: labels, LabelFingerprint: disk.LabelFingerprint}
+			if _, err = client.Disks.SetLabels(project, zone, id, req).Context(kt.Ctx).Do(); err != nil {
+				logs.Errorf("set gcp disk labels failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+				return err
+			}
+
+		default:
+			return errf.Newf(errf.InvalidParameter, "gcp resource type %s does not support label", resType)
+		}
+	}
+
+	return nil
+}
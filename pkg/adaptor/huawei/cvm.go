@@ -366,7 +366,7 @@ func (h *HuaWei) ResetCvmPwd(kt *kit.Kit, opt *typecvm.HuaWeiResetPwdOption) err
 	return err
 }
 
-// InquiryPriceCvm 创建云主机询价
+// InquiryPriceCvm 创建云主机询价，RootVolume 为空时仅查询云主机规格的价格，不包含云硬盘，用于调整规格询价
 // reference: https://console-intl.huaweicloud.com/apiexplorer/#/openapi/BSSINTL/debug?api=ListRateOnPeriodDetail
 // reference: https://console-intl.huaweicloud.com/apiexplorer/#/openapi/BSSINTL/debug?api=ListOnDemandResourceRatings
 func (h *HuaWei) InquiryPriceCvm(kt *kit.Kit, opt *typecvm.HuaWeiCreateOption) (
@@ -423,19 +423,21 @@ func (h *HuaWei) inquiryPricePrepaidCvm(kt *kit.Kit, opt *typecvm.HuaWeiCreateOp
 		SubscriptionNum:  1,
 	})
 
-	infos = append(infos, bssmodel.PeriodProductInfo{
-		Id:               uuid.UUID(),
-		CloudServiceType: "hws.service.type.ebs",
-		ResourceType:     "hws.resource.type.volume",
-		ResourceSpec:     string(opt.RootVolume.VolumeType),
-		Region:           opt.Region,
-		AvailableZone:    converter.ValToPtr(opt.Zone),
-		ResourceSize:     converter.ValToPtr(opt.RootVolume.SizeGB),
-		SizeMeasureId:    converter.ValToPtr(int32(17)),
-		PeriodType:       periodType,
-		PeriodNum:        converter.PtrToVal(opt.InstanceCharge.PeriodNum),
-		SubscriptionNum:  1,
-	})
+	if opt.RootVolume != nil {
+		infos = append(infos, bssmodel.PeriodProductInfo{
+			Id:               uuid.UUID(),
+			CloudServiceType: "hws.service.type.ebs",
+			ResourceType:     "hws.resource.type.volume",
+			ResourceSpec:     string(opt.RootVolume.VolumeType),
+			Region:           opt.Region,
+			AvailableZone:    converter.ValToPtr(opt.Zone),
+			ResourceSize:     converter.ValToPtr(opt.RootVolume.SizeGB),
+			SizeMeasureId:    converter.ValToPtr(int32(17)),
+			PeriodType:       periodType,
+			PeriodNum:        converter.PtrToVal(opt.InstanceCharge.PeriodNum),
+			SubscriptionNum:  1,
+		})
+	}
 
 	for _, one := range opt.DataVolume {
 		infos = append(infos, bssmodel.PeriodProductInfo{
@@ -495,20 +497,22 @@ func (h *HuaWei) inquiryPricePostPaidCvm(kt *kit.Kit, opt *typecvm.HuaWeiCreateO
 		SubscriptionNum:  1,
 	})
 
-	infos = append(infos, bssmodel.DemandProductInfo{
-		Id:               uuid.UUID(),
-		CloudServiceType: "hws.service.type.ebs",
-		ResourceType:     "hws.resource.type.volume",
-		ResourceSpec:     string(opt.RootVolume.VolumeType),
-		Region:           opt.Region,
-		AvailableZone:    converter.ValToPtr(opt.Zone),
-		ResourceSize:     converter.ValToPtr(opt.RootVolume.SizeGB),
-		SizeMeasureId:    converter.ValToPtr(int32(17)),
-		UsageFactor:      "Duration",
-		UsageValue:       1,
-		UsageMeasureId:   4,
-		SubscriptionNum:  1,
-	})
+	if opt.RootVolume != nil {
+		infos = append(infos, bssmodel.DemandProductInfo{
+			Id:               uuid.UUID(),
+			CloudServiceType: "hws.service.type.ebs",
+			ResourceType:     "hws.resource.type.volume",
+			ResourceSpec:     string(opt.RootVolume.VolumeType),
+			Region:           opt.Region,
+			AvailableZone:    converter.ValToPtr(opt.Zone),
+			ResourceSize:     converter.ValToPtr(opt.RootVolume.SizeGB),
+			SizeMeasureId:    converter.ValToPtr(int32(17)),
+			UsageFactor:      "Duration",
+			UsageValue:       1,
+			UsageMeasureId:   4,
+			SubscriptionNum:  1,
+		})
+	}
 
 	for _, one := range opt.DataVolume {
 		infos = append(infos, bssmodel.DemandProductInfo{
@@ -0,0 +1,178 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"fmt"
+
+	"hcm/pkg/adaptor/types/disk"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/evs/v2/model"
+)
+
+// huaWeiSnapshotQueryLimit 华为云查询快照单页最大数量
+const huaWeiSnapshotQueryLimit = 1000
+
+// ListDiskSnapshot 查询云硬盘快照，分页查询全部满足条件的快照
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2038.html
+func (h *HuaWei) ListDiskSnapshot(kt *kit.Kit, opt *disk.HuaWeiDiskSnapshotListOption) ([]disk.DiskSnapshot, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "huawei disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("new huawei evs client failed, err: %v", err)
+	}
+
+	req := &model.ListSnapshotsRequest{Limit: converter.ValToPtr(int32(huaWeiSnapshotQueryLimit))}
+	if len(opt.CloudID) != 0 {
+		req.Id = converter.ValToPtr(opt.CloudID)
+	}
+	if len(opt.CloudDiskID) != 0 {
+		req.VolumeId = converter.ValToPtr(opt.CloudDiskID)
+	}
+
+	snapshots := make([]disk.DiskSnapshot, 0)
+	for offset := int32(0); ; offset += huaWeiSnapshotQueryLimit {
+		req.Offset = converter.ValToPtr(offset)
+		resp, err := client.ListSnapshots(req)
+		if err != nil {
+			logs.Errorf("list huawei disk snapshot failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return nil, err
+		}
+
+		details := converter.PtrToVal(resp.Snapshots)
+		for _, one := range details {
+			snapshots = append(snapshots, disk.DiskSnapshot{
+				CloudID:          one.Id,
+				Name:             converter.PtrToVal(one.Name),
+				Region:           opt.Region,
+				CloudDiskID:      one.VolumeId,
+				DiskSize:         uint64(one.Size),
+				Status:           one.Status,
+				CloudCreatedTime: one.CreatedAt,
+			})
+		}
+
+		if len(details) < huaWeiSnapshotQueryLimit {
+			break
+		}
+	}
+
+	return snapshots, nil
+}
+
+// CreateDiskSnapshot 为云硬盘创建快照，快照为异步创建，返回时快照可能仍处于creating状态
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2035.html
+func (h *HuaWei) CreateDiskSnapshot(kt *kit.Kit, opt *disk.HuaWeiDiskSnapshotCreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "huawei disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return "", fmt.Errorf("new huawei evs client failed, err: %v", err)
+	}
+
+	snapshotOpt := &model.CreateSnapshotOption{VolumeId: opt.CloudDiskID}
+	if len(opt.SnapshotName) != 0 {
+		snapshotOpt.Name = converter.ValToPtr(opt.SnapshotName)
+	}
+	req := &model.CreateSnapshotRequest{Body: &model.CreateSnapshotRequestBody{Snapshot: snapshotOpt}}
+	resp, err := client.CreateSnapshot(req)
+	if err != nil {
+		logs.Errorf("create huawei disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return "", err
+	}
+
+	if resp.Snapshot == nil || len(converter.PtrToVal(resp.Snapshot.Id)) == 0 {
+		return "", fmt.Errorf("huawei create disk snapshot but return snapshot id is empty, disk: %s",
+			opt.CloudDiskID)
+	}
+
+	return converter.PtrToVal(resp.Snapshot.Id), nil
+}
+
+// DeleteDiskSnapshot 删除云硬盘快照
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2037.html
+func (h *HuaWei) DeleteDiskSnapshot(kt *kit.Kit, opt *disk.HuaWeiDiskSnapshotDeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "huawei disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new huawei evs client failed, err: %v", err)
+	}
+
+	if _, err = client.DeleteSnapshot(&model.DeleteSnapshotRequest{SnapshotId: opt.CloudID}); err != nil {
+		logs.Errorf("delete huawei disk snapshot failed, err: %v, id: %s, rid: %s", err, opt.CloudID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// RollbackDiskSnapshot 将快照回滚到源云硬盘，云硬盘需处于未挂载状态或所在主机已关机
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2040.html
+func (h *HuaWei) RollbackDiskSnapshot(kt *kit.Kit, opt *disk.HuaWeiDiskSnapshotRollbackOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "huawei disk snapshot rollback option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new huawei evs client failed, err: %v", err)
+	}
+
+	req := &model.RollbackSnapshotRequest{
+		SnapshotId: opt.CloudID,
+		Body: &model.RollbackSnapshotRequestBody{
+			Rollback: &model.RollbackSnapshotOption{VolumeId: opt.CloudDiskID},
+		},
+	}
+	if _, err = client.RollbackSnapshot(req); err != nil {
+		logs.Errorf("rollback huawei disk snapshot failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+
+	return nil
+}
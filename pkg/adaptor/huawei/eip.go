@@ -31,6 +31,7 @@ import (
 	"hcm/pkg/logs"
 	"hcm/pkg/tools/converter"
 
+	eipv2 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/eip/v2"
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/eip/v2/model"
 )
 
@@ -110,11 +111,41 @@ func (h *HuaWei) ListEip(kt *kit.Kit, opt *eip.HuaWeiEipListOption) (*eip.HuaWei
 				}
 			}
 		}
+
+		// ListPublicips 不返回标签，需要单独查询
+		tags, err := h.listEipTags(kt, client, eips[idx].CloudID)
+		if err != nil {
+			return nil, err
+		}
+		eips[idx].Tags = tags
 	}
 
 	return &eip.HuaWeiEipListResult{Details: eips}, nil
 }
 
+// listEipTags 查询eip的云上标签
+func (h *HuaWei) listEipTags(kt *kit.Kit, client *eipv2.EipClient, cloudID string) (map[string]string, error) {
+	resp, err := client.ShowPublicipTags(&model.ShowPublicipTagsRequest{PublicipId: cloudID})
+	if err != nil {
+		logs.Errorf("[%s] fail to ShowPublicipTags, err: %v, cloudID: %s, rid: %s", enumor.HuaWei, err, cloudID,
+			kt.Rid)
+		return nil, err
+	}
+
+	if resp.Tags == nil || len(*resp.Tags) == 0 {
+		return nil, nil
+	}
+
+	tags := make(map[string]string, len(*resp.Tags))
+	for _, tag := range *resp.Tags {
+		if tag.Key == nil {
+			continue
+		}
+		tags[*tag.Key] = converter.PtrToVal(tag.Value)
+	}
+	return tags, nil
+}
+
 // DeleteEip ...
 // reference: https://support.huaweicloud.com/api-eip/eip_api_0005.html
 func (h *HuaWei) DeleteEip(kt *kit.Kit, opt *eip.HuaWeiEipDeleteOption) error {
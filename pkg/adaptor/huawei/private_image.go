@@ -0,0 +1,308 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/adaptor/poller"
+	"hcm/pkg/adaptor/types/image"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/ims/v2/model"
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/ims/v2/region"
+)
+
+const (
+	// defaultImsAgencyName 华为云跨区域复制镜像时使用的默认镜像服务委托名称
+	defaultImsAgencyName = "ims_admin_agency"
+	// imsListImageLimit 镜像列表分页查询的每页数量
+	imsListImageLimit int32 = 500
+)
+
+// CreateImage 基于云服务器创建私有镜像，并等待镜像创建完成
+// reference: https://support.huaweicloud.com/api-ims/ims_03_0703.html
+func (h *HuaWei) CreateImage(kt *kit.Kit, opt *image.HuaWeiImageCreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "huawei image create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.imsClientV2(region.ValueOf(opt.Region))
+	if err != nil {
+		return "", fmt.Errorf("new huawei ims client failed, err: %v", err)
+	}
+
+	req := &model.CreateImageRequest{
+		Body: &model.CreateImageRequestBody{
+			Name:       opt.ImageName,
+			InstanceId: converter.ValToPtr(opt.CloudCvmID),
+		},
+	}
+	resp, err := client.CreateImage(req)
+	if err != nil {
+		logs.Errorf("huawei create image failed, err: %v, cvm: %s, rid: %s", err, opt.CloudCvmID, kt.Rid)
+		return "", err
+	}
+
+	return h.waitImageJob(kt, opt.Region, converter.PtrToVal(resp.JobId))
+}
+
+// CopyImage 将私有镜像复制到其他区域，目的区域使用与区域同名的默认项目，并等待各目的区域的镜像复制完成
+// reference: https://support.huaweicloud.com/api-ims/ims_03_0606.html
+func (h *HuaWei) CopyImage(kt *kit.Kit, opt *image.HuaWeiImageCopyOption) ([]image.CopiedImage, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "huawei image copy option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.imsClientV2(region.ValueOf(opt.Region))
+	if err != nil {
+		return nil, fmt.Errorf("new huawei ims client failed, err: %v", err)
+	}
+
+	agencyName := opt.AgencyName
+	if len(agencyName) == 0 {
+		agencyName = defaultImsAgencyName
+	}
+
+	copied := make([]image.CopiedImage, 0, len(opt.DstRegions))
+	for _, dstRegion := range opt.DstRegions {
+		req := &model.CopyImageCrossRegionRequest{
+			ImageId: opt.CloudImageID,
+			Body: &model.CopyImageCrossRegionRequestBody{
+				AgencyName:  agencyName,
+				Name:        opt.ImageName,
+				ProjectName: dstRegion,
+				Region:      dstRegion,
+			},
+		}
+		resp, err := client.CopyImageCrossRegion(req)
+		if err != nil {
+			logs.Errorf("huawei copy image cross region failed, err: %v, image: %s, dst region: %s, rid: %s", err,
+				opt.CloudImageID, dstRegion, kt.Rid)
+			return nil, err
+		}
+
+		imageID, err := h.waitImageJob(kt, opt.Region, converter.PtrToVal(resp.JobId))
+		if err != nil {
+			return nil, err
+		}
+		copied = append(copied, image.CopiedImage{Region: dstRegion, CloudImageID: imageID})
+	}
+
+	return copied, nil
+}
+
+// ShareImage 将私有镜像共享给其他账号的项目，共享后需要接收方接受共享
+// reference: https://support.huaweicloud.com/api-ims/ims_03_0403.html
+func (h *HuaWei) ShareImage(kt *kit.Kit, opt *image.HuaWeiImageShareOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "huawei image share option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.imsClientV2(region.ValueOf(opt.Region))
+	if err != nil {
+		return fmt.Errorf("new huawei ims client failed, err: %v", err)
+	}
+
+	req := &model.BatchAddMembersRequest{
+		Body: &model.BatchAddMembersRequestBody{
+			Images:   []string{opt.CloudImageID},
+			Projects: opt.CloudProjectIDs,
+		},
+	}
+	resp, err := client.BatchAddMembers(req)
+	if err != nil {
+		logs.Errorf("huawei share image failed, err: %v, image: %s, projects: %v, rid: %s", err, opt.CloudImageID,
+			opt.CloudProjectIDs, kt.Rid)
+		return err
+	}
+
+	_, err = h.waitImageJob(kt, opt.Region, converter.PtrToVal(resp.JobId))
+	return err
+}
+
+// AcceptSharedImage 接受其他账号共享的镜像，需要使用接收方账号调用
+// reference: https://support.huaweicloud.com/api-ims/ims_03_0407.html
+func (h *HuaWei) AcceptSharedImage(kt *kit.Kit, opt *image.HuaWeiImageAcceptOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "huawei image accept option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.imsClientV2(region.ValueOf(opt.Region))
+	if err != nil {
+		return fmt.Errorf("new huawei ims client failed, err: %v", err)
+	}
+
+	req := &model.GlanceUpdateImageMemberRequest{
+		ImageId:  opt.CloudImageID,
+		MemberId: opt.CloudProjectID,
+		Body: &model.GlanceUpdateImageMemberRequestBody{
+			Status: model.GetGlanceUpdateImageMemberRequestBodyStatusEnum().ACCEPTED,
+		},
+	}
+	if _, err = client.GlanceUpdateImageMember(req); err != nil {
+		logs.Errorf("huawei accept shared image failed, err: %v, image: %s, project: %s, rid: %s", err,
+			opt.CloudImageID, opt.CloudProjectID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// waitImageJob 等待镜像服务的异步任务执行完成，返回任务关联的镜像ID
+func (h *HuaWei) waitImageJob(kt *kit.Kit, regionID string, jobID string) (string, error) {
+	if len(jobID) == 0 {
+		return "", errors.New("huawei ims job id is empty")
+	}
+
+	respPoller := poller.Poller[*HuaWei, *model.ShowJobResponse, poller.BaseDoneResult]{
+		Handler: &imsJobPollingHandler{region: regionID},
+	}
+	result, err := respPoller.PollUntilDone(h, kt, []*string{converter.ValToPtr(jobID)}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if len(result.SuccessCloudIDs) == 0 {
+		return "", fmt.Errorf("huawei ims job %s is not finished successfully, message: %s", jobID,
+			result.FailedMessage)
+	}
+
+	return result.SuccessCloudIDs[0], nil
+}
+
+type imsJobPollingHandler struct {
+	region string
+}
+
+// Done 任务状态为SUCCESS或FAIL时执行结束
+func (h *imsJobPollingHandler) Done(job *model.ShowJobResponse) (bool, *poller.BaseDoneResult) {
+	result := new(poller.BaseDoneResult)
+	imageID := ""
+	if job.Entities != nil {
+		imageID = converter.PtrToVal(job.Entities.ImageId)
+	}
+
+	switch converter.PtrToVal(job.Status) {
+	case model.GetShowJobResponseStatusEnum().SUCCESS:
+		result.SuccessCloudIDs = append(result.SuccessCloudIDs, imageID)
+	case model.GetShowJobResponseStatusEnum().FAIL:
+		result.FailedCloudIDs = append(result.FailedCloudIDs, imageID)
+		result.FailedMessage = converter.PtrToVal(job.FailReason)
+	default:
+		return false, result
+	}
+
+	return true, result
+}
+
+// Poll 查询镜像服务的异步任务
+func (h *imsJobPollingHandler) Poll(client *HuaWei, kt *kit.Kit, jobIDs []*string) (*model.ShowJobResponse,
+	error) {
+
+	if len(jobIDs) == 0 {
+		return nil, errors.New("job id is required")
+	}
+
+	imsCli, err := client.clientSet.imsClientV2(region.ValueOf(h.region))
+	if err != nil {
+		return nil, fmt.Errorf("new huawei ims client failed, err: %v", err)
+	}
+
+	resp, err := imsCli.ShowJob(&model.ShowJobRequest{JobId: converter.PtrToVal(jobIDs[0])})
+	if err != nil {
+		logs.Errorf("show huawei ims job failed, err: %v, job: %s, rid: %s", err, converter.PtrToVal(jobIDs[0]),
+			kt.Rid)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+var _ poller.PollingHandler[*HuaWei, *model.ShowJobResponse, poller.BaseDoneResult] = new(imsJobPollingHandler)
+
+// ListPrivateImageState 分页查询地域下的全部私有镜像及其状态，已删除的镜像不会返回
+// reference: https://support.huaweicloud.com/api-ims/ims_03_0702.html
+func (h *HuaWei) ListPrivateImageState(kt *kit.Kit, opt *image.PrivateImageStateListOption) (
+	[]image.PrivateImageState, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "huawei private image state list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.imsClientV2(region.ValueOf(opt.Region))
+	if err != nil {
+		return nil, fmt.Errorf("new huawei ims client failed, err: %v", err)
+	}
+
+	privateType := model.GetListImagesRequestImagetypeEnum().PRIVATE
+	req := &model.ListImagesRequest{
+		Imagetype: &privateType,
+		Limit:     converter.ValToPtr(imsListImageLimit),
+	}
+
+	states := make([]image.PrivateImageState, 0)
+	for {
+		resp, err := client.ListImages(req)
+		if err != nil {
+			logs.Errorf("huawei list private images failed, err: %v, region: %s, rid: %s", err, opt.Region, kt.Rid)
+			return nil, err
+		}
+
+		if resp.Images == nil || len(*resp.Images) == 0 {
+			break
+		}
+
+		for _, one := range *resp.Images {
+			states = append(states, image.PrivateImageState{CloudImageID: one.Id, State: one.Status.Value()})
+		}
+
+		if len(*resp.Images) < int(imsListImageLimit) {
+			break
+		}
+		req.Marker = converter.ValToPtr(states[len(states)-1].CloudImageID)
+	}
+
+	return states, nil
+}
@@ -0,0 +1,254 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"fmt"
+
+	typesrenewal "hcm/pkg/adaptor/types/renewal"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	bssmodel "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/bssintl/v2/model"
+)
+
+const (
+	// renewPeriodTypeMonth 续费周期类型：月
+	renewPeriodTypeMonth int32 = 2
+	// expirePolicyGracePeriod 到期后进入宽限期
+	expirePolicyGracePeriod int32 = 0
+	// expirePolicyAutoRenew 到期后自动续费
+	expirePolicyAutoRenew int32 = 3
+	// renewAutoPay 续费订单自动支付
+	renewAutoPay int32 = 1
+	// bandwidthResourceTypeCode 费用中心中共享带宽的资源类型编码
+	bandwidthResourceTypeCode = "hws.resource.type.bandwidth"
+	// customerResourcesMaxLimit 费用中心单次查询的资源数上限
+	customerResourcesMaxLimit int32 = 500
+	// bssTimeLayout 费用中心查询条件中的时间格式，UTC时间
+	bssTimeLayout = "2006-01-02T15:04:05Z"
+)
+
+// prepaidResourceStatuses 需要关注续费的资源状态：已生效、已过期、宽限期
+var prepaidResourceStatuses = []int32{2, 3, 5}
+
+// isRenewableResType 华为云费用中心按资源ID续费，支持云服务器、云硬盘和共享带宽
+func isRenewableResType(resType enumor.CloudResourceType) bool {
+	switch resType {
+	case enumor.CvmCloudResType, enumor.DiskCloudResType, enumor.BandwidthPackageCloudResType:
+		return true
+	default:
+		return false
+	}
+}
+
+// RenewResources 续费包年包月的云服务器、云硬盘或共享带宽，华为云通过费用中心统一续费，不区分地域，返回每个资源的续费结果
+// reference: https://support.huaweicloud.com/api-oce/zh-cn_topic_0082522030.html
+func (h *HuaWei) RenewResources(kt *kit.Kit, opt *typesrenewal.RenewResOption) (*typesrenewal.BatchResult, error) {
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if !isRenewableResType(opt.ResType) {
+		return nil, errf.Newf(errf.InvalidParameter, "huawei resource type %s does not support renew", opt.ResType)
+	}
+
+	client, err := h.clientSet.bssintlGlobalClient()
+	if err != nil {
+		return nil, fmt.Errorf("new bss client failed, err: %v", err)
+	}
+
+	req := &bssmodel.RenewalResourcesRequest{
+		Body: &bssmodel.RenewalResourcesReq{
+			ResourceIds:  opt.CloudIDs,
+			PeriodType:   renewPeriodTypeMonth,
+			PeriodNum:    int32(opt.Period),
+			ExpirePolicy: expirePolicyGracePeriod,
+			IsAutoPay:    converter.ValToPtr(renewAutoPay),
+		},
+	}
+	resp, err := client.RenewalResources(req)
+	if err != nil {
+		logs.Errorf("huawei renewal resources failed, err: %v, ids: %v, rid: %s", err, opt.CloudIDs, kt.Rid)
+		return nil, err
+	}
+
+	result := new(typesrenewal.BatchResult)
+	failed := make(map[string]struct{})
+	if resp.FailResourceInfos != nil {
+		for _, one := range *resp.FailResourceInfos {
+			id := converter.PtrToVal(one.ResourceId)
+			failed[id] = struct{}{}
+			result.AddFailed(id, converter.PtrToVal(one.ErrorMsg))
+		}
+	}
+	for _, id := range opt.CloudIDs {
+		if _, exists := failed[id]; !exists {
+			result.SuccessCloudIDs = append(result.SuccessCloudIDs, id)
+		}
+	}
+
+	return result, nil
+}
+
+// SetResourcesAutoRenew 开启或关闭包年包月资源的自动续费，接口仅支持单个资源，逐个设置并记录每个资源的结果
+// reference: https://support.huaweicloud.com/api-oce/zh-cn_topic_0082522031.html
+// reference: https://support.huaweicloud.com/api-oce/zh-cn_topic_0082522032.html
+func (h *HuaWei) SetResourcesAutoRenew(kt *kit.Kit, opt *typesrenewal.SetAutoRenewOption) (
+	*typesrenewal.BatchResult, error) {
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if !isRenewableResType(opt.ResType) {
+		return nil, errf.Newf(errf.InvalidParameter, "huawei resource type %s does not support auto renew",
+			opt.ResType)
+	}
+
+	client, err := h.clientSet.bssintlGlobalClient()
+	if err != nil {
+		return nil, fmt.Errorf("new bss client failed, err: %v", err)
+	}
+
+	result := new(typesrenewal.BatchResult)
+	for _, id := range opt.CloudIDs {
+		if opt.AutoRenew {
+			_, err = client.AutoRenewalResources(&bssmodel.AutoRenewalResourcesRequest{ResourceId: id})
+		} else {
+			_, err = client.CancelAutoRenewalResources(&bssmodel.CancelAutoRenewalResourcesRequest{ResourceId: id})
+		}
+		if err != nil {
+			logs.Errorf("huawei set resource auto renew failed, err: %v, id: %s, auto renew: %v, rid: %s", err, id,
+				opt.AutoRenew, kt.Rid)
+			result.AddFailed(id, err.Error())
+			continue
+		}
+		result.SuccessCloudIDs = append(result.SuccessCloudIDs, id)
+	}
+
+	return result, nil
+}
+
+// ListPrepaidExpireTime 查询包年包月资源的到期时间，华为云云服务器的到期时间仅能通过费用中心查询
+// reference: https://support.huaweicloud.com/api-oce/oce_02_0009.html
+func (h *HuaWei) ListPrepaidExpireTime(kt *kit.Kit, opt *typesrenewal.ListExpireTimeOption) (
+	[]typesrenewal.ResExpireTime, error) {
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.bssintlGlobalClient()
+	if err != nil {
+		return nil, fmt.Errorf("new bss client failed, err: %v", err)
+	}
+
+	req := &bssmodel.ListPayPerUseCustomerResourcesRequest{
+		Body: &bssmodel.QueryResourcesReq{
+			ResourceIds:      converter.ValToPtr(opt.CloudIDs),
+			OnlyMainResource: converter.ValToPtr(int32(1)),
+			Limit:            converter.ValToPtr(int32(len(opt.CloudIDs))),
+		},
+	}
+	resp, err := client.ListPayPerUseCustomerResources(req)
+	if err != nil {
+		logs.Errorf("huawei list customer resources failed, err: %v, ids: %v, rid: %s", err, opt.CloudIDs, kt.Rid)
+		return nil, err
+	}
+
+	if resp.Data == nil {
+		return make([]typesrenewal.ResExpireTime, 0), nil
+	}
+
+	result := make([]typesrenewal.ResExpireTime, 0, len(*resp.Data))
+	for _, one := range *resp.Data {
+		result = append(result, typesrenewal.ResExpireTime{
+			CloudID:    converter.PtrToVal(one.ResourceId),
+			ExpireTime: converter.PtrToVal(one.ExpireTime),
+			AutoRenew:  converter.PtrToVal(one.ExpirePolicy) == expirePolicyAutoRenew,
+		})
+	}
+
+	return result, nil
+}
+
+// ListPrepaidBandwidthPackage 查询包年包月的共享带宽，共享带宽未纳入hcm资源管理，到期时间及续费标识从费用中心查询
+// reference: https://support.huaweicloud.com/api-oce/oce_02_0009.html
+func (h *HuaWei) ListPrepaidBandwidthPackage(kt *kit.Kit, opt *typesrenewal.ListBandwidthPackageOption) (
+	[]typesrenewal.PrepaidBandwidthPackage, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list bandwidth package option is required")
+	}
+
+	client, err := h.clientSet.bssintlGlobalClient()
+	if err != nil {
+		return nil, fmt.Errorf("new bss client failed, err: %v", err)
+	}
+
+	query := &bssmodel.QueryResourcesReq{
+		OnlyMainResource: converter.ValToPtr(int32(1)),
+		StatusList:       converter.ValToPtr(prepaidResourceStatuses),
+		Limit:            converter.ValToPtr(customerResourcesMaxLimit),
+	}
+	if opt.ExpireTimeEnd != nil {
+		query.ExpireTimeEnd = converter.ValToPtr(opt.ExpireTimeEnd.UTC().Format(bssTimeLayout))
+	}
+
+	result := make([]typesrenewal.PrepaidBandwidthPackage, 0)
+	for offset := int32(0); ; offset += customerResourcesMaxLimit {
+		query.Offset = converter.ValToPtr(offset)
+		resp, err := client.ListPayPerUseCustomerResources(&bssmodel.ListPayPerUseCustomerResourcesRequest{
+			Body: query,
+		})
+		if err != nil {
+			logs.Errorf("huawei list customer resources failed, err: %v, offset: %d, rid: %s", err, offset, kt.Rid)
+			return nil, err
+		}
+
+		if resp.Data == nil {
+			break
+		}
+
+		for _, one := range *resp.Data {
+			if converter.PtrToVal(one.ResourceTypeCode) != bandwidthResourceTypeCode {
+				continue
+			}
+
+			result = append(result, typesrenewal.PrepaidBandwidthPackage{
+				CloudID:    converter.PtrToVal(one.ResourceId),
+				Name:       converter.PtrToVal(one.ResourceName),
+				Region:     converter.PtrToVal(one.RegionCode),
+				ExpireTime: converter.PtrToVal(one.ExpireTime),
+				AutoRenew:  converter.PtrToVal(one.ExpirePolicy) == expirePolicyAutoRenew,
+			})
+		}
+
+		if int32(len(*resp.Data)) < customerResourcesMaxLimit {
+			break
+		}
+	}
+
+	return result, nil
+}
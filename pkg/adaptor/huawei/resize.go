@@ -0,0 +1,118 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"fmt"
+
+	"hcm/pkg/adaptor/poller"
+	"hcm/pkg/adaptor/types"
+	"hcm/pkg/adaptor/types/cvm"
+	"hcm/pkg/adaptor/types/disk"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	ecsmodel "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/ecs/v2/model"
+	evsmodel "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/evs/v2/model"
+)
+
+// ResizeCvm 变更云服务器规格，包年包月实例自动支付变更订单
+// reference: https://support.huaweicloud.com/api-ecs/ecs_02_0208.html
+func (h *HuaWei) ResizeCvm(kt *kit.Kit, opt *cvm.HuaWeiResizeOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "resize option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.ecsClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new ecs client failed, err: %v", err)
+	}
+
+	autoPay := "true"
+	req := &ecsmodel.ResizeServerRequest{
+		ServerId: opt.CloudID,
+		Body: &ecsmodel.ResizeServerRequestBody{
+			Resize: &ecsmodel.ResizePrePaidServerOption{
+				FlavorRef:   opt.FlavorRef,
+				Extendparam: &ecsmodel.ResizeServerExtendParam{IsAutoPay: &autoPay},
+			},
+		},
+	}
+
+	resp, err := client.ResizeServer(req)
+	if err != nil {
+		logs.Errorf("resize huawei cvm failed, err: %v, id: %s, rid: %s", err, opt.CloudID, kt.Rid)
+		return err
+	}
+
+	// 按需实例返回任务ID，包年包月实例返回订单号
+	if resp.JobId == nil {
+		return nil
+	}
+
+	handler := &jobPollingHandler{
+		opt.Region,
+	}
+	respPoller := poller.Poller[*HuaWei, []ecsmodel.SubJob, poller.BaseDoneResult]{Handler: handler}
+	_, err = respPoller.PollUntilDone(h, kt, []*string{resp.JobId}, types.NewBatchOperateCvmPollerOpt())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ResizeDisk 扩容云硬盘，包周期云硬盘自动支付扩容订单
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2070.html
+func (h *HuaWei) ResizeDisk(kt *kit.Kit, opt *disk.HuaWeiDiskResizeOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "huawei disk resize option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new evs client failed, err: %v", err)
+	}
+
+	autoPay := evsmodel.GetBssParamForResizeVolumeIsAutoPayEnum().TRUE
+	req := &evsmodel.ResizeVolumeRequest{
+		VolumeId: opt.CloudDiskID,
+		Body: &evsmodel.ResizeVolumeRequestBody{
+			BssParam: &evsmodel.BssParamForResizeVolume{IsAutoPay: &autoPay},
+			OsExtend: &evsmodel.OsExtend{NewSize: opt.DiskSize},
+		},
+	}
+
+	if _, err = client.ResizeVolume(req); err != nil {
+		logs.Errorf("resize huawei disk failed, err: %v, id: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
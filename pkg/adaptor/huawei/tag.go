@@ -0,0 +1,181 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"fmt"
+
+	typestag "hcm/pkg/adaptor/types/tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	ecsmodel "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/ecs/v2/model"
+	evsmodel "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/evs/v2/model"
+)
+
+// TagResources 为资源批量添加标签，已存在的同名标签会被覆盖
+// reference: https://support.huaweicloud.com/api-ecs/ecs_02_1002.html
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2086.html
+func (h *HuaWei) TagResources(kt *kit.Kit, opt *typestag.TagResOption) error {
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	switch opt.ResType {
+	case enumor.CvmCloudResType:
+		return h.tagServers(kt, opt)
+	case enumor.DiskCloudResType:
+		return h.tagVolumes(kt, opt)
+	default:
+		return errf.Newf(errf.InvalidParameter, "huawei resource type %s does not support tag", opt.ResType)
+	}
+}
+
+func (h *HuaWei) tagServers(kt *kit.Kit, opt *typestag.TagResOption) error {
+	client, err := h.clientSet.ecsClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new ecs client failed, err: %v", err)
+	}
+
+	tags := make([]ecsmodel.ServerTag, 0, len(opt.Tags))
+	for key, value := range opt.Tags {
+		tags = append(tags, ecsmodel.ServerTag{Key: key, Value: value})
+	}
+
+	for _, id := range opt.CloudIDs {
+		req := &ecsmodel.BatchCreateServerTagsRequest{
+			ServerId: id,
+			Body: &ecsmodel.BatchCreateServerTagsRequestBody{
+				Action: ecsmodel.GetBatchCreateServerTagsRequestBodyActionEnum().CREATE,
+				Tags:   tags,
+			},
+		}
+		if _, err = client.BatchCreateServerTags(req); err != nil {
+			logs.Errorf("huawei batch create server tags failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *HuaWei) tagVolumes(kt *kit.Kit, opt *typestag.TagResOption) error {
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new evs client failed, err: %v", err)
+	}
+
+	tags := make([]evsmodel.Tag, 0, len(opt.Tags))
+	for key, value := range opt.Tags {
+		tags = append(tags, evsmodel.Tag{Key: key, Value: value})
+	}
+
+	for _, id := range opt.CloudIDs {
+		req := &evsmodel.BatchCreateVolumeTagsRequest{
+			VolumeId: id,
+			Body: &evsmodel.BatchCreateVolumeTagsRequestBody{
+				Action: evsmodel.GetBatchCreateVolumeTagsRequestBodyActionEnum().CREATE,
+				Tags:   tags,
+			},
+		}
+		if _, err = client.BatchCreateVolumeTags(req); err != nil {
+			logs.Errorf("huawei batch create volume tags failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnTagResources 批量删除资源的指定标签
+// reference: https://support.huaweicloud.com/api-ecs/ecs_02_1003.html
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2087.html
+func (h *HuaWei) UnTagResources(kt *kit.Kit, opt *typestag.UnTagResOption) error {
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	switch opt.ResType {
+	case enumor.CvmCloudResType:
+		return h.unTagServers(kt, opt)
+	case enumor.DiskCloudResType:
+		return h.unTagVolumes(kt, opt)
+	default:
+		return errf.Newf(errf.InvalidParameter, "huawei resource type %s does not support tag", opt.ResType)
+	}
+}
+
+func (h *HuaWei) unTagServers(kt *kit.Kit, opt *typestag.UnTagResOption) error {
+	client, err := h.clientSet.ecsClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new ecs client failed, err: %v", err)
+	}
+
+	tags := make([]ecsmodel.ServerTag, 0, len(opt.Keys))
+	for _, key := range opt.Keys {
+		tags = append(tags, ecsmodel.ServerTag{Key: key})
+	}
+
+	for _, id := range opt.CloudIDs {
+		req := &ecsmodel.BatchDeleteServerTagsRequest{
+			ServerId: id,
+			Body: &ecsmodel.BatchDeleteServerTagsRequestBody{
+				Action: ecsmodel.GetBatchDeleteServerTagsRequestBodyActionEnum().DELETE,
+				Tags:   tags,
+			},
+		}
+		if _, err = client.BatchDeleteServerTags(req); err != nil {
+			logs.Errorf("huawei batch delete server tags failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *HuaWei) unTagVolumes(kt *kit.Kit, opt *typestag.UnTagResOption) error {
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new evs client failed, err: %v", err)
+	}
+
+	tags := make([]evsmodel.DeleteTagsOption, 0, len(opt.Keys))
+	for _, key := range opt.Keys {
+		tags = append(tags, evsmodel.DeleteTagsOption{Key: key})
+	}
+
+	for _, id := range opt.CloudIDs {
+		req := &evsmodel.BatchDeleteVolumeTagsRequest{
+			VolumeId: id,
+			Body: &evsmodel.BatchDeleteVolumeTagsRequestBody{
+				Action: evsmodel.GetBatchDeleteVolumeTagsRequestBodyActionEnum().DELETE,
+				Tags:   tags,
+			},
+		}
+		if _, err = client.BatchDeleteVolumeTags(req); err != nil {
+			logs.Errorf("huawei batch delete volume tags failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
@@ -235,6 +235,7 @@ func convertVpc(data *model.Vpc, region string) *types.HuaWeiVpc {
 			Status:              data.Status,
 			EnterpriseProjectId: data.EnterpriseProjectId,
 		},
+		Tags: convVpcTags(data.Tags),
 	}
 
 	if data.Cidr != "" {
@@ -254,6 +255,19 @@ func convertVpc(data *model.Vpc, region string) *types.HuaWeiVpc {
 	return v
 }
 
+// convVpcTags 将华为云 vpc 标签转换为 map
+func convVpcTags(tags []model.Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		result[tag.Key] = tag.Value
+	}
+	return result
+}
+
 type createVpcPollingHandler struct {
 	region string
 }
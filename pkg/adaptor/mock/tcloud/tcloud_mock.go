@@ -16,10 +16,12 @@ import (
 	image "hcm/pkg/adaptor/types/image"
 	instancetype "hcm/pkg/adaptor/types/instance-type"
 	region "hcm/pkg/adaptor/types/region"
+	renewal "hcm/pkg/adaptor/types/renewal"
 	routetable "hcm/pkg/adaptor/types/route-table"
 	securitygroup "hcm/pkg/adaptor/types/security-group"
 	securitygrouprule "hcm/pkg/adaptor/types/security-group-rule"
 	adtysubnet "hcm/pkg/adaptor/types/subnet"
+	tag "hcm/pkg/adaptor/types/tag"
 	zone "hcm/pkg/adaptor/types/zone"
 	cloud "hcm/pkg/api/core/cloud"
 	kit "hcm/pkg/kit"
@@ -520,6 +522,45 @@ func (c *TCloudCreateDiskCall) DoAndReturn(f func(*kit.Kit, *disk.TCloudDiskCrea
 	return c
 }
 
+// CreateDiskSnapshot mocks base method.
+func (m *MockTCloud) CreateDiskSnapshot(kt *kit.Kit, opt *disk.TCloudDiskSnapshotCreateOption) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDiskSnapshot", kt, opt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDiskSnapshot indicates an expected call of CreateDiskSnapshot.
+func (mr *MockTCloudMockRecorder) CreateDiskSnapshot(kt, opt interface{}) *TCloudCreateDiskSnapshotCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDiskSnapshot", reflect.TypeOf((*MockTCloud)(nil).CreateDiskSnapshot), kt, opt)
+	return &TCloudCreateDiskSnapshotCall{Call: call}
+}
+
+// TCloudCreateDiskSnapshotCall wrap *gomock.Call
+type TCloudCreateDiskSnapshotCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudCreateDiskSnapshotCall) Return(arg0 string, arg1 error) *TCloudCreateDiskSnapshotCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudCreateDiskSnapshotCall) Do(f func(*kit.Kit, *disk.TCloudDiskSnapshotCreateOption) (string, error)) *TCloudCreateDiskSnapshotCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudCreateDiskSnapshotCall) DoAndReturn(f func(*kit.Kit, *disk.TCloudDiskSnapshotCreateOption) (string, error)) *TCloudCreateDiskSnapshotCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // CreateEip mocks base method.
 func (m *MockTCloud) CreateEip(kt *kit.Kit, opt *eip.TCloudEipCreateOption) (*poller.BaseDoneResult, error) {
 	m.ctrl.T.Helper()
@@ -559,6 +600,84 @@ func (c *TCloudCreateEipCall) DoAndReturn(f func(*kit.Kit, *eip.TCloudEipCreateO
 	return c
 }
 
+// CreateImage mocks base method.
+func (m *MockTCloud) CreateImage(kt *kit.Kit, opt *image.TCloudImageCreateOption) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImage", kt, opt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImage indicates an expected call of CreateImage.
+func (mr *MockTCloudMockRecorder) CreateImage(kt, opt interface{}) *TCloudCreateImageCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImage", reflect.TypeOf((*MockTCloud)(nil).CreateImage), kt, opt)
+	return &TCloudCreateImageCall{Call: call}
+}
+
+// TCloudCreateImageCall wrap *gomock.Call
+type TCloudCreateImageCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudCreateImageCall) Return(arg0 string, arg1 error) *TCloudCreateImageCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudCreateImageCall) Do(f func(*kit.Kit, *image.TCloudImageCreateOption) (string, error)) *TCloudCreateImageCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudCreateImageCall) DoAndReturn(f func(*kit.Kit, *image.TCloudImageCreateOption) (string, error)) *TCloudCreateImageCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CopyImage mocks base method.
+func (m *MockTCloud) CopyImage(kt *kit.Kit, opt *image.TCloudImageCopyOption) ([]image.CopiedImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyImage", kt, opt)
+	ret0, _ := ret[0].([]image.CopiedImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyImage indicates an expected call of CopyImage.
+func (mr *MockTCloudMockRecorder) CopyImage(kt, opt interface{}) *TCloudCopyImageCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyImage", reflect.TypeOf((*MockTCloud)(nil).CopyImage), kt, opt)
+	return &TCloudCopyImageCall{Call: call}
+}
+
+// TCloudCopyImageCall wrap *gomock.Call
+type TCloudCopyImageCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudCopyImageCall) Return(arg0 []image.CopiedImage, arg1 error) *TCloudCopyImageCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudCopyImageCall) Do(f func(*kit.Kit, *image.TCloudImageCopyOption) ([]image.CopiedImage, error)) *TCloudCopyImageCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudCopyImageCall) DoAndReturn(f func(*kit.Kit, *image.TCloudImageCopyOption) ([]image.CopiedImage, error)) *TCloudCopyImageCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // CreateSecurityGroup mocks base method.
 func (m *MockTCloud) CreateSecurityGroup(kt *kit.Kit, opt *securitygroup.TCloudCreateOption) (*v20170312.SecurityGroup, error) {
 	m.ctrl.T.Helper()
@@ -829,6 +948,44 @@ func (c *TCloudDeleteDiskCall) DoAndReturn(f func(*kit.Kit, *disk.TCloudDiskDele
 	return c
 }
 
+// DeleteDiskSnapshot mocks base method.
+func (m *MockTCloud) DeleteDiskSnapshot(kt *kit.Kit, opt *disk.TCloudDiskSnapshotDeleteOption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDiskSnapshot", kt, opt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDiskSnapshot indicates an expected call of DeleteDiskSnapshot.
+func (mr *MockTCloudMockRecorder) DeleteDiskSnapshot(kt, opt interface{}) *TCloudDeleteDiskSnapshotCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDiskSnapshot", reflect.TypeOf((*MockTCloud)(nil).DeleteDiskSnapshot), kt, opt)
+	return &TCloudDeleteDiskSnapshotCall{Call: call}
+}
+
+// TCloudDeleteDiskSnapshotCall wrap *gomock.Call
+type TCloudDeleteDiskSnapshotCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudDeleteDiskSnapshotCall) Return(arg0 error) *TCloudDeleteDiskSnapshotCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudDeleteDiskSnapshotCall) Do(f func(*kit.Kit, *disk.TCloudDiskSnapshotDeleteOption) error) *TCloudDeleteDiskSnapshotCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudDeleteDiskSnapshotCall) DoAndReturn(f func(*kit.Kit, *disk.TCloudDiskSnapshotDeleteOption) error) *TCloudDeleteDiskSnapshotCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // DeleteEip mocks base method.
 func (m *MockTCloud) DeleteEip(kt *kit.Kit, opt *eip.TCloudEipDeleteOption) error {
 	m.ctrl.T.Helper()
@@ -1368,6 +1525,45 @@ func (c *TCloudInquiryPriceDiskCall) DoAndReturn(f func(*kit.Kit, *disk.TCloudDi
 	return c
 }
 
+// InquiryPriceResizeCvm mocks base method.
+func (m *MockTCloud) InquiryPriceResizeCvm(kt *kit.Kit, opt *cvm.TCloudResizeOption) (*cvm.InquiryPriceResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InquiryPriceResizeCvm", kt, opt)
+	ret0, _ := ret[0].(*cvm.InquiryPriceResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InquiryPriceResizeCvm indicates an expected call of InquiryPriceResizeCvm.
+func (mr *MockTCloudMockRecorder) InquiryPriceResizeCvm(kt, opt interface{}) *TCloudInquiryPriceResizeCvmCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InquiryPriceResizeCvm", reflect.TypeOf((*MockTCloud)(nil).InquiryPriceResizeCvm), kt, opt)
+	return &TCloudInquiryPriceResizeCvmCall{Call: call}
+}
+
+// TCloudInquiryPriceResizeCvmCall wrap *gomock.Call
+type TCloudInquiryPriceResizeCvmCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudInquiryPriceResizeCvmCall) Return(arg0 *cvm.InquiryPriceResult, arg1 error) *TCloudInquiryPriceResizeCvmCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudInquiryPriceResizeCvmCall) Do(f func(*kit.Kit, *cvm.TCloudResizeOption) (*cvm.InquiryPriceResult, error)) *TCloudInquiryPriceResizeCvmCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudInquiryPriceResizeCvmCall) DoAndReturn(f func(*kit.Kit, *cvm.TCloudResizeOption) (*cvm.InquiryPriceResult, error)) *TCloudInquiryPriceResizeCvmCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // ListAccount mocks base method.
 func (m *MockTCloud) ListAccount(kt *kit.Kit) ([]account.TCloudAccount, error) {
 	m.ctrl.T.Helper()
@@ -1485,6 +1681,45 @@ func (c *TCloudListDiskCall) DoAndReturn(f func(*kit.Kit, *core.TCloudListOption
 	return c
 }
 
+// ListDiskSnapshot mocks base method.
+func (m *MockTCloud) ListDiskSnapshot(kt *kit.Kit, opt *disk.TCloudDiskSnapshotListOption) ([]disk.DiskSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDiskSnapshot", kt, opt)
+	ret0, _ := ret[0].([]disk.DiskSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDiskSnapshot indicates an expected call of ListDiskSnapshot.
+func (mr *MockTCloudMockRecorder) ListDiskSnapshot(kt, opt interface{}) *TCloudListDiskSnapshotCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDiskSnapshot", reflect.TypeOf((*MockTCloud)(nil).ListDiskSnapshot), kt, opt)
+	return &TCloudListDiskSnapshotCall{Call: call}
+}
+
+// TCloudListDiskSnapshotCall wrap *gomock.Call
+type TCloudListDiskSnapshotCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudListDiskSnapshotCall) Return(arg0 []disk.DiskSnapshot, arg1 error) *TCloudListDiskSnapshotCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudListDiskSnapshotCall) Do(f func(*kit.Kit, *disk.TCloudDiskSnapshotListOption) ([]disk.DiskSnapshot, error)) *TCloudListDiskSnapshotCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudListDiskSnapshotCall) DoAndReturn(f func(*kit.Kit, *disk.TCloudDiskSnapshotListOption) ([]disk.DiskSnapshot, error)) *TCloudListDiskSnapshotCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // ListEip mocks base method.
 func (m *MockTCloud) ListEip(kt *kit.Kit, opt *eip.TCloudEipListOption) (*eip.TCloudEipListResult, error) {
 	m.ctrl.T.Helper()
@@ -1641,6 +1876,45 @@ func (c *TCloudListPoliciesGrantingServiceAccessCall) DoAndReturn(f func(*kit.Ki
 	return c
 }
 
+// ListPrivateImageState mocks base method.
+func (m *MockTCloud) ListPrivateImageState(kt *kit.Kit, opt *image.PrivateImageStateListOption) ([]image.PrivateImageState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPrivateImageState", kt, opt)
+	ret0, _ := ret[0].([]image.PrivateImageState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPrivateImageState indicates an expected call of ListPrivateImageState.
+func (mr *MockTCloudMockRecorder) ListPrivateImageState(kt, opt interface{}) *TCloudListPrivateImageStateCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPrivateImageState", reflect.TypeOf((*MockTCloud)(nil).ListPrivateImageState), kt, opt)
+	return &TCloudListPrivateImageStateCall{Call: call}
+}
+
+// TCloudListPrivateImageStateCall wrap *gomock.Call
+type TCloudListPrivateImageStateCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudListPrivateImageStateCall) Return(arg0 []image.PrivateImageState, arg1 error) *TCloudListPrivateImageStateCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudListPrivateImageStateCall) Do(f func(*kit.Kit, *image.PrivateImageStateListOption) ([]image.PrivateImageState, error)) *TCloudListPrivateImageStateCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudListPrivateImageStateCall) DoAndReturn(f func(*kit.Kit, *image.PrivateImageStateListOption) ([]image.PrivateImageState, error)) *TCloudListPrivateImageStateCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // ListRegion mocks base method.
 func (m *MockTCloud) ListRegion(kt *kit.Kit) (*region.TCloudRegionListResult, error) {
 	m.ctrl.T.Helper()
@@ -1952,6 +2226,45 @@ func (c *TCloudRebootCvmCall) DoAndReturn(f func(*kit.Kit, *cvm.TCloudRebootOpti
 	return c
 }
 
+// RenewResources mocks base method.
+func (m *MockTCloud) RenewResources(kt *kit.Kit, opt *renewal.RenewResOption) (*renewal.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewResources", kt, opt)
+	ret0, _ := ret[0].(*renewal.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewResources indicates an expected call of RenewResources.
+func (mr *MockTCloudMockRecorder) RenewResources(kt, opt interface{}) *TCloudRenewResourcesCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewResources", reflect.TypeOf((*MockTCloud)(nil).RenewResources), kt, opt)
+	return &TCloudRenewResourcesCall{Call: call}
+}
+
+// TCloudRenewResourcesCall wrap *gomock.Call
+type TCloudRenewResourcesCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudRenewResourcesCall) Return(arg0 *renewal.BatchResult, arg1 error) *TCloudRenewResourcesCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudRenewResourcesCall) Do(f func(*kit.Kit, *renewal.RenewResOption) (*renewal.BatchResult, error)) *TCloudRenewResourcesCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudRenewResourcesCall) DoAndReturn(f func(*kit.Kit, *renewal.RenewResOption) (*renewal.BatchResult, error)) *TCloudRenewResourcesCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // ResetCvmPwd mocks base method.
 func (m *MockTCloud) ResetCvmPwd(kt *kit.Kit, opt *cvm.TCloudResetPwdOption) error {
 	m.ctrl.T.Helper()
@@ -1990,6 +2303,120 @@ func (c *TCloudResetCvmPwdCall) DoAndReturn(f func(*kit.Kit, *cvm.TCloudResetPwd
 	return c
 }
 
+// ResizeCvm mocks base method.
+func (m *MockTCloud) ResizeCvm(kt *kit.Kit, opt *cvm.TCloudResizeOption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResizeCvm", kt, opt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResizeCvm indicates an expected call of ResizeCvm.
+func (mr *MockTCloudMockRecorder) ResizeCvm(kt, opt interface{}) *TCloudResizeCvmCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResizeCvm", reflect.TypeOf((*MockTCloud)(nil).ResizeCvm), kt, opt)
+	return &TCloudResizeCvmCall{Call: call}
+}
+
+// TCloudResizeCvmCall wrap *gomock.Call
+type TCloudResizeCvmCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudResizeCvmCall) Return(arg0 error) *TCloudResizeCvmCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudResizeCvmCall) Do(f func(*kit.Kit, *cvm.TCloudResizeOption) error) *TCloudResizeCvmCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudResizeCvmCall) DoAndReturn(f func(*kit.Kit, *cvm.TCloudResizeOption) error) *TCloudResizeCvmCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ResizeDisk mocks base method.
+func (m *MockTCloud) ResizeDisk(kt *kit.Kit, opt *disk.TCloudDiskResizeOption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResizeDisk", kt, opt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResizeDisk indicates an expected call of ResizeDisk.
+func (mr *MockTCloudMockRecorder) ResizeDisk(kt, opt interface{}) *TCloudResizeDiskCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResizeDisk", reflect.TypeOf((*MockTCloud)(nil).ResizeDisk), kt, opt)
+	return &TCloudResizeDiskCall{Call: call}
+}
+
+// TCloudResizeDiskCall wrap *gomock.Call
+type TCloudResizeDiskCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudResizeDiskCall) Return(arg0 error) *TCloudResizeDiskCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudResizeDiskCall) Do(f func(*kit.Kit, *disk.TCloudDiskResizeOption) error) *TCloudResizeDiskCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudResizeDiskCall) DoAndReturn(f func(*kit.Kit, *disk.TCloudDiskResizeOption) error) *TCloudResizeDiskCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// RollbackDiskSnapshot mocks base method.
+func (m *MockTCloud) RollbackDiskSnapshot(kt *kit.Kit, opt *disk.TCloudDiskSnapshotRollbackOption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackDiskSnapshot", kt, opt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackDiskSnapshot indicates an expected call of RollbackDiskSnapshot.
+func (mr *MockTCloudMockRecorder) RollbackDiskSnapshot(kt, opt interface{}) *TCloudRollbackDiskSnapshotCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackDiskSnapshot", reflect.TypeOf((*MockTCloud)(nil).RollbackDiskSnapshot), kt, opt)
+	return &TCloudRollbackDiskSnapshotCall{Call: call}
+}
+
+// TCloudRollbackDiskSnapshotCall wrap *gomock.Call
+type TCloudRollbackDiskSnapshotCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudRollbackDiskSnapshotCall) Return(arg0 error) *TCloudRollbackDiskSnapshotCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudRollbackDiskSnapshotCall) Do(f func(*kit.Kit, *disk.TCloudDiskSnapshotRollbackOption) error) *TCloudRollbackDiskSnapshotCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudRollbackDiskSnapshotCall) DoAndReturn(f func(*kit.Kit, *disk.TCloudDiskSnapshotRollbackOption) error) *TCloudRollbackDiskSnapshotCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // SecurityGroupCvmAssociate mocks base method.
 func (m *MockTCloud) SecurityGroupCvmAssociate(kt *kit.Kit, opt *securitygroup.TCloudAssociateCvmOption) error {
 	m.ctrl.T.Helper()
@@ -2066,6 +2493,83 @@ func (c *TCloudSecurityGroupCvmDisassociateCall) DoAndReturn(f func(*kit.Kit, *s
 	return c
 }
 
+// SetResourcesAutoRenew mocks base method.
+func (m *MockTCloud) SetResourcesAutoRenew(kt *kit.Kit, opt *renewal.SetAutoRenewOption) (*renewal.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetResourcesAutoRenew", kt, opt)
+	ret0, _ := ret[0].(*renewal.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetResourcesAutoRenew indicates an expected call of SetResourcesAutoRenew.
+func (mr *MockTCloudMockRecorder) SetResourcesAutoRenew(kt, opt interface{}) *TCloudSetResourcesAutoRenewCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResourcesAutoRenew", reflect.TypeOf((*MockTCloud)(nil).SetResourcesAutoRenew), kt, opt)
+	return &TCloudSetResourcesAutoRenewCall{Call: call}
+}
+
+// TCloudSetResourcesAutoRenewCall wrap *gomock.Call
+type TCloudSetResourcesAutoRenewCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudSetResourcesAutoRenewCall) Return(arg0 *renewal.BatchResult, arg1 error) *TCloudSetResourcesAutoRenewCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudSetResourcesAutoRenewCall) Do(f func(*kit.Kit, *renewal.SetAutoRenewOption) (*renewal.BatchResult, error)) *TCloudSetResourcesAutoRenewCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudSetResourcesAutoRenewCall) DoAndReturn(f func(*kit.Kit, *renewal.SetAutoRenewOption) (*renewal.BatchResult, error)) *TCloudSetResourcesAutoRenewCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ShareImage mocks base method.
+func (m *MockTCloud) ShareImage(kt *kit.Kit, opt *image.TCloudImageShareOption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShareImage", kt, opt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ShareImage indicates an expected call of ShareImage.
+func (mr *MockTCloudMockRecorder) ShareImage(kt, opt interface{}) *TCloudShareImageCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareImage", reflect.TypeOf((*MockTCloud)(nil).ShareImage), kt, opt)
+	return &TCloudShareImageCall{Call: call}
+}
+
+// TCloudShareImageCall wrap *gomock.Call
+type TCloudShareImageCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudShareImageCall) Return(arg0 error) *TCloudShareImageCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudShareImageCall) Do(f func(*kit.Kit, *image.TCloudImageShareOption) error) *TCloudShareImageCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudShareImageCall) DoAndReturn(f func(*kit.Kit, *image.TCloudImageShareOption) error) *TCloudShareImageCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // StartCvm mocks base method.
 func (m *MockTCloud) StartCvm(kt *kit.Kit, opt *cvm.TCloudStartOption) error {
 	m.ctrl.T.Helper()
@@ -2142,6 +2646,82 @@ func (c *TCloudStopCvmCall) DoAndReturn(f func(*kit.Kit, *cvm.TCloudStopOption)
 	return c
 }
 
+// TagResources mocks base method.
+func (m *MockTCloud) TagResources(kt *kit.Kit, opt *tag.TagResOption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagResources", kt, opt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TagResources indicates an expected call of TagResources.
+func (mr *MockTCloudMockRecorder) TagResources(kt, opt interface{}) *TCloudTagResourcesCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagResources", reflect.TypeOf((*MockTCloud)(nil).TagResources), kt, opt)
+	return &TCloudTagResourcesCall{Call: call}
+}
+
+// TCloudTagResourcesCall wrap *gomock.Call
+type TCloudTagResourcesCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudTagResourcesCall) Return(arg0 error) *TCloudTagResourcesCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudTagResourcesCall) Do(f func(*kit.Kit, *tag.TagResOption) error) *TCloudTagResourcesCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudTagResourcesCall) DoAndReturn(f func(*kit.Kit, *tag.TagResOption) error) *TCloudTagResourcesCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UnTagResources mocks base method.
+func (m *MockTCloud) UnTagResources(kt *kit.Kit, opt *tag.UnTagResOption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnTagResources", kt, opt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnTagResources indicates an expected call of UnTagResources.
+func (mr *MockTCloudMockRecorder) UnTagResources(kt, opt interface{}) *TCloudUnTagResourcesCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnTagResources", reflect.TypeOf((*MockTCloud)(nil).UnTagResources), kt, opt)
+	return &TCloudUnTagResourcesCall{Call: call}
+}
+
+// TCloudUnTagResourcesCall wrap *gomock.Call
+type TCloudUnTagResourcesCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudUnTagResourcesCall) Return(arg0 error) *TCloudUnTagResourcesCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudUnTagResourcesCall) Do(f func(*kit.Kit, *tag.UnTagResOption) error) *TCloudUnTagResourcesCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudUnTagResourcesCall) DoAndReturn(f func(*kit.Kit, *tag.UnTagResOption) error) *TCloudUnTagResourcesCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // UpdateRouteTable mocks base method.
 func (m *MockTCloud) UpdateRouteTable(arg0 *kit.Kit, arg1 *routetable.TCloudRouteTableUpdateOption) error {
 	m.ctrl.T.Helper()
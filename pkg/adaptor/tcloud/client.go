@@ -47,6 +47,7 @@ type ClientSet interface {
 	BillClient() (*billing.Client, error)
 	ClbClient(region string) (*clb.Client, error)
 	CertClient() (*ssl.Client, error)
+	TagClient() *common.Client
 }
 
 // clientSet to get tcloud sdk client set
@@ -131,3 +132,8 @@ func (c *clientSet) CertClient() (*ssl.Client, error) {
 
 	return client, nil
 }
+
+// TagClient tcloud tag common client, sdk 未引入标签服务包, 使用通用请求调用
+func (c *clientSet) TagClient() *common.Client {
+	return common.NewCommonClient(c.credential, "", c.profile)
+}
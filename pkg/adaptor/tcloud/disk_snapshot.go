@@ -0,0 +1,239 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"fmt"
+
+	"hcm/pkg/adaptor/poller"
+	adcore "hcm/pkg/adaptor/types/core"
+	"hcm/pkg/adaptor/types/disk"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	cbs "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cbs/v20170312"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+)
+
+// CreateDiskSnapshot 为云硬盘创建快照，并等待快照创建完成
+// reference: https://cloud.tencent.com/document/api/362/15648
+func (t *TCloudImpl) CreateDiskSnapshot(kt *kit.Kit, opt *disk.TCloudDiskSnapshotCreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "tcloud disk snapshot create option is required")
+	}
+
+	req, err := opt.ToCreateSnapshotRequest()
+	if err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := t.clientSet.CbsClient(opt.Region)
+	if err != nil {
+		return "", fmt.Errorf("new tcloud cbs client failed, err: %v", err)
+	}
+
+	resp, err := client.CreateSnapshotWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("tcloud create disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return "", err
+	}
+
+	snapshotID := converter.PtrToVal(resp.Response.SnapshotId)
+	if len(snapshotID) == 0 {
+		return "", fmt.Errorf("tcloud create disk snapshot but return snapshot id is empty, disk: %s",
+			opt.CloudDiskID)
+	}
+
+	respPoller := poller.Poller[*TCloudImpl, []*cbs.Snapshot, poller.BaseDoneResult]{
+		Handler: &createSnapshotPollingHandler{region: opt.Region},
+	}
+	result, err := respPoller.PollUntilDone(t, kt, []*string{common.StringPtr(snapshotID)}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if len(result.SuccessCloudIDs) == 0 {
+		return "", fmt.Errorf("tcloud disk snapshot %s is not created successfully, message: %s", snapshotID,
+			result.FailedMessage)
+	}
+
+	return snapshotID, nil
+}
+
+// ListDiskSnapshot 查询云硬盘快照，分页查询全部满足条件的快照
+// reference: https://cloud.tencent.com/document/api/362/15647
+func (t *TCloudImpl) ListDiskSnapshot(kt *kit.Kit, opt *disk.TCloudDiskSnapshotListOption) (
+	[]disk.DiskSnapshot, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "tcloud disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := t.clientSet.CbsClient(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("new tcloud cbs client failed, err: %v", err)
+	}
+
+	req := cbs.NewDescribeSnapshotsRequest()
+	// SnapshotIds 与 Filters 不能同时指定
+	if len(opt.CloudIDs) != 0 {
+		req.SnapshotIds = common.StringPtrs(opt.CloudIDs)
+	} else if len(opt.CloudDiskIDs) != 0 {
+		req.Filters = []*cbs.Filter{{Name: common.StringPtr("disk-id"), Values: common.StringPtrs(opt.CloudDiskIDs)}}
+	}
+	req.Limit = common.Uint64Ptr(uint64(adcore.TCloudQueryLimit))
+
+	snapshots := make([]disk.DiskSnapshot, 0)
+	for offset := uint64(0); ; offset += uint64(adcore.TCloudQueryLimit) {
+		req.Offset = common.Uint64Ptr(offset)
+		resp, err := client.DescribeSnapshotsWithContext(kt.Ctx, req)
+		if err != nil {
+			logs.Errorf("list tcloud disk snapshot failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range resp.Response.SnapshotSet {
+			snapshots = append(snapshots, disk.DiskSnapshot{
+				CloudID:          converter.PtrToVal(one.SnapshotId),
+				Name:             converter.PtrToVal(one.SnapshotName),
+				Region:           opt.Region,
+				CloudDiskID:      converter.PtrToVal(one.DiskId),
+				DiskSize:         converter.PtrToVal(one.DiskSize),
+				Status:           converter.PtrToVal(one.SnapshotState),
+				CloudCreatedTime: converter.PtrToVal(one.CreateTime),
+			})
+		}
+
+		if len(resp.Response.SnapshotSet) < adcore.TCloudQueryLimit {
+			break
+		}
+	}
+
+	return snapshots, nil
+}
+
+// DeleteDiskSnapshot 删除云硬盘快照
+// reference: https://cloud.tencent.com/document/api/362/15649
+func (t *TCloudImpl) DeleteDiskSnapshot(kt *kit.Kit, opt *disk.TCloudDiskSnapshotDeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "tcloud disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := t.clientSet.CbsClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new tcloud cbs client failed, err: %v", err)
+	}
+
+	req := cbs.NewDeleteSnapshotsRequest()
+	req.SnapshotIds = common.StringPtrs(opt.CloudIDs)
+	if _, err = client.DeleteSnapshotsWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("delete tcloud disk snapshot failed, err: %v, ids: %v, rid: %s", err, opt.CloudIDs, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// RollbackDiskSnapshot 将快照回滚到源云硬盘，云硬盘挂载在运行中的主机上时会先自动关机，回滚后再自动开机
+// reference: https://cloud.tencent.com/document/api/362/15650
+func (t *TCloudImpl) RollbackDiskSnapshot(kt *kit.Kit, opt *disk.TCloudDiskSnapshotRollbackOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "tcloud disk snapshot rollback option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := t.clientSet.CbsClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new tcloud cbs client failed, err: %v", err)
+	}
+
+	req := cbs.NewApplySnapshotRequest()
+	req.SnapshotId = common.StringPtr(opt.CloudID)
+	req.DiskId = common.StringPtr(opt.CloudDiskID)
+	req.AutoStopInstance = common.BoolPtr(true)
+	req.AutoStartInstance = common.BoolPtr(true)
+	if _, err = client.ApplySnapshotWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("rollback tcloud disk snapshot failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+type createSnapshotPollingHandler struct {
+	region string
+}
+
+// Done 快照状态为NORMAL时创建完成
+func (h *createSnapshotPollingHandler) Done(pollResult []*cbs.Snapshot) (bool, *poller.BaseDoneResult) {
+	result := new(poller.BaseDoneResult)
+	for _, one := range pollResult {
+		switch converter.PtrToVal(one.SnapshotState) {
+		case "NORMAL":
+			result.SuccessCloudIDs = append(result.SuccessCloudIDs, converter.PtrToVal(one.SnapshotId))
+		case "CREATING":
+			result.UnknownCloudIDs = append(result.UnknownCloudIDs, converter.PtrToVal(one.SnapshotId))
+		default:
+			result.FailedCloudIDs = append(result.FailedCloudIDs, converter.PtrToVal(one.SnapshotId))
+			result.FailedMessage = fmt.Sprintf("snapshot state: %s", converter.PtrToVal(one.SnapshotState))
+		}
+	}
+
+	return len(result.UnknownCloudIDs) == 0, result
+}
+
+// Poll 查询快照状态
+func (h *createSnapshotPollingHandler) Poll(client *TCloudImpl, kt *kit.Kit, cloudIDs []*string) (
+	[]*cbs.Snapshot, error) {
+
+	cbsClient, err := client.clientSet.CbsClient(h.region)
+	if err != nil {
+		return nil, fmt.Errorf("new tcloud cbs client failed, err: %v", err)
+	}
+
+	req := cbs.NewDescribeSnapshotsRequest()
+	req.SnapshotIds = cloudIDs
+	resp, err := cbsClient.DescribeSnapshotsWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("describe tcloud disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	if len(resp.Response.SnapshotSet) != len(cloudIDs) {
+		return nil, fmt.Errorf("tcloud disk snapshot %v not found", converter.PtrToSlice(cloudIDs))
+	}
+
+	return resp.Response.SnapshotSet, nil
+}
+
+var _ poller.PollingHandler[*TCloudImpl, []*cbs.Snapshot, poller.BaseDoneResult] = new(createSnapshotPollingHandler)
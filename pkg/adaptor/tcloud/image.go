@@ -22,12 +22,15 @@ package tcloud
 import (
 	"fmt"
 
+	"hcm/pkg/adaptor/poller"
 	"hcm/pkg/adaptor/types/core"
 	"hcm/pkg/adaptor/types/image"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
 
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
 	cvm "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cvm/v20170312"
@@ -86,6 +89,246 @@ func (t *TCloudImpl) ListImage(kt *kit.Kit,
 	return &image.TCloudImageListResult{Details: images}, nil
 }
 
+// CreateImage 基于主机创建自定义镜像，并等待镜像创建完成
+// reference: https://cloud.tencent.com/document/api/213/16726
+func (t *TCloudImpl) CreateImage(kt *kit.Kit, opt *image.TCloudImageCreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "tcloud image create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := t.clientSet.CvmClient(opt.Region)
+	if err != nil {
+		return "", fmt.Errorf("new tcloud cvm client failed, err: %v", err)
+	}
+
+	req := cvm.NewCreateImageRequest()
+	req.InstanceId = common.StringPtr(opt.CloudCvmID)
+	req.ImageName = common.StringPtr(opt.ImageName)
+	if opt.ForcePoweroff {
+		req.ForcePoweroff = common.StringPtr("TRUE")
+	}
+
+	resp, err := client.CreateImageWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("tcloud create image failed, err: %v, cvm: %s, rid: %s", err, opt.CloudCvmID, kt.Rid)
+		return "", err
+	}
+
+	imageID := converter.PtrToVal(resp.Response.ImageId)
+	if len(imageID) == 0 {
+		return "", fmt.Errorf("tcloud create image but return image id is empty, cvm: %s", opt.CloudCvmID)
+	}
+
+	respPoller := poller.Poller[*TCloudImpl, []*cvm.Image, poller.BaseDoneResult]{
+		Handler: &createImagePollingHandler{region: opt.Region},
+	}
+	result, err := respPoller.PollUntilDone(t, kt, []*string{common.StringPtr(imageID)}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if len(result.SuccessCloudIDs) == 0 {
+		return "", fmt.Errorf("tcloud image %s is not created successfully, message: %s", imageID,
+			result.FailedMessage)
+	}
+
+	return imageID, nil
+}
+
+// CopyImage 将自定义镜像复制到其他地域，并等待各目的地域的镜像复制完成
+// reference: https://cloud.tencent.com/document/api/213/30551
+func (t *TCloudImpl) CopyImage(kt *kit.Kit, opt *image.TCloudImageCopyOption) ([]image.CopiedImage, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "tcloud image copy option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := t.clientSet.CvmClient(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("new tcloud cvm client failed, err: %v", err)
+	}
+
+	req := cvm.NewSyncImagesRequest()
+	req.ImageIds = common.StringPtrs([]string{opt.CloudImageID})
+	req.DestinationRegions = common.StringPtrs(opt.DstRegions)
+	req.ImageSetRequired = common.BoolPtr(true)
+	if len(opt.ImageName) != 0 {
+		req.ImageName = common.StringPtr(opt.ImageName)
+	}
+
+	resp, err := client.SyncImagesWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("tcloud sync images failed, err: %v, image: %s, rid: %s", err, opt.CloudImageID, kt.Rid)
+		return nil, err
+	}
+
+	copied := make([]image.CopiedImage, 0, len(resp.Response.ImageSet))
+	for _, one := range resp.Response.ImageSet {
+		copied = append(copied, image.CopiedImage{
+			Region:       converter.PtrToVal(one.Region),
+			CloudImageID: converter.PtrToVal(one.ImageId),
+		})
+	}
+
+	if len(copied) != len(opt.DstRegions) {
+		return nil, fmt.Errorf("tcloud sync image %s to %d regions, but return %d images", opt.CloudImageID,
+			len(opt.DstRegions), len(copied))
+	}
+
+	for _, one := range copied {
+		respPoller := poller.Poller[*TCloudImpl, []*cvm.Image, poller.BaseDoneResult]{
+			Handler: &createImagePollingHandler{region: one.Region},
+		}
+		result, err := respPoller.PollUntilDone(t, kt, []*string{common.StringPtr(one.CloudImageID)}, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(result.SuccessCloudIDs) == 0 {
+			return nil, fmt.Errorf("tcloud image %s is not copied to %s successfully, message: %s",
+				one.CloudImageID, one.Region, result.FailedMessage)
+		}
+	}
+
+	return copied, nil
+}
+
+// ShareImage 将自定义镜像共享给其他腾讯云主账号
+// reference: https://cloud.tencent.com/document/api/213/15710
+func (t *TCloudImpl) ShareImage(kt *kit.Kit, opt *image.TCloudImageShareOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "tcloud image share option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := t.clientSet.CvmClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new tcloud cvm client failed, err: %v", err)
+	}
+
+	req := cvm.NewModifyImageSharePermissionRequest()
+	req.ImageId = common.StringPtr(opt.CloudImageID)
+	req.AccountIds = common.StringPtrs(opt.CloudMainAccountIDs)
+	req.Permission = common.StringPtr("SHARE")
+
+	if _, err = client.ModifyImageSharePermissionWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("tcloud share image failed, err: %v, image: %s, accounts: %v, rid: %s", err, opt.CloudImageID,
+			opt.CloudMainAccountIDs, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// ListPrivateImageState 分页查询地域下的全部自定义镜像及其状态，已删除的镜像不会返回
+// reference: https://cloud.tencent.com/document/api/213/15715
+func (t *TCloudImpl) ListPrivateImageState(kt *kit.Kit, opt *image.PrivateImageStateListOption) (
+	[]image.PrivateImageState, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "tcloud private image state list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := t.clientSet.CvmClient(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("new tcloud cvm client failed, err: %v", err)
+	}
+
+	req := cvm.NewDescribeImagesRequest()
+	req.Filters = []*cvm.Filter{{
+		Name:   common.StringPtr("image-type"),
+		Values: common.StringPtrs([]string{"PRIVATE_IMAGE"}),
+	}}
+	req.Offset = common.Uint64Ptr(0)
+	req.Limit = common.Uint64Ptr(uint64(core.TCloudQueryLimit))
+
+	states := make([]image.PrivateImageState, 0)
+	for {
+		resp, err := client.DescribeImagesWithContext(kt.Ctx, req)
+		if err != nil {
+			logs.Errorf("describe tcloud private images failed, err: %v, region: %s, rid: %s", err, opt.Region,
+				kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range resp.Response.ImageSet {
+			states = append(states, image.PrivateImageState{
+				CloudImageID: converter.PtrToVal(one.ImageId),
+				State:        converter.PtrToVal(one.ImageState),
+			})
+		}
+
+		if len(resp.Response.ImageSet) < core.TCloudQueryLimit {
+			break
+		}
+		req.Offset = common.Uint64Ptr(*req.Offset + uint64(core.TCloudQueryLimit))
+	}
+
+	return states, nil
+}
+
+type createImagePollingHandler struct {
+	region string
+}
+
+// Done 镜像状态为NORMAL时创建完成
+func (h *createImagePollingHandler) Done(pollResult []*cvm.Image) (bool, *poller.BaseDoneResult) {
+	result := new(poller.BaseDoneResult)
+	for _, one := range pollResult {
+		switch converter.PtrToVal(one.ImageState) {
+		case "NORMAL":
+			result.SuccessCloudIDs = append(result.SuccessCloudIDs, converter.PtrToVal(one.ImageId))
+		case "CREATEFAILED":
+			result.FailedCloudIDs = append(result.FailedCloudIDs, converter.PtrToVal(one.ImageId))
+			result.FailedMessage = fmt.Sprintf("image state: %s", converter.PtrToVal(one.ImageState))
+		default:
+			result.UnknownCloudIDs = append(result.UnknownCloudIDs, converter.PtrToVal(one.ImageId))
+		}
+	}
+
+	return len(result.UnknownCloudIDs) == 0, result
+}
+
+// Poll 查询镜像状态
+func (h *createImagePollingHandler) Poll(client *TCloudImpl, kt *kit.Kit, cloudIDs []*string) ([]*cvm.Image,
+	error) {
+
+	cvmClient, err := client.clientSet.CvmClient(h.region)
+	if err != nil {
+		return nil, fmt.Errorf("new tcloud cvm client failed, err: %v", err)
+	}
+
+	req := cvm.NewDescribeImagesRequest()
+	req.ImageIds = cloudIDs
+	resp, err := cvmClient.DescribeImagesWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("describe tcloud images failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	if len(resp.Response.ImageSet) != len(cloudIDs) {
+		return nil, fmt.Errorf("tcloud image %v not found", converter.PtrToSlice(cloudIDs))
+	}
+
+	return resp.Response.ImageSet, nil
+}
+
+var _ poller.PollingHandler[*TCloudImpl, []*cvm.Image, poller.BaseDoneResult] = new(createImagePollingHandler)
+
 func changeArchitecture(architecture *string) string {
 	if architecture == nil {
 		return constant.X86
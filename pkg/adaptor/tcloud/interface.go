@@ -36,10 +36,12 @@ import (
 	"hcm/pkg/adaptor/types/instance-type"
 	typelb "hcm/pkg/adaptor/types/load-balancer"
 	"hcm/pkg/adaptor/types/region"
+	typesrenewal "hcm/pkg/adaptor/types/renewal"
 	"hcm/pkg/adaptor/types/route-table"
 	"hcm/pkg/adaptor/types/security-group"
 	"hcm/pkg/adaptor/types/security-group-rule"
 	"hcm/pkg/adaptor/types/subnet"
+	typestag "hcm/pkg/adaptor/types/tag"
 	"hcm/pkg/adaptor/types/zone"
 	"hcm/pkg/api/core/cloud"
 	"hcm/pkg/kit"
@@ -54,6 +56,10 @@ import (
 type TCloud interface {
 	ListImage(kt *kit.Kit,
 		opt *image.TCloudImageListOption) (*image.TCloudImageListResult, error)
+	CreateImage(kt *kit.Kit, opt *image.TCloudImageCreateOption) (string, error)
+	CopyImage(kt *kit.Kit, opt *image.TCloudImageCopyOption) ([]image.CopiedImage, error)
+	ShareImage(kt *kit.Kit, opt *image.TCloudImageShareOption) error
+	ListPrivateImageState(kt *kit.Kit, opt *image.PrivateImageStateListOption) ([]image.PrivateImageState, error)
 	CreateSubnet(kt *kit.Kit, opt *adtysubnet.TCloudSubnetCreateOption) (*adtysubnet.TCloudSubnet,
 		error)
 	CreateSubnets(kt *kit.Kit, opt *adtysubnet.TCloudSubnetsCreateOption) ([]adtysubnet.TCloudSubnet,
@@ -87,6 +93,11 @@ type TCloud interface {
 	DeleteDisk(kt *kit.Kit, opt *disk.TCloudDiskDeleteOption) error
 	AttachDisk(kt *kit.Kit, opt *disk.TCloudDiskAttachOption) error
 	DetachDisk(kt *kit.Kit, opt *disk.TCloudDiskDetachOption) error
+	CreateDiskSnapshot(kt *kit.Kit, opt *disk.TCloudDiskSnapshotCreateOption) (string, error)
+	ListDiskSnapshot(kt *kit.Kit, opt *disk.TCloudDiskSnapshotListOption) ([]disk.DiskSnapshot, error)
+	DeleteDiskSnapshot(kt *kit.Kit, opt *disk.TCloudDiskSnapshotDeleteOption) error
+	RollbackDiskSnapshot(kt *kit.Kit, opt *disk.TCloudDiskSnapshotRollbackOption) error
+	ResizeDisk(kt *kit.Kit, opt *disk.TCloudDiskResizeOption) error
 	ListEip(kt *kit.Kit, opt *eip.TCloudEipListOption) (*eip.TCloudEipListResult, error)
 	CountEip(kt *kit.Kit, region string) (int32, error)
 	DeleteEip(kt *kit.Kit, opt *eip.TCloudEipDeleteOption) error
@@ -123,6 +134,9 @@ type TCloud interface {
 	CreateCvm(kt *kit.Kit, opt *cvm.TCloudCreateOption) (*poller.BaseDoneResult, error)
 	InquiryPriceCvm(kt *kit.Kit, opt *cvm.TCloudCreateOption) (
 		*cvm.InquiryPriceResult, error)
+	ResizeCvm(kt *kit.Kit, opt *cvm.TCloudResizeOption) error
+	InquiryPriceResizeCvm(kt *kit.Kit, opt *cvm.TCloudResizeOption) (
+		*cvm.InquiryPriceResult, error)
 	ListPoliciesGrantingServiceAccess(kt *kit.Kit, opt *account.TCloudListPolicyOption) (
 		[]*v20190116.ListGrantServiceAccessNode, error)
 	ListArgsTplAddress(kt *kit.Kit, opt *typeargstpl.TCloudListOption) (
@@ -187,4 +201,10 @@ type TCloud interface {
 
 	CreateLoadBalancerSnatIps(kt *kit.Kit, opt *typelb.TCloudCreateSnatIpOpt) error
 	DeleteLoadBalancerSnatIps(kt *kit.Kit, opt *typelb.TCloudDeleteSnatIpOpt) error
+
+	TagResources(kt *kit.Kit, opt *typestag.TagResOption) error
+	UnTagResources(kt *kit.Kit, opt *typestag.UnTagResOption) error
+
+	RenewResources(kt *kit.Kit, opt *typesrenewal.RenewResOption) (*typesrenewal.BatchResult, error)
+	SetResourcesAutoRenew(kt *kit.Kit, opt *typesrenewal.SetAutoRenewOption) (*typesrenewal.BatchResult, error)
 }
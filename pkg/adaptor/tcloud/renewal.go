@@ -0,0 +1,161 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"fmt"
+
+	typesrenewal "hcm/pkg/adaptor/types/renewal"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	cbs "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cbs/v20170312"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	cvm "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cvm/v20170312"
+)
+
+const (
+	// renewFlagAuto 通知过期且自动续费
+	renewFlagAuto = "NOTIFY_AND_AUTO_RENEW"
+	// renewFlagManual 通知过期不自动续费
+	renewFlagManual = "NOTIFY_AND_MANUAL_RENEW"
+	// bwpRenewUnsupportedMsg 腾讯云未提供带宽包续费及设置续费标识的接口，需要在控制台操作
+	bwpRenewUnsupportedMsg = "tcloud does not provide api to renew bandwidth package, please renew " +
+		"it in the tcloud console"
+)
+
+// RenewResources 续费包年包月的主机或云硬盘，返回每个资源的续费结果
+func (t *TCloudImpl) RenewResources(kt *kit.Kit, opt *typesrenewal.RenewResOption) (*typesrenewal.BatchResult,
+	error) {
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	switch opt.ResType {
+	case enumor.CvmCloudResType:
+		return t.renewCvm(kt, opt)
+	case enumor.DiskCloudResType:
+		return t.renewDisk(kt, opt)
+	case enumor.BandwidthPackageCloudResType:
+		return nil, errf.New(errf.InvalidParameter, bwpRenewUnsupportedMsg)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "tcloud resource type %s does not support renew", opt.ResType)
+	}
+}
+
+// renewCvm 主机续费接口为批量接口，一批主机同时成功或失败
+// reference: https://cloud.tencent.com/document/api/213/15740
+func (t *TCloudImpl) renewCvm(kt *kit.Kit, opt *typesrenewal.RenewResOption) (*typesrenewal.BatchResult, error) {
+	client, err := t.clientSet.CvmClient(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("init tencent cloud client failed, err: %v", err)
+	}
+
+	req := cvm.NewRenewInstancesRequest()
+	req.InstanceIds = common.StringPtrs(opt.CloudIDs)
+	req.InstanceChargePrepaid = &cvm.InstanceChargePrepaid{Period: common.Int64Ptr(opt.Period)}
+	if _, err = client.RenewInstancesWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("renew tcloud cvm failed, err: %v, ids: %v, rid: %s", err, opt.CloudIDs, kt.Rid)
+		return nil, err
+	}
+
+	return &typesrenewal.BatchResult{SuccessCloudIDs: opt.CloudIDs}, nil
+}
+
+// renewDisk 云硬盘续费接口仅支持单个云硬盘，逐个续费并记录每个云硬盘的结果，单个失败不影响其他云硬盘续费
+// reference: https://cloud.tencent.com/document/api/362/15669
+func (t *TCloudImpl) renewDisk(kt *kit.Kit, opt *typesrenewal.RenewResOption) (*typesrenewal.BatchResult, error) {
+	client, err := t.clientSet.CbsClient(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("init tencent cloud client failed, err: %v", err)
+	}
+
+	result := new(typesrenewal.BatchResult)
+	for _, id := range opt.CloudIDs {
+		req := cbs.NewRenewDiskRequest()
+		req.DiskId = common.StringPtr(id)
+		req.DiskChargePrepaid = &cbs.DiskChargePrepaid{Period: common.Uint64Ptr(uint64(opt.Period))}
+		if _, err = client.RenewDiskWithContext(kt.Ctx, req); err != nil {
+			logs.Errorf("renew tcloud disk failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			result.AddFailed(id, err.Error())
+			continue
+		}
+		result.SuccessCloudIDs = append(result.SuccessCloudIDs, id)
+	}
+
+	return result, nil
+}
+
+// SetResourcesAutoRenew 设置包年包月的主机或云硬盘的自动续费标识
+// reference: https://cloud.tencent.com/document/api/213/15752
+// reference: https://cloud.tencent.com/document/api/362/15668
+func (t *TCloudImpl) SetResourcesAutoRenew(kt *kit.Kit, opt *typesrenewal.SetAutoRenewOption) (
+	*typesrenewal.BatchResult, error) {
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	renewFlag := renewFlagManual
+	if opt.AutoRenew {
+		renewFlag = renewFlagAuto
+	}
+
+	switch opt.ResType {
+	case enumor.CvmCloudResType:
+		client, err := t.clientSet.CvmClient(opt.Region)
+		if err != nil {
+			return nil, fmt.Errorf("init tencent cloud client failed, err: %v", err)
+		}
+
+		req := cvm.NewModifyInstancesRenewFlagRequest()
+		req.InstanceIds = common.StringPtrs(opt.CloudIDs)
+		req.RenewFlag = common.StringPtr(renewFlag)
+		if _, err = client.ModifyInstancesRenewFlagWithContext(kt.Ctx, req); err != nil {
+			logs.Errorf("modify tcloud cvm renew flag failed, err: %v, ids: %v, rid: %s", err, opt.CloudIDs, kt.Rid)
+			return nil, err
+		}
+
+	case enumor.DiskCloudResType:
+		client, err := t.clientSet.CbsClient(opt.Region)
+		if err != nil {
+			return nil, fmt.Errorf("init tencent cloud client failed, err: %v", err)
+		}
+
+		req := cbs.NewModifyDisksRenewFlagRequest()
+		req.DiskIds = common.StringPtrs(opt.CloudIDs)
+		req.RenewFlag = common.StringPtr(renewFlag)
+		if _, err = client.ModifyDisksRenewFlagWithContext(kt.Ctx, req); err != nil {
+			logs.Errorf("modify tcloud disk renew flag failed, err: %v, ids: %v, rid: %s", err, opt.CloudIDs, kt.Rid)
+			return nil, err
+		}
+
+	case enumor.BandwidthPackageCloudResType:
+		return nil, errf.New(errf.InvalidParameter, bwpRenewUnsupportedMsg)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "tcloud resource type %s does not support auto renew",
+			opt.ResType)
+	}
+
+	return &typesrenewal.BatchResult{SuccessCloudIDs: opt.CloudIDs}, nil
+}
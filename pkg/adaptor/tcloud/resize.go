@@ -0,0 +1,214 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"fmt"
+
+	"hcm/pkg/adaptor/poller"
+	"hcm/pkg/adaptor/types"
+	"hcm/pkg/adaptor/types/core"
+	typecvm "hcm/pkg/adaptor/types/cvm"
+	"hcm/pkg/adaptor/types/disk"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	cbs "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cbs/v20170312"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	cvm "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cvm/v20170312"
+)
+
+// ResizeCvm 调整实例规格，运行中的实例需要先关机或指定强制关机
+// reference: https://cloud.tencent.com/document/api/213/15744
+func (t *TCloudImpl) ResizeCvm(kt *kit.Kit, opt *typecvm.TCloudResizeOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "resize cvm option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := t.clientSet.CvmClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("init tencent cloud client failed, err: %v", err)
+	}
+
+	req := cvm.NewResetInstancesTypeRequest()
+	req.InstanceIds = common.StringPtrs(opt.CloudIDs)
+	req.InstanceType = common.StringPtr(opt.InstanceType)
+	req.ForceStop = common.BoolPtr(opt.ForceStop)
+
+	if _, err = client.ResetInstancesTypeWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("reset cvm instance type failed, err: %v, ids: %v, rid: %s", err, opt.CloudIDs, kt.Rid)
+		return err
+	}
+
+	handler := &resizeCvmPollingHandler{
+		region:       opt.Region,
+		instanceType: opt.InstanceType,
+	}
+	respPoller := poller.Poller[*TCloudImpl, []*cvm.Instance, poller.BaseDoneResult]{Handler: handler}
+	res, err := respPoller.PollUntilDone(t, kt, converter.SliceToPtr(opt.CloudIDs), types.NewBatchOperateCvmPollerOpt())
+	if err != nil {
+		logs.Errorf("poll resize cvm failed, err: %v, res: %#v, rid: %s", err, res, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// InquiryPriceResizeCvm 调整实例规格询价，与 InquiryPriceCvm 返回相同的价格结构
+// reference: https://cloud.tencent.com/document/api/213/15733
+func (t *TCloudImpl) InquiryPriceResizeCvm(kt *kit.Kit, opt *typecvm.TCloudResizeOption) (
+	*typecvm.InquiryPriceResult, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := t.clientSet.CvmClient(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("init tencent cloud client failed, err: %v", err)
+	}
+
+	req := cvm.NewInquiryPriceResetInstancesTypeRequest()
+	req.InstanceIds = common.StringPtrs(opt.CloudIDs)
+	req.InstanceType = common.StringPtr(opt.InstanceType)
+
+	resp, err := client.InquiryPriceResetInstancesTypeWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("inquiry price reset instances type failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	result := new(typecvm.InquiryPriceResult)
+	if resp.Response.Price == nil || resp.Response.Price.InstancePrice == nil {
+		return result, nil
+	}
+
+	// 包年包月实例返回变配需补缴的费用，按量计费实例返回变配后的单价
+	price := resp.Response.Price.InstancePrice
+	if price.OriginalPrice != nil {
+		result.OriginalPrice = converter.PtrToVal(price.OriginalPrice)
+		result.DiscountPrice = converter.PtrToVal(price.DiscountPrice)
+	} else {
+		result.OriginalPrice = converter.PtrToVal(price.UnitPrice)
+		result.DiscountPrice = converter.PtrToVal(price.UnitPriceDiscount)
+	}
+
+	return result, nil
+}
+
+type resizeCvmPollingHandler struct {
+	region       string
+	instanceType string
+}
+
+// Done 实例规格已变更且没有进行中的操作时结束
+func (h *resizeCvmPollingHandler) Done(cvms []*cvm.Instance) (bool, *poller.BaseDoneResult) {
+	result := new(poller.BaseDoneResult)
+
+	flag := true
+	for _, instance := range cvms {
+		if converter.PtrToVal(instance.InstanceType) != h.instanceType ||
+			converter.PtrToVal(instance.LatestOperationState) == "OPERATING" {
+			flag = false
+			continue
+		}
+
+		result.SuccessCloudIDs = append(result.SuccessCloudIDs, *instance.InstanceId)
+	}
+
+	return flag, result
+}
+
+// Poll ...
+func (h *resizeCvmPollingHandler) Poll(client *TCloudImpl, kt *kit.Kit, cloudIDs []*string) ([]*cvm.Instance, error) {
+	return poll(client, kt, h.region, cloudIDs)
+}
+
+// ResizeDisk 扩容云硬盘
+// reference: https://cloud.tencent.com/document/api/362/16315
+func (t *TCloudImpl) ResizeDisk(kt *kit.Kit, opt *disk.TCloudDiskResizeOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "tcloud disk resize option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := t.clientSet.CbsClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new tcloud cbs client failed, err: %v", err)
+	}
+
+	req := cbs.NewResizeDiskRequest()
+	req.DiskId = common.StringPtr(opt.CloudDiskID)
+	req.DiskSize = common.Uint64Ptr(opt.DiskSize)
+
+	if _, err = client.ResizeDiskWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("tcloud resize disk failed, err: %v, id: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return err
+	}
+
+	respPoller := poller.Poller[*TCloudImpl, []disk.TCloudDisk, poller.BaseDoneResult]{
+		Handler: &resizeDiskPollingHandler{region: opt.Region, diskSize: opt.DiskSize},
+	}
+	_, err = respPoller.PollUntilDone(t, kt, []*string{&opt.CloudDiskID}, nil)
+	return err
+}
+
+type resizeDiskPollingHandler struct {
+	region   string
+	diskSize uint64
+}
+
+// Done 云硬盘容量达到目标值且不处于扩容中时结束
+func (h *resizeDiskPollingHandler) Done(pollResult []disk.TCloudDisk) (bool, *poller.BaseDoneResult) {
+	if len(pollResult) == 0 {
+		return false, nil
+	}
+
+	r := pollResult[0]
+	if converter.PtrToVal(r.DiskSize) != h.diskSize || converter.PtrToVal(r.DiskState) == "EXPANDING" {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Poll ...
+func (h *resizeDiskPollingHandler) Poll(client *TCloudImpl, kt *kit.Kit, cloudIDs []*string) ([]disk.TCloudDisk,
+	error) {
+	if len(cloudIDs) != 1 {
+		return nil, fmt.Errorf("poll only support one id param, but get %v. rid: %s", cloudIDs, kt.Rid)
+	}
+
+	cIDs := converter.PtrToSlice(cloudIDs)
+	return client.ListDisk(kt,
+		&core.TCloudListOption{Region: h.region, CloudIDs: cIDs, Page: &core.TCloudPage{Limit: core.TCloudQueryLimit}})
+}
@@ -0,0 +1,115 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"testing"
+
+	"hcm/pkg/adaptor/types/disk"
+
+	cbs "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cbs/v20170312"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	cvm "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cvm/v20170312"
+)
+
+func TestResizeCvmPollingHandlerDone(t *testing.T) {
+	handler := &resizeCvmPollingHandler{region: "ap-guangzhou", instanceType: "S5.LARGE8"}
+
+	instance := func(id, instanceType, state string) *cvm.Instance {
+		return &cvm.Instance{
+			InstanceId:           common.StringPtr(id),
+			InstanceType:         common.StringPtr(instanceType),
+			LatestOperationState: common.StringPtr(state),
+		}
+	}
+
+	cases := []struct {
+		name         string
+		cvms         []*cvm.Instance
+		expectDone   bool
+		expectSucIDs []string
+	}{
+		{
+			name: "all resized",
+			cvms: []*cvm.Instance{
+				instance("ins-1", "S5.LARGE8", "SUCCESS"),
+				instance("ins-2", "S5.LARGE8", ""),
+			},
+			expectDone:   true,
+			expectSucIDs: []string{"ins-1", "ins-2"},
+		},
+		{
+			name: "instance type not changed",
+			cvms: []*cvm.Instance{
+				instance("ins-1", "S5.LARGE8", "SUCCESS"),
+				instance("ins-2", "S5.MEDIUM4", ""),
+			},
+			expectDone:   false,
+			expectSucIDs: []string{"ins-1"},
+		},
+		{
+			name:       "operation in progress",
+			cvms:       []*cvm.Instance{instance("ins-1", "S5.LARGE8", "OPERATING")},
+			expectDone: false,
+		},
+	}
+
+	for _, c := range cases {
+		done, result := handler.Done(c.cvms)
+		if done != c.expectDone {
+			t.Errorf("%s: expect done: %v, got: %v", c.name, c.expectDone, done)
+		}
+		if len(result.SuccessCloudIDs) != len(c.expectSucIDs) {
+			t.Errorf("%s: expect success ids: %v, got: %v", c.name, c.expectSucIDs, result.SuccessCloudIDs)
+			continue
+		}
+		for i, id := range c.expectSucIDs {
+			if result.SuccessCloudIDs[i] != id {
+				t.Errorf("%s: expect success ids: %v, got: %v", c.name, c.expectSucIDs, result.SuccessCloudIDs)
+				break
+			}
+		}
+	}
+}
+
+func TestResizeDiskPollingHandlerDone(t *testing.T) {
+	handler := &resizeDiskPollingHandler{region: "ap-guangzhou", diskSize: 100}
+
+	newDisk := func(size uint64, state string) disk.TCloudDisk {
+		return disk.TCloudDisk{Disk: &cbs.Disk{DiskSize: common.Uint64Ptr(size), DiskState: common.StringPtr(state)}}
+	}
+
+	cases := []struct {
+		name       string
+		disks      []disk.TCloudDisk
+		expectDone bool
+	}{
+		{name: "empty result", disks: nil, expectDone: false},
+		{name: "size not changed", disks: []disk.TCloudDisk{newDisk(50, "ATTACHED")}, expectDone: false},
+		{name: "expanding", disks: []disk.TCloudDisk{newDisk(100, "EXPANDING")}, expectDone: false},
+		{name: "resized", disks: []disk.TCloudDisk{newDisk(100, "ATTACHED")}, expectDone: true},
+	}
+
+	for _, c := range cases {
+		if done, _ := handler.Done(c.disks); done != c.expectDone {
+			t.Errorf("%s: expect done: %v, got: %v", c.name, c.expectDone, done)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"fmt"
+
+	typestag "hcm/pkg/adaptor/types/tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/maps"
+	"hcm/pkg/tools/slice"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+	vpc "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/vpc/v20170312"
+)
+
+const (
+	tagService = "tag"
+	tagVersion = "2018-08-13"
+	// tagBatchLimit 标签接口单次请求的资源数及标签数上限
+	tagBatchLimit = 9
+)
+
+// tagResourceSegment 资源类型对应资源六段式中的服务类型及资源前缀
+// reference: https://cloud.tencent.com/document/product/598/10606
+var tagResourceSegment = map[enumor.CloudResourceType][2]string{
+	enumor.CvmCloudResType:           {"cvm", "instance"},
+	enumor.DiskCloudResType:          {"cvm", "volume"},
+	enumor.SecurityGroupCloudResType: {"cvm", "sg"},
+	enumor.EipCloudResType:           {"cvm", "eip"},
+	enumor.VpcCloudResType:           {"vpc", "vpc"},
+	enumor.SubnetCloudResType:        {"vpc", "subnet"},
+	enumor.LoadBalancerCloudResType:  {"clb", "clb"},
+}
+
+// TagResources 为资源批量绑定标签，已存在的同名标签会被覆盖
+// reference: https://cloud.tencent.com/document/api/651/72282
+func (t *TCloudImpl) TagResources(kt *kit.Kit, opt *typestag.TagResOption) error {
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	resources, err := t.genTagResourceNames(kt, opt.Region, opt.ResType, opt.CloudIDs)
+	if err != nil {
+		return err
+	}
+
+	tags := make([]map[string]string, 0, len(opt.Tags))
+	for _, key := range maps.Keys(opt.Tags) {
+		tags = append(tags, map[string]string{"TagKey": key, "TagValue": opt.Tags[key]})
+	}
+
+	for _, resBatch := range slice.Split(resources, tagBatchLimit) {
+		for _, tagBatch := range slice.Split(tags, tagBatchLimit) {
+			params := map[string]interface{}{"ResourceList": resBatch, "Tags": tagBatch}
+			if err = t.sendTagRequest(kt, "TagResources", params); err != nil {
+				logs.Errorf("tcloud tag resources failed, err: %v, resources: %v, rid: %s", err, resBatch, kt.Rid)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// UnTagResources 为资源批量解绑标签
+// reference: https://cloud.tencent.com/document/api/651/72281
+func (t *TCloudImpl) UnTagResources(kt *kit.Kit, opt *typestag.UnTagResOption) error {
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	resources, err := t.genTagResourceNames(kt, opt.Region, opt.ResType, opt.CloudIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, resBatch := range slice.Split(resources, tagBatchLimit) {
+		for _, keyBatch := range slice.Split(opt.Keys, tagBatchLimit) {
+			params := map[string]interface{}{"ResourceList": resBatch, "TagKeys": keyBatch}
+			if err = t.sendTagRequest(kt, "UnTagResources", params); err != nil {
+				logs.Errorf("tcloud untag resources failed, err: %v, resources: %v, rid: %s", err, resBatch, kt.Rid)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// genTagResourceNames 生成资源六段式，如 qcs::cvm:ap-guangzhou:uin/100000:instance/ins-xxx
+func (t *TCloudImpl) genTagResourceNames(kt *kit.Kit, region string, resType enumor.CloudResourceType,
+	cloudIDs []string) ([]string, error) {
+
+	segment, exists := tagResourceSegment[resType]
+	if !exists {
+		return nil, errf.Newf(errf.InvalidParameter, "tcloud resource type %s does not support tag", resType)
+	}
+
+	if len(region) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "region is required")
+	}
+
+	info, err := t.GetAccountInfoBySecret(kt)
+	if err != nil {
+		logs.Errorf("get tcloud account info failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	names := make([]string, 0, len(cloudIDs))
+	for _, id := range cloudIDs {
+		names = append(names, fmt.Sprintf("qcs::%s:%s:uin/%s:%s/%s", segment[0], region,
+			info.CloudMainAccountID, segment[1], id))
+	}
+
+	return names, nil
+}
+
+func (t *TCloudImpl) sendTagRequest(kt *kit.Kit, action string, params map[string]interface{}) error {
+	req := tchttp.NewCommonRequest(tagService, tagVersion, action)
+	req.SetContext(kt.Ctx)
+	if err := req.SetActionParameters(params); err != nil {
+		return fmt.Errorf("set tcloud %s request parameters failed, err: %v", action, err)
+	}
+
+	return t.clientSet.TagClient().Send(req, tchttp.NewCommonResponse())
+}
+
+// convVpcTags 将 vpc 服务返回的标签转换为 map
+func convVpcTags(tags []*vpc.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, one := range tags {
+		if one == nil || one.Key == nil {
+			continue
+		}
+		result[*one.Key] = converter.PtrToVal(one.Value)
+	}
+
+	return result
+}
@@ -171,6 +171,7 @@ func convertVpc(data *vpc.Vpc, region string) *types.TCloudVpc {
 		CloudID: converter.PtrToVal(data.VpcId),
 		Name:    converter.PtrToVal(data.VpcName),
 		Region:  region,
+		Tags:    convVpcTags(data.TagSet),
 		Extension: &cloud.TCloudVpcExtension{
 			Cidr:            nil,
 			IsDefault:       converter.PtrToVal(data.IsDefault),
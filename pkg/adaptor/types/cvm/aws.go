@@ -186,3 +186,16 @@ type AwsCvm struct {
 func (cvm AwsCvm) GetCloudID() string {
 	return converter.PtrToVal(cvm.InstanceId)
 }
+
+// GetCloudTags ...
+func (cvm AwsCvm) GetCloudTags() map[string]string {
+	tags := make(map[string]string, len(cvm.Tags))
+	for _, one := range cvm.Tags {
+		if one == nil || one.Key == nil {
+			continue
+		}
+		tags[*one.Key] = converter.PtrToVal(one.Value)
+	}
+
+	return tags
+}
@@ -94,6 +94,8 @@ type AzureStopOption struct {
 	// indicates non-graceful shutdown whereas false indicates otherwise.
 	// Default value for this flag is false if not specified
 	SkipShutdown bool `json:"skip_shutdown" validate:"omitempty"`
+	// Deallocate 是否解除分配虚拟机，解除分配后才能扩容虚拟机挂载的托管磁盘
+	Deallocate bool `json:"deallocate" validate:"omitempty"`
 }
 
 // Validate cvm operation option.
@@ -204,9 +206,20 @@ type AzureCvm struct {
 	VCPUsPerCore        *int32                                        `json:"vcpus_per_core"`
 	TimeCreated         *time.Time                                    `json:"time_created"`
 	StorageProfile      *armcompute.StorageProfile                    `json:"storage_profile"`
+	Tags                map[string]*string                            `json:"tags"`
 }
 
 // GetCloudID ...
 func (cvm AzureCvm) GetCloudID() string {
 	return converter.PtrToVal(cvm.ID)
 }
+
+// GetCloudTags ...
+func (cvm AzureCvm) GetCloudTags() map[string]string {
+	tags := make(map[string]string, len(cvm.Tags))
+	for key, value := range cvm.Tags {
+		tags[key] = converter.PtrToVal(value)
+	}
+
+	return tags
+}
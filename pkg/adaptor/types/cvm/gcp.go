@@ -209,3 +209,13 @@ type GcpCvm struct {
 func (cvm GcpCvm) GetCloudID() string {
 	return fmt.Sprint(cvm.Id)
 }
+
+// GetCloudTags return the labels of the instance.
+func (cvm GcpCvm) GetCloudTags() map[string]string {
+	tags := make(map[string]string, len(cvm.Labels))
+	for key, value := range cvm.Labels {
+		tags[key] = value
+	}
+
+	return tags
+}
@@ -21,9 +21,11 @@ package cvm
 
 import (
 	"fmt"
+	"strings"
 
 	"hcm/pkg/adaptor/types/core"
 	"hcm/pkg/criteria/validator"
+	"hcm/pkg/tools/converter"
 
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/ecs/v2/model"
 )
@@ -320,3 +322,17 @@ type HuaWeiCvm struct {
 func (cvm HuaWeiCvm) GetCloudID() string {
 	return cvm.Id
 }
+
+// GetCloudTags return the tags of the server, each tag of huawei server is formatted as: key=value
+func (cvm HuaWeiCvm) GetCloudTags() map[string]string {
+	tags := make(map[string]string)
+	for _, one := range converter.PtrToVal(cvm.Tags) {
+		key, value, _ := strings.Cut(one, "=")
+		if len(key) == 0 {
+			continue
+		}
+		tags[key] = value
+	}
+
+	return tags
+}
@@ -0,0 +1,88 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cvm
+
+import (
+	"hcm/pkg/criteria/validator"
+)
+
+// -------------------------- Resize --------------------------
+
+// TCloudResizeOption 调整腾讯云主机实例规格
+type TCloudResizeOption struct {
+	Region       string   `json:"region" validate:"required"`
+	CloudIDs     []string `json:"cloud_ids" validate:"required,min=1,max=100"`
+	InstanceType string   `json:"instance_type" validate:"required"`
+	// ForceStop 是否对运行中的实例选择强制关机，默认为false
+	ForceStop bool `json:"force_stop" validate:"omitempty"`
+}
+
+// Validate tcloud cvm resize option.
+func (opt TCloudResizeOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsResizeOption 调整Aws主机实例类型，实例需处于关机状态
+type AwsResizeOption struct {
+	Region       string `json:"region" validate:"required"`
+	CloudID      string `json:"cloud_id" validate:"required"`
+	InstanceType string `json:"instance_type" validate:"required"`
+}
+
+// Validate aws cvm resize option.
+func (opt AwsResizeOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiResizeOption 调整华为云主机规格
+type HuaWeiResizeOption struct {
+	Region    string `json:"region" validate:"required"`
+	CloudID   string `json:"cloud_id" validate:"required"`
+	FlavorRef string `json:"flavor_ref" validate:"required"`
+}
+
+// Validate huawei cvm resize option.
+func (opt HuaWeiResizeOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AzureResizeOption 调整Azure虚拟机大小
+type AzureResizeOption struct {
+	ResourceGroupName string `json:"resource_group_name" validate:"required"`
+	Name              string `json:"name" validate:"required"`
+	VMSize            string `json:"vm_size" validate:"required"`
+}
+
+// Validate azure cvm resize option.
+func (opt AzureResizeOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// GcpResizeOption 调整Gcp实例机器类型，实例需处于关机状态
+type GcpResizeOption struct {
+	Zone        string `json:"zone" validate:"required"`
+	Name        string `json:"name" validate:"required"`
+	MachineType string `json:"machine_type" validate:"required"`
+}
+
+// Validate gcp cvm resize option.
+func (opt GcpResizeOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
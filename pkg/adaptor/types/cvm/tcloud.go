@@ -297,6 +297,19 @@ func (cvm TCloudCvm) GetCloudID() string {
 	return converter.PtrToVal(cvm.InstanceId)
 }
 
+// GetCloudTags ...
+func (cvm TCloudCvm) GetCloudTags() map[string]string {
+	tags := make(map[string]string, len(cvm.Tags))
+	for _, one := range cvm.Tags {
+		if one == nil || one.Key == nil {
+			continue
+		}
+		tags[*one.Key] = converter.PtrToVal(one.Value)
+	}
+
+	return tags
+}
+
 // InquiryPriceResult define tcloud inquiry price result.
 type InquiryPriceResult struct {
 	DiscountPrice float64 `json:"discount_price"`
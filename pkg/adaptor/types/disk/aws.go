@@ -155,3 +155,16 @@ type AwsDisk struct {
 func (disk AwsDisk) GetCloudID() string {
 	return converter.PtrToVal(disk.VolumeId)
 }
+
+// GetCloudTags ...
+func (disk AwsDisk) GetCloudTags() map[string]string {
+	tags := make(map[string]string, len(disk.Tags))
+	for _, one := range disk.Tags {
+		if one == nil || one.Key == nil {
+			continue
+		}
+		tags[*one.Key] = converter.PtrToVal(one.Value)
+	}
+
+	return tags
+}
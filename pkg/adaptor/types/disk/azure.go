@@ -133,16 +133,17 @@ func (opt *AzureDiskDetachOption) Validate() error {
 
 // AzureDisk define azure disk.
 type AzureDisk struct {
-	ID       *string   `json:"id"`
-	Name     *string   `json:"name"`
-	Location *string   `json:"location"`
-	Type     *string   `json:"type"`
-	Status   *string   `json:"status"`
-	DiskSize *int64    `json:"disk_size"`
-	OSType   *string   `json:"os_type"`
-	Zones    []*string `json:"zone"`
-	SKUName  *string   `json:"sku_name"`
-	SKUTier  *string   `json:"sku_tier"`
+	ID       *string            `json:"id"`
+	Name     *string            `json:"name"`
+	Location *string            `json:"location"`
+	Type     *string            `json:"type"`
+	Status   *string            `json:"status"`
+	DiskSize *int64             `json:"disk_size"`
+	OSType   *string            `json:"os_type"`
+	Zones    []*string          `json:"zone"`
+	SKUName  *string            `json:"sku_name"`
+	SKUTier  *string            `json:"sku_tier"`
+	Tags     map[string]*string `json:"tags"`
 	Boot     *bool
 }
 
@@ -150,3 +151,13 @@ type AzureDisk struct {
 func (disk AzureDisk) GetCloudID() string {
 	return converter.PtrToVal(disk.ID)
 }
+
+// GetCloudTags ...
+func (disk AzureDisk) GetCloudTags() map[string]string {
+	tags := make(map[string]string, len(disk.Tags))
+	for key, value := range disk.Tags {
+		tags[key] = converter.PtrToVal(value)
+	}
+
+	return tags
+}
@@ -151,3 +151,13 @@ type GcpDisk struct {
 func (disk GcpDisk) GetCloudID() string {
 	return fmt.Sprint(disk.Id)
 }
+
+// GetCloudTags return the labels of the disk.
+func (disk GcpDisk) GetCloudTags() map[string]string {
+	tags := make(map[string]string, len(disk.Labels))
+	for key, value := range disk.Labels {
+		tags[key] = value
+	}
+
+	return tags
+}
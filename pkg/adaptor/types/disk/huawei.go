@@ -286,3 +286,13 @@ type HuaWeiDisk struct {
 func (disk HuaWeiDisk) GetCloudID() string {
 	return disk.Id
 }
+
+// GetCloudTags ...
+func (disk HuaWeiDisk) GetCloudTags() map[string]string {
+	tags := make(map[string]string, len(disk.Tags))
+	for key, value := range disk.Tags {
+		tags[key] = value
+	}
+
+	return tags
+}
@@ -0,0 +1,85 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disk
+
+import (
+	"hcm/pkg/criteria/validator"
+)
+
+// TCloudDiskResizeOption 扩容腾讯云云硬盘
+type TCloudDiskResizeOption struct {
+	Region      string `json:"region" validate:"required"`
+	CloudDiskID string `json:"cloud_disk_id" validate:"required"`
+	// DiskSize 扩容后的云硬盘大小，单位GB
+	DiskSize uint64 `json:"disk_size" validate:"required"`
+}
+
+// Validate ...
+func (o *TCloudDiskResizeOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// AwsDiskResizeOption 扩容Aws云硬盘
+type AwsDiskResizeOption struct {
+	Region      string `json:"region" validate:"required"`
+	CloudDiskID string `json:"cloud_disk_id" validate:"required"`
+	DiskSize    int64  `json:"disk_size" validate:"required"`
+}
+
+// Validate ...
+func (o *AwsDiskResizeOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// HuaWeiDiskResizeOption 扩容华为云云硬盘
+type HuaWeiDiskResizeOption struct {
+	Region      string `json:"region" validate:"required"`
+	CloudDiskID string `json:"cloud_disk_id" validate:"required"`
+	DiskSize    int32  `json:"disk_size" validate:"required"`
+}
+
+// Validate ...
+func (o *HuaWeiDiskResizeOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// AzureDiskResizeOption 扩容Azure托管磁盘
+type AzureDiskResizeOption struct {
+	ResourceGroupName string `json:"resource_group_name" validate:"required"`
+	DiskName          string `json:"disk_name" validate:"required"`
+	DiskSize          int32  `json:"disk_size" validate:"required"`
+}
+
+// Validate ...
+func (o *AzureDiskResizeOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// GcpDiskResizeOption 扩容Gcp永久性磁盘
+type GcpDiskResizeOption struct {
+	Zone     string `json:"zone" validate:"required"`
+	DiskName string `json:"disk_name" validate:"required"`
+	DiskSize int64  `json:"disk_size" validate:"required"`
+}
+
+// Validate ...
+func (o *GcpDiskResizeOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
@@ -0,0 +1,239 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disk
+
+import (
+	"hcm/pkg/criteria/validator"
+)
+
+// DiskSnapshot 各云统一的云硬盘快照信息
+type DiskSnapshot struct {
+	CloudID string `json:"cloud_id"`
+	Name    string `json:"name"`
+	// Region 快照所在地域，Gcp快照为全局资源，取源云硬盘所在地域
+	Region      string `json:"region"`
+	CloudDiskID string `json:"cloud_disk_id"`
+	// DiskSize 源云硬盘大小，单位GB
+	DiskSize         uint64 `json:"disk_size"`
+	Status           string `json:"status"`
+	CloudCreatedTime string `json:"cloud_created_time"`
+}
+
+// GetCloudID ...
+func (s DiskSnapshot) GetCloudID() string {
+	return s.CloudID
+}
+
+// TCloudDiskSnapshotListOption define tcloud disk snapshot list option.
+type TCloudDiskSnapshotListOption struct {
+	Region       string   `json:"region" validate:"required"`
+	CloudIDs     []string `json:"cloud_ids" validate:"omitempty,max=100"`
+	CloudDiskIDs []string `json:"cloud_disk_ids" validate:"omitempty,max=5"`
+}
+
+// Validate ...
+func (o *TCloudDiskSnapshotListOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// TCloudDiskSnapshotDeleteOption define tcloud disk snapshot delete option.
+type TCloudDiskSnapshotDeleteOption struct {
+	Region   string   `json:"region" validate:"required"`
+	CloudIDs []string `json:"cloud_ids" validate:"required,min=1,max=100"`
+}
+
+// Validate ...
+func (o *TCloudDiskSnapshotDeleteOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// TCloudDiskSnapshotRollbackOption define tcloud disk snapshot rollback option.
+type TCloudDiskSnapshotRollbackOption struct {
+	Region      string `json:"region" validate:"required"`
+	CloudID     string `json:"cloud_id" validate:"required"`
+	CloudDiskID string `json:"cloud_disk_id" validate:"required"`
+}
+
+// Validate ...
+func (o *TCloudDiskSnapshotRollbackOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// AwsDiskSnapshotListOption define aws disk snapshot list option, only snapshots owned by the account are listed.
+type AwsDiskSnapshotListOption struct {
+	Region       string   `json:"region" validate:"required"`
+	CloudIDs     []string `json:"cloud_ids" validate:"omitempty,max=1000"`
+	CloudDiskIDs []string `json:"cloud_disk_ids" validate:"omitempty,max=200"`
+}
+
+// Validate ...
+func (o *AwsDiskSnapshotListOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// AwsDiskSnapshotCreateOption define aws disk snapshot create option.
+type AwsDiskSnapshotCreateOption struct {
+	Region       string `json:"region" validate:"required"`
+	CloudDiskID  string `json:"cloud_disk_id" validate:"required"`
+	SnapshotName string `json:"snapshot_name" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (o *AwsDiskSnapshotCreateOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// AwsDiskSnapshotDeleteOption define aws disk snapshot delete option.
+type AwsDiskSnapshotDeleteOption struct {
+	Region  string `json:"region" validate:"required"`
+	CloudID string `json:"cloud_id" validate:"required"`
+}
+
+// Validate ...
+func (o *AwsDiskSnapshotDeleteOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// AwsDiskSnapshotRollbackOption define aws disk snapshot rollback option, aws only support replacing the root volume
+// of an instance with the snapshot.
+type AwsDiskSnapshotRollbackOption struct {
+	Region     string `json:"region" validate:"required"`
+	CloudID    string `json:"cloud_id" validate:"required"`
+	CloudCvmID string `json:"cloud_cvm_id" validate:"required"`
+}
+
+// Validate ...
+func (o *AwsDiskSnapshotRollbackOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// HuaWeiDiskSnapshotListOption define huawei disk snapshot list option.
+type HuaWeiDiskSnapshotListOption struct {
+	Region      string `json:"region" validate:"required"`
+	CloudID     string `json:"cloud_id" validate:"omitempty"`
+	CloudDiskID string `json:"cloud_disk_id" validate:"omitempty"`
+}
+
+// Validate ...
+func (o *HuaWeiDiskSnapshotListOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// HuaWeiDiskSnapshotCreateOption define huawei disk snapshot create option.
+type HuaWeiDiskSnapshotCreateOption struct {
+	Region       string `json:"region" validate:"required"`
+	CloudDiskID  string `json:"cloud_disk_id" validate:"required"`
+	SnapshotName string `json:"snapshot_name" validate:"omitempty,max=64"`
+}
+
+// Validate ...
+func (o *HuaWeiDiskSnapshotCreateOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// HuaWeiDiskSnapshotDeleteOption define huawei disk snapshot delete option.
+type HuaWeiDiskSnapshotDeleteOption struct {
+	Region  string `json:"region" validate:"required"`
+	CloudID string `json:"cloud_id" validate:"required"`
+}
+
+// Validate ...
+func (o *HuaWeiDiskSnapshotDeleteOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// HuaWeiDiskSnapshotRollbackOption define huawei disk snapshot rollback option.
+type HuaWeiDiskSnapshotRollbackOption struct {
+	Region      string `json:"region" validate:"required"`
+	CloudID     string `json:"cloud_id" validate:"required"`
+	CloudDiskID string `json:"cloud_disk_id" validate:"required"`
+}
+
+// Validate ...
+func (o *HuaWeiDiskSnapshotRollbackOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// AzureDiskSnapshotListOption define azure disk snapshot list option, list the snapshots of the subscription in
+// the region.
+type AzureDiskSnapshotListOption struct {
+	Region string `json:"region" validate:"required"`
+}
+
+// Validate ...
+func (o *AzureDiskSnapshotListOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// AzureDiskSnapshotCreateOption define azure disk snapshot create option, the snapshot is created in the resource
+// group of the disk.
+type AzureDiskSnapshotCreateOption struct {
+	Region       string `json:"region" validate:"required"`
+	CloudDiskID  string `json:"cloud_disk_id" validate:"required"`
+	SnapshotName string `json:"snapshot_name" validate:"required,max=80"`
+}
+
+// Validate ...
+func (o *AzureDiskSnapshotCreateOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// AzureDiskSnapshotDeleteOption define azure disk snapshot delete option.
+type AzureDiskSnapshotDeleteOption struct {
+	CloudID string `json:"cloud_id" validate:"required"`
+}
+
+// Validate ...
+func (o *AzureDiskSnapshotDeleteOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// GcpDiskSnapshotListOption define gcp disk snapshot list option, gcp snapshot is global resource, the snapshots
+// are filtered by the region of the source disk.
+type GcpDiskSnapshotListOption struct {
+	Region string `json:"region" validate:"required"`
+}
+
+// Validate ...
+func (o *GcpDiskSnapshotListOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// GcpDiskSnapshotCreateOption define gcp disk snapshot create option.
+type GcpDiskSnapshotCreateOption struct {
+	Zone         string `json:"zone" validate:"required"`
+	DiskName     string `json:"disk_name" validate:"required"`
+	SnapshotName string `json:"snapshot_name" validate:"required,max=63"`
+}
+
+// Validate ...
+func (o *GcpDiskSnapshotCreateOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// GcpDiskSnapshotDeleteOption define gcp disk snapshot delete option.
+type GcpDiskSnapshotDeleteOption struct {
+	SnapshotName string `json:"snapshot_name" validate:"required"`
+}
+
+// Validate ...
+func (o *GcpDiskSnapshotDeleteOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
@@ -196,8 +196,48 @@ func (disk TCloudDisk) GetCloudID() string {
 	return converter.PtrToVal(disk.DiskId)
 }
 
+// GetCloudTags ...
+func (disk TCloudDisk) GetCloudTags() map[string]string {
+	tags := make(map[string]string, len(disk.Tags))
+	for _, one := range disk.Tags {
+		if one == nil || one.Key == nil {
+			continue
+		}
+		tags[*one.Key] = converter.PtrToVal(one.Value)
+	}
+
+	return tags
+}
+
 // InquiryPriceResult define tcloud inquiry price result.
 type InquiryPriceResult struct {
 	DiscountPrice float64 `json:"discount_price"`
 	OriginalPrice float64 `json:"original_price"`
 }
+
+// TCloudDiskSnapshotCreateOption ...
+type TCloudDiskSnapshotCreateOption struct {
+	Region       string `json:"region" validate:"required"`
+	CloudDiskID  string `json:"cloud_disk_id" validate:"required"`
+	SnapshotName string `json:"snapshot_name" validate:"omitempty,max=60"`
+}
+
+// Validate ...
+func (o *TCloudDiskSnapshotCreateOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// ToCreateSnapshotRequest ...
+func (o *TCloudDiskSnapshotCreateOption) ToCreateSnapshotRequest() (*cbs.CreateSnapshotRequest, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
+	req := cbs.NewCreateSnapshotRequest()
+	req.DiskId = common.StringPtr(o.CloudDiskID)
+	if len(o.SnapshotName) != 0 {
+		req.SnapshotName = common.StringPtr(o.SnapshotName)
+	}
+
+	return req, nil
+}
@@ -59,6 +59,7 @@ type AwsEip struct {
 	NetworkBorderGroup      *string
 	NetworkInterfaceId      *string
 	NetworkInterfaceOwnerId *string
+	Tags                    map[string]string
 }
 
 // GetCloudID ...
@@ -66,6 +67,11 @@ func (eip *AwsEip) GetCloudID() string {
 	return eip.CloudID
 }
 
+// GetCloudTags ...
+func (eip *AwsEip) GetCloudTags() map[string]string {
+	return eip.Tags
+}
+
 // AwsEipDeleteOption ...
 type AwsEipDeleteOption struct {
 	Region  string `json:"region" validate:"required"`
@@ -79,6 +79,7 @@ type AzureEip struct {
 	Fqdn                   *string
 	Zones                  []*string
 	PublicIPAddressVersion *string
+	Tags                   map[string]string
 }
 
 // GetCloudID ...
@@ -86,6 +87,11 @@ func (eip *AzureEip) GetCloudID() string {
 	return eip.CloudID
 }
 
+// GetCloudTags ...
+func (eip *AzureEip) GetCloudTags() map[string]string {
+	return eip.Tags
+}
+
 // AzureEipDeleteOption ...
 type AzureEipDeleteOption struct {
 	ResourceGroupName string `json:"resource_group_name" validate:"required"`
@@ -80,6 +80,7 @@ type GcpEip struct {
 	Subnetwork   string
 	SelfLink     string
 	Users        []string
+	Labels       map[string]string
 }
 
 // GetCloudID ...
@@ -87,6 +88,11 @@ func (eip *GcpEip) GetCloudID() string {
 	return eip.CloudID
 }
 
+// GetCloudTags return the labels of the address.
+func (eip *GcpEip) GetCloudTags() map[string]string {
+	return eip.Labels
+}
+
 // GcpEipDeleteOption ...
 type GcpEipDeleteOption struct {
 	Region  string `json:"region" validate:"required"`
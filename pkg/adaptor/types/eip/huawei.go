@@ -86,6 +86,7 @@ type HuaWeiEip struct {
 	Type                *string
 	BandwidthShareType  string
 	ChargeMode          string
+	Tags                map[string]string
 }
 
 // GetCloudID ...
@@ -93,6 +94,11 @@ func (eip *HuaWeiEip) GetCloudID() string {
 	return eip.CloudID
 }
 
+// GetCloudTags ...
+func (eip *HuaWeiEip) GetCloudTags() map[string]string {
+	return eip.Tags
+}
+
 // HuaWeiEipDeleteOption ...
 type HuaWeiEipDeleteOption struct {
 	CloudID string `json:"cloud_id" validate:"required"`
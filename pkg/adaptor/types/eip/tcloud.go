@@ -66,6 +66,7 @@ type TCloudEip struct {
 	Bandwidth               *uint64
 	InternetChargeType      *string
 	InternetServiceProvider *string
+	Tags                    map[string]string
 }
 
 // GetCloudID ...
@@ -73,6 +74,11 @@ func (eip *TCloudEip) GetCloudID() string {
 	return eip.CloudID
 }
 
+// GetCloudTags ...
+func (eip *TCloudEip) GetCloudTags() map[string]string {
+	return eip.Tags
+}
+
 // TCloudEipDeleteOption ...
 type TCloudEipDeleteOption struct {
 	CloudIDs []string `json:"cloud_ids" validate:"required"`
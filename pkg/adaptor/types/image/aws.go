@@ -68,3 +68,43 @@ type AwsImage struct {
 func (image AwsImage) GetCloudID() string {
 	return image.CloudID
 }
+
+// AwsImageCreateOption define aws create private image from cvm option.
+type AwsImageCreateOption struct {
+	Region     string `json:"region" validate:"required"`
+	CloudCvmID string `json:"cloud_cvm_id" validate:"required"`
+	ImageName  string `json:"image_name" validate:"required,max=128"`
+	// Reboot 创建镜像前是否重启主机以保证文件系统一致，为false时不重启主机直接创建镜像
+	Reboot bool `json:"reboot" validate:"omitempty"`
+}
+
+// Validate aws image create option.
+func (opt AwsImageCreateOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsImageCopyOption define aws copy private image to other regions option.
+type AwsImageCopyOption struct {
+	Region       string   `json:"region" validate:"required"`
+	CloudImageID string   `json:"cloud_image_id" validate:"required"`
+	DstRegions   []string `json:"dst_regions" validate:"required,min=1,max=10"`
+	ImageName    string   `json:"image_name" validate:"required,max=128"`
+}
+
+// Validate aws image copy option.
+func (opt AwsImageCopyOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsImageShareOption define aws share private image to other accounts option.
+type AwsImageShareOption struct {
+	Region       string `json:"region" validate:"required"`
+	CloudImageID string `json:"cloud_image_id" validate:"required"`
+	// CloudAccountIDs 接收共享镜像的亚马逊云账号ID
+	CloudAccountIDs []string `json:"cloud_account_ids" validate:"required,min=1,max=100"`
+}
+
+// Validate aws image share option.
+func (opt AwsImageShareOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
@@ -64,3 +64,15 @@ func (opt AzureImageListOption) Validate() error {
 
 	return nil
 }
+
+// AzureImageCreateOption define azure create managed image from cvm option.
+type AzureImageCreateOption struct {
+	Region     string `json:"region" validate:"required"`
+	CloudCvmID string `json:"cloud_cvm_id" validate:"required"`
+	ImageName  string `json:"image_name" validate:"required,max=80"`
+}
+
+// Validate azure image create option.
+func (opt AzureImageCreateOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
@@ -68,3 +68,28 @@ type GcpImage struct {
 func (image GcpImage) GetCloudID() string {
 	return image.CloudID
 }
+
+// GcpImageCreateOption define gcp create private image from cvm boot disk option.
+type GcpImageCreateOption struct {
+	// CloudBootDiskSelfLink 主机系统盘的self link
+	CloudBootDiskSelfLink string `json:"cloud_boot_disk_self_link" validate:"required"`
+	// ImageName gcp镜像名称需以小写字母开头，只能包含小写字母、数字和连字符
+	ImageName string `json:"image_name" validate:"required,max=63"`
+}
+
+// Validate gcp image create option.
+func (opt GcpImageCreateOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// GcpImageShareOption define gcp share private image to other service accounts option.
+type GcpImageShareOption struct {
+	ImageName string `json:"image_name" validate:"required"`
+	// ServiceAccountEmails 接收共享镜像的账号所使用的服务账号邮箱
+	ServiceAccountEmails []string `json:"service_account_emails" validate:"required,min=1,max=100"`
+}
+
+// Validate gcp image share option.
+func (opt GcpImageShareOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
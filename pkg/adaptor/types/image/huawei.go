@@ -70,3 +70,56 @@ type HuaWeiImage struct {
 func (image HuaWeiImage) GetCloudID() string {
 	return image.CloudID
 }
+
+// HuaWeiImageCreateOption define huawei create private image from cvm option.
+type HuaWeiImageCreateOption struct {
+	Region     string `json:"region" validate:"required"`
+	CloudCvmID string `json:"cloud_cvm_id" validate:"required"`
+	ImageName  string `json:"image_name" validate:"required,max=128"`
+}
+
+// Validate huawei image create option.
+func (opt HuaWeiImageCreateOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiImageCopyOption define huawei copy private image to other regions option.
+type HuaWeiImageCopyOption struct {
+	Region       string   `json:"region" validate:"required"`
+	CloudImageID string   `json:"cloud_image_id" validate:"required"`
+	DstRegions   []string `json:"dst_regions" validate:"required,min=1,max=10"`
+	ImageName    string   `json:"image_name" validate:"required,max=128"`
+	// AgencyName 镜像服务的委托名称，为空时使用华为云默认的镜像服务委托
+	AgencyName string `json:"agency_name" validate:"omitempty"`
+}
+
+// Validate huawei image copy option.
+func (opt HuaWeiImageCopyOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiImageShareOption define huawei share private image to other projects option.
+type HuaWeiImageShareOption struct {
+	Region       string `json:"region" validate:"required"`
+	CloudImageID string `json:"cloud_image_id" validate:"required"`
+	// CloudProjectIDs 接收共享镜像的账号在镜像所在地域的项目ID
+	CloudProjectIDs []string `json:"cloud_project_ids" validate:"required,min=1,max=50"`
+}
+
+// Validate huawei image share option.
+func (opt HuaWeiImageShareOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiImageAcceptOption define huawei accept shared image option.
+type HuaWeiImageAcceptOption struct {
+	Region       string `json:"region" validate:"required"`
+	CloudImageID string `json:"cloud_image_id" validate:"required"`
+	// CloudProjectID 接收共享镜像的项目ID
+	CloudProjectID string `json:"cloud_project_id" validate:"required"`
+}
+
+// Validate huawei image accept option.
+func (opt HuaWeiImageAcceptOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
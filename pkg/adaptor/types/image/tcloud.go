@@ -70,3 +70,44 @@ func (opt TCloudImageListOption) Validate() error {
 
 	return nil
 }
+
+// TCloudImageCreateOption define tcloud create custom image from cvm option.
+type TCloudImageCreateOption struct {
+	Region     string `json:"region" validate:"required"`
+	CloudCvmID string `json:"cloud_cvm_id" validate:"required"`
+	ImageName  string `json:"image_name" validate:"required,max=60"`
+	// ForcePoweroff 创建镜像时是否强制关机
+	ForcePoweroff bool `json:"force_poweroff"`
+}
+
+// Validate tcloud image create option.
+func (opt TCloudImageCreateOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// TCloudImageCopyOption define tcloud copy custom image to other regions option.
+type TCloudImageCopyOption struct {
+	Region       string   `json:"region" validate:"required"`
+	CloudImageID string   `json:"cloud_image_id" validate:"required"`
+	DstRegions   []string `json:"dst_regions" validate:"required,min=1,max=10"`
+	// ImageName 目的地域的镜像名称，为空时与源镜像名称一致
+	ImageName string `json:"image_name" validate:"omitempty,max=60"`
+}
+
+// Validate tcloud image copy option.
+func (opt TCloudImageCopyOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// TCloudImageShareOption define tcloud share custom image to other accounts option.
+type TCloudImageShareOption struct {
+	Region       string `json:"region" validate:"required"`
+	CloudImageID string `json:"cloud_image_id" validate:"required"`
+	// CloudMainAccountIDs 接收共享镜像的腾讯云主账号ID
+	CloudMainAccountIDs []string `json:"cloud_main_account_ids" validate:"required,min=1,max=50"`
+}
+
+// Validate tcloud image share option.
+func (opt TCloudImageShareOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
@@ -157,6 +157,19 @@ func (clb TCloudClb) GetCloudID() string {
 	return cvt.PtrToVal(clb.LoadBalancerId)
 }
 
+// GetCloudTags ...
+func (clb TCloudClb) GetCloudTags() map[string]string {
+	tags := make(map[string]string, len(clb.Tags))
+	for _, one := range clb.Tags {
+		if one == nil || one.TagKey == nil {
+			continue
+		}
+		tags[*one.TagKey] = cvt.PtrToVal(one.TagValue)
+	}
+
+	return tags
+}
+
 // GetIPVersion 返回ip版本信息
 func (clb TCloudClb) GetIPVersion() enumor.IPAddressType {
 
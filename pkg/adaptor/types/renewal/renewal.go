@@ -0,0 +1,117 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package renewal defines the option for prepaid cloud resource renewal operations.
+package renewal
+
+import (
+	"errors"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// RenewResOption 续费包年包月资源参数
+type RenewResOption struct {
+	Region   string                   `json:"region" validate:"omitempty"`
+	ResType  enumor.CloudResourceType `json:"res_type" validate:"required"`
+	CloudIDs []string                 `json:"cloud_ids" validate:"required,min=1,max=100"`
+	// Period 续费时长，单位：月
+	Period int64 `json:"period" validate:"required,min=1,max=36"`
+}
+
+// Validate RenewResOption.
+func (opt *RenewResOption) Validate() error {
+	if opt == nil {
+		return errors.New("renew resource option is required")
+	}
+
+	return validator.Validate.Struct(opt)
+}
+
+// SetAutoRenewOption 设置包年包月资源自动续费参数
+type SetAutoRenewOption struct {
+	Region   string                   `json:"region" validate:"omitempty"`
+	ResType  enumor.CloudResourceType `json:"res_type" validate:"required"`
+	CloudIDs []string                 `json:"cloud_ids" validate:"required,min=1,max=100"`
+	// AutoRenew 为true时开启自动续费，为false时关闭自动续费
+	AutoRenew bool `json:"auto_renew"`
+}
+
+// Validate SetAutoRenewOption.
+func (opt *SetAutoRenewOption) Validate() error {
+	if opt == nil {
+		return errors.New("set auto renew option is required")
+	}
+
+	return validator.Validate.Struct(opt)
+}
+
+// BatchResult 续费或设置自动续费的结果，部分资源操作失败时不影响其他资源
+type BatchResult struct {
+	SuccessCloudIDs []string `json:"success_cloud_ids"`
+	FailedCloudIDs  []string `json:"failed_cloud_ids"`
+	FailedMessage   string   `json:"failed_message"`
+}
+
+// AddFailed 记录操作失败的资源及失败原因
+func (r *BatchResult) AddFailed(cloudID string, reason string) {
+	r.FailedCloudIDs = append(r.FailedCloudIDs, cloudID)
+	if len(r.FailedMessage) != 0 {
+		r.FailedMessage += "; "
+	}
+	r.FailedMessage += cloudID + ": " + reason
+}
+
+// ListExpireTimeOption 查询包年包月资源到期时间参数
+type ListExpireTimeOption struct {
+	CloudIDs []string `json:"cloud_ids" validate:"required,min=1,max=50"`
+}
+
+// Validate ListExpireTimeOption.
+func (opt *ListExpireTimeOption) Validate() error {
+	if opt == nil {
+		return errors.New("list expire time option is required")
+	}
+
+	return validator.Validate.Struct(opt)
+}
+
+// ResExpireTime 包年包月资源的到期时间
+type ResExpireTime struct {
+	CloudID    string `json:"cloud_id"`
+	ExpireTime string `json:"expire_time"`
+	AutoRenew  bool   `json:"auto_renew"`
+}
+
+// ListBandwidthPackageOption 查询包年包月带宽包参数
+type ListBandwidthPackageOption struct {
+	// ExpireTimeEnd 仅查询该时间之前到期的带宽包，为空时查询所有包年包月的带宽包
+	ExpireTimeEnd *time.Time `json:"expire_time_end"`
+}
+
+// PrepaidBandwidthPackage 包年包月的带宽包
+type PrepaidBandwidthPackage struct {
+	CloudID    string `json:"cloud_id"`
+	Name       string `json:"name"`
+	Region     string `json:"region"`
+	ExpireTime string `json:"expire_time"`
+	AutoRenew  bool   `json:"auto_renew"`
+}
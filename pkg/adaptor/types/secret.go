@@ -27,9 +27,9 @@ import (
 // BaseSecret defines the hybrid cloud's base secret info.
 type BaseSecret struct {
 	// CloudSecretID is the secret id to do credential.
-	CloudSecretID string `json:"cloud_secret_id"`
+	CloudSecretID string `json:"cloud_secret_id" redact:"true"`
 	// CloudSecretKey is the secret key to do credential.
-	CloudSecretKey string `json:"cloud_secret_key"`
+	CloudSecretKey string `json:"cloud_secret_key" redact:"true"`
 	// CloudAccountID is the account id to do credential.
 	CloudAccountID string `json:"cloud_account_id"`
 }
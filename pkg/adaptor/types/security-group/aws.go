@@ -102,3 +102,16 @@ type AwsSG struct {
 func (sg AwsSG) GetCloudID() string {
 	return converter.PtrToVal(sg.GroupId)
 }
+
+// GetCloudTags ...
+func (sg AwsSG) GetCloudTags() map[string]string {
+	tags := make(map[string]string, len(sg.Tags))
+	for _, one := range sg.Tags {
+		if one == nil || one.Key == nil {
+			continue
+		}
+		tags[*one.Key] = converter.PtrToVal(one.Value)
+	}
+
+	return tags
+}
@@ -108,9 +108,20 @@ type AzureSecurityGroup struct {
 	FlushConnection *bool                      `json:"flush_connection"`
 	ResourceGUID    *string                    `json:"resource_guid"`
 	SecurityRules   []*armnetwork.SecurityRule `json:"security_rules"`
+	Tags            map[string]*string         `json:"tags"`
 }
 
 // GetCloudID ...
 func (sg AzureSecurityGroup) GetCloudID() string {
 	return converter.PtrToVal(sg.ID)
 }
+
+// GetCloudTags ...
+func (sg AzureSecurityGroup) GetCloudTags() map[string]string {
+	tags := make(map[string]string, len(sg.Tags))
+	for key, value := range sg.Tags {
+		tags[key] = converter.PtrToVal(value)
+	}
+
+	return tags
+}
@@ -128,3 +128,16 @@ type TCloudSG struct {
 func (sg TCloudSG) GetCloudID() string {
 	return converter.PtrToVal(sg.SecurityGroupId)
 }
+
+// GetCloudTags ...
+func (sg TCloudSG) GetCloudTags() map[string]string {
+	tags := make(map[string]string, len(sg.TagSet))
+	for _, one := range sg.TagSet {
+		if one == nil || one.Key == nil {
+			continue
+		}
+		tags[*one.Key] = converter.PtrToVal(one.Value)
+	}
+
+	return tags
+}
@@ -0,0 +1,66 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tag defines the option for cloud resource tag operations.
+package tag
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// TagResOption 为云资源添加(覆盖同名)标签参数
+type TagResOption struct {
+	Region string `json:"region" validate:"omitempty"`
+	// Zone gcp 可用区资源需要
+	Zone     string                   `json:"zone" validate:"omitempty"`
+	ResType  enumor.CloudResourceType `json:"res_type" validate:"required"`
+	CloudIDs []string                 `json:"cloud_ids" validate:"required,min=1,max=100"`
+	Tags     map[string]string        `json:"tags" validate:"required,min=1"`
+}
+
+// Validate TagResOption.
+func (opt *TagResOption) Validate() error {
+	if opt == nil {
+		return errors.New("tag resource option is required")
+	}
+
+	return validator.Validate.Struct(opt)
+}
+
+// UnTagResOption 删除云资源标签参数
+type UnTagResOption struct {
+	Region string `json:"region" validate:"omitempty"`
+	// Zone gcp 可用区资源需要
+	Zone     string                   `json:"zone" validate:"omitempty"`
+	ResType  enumor.CloudResourceType `json:"res_type" validate:"required"`
+	CloudIDs []string                 `json:"cloud_ids" validate:"required,min=1,max=100"`
+	Keys     []string                 `json:"keys" validate:"required,min=1"`
+}
+
+// Validate UnTagResOption.
+func (opt *UnTagResOption) Validate() error {
+	if opt == nil {
+		return errors.New("untag resource option is required")
+	}
+
+	return validator.Validate.Struct(opt)
+}
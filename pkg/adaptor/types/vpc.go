@@ -276,6 +276,8 @@ type Vpc[T VpcExtension] struct {
 	Region    string  `json:"region"`
 	Memo      *string `json:"memo,omitempty"`
 	Extension *T      `json:"extension"`
+	// Tags 云上标签，目前 tcloud、aws、azure、huawei 会赋值
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // AzureVpcExtension defines azure vpc extensional info.
@@ -300,6 +302,11 @@ func (vpc TCloudVpc) GetCloudID() string {
 	return vpc.CloudID
 }
 
+// GetCloudTags ...
+func (vpc TCloudVpc) GetCloudTags() map[string]string {
+	return vpc.Tags
+}
+
 // AwsVpc defines aws vpc.
 type AwsVpc Vpc[cloud.AwsVpcExtension]
 
@@ -308,6 +315,11 @@ func (vpc AwsVpc) GetCloudID() string {
 	return vpc.CloudID
 }
 
+// GetCloudTags ...
+func (vpc AwsVpc) GetCloudTags() map[string]string {
+	return vpc.Tags
+}
+
 // GcpVpc defines gcp vpc.
 type GcpVpc Vpc[cloud.GcpVpcExtension]
 
@@ -324,6 +336,11 @@ func (vpc AzureVpc) GetCloudID() string {
 	return vpc.CloudID
 }
 
+// GetCloudTags ...
+func (vpc AzureVpc) GetCloudTags() map[string]string {
+	return vpc.Tags
+}
+
 // HuaWeiVpc defines huawei vpc.
 type HuaWeiVpc Vpc[cloud.HuaWeiVpcExtension]
 
@@ -332,6 +349,11 @@ func (vpc HuaWeiVpc) GetCloudID() string {
 	return vpc.CloudID
 }
 
+// GetCloudTags ...
+func (vpc HuaWeiVpc) GetCloudTags() map[string]string {
+	return vpc.Tags
+}
+
 // VpcUsage define vpc usage.
 type VpcUsage struct {
 	ID           *string  `json:"id"`
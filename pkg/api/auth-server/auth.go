@@ -95,6 +95,12 @@ type RegisterResourceCreatorActionResp struct {
 	Data          []client.CreatorActionPolicy `json:"data"`
 }
 
+// InvalidateCacheReq invalidate auth-server authorize cache request.
+type InvalidateCacheReq struct {
+	// Users whose authorize cache need to be invalidated, empty means all users.
+	Users []string `json:"users"`
+}
+
 // GetNoAuthSkipUrlResp get iam apply permission url response.
 type GetNoAuthSkipUrlResp struct {
 	rest.BaseResp `json:",inline"`
@@ -35,3 +35,13 @@ type ItsmApproveResult struct {
 func (req *ItsmApproveResult) Validate() error {
 	return validator.Validate.Struct(req)
 }
+
+// NativeApprovalOperateReq 内置审批引擎审批通过、驳回和评论的请求
+type NativeApprovalOperateReq struct {
+	Comment string `json:"comment" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *NativeApprovalOperateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
@@ -19,15 +19,25 @@
 
 package application
 
-import "hcm/pkg/criteria/validator"
+import (
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/validator"
+)
 
 // CreateCommonReq define create request.
 type CreateCommonReq struct {
 	// Remark 申请单备注
 	Remark *string `json:"remark" validate:"omitempty"`
+	// Tags 申请单标签，用于申请策略评估，如要求申请单必须填写负责人标签。
+	// 主机、硬盘、VPC、负载均衡申请单交付时会将这些标签写入创建的资源
+	Tags map[string]string `json:"tags" validate:"omitempty"`
 }
 
 // Validate create common req.
 func (req *CreateCommonReq) Validate() error {
-	return validator.Validate.Struct(req)
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return protocloud.ValidateResourceTags(req.Tags)
 }
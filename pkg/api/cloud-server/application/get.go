@@ -21,6 +21,7 @@ package application
 
 import (
 	"hcm/pkg/api/core"
+	coreapplication "hcm/pkg/api/core/application"
 	"hcm/pkg/criteria/enumor"
 )
 
@@ -38,4 +39,6 @@ type ApplicationGetResp struct {
 	core.Revision  `json:",inline"`
 
 	TicketUrl string `json:"ticket_url"`
+	// ApprovalDetail 内置审批引擎的审批详情，ITSM单据为空
+	ApprovalDetail *coreapplication.NativeApproval `json:"approval_detail,omitempty"`
 }
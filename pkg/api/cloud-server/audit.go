@@ -64,3 +64,16 @@ type AuditAsyncTaskListReq struct {
 func (req *AuditAsyncTaskListReq) Validate() error {
 	return validator.Validate.Struct(req)
 }
+
+// -------------------------- Verify Audit Chain --------------------------
+
+// AuditChainVerifyReq define verify the audit hash chains of a day req.
+type AuditChainVerifyReq struct {
+	// Day is the created date of the audits, e.g. 2024-10-28.
+	Day string `json:"day" validate:"required,datetime=2006-01-02"`
+}
+
+// Validate audit chain verify req.
+func (req *AuditChainVerifyReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
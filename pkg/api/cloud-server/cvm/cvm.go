@@ -25,6 +25,7 @@ import (
 
 	rr "hcm/pkg/api/core/recycle-record"
 	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
 )
 
@@ -160,3 +161,66 @@ type CvmRelatedInfo struct {
 	EipCount  int      `json:"eip_count"`
 	Eip       []string `json:"eip"`
 }
+
+// RollingOperateCvmReq 滚动操作主机请求，按批次执行操作，每批次之后经过健康检查才继续下一批次
+type RollingOperateCvmReq struct {
+	IDs []string `json:"ids" validate:"required,min=1"`
+	// BatchSize 每批次操作的主机数
+	BatchSize int `json:"batch_size" validate:"required,min=1"`
+	// PauseSec 每批次操作完成后、健康检查前的暂停秒数
+	PauseSec int `json:"pause_sec" validate:"min=0"`
+	// FailureThreshold 允许累计未通过健康检查的主机数，超过则终止后续批次
+	FailureThreshold int `json:"failure_threshold" validate:"min=0"`
+	// Gate 健康检查配置
+	Gate RollingGateReq `json:"gate" validate:"required"`
+}
+
+// RollingGateReq 滚动操作健康检查配置
+type RollingGateReq struct {
+	Type enumor.RollingGateType `json:"type" validate:"required"`
+	// CheckTimeoutSec 健康检查最长等待秒数，超时仍未通过的主机计为失败
+	CheckTimeoutSec int `json:"check_timeout_sec" validate:"min=0"`
+	// TargetGroupID 检查类型为 lb_target_health 时必填，检查该目标组所绑定监听器下的后端服务健康状态
+	TargetGroupID string `json:"target_group_id" validate:"omitempty"`
+}
+
+// Validate rolling operate cvm request.
+func (req *RollingOperateCvmReq) Validate() error {
+	if len(req.IDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("cvm ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := req.Gate.Type.Validate(); err != nil {
+		return err
+	}
+
+	if req.Gate.Type == enumor.RollingGateLbTargetHealth && len(req.Gate.TargetGroupID) == 0 {
+		return errors.New("target_group_id is required for lb_target_health gate")
+	}
+
+	if req.PauseSec > constant.RollingCvmPauseMaxSec {
+		return fmt.Errorf("pause_sec should <= %d", constant.RollingCvmPauseMaxSec)
+	}
+
+	if req.Gate.CheckTimeoutSec > constant.RollingCvmCheckTimeoutMaxSec {
+		return fmt.Errorf("check_timeout_sec should <= %d", constant.RollingCvmCheckTimeoutMaxSec)
+	}
+
+	return nil
+}
+
+// ResizeCvmReq 调整主机规格请求，运行中的主机会先关机，调整规格后再开机
+type ResizeCvmReq struct {
+	ID string `json:"id" validate:"required"`
+	// InstanceType 目标机型，需在主机所在地域/可用区的可用机型列表中
+	InstanceType string `json:"instance_type" validate:"required"`
+}
+
+// Validate resize cvm request.
+func (req *ResizeCvmReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
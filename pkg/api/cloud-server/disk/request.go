@@ -133,3 +133,14 @@ type DiskDeleteRecycleReq struct {
 func (req DiskDeleteRecycleReq) Validate() error {
 	return validator.Validate.Struct(req)
 }
+
+// ResizeDiskReq 扩容云硬盘请求，扩容后的大小需大于当前大小，单位GB
+type ResizeDiskReq struct {
+	ID       string `json:"id" validate:"required"`
+	DiskSize uint64 `json:"disk_size" validate:"required,min=1"`
+}
+
+// Validate resize disk request.
+func (req *ResizeDiskReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
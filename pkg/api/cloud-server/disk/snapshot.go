@@ -0,0 +1,147 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package csdisk
+
+import (
+	"fmt"
+
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/tools/cron"
+)
+
+// SnapshotCreateReq 为云硬盘创建快照
+type SnapshotCreateReq struct {
+	Snapshots []SnapshotCreateInfo `json:"snapshots" validate:"required,min=1,dive"`
+}
+
+// Validate SnapshotCreateReq.
+func (req *SnapshotCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Snapshots) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("snapshots should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return nil
+}
+
+// SnapshotCreateInfo 创建快照的源云硬盘及快照信息
+type SnapshotCreateInfo struct {
+	DiskID string `json:"disk_id" validate:"required"`
+	// SnapshotName 快照名称，GCP要求由小写字母、数字和连字符组成
+	SnapshotName string `json:"snapshot_name" validate:"required,max=60"`
+	Memo         string `json:"memo" validate:"omitempty,max=255"`
+}
+
+// SnapshotDeleteReq 删除云硬盘快照
+type SnapshotDeleteReq struct {
+	IDs []string `json:"ids" validate:"required,min=1"`
+}
+
+// Validate SnapshotDeleteReq.
+func (req *SnapshotDeleteReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.IDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return nil
+}
+
+// SnapshotRollbackReq 将快照回滚到源云硬盘
+type SnapshotRollbackReq struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// Validate SnapshotRollbackReq.
+func (req *SnapshotRollbackReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// SnapshotSyncReq 同步账号地域下的云硬盘快照
+type SnapshotSyncReq struct {
+	AccountID string `json:"account_id" validate:"required"`
+	Region    string `json:"region" validate:"required"`
+}
+
+// Validate SnapshotSyncReq.
+func (req *SnapshotSyncReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// SnapshotPolicyCreateReq 创建定期快照策略
+type SnapshotPolicyCreateReq struct {
+	Name string `json:"name" validate:"required,max=255"`
+	// Cron 执行周期，标准的5段cron表达式(分 时 日 月 周)
+	Cron string `json:"cron" validate:"required,max=64"`
+	// RetentionCount 每块云硬盘保留的策略快照个数
+	RetentionCount uint64   `json:"retention_count" validate:"required,min=1,max=100"`
+	DiskIDs        []string `json:"disk_ids" validate:"required,min=1,max=100"`
+	Enabled        *bool    `json:"enabled" validate:"required"`
+	Memo           *string  `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate SnapshotPolicyCreateReq.
+func (req *SnapshotPolicyCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return cron.Validate(req.Cron)
+}
+
+// SnapshotPolicyUpdateReq 更新定期快照策略，为空的字段不更新
+type SnapshotPolicyUpdateReq struct {
+	Name           string    `json:"name" validate:"omitempty,max=255"`
+	Cron           string    `json:"cron" validate:"omitempty,max=64"`
+	RetentionCount uint64    `json:"retention_count" validate:"omitempty,max=100"`
+	DiskIDs        *[]string `json:"disk_ids" validate:"omitempty,min=1,max=100"`
+	Enabled        *bool     `json:"enabled" validate:"omitempty"`
+	Memo           *string   `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate SnapshotPolicyUpdateReq.
+func (req *SnapshotPolicyUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Cron) != 0 {
+		return cron.Validate(req.Cron)
+	}
+
+	return nil
+}
+
+// SnapshotPolicyDeleteReq 删除定期快照策略，已创建的策略快照不会被删除
+type SnapshotPolicyDeleteReq struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// Validate SnapshotPolicyDeleteReq.
+func (req *SnapshotPolicyDeleteReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
@@ -0,0 +1,160 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudserver
+
+import (
+	"errors"
+	"math"
+
+	"hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/tools/cidr"
+)
+
+// -------------------------- Pool --------------------------
+
+// IpamPoolCreateReq define ipam cidr pool create req.
+type IpamPoolCreateReq struct {
+	Name   string        `json:"name" validate:"required,max=255"`
+	Vendor enumor.Vendor `json:"vendor" validate:"required"`
+	Region string        `json:"region" validate:"required"`
+	Cidr   string        `json:"cidr" validate:"required,cidrv4"`
+	Memo   *string       `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate IpamPoolCreateReq.
+func (req *IpamPoolCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return req.Vendor.Validate()
+}
+
+// IpamPoolUpdateReq define ipam cidr pool update req.
+type IpamPoolUpdateReq struct {
+	Name string  `json:"name" validate:"omitempty,max=255"`
+	Memo *string `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate IpamPoolUpdateReq.
+func (req *IpamPoolUpdateReq) Validate() error {
+	if len(req.Name) == 0 && req.Memo == nil {
+		return errors.New("name or memo is required")
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// IpamBatchDeleteReq define ipam cidr pool or allocation batch delete req.
+type IpamBatchDeleteReq struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// Validate IpamBatchDeleteReq.
+func (req *IpamBatchDeleteReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// -------------------------- Allocation --------------------------
+
+// IpamReserveReq define ipam cidr reserve req, the reserved cidr will never be proposed or used by new vpc.
+type IpamReserveReq struct {
+	PoolID string  `json:"pool_id" validate:"required"`
+	Cidr   string  `json:"cidr" validate:"required,cidrv4"`
+	Memo   *string `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate IpamReserveReq.
+func (req *IpamReserveReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// -------------------------- Propose --------------------------
+
+// IpamProposeReq define ipam cidr propose req. pool_id is used to propose a cidr for a new vpc, and vpc_id is used
+// to propose a cidr for a new subnet, one of them should be set. the size of the cidr is defined by mask_len or
+// ip_num, if ip_num is set, the smallest cidr which has at least ip_num addresses is proposed.
+type IpamProposeReq struct {
+	PoolID  string `json:"pool_id" validate:"omitempty"`
+	VpcID   string `json:"vpc_id" validate:"omitempty"`
+	MaskLen int    `json:"mask_len" validate:"omitempty,min=8,max=29"`
+	IPNum   uint64 `json:"ip_num" validate:"omitempty,min=1"`
+	// Allocate 是否为vpc分配该网段，分配后该网段不会再推荐给其他vpc，只在pool_id不为空时生效
+	Allocate bool    `json:"allocate" validate:"omitempty"`
+	Memo     *string `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate IpamProposeReq.
+func (req *IpamProposeReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if (len(req.PoolID) == 0) == (len(req.VpcID) == 0) {
+		return errors.New("one of pool_id and vpc_id should be set")
+	}
+
+	if (req.MaskLen == 0) == (req.IPNum == 0) {
+		return errors.New("one of mask_len and ip_num should be set")
+	}
+
+	if req.IPNum > math.MaxInt32 {
+		return errors.New("ip_num is too large")
+	}
+
+	return nil
+}
+
+// GetMaskLen get the mask length of the proposed cidr.
+func (req *IpamProposeReq) GetMaskLen() int {
+	if req.MaskLen != 0 {
+		return req.MaskLen
+	}
+
+	return cidr.IpNumToMasklen(int(req.IPNum))
+}
+
+// IpamProposeResult define ipam cidr propose result.
+type IpamProposeResult struct {
+	Cidr string `json:"cidr"`
+	// AllocationID 分配记录ID，只在allocate为true时返回
+	AllocationID string `json:"allocation_id,omitempty"`
+}
+
+// -------------------------- Check --------------------------
+
+// IpamCheckReq define ipam cidr check req. if vpc_id is set, only check the subnets of the vpc.
+type IpamCheckReq struct {
+	Cidr  string `json:"cidr" validate:"required,cidrv4"`
+	VpcID string `json:"vpc_id" validate:"omitempty"`
+}
+
+// Validate IpamCheckReq.
+func (req *IpamCheckReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// IpamCheckResult define ipam cidr check result.
+type IpamCheckResult struct {
+	Overlapped bool                     `json:"overlapped"`
+	Conflicts  []cloud.IpamCidrConflict `json:"conflicts"`
+}
@@ -0,0 +1,191 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudserver
+
+import (
+	"errors"
+	"fmt"
+
+	rr "hcm/pkg/api/core/recycle-record"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+
+	"github.com/shopspring/decimal"
+)
+
+// OrphanResTypes 支持检测闲置、孤立资源的资源类型
+var OrphanResTypes = []enumor.CloudResourceType{
+	enumor.DiskCloudResType,
+	enumor.EipCloudResType,
+	enumor.SecurityGroupCloudResType,
+	enumor.LoadBalancerCloudResType,
+	enumor.CvmCloudResType,
+}
+
+// DefaultOrphanStoppedDays 主机关机超过该天数视为闲置
+const DefaultOrphanStoppedDays = 30
+
+func validateOrphanResType(resType enumor.CloudResourceType) error {
+	for _, one := range OrphanResTypes {
+		if one == resType {
+			return nil
+		}
+	}
+	return fmt.Errorf("res_type %s does not support orphan detection", resType)
+}
+
+// OrphanResListReq 查询业务下闲置、孤立资源报告
+type OrphanResListReq struct {
+	// ResTypes 检测的资源类型，不传时检测全部支持的类型
+	ResTypes   []enumor.CloudResourceType `json:"res_types" validate:"omitempty,max=5"`
+	AccountIDs []string                   `json:"account_ids" validate:"omitempty,max=100"`
+	// StoppedDays 主机关机超过该天数视为闲置，不传时为30天
+	StoppedDays int `json:"stopped_days" validate:"omitempty,min=1,max=365"`
+	// BillYear、BillMonth 估算资源月成本使用的账单月份，不传时使用上个月的账单
+	BillYear  int `json:"bill_year" validate:"omitempty,min=2000"`
+	BillMonth int `json:"bill_month" validate:"omitempty,min=1,max=12"`
+}
+
+// Validate OrphanResListReq.
+func (req *OrphanResListReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, resType := range req.ResTypes {
+		if err := validateOrphanResType(resType); err != nil {
+			return err
+		}
+	}
+
+	if (req.BillYear == 0) != (req.BillMonth == 0) {
+		return errors.New("bill_year and bill_month must be set together")
+	}
+
+	return nil
+}
+
+// OrphanResListResult defines list orphan resources result.
+type OrphanResListResult struct {
+	Details []OrphanRes `json:"details"`
+	// Summary 按账号汇总的闲置资源数量和估算成本
+	Summary []OrphanAccountSummary `json:"summary"`
+}
+
+// OrphanReason 资源被判定为闲置、孤立的原因
+type OrphanReason string
+
+const (
+	// OrphanDiskUnattached 磁盘未挂载到任何主机
+	OrphanDiskUnattached OrphanReason = "disk_unattached"
+	// OrphanEipUnbound 弹性公网IP未绑定任何实例
+	OrphanEipUnbound OrphanReason = "eip_unbound"
+	// OrphanSGUnused 安全组未关联主机、负载均衡等任何资源
+	OrphanSGUnused OrphanReason = "security_group_unused"
+	// OrphanLbNoListener 负载均衡没有监听器
+	OrphanLbNoListener OrphanReason = "load_balancer_no_listener"
+	// OrphanLbNoTarget 负载均衡的监听器没有任何后端目标
+	OrphanLbNoTarget OrphanReason = "load_balancer_no_target"
+	// OrphanCvmLongStopped 主机关机超过指定天数
+	OrphanCvmLongStopped OrphanReason = "cvm_long_stopped"
+)
+
+// OrphanRes 闲置、孤立资源
+type OrphanRes struct {
+	ResType   enumor.CloudResourceType `json:"res_type"`
+	ID        string                   `json:"id"`
+	CloudID   string                   `json:"cloud_id"`
+	Name      string                   `json:"name"`
+	Vendor    enumor.Vendor            `json:"vendor"`
+	AccountID string                   `json:"account_id"`
+	Region    string                   `json:"region"`
+	BkBizID   int64                    `json:"bk_biz_id"`
+	Reason    OrphanReason             `json:"reason"`
+	// IdleSince 资源开始闲置的时间下限，目前仅关机主机有值，取主机最后一次更新的时间
+	IdleSince string `json:"idle_since,omitempty"`
+
+	// MonthlyCost 资源在账单月份的成本，账单中无法关联到该资源时为空
+	MonthlyCost *decimal.Decimal    `json:"monthly_cost,omitempty"`
+	Currency    enumor.CurrencyCode `json:"currency,omitempty"`
+}
+
+// OrphanAccountSummary 账号下闲置资源的汇总
+type OrphanAccountSummary struct {
+	AccountID string        `json:"account_id"`
+	Vendor    enumor.Vendor `json:"vendor"`
+	// Counts 各资源类型的闲置资源数量
+	Counts map[enumor.CloudResourceType]int `json:"counts"`
+	// EstimatedCosts 按币种汇总的闲置资源月成本
+	EstimatedCosts []OrphanCost `json:"estimated_costs"`
+}
+
+// OrphanCost 某一币种的成本
+type OrphanCost struct {
+	Currency enumor.CurrencyCode `json:"currency"`
+	Cost     decimal.Decimal     `json:"cost"`
+}
+
+// OrphanResRecycleReq 将选中的闲置资源移入回收站
+type OrphanResRecycleReq struct {
+	Items []OrphanResRecycleItem `json:"items" validate:"min=1,max=5,dive"`
+	// StoppedDays 校验主机是否仍为闲置时使用的关机天数，需与查询报告时一致，不传时为30天
+	StoppedDays int `json:"stopped_days" validate:"omitempty,min=1,max=365"`
+	// CvmRecycleOptions 回收主机时磁盘、弹性公网IP是否随主机一起回收，默认不随主机回收
+	CvmRecycleOptions rr.CvmRecycleOptions `json:"cvm_recycle_options"`
+}
+
+// OrphanResRecycleItem 同一资源类型待回收的闲置资源
+type OrphanResRecycleItem struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	IDs     []string                 `json:"ids" validate:"min=1,max=100"`
+}
+
+// Validate OrphanResRecycleReq.
+func (req *OrphanResRecycleReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	resTypes := make(map[enumor.CloudResourceType]struct{}, len(req.Items))
+	for _, item := range req.Items {
+		if err := validateOrphanResType(item.ResType); err != nil {
+			return err
+		}
+		if _, exists := resTypes[item.ResType]; exists {
+			return fmt.Errorf("res_type %s is duplicated", item.ResType)
+		}
+		resTypes[item.ResType] = struct{}{}
+	}
+
+	return nil
+}
+
+// OrphanResRecycleResult 闲置资源回收结果，各资源类型分别回收，互不影响
+type OrphanResRecycleResult struct {
+	Details []OrphanResRecycleDetail `json:"details"`
+}
+
+// OrphanResRecycleDetail 某一资源类型的回收结果
+type OrphanResRecycleDetail struct {
+	ResType enumor.CloudResourceType `json:"res_type"`
+	// Result 与该资源类型回收接口的返回一致，成功时为回收任务ID，部分失败时为失败详情
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
@@ -0,0 +1,97 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudserver
+
+import (
+	"fmt"
+
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/validator"
+)
+
+// PrivateImageCreateReq 基于主机创建私有镜像
+type PrivateImageCreateReq struct {
+	Images []PrivateImageCreateInfo `json:"images" validate:"required,min=1,dive"`
+	// ForcePoweroff 创建镜像时是否强制关机，仅腾讯云、亚马逊云支持，亚马逊云为创建前重启主机
+	ForcePoweroff bool `json:"force_poweroff" validate:"omitempty"`
+}
+
+// Validate PrivateImageCreateReq.
+func (req *PrivateImageCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Images) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("images should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return nil
+}
+
+// PrivateImageCreateInfo 创建私有镜像的源主机及镜像信息
+type PrivateImageCreateInfo struct {
+	CvmID     string `json:"cvm_id" validate:"required"`
+	ImageName string `json:"image_name" validate:"required,max=128"`
+	Memo      string `json:"memo" validate:"omitempty,max=255"`
+}
+
+// PrivateImageCopyReq 复制私有镜像到其他地域
+type PrivateImageCopyReq struct {
+	ID         string   `json:"id" validate:"required"`
+	DstRegions []string `json:"dst_regions" validate:"required,min=1,max=10"`
+	// ImageName 目的地域的镜像名称，为空时与源镜像名称一致
+	ImageName string `json:"image_name" validate:"omitempty,max=128"`
+}
+
+// Validate PrivateImageCopyReq.
+func (req *PrivateImageCopyReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// PrivateImageShareReq 共享私有镜像给同一根账号下的其他账号
+type PrivateImageShareReq struct {
+	ID              string   `json:"id" validate:"required"`
+	ShareAccountIDs []string `json:"share_account_ids" validate:"required,min=1,max=50"`
+}
+
+// Validate PrivateImageShareReq.
+func (req *PrivateImageShareReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// PrivateImageDeprecateReq 弃用或恢复私有镜像，弃用的镜像不能再用于创建主机
+type PrivateImageDeprecateReq struct {
+	IDs        []string `json:"ids" validate:"required,min=1"`
+	Deprecated *bool    `json:"deprecated" validate:"required"`
+}
+
+// Validate PrivateImageDeprecateReq.
+func (req *PrivateImageDeprecateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.IDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return nil
+}
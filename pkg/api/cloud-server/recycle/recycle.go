@@ -21,17 +21,66 @@
 package recycle
 
 import (
+	"hcm/pkg/api/core"
 	rr "hcm/pkg/api/core/recycle-record"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
 )
 
 // ------------------------ Recycle ------------------------
 
+// ResRecycleReq defines recycle resource request, used by the resources recycled in the generic way.
+type ResRecycleReq struct {
+	IDs []string `json:"ids" validate:"min=1,max=100"`
+}
+
+// Validate ResRecycleReq
+func (req ResRecycleReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ResRecoverReq defines recover resource request.
+type ResRecoverReq struct {
+	RecordIDs []string `json:"record_ids" validate:"min=1,max=100"`
+}
+
+// Validate ResRecoverReq
+func (req ResRecoverReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ResDeleteRecycleReq defines delete recycled resource request.
+type ResDeleteRecycleReq struct {
+	RecordIDs []string `json:"record_ids" validate:"min=1,max=100"`
+}
+
+// Validate ResDeleteRecycleReq
+func (req ResDeleteRecycleReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// RecyclePolicyDeleteReq defines batch delete recycle policy request.
+type RecyclePolicyDeleteReq struct {
+	IDs []string `json:"ids" validate:"min=1,max=100"`
+}
+
+// Validate RecyclePolicyDeleteReq
+func (req RecyclePolicyDeleteReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
 // RecycleResult defines recycle resource result.
 type RecycleResult struct {
 	TaskID string `json:"task_id"`
 }
 
+// ResRecycleResult defines the result of the resources recycled in the generic way, task id is set when some of the
+// resources are put into recycle bin, the failed resources are not put into recycle bin.
+type ResRecycleResult struct {
+	TaskID                     string `json:"task_id,omitempty"`
+	core.BatchOperateAllResult `json:",inline"`
+}
+
 // -------------------------- List --------------------------
 
 // RecycleRecordListResult defines list recycle record result.
@@ -0,0 +1,132 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudserver
+
+import (
+	"errors"
+	"fmt"
+
+	hcrenewal "hcm/pkg/api/hc-service/renewal"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ExpiringResListReq 查询指定天数内到期的包年包月资源，不指定资源类型时查询所有支持的资源类型
+type ExpiringResListReq struct {
+	ResType    enumor.CloudResourceType `json:"res_type" validate:"omitempty"`
+	AccountIDs []string                 `json:"account_ids" validate:"omitempty,max=100"`
+	// AdvanceDays 查询该天数内到期的资源，已过期但尚未释放的资源也会返回
+	AdvanceDays uint `json:"advance_days" validate:"required,min=1,max=365"`
+}
+
+// Validate ExpiringResListReq.
+func (req *ExpiringResListReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.ResType) != 0 {
+		return hcrenewal.ValidatePrepaidResType(req.ResType)
+	}
+
+	return nil
+}
+
+// ExpiringResListResult defines list expiring prepaid resources result.
+type ExpiringResListResult struct {
+	Details []ExpiringRes `json:"details"`
+}
+
+// ExpiringRes 即将到期的包年包月资源
+type ExpiringRes struct {
+	ResType    enumor.CloudResourceType `json:"res_type"`
+	ID         string                   `json:"id"`
+	CloudID    string                   `json:"cloud_id"`
+	Name       string                   `json:"name"`
+	Vendor     enumor.Vendor            `json:"vendor"`
+	AccountID  string                   `json:"account_id"`
+	Region     string                   `json:"region"`
+	BkBizID    int64                    `json:"bk_biz_id"`
+	ExpireTime string                   `json:"expire_time"`
+	// RemainDays 距离到期的剩余天数，已过期时为负数
+	RemainDays int  `json:"remain_days"`
+	AutoRenew  bool `json:"auto_renew"`
+}
+
+// PrepaidResRenewReq 续费包年包月资源
+type PrepaidResRenewReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	// AccountID 带宽包所属账号，资源类型为带宽包时必填
+	AccountID string `json:"account_id" validate:"omitempty"`
+	// IDs 资源ID，资源类型为带宽包时为带宽包的云上ID
+	IDs []string `json:"ids" validate:"required,min=1"`
+	// Period 续费时长，单位：月
+	Period int64 `json:"period" validate:"required,min=1,max=36"`
+}
+
+// Validate PrepaidResRenewReq.
+func (req *PrepaidResRenewReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.IDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return validatePrepaidResAccount(req.ResType, req.AccountID)
+}
+
+// PrepaidResAutoRenewReq 开启或关闭包年包月资源的自动续费
+type PrepaidResAutoRenewReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	// AccountID 带宽包所属账号，资源类型为带宽包时必填
+	AccountID string `json:"account_id" validate:"omitempty"`
+	// IDs 资源ID，资源类型为带宽包时为带宽包的云上ID
+	IDs       []string `json:"ids" validate:"required,min=1"`
+	AutoRenew *bool    `json:"auto_renew" validate:"required"`
+}
+
+// Validate PrepaidResAutoRenewReq.
+func (req *PrepaidResAutoRenewReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.IDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return validatePrepaidResAccount(req.ResType, req.AccountID)
+}
+
+// validatePrepaidResAccount 带宽包未纳入hcm资源管理，需要指定所属账号
+func validatePrepaidResAccount(resType enumor.CloudResourceType, accountID string) error {
+	if err := hcrenewal.ValidatePrepaidResType(resType); err != nil {
+		return err
+	}
+
+	if resType == enumor.BandwidthPackageCloudResType && len(accountID) == 0 {
+		return errors.New("account_id is required when res_type is bandwidth_package")
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudserver
+
+import (
+	"errors"
+	"fmt"
+
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ResourceTagAddReq 为同一类型的资源添加标签，已存在的同名标签的值会被覆盖
+type ResourceTagAddReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResIDs  []string                 `json:"res_ids" validate:"required,min=1"`
+	Tags    map[string]string        `json:"tags" validate:"required,min=1"`
+}
+
+// Validate ResourceTagAddReq.
+func (req *ResourceTagAddReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.ResIDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("res_ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return protocloud.ValidateResourceTags(req.Tags)
+}
+
+// ResourceTagRemoveReq 删除同一类型资源的指定标签
+type ResourceTagRemoveReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResIDs  []string                 `json:"res_ids" validate:"required,min=1"`
+	Keys    []string                 `json:"keys" validate:"required,min=1"`
+}
+
+// Validate ResourceTagRemoveReq.
+func (req *ResourceTagRemoveReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.ResIDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("res_ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	for _, key := range req.Keys {
+		if len(key) == 0 {
+			return errors.New("tag key can not be empty")
+		}
+	}
+
+	return nil
+}
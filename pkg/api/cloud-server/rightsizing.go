@@ -0,0 +1,130 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudserver
+
+import (
+	"errors"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+
+	"github.com/shopspring/decimal"
+)
+
+// RightsizingRecommendListReq 查询业务下主机的规格优化建议
+type RightsizingRecommendListReq struct {
+	AccountIDs []string `json:"account_ids" validate:"omitempty,max=100"`
+	CvmIDs     []string `json:"cvm_ids" validate:"omitempty,max=500"`
+	// BillYear、BillMonth 估算主机月成本使用的账单月份，不传时使用上个月的账单
+	BillYear  int `json:"bill_year" validate:"omitempty,min=2000"`
+	BillMonth int `json:"bill_month" validate:"omitempty,min=1,max=12"`
+	// Page 按主机ID分页，每页需实时查询机型和价格，单页最多100台主机
+	Page *core.BasePage `json:"page" validate:"required"`
+}
+
+// rightsizingMaxPageLimit 单页最多生成建议的主机数
+const rightsizingMaxPageLimit = 100
+
+// Validate RightsizingRecommendListReq.
+func (req *RightsizingRecommendListReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if (req.BillYear == 0) != (req.BillMonth == 0) {
+		return errors.New("bill_year and bill_month must be set together")
+	}
+
+	return req.Page.Validate(&core.PageOption{MaxLimit: rightsizingMaxPageLimit, DisabledSort: true})
+}
+
+// RightsizingRecommendListResult defines list rightsizing recommendations result.
+type RightsizingRecommendListResult struct {
+	// Count 业务下符合条件的运行中主机总数，仅 page.count 为 true 时返回
+	Count   uint64                      `json:"count"`
+	Details []RightsizingRecommendation `json:"details"`
+	// NoMetricCvmIDs 指标源中没有利用率指标，未生成建议的运行中主机
+	NoMetricCvmIDs []string `json:"no_metric_cvm_ids"`
+}
+
+// RightsizingAction 规格优化建议的操作
+type RightsizingAction string
+
+const (
+	// RightsizingDownsize 同机型族内降配
+	RightsizingDownsize RightsizingAction = "downsize"
+	// RightsizingChangeFamily 更换为CPU、内存配比更合适的机型族
+	RightsizingChangeFamily RightsizingAction = "change_family"
+	// RightsizingShutdown 空闲主机关机
+	RightsizingShutdown RightsizingAction = "shutdown"
+)
+
+// RightsizingCostSource 主机成本及预计节省费用的来源
+type RightsizingCostSource string
+
+const (
+	// RightsizingCostBill 按账单月份内能关联到主机的账单费用估算
+	RightsizingCostBill RightsizingCostSource = "bill"
+	// RightsizingCostListPrice 账单无法关联到主机时，按当前机型与目标机型的目录价估算
+	RightsizingCostListPrice RightsizingCostSource = "list_price"
+	// RightsizingCostUnknown 没有可用的账单和目录价，不返回成本及预计节省费用
+	RightsizingCostUnknown RightsizingCostSource = "unknown"
+)
+
+// CvmUtilization 主机在统计周期内的CPU、内存使用率，单位：百分比
+type CvmUtilization struct {
+	CpuAvg float64 `json:"cpu_avg"`
+	CpuP95 float64 `json:"cpu_p95"`
+	MemAvg float64 `json:"mem_avg"`
+	MemP95 float64 `json:"mem_p95"`
+}
+
+// RightsizingRecommendation 主机规格优化建议，内存单位为MB
+type RightsizingRecommendation struct {
+	CvmID     string        `json:"cvm_id"`
+	CloudID   string        `json:"cloud_id"`
+	Name      string        `json:"name"`
+	Vendor    enumor.Vendor `json:"vendor"`
+	AccountID string        `json:"account_id"`
+	Region    string        `json:"region"`
+	Zone      string        `json:"zone"`
+	BkBizID   int64         `json:"bk_biz_id"`
+
+	Action      RightsizingAction `json:"action"`
+	Reason      string            `json:"reason"`
+	Utilization CvmUtilization    `json:"utilization"`
+
+	CurrentInstanceType string `json:"current_instance_type"`
+	CurrentCPU          int64  `json:"current_cpu"`
+	CurrentMemory       int64  `json:"current_memory"`
+	// TargetInstanceType 建议调整的目标机型，关机建议时为空
+	TargetInstanceType string `json:"target_instance_type,omitempty"`
+	TargetCPU          int64  `json:"target_cpu,omitempty"`
+	TargetMemory       int64  `json:"target_memory,omitempty"`
+
+	// CostSource 成本及预计节省费用的来源，为 unknown 时不返回成本及预计节省费用
+	CostSource RightsizingCostSource `json:"cost_source"`
+	// MonthlyCost 主机每月成本，来源见 CostSource，成本未知时为空
+	MonthlyCost *decimal.Decimal    `json:"monthly_cost,omitempty"`
+	Currency    enumor.CurrencyCode `json:"currency,omitempty"`
+	// EstimatedMonthlySavings 按机型规格估算的每月可节省费用，成本未知时为空
+	EstimatedMonthlySavings *decimal.Decimal `json:"estimated_monthly_savings,omitempty"`
+}
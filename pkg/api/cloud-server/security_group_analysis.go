@@ -0,0 +1,143 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudserver
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// -------------------------- Effective Rule --------------------------
+
+// SGEffectiveRuleReq define security group effective rule analyze req.
+type SGEffectiveRuleReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResID   string                   `json:"res_id" validate:"required"`
+}
+
+// Validate SGEffectiveRuleReq.
+func (req *SGEffectiveRuleReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return validateSGAnalysisResType(req.ResType)
+}
+
+func validateSGAnalysisResType(resType enumor.CloudResourceType) error {
+	switch resType {
+	case enumor.CvmCloudResType, enumor.NetworkInterfaceCloudResType:
+	default:
+		return fmt.Errorf("res_type: %s not support, only support cvm and network_interface", resType)
+	}
+
+	return nil
+}
+
+// SGEffectiveRuleResult define security group effective rule analyze result.
+type SGEffectiveRuleResult struct {
+	Vendor  enumor.Vendor            `json:"vendor"`
+	ResType enumor.CloudResourceType `json:"res_type"`
+	ResID   string                   `json:"res_id"`
+	// Policies 流量经过的检查点，流量需要被所有检查点放通才能到达
+	Policies []SGPolicyAnalysis `json:"policies"`
+}
+
+// SGPolicyAnalysis define analysis result of one checkpoint on the traffic path.
+type SGPolicyAnalysis struct {
+	// Scope 检查点作用范围，比如 cvm、network_interface、subnet、vpc
+	Scope            enumor.CloudResourceType       `json:"scope"`
+	ScopeID          string                         `json:"scope_id"`
+	SecurityGroupIDs []string                       `json:"security_group_ids"`
+	DefaultIngress   enumor.SecurityGroupRuleAction `json:"default_ingress"`
+	DefaultEgress    enumor.SecurityGroupRuleAction `json:"default_egress"`
+	Ingress          []cloud.AnalyzedSGRule         `json:"ingress"`
+	Egress           []cloud.AnalyzedSGRule         `json:"egress"`
+}
+
+// -------------------------- Reachability --------------------------
+
+// SGReachabilityCheckReq define security group reachability check req.
+type SGReachabilityCheckReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResID   string                   `json:"res_id" validate:"required"`
+	// Type ingress 时 Address 为源地址，egress 时 Address 为目的地址
+	Type     enumor.SecurityGroupRuleType `json:"type" validate:"required"`
+	Address  string                       `json:"address" validate:"required"`
+	Protocol string                       `json:"protocol" validate:"required"`
+	Port     int64                        `json:"port" validate:"omitempty,min=0,max=65535"`
+}
+
+// Validate SGReachabilityCheckReq.
+func (req *SGReachabilityCheckReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := validateSGAnalysisResType(req.ResType); err != nil {
+		return err
+	}
+
+	if req.Type != enumor.Ingress && req.Type != enumor.Egress {
+		return fmt.Errorf("type: %s not support", req.Type)
+	}
+
+	if net.ParseIP(req.Address) == nil {
+		return fmt.Errorf("address: %s is not a valid ip", req.Address)
+	}
+
+	switch strings.ToLower(req.Protocol) {
+	case "tcp", "udp":
+		if req.Port == 0 {
+			return fmt.Errorf("port is required when protocol is %s", req.Protocol)
+		}
+	case "icmp", "icmpv6", "gre":
+	default:
+		return fmt.Errorf("protocol: %s not support", req.Protocol)
+	}
+
+	return nil
+}
+
+// SGReachabilityResult define security group reachability check result.
+type SGReachabilityResult struct {
+	Allowed bool `json:"allowed"`
+	// Indeterminate 存在无法判定是否命中的规则且可能改变结果，此时 Allowed 为 false
+	Indeterminate bool               `json:"indeterminate"`
+	Decisions     []SGPolicyDecision `json:"decisions"`
+}
+
+// SGPolicyDecision define reachability decision of one checkpoint.
+type SGPolicyDecision struct {
+	Scope   enumor.CloudResourceType `json:"scope"`
+	ScopeID string                   `json:"scope_id"`
+	Allowed bool                     `json:"allowed"`
+	// MatchedRule 命中的规则，为空表示未命中任何规则，使用默认动作
+	MatchedRule *cloud.AnalyzedSGRule `json:"matched_rule,omitempty"`
+	// UnresolvedRuleIDs 协议端口匹配，但对端为安全组、地址模版等无法判定的规则
+	UnresolvedRuleIDs []string `json:"unresolved_rule_ids,omitempty"`
+	// Indeterminate 无法判定的规则中存在与最终动作不同的规则，检查点的结果无法确定，此时 Allowed 为 false
+	Indeterminate bool `json:"indeterminate"`
+}
@@ -0,0 +1,144 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudserver
+
+import (
+	"errors"
+
+	"hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// -------------------------- Create --------------------------
+
+// SGRuleTemplateCreateReq define security group rule template create req.
+type SGRuleTemplateCreateReq struct {
+	Name  string                 `json:"name" validate:"required,max=255"`
+	Rules []cloud.SGTemplateRule `json:"rules" validate:"required,min=1,max=100"`
+	Memo  *string                `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate SGRuleTemplateCreateReq.
+func (req *SGRuleTemplateCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return cloud.ValidateSGTemplateRules(req.Rules)
+}
+
+// -------------------------- Update --------------------------
+
+// SGRuleTemplateUpdateReq define security group rule template update req.
+type SGRuleTemplateUpdateReq struct {
+	Name  string                 `json:"name" validate:"omitempty,max=255"`
+	Rules []cloud.SGTemplateRule `json:"rules" validate:"omitempty,max=100"`
+	Memo  *string                `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate SGRuleTemplateUpdateReq.
+func (req *SGRuleTemplateUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Name) == 0 && len(req.Rules) == 0 && req.Memo == nil {
+		return errors.New("at least one of name, rules, memo should be updated")
+	}
+
+	if len(req.Rules) != 0 {
+		return cloud.ValidateSGTemplateRules(req.Rules)
+	}
+
+	return nil
+}
+
+// -------------------------- Delete --------------------------
+
+// SGRuleTemplateDeleteReq define security group rule template delete req.
+type SGRuleTemplateDeleteReq struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// Validate SGRuleTemplateDeleteReq.
+func (req *SGRuleTemplateDeleteReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// -------------------------- Apply --------------------------
+
+// SGRuleTemplateApplyReq define security group rule template apply req.
+type SGRuleTemplateApplyReq struct {
+	// SecurityGroupIDs 下发的安全组，支持tcloud、aws、huawei、azure
+	SecurityGroupIDs []string `json:"security_group_ids" validate:"omitempty,max=100"`
+	// GcpVpcIDs gcp没有安全组，规则会以防火墙规则的形式下发到vpc
+	GcpVpcIDs []string `json:"gcp_vpc_ids" validate:"omitempty,max=100"`
+	// GcpTargetTags gcp防火墙规则的目标标签，为空表示作用于vpc下的所有实例
+	GcpTargetTags []string `json:"gcp_target_tags" validate:"omitempty"`
+}
+
+// Validate SGRuleTemplateApplyReq.
+func (req *SGRuleTemplateApplyReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.SecurityGroupIDs) == 0 && len(req.GcpVpcIDs) == 0 {
+		return errors.New("security_group_ids or gcp_vpc_ids is required")
+	}
+
+	if len(req.SecurityGroupIDs)+len(req.GcpVpcIDs) > 100 {
+		return errors.New("security_group_ids and gcp_vpc_ids should <= 100 in total")
+	}
+
+	return nil
+}
+
+// SGRuleTemplateApplyResult define security group rule template apply result.
+type SGRuleTemplateApplyResult struct {
+	// FlowID 下发规则的异步任务流ID
+	FlowID string `json:"flow_id"`
+	// ApplyIDs 每个下发目标对应的下发记录ID
+	ApplyIDs []string `json:"apply_ids"`
+}
+
+// -------------------------- Drift --------------------------
+
+// SGRuleTemplateDriftResult define security group rule template drift check result.
+type SGRuleTemplateDriftResult struct {
+	TemplateID      string                `json:"template_id"`
+	TemplateVersion uint64                `json:"template_version"`
+	Details         []SGRuleTemplateDrift `json:"details"`
+}
+
+// SGRuleTemplateDrift define drift check result of one apply target.
+type SGRuleTemplateDrift struct {
+	ApplyID        string                      `json:"apply_id"`
+	Vendor         enumor.Vendor               `json:"vendor"`
+	ResType        enumor.CloudResourceType    `json:"res_type"`
+	ResID          string                      `json:"res_id"`
+	CloudResID     string                      `json:"cloud_res_id"`
+	AppliedVersion uint64                      `json:"applied_version"`
+	Status         enumor.SGRuleTplDriftStatus `json:"status"`
+	// MissingRules 当前模版中在目标上已经不存在的规则下标
+	MissingRules []int  `json:"missing_rules"`
+	Reason       string `json:"reason,omitempty"`
+}
@@ -0,0 +1,66 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudserver
+
+import (
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/runtime/filter"
+)
+
+// TagPolicyDeleteReq defines batch delete tag policy request.
+type TagPolicyDeleteReq struct {
+	IDs []string `json:"ids" validate:"min=1,max=100"`
+}
+
+// Validate TagPolicyDeleteReq.
+func (req *TagPolicyDeleteReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// TagComplianceStatsReq 按业务或账号统计标签合规率，filter 支持 vendor、account_id、bk_biz_id、res_type 字段
+type TagComplianceStatsReq struct {
+	GroupBy enumor.TagComplianceGroupBy `json:"group_by" validate:"required"`
+	Filter  *filter.Expression          `json:"filter" validate:"omitempty"`
+}
+
+// Validate TagComplianceStatsReq.
+func (req *TagComplianceStatsReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return req.GroupBy.Validate()
+}
+
+// TagComplianceStatsResult defines tag compliance stats result.
+type TagComplianceStatsResult struct {
+	Details []TagComplianceStatsItem `json:"details"`
+}
+
+// TagComplianceStatsItem 分组的标签合规统计，按业务分组时返回 bk_biz_id，按账号分组时返回 account_id
+type TagComplianceStatsItem struct {
+	BkBizID        *int64 `json:"bk_biz_id,omitempty"`
+	AccountID      string `json:"account_id,omitempty"`
+	TotalCount     uint64 `json:"total_count"`
+	CompliantCount uint64 `json:"compliant_count"`
+	// ComplianceRate 合规率百分比，保留两位小数
+	ComplianceRate float64 `json:"compliance_rate"`
+}
@@ -0,0 +1,121 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudserver
+
+import (
+	"fmt"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+const (
+	// DefaultTopologyDepth is the default depth of the topology graph.
+	DefaultTopologyDepth = 2
+	// DefaultTopologyMaxNodes is the default max node count of the topology graph.
+	DefaultTopologyMaxNodes = 500
+)
+
+// TopologyGraphReq define network topology graph req.
+type TopologyGraphReq struct {
+	// ResType 根节点资源类型，支持vpc、subnet、cvm、network_interface、security_group、eip、load_balancer、route_table
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResID   string                   `json:"res_id" validate:"required"`
+	// Depth 从根节点出发的最大跳数，默认为2
+	Depth int `json:"depth" validate:"omitempty,min=1,max=5"`
+	// MaxNodes 返回的最大节点数，超出后停止展开，默认为500
+	MaxNodes int `json:"max_nodes" validate:"omitempty,min=1,max=2000"`
+	// EdgeTypes 只沿着这些类型的关系展开，为空表示所有关系
+	EdgeTypes []enumor.TopologyEdgeType `json:"edge_types" validate:"omitempty"`
+}
+
+// Validate TopologyGraphReq.
+func (req *TopologyGraphReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if !IsTopologyResType(req.ResType) {
+		return fmt.Errorf("res_type: %s not support", req.ResType)
+	}
+
+	for _, one := range req.EdgeTypes {
+		if err := one.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if req.Depth == 0 {
+		req.Depth = DefaultTopologyDepth
+	}
+
+	if req.MaxNodes == 0 {
+		req.MaxNodes = DefaultTopologyMaxNodes
+	}
+
+	return nil
+}
+
+// IsTopologyResType return whether the resource type can be a node of topology graph.
+func IsTopologyResType(resType enumor.CloudResourceType) bool {
+	switch resType {
+	case enumor.VpcCloudResType, enumor.SubnetCloudResType, enumor.CvmCloudResType,
+		enumor.NetworkInterfaceCloudResType, enumor.SecurityGroupCloudResType, enumor.EipCloudResType,
+		enumor.LoadBalancerCloudResType, enumor.RouteTableCloudResType:
+		return true
+	default:
+		return false
+	}
+}
+
+// TopologyGraph define network topology graph.
+type TopologyGraph struct {
+	Root  string         `json:"root"`
+	Nodes []TopologyNode `json:"nodes"`
+	Edges []TopologyEdge `json:"edges"`
+	// Truncated 节点数超过max_nodes后停止展开时为true
+	Truncated bool `json:"truncated"`
+}
+
+// TopologyNode define node of network topology graph.
+type TopologyNode struct {
+	// Key 节点唯一标识，格式为 res_type:res_id
+	Key     string                   `json:"key"`
+	ResType enumor.CloudResourceType `json:"res_type"`
+	ResID   string                   `json:"res_id"`
+	CloudID string                   `json:"cloud_id"`
+	Name    string                   `json:"name"`
+	Vendor  enumor.Vendor            `json:"vendor"`
+	BkBizID int64                    `json:"bk_biz_id"`
+	// Depth 与根节点之间的跳数
+	Depth int `json:"depth"`
+}
+
+// TopologyEdge define edge of network topology graph, source and target are the key of node.
+type TopologyEdge struct {
+	Type   enumor.TopologyEdgeType `json:"type"`
+	Source string                  `json:"source"`
+	Target string                  `json:"target"`
+}
+
+// TopologyNodeKey return the key of topology node.
+func TopologyNodeKey(resType enumor.CloudResourceType, resID string) string {
+	return string(resType) + ":" + resID
+}
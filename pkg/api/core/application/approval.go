@@ -0,0 +1,104 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package coreapplication defines application core types.
+package coreapplication
+
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ApprovalStage 内置审批引擎的审批阶段配置
+type ApprovalStage struct {
+	Name         string              `json:"name" validate:"required,max=64"`
+	ApproverType enumor.ApproverType `json:"approver_type" validate:"required"`
+	// Users 指定的审批人，仅当审批人类型为static_user时生效
+	Users []string `json:"users,omitempty" validate:"omitempty,max=100"`
+}
+
+// Validate ApprovalStage.
+func (s ApprovalStage) Validate() error {
+	if err := validator.Validate.Struct(s); err != nil {
+		return err
+	}
+
+	if err := s.ApproverType.Validate(); err != nil {
+		return err
+	}
+
+	if s.ApproverType == enumor.StaticUserApprover && len(s.Users) == 0 {
+		return fmt.Errorf("stage %s approver type is static_user, users is required", s.Name)
+	}
+
+	return nil
+}
+
+// ApprovalStages 审批阶段配置列表，按顺序依次审批
+type ApprovalStages []ApprovalStage
+
+// Validate ApprovalStages.
+func (s ApprovalStages) Validate() error {
+	if len(s) == 0 {
+		return errors.New("approval stages is required")
+	}
+
+	for _, stage := range s {
+		if err := stage.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NativeApproval 内置审批引擎的单据审批详情
+type NativeApproval struct {
+	// Revision 审批详情的版本，每次变更审批详情时递增，用于并发更新时的CAS
+	Revision int `json:"revision"`
+	// CurrentStage 当前所处的审批阶段下标
+	CurrentStage int `json:"current_stage"`
+	// TimeoutHours 单个阶段的审批超时时间，为0表示不超时
+	TimeoutHours uint                  `json:"timeout_hours"`
+	Stages       []NativeApprovalStage `json:"stages"`
+	Records      []ApprovalRecord      `json:"records"`
+}
+
+// NativeApprovalStage 单据实际的审批阶段
+type NativeApprovalStage struct {
+	Name         string                     `json:"name"`
+	ApproverType enumor.ApproverType        `json:"approver_type"`
+	Approvers    []string                   `json:"approvers"`
+	Status       enumor.ApprovalStageStatus `json:"status"`
+	// Deadline 审批超时时间，超时后审批升级给平台管理员
+	Deadline  string `json:"deadline,omitempty"`
+	Escalated bool   `json:"escalated"`
+}
+
+// ApprovalRecord 审批操作记录
+type ApprovalRecord struct {
+	Stage     int                   `json:"stage"`
+	Operator  string                `json:"operator"`
+	Action    enumor.ApprovalAction `json:"action"`
+	Comment   string                `json:"comment,omitempty"`
+	CreatedAt string                `json:"created_at"`
+}
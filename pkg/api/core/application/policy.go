@@ -0,0 +1,166 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package coreapplication
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ApplicationPolicy 申请策略，在申请单创建审批单据前进行评估
+type ApplicationPolicy struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// BkBizID 策略生效的业务，0表示对所有业务生效
+	BkBizID int64 `json:"bk_biz_id"`
+	// ApplicationType 策略生效的申请类型，为空表示对所有申请类型生效
+	ApplicationType enumor.ApplicationType         `json:"application_type"`
+	Effect          enumor.ApplicationPolicyEffect `json:"effect"`
+	Conditions      PolicyConditions               `json:"conditions"`
+	// Message 违反策略时的提示信息
+	Message       string `json:"message"`
+	Enabled       bool   `json:"enabled"`
+	Memo          string `json:"memo"`
+	core.Revision `json:",inline"`
+}
+
+// PolicyCondition 申请策略的条件，field为申请单事实数据的字段，标签使用tags.{key}
+type PolicyCondition struct {
+	Field string                   `json:"field" validate:"required,max=64"`
+	Op    enumor.PolicyConditionOp `json:"op" validate:"required"`
+	Value interface{}              `json:"value"`
+}
+
+// Validate PolicyCondition.
+func (c PolicyCondition) Validate() error {
+	if err := validator.Validate.Struct(c); err != nil {
+		return err
+	}
+
+	if err := c.Op.Validate(); err != nil {
+		return err
+	}
+
+	if c.Op != enumor.PolicyOpExists && c.Value == nil {
+		return fmt.Errorf("condition %s %s value is required", c.Field, c.Op)
+	}
+
+	return nil
+}
+
+// PolicyConditions 申请策略的条件列表，全部满足才算满足策略
+type PolicyConditions []PolicyCondition
+
+// Validate PolicyConditions.
+func (cs PolicyConditions) Validate() error {
+	if len(cs) == 0 {
+		return errors.New("conditions is required")
+	}
+
+	for _, c := range cs {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PolicyFacts 申请单用于策略评估的事实数据
+type PolicyFacts map[string]interface{}
+
+const (
+	// FactVendor 云厂商
+	FactVendor = "vendor"
+	// FactApplicationType 申请类型
+	FactApplicationType = "application_type"
+	// FactBkBizIDs 申请的业务ID列表
+	FactBkBizIDs = "bk_biz_ids"
+	// FactRegion 地域
+	FactRegion = "region"
+	// FactZone 可用区
+	FactZone = "zone"
+	// FactInstanceType 机型
+	FactInstanceType = "instance_type"
+	// FactInstanceFamily 机型族
+	FactInstanceFamily = "instance_family"
+	// FactCpu 单台主机的CPU核数
+	FactCpu = "cpu"
+	// FactMemory 单台主机的内存，单位MB
+	FactMemory = "memory"
+	// FactCount 申请的资源数量
+	FactCount = "count"
+	// FactPublicIP 是否分配公网IP
+	FactPublicIP = "public_ip"
+	// FactDiskSizeGB 单个资源的硬盘总大小，单位GB
+	FactDiskSizeGB = "disk_size_gb"
+	// FactTags 申请单的标签
+	FactTags = "tags"
+)
+
+// Get 获取事实数据，支持使用tags.{key}获取标签值
+func (f PolicyFacts) Get(field string) (interface{}, bool) {
+	if key, ok := strings.CutPrefix(field, FactTags+"."); ok {
+		tags, ok := f[FactTags].(map[string]string)
+		if !ok {
+			return nil, false
+		}
+		val, exists := tags[key]
+		return val, exists
+	}
+
+	val, exists := f[field]
+	return val, exists
+}
+
+// PolicyEvaluation 申请单的策略评估结果，记录在申请单内容中
+type PolicyEvaluation struct {
+	Result      enumor.PolicyEvaluationResult `json:"result"`
+	Facts       PolicyFacts                   `json:"facts"`
+	Policies    []PolicyEvaluationItem        `json:"policies"`
+	EvaluatedAt string                        `json:"evaluated_at"`
+}
+
+// Reasons 违反策略的原因
+func (e *PolicyEvaluation) Reasons() []string {
+	reasons := make([]string, 0)
+	for _, one := range e.Policies {
+		if one.Effect == enumor.RequirePolicyEffect && !one.Matched {
+			reasons = append(reasons, one.Reasons...)
+		}
+	}
+
+	return reasons
+}
+
+// PolicyEvaluationItem 单个策略的评估结果
+type PolicyEvaluationItem struct {
+	PolicyID string                         `json:"policy_id"`
+	Name     string                         `json:"name"`
+	Effect   enumor.ApplicationPolicyEffect `json:"effect"`
+	// Matched 申请单是否满足策略的全部条件
+	Matched bool     `json:"matched"`
+	Reasons []string `json:"reasons,omitempty"`
+}
@@ -0,0 +1,93 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import "hcm/pkg/criteria/enumor"
+
+// Chain define audit hash chain.
+type Chain struct {
+	Chain      string `json:"chain"`
+	LastSeq    uint64 `json:"last_seq"`
+	LastHash   string `json:"last_hash"`
+	ExportPath string `json:"export_path"`
+	ExportedAt string `json:"exported_at"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// ChainVerifyResult define audit hash chain verify result.
+type ChainVerifyResult struct {
+	Chain string `json:"chain"`
+	// Verified is true if no issue is found in the chain.
+	Verified bool `json:"verified"`
+	// Count is the count of audits in the chain.
+	Count    uint64 `json:"count"`
+	LastSeq  uint64 `json:"last_seq"`
+	LastHash string `json:"last_hash"`
+	// Issues is the issues found in the chain, at most ChainVerifyMaxIssues issues are returned.
+	Issues []ChainIssue `json:"issues"`
+}
+
+// ChainDayVerifyResult define the verify result of all the audit hash chains of a day.
+type ChainDayVerifyResult struct {
+	Day string `json:"day"`
+	// Verified is true if no issue is found in all the chains.
+	Verified bool `json:"verified"`
+	// Count is the count of audits in all the chains.
+	Count  uint64              `json:"count"`
+	Chains []ChainVerifyResult `json:"chains"`
+}
+
+// ChainVerifyMaxIssues is the max count of issues returned by chain verification.
+const ChainVerifyMaxIssues = 100
+
+// ChainIssue define the issue found in audit hash chain.
+type ChainIssue struct {
+	Type enumor.AuditChainIssueType `json:"type"`
+	// Seq is the sequence where the issue is found.
+	Seq uint64 `json:"seq"`
+	// AuditID is the id of the audit where the issue is found, zero if the audit is missing.
+	AuditID uint64 `json:"audit_id"`
+	Message string `json:"message"`
+}
+
+// ChainExportResult define audit hash chain export result.
+type ChainExportResult struct {
+	SegmentPath  string `json:"segment_path"`
+	ManifestPath string `json:"manifest_path"`
+	Count        uint64 `json:"count"`
+	Verified     bool   `json:"verified"`
+	Signature    string `json:"signature"`
+}
+
+// ChainManifest is the manifest of an exported audit chain segment, Signature is the hex encoded HMAC-SHA256 of
+// the manifest json without signature.
+type ChainManifest struct {
+	Chain         string `json:"chain"`
+	Count         uint64 `json:"count"`
+	FirstSeq      uint64 `json:"first_seq"`
+	LastSeq       uint64 `json:"last_seq"`
+	LastHash      string `json:"last_hash"`
+	Verified      bool   `json:"verified"`
+	SegmentPath   string `json:"segment_path"`
+	SegmentSha256 string `json:"segment_sha256"`
+	ExportedAt    string `json:"exported_at"`
+	Signature     string `json:"signature,omitempty"`
+}
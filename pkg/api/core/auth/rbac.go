@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package coreauth defines local rbac auth core types.
+package coreauth
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/iam/meta"
+)
+
+// Role define local rbac auth role.
+type Role struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	Memo           *string `json:"memo"`
+	*core.Revision `json:",inline"`
+}
+
+// RolePolicy define resource scoped permission of the local rbac auth role.
+type RolePolicy struct {
+	ID      string                 `json:"id"`
+	RoleID  string                 `json:"role_id"`
+	ResType meta.ResourceType      `json:"res_type"`
+	Action  meta.Action            `json:"action"`
+	Scope   enumor.AuthPolicyScope `json:"scope"`
+	// ScopeID scope为biz时为业务ID，scope为resource时为鉴权资源ID，scope为any时为空
+	ScopeID   string `json:"scope_id"`
+	Creator   string `json:"creator"`
+	CreatedAt string `json:"created_at"`
+}
+
+// RoleBinding define the binding between user and local rbac auth role.
+type RoleBinding struct {
+	ID        string `json:"id"`
+	RoleID    string `json:"role_id"`
+	User      string `json:"user"`
+	Creator   string `json:"creator"`
+	CreatedAt string `json:"created_at"`
+}
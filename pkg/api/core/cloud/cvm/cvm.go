@@ -42,7 +42,7 @@ type BaseCvm struct {
 	SubnetIDs      []string `json:"subnet_ids"`
 
 	CloudImageID string `json:"cloud_image_id"`
-	// ImageID 预留字段，因为目前 hcm 还没有支持镜像资源。
+	// ImageID 主机使用的公共镜像在hcm中的ID，使用私有镜像的主机为空，可通过 cloud_image_id 关联私有镜像
 	ImageID string  `json:"image_id,omitempty"`
 	OsName  string  `json:"os_name"`
 	Memo    *string `json:"memo"`
@@ -58,8 +58,10 @@ type BaseCvm struct {
 		aws: pending | running | shutting-down | terminated | stopping | stopped
 		azure：PowerState/running｜PowerState/stopped｜PowerState/deallocating｜PowerState/deallocated
 	*/
-	Status        string `json:"status"`
-	RecycleStatus string `json:"recycle_status,omitempty"`
+	Status string `json:"status"`
+	// LifecycleStatus 由 Status 归一化得到的生命周期状态，在资源同步时计算
+	LifecycleStatus enumor.CvmLifecycleStatus `json:"lifecycle_status"`
+	RecycleStatus   string                    `json:"recycle_status,omitempty"`
 
 	// PrivateIPv4Addresses 内网IP
 	PrivateIPv4Addresses []string `json:"private_ipv4_addresses"`
@@ -0,0 +1,100 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cvm
+
+import "hcm/pkg/criteria/enumor"
+
+// VendorLifecycleStatusMap 各云厂商主机状态到归一化生命周期状态的映射
+var VendorLifecycleStatusMap = map[enumor.Vendor]map[string]enumor.CvmLifecycleStatus{
+	enumor.TCloud: tcloudLifecycleStatusMap,
+	enumor.Aws:    awsLifecycleStatusMap,
+	enumor.Gcp:    gcpLifecycleStatusMap,
+	enumor.Azure:  azureLifecycleStatusMap,
+	enumor.HuaWei: huaweiLifecycleStatusMap,
+}
+
+var tcloudLifecycleStatusMap = map[string]enumor.CvmLifecycleStatus{
+	"PENDING":       enumor.CvmLifecyclePending,
+	"LAUNCH_FAILED": enumor.CvmLifecycleError,
+	"RUNNING":       enumor.CvmLifecycleRunning,
+	"STOPPED":       enumor.CvmLifecycleStopped,
+	"STARTING":      enumor.CvmLifecyclePending,
+	"STOPPING":      enumor.CvmLifecycleStopping,
+	"REBOOTING":     enumor.CvmLifecycleRebooting,
+	"SHUTDOWN":      enumor.CvmLifecycleStopped,
+	"TERMINATING":   enumor.CvmLifecycleTerminating,
+}
+
+var awsLifecycleStatusMap = map[string]enumor.CvmLifecycleStatus{
+	"pending":       enumor.CvmLifecyclePending,
+	"running":       enumor.CvmLifecycleRunning,
+	"shutting-down": enumor.CvmLifecycleTerminating,
+	"terminated":    enumor.CvmLifecycleTerminated,
+	"stopping":      enumor.CvmLifecycleStopping,
+	"stopped":       enumor.CvmLifecycleStopped,
+}
+
+// gcpLifecycleStatusMap gcp 的 TERMINATED 表示实例已停止，可以再次启动
+var gcpLifecycleStatusMap = map[string]enumor.CvmLifecycleStatus{
+	"PROVISIONING": enumor.CvmLifecyclePending,
+	"STAGING":      enumor.CvmLifecyclePending,
+	"RUNNING":      enumor.CvmLifecycleRunning,
+	"STOPPING":     enumor.CvmLifecycleStopping,
+	"SUSPENDING":   enumor.CvmLifecycleStopping,
+	"SUSPENDED":    enumor.CvmLifecycleStopped,
+	"REPAIRING":    enumor.CvmLifecycleError,
+	"TERMINATED":   enumor.CvmLifecycleStopped,
+}
+
+var azureLifecycleStatusMap = map[string]enumor.CvmLifecycleStatus{
+	"PowerState/starting":     enumor.CvmLifecyclePending,
+	"PowerState/running":      enumor.CvmLifecycleRunning,
+	"PowerState/stopping":     enumor.CvmLifecycleStopping,
+	"PowerState/stopped":      enumor.CvmLifecycleStopped,
+	"PowerState/deallocating": enumor.CvmLifecycleStopping,
+	"PowerState/deallocated":  enumor.CvmLifecycleStopped,
+}
+
+var huaweiLifecycleStatusMap = map[string]enumor.CvmLifecycleStatus{
+	"BUILD":             enumor.CvmLifecyclePending,
+	"REBOOT":            enumor.CvmLifecycleRebooting,
+	"HARD_REBOOT":       enumor.CvmLifecycleRebooting,
+	"REBUILD":           enumor.CvmLifecyclePending,
+	"MIGRATING":         enumor.CvmLifecyclePending,
+	"RESIZE":            enumor.CvmLifecyclePending,
+	"ACTIVE":            enumor.CvmLifecycleRunning,
+	"SHUTOFF":           enumor.CvmLifecycleStopped,
+	"REVERT_RESIZE":     enumor.CvmLifecyclePending,
+	"VERIFY_RESIZE":     enumor.CvmLifecyclePending,
+	"ERROR":             enumor.CvmLifecycleError,
+	"DELETED":           enumor.CvmLifecycleTerminated,
+	"SHELVED":           enumor.CvmLifecycleStopped,
+	"SHELVED_OFFLOADED": enumor.CvmLifecycleStopped,
+}
+
+// NormalizeLifecycleStatus 将云厂商的主机状态转换为归一化的生命周期状态，无法识别的状态返回 unknown
+func NormalizeLifecycleStatus(vendor enumor.Vendor, status string) enumor.CvmLifecycleStatus {
+	lifecycle, exists := VendorLifecycleStatusMap[vendor][status]
+	if !exists {
+		return enumor.CvmLifecycleUnknown
+	}
+
+	return lifecycle
+}
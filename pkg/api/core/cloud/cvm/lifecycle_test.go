@@ -0,0 +1,122 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cvm
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestNormalizeLifecycleStatus(t *testing.T) {
+	cases := []struct {
+		vendor enumor.Vendor
+		status string
+		expect enumor.CvmLifecycleStatus
+	}{
+		{vendor: enumor.TCloud, status: "RUNNING", expect: enumor.CvmLifecycleRunning},
+		{vendor: enumor.TCloud, status: "STOPPED", expect: enumor.CvmLifecycleStopped},
+		{vendor: enumor.TCloud, status: "SHUTDOWN", expect: enumor.CvmLifecycleStopped},
+		{vendor: enumor.TCloud, status: "STARTING", expect: enumor.CvmLifecyclePending},
+		{vendor: enumor.TCloud, status: "LAUNCH_FAILED", expect: enumor.CvmLifecycleError},
+		{vendor: enumor.TCloud, status: "TERMINATING", expect: enumor.CvmLifecycleTerminating},
+		{vendor: enumor.Aws, status: "running", expect: enumor.CvmLifecycleRunning},
+		{vendor: enumor.Aws, status: "stopped", expect: enumor.CvmLifecycleStopped},
+		{vendor: enumor.Aws, status: "shutting-down", expect: enumor.CvmLifecycleTerminating},
+		{vendor: enumor.Aws, status: "terminated", expect: enumor.CvmLifecycleTerminated},
+		{vendor: enumor.Gcp, status: "RUNNING", expect: enumor.CvmLifecycleRunning},
+		{vendor: enumor.Gcp, status: "TERMINATED", expect: enumor.CvmLifecycleStopped},
+		{vendor: enumor.Gcp, status: "REPAIRING", expect: enumor.CvmLifecycleError},
+		{vendor: enumor.Azure, status: "PowerState/running", expect: enumor.CvmLifecycleRunning},
+		{vendor: enumor.Azure, status: "PowerState/deallocated", expect: enumor.CvmLifecycleStopped},
+		{vendor: enumor.Azure, status: "PowerState/deallocating", expect: enumor.CvmLifecycleStopping},
+		{vendor: enumor.HuaWei, status: "ACTIVE", expect: enumor.CvmLifecycleRunning},
+		{vendor: enumor.HuaWei, status: "SHUTOFF", expect: enumor.CvmLifecycleStopped},
+		{vendor: enumor.HuaWei, status: "HARD_REBOOT", expect: enumor.CvmLifecycleRebooting},
+		{vendor: enumor.HuaWei, status: "DELETED", expect: enumor.CvmLifecycleTerminated},
+		// 状态大小写敏感，且不同厂商的状态不能混用
+		{vendor: enumor.TCloud, status: "running", expect: enumor.CvmLifecycleUnknown},
+		{vendor: enumor.Aws, status: "RUNNING", expect: enumor.CvmLifecycleUnknown},
+		{vendor: enumor.HuaWei, status: "RUNNING", expect: enumor.CvmLifecycleUnknown},
+		{vendor: enumor.Azure, status: "running", expect: enumor.CvmLifecycleUnknown},
+		{vendor: enumor.TCloud, status: "", expect: enumor.CvmLifecycleUnknown},
+		{vendor: enumor.Zenlayer, status: "RUNNING", expect: enumor.CvmLifecycleUnknown},
+	}
+
+	for _, c := range cases {
+		got := NormalizeLifecycleStatus(c.vendor, c.status)
+		if got != c.expect {
+			t.Errorf("normalize %s status %q expect %s, got: %s", c.vendor, c.status, c.expect, got)
+		}
+	}
+}
+
+// TestLifecycleStatusReverseMapping 校验生命周期状态到各厂商状态的反向映射
+func TestLifecycleStatusReverseMapping(t *testing.T) {
+	reverse := make(map[enumor.CvmLifecycleStatus]map[enumor.Vendor][]string)
+	for vendor, statusMap := range VendorLifecycleStatusMap {
+		for status, lifecycle := range statusMap {
+			if err := lifecycle.Validate(); err != nil {
+				t.Errorf("%s status %s mapped to invalid lifecycle status, err: %v", vendor, status, err)
+			}
+			if lifecycle == enumor.CvmLifecycleUnknown {
+				t.Errorf("%s status %s should not be mapped to unknown explicitly", vendor, status)
+			}
+
+			if _, exists := reverse[lifecycle]; !exists {
+				reverse[lifecycle] = make(map[enumor.Vendor][]string)
+			}
+			reverse[lifecycle][vendor] = append(reverse[lifecycle][vendor], status)
+		}
+	}
+
+	// 开关机、重启等操作依赖 running 和 stopped，每个厂商都必须能映射出这两个状态
+	for _, lifecycle := range []enumor.CvmLifecycleStatus{enumor.CvmLifecycleRunning, enumor.CvmLifecycleStopped} {
+		for vendor := range VendorLifecycleStatusMap {
+			if len(reverse[lifecycle][vendor]) == 0 {
+				t.Errorf("%s has no status mapped to lifecycle status %s", vendor, lifecycle)
+			}
+		}
+	}
+
+	// 除 unknown 外的每个生命周期状态至少由一个厂商状态映射得到
+	all := []enumor.CvmLifecycleStatus{enumor.CvmLifecyclePending, enumor.CvmLifecycleRunning,
+		enumor.CvmLifecycleStopping, enumor.CvmLifecycleStopped, enumor.CvmLifecycleRebooting,
+		enumor.CvmLifecycleTerminating, enumor.CvmLifecycleTerminated, enumor.CvmLifecycleError}
+	for _, lifecycle := range all {
+		if len(reverse[lifecycle]) == 0 {
+			t.Errorf("lifecycle status %s is not mapped from any vendor status", lifecycle)
+		}
+	}
+
+	expectRunning := map[enumor.Vendor][]string{
+		enumor.TCloud: {"RUNNING"},
+		enumor.Aws:    {"running"},
+		enumor.Gcp:    {"RUNNING"},
+		enumor.Azure:  {"PowerState/running"},
+		enumor.HuaWei: {"ACTIVE"},
+	}
+	for vendor, expect := range expectRunning {
+		got := reverse[enumor.CvmLifecycleRunning][vendor]
+		if len(got) != len(expect) || got[0] != expect[0] {
+			t.Errorf("%s running status expect %v, got: %v", vendor, expect, got)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package coredisk
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+)
+
+// Snapshot 云硬盘快照
+type Snapshot struct {
+	ID        string        `json:"id"`
+	Vendor    enumor.Vendor `json:"vendor"`
+	AccountID string        `json:"account_id"`
+	BkBizID   int64         `json:"bk_biz_id"`
+	Region    string        `json:"region"`
+	CloudID   string        `json:"cloud_id"`
+	Name      string        `json:"name"`
+	// DiskID 源云硬盘ID，源云硬盘未同步到hcm时为空
+	DiskID      string `json:"disk_id"`
+	CloudDiskID string `json:"cloud_disk_id"`
+	// DiskSize 源云硬盘大小，单位GB
+	DiskSize uint64 `json:"disk_size"`
+	// Status 云上快照状态
+	Status string `json:"status"`
+	// PolicyID 创建快照的定期快照策略ID，手动创建的快照为空
+	PolicyID         string `json:"policy_id"`
+	CloudCreatedTime string `json:"cloud_created_time"`
+	Memo             string `json:"memo"`
+	core.Revision    `json:",inline"`
+}
+
+// GetID ...
+func (s Snapshot) GetID() string {
+	return s.ID
+}
+
+// GetCloudID ...
+func (s Snapshot) GetCloudID() string {
+	return s.CloudID
+}
+
+// SnapshotPolicy 定期快照策略
+type SnapshotPolicy struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	BkBizID int64  `json:"bk_biz_id"`
+	// Cron 执行周期，标准的5段cron表达式(分 时 日 月 周)
+	Cron string `json:"cron"`
+	// RetentionCount 每块云硬盘保留的策略快照个数
+	RetentionCount uint64   `json:"retention_count"`
+	DiskIDs        []string `json:"disk_ids"`
+	Enabled        bool     `json:"enabled"`
+	LastExecutedAt string   `json:"last_executed_at"`
+	Memo           string   `json:"memo"`
+	core.Revision  `json:",inline"`
+}
+
+// SnapshotVendors 支持云硬盘快照管理的云厂商
+var SnapshotVendors = []enumor.Vendor{enumor.TCloud, enumor.Aws, enumor.HuaWei, enumor.Azure, enumor.Gcp}
+
+// SnapshotRollbackVendors 支持回滚云硬盘快照的云厂商，Azure和Gcp云上不提供快照回滚能力
+var SnapshotRollbackVendors = []enumor.Vendor{enumor.TCloud, enumor.Aws, enumor.HuaWei}
+
+// vendorSnapshotStatusMap 各云厂商快照状态到归一化快照状态的映射
+var vendorSnapshotStatusMap = map[enumor.Vendor]map[string]enumor.DiskSnapshotStatus{
+	// reference: https://cloud.tencent.com/document/api/362/15669#Snapshot
+	enumor.TCloud: {
+		"CREATING":            enumor.DiskSnapshotCreating,
+		"COPYING_FROM_REMOTE": enumor.DiskSnapshotCreating,
+		"CHECKING_COPIED":     enumor.DiskSnapshotCreating,
+		"NORMAL":              enumor.DiskSnapshotNormal,
+		"ROLLBACKING":         enumor.DiskSnapshotNormal,
+	},
+	// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_Snapshot.html
+	enumor.Aws: {
+		"pending":   enumor.DiskSnapshotCreating,
+		"completed": enumor.DiskSnapshotNormal,
+		"error":     enumor.DiskSnapshotFailed,
+	},
+	// reference: https://support.huaweicloud.com/api-evs/evs_04_2048.html
+	enumor.HuaWei: {
+		"creating":    enumor.DiskSnapshotCreating,
+		"available":   enumor.DiskSnapshotNormal,
+		"rollbacking": enumor.DiskSnapshotNormal,
+		"backing-up":  enumor.DiskSnapshotNormal,
+		"error":       enumor.DiskSnapshotFailed,
+	},
+	// azure 快照使用资源的部署状态
+	enumor.Azure: {
+		"Creating":  enumor.DiskSnapshotCreating,
+		"Succeeded": enumor.DiskSnapshotNormal,
+		"Failed":    enumor.DiskSnapshotFailed,
+	},
+	// reference: https://cloud.google.com/compute/docs/reference/rest/v1/snapshots
+	enumor.Gcp: {
+		"CREATING":  enumor.DiskSnapshotCreating,
+		"UPLOADING": enumor.DiskSnapshotCreating,
+		"READY":     enumor.DiskSnapshotNormal,
+		"FAILED":    enumor.DiskSnapshotFailed,
+	},
+}
+
+// NormalizeSnapshotStatus 将云厂商的快照状态转换为归一化的快照状态，无法识别的状态返回 unknown
+func NormalizeSnapshotStatus(vendor enumor.Vendor, status string) enumor.DiskSnapshotStatus {
+	normalized, exists := vendorSnapshotStatusMap[vendor][status]
+	if !exists {
+		return enumor.DiskSnapshotUnknown
+	}
+
+	return normalized
+}
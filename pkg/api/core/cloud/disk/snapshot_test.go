@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package coredisk
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestNormalizeSnapshotStatus(t *testing.T) {
+	cases := []struct {
+		vendor enumor.Vendor
+		status string
+		expect enumor.DiskSnapshotStatus
+	}{
+		{vendor: enumor.TCloud, status: "CREATING", expect: enumor.DiskSnapshotCreating},
+		{vendor: enumor.TCloud, status: "NORMAL", expect: enumor.DiskSnapshotNormal},
+		{vendor: enumor.Aws, status: "pending", expect: enumor.DiskSnapshotCreating},
+		{vendor: enumor.Aws, status: "completed", expect: enumor.DiskSnapshotNormal},
+		{vendor: enumor.Aws, status: "error", expect: enumor.DiskSnapshotFailed},
+		{vendor: enumor.HuaWei, status: "available", expect: enumor.DiskSnapshotNormal},
+		{vendor: enumor.HuaWei, status: "error", expect: enumor.DiskSnapshotFailed},
+		{vendor: enumor.Azure, status: "Succeeded", expect: enumor.DiskSnapshotNormal},
+		{vendor: enumor.Azure, status: "Failed", expect: enumor.DiskSnapshotFailed},
+		{vendor: enumor.Gcp, status: "UPLOADING", expect: enumor.DiskSnapshotCreating},
+		{vendor: enumor.Gcp, status: "READY", expect: enumor.DiskSnapshotNormal},
+		// 状态大小写敏感，且不同厂商的状态不能混用
+		{vendor: enumor.TCloud, status: "normal", expect: enumor.DiskSnapshotUnknown},
+		{vendor: enumor.Gcp, status: "completed", expect: enumor.DiskSnapshotUnknown},
+		{vendor: enumor.Aws, status: "", expect: enumor.DiskSnapshotUnknown},
+	}
+
+	for _, c := range cases {
+		got := NormalizeSnapshotStatus(c.vendor, c.status)
+		if got != c.expect {
+			t.Errorf("normalize %s snapshot status %q expect %s, got: %s", c.vendor, c.status, c.expect, got)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package coreimage
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+)
+
+// PrivateImage 私有镜像，通过hcm基于主机创建或跨地域复制的自定义镜像
+type PrivateImage struct {
+	ID        string        `json:"id"`
+	Vendor    enumor.Vendor `json:"vendor"`
+	AccountID string        `json:"account_id"`
+	BkBizID   int64         `json:"bk_biz_id"`
+	Region    string        `json:"region"`
+	CloudID   string        `json:"cloud_id"`
+	Name      string        `json:"name"`
+	// SourceCvmID 创建镜像的源主机ID
+	SourceCvmID      string `json:"source_cvm_id"`
+	SourceCloudCvmID string `json:"source_cloud_cvm_id"`
+	// SourceImageID 跨地域复制的源镜像ID，非复制的镜像为空
+	SourceImageID string `json:"source_image_id"`
+	// SharedAccountIDs 已共享的账号ID
+	SharedAccountIDs []string `json:"shared_account_ids"`
+	// Status 镜像状态，由定时同步根据云上镜像状态更新
+	Status enumor.PrivateImageStatus `json:"status"`
+	// Deprecated 是否已弃用，弃用的镜像不能再用于创建主机
+	Deprecated    bool   `json:"deprecated"`
+	Memo          string `json:"memo"`
+	core.Revision `json:",inline"`
+}
+
+// PrivateImageVendors 支持私有镜像管理的云厂商
+var PrivateImageVendors = []enumor.Vendor{enumor.TCloud, enumor.HuaWei, enumor.Aws, enumor.Gcp, enumor.Azure}
+
+// PrivateImageCopyVendors 支持跨地域复制私有镜像的云厂商，gcp镜像为全局资源可直接在各地域使用，
+// azure托管镜像不支持跨地域复制
+var PrivateImageCopyVendors = []enumor.Vendor{enumor.TCloud, enumor.HuaWei, enumor.Aws}
+
+// PrivateImageShareVendors 支持共享私有镜像的云厂商，azure托管镜像需通过订阅间的角色授权使用，不支持共享
+var PrivateImageShareVendors = []enumor.Vendor{enumor.TCloud, enumor.HuaWei, enumor.Aws, enumor.Gcp}
+
+// vendorPrivateImageStatusMap 各云厂商镜像状态到归一化私有镜像状态的映射
+var vendorPrivateImageStatusMap = map[enumor.Vendor]map[string]enumor.PrivateImageStatus{
+	// reference: https://cloud.tencent.com/document/api/213/15753#Image
+	enumor.TCloud: {
+		"CREATING":     enumor.PrivateImageCreating,
+		"SYNCING":      enumor.PrivateImageCreating,
+		"IMPORTING":    enumor.PrivateImageCreating,
+		"NORMAL":       enumor.PrivateImageNormal,
+		"USING":        enumor.PrivateImageNormal,
+		"CREATEFAILED": enumor.PrivateImageFailed,
+		"IMPORTFAILED": enumor.PrivateImageFailed,
+	},
+	// reference: https://support.huaweicloud.com/api-ims/ims_03_0702.html
+	enumor.HuaWei: {
+		"queued": enumor.PrivateImageCreating,
+		"saving": enumor.PrivateImageCreating,
+		"active": enumor.PrivateImageNormal,
+		"killed": enumor.PrivateImageFailed,
+	},
+	// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_Image.html
+	enumor.Aws: {
+		"pending":      enumor.PrivateImageCreating,
+		"transient":    enumor.PrivateImageCreating,
+		"available":    enumor.PrivateImageNormal,
+		"failed":       enumor.PrivateImageFailed,
+		"invalid":      enumor.PrivateImageFailed,
+		"error":        enumor.PrivateImageFailed,
+		"deregistered": enumor.PrivateImageFailed,
+	},
+	// reference: https://cloud.google.com/compute/docs/reference/rest/v1/images
+	enumor.Gcp: {
+		"PENDING": enumor.PrivateImageCreating,
+		"READY":   enumor.PrivateImageNormal,
+		"FAILED":  enumor.PrivateImageFailed,
+	},
+	// reference: https://learn.microsoft.com/en-us/rest/api/compute/images/get?tabs=HTTP#image
+	enumor.Azure: {
+		"Creating":  enumor.PrivateImageCreating,
+		"Updating":  enumor.PrivateImageCreating,
+		"Succeeded": enumor.PrivateImageNormal,
+		"Failed":    enumor.PrivateImageFailed,
+	},
+}
+
+// NormalizePrivateImageStatus 将云厂商的镜像状态转换为归一化的私有镜像状态，无法识别的状态返回 unknown
+func NormalizePrivateImageStatus(vendor enumor.Vendor, state string) enumor.PrivateImageStatus {
+	status, exists := vendorPrivateImageStatusMap[vendor][state]
+	if !exists {
+		return enumor.PrivateImageUnknown
+	}
+
+	return status
+}
+
+// PrivateImageType 私有镜像转换为镜像基础信息时的镜像类型
+const PrivateImageType = "private"
@@ -0,0 +1,69 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package coreimage
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestNormalizePrivateImageStatus(t *testing.T) {
+	cases := []struct {
+		vendor enumor.Vendor
+		state  string
+		expect enumor.PrivateImageStatus
+	}{
+		{vendor: enumor.TCloud, state: "CREATING", expect: enumor.PrivateImageCreating},
+		{vendor: enumor.TCloud, state: "SYNCING", expect: enumor.PrivateImageCreating},
+		{vendor: enumor.TCloud, state: "NORMAL", expect: enumor.PrivateImageNormal},
+		{vendor: enumor.TCloud, state: "USING", expect: enumor.PrivateImageNormal},
+		{vendor: enumor.TCloud, state: "CREATEFAILED", expect: enumor.PrivateImageFailed},
+		{vendor: enumor.TCloud, state: "IMPORTFAILED", expect: enumor.PrivateImageFailed},
+		{vendor: enumor.HuaWei, state: "queued", expect: enumor.PrivateImageCreating},
+		{vendor: enumor.HuaWei, state: "saving", expect: enumor.PrivateImageCreating},
+		{vendor: enumor.HuaWei, state: "active", expect: enumor.PrivateImageNormal},
+		{vendor: enumor.HuaWei, state: "killed", expect: enumor.PrivateImageFailed},
+		{vendor: enumor.Aws, state: "pending", expect: enumor.PrivateImageCreating},
+		{vendor: enumor.Aws, state: "available", expect: enumor.PrivateImageNormal},
+		{vendor: enumor.Aws, state: "failed", expect: enumor.PrivateImageFailed},
+		{vendor: enumor.Gcp, state: "PENDING", expect: enumor.PrivateImageCreating},
+		{vendor: enumor.Gcp, state: "READY", expect: enumor.PrivateImageNormal},
+		{vendor: enumor.Gcp, state: "FAILED", expect: enumor.PrivateImageFailed},
+		{vendor: enumor.Azure, state: "Creating", expect: enumor.PrivateImageCreating},
+		{vendor: enumor.Azure, state: "Succeeded", expect: enumor.PrivateImageNormal},
+		{vendor: enumor.Azure, state: "Failed", expect: enumor.PrivateImageFailed},
+		// 状态大小写敏感，且不同厂商的状态不能混用
+		{vendor: enumor.TCloud, state: "normal", expect: enumor.PrivateImageUnknown},
+		{vendor: enumor.HuaWei, state: "NORMAL", expect: enumor.PrivateImageUnknown},
+		{vendor: enumor.HuaWei, state: "deleted", expect: enumor.PrivateImageUnknown},
+		{vendor: enumor.TCloud, state: "", expect: enumor.PrivateImageUnknown},
+		{vendor: enumor.Aws, state: "AVAILABLE", expect: enumor.PrivateImageUnknown},
+		{vendor: enumor.Gcp, state: "available", expect: enumor.PrivateImageUnknown},
+		{vendor: enumor.Azure, state: "READY", expect: enumor.PrivateImageUnknown},
+	}
+
+	for _, c := range cases {
+		got := NormalizePrivateImageStatus(c.vendor, c.state)
+		if got != c.expect {
+			t.Errorf("normalize %s image state %q expect %s, got: %s", c.vendor, c.state, c.expect, got)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+)
+
+// IpamPool define ipam cidr pool, the vpcs of the biz in the vendor region should be allocated from the pools.
+type IpamPool struct {
+	ID             string        `json:"id"`
+	Name           string        `json:"name"`
+	Vendor         enumor.Vendor `json:"vendor"`
+	Region         string        `json:"region"`
+	BkBizID        int64         `json:"bk_biz_id"`
+	Cidr           string        `json:"cidr"`
+	Memo           *string       `json:"memo"`
+	*core.Revision `json:",inline"`
+}
+
+// IpamAllocation define cidr allocated or reserved from ipam cidr pool.
+type IpamAllocation struct {
+	ID      string                    `json:"id"`
+	PoolID  string                    `json:"pool_id"`
+	BkBizID int64                     `json:"bk_biz_id"`
+	Cidr    string                    `json:"cidr"`
+	Type    enumor.IpamAllocationType `json:"type"`
+	// ResType、ResID 使用该网段的资源，分配后尚未创建资源时为空
+	ResType        enumor.CloudResourceType `json:"res_type"`
+	ResID          string                   `json:"res_id"`
+	Memo           *string                  `json:"memo"`
+	*core.Revision `json:",inline"`
+}
+
+// IpamCidrConflict define the occupied cidr which overlaps with the checked cidr.
+type IpamCidrConflict struct {
+	Usage   enumor.IpamCidrUsage `json:"usage"`
+	Vendor  enumor.Vendor        `json:"vendor,omitempty"`
+	BkBizID int64                `json:"bk_biz_id"`
+	// ResID vpc、子网或ipam分配记录的ID
+	ResID   string `json:"res_id"`
+	CloudID string `json:"cloud_id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Cidr    string `json:"cidr"`
+}
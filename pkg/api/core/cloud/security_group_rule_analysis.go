@@ -0,0 +1,65 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"hcm/pkg/criteria/enumor"
+)
+
+// NormalizedSGRule define vendor neutral security group rule, used to analyze rules across vendors.
+type NormalizedSGRule struct {
+	// RuleID hcm中规则的ID，gcp为防火墙规则ID
+	RuleID string `json:"rule_id"`
+	// CloudRuleID 云上规则ID，tcloud规则没有云上ID，为空
+	CloudRuleID     string                         `json:"cloud_rule_id"`
+	SecurityGroupID string                         `json:"security_group_id"`
+	Vendor          enumor.Vendor                  `json:"vendor"`
+	Type            enumor.SecurityGroupRuleType   `json:"type"`
+	Action          enumor.SecurityGroupRuleAction `json:"action"`
+	// Protocol 协议，统一为小写，all 表示所有协议
+	Protocol string `json:"protocol"`
+	// Ports 端口范围，为空表示所有端口
+	Ports []PortRange `json:"ports"`
+	// Addresses 对端地址，ingress为源地址，egress为目的地址，为CIDR格式，* 表示任意地址
+	Addresses []string `json:"addresses"`
+	// References 无法解析成CIDR的对端引用，比如安全组、地址模版、服务标签等
+	References []string `json:"references,omitempty"`
+	// Targets 规则只作用于匹配的实例，比如gcp防火墙规则的目标标记和目标服务账号，为空表示作用于所有实例
+	Targets []string `json:"targets,omitempty"`
+	// Priority 厂商原始优先级，值越小优先级越高
+	Priority int64   `json:"priority"`
+	Memo     *string `json:"memo,omitempty"`
+}
+
+// PortRange define port range, From and To are included.
+type PortRange struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// AnalyzedSGRule define security group rule with analysis result.
+type AnalyzedSGRule struct {
+	NormalizedSGRule `json:",inline"`
+	// Order 规则在实际生效链路中的匹配顺序，从0开始
+	Order  int                         `json:"order"`
+	Status enumor.SGRuleAnalysisStatus `json:"status"`
+	// CoveredBy 完全覆盖该规则的规则ID
+	CoveredBy string `json:"covered_by,omitempty"`
+}
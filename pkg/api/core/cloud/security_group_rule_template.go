@@ -0,0 +1,159 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// SGRuleTemplate define vendor neutral security group rule template.
+type SGRuleTemplate struct {
+	ID      string           `json:"id"`
+	Name    string           `json:"name"`
+	BkBizID int64            `json:"bk_biz_id"`
+	Rules   []SGTemplateRule `json:"rules"`
+	// Version 模版版本，每次修改规则后递增，用于检测已下发安全组的漂移
+	Version        uint64  `json:"version"`
+	Memo           *string `json:"memo"`
+	*core.Revision `json:",inline"`
+}
+
+// SGTemplateRule define vendor neutral security group rule, it will be compiled into each vendor's rule model.
+type SGTemplateRule struct {
+	Type   enumor.SecurityGroupRuleType   `json:"type" validate:"required"`
+	Action enumor.SecurityGroupRuleAction `json:"action" validate:"required"`
+	// Protocol 协议，支持 tcp、udp、icmp、all
+	Protocol string `json:"protocol" validate:"required"`
+	// Ports 端口，支持 "80"、"8000-9000" 格式，为空表示所有端口，只有tcp、udp协议可以指定端口
+	Ports []string `json:"ports" validate:"omitempty"`
+	// Addresses 对端地址，ingress为源地址，egress为目的地址，支持IPv4、IPv6的IP或CIDR
+	Addresses []string `json:"addresses" validate:"required,min=1"`
+	Memo      *string  `json:"memo" validate:"omitempty"`
+}
+
+// Validate SGTemplateRule.
+func (r SGTemplateRule) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+
+	if r.Type != enumor.Ingress && r.Type != enumor.Egress {
+		return fmt.Errorf("unsupported rule type: %s", r.Type)
+	}
+
+	if err := r.Action.Validate(); err != nil {
+		return err
+	}
+
+	switch r.Protocol {
+	case "tcp", "udp":
+	case "icmp", "all":
+		if len(r.Ports) != 0 {
+			return fmt.Errorf("protocol %s can not specify ports", r.Protocol)
+		}
+	default:
+		return fmt.Errorf("unsupported protocol: %s", r.Protocol)
+	}
+
+	for _, port := range r.Ports {
+		if _, _, err := ParseSGTemplatePort(port); err != nil {
+			return err
+		}
+	}
+
+	for _, addr := range r.Addresses {
+		if net.ParseIP(addr) == nil && !isCidr(addr) {
+			return fmt.Errorf("invalid address: %s, should be ip or cidr", addr)
+		}
+	}
+
+	return nil
+}
+
+// ParseSGTemplatePort parse template port like "80" or "8000-9000".
+func ParseSGTemplatePort(port string) (int64, int64, error) {
+	from, to, found := strings.Cut(port, "-")
+	if !found {
+		to = from
+	}
+
+	fromPort, err := strconv.ParseInt(strings.TrimSpace(from), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port: %s", port)
+	}
+
+	toPort, err := strconv.ParseInt(strings.TrimSpace(to), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port: %s", port)
+	}
+
+	if fromPort < 1 || toPort > 65535 || fromPort > toPort {
+		return 0, 0, fmt.Errorf("invalid port range: %s", port)
+	}
+
+	return fromPort, toPort, nil
+}
+
+func isCidr(addr string) bool {
+	_, _, err := net.ParseCIDR(addr)
+	return err == nil
+}
+
+// ValidateSGTemplateRules validate security group template rules.
+func ValidateSGTemplateRules(rules []SGTemplateRule) error {
+	if len(rules) == 0 {
+		return errors.New("rules is required")
+	}
+
+	for idx, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("rules[%d] is invalid, err: %v", idx, err)
+		}
+	}
+
+	return nil
+}
+
+// SGRuleTplApply define the apply record of security group rule template, one record for each target.
+type SGRuleTplApply struct {
+	ID         string `json:"id"`
+	TemplateID string `json:"template_id"`
+	// TemplateVersion 下发的模版版本
+	TemplateVersion uint64        `json:"template_version"`
+	Vendor          enumor.Vendor `json:"vendor"`
+	AccountID       string        `json:"account_id"`
+	BkBizID         int64         `json:"bk_biz_id"`
+	// ResType 下发的目标资源类型，gcp为vpc，其余厂商为安全组
+	ResType        enumor.CloudResourceType   `json:"res_type"`
+	ResID          string                     `json:"res_id"`
+	CloudResID     string                     `json:"cloud_res_id"`
+	State          enumor.SGRuleTplApplyState `json:"state"`
+	Reason         string                     `json:"reason"`
+	FlowID         string                     `json:"flow_id"`
+	*core.Revision `json:",inline"`
+}
@@ -0,0 +1,156 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/tools/slice"
+)
+
+// TagPolicy 标签策略，在账号资源同步后评估资源的标签是否合规
+type TagPolicy struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// BkBizID 策略生效的业务，0表示全局策略
+	BkBizID int64 `json:"bk_biz_id"`
+	// ResTypes 策略生效的资源类型，为空表示对所有支持标签的资源类型生效
+	ResTypes      []enumor.CloudResourceType `json:"res_types"`
+	Rules         TagPolicyRules             `json:"rules"`
+	AutoRemediate bool                       `json:"auto_remediate"`
+	Enabled       bool                       `json:"enabled"`
+	Memo          string                     `json:"memo"`
+	core.Revision `json:",inline"`
+}
+
+// TagPolicyResTypes 标签策略支持的资源类型，与同步标签的资源类型保持一致
+var TagPolicyResTypes = []enumor.CloudResourceType{enumor.CvmCloudResType, enumor.DiskCloudResType,
+	enumor.SecurityGroupCloudResType, enumor.EipCloudResType, enumor.VpcCloudResType, enumor.LoadBalancerCloudResType}
+
+// ValidateTagPolicyResTypes validate the resource types of tag policy.
+func ValidateTagPolicyResTypes(resTypes []enumor.CloudResourceType) error {
+	for _, resType := range resTypes {
+		if !slice.IsItemInSlice(TagPolicyResTypes, resType) {
+			return fmt.Errorf("tag policy not support res type: %s", resType)
+		}
+	}
+
+	return nil
+}
+
+// MatchResType 判断策略是否对资源类型生效
+func (p *TagPolicy) MatchResType(resType enumor.CloudResourceType) bool {
+	return len(p.ResTypes) == 0 || slice.IsItemInSlice(p.ResTypes, resType)
+}
+
+// TagPolicyRule 标签策略规则，资源必须包含该标签键，设置了允许值时标签值必须在允许值范围内
+type TagPolicyRule struct {
+	Key           string   `json:"key" validate:"required,max=255"`
+	AllowedValues []string `json:"allowed_values" validate:"omitempty,dive,max=255"`
+	// DefaultValue 自动补齐标签时使用的默认值，支持使用 {bk_biz_id}、{bk_biz_name}、{bk_module_name} 变量，
+	// 其中 {bk_module_name} 为主机在CMDB中所属的模块名称，仅对主机生效
+	DefaultValue string `json:"default_value" validate:"omitempty,max=255"`
+}
+
+// TagPolicyRules 标签策略规则列表
+type TagPolicyRules []TagPolicyRule
+
+// Validate TagPolicyRules.
+func (rs TagPolicyRules) Validate() error {
+	if len(rs) == 0 {
+		return errors.New("rules is required")
+	}
+
+	keys := make(map[string]struct{}, len(rs))
+	for _, rule := range rs {
+		if err := validator.Validate.Struct(rule); err != nil {
+			return err
+		}
+
+		if _, exists := keys[rule.Key]; exists {
+			return fmt.Errorf("tag key %s is duplicated", rule.Key)
+		}
+		keys[rule.Key] = struct{}{}
+
+		if len(rule.DefaultValue) != 0 && len(rule.AllowedValues) != 0 && !IsTagValueTemplate(rule.DefaultValue) &&
+			!slice.IsItemInSlice(rule.AllowedValues, rule.DefaultValue) {
+			return fmt.Errorf("default value of tag key %s is not in the allowed values", rule.Key)
+		}
+	}
+
+	return nil
+}
+
+// 标签默认值支持的变量
+const (
+	// TagValueVarBizID 资源所属业务ID
+	TagValueVarBizID = "{bk_biz_id}"
+	// TagValueVarBizName 资源所属业务名称
+	TagValueVarBizName = "{bk_biz_name}"
+	// TagValueVarModuleName 主机在CMDB中所属的模块名称
+	TagValueVarModuleName = "{bk_module_name}"
+)
+
+// IsTagValueTemplate 判断标签默认值是否包含变量
+func IsTagValueTemplate(value string) bool {
+	return strings.Contains(value, TagValueVarBizID) || strings.Contains(value, TagValueVarBizName) ||
+		strings.Contains(value, TagValueVarModuleName)
+}
+
+// TagViolation 资源违反的标签规则
+type TagViolation struct {
+	Key  string                  `json:"key"`
+	Type enumor.TagViolationType `json:"type"`
+	// Value 资源当前的标签值，缺少标签时为空
+	Value string `json:"value,omitempty"`
+}
+
+// TagComplianceFinding 标签不合规资源
+type TagComplianceFinding struct {
+	ID             string                   `json:"id"`
+	PolicyID       string                   `json:"policy_id"`
+	Vendor         enumor.Vendor            `json:"vendor"`
+	AccountID      string                   `json:"account_id"`
+	BkBizID        int64                    `json:"bk_biz_id"`
+	Region         string                   `json:"region"`
+	ResType        enumor.CloudResourceType `json:"res_type"`
+	ResID          string                   `json:"res_id"`
+	CloudResID     string                   `json:"cloud_res_id"`
+	ResName        string                   `json:"res_name"`
+	Violations     []TagViolation           `json:"violations"`
+	*core.Revision `json:",inline"`
+}
+
+// TagComplianceSummary 按账号、业务和资源类型统计的标签合规情况
+type TagComplianceSummary struct {
+	ID             string                   `json:"id"`
+	Vendor         enumor.Vendor            `json:"vendor"`
+	AccountID      string                   `json:"account_id"`
+	BkBizID        int64                    `json:"bk_biz_id"`
+	ResType        enumor.CloudResourceType `json:"res_type"`
+	TotalCount     uint64                   `json:"total_count"`
+	CompliantCount uint64                   `json:"compliant_count"`
+	*core.Revision `json:",inline"`
+}
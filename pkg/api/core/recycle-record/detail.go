@@ -100,3 +100,39 @@ type DiskRecycleOptions struct{}
 type DiskRelatedRecycleOpt struct {
 	CvmID string `json:"cvm_id"`
 }
+
+// EipRecycleDetail eip回收详情，记录回收前eip的绑定信息，用于恢复时重新绑定
+type EipRecycleDetail struct {
+	CvmID        string `json:"cvm_id,omitempty"`
+	NicID        string `json:"nic_id,omitempty"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// SecurityGroupRecycleDetail 安全组回收详情，记录回收前安全组关联的主机和负载均衡，用于恢复时重新关联
+type SecurityGroupRecycleDetail struct {
+	CvmIDs        []string       `json:"cvm_ids,omitempty"`
+	LoadBalancers []SGLbBindInfo `json:"load_balancers,omitempty"`
+	ErrorMessage  string         `json:"error_message,omitempty"`
+}
+
+// SGLbBindInfo 安全组与负载均衡的绑定信息
+type SGLbBindInfo struct {
+	LbID string `json:"lb_id"`
+	// Priority 安全组在负载均衡上的优先级
+	Priority int64 `json:"priority"`
+}
+
+// LoadBalancerRecycleDetail 负载均衡回收详情
+type LoadBalancerRecycleDetail struct {
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// VpcRecycleDetail vpc回收详情
+type VpcRecycleDetail struct {
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// SubnetRecycleDetail 子网回收详情
+type SubnetRecycleDetail struct {
+	ErrorMessage string `json:"error_message,omitempty"`
+}
@@ -51,5 +51,14 @@ type BaseRecycleRecord struct {
 	Region        string                     `json:"region"`
 	Status        enumor.RecycleRecordStatus `json:"status"`
 	RecycledAt    string                     `json:"recycled_at"`
+	SnapshotIDs   []string                   `json:"snapshot_ids,omitempty"`
+	Timeline      []TimelineEvent            `json:"timeline,omitempty"`
 	core.Revision `json:",inline"`
 }
+
+// TimelineEvent defines one event of recycle record timeline.
+type TimelineEvent struct {
+	Stage   enumor.RecycleTimelineStage `json:"stage"`
+	Time    string                      `json:"time"`
+	Message string                      `json:"message,omitempty"`
+}
@@ -38,6 +38,7 @@ type ApplicationCreateReq struct {
 	Content        string                   `json:"content" validate:"required"`
 	DeliveryDetail string                   `json:"delivery_detail" validate:"required"`
 	Memo           *string                  `json:"memo" validate:"omitempty"`
+	ApprovalDetail string                   `json:"approval_detail" validate:"omitempty"`
 }
 
 // Validate ...
@@ -49,6 +50,11 @@ func (req *ApplicationCreateReq) Validate() error {
 type ApplicationUpdateReq struct {
 	Status         enumor.ApplicationStatus `json:"status" validate:"required"`
 	DeliveryDetail *string                  `json:"delivery_detail" validate:"omitempty"`
+	ApprovalDetail *string                  `json:"approval_detail" validate:"omitempty"`
+	// ExpectedStatus 不为空时，仅当申请单当前状态与其一致时才更新，否则返回 errf.RecordNotUpdate
+	ExpectedStatus enumor.ApplicationStatus `json:"expected_status" validate:"omitempty"`
+	// ExpectedApprovalRevision 不为空时，仅当内置审批详情的版本与其一致时才更新，否则返回 errf.RecordNotUpdate
+	ExpectedApprovalRevision *int `json:"expected_approval_revision" validate:"omitempty"`
 }
 
 // Validate ...
@@ -68,6 +74,7 @@ type ApplicationResp struct {
 	Content        string                   `json:"content"`
 	DeliveryDetail string                   `json:"delivery_detail"`
 	Memo           *string                  `json:"memo"`
+	ApprovalDetail string                   `json:"approval_detail"`
 	core.Revision  `json:",inline"`
 }
 
@@ -0,0 +1,104 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package dataservice
+
+import (
+	"errors"
+
+	"hcm/pkg/api/core"
+	coreapplication "hcm/pkg/api/core/application"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ApplicationPolicyCreateReq ...
+type ApplicationPolicyCreateReq struct {
+	Name            string                           `json:"name" validate:"required,max=64"`
+	BkBizID         int64                            `json:"bk_biz_id" validate:"min=0"`
+	ApplicationType enumor.ApplicationType           `json:"application_type" validate:"omitempty"`
+	Effect          enumor.ApplicationPolicyEffect   `json:"effect" validate:"required"`
+	Conditions      coreapplication.PolicyConditions `json:"conditions" validate:"required"`
+	Message         string                           `json:"message" validate:"omitempty,max=255"`
+	Enabled         *bool                            `json:"enabled" validate:"required"`
+	Memo            string                           `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *ApplicationPolicyCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.ApplicationType) != 0 {
+		if err := req.ApplicationType.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := req.Effect.Validate(); err != nil {
+		return err
+	}
+
+	return req.Conditions.Validate()
+}
+
+// ApplicationPolicyUpdateReq ...
+type ApplicationPolicyUpdateReq struct {
+	Name            string                           `json:"name" validate:"omitempty,max=64"`
+	ApplicationType *enumor.ApplicationType          `json:"application_type" validate:"omitempty"`
+	Effect          enumor.ApplicationPolicyEffect   `json:"effect" validate:"omitempty"`
+	Conditions      coreapplication.PolicyConditions `json:"conditions" validate:"omitempty"`
+	Message         *string                          `json:"message" validate:"omitempty,max=255"`
+	Enabled         *bool                            `json:"enabled" validate:"omitempty"`
+	Memo            *string                          `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *ApplicationPolicyUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Name) == 0 && req.ApplicationType == nil && len(req.Effect) == 0 && len(req.Conditions) == 0 &&
+		req.Message == nil && req.Enabled == nil && req.Memo == nil {
+		return errors.New("at least one field should be updated")
+	}
+
+	if req.ApplicationType != nil && len(*req.ApplicationType) != 0 {
+		if err := req.ApplicationType.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(req.Effect) != 0 {
+		if err := req.Effect.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(req.Conditions) != 0 {
+		return req.Conditions.Validate()
+	}
+
+	return nil
+}
+
+// ApplicationPolicyListResult ...
+type ApplicationPolicyListResult = core.ListResultT[*coreapplication.ApplicationPolicy]
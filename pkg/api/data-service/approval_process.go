@@ -20,7 +20,10 @@
 package dataservice
 
 import (
+	"errors"
+
 	"hcm/pkg/api/core"
+	coreapplication "hcm/pkg/api/core/application"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/rest"
@@ -29,23 +32,72 @@ import (
 
 // ApprovalProcessCreateReq ...
 type ApprovalProcessCreateReq struct {
-	ApplicationType enumor.ApplicationType `json:"application_type" validate:"required"`
-	ServiceID       int64                  `json:"service_id" validate:"required,min=1"`
+	ApplicationType enumor.ApplicationType         `json:"application_type" validate:"required"`
+	ServiceID       int64                          `json:"service_id" validate:"omitempty,min=1"`
+	Managers        string                         `json:"managers" validate:"required,max=255"`
+	Engine          enumor.ApprovalEngine          `json:"engine" validate:"omitempty"`
+	Stages          coreapplication.ApprovalStages `json:"stages" validate:"omitempty"`
+	TimeoutHours    uint                           `json:"timeout_hours" validate:"omitempty"`
 }
 
 // Validate ...
 func (req *ApprovalProcessCreateReq) Validate() error {
-	return validator.Validate.Struct(req)
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Engine) == 0 {
+		req.Engine = enumor.ItsmApprovalEngine
+	}
+
+	return validateApprovalEngine(req.Engine, req.ServiceID, req.Stages)
 }
 
 // ApprovalProcessUpdateReq ...
 type ApprovalProcessUpdateReq struct {
-	ServiceID int64 `json:"service_id" validate:"required,min=1"`
+	ServiceID    int64                          `json:"service_id" validate:"omitempty,min=1"`
+	Managers     string                         `json:"managers" validate:"omitempty,max=255"`
+	Engine       enumor.ApprovalEngine          `json:"engine" validate:"omitempty"`
+	Stages       coreapplication.ApprovalStages `json:"stages" validate:"omitempty"`
+	TimeoutHours *uint                          `json:"timeout_hours" validate:"omitempty"`
 }
 
 // Validate ...
 func (req *ApprovalProcessUpdateReq) Validate() error {
-	return validator.Validate.Struct(req)
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Engine) == 0 {
+		if len(req.Stages) != 0 {
+			return req.Stages.Validate()
+		}
+		return nil
+	}
+
+	return validateApprovalEngine(req.Engine, req.ServiceID, req.Stages)
+}
+
+// validateApprovalEngine 切换审批引擎时需要同时提供引擎所需的配置
+func validateApprovalEngine(engine enumor.ApprovalEngine, serviceID int64,
+	stages coreapplication.ApprovalStages) error {
+
+	if err := engine.Validate(); err != nil {
+		return err
+	}
+
+	switch engine {
+	case enumor.ItsmApprovalEngine:
+		if serviceID <= 0 {
+			return errors.New("service_id is required when engine is itsm")
+		}
+	case enumor.NativeApprovalEngine:
+		if err := stages.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // ApprovalProcessListReq ...
@@ -61,10 +113,13 @@ func (l *ApprovalProcessListReq) Validate() error {
 
 // ApprovalProcessResp ...
 type ApprovalProcessResp struct {
-	ID              string                 `json:"id"`
-	ApplicationType enumor.ApplicationType `json:"application_type"`
-	ServiceID       int64                  `json:"service_id"`
-	Managers        string                 `json:"managers"`
+	ID              string                         `json:"id"`
+	ApplicationType enumor.ApplicationType         `json:"application_type"`
+	ServiceID       int64                          `json:"service_id"`
+	Managers        string                         `json:"managers"`
+	Engine          enumor.ApprovalEngine          `json:"engine"`
+	Stages          coreapplication.ApprovalStages `json:"stages"`
+	TimeoutHours    uint                           `json:"timeout_hours"`
 	core.Revision   `json:",inline"`
 }
 
@@ -141,6 +141,20 @@ func (o *OperationAction) ConvAuditAction() (enumor.AuditAction, error) {
 		return enumor.Associate, nil
 	case Disassociate:
 		return enumor.Disassociate, nil
+	case Renew:
+		return enumor.Renew, nil
+	case SetAutoRenew:
+		return enumor.SetAutoRenew, nil
+	case CreateImage:
+		return enumor.CreateImage, nil
+	case CreateSnapshot:
+		return enumor.CreateSnapshot, nil
+	case DeleteSnapshot:
+		return enumor.DeleteSnapshot, nil
+	case RollbackSnapshot:
+		return enumor.RollbackSnapshot, nil
+	case Resize:
+		return enumor.Resize, nil
 
 	default:
 		return "", fmt.Errorf("action is not corresponding audit action")
@@ -157,6 +171,20 @@ const (
 	Associate OperationAction = "associate"
 	// Disassociate 解绑、解挂载等操作
 	Disassociate OperationAction = "disassociate"
+	// Renew 包年包月资源续费
+	Renew OperationAction = "renew"
+	// SetAutoRenew 设置包年包月资源自动续费
+	SetAutoRenew OperationAction = "set_auto_renew"
+	// CreateImage 基于主机创建镜像
+	CreateImage OperationAction = "create_image"
+	// CreateSnapshot 创建云硬盘快照
+	CreateSnapshot OperationAction = "create_snapshot"
+	// DeleteSnapshot 删除云硬盘快照
+	DeleteSnapshot OperationAction = "delete_snapshot"
+	// RollbackSnapshot 回滚云硬盘快照
+	RollbackSnapshot OperationAction = "rollback_snapshot"
+	// Resize 调整主机规格、扩容云硬盘
+	Resize OperationAction = "resize"
 )
 
 // CloudResourceOperationAuditReq define cloud resource operation audit req.
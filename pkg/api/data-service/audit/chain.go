@@ -0,0 +1,83 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"hcm/pkg/api/core/audit"
+	"hcm/pkg/criteria/validator"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/rest"
+)
+
+// -------------------------- Chain --------------------------
+
+// ChainVerifyReq defines verify audit hash chain request.
+type ChainVerifyReq struct {
+	// Chain is the chain name, which is the created date of the audits with the shard, e.g. 2024-10-28/03.
+	Chain string `json:"chain" validate:"required,max=16"`
+}
+
+// Validate ChainVerifyReq.
+func (req *ChainVerifyReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return tableaudit.ValidateChainName(req.Chain)
+}
+
+// ChainVerifyResp defines verify audit hash chain response.
+type ChainVerifyResp struct {
+	rest.BaseResp `json:",inline"`
+	Data          *audit.ChainVerifyResult `json:"data"`
+}
+
+// ChainListResult defines list audit hash chain result.
+type ChainListResult struct {
+	Count   uint64        `json:"count"`
+	Details []audit.Chain `json:"details"`
+}
+
+// ChainListResp defines list audit hash chain response.
+type ChainListResp struct {
+	rest.BaseResp `json:",inline"`
+	Data          *ChainListResult `json:"data"`
+}
+
+// ChainExportReq defines export audit hash chain segment request.
+type ChainExportReq struct {
+	// Chain is the chain name, which is the created date of the audits with the shard, e.g. 2024-10-28/03.
+	Chain string `json:"chain" validate:"required,max=16"`
+}
+
+// Validate ChainExportReq.
+func (req *ChainExportReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return tableaudit.ValidateChainName(req.Chain)
+}
+
+// ChainExportResp defines export audit hash chain segment response.
+type ChainExportResp struct {
+	rest.BaseResp `json:",inline"`
+	Data          *audit.ChainExportResult `json:"data"`
+}
@@ -0,0 +1,143 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package dsauth defines data-service local rbac auth api call protocols.
+package dsauth
+
+import (
+	"errors"
+	"fmt"
+
+	coreauth "hcm/pkg/api/core/auth"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/iam/meta"
+)
+
+// -------------------------- Role --------------------------
+
+// RoleCreateReq define auth role create request.
+type RoleCreateReq struct {
+	Name string  `json:"name" validate:"required,max=255"`
+	Memo *string `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate RoleCreateReq.
+func (req *RoleCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// RoleUpdateReq define auth role update request.
+type RoleUpdateReq struct {
+	Name string  `json:"name" validate:"omitempty,max=255"`
+	Memo *string `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate RoleUpdateReq.
+func (req *RoleUpdateReq) Validate() error {
+	if len(req.Name) == 0 && req.Memo == nil {
+		return errors.New("name or memo is required")
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// RoleListResult define auth role list result.
+type RoleListResult struct {
+	Count   uint64          `json:"count"`
+	Details []coreauth.Role `json:"details"`
+}
+
+// -------------------------- Policy --------------------------
+
+// RolePolicyBatchCreateReq define auth role policy batch create request.
+type RolePolicyBatchCreateReq struct {
+	RoleID   string             `json:"role_id" validate:"required"`
+	Policies []RolePolicyCreate `json:"policies" validate:"required,min=1,dive"`
+}
+
+// Validate RolePolicyBatchCreateReq.
+func (req *RolePolicyBatchCreateReq) Validate() error {
+	if len(req.Policies) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("policies should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, one := range req.Policies {
+		if err := one.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RolePolicyCreate define auth role policy create option.
+type RolePolicyCreate struct {
+	ResType meta.ResourceType      `json:"res_type" validate:"required,max=64"`
+	Action  meta.Action            `json:"action" validate:"required,max=64"`
+	Scope   enumor.AuthPolicyScope `json:"scope" validate:"required"`
+	ScopeID string                 `json:"scope_id" validate:"max=64"`
+}
+
+// Validate RolePolicyCreate.
+func (c RolePolicyCreate) Validate() error {
+	if err := c.Scope.Validate(); err != nil {
+		return err
+	}
+
+	if c.Scope == enumor.AuthScopeAny && len(c.ScopeID) != 0 {
+		return errors.New("scope_id must be empty when scope is any")
+	}
+
+	if c.Scope != enumor.AuthScopeAny && len(c.ScopeID) == 0 {
+		return fmt.Errorf("scope_id is required when scope is %s", c.Scope)
+	}
+
+	return nil
+}
+
+// RolePolicyListResult define auth role policy list result.
+type RolePolicyListResult struct {
+	Count   uint64                `json:"count"`
+	Details []coreauth.RolePolicy `json:"details"`
+}
+
+// -------------------------- Binding --------------------------
+
+// RoleBindingBatchCreateReq define auth role binding batch create request.
+type RoleBindingBatchCreateReq struct {
+	RoleID string   `json:"role_id" validate:"required"`
+	Users  []string `json:"users" validate:"required,min=1,max=100,dive,required,max=64"`
+}
+
+// Validate RoleBindingBatchCreateReq.
+func (req *RoleBindingBatchCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// RoleBindingListResult define auth role binding list result.
+type RoleBindingListResult struct {
+	Count   uint64                 `json:"count"`
+	Details []coreauth.RoleBinding `json:"details"`
+}
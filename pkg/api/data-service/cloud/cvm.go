@@ -26,6 +26,7 @@ import (
 	"hcm/pkg/api/core"
 	corecvm "hcm/pkg/api/core/cloud/cvm"
 	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/rest"
 	"hcm/pkg/runtime/filter"
@@ -65,6 +66,9 @@ type CvmBatchCreate[Extension corecvm.Extension] struct {
 	CloudLaunchedTime    string     `json:"cloud_launched_time"`
 	CloudExpiredTime     string     `json:"cloud_expired_time"`
 	Extension            *Extension `json:"extension" validate:"required"`
+
+	// LifecycleStatus 由 Status 归一化得到的生命周期状态
+	LifecycleStatus enumor.CvmLifecycleStatus `json:"lifecycle_status" validate:"omitempty"`
 }
 
 // Validate cvm create request.
@@ -104,6 +108,9 @@ type CvmBatchUpdate[Extension corecvm.Extension] struct {
 	CloudLaunchedTime    string     `json:"cloud_launched_time"`
 	CloudExpiredTime     string     `json:"cloud_expired_time"`
 	Extension            *Extension `json:"extension,omitempty"`
+
+	// LifecycleStatus 由 Status 归一化得到的生命周期状态
+	LifecycleStatus enumor.CvmLifecycleStatus `json:"lifecycle_status" validate:"omitempty"`
 }
 
 // Validate cvm update request.
@@ -0,0 +1,141 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disk
+
+import (
+	"errors"
+
+	"hcm/pkg/api/core"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/tools/cron"
+)
+
+// SnapshotBatchCreateReq define disk snapshot batch create request.
+type SnapshotBatchCreateReq struct {
+	Snapshots []SnapshotCreate `json:"snapshots" validate:"required,min=1,max=100,dive"`
+}
+
+// Validate SnapshotBatchCreateReq.
+func (req *SnapshotBatchCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// SnapshotCreate define disk snapshot create info.
+type SnapshotCreate struct {
+	Vendor           enumor.Vendor `json:"vendor" validate:"required"`
+	AccountID        string        `json:"account_id" validate:"required"`
+	BkBizID          int64         `json:"bk_biz_id" validate:"required"`
+	Region           string        `json:"region" validate:"required"`
+	CloudID          string        `json:"cloud_id" validate:"required"`
+	Name             string        `json:"name" validate:"omitempty,max=255"`
+	DiskID           string        `json:"disk_id" validate:"omitempty"`
+	CloudDiskID      string        `json:"cloud_disk_id" validate:"omitempty"`
+	DiskSize         uint64        `json:"disk_size" validate:"omitempty"`
+	Status           string        `json:"status" validate:"omitempty,max=32"`
+	PolicyID         string        `json:"policy_id" validate:"omitempty"`
+	CloudCreatedTime string        `json:"cloud_created_time" validate:"omitempty"`
+	Memo             string        `json:"memo" validate:"omitempty,max=255"`
+}
+
+// SnapshotBatchUpdateReq define disk snapshot batch update request.
+type SnapshotBatchUpdateReq struct {
+	Snapshots []SnapshotUpdate `json:"snapshots" validate:"required,min=1,max=100,dive"`
+}
+
+// Validate SnapshotBatchUpdateReq.
+func (req *SnapshotBatchUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, one := range req.Snapshots {
+		if one.BkBizID == 0 && len(one.Name) == 0 && len(one.DiskID) == 0 && one.DiskSize == 0 &&
+			len(one.Status) == 0 && one.Memo == nil {
+			return errors.New("at least one field should be updated")
+		}
+	}
+
+	return nil
+}
+
+// SnapshotUpdate define disk snapshot update info, the empty field will not be updated.
+type SnapshotUpdate struct {
+	ID       string  `json:"id" validate:"required"`
+	BkBizID  int64   `json:"bk_biz_id" validate:"omitempty"`
+	Name     string  `json:"name" validate:"omitempty,max=255"`
+	DiskID   string  `json:"disk_id" validate:"omitempty"`
+	DiskSize uint64  `json:"disk_size" validate:"omitempty"`
+	Status   string  `json:"status" validate:"omitempty,max=32"`
+	Memo     *string `json:"memo" validate:"omitempty,max=255"`
+}
+
+// SnapshotListResult define disk snapshot list result.
+type SnapshotListResult = core.ListResultT[*coredisk.Snapshot]
+
+// SnapshotPolicyCreateReq define disk snapshot policy create request.
+type SnapshotPolicyCreateReq struct {
+	Name           string   `json:"name" validate:"required,max=255"`
+	BkBizID        int64    `json:"bk_biz_id" validate:"required,min=1"`
+	Cron           string   `json:"cron" validate:"required,max=64"`
+	RetentionCount uint64   `json:"retention_count" validate:"required,min=1,max=100"`
+	DiskIDs        []string `json:"disk_ids" validate:"required,min=1,max=100"`
+	Enabled        *bool    `json:"enabled" validate:"required"`
+	Memo           *string  `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate SnapshotPolicyCreateReq.
+func (req *SnapshotPolicyCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return cron.Validate(req.Cron)
+}
+
+// SnapshotPolicyUpdateReq define disk snapshot policy update request, the empty field will not be updated.
+type SnapshotPolicyUpdateReq struct {
+	Name           string    `json:"name" validate:"omitempty,max=255"`
+	Cron           string    `json:"cron" validate:"omitempty,max=64"`
+	RetentionCount uint64    `json:"retention_count" validate:"omitempty,max=100"`
+	DiskIDs        *[]string `json:"disk_ids" validate:"omitempty,min=1,max=100"`
+	Enabled        *bool     `json:"enabled" validate:"omitempty"`
+	LastExecutedAt string    `json:"last_executed_at" validate:"omitempty"`
+	Memo           *string   `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate SnapshotPolicyUpdateReq.
+func (req *SnapshotPolicyUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Cron) != 0 {
+		if err := cron.Validate(req.Cron); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SnapshotPolicyListResult define disk snapshot policy list result.
+type SnapshotPolicyListResult = core.ListResultT[*coredisk.SnapshotPolicy]
@@ -0,0 +1,86 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package image
+
+import (
+	"errors"
+
+	"hcm/pkg/api/core"
+	coreimage "hcm/pkg/api/core/cloud/image"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// PrivateImageBatchCreateReq define private image batch create request.
+type PrivateImageBatchCreateReq struct {
+	Images []PrivateImageCreate `json:"images" validate:"required,min=1,max=100,dive"`
+}
+
+// Validate PrivateImageBatchCreateReq.
+func (req *PrivateImageBatchCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// PrivateImageCreate define private image create info.
+type PrivateImageCreate struct {
+	Vendor           enumor.Vendor             `json:"vendor" validate:"required"`
+	AccountID        string                    `json:"account_id" validate:"required"`
+	BkBizID          int64                     `json:"bk_biz_id" validate:"required"`
+	Region           string                    `json:"region" validate:"required"`
+	CloudID          string                    `json:"cloud_id" validate:"required"`
+	Name             string                    `json:"name" validate:"omitempty,max=255"`
+	SourceCvmID      string                    `json:"source_cvm_id" validate:"omitempty"`
+	SourceCloudCvmID string                    `json:"source_cloud_cvm_id" validate:"omitempty"`
+	SourceImageID    string                    `json:"source_image_id" validate:"omitempty"`
+	Status           enumor.PrivateImageStatus `json:"status" validate:"required"`
+	Memo             string                    `json:"memo" validate:"omitempty,max=255"`
+}
+
+// PrivateImageBatchUpdateReq define private image batch update request.
+type PrivateImageBatchUpdateReq struct {
+	Images []PrivateImageUpdate `json:"images" validate:"required,min=1,max=100,dive"`
+}
+
+// Validate PrivateImageBatchUpdateReq.
+func (req *PrivateImageBatchUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, one := range req.Images {
+		if one.SharedAccountIDs == nil && one.Deprecated == nil && one.Memo == nil && one.Status == nil {
+			return errors.New("at least one field should be updated")
+		}
+	}
+
+	return nil
+}
+
+// PrivateImageUpdate define private image update info.
+type PrivateImageUpdate struct {
+	ID               string                     `json:"id" validate:"required"`
+	SharedAccountIDs *[]string                  `json:"shared_account_ids" validate:"omitempty"`
+	Deprecated       *bool                      `json:"deprecated" validate:"omitempty"`
+	Status           *enumor.PrivateImageStatus `json:"status" validate:"omitempty"`
+	Memo             *string                    `json:"memo" validate:"omitempty,max=255"`
+}
+
+// PrivateImageListResult define private image list result.
+type PrivateImageListResult = core.ListResultT[*coreimage.PrivateImage]
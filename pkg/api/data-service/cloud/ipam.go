@@ -0,0 +1,137 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// -------------------------- Pool --------------------------
+
+// IpamPoolBatchCreateReq define ipam cidr pool batch create request.
+type IpamPoolBatchCreateReq struct {
+	Pools []IpamPoolCreate `json:"pools" validate:"required,min=1,max=100,dive"`
+}
+
+// Validate IpamPoolBatchCreateReq.
+func (req *IpamPoolBatchCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// IpamPoolCreate define ipam cidr pool create option.
+type IpamPoolCreate struct {
+	Name    string        `json:"name" validate:"required,max=255"`
+	Vendor  enumor.Vendor `json:"vendor" validate:"required"`
+	Region  string        `json:"region" validate:"required"`
+	BkBizID int64         `json:"bk_biz_id" validate:"required"`
+	Cidr    string        `json:"cidr" validate:"required,cidrv4"`
+	Memo    *string       `json:"memo" validate:"omitempty,max=255"`
+}
+
+// IpamPoolUpdateReq define ipam cidr pool update request.
+type IpamPoolUpdateReq struct {
+	Name string  `json:"name" validate:"omitempty,max=255"`
+	Memo *string `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate IpamPoolUpdateReq.
+func (req *IpamPoolUpdateReq) Validate() error {
+	if len(req.Name) == 0 && req.Memo == nil {
+		return errors.New("name or memo is required")
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// IpamPoolListResult define ipam cidr pool list result.
+type IpamPoolListResult struct {
+	Count   uint64           `json:"count"`
+	Details []cloud.IpamPool `json:"details"`
+}
+
+// -------------------------- Allocation --------------------------
+
+// IpamAllocationBatchCreateReq define ipam cidr allocation batch create request.
+type IpamAllocationBatchCreateReq struct {
+	Allocations []IpamAllocationCreate `json:"allocations" validate:"required,min=1,dive"`
+}
+
+// Validate IpamAllocationBatchCreateReq.
+func (req *IpamAllocationBatchCreateReq) Validate() error {
+	if len(req.Allocations) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("allocations should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, one := range req.Allocations {
+		if err := one.Type.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IpamAllocationCreate define ipam cidr allocation create option.
+type IpamAllocationCreate struct {
+	PoolID  string                    `json:"pool_id" validate:"required"`
+	BkBizID int64                     `json:"bk_biz_id" validate:"required"`
+	Cidr    string                    `json:"cidr" validate:"required,cidrv4"`
+	Type    enumor.IpamAllocationType `json:"type" validate:"required"`
+	ResType enumor.CloudResourceType  `json:"res_type" validate:"omitempty"`
+	ResID   string                    `json:"res_id" validate:"omitempty"`
+	Memo    *string                   `json:"memo" validate:"omitempty,max=255"`
+}
+
+// IpamAllocationUpdateReq define ipam cidr allocation update request, used to bind the allocated cidr to the
+// resource which is created with it.
+type IpamAllocationUpdateReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"omitempty"`
+	ResID   string                   `json:"res_id" validate:"omitempty"`
+	Memo    *string                  `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate IpamAllocationUpdateReq.
+func (req *IpamAllocationUpdateReq) Validate() error {
+	if len(req.ResID) == 0 && req.Memo == nil {
+		return errors.New("res_id or memo is required")
+	}
+
+	if len(req.ResID) != 0 && len(req.ResType) == 0 {
+		return errors.New("res_type is required when res_id is set")
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// IpamAllocationListResult define ipam cidr allocation list result.
+type IpamAllocationListResult struct {
+	Count   uint64                 `json:"count"`
+	Details []cloud.IpamAllocation `json:"details"`
+}
@@ -0,0 +1,166 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ResourceTagKeyMaxLength 资源标签键、值的最大长度
+const ResourceTagKeyMaxLength = 255
+
+// ValidateResourceTags validate resource tags.
+func ValidateResourceTags(tags map[string]string) error {
+	for key, value := range tags {
+		if len(key) == 0 {
+			return errors.New("tag key can not be empty")
+		}
+
+		if len(key) > ResourceTagKeyMaxLength || len(value) > ResourceTagKeyMaxLength {
+			return fmt.Errorf("length of tag key and value should <= %d, key: %s", ResourceTagKeyMaxLength, key)
+		}
+	}
+
+	return nil
+}
+
+// -------------------------- Sync --------------------------
+
+// TagSyncResTypes 各云厂商同步了标签的资源类型，资源同步时将这些资源的云上标签同步到资源标签表，
+// 新增资源标签同步时需要在此登记，标签合规评估等依赖资源标签表的功能按此范围处理资源
+var TagSyncResTypes = map[enumor.Vendor][]enumor.CloudResourceType{
+	enumor.TCloud: {enumor.CvmCloudResType, enumor.DiskCloudResType, enumor.SecurityGroupCloudResType,
+		enumor.EipCloudResType, enumor.VpcCloudResType, enumor.LoadBalancerCloudResType},
+	enumor.Aws: {enumor.CvmCloudResType, enumor.DiskCloudResType, enumor.SecurityGroupCloudResType,
+		enumor.EipCloudResType, enumor.VpcCloudResType},
+	enumor.Azure: {enumor.CvmCloudResType, enumor.DiskCloudResType, enumor.SecurityGroupCloudResType,
+		enumor.EipCloudResType, enumor.VpcCloudResType},
+	enumor.Gcp: {enumor.CvmCloudResType, enumor.DiskCloudResType, enumor.EipCloudResType},
+	enumor.HuaWei: {enumor.CvmCloudResType, enumor.DiskCloudResType, enumor.EipCloudResType,
+		enumor.VpcCloudResType},
+}
+
+// IsTagSyncResType 判断云厂商的资源类型是否同步了标签
+func IsTagSyncResType(vendor enumor.Vendor, resType enumor.CloudResourceType) bool {
+	for _, one := range TagSyncResTypes[vendor] {
+		if one == resType {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceTagSyncReq sync the tags of the account's cloud resources with the cloud,
+// the tags of the resource in items will be replaced, the tags of deleted resources will be removed.
+type ResourceTagSyncReq struct {
+	Vendor             enumor.Vendor            `json:"vendor" validate:"required"`
+	AccountID          string                   `json:"account_id" validate:"required"`
+	ResType            enumor.CloudResourceType `json:"res_type" validate:"required"`
+	Items              []ResourceTagSyncItem    `json:"items" validate:"omitempty,dive"`
+	DeletedCloudResIDs []string                 `json:"deleted_cloud_res_ids" validate:"omitempty"`
+}
+
+// Validate ResourceTagSyncReq.
+func (req *ResourceTagSyncReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if !IsTagSyncResType(req.Vendor, req.ResType) {
+		return fmt.Errorf("%s %s tags are not synced, it should be registered in TagSyncResTypes", req.Vendor,
+			req.ResType)
+	}
+
+	if len(req.Items) == 0 && len(req.DeletedCloudResIDs) == 0 {
+		return errors.New("items or deleted_cloud_res_ids is required")
+	}
+
+	if len(req.Items) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("items should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	if len(req.DeletedCloudResIDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("deleted_cloud_res_ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	for _, one := range req.Items {
+		if err := ValidateResourceTags(one.Tags); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ResourceTagSyncItem define the cloud tags of the resource.
+type ResourceTagSyncItem struct {
+	CloudResID string            `json:"cloud_res_id" validate:"required"`
+	Tags       map[string]string `json:"tags" validate:"omitempty"`
+}
+
+// -------------------------- Upsert --------------------------
+
+// ResourceTagBatchUpsertReq add tags to resources, the value of the tag will be updated if the key exists.
+type ResourceTagBatchUpsertReq struct {
+	Items []ResourceTagUpsertItem `json:"items" validate:"required,min=1,dive"`
+}
+
+// Validate ResourceTagBatchUpsertReq.
+func (req *ResourceTagBatchUpsertReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Items) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("items should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	for _, one := range req.Items {
+		if err := ValidateResourceTags(one.Tags); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ResourceTagUpsertItem define the tags to be added to the resource.
+type ResourceTagUpsertItem struct {
+	Vendor     enumor.Vendor            `json:"vendor" validate:"required"`
+	AccountID  string                   `json:"account_id" validate:"required"`
+	ResType    enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResID      string                   `json:"res_id" validate:"required"`
+	CloudResID string                   `json:"cloud_res_id" validate:"required"`
+	Tags       map[string]string        `json:"tags" validate:"required,min=1"`
+}
+
+// -------------------------- List --------------------------
+
+// ResourceTagListResult define resource tag list result.
+type ResourceTagListResult struct {
+	Count   uint64              `json:"count"`
+	Details []cloud.ResourceTag `json:"details"`
+}
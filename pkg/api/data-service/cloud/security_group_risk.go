@@ -0,0 +1,78 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+)
+
+// -------------------------- Replace --------------------------
+
+// SGRiskFindingReplaceReq replace all the risk findings of the account with the latest scan result.
+type SGRiskFindingReplaceReq struct {
+	Vendor    enumor.Vendor         `json:"vendor" validate:"required"`
+	AccountID string                `json:"account_id" validate:"required"`
+	Findings  []SGRiskFindingCreate `json:"findings" validate:"omitempty,dive"`
+}
+
+// Validate SGRiskFindingReplaceReq.
+func (req *SGRiskFindingReplaceReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, one := range req.Findings {
+		if one.Vendor != req.Vendor || one.AccountID != req.AccountID {
+			return errf.Newf(errf.InvalidParameter, "finding of res: %s not belongs to account: %s", one.ResID,
+				req.AccountID)
+		}
+	}
+
+	return nil
+}
+
+// SGRiskFindingCreate define security group risk finding create option.
+type SGRiskFindingCreate struct {
+	Vendor      enumor.Vendor            `json:"vendor" validate:"required"`
+	AccountID   string                   `json:"account_id" validate:"required"`
+	BkBizID     int64                    `json:"bk_biz_id" validate:"required"`
+	Region      string                   `json:"region" validate:"omitempty"`
+	ResType     enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResID       string                   `json:"res_id" validate:"required"`
+	CloudResID  string                   `json:"cloud_res_id" validate:"omitempty"`
+	ResName     string                   `json:"res_name" validate:"omitempty"`
+	RuleID      string                   `json:"rule_id" validate:"omitempty"`
+	CloudRuleID string                   `json:"cloud_rule_id" validate:"omitempty"`
+	CheckName   string                   `json:"check_name" validate:"required"`
+	CheckType   enumor.SGRiskCheckType   `json:"check_type" validate:"required"`
+	Severity    enumor.SGRiskSeverity    `json:"severity" validate:"required"`
+	Description string                   `json:"description" validate:"omitempty"`
+}
+
+// -------------------------- List --------------------------
+
+// SGRiskFindingListResult define security group risk finding list result.
+type SGRiskFindingListResult struct {
+	Count   uint64                `json:"count"`
+	Details []cloud.SGRiskFinding `json:"details"`
+}
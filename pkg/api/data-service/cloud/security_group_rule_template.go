@@ -0,0 +1,162 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// -------------------------- Create --------------------------
+
+// SGRuleTemplateBatchCreateReq define security group rule template batch create request.
+type SGRuleTemplateBatchCreateReq struct {
+	Templates []SGRuleTemplateCreate `json:"templates" validate:"required,min=1,max=100,dive"`
+}
+
+// Validate SGRuleTemplateBatchCreateReq.
+func (req *SGRuleTemplateBatchCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, one := range req.Templates {
+		if err := cloud.ValidateSGTemplateRules(one.Rules); err != nil {
+			return fmt.Errorf("template %s is invalid, err: %v", one.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// SGRuleTemplateCreate define security group rule template create option.
+type SGRuleTemplateCreate struct {
+	Name    string                 `json:"name" validate:"required,max=255"`
+	BkBizID int64                  `json:"bk_biz_id" validate:"required"`
+	Rules   []cloud.SGTemplateRule `json:"rules" validate:"required"`
+	Memo    *string                `json:"memo" validate:"omitempty,max=255"`
+}
+
+// -------------------------- Update --------------------------
+
+// SGRuleTemplateUpdateReq define security group rule template update request, the template version will be
+// increased when the rules are updated.
+type SGRuleTemplateUpdateReq struct {
+	Name  string                 `json:"name" validate:"omitempty,max=255"`
+	Rules []cloud.SGTemplateRule `json:"rules" validate:"omitempty"`
+	Memo  *string                `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate SGRuleTemplateUpdateReq.
+func (req *SGRuleTemplateUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Name) == 0 && len(req.Rules) == 0 && req.Memo == nil {
+		return errors.New("at least one of name, rules, memo should be updated")
+	}
+
+	if len(req.Rules) != 0 {
+		return cloud.ValidateSGTemplateRules(req.Rules)
+	}
+
+	return nil
+}
+
+// -------------------------- List --------------------------
+
+// SGRuleTemplateListResult define security group rule template list result.
+type SGRuleTemplateListResult struct {
+	Count   uint64                 `json:"count"`
+	Details []cloud.SGRuleTemplate `json:"details"`
+}
+
+// -------------------------- Apply --------------------------
+
+// SGRuleTplApplyBatchCreateReq define security group rule template apply record batch create request.
+type SGRuleTplApplyBatchCreateReq struct {
+	Applies []SGRuleTplApplyCreate `json:"applies" validate:"required,min=1,dive"`
+}
+
+// Validate SGRuleTplApplyBatchCreateReq.
+func (req *SGRuleTplApplyBatchCreateReq) Validate() error {
+	if len(req.Applies) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("applies should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// SGRuleTplApplyCreate define security group rule template apply record create option.
+type SGRuleTplApplyCreate struct {
+	TemplateID      string                   `json:"template_id" validate:"required"`
+	TemplateVersion uint64                   `json:"template_version" validate:"required"`
+	Vendor          enumor.Vendor            `json:"vendor" validate:"required"`
+	AccountID       string                   `json:"account_id" validate:"required"`
+	BkBizID         int64                    `json:"bk_biz_id" validate:"required"`
+	ResType         enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResID           string                   `json:"res_id" validate:"required"`
+	CloudResID      string                   `json:"cloud_res_id" validate:"omitempty"`
+}
+
+// SGRuleTplApplyBatchUpdateReq define security group rule template apply record batch update request.
+type SGRuleTplApplyBatchUpdateReq struct {
+	Applies []SGRuleTplApplyUpdate `json:"applies" validate:"required,min=1,dive"`
+}
+
+// Validate SGRuleTplApplyBatchUpdateReq.
+func (req *SGRuleTplApplyBatchUpdateReq) Validate() error {
+	if len(req.Applies) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("applies should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// SGRuleTplApplyUpdate define security group rule template apply record update option.
+type SGRuleTplApplyUpdate struct {
+	ID     string                     `json:"id" validate:"required"`
+	State  enumor.SGRuleTplApplyState `json:"state" validate:"omitempty"`
+	Reason string                     `json:"reason" validate:"omitempty,max=1024"`
+	FlowID string                     `json:"flow_id" validate:"omitempty"`
+}
+
+// SGRuleTplApplyListResult define security group rule template apply record list result.
+type SGRuleTplApplyListResult struct {
+	Count   uint64                 `json:"count"`
+	Details []cloud.SGRuleTplApply `json:"details"`
+}
+
+// SGRuleTplDeleteReq define security group rule template delete request, the apply records of the templates will
+// be deleted at the same time.
+type SGRuleTplDeleteReq struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// Validate SGRuleTplDeleteReq.
+func (req *SGRuleTplDeleteReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
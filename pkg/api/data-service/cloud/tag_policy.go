@@ -0,0 +1,173 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+)
+
+// -------------------------- Create --------------------------
+
+// TagPolicyCreateReq define tag policy create request.
+type TagPolicyCreateReq struct {
+	Name          string                     `json:"name" validate:"required,max=64"`
+	BkBizID       int64                      `json:"bk_biz_id" validate:"min=0"`
+	ResTypes      []enumor.CloudResourceType `json:"res_types" validate:"omitempty"`
+	Rules         cloud.TagPolicyRules       `json:"rules" validate:"required"`
+	AutoRemediate *bool                      `json:"auto_remediate" validate:"required"`
+	Enabled       *bool                      `json:"enabled" validate:"required"`
+	Memo          string                     `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate TagPolicyCreateReq.
+func (req *TagPolicyCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := cloud.ValidateTagPolicyResTypes(req.ResTypes); err != nil {
+		return err
+	}
+
+	return req.Rules.Validate()
+}
+
+// -------------------------- Update --------------------------
+
+// TagPolicyUpdateReq define tag policy update request.
+type TagPolicyUpdateReq struct {
+	Name string `json:"name" validate:"omitempty,max=64"`
+	// ResTypes 设置为空数组表示对所有支持标签的资源类型生效
+	ResTypes      *[]enumor.CloudResourceType `json:"res_types" validate:"omitempty"`
+	Rules         cloud.TagPolicyRules        `json:"rules" validate:"omitempty"`
+	AutoRemediate *bool                       `json:"auto_remediate" validate:"omitempty"`
+	Enabled       *bool                       `json:"enabled" validate:"omitempty"`
+	Memo          *string                     `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate TagPolicyUpdateReq.
+func (req *TagPolicyUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Name) == 0 && req.ResTypes == nil && len(req.Rules) == 0 && req.AutoRemediate == nil &&
+		req.Enabled == nil && req.Memo == nil {
+		return errors.New("at least one field should be updated")
+	}
+
+	if req.ResTypes != nil {
+		if err := cloud.ValidateTagPolicyResTypes(*req.ResTypes); err != nil {
+			return err
+		}
+	}
+
+	if len(req.Rules) != 0 {
+		return req.Rules.Validate()
+	}
+
+	return nil
+}
+
+// -------------------------- List --------------------------
+
+// TagPolicyListResult define tag policy list result.
+type TagPolicyListResult = core.ListResultT[*cloud.TagPolicy]
+
+// -------------------------- Replace Compliance --------------------------
+
+// TagComplianceReplaceReq replace all the tag compliance findings and summaries of the account with the latest
+// evaluation result.
+type TagComplianceReplaceReq struct {
+	Vendor    enumor.Vendor                `json:"vendor" validate:"required"`
+	AccountID string                       `json:"account_id" validate:"required"`
+	Findings  []TagComplianceFindingCreate `json:"findings" validate:"omitempty,dive"`
+	Summaries []TagComplianceSummaryCreate `json:"summaries" validate:"omitempty,dive"`
+}
+
+// Validate TagComplianceReplaceReq.
+func (req *TagComplianceReplaceReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, one := range req.Findings {
+		if one.Vendor != req.Vendor || one.AccountID != req.AccountID {
+			return errf.Newf(errf.InvalidParameter, "finding of res: %s not belongs to account: %s", one.ResID,
+				req.AccountID)
+		}
+	}
+
+	for _, one := range req.Summaries {
+		if one.Vendor != req.Vendor || one.AccountID != req.AccountID {
+			return errf.Newf(errf.InvalidParameter, "summary of biz: %d not belongs to account: %s", one.BkBizID,
+				req.AccountID)
+		}
+
+		if one.CompliantCount > one.TotalCount {
+			return errf.Newf(errf.InvalidParameter, "compliant count of biz: %d should <= total count",
+				one.BkBizID)
+		}
+	}
+
+	return nil
+}
+
+// TagComplianceFindingCreate define tag compliance finding create option.
+type TagComplianceFindingCreate struct {
+	PolicyID   string                   `json:"policy_id" validate:"required"`
+	Vendor     enumor.Vendor            `json:"vendor" validate:"required"`
+	AccountID  string                   `json:"account_id" validate:"required"`
+	BkBizID    int64                    `json:"bk_biz_id" validate:"required"`
+	Region     string                   `json:"region" validate:"omitempty"`
+	ResType    enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResID      string                   `json:"res_id" validate:"required"`
+	CloudResID string                   `json:"cloud_res_id" validate:"omitempty"`
+	ResName    string                   `json:"res_name" validate:"omitempty"`
+	Violations []cloud.TagViolation     `json:"violations" validate:"required,min=1"`
+}
+
+// TagComplianceSummaryCreate define tag compliance summary create option.
+type TagComplianceSummaryCreate struct {
+	Vendor         enumor.Vendor            `json:"vendor" validate:"required"`
+	AccountID      string                   `json:"account_id" validate:"required"`
+	BkBizID        int64                    `json:"bk_biz_id" validate:"required"`
+	ResType        enumor.CloudResourceType `json:"res_type" validate:"required"`
+	TotalCount     uint64                   `json:"total_count"`
+	CompliantCount uint64                   `json:"compliant_count"`
+}
+
+// TagComplianceFindingListResult define tag compliance finding list result.
+type TagComplianceFindingListResult struct {
+	Count   uint64                       `json:"count"`
+	Details []cloud.TagComplianceFinding `json:"details"`
+}
+
+// TagComplianceSummaryListResult define tag compliance summary list result.
+type TagComplianceSummaryListResult struct {
+	Count   uint64                       `json:"count"`
+	Details []cloud.TagComplianceSummary `json:"details"`
+}
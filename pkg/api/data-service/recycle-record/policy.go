@@ -0,0 +1,68 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recyclerecord
+
+import (
+	"errors"
+
+	rr "hcm/pkg/api/core/recycle-record"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// RecyclePolicyCreateReq defines create recycle policy request.
+type RecyclePolicyCreateReq struct {
+	BkBizID          int64                          `json:"bk_biz_id" validate:"min=0"`
+	ResType          enumor.CloudResourceType       `json:"res_type" validate:"required"`
+	RetentionHours   *uint64                        `json:"retention_hours" validate:"required"`
+	PreDestroyAction enumor.RecyclePreDestroyAction `json:"pre_destroy_action" validate:"omitempty"`
+	Memo             *string                        `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate RecyclePolicyCreateReq.
+func (req *RecyclePolicyCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return req.PreDestroyAction.Validate(req.ResType)
+}
+
+// RecyclePolicyUpdateReq defines update recycle policy request.
+type RecyclePolicyUpdateReq struct {
+	RetentionHours   *uint64                         `json:"retention_hours" validate:"omitempty"`
+	PreDestroyAction *enumor.RecyclePreDestroyAction `json:"pre_destroy_action" validate:"omitempty"`
+	Memo             *string                         `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate RecyclePolicyUpdateReq.
+func (req *RecyclePolicyUpdateReq) Validate() error {
+	if req.RetentionHours == nil && req.PreDestroyAction == nil && req.Memo == nil {
+		return errors.New("one of the update fields must be set")
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// RecyclePolicyListResult defines list recycle policy result.
+type RecyclePolicyListResult struct {
+	Count   uint64             `json:"count"`
+	Details []rr.RecyclePolicy `json:"details"`
+}
@@ -92,6 +92,10 @@ type UpdateReq struct {
 	ID     string                     `json:"id" validate:"required"`
 	Status enumor.RecycleRecordStatus `json:"status" validate:"omitempty"`
 	Detail interface{}                `json:"detail" validate:"omitempty"`
+	// SnapshotIDs 销毁前创建的快照或镜像ID，非空时覆盖原有值
+	SnapshotIDs []string `json:"snapshot_ids" validate:"omitempty"`
+	// Timeline 追加到回收记录时间线的事件
+	Timeline *rr.TimelineEvent `json:"timeline" validate:"omitempty"`
 }
 
 // Validate BatchUpdateReq.
@@ -61,6 +61,8 @@ func (req *AzureDeleteReq) Validate() error {
 // AzureStopReq azure stop req.
 type AzureStopReq struct {
 	SkipShutdown bool `json:"skip_shutdown" validate:"omitempty"`
+	// Deallocate 是否解除分配虚拟机
+	Deallocate bool `json:"deallocate" validate:"omitempty"`
 }
 
 // Validate request.
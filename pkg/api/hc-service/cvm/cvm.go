@@ -52,3 +52,28 @@ type OperateSyncReq struct {
 	CloudIDs  []string `json:"cloud_ids" validate:"omitempty"`
 	SelfLinks []string `json:"self_links" validate:"omitempty"`
 }
+
+// SyncCvmStatusReq 按主机ID从云上刷新主机状态请求
+type SyncCvmStatusReq struct {
+	IDs []string `json:"ids" validate:"required,min=1"`
+}
+
+// Validate sync cvm status request.
+func (req *SyncCvmStatusReq) Validate() error {
+	if len(req.IDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("cvm ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// ResizeCvmReq 调整主机规格请求，instance_type 为各云的实例规格，华为云为规格ID
+type ResizeCvmReq struct {
+	ID           string `json:"id" validate:"required"`
+	InstanceType string `json:"instance_type" validate:"required"`
+}
+
+// Validate resize cvm request.
+func (req *ResizeCvmReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
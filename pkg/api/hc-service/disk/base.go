@@ -79,6 +79,18 @@ func (req *DiskDetachReq) Validate() error {
 	return validator.Validate.Struct(req)
 }
 
+// DiskResizeReq 云硬盘扩容请求
+type DiskResizeReq struct {
+	DiskID string `json:"disk_id" validate:"required"`
+	// DiskSize 扩容后的云硬盘大小，单位GB
+	DiskSize uint64 `json:"disk_size" validate:"required"`
+}
+
+// Validate ...
+func (req *DiskResizeReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
 // BatchCreateResult ...
 type BatchCreateResult struct {
 	UnknownCloudIDs []string `json:"unknown_cloud_ids"`
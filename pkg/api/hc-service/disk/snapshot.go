@@ -0,0 +1,98 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disk
+
+import (
+	"hcm/pkg/criteria/validator"
+)
+
+// SnapshotCreateReq define create disk snapshot request.
+type SnapshotCreateReq struct {
+	AccountID    string `json:"account_id" validate:"required"`
+	DiskID       string `json:"disk_id" validate:"required"`
+	SnapshotName string `json:"snapshot_name" validate:"required,max=60"`
+	// PolicyID 定期快照策略ID，由定期快照策略触发创建时设置
+	PolicyID string `json:"policy_id" validate:"omitempty"`
+	// RetentionCount 策略快照保留个数，大于0时创建完成后删除该云硬盘超出保留个数的最早的策略快照
+	RetentionCount uint64 `json:"retention_count" validate:"omitempty"`
+	Memo           string `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate SnapshotCreateReq.
+func (req *SnapshotCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// SnapshotDeleteReq define delete disk snapshot request.
+type SnapshotDeleteReq struct {
+	AccountID string   `json:"account_id" validate:"required"`
+	IDs       []string `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// Validate SnapshotDeleteReq.
+func (req *SnapshotDeleteReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// SnapshotRollbackReq define rollback disk snapshot to the source disk request.
+type SnapshotRollbackReq struct {
+	AccountID string `json:"account_id" validate:"required"`
+	ID        string `json:"id" validate:"required"`
+}
+
+// Validate SnapshotRollbackReq.
+func (req *SnapshotRollbackReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// SnapshotSyncReq define sync disk snapshot request.
+type SnapshotSyncReq struct {
+	AccountID string `json:"account_id" validate:"required"`
+	Region    string `json:"region" validate:"required"`
+}
+
+// Validate SnapshotSyncReq.
+func (req *SnapshotSyncReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// SnapshotStatusRefreshReq define refresh disk snapshot cloud status request.
+type SnapshotStatusRefreshReq struct {
+	AccountID string   `json:"account_id" validate:"required"`
+	IDs       []string `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// Validate SnapshotStatusRefreshReq.
+func (req *SnapshotStatusRefreshReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// SnapshotStatusRefreshResult define refresh disk snapshot cloud status result.
+type SnapshotStatusRefreshResult struct {
+	Details []SnapshotStatus `json:"details"`
+}
+
+// SnapshotStatus define disk snapshot cloud status.
+type SnapshotStatus struct {
+	ID      string `json:"id"`
+	CloudID string `json:"cloud_id"`
+	// Status 云上快照状态
+	Status string `json:"status"`
+}
@@ -0,0 +1,76 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package image
+
+import (
+	"hcm/pkg/criteria/validator"
+)
+
+// PrivateImageCreateReq define create private image from cvm request.
+type PrivateImageCreateReq struct {
+	AccountID string `json:"account_id" validate:"required"`
+	CvmID     string `json:"cvm_id" validate:"required"`
+	ImageName string `json:"image_name" validate:"required,max=128"`
+	// ForcePoweroff 创建镜像时是否强制关机，仅腾讯云、亚马逊云支持，亚马逊云为创建前重启主机
+	ForcePoweroff bool   `json:"force_poweroff" validate:"omitempty"`
+	Memo          string `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate PrivateImageCreateReq.
+func (req *PrivateImageCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// PrivateImageCopyReq define copy private image to other regions request.
+type PrivateImageCopyReq struct {
+	AccountID  string   `json:"account_id" validate:"required"`
+	ID         string   `json:"id" validate:"required"`
+	DstRegions []string `json:"dst_regions" validate:"required,min=1,max=10"`
+	// ImageName 目的地域的镜像名称，为空时与源镜像名称一致
+	ImageName string `json:"image_name" validate:"omitempty,max=128"`
+}
+
+// Validate PrivateImageCopyReq.
+func (req *PrivateImageCopyReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// PrivateImageShareReq define share private image to other accounts request.
+type PrivateImageShareReq struct {
+	AccountID string `json:"account_id" validate:"required"`
+	ID        string `json:"id" validate:"required"`
+	// ShareAccountIDs 接收共享镜像的hcm账号ID，需与镜像所属账号为同一云厂商
+	ShareAccountIDs []string `json:"share_account_ids" validate:"required,min=1,max=50"`
+}
+
+// Validate PrivateImageShareReq.
+func (req *PrivateImageShareReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// PrivateImageSyncReq define sync private image status request.
+type PrivateImageSyncReq struct {
+	AccountID string `json:"account_id" validate:"required"`
+}
+
+// Validate PrivateImageSyncReq.
+func (req *PrivateImageSyncReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
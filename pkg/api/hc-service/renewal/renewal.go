@@ -0,0 +1,128 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package hcrenewal ...
+package hcrenewal
+
+import (
+	"fmt"
+	"time"
+
+	typesrenewal "hcm/pkg/adaptor/types/renewal"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ValidatePrepaidResType 校验资源类型是否支持包年包月续费操作，目前支持主机、云硬盘和带宽包。
+// 带宽包未纳入hcm资源管理，操作带宽包时资源ID为带宽包的云上ID。
+func ValidatePrepaidResType(resType enumor.CloudResourceType) error {
+	switch resType {
+	case enumor.CvmCloudResType, enumor.DiskCloudResType, enumor.BandwidthPackageCloudResType:
+		return nil
+	default:
+		return fmt.Errorf("res_type: %s does not support renew, only support cvm, disk and bandwidth_package",
+			resType)
+	}
+}
+
+// ResRenewReq 续费同一账号下同一类型的包年包月资源
+type ResRenewReq struct {
+	AccountID string                   `json:"account_id" validate:"required"`
+	ResType   enumor.CloudResourceType `json:"res_type" validate:"required"`
+	// ResIDs 资源ID，资源类型为带宽包时为带宽包的云上ID
+	ResIDs []string `json:"res_ids" validate:"required,min=1"`
+	// Period 续费时长，单位：月
+	Period int64 `json:"period" validate:"required,min=1,max=36"`
+}
+
+// Validate ResRenewReq.
+func (req *ResRenewReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.ResIDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("res_ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return ValidatePrepaidResType(req.ResType)
+}
+
+// BatchResult 续费或设置自动续费的结果，部分资源操作失败时不影响其他资源，成功的资源会重新同步
+type BatchResult struct {
+	SuccessIDs    []string `json:"success_ids"`
+	FailedIDs     []string `json:"failed_ids"`
+	FailedMessage string   `json:"failed_message"`
+}
+
+// ResAutoRenewSetReq 开启或关闭同一账号下同一类型的包年包月资源的自动续费
+type ResAutoRenewSetReq struct {
+	AccountID string                   `json:"account_id" validate:"required"`
+	ResType   enumor.CloudResourceType `json:"res_type" validate:"required"`
+	// ResIDs 资源ID，资源类型为带宽包时为带宽包的云上ID
+	ResIDs    []string `json:"res_ids" validate:"required,min=1"`
+	AutoRenew bool     `json:"auto_renew"`
+}
+
+// Validate ResAutoRenewSetReq.
+func (req *ResAutoRenewSetReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.ResIDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("res_ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return ValidatePrepaidResType(req.ResType)
+}
+
+// ExpireTimeListReq 查询账号下包年包月资源的到期时间
+type ExpireTimeListReq struct {
+	AccountID string   `json:"account_id" validate:"required"`
+	CloudIDs  []string `json:"cloud_ids" validate:"required,min=1,max=50"`
+}
+
+// Validate ExpireTimeListReq.
+func (req *ExpireTimeListReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ExpireTimeListResult 包年包月资源的到期时间
+type ExpireTimeListResult struct {
+	Details []typesrenewal.ResExpireTime `json:"details"`
+}
+
+// BandwidthPackageListReq 查询账号下包年包月的带宽包
+type BandwidthPackageListReq struct {
+	AccountID string `json:"account_id" validate:"required"`
+	// ExpireTimeEnd 仅查询该时间之前到期的带宽包，为空时查询所有包年包月的带宽包
+	ExpireTimeEnd *time.Time `json:"expire_time_end" validate:"omitempty"`
+}
+
+// Validate BandwidthPackageListReq.
+func (req *BandwidthPackageListReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// BandwidthPackageListResult 包年包月的带宽包
+type BandwidthPackageListResult struct {
+	Details []typesrenewal.PrepaidBandwidthPackage `json:"details"`
+}
@@ -21,6 +21,10 @@
 package action
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"hcm/pkg/async/action/run"
 	"hcm/pkg/criteria/enumor"
 )
@@ -44,3 +48,34 @@ type ParameterAction interface {
 	// ParameterNew 返回新的参数结构。返回参数可以实现 Decoder 接口，自定义解码方式。
 	ParameterNew() (params interface{})
 }
+
+// RescheduleError Action 需要等待一段时间后再次执行时返回该错误，任务会置回 pending 状态，等待 After 后重新下发执行。
+// 等待期间不占用执行器的 worker，也不计入任务的执行超时，适用于暂停、轮询外部状态等耗时较长的场景。
+// 重新下发时间会记录到任务的 reason 中，由调度器到期后推送执行，节点重启后依然生效。
+// State: running -> pending -> running
+type RescheduleError struct {
+	// After 重新下发前的等待时长
+	After time.Duration
+	// Reason 等待原因，记录到任务的 reason 中
+	Reason string
+}
+
+// Error return reschedule error message.
+func (e *RescheduleError) Error() string {
+	return fmt.Sprintf("reschedule after %s, reason: %s", e.After, e.Reason)
+}
+
+// Reschedule 返回等待 after 后重新执行任务的错误
+func Reschedule(after time.Duration, reason string) error {
+	return &RescheduleError{After: after, Reason: reason}
+}
+
+// AsRescheduleError 判断错误是否为 RescheduleError，是则返回该错误
+func AsRescheduleError(err error) (*RescheduleError, bool) {
+	var target *RescheduleError
+	if errors.As(err, &target) {
+		return target, true
+	}
+
+	return nil, false
+}
@@ -24,6 +24,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"hcm/pkg/api/core"
 	"hcm/pkg/async/action"
@@ -74,7 +75,8 @@ type executor struct {
 	initQueue   chan *initPayload
 	backend     backend.Backend
 
-	closeCh chan struct{}
+	closeCh  chan struct{}
+	pushLock sync.RWMutex
 
 	GetSchedulerFunc func() Scheduler
 }
@@ -93,7 +95,7 @@ func NewExecutor(kt *kit.Kit, bd backend.Backend, opt *ExecutorOption) Executor
 		initWg:             sync.WaitGroup{},
 		workerQueue:        make(chan *Task, 10),
 		initQueue:          make(chan *initPayload),
-		closeCh:            make(chan struct{}, 1),
+		closeCh:            make(chan struct{}),
 		workerNumber:       opt.WorkerNumber,
 		taskExecTimeoutSec: opt.TaskExecTimeoutSec,
 	}
@@ -170,8 +172,13 @@ func (exec *executor) workerDo(task *Task) (err error) {
 	// cancelMap清理执行成功/失败的任务
 	defer exec.cancelMap.Delete(task.ID)
 	// 无论任务成功还是失败，都需要交给scheduler分析任务流的状态
-	// 执行完的任务回写到scheduler用于获取待执行的任务
-	defer exec.GetSchedulerFunc().EntryTask(task)
+	// 执行完的任务回写到scheduler用于获取待执行的任务，等待重新下发的任务由scheduler到期后再推送到执行器
+	defer func() {
+		if _, waiting := task.rescheduleAt(); waiting {
+			return
+		}
+		exec.GetSchedulerFunc().EntryTask(task)
+	}()
 	var runErr error
 	var failedRet any
 
@@ -269,6 +276,14 @@ func (exec *executor) runTaskOnce(task *Task, act action.Action) (needRetry bool
 		}
 
 		result, err := act.Run(task.ExecuteKit, params)
+		if rescheduleErr, ok := action.AsRescheduleError(err); ok {
+			// 任务需要等待后重新执行，置回pending状态并记录重新下发时间，不占用worker等待
+			after := max(rescheduleErr.After, MinRescheduleInterval)
+			if err = exec.rescheduleTask(task, rescheduleErr.Reason, after); err != nil {
+				return false, nil, err
+			}
+			return false, nil, nil
+		}
 		if err != nil {
 			if errf.IsContextCanceled(err) {
 				// 被取消不需要重试
@@ -288,15 +303,45 @@ func (exec *executor) runTaskOnce(task *Task, act action.Action) (needRetry bool
 	return false, nil, nil
 }
 
+// rescheduleTask 将任务置回pending状态，并将重新下发时间持久化到任务的reason中。任务到期后由scheduler重新推送到执行器，
+// 等待期间节点重启时，任务流被重新调度后依然会等待到重新下发时间再执行。
+func (exec *executor) rescheduleTask(task *Task, reason string, after time.Duration) error {
+	md, err := task.buildTaskUpdateModel(exec.kt, enumor.TaskPending, reason, nil)
+	if err != nil {
+		return err
+	}
+	md.Reason.RescheduleAt = times.ConvStdTimeFormat(times.ConvStdTimeNow().Add(after))
+
+	rty := retry.NewRetryPolicy(DefRetryCount, DefRetryRangeMS)
+	err = rty.BaseExec(exec.kt, func() error {
+		return exec.backend.UpdateTask(exec.kt, md)
+	})
+	if err != nil {
+		logs.Errorf("reschedule task failed, err: %v, retryCount: %d, id: %s, reschedule_at: %s, rid: %s",
+			err, DefRetryCount, task.ID, md.Reason.RescheduleAt, exec.kt.Rid)
+		return err
+	}
+
+	task.State = enumor.TaskPending
+	task.Reason = md.Reason
+	logs.V(3).Infof("task %s will be rescheduled at %s, rid: %s", task.ID, md.Reason.RescheduleAt, task.Kit.Rid)
+
+	return nil
+}
+
 // Push 任务写入到initQueue
 func (exec *executor) Push(flow *Flow, task *Task) {
 
+	// 持有读锁写入initQueue，避免与Close并发时向已关闭的initQueue写入
+	exec.pushLock.RLock()
+	defer exec.pushLock.RUnlock()
+
 	// try to exit the sender goroutine as early as possible.
 	// try-receive and try-send select blocks are specially optimized by the standard Go compiler,
 	// so they are very efficient.
 	select {
 	case <-exec.closeCh:
-		logs.Infof("scheduler has already closed, so will not execute next task instances")
+		logs.Infof("executor has already closed, so will not execute task: %s", task.ID)
 		return
 	default:
 	}
@@ -365,10 +410,12 @@ func (exec *executor) Close() {
 
 	logs.Infof("executor receive close cmd, start to close")
 
-	defer close(exec.closeCh)
-	exec.closeCh <- struct{}{}
-
+	// 先关闭closeCh，再持有写锁关闭initQueue，确保关闭后不会再有任务写入initQueue
+	close(exec.closeCh)
+	exec.pushLock.Lock()
 	close(exec.initQueue)
+	exec.pushLock.Unlock()
+
 	exec.initWg.Wait()
 	close(exec.workerQueue)
 	exec.workerWg.Wait()
@@ -35,15 +35,18 @@ import (
 	tableasync "hcm/pkg/dal/table/async"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
 	cvt "hcm/pkg/tools/converter"
 	"hcm/pkg/tools/retry"
 	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
 )
 
 /*
 Scheduler （调度器）: TODO: 换为 捕获器、消费器，添加假死任务销毁逻辑
  1. 获取分配给当前节点的处于Scheduled状态的任务流，构建任务流树，将待执行任务推送到执行器执行。
  2. 分析执行器执行完的任务，判断任务流树状态，如果任务流处理完，更新状态，否则将子节点推送到执行器执行。
+ 3. 获取等待重新下发且已到期的任务，如果任务流树在当前节点，将任务重新推送到执行器执行。
 */
 type Scheduler interface {
 	compctrl.Closer
@@ -71,6 +74,10 @@ type scheduler struct {
 	leader   leader.Leader
 
 	closeCh chan struct{}
+
+	// pushedRescheduleAt 已推送到执行器的等待重新下发任务及其重新下发时间，避免执行器执行前重复推送，仅由
+	// rescheduledTaskWatcher 协程访问
+	pushedRescheduleAt map[string]string
 }
 
 // NewScheduler 实例化任务流调度器
@@ -85,6 +92,8 @@ func NewScheduler(bd backend.Backend, exec Executor, ld leader.Leader, opt *Sche
 		backend:          bd,
 		executor:         exec,
 		leader:           ld,
+
+		pushedRescheduleAt: make(map[string]string),
 	}
 }
 
@@ -94,9 +103,10 @@ func (sch *scheduler) Start() {
 	logs.Infof("scheduler start, worker number: %d, interval: %v", sch.workerNumber, sch.watchIntervalSec)
 
 	// 定期获取等待执行的任务流
-	sch.workerWg.Add(2)
+	sch.workerWg.Add(3)
 	go sch.scheduledFlowWatcher()
 	go sch.canceledFlowWatcher()
+	go sch.rescheduledTaskWatcher()
 
 	// 启动workerNumber个协程进行任务流解析
 	for i := 0; i < int(sch.workerNumber); i++ {
@@ -201,6 +211,110 @@ func (sch *scheduler) handleCanceledFlow(kt *kit.Kit) error {
 	return nil
 }
 
+// rescheduledTaskWatcher 定期查询等待重新下发且已到期的任务，并推送到执行器执行
+func (sch *scheduler) rescheduledTaskWatcher() {
+	defer sch.workerWg.Done()
+
+	for {
+		select {
+		case <-sch.closeCh:
+			return
+		default:
+		}
+		// Kit: Kit initiate, 每次执行创建新kit
+		kt := NewKit()
+		if err := sch.handleRescheduledTask(kt); err != nil {
+			logs.Errorf("%s: scheduler watch rescheduled task failed, err: %v, rid: %s",
+				constant.AsyncTaskWarnSign, err, kt.Rid)
+		}
+
+		time.Sleep(sch.watchIntervalSec)
+	}
+}
+
+// handleRescheduledTask 将已到期且任务流树在当前节点的等待重新下发任务推送到执行器，任务流树不在当前节点的任务
+// 由对应节点处理，节点下线后由WatchDog将任务流重新调度。
+func (sch *scheduler) handleRescheduledTask(kt *kit.Kit) error {
+	tasks, err := listRescheduledTask(kt, sch.backend)
+	if err != nil {
+		logs.Errorf("list rescheduled task failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	now := times.ConvStdTimeNow()
+	pushed := make(map[string]string)
+	for _, task := range tasks {
+		at, waiting := task.rescheduleAt()
+		if !waiting || at.After(now) {
+			continue
+		}
+
+		tree, ok := sch.getTaskTree(task.FlowID)
+		if !ok {
+			continue
+		}
+
+		pushed[task.ID] = task.Reason.RescheduleAt
+		if sch.pushedRescheduleAt[task.ID] == task.Reason.RescheduleAt {
+			// 上次已推送，等待执行器执行
+			continue
+		}
+
+		logs.V(3).Infof("push rescheduled task %s to executor, reschedule_at: %s, rid: %s", task.ID,
+			task.Reason.RescheduleAt, kt.Rid)
+		sch.executor.Push(tree.Flow, task)
+	}
+	sch.pushedRescheduleAt = pushed
+
+	return nil
+}
+
+// listRescheduledTask 查询全部等待重新下发的任务
+func listRescheduledTask(kt *kit.Kit, bd backend.Backend) ([]*Task, error) {
+
+	input := &backend.ListInput{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				tools.RuleEqual("state", enumor.TaskPending),
+				&filter.AtomRule{
+					Field: "reason",
+					Op:    filter.JSONContainsPath.Factory(),
+					Value: "reschedule_at",
+				},
+			},
+		},
+		Page: &core.BasePage{
+			Count: false,
+			Start: 0,
+			Limit: core.DefaultMaxPageLimit,
+		},
+	}
+	tasks := make([]*Task, 0)
+	for {
+		result, err := bd.ListTask(kt, input)
+		if err != nil {
+			logs.Errorf("list task failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range result {
+			tasks = append(tasks, &Task{
+				Task: one,
+				Kit:  kt.NewSubKit(),
+			})
+		}
+
+		if len(result) < int(core.DefaultMaxPageLimit) {
+			break
+		}
+
+		input.Page.Start += uint32(input.Page.Limit)
+	}
+
+	return tasks, nil
+}
+
 // listTaskByFlowID 查询当前FlowID全部的任务节点
 func listTaskByFlowID(kt *kit.Kit, bd backend.Backend, flowID string) ([]*Task, error) {
 
@@ -332,9 +446,12 @@ func (sch *scheduler) parseFlowAndPushTask(kt *kit.Kit, flow *Flow) error {
 		taskIDMap[one.ID] = one
 	}
 
-	// 所有可执行任务推送到执行器
+	// 所有可执行任务推送到执行器，等待重新下发的任务由 rescheduledTaskWatcher 到期后推送
 	flow.State = enumor.FlowRunning
 	for _, taskID := range executableTaskNodes {
+		if _, waiting := taskIDMap[taskID].rescheduleAt(); waiting {
+			continue
+		}
 		sch.executor.Push(flow, taskIDMap[taskID])
 	}
 
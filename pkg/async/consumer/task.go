@@ -23,10 +23,12 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	"hcm/pkg/async/action"
 	"hcm/pkg/async/action/run"
 	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	tableasync "hcm/pkg/dal/table/async"
 	"hcm/pkg/dal/table/types"
@@ -46,6 +48,23 @@ type Task struct {
 	Flow       *Flow
 }
 
+// rescheduleAt 返回任务等待重新下发的时间，waiting 为 false 表示任务不处于等待重新下发状态。
+// 重新下发时间解析失败时按已到期处理，避免任务一直处于pending状态。
+func (task *Task) rescheduleAt() (at time.Time, waiting bool) {
+	if task.State != enumor.TaskPending || task.Reason == nil || len(task.Reason.RescheduleAt) == 0 {
+		return time.Time{}, false
+	}
+
+	at, err := time.Parse(constant.TimeStdFormat, task.Reason.RescheduleAt)
+	if err != nil {
+		logs.Errorf("parse task reschedule time failed, err: %v, id: %s, reschedule_at: %s", err, task.ID,
+			task.Reason.RescheduleAt)
+		return time.Time{}, true
+	}
+
+	return at, true
+}
+
 // ValidateBeforeExec task validate before execute.
 func (task *Task) ValidateBeforeExec(act action.Action) error {
 	switch task.State {
@@ -20,6 +20,8 @@
 package consumer
 
 import (
+	"time"
+
 	"hcm/pkg/api/core"
 	"hcm/pkg/async/backend/model"
 	"hcm/pkg/kit"
@@ -35,6 +37,8 @@ var (
 	DefRetryCount = uint(3)
 	// DefRetryRangeMS 任务执行失败默认重试周期
 	DefRetryRangeMS = [2]uint{1000, 15000}
+	// MinRescheduleInterval 任务重新下发的最小等待时长
+	MinRescheduleInterval = time.Second
 )
 
 const (
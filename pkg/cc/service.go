@@ -77,9 +77,10 @@ type Setting interface {
 
 // ApiServerSetting defines api server used setting options.
 type ApiServerSetting struct {
-	Network Network   `yaml:"network"`
-	Service Service   `yaml:"service"`
-	Log     LogOption `yaml:"log"`
+	Network   Network   `yaml:"network"`
+	Service   Service   `yaml:"service"`
+	Log       LogOption `yaml:"log"`
+	RateLimit RateLimit `yaml:"rateLimit"`
 }
 
 // trySetFlagBindIP try set flag bind ip.
@@ -92,6 +93,7 @@ func (s *ApiServerSetting) trySetDefault() {
 	s.Network.trySetDefault()
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
+	s.RateLimit.trySetDefault()
 
 	return
 }
@@ -107,6 +109,10 @@ func (s ApiServerSetting) Validate() error {
 		return err
 	}
 
+	if err := s.RateLimit.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -124,6 +130,12 @@ type CloudServerSetting struct {
 	Itsm           ApiGateway     `yaml:"itsm"`
 	CloudSelection CloudSelection `yaml:"cloudSelection"`
 	Cmsi           CMSI           `yaml:"cmsi"`
+	SGRiskScan     SGRiskScan     `yaml:"sgRiskScan"`
+	TagCompliance  TagCompliance  `yaml:"tagCompliance"`
+	AuditExport    AuditExport    `yaml:"auditExport"`
+	ExpiryWatch    ExpiryWatch    `yaml:"expiryWatch"`
+	SnapshotPolicy SnapshotPolicy `yaml:"snapshotPolicy"`
+	Rightsizing    Rightsizing    `yaml:"rightsizing"`
 }
 
 // trySetFlagBindIP try set flag bind ip.
@@ -136,6 +148,9 @@ func (s *CloudServerSetting) trySetDefault() {
 	s.Network.trySetDefault()
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
+	s.AuditExport.trySetDefault()
+	s.ExpiryWatch.trySetDefault()
+	s.Rightsizing.trySetDefault()
 
 	return
 }
@@ -178,6 +193,14 @@ func (s CloudServerSetting) Validate() error {
 		return err
 	}
 
+	if err := s.SGRiskScan.validate(); err != nil {
+		return err
+	}
+
+	if err := s.Rightsizing.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -190,6 +213,8 @@ type DataServiceSetting struct {
 	Objectstore ObjectStore `yaml:"objectstore"`
 	Crypto      Crypto      `yaml:"crypto"`
 	Esb         Esb         `yaml:"esb"`
+	AuditChain  AuditChain  `yaml:"auditChain"`
+	AuditSink   AuditSink   `yaml:"auditSink"`
 }
 
 // trySetFlagBindIP try set flag bind ip.
@@ -203,6 +228,8 @@ func (s *DataServiceSetting) trySetDefault() {
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
 	s.Database.trySetDefault()
+	s.AuditChain.trySetDefault()
+	s.AuditSink.trySetDefault()
 
 	return
 }
@@ -229,6 +256,14 @@ func (s DataServiceSetting) Validate() error {
 		return err
 	}
 
+	if err := s.AuditChain.validate(); err != nil {
+		return err
+	}
+
+	if err := s.AuditSink.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -274,7 +309,8 @@ type AuthServerSetting struct {
 	Log     LogOption `yaml:"log"`
 	Esb     Esb       `yaml:"esb"`
 
-	IAM IAM `yaml:"iam"`
+	IAM       IAM       `yaml:"iam"`
+	Authorize Authorize `yaml:"authorize"`
 }
 
 // trySetFlagBindIP try set flag bind ip.
@@ -287,6 +323,7 @@ func (s *AuthServerSetting) trySetDefault() {
 	s.Network.trySetDefault()
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
+	s.Authorize.trySetDefault()
 
 	return
 }
@@ -305,10 +342,17 @@ func (s AuthServerSetting) Validate() error {
 		return err
 	}
 
-	if err := s.IAM.validate(); err != nil {
+	if err := s.Authorize.validate(); err != nil {
 		return err
 	}
 
+	// iam is only required when authorize with iam backend.
+	if s.Authorize.Backend == IAMAuthBackend {
+		if err := s.IAM.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
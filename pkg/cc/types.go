@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"strings"
 	"time"
 
 	"hcm/pkg/criteria/enumor"
@@ -501,6 +502,87 @@ func (s IAM) validate() error {
 	return nil
 }
 
+// AuthBackendType is the authorize backend type of auth server.
+type AuthBackendType string
+
+const (
+	// IAMAuthBackend 使用蓝鲸权限中心进行鉴权
+	IAMAuthBackend AuthBackendType = "iam"
+	// LocalAuthBackend 使用存储在hcm数据库中的本地rbac策略进行鉴权，不依赖蓝鲸权限中心
+	LocalAuthBackend AuthBackendType = "local"
+)
+
+// Authorize defines auth server's authorize backend related settings.
+type Authorize struct {
+	// Backend is the authorize backend type, default is iam.
+	Backend AuthBackendType `yaml:"backend"`
+	// Admins are the super administrators of local authorize backend, they are authorized to do all operations
+	// and manage the local rbac roles, policies and bindings.
+	Admins []string `yaml:"admins"`
+	// Cache defines the authorize decision cache settings.
+	Cache AuthCache `yaml:"cache"`
+}
+
+// trySetDefault set the Authorize default value if user not configured.
+func (s *Authorize) trySetDefault() {
+	if len(s.Backend) == 0 {
+		s.Backend = IAMAuthBackend
+	}
+
+	s.Cache.trySetDefault()
+}
+
+// AuthCache defines the authorize decision and authorized instances cache settings of auth server.
+type AuthCache struct {
+	// Disabled defines whether the cache is disabled.
+	Disabled bool `yaml:"disabled"`
+	// TTLSec is the seconds that a cached decision is valid, default is 30 seconds.
+	TTLSec uint `yaml:"ttlSec"`
+	// DenyTTLSec is the seconds that a cached deny decision or partial authorized instances are valid, it is much
+	// shorter than TTLSec because permissions granted in iam are noticed with delay, default is 3 seconds.
+	DenyTTLSec uint `yaml:"denyTTLSec"`
+	// MaxEntries is the max count of cached entries, the least recently used entries are evicted when exceeded,
+	// default is 100000.
+	MaxEntries uint `yaml:"maxEntries"`
+	// PolicyCheckIntervalSec is the interval seconds to check iam policy changes, the cache of the users whose
+	// policies changed are invalidated, default is 30 seconds.
+	PolicyCheckIntervalSec uint `yaml:"policyCheckIntervalSec"`
+}
+
+// trySetDefault set the AuthCache default value if user not configured.
+func (s *AuthCache) trySetDefault() {
+	if s.TTLSec == 0 {
+		s.TTLSec = 30
+	}
+
+	if s.DenyTTLSec == 0 {
+		s.DenyTTLSec = 3
+	}
+
+	if s.MaxEntries == 0 {
+		s.MaxEntries = 100000
+	}
+
+	if s.PolicyCheckIntervalSec == 0 {
+		s.PolicyCheckIntervalSec = 30
+	}
+}
+
+// validate Authorize.
+func (s Authorize) validate() error {
+	switch s.Backend {
+	case IAMAuthBackend:
+	case LocalAuthBackend:
+		if len(s.Admins) == 0 {
+			return errors.New("authorize admins is not set when using local authorize backend")
+		}
+	default:
+		return fmt.Errorf("unsupported authorize backend: %s", s.Backend)
+	}
+
+	return nil
+}
+
 // Web 服务依赖所需特有配置， 包括登录、静态文件等配置的定义
 type Web struct {
 	StaticFileDirPath string `yaml:"staticFileDirPath"`
@@ -644,6 +726,186 @@ func (a Recycle) validate() error {
 	return nil
 }
 
+// SGRiskScan 安全组风险扫描配置，账号资源同步完成后对其安全组进行扫描
+type SGRiskScan struct {
+	Enable bool `yaml:"enable"`
+	// Rules 扫描规则，未配置时使用内置的默认规则
+	Rules  []SGRiskRule `yaml:"rules"`
+	Notice SGRiskNotice `yaml:"notice"`
+}
+
+func (s SGRiskScan) validate() error {
+	if !s.Enable {
+		return nil
+	}
+
+	names := make(map[string]struct{}, len(s.Rules))
+	for _, rule := range s.Rules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+
+		if _, exists := names[rule.Name]; exists {
+			return fmt.Errorf("sgRiskScan rule name %s is duplicated", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+	}
+
+	return s.Notice.validate()
+}
+
+// SGRiskRule 安全组风险扫描规则
+type SGRiskRule struct {
+	Name     string                 `yaml:"name"`
+	Type     enumor.SGRiskCheckType `yaml:"type"`
+	Severity enumor.SGRiskSeverity  `yaml:"severity"`
+	// Protocol 对公网开放端口检查的协议，默认为tcp
+	Protocol string `yaml:"protocol"`
+	// Ports 对公网开放后视为风险的端口
+	Ports []int64 `yaml:"ports"`
+	// MaxPortCount 单条入站允许规则可放通的最大端口数量
+	MaxPortCount int64 `yaml:"maxPortCount"`
+}
+
+func (r SGRiskRule) validate() error {
+	if len(r.Name) == 0 {
+		return errors.New("sgRiskScan rule name is required")
+	}
+
+	if err := r.Type.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.Severity.Validate(); err != nil {
+		return err
+	}
+
+	switch r.Type {
+	case enumor.SGRiskPublicPort:
+		if len(r.Ports) == 0 {
+			return fmt.Errorf("sgRiskScan rule %s ports is required", r.Name)
+		}
+	case enumor.SGRiskWidePortRange:
+		if r.MaxPortCount <= 0 {
+			return fmt.Errorf("sgRiskScan rule %s maxPortCount must > 0", r.Name)
+		}
+	}
+
+	return nil
+}
+
+// SGRiskNotice 安全组风险通知配置，新发现的风险按业务通过CMSI邮件通知业务运维人员及配置的额外接收人
+type SGRiskNotice struct {
+	Enable bool `yaml:"enable"`
+	// MinSeverity 达到该风险等级才通知，默认为high
+	MinSeverity enumor.SGRiskSeverity `yaml:"minSeverity"`
+	// Receivers 接收所有业务风险通知的额外接收人，未分配业务的风险只通知这些接收人
+	Receivers []string `yaml:"receivers"`
+}
+
+func (n SGRiskNotice) validate() error {
+	if !n.Enable {
+		return nil
+	}
+
+	if len(n.MinSeverity) != 0 {
+		if err := n.MinSeverity.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TagCompliance 标签合规评估配置，账号资源同步完成后使用标签策略评估其资源
+type TagCompliance struct {
+	Enable bool `yaml:"enable"`
+	// Remediate 是否允许开启了自动补齐的标签策略为缺少标签的资源补齐默认标签
+	Remediate bool `yaml:"remediate"`
+}
+
+// ExpiryWatch 包年包月资源到期监控配置，定期通过邮件通知业务即将到期的资源
+type ExpiryWatch struct {
+	Enable bool `yaml:"enable"`
+	// AdvanceDays 资源到期前多少天开始通知，默认为7天
+	AdvanceDays uint `yaml:"advanceDays"`
+	// IntervalHour 检查间隔，单位：小时，默认为24小时
+	IntervalHour uint `yaml:"intervalHour"`
+	// Receivers 除业务运维人员外，额外接收所有业务到期通知的人员
+	Receivers []string `yaml:"receivers"`
+}
+
+// trySetDefault set the ExpiryWatch default value if user not configured.
+func (e *ExpiryWatch) trySetDefault() {
+	if e.AdvanceDays == 0 {
+		e.AdvanceDays = 7
+	}
+
+	if e.IntervalHour == 0 {
+		e.IntervalHour = 24
+	}
+}
+
+// StaticFileMetricSource 从静态文件读取主机利用率指标的指标源类型
+const StaticFileMetricSource = "static_file"
+
+// Rightsizing 主机规格优化建议配置，主机利用率指标来自可插拔的指标源
+type Rightsizing struct {
+	MetricSource RightsizingMetricSource `yaml:"metricSource"`
+	// IdleCpuPercent CPU使用率P95低于该值且内存使用率P95低于IdleMemPercent时视为空闲主机，建议关机，默认为5
+	IdleCpuPercent float64 `yaml:"idleCpuPercent"`
+	// IdleMemPercent 空闲主机的内存使用率P95上限，默认为10
+	IdleMemPercent float64 `yaml:"idleMemPercent"`
+	// TargetPercent 调整规格后预期的CPU、内存使用率P95上限，默认为70
+	TargetPercent float64 `yaml:"targetPercent"`
+}
+
+// RightsizingMetricSource 主机利用率指标源配置，未配置类型时不提供规格优化建议
+type RightsizingMetricSource struct {
+	// Type 指标源类型，目前仅支持 static_file
+	Type string `yaml:"type"`
+	// Path static_file 类型指标源的文件路径，每次生成建议时重新读取
+	Path string `yaml:"path"`
+}
+
+// trySetDefault set the Rightsizing default value if user not configured.
+func (r *Rightsizing) trySetDefault() {
+	if r.IdleCpuPercent == 0 {
+		r.IdleCpuPercent = 5
+	}
+
+	if r.IdleMemPercent == 0 {
+		r.IdleMemPercent = 10
+	}
+
+	if r.TargetPercent == 0 {
+		r.TargetPercent = 70
+	}
+}
+
+func (r Rightsizing) validate() error {
+	switch r.MetricSource.Type {
+	case "":
+	case StaticFileMetricSource:
+		if len(r.MetricSource.Path) == 0 {
+			return errors.New("rightsizing.metricSource.path is required when type is static_file")
+		}
+	default:
+		return fmt.Errorf("rightsizing.metricSource.type %s is not supported", r.MetricSource.Type)
+	}
+
+	if r.IdleCpuPercent >= r.TargetPercent || r.IdleMemPercent >= r.TargetPercent || r.TargetPercent > 100 {
+		return errors.New("rightsizing idle percent must be less than target percent, and target percent must <= 100")
+	}
+
+	return nil
+}
+
+// SnapshotPolicy 定期快照策略配置，开启后每分钟检查一次到达执行时间的策略并为其云硬盘创建快照
+type SnapshotPolicy struct {
+	Enable bool `yaml:"enable"`
+}
+
 // BillConfig 账号账单配置
 type BillConfig struct {
 	Enable          bool   `yaml:"enable"`
@@ -889,3 +1151,312 @@ type AwsSavingPlanOption struct {
 type BillAllocationOption struct {
 	AwsSavingPlans []AwsSavingPlanOption `yaml:"awsSavingPlans"`
 }
+
+// RateLimitDimension is the dimension that a rate limit rule counts requests by.
+type RateLimitDimension string
+
+const (
+	// AppCodeRateLimit counts requests by the app code of the gateway request, every app code has its own bucket.
+	AppCodeRateLimit RateLimitDimension = "appCode"
+	// UserRateLimit counts requests by the user of the gateway request, every user has its own bucket.
+	UserRateLimit RateLimitDimension = "user"
+	// RouteRateLimit counts all the requests matching the rule's routes in one shared bucket.
+	RouteRateLimit RateLimitDimension = "route"
+)
+
+// RateLimit defines the api server's token bucket rate limit settings.
+type RateLimit struct {
+	// Enable defines whether the rate limit is enabled.
+	Enable bool `yaml:"enable"`
+	// Shared defines whether the token buckets are shared by all the api server instances through etcd,
+	// otherwise every instance limits the requests it received independently. when etcd is unavailable, every
+	// instance limits the requests by its share of the rate.
+	Shared bool `yaml:"shared"`
+	// BucketTTLSec is the seconds that an idle shared token bucket is kept in etcd, default is 600 seconds.
+	BucketTTLSec uint `yaml:"bucketTTLSec"`
+	// Rules is the rate limit rules, a request is rejected if any of the matched rules has no token left.
+	Rules []RateLimitRule `yaml:"rules"`
+}
+
+// RateLimitRule defines a token bucket rate limit rule.
+type RateLimitRule struct {
+	// Name is the unique name of the rule.
+	Name string `yaml:"name"`
+	// Dimension is the dimension that the rule counts requests by.
+	Dimension RateLimitDimension `yaml:"dimension"`
+	// Values limits the rule to the specified app codes or users, empty means all app codes or users.
+	// it is ignored when dimension is route.
+	Values []string `yaml:"values"`
+	// Routes limits the rule to the requests whose path (without /api/v1) has one of the prefixes, empty means
+	// all the routes. e.g. /cloud/cvms/list
+	Routes []string `yaml:"routes"`
+	// Rate is the count of tokens generated per second.
+	Rate float64 `yaml:"rate"`
+	// Burst is the max count of tokens in the bucket, default is the ceil of rate.
+	Burst uint `yaml:"burst"`
+}
+
+// trySetDefault set the RateLimit default value if user not configured.
+func (s *RateLimit) trySetDefault() {
+	if s.BucketTTLSec == 0 {
+		s.BucketTTLSec = 600
+	}
+
+	for idx := range s.Rules {
+		if s.Rules[idx].Burst == 0 && s.Rules[idx].Rate > 0 {
+			burst := uint(s.Rules[idx].Rate)
+			if float64(burst) < s.Rules[idx].Rate {
+				burst++
+			}
+			s.Rules[idx].Burst = burst
+		}
+	}
+}
+
+// validate RateLimit.
+func (s RateLimit) validate() error {
+	if !s.Enable {
+		return nil
+	}
+
+	names := make(map[string]struct{}, len(s.Rules))
+	for _, rule := range s.Rules {
+		if len(rule.Name) == 0 {
+			return errors.New("rate limit rule name is not set")
+		}
+
+		if _, exists := names[rule.Name]; exists {
+			return fmt.Errorf("rate limit rule name %s is duplicated", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+
+		switch rule.Dimension {
+		case AppCodeRateLimit, UserRateLimit, RouteRateLimit:
+		default:
+			return fmt.Errorf("rate limit rule %s dimension %s is invalid", rule.Name, rule.Dimension)
+		}
+
+		if rule.Rate <= 0 {
+			return fmt.Errorf("rate limit rule %s rate should be greater than 0", rule.Name)
+		}
+
+		for _, route := range rule.Routes {
+			if !strings.HasPrefix(route, "/") {
+				return fmt.Errorf("rate limit rule %s route %s should start with /", rule.Name, route)
+			}
+		}
+	}
+
+	return nil
+}
+
+// AuditChain defines the audit hash chain settings of data service.
+type AuditChain struct {
+	// SignKey is the key to sign the exported audit chain segments with HMAC-SHA256, the segments can not be
+	// exported if it is not set.
+	SignKey string `yaml:"signKey"`
+	// ExportPrefix is the object store path prefix that the audit chain segments are exported to, default is audit.
+	ExportPrefix string `yaml:"exportPrefix"`
+	// Shards is the count of chains that the audits of a day are linked to, audits linked to different chains are
+	// created concurrently, default is 16, max is 100.
+	Shards uint `yaml:"shards"`
+}
+
+// trySetDefault set the AuditChain default value if user not configured.
+func (s *AuditChain) trySetDefault() {
+	if len(s.ExportPrefix) == 0 {
+		s.ExportPrefix = "audit"
+	}
+
+	if s.Shards == 0 {
+		s.Shards = 16
+	}
+}
+
+// validate AuditChain.
+func (s AuditChain) validate() error {
+	if s.Shards > 100 {
+		return errors.New("auditChain.shards should be no more than 100")
+	}
+
+	return nil
+}
+
+// AuditExport defines the periodic export of the audit chain segments, the segments of the days before today
+// which are not exported are exported to the object store of data service.
+type AuditExport struct {
+	Enable bool `yaml:"enable"`
+	// IntervalMin is the interval minutes to check the audit chains to be exported, default is 60 minutes.
+	IntervalMin uint `yaml:"intervalMin"`
+}
+
+// trySetDefault set the AuditExport default value if user not configured.
+func (s *AuditExport) trySetDefault() {
+	if s.IntervalMin == 0 {
+		s.IntervalMin = 60
+	}
+}
+
+// AuditSinkType is the type of audit sink.
+type AuditSinkType string
+
+const (
+	// SyslogAuditSink sends audits as RFC5424 syslog messages.
+	SyslogAuditSink AuditSinkType = "syslog"
+	// WebhookAuditSink posts audits to a http webhook with HMAC signature.
+	WebhookAuditSink AuditSinkType = "webhook"
+	// FileAuditSink appends audits to a local file as json lines, it is used for local testing.
+	FileAuditSink AuditSinkType = "file"
+)
+
+// AuditSink defines the settings of delivering audits to the external sinks, such as SIEM.
+// the audits are put into outbox when created, and delivered to all the sinks at least once.
+type AuditSink struct {
+	Enable bool `yaml:"enable"`
+	// BatchSize is the max count of audits delivered in one batch, default is 100.
+	BatchSize uint `yaml:"batchSize"`
+	// IntervalMS is the interval milliseconds to check the outbox, default is 1000.
+	IntervalMS uint `yaml:"intervalMS"`
+	// RetryBackoffSec is the base backoff seconds of the failed delivery, it is doubled with every attempt,
+	// default is 5 seconds.
+	RetryBackoffSec uint `yaml:"retryBackoffSec"`
+	// MaxRetryBackoffSec is the max backoff seconds of the failed delivery, default is 600 seconds.
+	MaxRetryBackoffSec uint `yaml:"maxRetryBackoffSec"`
+	// MaxAttempts is the max delivery attempts of an audit, the audit is kept in outbox as dead letter and not
+	// retried any more if it still fails, default is 20.
+	MaxAttempts uint `yaml:"maxAttempts"`
+	// Sinks is the sinks that the audits are delivered to.
+	Sinks []AuditSinkOption `yaml:"sinks"`
+}
+
+// AuditSinkOption defines an audit sink.
+type AuditSinkOption struct {
+	// Name is the unique name of the sink.
+	Name    string              `yaml:"name"`
+	Type    AuditSinkType       `yaml:"type"`
+	Syslog  AuditSyslogSink     `yaml:"syslog"`
+	Webhook AuditWebhookSink    `yaml:"webhook"`
+	File    AuditFileSinkOption `yaml:"file"`
+}
+
+// AuditSyslogSink defines the syslog audit sink.
+type AuditSyslogSink struct {
+	// Network is the network of the syslog server, udp or tcp, default is udp.
+	Network string `yaml:"network"`
+	// Address is the host:port of the syslog server.
+	Address string `yaml:"address"`
+	// AppName is the APP-NAME of the syslog message, default is bk-hcm.
+	AppName string `yaml:"appName"`
+	// Facility is the facility of the syslog message, default is 13 (log audit).
+	Facility uint `yaml:"facility"`
+}
+
+// AuditWebhookSink defines the webhook audit sink.
+type AuditWebhookSink struct {
+	URL string `yaml:"url"`
+	// Secret is the key to sign the request body with HMAC-SHA256.
+	Secret string `yaml:"secret"`
+	// TimeoutSec is the request timeout seconds, default is 10 seconds.
+	TimeoutSec uint `yaml:"timeoutSec"`
+}
+
+// AuditFileSinkOption defines the file audit sink.
+type AuditFileSinkOption struct {
+	Path string `yaml:"path"`
+}
+
+// trySetDefault set the AuditSink default value if user not configured.
+func (s *AuditSink) trySetDefault() {
+	if s.BatchSize == 0 {
+		s.BatchSize = 100
+	}
+
+	if s.IntervalMS == 0 {
+		s.IntervalMS = 1000
+	}
+
+	if s.RetryBackoffSec == 0 {
+		s.RetryBackoffSec = 5
+	}
+
+	if s.MaxRetryBackoffSec == 0 {
+		s.MaxRetryBackoffSec = 600
+	}
+
+	if s.MaxAttempts == 0 {
+		s.MaxAttempts = 20
+	}
+
+	for idx := range s.Sinks {
+		sink := &s.Sinks[idx]
+		if len(sink.Syslog.Network) == 0 {
+			sink.Syslog.Network = "udp"
+		}
+
+		if len(sink.Syslog.AppName) == 0 {
+			sink.Syslog.AppName = "bk-hcm"
+		}
+
+		if sink.Syslog.Facility == 0 {
+			sink.Syslog.Facility = 13
+		}
+
+		if sink.Webhook.TimeoutSec == 0 {
+			sink.Webhook.TimeoutSec = 10
+		}
+	}
+}
+
+// validate AuditSink.
+func (s AuditSink) validate() error {
+	if !s.Enable {
+		return nil
+	}
+
+	if len(s.Sinks) == 0 {
+		return errors.New("audit sinks are not set when audit sink is enabled")
+	}
+
+	names := make(map[string]struct{}, len(s.Sinks))
+	for _, sink := range s.Sinks {
+		if len(sink.Name) == 0 {
+			return errors.New("audit sink name is not set")
+		}
+
+		if _, exists := names[sink.Name]; exists {
+			return fmt.Errorf("audit sink name %s is duplicated", sink.Name)
+		}
+		names[sink.Name] = struct{}{}
+
+		switch sink.Type {
+		case SyslogAuditSink:
+			if sink.Syslog.Network != "udp" && sink.Syslog.Network != "tcp" {
+				return fmt.Errorf("audit sink %s syslog network %s is invalid", sink.Name, sink.Syslog.Network)
+			}
+
+			if len(sink.Syslog.Address) == 0 {
+				return fmt.Errorf("audit sink %s syslog address is not set", sink.Name)
+			}
+
+			if sink.Syslog.Facility > 23 {
+				return fmt.Errorf("audit sink %s syslog facility should be <= 23", sink.Name)
+			}
+		case WebhookAuditSink:
+			if len(sink.Webhook.URL) == 0 {
+				return fmt.Errorf("audit sink %s webhook url is not set", sink.Name)
+			}
+
+			if len(sink.Webhook.Secret) == 0 {
+				return fmt.Errorf("audit sink %s webhook secret is not set", sink.Name)
+			}
+		case FileAuditSink:
+			if len(sink.File.Path) == 0 {
+				return fmt.Errorf("audit sink %s file path is not set", sink.Name)
+			}
+		default:
+			return fmt.Errorf("audit sink %s type %s is invalid", sink.Name, sink.Type)
+		}
+	}
+
+	return nil
+}
@@ -210,3 +210,22 @@ func (c *Client) GetApplyPermUrl(ctx context.Context, h http.Header, req *meta.I
 
 	return resp.Data, err
 }
+
+// InvalidateCache invalidate authorize cache of the users, empty users means all users.
+func (c *Client) InvalidateCache(ctx context.Context, h http.Header, req *authserver.InvalidateCacheReq) error {
+	resp := new(rest.BaseResp)
+
+	err := c.client.Post().
+		WithContext(ctx).
+		Body(req).
+		SubResourcef("/auth/cache/invalidate").
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return err
+}
@@ -0,0 +1,90 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package authserver
+
+import (
+	authserver "hcm/pkg/api/auth-server"
+	"hcm/pkg/api/core"
+	dsauth "hcm/pkg/api/data-service/auth"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// CreateRbacRole create local rbac role.
+func (c *Client) CreateRbacRole(kt *kit.Kit, req *dsauth.RoleCreateReq) (*core.CreateResult, error) {
+	return common.Request[dsauth.RoleCreateReq, core.CreateResult](c.client, rest.POST, kt, req,
+		"/rbac/roles/create")
+}
+
+// UpdateRbacRole update local rbac role.
+func (c *Client) UpdateRbacRole(kt *kit.Kit, id string, req *dsauth.RoleUpdateReq) error {
+	return common.RequestNoResp[dsauth.RoleUpdateReq](c.client, rest.PATCH, kt, req, "/rbac/roles/%s", id)
+}
+
+// ListRbacRole list local rbac roles.
+func (c *Client) ListRbacRole(kt *kit.Kit, req *core.ListReq) (*dsauth.RoleListResult, error) {
+	return common.Request[core.ListReq, dsauth.RoleListResult](c.client, rest.POST, kt, req, "/rbac/roles/list")
+}
+
+// BatchDeleteRbacRole batch delete local rbac roles.
+func (c *Client) BatchDeleteRbacRole(kt *kit.Kit, req *authserver.RbacBatchDeleteReq) error {
+	return common.RequestNoResp[authserver.RbacBatchDeleteReq](c.client, rest.DELETE, kt, req, "/rbac/roles/batch")
+}
+
+// CreateRbacPolicy create local rbac role policies.
+func (c *Client) CreateRbacPolicy(kt *kit.Kit, req *dsauth.RolePolicyBatchCreateReq) (*core.BatchCreateResult,
+	error) {
+
+	return common.Request[dsauth.RolePolicyBatchCreateReq, core.BatchCreateResult](c.client, rest.POST, kt, req,
+		"/rbac/policies/create")
+}
+
+// ListRbacPolicy list local rbac role policies.
+func (c *Client) ListRbacPolicy(kt *kit.Kit, req *core.ListReq) (*dsauth.RolePolicyListResult, error) {
+	return common.Request[core.ListReq, dsauth.RolePolicyListResult](c.client, rest.POST, kt, req,
+		"/rbac/policies/list")
+}
+
+// BatchDeleteRbacPolicy batch delete local rbac role policies.
+func (c *Client) BatchDeleteRbacPolicy(kt *kit.Kit, req *authserver.RbacBatchDeleteReq) error {
+	return common.RequestNoResp[authserver.RbacBatchDeleteReq](c.client, rest.DELETE, kt, req,
+		"/rbac/policies/batch")
+}
+
+// CreateRbacBinding create local rbac role bindings.
+func (c *Client) CreateRbacBinding(kt *kit.Kit, req *dsauth.RoleBindingBatchCreateReq) (*core.BatchCreateResult,
+	error) {
+
+	return common.Request[dsauth.RoleBindingBatchCreateReq, core.BatchCreateResult](c.client, rest.POST, kt, req,
+		"/rbac/bindings/create")
+}
+
+// ListRbacBinding list local rbac role bindings.
+func (c *Client) ListRbacBinding(kt *kit.Kit, req *core.ListReq) (*dsauth.RoleBindingListResult, error) {
+	return common.Request[core.ListReq, dsauth.RoleBindingListResult](c.client, rest.POST, kt, req,
+		"/rbac/bindings/list")
+}
+
+// BatchDeleteRbacBinding batch delete local rbac role bindings.
+func (c *Client) BatchDeleteRbacBinding(kt *kit.Kit, req *authserver.RbacBatchDeleteReq) error {
+	return common.RequestNoResp[authserver.RbacBatchDeleteReq](c.client, rest.DELETE, kt, req,
+		"/rbac/bindings/batch")
+}
@@ -0,0 +1,66 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	proto "hcm/pkg/api/data-service"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// ApplicationPolicyClient is data service application policy api client.
+type ApplicationPolicyClient struct {
+	client rest.ClientInterface
+}
+
+// NewApplicationPolicyClient create a new application policy api client.
+func NewApplicationPolicyClient(client rest.ClientInterface) *ApplicationPolicyClient {
+	return &ApplicationPolicyClient{
+		client: client,
+	}
+}
+
+// Create application policy.
+func (a *ApplicationPolicyClient) Create(kt *kit.Kit, req *proto.ApplicationPolicyCreateReq) (*core.CreateResult,
+	error) {
+
+	return common.Request[proto.ApplicationPolicyCreateReq, core.CreateResult](a.client, rest.POST, kt, req,
+		"/application_policies/create")
+}
+
+// Update application policy.
+func (a *ApplicationPolicyClient) Update(kt *kit.Kit, id string, req *proto.ApplicationPolicyUpdateReq) error {
+	return common.RequestNoResp[proto.ApplicationPolicyUpdateReq](a.client, rest.PATCH, kt, req,
+		"/application_policies/%s", id)
+}
+
+// List application policy.
+func (a *ApplicationPolicyClient) List(kt *kit.Kit, req *core.ListReq) (*proto.ApplicationPolicyListResult, error) {
+	return common.Request[core.ListReq, proto.ApplicationPolicyListResult](a.client, rest.POST, kt, req,
+		"/application_policies/list")
+}
+
+// BatchDelete application policy.
+func (a *ApplicationPolicyClient) BatchDelete(kt *kit.Kit, req *proto.BatchDeleteReq) error {
+	return common.RequestNoResp[proto.BatchDeleteReq](a.client, rest.DELETE, kt, req,
+		"/application_policies/batch")
+}
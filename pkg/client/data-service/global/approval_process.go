@@ -42,7 +42,7 @@ func NewApprovalProcessClient(client rest.ClientInterface) *ApprovalProcessClien
 }
 
 // Create ...
-func (a *ApprovalProcessClient) Create(ctx context.Context, h http.Header, request *proto.ApplicationCreateReq) (
+func (a *ApprovalProcessClient) Create(ctx context.Context, h http.Header, request *proto.ApprovalProcessCreateReq) (
 	*core.CreateResult, error,
 ) {
 	resp := new(core.CreateResp)
@@ -218,3 +218,25 @@ func (a *AuditClient) GetAuditRaw(kt *kit.Kit, id uint64) (
 	return common.Request[common.Empty, coreaudit.RawAudit](a.client, rest.GET, kt, nil,
 		"/audits/%d", id)
 }
+
+// ListAuditChain list audit hash chains.
+func (a *AuditClient) ListAuditChain(kt *kit.Kit, req *core.ListReq) (*protoaudit.ChainListResult, error) {
+	return common.Request[core.ListReq, protoaudit.ChainListResult](a.client, rest.POST, kt, req,
+		"/audits/chains/list")
+}
+
+// VerifyAuditChain verify audit hash chain.
+func (a *AuditClient) VerifyAuditChain(kt *kit.Kit, req *protoaudit.ChainVerifyReq) (
+	*coreaudit.ChainVerifyResult, error) {
+
+	return common.Request[protoaudit.ChainVerifyReq, coreaudit.ChainVerifyResult](a.client, rest.POST, kt, req,
+		"/audits/chains/verify")
+}
+
+// ExportAuditChain export audit hash chain segment to object store.
+func (a *AuditClient) ExportAuditChain(kt *kit.Kit, req *protoaudit.ChainExportReq) (
+	*coreaudit.ChainExportResult, error) {
+
+	return common.Request[protoaudit.ChainExportReq, coreaudit.ChainExportResult](a.client, rest.POST, kt, req,
+		"/audits/chains/export")
+}
@@ -0,0 +1,106 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	dsauth "hcm/pkg/api/data-service/auth"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewAuthRbacClient create a new local rbac auth api client.
+func NewAuthRbacClient(client rest.ClientInterface) *AuthRbacClient {
+	return &AuthRbacClient{
+		client: client,
+	}
+}
+
+// AuthRbacClient is data service local rbac auth api client.
+type AuthRbacClient struct {
+	client rest.ClientInterface
+}
+
+// CreateRole create auth role.
+func (cli *AuthRbacClient) CreateRole(kt *kit.Kit, request *dsauth.RoleCreateReq) (*core.CreateResult, error) {
+	return common.Request[dsauth.RoleCreateReq, core.CreateResult](cli.client, rest.POST, kt, request,
+		"/auth/roles/create")
+}
+
+// UpdateRole update auth role.
+func (cli *AuthRbacClient) UpdateRole(kt *kit.Kit, id string, request *dsauth.RoleUpdateReq) error {
+	return common.RequestNoResp[dsauth.RoleUpdateReq](cli.client, rest.PATCH, kt, request, "/auth/roles/%s", id)
+}
+
+// ListRole list auth roles.
+func (cli *AuthRbacClient) ListRole(kt *kit.Kit, request *core.ListReq) (*dsauth.RoleListResult, error) {
+	return common.Request[core.ListReq, dsauth.RoleListResult](cli.client, rest.POST, kt, request,
+		"/auth/roles/list")
+}
+
+// BatchDeleteRole batch delete auth roles.
+func (cli *AuthRbacClient) BatchDeleteRole(kt *kit.Kit, request *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, request,
+		"/auth/roles/batch")
+}
+
+// BatchCreatePolicy batch create auth role policies.
+func (cli *AuthRbacClient) BatchCreatePolicy(kt *kit.Kit, request *dsauth.RolePolicyBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[dsauth.RolePolicyBatchCreateReq, core.BatchCreateResult](cli.client, rest.POST, kt,
+		request, "/auth/role_policies/batch/create")
+}
+
+// ListPolicy list auth role policies.
+func (cli *AuthRbacClient) ListPolicy(kt *kit.Kit, request *core.ListReq) (*dsauth.RolePolicyListResult, error) {
+	return common.Request[core.ListReq, dsauth.RolePolicyListResult](cli.client, rest.POST, kt, request,
+		"/auth/role_policies/list")
+}
+
+// BatchDeletePolicy batch delete auth role policies.
+func (cli *AuthRbacClient) BatchDeletePolicy(kt *kit.Kit, request *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, request,
+		"/auth/role_policies/batch")
+}
+
+// BatchCreateBinding batch create auth role bindings.
+func (cli *AuthRbacClient) BatchCreateBinding(kt *kit.Kit, request *dsauth.RoleBindingBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[dsauth.RoleBindingBatchCreateReq, core.BatchCreateResult](cli.client, rest.POST, kt,
+		request, "/auth/role_bindings/batch/create")
+}
+
+// ListBinding list auth role bindings.
+func (cli *AuthRbacClient) ListBinding(kt *kit.Kit, request *core.ListReq) (*dsauth.RoleBindingListResult,
+	error) {
+
+	return common.Request[core.ListReq, dsauth.RoleBindingListResult](cli.client, rest.POST, kt, request,
+		"/auth/role_bindings/list")
+}
+
+// BatchDeleteBinding batch delete auth role bindings.
+func (cli *AuthRbacClient) BatchDeleteBinding(kt *kit.Kit, request *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, request,
+		"/auth/role_bindings/batch")
+}
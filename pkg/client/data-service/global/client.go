@@ -47,9 +47,10 @@ type Client struct {
 	RecycleRecord *RecycleRecordClient
 	Audit         *AuditClient
 
-	Application     *ApplicationClient
-	ApprovalProcess *ApprovalProcessClient
-	Bill            *BillClient
+	Application       *ApplicationClient
+	ApprovalProcess   *ApprovalProcessClient
+	ApplicationPolicy *ApplicationPolicyClient
+	Bill              *BillClient
 
 	UserCollection *UserCollectionClient
 
@@ -57,6 +58,14 @@ type Client struct {
 	ArgsTpl        *ArgsTplClient
 	LoadBalancer   *LoadBalancerClient
 	SGCommonRel    *SGCommonRelClient
+	SGRiskFinding  *SGRiskFindingClient
+	ResourceTag    *ResourceTagClient
+	TagPolicy      *TagPolicyClient
+	PrivateImage   *PrivateImageClient
+	DiskSnapshot   *DiskSnapshotClient
+	SGRuleTemplate *SGRuleTemplateClient
+	Ipam           *IpamClient
+	AuthRbac       *AuthRbacClient
 
 	MainAccount *MainAccountClient
 	RootAccount *RootAccountClient
@@ -90,9 +99,10 @@ func NewClient(client rest.ClientInterface) *Client {
 		RecycleRecord: NewRecycleRecordClient(client),
 		Audit:         NewAuditClient(client),
 
-		Application:     NewApplicationClient(client),
-		ApprovalProcess: NewApprovalProcessClient(client),
-		Bill:            NewBillClient(client),
+		Application:       NewApplicationClient(client),
+		ApprovalProcess:   NewApprovalProcessClient(client),
+		ApplicationPolicy: NewApplicationPolicyClient(client),
+		Bill:              NewBillClient(client),
 
 		UserCollection: NewUserCollectionClient(client),
 
@@ -100,6 +110,14 @@ func NewClient(client rest.ClientInterface) *Client {
 		ArgsTpl:        NewCloudArgumentTemplateClient(client),
 		LoadBalancer:   NewLoadBalancerClient(client),
 		SGCommonRel:    NewCloudSGCommonRelClient(client),
+		SGRiskFinding:  NewSGRiskFindingClient(client),
+		ResourceTag:    NewResourceTagClient(client),
+		TagPolicy:      NewTagPolicyClient(client),
+		PrivateImage:   NewPrivateImageClient(client),
+		DiskSnapshot:   NewDiskSnapshotClient(client),
+		SGRuleTemplate: NewSGRuleTemplateClient(client),
+		Ipam:           NewIpamClient(client),
+		AuthRbac:       NewAuthRbacClient(client),
 		MainAccount:    NewMainAccountClient(client),
 		RootAccount:    NewRootAccountClient(client),
 	}
@@ -0,0 +1,95 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewIpamClient create a new ipam api client.
+func NewIpamClient(client rest.ClientInterface) *IpamClient {
+	return &IpamClient{
+		client: client,
+	}
+}
+
+// IpamClient is data service ipam api client.
+type IpamClient struct {
+	client rest.ClientInterface
+}
+
+// BatchCreatePool batch create ipam cidr pools.
+func (cli *IpamClient) BatchCreatePool(kt *kit.Kit, request *protocloud.IpamPoolBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[protocloud.IpamPoolBatchCreateReq, core.BatchCreateResult](cli.client, rest.POST, kt,
+		request, "/ipam/pools/batch/create")
+}
+
+// UpdatePool update ipam cidr pool.
+func (cli *IpamClient) UpdatePool(kt *kit.Kit, id string, request *protocloud.IpamPoolUpdateReq) error {
+	return common.RequestNoResp[protocloud.IpamPoolUpdateReq](cli.client, rest.PATCH, kt, request,
+		"/ipam/pools/%s", id)
+}
+
+// ListPool list ipam cidr pools.
+func (cli *IpamClient) ListPool(kt *kit.Kit, request *core.ListReq) (*protocloud.IpamPoolListResult, error) {
+	return common.Request[core.ListReq, protocloud.IpamPoolListResult](cli.client, rest.POST, kt, request,
+		"/ipam/pools/list")
+}
+
+// BatchDeletePool batch delete ipam cidr pools.
+func (cli *IpamClient) BatchDeletePool(kt *kit.Kit, request *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, request,
+		"/ipam/pools/batch")
+}
+
+// BatchCreateAllocation batch create ipam cidr allocations.
+func (cli *IpamClient) BatchCreateAllocation(kt *kit.Kit, request *protocloud.IpamAllocationBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[protocloud.IpamAllocationBatchCreateReq, core.BatchCreateResult](cli.client, rest.POST,
+		kt, request, "/ipam/allocations/batch/create")
+}
+
+// UpdateAllocation update ipam cidr allocation.
+func (cli *IpamClient) UpdateAllocation(kt *kit.Kit, id string, request *protocloud.IpamAllocationUpdateReq) error {
+	return common.RequestNoResp[protocloud.IpamAllocationUpdateReq](cli.client, rest.PATCH, kt, request,
+		"/ipam/allocations/%s", id)
+}
+
+// ListAllocation list ipam cidr allocations.
+func (cli *IpamClient) ListAllocation(kt *kit.Kit, request *core.ListReq) (*protocloud.IpamAllocationListResult,
+	error) {
+
+	return common.Request[core.ListReq, protocloud.IpamAllocationListResult](cli.client, rest.POST, kt, request,
+		"/ipam/allocations/list")
+}
+
+// BatchDeleteAllocation batch delete ipam cidr allocations.
+func (cli *IpamClient) BatchDeleteAllocation(kt *kit.Kit, request *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, request,
+		"/ipam/allocations/batch")
+}
@@ -0,0 +1,67 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	dataimage "hcm/pkg/api/data-service/cloud/image"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewPrivateImageClient create a new private image api client.
+func NewPrivateImageClient(client rest.ClientInterface) *PrivateImageClient {
+	return &PrivateImageClient{
+		client: client,
+	}
+}
+
+// PrivateImageClient is data service private image api client.
+type PrivateImageClient struct {
+	client rest.ClientInterface
+}
+
+// BatchCreate private image.
+func (cli *PrivateImageClient) BatchCreate(kt *kit.Kit, req *dataimage.PrivateImageBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[dataimage.PrivateImageBatchCreateReq, core.BatchCreateResult](cli.client, rest.POST, kt,
+		req, "/private_images/batch/create")
+}
+
+// BatchUpdate private image.
+func (cli *PrivateImageClient) BatchUpdate(kt *kit.Kit, req *dataimage.PrivateImageBatchUpdateReq) error {
+	return common.RequestNoResp[dataimage.PrivateImageBatchUpdateReq](cli.client, rest.PATCH, kt, req,
+		"/private_images/batch/update")
+}
+
+// List private image.
+func (cli *PrivateImageClient) List(kt *kit.Kit, req *core.ListReq) (*dataimage.PrivateImageListResult, error) {
+	return common.Request[core.ListReq, dataimage.PrivateImageListResult](cli.client, rest.POST, kt, req,
+		"/private_images/list")
+}
+
+// BatchDelete private image.
+func (cli *PrivateImageClient) BatchDelete(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, req,
+		"/private_images/batch")
+}
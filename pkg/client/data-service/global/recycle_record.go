@@ -22,6 +22,7 @@ package global
 import (
 	"hcm/pkg/api/core"
 	rr "hcm/pkg/api/core/recycle-record"
+	dataservice "hcm/pkg/api/data-service"
 	proto "hcm/pkg/api/data-service/recycle-record"
 	"hcm/pkg/client/common"
 	"hcm/pkg/criteria/errf"
@@ -165,3 +166,31 @@ func (r *RecycleRecordClient) BatchUpdateRecycleStatus(kt *kit.Kit, request *pro
 
 	return nil
 }
+
+// CreateRecyclePolicy create recycle policy.
+func (r *RecycleRecordClient) CreateRecyclePolicy(kt *kit.Kit, req *proto.RecyclePolicyCreateReq) (
+	*core.CreateResult, error) {
+
+	return common.Request[proto.RecyclePolicyCreateReq, core.CreateResult](r.client, rest.POST, kt, req,
+		"/recycle_policies/create")
+}
+
+// UpdateRecyclePolicy update recycle policy.
+func (r *RecycleRecordClient) UpdateRecyclePolicy(kt *kit.Kit, id string, req *proto.RecyclePolicyUpdateReq) error {
+	return common.RequestNoResp[proto.RecyclePolicyUpdateReq](r.client, rest.PATCH, kt, req,
+		"/recycle_policies/%s", id)
+}
+
+// ListRecyclePolicy list recycle policy.
+func (r *RecycleRecordClient) ListRecyclePolicy(kt *kit.Kit, req *core.ListReq) (*proto.RecyclePolicyListResult,
+	error) {
+
+	return common.Request[core.ListReq, proto.RecyclePolicyListResult](r.client, rest.POST, kt, req,
+		"/recycle_policies/list")
+}
+
+// BatchDeleteRecyclePolicy batch delete recycle policy.
+func (r *RecycleRecordClient) BatchDeleteRecyclePolicy(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](r.client, rest.DELETE, kt, req,
+		"/recycle_policies/batch")
+}
@@ -0,0 +1,54 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewSGRiskFindingClient create a new security group risk finding api client.
+func NewSGRiskFindingClient(client rest.ClientInterface) *SGRiskFindingClient {
+	return &SGRiskFindingClient{
+		client: client,
+	}
+}
+
+// SGRiskFindingClient is data service security group risk finding api client.
+type SGRiskFindingClient struct {
+	client rest.ClientInterface
+}
+
+// Replace security group risk findings of the account.
+func (cli *SGRiskFindingClient) Replace(kt *kit.Kit, request *protocloud.SGRiskFindingReplaceReq) error {
+	return common.RequestNoResp[protocloud.SGRiskFindingReplaceReq](cli.client, rest.POST, kt, request,
+		"/security_group_risk_findings/replace")
+}
+
+// List security group risk findings.
+func (cli *SGRiskFindingClient) List(kt *kit.Kit, request *core.ListReq) (*protocloud.SGRiskFindingListResult,
+	error) {
+
+	return common.Request[core.ListReq, protocloud.SGRiskFindingListResult](cli.client, rest.POST, kt, request,
+		"/security_group_risk_findings/list")
+}
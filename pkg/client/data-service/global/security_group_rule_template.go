@@ -0,0 +1,92 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewSGRuleTemplateClient create a new security group rule template api client.
+func NewSGRuleTemplateClient(client rest.ClientInterface) *SGRuleTemplateClient {
+	return &SGRuleTemplateClient{
+		client: client,
+	}
+}
+
+// SGRuleTemplateClient is data service security group rule template api client.
+type SGRuleTemplateClient struct {
+	client rest.ClientInterface
+}
+
+// BatchCreate security group rule templates.
+func (cli *SGRuleTemplateClient) BatchCreate(kt *kit.Kit, request *protocloud.SGRuleTemplateBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[protocloud.SGRuleTemplateBatchCreateReq, core.BatchCreateResult](cli.client, rest.POST,
+		kt, request, "/security_group_rule_templates/batch/create")
+}
+
+// Update security group rule template.
+func (cli *SGRuleTemplateClient) Update(kt *kit.Kit, id string, request *protocloud.SGRuleTemplateUpdateReq) error {
+	return common.RequestNoResp[protocloud.SGRuleTemplateUpdateReq](cli.client, rest.PATCH, kt, request,
+		"/security_group_rule_templates/%s", id)
+}
+
+// List security group rule templates.
+func (cli *SGRuleTemplateClient) List(kt *kit.Kit, request *core.ListReq) (*protocloud.SGRuleTemplateListResult,
+	error) {
+
+	return common.Request[core.ListReq, protocloud.SGRuleTemplateListResult](cli.client, rest.POST, kt, request,
+		"/security_group_rule_templates/list")
+}
+
+// BatchDelete security group rule templates and their apply records.
+func (cli *SGRuleTemplateClient) BatchDelete(kt *kit.Kit, request *protocloud.SGRuleTplDeleteReq) error {
+	return common.RequestNoResp[protocloud.SGRuleTplDeleteReq](cli.client, rest.DELETE, kt, request,
+		"/security_group_rule_templates/batch")
+}
+
+// BatchCreateApply create security group rule template apply records.
+func (cli *SGRuleTemplateClient) BatchCreateApply(kt *kit.Kit, request *protocloud.SGRuleTplApplyBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[protocloud.SGRuleTplApplyBatchCreateReq, core.BatchCreateResult](cli.client, rest.POST,
+		kt, request, "/security_group_rule_template_applies/batch/create")
+}
+
+// BatchUpdateApply update security group rule template apply records.
+func (cli *SGRuleTemplateClient) BatchUpdateApply(kt *kit.Kit,
+	request *protocloud.SGRuleTplApplyBatchUpdateReq) error {
+
+	return common.RequestNoResp[protocloud.SGRuleTplApplyBatchUpdateReq](cli.client, rest.PATCH, kt, request,
+		"/security_group_rule_template_applies/batch")
+}
+
+// ListApply list security group rule template apply records.
+func (cli *SGRuleTemplateClient) ListApply(kt *kit.Kit, request *core.ListReq) (
+	*protocloud.SGRuleTplApplyListResult, error) {
+
+	return common.Request[core.ListReq, protocloud.SGRuleTplApplyListResult](cli.client, rest.POST, kt, request,
+		"/security_group_rule_template_applies/list")
+}
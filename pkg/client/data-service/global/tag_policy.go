@@ -0,0 +1,86 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewTagPolicyClient create a new tag policy api client.
+func NewTagPolicyClient(client rest.ClientInterface) *TagPolicyClient {
+	return &TagPolicyClient{
+		client: client,
+	}
+}
+
+// TagPolicyClient is data service tag policy and tag compliance api client.
+type TagPolicyClient struct {
+	client rest.ClientInterface
+}
+
+// Create tag policy.
+func (cli *TagPolicyClient) Create(kt *kit.Kit, req *protocloud.TagPolicyCreateReq) (*core.CreateResult, error) {
+	return common.Request[protocloud.TagPolicyCreateReq, core.CreateResult](cli.client, rest.POST, kt, req,
+		"/tag_policies/create")
+}
+
+// Update tag policy.
+func (cli *TagPolicyClient) Update(kt *kit.Kit, id string, req *protocloud.TagPolicyUpdateReq) error {
+	return common.RequestNoResp[protocloud.TagPolicyUpdateReq](cli.client, rest.PATCH, kt, req,
+		"/tag_policies/%s", id)
+}
+
+// List tag policy.
+func (cli *TagPolicyClient) List(kt *kit.Kit, req *core.ListReq) (*protocloud.TagPolicyListResult, error) {
+	return common.Request[core.ListReq, protocloud.TagPolicyListResult](cli.client, rest.POST, kt, req,
+		"/tag_policies/list")
+}
+
+// BatchDelete tag policy.
+func (cli *TagPolicyClient) BatchDelete(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, req, "/tag_policies/batch")
+}
+
+// ReplaceCompliance replace tag compliance findings and summaries of the account.
+func (cli *TagPolicyClient) ReplaceCompliance(kt *kit.Kit, req *protocloud.TagComplianceReplaceReq) error {
+	return common.RequestNoResp[protocloud.TagComplianceReplaceReq](cli.client, rest.POST, kt, req,
+		"/tag_compliance/replace")
+}
+
+// ListComplianceFinding list tag compliance findings.
+func (cli *TagPolicyClient) ListComplianceFinding(kt *kit.Kit, req *core.ListReq) (
+	*protocloud.TagComplianceFindingListResult, error) {
+
+	return common.Request[core.ListReq, protocloud.TagComplianceFindingListResult](cli.client, rest.POST, kt, req,
+		"/tag_compliance/findings/list")
+}
+
+// ListComplianceSummary list tag compliance summaries.
+func (cli *TagPolicyClient) ListComplianceSummary(kt *kit.Kit, req *core.ListReq) (
+	*protocloud.TagComplianceSummaryListResult, error) {
+
+	return common.Request[core.ListReq, protocloud.TagComplianceSummaryListResult](cli.client, rest.POST, kt, req,
+		"/tag_compliance/summaries/list")
+}
@@ -39,6 +39,9 @@ type Client struct {
 	InstanceType  *InstanceTypeClient
 	Bill          *BillClient
 	MainAccount   *MainAccountClient
+	ResourceTag   *ResourceTagClient
+	DiskSnapshot  *DiskSnapshotClient
+	PrivateImage  *PrivateImageClient
 }
 
 // NewClient create a new aws api client.
@@ -58,5 +61,8 @@ func NewClient(client rest.ClientInterface) *Client {
 		InstanceType:  NewInstanceTypeClient(client),
 		Bill:          NewBillClient(client),
 		MainAccount:   NewMainAccountClient(client),
+		ResourceTag:   NewResourceTagClient(client),
+		DiskSnapshot:  NewDiskSnapshotClient(client),
+		PrivateImage:  NewPrivateImageClient(client),
 	}
 }
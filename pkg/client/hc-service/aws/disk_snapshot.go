@@ -0,0 +1,72 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"net/http"
+
+	"hcm/pkg/api/core"
+	proto "hcm/pkg/api/hc-service/disk"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewDiskSnapshotClient create a new disk snapshot api client.
+func NewDiskSnapshotClient(client rest.ClientInterface) *DiskSnapshotClient {
+	return &DiskSnapshotClient{
+		client: client,
+	}
+}
+
+// DiskSnapshotClient is hc service disk snapshot api client.
+type DiskSnapshotClient struct {
+	client rest.ClientInterface
+}
+
+// Create disk snapshot.
+func (cli *DiskSnapshotClient) Create(kt *kit.Kit, req *proto.SnapshotCreateReq) (*core.CreateResult, error) {
+	return common.Request[proto.SnapshotCreateReq, core.CreateResult](cli.client, http.MethodPost, kt, req,
+		"/disk_snapshots/create")
+}
+
+// Delete disk snapshots.
+func (cli *DiskSnapshotClient) Delete(kt *kit.Kit, req *proto.SnapshotDeleteReq) error {
+	return common.RequestNoResp[proto.SnapshotDeleteReq](cli.client, http.MethodPost, kt, req, "/disk_snapshots/delete")
+}
+
+// Rollback disk snapshot to its source disk.
+func (cli *DiskSnapshotClient) Rollback(kt *kit.Kit, req *proto.SnapshotRollbackReq) error {
+	return common.RequestNoResp[proto.SnapshotRollbackReq](cli.client, http.MethodPost, kt, req,
+		"/disk_snapshots/rollback")
+}
+
+// Sync disk snapshots of account region.
+func (cli *DiskSnapshotClient) Sync(kt *kit.Kit, req *proto.SnapshotSyncReq) error {
+	return common.RequestNoResp[proto.SnapshotSyncReq](cli.client, http.MethodPost, kt, req, "/disk_snapshots/sync")
+}
+
+// RefreshStatus refresh disk snapshots status from cloud.
+func (cli *DiskSnapshotClient) RefreshStatus(kt *kit.Kit, req *proto.SnapshotStatusRefreshReq) (
+	*proto.SnapshotStatusRefreshResult, error) {
+
+	return common.Request[proto.SnapshotStatusRefreshReq, proto.SnapshotStatusRefreshResult](cli.client,
+		http.MethodPost, kt, req, "/disk_snapshots/status/refresh")
+}
@@ -39,6 +39,9 @@ type Client struct {
 	InstanceType     *InstanceTypeClient
 	NetworkInterface *NetworkInterfaceClient
 	Bill             *BillClient
+	ResourceTag      *ResourceTagClient
+	DiskSnapshot     *DiskSnapshotClient
+	PrivateImage     *PrivateImageClient
 }
 
 // NewClient create a new azure api client.
@@ -58,5 +61,8 @@ func NewClient(client rest.ClientInterface) *Client {
 		InstanceType:     NewInstanceTypeClient(client),
 		NetworkInterface: NewNetworkInterfaceClient(client),
 		Bill:             NewBillClient(client),
+		ResourceTag:      NewResourceTagClient(client),
+		DiskSnapshot:     NewDiskSnapshotClient(client),
+		PrivateImage:     NewPrivateImageClient(client),
 	}
 }
@@ -0,0 +1,54 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package azure
+
+import (
+	"net/http"
+
+	"hcm/pkg/api/core"
+	proto "hcm/pkg/api/hc-service/image"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewPrivateImageClient create a new private image api client.
+func NewPrivateImageClient(client rest.ClientInterface) *PrivateImageClient {
+	return &PrivateImageClient{
+		client: client,
+	}
+}
+
+// PrivateImageClient is hc service private image api client.
+type PrivateImageClient struct {
+	client rest.ClientInterface
+}
+
+// Create private image from cvm.
+func (cli *PrivateImageClient) Create(kt *kit.Kit, req *proto.PrivateImageCreateReq) (*core.CreateResult, error) {
+	return common.Request[proto.PrivateImageCreateReq, core.CreateResult](cli.client, http.MethodPost, kt, req,
+		"/private_images/create")
+}
+
+// Sync private image status from cloud.
+func (cli *PrivateImageClient) Sync(kt *kit.Kit, req *proto.PrivateImageSyncReq) error {
+	return common.RequestNoResp[proto.PrivateImageSyncReq](cli.client, http.MethodPost, kt, req,
+		"/private_images/sync")
+}
@@ -40,6 +40,9 @@ type Client struct {
 	NetworkInterface *NetworkInterfaceClient
 	Bill             *BillClient
 	MainAccount      *MainAccountClient
+	ResourceTag      *ResourceTagClient
+	DiskSnapshot     *DiskSnapshotClient
+	PrivateImage     *PrivateImageClient
 }
 
 // NewClient create a new gcp api client.
@@ -60,5 +63,8 @@ func NewClient(client rest.ClientInterface) *Client {
 		NetworkInterface: NewNetworkInterfaceClient(client),
 		Bill:             NewBillClient(client),
 		MainAccount:      NewMainAccountClient(client),
+		ResourceTag:      NewResourceTagClient(client),
+		DiskSnapshot:     NewDiskSnapshotClient(client),
+		PrivateImage:     NewPrivateImageClient(client),
 	}
 }
@@ -177,3 +177,49 @@ func (cli *CvmClient) BatchCreateCvm(kt *kit.Kit, request *protocvm.GcpBatchCrea
 
 	return resp.Data, nil
 }
+
+// SyncCvmStatus 按主机ID从云上刷新主机状态
+func (cli *CvmClient) SyncCvmStatus(kt *kit.Kit, request *protocvm.SyncCvmStatusReq) error {
+
+	resp := new(rest.BaseResp)
+
+	err := cli.client.Post().
+		WithContext(kt.Ctx).
+		Body(request).
+		SubResourcef("/cvms/status/sync").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// ResizeCvm 调整主机规格
+func (cli *CvmClient) ResizeCvm(kt *kit.Kit, request *protocvm.ResizeCvmReq) error {
+
+	resp := new(rest.BaseResp)
+
+	err := cli.client.Post().
+		WithContext(kt.Ctx).
+		Body(request).
+		SubResourcef("/cvms/resize").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
@@ -39,6 +39,10 @@ type Client struct {
 	InstanceType     *InstanceTypeClient
 	NetworkInterface *NetworkInterfaceClient
 	Bill             *BillClient
+	ResourceTag      *ResourceTagClient
+	Renewal          *RenewalClient
+	PrivateImage     *PrivateImageClient
+	DiskSnapshot     *DiskSnapshotClient
 }
 
 // NewClient create a new huawei api client.
@@ -58,5 +62,9 @@ func NewClient(client rest.ClientInterface) *Client {
 		InstanceType:     NewInstanceTypeClient(client),
 		NetworkInterface: NewNetworkInterfaceClient(client),
 		Bill:             NewBillClient(client),
+		ResourceTag:      NewResourceTagClient(client),
+		Renewal:          NewRenewalClient(client),
+		PrivateImage:     NewPrivateImageClient(client),
+		DiskSnapshot:     NewDiskSnapshotClient(client),
 	}
 }
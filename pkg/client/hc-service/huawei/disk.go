@@ -184,3 +184,25 @@ func (cli *DiskClient) InquiryPrice(kt *kit.Kit, request *disk.HuaWeiDiskCreateR
 
 	return resp.Data, nil
 }
+
+// ResizeDisk 扩容云硬盘
+func (cli *DiskClient) ResizeDisk(kt *kit.Kit, req *disk.DiskResizeReq) error {
+	resp := new(rest.BaseResp)
+
+	err := cli.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/disks/resize").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
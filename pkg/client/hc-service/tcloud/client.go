@@ -42,6 +42,10 @@ type Client struct {
 	Cert          *CertClient
 	Clb           *ClbClient
 	BandPkg       *BandwidthPackageClient
+	ResourceTag   *ResourceTagClient
+	Renewal       *RenewalClient
+	PrivateImage  *PrivateImageClient
+	DiskSnapshot  *DiskSnapshotClient
 }
 
 // NewClient create a new tcloud api client.
@@ -64,5 +68,9 @@ func NewClient(client rest.ClientInterface) *Client {
 		Cert:          NewCertClient(client),
 		Clb:           NewClbClient(client),
 		BandPkg:       NewBandPkgClient(client),
+		ResourceTag:   NewResourceTagClient(client),
+		Renewal:       NewRenewalClient(client),
+		PrivateImage:  NewPrivateImageClient(client),
+		DiskSnapshot:  NewDiskSnapshotClient(client),
 	}
 }
@@ -233,3 +233,76 @@ func (cli *CvmClient) InquiryPrice(kt *kit.Kit, request *protocvm.TCloudBatchCre
 
 	return resp.Data, nil
 }
+
+// SyncCvmStatus 按主机ID从云上刷新主机状态
+func (cli *CvmClient) SyncCvmStatus(kt *kit.Kit, request *protocvm.SyncCvmStatusReq) error {
+
+	resp := new(rest.BaseResp)
+
+	err := cli.client.Post().
+		WithContext(kt.Ctx).
+		Body(request).
+		SubResourcef("/cvms/status/sync").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// ResizeCvm 调整主机规格
+func (cli *CvmClient) ResizeCvm(kt *kit.Kit, request *protocvm.ResizeCvmReq) error {
+
+	resp := new(rest.BaseResp)
+
+	err := cli.client.Post().
+		WithContext(kt.Ctx).
+		Body(request).
+		SubResourcef("/cvms/resize").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// InquiryResizePrice 调整主机规格询价
+func (cli *CvmClient) InquiryResizePrice(kt *kit.Kit, request *protocvm.ResizeCvmReq) (
+	*typecvm.InquiryPriceResult, error) {
+
+	resp := &struct {
+		*rest.BaseResp `json:",inline"`
+		Data           *typecvm.InquiryPriceResult `json:"data"`
+	}{}
+
+	err := cli.client.Post().
+		WithContext(kt.Ctx).
+		Body(request).
+		SubResourcef("/cvms/resize/prices/inquiry").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
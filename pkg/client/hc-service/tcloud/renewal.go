@@ -0,0 +1,53 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"net/http"
+
+	proto "hcm/pkg/api/hc-service/renewal"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewRenewalClient create a new prepaid resource renewal api client.
+func NewRenewalClient(client rest.ClientInterface) *RenewalClient {
+	return &RenewalClient{
+		client: client,
+	}
+}
+
+// RenewalClient is hc service prepaid resource renewal api client.
+type RenewalClient struct {
+	client rest.ClientInterface
+}
+
+// Renew prepaid resources.
+func (cli *RenewalClient) Renew(kt *kit.Kit, req *proto.ResRenewReq) (*proto.BatchResult, error) {
+	return common.Request[proto.ResRenewReq, proto.BatchResult](cli.client, http.MethodPost, kt, req,
+		"/prepaid_resources/renew")
+}
+
+// SetAutoRenew enable or disable auto renew of prepaid resources.
+func (cli *RenewalClient) SetAutoRenew(kt *kit.Kit, req *proto.ResAutoRenewSetReq) (*proto.BatchResult, error) {
+	return common.Request[proto.ResAutoRenewSetReq, proto.BatchResult](cli.client, http.MethodPost, kt, req,
+		"/prepaid_resources/auto_renew/set")
+}
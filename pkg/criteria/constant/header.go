@@ -46,4 +46,7 @@ const (
 
 	// BKGWAuthKey is blueking api gateway authorization header key.
 	BKGWAuthKey = "X-Bkapi-Authorization"
+
+	// AuthCacheBypassKey is the header key to bypass auth-server's authorize decision cache, used for debugging.
+	AuthCacheBypassKey = "X-Bkhcm-Auth-Cache-Bypass"
 )
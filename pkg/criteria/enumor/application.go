@@ -49,6 +49,9 @@ func (a ApplicationType) Validate() error {
 	case UpdateMainAccount:
 
 	case CreateLoadBalancer:
+
+	case ResizeCvm:
+	case ResizeDisk:
 	default:
 		return fmt.Errorf("unsupported application type: %s", a)
 	}
@@ -71,6 +74,10 @@ const (
 	UpdateMainAccount ApplicationType = "update_main_account"
 	// CreateLoadBalancer 创建负载均衡
 	CreateLoadBalancer ApplicationType = "create_load_balancer"
+	// ResizeCvm 调整主机规格
+	ResizeCvm ApplicationType = "resize_cvm"
+	// ResizeDisk 扩容云盘
+	ResizeDisk ApplicationType = "resize_disk"
 
 	// CreateSecurityGroup 创建安全组
 	CreateSecurityGroup ApplicationType = "create_security_group"
@@ -119,4 +126,8 @@ type ApplicationSource string
 const (
 	// ApplicationSourceITSM itsm 单据
 	ApplicationSourceITSM ApplicationSource = "itsm"
+	// ApplicationSourceNative 内置审批引擎单据
+	ApplicationSourceNative ApplicationSource = "native"
+	// ApplicationSourcePolicy 由申请策略自动审批或驳回的单据
+	ApplicationSourcePolicy ApplicationSource = "policy"
 )
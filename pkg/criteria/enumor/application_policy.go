@@ -0,0 +1,91 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// ApplicationPolicyEffect 申请策略的效果
+type ApplicationPolicyEffect string
+
+const (
+	// RequirePolicyEffect 申请单必须满足策略的全部条件，否则驳回
+	RequirePolicyEffect ApplicationPolicyEffect = "require"
+	// AutoApprovePolicyEffect 申请单满足策略的全部条件时免审批直接交付
+	AutoApprovePolicyEffect ApplicationPolicyEffect = "auto_approve"
+)
+
+// Validate ApplicationPolicyEffect.
+func (e ApplicationPolicyEffect) Validate() error {
+	switch e {
+	case RequirePolicyEffect, AutoApprovePolicyEffect:
+	default:
+		return fmt.Errorf("unsupported application policy effect: %s", e)
+	}
+
+	return nil
+}
+
+// PolicyConditionOp 申请策略条件的操作符
+type PolicyConditionOp string
+
+const (
+	// PolicyOpEqual 等于
+	PolicyOpEqual PolicyConditionOp = "eq"
+	// PolicyOpNotEqual 不等于
+	PolicyOpNotEqual PolicyConditionOp = "neq"
+	// PolicyOpGreaterThan 大于
+	PolicyOpGreaterThan PolicyConditionOp = "gt"
+	// PolicyOpGreaterThanEqual 大于等于
+	PolicyOpGreaterThanEqual PolicyConditionOp = "gte"
+	// PolicyOpLessThan 小于
+	PolicyOpLessThan PolicyConditionOp = "lt"
+	// PolicyOpLessThanEqual 小于等于
+	PolicyOpLessThanEqual PolicyConditionOp = "lte"
+	// PolicyOpIn 在给定的列表中
+	PolicyOpIn PolicyConditionOp = "in"
+	// PolicyOpNotIn 不在给定的列表中
+	PolicyOpNotIn PolicyConditionOp = "nin"
+	// PolicyOpExists 字段存在且不为空
+	PolicyOpExists PolicyConditionOp = "exists"
+)
+
+// Validate PolicyConditionOp.
+func (op PolicyConditionOp) Validate() error {
+	switch op {
+	case PolicyOpEqual, PolicyOpNotEqual, PolicyOpGreaterThan, PolicyOpGreaterThanEqual, PolicyOpLessThan,
+		PolicyOpLessThanEqual, PolicyOpIn, PolicyOpNotIn, PolicyOpExists:
+	default:
+		return fmt.Errorf("unsupported policy condition op: %s", op)
+	}
+
+	return nil
+}
+
+// PolicyEvaluationResult 申请策略评估结果
+type PolicyEvaluationResult string
+
+const (
+	// PolicyEvaluationPass 未违反策略，按审批流程审批
+	PolicyEvaluationPass PolicyEvaluationResult = "pass"
+	// PolicyEvaluationDeny 违反策略，直接驳回
+	PolicyEvaluationDeny PolicyEvaluationResult = "deny"
+	// PolicyEvaluationAutoApprove 满足自动审批策略，免审批直接交付
+	PolicyEvaluationAutoApprove PolicyEvaluationResult = "auto_approve"
+)
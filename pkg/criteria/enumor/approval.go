@@ -0,0 +1,96 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// ApprovalEngine 申请单审批引擎
+type ApprovalEngine string
+
+const (
+	// ItsmApprovalEngine 使用蓝鲸ITSM进行审批
+	ItsmApprovalEngine ApprovalEngine = "itsm"
+	// NativeApprovalEngine 使用hcm内置审批引擎进行审批
+	NativeApprovalEngine ApprovalEngine = "native"
+)
+
+// Validate ApprovalEngine.
+func (e ApprovalEngine) Validate() error {
+	switch e {
+	case ItsmApprovalEngine, NativeApprovalEngine:
+	default:
+		return fmt.Errorf("unsupported approval engine: %s", e)
+	}
+
+	return nil
+}
+
+// ApproverType 内置审批引擎的审批人类型
+type ApproverType string
+
+const (
+	// PlatformManagerApprover 平台管理员，即审批流程中配置的managers
+	PlatformManagerApprover ApproverType = "platform_manager"
+	// BizManagerApprover 业务运维人员，来自CMDB业务的运维人员
+	BizManagerApprover ApproverType = "biz_manager"
+	// AccountManagerApprover 云账号负责人
+	AccountManagerApprover ApproverType = "account_manager"
+	// StaticUserApprover 指定的审批人
+	StaticUserApprover ApproverType = "static_user"
+)
+
+// Validate ApproverType.
+func (t ApproverType) Validate() error {
+	switch t {
+	case PlatformManagerApprover, BizManagerApprover, AccountManagerApprover, StaticUserApprover:
+	default:
+		return fmt.Errorf("unsupported approver type: %s", t)
+	}
+
+	return nil
+}
+
+// ApprovalAction 内置审批引擎的审批动作
+type ApprovalAction string
+
+const (
+	// ApproveAction 审批通过
+	ApproveAction ApprovalAction = "approve"
+	// RejectAction 审批驳回
+	RejectAction ApprovalAction = "reject"
+	// CommentAction 评论
+	CommentAction ApprovalAction = "comment"
+	// EscalateAction 审批超时升级
+	EscalateAction ApprovalAction = "escalate"
+)
+
+// ApprovalStageStatus 内置审批引擎的审批阶段状态
+type ApprovalStageStatus string
+
+const (
+	// WaitingApprovalStage 未开始
+	WaitingApprovalStage ApprovalStageStatus = "waiting"
+	// PendingApprovalStage 审批中
+	PendingApprovalStage ApprovalStageStatus = "pending"
+	// PassApprovalStage 已通过
+	PassApprovalStage ApprovalStageStatus = "pass"
+	// RejectedApprovalStage 已驳回
+	RejectedApprovalStage ApprovalStageStatus = "rejected"
+)
@@ -46,13 +46,28 @@ var defaultFlowNameMap = map[FlowName]struct{}{
 	FlowRebootCvm:              {},
 	FlowDeleteCvm:              {},
 	FlowCreateCvm:              {},
+	FlowRollingStartCvm:        {},
+	FlowRollingStopCvm:         {},
+	FlowRollingRebootCvm:       {},
+	FlowRollingDeleteCvm:       {},
+	FlowResizeCvm:              {},
+	FlowResizeDisk:             {},
 	FlowDeleteFirewallRule:     {},
 	FlowDeleteSubnet:           {},
 	FlowNormalTest:             {},
 	FlowSleepTest:              {},
 	FlowDeleteSecurityGroup:    {},
 	FlowCreateHuaweiSGRule:     {},
+	FlowApplySGRuleTemplate:    {},
 	FlowDeleteEIP:              {},
+	FlowAddResourceTags:        {},
+	FlowRenewPrepaidRes:        {},
+	FlowSetPrepaidResAutoRenew: {},
+	FlowCreatePrivateImage:     {},
+	FlowCopyPrivateImage:       {},
+	FlowCreateDiskSnapshot:     {},
+	FlowDeleteDiskSnapshot:     {},
+	FlowExecDiskSnapshotPolicy: {},
 	FlowPullRawBill:            {},
 	FlowSplitBill:              {},
 	FlowBillDailySummary:       {},
@@ -99,6 +114,26 @@ const (
 	FlowCreateCvm FlowName = "create_cvm"
 )
 
+// 主机滚动操作相关Flow，按批次执行主机操作，每批次之后经过健康检查才继续下一批次
+const (
+	// FlowRollingStartCvm 滚动开机
+	FlowRollingStartCvm FlowName = "rolling_start_cvm"
+	// FlowRollingStopCvm 滚动关机
+	FlowRollingStopCvm FlowName = "rolling_stop_cvm"
+	// FlowRollingRebootCvm 滚动重启
+	FlowRollingRebootCvm FlowName = "rolling_reboot_cvm"
+	// FlowRollingDeleteCvm 滚动删除
+	FlowRollingDeleteCvm FlowName = "rolling_delete_cvm"
+)
+
+// 主机、云硬盘变配相关Flow
+const (
+	// FlowResizeCvm 调整主机规格，运行中的主机按关机、调整规格、开机的顺序执行
+	FlowResizeCvm FlowName = "resize_cvm"
+	// FlowResizeDisk 扩容云硬盘
+	FlowResizeDisk FlowName = "resize_disk"
+)
+
 // 防火墙相关Flow
 const (
 	FlowDeleteFirewallRule FlowName = "delete_firewall_rule"
@@ -121,6 +156,7 @@ const (
 const (
 	FlowDeleteSecurityGroup FlowName = "delete_security_group"
 	FlowCreateHuaweiSGRule  FlowName = "create_huawei_sg_rule"
+	FlowApplySGRuleTemplate FlowName = "apply_sg_rule_template"
 )
 
 // EIP 相关Flow
@@ -129,6 +165,38 @@ const (
 	FlowDeleteEIP FlowName = "delete_eip"
 )
 
+// 资源标签相关Flow
+const (
+	// FlowAddResourceTags 为资源添加标签，如标签策略的自动补齐标签
+	FlowAddResourceTags FlowName = "add_resource_tags"
+)
+
+// 包年包月资源续费相关Flow
+const (
+	// FlowRenewPrepaidRes 续费包年包月资源
+	FlowRenewPrepaidRes FlowName = "renew_prepaid_res"
+	// FlowSetPrepaidResAutoRenew 设置包年包月资源的自动续费
+	FlowSetPrepaidResAutoRenew FlowName = "set_prepaid_res_auto_renew"
+)
+
+// 私有镜像相关Flow
+const (
+	// FlowCreatePrivateImage 基于主机创建私有镜像
+	FlowCreatePrivateImage FlowName = "create_private_image"
+	// FlowCopyPrivateImage 复制私有镜像到其他地域
+	FlowCopyPrivateImage FlowName = "copy_private_image"
+)
+
+// 云硬盘快照相关Flow
+const (
+	// FlowCreateDiskSnapshot 创建云硬盘快照
+	FlowCreateDiskSnapshot FlowName = "create_disk_snapshot"
+	// FlowDeleteDiskSnapshot 删除云硬盘快照
+	FlowDeleteDiskSnapshot FlowName = "delete_disk_snapshot"
+	// FlowExecDiskSnapshotPolicy 执行定期快照策略，为策略下的云硬盘创建快照
+	FlowExecDiskSnapshotPolicy FlowName = "exec_disk_snapshot_policy"
+)
+
 // Flow 相关Flow
 const (
 	// FlowLoadBalancerOperateWatch 负载均衡操作查询
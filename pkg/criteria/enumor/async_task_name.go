@@ -29,12 +29,18 @@ func (v ActionName) Validate() error {
 	switch v {
 	case ActionAssignCvm, ActionStartCvm, ActionStopCvm, ActionRebootCvm, ActionDeleteCvm, ActionCreateCvm,
 		ActionCreateAwsCvm, ActionCreateHuaWeiCvm, ActionCreateGcpCvm, ActionCreateAzureCvm:
+	case ActionRollingCvmGate:
+	case ActionResizeCvm, ActionResizeDisk:
 
 	case ActionDeleteFirewallRule:
 
 	case ActionDeleteSubnet:
-	case ActionDeleteSecurityGroup, ActionCreateHuaweiSGRule:
+	case ActionDeleteSecurityGroup, ActionCreateHuaweiSGRule, ActionApplySGRuleTemplate:
 	case ActionDeleteEIP:
+	case ActionAddResourceTags:
+	case ActionRenewPrepaidRes, ActionSetPrepaidResAutoRenew:
+	case ActionCreatePrivateImage, ActionCopyPrivateImage:
+	case ActionCreateDiskSnapshot, ActionDeleteDiskSnapshot:
 
 	case VirRoot:
 	case ActionCreateFactoryTest, ActionProduceTest, ActionAssembleTest, ActionSleep:
@@ -65,6 +71,20 @@ const (
 	ActionCreateAzureCvm  ActionName = "create_azure_cvm"
 )
 
+// 主机滚动操作相关Action
+const (
+	// ActionRollingCvmGate 滚动操作批次间的健康检查，未通过的主机数超过失败阈值时终止后续批次
+	ActionRollingCvmGate ActionName = "rolling_cvm_gate"
+)
+
+// 主机、云硬盘变配相关Action
+const (
+	// ActionResizeCvm 调整主机规格
+	ActionResizeCvm ActionName = "resize_cvm"
+	// ActionResizeDisk 扩容云硬盘
+	ActionResizeDisk ActionName = "resize_disk"
+)
+
 // 防火墙相关Action
 const (
 	ActionDeleteFirewallRule ActionName = "delete_firewall_rule"
@@ -91,6 +111,7 @@ const (
 const (
 	ActionDeleteSecurityGroup ActionName = "delete_security_group"
 	ActionCreateHuaweiSGRule  ActionName = "create_huawei_sg_rule"
+	ActionApplySGRuleTemplate ActionName = "apply_sg_rule_template"
 )
 
 // EIP related action
@@ -99,6 +120,36 @@ const (
 	ActionDeleteEIP ActionName = "delete_eip"
 )
 
+// 资源标签相关Action
+const (
+	// ActionAddResourceTags 为资源添加标签
+	ActionAddResourceTags ActionName = "add_resource_tags"
+)
+
+// 包年包月资源续费相关Action
+const (
+	// ActionRenewPrepaidRes 续费包年包月资源
+	ActionRenewPrepaidRes ActionName = "renew_prepaid_res"
+	// ActionSetPrepaidResAutoRenew 设置包年包月资源的自动续费
+	ActionSetPrepaidResAutoRenew ActionName = "set_prepaid_res_auto_renew"
+)
+
+// 私有镜像相关Action
+const (
+	// ActionCreatePrivateImage 基于主机创建私有镜像
+	ActionCreatePrivateImage ActionName = "create_private_image"
+	// ActionCopyPrivateImage 复制私有镜像到其他地域
+	ActionCopyPrivateImage ActionName = "copy_private_image"
+)
+
+// 云硬盘快照相关Action
+const (
+	// ActionCreateDiskSnapshot 创建云硬盘快照
+	ActionCreateDiskSnapshot ActionName = "create_disk_snapshot"
+	// ActionDeleteDiskSnapshot 删除云硬盘快照
+	ActionDeleteDiskSnapshot ActionName = "delete_disk_snapshot"
+)
+
 // Flow相关Action
 const (
 	ActionLoadBalancerOperateWatch ActionName = "load_balancer_operate_watch"
@@ -50,6 +50,7 @@ const (
 	UrlRuleDomainAuditResType     AuditResourceType = "url_rule_domain"
 	MainAccountAuditResType       AuditResourceType = "main_account"
 	RootAccountAuditResType       AuditResourceType = "root_account"
+	BandwidthPackageAuditResType  AuditResourceType = "bandwidth_package"
 )
 
 // AuditResourceTypeEnums resource type map.
@@ -75,6 +76,7 @@ var AuditResourceTypeEnums = map[AuditResourceType]struct{}{
 	UrlRuleDomainAuditResType:     {},
 	MainAccountAuditResType:       {},
 	RootAccountAuditResType:       {},
+	BandwidthPackageAuditResType:  {},
 }
 
 // Exist judge enum value exist.
@@ -115,24 +117,45 @@ const (
 	Bind AuditAction = "bind"
 	// Deliver 交付
 	Deliver AuditAction = "deliver"
+	// Renew 续费
+	Renew AuditAction = "renew"
+	// SetAutoRenew 设置自动续费
+	SetAutoRenew AuditAction = "set_auto_renew"
+	// CreateImage 基于主机创建镜像
+	CreateImage AuditAction = "create_image"
+	// CreateSnapshot 创建云硬盘快照
+	CreateSnapshot AuditAction = "create_snapshot"
+	// DeleteSnapshot 删除云硬盘快照
+	DeleteSnapshot AuditAction = "delete_snapshot"
+	// RollbackSnapshot 回滚云硬盘快照
+	RollbackSnapshot AuditAction = "rollback_snapshot"
+	// Resize 调整主机规格、扩容云硬盘
+	Resize AuditAction = "resize"
 )
 
 // AuditActionEnums op type map.
 var AuditActionEnums = map[AuditAction]struct{}{
-	Create:       {},
-	Update:       {},
-	Delete:       {},
-	Assign:       {},
-	Recycle:      {},
-	Recover:      {},
-	Reboot:       {},
-	Start:        {},
-	Stop:         {},
-	ResetPwd:     {},
-	Associate:    {},
-	Disassociate: {},
-	Bind:         {},
-	Deliver:      {},
+	Create:           {},
+	Update:           {},
+	Delete:           {},
+	Assign:           {},
+	Recycle:          {},
+	Recover:          {},
+	Reboot:           {},
+	Start:            {},
+	Stop:             {},
+	ResetPwd:         {},
+	Associate:        {},
+	Disassociate:     {},
+	Bind:             {},
+	Deliver:          {},
+	Renew:            {},
+	SetAutoRenew:     {},
+	CreateImage:      {},
+	CreateSnapshot:   {},
+	DeleteSnapshot:   {},
+	RollbackSnapshot: {},
+	Resize:           {},
 }
 
 // Exist judge enum value exist.
@@ -163,3 +186,27 @@ func (a AuditAssignedResType) Exist() bool {
 	_, exist := AuditAssignedResTypeEnums[a]
 	return exist
 }
+
+// AuditChainIssueType is the issue type found when verifying the audit hash chain.
+type AuditChainIssueType string
+
+const (
+	// AuditChainGap 审计记录序号不连续，中间的审计记录被删除
+	AuditChainGap AuditChainIssueType = "gap"
+	// AuditChainModified 审计记录内容与哈希不一致，审计记录被修改
+	AuditChainModified AuditChainIssueType = "modified"
+	// AuditChainBrokenLink 审计记录的前序哈希与上一条审计记录的哈希不一致
+	AuditChainBrokenLink AuditChainIssueType = "broken_link"
+	// AuditChainTailMissing 哈希链末尾的审计记录被删除
+	AuditChainTailMissing AuditChainIssueType = "tail_missing"
+)
+
+// AuditOutboxStatus is the delivery status of the audit outbox event.
+type AuditOutboxStatus string
+
+const (
+	// AuditOutboxPending 审计事件等待投递，投递失败时按退避时间重试
+	AuditOutboxPending AuditOutboxStatus = "pending"
+	// AuditOutboxDeadLetter 审计事件投递失败次数达到上限，不再重试，需要人工处理
+	AuditOutboxDeadLetter AuditOutboxStatus = "dead_letter"
+)
@@ -0,0 +1,45 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// AuthPolicyScope is the resource scope of local rbac role policy.
+type AuthPolicyScope string
+
+const (
+	// AuthScopeAny 对该资源类型的所有实例生效，等同于iam中无限制条件的权限
+	AuthScopeAny AuthPolicyScope = "any"
+	// AuthScopeBiz 对指定业务下的资源生效，scope_id为业务ID
+	AuthScopeBiz AuthPolicyScope = "biz"
+	// AuthScopeResource 对指定的资源实例生效，scope_id为鉴权时传入的资源ID(如账号ID)
+	AuthScopeResource AuthPolicyScope = "resource"
+)
+
+// Validate AuthPolicyScope.
+func (s AuthPolicyScope) Validate() error {
+	switch s {
+	case AuthScopeAny, AuthScopeBiz, AuthScopeResource:
+	default:
+		return fmt.Errorf("unsupported auth policy scope: %s", s)
+	}
+
+	return nil
+}
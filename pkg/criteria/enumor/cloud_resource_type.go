@@ -48,6 +48,8 @@ var typeMapping = map[CloudResourceType]table.Name{
 	ListenerCloudResType:         table.LoadBalancerListenerTable,
 	TargetGroupCloudResType:      table.LoadBalancerTargetGroupTable,
 	TCLoudUrlRuleCloudResType:    table.TCloudLbUrlRuleTable,
+	PrivateImageCloudResType:     table.PrivateImageTable,
+	DiskSnapshotCloudResType:     table.DiskSnapshotTable,
 }
 
 // ConvTableName conv CloudResourceType to table.Name.
@@ -88,4 +90,7 @@ const (
 	ListenerCloudResType         CloudResourceType = "listener"
 	TargetGroupCloudResType      CloudResourceType = "target_group"
 	TCLoudUrlRuleCloudResType    CloudResourceType = "tcloud_url_rule"
+	PrivateImageCloudResType     CloudResourceType = "private_image"
+	DiskSnapshotCloudResType     CloudResourceType = "disk_snapshot"
+	BandwidthPackageCloudResType CloudResourceType = "bandwidth_package"
 )
@@ -0,0 +1,82 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// CvmLifecycleStatus 各云厂商主机状态归一化后的生命周期状态
+type CvmLifecycleStatus string
+
+// Validate CvmLifecycleStatus.
+func (s CvmLifecycleStatus) Validate() error {
+	switch s {
+	case CvmLifecyclePending, CvmLifecycleRunning, CvmLifecycleStopping, CvmLifecycleStopped,
+		CvmLifecycleRebooting, CvmLifecycleTerminating, CvmLifecycleTerminated, CvmLifecycleError,
+		CvmLifecycleUnknown:
+	default:
+		return fmt.Errorf("unsupported cvm lifecycle status: %s", s)
+	}
+
+	return nil
+}
+
+const (
+	// CvmLifecyclePending 创建中、开机中等过渡状态
+	CvmLifecyclePending CvmLifecycleStatus = "pending"
+	// CvmLifecycleRunning 运行中
+	CvmLifecycleRunning CvmLifecycleStatus = "running"
+	// CvmLifecycleStopping 关机中
+	CvmLifecycleStopping CvmLifecycleStatus = "stopping"
+	// CvmLifecycleStopped 已关机
+	CvmLifecycleStopped CvmLifecycleStatus = "stopped"
+	// CvmLifecycleRebooting 重启中
+	CvmLifecycleRebooting CvmLifecycleStatus = "rebooting"
+	// CvmLifecycleTerminating 销毁中
+	CvmLifecycleTerminating CvmLifecycleStatus = "terminating"
+	// CvmLifecycleTerminated 已销毁
+	CvmLifecycleTerminated CvmLifecycleStatus = "terminated"
+	// CvmLifecycleError 异常，如创建失败
+	CvmLifecycleError CvmLifecycleStatus = "error"
+	// CvmLifecycleUnknown 云上状态无法识别
+	CvmLifecycleUnknown CvmLifecycleStatus = "unknown"
+)
+
+// RollingGateType 主机滚动操作批次间的健康检查类型
+type RollingGateType string
+
+// Validate RollingGateType.
+func (t RollingGateType) Validate() error {
+	switch t {
+	case RollingGateNone, RollingGateCvmStatus, RollingGateLbTargetHealth:
+	default:
+		return fmt.Errorf("unsupported rolling gate type: %s", t)
+	}
+
+	return nil
+}
+
+const (
+	// RollingGateNone 不做健康检查，仅在批次间暂停
+	RollingGateNone RollingGateType = "none"
+	// RollingGateCvmStatus 检查本批次主机是否达到操作对应的生命周期状态
+	RollingGateCvmStatus RollingGateType = "cvm_status"
+	// RollingGateLbTargetHealth 检查目标组所绑定监听器下的后端服务健康状态，仅支持TCloud
+	RollingGateLbTargetHealth RollingGateType = "lb_target_health"
+)
@@ -39,3 +39,17 @@ const (
 	// DiskBindCvm disk bind cvm
 	DiskBindCvm DiskBindType = "CVM"
 )
+
+// DiskSnapshotStatus 云硬盘快照归一化后的状态
+type DiskSnapshotStatus string
+
+const (
+	// DiskSnapshotCreating 创建中等过渡状态
+	DiskSnapshotCreating DiskSnapshotStatus = "creating"
+	// DiskSnapshotNormal 可用
+	DiskSnapshotNormal DiskSnapshotStatus = "normal"
+	// DiskSnapshotFailed 创建失败
+	DiskSnapshotFailed DiskSnapshotStatus = "failed"
+	// DiskSnapshotUnknown 云上状态无法识别
+	DiskSnapshotUnknown DiskSnapshotStatus = "unknown"
+)
@@ -27,3 +27,17 @@ const (
 	WindowsOsType OsType = "Windows"
 	OtherOsType   OsType = "Other"
 )
+
+// PrivateImageStatus 私有镜像归一化后的状态
+type PrivateImageStatus string
+
+const (
+	// PrivateImageCreating 创建中、复制中等过渡状态
+	PrivateImageCreating PrivateImageStatus = "creating"
+	// PrivateImageNormal 可用
+	PrivateImageNormal PrivateImageStatus = "normal"
+	// PrivateImageFailed 创建或复制失败
+	PrivateImageFailed PrivateImageStatus = "failed"
+	// PrivateImageUnknown 云上状态无法识别
+	PrivateImageUnknown PrivateImageStatus = "unknown"
+)
@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// IpamAllocationType is ipam cidr allocation type.
+type IpamAllocationType string
+
+const (
+	// IpamAllocation 从地址池中分配给待创建的vpc、子网使用的网段
+	IpamAllocation IpamAllocationType = "allocation"
+	// IpamReservation 预留网段，不会被分配，如用于IDC或专线互通的网段
+	IpamReservation IpamAllocationType = "reservation"
+)
+
+// Validate IpamAllocationType.
+func (t IpamAllocationType) Validate() error {
+	switch t {
+	case IpamAllocation, IpamReservation:
+	default:
+		return fmt.Errorf("unsupported ipam allocation type: %s", t)
+	}
+
+	return nil
+}
+
+// IpamCidrUsage is the usage of the cidr which is occupied.
+type IpamCidrUsage string
+
+const (
+	// IpamUsageVpc 已同步的vpc网段
+	IpamUsageVpc IpamCidrUsage = "vpc"
+	// IpamUsageSubnet 已同步的子网网段
+	IpamUsageSubnet IpamCidrUsage = "subnet"
+	// IpamUsageAllocation ipam分配记录占用的网段
+	IpamUsageAllocation IpamCidrUsage = "allocation"
+	// IpamUsageReservation ipam预留的网段
+	IpamUsageReservation IpamCidrUsage = "reservation"
+)
@@ -19,6 +19,8 @@
 
 package enumor
 
+import "fmt"
+
 const (
 	// RecycleStatus is a special status indicating that resource is recycling
 	RecycleStatus = "recycling"
@@ -42,8 +44,13 @@ const (
 
 // RecycleAuditResTypeMap recycle resource audit type to cloud resource type map.
 var RecycleAuditResTypeMap = map[AuditResourceType]CloudResourceType{
-	CvmAuditResType:  CvmCloudResType,
-	DiskAuditResType: DiskCloudResType,
+	CvmAuditResType:           CvmCloudResType,
+	DiskAuditResType:          DiskCloudResType,
+	EipAuditResType:           EipCloudResType,
+	SecurityGroupAuditResType: SecurityGroupCloudResType,
+	LoadBalancerAuditResType:  LoadBalancerCloudResType,
+	VpcCloudAuditResType:      VpcCloudResType,
+	SubnetAuditResType:        SubnetCloudResType,
 }
 
 // RecycleType 回收类型
@@ -57,3 +64,68 @@ const (
 	// 目前主要用于标识disk作为关联资源随cvm回收的类型。
 	RecycleTypeRelated RecycleType = "related"
 )
+
+// RecyclePreDestroyAction 回收资源销毁前的动作
+type RecyclePreDestroyAction string
+
+const (
+	// NoneRecyclePreDestroyAction 销毁前不做任何处理
+	NoneRecyclePreDestroyAction RecyclePreDestroyAction = ""
+	// SnapshotRecyclePreDestroyAction 销毁前为硬盘创建快照，仅支持硬盘
+	SnapshotRecyclePreDestroyAction RecyclePreDestroyAction = "snapshot"
+	// ImageRecyclePreDestroyAction 销毁前为主机创建镜像，仅支持主机
+	ImageRecyclePreDestroyAction RecyclePreDestroyAction = "image"
+)
+
+// recyclePreDestroyActionResTypes the resource types supported by the pre-destroy action.
+var recyclePreDestroyActionResTypes = map[RecyclePreDestroyAction]CloudResourceType{
+	SnapshotRecyclePreDestroyAction: DiskCloudResType,
+	ImageRecyclePreDestroyAction:    CvmCloudResType,
+}
+
+// IsRecyclePreDestroySupported returns whether the resource type supports any pre-destroy action.
+func IsRecyclePreDestroySupported(resType CloudResourceType) bool {
+	for _, supported := range recyclePreDestroyActionResTypes {
+		if supported == resType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate the pre-destroy action is supported by the resource type.
+func (a RecyclePreDestroyAction) Validate(resType CloudResourceType) error {
+	if a == NoneRecyclePreDestroyAction {
+		return nil
+	}
+
+	supported, exists := recyclePreDestroyActionResTypes[a]
+	if !exists {
+		return fmt.Errorf("unsupported recycle pre-destroy action: %s", a)
+	}
+
+	if supported != resType {
+		return fmt.Errorf("recycle pre-destroy action %s only supports %s", a, supported)
+	}
+
+	return nil
+}
+
+// RecycleTimelineStage 回收记录时间线的阶段
+type RecycleTimelineStage string
+
+const (
+	// ScheduledRecycleTimelineStage 资源放入回收站，等待销毁
+	ScheduledRecycleTimelineStage RecycleTimelineStage = "scheduled"
+	// SnapshottingRecycleTimelineStage 执行销毁前动作，创建快照或镜像
+	SnapshottingRecycleTimelineStage RecycleTimelineStage = "snapshotting"
+	// SnapshottedRecycleTimelineStage 销毁前创建的快照或镜像已可用，等待销毁
+	SnapshottedRecycleTimelineStage RecycleTimelineStage = "snapshotted"
+	// DestroyedRecycleTimelineStage 资源已销毁
+	DestroyedRecycleTimelineStage RecycleTimelineStage = "destroyed"
+	// RecoveredRecycleTimelineStage 资源已恢复
+	RecoveredRecycleTimelineStage RecycleTimelineStage = "recovered"
+	// FailedRecycleTimelineStage 回收失败
+	FailedRecycleTimelineStage RecycleTimelineStage = "failed"
+)
@@ -0,0 +1,154 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// SecurityGroupRuleAction is vendor neutral security group rule action.
+type SecurityGroupRuleAction string
+
+const (
+	// SGRuleAllow allow the matched traffic.
+	SGRuleAllow SecurityGroupRuleAction = "allow"
+	// SGRuleDeny deny the matched traffic.
+	SGRuleDeny SecurityGroupRuleAction = "deny"
+)
+
+// Validate SecurityGroupRuleAction.
+func (a SecurityGroupRuleAction) Validate() error {
+	switch a {
+	case SGRuleAllow, SGRuleDeny:
+	default:
+		return fmt.Errorf("unsupported security group rule action: %s", a)
+	}
+
+	return nil
+}
+
+// SGRuleAnalysisStatus is security group rule analysis status.
+type SGRuleAnalysisStatus string
+
+const (
+	// SGRuleEffective 规则会被命中，对最终结果有影响
+	SGRuleEffective SGRuleAnalysisStatus = "effective"
+	// SGRuleShadowed 规则被优先级更高且动作相反的规则完全覆盖，永远不会被命中
+	SGRuleShadowed SGRuleAnalysisStatus = "shadowed"
+	// SGRuleRedundant 规则被动作相同的其他规则完全覆盖，删除后不影响最终结果
+	SGRuleRedundant SGRuleAnalysisStatus = "redundant"
+)
+
+// SGRiskSeverity is security group risk severity.
+type SGRiskSeverity string
+
+const (
+	// SGRiskHigh 高危
+	SGRiskHigh SGRiskSeverity = "high"
+	// SGRiskMedium 中危
+	SGRiskMedium SGRiskSeverity = "medium"
+	// SGRiskLow 低危
+	SGRiskLow SGRiskSeverity = "low"
+)
+
+// Validate SGRiskSeverity.
+func (s SGRiskSeverity) Validate() error {
+	switch s {
+	case SGRiskHigh, SGRiskMedium, SGRiskLow:
+	default:
+		return fmt.Errorf("unsupported security group risk severity: %s", s)
+	}
+
+	return nil
+}
+
+// Level return the severity level, the higher the level, the more dangerous.
+func (s SGRiskSeverity) Level() int {
+	switch s {
+	case SGRiskHigh:
+		return 3
+	case SGRiskMedium:
+		return 2
+	case SGRiskLow:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// SGRiskCheckType is security group risk check type.
+type SGRiskCheckType string
+
+const (
+	// SGRiskPublicPort 对公网(0.0.0.0/0、::/0)开放了指定端口
+	SGRiskPublicPort SGRiskCheckType = "public_port"
+	// SGRiskWidePortRange 入站允许规则放通的端口范围过大
+	SGRiskWidePortRange SGRiskCheckType = "wide_port_range"
+	// SGRiskUnattached 安全组未关联任何资源
+	SGRiskUnattached SGRiskCheckType = "unattached"
+	// SGRiskEmpty 安全组没有任何规则
+	SGRiskEmpty SGRiskCheckType = "empty"
+)
+
+// Validate SGRiskCheckType.
+func (t SGRiskCheckType) Validate() error {
+	switch t {
+	case SGRiskPublicPort, SGRiskWidePortRange, SGRiskUnattached, SGRiskEmpty:
+	default:
+		return fmt.Errorf("unsupported security group risk check type: %s", t)
+	}
+
+	return nil
+}
+
+// SGRuleTplApplyState is security group rule template apply state.
+type SGRuleTplApplyState string
+
+const (
+	// SGRuleTplApplyPending 等待下发
+	SGRuleTplApplyPending SGRuleTplApplyState = "pending"
+	// SGRuleTplApplySuccess 下发成功
+	SGRuleTplApplySuccess SGRuleTplApplyState = "success"
+	// SGRuleTplApplyFailed 下发失败
+	SGRuleTplApplyFailed SGRuleTplApplyState = "failed"
+)
+
+// Validate SGRuleTplApplyState.
+func (s SGRuleTplApplyState) Validate() error {
+	switch s {
+	case SGRuleTplApplyPending, SGRuleTplApplySuccess, SGRuleTplApplyFailed:
+	default:
+		return fmt.Errorf("unsupported security group rule template apply state: %s", s)
+	}
+
+	return nil
+}
+
+// SGRuleTplDriftStatus is the drift status of the security group which the rule template applied to.
+type SGRuleTplDriftStatus string
+
+const (
+	// SGRuleTplInSync 安全组规则与模版当前版本一致
+	SGRuleTplInSync SGRuleTplDriftStatus = "in_sync"
+	// SGRuleTplOutdated 模版已更新，安全组下发的是旧版本
+	SGRuleTplOutdated SGRuleTplDriftStatus = "outdated"
+	// SGRuleTplDrifted 安全组中的规则被修改或删除，不再满足模版
+	SGRuleTplDrifted SGRuleTplDriftStatus = "drifted"
+	// SGRuleTplNotApplied 模版尚未下发成功
+	SGRuleTplNotApplied SGRuleTplDriftStatus = "not_applied"
+)
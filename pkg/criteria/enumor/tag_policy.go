@@ -0,0 +1,64 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// TagViolationType is the violation type of the tag policy rule.
+type TagViolationType string
+
+const (
+	// TagViolationMissing 资源缺少策略要求的标签键
+	TagViolationMissing TagViolationType = "missing"
+	// TagViolationInvalidValue 资源的标签值不在策略允许的取值范围内
+	TagViolationInvalidValue TagViolationType = "invalid_value"
+)
+
+// Validate TagViolationType.
+func (t TagViolationType) Validate() error {
+	switch t {
+	case TagViolationMissing, TagViolationInvalidValue:
+	default:
+		return fmt.Errorf("unsupported tag violation type: %s", t)
+	}
+
+	return nil
+}
+
+// TagComplianceGroupBy is the dimension to aggregate the tag compliance statistics.
+type TagComplianceGroupBy string
+
+const (
+	// TagComplianceGroupByBiz 按业务统计标签合规率
+	TagComplianceGroupByBiz TagComplianceGroupBy = "bk_biz_id"
+	// TagComplianceGroupByAccount 按账号统计标签合规率
+	TagComplianceGroupByAccount TagComplianceGroupBy = "account_id"
+)
+
+// Validate TagComplianceGroupBy.
+func (g TagComplianceGroupBy) Validate() error {
+	switch g {
+	case TagComplianceGroupByBiz, TagComplianceGroupByAccount:
+	default:
+		return fmt.Errorf("unsupported tag compliance group by: %s", g)
+	}
+
+	return nil
+}
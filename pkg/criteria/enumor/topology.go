@@ -0,0 +1,60 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// TopologyEdgeType is the relation type between two resources in network topology graph.
+type TopologyEdgeType string
+
+const (
+	// TopoVpcSubnet vpc -> subnet
+	TopoVpcSubnet TopologyEdgeType = "vpc_subnet"
+	// TopoVpcCvm vpc -> cvm
+	TopoVpcCvm TopologyEdgeType = "vpc_cvm"
+	// TopoSubnetCvm subnet -> cvm
+	TopoSubnetCvm TopologyEdgeType = "subnet_cvm"
+	// TopoVpcRouteTable vpc -> route table
+	TopoVpcRouteTable TopologyEdgeType = "vpc_route_table"
+	// TopoRouteTableSubnet route table -> subnet
+	TopoRouteTableSubnet TopologyEdgeType = "route_table_subnet"
+	// TopoVpcLoadBalancer vpc -> load balancer
+	TopoVpcLoadBalancer TopologyEdgeType = "vpc_load_balancer"
+	// TopoCvmNetworkInterface cvm -> network interface
+	TopoCvmNetworkInterface TopologyEdgeType = "cvm_network_interface"
+	// TopoSecurityGroupBinding security group -> cvm、subnet、load balancer and other bound resources
+	TopoSecurityGroupBinding TopologyEdgeType = "security_group_binding"
+	// TopoEipCvm eip -> cvm
+	TopoEipCvm TopologyEdgeType = "eip_cvm"
+	// TopoLoadBalancerTarget load balancer -> backend cvm
+	TopoLoadBalancerTarget TopologyEdgeType = "load_balancer_target"
+)
+
+// Validate TopologyEdgeType.
+func (t TopologyEdgeType) Validate() error {
+	switch t {
+	case TopoVpcSubnet, TopoVpcCvm, TopoSubnetCvm, TopoVpcRouteTable, TopoRouteTableSubnet, TopoVpcLoadBalancer,
+		TopoCvmNetworkInterface, TopoSecurityGroupBinding, TopoEipCvm, TopoLoadBalancerTarget:
+	default:
+		return fmt.Errorf("unsupported topology edge type: %s", t)
+	}
+
+	return nil
+}
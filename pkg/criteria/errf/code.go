@@ -72,4 +72,6 @@ const (
 	BillItemImportDataError int32 = 2000016
 	// BillItemImportEmptyDataError 账单导入空列表
 	BillItemImportEmptyDataError int32 = 2000017
+	// ApplicationPolicyDenied 申请单违反申请策略被驳回
+	ApplicationPolicyDenied int32 = 2000018
 )
@@ -43,6 +43,7 @@ import (
 type Application interface {
 	CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, model *application.ApplicationTable) (string, error)
 	Update(kt *kit.Kit, expr *filter.Expression, model *application.ApplicationTable) error
+	UpdateByCAS(kt *kit.Kit, expr *filter.Expression, model *application.ApplicationTable) error
 	List(kt *kit.Kit, opt *types.ListOption) (*types.ListApplicationDetails, error)
 }
 
@@ -81,6 +82,20 @@ func (a *ApplicationDao) CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, model *applicati
 
 // Update ...
 func (a *ApplicationDao) Update(kt *kit.Kit, filterExpr *filter.Expression, model *application.ApplicationTable) error {
+	return a.update(kt, filterExpr, model, false)
+}
+
+// UpdateByCAS update application only when it matches the filter, the filter contains the expected values of the
+// fields that may be changed concurrently, returns errf.RecordNotUpdate if no application is updated.
+func (a *ApplicationDao) UpdateByCAS(kt *kit.Kit, filterExpr *filter.Expression,
+	model *application.ApplicationTable) error {
+
+	return a.update(kt, filterExpr, model, true)
+}
+
+func (a *ApplicationDao) update(kt *kit.Kit, filterExpr *filter.Expression, model *application.ApplicationTable,
+	mustEffect bool) error {
+
 	if filterExpr == nil {
 		return errf.New(errf.InvalidParameter, "filter expr is nil")
 	}
@@ -110,6 +125,9 @@ func (a *ApplicationDao) Update(kt *kit.Kit, filterExpr *filter.Expression, mode
 		}
 
 		if effected == 0 {
+			if mustEffect {
+				return nil, errf.New(errf.RecordNotUpdate, "application has been changed or not found")
+			}
 			logs.ErrorJson("update application, but record not found, filter: %v, rid: %v", filterExpr, kt.Rid)
 			// return nil, errf.New(errf.RecordNotFound, orm.ErrRecordNotFound.Error())
 		}
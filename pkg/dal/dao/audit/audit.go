@@ -44,20 +44,32 @@ type Interface interface {
 	BatchCreate(kt *kit.Kit, audits []*audit.AuditTable) error
 	BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, audits []*audit.AuditTable) error
 	List(kt *kit.Kit, opt *types.ListOption) (*types.ListAuditDetails, error)
+	ListChain(kt *kit.Kit, opt *types.ListOption) (*types.ListAuditChainDetails, error)
+	ListChainAudits(kt *kit.Kit, chain string, afterSeq uint64, limit uint) ([]audit.AuditTable, error)
+	UpdateChainExport(kt *kit.Kit, chain, exportPath, exportedAt string) error
+	ListChainAuditsBySeq(kt *kit.Kit, chain string, seqs []uint64) ([]audit.AuditTable, error)
+	ListDueOutbox(kt *kit.Kit, now int64, limit uint) ([]audit.AuditOutboxTable, error)
+	DeleteOutbox(kt *kit.Kit, ids []uint64) error
+	DelayOutbox(kt *kit.Kit, delay *OutboxDelay) error
 }
 
 var _ Interface = new(Dao)
 
-// NewAudit new audit.
-func NewAudit(orm orm.Interface) Interface {
+// NewAudit new audit, if outbox is true, audit events are put into outbox to be delivered to the audit sinks.
+// the audits of a day are linked to chainShards hash chains, zero means one chain.
+func NewAudit(orm orm.Interface, outbox bool, chainShards uint) Interface {
 	return &Dao{
-		Orm: orm,
+		Orm:         orm,
+		Outbox:      outbox,
+		ChainShards: chainShards,
 	}
 }
 
 // Dao audit dao.
 type Dao struct {
-	Orm orm.Interface
+	Orm         orm.Interface
+	Outbox      bool
+	ChainShards uint
 }
 
 // Create audit.
@@ -67,21 +79,11 @@ func (d Dao) Create(kt *kit.Kit, one *audit.AuditTable) error {
 
 // BatchCreate batch create audit.
 func (d Dao) BatchCreate(kt *kit.Kit, audits []*audit.AuditTable) error {
-	for _, one := range audits {
-		if err := one.CreateValidate(); err != nil {
-			return err
-		}
-	}
-
-	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.AuditTable,
-		audit.AuditColumns.ColumnExpr(), audit.AuditColumns.ColonNameExpr())
-
-	if err := d.Orm.Do().BulkInsert(kt.Ctx, sql, audits); err != nil {
-		logs.Errorf("insert %s failed, err: %v, rid: %s", table.AuditTable, err, kt.Rid)
-		return fmt.Errorf("insert %s failed, err: %v", table.AuditTable, err)
-	}
-
-	return nil
+	// audits are linked to the hash chain, which need to be done in transaction.
+	_, err := d.Orm.AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, d.BatchCreateWithTx(kt, txn, audits)
+	})
+	return err
 }
 
 // BatchCreateWithTx batch create audit with tx.
@@ -92,6 +94,11 @@ func (d Dao) BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, audits []*audit.AuditTa
 		}
 	}
 
+	if err := d.linkChain(kt, tx, audits); err != nil {
+		logs.Errorf("link audits to hash chain failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
 	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.AuditTable,
 		audit.AuditColumns.ColumnExpr(), audit.AuditColumns.ColonNameExpr())
 
@@ -100,6 +107,12 @@ func (d Dao) BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, audits []*audit.AuditTa
 		return fmt.Errorf("insert %s failed, err: %v", table.AuditTable, err)
 	}
 
+	if d.Outbox {
+		if err := d.createOutboxWithTx(kt, tx, audits); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
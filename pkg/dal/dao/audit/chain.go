@@ -0,0 +1,220 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// linkChain links the audits to one of the shard hash chains of current date. the chain head is locked until the
+// transaction ends, so that the audits of a chain are strictly sequential even if they are created by multiple
+// instances, and the audits linked to different shards are created concurrently.
+func (d Dao) linkChain(kt *kit.Kit, tx *sqlx.Tx, audits []*audit.AuditTable) error {
+	if len(audits) == 0 {
+		return nil
+	}
+
+	chain := audit.ChainName(time.Now().Format(constant.DateLayout), chainShard(kt.Rid, d.ChainShards))
+	args := map[string]interface{}{"chain": chain}
+
+	initSql := fmt.Sprintf(`INSERT IGNORE INTO %s (chain, last_seq, last_hash) VALUES (:chain, 0, '')`,
+		table.AuditChainTable)
+	if err := d.Orm.Txn(tx).Insert(kt.Ctx, initSql, args); err != nil {
+		return fmt.Errorf("init audit chain %s failed, err: %v", chain, err)
+	}
+
+	lockSql := fmt.Sprintf(`SELECT %s FROM %s WHERE chain = :chain FOR UPDATE`,
+		audit.AuditChainColumns.FieldsNamedExpr(nil), table.AuditChainTable)
+	heads := make([]audit.AuditChainTable, 0)
+	if err := d.Orm.Txn(tx).Select(kt.Ctx, &heads, lockSql, args); err != nil {
+		return fmt.Errorf("lock audit chain %s failed, err: %v", chain, err)
+	}
+
+	if len(heads) != 1 {
+		return fmt.Errorf("audit chain %s not found", chain)
+	}
+
+	seq, prevHash := heads[0].LastSeq, heads[0].LastHash
+	for _, one := range audits {
+		seq++
+		one.Chain = chain
+		one.Seq = seq
+		one.PrevHash = prevHash
+
+		hash, err := one.ComputeHash()
+		if err != nil {
+			return fmt.Errorf("compute audit hash failed, err: %v", err)
+		}
+		one.Hash = hash
+		prevHash = hash
+	}
+
+	updateSql := fmt.Sprintf(`UPDATE %s SET last_seq = :last_seq, last_hash = :last_hash WHERE chain = :chain`,
+		table.AuditChainTable)
+	updateArgs := map[string]interface{}{"chain": chain, "last_seq": seq, "last_hash": prevHash}
+	if _, err := d.Orm.Txn(tx).Update(kt.Ctx, updateSql, updateArgs); err != nil {
+		return fmt.Errorf("update audit chain %s head failed, err: %v", chain, err)
+	}
+
+	return nil
+}
+
+// chainShard returns the shard that the audits of the request are linked to, the requests are spread over the
+// shards by request id.
+func chainShard(rid string, shards uint) uint {
+	if shards <= 1 {
+		return 0
+	}
+
+	if len(rid) == 0 {
+		return uint(rand.Intn(int(shards)))
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(rid))
+	return uint(h.Sum32()) % shards
+}
+
+// ListChain list audit chains.
+func (d Dao) ListChain(kt *kit.Kit, opt *types.ListOption) (*types.ListAuditChainDetails, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list options is nil")
+	}
+
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(audit.AuditChainColumns.ColumnTypes())),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.AuditChainTable, whereExpr)
+
+		count, err := d.Orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count audit chain failed, err: %v, filter: %s, rid: %s", err, opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &types.ListAuditChainDetails{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, &types.PageSQLOption{Sort: types.SortOption{Sort: "chain",
+		IfNotPresent: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, audit.AuditChainColumns.FieldsNamedExpr(opt.Fields),
+		table.AuditChainTable, whereExpr, pageExpr)
+
+	details := make([]audit.AuditChainTable, 0)
+	if err = d.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		return nil, err
+	}
+
+	return &types.ListAuditChainDetails{Details: details}, nil
+}
+
+// ListChainAudits list the audits of the chain whose seq is greater than afterSeq in seq order.
+func (d Dao) ListChainAudits(kt *kit.Kit, chain string, afterSeq uint64, limit uint) ([]audit.AuditTable, error) {
+	if len(chain) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "chain is required")
+	}
+
+	if limit == 0 || limit > core.DefaultMaxPageLimit {
+		limit = core.DefaultMaxPageLimit
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s WHERE chain = :chain AND seq > :seq ORDER BY seq ASC LIMIT %d`,
+		audit.AuditColumns.FieldsNamedExpr(nil), table.AuditTable, limit)
+
+	details := make([]audit.AuditTable, 0)
+	args := map[string]interface{}{"chain": chain, "seq": afterSeq}
+	if err := d.Orm.Do().Select(kt.Ctx, &details, sql, args); err != nil {
+		logs.Errorf("list audit chain %s audits failed, err: %v, rid: %s", chain, err, kt.Rid)
+		return nil, err
+	}
+
+	return details, nil
+}
+
+// UpdateChainExport update the export info of the audit chain.
+func (d Dao) UpdateChainExport(kt *kit.Kit, chain, exportPath, exportedAt string) error {
+	sql := fmt.Sprintf(`UPDATE %s SET export_path = :export_path, exported_at = :exported_at WHERE chain = :chain`,
+		table.AuditChainTable)
+
+	args := map[string]interface{}{"chain": chain, "export_path": exportPath, "exported_at": exportedAt}
+	effected, err := d.Orm.Do().Update(kt.Ctx, sql, args)
+	if err != nil {
+		logs.Errorf("update audit chain %s export info failed, err: %v, rid: %s", chain, err, kt.Rid)
+		return err
+	}
+
+	if effected == 0 {
+		return errf.Newf(errf.RecordNotFound, "audit chain %s not found", chain)
+	}
+
+	return nil
+}
+
+// ListChainAuditsBySeq list the audits of the chain by seqs.
+func (d Dao) ListChainAuditsBySeq(kt *kit.Kit, chain string, seqs []uint64) ([]audit.AuditTable, error) {
+	if len(chain) == 0 || len(seqs) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "chain and seqs are required")
+	}
+
+	expr := tools.ExpressionAnd(tools.RuleEqual("chain", chain), tools.RuleIn("seq", seqs))
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s ORDER BY seq ASC`, audit.AuditColumns.FieldsNamedExpr(nil),
+		table.AuditTable, whereExpr)
+
+	details := make([]audit.AuditTable, 0)
+	if err = d.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.Errorf("list audit chain %s audits by seq failed, err: %v, rid: %s", chain, err, kt.Rid)
+		return nil, err
+	}
+
+	return details, nil
+}
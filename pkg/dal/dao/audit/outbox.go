@@ -0,0 +1,135 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"fmt"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/audit"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// createOutboxWithTx put the audits into outbox in the same transaction, so that they are delivered at least once.
+func (d Dao) createOutboxWithTx(kt *kit.Kit, tx *sqlx.Tx, audits []*audit.AuditTable) error {
+	if len(audits) == 0 {
+		return nil
+	}
+
+	outbox := make([]audit.AuditOutboxTable, 0, len(audits))
+	for _, one := range audits {
+		outbox = append(outbox, audit.AuditOutboxTable{AuditChain: one.Chain, AuditSeq: one.Seq,
+			DeliveredSinks: make(types.StringArray, 0), Status: enumor.AuditOutboxPending})
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s) VALUES(%s)`, table.AuditOutboxTable,
+		audit.AuditOutboxColumns.ColumnExpr(), audit.AuditOutboxColumns.ColonNameExpr())
+	if err := d.Orm.Txn(tx).BulkInsert(kt.Ctx, sql, outbox); err != nil {
+		logs.Errorf("insert %s failed, err: %v, rid: %s", table.AuditOutboxTable, err, kt.Rid)
+		return fmt.Errorf("insert %s failed, err: %v", table.AuditOutboxTable, err)
+	}
+
+	return nil
+}
+
+// ListDueOutbox list the pending outbox events whose next retry time is not after now in creation order.
+func (d Dao) ListDueOutbox(kt *kit.Kit, now int64, limit uint) ([]audit.AuditOutboxTable, error) {
+	sql := fmt.Sprintf(`SELECT %s FROM %s WHERE status = :status AND next_retry_at <= :now ORDER BY id ASC LIMIT %d`,
+		audit.AuditOutboxColumns.FieldsNamedExpr(nil), table.AuditOutboxTable, limit)
+
+	details := make([]audit.AuditOutboxTable, 0)
+	args := map[string]interface{}{"status": enumor.AuditOutboxPending, "now": now}
+	if err := d.Orm.Do().Select(kt.Ctx, &details, sql, args); err != nil {
+		logs.Errorf("list due audit outbox failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	return details, nil
+}
+
+// DeleteOutbox delete the delivered outbox events.
+func (d Dao) DeleteOutbox(kt *kit.Kit, ids []uint64) error {
+	if len(ids) == 0 {
+		return errf.New(errf.InvalidParameter, "ids is required")
+	}
+
+	whereExpr, whereValue, err := tools.ContainersExpression("id", ids).SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s %s`, table.AuditOutboxTable, whereExpr)
+	if _, err = d.Orm.Do().Delete(kt.Ctx, sql, whereValue); err != nil {
+		logs.Errorf("delete audit outbox failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// OutboxDelay is the failed delivery attempt of the outbox events.
+type OutboxDelay struct {
+	IDs []uint64
+	// DeliveredSinks is the names of the sinks that the audits of the events are delivered to so far.
+	DeliveredSinks types.StringArray
+	NextRetryAt    int64
+	LastError      string
+	// Status is the status of the events after this attempt, dead letter events are not retried any more.
+	Status enumor.AuditOutboxStatus
+}
+
+// DelayOutbox record the failed delivery attempt of the outbox events, and delay them to the next retry time.
+func (d Dao) DelayOutbox(kt *kit.Kit, delay *OutboxDelay) error {
+	if delay == nil || len(delay.IDs) == 0 {
+		return errf.New(errf.InvalidParameter, "ids is required")
+	}
+
+	whereExpr, whereValue, err := tools.ContainersExpression("id", delay.IDs).
+		SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	lastErr := delay.LastError
+	if len(lastErr) > 1024 {
+		lastErr = lastErr[:1024]
+	}
+	whereValue["delivered_sinks"] = delay.DeliveredSinks
+	whereValue["status"] = delay.Status
+	whereValue["next_retry_at"] = delay.NextRetryAt
+	whereValue["last_error"] = lastErr
+
+	sql := fmt.Sprintf(`UPDATE %s SET attempts = attempts + 1, delivered_sinks = :delivered_sinks, `+
+		`status = :status, next_retry_at = :next_retry_at, last_error = :last_error %s`, table.AuditOutboxTable,
+		whereExpr)
+	if _, err = d.Orm.Do().Update(kt.Ctx, sql, whereValue); err != nil {
+		logs.Errorf("delay audit outbox failed, err: %v, ids: %v, rid: %s", err, delay.IDs, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -194,12 +194,12 @@ func (dao Dao) List(kt *kit.Kit, opt *types.ListOption) (*types.ListCvmDetails,
 	columnTypes := tablecvm.TableColumns.ColumnTypes()
 	columnTypes["extension.resource_group_name"] = enumor.String
 	columnTypes["extension.zones"] = enumor.Json
-	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes), filter.AllowTagRule()),
 		core.NewDefaultPageOption()); err != nil {
 		return nil, err
 	}
 
-	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.TagSqlWhereOption(enumor.CvmCloudResType))
 	if err != nil {
 		return nil, err
 	}
@@ -194,12 +194,13 @@ func (dao LoadBalancerDao) List(kt *kit.Kit, opt *types.ListOption) (*typeslb.Li
 		return nil, errf.New(errf.InvalidParameter, "list options is nil")
 	}
 
-	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(tablelb.LoadBalancerColumns.ColumnTypes())),
-		core.NewDefaultPageOption()); err != nil {
+	exprOpt := filter.NewExprOption(filter.RuleFields(tablelb.LoadBalancerColumns.ColumnTypes()),
+		filter.AllowTagRule())
+	if err := opt.Validate(exprOpt, core.NewDefaultPageOption()); err != nil {
 		return nil, err
 	}
 
-	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.TagSqlWhereOption(enumor.LoadBalancerCloudResType))
 	if err != nil {
 		return nil, err
 	}
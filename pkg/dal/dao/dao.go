@@ -31,6 +31,7 @@ import (
 	daoasync "hcm/pkg/dal/dao/async"
 	"hcm/pkg/dal/dao/audit"
 	"hcm/pkg/dal/dao/auth"
+	"hcm/pkg/dal/dao/auth/rbac"
 	"hcm/pkg/dal/dao/bill"
 	"hcm/pkg/dal/dao/cloud"
 	daoselection "hcm/pkg/dal/dao/cloud-selection"
@@ -43,18 +44,23 @@ import (
 	"hcm/pkg/dal/dao/cloud/eip"
 	eipcvmrel "hcm/pkg/dal/dao/cloud/eip-cvm-rel"
 	cimage "hcm/pkg/dal/dao/cloud/image"
+	"hcm/pkg/dal/dao/cloud/ipam"
 	loadbalancer "hcm/pkg/dal/dao/cloud/load-balancer"
 	networkinterface "hcm/pkg/dal/dao/cloud/network-interface"
 	nicvmrel "hcm/pkg/dal/dao/cloud/network-interface-cvm-rel"
 	"hcm/pkg/dal/dao/cloud/region"
 	resflow "hcm/pkg/dal/dao/cloud/resource-flow"
 	resourcegroup "hcm/pkg/dal/dao/cloud/resource-group"
+	restag "hcm/pkg/dal/dao/cloud/resource-tag"
 	routetable "hcm/pkg/dal/dao/cloud/route-table"
 	securitygroup "hcm/pkg/dal/dao/cloud/security-group"
 	sgcomrel "hcm/pkg/dal/dao/cloud/security-group-common-rel"
 	sgcvmrel "hcm/pkg/dal/dao/cloud/security-group-cvm-rel"
+	sgrisk "hcm/pkg/dal/dao/cloud/security-group-risk"
+	sgruletpl "hcm/pkg/dal/dao/cloud/security-group-rule-template"
 	daosubaccount "hcm/pkg/dal/dao/cloud/sub-account"
 	daosync "hcm/pkg/dal/dao/cloud/sync"
+	tagpolicy "hcm/pkg/dal/dao/cloud/tag-policy"
 	"hcm/pkg/dal/dao/cloud/zone"
 	idgenerator "hcm/pkg/dal/dao/id-generator"
 	"hcm/pkg/dal/dao/orm"
@@ -97,12 +103,17 @@ type Set interface {
 	Route() routetable.Route
 	Application() application.Application
 	ApprovalProcess() application.ApprovalProcess
+	ApplicationPolicy() application.ApplicationPolicy
 	NetworkInterface() networkinterface.NetworkInterface
 	RecycleRecord() recyclerecord.RecycleRecord
+	RecyclePolicy() recyclerecord.RecyclePolicy
 	Eip() eip.Eip
 	Disk() disk.Disk
+	DiskSnapshot() disk.Snapshot
+	DiskSnapshotPolicy() disk.SnapshotPolicy
 	NiCvmRel() nicvmrel.NiCvmRel
 	Image() cimage.Image
+	PrivateImage() cimage.PrivateImage
 	DiskCvmRel() diskcvmrel.DiskCvmRel
 	EipCvmRel() eipcvmrel.EipCvmRel
 	AccountBillConfig() cloudbill.Interface
@@ -134,14 +145,53 @@ type Set interface {
 	ResourceFlowRel() resflow.ResourceFlowRelInterface
 	ResourceFlowLock() resflow.ResourceFlowLockInterface
 	SGCommonRel() sgcomrel.Interface
+	SGRiskFinding() sgrisk.Interface
+	ResourceTag() restag.Interface
+	TagPolicy() tagpolicy.PolicyInterface
+	TagComplianceFinding() tagpolicy.FindingInterface
+	TagComplianceSummary() tagpolicy.SummaryInterface
+	SGRuleTemplate() sgruletpl.Interface
+	SGRuleTplApply() sgruletpl.ApplyInterface
+	IpamPool() ipam.PoolInterface
+	IpamAllocation() ipam.AllocationInterface
+	AuthRole() rbac.RoleInterface
+	AuthRolePolicy() rbac.PolicyInterface
+	AuthRoleBinding() rbac.BindingInterface
 	MainAccount() accountset.MainAccount
 	RootAccount() accountset.RootAccount
 
 	Txn() *Txn
 }
 
+// SetOption defines the option of the DAO set.
+type SetOption func(opts *setOptions)
+
+type setOptions struct {
+	auditOutbox      bool
+	auditChainShards uint
+}
+
+// WithAuditOutbox puts the created audits into outbox to be delivered to the audit sinks.
+func WithAuditOutbox(enable bool) SetOption {
+	return func(opts *setOptions) {
+		opts.auditOutbox = enable
+	}
+}
+
+// WithAuditChainShards links the audits of a day to the specified count of shard chains.
+func WithAuditChainShards(shards uint) SetOption {
+	return func(opts *setOptions) {
+		opts.auditChainShards = shards
+	}
+}
+
 // NewDaoSet create the DAO set instance.
-func NewDaoSet(opt cc.DataBase) (Set, error) {
+func NewDaoSet(opt cc.DataBase, opts ...SetOption) (Set, error) {
+	setOpts := new(setOptions)
+	for _, one := range opts {
+		one(setOpts)
+	}
+
 	db, err := connect(opt.Resource)
 	if err != nil {
 		return nil, fmt.Errorf("init sharding failed, err: %v", err)
@@ -156,7 +206,7 @@ func NewDaoSet(opt cc.DataBase) (Set, error) {
 		idGen: idGen,
 		orm:   ormInst,
 		db:    db,
-		audit: audit.NewAudit(ormInst),
+		audit: audit.NewAudit(ormInst, setOpts.auditOutbox, setOpts.auditChainShards),
 	}
 
 	return s, nil
@@ -225,6 +275,14 @@ func (s *set) Image() cimage.Image {
 	}
 }
 
+// PrivateImage return private image dao.
+func (s *set) PrivateImage() cimage.PrivateImage {
+	return &cimage.PrivateImageDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // NiCvmRel return NiCvmRel dao.
 func (s *set) NiCvmRel() nicvmrel.NiCvmRel {
 	return &nicvmrel.NiCvmRelDao{
@@ -243,6 +301,22 @@ func (s *set) Disk() disk.Disk {
 	}
 }
 
+// DiskSnapshot return disk snapshot dao.
+func (s *set) DiskSnapshot() disk.Snapshot {
+	return &disk.SnapshotDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// DiskSnapshotPolicy return disk snapshot policy dao.
+func (s *set) DiskSnapshotPolicy() disk.SnapshotPolicy {
+	return &disk.SnapshotPolicyDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // Eip return Eip dao.
 func (s *set) Eip() eip.Eip {
 	return &eip.EipDao{
@@ -457,6 +531,14 @@ func (s *set) ApprovalProcess() application.ApprovalProcess {
 	}
 }
 
+// ApplicationPolicy return application policy dao.
+func (s *set) ApplicationPolicy() application.ApplicationPolicy {
+	return &application.ApplicationPolicyDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // NetworkInterface return network interface dao.
 func (s *set) NetworkInterface() networkinterface.NetworkInterface {
 	return &networkinterface.NetworkInterfaceDao{
@@ -471,6 +553,14 @@ func (s *set) RecycleRecord() recyclerecord.RecycleRecord {
 	return recyclerecord.NewRecycleRecordDao(s.orm, s.idGen, s.audit)
 }
 
+// RecyclePolicy return recycle policy dao.
+func (s *set) RecyclePolicy() recyclerecord.RecyclePolicy {
+	return &recyclerecord.PolicyDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // Txn define dao set Txn.
 type Txn struct {
 	orm orm.Interface
@@ -731,6 +821,102 @@ func (s *set) SGCommonRel() sgcomrel.Interface {
 	}
 }
 
+// SGRiskFinding return security group risk finding dao.
+func (s *set) SGRiskFinding() sgrisk.Interface {
+	return &sgrisk.Dao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// SGRuleTemplate return security group rule template dao.
+func (s *set) SGRuleTemplate() sgruletpl.Interface {
+	return &sgruletpl.Dao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// SGRuleTplApply return security group rule template apply dao.
+func (s *set) SGRuleTplApply() sgruletpl.ApplyInterface {
+	return &sgruletpl.ApplyDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// IpamPool return ipam cidr pool dao.
+func (s *set) IpamPool() ipam.PoolInterface {
+	return &ipam.PoolDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// IpamAllocation return ipam cidr allocation dao.
+func (s *set) IpamAllocation() ipam.AllocationInterface {
+	return &ipam.AllocationDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AuthRole return local rbac auth role dao.
+func (s *set) AuthRole() rbac.RoleInterface {
+	return &rbac.RoleDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AuthRolePolicy return local rbac auth role policy dao.
+func (s *set) AuthRolePolicy() rbac.PolicyInterface {
+	return &rbac.PolicyDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AuthRoleBinding return local rbac auth role binding dao.
+func (s *set) AuthRoleBinding() rbac.BindingInterface {
+	return &rbac.BindingDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// ResourceTag return resource tag dao.
+func (s *set) ResourceTag() restag.Interface {
+	return &restag.Dao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// TagPolicy return tag policy dao.
+func (s *set) TagPolicy() tagpolicy.PolicyInterface {
+	return &tagpolicy.PolicyDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// TagComplianceFinding return tag compliance finding dao.
+func (s *set) TagComplianceFinding() tagpolicy.FindingInterface {
+	return &tagpolicy.FindingDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// TagComplianceSummary return tag compliance summary dao.
+func (s *set) TagComplianceSummary() tagpolicy.SummaryInterface {
+	return &tagpolicy.SummaryDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // MainAccount return mainaccount dao
 func (s *set) MainAccount() accountset.MainAccount {
 	return &accountset.MainAccountDao{
@@ -22,6 +22,8 @@ package tools
 import (
 	"fmt"
 
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table"
 	"hcm/pkg/runtime/filter"
 )
 
@@ -70,6 +72,17 @@ var DefaultSqlWhereOption = &filter.SQLWhereOption{
 	Priority: filter.Priority{"id"},
 }
 
+// TagSqlWhereOption define sql where option which supports resource tag rule of the resource type.
+func TagSqlWhereOption(resType enumor.CloudResourceType) *filter.SQLWhereOption {
+	return &filter.SQLWhereOption{
+		Priority: filter.Priority{"id"},
+		TagOption: &filter.TagOption{
+			Table:   string(table.ResourceTagTable),
+			ResType: string(resType),
+		},
+	}
+}
+
 // And merge expressions using 'and' operation.
 func And(rules ...filter.RuleFactory) (*filter.Expression, error) {
 	if len(rules) == 0 {
@@ -26,3 +26,9 @@ type ListAuditDetails struct {
 	Count   uint64             `json:"count"`
 	Details []audit.AuditTable `json:"details"`
 }
+
+// ListAuditChainDetails list audit chain details.
+type ListAuditChainDetails struct {
+	Count   uint64                  `json:"count"`
+	Details []audit.AuditChainTable `json:"details"`
+}
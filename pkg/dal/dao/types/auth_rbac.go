@@ -0,0 +1,40 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package types
+
+import tableauth "hcm/pkg/dal/table/auth"
+
+// ListAuthRoleDetails list auth role details.
+type ListAuthRoleDetails struct {
+	Count   uint64                `json:"count,omitempty"`
+	Details []tableauth.RoleTable `json:"details,omitempty"`
+}
+
+// ListAuthRolePolicyDetails list auth role policy details.
+type ListAuthRolePolicyDetails struct {
+	Count   uint64                      `json:"count,omitempty"`
+	Details []tableauth.RolePolicyTable `json:"details,omitempty"`
+}
+
+// ListAuthRoleBindingDetails list auth role binding details.
+type ListAuthRoleBindingDetails struct {
+	Count   uint64                       `json:"count,omitempty"`
+	Details []tableauth.RoleBindingTable `json:"details,omitempty"`
+}
@@ -31,3 +31,15 @@ type DiskListResult struct {
 type DiskCountResult struct {
 	Count uint64
 }
+
+// DiskSnapshotListResult list disk snapshot result.
+type DiskSnapshotListResult struct {
+	Count   uint64               `json:"count,omitempty"`
+	Details []disk.SnapshotTable `json:"details,omitempty"`
+}
+
+// DiskSnapshotPolicyListResult list disk snapshot policy result.
+type DiskSnapshotPolicyListResult struct {
+	Count   uint64                     `json:"count,omitempty"`
+	Details []disk.SnapshotPolicyTable `json:"details,omitempty"`
+}
@@ -7,3 +7,9 @@ type ImageListResult struct {
 	Count   uint64
 	Details []*image.ImageModel
 }
+
+// PrivateImageListResult list private image result.
+type PrivateImageListResult struct {
+	Count   uint64                    `json:"count,omitempty"`
+	Details []image.PrivateImageTable `json:"details,omitempty"`
+}
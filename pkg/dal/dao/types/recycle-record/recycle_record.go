@@ -47,3 +47,9 @@ type ResourceUpdateOptions struct {
 	IDs     []string
 	Status  string
 }
+
+// RecyclePolicyListResult list recycle policy result.
+type RecyclePolicyListResult struct {
+	Count   uint64                  `json:"count"`
+	Details []rr.RecyclePolicyTable `json:"details"`
+}
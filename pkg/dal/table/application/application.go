@@ -46,6 +46,7 @@ var ApplicationColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "content", NamedC: "content", Type: enumor.Json},
 	{Column: "delivery_detail", NamedC: "delivery_detail", Type: enumor.Json},
 	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "approval_detail", NamedC: "approval_detail", Type: enumor.Json},
 
 	{Column: "creator", NamedC: "creator", Type: enumor.String},
 	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
@@ -76,6 +77,8 @@ type ApplicationTable struct {
 	DeliveryDetail types.JsonField `db:"delivery_detail" json:"delivery_detail"`
 	// Memo 备注或申请理由
 	Memo *string `db:"memo" json:"memo" validate:"omitempty,max=255"`
+	// ApprovalDetail 内置审批引擎的审批详情，ITSM单据为空
+	ApprovalDetail types.JsonField `db:"approval_detail" json:"approval_detail"`
 
 	// Creator 创建者
 	Creator string `db:"creator" json:"creator" validate:"max=64"`
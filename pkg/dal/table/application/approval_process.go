@@ -42,6 +42,9 @@ var ApprovalProcessColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
 	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
 	{Column: "managers", NamedC: "managers", Type: enumor.String},
+	{Column: "engine", NamedC: "engine", Type: enumor.String},
+	{Column: "stages", NamedC: "stages", Type: enumor.Json},
+	{Column: "timeout_hours", NamedC: "timeout_hours", Type: enumor.Numeric},
 }
 
 // ApprovalProcessTable 审批流程表
@@ -50,8 +53,8 @@ type ApprovalProcessTable struct {
 	ID string `db:"id" json:"id" validate:"max=64"`
 	// ApplicationType 申请类型（新增账号、新增CVM等）
 	ApplicationType string `db:"application_type" json:"application_type" validate:"max=64"`
-	// ServiceID ITSM流程的服务ID
-	ServiceID int64 `db:"service_id" json:"service_id" validate:"min=1"`
+	// ServiceID ITSM流程的服务ID，内置审批引擎不需要
+	ServiceID int64 `db:"service_id" json:"service_id" validate:"min=0"`
 	// Creator 创建者
 	Creator string `db:"creator" json:"creator" validate:"max=64"`
 	// Reviser 更新者
@@ -62,6 +65,12 @@ type ApprovalProcessTable struct {
 	UpdatedAt types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
 	// Managers 审批人
 	Managers string `db:"managers" json:"managers" validate:"max=255"`
+	// Engine 审批引擎（itsm、native）
+	Engine string `db:"engine" json:"engine" validate:"max=16"`
+	// Stages 内置审批引擎的审批阶段配置
+	Stages types.JsonField `db:"stages" json:"stages"`
+	// TimeoutHours 内置审批引擎单个阶段的审批超时时间，超时后升级给平台管理员审批
+	TimeoutHours *uint `db:"timeout_hours" json:"timeout_hours"`
 }
 
 // TableName return approval process table name.
@@ -83,8 +92,15 @@ func (a ApprovalProcessTable) InsertValidate() error {
 		return errors.New("application type is required")
 	}
 
-	if a.ServiceID <= 0 {
-		return errors.New("service id should be gt 0")
+	switch enumor.ApprovalEngine(a.Engine) {
+	case enumor.NativeApprovalEngine:
+		if len(a.Stages) == 0 {
+			return errors.New("stages is required when engine is native")
+		}
+	default:
+		if a.ServiceID <= 0 {
+			return errors.New("service id should be gt 0")
+		}
 	}
 
 	if len(a.Creator) == 0 {
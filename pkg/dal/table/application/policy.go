@@ -0,0 +1,145 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// ApplicationPolicyColumns defines all the application policy table's columns.
+var ApplicationPolicyColumns = utils.MergeColumns(nil, ApplicationPolicyColumnDescriptor)
+
+// ApplicationPolicyColumnDescriptor is application policy's column descriptors.
+var ApplicationPolicyColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "application_type", NamedC: "application_type", Type: enumor.String},
+	{Column: "effect", NamedC: "effect", Type: enumor.String},
+	{Column: "conditions", NamedC: "conditions", Type: enumor.Json},
+	{Column: "message", NamedC: "message", Type: enumor.String},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// ApplicationPolicyTable 申请策略表，申请单创建审批单据前按业务和申请类型评估策略
+type ApplicationPolicyTable struct {
+	// ID 策略ID
+	ID string `db:"id" json:"id" validate:"max=64"`
+	// Name 策略名称
+	Name string `db:"name" json:"name" validate:"max=64"`
+	// BkBizID 策略生效的业务ID，0表示对所有业务生效
+	BkBizID int64 `db:"bk_biz_id" json:"bk_biz_id" validate:"min=0"`
+	// ApplicationType 策略生效的申请类型，为空表示对所有申请类型生效
+	ApplicationType *string `db:"application_type" json:"application_type" validate:"omitempty,max=64"`
+	// Effect 策略效果（require、auto_approve）
+	Effect string `db:"effect" json:"effect" validate:"max=32"`
+	// Conditions 策略条件
+	Conditions types.JsonField `db:"conditions" json:"conditions"`
+	// Message 违反策略时的提示信息
+	Message *string `db:"message" json:"message" validate:"omitempty,max=255"`
+	// Enabled 是否启用
+	Enabled *bool `db:"enabled" json:"enabled"`
+	// Memo 备注
+	Memo *string `db:"memo" json:"memo" validate:"omitempty,max=255"`
+	// Creator 创建者
+	Creator string `db:"creator" json:"creator" validate:"max=64"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" json:"reviser" validate:"max=64"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return application policy table name.
+func (a ApplicationPolicyTable) TableName() table.Name {
+	return table.ApplicationPolicyTable
+}
+
+// InsertValidate application policy table when insert
+func (a ApplicationPolicyTable) InsertValidate() error {
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ID) != 0 {
+		return errors.New("id can not set")
+	}
+
+	if len(a.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if err := enumor.ApplicationPolicyEffect(a.Effect).Validate(); err != nil {
+		return err
+	}
+
+	if len(a.Conditions) == 0 {
+		return errors.New("conditions is required")
+	}
+
+	if a.Enabled == nil {
+		return errors.New("enabled is required")
+	}
+
+	if len(a.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(a.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate application policy table when update
+func (a ApplicationPolicyTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if a.BkBizID != 0 {
+		return errors.New("biz id can not update")
+	}
+
+	if len(a.Effect) != 0 {
+		if err := enumor.ApplicationPolicyEffect(a.Effect).Validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(a.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
@@ -31,6 +31,8 @@ type Reason struct {
 	PreState string `json:"pre_state,omitempty"`
 	// 改为rollback的次数
 	RollbackCount uint `json:"rollback_count,omitempty"`
+	// 任务等待重新下发的时间，到期后由调度器重新推送执行，任务状态变更时清空
+	RescheduleAt string `json:"reschedule_at,omitempty"`
 }
 
 // Scan is used to decode raw message which is read from db into Reason.
@@ -31,6 +31,7 @@ import (
 	"hcm/pkg/dal/table"
 	"hcm/pkg/dal/table/types"
 	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/tools/redact"
 )
 
 // AuditColumns defines all the audit table's columns.
@@ -52,6 +53,10 @@ var AuditColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "rid", NamedC: "rid", Type: enumor.String},
 	{Column: "app_code", NamedC: "app_code", Type: enumor.String},
 	{Column: "detail", NamedC: "detail", Type: enumor.Json},
+	{Column: "chain", NamedC: "chain", Type: enumor.String},
+	{Column: "seq", NamedC: "seq", Type: enumor.Numeric},
+	{Column: "prev_hash", NamedC: "prev_hash", Type: enumor.String},
+	{Column: "hash", NamedC: "hash", Type: enumor.String},
 	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
 }
 
@@ -71,7 +76,15 @@ type AuditTable struct {
 	Rid        string                   `db:"rid" json:"rid" validate:"lte=64"`
 	AppCode    string                   `db:"app_code" json:"app_code" validate:"lte=64"`
 	Detail     *BasicDetail             `db:"detail" json:"detail" validate:"-"`
-	CreatedAt  types.Time               `db:"created_at" json:"created_at"`
+	// Chain is the hash chain that the audit belongs to, audits are chained by the created date.
+	Chain string `db:"chain" json:"chain" validate:"lte=16"`
+	// Seq is the sequence of the audit in the chain, starts from 1.
+	Seq uint64 `db:"seq" json:"seq"`
+	// PrevHash is the hash of the previous audit in the chain, empty for the first audit.
+	PrevHash string `db:"prev_hash" json:"prev_hash" validate:"lte=64"`
+	// Hash is the hash of the audit content and PrevHash.
+	Hash      string     `db:"hash" json:"hash" validate:"lte=64"`
+	CreatedAt types.Time `db:"created_at" json:"created_at"`
 }
 
 // CreateValidate audit when created
@@ -141,10 +154,11 @@ func (detail *BasicDetail) Scan(raw interface{}) error {
 }
 
 // Value encode the scope selector to a json raw, so that it can be stored to db with json raw.
+// the sensitive fields like cloud secret keys are masked, so that they are never stored in audit table.
 func (detail *BasicDetail) Value() (driver.Value, error) {
 	if detail == nil {
 		return nil, errors.New("auditBasicDetail is not initialized, can not be encoded")
 	}
 
-	return json.Marshal(detail)
+	return redact.Marshal(detail)
 }
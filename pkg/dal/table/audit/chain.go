@@ -0,0 +1,184 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/tools/redact"
+)
+
+// AuditChainColumns defines all the audit chain table's columns.
+var AuditChainColumns = utils.MergeColumns(nil, AuditChainColumnDescriptor)
+
+// AuditChainColumnDescriptor is AuditChainTable's column descriptors.
+var AuditChainColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "chain", NamedC: "chain", Type: enumor.String},
+	{Column: "last_seq", NamedC: "last_seq", Type: enumor.Numeric},
+	{Column: "last_hash", NamedC: "last_hash", Type: enumor.String},
+	{Column: "export_path", NamedC: "export_path", Type: enumor.String},
+	{Column: "exported_at", NamedC: "exported_at", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AuditChainTable is the head of an audit hash chain, audits created in the same day are linked to one of the
+// shard chains of the day.
+type AuditChainTable struct {
+	// Chain is the chain name, which is the created date of the audits with the shard, e.g. 2024-10-28/03.
+	Chain string `db:"chain" json:"chain" validate:"lte=16"`
+	// LastSeq is the sequence of the last audit in the chain.
+	LastSeq uint64 `db:"last_seq" json:"last_seq"`
+	// LastHash is the hash of the last audit in the chain.
+	LastHash string `db:"last_hash" json:"last_hash" validate:"lte=64"`
+	// ExportPath is the object store path that the chain segment is exported to.
+	ExportPath string `db:"export_path" json:"export_path" validate:"lte=255"`
+	// ExportedAt is the time that the chain segment is exported.
+	ExportedAt string     `db:"exported_at" json:"exported_at" validate:"lte=64"`
+	CreatedAt  types.Time `db:"created_at" json:"created_at" validate:"isdefault"`
+	UpdatedAt  types.Time `db:"updated_at" json:"updated_at" validate:"isdefault"`
+}
+
+// TableName is the audit chain's database table name.
+func (a AuditChainTable) TableName() table.Name {
+	return table.AuditChainTable
+}
+
+// InsertValidate validate audit chain on insert.
+func (a AuditChainTable) InsertValidate() error {
+	if len(a.Chain) == 0 {
+		return fmt.Errorf("chain is required")
+	}
+
+	return validator.Validate.Struct(a)
+}
+
+// MaxChainShards is the max count of shard chains of a day, the shard is formatted as 2 digits in chain name.
+const MaxChainShards = 100
+
+// ChainName returns the name of the shard chain of the day, e.g. 2024-10-28/03.
+func ChainName(day string, shard uint) string {
+	return fmt.Sprintf("%s/%02d", day, shard)
+}
+
+// ValidateChainName validate the chain name, which is the created date of the audits with the shard, the chains
+// created before sharding have no shard, e.g. 2024-10-28/03 or 2024-10-28.
+func ValidateChainName(chain string) error {
+	day, shard, sharded := strings.Cut(chain, "/")
+	if _, err := time.Parse(constant.DateLayout, day); err != nil {
+		return fmt.Errorf("chain %s has invalid date", chain)
+	}
+
+	if !sharded {
+		return nil
+	}
+
+	if len(shard) != 2 || shard[0] < '0' || shard[0] > '9' || shard[1] < '0' || shard[1] > '9' {
+		return fmt.Errorf("chain %s has invalid shard", chain)
+	}
+
+	return nil
+}
+
+// chainPayload is the audit content that the hash is computed on, the field order must not be changed,
+// otherwise the hash of the existing audits can not be verified.
+type chainPayload struct {
+	PrevHash   string          `json:"prev_hash"`
+	Chain      string          `json:"chain"`
+	Seq        uint64          `json:"seq"`
+	ResID      string          `json:"res_id"`
+	CloudResID string          `json:"cloud_res_id"`
+	ResName    string          `json:"res_name"`
+	ResType    string          `json:"res_type"`
+	Action     string          `json:"action"`
+	BkBizID    int64           `json:"bk_biz_id"`
+	Vendor     string          `json:"vendor"`
+	AccountID  string          `json:"account_id"`
+	Operator   string          `json:"operator"`
+	Source     string          `json:"source"`
+	Rid        string          `json:"rid"`
+	AppCode    string          `json:"app_code"`
+	Detail     json.RawMessage `json:"detail"`
+}
+
+// ComputeHash computes the hash of the audit with its chain, seq and previous hash.
+// the detail is hashed in its canonical json form (the stored redacted detail with sorted keys and normalized
+// numbers), so that the hash computed on creation is the same as the one computed on the audit read from db.
+func (a *AuditTable) ComputeHash() (string, error) {
+	detail, err := canonicalDetail(a.Detail)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(chainPayload{
+		PrevHash:   a.PrevHash,
+		Chain:      a.Chain,
+		Seq:        a.Seq,
+		ResID:      a.ResID,
+		CloudResID: a.CloudResID,
+		ResName:    a.ResName,
+		ResType:    string(a.ResType),
+		Action:     string(a.Action),
+		BkBizID:    a.BkBizID,
+		Vendor:     string(a.Vendor),
+		AccountID:  a.AccountID,
+		Operator:   a.Operator,
+		Source:     string(a.Source),
+		Rid:        a.Rid,
+		AppCode:    a.AppCode,
+		Detail:     detail,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalDetail returns the canonical json of the detail as it is stored in db.
+func canonicalDetail(detail *BasicDetail) (json.RawMessage, error) {
+	if detail == nil {
+		return json.RawMessage("null"), nil
+	}
+
+	raw, err := redact.Marshal(detail)
+	if err != nil {
+		return nil, fmt.Errorf("marshal audit detail failed, err: %v", err)
+	}
+
+	var value interface{}
+	if err = json.Unmarshal(raw, &value); err != nil {
+		return nil, fmt.Errorf("unmarshal audit detail failed, err: %v", err)
+	}
+
+	return json.Marshal(value)
+}
@@ -0,0 +1,91 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestComputeHash(t *testing.T) {
+	created := &AuditTable{
+		ResID:    "00000001",
+		ResType:  enumor.AccountAuditResType,
+		Action:   enumor.Update,
+		BkBizID:  1234567890123,
+		Operator: "admin",
+		Chain:    "2024-10-28",
+		Seq:      2,
+		PrevHash: "prev",
+		Detail: &BasicDetail{
+			Data:    map[string]interface{}{"name": "test", "cloud_secret_key": "key", "price": 1.50},
+			Changed: struct{ Count int }{Count: 10},
+		},
+	}
+
+	hash, err := created.ComputeHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate the audit read from db, detail is stored redacted and scanned into interface values.
+	raw, err := created.Detail.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := *created
+	read.Detail = new(BasicDetail)
+	if err = read.Detail.Scan(raw); err != nil {
+		t.Fatal(err)
+	}
+
+	readHash, err := read.ComputeHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hash != readHash {
+		t.Fatalf("hash of the audit read from db %s is not equal to the created one %s", readHash, hash)
+	}
+
+	read.Operator = "hacker"
+	if modifiedHash, _ := read.ComputeHash(); modifiedHash == hash {
+		t.Fatal("hash of the modified audit should be changed")
+	}
+}
+
+func TestValidateChainName(t *testing.T) {
+	if name := ChainName("2024-10-28", 3); name != "2024-10-28/03" {
+		t.Fatalf("chain name is wrong, got: %s", name)
+	}
+
+	for _, chain := range []string{"2024-10-28", "2024-10-28/03", ChainName("2024-10-28", 99)} {
+		if err := ValidateChainName(chain); err != nil {
+			t.Fatalf("chain %s should be valid, err: %v", chain, err)
+		}
+	}
+
+	for _, chain := range []string{"", "2024-13-01", "2024-10-28/", "2024-10-28/3", "2024-10-28/0a", "2024-10-28/100"} {
+		if err := ValidateChainName(chain); err == nil {
+			t.Fatalf("chain %s should be invalid", chain)
+		}
+	}
+}
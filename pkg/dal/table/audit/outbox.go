@@ -0,0 +1,70 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AuditOutboxColumns defines all the audit outbox table's columns.
+var AuditOutboxColumns = utils.MergeColumns(utils.InsertWithoutPrimaryID, AuditOutboxColumnDescriptor)
+
+// AuditOutboxColumnDescriptor is AuditOutboxTable's column descriptors.
+var AuditOutboxColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.Numeric},
+	{Column: "audit_chain", NamedC: "audit_chain", Type: enumor.String},
+	{Column: "audit_seq", NamedC: "audit_seq", Type: enumor.Numeric},
+	{Column: "delivered_sinks", NamedC: "delivered_sinks", Type: enumor.Json},
+	{Column: "status", NamedC: "status", Type: enumor.String},
+	{Column: "attempts", NamedC: "attempts", Type: enumor.Numeric},
+	{Column: "next_retry_at", NamedC: "next_retry_at", Type: enumor.Numeric},
+	{Column: "last_error", NamedC: "last_error", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// AuditOutboxTable is the audit event to be delivered to the audit sinks, it is created in the same transaction as
+// the audit, and deleted after the audit is delivered to all the sinks, so that every audit is delivered at least once.
+// the event is kept as dead letter if it still fails after the max attempts.
+type AuditOutboxTable struct {
+	ID uint64 `db:"id" json:"id"`
+	// AuditChain and AuditSeq identify the audit to be delivered.
+	AuditChain string `db:"audit_chain" json:"audit_chain"`
+	AuditSeq   uint64 `db:"audit_seq" json:"audit_seq"`
+	// DeliveredSinks is the names of the sinks that the audit is already delivered to, it is not delivered to
+	// them again when retrying.
+	DeliveredSinks types.StringArray `db:"delivered_sinks" json:"delivered_sinks"`
+	// Status is the delivery status of the event.
+	Status enumor.AuditOutboxStatus `db:"status" json:"status"`
+	// Attempts is the count of failed delivery attempts.
+	Attempts uint `db:"attempts" json:"attempts"`
+	// NextRetryAt is the unix seconds that the audit can be delivered again.
+	NextRetryAt int64 `db:"next_retry_at" json:"next_retry_at"`
+	// LastError is the error of the last failed delivery attempt.
+	LastError string     `db:"last_error" json:"last_error"`
+	CreatedAt types.Time `db:"created_at" json:"created_at"`
+}
+
+// TableName is the audit outbox's database table name.
+func (a AuditOutboxTable) TableName() table.Name {
+	return table.AuditOutboxTable
+}
@@ -0,0 +1,82 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tableauth
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// RoleBindingColumns defines all the auth role binding table's columns.
+var RoleBindingColumns = utils.MergeColumns(nil, RoleBindingColumnDescriptor)
+
+// RoleBindingColumnDescriptor is auth role binding table column descriptors.
+var RoleBindingColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "role_id", NamedC: "role_id", Type: enumor.String},
+	{Column: "user", NamedC: "user", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// RoleBindingTable 用户与角色的绑定关系表
+type RoleBindingTable struct {
+	// ID 主键
+	ID string `db:"id" validate:"len=0" json:"id"`
+	// RoleID 角色ID
+	RoleID string `db:"role_id" validate:"max=64" json:"role_id"`
+	// User 被授予角色的用户名
+	User string `db:"user" validate:"max=64" json:"user"`
+	// Creator 创建者
+	Creator string `db:"creator" validate:"max=64" json:"creator"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" validate:"isdefault" json:"created_at"`
+}
+
+// TableName return auth role binding table name.
+func (t RoleBindingTable) TableName() table.Name {
+	return table.AuthRoleBindingTable
+}
+
+// InsertValidate validate auth role binding table on insert.
+func (t RoleBindingTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.RoleID) == 0 {
+		return errors.New("role id can not be empty")
+	}
+
+	if len(t.User) == 0 {
+		return errors.New("user can not be empty")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator can not be empty")
+	}
+
+	return nil
+}
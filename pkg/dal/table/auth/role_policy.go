@@ -0,0 +1,108 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tableauth
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/iam/meta"
+)
+
+// RolePolicyColumns defines all the auth role policy table's columns.
+var RolePolicyColumns = utils.MergeColumns(nil, RolePolicyColumnDescriptor)
+
+// RolePolicyColumnDescriptor is auth role policy table column descriptors.
+var RolePolicyColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "role_id", NamedC: "role_id", Type: enumor.String},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "action", NamedC: "action", Type: enumor.String},
+	{Column: "scope", NamedC: "scope", Type: enumor.String},
+	{Column: "scope_id", NamedC: "scope_id", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// RolePolicyTable 角色的资源权限策略表，策略不可修改，只能新增或删除
+type RolePolicyTable struct {
+	// ID 主键
+	ID string `db:"id" validate:"len=0" json:"id"`
+	// RoleID 所属角色ID
+	RoleID string `db:"role_id" validate:"max=64" json:"role_id"`
+	// ResType 资源类型，与鉴权时的 meta.ResourceType 一致，`*` 表示所有资源类型
+	ResType meta.ResourceType `db:"res_type" validate:"max=64" json:"res_type"`
+	// Action 操作，与鉴权时的 meta.Action 一致，`*` 表示所有操作
+	Action meta.Action `db:"action" validate:"max=64" json:"action"`
+	// Scope 策略生效范围
+	Scope enumor.AuthPolicyScope `db:"scope" validate:"max=32" json:"scope"`
+	// ScopeID 生效范围对应的ID，scope为any时为空
+	ScopeID string `db:"scope_id" validate:"max=64" json:"scope_id"`
+	// Creator 创建者
+	Creator string `db:"creator" validate:"max=64" json:"creator"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" validate:"isdefault" json:"created_at"`
+}
+
+// TableName return auth role policy table name.
+func (t RolePolicyTable) TableName() table.Name {
+	return table.AuthRolePolicyTable
+}
+
+// InsertValidate validate auth role policy table on insert.
+func (t RolePolicyTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.RoleID) == 0 {
+		return errors.New("role id can not be empty")
+	}
+
+	if len(t.ResType) == 0 {
+		return errors.New("res type can not be empty")
+	}
+
+	if len(t.Action) == 0 {
+		return errors.New("action can not be empty")
+	}
+
+	if err := t.Scope.Validate(); err != nil {
+		return err
+	}
+
+	if t.Scope == enumor.AuthScopeAny && len(t.ScopeID) != 0 {
+		return errors.New("scope id must be empty when scope is any")
+	}
+
+	if t.Scope != enumor.AuthScopeAny && len(t.ScopeID) == 0 {
+		return errors.New("scope id can not be empty")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator can not be empty")
+	}
+
+	return nil
+}
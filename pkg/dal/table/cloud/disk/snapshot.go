@@ -0,0 +1,150 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disk
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// SnapshotColumns defines all the disk snapshot table's columns.
+var SnapshotColumns = utils.MergeColumns(nil, SnapshotColumnDescriptor)
+
+// SnapshotColumnDescriptor is disk snapshot table column descriptors.
+var SnapshotColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "region", NamedC: "region", Type: enumor.String},
+	{Column: "cloud_id", NamedC: "cloud_id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "disk_id", NamedC: "disk_id", Type: enumor.String},
+	{Column: "cloud_disk_id", NamedC: "cloud_disk_id", Type: enumor.String},
+	{Column: "disk_size", NamedC: "disk_size", Type: enumor.Numeric},
+	{Column: "status", NamedC: "status", Type: enumor.String},
+	{Column: "policy_id", NamedC: "policy_id", Type: enumor.String},
+	{Column: "cloud_created_time", NamedC: "cloud_created_time", Type: enumor.String},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// SnapshotTable 云硬盘快照表
+type SnapshotTable struct {
+	// ID 快照ID
+	ID string `db:"id" json:"id" validate:"max=64"`
+	// Vendor 云厂商
+	Vendor enumor.Vendor `db:"vendor" json:"vendor" validate:"max=16"`
+	// AccountID 快照所属账号ID
+	AccountID string `db:"account_id" json:"account_id" validate:"max=64"`
+	// BkBizID 快照所属业务，与源云硬盘的业务一致
+	BkBizID int64 `db:"bk_biz_id" json:"bk_biz_id"`
+	// Region 快照所在地域
+	Region string `db:"region" json:"region" validate:"max=255"`
+	// CloudID 云上快照ID
+	CloudID string `db:"cloud_id" json:"cloud_id" validate:"max=255"`
+	// Name 快照名称
+	Name string `db:"name" json:"name" validate:"max=255"`
+	// DiskID 源云硬盘ID，源云硬盘未同步到hcm时为空
+	DiskID string `db:"disk_id" json:"disk_id" validate:"max=64"`
+	// CloudDiskID 源云硬盘云上ID
+	CloudDiskID string `db:"cloud_disk_id" json:"cloud_disk_id" validate:"max=255"`
+	// DiskSize 源云硬盘大小，单位GB
+	DiskSize uint64 `db:"disk_size" json:"disk_size"`
+	// Status 云上快照状态
+	Status string `db:"status" json:"status" validate:"max=32"`
+	// PolicyID 创建快照的定期快照策略ID，手动创建的快照为空
+	PolicyID string `db:"policy_id" json:"policy_id" validate:"max=64"`
+	// CloudCreatedTime 云上快照创建时间
+	CloudCreatedTime string `db:"cloud_created_time" json:"cloud_created_time" validate:"max=64"`
+	// Memo 备注
+	Memo *string `db:"memo" json:"memo" validate:"omitempty,max=255"`
+	// Creator 创建者
+	Creator string `db:"creator" json:"creator" validate:"max=64"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" json:"reviser" validate:"max=64"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" json:"created_at" validate:"isdefault"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at" validate:"isdefault"`
+}
+
+// TableName return disk snapshot table name.
+func (t SnapshotTable) TableName() table.Name {
+	return table.DiskSnapshotTable
+}
+
+// InsertValidate validate disk snapshot on insertion.
+func (t SnapshotTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) != 0 {
+		return errors.New("id can not set")
+	}
+
+	if len(t.Vendor) == 0 {
+		return errors.New("vendor is required")
+	}
+
+	if len(t.AccountID) == 0 {
+		return errors.New("account id is required")
+	}
+
+	if len(t.Region) == 0 {
+		return errors.New("region is required")
+	}
+
+	if len(t.CloudID) == 0 {
+		return errors.New("cloud id is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate validate disk snapshot on update.
+func (t SnapshotTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Vendor) != 0 || len(t.AccountID) != 0 || len(t.Region) != 0 || len(t.CloudID) != 0 {
+		return errors.New("vendor, account id, region and cloud id can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
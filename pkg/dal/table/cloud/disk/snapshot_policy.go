@@ -0,0 +1,135 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disk
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// SnapshotPolicyColumns defines all the disk snapshot policy table's columns.
+var SnapshotPolicyColumns = utils.MergeColumns(nil, SnapshotPolicyColumnDescriptor)
+
+// SnapshotPolicyColumnDescriptor is disk snapshot policy table column descriptors.
+var SnapshotPolicyColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "cron", NamedC: "cron", Type: enumor.String},
+	{Column: "retention_count", NamedC: "retention_count", Type: enumor.Numeric},
+	{Column: "disk_ids", NamedC: "disk_ids", Type: enumor.Json},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "last_executed_at", NamedC: "last_executed_at", Type: enumor.String},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// SnapshotPolicyTable 定期快照策略表，按cron表达式为业务下的云硬盘定期创建快照，并只保留最近的若干个
+type SnapshotPolicyTable struct {
+	// ID 策略ID
+	ID string `db:"id" json:"id" validate:"max=64"`
+	// Name 策略名称
+	Name string `db:"name" json:"name" validate:"max=255"`
+	// BkBizID 策略所属业务
+	BkBizID int64 `db:"bk_biz_id" json:"bk_biz_id"`
+	// Cron 执行周期，标准的5段cron表达式(分 时 日 月 周)
+	Cron string `db:"cron" json:"cron" validate:"max=64"`
+	// RetentionCount 每块云硬盘保留的策略快照个数，超出时删除最早的快照
+	RetentionCount uint64 `db:"retention_count" json:"retention_count"`
+	// DiskIDs 应用策略的云硬盘ID
+	DiskIDs types.StringArray `db:"disk_ids" json:"disk_ids"`
+	// Enabled 是否启用
+	Enabled *bool `db:"enabled" json:"enabled"`
+	// LastExecutedAt 最近一次执行的时间
+	LastExecutedAt string `db:"last_executed_at" json:"last_executed_at" validate:"max=64"`
+	// Memo 备注
+	Memo *string `db:"memo" json:"memo" validate:"omitempty,max=255"`
+	// Creator 创建者
+	Creator string `db:"creator" json:"creator" validate:"max=64"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" json:"reviser" validate:"max=64"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" json:"created_at" validate:"isdefault"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at" validate:"isdefault"`
+}
+
+// TableName return disk snapshot policy table name.
+func (t SnapshotPolicyTable) TableName() table.Name {
+	return table.DiskSnapshotPolicyTable
+}
+
+// InsertValidate validate disk snapshot policy on insertion.
+func (t SnapshotPolicyTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) != 0 {
+		return errors.New("id can not set")
+	}
+
+	if len(t.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if len(t.Cron) == 0 {
+		return errors.New("cron is required")
+	}
+
+	if t.RetentionCount == 0 {
+		return errors.New("retention count is required")
+	}
+
+	if t.Enabled == nil {
+		return errors.New("enabled is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate validate disk snapshot policy on update.
+func (t SnapshotPolicyTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if t.BkBizID != 0 {
+		return errors.New("bk biz id can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package image
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// PrivateImageColumns defines all the private image table's columns.
+var PrivateImageColumns = utils.MergeColumns(nil, PrivateImageColumnDescriptor)
+
+// PrivateImageColumnDescriptor is private image table column descriptors.
+var PrivateImageColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "region", NamedC: "region", Type: enumor.String},
+	{Column: "cloud_id", NamedC: "cloud_id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "source_cvm_id", NamedC: "source_cvm_id", Type: enumor.String},
+	{Column: "source_cloud_cvm_id", NamedC: "source_cloud_cvm_id", Type: enumor.String},
+	{Column: "source_image_id", NamedC: "source_image_id", Type: enumor.String},
+	{Column: "shared_account_ids", NamedC: "shared_account_ids", Type: enumor.Json},
+	{Column: "status", NamedC: "status", Type: enumor.String},
+	{Column: "deprecated", NamedC: "deprecated", Type: enumor.Boolean},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// PrivateImageTable 私有镜像表，记录通过hcm基于主机创建或跨地域复制的自定义镜像
+type PrivateImageTable struct {
+	// ID 镜像ID
+	ID string `db:"id" json:"id" validate:"max=64"`
+	// Vendor 云厂商
+	Vendor enumor.Vendor `db:"vendor" json:"vendor" validate:"max=16"`
+	// AccountID 镜像所属账号ID
+	AccountID string `db:"account_id" json:"account_id" validate:"max=64"`
+	// BkBizID 镜像所属业务，与源主机的业务一致
+	BkBizID int64 `db:"bk_biz_id" json:"bk_biz_id"`
+	// Region 镜像所在地域
+	Region string `db:"region" json:"region" validate:"max=255"`
+	// CloudID 云上镜像ID
+	CloudID string `db:"cloud_id" json:"cloud_id" validate:"max=255"`
+	// Name 镜像名称
+	Name string `db:"name" json:"name" validate:"max=255"`
+	// SourceCvmID 创建镜像的源主机ID
+	SourceCvmID string `db:"source_cvm_id" json:"source_cvm_id" validate:"max=64"`
+	// SourceCloudCvmID 创建镜像的源主机云上ID
+	SourceCloudCvmID string `db:"source_cloud_cvm_id" json:"source_cloud_cvm_id" validate:"max=255"`
+	// SourceImageID 跨地域复制的源镜像ID，非复制的镜像为空
+	SourceImageID string `db:"source_image_id" json:"source_image_id" validate:"max=64"`
+	// SharedAccountIDs 已共享的账号ID
+	SharedAccountIDs types.StringArray `db:"shared_account_ids" json:"shared_account_ids"`
+	// Status 镜像状态
+	Status enumor.PrivateImageStatus `db:"status" json:"status" validate:"max=32"`
+	// Deprecated 是否已弃用，弃用的镜像不能再用于创建主机
+	Deprecated *bool `db:"deprecated" json:"deprecated"`
+	// Memo 备注
+	Memo *string `db:"memo" json:"memo" validate:"omitempty,max=255"`
+	// Creator 创建者
+	Creator string `db:"creator" json:"creator" validate:"max=64"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" json:"reviser" validate:"max=64"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" json:"created_at" validate:"isdefault"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at" validate:"isdefault"`
+}
+
+// TableName return private image table name.
+func (t PrivateImageTable) TableName() table.Name {
+	return table.PrivateImageTable
+}
+
+// InsertValidate validate private image on insertion.
+func (t PrivateImageTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) != 0 {
+		return errors.New("id can not set")
+	}
+
+	if len(t.Vendor) == 0 {
+		return errors.New("vendor is required")
+	}
+
+	if len(t.AccountID) == 0 {
+		return errors.New("account id is required")
+	}
+
+	if len(t.Region) == 0 {
+		return errors.New("region is required")
+	}
+
+	if len(t.CloudID) == 0 {
+		return errors.New("cloud id is required")
+	}
+
+	if len(t.Status) == 0 {
+		return errors.New("status is required")
+	}
+
+	if t.Deprecated == nil {
+		return errors.New("deprecated is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate validate private image on update.
+func (t PrivateImageTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Vendor) != 0 || len(t.AccountID) != 0 || len(t.Region) != 0 || len(t.CloudID) != 0 {
+		return errors.New("vendor, account id, region and cloud id can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
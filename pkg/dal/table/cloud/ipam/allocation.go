@@ -0,0 +1,128 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tableipam
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// IpamAllocationColumns defines all the ipam cidr allocation table's columns.
+var IpamAllocationColumns = utils.MergeColumns(nil, IpamAllocationColumnDescriptor)
+
+// IpamAllocationColumnDescriptor is ipam cidr allocation table column descriptors.
+var IpamAllocationColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "pool_id", NamedC: "pool_id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "cidr", NamedC: "cidr", Type: enumor.String},
+	{Column: "type", NamedC: "type", Type: enumor.String},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "res_id", NamedC: "res_id", Type: enumor.String},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// IpamAllocationTable ipam网段分配记录，包括分配给待创建资源的网段和预留网段
+type IpamAllocationTable struct {
+	// ID 主键
+	ID string `db:"id" validate:"len=0" json:"id"`
+	// PoolID 所属地址池ID
+	PoolID string `db:"pool_id" validate:"max=64" json:"pool_id"`
+	// BkBizID 业务ID
+	BkBizID int64 `db:"bk_biz_id" validate:"min=-1" json:"bk_biz_id"`
+	// Cidr 分配的网段
+	Cidr string `db:"cidr" validate:"max=64" json:"cidr"`
+	// Type 分配类型
+	Type enumor.IpamAllocationType `db:"type" validate:"max=32" json:"type"`
+	// ResType 使用该网段的资源类型
+	ResType enumor.CloudResourceType `db:"res_type" validate:"max=64" json:"res_type"`
+	// ResID 使用该网段的资源ID
+	ResID string `db:"res_id" validate:"max=64" json:"res_id"`
+	// Memo 备注
+	Memo *string `db:"memo" validate:"omitempty,max=255" json:"memo"`
+	// Creator 创建者
+	Creator string `db:"creator" validate:"max=64" json:"creator"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" validate:"max=64" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" validate:"isdefault" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" validate:"isdefault" json:"updated_at"`
+}
+
+// TableName return ipam cidr allocation table name.
+func (t IpamAllocationTable) TableName() table.Name {
+	return table.IpamAllocationTable
+}
+
+// InsertValidate validate ipam cidr allocation table on insert.
+func (t IpamAllocationTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.PoolID) == 0 {
+		return errors.New("pool_id can not be empty")
+	}
+
+	if len(t.Cidr) == 0 {
+		return errors.New("cidr can not be empty")
+	}
+
+	if err := t.Type.Validate(); err != nil {
+		return err
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator can not be empty")
+	}
+
+	return nil
+}
+
+// UpdateValidate validate ipam cidr allocation table on update.
+func (t IpamAllocationTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.PoolID) != 0 || len(t.Cidr) != 0 || len(t.Type) != 0 {
+		return errors.New("pool_id, cidr, type can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser can not be empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tableipam ...
+package tableipam
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// IpamPoolColumns defines all the ipam cidr pool table's columns.
+var IpamPoolColumns = utils.MergeColumns(nil, IpamPoolColumnDescriptor)
+
+// IpamPoolColumnDescriptor is ipam cidr pool table column descriptors.
+var IpamPoolColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "region", NamedC: "region", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "cidr", NamedC: "cidr", Type: enumor.String},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// IpamPoolTable ipam网段地址池，业务在某个云厂商地域下创建的vpc需要从地址池中分配网段
+type IpamPoolTable struct {
+	// ID 主键
+	ID string `db:"id" validate:"len=0" json:"id"`
+	// Name 地址池名称
+	Name string `db:"name" validate:"max=255" json:"name"`
+	// Vendor 云厂商
+	Vendor enumor.Vendor `db:"vendor" validate:"max=16" json:"vendor"`
+	// Region 地域
+	Region string `db:"region" validate:"max=255" json:"region"`
+	// BkBizID 业务ID
+	BkBizID int64 `db:"bk_biz_id" validate:"min=-1" json:"bk_biz_id"`
+	// Cidr 地址池网段，仅支持IPv4
+	Cidr string `db:"cidr" validate:"max=64" json:"cidr"`
+	// Memo 备注
+	Memo *string `db:"memo" validate:"omitempty,max=255" json:"memo"`
+	// Creator 创建者
+	Creator string `db:"creator" validate:"max=64" json:"creator"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" validate:"max=64" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" validate:"isdefault" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" validate:"isdefault" json:"updated_at"`
+}
+
+// TableName return ipam cidr pool table name.
+func (t IpamPoolTable) TableName() table.Name {
+	return table.IpamPoolTable
+}
+
+// InsertValidate validate ipam cidr pool table on insert.
+func (t IpamPoolTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Name) == 0 {
+		return errors.New("name can not be empty")
+	}
+
+	if len(t.Vendor) == 0 {
+		return errors.New("vendor can not be empty")
+	}
+
+	if len(t.Region) == 0 {
+		return errors.New("region can not be empty")
+	}
+
+	if len(t.Cidr) == 0 {
+		return errors.New("cidr can not be empty")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator can not be empty")
+	}
+
+	return nil
+}
+
+// UpdateValidate validate ipam cidr pool table on update.
+func (t IpamPoolTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Vendor) != 0 || len(t.Region) != 0 || len(t.Cidr) != 0 {
+		return errors.New("vendor, region, cidr can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser can not be empty")
+	}
+
+	return nil
+}
@@ -41,6 +41,7 @@ var LoadBalancerColumnsDescriptor = utils.ColumnDescriptors{
 	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
 	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
 	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "recycle_status", NamedC: "recycle_status", Type: enumor.String},
 
 	{Column: "region", NamedC: "region", Type: enumor.String},
 	{Column: "zones", NamedC: "zones", Type: enumor.Json},
@@ -77,6 +78,7 @@ type LoadBalancerTable struct {
 	Vendor               enumor.Vendor     `db:"vendor" validate:"lte=16"  json:"vendor"`
 	AccountID            string            `db:"account_id" validate:"lte=64" json:"account_id"`
 	BkBizID              int64             `db:"bk_biz_id" json:"bk_biz_id"`
+	RecycleStatus        string            `db:"recycle_status" validate:"lte=32" json:"recycle_status,omitempty"`
 	Region               string            `db:"region" validate:"lte=20" json:"region"`
 	Zones                types.StringArray `db:"zones" validate:"lte=20" json:"zones"`
 	BackupZones          types.StringArray `db:"backup_zones" json:"backup_zones"`
@@ -0,0 +1,116 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tablerestag ...
+package tablerestag
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// ResourceTagColumns defines all the resource tag table's columns.
+var ResourceTagColumns = utils.MergeColumns(nil, ResourceTagColumnDescriptor)
+
+// ResourceTagColumnDescriptor is resource tag table column descriptors.
+var ResourceTagColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "res_id", NamedC: "res_id", Type: enumor.String},
+	{Column: "cloud_res_id", NamedC: "cloud_res_id", Type: enumor.String},
+	{Column: "tag_key", NamedC: "tag_key", Type: enumor.String},
+	{Column: "tag_value", NamedC: "tag_value", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// ResourceTagTable 资源标签，aws/azure/huawei/tcloud 的标签和 gcp 的 label 统一存储在该表中
+type ResourceTagTable struct {
+	// ID 主键
+	ID string `db:"id" validate:"len=0" json:"id"`
+	// Vendor 云厂商
+	Vendor enumor.Vendor `db:"vendor" validate:"max=16" json:"vendor"`
+	// AccountID 账号ID
+	AccountID string `db:"account_id" validate:"max=64" json:"account_id"`
+	// ResType 资源类型
+	ResType enumor.CloudResourceType `db:"res_type" validate:"max=64" json:"res_type"`
+	// ResID 资源ID
+	ResID string `db:"res_id" validate:"max=64" json:"res_id"`
+	// CloudResID 资源云上ID
+	CloudResID string `db:"cloud_res_id" validate:"max=255" json:"cloud_res_id"`
+	// TagKey 标签键
+	TagKey string `db:"tag_key" validate:"max=255" json:"tag_key"`
+	// TagValue 标签值
+	TagValue string `db:"tag_value" validate:"max=255" json:"tag_value"`
+	// Creator 创建者
+	Creator string `db:"creator" validate:"max=64" json:"creator"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" validate:"max=64" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" validate:"isdefault" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" validate:"isdefault" json:"updated_at"`
+}
+
+// TableName return resource tag table name.
+func (t ResourceTagTable) TableName() table.Name {
+	return table.ResourceTagTable
+}
+
+// InsertValidate validate resource tag table on insert.
+func (t ResourceTagTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Vendor) == 0 {
+		return errors.New("vendor can not be empty")
+	}
+
+	if len(t.AccountID) == 0 {
+		return errors.New("account id can not be empty")
+	}
+
+	if len(t.ResType) == 0 {
+		return errors.New("res type can not be empty")
+	}
+
+	if len(t.ResID) == 0 {
+		return errors.New("res id can not be empty")
+	}
+
+	if len(t.TagKey) == 0 {
+		return errors.New("tag key can not be empty")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator can not be empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tablesgrisk ...
+package tablesgrisk
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// SGRiskFindingColumns defines all the security group risk finding table's columns.
+var SGRiskFindingColumns = utils.MergeColumns(nil, SGRiskFindingColumnDescriptor)
+
+// SGRiskFindingColumnDescriptor is security group risk finding table column descriptors.
+var SGRiskFindingColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "region", NamedC: "region", Type: enumor.String},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "res_id", NamedC: "res_id", Type: enumor.String},
+	{Column: "cloud_res_id", NamedC: "cloud_res_id", Type: enumor.String},
+	{Column: "res_name", NamedC: "res_name", Type: enumor.String},
+	{Column: "rule_id", NamedC: "rule_id", Type: enumor.String},
+	{Column: "cloud_rule_id", NamedC: "cloud_rule_id", Type: enumor.String},
+	{Column: "check_name", NamedC: "check_name", Type: enumor.String},
+	{Column: "check_type", NamedC: "check_type", Type: enumor.String},
+	{Column: "severity", NamedC: "severity", Type: enumor.String},
+	{Column: "description", NamedC: "description", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// SGRiskFindingTable 安全组风险扫描结果
+type SGRiskFindingTable struct {
+	// ID 主键
+	ID string `db:"id" validate:"len=0" json:"id"`
+	// Vendor 云厂商
+	Vendor enumor.Vendor `db:"vendor" validate:"max=16" json:"vendor"`
+	// AccountID 账号ID
+	AccountID string `db:"account_id" validate:"max=64" json:"account_id"`
+	// BkBizID 风险资源所属业务ID
+	BkBizID int64 `db:"bk_biz_id" validate:"min=-1" json:"bk_biz_id"`
+	// Region 地域
+	Region string `db:"region" validate:"max=255" json:"region"`
+	// ResType 风险资源类型，安全组或者gcp防火墙规则
+	ResType enumor.CloudResourceType `db:"res_type" validate:"max=64" json:"res_type"`
+	// ResID 风险资源ID
+	ResID string `db:"res_id" validate:"max=64" json:"res_id"`
+	// CloudResID 风险资源云上ID
+	CloudResID string `db:"cloud_res_id" validate:"max=255" json:"cloud_res_id"`
+	// ResName 风险资源名称
+	ResName string `db:"res_name" validate:"max=255" json:"res_name"`
+	// RuleID 命中风险的安全组规则ID，安全组级别的风险为空
+	RuleID string `db:"rule_id" validate:"max=64" json:"rule_id"`
+	// CloudRuleID 命中风险的安全组规则云上ID
+	CloudRuleID string `db:"cloud_rule_id" validate:"max=255" json:"cloud_rule_id"`
+	// CheckName 扫描规则名称
+	CheckName string `db:"check_name" validate:"max=64" json:"check_name"`
+	// CheckType 扫描规则类型
+	CheckType enumor.SGRiskCheckType `db:"check_type" validate:"max=64" json:"check_type"`
+	// Severity 风险等级
+	Severity enumor.SGRiskSeverity `db:"severity" validate:"max=16" json:"severity"`
+	// Description 风险描述
+	Description string `db:"description" validate:"max=1024" json:"description"`
+	// Creator 创建者
+	Creator string `db:"creator" validate:"max=64" json:"creator"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" validate:"max=64" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" validate:"isdefault" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" validate:"isdefault" json:"updated_at"`
+}
+
+// TableName return security group risk finding table name.
+func (t SGRiskFindingTable) TableName() table.Name {
+	return table.SGRiskFindingTable
+}
+
+// InsertValidate validate security group risk finding table on insert.
+func (t SGRiskFindingTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Vendor) == 0 {
+		return errors.New("vendor can not be empty")
+	}
+
+	if len(t.AccountID) == 0 {
+		return errors.New("account id can not be empty")
+	}
+
+	if len(t.ResID) == 0 {
+		return errors.New("res id can not be empty")
+	}
+
+	if err := t.CheckType.Validate(); err != nil {
+		return err
+	}
+
+	if err := t.Severity.Validate(); err != nil {
+		return err
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator can not be empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tablesgruletpl
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// SGRuleTplApplyColumns defines all the security group rule template apply table's columns.
+var SGRuleTplApplyColumns = utils.MergeColumns(nil, SGRuleTplApplyColumnDescriptor)
+
+// SGRuleTplApplyColumnDescriptor is security group rule template apply table column descriptors.
+var SGRuleTplApplyColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "template_id", NamedC: "template_id", Type: enumor.String},
+	{Column: "template_version", NamedC: "template_version", Type: enumor.Numeric},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "res_id", NamedC: "res_id", Type: enumor.String},
+	{Column: "cloud_res_id", NamedC: "cloud_res_id", Type: enumor.String},
+	{Column: "state", NamedC: "state", Type: enumor.String},
+	{Column: "reason", NamedC: "reason", Type: enumor.String},
+	{Column: "flow_id", NamedC: "flow_id", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// SGRuleTplApplyTable 安全组规则模版下发记录，每个下发目标一条记录
+type SGRuleTplApplyTable struct {
+	// ID 主键
+	ID string `db:"id" validate:"len=0" json:"id"`
+	// TemplateID 规则模版ID
+	TemplateID string `db:"template_id" validate:"max=64" json:"template_id"`
+	// TemplateVersion 下发的模版版本
+	TemplateVersion uint64 `db:"template_version" json:"template_version"`
+	// Vendor 云厂商
+	Vendor enumor.Vendor `db:"vendor" validate:"max=16" json:"vendor"`
+	// AccountID 账号ID
+	AccountID string `db:"account_id" validate:"max=64" json:"account_id"`
+	// BkBizID 业务ID
+	BkBizID int64 `db:"bk_biz_id" validate:"min=-1" json:"bk_biz_id"`
+	// ResType 下发目标资源类型，gcp为vpc，其余厂商为安全组
+	ResType enumor.CloudResourceType `db:"res_type" validate:"max=64" json:"res_type"`
+	// ResID 下发目标资源ID
+	ResID string `db:"res_id" validate:"max=64" json:"res_id"`
+	// CloudResID 下发目标资源云上ID
+	CloudResID string `db:"cloud_res_id" validate:"max=255" json:"cloud_res_id"`
+	// State 下发状态
+	State enumor.SGRuleTplApplyState `db:"state" validate:"max=16" json:"state"`
+	// Reason 下发失败原因
+	Reason string `db:"reason" validate:"max=1024" json:"reason"`
+	// FlowID 下发使用的异步任务流ID
+	FlowID string `db:"flow_id" validate:"max=64" json:"flow_id"`
+	// Creator 创建者
+	Creator string `db:"creator" validate:"max=64" json:"creator"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" validate:"max=64" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" validate:"isdefault" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" validate:"isdefault" json:"updated_at"`
+}
+
+// TableName return security group rule template apply table name.
+func (t SGRuleTplApplyTable) TableName() table.Name {
+	return table.SGRuleTplApplyTable
+}
+
+// InsertValidate validate security group rule template apply table on insert.
+func (t SGRuleTplApplyTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.TemplateID) == 0 {
+		return errors.New("template id can not be empty")
+	}
+
+	if len(t.Vendor) == 0 {
+		return errors.New("vendor can not be empty")
+	}
+
+	if len(t.ResID) == 0 {
+		return errors.New("res id can not be empty")
+	}
+
+	if err := t.State.Validate(); err != nil {
+		return err
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator can not be empty")
+	}
+
+	return nil
+}
+
+// UpdateValidate validate security group rule template apply table on update.
+func (t SGRuleTplApplyTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.State) != 0 {
+		if err := t.State.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser can not be empty")
+	}
+
+	return nil
+}
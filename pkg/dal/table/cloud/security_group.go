@@ -38,6 +38,7 @@ var SecurityGroupColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
 	{Column: "cloud_id", NamedC: "cloud_id", Type: enumor.String},
 	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "recycle_status", NamedC: "recycle_status", Type: enumor.String},
 	{Column: "region", NamedC: "region", Type: enumor.String},
 	{Column: "name", NamedC: "name", Type: enumor.String},
 	{Column: "memo", NamedC: "memo", Type: enumor.String},
@@ -51,19 +52,20 @@ var SecurityGroupColumnDescriptor = utils.ColumnDescriptors{
 
 // SecurityGroupTable define security group table.
 type SecurityGroupTable struct {
-	ID        string          `db:"id" json:"id" validate:"lte=64"`
-	Vendor    enumor.Vendor   `db:"vendor" json:"vendor" validate:"lte=16"`
-	CloudID   string          `db:"cloud_id" json:"cloud_id" validate:"lte=255"`
-	BkBizID   int64           `db:"bk_biz_id" json:"bk_biz_id"`
-	Region    string          `db:"region" json:"region" validate:"lte=20"`
-	Name      string          `db:"name" json:"name" validate:"lte=255"`
-	Memo      *string         `db:"memo" json:"memo" validate:"omitempty,lte=255"`
-	AccountID string          `db:"account_id" json:"account_id" validate:"lte=64"`
-	Extension types.JsonField `db:"extension" json:"extension"`
-	Creator   string          `db:"creator" json:"creator" validate:"lte=64"`
-	Reviser   string          `db:"reviser" json:"reviser" validate:"lte=64"`
-	CreatedAt types.Time      `db:"created_at" json:"created_at" validate:"excluded_unless"`
-	UpdatedAt types.Time      `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+	ID            string          `db:"id" json:"id" validate:"lte=64"`
+	Vendor        enumor.Vendor   `db:"vendor" json:"vendor" validate:"lte=16"`
+	CloudID       string          `db:"cloud_id" json:"cloud_id" validate:"lte=255"`
+	BkBizID       int64           `db:"bk_biz_id" json:"bk_biz_id"`
+	RecycleStatus string          `db:"recycle_status" json:"recycle_status,omitempty" validate:"lte=32"`
+	Region        string          `db:"region" json:"region" validate:"lte=20"`
+	Name          string          `db:"name" json:"name" validate:"lte=255"`
+	Memo          *string         `db:"memo" json:"memo" validate:"omitempty,lte=255"`
+	AccountID     string          `db:"account_id" json:"account_id" validate:"lte=64"`
+	Extension     types.JsonField `db:"extension" json:"extension"`
+	Creator       string          `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser       string          `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt     types.Time      `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt     types.Time      `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
 }
 
 // TableName return security group table name.
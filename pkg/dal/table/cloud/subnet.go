@@ -50,6 +50,7 @@ var SubnetColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "vpc_id", NamedC: "vpc_id", Type: enumor.String},
 	{Column: "route_table_id", NamedC: "route_table_id", Type: enumor.String},
 	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "recycle_status", NamedC: "recycle_status", Type: enumor.String},
 	{Column: "creator", NamedC: "creator", Type: enumor.String},
 	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
 	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
@@ -90,6 +91,8 @@ type SubnetTable struct {
 	RouteTableID *string `db:"route_table_id" validate:"omitempty,max=64" json:"route_table_id"`
 	// BkBizID 业务ID
 	BkBizID int64 `db:"bk_biz_id" validate:"min=-1" json:"bk_biz_id"`
+	// RecycleStatus 回收状态
+	RecycleStatus string `db:"recycle_status" validate:"max=32" json:"recycle_status,omitempty"`
 	// Creator 创建者
 	Creator string `db:"creator" validate:"max=64" json:"creator"`
 	// Reviser 更新者
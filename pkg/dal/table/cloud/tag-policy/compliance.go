@@ -0,0 +1,202 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tabletagpolicy
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// TagComplianceFindingColumns defines all the tag compliance finding table's columns.
+var TagComplianceFindingColumns = utils.MergeColumns(nil, TagComplianceFindingColumnDescriptor)
+
+// TagComplianceFindingColumnDescriptor is tag compliance finding table column descriptors.
+var TagComplianceFindingColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "policy_id", NamedC: "policy_id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "region", NamedC: "region", Type: enumor.String},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "res_id", NamedC: "res_id", Type: enumor.String},
+	{Column: "cloud_res_id", NamedC: "cloud_res_id", Type: enumor.String},
+	{Column: "res_name", NamedC: "res_name", Type: enumor.String},
+	{Column: "violations", NamedC: "violations", Type: enumor.Json},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// TagComplianceFindingTable 标签不合规资源，一个资源违反一条策略记录一条结果
+type TagComplianceFindingTable struct {
+	// ID 主键
+	ID string `db:"id" validate:"len=0" json:"id"`
+	// PolicyID 违反的标签策略ID
+	PolicyID string `db:"policy_id" validate:"max=64" json:"policy_id"`
+	// Vendor 云厂商
+	Vendor enumor.Vendor `db:"vendor" validate:"max=16" json:"vendor"`
+	// AccountID 账号ID
+	AccountID string `db:"account_id" validate:"max=64" json:"account_id"`
+	// BkBizID 资源所属业务ID
+	BkBizID int64 `db:"bk_biz_id" validate:"min=-1" json:"bk_biz_id"`
+	// Region 地域
+	Region string `db:"region" validate:"max=255" json:"region"`
+	// ResType 资源类型
+	ResType enumor.CloudResourceType `db:"res_type" validate:"max=64" json:"res_type"`
+	// ResID 资源ID
+	ResID string `db:"res_id" validate:"max=64" json:"res_id"`
+	// CloudResID 资源云上ID
+	CloudResID string `db:"cloud_res_id" validate:"max=255" json:"cloud_res_id"`
+	// ResName 资源名称
+	ResName string `db:"res_name" validate:"max=255" json:"res_name"`
+	// Violations 违反的标签规则
+	Violations types.JsonField `db:"violations" json:"violations"`
+	// Creator 创建者
+	Creator string `db:"creator" validate:"max=64" json:"creator"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" validate:"max=64" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" validate:"isdefault" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" validate:"isdefault" json:"updated_at"`
+}
+
+// TableName return tag compliance finding table name.
+func (t TagComplianceFindingTable) TableName() table.Name {
+	return table.TagComplianceFindingTable
+}
+
+// InsertValidate validate tag compliance finding table on insert.
+func (t TagComplianceFindingTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.PolicyID) == 0 {
+		return errors.New("policy id can not be empty")
+	}
+
+	if len(t.Vendor) == 0 {
+		return errors.New("vendor can not be empty")
+	}
+
+	if len(t.AccountID) == 0 {
+		return errors.New("account id can not be empty")
+	}
+
+	if len(t.ResID) == 0 {
+		return errors.New("res id can not be empty")
+	}
+
+	if len(t.Violations) == 0 {
+		return errors.New("violations can not be empty")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator can not be empty")
+	}
+
+	return nil
+}
+
+// TagComplianceSummaryColumns defines all the tag compliance summary table's columns.
+var TagComplianceSummaryColumns = utils.MergeColumns(nil, TagComplianceSummaryColumnDescriptor)
+
+// TagComplianceSummaryColumnDescriptor is tag compliance summary table column descriptors.
+var TagComplianceSummaryColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "total_count", NamedC: "total_count", Type: enumor.Numeric},
+	{Column: "compliant_count", NamedC: "compliant_count", Type: enumor.Numeric},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// TagComplianceSummaryTable 标签合规统计，按账号、业务和资源类型记录参与评估的资源数和合规的资源数
+type TagComplianceSummaryTable struct {
+	// ID 主键
+	ID string `db:"id" validate:"len=0" json:"id"`
+	// Vendor 云厂商
+	Vendor enumor.Vendor `db:"vendor" validate:"max=16" json:"vendor"`
+	// AccountID 账号ID
+	AccountID string `db:"account_id" validate:"max=64" json:"account_id"`
+	// BkBizID 资源所属业务ID
+	BkBizID int64 `db:"bk_biz_id" validate:"min=-1" json:"bk_biz_id"`
+	// ResType 资源类型
+	ResType enumor.CloudResourceType `db:"res_type" validate:"max=64" json:"res_type"`
+	// TotalCount 参与评估的资源数
+	TotalCount uint64 `db:"total_count" json:"total_count"`
+	// CompliantCount 满足全部生效策略的资源数
+	CompliantCount uint64 `db:"compliant_count" json:"compliant_count"`
+	// Creator 创建者
+	Creator string `db:"creator" validate:"max=64" json:"creator"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" validate:"max=64" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" validate:"isdefault" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" validate:"isdefault" json:"updated_at"`
+}
+
+// TableName return tag compliance summary table name.
+func (t TagComplianceSummaryTable) TableName() table.Name {
+	return table.TagComplianceSummaryTable
+}
+
+// InsertValidate validate tag compliance summary table on insert.
+func (t TagComplianceSummaryTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Vendor) == 0 {
+		return errors.New("vendor can not be empty")
+	}
+
+	if len(t.AccountID) == 0 {
+		return errors.New("account id can not be empty")
+	}
+
+	if len(t.ResType) == 0 {
+		return errors.New("res type can not be empty")
+	}
+
+	if t.CompliantCount > t.TotalCount {
+		return errors.New("compliant count can not be greater than total count")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator can not be empty")
+	}
+
+	return nil
+}
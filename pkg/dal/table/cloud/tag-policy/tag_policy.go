@@ -0,0 +1,136 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tabletagpolicy ...
+package tabletagpolicy
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// GlobalTagPolicyBizID 全局标签策略的业务ID，对所有业务（包括未分配业务）的资源生效
+const GlobalTagPolicyBizID int64 = 0
+
+// TagPolicyColumns defines all the tag policy table's columns.
+var TagPolicyColumns = utils.MergeColumns(nil, TagPolicyColumnDescriptor)
+
+// TagPolicyColumnDescriptor is tag policy table column descriptors.
+var TagPolicyColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "res_types", NamedC: "res_types", Type: enumor.Json},
+	{Column: "rules", NamedC: "rules", Type: enumor.Json},
+	{Column: "auto_remediate", NamedC: "auto_remediate", Type: enumor.Boolean},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// TagPolicyTable 标签策略表，按业务设置资源必须包含的标签键以及允许的标签值，账号资源同步后进行合规评估
+type TagPolicyTable struct {
+	// ID 策略ID
+	ID string `db:"id" json:"id" validate:"max=64"`
+	// Name 策略名称
+	Name string `db:"name" json:"name" validate:"max=64"`
+	// BkBizID 策略生效的业务ID，0表示全局策略
+	BkBizID int64 `db:"bk_biz_id" json:"bk_biz_id" validate:"min=0"`
+	// ResTypes 策略生效的资源类型，为空表示对所有支持标签的资源类型生效
+	ResTypes types.StringArray `db:"res_types" json:"res_types"`
+	// Rules 标签规则
+	Rules types.JsonField `db:"rules" json:"rules"`
+	// AutoRemediate 是否自动为缺少标签的资源补齐默认标签
+	AutoRemediate *bool `db:"auto_remediate" json:"auto_remediate"`
+	// Enabled 是否启用
+	Enabled *bool `db:"enabled" json:"enabled"`
+	// Memo 备注
+	Memo *string `db:"memo" json:"memo" validate:"omitempty,max=255"`
+	// Creator 创建者
+	Creator string `db:"creator" json:"creator" validate:"max=64"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" json:"reviser" validate:"max=64"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" json:"created_at" validate:"isdefault"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at" validate:"isdefault"`
+}
+
+// TableName return tag policy table name.
+func (t TagPolicyTable) TableName() table.Name {
+	return table.TagPolicyTable
+}
+
+// InsertValidate validate tag policy on insertion.
+func (t TagPolicyTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) != 0 {
+		return errors.New("id can not set")
+	}
+
+	if len(t.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if len(t.Rules) == 0 {
+		return errors.New("rules is required")
+	}
+
+	if t.AutoRemediate == nil {
+		return errors.New("auto remediate is required")
+	}
+
+	if t.Enabled == nil {
+		return errors.New("enabled is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate validate tag policy on update.
+func (t TagPolicyTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if t.BkBizID != 0 {
+		return errors.New("biz id can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
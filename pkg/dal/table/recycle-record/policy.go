@@ -0,0 +1,127 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recyclerecord
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// GlobalRecyclePolicyBizID 全局回收策略的业务ID，对所有业务生效
+const GlobalRecyclePolicyBizID int64 = 0
+
+// RecyclePolicyColumns defines all the recycle policy table's columns.
+var RecyclePolicyColumns = utils.MergeColumns(nil, RecyclePolicyColumnDescriptor)
+
+// RecyclePolicyColumnDescriptor is RecyclePolicyTable's column descriptors.
+var RecyclePolicyColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "retention_hours", NamedC: "retention_hours", Type: enumor.Numeric},
+	{Column: "pre_destroy_action", NamedC: "pre_destroy_action", Type: enumor.String},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// RecyclePolicyTable 回收站策略表，按业务和资源类型设置资源在回收站中的保留时间以及销毁前的动作
+type RecyclePolicyTable struct {
+	// ID 主键
+	ID string `db:"id" json:"id" validate:"lte=64"`
+	// BkBizID 策略生效的业务ID，0表示全局策略
+	BkBizID int64 `db:"bk_biz_id" json:"bk_biz_id" validate:"min=-1"`
+	// ResType 资源类型
+	ResType enumor.CloudResourceType `db:"res_type" json:"res_type" validate:"lte=64"`
+	// RetentionHours 资源在回收站中的保留时间，单位小时
+	RetentionHours *uint64 `db:"retention_hours" json:"retention_hours"`
+	// PreDestroyAction 销毁前的动作
+	PreDestroyAction *enumor.RecyclePreDestroyAction `db:"pre_destroy_action" json:"pre_destroy_action"`
+	// Memo 备注
+	Memo *string `db:"memo" json:"memo" validate:"omitempty,max=255"`
+	// Creator 创建者
+	Creator string `db:"creator" validate:"max=64" json:"creator"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" validate:"max=64" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" validate:"isdefault" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" validate:"isdefault" json:"updated_at"`
+}
+
+// TableName is the recycle policy's database table name.
+func (t RecyclePolicyTable) TableName() table.Name {
+	return table.RecyclePolicyTable
+}
+
+// InsertValidate validate recycle policy on insertion.
+func (t RecyclePolicyTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ResType) == 0 {
+		return errors.New("resource type can not be empty")
+	}
+
+	if t.RetentionHours == nil {
+		return errors.New("retention hours is required")
+	}
+
+	if t.PreDestroyAction != nil {
+		if err := t.PreDestroyAction.Validate(t.ResType); err != nil {
+			return err
+		}
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator can not be empty")
+	}
+
+	return nil
+}
+
+// UpdateValidate validate recycle policy on update.
+func (t RecyclePolicyTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if t.BkBizID != 0 {
+		return errors.New("biz id can not update")
+	}
+
+	if len(t.ResType) != 0 {
+		return errors.New("resource type can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
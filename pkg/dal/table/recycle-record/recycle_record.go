@@ -48,6 +48,8 @@ var RecycleRecordColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "region", NamedC: "region", Type: enumor.String},
 	{Column: "detail", NamedC: "detail", Type: enumor.Json},
 	{Column: "status", NamedC: "status", Type: enumor.String},
+	{Column: "snapshot_ids", NamedC: "snapshot_ids", Type: enumor.Json},
+	{Column: "timeline", NamedC: "timeline", Type: enumor.Json},
 	{Column: "creator", NamedC: "creator", Type: enumor.String},
 	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
 	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
@@ -83,6 +85,10 @@ type RecycleRecordTable struct {
 	Detail types.JsonField `db:"detail" json:"detail" validate:"omitempty"`
 	// Detail 回收状态
 	Status string `db:"status" validate:"lte=32" json:"status"`
+	// SnapshotIDs 销毁前为资源创建的快照或镜像的云上ID
+	SnapshotIDs types.JsonField `db:"snapshot_ids" json:"snapshot_ids" validate:"omitempty"`
+	// Timeline 回收记录时间线
+	Timeline types.JsonField `db:"timeline" json:"timeline" validate:"omitempty"`
 	// Creator 创建者
 	Creator string `db:"creator" validate:"max=64" json:"creator"`
 	// Reviser 更新者
@@ -155,7 +161,7 @@ func (r RecycleRecordTable) UpdateValidate() error {
 		return err
 	}
 
-	if len(r.Status) == 0 && len(r.Detail) == 0 {
+	if len(r.Status) == 0 && len(r.Detail) == 0 && len(r.SnapshotIDs) == 0 && len(r.Timeline) == 0 {
 		return errors.New("one of the update fields must be set")
 	}
 
@@ -52,6 +52,14 @@ const (
 	VpcSecurityGroupRelTable Name = "vpc_security_group_rel"
 	// SecurityGroupTagTable is security group tag table's name.
 	SecurityGroupTagTable Name = "security_group_tag"
+	// ResourceTagTable is resource tag table's name.
+	ResourceTagTable Name = "resource_tag"
+	// TagPolicyTable is tag policy table's name.
+	TagPolicyTable Name = "tag_policy"
+	// TagComplianceFindingTable is tag compliance finding table's name.
+	TagComplianceFindingTable Name = "tag_compliance_finding"
+	// TagComplianceSummaryTable is tag compliance summary table's name.
+	TagComplianceSummaryTable Name = "tag_compliance_summary"
 	// SecurityGroupSubnetTable is security group subnet table's name.
 	SecurityGroupSubnetTable Name = "security_group_subnet_rel"
 	// SecurityGroupCvmTable is security group cvm table's name.
@@ -92,6 +100,12 @@ const (
 	EipTable Name = "eip"
 	// ImageTable is image table's name
 	ImageTable Name = "image"
+	// PrivateImageTable is private image table's name
+	PrivateImageTable Name = "private_image"
+	// DiskSnapshotTable is disk snapshot table's name
+	DiskSnapshotTable Name = "disk_snapshot"
+	// DiskSnapshotPolicyTable is disk snapshot policy table's name
+	DiskSnapshotPolicyTable Name = "disk_snapshot_policy"
 	// ZoneTable is zone table's name.
 	ZoneTable Name = "zone"
 	// CvmTable is cvm table's name.
@@ -120,6 +134,8 @@ const (
 	ApplicationTable Name = "application"
 	// ApprovalProcessTable is approval process table name
 	ApprovalProcessTable Name = "approval_process"
+	// ApplicationPolicyTable is application policy table name
+	ApplicationPolicyTable Name = "application_policy"
 	// NetworkInterfaceTable is network interface table's name.
 	NetworkInterfaceTable Name = "network_interface"
 	// NetworkInterfaceCvmRelTable is network interface and cvm rel table's name.
@@ -156,6 +172,28 @@ const (
 	LoadBalancerTable Name = "load_balancer"
 	// SecurityGroupCommonRelTable is security group common rel table's name.
 	SecurityGroupCommonRelTable Name = "security_group_common_rel"
+	// SGRiskFindingTable is security group risk finding table's name.
+	SGRiskFindingTable Name = "security_group_risk_finding"
+	// SGRuleTemplateTable is security group rule template table's name.
+	SGRuleTemplateTable Name = "security_group_rule_template"
+	// SGRuleTplApplyTable is security group rule template apply table's name.
+	SGRuleTplApplyTable Name = "security_group_rule_template_apply"
+	// IpamPoolTable is ipam cidr pool table's name.
+	IpamPoolTable Name = "ipam_cidr_pool"
+	// IpamAllocationTable is ipam cidr allocation table's name.
+	IpamAllocationTable Name = "ipam_cidr_allocation"
+	// AuthRoleTable is local rbac auth role table's name.
+	AuthRoleTable Name = "auth_role"
+	// AuthRolePolicyTable is local rbac auth role policy table's name.
+	AuthRolePolicyTable Name = "auth_role_policy"
+	// AuthRoleBindingTable is local rbac auth role binding table's name.
+	AuthRoleBindingTable Name = "auth_role_binding"
+	// AuditChainTable is audit hash chain table's name.
+	AuditChainTable Name = "audit_chain"
+	// AuditOutboxTable is audit event outbox table's name.
+	AuditOutboxTable Name = "audit_outbox"
+	// RecyclePolicyTable is recycle bin policy table's name.
+	RecyclePolicyTable Name = "recycle_policy"
 	// LoadBalancerListenerTable is load_balancer_listener table's name.
 	LoadBalancerListenerTable Name = "load_balancer_listener"
 	// TCloudLbUrlRuleTable is tcloud_lb_url_rule table's name.
@@ -226,6 +264,10 @@ var TableMap = map[Name]struct{}{
 	SecurityGroupTable:           {},
 	VpcSecurityGroupRelTable:     {},
 	SecurityGroupTagTable:        {},
+	ResourceTagTable:             {},
+	TagPolicyTable:               {},
+	TagComplianceFindingTable:    {},
+	TagComplianceSummaryTable:    {},
 	SecurityGroupSubnetTable:     {},
 	SGSecurityGroupRuleTable:     {},
 	TCloudSecurityGroupRuleTable: {},
@@ -250,12 +292,16 @@ var TableMap = map[Name]struct{}{
 	CvmTable:                     {},
 	ApplicationTable:             {},
 	ApprovalProcessTable:         {},
+	ApplicationPolicyTable:       {},
 	NetworkInterfaceTable:        {},
 	NetworkInterfaceCvmRelTable:  {},
 	RecycleRecordTable:           {},
 	EipTable:                     {},
 	DiskTable:                    {},
 	ImageTable:                   {},
+	PrivateImageTable:            {},
+	DiskSnapshotTable:            {},
+	DiskSnapshotPolicyTable:      {},
 	DiskCvmRelTableName:          {},
 	EipCvmRelTableName:           {},
 	AccountBillConfigTable:       {},
@@ -287,6 +333,17 @@ var TableMap = map[Name]struct{}{
 	AccountBillSyncRecordTable:      {},
 	LoadBalancerTable:               {},
 	SecurityGroupCommonRelTable:     {},
+	SGRiskFindingTable:              {},
+	SGRuleTemplateTable:             {},
+	SGRuleTplApplyTable:             {},
+	IpamPoolTable:                   {},
+	IpamAllocationTable:             {},
+	AuthRoleTable:                   {},
+	AuthRolePolicyTable:             {},
+	AuthRoleBindingTable:            {},
+	AuditChainTable:                 {},
+	AuditOutboxTable:                {},
+	RecyclePolicyTable:              {},
 	LoadBalancerListenerTable:       {},
 	TCloudLbUrlRuleTable:            {},
 	LoadBalancerTargetTable:         {},
@@ -25,6 +25,7 @@ import (
 	"hcm/pkg/cc"
 	authserver "hcm/pkg/client/auth-server"
 	"hcm/pkg/client/discovery"
+	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/iam/meta"
@@ -55,6 +56,8 @@ type Authorizer interface {
 	GetPermissionToApply(kt *kit.Kit, res ...meta.ResourceAttribute) (*meta.IamPermission, error)
 	// GetApplyPermUrl get iam apply permission url.
 	GetApplyPermUrl(kt *kit.Kit, input *meta.IamPermission) (string, error)
+	// InvalidateCache invalidate the authorize cache of the users when their permissions changed, empty means all.
+	InvalidateCache(kt *kit.Kit, users ...string) error
 }
 
 // NewAuthorizer create an authorizer for iam authorize related operation.
@@ -264,3 +267,17 @@ func (a authorizer) GetPermissionToApply(kt *kit.Kit, res ...meta.ResourceAttrib
 	return permission, nil
 
 }
+
+// InvalidateCache invalidate the authorize cache of the users when their permissions changed, empty means all.
+func (a authorizer) InvalidateCache(kt *kit.Kit, users ...string) error {
+	req := &asproto.InvalidateCacheReq{Users: users}
+	// only hcm internal services are allowed to invalidate the cache, so request it as backend operation.
+	header := kt.Header()
+	header.Set(constant.AppCodeKey, constant.BackendOperationAppCodeKey)
+	if err := a.authClient.InvalidateCache(kt.Ctx, header, req); err != nil {
+		logs.Errorf("invalidate auth cache failed, err: %v, users: %v, rid: %s", err, users, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -51,6 +51,11 @@ func (s *Sys) GetSystemToken(ctx context.Context) (string, error) {
 	return s.client.GetSystemToken(ctx)
 }
 
+// ListPolicies list the iam policies of the action.
+func (s *Sys) ListPolicies(ctx context.Context, params *client.ListPoliciesParams) (*client.ListPoliciesData, error) {
+	return s.client.ListPolicies(ctx, params)
+}
+
 /**
 1. 资源间的依赖关系为 Action 依赖 InstanceSelection 依赖 ResourceType，对资源的增删改操作需要按照这个依赖顺序调整
 2. ActionGroup、ResCreatorAction、CommonAction 依赖于 Action，这些资源的增删操作始终放在最后
@@ -62,6 +62,15 @@ const (
 
 	// OrmCmdSubSys defines all the orm command related sub system.
 	OrmCmdSubSys = "orm"
+
+	// AuthCacheSubSys defines auth server's authorize cache sub system.
+	AuthCacheSubSys = "auth_cache"
+
+	// RateLimitSubSys defines api server's rate limit sub system.
+	RateLimitSubSys = "rate_limit"
+
+	// AuditSinkSubSys defines data service's audit sink sub system.
+	AuditSinkSubSys = "audit_sink"
 )
 
 // labels
@@ -29,6 +29,7 @@ import (
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
+	"hcm/pkg/tools/redact"
 
 	"github.com/emicklei/go-restful/v3"
 )
@@ -67,7 +68,8 @@ func (c *Contexts) ReDecodeInto(to interface{}) error {
 
 	err = json.Unmarshal(byt, to)
 	if err != nil {
-		logs.ErrorDepthf(1, "decode request body failed, err: %s, rid: %s", err.Error(), c.Kit.Rid)
+		logs.ErrorDepthf(1, "decode request body failed, err: %s, body: %s, rid: %s", err.Error(), redact.JSON(byt),
+			c.Kit.Rid)
 		return errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
@@ -34,6 +34,7 @@ import (
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
+	"hcm/pkg/tools/redact"
 
 	"github.com/emicklei/go-restful/v3"
 	prm "github.com/prometheus/client_golang/prometheus"
@@ -168,6 +169,8 @@ func (r *Handler) wrapperAction(action *action) func(req *restful.Request, resp
 			if err := json.Compact(compactJson, byt); err == nil {
 				compactBody = compactJson.String()
 			}
+			// sensitive fields like cloud secret keys must not be written to logs in cleartext.
+			compactBody = redact.String(compactBody)
 			logs.Infof("%s received restful request, body: %s, rid: %s", action.Alias, compactBody, kt.Rid)
 		}
 
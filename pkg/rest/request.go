@@ -40,6 +40,7 @@ import (
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest/client"
+	"hcm/pkg/tools/redact"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -404,7 +405,8 @@ func (r *Request) doWithHost(client client.HTTPClient, host string, retries int,
 		// "Connection reset by peer" is a special err which in most scenario is a transient error.
 		// Which means that we can retry it. And so does the GET operation.
 		// While the other "write" operation can not simply retry it again, because they are not idempotent.
-		logs.Errorf("http request %s %s with body %s, but %v, rid: %s", string(r.verb), url, r.body, err, rid)
+		logs.Errorf("http request %s %s with body %s, but %v, rid: %s", string(r.verb), url, redact.JSON(r.body),
+			err, rid)
 		r.checkToleranceLatency(&start, url, rid)
 		if !isConnectionReset(err) || r.verb != GET {
 			return &Result{Err: err, Rid: rid}, true
@@ -438,8 +440,8 @@ func (r *Request) doWithHost(client client.HTTPClient, host string, retries int,
 				time.Sleep(20 * time.Millisecond)
 				return nil, false
 			}
-			logs.Errorf("http request %s %s with body %s, err: %v, rid: %s", string(r.verb), url, r.body,
-				err, rid)
+			logs.Errorf("http request %s %s with body %s, err: %v, rid: %s", string(r.verb), url,
+				redact.JSON(r.body), err, rid)
 			return &Result{Err: err, Rid: rid}, true
 		}
 		body = data
@@ -447,7 +449,8 @@ func (r *Request) doWithHost(client client.HTTPClient, host string, retries int,
 
 	if logs.V(4) {
 		logs.Infof("http request cost: %dms, %s %s with body %s, response status: %s, response body: %s, rid: "+
-			"%s", time.Since(start)/time.Millisecond, string(r.verb), url, r.body, resp.Status, body, rid)
+			"%s", time.Since(start)/time.Millisecond, string(r.verb), url, redact.JSON(r.body), resp.Status,
+			redact.JSON(body), rid)
 	}
 
 	return &Result{
@@ -63,6 +63,9 @@ type ExprOption struct {
 	// MaxRulesLimit defines the max number of rules an expression allows.
 	// If not set, then use default value: DefaultMaxRuleLimit
 	MaxRulesLimit uint
+	// TagRule defines whether the expression allows the resource tag rule,
+	// which field is prefixed with TagFieldPrefix, like: tags.env
+	TagRule bool
 }
 
 // ExprOptionFunc expr option func defines.
@@ -96,8 +99,15 @@ func MaxRulesLimit(limit uint) ExprOptionFunc {
 	}
 }
 
+// AllowTagRule set the expression allows resource tag rule func.
+func AllowTagRule() ExprOptionFunc {
+	return func(opt *ExprOption) {
+		opt.TagRule = true
+	}
+}
+
 // NewExprOption new expr option.
-// ExprOptionFunc: RuleFields、MaxInLimit、MaxNotInLimit、MaxRulesLimit
+// ExprOptionFunc: RuleFields、MaxInLimit、MaxNotInLimit、MaxRulesLimit、AllowTagRule
 func NewExprOption(opts ...ExprOptionFunc) *ExprOption {
 	exprOpt := new(ExprOption)
 	for _, opt := range opts {
@@ -165,6 +175,10 @@ func (exp Expression) Validate(opt *ExprOption) (hitErr error) {
 
 		// all the rule's filed should exist in the reminder.
 		for one := range fieldsReminder {
+			if opt.TagRule && IsTagField(one) {
+				continue
+			}
+
 			if exist := reminder[one]; !exist {
 				return fmt.Errorf("expression rules filed(%s) should not exist(not supported)", one)
 			}
@@ -447,8 +461,19 @@ func (ar AtomRule) Validate(opt *ExprOption) error {
 		return errors.New("rule value can not be nil")
 	}
 
+	if IsTagField(ar.Field) {
+		if err := validateTagRule(ar); err != nil {
+			return err
+		}
+	}
+
 	if opt != nil {
 		typ, exist := opt.RuleFields[ar.Field]
+		if !exist && opt.TagRule && IsTagField(ar.Field) {
+			// tag value is always a string.
+			typ, exist = enumor.String, true
+		}
+
 		if !exist {
 			return fmt.Errorf("rule field: %s is not exist in the expr option", ar.Field)
 		}
@@ -537,6 +562,14 @@ func (ar AtomRule) RuleField() string {
 
 // SQLExprAndValue convert this atom rule to a mysql's sub query expression, and field's value.
 func (ar AtomRule) SQLExprAndValue(opt *SQLWhereOption) (string, map[string]interface{}, error) {
+	if IsTagField(ar.Field) {
+		var tagOpt *TagOption
+		if opt != nil {
+			tagOpt = opt.TagOption
+		}
+		return tagSQLExprAndValue(ar, tagOpt)
+	}
+
 	expr, value, err := ar.Op.Operator().SQLExprAndValue(ar.Field, ar.Value)
 	if err != nil {
 		return "", nil, err
@@ -0,0 +1,118 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TagFieldPrefix 资源标签查询规则的字段前缀，如 tags.env 表示查询标签键为 env 的资源
+const TagFieldPrefix = "tags."
+
+// tagSupportedOps 资源标签查询规则支持的操作符，规则作用于标签值
+var tagSupportedOps = map[OpType]struct{}{
+	Equal:               {},
+	NotEqual:            {},
+	In:                  {},
+	NotIn:               {},
+	ContainsSensitive:   {},
+	ContainsInsensitive: {},
+}
+
+// IsTagField test if the rule field is a resource tag field, like: tags.env
+func IsTagField(field string) bool {
+	return strings.HasPrefix(field, TagFieldPrefix) && len(field) > len(TagFieldPrefix)
+}
+
+// TagKey return the tag key of the resource tag field.
+func TagKey(field string) string {
+	return strings.TrimPrefix(field, TagFieldPrefix)
+}
+
+// TagField return the resource tag rule field of the tag key.
+func TagField(key string) string {
+	return TagFieldPrefix + key
+}
+
+// TagOption defines how to generate the resource tag rule's SQL expression.
+// resource tag rule is converted to a sub query of the resource tag table, like:
+// id IN (SELECT res_id FROM resource_tag WHERE res_type = 'cvm' AND tag_key = 'env' AND tag_value = 'prod')
+type TagOption struct {
+	// Table is the resource tag table's name.
+	Table string
+	// ResType is the resource type of the tags to be matched.
+	ResType string
+	// IDField is the resource's id field which is matched with the res_id of the resource tag,
+	// if not set, then use default value: id
+	IDField string
+}
+
+// Validate the tag option is valid or not.
+func (opt TagOption) Validate() error {
+	if len(opt.Table) == 0 {
+		return errors.New("tag option's table is required")
+	}
+
+	if len(opt.ResType) == 0 {
+		return errors.New("tag option's resource type is required")
+	}
+
+	return nil
+}
+
+// validateTagRule validate the resource tag rule's operator.
+func validateTagRule(ar AtomRule) error {
+	if _, exist := tagSupportedOps[ar.Op.Operator().Name()]; !exist {
+		return fmt.Errorf("tag rule field: %s does not support operator: %s", ar.Field, ar.Op)
+	}
+
+	return nil
+}
+
+// tagSQLExprAndValue convert the resource tag rule to a sub query expression of the resource tag table.
+func tagSQLExprAndValue(ar AtomRule, opt *TagOption) (string, map[string]interface{}, error) {
+	if opt == nil {
+		return "", nil, fmt.Errorf("tag rule field: %s is not supported", ar.Field)
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", nil, err
+	}
+
+	valueExpr, value, err := ar.Op.Operator().SQLExprAndValue("tag_value", ar.Value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	resTypePlaceholder := fieldPlaceholderName("tag_res_type")
+	keyPlaceholder := fieldPlaceholderName("tag_key")
+	value[resTypePlaceholder] = opt.ResType
+	value[keyPlaceholder] = TagKey(ar.Field)
+
+	idField := opt.IDField
+	if len(idField) == 0 {
+		idField = "id"
+	}
+
+	return fmt.Sprintf("%s IN (SELECT res_id FROM %s WHERE res_type = %s%s AND tag_key = %s%s AND %s)", idField,
+		opt.Table, SqlPlaceholder, resTypePlaceholder, SqlPlaceholder, keyPlaceholder, valueExpr), value, nil
+}
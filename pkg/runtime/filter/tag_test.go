@@ -0,0 +1,110 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package filter
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestTagRuleValidate(t *testing.T) {
+	expr := &Expression{
+		Op: And,
+		Rules: []RuleFactory{
+			&AtomRule{Field: "name", Op: Equal.Factory(), Value: "hcm"},
+			&AtomRule{Field: "tags.env", Op: In.Factory(), Value: []string{"prod", "test"}},
+		},
+	}
+
+	opt := NewExprOption(RuleFields(map[string]enumor.ColumnType{"name": enumor.String}))
+	if err := expr.Validate(opt); err == nil {
+		t.Errorf("tag rule should not be allowed without tag rule option")
+		return
+	}
+
+	opt = NewExprOption(RuleFields(map[string]enumor.ColumnType{"name": enumor.String}), AllowTagRule())
+	if err := expr.Validate(opt); err != nil {
+		t.Errorf("validate tag rule failed, err: %v", err)
+		return
+	}
+
+	expr.Rules[1] = &AtomRule{Field: "tags.env", Op: Equal.Factory(), Value: 1}
+	if err := expr.Validate(opt); err == nil || !strings.Contains(err.Error(), "value should be a string") {
+		t.Errorf("validate tag rule value type failed, err: %v", err)
+		return
+	}
+
+	expr.Rules[1] = &AtomRule{Field: "tags.env", Op: GreaterThan.Factory(), Value: "a"}
+	if err := expr.Validate(opt); err == nil || !strings.Contains(err.Error(), "does not support operator") {
+		t.Errorf("validate tag rule operator failed, err: %v", err)
+		return
+	}
+}
+
+func TestTagRuleSQLWhereExpr(t *testing.T) {
+	expr := &Expression{
+		Op: And,
+		Rules: []RuleFactory{
+			&AtomRule{Field: "name", Op: Equal.Factory(), Value: "hcm"},
+			&AtomRule{Field: "tags.env", Op: Equal.Factory(), Value: "prod"},
+		},
+	}
+
+	if _, _, err := expr.SQLWhereExpr(&SQLWhereOption{Priority: []string{"id"}}); err == nil {
+		t.Errorf("tag rule should not be supported without tag option")
+		return
+	}
+
+	opt := &SQLWhereOption{
+		Priority:  []string{"id"},
+		TagOption: &TagOption{Table: "resource_tag", ResType: "cvm"},
+	}
+	where, value, err := expr.SQLWhereExpr(opt)
+	if err != nil {
+		t.Errorf("generate tag rule sql where expr failed, err: %v", err)
+		return
+	}
+
+	reg := regexp.MustCompile("^WHERE name = :name_[a-zA-Z0-9]{4} AND id IN \\(SELECT res_id FROM resource_tag " +
+		"WHERE res_type = :tag_res_type_[a-zA-Z0-9]{4} AND tag_key = :tag_key_[a-zA-Z0-9]{4} AND " +
+		"tag_value = :tag_value_[a-zA-Z0-9]{4}\\)$")
+	if !reg.MatchString(where) {
+		t.Errorf("tag rule sql where expr is not expected, where: %s", where)
+		return
+	}
+
+	if len(value) != 4 {
+		t.Errorf("tag rule sql where value is not expected, value: %v", value)
+		return
+	}
+
+	for placeholder, val := range value {
+		switch {
+		case strings.HasPrefix(placeholder, "tag_res_type_") && val != "cvm",
+			strings.HasPrefix(placeholder, "tag_key_") && val != "env",
+			strings.HasPrefix(placeholder, "tag_value_") && val != "prod":
+			t.Errorf("tag rule sql where value is not expected, value: %v", value)
+			return
+		}
+	}
+}
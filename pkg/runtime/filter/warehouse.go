@@ -68,6 +68,9 @@ type SQLWhereOption struct {
 	// field during query.
 	Priority      Priority
 	CrownedOption *CrownedOption
+	// TagOption defines how to generate the resource tag rule's SQL expression,
+	// resource tag rule is not supported if it is not set.
+	TagOption *TagOption
 }
 
 // Validate the options is valid or not
@@ -76,6 +79,12 @@ func (sop SQLWhereOption) Validate() error {
 		return errors.New("priority fields can not be empty, should be the resource table's index")
 	}
 
+	if sop.TagOption != nil {
+		if err := sop.TagOption.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if sop.CrownedOption == nil {
 		return nil
 	}
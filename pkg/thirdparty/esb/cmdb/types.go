@@ -132,8 +132,9 @@ type SearchBizResult struct {
 
 // Biz is cmdb biz info.
 type Biz struct {
-	BizID   int64  `json:"bk_biz_id"`
-	BizName string `json:"bk_biz_name"`
+	BizID         int64  `json:"bk_biz_id"`
+	BizName       string `json:"bk_biz_name"`
+	BizMaintainer string `json:"bk_biz_maintainer"`
 }
 
 // -------------------------- cloud area --------------------------
@@ -336,6 +337,7 @@ type ModuleInfoResult struct {
 
 // ModuleInfo cmdb module info
 type ModuleInfo struct {
+	BkModuleID   int64  `json:"bk_module_id"`
 	BkSetID      int64  `json:"bk_set_id"`
 	BkModuleName string `json:"bk_module_name"`
 	Default      int64  `json:"default"`
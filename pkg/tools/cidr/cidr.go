@@ -166,3 +166,66 @@ func NextAvailableNet(outer net.IPNet, used []net.IPNet, masklen int) (net.IPNet
 	return nextAvailable, nil
 
 }
+
+// IsOverlapped 判断两个IPv4网段是否存在重叠
+func IsOverlapped(a, b net.IPNet) bool {
+	return a.Contains(b.IP.Mask(b.Mask)) || b.Contains(a.IP.Mask(a.Mask))
+}
+
+// FirstAvailableNet find the first available net, unlike NextAvailableNet, the gap between used nets will be
+// reused and the used nets can overlap each other.
+// Params:
+// 1. outer: 待分配的网段
+// 2. used: 已经被占用的网段，可以与outer部分重叠，也可以互相重叠
+// 3. masklen: 待分配的网段掩码长度
+func FirstAvailableNet(outer net.IPNet, used []net.IPNet, masklen int) (net.IPNet, error) {
+	outerMasklen, bits := outer.Mask.Size()
+	if bits != 32 {
+		return net.IPNet{}, errors.New("only ipv4 net is supported")
+	}
+
+	if masklen < outerMasklen || masklen > 32 {
+		return net.IPNet{}, errors.New("new net mask length is shorter than outer net")
+	}
+
+	type block struct{ start, end uint64 }
+	toBlock := func(n net.IPNet) block {
+		ones, _ := n.Mask.Size()
+		start := uint64(binary.BigEndian.Uint32(n.IP.Mask(n.Mask).To4()))
+		return block{start: start, end: start + 1<<(32-ones) - 1}
+	}
+
+	outerBlock := toBlock(outer)
+	blocks := make([]block, 0, len(used))
+	for _, u := range used {
+		if u.IP.To4() == nil {
+			continue
+		}
+		b := toBlock(u)
+		if b.end < outerBlock.start || b.start > outerBlock.end {
+			continue
+		}
+		blocks = append(blocks, b)
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].start < blocks[j].start })
+
+	size := uint64(1) << (32 - masklen)
+	candidate := outerBlock.start
+	for _, b := range blocks {
+		if candidate+size-1 < b.start {
+			break
+		}
+		if b.end >= candidate {
+			// 对齐到下一个掩码边界
+			candidate = (b.end/size + 1) * size
+		}
+	}
+
+	if candidate+size-1 > outerBlock.end {
+		return net.IPNet{}, errors.New("out of range")
+	}
+
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, uint32(candidate))
+	return net.IPNet{IP: ip, Mask: net.CIDRMask(masklen, 32)}, nil
+}
@@ -183,3 +183,45 @@ func TestNextAvailableNetByIPNum(t *testing.T) {
 
 	}
 }
+
+func TestFirstAvailableNet(t *testing.T) {
+	_, outer, _ := net.ParseCIDR("10.0.0.0/16")
+	usedNetStr := []string{"10.0.0.0/24", "10.0.1.0/25", "10.0.0.128/25", "10.0.3.0/24", "192.168.0.0/16"}
+	used := make([]net.IPNet, len(usedNetStr))
+	for idx, netStr := range usedNetStr {
+		_, n, _ := net.ParseCIDR(netStr)
+		used[idx] = *n
+	}
+
+	cases := []struct {
+		masklen int
+		expect  string
+	}{
+		{15, ""},
+		{16, ""},
+		{24, "10.0.2.0/24"},
+		{25, "10.0.1.128/25"},
+		{23, "10.0.4.0/23"},
+		{22, "10.0.4.0/22"},
+	}
+	for _, c := range cases {
+		got, err := FirstAvailableNet(*outer, used, c.masklen)
+		if len(c.expect) == 0 {
+			if err == nil {
+				t.Errorf("masklen %d expect error, but got: %s", c.masklen, got.String())
+			}
+			continue
+		}
+
+		if err != nil || got.String() != c.expect {
+			t.Errorf("masklen %d got: %s, err: %v, expect: %s", c.masklen, got.String(), err, c.expect)
+		}
+	}
+
+	_, a, _ := net.ParseCIDR("10.0.0.0/8")
+	_, b, _ := net.ParseCIDR("10.1.2.0/24")
+	_, c, _ := net.ParseCIDR("172.16.0.0/12")
+	if !IsOverlapped(*a, *b) || !IsOverlapped(*b, *a) || IsOverlapped(*a, *c) {
+		t.Errorf("is overlapped check failed")
+	}
+}
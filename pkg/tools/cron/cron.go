@@ -0,0 +1,175 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package cron provides a parser of the standard 5 fields cron expression, which is used to decide whether a
+// periodic job should be executed at a given minute.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field defines the value range of a cron expression field.
+type field struct {
+	name string
+	min  int
+	max  int
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	// day of week 0 and 7 are both sunday.
+	{name: "day of week", min: 0, max: 7},
+}
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// domStar and dowStar record whether day of month and day of week are "*", if both of them are restricted,
+	// the schedule matches when either of them matches, which is the same with the standard cron.
+	domStar bool
+	dowStar bool
+}
+
+// Parse parse the standard 5 fields cron expression: minute hour day-of-month month day-of-week.
+// every field supports "*", "n", "a-b", "*/s", "a-b/s" and the list of them separated by ",".
+func Parse(expr string) (*Schedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("cron expression %q should have %d fields, but got %d", expr, len(fields),
+			len(parts))
+	}
+
+	bits := make([]uint64, len(fields))
+	for idx, part := range parts {
+		val, err := parseField(part, fields[idx])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q is invalid, %v", expr, err)
+		}
+		bits[idx] = val
+	}
+
+	// convert sunday 7 to 0.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	return &Schedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: parts[2] == "*",
+		dowStar: parts[4] == "*",
+	}, nil
+}
+
+// Validate validate the cron expression.
+func Validate(expr string) error {
+	_, err := Parse(expr)
+	return err
+}
+
+func parseField(part string, f field) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(part, ",") {
+		start, end, step, err := parseRange(item, f)
+		if err != nil {
+			return 0, err
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseRange(item string, f field) (start, end, step int, err error) {
+	step = 1
+	rangeExpr := item
+	if idx := strings.Index(item, "/"); idx != -1 {
+		rangeExpr = item[:idx]
+		if step, err = strconv.Atoi(item[idx+1:]); err != nil || step <= 0 {
+			return 0, 0, 0, fmt.Errorf("%s step of %q is invalid", f.name, item)
+		}
+	}
+
+	switch {
+	case rangeExpr == "*":
+		start, end = f.min, f.max
+		// "*" of day of week means 0-6, avoid sunday is set twice.
+		if f.max == 7 {
+			end = 6
+		}
+
+	case strings.Contains(rangeExpr, "-"):
+		bounds := strings.SplitN(rangeExpr, "-", 2)
+		if start, err = strconv.Atoi(bounds[0]); err != nil {
+			return 0, 0, 0, fmt.Errorf("%s range %q is invalid", f.name, item)
+		}
+		if end, err = strconv.Atoi(bounds[1]); err != nil {
+			return 0, 0, 0, fmt.Errorf("%s range %q is invalid", f.name, item)
+		}
+
+	default:
+		if start, err = strconv.Atoi(rangeExpr); err != nil {
+			return 0, 0, 0, fmt.Errorf("%s value %q is invalid", f.name, item)
+		}
+		end = start
+		// "n/s" means from n to the max value with step s.
+		if strings.Contains(item, "/") {
+			end = f.max
+		}
+	}
+
+	if start < f.min || end > f.max || start > end {
+		return 0, 0, 0, fmt.Errorf("%s %q is out of range [%d, %d]", f.name, item, f.min, f.max)
+	}
+
+	return start, end, step, nil
+}
+
+// Match returns whether the schedule should be executed at the minute of the given time.
+func (s *Schedule) Match(t time.Time) bool {
+	if s.minute&(1<<uint(t.Minute())) == 0 || s.hour&(1<<uint(t.Hour())) == 0 ||
+		s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
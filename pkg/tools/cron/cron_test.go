@@ -0,0 +1,75 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	valid := []string{"* * * * *", "0 2 * * *", "*/15 0-6 1,15 * 1-5", "30 3 * * 7", "5/10 * * 1-12/2 *"}
+	for _, expr := range valid {
+		_, err := Parse(expr)
+		assert.NoError(t, err, expr)
+	}
+
+	invalid := []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8",
+		"*/0 * * * *", "5-1 * * * *", "a * * * *"}
+	for _, expr := range invalid {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	// 2024-11-18 is monday.
+	monday := time.Date(2024, 11, 18, 2, 0, 0, 0, time.Local)
+	sunday := time.Date(2024, 11, 17, 2, 0, 0, 0, time.Local)
+
+	testCases := []struct {
+		expr  string
+		time  time.Time
+		match bool
+	}{
+		{expr: "* * * * *", time: monday, match: true},
+		{expr: "0 2 * * *", time: monday, match: true},
+		{expr: "0 2 * * *", time: monday.Add(time.Minute), match: false},
+		{expr: "*/15 * * * *", time: monday.Add(45 * time.Minute), match: true},
+		{expr: "*/15 * * * *", time: monday.Add(50 * time.Minute), match: false},
+		{expr: "0 2 * * 1-5", time: monday, match: true},
+		{expr: "0 2 * * 1-5", time: sunday, match: false},
+		{expr: "0 2 * * 7", time: sunday, match: true},
+		{expr: "0 2 * * 0", time: sunday, match: true},
+		// day of month and day of week are both restricted, match either of them.
+		{expr: "0 2 1 * 1", time: monday, match: true},
+		{expr: "0 2 18 * 0", time: monday, match: true},
+		{expr: "0 2 1 * 0", time: monday, match: false},
+		{expr: "0 2 18 12 *", time: monday, match: false},
+	}
+
+	for _, testCase := range testCases {
+		schedule, err := Parse(testCase.expr)
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.match, schedule.Match(testCase.time), testCase.expr)
+	}
+}
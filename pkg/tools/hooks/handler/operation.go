@@ -133,3 +133,18 @@ func ResOperateAuth(cts *rest.Contexts, opt *ValidWithAuthOption) error {
 
 	return opt.Authorizer.AuthorizeWithPerm(cts.Kit, authRes...)
 }
+
+// ValidateNotRecycled 校验资源不在回收站中, 用于不经过 ValidWithAuthHandler 的批量操作, basicInfos 需要包含 recycle_status 字段
+func ValidateNotRecycled(basicInfos map[string]types.CloudResourceBasicInfo) error {
+	recycledIDs := make([]string, 0)
+	for id, info := range basicInfos {
+		if info.RecycleStatus == enumor.RecycleStatus {
+			recycledIDs = append(recycledIDs, id)
+		}
+	}
+
+	if len(recycledIDs) > 0 {
+		return errf.Newf(errf.InvalidParameter, "resources(ids: %+v) are in recycle bin", recycledIDs)
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package redact masks the sensitive fields, such as cloud secret keys, passwords and tokens, before the data is
+// written to logs or audit tables.
+//
+// the sensitive fields are defined in two ways:
+//  1. field list: a field is sensitive if its json path ends with one of the registered fields, a field can be a
+//     bare json key (e.g. cloud_secret_key) which matches at any depth, or a dot separated json path
+//     (e.g. extension.cloud_secret_id) which matches the trailing keys of the path.
+//  2. struct tag: a struct field with `redact:"true"` tag is sensitive, its json key is treated as a bare field.
+package redact
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"hcm/pkg/tools/json"
+)
+
+// Mask is the value that the sensitive fields are replaced with.
+const Mask = "******"
+
+// tagName is the struct tag name that marks a field as sensitive.
+const tagName = "redact"
+
+// defaultFields is the default sensitive field list.
+var defaultFields = []string{
+	"cloud_secret_id",
+	"cloud_secret_key",
+	"cloud_client_secret_id",
+	"cloud_client_secret_key",
+	"cloud_service_secret_id",
+	"cloud_service_secret_key",
+	"secret_key",
+	"private_key",
+	"private_key_id",
+	"password",
+	"confirmed_password",
+	"cloud_init_password",
+	"token",
+	"access_token",
+	"refresh_token",
+	"app_secret",
+	"client_secret",
+}
+
+var (
+	lock   sync.RWMutex
+	fields = parseFields(defaultFields)
+	// fieldRegexp matches the sensitive string fields in a body which is not a valid json.
+	fieldRegexp = buildFieldRegexp(fields)
+)
+
+// RegisterFields register extra sensitive fields, see package doc for the format of the field.
+func RegisterFields(extra ...string) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	fields = append(fields, parseFields(extra)...)
+	fieldRegexp = buildFieldRegexp(fields)
+}
+
+// JSON returns the json with sensitive fields masked, json string values which contain a json object are redacted
+// recursively. if raw is not a valid json, sensitive string fields are masked by pattern.
+func JSON(raw []byte) []byte {
+	if len(raw) == 0 {
+		return raw
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		lock.RLock()
+		defer lock.RUnlock()
+		return fieldRegexp.ReplaceAll(raw, []byte(`"$1":"`+Mask+`"`))
+	}
+
+	redacted, err := json.Marshal(redactValue(data, nil, currentFields()))
+	if err != nil {
+		return []byte(Mask)
+	}
+
+	return redacted
+}
+
+// String is the string version of JSON.
+func String(raw string) string {
+	return string(JSON([]byte(raw)))
+}
+
+// Marshal marshals v to json with sensitive fields masked, including the fields of v marked by struct tag.
+func Marshal(v interface{}) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var data interface{}
+	if err = json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+
+	matchers := currentFields()
+	for key := range taggedKeys(reflect.ValueOf(v), 0, make(map[string]struct{})) {
+		matchers = append(matchers, []string{key})
+	}
+
+	return json.Marshal(redactValue(data, nil, matchers))
+}
+
+func currentFields() [][]string {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	copied := make([][]string, len(fields))
+	copy(copied, fields)
+	return copied
+}
+
+// redactValue masks the sensitive fields of the decoded json value, path is the json keys of the value.
+func redactValue(value interface{}, path []string, matchers [][]string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, one := range v {
+			childPath := append(path[:len(path):len(path)], key)
+			if isSensitive(childPath, matchers) {
+				if one != nil {
+					v[key] = Mask
+				}
+				continue
+			}
+			v[key] = redactValue(one, childPath, matchers)
+		}
+		return v
+
+	case []interface{}:
+		for idx := range v {
+			v[idx] = redactValue(v[idx], path, matchers)
+		}
+		return v
+
+	case string:
+		// json object stored as string, e.g. account extension stored as a json field.
+		trimmed := strings.TrimSpace(v)
+		if !strings.HasPrefix(trimmed, "{") {
+			return v
+		}
+
+		var nested interface{}
+		if err := json.Unmarshal([]byte(trimmed), &nested); err != nil {
+			return v
+		}
+
+		redacted, err := json.Marshal(redactValue(nested, path, matchers))
+		if err != nil {
+			return Mask
+		}
+		return string(redacted)
+
+	default:
+		return v
+	}
+}
+
+// isSensitive checks whether the json path ends with one of the sensitive fields.
+func isSensitive(path []string, matchers [][]string) bool {
+	for _, matcher := range matchers {
+		if len(matcher) > len(path) {
+			continue
+		}
+
+		matched := true
+		offset := len(path) - len(matcher)
+		for idx, key := range matcher {
+			if path[offset+idx] != key {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// maxTagDepth is the max depth that the struct tags are looked up, so that cyclic values do not loop forever.
+const maxTagDepth = 10
+
+// taggedKeys collects the json keys of the struct fields marked with redact tag in the value.
+func taggedKeys(v reflect.Value, depth int, keys map[string]struct{}) map[string]struct{} {
+	if depth > maxTagDepth || !v.IsValid() {
+		return keys
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			taggedKeys(v.Elem(), depth+1, keys)
+		}
+
+	case reflect.Slice, reflect.Array:
+		for idx := 0; idx < v.Len(); idx++ {
+			taggedKeys(v.Index(idx), depth+1, keys)
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			taggedKeys(iter.Value(), depth+1, keys)
+		}
+
+	case reflect.Struct:
+		typ := v.Type()
+		for idx := 0; idx < typ.NumField(); idx++ {
+			field := typ.Field(idx)
+			if !field.IsExported() {
+				continue
+			}
+
+			if field.Tag.Get(tagName) == "true" {
+				keys[jsonKey(field)] = struct{}{}
+				continue
+			}
+
+			taggedKeys(v.Field(idx), depth+1, keys)
+		}
+	}
+
+	return keys
+}
+
+// jsonKey returns the json key of the struct field.
+func jsonKey(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if len(name) == 0 || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func parseFields(list []string) [][]string {
+	parsed := make([][]string, 0, len(list))
+	for _, one := range list {
+		if len(one) == 0 {
+			continue
+		}
+		parsed = append(parsed, strings.Split(one, "."))
+	}
+	return parsed
+}
+
+// buildFieldRegexp builds the regexp that matches the sensitive string fields by the last key of the fields.
+func buildFieldRegexp(list [][]string) *regexp.Regexp {
+	keys := make([]string, 0, len(list))
+	for _, one := range list {
+		keys = append(keys, regexp.QuoteMeta(one[len(one)-1]))
+	}
+	return regexp.MustCompile(`"(` + strings.Join(keys, "|") + `)"\s*:\s*"(?:[^"\\]|\\.)*"`)
+}
@@ -0,0 +1,74 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	raw := `{"name":"test","extension":{"cloud_secret_id":"id","cloud_secret_key":"key","cloud_account_id":"123"},` +
+		`"users":[{"password":"pwd","token":null}],"ext":"{\"cloud_client_secret_key\":\"azure\"}"}`
+
+	redacted := string(JSON([]byte(raw)))
+	for _, secret := range []string{`"id"`, `"key"`, "pwd", "azure"} {
+		if strings.Contains(redacted, secret) {
+			t.Fatalf("secret %s is not redacted, result: %s", secret, redacted)
+		}
+	}
+
+	for _, kept := range []string{`"test"`, `"123"`, `"token":null`} {
+		if !strings.Contains(redacted, kept) {
+			t.Fatalf("field %s should be kept, result: %s", kept, redacted)
+		}
+	}
+
+	invalid := `{"cloud_secret_key": "key", "name": "test"`
+	if redacted = string(JSON([]byte(invalid))); strings.Contains(redacted, `"key"`) ||
+		!strings.Contains(redacted, "test") {
+		t.Fatalf("invalid json is not redacted by pattern, result: %s", redacted)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	type credential struct {
+		Name    string `json:"name"`
+		KeyJson string `json:"key_json" redact:"true"`
+	}
+
+	raw, err := Marshal(map[string]interface{}{"cred": &credential{Name: "gcp", KeyJson: "secret"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(raw), `"secret"`) || !strings.Contains(string(raw), `"gcp"`) {
+		t.Fatalf("tagged field is not redacted, result: %s", raw)
+	}
+}
+
+func TestPathField(t *testing.T) {
+	RegisterFields("spec.credential")
+
+	redacted := string(JSON([]byte(`{"spec":{"credential":"c1"},"credential":"c2"}`)))
+	if strings.Contains(redacted, "c1") || !strings.Contains(redacted, "c2") {
+		t.Fatalf("path field is not redacted by path, result: %s", redacted)
+	}
+}
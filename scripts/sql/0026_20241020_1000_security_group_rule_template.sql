@@ -0,0 +1,80 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+
+/*
+    SQLVER=0026,HCMVER=v1.6.2
+
+    Notes:
+    1. 添加跨云安全组规则模版表`security_group_rule_template`
+    2. 添加安全组规则模版下发记录表`security_group_rule_template_apply`
+*/
+
+START TRANSACTION;
+
+create table if not exists `security_group_rule_template`
+(
+    `id`         varchar(64)         not null,
+    `name`       varchar(255)        not null,
+    `bk_biz_id`  bigint(1)           not null default -1,
+    `rules`      json                not null,
+    `version`    bigint(1) unsigned  not null default 1,
+    `memo`       varchar(255)                 default '',
+    `creator`    varchar(64)         not null,
+    `reviser`    varchar(64)         not null,
+    `created_at` timestamp           not null default current_timestamp,
+    `updated_at` timestamp           not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_bk_biz_id_name` (`bk_biz_id`, `name`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='跨云安全组规则模版表';
+
+create table if not exists `security_group_rule_template_apply`
+(
+    `id`               varchar(64)         not null,
+    `template_id`      varchar(64)         not null,
+    `template_version` bigint(1) unsigned  not null,
+    `vendor`           varchar(16)         not null,
+    `account_id`       varchar(64)         not null,
+    `bk_biz_id`        bigint(1)           not null default -1,
+    `res_type`         varchar(64)         not null,
+    `res_id`           varchar(64)         not null,
+    `cloud_res_id`     varchar(255)        not null default '',
+    `state`            varchar(16)         not null,
+    `reason`           varchar(1024)       not null default '',
+    `flow_id`          varchar(64)         not null default '',
+    `creator`          varchar(64)         not null,
+    `reviser`          varchar(64)         not null,
+    `created_at`       timestamp           not null default current_timestamp,
+    `updated_at`       timestamp           not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    key `idx_template_id_res_id` (`template_id`, `res_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='安全组规则模版下发记录表';
+
+insert into id_generator(`resource`, `max_id`)
+values ('security_group_rule_template', '0'),
+       ('security_group_rule_template_apply', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.2' as `hcm_ver`, '0026' as `sql_ver`;
+
+COMMIT
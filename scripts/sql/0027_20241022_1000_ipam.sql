@@ -0,0 +1,78 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+
+/*
+    SQLVER=0027,HCMVER=v1.6.2
+
+    Notes:
+    1. 添加ipam网段地址池表`ipam_cidr_pool`
+    2. 添加ipam网段分配记录表`ipam_cidr_allocation`
+*/
+
+START TRANSACTION;
+
+create table if not exists `ipam_cidr_pool`
+(
+    `id`         varchar(64)  not null,
+    `name`       varchar(255) not null,
+    `vendor`     varchar(16)  not null,
+    `region`     varchar(255) not null,
+    `bk_biz_id`  bigint(1)    not null default -1,
+    `cidr`       varchar(64)  not null,
+    `memo`       varchar(255)          default '',
+    `creator`    varchar(64)  not null,
+    `reviser`    varchar(64)  not null,
+    `created_at` timestamp    not null default current_timestamp,
+    `updated_at` timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_bk_biz_id_name` (`bk_biz_id`, `name`),
+    key `idx_bk_biz_id_vendor_region` (`bk_biz_id`, `vendor`, `region`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='ipam网段地址池表';
+
+create table if not exists `ipam_cidr_allocation`
+(
+    `id`         varchar(64)  not null,
+    `pool_id`    varchar(64)  not null,
+    `bk_biz_id`  bigint(1)    not null default -1,
+    `cidr`       varchar(64)  not null,
+    `type`       varchar(32)  not null,
+    `res_type`   varchar(64)  not null default '',
+    `res_id`     varchar(64)  not null default '',
+    `memo`       varchar(255)          default '',
+    `creator`    varchar(64)  not null,
+    `reviser`    varchar(64)  not null,
+    `created_at` timestamp    not null default current_timestamp,
+    `updated_at` timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    key `idx_pool_id` (`pool_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='ipam网段分配记录表';
+
+insert into id_generator(`resource`, `max_id`)
+values ('ipam_cidr_pool', '0'),
+       ('ipam_cidr_allocation', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.2' as `hcm_ver`, '0027' as `sql_ver`;
+
+COMMIT
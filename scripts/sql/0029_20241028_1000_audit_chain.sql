@@ -0,0 +1,55 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+
+/*
+    SQLVER=0029,HCMVER=v1.6.2
+
+    Notes:
+    1. 审计表`audit`添加哈希链相关字段`chain`、`seq`、`prev_hash`、`hash`
+    2. 添加审计哈希链表`audit_chain`
+*/
+
+START TRANSACTION;
+
+alter table `audit`
+    add column `chain` varchar(16) not null default '',
+    add column `seq` bigint(1) unsigned not null default 0,
+    add column `prev_hash` char(64) not null default '',
+    add column `hash` char(64) not null default '',
+    add index `idx_chain_seq` (`chain`, `seq`);
+
+create table if not exists `audit_chain`
+(
+    `chain`       varchar(16)         not null,
+    `last_seq`    bigint(1) unsigned  not null default 0,
+    `last_hash`   char(64)            not null default '',
+    `export_path` varchar(255)        not null default '',
+    `exported_at` varchar(64)         not null default '',
+    `created_at`  timestamp           not null default current_timestamp,
+    `updated_at`  timestamp           not null default current_timestamp on update current_timestamp,
+    primary key (`chain`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='审计哈希链表';
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.2' as `hcm_ver`, '0029' as `sql_ver`;
+
+COMMIT
\ No newline at end of file
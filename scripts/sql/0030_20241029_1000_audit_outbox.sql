@@ -0,0 +1,48 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+
+/*
+    SQLVER=0030,HCMVER=v1.6.2
+
+    Notes:
+    1. 添加审计事件外发发件箱表`audit_outbox`
+*/
+
+START TRANSACTION;
+
+create table if not exists `audit_outbox`
+(
+    `id`            bigint(1) unsigned not null auto_increment,
+    `audit_chain`   varchar(16)        not null,
+    `audit_seq`     bigint(1) unsigned not null,
+    `attempts`      int(1) unsigned    not null default 0,
+    `next_retry_at` bigint(1)          not null default 0,
+    `last_error`    varchar(1024)      not null default '',
+    `created_at`    timestamp          not null default current_timestamp,
+    primary key (`id`),
+    key `idx_next_retry_at` (`next_retry_at`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='审计事件外发发件箱表';
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.2' as `hcm_ver`, '0030' as `sql_ver`;
+
+COMMIT
\ No newline at end of file
@@ -0,0 +1,42 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+
+/*
+    SQLVER=0031,HCMVER=v1.6.2
+
+    Notes:
+    1. 安全组、负载均衡、VPC、子网增加回收状态recycle_status字段
+*/
+
+START TRANSACTION;
+
+alter table security_group
+    add column `recycle_status` varchar(32) default '';
+alter table load_balancer
+    add column `recycle_status` varchar(32) default '';
+alter table vpc
+    add column `recycle_status` varchar(32) default '';
+alter table subnet
+    add column `recycle_status` varchar(32) default '';
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.2' as `hcm_ver`, '0031' as `sql_ver`;
+
+COMMIT
\ No newline at end of file
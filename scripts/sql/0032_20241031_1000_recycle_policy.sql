@@ -0,0 +1,60 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+
+/*
+    SQLVER=0032,HCMVER=v1.6.2
+
+    Notes:
+    1. 添加回收站策略表`recycle_policy`
+    2. 回收记录表增加快照ID`snapshot_ids`和时间线`timeline`字段
+*/
+
+START TRANSACTION;
+
+create table if not exists `recycle_policy`
+(
+    `id`                 varchar(64)     not null,
+    `bk_biz_id`          bigint          not null default 0,
+    `res_type`           varchar(64)     not null,
+    `retention_hours`    bigint unsigned not null,
+    `pre_destroy_action` varchar(32)     not null default '',
+    `memo`               varchar(255)             default '',
+    `creator`            varchar(64)     not null,
+    `reviser`            varchar(64)     not null,
+    `created_at`         timestamp       not null default current_timestamp,
+    `updated_at`         timestamp       not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_bk_biz_id_res_type` (`bk_biz_id`, `res_type`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='回收站策略表';
+
+alter table recycle_record
+    add column `snapshot_ids` json default null after `status`;
+alter table recycle_record
+    add column `timeline` json default null after `snapshot_ids`;
+
+insert into id_generator(`resource`, `max_id`)
+values ('recycle_policy', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.2' as `hcm_ver`, '0032' as `sql_ver`;
+
+COMMIT
\ No newline at end of file
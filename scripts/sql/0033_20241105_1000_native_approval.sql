@@ -0,0 +1,44 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+
+/*
+    SQLVER=0033,HCMVER=v1.6.2
+
+    Notes:
+    1. 审批流程表增加审批引擎`engine`、审批阶段`stages`和超时时间`timeout_hours`字段
+    2. 申请单表增加内置审批引擎的审批详情`approval_detail`字段
+*/
+
+START TRANSACTION;
+
+alter table approval_process
+    add column `engine` varchar(16) not null default 'itsm' after `managers`;
+alter table approval_process
+    add column `stages` json default null after `engine`;
+alter table approval_process
+    add column `timeout_hours` int unsigned not null default 0 after `stages`;
+
+alter table application
+    add column `approval_detail` json default null after `memo`;
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.2' as `hcm_ver`, '0033' as `sql_ver`;
+
+COMMIT
\ No newline at end of file
@@ -0,0 +1,105 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+
+/*
+    SQLVER=0036,HCMVER=v1.6.2
+
+    Notes:
+    1. 添加标签策略表`tag_policy`
+    2. 添加标签不合规资源表`tag_compliance_finding`
+    3. 添加标签合规统计表`tag_compliance_summary`
+*/
+
+START TRANSACTION;
+
+create table if not exists `tag_policy`
+(
+    `id`             varchar(64)  not null,
+    `name`           varchar(64)  not null,
+    `bk_biz_id`      bigint(1)    not null default 0,
+    `res_types`      json         not null,
+    `rules`          json         not null,
+    `auto_remediate` tinyint(1)   not null default 0,
+    `enabled`        tinyint(1)   not null default 1,
+    `memo`           varchar(255)          default '',
+    `creator`        varchar(64)  not null,
+    `reviser`        varchar(64)  not null,
+    `created_at`     timestamp    not null default current_timestamp,
+    `updated_at`     timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_bk_biz_id_name` (`bk_biz_id`, `name`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='标签策略表';
+
+create table if not exists `tag_compliance_finding`
+(
+    `id`           varchar(64)  not null,
+    `policy_id`    varchar(64)  not null,
+    `vendor`       varchar(16)  not null,
+    `account_id`   varchar(64)  not null,
+    `bk_biz_id`    bigint(1)    not null default -1,
+    `region`       varchar(255) not null default '',
+    `res_type`     varchar(64)  not null,
+    `res_id`       varchar(64)  not null,
+    `cloud_res_id` varchar(255) not null default '',
+    `res_name`     varchar(255) not null default '',
+    `violations`   json         not null,
+    `creator`      varchar(64)  not null,
+    `reviser`      varchar(64)  not null,
+    `created_at`   timestamp    not null default current_timestamp,
+    `updated_at`   timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    key `idx_vendor_account_id` (`vendor`, `account_id`),
+    key `idx_bk_biz_id_res_type` (`bk_biz_id`, `res_type`),
+    key `idx_policy_id` (`policy_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='标签不合规资源表';
+
+create table if not exists `tag_compliance_summary`
+(
+    `id`              varchar(64)     not null,
+    `vendor`          varchar(16)     not null,
+    `account_id`      varchar(64)     not null,
+    `bk_biz_id`       bigint(1)       not null default -1,
+    `res_type`        varchar(64)     not null,
+    `total_count`     bigint unsigned not null default 0,
+    `compliant_count` bigint unsigned not null default 0,
+    `creator`         varchar(64)     not null,
+    `reviser`         varchar(64)     not null,
+    `created_at`      timestamp       not null default current_timestamp,
+    `updated_at`      timestamp       not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_account_id_bk_biz_id_res_type` (`account_id`, `bk_biz_id`, `res_type`),
+    key `idx_bk_biz_id` (`bk_biz_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='标签合规统计表';
+
+insert into id_generator(`resource`, `max_id`)
+values ('tag_policy', '0'),
+       ('tag_compliance_finding', '0'),
+       ('tag_compliance_summary', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.2' as `hcm_ver`, '0036' as `sql_ver`;
+
+COMMIT
\ No newline at end of file
@@ -0,0 +1,86 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+
+/*
+    SQLVER=0037,HCMVER=v1.6.2
+
+    Notes:
+    1. 主机表`cvm`添加归一化的生命周期状态字段`lifecycle_status`，并根据云上状态初始化
+*/
+
+START TRANSACTION;
+
+alter table cvm
+    add column `lifecycle_status` varchar(32) not null default '' after `status`;
+alter table cvm
+    add index `idx_lifecycle_status` (`lifecycle_status`);
+
+update cvm
+set `lifecycle_status` = case
+        when `vendor` = 'tcloud' and `status` = 'PENDING' then 'pending'
+        when `vendor` = 'tcloud' and `status` = 'LAUNCH_FAILED' then 'error'
+        when `vendor` = 'tcloud' and `status` = 'RUNNING' then 'running'
+        when `vendor` = 'tcloud' and `status` = 'STOPPED' then 'stopped'
+        when `vendor` = 'tcloud' and `status` = 'STARTING' then 'pending'
+        when `vendor` = 'tcloud' and `status` = 'STOPPING' then 'stopping'
+        when `vendor` = 'tcloud' and `status` = 'REBOOTING' then 'rebooting'
+        when `vendor` = 'tcloud' and `status` = 'SHUTDOWN' then 'stopped'
+        when `vendor` = 'tcloud' and `status` = 'TERMINATING' then 'terminating'
+        when `vendor` = 'aws' and `status` = 'pending' then 'pending'
+        when `vendor` = 'aws' and `status` = 'running' then 'running'
+        when `vendor` = 'aws' and `status` = 'shutting-down' then 'terminating'
+        when `vendor` = 'aws' and `status` = 'terminated' then 'terminated'
+        when `vendor` = 'aws' and `status` = 'stopping' then 'stopping'
+        when `vendor` = 'aws' and `status` = 'stopped' then 'stopped'
+        when `vendor` = 'gcp' and `status` = 'PROVISIONING' then 'pending'
+        when `vendor` = 'gcp' and `status` = 'STAGING' then 'pending'
+        when `vendor` = 'gcp' and `status` = 'RUNNING' then 'running'
+        when `vendor` = 'gcp' and `status` = 'STOPPING' then 'stopping'
+        when `vendor` = 'gcp' and `status` = 'SUSPENDING' then 'stopping'
+        when `vendor` = 'gcp' and `status` = 'SUSPENDED' then 'stopped'
+        when `vendor` = 'gcp' and `status` = 'REPAIRING' then 'error'
+        when `vendor` = 'gcp' and `status` = 'TERMINATED' then 'stopped'
+        when `vendor` = 'azure' and `status` = 'PowerState/starting' then 'pending'
+        when `vendor` = 'azure' and `status` = 'PowerState/running' then 'running'
+        when `vendor` = 'azure' and `status` = 'PowerState/stopping' then 'stopping'
+        when `vendor` = 'azure' and `status` = 'PowerState/stopped' then 'stopped'
+        when `vendor` = 'azure' and `status` = 'PowerState/deallocating' then 'stopping'
+        when `vendor` = 'azure' and `status` = 'PowerState/deallocated' then 'stopped'
+        when `vendor` = 'huawei' and `status` = 'BUILD' then 'pending'
+        when `vendor` = 'huawei' and `status` = 'REBOOT' then 'rebooting'
+        when `vendor` = 'huawei' and `status` = 'HARD_REBOOT' then 'rebooting'
+        when `vendor` = 'huawei' and `status` = 'REBUILD' then 'pending'
+        when `vendor` = 'huawei' and `status` = 'MIGRATING' then 'pending'
+        when `vendor` = 'huawei' and `status` = 'RESIZE' then 'pending'
+        when `vendor` = 'huawei' and `status` = 'ACTIVE' then 'running'
+        when `vendor` = 'huawei' and `status` = 'SHUTOFF' then 'stopped'
+        when `vendor` = 'huawei' and `status` = 'REVERT_RESIZE' then 'pending'
+        when `vendor` = 'huawei' and `status` = 'VERIFY_RESIZE' then 'pending'
+        when `vendor` = 'huawei' and `status` = 'ERROR' then 'error'
+        when `vendor` = 'huawei' and `status` = 'DELETED' then 'terminated'
+        when `vendor` = 'huawei' and `status` = 'SHELVED' then 'stopped'
+        when `vendor` = 'huawei' and `status` = 'SHELVED_OFFLOADED' then 'stopped'
+        else 'unknown'
+    end;
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.2' as `hcm_ver`, '0037' as `sql_ver`;
+
+COMMIT
\ No newline at end of file
@@ -0,0 +1,64 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+
+/*
+    SQLVER=0038,HCMVER=v1.6.2
+
+    Notes:
+    1. 添加私有镜像表`private_image`
+*/
+
+START TRANSACTION;
+
+create table if not exists `private_image`
+(
+    `id`                  varchar(64)  not null,
+    `vendor`              varchar(16)  not null,
+    `account_id`          varchar(64)  not null,
+    `bk_biz_id`           bigint(1)    not null default -1,
+    `region`              varchar(255) not null,
+    `cloud_id`            varchar(255) not null,
+    `name`                varchar(255) not null default '',
+    `source_cvm_id`       varchar(64)  not null default '',
+    `source_cloud_cvm_id` varchar(255) not null default '',
+    `source_image_id`     varchar(64)  not null default '',
+    `shared_account_ids`  json         not null,
+    `deprecated`          tinyint(1)   not null default 0,
+    `memo`                varchar(255)          default '',
+    `creator`             varchar(64)  not null,
+    `reviser`             varchar(64)  not null,
+    `created_at`          timestamp    not null default current_timestamp,
+    `updated_at`          timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_vendor_region_cloud_id` (`vendor`, `region`, `cloud_id`),
+    key `idx_account_id` (`account_id`),
+    key `idx_bk_biz_id` (`bk_biz_id`),
+    key `idx_source_cvm_id` (`source_cvm_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='私有镜像表';
+
+insert into id_generator(`resource`, `max_id`)
+values ('private_image', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.2' as `hcm_ver`, '0038' as `sql_ver`;
+
+COMMIT
@@ -0,0 +1,88 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+
+/*
+    SQLVER=0039,HCMVER=v1.6.2
+
+    Notes:
+    1. 添加云硬盘快照表`disk_snapshot`
+    2. 添加定期快照策略表`disk_snapshot_policy`
+*/
+
+START TRANSACTION;
+
+create table if not exists `disk_snapshot`
+(
+    `id`                 varchar(64)     not null,
+    `vendor`             varchar(16)     not null,
+    `account_id`         varchar(64)     not null,
+    `bk_biz_id`          bigint(1)       not null default -1,
+    `region`             varchar(255)    not null,
+    `cloud_id`           varchar(255)    not null,
+    `name`               varchar(255)    not null default '',
+    `disk_id`            varchar(64)     not null default '',
+    `cloud_disk_id`      varchar(255)    not null default '',
+    `disk_size`          bigint unsigned not null default 0,
+    `status`             varchar(32)     not null default '',
+    `policy_id`          varchar(64)     not null default '',
+    `cloud_created_time` varchar(64)     not null default '',
+    `memo`               varchar(255)             default '',
+    `creator`            varchar(64)     not null,
+    `reviser`            varchar(64)     not null,
+    `created_at`         timestamp       not null default current_timestamp,
+    `updated_at`         timestamp       not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_vendor_account_id_cloud_id` (`vendor`, `account_id`, `cloud_id`),
+    key `idx_bk_biz_id` (`bk_biz_id`),
+    key `idx_disk_id` (`disk_id`),
+    key `idx_policy_id` (`policy_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='云硬盘快照表';
+
+create table if not exists `disk_snapshot_policy`
+(
+    `id`               varchar(64)     not null,
+    `name`             varchar(255)    not null,
+    `bk_biz_id`        bigint(1)       not null,
+    `cron`             varchar(64)     not null,
+    `retention_count`  bigint unsigned not null,
+    `disk_ids`         json            not null,
+    `enabled`          tinyint(1)      not null default 1,
+    `last_executed_at` varchar(64)     not null default '',
+    `memo`             varchar(255)             default '',
+    `creator`          varchar(64)     not null,
+    `reviser`          varchar(64)     not null,
+    `created_at`       timestamp       not null default current_timestamp,
+    `updated_at`       timestamp       not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_bk_biz_id_name` (`bk_biz_id`, `name`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='定期快照策略表';
+
+insert into id_generator(`resource`, `max_id`)
+values ('disk_snapshot', '0'),
+       ('disk_snapshot_policy', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.2' as `hcm_ver`, '0039' as `sql_ver`;
+
+COMMIT
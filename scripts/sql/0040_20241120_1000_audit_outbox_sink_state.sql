@@ -0,0 +1,39 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+
+/*
+    SQLVER=0040,HCMVER=v1.6.2
+
+    Notes:
+    1. 审计事件外发发件箱表`audit_outbox`添加已投递的外发目标`delivered_sinks`、状态`status`字段
+*/
+
+START TRANSACTION;
+
+alter table `audit_outbox`
+    add column `delivered_sinks` json                 default null after `audit_seq`,
+    add column `status`          varchar(16) not null default 'pending' after `delivered_sinks`,
+    drop index `idx_next_retry_at`,
+    add index `idx_status_next_retry_at` (`status`, `next_retry_at`);
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.2' as `hcm_ver`, '0040' as `sql_ver`;
+
+COMMIT